@@ -15,11 +15,13 @@ CERTDIR_TRUSTEDJWTCERTS=$CERTS_PATH/trustedjwt
 CERTDIR_TRUSTEDCAS=$CERTS_PATH/trustedca
 KEYS_PATH=$PRODUCT_HOME/keys
 KEYS_TRANSFER_POLICY_PATH=$PRODUCT_HOME/keys-transfer-policy
+TENANT_QUOTAS_PATH=$PRODUCT_HOME/tenant-quotas
+APPROVALS_PATH=$PRODUCT_HOME/approvals
 SAML_CERTS_PATH=$CERTS_PATH/saml
 TPM_IDENTITY_CERTS_PATH=$CERTS_PATH/tpm-identity
 
 if [ ! -f $CONFIG_PATH/.setup_done ]; then
-  for directory in $PRODUCT_HOME $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $KEYS_PATH $KEYS_TRANSFER_POLICY_PATH $SAML_CERTS_PATH $TPM_IDENTITY_CERTS_PATH; do
+  for directory in $PRODUCT_HOME $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $KEYS_PATH $KEYS_TRANSFER_POLICY_PATH $TENANT_QUOTAS_PATH $APPROVALS_PATH $SAML_CERTS_PATH $TPM_IDENTITY_CERTS_PATH; do
     mkdir -p $directory
     if [ $? -ne 0 ]; then
       echo "Cannot create directory: $directory"
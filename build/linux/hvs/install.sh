@@ -31,8 +31,9 @@ CERTDIR_TRUSTEDCAS=$CERTS_PATH/trustedca/root
 CERTDIR_TRUSTEDPCAS=$CERTS_PATH/trustedca/privacy-ca
 KEYS_PATH=$CONFIG_PATH/trusted-keys
 CERTDIR_ENDORSEMENTCA=$CERTS_PATH/endorsement
+CERTDIR_PLATFORMCA=$CERTS_PATH/platform
 
-for directory in $BIN_PATH $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $CERTDIR_TRUSTEDPCAS $KEYS_PATH $CERTDIR_ENDORSEMENTCA; do
+for directory in $BIN_PATH $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $CERTDIR_TRUSTEDPCAS $KEYS_PATH $CERTDIR_ENDORSEMENTCA $CERTDIR_PLATFORMCA; do
   # mkdir -p will return 0 if directory exists or is a symlink to an existing directory or directory and parents can be created
   mkdir -p $directory
   if [ $? -ne 0 ]; then
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package main
+
+// measure measures the files, directories and symbolic links of a manifest in a live file system and creates their
+// measurement XML and SOFTWARE flavor, to author flavors without the workload measurement tool of the hosts.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/measurement"
+	"github.com/pkg/errors"
+)
+
+const usage = `Usage: measure -manifest <file> [-root <dir>] [-measurement-output <file>] [-flavor-output <file>]
+
+Measures the File, Dir and Symlink entries of the manifest, in XML, in the file system mounted at the root directory
+and writes the measurement XML and the SOFTWARE flavor, in JSON, that can be imported in HVS. The flavor is written
+to the standard output by default.
+`
+
+type measureArgs struct {
+	manifest          string
+	root              string
+	measurementOutput string
+	flavorOutput      string
+}
+
+func main() {
+	var args measureArgs
+	flags := flag.NewFlagSet("measure", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&args.manifest, "manifest", "", "manifest of the measurements, in XML")
+	flags.StringVar(&args.root, "root", "/", "directory the paths of the manifest are relative to")
+	flags.StringVar(&args.measurementOutput, "measurement-output", "", "file the measurement XML is written to")
+	flags.StringVar(&args.flavorOutput, "flavor-output", "", "file the SOFTWARE flavor is written to, the standard output by default")
+	_ = flags.Parse(os.Args[1:])
+
+	if args.manifest == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := measure(args); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(2)
+	}
+}
+
+// measure writes the measurement XML and the SOFTWARE flavor of the manifest
+func measure(args measureArgs) error {
+	manifestXml, err := ioutil.ReadFile(args.manifest)
+	if err != nil {
+		return errors.Wrap(err, "Error reading "+args.manifest)
+	}
+	flavor, measurementXml, err := measurement.CreateSoftwareFlavor(manifestXml, args.root)
+	if err != nil {
+		return err
+	}
+
+	if args.measurementOutput != "" {
+		err = ioutil.WriteFile(args.measurementOutput, append(measurementXml, '\n'), 0644)
+		if err != nil {
+			return errors.Wrap(err, "Error writing the measurement")
+		}
+	}
+
+	flavorJson, err := json.MarshalIndent(flavor, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding the flavor")
+	}
+	flavorJson = append(flavorJson, '\n')
+	if args.flavorOutput != "" {
+		err = ioutil.WriteFile(args.flavorOutput, flavorJson, 0644)
+	} else {
+		_, err = os.Stdout.Write(flavorJson)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Error writing the flavor")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package main
+
+// verifier-replay replays the decisions of the HVS decision log against the archived host manifest and flavors
+// they were made on, and reports if the same decision is reproduced.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+const usage = `Usage: verifier-replay -decision-log <file> -manifest <file> -flavor <file> [-base-flavor <file>]
+                       -privacy-ca <path> -tag-ca <path> -flavor-signing-cert <file> -flavor-ca <path>
+                       [-flavor-co-signing-certs <path> -flavor-signature-quorum <n>]
+                       [-max-quote-age <duration> -clock-skew-threshold <duration>]
+
+Replays the decisions of the decision log made on the host manifest and flavor, verifying them again at the time
+of the decision. The certificates are PEM files, or directories of PEM files, archived with the evidence.
+`
+
+type replayArgs struct {
+	decisionLog       string
+	manifest          string
+	flavor            string
+	baseFlavor        string
+	privacyCA         string
+	tagCA             string
+	flavorSigningCert string
+	flavorCA          string
+	coSigningCerts    string
+	signatureQuorum   int
+	maxQuoteAge       time.Duration
+	skewThreshold     time.Duration
+}
+
+func main() {
+	var args replayArgs
+	flags := flag.NewFlagSet("verifier-replay", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&args.decisionLog, "decision-log", "", "decision log written by HVS")
+	flags.StringVar(&args.manifest, "manifest", "", "archived host manifest, in JSON")
+	flags.StringVar(&args.flavor, "flavor", "", "archived signed flavor, in JSON, the delta flavor for a decision on a delta flavor")
+	flags.StringVar(&args.baseFlavor, "base-flavor", "", "archived signed base flavor, in JSON, for a decision on a delta flavor")
+	flags.StringVar(&args.privacyCA, "privacy-ca", "", "privacy CA certificates")
+	flags.StringVar(&args.tagCA, "tag-ca", "", "asset tag CA certificates")
+	flags.StringVar(&args.flavorSigningCert, "flavor-signing-cert", "", "flavor signing certificate and its chain")
+	flags.StringVar(&args.flavorCA, "flavor-ca", "", "flavor signing root CA certificates")
+	flags.StringVar(&args.coSigningCerts, "flavor-co-signing-certs", "", "certificates of the other trusted flavor signers")
+	flags.IntVar(&args.signatureQuorum, "flavor-signature-quorum", 0, "number of the trusted flavor signers that must have signed the flavors")
+	flags.DurationVar(&args.maxQuoteAge, "max-quote-age", 0, "maximum age of the quotes HVS was configured with")
+	flags.DurationVar(&args.skewThreshold, "clock-skew-threshold", 0, "host clock skew threshold HVS was configured with")
+	_ = flags.Parse(os.Args[1:])
+
+	if args.decisionLog == "" || args.manifest == "" || args.flavor == "" || args.privacyCA == "" ||
+		args.tagCA == "" || args.flavorSigningCert == "" || args.flavorCA == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	reproduced, err := replay(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+	if !reproduced {
+		os.Exit(1)
+	}
+}
+
+// replay replays the decisions of the log made on the evidence and returns true if all of them are reproduced
+func replay(args replayArgs) (bool, error) {
+	certs, err := loadVerifierCertificates(args)
+	if err != nil {
+		return false, err
+	}
+
+	var hostManifest types.HostManifest
+	if err = readJSON(args.manifest, &hostManifest); err != nil {
+		return false, err
+	}
+	var flavors []*hvs.SignedFlavor
+	for _, flavorFile := range []string{args.baseFlavor, args.flavor} {
+		if flavorFile == "" {
+			continue
+		}
+		var signedFlavor hvs.SignedFlavor
+		if err = readJSON(flavorFile, &signedFlavor); err != nil {
+			return false, err
+		}
+		flavors = append(flavors, &signedFlavor)
+	}
+
+	logFile, err := os.Open(args.decisionLog)
+	if err != nil {
+		return false, errors.Wrap(err, "Error opening the decision log")
+	}
+	defer logFile.Close()
+	decisions, err := verifier.ReadDecisionLogs(logFile)
+	if err != nil {
+		return false, err
+	}
+
+	reproduced, replayed := true, 0
+	for i := range decisions {
+		if matches, err := decisions[i].Matches(&hostManifest, flavors); err != nil {
+			return false, err
+		} else if !matches {
+			continue
+		}
+		replayed++
+		differences, err := verifier.ReplayDecision(certs, &decisions[i], &hostManifest, flavors)
+		if err != nil {
+			return false, err
+		}
+		if len(differences) == 0 {
+			fmt.Printf("Decision made at %s reproduced, trusted: %t\n", decisions[i].VerifiedAt, decisions[i].Trusted)
+			continue
+		}
+		reproduced = false
+		fmt.Printf("Decision made at %s not reproduced:\n", decisions[i].VerifiedAt)
+		for _, difference := range differences {
+			fmt.Println("  " + difference)
+		}
+	}
+	if replayed == 0 {
+		return false, errors.New("No decision of the decision log was made on the host manifest and flavors")
+	}
+	return reproduced, nil
+}
+
+func loadVerifierCertificates(args replayArgs) (verifier.VerifierCertificates, error) {
+	certs, err := verifier.LoadVerifierCertificates(verifier.CertificatePaths{
+		PrivacyCA:            args.privacyCA,
+		AssetTagCA:           args.tagCA,
+		FlavorSigningCert:    args.flavorSigningCert,
+		FlavorCA:             args.flavorCA,
+		FlavorCoSigningCerts: args.coSigningCerts,
+	})
+	if err != nil {
+		return verifier.VerifierCertificates{}, err
+	}
+	certs.FlavorSignatureQuorum = args.signatureQuorum
+	certs.MaxQuoteAge = args.maxQuoteAge
+	certs.ClockSkewThreshold = args.skewThreshold
+	return certs, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "Error reading "+path)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return errors.Wrap(err, "Error decoding "+path)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package main
+
+// verify verifies an archived host manifest against a set of flavors offline and outputs the trust report HVS
+// would create, for auditors and developers that do not run HVS.
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+const usage = `Usage: verify -manifest <file> -flavors <file> -privacy-ca <path> -tag-ca <path>
+              -flavor-signing-cert <file> -flavor-ca <path> [-skip-flavor-signature-verification]
+              [-flavor-co-signing-certs <path> -flavor-signature-quorum <n>]
+              [-max-quote-age <duration> -clock-skew-threshold <duration>]
+              [-verification-time <RFC3339 time>] [-output <file>]
+
+Verifies the host manifest against the flavors and writes the trust report, in JSON. The flavors file holds a
+signed flavor, a list of signed flavors or a signed flavor collection as returned by HVS. The certificates are PEM
+files, or directories of PEM files. The exit status is 0 when the host is trusted and 1 when it is not.
+`
+
+type verifyArgs struct {
+	manifest          string
+	flavors           string
+	privacyCA         string
+	tagCA             string
+	flavorSigningCert string
+	flavorCA          string
+	coSigningCerts    string
+	signatureQuorum   int
+	skipSignature     bool
+	maxQuoteAge       time.Duration
+	skewThreshold     time.Duration
+	verificationTime  string
+	output            string
+}
+
+func main() {
+	var args verifyArgs
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&args.manifest, "manifest", "", "host manifest, in JSON")
+	flags.StringVar(&args.flavors, "flavors", "", "signed flavors, in JSON")
+	flags.StringVar(&args.privacyCA, "privacy-ca", "", "privacy CA certificates")
+	flags.StringVar(&args.tagCA, "tag-ca", "", "asset tag CA certificates")
+	flags.StringVar(&args.flavorSigningCert, "flavor-signing-cert", "", "flavor signing certificate and its chain")
+	flags.StringVar(&args.flavorCA, "flavor-ca", "", "flavor signing root CA certificates")
+	flags.StringVar(&args.coSigningCerts, "flavor-co-signing-certs", "", "certificates of the other trusted flavor signers")
+	flags.IntVar(&args.signatureQuorum, "flavor-signature-quorum", 0, "number of the trusted flavor signers that must have signed the flavors")
+	flags.BoolVar(&args.skipSignature, "skip-flavor-signature-verification", false, "do not verify the signatures of the flavors")
+	flags.DurationVar(&args.maxQuoteAge, "max-quote-age", 0, "maximum age of the quotes")
+	flags.DurationVar(&args.skewThreshold, "clock-skew-threshold", 0, "host clock skew threshold")
+	flags.StringVar(&args.verificationTime, "verification-time", "", "time the certificates are checked at, the current time by default")
+	flags.StringVar(&args.output, "output", "", "file the trust report is written to, the standard output by default")
+	_ = flags.Parse(os.Args[1:])
+
+	if args.manifest == "" || args.flavors == "" || args.privacyCA == "" || args.tagCA == "" ||
+		args.flavorSigningCert == "" || args.flavorCA == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	trusted, err := verify(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(2)
+	}
+	if !trusted {
+		os.Exit(1)
+	}
+}
+
+// verify writes the trust report of the evidence and returns true if the host is trusted
+func verify(args verifyArgs) (bool, error) {
+	certs, err := verifier.LoadVerifierCertificates(verifier.CertificatePaths{
+		PrivacyCA:            args.privacyCA,
+		AssetTagCA:           args.tagCA,
+		FlavorSigningCert:    args.flavorSigningCert,
+		FlavorCA:             args.flavorCA,
+		FlavorCoSigningCerts: args.coSigningCerts,
+	})
+	if err != nil {
+		return false, err
+	}
+	certs.FlavorSignatureQuorum = args.signatureQuorum
+	certs.MaxQuoteAge = args.maxQuoteAge
+	certs.ClockSkewThreshold = args.skewThreshold
+	if args.verificationTime != "" {
+		if certs.VerificationTime, err = time.Parse(time.RFC3339, args.verificationTime); err != nil {
+			return false, errors.Wrap(err, "Invalid verification time")
+		}
+	}
+
+	var hostManifest types.HostManifest
+	if err = readJSON(args.manifest, &hostManifest); err != nil {
+		return false, err
+	}
+	flavors, err := readFlavors(args.flavors)
+	if err != nil {
+		return false, err
+	}
+
+	trustReport, err := verifier.VerifyEvidence(certs, &hostManifest, flavors, args.skipSignature)
+	if err != nil {
+		return false, err
+	}
+
+	report, err := json.MarshalIndent(trustReport, "", "    ")
+	if err != nil {
+		return false, errors.Wrap(err, "Error encoding the trust report")
+	}
+	report = append(report, '\n')
+	if args.output == "" {
+		_, err = os.Stdout.Write(report)
+	} else {
+		err = ioutil.WriteFile(args.output, report, 0644)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "Error writing the trust report")
+	}
+	return trustReport.Trusted, nil
+}
+
+// readFlavors reads a signed flavor, a list of signed flavors or a signed flavor collection
+func readFlavors(path string) ([]hvs.SignedFlavor, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading "+path)
+	}
+
+	var flavors []hvs.SignedFlavor
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &flavors)
+	} else {
+		var collection hvs.SignedFlavorCollection
+		if err = json.Unmarshal(data, &collection); err == nil && len(collection.SignedFlavors) > 0 {
+			return collection.SignedFlavors, nil
+		}
+		var signedFlavor hvs.SignedFlavor
+		if err = json.Unmarshal(data, &signedFlavor); err == nil {
+			flavors = append(flavors, signedFlavor)
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error decoding "+path)
+	}
+	return flavors, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "Error reading "+path)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return errors.Wrap(err, "Error decoding "+path)
+	}
+	return nil
+}
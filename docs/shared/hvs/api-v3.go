@@ -0,0 +1,23 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// The operations of the API are also served below /hvs/v3/, side by side with /hvs/v2/. The v3 API differs in the
+// following:
+//   - The collections are returned in a CollectionPage envelope. The page is selected with the limit (1 to 1000,
+//     100 by default) and offset query parameters, the next link of the envelope returns the following page.
+//   - A POST request sent with an Idempotency-Key header is processed once, the response is replayed with the
+//     Idempotent-Replayed header when the request is sent again with the same key within 24 hours. A key reused
+//     with a different request is rejected with 422, a key whose request is still in progress with 409.
+
+// CollectionPage response payload of the v3 API collections
+// swagger:parameters CollectionPage
+type CollectionPage struct {
+	// in:body
+	Body hvs.CollectionPage
+}
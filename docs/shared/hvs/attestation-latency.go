@@ -0,0 +1,86 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// AttestationLatencyMetrics response payload
+// swagger:parameters AttestationLatencyMetrics
+type AttestationLatencyMetrics struct {
+	// in:body
+	Body hvs.AttestationLatencyMetrics
+}
+
+// ---
+
+// swagger:operation GET /attestation-latency AttestationLatency Retrieve-AttestationLatency
+// ---
+// description: |
+//   Retrieves the attestation latency of the reports created since HVS was started, broken down per stage. The
+//   stages are queue_wait, quote_retrieval, flavor_match, rule_evaluation, signing and persistence, the total
+//   is the end to end latency of the attestation. The timings of each report are also returned in the
+//   stage_timings of the report.
+//
+//   When fvs.attestation-latency-budget is configured, the reports whose total latency exceeds the budget are
+//   counted and aggregated separately in over_budget_stages, and their breakdown is logged.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | report_count                   | Number of reports created. |
+//    | budget_ms                      | Configured latency budget in milliseconds. Omitted when no budget is configured. |
+//    | over_budget_count              | Number of reports whose total latency exceeded the budget. |
+//    | stages                         | Total, average, maximum and last latency of each stage in milliseconds. |
+//    | over_budget_stages             | Latency of each stage over the reports that exceeded the budget. |
+//
+// x-permissions: attestation_latency:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the attestation latency.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/AttestationLatencyMetrics"
+//   '415':
+//     description: Invalid Accept Header in Request
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/attestation-latency
+// x-sample-call-output: |
+//   {
+//        "report_count": 2,
+//        "budget_ms": 1000,
+//        "over_budget_count": 1,
+//        "stages": {
+//            "queue_wait": {"total_ms": 40, "average_ms": 20, "max_ms": 30, "last_ms": 30},
+//            "quote_retrieval": {"total_ms": 1800, "average_ms": 900, "max_ms": 1500, "last_ms": 1500},
+//            "flavor_match": {"total_ms": 24, "average_ms": 12, "max_ms": 14, "last_ms": 14},
+//            "rule_evaluation": {"total_ms": 16, "average_ms": 8, "max_ms": 9, "last_ms": 9},
+//            "signing": {"total_ms": 10, "average_ms": 5, "max_ms": 5, "last_ms": 5},
+//            "persistence": {"total_ms": 20, "average_ms": 10, "max_ms": 12, "last_ms": 12},
+//            "total": {"total_ms": 1910, "average_ms": 955, "max_ms": 1570, "last_ms": 1570}
+//        },
+//        "over_budget_stages": {
+//            "queue_wait": {"total_ms": 30, "average_ms": 30, "max_ms": 30, "last_ms": 30},
+//            "quote_retrieval": {"total_ms": 1500, "average_ms": 1500, "max_ms": 1500, "last_ms": 1500},
+//            "flavor_match": {"total_ms": 14, "average_ms": 14, "max_ms": 14, "last_ms": 14},
+//            "rule_evaluation": {"total_ms": 9, "average_ms": 9, "max_ms": 9, "last_ms": 9},
+//            "signing": {"total_ms": 5, "average_ms": 5, "max_ms": 5, "last_ms": 5},
+//            "persistence": {"total_ms": 12, "average_ms": 12, "max_ms": 12, "last_ms": 12},
+//            "total": {"total_ms": 1570, "average_ms": 1570, "max_ms": 1570, "last_ms": 1570}
+//        }
+//   }
+
+// ---
@@ -0,0 +1,133 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// AuditEventCollection response payload
+// swagger:parameters AuditEventCollection
+type AuditEventCollection struct {
+	//	in:body
+	Body hvs.AuditEventCollection
+}
+
+// ---
+
+// swagger:operation GET /audit-events AuditEvent Search-AuditEvents
+// ---
+// description: |
+//   Searches the audit trail of the HVS API, the most recent events first. Every POST, PUT and DELETE call to an
+//   authenticated HVS API is recorded with the subject of the token of the user that made it, its route and its
+//   outcome, including the calls that were rejected. The state of the flavor or flavorgroup changed by a call is
+//   recorded before and after the call.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | actor                          | Subject of the token of the user that made the call. |
+//    | tenant_id                      | Tenant of the user, when the user belongs to a tenant. |
+//    | method                         | POST, PUT or DELETE. |
+//    | route                          | Path of the call below the API version. |
+//    | status_code                    | HTTP status code of the response. |
+//    | entity_type                    | First segment of the route, e.g. flavors or flavorgroups. |
+//    | entity_id                      | ID of the resource in the route, when the route has one. |
+//    | summary                        | Summary of the change and its outcome. |
+//    | before                         | State of the flavor or flavorgroup before the call. |
+//    | after                          | State of the flavor or flavorgroup after the call, the created resources for a create call. |
+//
+// x-permissions: audit_events:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: actor
+//     description: Subject of the token of the user that made the calls.
+//     in: query
+//     type: string
+//     required: false
+//   - name: method
+//     description: Method of the calls. POST, PUT or DELETE.
+//     in: query
+//     type: string
+//     required: false
+//   - name: entityType
+//     description: Type of the resource changed by the calls, e.g. flavors.
+//     in: query
+//     type: string
+//     required: false
+//   - name: entityId
+//     description: ID of the resource changed by the calls.
+//     in: query
+//     type: string
+//     format: uuid
+//     required: false
+//   - name: fromDate
+//     description: |
+//       Events recorded from this date. Date must be in one of the formats yyyy-MM-ddTHH:mm:ss.SSSZ,
+//       yyyy-MM-dd HH:mm:ss or yyyy-MM-dd.
+//     in: query
+//     type: string
+//     required: false
+//   - name: toDate
+//     description: Events recorded until this date, in the formats of fromDate.
+//     in: query
+//     type: string
+//     required: false
+//   - name: limit
+//     description: Maximum number of events returned. Defaults to 10000.
+//     in: query
+//     type: integer
+//     required: false
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully searched the AuditEvents.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/AuditEventCollection"
+//   '400':
+//     description: Invalid search criteria provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/audit-events?entityType=flavors&method=DELETE
+// x-sample-call-output: |
+//   {
+//        "audit_events" : [
+//            {
+//                "id"          : "4f8d2b1e-5c3a-4e7f-9b6d-2a1c0e9f8d7b",
+//                "created"     : "2020-10-08T10:15:30.123Z",
+//                "actor"       : "admin@hvs",
+//                "remote_addr" : "10.105.168.1:51122",
+//                "method"      : "DELETE",
+//                "route"       : "/flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3",
+//                "status_code" : 204,
+//                "entity_type" : "flavors",
+//                "entity_id"   : "c36b5412-8c02-4e08-8a74-8bfa40425cf3",
+//                "summary"     : "delete /flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3 succeeded",
+//                "before"      : {
+//                    "flavor"    : {
+//                        "meta" : {
+//                            "id"          : "c36b5412-8c02-4e08-8a74-8bfa40425cf3",
+//                            "description" : {
+//                                "flavor_part" : "PLATFORM",
+//                                "label"       : "INTEL_IntelCorporation_SE5C620.86B.00.01.0014.070920180847_TPM2.0"
+//                            }
+//                        }
+//                    },
+//                    "signature" : "..."
+//                }
+//            }
+//        ]
+//   }
@@ -39,11 +39,11 @@ type CaCertificateCollection struct {
 //   - application/x-pem-file
 // parameters:
 //   - name: domain
-//     description: Available Certificate Domains are {saml, ek, endorsement, root}
+//     description: Available Certificate Domains are {saml, ek, endorsement, platform, rim, root}
 //     in: query
 //     type: string
 //     required: true
-//     enum: [saml, ek, endorsement, root]
+//     enum: [saml, ek, endorsement, platform, rim, root]
 //   - name: Accept
 //     description: Accept header
 //     in: header
@@ -121,7 +121,7 @@ type CaCertificateCollection struct {
 //     schema:
 //       $ref: "#/definitions/CaCertificate"
 //   '400':
-//     description: Invalid CACertificate in request body/Invalid type, only root or endorsement ca certificate can be added
+//     description: Invalid CACertificate in request body/Invalid type, only root, endorsement, platform or rim ca certificate can be added
 //   '415':
 //     description: Invalid Accept/Content-Type Header in Request - should be application/json
 //   '500':
@@ -152,7 +152,7 @@ type CaCertificateCollection struct {
 //   - application/json
 // parameters:
 //   - name: certType
-//     description: Available Certificate Types are {root, endorsement, ek, privacy, aik, tag, saml, tls}
+//     description: Available Certificate Types are {root, endorsement, ek, privacy, aik, tag, platform, rim, saml, tls}
 //     in: path
 //     type: string
 //     required: true
@@ -163,6 +163,8 @@ type CaCertificateCollection struct {
 //       - privacy
 //       - aik
 //       - tag
+//       - platform
+//       - rim
 //       - saml
 //       - tls
 //   - name: Accept
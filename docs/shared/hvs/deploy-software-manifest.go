@@ -16,6 +16,20 @@ type DeployManifestRequest struct {
 	Body hvs.DeployManifestRequest
 }
 
+// DeploySoftwareManifests API request payload
+// swagger:parameters DeploySoftwareManifests
+type DeployManifestsRequest struct {
+	// in:body
+	Body hvs.DeployManifestsRequest
+}
+
+// DeploySoftwareManifests API response payload
+// swagger:parameters DeployManifestsResponse
+type DeployManifestsResponse struct {
+	// in:body
+	Body hvs.DeployManifestsResponse
+}
+
 // ---
 //
 // swagger:operation POST /rpc/deploy-software-manifest Deploy-Software-Manifest Deploy-Software-Manifest
@@ -62,3 +76,86 @@ type DeployManifestRequest struct {
 //         "host_id":"d9d43923-05ae-4c8a-a64f-eba02473010d"
 //      }
 // ---
+
+// swagger:operation POST /rpc/deploy-software-manifests Deploy-Software-Manifest Deploy-Software-Manifests
+// ---
+//
+// description: |
+//              Creates the manifests of several software flavors and deploys them to the host in one call. The
+//              flavors that are not found or are not SOFTWARE flavors are reported in their result and the other
+//              manifests are still deployed. A manifest that fails to deploy does not prevent the deployment of
+//              the next ones.
+//              When verify_measurements is set, the host manifest is retrieved after the deployment and each
+//              deployed manifest is reported as measured when the PCR 15 event log of the host contains the
+//              measurement of its flavor. The measurements are extended to PCR 15 when the host boots, a manifest
+//              deployed to a host that has not rebooted since is reported as not measured. The measured field is
+//              omitted when the host manifest cannot be retrieved.
+//
+// x-permissions: software_flavors:deploy
+// security:
+//  - bearerAuth: []
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/DeployManifestsRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Deployed the application manifests, the result of each manifest is in the response.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/DeployManifestsResponse"
+//   '400':
+//     description: Invalid request body provided
+//   '415':
+//     description: Invalid Content-Type Header
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/rpc/deploy-software-manifests
+// x-sample-call-input: |
+//      {
+//         "host_id":"d9d43923-05ae-4c8a-a64f-eba02473010d",
+//         "flavor_ids":[
+//            "436c729a-e3a6-4d71-8ea2-fc3b459bd4b3",
+//            "71e4c52e-595a-429d-9917-1965b437c353"
+//         ],
+//         "verify_measurements":true
+//      }
+// x-sample-call-output: |
+//      {
+//         "host_id":"d9d43923-05ae-4c8a-a64f-eba02473010d",
+//         "results":[
+//            {
+//               "flavor_id":"436c729a-e3a6-4d71-8ea2-fc3b459bd4b3",
+//               "deployed":true,
+//               "measured":false
+//            },
+//            {
+//               "flavor_id":"71e4c52e-595a-429d-9917-1965b437c353",
+//               "deployed":false,
+//               "error":"Flavor associated with the provided flavor id is not a SOFTWARE flavor"
+//            }
+//         ]
+//      }
+// ---
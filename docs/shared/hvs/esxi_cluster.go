@@ -21,6 +21,13 @@ type ESXiCluster struct {
 	Body hvs.ESXiCluster
 }
 
+// ESXiClusterTrust response payload
+// swagger:parameters ESXiClusterTrust
+type ESXiClusterTrust struct {
+	// in:body
+	Body hvs.ESXiClusterTrust
+}
+
 // ESXiClusterCollection response payload
 // swagger:parameters ESXiClusterCollection
 type ESXiClusterCollection struct {
@@ -216,6 +223,82 @@ type ESXiClusterCollection struct {
 
 // ---
 
+// swagger:operation GET /esxi-cluster/{esxi-cluster_id}/trust ESXi-Cluster Retrieve-ESXi-cluster-trust
+// ---
+//
+// description: |
+//   Retrieves the trust of an ESXi cluster rolled up from the latest reports of its hosts. The cluster is trusted when all its hosts are trusted.
+//   The hosts that are not registered yet or have no report are counted as unknown, and the number of untrusted hosts is given for each flavor part.
+//   When the trust score of the reports is configured, the trust_score of the cluster is the average of the scores of its hosts.
+// x-permissions: esxi_clusters:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: esxi-cluster_id
+//   description: Unique ID of the ESXi cluster.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the trust of the ESXi cluster.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ESXiClusterTrust"
+//   '404':
+//     description: No relevant cluster records found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/esxi-cluster/9519febc-2c8d-4bb0-afec-b7a23db5735a/trust
+// x-sample-call-output: |
+//          {
+//              "id": "9519febc-2c8d-4bb0-afec-b7a23db5735a",
+//              "cluster_name": "Cluster name",
+//              "trusted": false,
+//              "host_count": 2,
+//              "trusted_count": 1,
+//              "untrusted_count": 1,
+//              "unknown_count": 0,
+//              "untrusted_flavor_parts": {
+//                  "OS": 1
+//              },
+//              "hosts": [
+//                  {
+//                      "host_name": "host.ip1",
+//                      "host_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+//                      "trusted": true,
+//                      "report_created": "2021-03-10T07:18:00.57Z",
+//                      "report_expiration": "2021-03-11T07:18:00.57Z"
+//                  },
+//                  {
+//                      "host_name": "host.ip2",
+//                      "host_id": "e57e5ea0-d465-461e-882d-1600090caa0d",
+//                      "trusted": false,
+//                      "untrusted_flavor_parts": [
+//                          "OS"
+//                      ],
+//                      "report_created": "2021-03-10T07:20:12.02Z",
+//                      "report_expiration": "2021-03-11T07:20:12.02Z"
+//                  }
+//              ]
+//          }
+
+// ---
+
 // swagger:operation DELETE /esxi-cluster/{esxi-cluster_id} ESXi-Cluster Delete-ESXi-cluster-record
 // ---
 //
@@ -0,0 +1,139 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// FlavorFromRim API request payload
+// swagger:parameters RimImportRequest
+type RimImportRequest struct {
+	// in:body
+	Body hvs.RimImportRequest
+}
+
+// ---
+//
+// swagger:operation POST /flavor-from-rim Flavor-From-Rim Create-Platform-Flavor
+// ---
+//
+// description: |
+//      A reference integrity manifest (RIM) is published by a platform vendor to describe the expected firmware measurements of a platform, as defined by the TCG PC Client Reference Integrity Manifest specification. A RIM is made of a base RIM, which is a SWID tag signed by the vendor, and a support RIM, which is the TCG event log with the reference measurements. The base RIM references the support RIM by its SHA256 hash.
+//
+//      The Verification Service exposes this REST API to import a RIM and create the PLATFORM flavor of the firmware PCRs 0 to 7 of the SHA1 and SHA256 banks, with the expected events of the support RIM. The PCR values of the flavor are computed by replaying the expected events, and the hosts are verified against the flavor and its event logs like any other PLATFORM flavor.
+//
+//      The signer certificate of the base RIM must be issued by one of the trusted RIM CAs, which are added with the certificate type 'rim' through the ca-certificates API. The signer certificate and any intermediate CA certificates must be carried in the KeyInfo of the XML signature.
+//
+//      The serialized RimImportRequest Go struct object represents the content of the request body.
+//
+//        | Attribute                      | Description                                     |
+//        |--------------------------------|-------------------------------------------------|
+//        | base_rim                       | Base64 encoded signed SWID tag of the base RIM. |
+//        | support_rim                    | Base64 encoded TCG event log of the support RIM referenced by the payload of the base RIM. |
+//        | feature                        | (Optional) The platform features expected on the hosts, such as TXT or CBNT, which are not described by the RIM. |
+//        | flavorgroup_names              | (Optional) Name of the flavor groups the created flavor needs to be associated to. If not provided, flavor is associated to default flavor group.|
+//
+//
+// x-permissions: flavors:create
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/RimImportRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '201':
+//     description: Successfully created the platform flavor.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/SignedFlavorCollection"
+//   '400':
+//     description: Invalid request body provided, the base RIM is not signed by a trusted RIM signer or the support RIM is not referenced by the base RIM
+//   '415':
+//     description: Invalid Accept/Content-Type Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-from-rim
+// x-sample-call-input: |
+//   {
+//      "base_rim": "PFNvZnR3YXJlSWRlbnRpdHkgeG1sbnM9Imh0dHA6Ly9zdGFuZGFyZHMuaXNvLm9yZy9pc28vMTk3NzAvLTIvMjAxNS9zY2hlbWEueHNkIi...",
+//      "support_rim": "AAAAAAMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC0AAABTcGVjIElEIEV2ZW50MDMAAAAAAAACAAIC...",
+//      "flavorgroup_names": ["automatic"]
+//   }
+//
+// x-sample-call-output: |
+//   {
+//      "signed_flavors": [
+//          {
+//              "flavor": {
+//                  "meta": {
+//                      "id": "3bc2ba1a-0f4f-4bd3-9f0c-bb5cd2d3a5f4",
+//                      "description": {
+//                          "flavor_part": "PLATFORM",
+//                          "source": "94f6b457-9ac9-4d35-9b3f-78804173b65a",
+//                          "label": "INTEL_Example.com_1.0.13_10-15-2026_12-31-08",
+//                          "bios_name": "Example.com",
+//                          "bios_version": "1.0.13",
+//                          "tpm_version": "2.0"
+//                      },
+//                      "vendor": "INTEL"
+//                  },
+//                  "bios": {
+//                      "bios_name": "Example.com",
+//                      "bios_version": "1.0.13"
+//                  },
+//                  "hardware": {
+//                      "feature": {
+//                          "TPM": {
+//                              "enabled": true,
+//                              "version": "2.0"
+//                          }
+//                      }
+//                  },
+//                  "pcrs": {
+//                      "SHA256": {
+//                          "pcr_0": {
+//                              "value": "eb6f94f1a4c9e0c2d4dcfd7e51e1bb9b39b1e4a92c1e04f38b19b78f3b6b9b22",
+//                              "event": [
+//                                  {
+//                                      "value": "7f2f3a8e4d6e3b1b5f2e4d0a1c9b8e7f6a5d4c3b2a1908f7e6d5c4b3a2918070",
+//                                      "label": "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+//                                      "info": {
+//                                          "EventName": "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+//                                          "EventType": "0x80000008"
+//                                      }
+//                                  }
+//                              ]
+//                          }
+//                      }
+//                  }
+//              },
+//              "signature": "EyuFK0QFnDj8I7u3p6kqL7Jx5fW1sQJZyQ5e8aQ0L9F8hQnS5pXGm3b0Yc..."
+//          }
+//      ]
+//   }
+// ---
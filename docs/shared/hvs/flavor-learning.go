@@ -0,0 +1,312 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// FlavorLearning response payload
+// swagger:parameters FlavorLearning
+type FlavorLearning struct {
+	// in:body
+	Body hvs.FlavorLearning
+}
+
+// FlavorLearningCollection response payload
+// swagger:parameters FlavorLearningCollection
+type FlavorLearningCollection struct {
+	//	in:body
+	Body hvs.FlavorLearningCollection
+}
+
+// FlavorLearningProposal response payload
+// swagger:parameters FlavorLearningProposal
+type FlavorLearningProposal struct {
+	//	in:body
+	Body hvs.FlavorLearningProposal
+}
+
+// ---
+
+// swagger:operation POST /flavor-learning FlavorLearning Create-FlavorLearning
+// ---
+// description: |
+//   Creates a FlavorLearning which designates a set of golden hosts and a time window over which their event logs
+//   are learnt. The host manifests retrieved by HVS from the golden hosts during the window, for instance after
+//   each reboot following a kernel or firmware update, are used to propose a flavor with the Retrieve-FlavorLearningProposal
+//   API.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | label                          | Unique label of the FlavorLearning, used as the label of the proposed flavor. |
+//    | flavor_part                    | Flavor part of the proposed flavor. PLATFORM or OS. |
+//    | pcrs                           | Indices of the PCRs to learn. |
+//    | host_ids                       | IDs of the registered golden hosts. |
+//    | start_time                     | (Optional) Start of the learning window. Defaults to the current time. |
+//    | end_time                       | End of the learning window. |
+//
+// x-permissions: flavor_learning:create
+// security:
+//   - bearerAuth: []
+// consumes:
+//   - application/json
+// produces:
+//   - application/json
+// parameters:
+//   - name: request body
+//     required: true
+//     in: body
+//     schema:
+//       "$ref": "#/definitions/FlavorLearning"
+//   - name: Content-Type
+//     description: Content-Type header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '201':
+//     description: Successfully created the FlavorLearning.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorLearning"
+//   '400':
+//     description: Invalid request body provided or golden host not registered
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-learning
+// x-sample-call-input: |
+//   {
+//        "label"       : "rhel-kernel-4.18.0-193",
+//        "flavor_part" : "OS",
+//        "pcrs"        : [17, 18],
+//        "host_ids"    : ["ee37c360-7eae-4250-a677-6ee12adce8e2", "e57e5ea0-d465-461e-882d-1600090caa0d"],
+//        "end_time"    : "2020-10-08T00:00:00Z"
+//   }
+// x-sample-call-output: |
+//   {
+//        "id"          : "0c4d6d86-4b4b-4b10-a5f6-e57c7a8b3c1e",
+//        "label"       : "rhel-kernel-4.18.0-193",
+//        "flavor_part" : "OS",
+//        "pcrs"        : [17, 18],
+//        "host_ids"    : ["ee37c360-7eae-4250-a677-6ee12adce8e2", "e57e5ea0-d465-461e-882d-1600090caa0d"],
+//        "start_time"  : "2020-10-01T00:00:00Z",
+//        "end_time"    : "2020-10-08T00:00:00Z"
+//   }
+
+// ---
+
+// swagger:operation GET /flavor-learning FlavorLearning Search-FlavorLearning
+// ---
+// description: |
+//   Searches the FlavorLearnings.
+//
+// x-permissions: flavor_learning:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: id
+//     description: FlavorLearning ID
+//     in: query
+//     type: string
+//     format: uuid
+//     required: false
+//   - name: labelEqualTo
+//     description: Label of the FlavorLearning.
+//     in: query
+//     type: string
+//     required: false
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully searched the FlavorLearnings.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorLearningCollection"
+//   '400':
+//     description: Invalid search criteria provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-learning?labelEqualTo=rhel-kernel-4.18.0-193
+
+// ---
+
+// swagger:operation GET /flavor-learning/{flavor-learning_id} FlavorLearning Retrieve-FlavorLearning
+// ---
+// description: |
+//   Retrieves a FlavorLearning.
+//   Returns - The serialized FlavorLearning Go struct object that was retrieved
+// x-permissions: flavor_learning:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: flavor-learning_id
+//     description: Unique ID of the FlavorLearning.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the FlavorLearning.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorLearning"
+//   '404':
+//     description: No relevant FlavorLearning record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-learning/0c4d6d86-4b4b-4b10-a5f6-e57c7a8b3c1e
+
+// ---
+
+// swagger:operation GET /flavor-learning/{flavor-learning_id}/proposal FlavorLearning Retrieve-FlavorLearningProposal
+// ---
+// description: |
+//   Proposes a flavor learnt from the host manifests collected on the golden hosts between the start of the learning
+//   window and the current time, or the end of the window once it is over.
+//
+//   For each PCR the events measured in every host manifest are kept in the proposed flavor. PCRs whose values differ
+//   across the host manifests are listed in volatile_pcrs and left out of the flavor, events that were not measured in
+//   every host manifest are listed in volatile_events so that they can be reviewed and excluded. The proposed flavor
+//   can be imported with the Create-Flavor API.
+// x-permissions: flavor_learning:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: flavor-learning_id
+//     description: Unique ID of the FlavorLearning.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully proposed a flavor.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorLearningProposal"
+//   '400':
+//     description: The learning window has not started or no host manifests were collected
+//   '404':
+//     description: No relevant FlavorLearning record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-learning/0c4d6d86-4b4b-4b10-a5f6-e57c7a8b3c1e/proposal
+// x-sample-call-output: |
+//   {
+//        "flavor_learning_id"  : "0c4d6d86-4b4b-4b10-a5f6-e57c7a8b3c1e",
+//        "host_manifest_count" : 6,
+//        "flavor": {
+//            "meta": {
+//                "id": "b37580d8-a7ab-4a3b-a1a4-de7b1d2a5e9c",
+//                "description": {
+//                    "flavor_part": "OS",
+//                    "label": "rhel-kernel-4.18.0-193",
+//                    "os_name": "RedHatEnterprise",
+//                    "os_version": "8.1",
+//                    "tpm_version": "2.0",
+//                    "tboot_installed": "true"
+//                },
+//                "vendor": "INTEL"
+//            },
+//            "pcrs": {
+//                "SHA256": {
+//                    "pcr_18": {
+//                        "value": "d9e55bd1c570a6408fb1368f3663ae92747241fc4d2a3622cef0efadae284d75"
+//                    }
+//                }
+//            }
+//        },
+//        "volatile_pcrs": {
+//            "SHA256": ["pcr_17"]
+//        },
+//        "volatile_events": {
+//            "SHA256": {
+//                "pcr_17": [
+//                    {
+//                        "digest_type": "com.intel.mtwilson.core.common.model.MeasurementSha256",
+//                        "value": "3c585604e87f855973731fea83e21fab9392d2fc9f9d4d47ee4e1a7c4c4f2a91",
+//                        "label": "LCP_DETAILS_HASH",
+//                        "info": {
+//                            "ComponentName": "LCP_DETAILS_HASH",
+//                            "EventName": "OpenSource.EventName"
+//                        }
+//                    }
+//                ]
+//            }
+//        }
+//   }
+
+// ---
+
+// swagger:operation DELETE /flavor-learning/{flavor-learning_id} FlavorLearning Delete-FlavorLearning
+// ---
+//  description: |
+//    Deletes a FlavorLearning.
+//  x-permissions: flavor_learning:delete
+//  security:
+//    - bearerAuth: []
+//  parameters:
+//    - name: flavor-learning_id
+//      description: Unique ID of the FlavorLearning.
+//      in: path
+//      required: true
+//      type: string
+//      format: uuid
+//  responses:
+//    '204':
+//      description: Successfully deleted the FlavorLearning.
+//    '404':
+//      description: No relevant FlavorLearning record found.
+//    '500':
+//      description: Internal server error
+//  x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-learning/0c4d6d86-4b4b-4b10-a5f6-e57c7a8b3c1e
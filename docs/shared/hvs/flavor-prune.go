@@ -0,0 +1,235 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// FlavorPrune response payload
+// swagger:parameters FlavorPrune
+type FlavorPrune struct {
+	// in:body
+	Body hvs.FlavorPrune
+}
+
+// FlavorPruneCollection response payload
+// swagger:parameters FlavorPruneCollection
+type FlavorPruneCollection struct {
+	//	in:body
+	Body hvs.FlavorPruneCollection
+}
+
+// ---
+
+// swagger:operation POST /flavor-prunes FlavorPrune Create-FlavorPrune
+// ---
+// description: |
+//   Retires the superseded versions of the auto-updated flavor families, the PLATFORM and OS flavors imported from
+//   the same host for the same vendor, bios or os and tpm version. The newest versions of each family are kept as per
+//   the policy. A superseded version referenced by a trusted rule result of the latest report of any host is kept
+//   and listed in in_use_flavors. The retired flavors are deleted and recorded with the FlavorPrune, so that they can
+//   be restored with the Undo-FlavorPrune API. When preview is set, the versions that would be retired are returned
+//   and nothing is deleted or recorded. Only the flavors of the tenant of the user are pruned, and the FlavorPrunes
+//   are only retrieved, searched and undone by the tenant that created them.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | policy.keep_versions           | Number of newest versions of each family to keep. At least 1. |
+//    | policy.min_age_days            | (Optional) Versions created less than min_age_days ago are kept. |
+//    | policy.flavor_parts            | (Optional) Flavor parts to prune. PLATFORM and/or OS. Defaults to both. |
+//    | preview                        | (Optional) Returns the versions that would be retired without retiring them. |
+//
+// x-permissions: flavor_prunes:create
+// security:
+//   - bearerAuth: []
+// consumes:
+//   - application/json
+// produces:
+//   - application/json
+// parameters:
+//   - name: request body
+//     required: true
+//     in: body
+//     schema:
+//       "$ref": "#/definitions/FlavorPrune"
+//   - name: Content-Type
+//     description: Content-Type header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully previewed the FlavorPrune.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorPrune"
+//   '201':
+//     description: Successfully retired the superseded flavor versions.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorPrune"
+//   '400':
+//     description: Invalid request body provided
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-prunes
+// x-sample-call-input: |
+//   {
+//        "policy" : {
+//            "keep_versions" : 2,
+//            "min_age_days"  : 30
+//        }
+//   }
+// x-sample-call-output: |
+//   {
+//        "id"     : "b3a4f1c2-7d0e-4c5b-9a61-2f8e0c9d4e17",
+//        "policy" : {
+//            "keep_versions" : 2,
+//            "min_age_days"  : 30
+//        },
+//        "created"         : "2020-10-08T00:00:00Z",
+//        "retired_flavors" : [
+//            {
+//                "flavor_id"       : "890d4c3b-2d3f-4f0c-9c51-7e8a6b1d2c3e",
+//                "label"           : "INTEL_IntelCorporation_SE5C620.86B.00.01.0014.070920180847_TPM2.0_06-05-2020_01-31-00",
+//                "flavor_part"     : "PLATFORM",
+//                "family"          : "PLATFORM_INTEL_https://tagent:1443_IntelCorporation_2.0",
+//                "created"         : "2020-06-05T01:31:00Z",
+//                "flavorgroup_ids" : ["d8f4aa4a-6a48-4c8c-8b0d-8a3c2e2c5f0b"]
+//            }
+//        ]
+//   }
+
+// ---
+
+// swagger:operation GET /flavor-prunes FlavorPrune Search-FlavorPrune
+// ---
+// description: |
+//   Lists the FlavorPrunes, the most recent first.
+//
+// x-permissions: flavor_prunes:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully searched the FlavorPrunes.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorPruneCollection"
+//   '400':
+//     description: Query parameters provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-prunes
+
+// ---
+
+// swagger:operation GET /flavor-prunes/{flavor-prune_id} FlavorPrune Retrieve-FlavorPrune
+// ---
+// description: |
+//   Retrieves a FlavorPrune.
+//   Returns - The serialized FlavorPrune Go struct object that was retrieved
+// x-permissions: flavor_prunes:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: flavor-prune_id
+//     description: Unique ID of the FlavorPrune.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the FlavorPrune.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorPrune"
+//   '404':
+//     description: No relevant FlavorPrune record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-prunes/b3a4f1c2-7d0e-4c5b-9a61-2f8e0c9d4e17
+
+// ---
+
+// swagger:operation POST /flavor-prunes/{flavor-prune_id}/undo FlavorPrune Undo-FlavorPrune
+// ---
+// description: |
+//   Restores the flavors retired by a FlavorPrune with their original creation time, in the flavorgroups that still
+//   exist, and queues the hosts of those flavorgroups for trust re-verification. A FlavorPrune can be undone once.
+//   Returns - The serialized FlavorPrune Go struct object with the time it was undone
+// x-permissions: flavor_prunes:create
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: flavor-prune_id
+//     description: Unique ID of the FlavorPrune.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully restored the retired flavors.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorPrune"
+//   '404':
+//     description: No relevant FlavorPrune record found.
+//   '409':
+//     description: The FlavorPrune is already undone.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error, for instance when a flavor with the same label was created since.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavor-prunes/b3a4f1c2-7d0e-4c5b-9a61-2f8e0c9d4e17/undo
@@ -24,6 +24,13 @@ type SignedFlavor struct {
 	Body hvs.SignedFlavor
 }
 
+// Flavor signatures API request payload
+// swagger:parameters FlavorSignatureCreateRequest
+type FlavorSignatureCreateRequest struct {
+	// in:body
+	Body models.FlavorSignatureCreateRequest
+}
+
 // Flavors API response payload
 // swagger:parameters SignedFlavorCollection
 type SignedFlavorCollection struct {
@@ -40,7 +47,10 @@ type SignedFlavorCollection struct {
 //   A flavor is a set of measurements and metadata organized in a flexible format that allows for ease of further extension. The measurements included in the flavor pertain to various hardware, software and feature categories, and their respective metadata sections provide descriptive information.
 //
 //   The four current flavor categories:
-//   PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE (See the product guide for a detailed explanation)
+//   PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE, CONTAINER_IMAGE (See the product guide for a detailed explanation)
+//
+//   CONTAINER_IMAGE flavors list the expected digest, and optionally the dm-verity root hash, of container images. They
+//   are verified against the container image measurements reported by the workload agent of Linux hosts.
 //
 //   When a flavor is created, it is associated with a flavor group. This means that the measurements for that flavor type are deemed acceptable to obtain a trusted status. If a host, associated with the same flavor group, matches the measurements contained within that flavor, the host is trusted for that particular flavor category (dependent on the flavor group policy). Searches for Flavor records. The identifying parameter can be specified as query to search flavors which will return flavor collection as a result.
 //
@@ -79,6 +89,16 @@ type SignedFlavorCollection struct {
 //   in: query
 //   type: string
 //   required: false
+// - name: metadataKey
+//   description: The name of a custom metadata field defined in the flavor metadata schema. Both metadataKey and metadataValue query parameters need to be specified.
+//   in: query
+//   type: string
+//   required: false
+// - name: metadataValue
+//   description: The value of the custom metadata field. When provided, metadataKey must be provided in query as well.
+//   in: query
+//   type: string
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -153,6 +173,18 @@ type SignedFlavorCollection struct {
 //
 //   If generic flavors are created, all hosts in the flavor group will be added to the backend queue, flavor verification process to re-evaluate their trust status. If host unique flavors are created, the individual affected hosts are added to the flavor verification process.
 //
+//   A delta flavor only overrides PCR values and event log entries of an existing base flavor, for instance after a kernel update. It is created by providing the flavor content with "base_flavor_id" set in the flavor meta section and only the changed PCRs in the pcrs section. The base flavor must exist, have the same flavor part and cannot be a delta flavor itself. Event log entries of the delta flavor replace the base flavor entries with the same label and are appended otherwise. During verification the delta flavor is merged with its base flavor and the signatures of both flavors are verified.
+//
+//   Flavor content can carry operator defined fields, such as the owner or the change ticket of the trust baseline, in the "custom_metadata" object of the flavor meta section. The fields are validated against the flavor-metadata-schema of the HVS configuration, which lists the name, type (string, integer, number, boolean or date) and whether the field is required. Fields that are not part of the schema are rejected. Flavors can be searched by custom metadata with the metadataKey and metadataValue query parameters.
+//
+//   Events of a PCR event log that change on every boot, such as boot counters or rotating LCP policy hashes, can be excluded from the PcrEventLogEquals, PcrEventLogEqualsExcluding and PcrEventLogIncludes rules with the "exclude" list of the PCR in the flavor content. Each exclusion has a "label" pattern and/or an "info" object of patterns per info field, an event is excluded when all the patterns of an exclusion match. Patterns are regular expressions matching the whole value, or wildcards where * matches any characters and ? a single character when "wildcard" is true. For example {"label": "LCP_*_HASH", "wildcard": true} or {"info": {"ComponentName": "commandLine\\..*"}}. The exclusions are part of the signed flavor and are listed in the rules of the trust report.
+//
+//   Flavor content can carry labels, such as {"intel.com/datacenter": "dc1"}, in the "labels" object of the flavor meta section. The flavor is a member of the flavorgroups of its tenant whose flavor selector matches its labels, in addition to the flavorgroups it is linked to, and the hosts of these flavorgroups are re-evaluated when it is created or updated. The keys are an optional DNS prefix and a name of alphanumerics, '-', '_' and '.', the values are made of the same characters.
+//
+//   A PLATFORM flavor can be built from the golden firmware measurements published by the platform vendor, such as a reference integrity manifest, instead of a live good known host with the "firmware_manifest" object. It lists the bios name and version, the platform features and the expected PCRs with their bank, index, value and/or events. The value of a PCR is computed by replaying its events when it is not given and must match the replay otherwise.
+//
+//   The flavors of a good known host HVS cannot connect to, such as a host of an air-gapped enclave, are created from the manifest of the host captured offline with the "host_manifest" object instead of the host connection string, for instance the host_manifest of a host status of a HVS the host is registered with. The same PLATFORM, OS, HOST_UNIQUE, SOFTWARE and ASSET_TAG flavors are created as from the host, the hardware UUID and the PCR values of the manifest are required.
+//
 //   The serialized FlavorCreateRequest Go struct object represents the content of the request body.
 //
 //    | Attribute                      | Description                                     |
@@ -162,8 +194,10 @@ type SignedFlavorCollection struct {
 //    |                                | For VMware, this includes the vCenter and host IP address or DNS host name i.e.: "vmware:https://vCenterServer.com:443/sdk;h=host;u=vCenterUsername;p=vCenterPassword" |
 //    | flavors                        | (Optional) A collection of flavors in the defined flavor format. No other parameters are needed in this case.
 //    | signed_flavors                 | (Optional) This is collection of signed flavors consisting of flavor and signature provided by user. |
+//    | host_manifest                  | (Optional) The host manifest of a good known host captured offline the flavors are created from. Cannot be provided with the host connection string or the firmware manifest. flavorgroup_names, partial_flavor_types can be provided as optional parameters along with the host manifest. |
+//    | firmware_manifest              | (Optional) The vendor published firmware measurements the PLATFORM flavor is built from. Cannot be provided with the host connection string, partial_flavor_types can only be PLATFORM. |
 //    | flavorgroup_names              | (Optional) Flavor group names that the created flavor(s) will be associated with. If not provided, created flavor will be associated with automatic flavor group. |
-//    | partial_flavor_types           | (Optional) List array input of flavor types to be imported from a host. Partial flavor type can be any of the following: PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE. Can be provided with the host connection string. See the product guide for more details on how flavor types are broken down for each host type. |
+//    | partial_flavor_types           | (Optional) List array input of flavor types to be imported from a host. Partial flavor type can be any of the following: PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE, CONTAINER_IMAGE. Can be provided with the host connection string. See the product guide for more details on how flavor types are broken down for each host type. |
 //
 // x-permissions: flavors:create
 // security:
@@ -484,3 +518,201 @@ type SignedFlavorCollection struct {
 // x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/f66ac31d-124d-418e-8200-2abf414a9adf
 
 // ---
+
+// swagger:operation POST /flavors/{flavor_id}/signatures Flavors Add-Flavor-Signature
+// ---
+//
+// description: |
+//   Adds the signature of a flavor co-signer to the additional signatures of a flavor. The signature is the base64
+//   encoded PKCS1 v1.5 signature of the SHA384 digest of the flavor, made with the key of one of the trusted
+//   flavor co-signing certificates in /etc/hvs/certs/trustedca/flavor-co-signing/. A co-signer signs a flavor once.
+//
+//   When fvs.flavor-signature-quorum is set, a flavor is trusted only when it is signed by that many of the trusted
+//   flavor signers, HVS and the co-signers, so that no single flavor signing key can make a flavor trusted. The hosts
+//   of the flavor are verified again once the signature is added.
+// x-permissions: flavors:sign
+// security:
+//  - bearerAuth: []
+// consumes:
+//  - application/json
+// produces:
+//  - application/json
+// parameters:
+// - name: flavor_id
+//   description: Unique UUID of the flavor.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorSignatureCreateRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '201':
+//     description: Successfully added the signature to the flavor.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/SignedFlavor"
+//   '400':
+//     description: Invalid request body, or the signature is not made by a trusted flavor co-signer.
+//   '404':
+//     description: No flavor with the provided flavor ID found.
+//   '409':
+//     description: The flavor is already signed by the co-signer.
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/f66ac31d-124d-418e-8200-2abf414a9adf/signatures
+// x-sample-call-input: |
+//  {
+//    "signature": "Tq3NV7tLfAv0SPcw4kQm6ZXcDjB5dT8QdeJ/2ZQCdbcNEUCkd7A9QhS0+9wPu8trZrpKzr3Qb1HWgYH2yIXEzJ/hUOx1g7n..."
+//  }
+// x-sample-call-output: |
+//  {
+//    "flavor": {
+//        "meta": {
+//            "id": "f66ac31d-124d-418e-8200-2abf414a9adf",
+//            "description": {
+//                "flavor_part": "SOFTWARE",
+//                "label": "ISL_Applications123",
+//                "digest_algorithm": "SHA384"
+//            }
+//        },
+//        "software": {
+//            ...
+//        }
+//    },
+//    "signature": "aas8/Nv7yYuwx2ZIOMrXFpNf333tBJgr87Dpo7Z5jjUR36Estlb8pYaTGN4Dz9JtbXZy2uIBLr1wjhkHVWm2r1FQq+2yJznXGCp...",
+//    "additional_signatures": [
+//        "Tq3NV7tLfAv0SPcw4kQm6ZXcDjB5dT8QdeJ/2ZQCdbcNEUCkd7A9QhS0+9wPu8trZrpKzr3Qb1HWgYH2yIXEzJ/hUOx1g7n..."
+//    ]
+//  }
+
+// ---
+
+// swagger:operation PATCH /flavors/{flavor_id} Flavors Update-Flavor
+// ---
+//
+// description: |
+//   Updates a flavor with a JSON merge patch (RFC 7386), for instance to change the label or the custom metadata of
+//   a flavor, or to fix a single expected PCR value or event log entry without recreating the flavor. Only the meta
+//   and pcrs sections of a flavor can be patched, and the id and flavor part of a flavor cannot be changed. The
+//   members of the patch replace the members of the same name in the flavor, objects are merged recursively, arrays
+//   such as the events of a PCR are replaced as a whole and null members are removed.
+//
+//   The patched flavor is signed again with the flavor signing key of HVS. The co-signatures of the flavor are
+//   removed as they do not sign the patched flavor. The hosts of the flavor are verified again once it is patched.
+// x-permissions: flavors:store
+// security:
+//  - bearerAuth: []
+// consumes:
+//  - application/merge-patch+json
+// produces:
+//  - application/json
+// parameters:
+// - name: flavor_id
+//   description: Unique UUID of the flavor.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   description: The JSON merge patch of the meta and pcrs sections of the flavor.
+//   required: true
+//   in: body
+//   schema:
+//     type: object
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/merge-patch+json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully patched and signed the flavor.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/SignedFlavor"
+//   '400':
+//     description: Invalid merge patch, or the patched flavor is invalid.
+//   '404':
+//     description: No flavor with the provided flavor ID found.
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/b37580d1-f2b4-4f40-a2fd-2b9ae4ba4dd6
+// x-sample-call-input: |
+//  {
+//    "meta": {
+//        "description": {
+//            "label": "RHEL_8.1_Kernel_4.18.0-193"
+//        }
+//    },
+//    "pcrs": {
+//        "SHA256": {
+//            "pcr_0": {
+//                "value": "1009d6bc1d92739e4e8e3c6819364f9149ee652804565b83bf731bdb6352b2a6"
+//            }
+//        }
+//    }
+//  }
+// x-sample-call-output: |
+//  {
+//    "flavor": {
+//        "meta": {
+//            "id": "b37580d1-f2b4-4f40-a2fd-2b9ae4ba4dd6",
+//            "description": {
+//                "flavor_part": "PLATFORM",
+//                "source": "computepa1",
+//                "label": "RHEL_8.1_Kernel_4.18.0-193",
+//                "bios_name": "Intel Corporation",
+//                "bios_version": "SE5C620.86B.00.01.6016.032720190737",
+//                "tpm_version": "2.0",
+//                "tboot_installed": "true"
+//            },
+//            "vendor": "INTEL"
+//        },
+//        "pcrs": {
+//            "SHA256": {
+//                "pcr_0": {
+//                    "value": "1009d6bc1d92739e4e8e3c6819364f9149ee652804565b83bf731bdb6352b2a6"
+//                },
+//                ...
+//            }
+//        }
+//    },
+//    "signature": "Xn8Lx2i2XxQ5ApdTWb2ytCSv/wrMn0B7Dk6sDpDqmx0JbLV1qmVBYy8oOUbWaS0Izs6bN+I4oxpm2jTbHyBBqXeYkUaPIAK8..."
+//  }
+
+// ---
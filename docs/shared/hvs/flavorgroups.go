@@ -61,6 +61,7 @@ type FlavorgroupFlavorLinkCollection struct {
 //       - ASSET_TAG
 //       - HOST_UNIQUE
 //       - SOFTWARE
+//       - CONTAINER_IMAGE
 //
 //   <b>Match Policy</b>: The policy which defines how the host is verified against the flavors in the flavor group for
 //   the specified flavor part.
@@ -198,6 +199,11 @@ type FlavorgroupFlavorLinkCollection struct {
 //    |--------------------------------|------------|
 //    | name                           | Name of the flavorgroup to be created. |
 //    | flavor_match_policy_collection | Collection of flavor match policies. Each flavor match policy contains two <br> parts: <br><b>flavor_part</b>:The type or classification of the flavor.<br> <b>match_policy</b>:The policy which defines how the host is verified against the <br> flavors in the flavor group for the specified flavor part. |
+//    | strict_event_log_verification  | Optional. When true, the events of the host event logs evaluated for the flavorgroup that <br> have an unrecognized type or fields that cannot be parsed fail the verification with <br> the PcrEventLogUnrecognizedEntry fault instead of being skipped. Defaults to false. |
+//    | parent_id                      | Optional. ID of the flavorgroup this flavorgroup inherits from. For each flavor part, the <br> flavorgroup inherits the match policy it does not define and the flavors it does not link <br> from the nearest ancestor that has them. The flavor_match_policy_collection can be omitted <br> when a parent is given. A strict ancestor makes its descendants strict. The hierarchy cannot <br> be deeper than 8 flavorgroups. |
+//    | compliance_profiles            | Optional. Named sets of flavor parts and rules of the rule definitions the hosts of the <br> flavorgroup are required to pass, e.g. a "NIST-boot-integrity" profile requiring the <br> PLATFORM and OS flavor parts. The reports of the hosts include the pass/fail of each profile. |
+//    | flavor_selector                | Optional. Label selector adding the flavors of the tenant whose labels match it to the <br> flavors linked to the flavorgroup. It contains <b>match_labels</b>, a map of label values, <br> and <b>match_expressions</b>, requirements with a key, an operator (In, NotIn, Exists, <br> DoesNotExist) and values. The labels must satisfy all of them. |
+//    | host_selector                  | Optional. Label selector adding the hosts of the tenant whose labels match it to the <br> hosts linked to the flavorgroup. |
 //
 // x-permissions: flavorgroups:create
 // security:
@@ -398,7 +404,8 @@ type FlavorgroupFlavorLinkCollection struct {
 // ---
 //
 // description: |
-//   Deletes a flavor group. If the flavor group is still associated with any hosts, an error will be thrown.
+//   Deletes a flavor group. If the flavor group is still associated with any hosts or other flavor groups still
+//   inherit from it, an error will be thrown.
 // x-permissions: flavorgroups:delete
 // security:
 //  - bearerAuth: []
@@ -27,6 +27,27 @@ type HostCreateRequest struct {
 	Body hvs.HostCreateRequest
 }
 
+// HostMaintenanceRequest request payload
+// swagger:parameters HostMaintenanceRequest
+type HostMaintenanceRequest struct {
+	// in:body
+	Body hvs.HostMaintenanceRequest
+}
+
+// HardwareUuidConflictCollection response payload
+// swagger:parameters HardwareUuidConflictCollection
+type HardwareUuidConflictCollection struct {
+	// in:body
+	Body hvs.HardwareUuidConflictCollection
+}
+
+// HardwareUuidConflictResolution request payload
+// swagger:parameters HardwareUuidConflictResolution
+type HardwareUuidConflictResolution struct {
+	// in:body
+	Body hvs.HardwareUuidConflictResolution
+}
+
 // HostFlavorgroup response payload
 // swagger:parameters HostFlavorgroup
 type HostFlavorgroup struct {
@@ -60,6 +81,12 @@ type HostFlavorgroupCreateRequest struct {
 //   "intel:https://trustagent.server.com:1443"</br>
 //   For VMware, this includes the vCenter and host IP address or DNS host name and credentials. e.g.:
 //   "vmware:https://vCenterServer.com:443/sdk;h=trustagent.server.com;u=vCenterUsername;p=vCenterPassword"</br>
+//   For air-gapped Intel hosts whose trust agent API cannot be exposed, HVS runs the tagent CLI on the host over ssh with the ssh credentials of the host. e.g.:
+//   "ssh://trustagent.server.com:22;u=sshUsername;p=sshPassword"</br>
+//   The key of the host must be in the ssh known hosts file of HVS, /etc/hvs/ssh_known_hosts by default.</br>
+//   The firmware inventory, secure boot state and TPM presence of Intel hosts with a Redfish BMC are added to their host info when the BMC URL is given with the bmc option. e.g.:
+//   "intel:https://trustagent.server.com:1443;bmc=https://bmc.server.com"</br>
+//   HVS reads the inventory with the host-connector bmc-username and bmc-password of its configuration. Platform flavors created from these hosts have their firmware versions, which are then verified.</br>
 //   </pre>
 //
 //   <b>Creates a host.</b>
@@ -69,6 +96,19 @@ type HostFlavorgroupCreateRequest struct {
 //   Once the host is created, it is added to the flavor verification queue in backend.</br>
 //   </pre>
 //
+//   <b>Pre-registers a host.</b>
+//   <pre>
+//   A host that is not reachable yet can be pre-registered with its hardware UUID, HVS does not connect to it and its lifecycle is PRE_REGISTERED.</br>
+//   HVS periodically connects to the pre-registered hosts. When the trust agent of the host comes online and reports the same hardware UUID, the registration is completed, the host is associated with the default flavor groups if none were specified and it is added to the flavor verification queue. Its lifecycle is then REGISTERED.</br>
+//   If the host reports another hardware UUID its lifecycle is REGISTRATION_FAILED, it is probed again once its hardware UUID is updated.</br>
+//   </pre>
+//
+//   <b>Host capabilities.</b>
+//   <pre>
+//   When HVS reaches the host, it discovers the attestation features of the host (TPM version, PCR banks, SGX and TDX support and the measurement agents installed) and caches them in the capabilities of the host. The trust agents that do not report their capabilities have them derived from their host info.</br>
+//   The quotes of the host are requested for the PCR banks it supports. The capabilities are discovered again when the connection string of the host is updated, they cannot be set in the requests.</br>
+//   </pre>
+//
 //   The serialized HostCreateRequest Go struct object represents the content of the request body.
 //
 //    | Attribute         | Description |
@@ -77,6 +117,9 @@ type HostFlavorgroupCreateRequest struct {
 //    | connection_string | The host connection string. |
 //    | flavorgroup_names | List of flavor group names that the created host will be associated. |
 //    | description       | Host description. |
+//    | pre_register      | Pre-registers the host without connecting to it. |
+//    | hardware_uuid     | Hardware UUID of the host, required to pre-register the host. |
+//    | labels            | Optional. Labels of the host, the host joins the flavorgroups whose host selector <br> matches them. The keys are an optional DNS prefix and a name, e.g. intel.com/datacenter. |
 //
 // x-permissions: hosts:create
 // security:
@@ -112,8 +155,16 @@ type HostFlavorgroupCreateRequest struct {
 //       application/json
 //     schema:
 //       $ref: "#/definitions/Host"
+//   '200':
+//     description: The host was merged into the registered host with the same hardware UUID.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Host"
 //   '400':
 //     description: Invalid request body provided
+//   '409':
+//     description: A registered host has the same hardware UUID
 //   '415':
 //     description: Invalid Accept Header in Request
 //   '500':
@@ -136,7 +187,14 @@ type HostFlavorgroupCreateRequest struct {
 //        "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e",
 //        "flavorgroup_names": [
 //            "automatic", "platform_software"
-//        ]
+//        ],
+//        "capabilities": {
+//            "tpm_version": "2.0",
+//            "pcr_banks": ["SHA1", "SHA256"],
+//            "sgx": false,
+//            "tdx": false,
+//            "measurement_agents": ["tagent"]
+//        }
 //    }
 
 // ---
@@ -218,6 +276,7 @@ type HostFlavorgroupCreateRequest struct {
 //    | connection_string | The host connection string. |
 //    | flavorgroup_names | List of flavor group names that the created host will be associated. |
 //    | description       | Host description. |
+//    | labels            | Optional. Labels of the host, the labels are kept when they are not provided and <br> removed when they are empty. |
 //
 //
 //
@@ -320,6 +379,120 @@ type HostFlavorgroupCreateRequest struct {
 
 // ---
 
+// swagger:operation PUT /hosts/{host_id}/maintenance Hosts SetHostMaintenance
+// ---
+//
+// description: |
+//   Puts a host in maintenance mode while its firmware or OS is updated. The host is not attested on schedule
+//   during the maintenance, the reports created in the meantime have the "suppressed-maintenance" status, are left
+//   out of the trust history of the host and do not trigger trust change notifications. The maintenance of a host
+//   already in maintenance is replaced.
+//
+//   The maintenance ends at the resume time, within the refresh period of the scheduled attestation, or when it is
+//   deleted. The host is then queued for attestation.
+//
+//    | Attribute | Description |
+//    |-----------|-------------|
+//    | reason    | Optional. Reason of the maintenance, up to 255 characters. |
+//    | resume_at | Optional. Time the maintenance ends, it must be in the future. The maintenance of a host <br> without a resume time lasts until it is deleted. |
+//
+// x-permissions: hosts:store
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/HostMaintenanceRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully put the host in maintenance mode.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Host"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Host record not found
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/maintenance
+// x-sample-call-input: |
+//    {
+//        "reason": "BIOS update",
+//        "resume_at": "2021-03-02T18:00:00Z"
+//    }
+// x-sample-call-output: |
+//    {
+//        "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//        "host_name":"Purley host2",
+//        "description": "RHEL TPM2.0 Purley Host",
+//        "connection_string": "https://trustagent.server.com:1443",
+//        "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e",
+//        "maintenance": {
+//            "reason": "BIOS update",
+//            "started_at": "2021-03-02T16:00:00Z",
+//            "resume_at": "2021-03-02T18:00:00Z"
+//        }
+//    }
+
+// ---
+
+// swagger:operation DELETE /hosts/{host_id}/maintenance Hosts EndHostMaintenance
+// ---
+//
+// description: |
+//   Ends the maintenance of a host and queues the host for attestation.
+// x-permissions: hosts:store
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully ended the maintenance of the host.
+//   '404':
+//     description: Host record not found or host not in maintenance mode
+//   '500':
+//     description: Internal server error
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/maintenance
+
+// ---
+
 // swagger:operation GET /hosts Hosts SearchHost
 // ---
 //
@@ -379,6 +552,15 @@ type HostFlavorgroupCreateRequest struct {
 //   in: query
 //   type: boolean
 //   required: false
+// - name: lifecycle
+//   description: Get hosts by registration state, this filter can be combined with the other parameters.
+//   in: query
+//   type: string
+//   enum:
+//      - PRE_REGISTERED
+//      - REGISTERED
+//      - REGISTRATION_FAILED
+//   required: false
 // - name: getTrustStatus
 //   description: Get trust status for host.
 //   in: query
@@ -437,6 +619,152 @@ type HostFlavorgroupCreateRequest struct {
 //
 // ---
 
+// swagger:operation GET /hosts/hardware-uuid-conflicts Hosts SearchHardwareUuidConflicts
+// ---
+//
+// description: |
+//   Lists the hosts registered with the same hardware UUID, grouped by hardware UUID.
+//
+//   Returns - The serialized HardwareUuidConflictCollection Go struct object that was retrieved.
+//
+// x-permissions: hosts:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the hardware UUID conflicts.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/HardwareUuidConflictCollection"
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/hardware-uuid-conflicts
+// x-sample-call-output: |
+//    {
+//        "conflicts": [
+//            {
+//                "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e",
+//                "hosts": [
+//                    {
+//                        "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//                        "host_name": "vm1",
+//                        "connection_string": "https://vm1.server.com:1443",
+//                        "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e"
+//                    },
+//                    {
+//                        "id": "ab1ef2c9-4e1a-4a0b-8d3e-6a5a2b0d9c11",
+//                        "host_name": "vm2",
+//                        "connection_string": "https://vm2.server.com:1443",
+//                        "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e"
+//                    }
+//                ]
+//            }
+//        ]
+//    }
+
+// ---
+
+// swagger:operation POST /hosts/hardware-uuid-conflicts Hosts ResolveHardwareUuidConflict
+// ---
+//
+// description: |
+//   Resolves the conflict of the hosts registered with the same hardware UUID. The host identified by host_id keeps the hardware UUID, the other hosts are resolved with:</br>
+//   auto-suffix - the hosts are given a hardware UUID derived from the hardware UUID and their host name.</br>
+//   merge - the hosts are deleted, the host kept is their only record.</br>
+//   The hosts kept are added to the flavor verification queue.
+//
+//    | Attribute     | Description |
+//    |---------------|-------------|
+//    | hardware_uuid | The hardware UUID the hosts conflict on. |
+//    | host_id       | The host that keeps the hardware UUID. |
+//    | resolution    | auto-suffix or merge. |
+//
+//   Returns - The serialized HostCollection Go struct object of the hosts kept.
+//
+// x-permissions: hosts:store
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/HardwareUuidConflictResolution"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully resolved the hardware UUID conflict.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/HostCollection"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: No hosts conflict on the hardware UUID
+//   '415':
+//     description: Invalid Content-Type or Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/hardware-uuid-conflicts
+// x-sample-call-input: |
+//    {
+//        "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e",
+//        "host_id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//        "resolution": "auto-suffix"
+//    }
+// x-sample-call-output: |
+//    {
+//        "hosts": [
+//            {
+//                "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//                "host_name": "vm1",
+//                "connection_string": "https://vm1.server.com:1443",
+//                "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e"
+//            },
+//            {
+//                "id": "ab1ef2c9-4e1a-4a0b-8d3e-6a5a2b0d9c11",
+//                "host_name": "vm2",
+//                "connection_string": "https://vm2.server.com:1443",
+//                "hardware_uuid": "5b0c9e7d-1f3a-5c44-8e2b-7d6f0a1c9e35"
+//            }
+//        ]
+//    }
+
+// ---
+
 // swagger:operation POST /hosts/{host_id}/flavorgroups HostFlavorgroupLinks CreateHostFlavorgroupLink
 // ---
 //
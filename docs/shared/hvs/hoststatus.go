@@ -20,6 +20,13 @@ type HostStatusCollection struct {
 	Body hvs.HostStatusCollection
 }
 
+// HostLifecycleTransitionCollection response payload
+// swagger:parameters HostLifecycleTransitionCollection
+type HostLifecycleTransitionCollection struct {
+	// in:body
+	Body hvs.HostLifecycleTransitionCollection
+}
+
 //  ---
 //
 //  swagger:operation GET /host-status HostStatuses SearchHostStatus
@@ -79,6 +86,18 @@ type HostStatusCollection struct {
 //        - tpm_not_present
 //        - unsupported_tpm
 //      required: false
+//    - name: lifecycleState
+//      description: Host lifecycle state.
+//      in: query
+//      type: string
+//      enum:
+//        - registered
+//        - queued
+//        - connected
+//        - attested
+//        - connection_failed
+//        - untrusted
+//      required: false
 //    - name: fromDate
 //      description: |
 //        Filters HostStatus records created after this date.
@@ -701,6 +720,10 @@ type HostStatusCollection struct {
 //    | UNSUPPORTED_TPM                |  Host TPM version is unsupported  |
 //    | UNKNOWN                        |  Host is in unknown state  |
 //
+//    The clock_skew of the status is the skew of the host clock measured from the timestamp of the quote at the last
+//    successful connection. The offset is positive when the host clock is ahead of HVS and is only known within the
+//    uncertainty.
+//
 //    Returns - The serialized Host Go struct object that was retrieved.
 //
 //  x-permissions: host_status:retrieve
@@ -743,7 +766,12 @@ type HostStatusCollection struct {
 //        "created": "2020-07-20T13:52:25.84078Z",
 //        "status": {
 //            "host_state": "CONNECTED",
-//            "last_time_connected": "2020-07-20T06:52:25.840740767-07:00"
+//            "last_time_connected": "2020-07-20T06:52:25.840740767-07:00",
+//            "clock_skew": {
+//                "quote_time": "2020-07-20T13:52:27Z",
+//                "offset_millis": 1240,
+//                "uncertainty_millis": 620
+//            }
 //        },
 //        "host_manifest": {
 //            "aik_certificate": "MIIDLzCCAZegAwIBAgIRAN+l/AlQRBLsXNHOJ4lxh8gwDQYJKoZIhvcNAQELBQAwIjEgMB4GA1UEAxMXSFZTIFByaXZhY3kgQ2VydGlmaWNhdGUwHhcNMjAwNzIwMTM0NzQzWhcNMjUwNzIwMTM0NzQzWjAAMIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAlVfaCXwhDrp61SyHCCibvsMnsEMsm5NE679e9alFmDPzQFSNyzfcUkx1FYtpmEde/f4lKmupTkQYGhhSDoS3haPEn6W1s6zBKc9WZwrdViRRJvku0tbtZ2NgCxmP/dTQ0jdWfHd7i1/DNmx1L/vyVp+Cf1Dvk0/y7mEJXoCuL2x4sz0rvaru0Qb/THBX/h+bZiUMrmGvldcxMzuWNBGA+bYZ9rch8g6z5JR9XsnC46ssE7g2jidBccmka8GMMn/lAnismTnL8mRWNOa8Uq5VtdlVPFkoM02eIg54N7TJZpVPxIUducfJGF2GP9nI9Nz+1+Zz275Cq1lC+gA+QKvOkwIDAQABowIwADANBgkqhkiG9w0BAQsFAAOCAYEAqdx0uH3VX9U/Rh/JdYdlGTPsD6B6En/2SI92gKVVDC8bYolB5etZIocJpc8385XNMXbA8WX8lFxws12KeB6bdFXuN/wtpJWMbuFnsb7/QPB4C+NznZFjRebxmTmNOZMhBKwhGRDVkevaPV/uFJcXqI0f10lKDiZjG4I2t3y0DJJMTAIg9mz6h7BGGnhmdyfLWwR56bHnJGO7t5tz7nBfbUhJ7KTBjRHyb0G1DUiT2DOit8+V0eRWmqsPk0hQAL0WzR93Ckw/wWWvM32OV768XKWKFPHNYeZIQRZglejg/PjYOU2ppz0w5M0Z/CzY3aRrwX0rkC9CqKWgkzwQrElk1sU6hE9DQh+uw9KdN2rtZ3urTLRVRD6ojqwyznl8gJ2uq6G92HGEPzq9orLLxNIcXtMrJeHsXK3r5zyos8s2fHLjR9A2Bqg/gK7QzzEJzbUoHvjp/i86Xs5jiwvwY7dE3SSLwjIbhxgCKZ6j2CKAKxmaZVbHurh3nPsgruQ/WnDG",
@@ -1428,3 +1456,84 @@ type HostStatusCollection struct {
 //        }
 //    }
 //  ---
+
+//  ---
+//
+//  swagger:operation GET /host-status/lifecycle-history HostStatuses SearchHostLifecycleHistory
+//  ---
+//  description: |
+//    Searches for the lifecycle state transitions of a host, most recent transition first.
+//
+//    The lifecycle state of a host is part of its host status and moves through the following states as the host is
+//    attested:
+//    | Lifecycle State                | Description                                     |
+//    |--------------------------------|-------------------------------------------------|
+//    | REGISTERED                     | Host has been registered and is not yet queued for attestation |
+//    | QUEUED                         | Host is waiting for its data to be retrieved or verified |
+//    | CONNECTED                      | Host data has been retrieved and is waiting for the flavor verification |
+//    | ATTESTED                       | Host has been verified as trusted |
+//    | UNTRUSTED                      | Host has been verified as untrusted |
+//    | CONNECTION_FAILED              | Host could not be reached, the host state of the status holds the reason |
+//
+//    Returns - The serialized HostLifecycleTransitionCollection Go struct object that was retrieved.
+//
+//  x-permissions: host_status:search
+//  security:
+//    - bearerAuth: []
+//  produces:
+//    - application/json
+//  parameters:
+//    - name: hostId
+//      description: Host UUID
+//      in: query
+//      type: string
+//      format: uuid
+//      required: true
+//    - name: limit
+//      description: Limits the number of transitions in the response.
+//      in: query
+//      type: integer
+//      minimum: 1
+//      default: 10000
+//      required: false
+//    - name: Accept
+//      description: Accept header
+//      in: header
+//      type: string
+//      required: true
+//      enum:
+//        - application/json
+//  responses:
+//    '200':
+//      description: Successfully retrieved the lifecycle history of the host. Also returned when no results are found.
+//      content: application/json
+//      schema:
+//        $ref: "#/definitions/HostLifecycleTransitionCollection"
+//    '400':
+//      description: Invalid values for search criteria
+//    '415':
+//      description: Invalid Accept Header in Request
+//    '500':
+//      description: Internal server error
+//
+//  x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/host-status/lifecycle-history?hostId=91b022fc-3f9b-4269-999c-b39af2eac1eb
+//  x-sample-call-output: |
+//    {
+//        "transitions": [
+//            {
+//                "id": "8b1b9e2e-30a6-4dcc-9a6e-5a7e9e0c0c3d",
+//                "host_id": "91b022fc-3f9b-4269-999c-b39af2eac1eb",
+//                "from": "CONNECTED",
+//                "to": "ATTESTED",
+//                "created": "2020-07-20T13:52:27.10415Z"
+//            },
+//            {
+//                "id": "3f63d4ee-4e8a-4bc9-8c06-1ea7f5d2f0a5",
+//                "host_id": "91b022fc-3f9b-4269-999c-b39af2eac1eb",
+//                "from": "QUEUED",
+//                "to": "CONNECTED",
+//                "created": "2020-07-20T13:52:25.84078Z"
+//            }
+//        ]
+//    }
+//  ---
@@ -0,0 +1,304 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// PlatformCertificate response payload
+// swagger:parameters PlatformCertificate
+type PlatformCertificate struct {
+	// in:body
+	Body hvs.PlatformCertificate
+}
+
+// PlatformCertificateCollection response payload
+// swagger:parameters PlatformCertificateCollection
+type PlatformCertificateCollection struct {
+	//	in:body
+	Body hvs.PlatformCertificateCollection
+}
+
+// ---
+
+// swagger:operation POST /platform-certificates PlatformCertificates Create-PlatformCertificate
+// ---
+// description: |
+//   Registers a TCG Platform Certificate, an RFC 5755 attribute certificate, for a host.
+//
+//   The certificate must be signed by a platform CA added with the "platform" ca-certificates type and a
+//   non revoked TpmEndorsement must already be registered for the hardware UUID, which binds the platform
+//   certificate to the EK of the host. The holder baseCertificateID of the certificate must be the issuer and
+//   serial number of that EK certificate. The platform manufacturer, model, version and serial are read from the
+//   TCG platform attributes of the directory name of the subject alternative name extension, or of the attributes
+//   of the certificate. The verified attributes are returned as platform_attributes of the host and its reports.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | certificate                    | The Base64 encoded PEM or DER platform attribute certificate |
+//    | hardware_uuid                  | Hardware UUID of the host associated with the certificate. |
+//
+// x-permissions: platform_certificates:create
+// security:
+//   - bearerAuth: []
+// consumes:
+//   - application/json
+// produces:
+//   - application/json
+// parameters:
+//   - name: request body
+//     required: true
+//     in: body
+//     schema:
+//       "$ref": "#/definitions/PlatformCertificate"
+//   - name: Content-Type
+//     description: Content-Type header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '201':
+//     description: Successfully registered the PlatformCertificate.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/PlatformCertificate"
+//   '400':
+//     description: Invalid request body provided, untrusted certificate, no TpmEndorsement registered for the host or
+//       certificate not held by the EK of the host
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/platform-certificates
+// x-sample-call-input: |
+//   {
+//        "hardware_uuid" : "80e54342-94f2-e711-906e-001560a04062",
+//        "certificate"   : "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUQzRENDQTRHZ0F3SUJBZ0lMQUxmVWV3WEJNTEpxOW9Rd0NnWUlLb1pJemowRUF3..."
+//   }
+// x-sample-call-output: |
+//   {
+//        "id"                    : "5a1a0ad0-2e9c-4c58-9d29-b1e2a8f0a8a1",
+//        "hardware_uuid"         : "80e54342-94f2-e711-906e-001560a04062",
+//        "certificate"           : "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUQzRENDQTRHZ0F3SUJBZ0lMQUxmVWV3WEJNTEpxOW9Rd0NnWUlLb1pJemowRUF3...",
+//        "issuer"                : "CN=Platform CA,O=Intel Corporation",
+//        "manufacturer"          : "Intel Corporation",
+//        "model"                 : "S2600WF",
+//        "serial"                : "BQWL83250078",
+//        "version"               : "H48104-850",
+//        "certificate_digest"    : "2f1e3b0c1c8d3cd0d7fb2ab8d6f7c5ee1c0b3a5a4e4f1b5a5d8dc8c2f37a1d0e7e3d1f2e6c9b8a7f6e5d4c3b2a1f0e9d8c",
+//        "ek_certificate_digest" : "da8e9c68faf66d2634a4cbe14534a1916db261f401ffaffd42dc901eae33dd57695f365a31d19da67e4cebf1491dea60",
+//        "not_before"            : "2020-09-01T00:00:00Z",
+//        "not_after"             : "2035-09-01T00:00:00Z"
+//   }
+
+// ---
+
+// swagger:operation GET /platform-certificates PlatformCertificates Search-PlatformCertificate
+// ---
+// description: |
+//   Searches the registered platform certificates.
+//
+// x-permissions: platform_certificates:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: id
+//     description: Platform certificate ID
+//     in: query
+//     type: string
+//     format: uuid
+//     required: false
+//   - name: hardwareUuidEqualTo
+//     description: hardware UUID of the host to which the platform certificate is associated.
+//     in: query
+//     type: string
+//     format: uuid
+//     required: false
+//   - name: manufacturerEqualTo
+//     description: Platform manufacturer.
+//     in: query
+//     type: string
+//     required: false
+//   - name: modelEqualTo
+//     description: Platform model.
+//     in: query
+//     type: string
+//     required: false
+//   - name: serialEqualTo
+//     description: Platform serial number.
+//     in: query
+//     type: string
+//     required: false
+//   - name: revokedEqualTo
+//     description: Boolean value to indicate status of the platform certificate. Default value is false.
+//     in: query
+//     type: boolean
+//     required: false
+//   - name: certificateDigestEqualTo
+//     description: SHA384 digest of the platform certificate.
+//     in: query
+//     type: string
+//     required: false
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully searched the platform certificates.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/PlatformCertificateCollection"
+//   '400':
+//     description: Invalid search criteria provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/platform-certificates?serialEqualTo=BQWL83250078
+// x-sample-call-output: |
+//   {
+//        "platform_certificates": [
+//           {
+//               "id"                    : "5a1a0ad0-2e9c-4c58-9d29-b1e2a8f0a8a1",
+//               "hardware_uuid"         : "80e54342-94f2-e711-906e-001560a04062",
+//               "certificate"           : "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUQzRENDQTRHZ0F3SUJBZ0lMQUxmVWV3WEJNTEpxOW9Rd0NnWUlLb1pJemowRUF3...",
+//               "issuer"                : "CN=Platform CA,O=Intel Corporation",
+//               "manufacturer"          : "Intel Corporation",
+//               "model"                 : "S2600WF",
+//               "serial"                : "BQWL83250078",
+//               "version"               : "H48104-850",
+//               "certificate_digest"    : "2f1e3b0c1c8d3cd0d7fb2ab8d6f7c5ee1c0b3a5a4e4f1b5a5d8dc8c2f37a1d0e7e3d1f2e6c9b8a7f6e5d4c3b2a1f0e9d8c",
+//               "ek_certificate_digest" : "da8e9c68faf66d2634a4cbe14534a1916db261f401ffaffd42dc901eae33dd57695f365a31d19da67e4cebf1491dea60",
+//               "not_before"            : "2020-09-01T00:00:00Z",
+//               "not_after"             : "2035-09-01T00:00:00Z"
+//           }
+//        ]
+//   }
+
+// ---
+
+// swagger:operation GET /platform-certificates/{platform-certificate_id} PlatformCertificates Retrieve-PlatformCertificate
+// ---
+// description: |
+//   Retrieves a platform certificate.
+//   Returns - The serialized PlatformCertificate Go struct object that was retrieved
+// x-permissions: platform_certificates:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: platform-certificate_id
+//     description: Unique ID of the PlatformCertificate.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the PlatformCertificate.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/PlatformCertificate"
+//   '404':
+//     description: No relevant PlatformCertificate record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/platform-certificates/5a1a0ad0-2e9c-4c58-9d29-b1e2a8f0a8a1
+
+// ---
+
+// swagger:operation DELETE /platform-certificates/{platform-certificate_id} PlatformCertificates Delete-PlatformCertificate
+// ---
+//  description: |
+//    Deletes a PlatformCertificate.
+//  x-permissions: platform_certificates:delete
+//  security:
+//    - bearerAuth: []
+//  parameters:
+//    - name: platform-certificate_id
+//      description: Unique ID of the PlatformCertificate.
+//      in: path
+//      required: true
+//      type: string
+//      format: uuid
+//  responses:
+//    '204':
+//      description: Successfully deleted the PlatformCertificate.
+//    '404':
+//      description: No relevant PlatformCertificate record found.
+//    '500':
+//      description: Internal server error
+//  x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/platform-certificates/5a1a0ad0-2e9c-4c58-9d29-b1e2a8f0a8a1
+
+// ---
+
+// swagger:operation POST /platform-certificates/{platform-certificate_id}/revoke PlatformCertificates Revoke-PlatformCertificate
+// ---
+// description: |
+//   Revokes a PlatformCertificate. The platform attributes are no longer reported from a revoked certificate and
+//   another platform certificate can be registered for the host. The revoked certificate is kept and can be searched
+//   with revokedEqualTo=true.
+//   Returns - The serialized PlatformCertificate Go struct object that was revoked
+// x-permissions: platform_certificates:revoke
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: platform-certificate_id
+//     description: Unique ID of the PlatformCertificate.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '200':
+//     description: Successfully revoked the PlatformCertificate.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/PlatformCertificate"
+//   '404':
+//     description: No relevant PlatformCertificate record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/platform-certificates/5a1a0ad0-2e9c-4c58-9d29-b1e2a8f0a8a1/revoke
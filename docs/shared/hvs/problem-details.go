@@ -0,0 +1,21 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+
+// The errors of all the operations are returned as RFC 7807 problem details with the application/problem+json media
+// type. The type of a problem is a stable URI, e.g. urn:intel-secl:problem:not-found, the detail is a message for
+// the users. The correlation_id of a problem, also returned in the X-Correlation-ID header of every response, is the
+// ID the service logged the error with. A client can set the X-Correlation-ID header of its requests to relate the
+// calls made for the same operation. The details of the server errors are only logged.
+
+// ProblemDetails response payload of the errors
+// swagger:parameters ProblemDetails
+type ProblemDetails struct {
+	// in:body
+	Body commErr.ProblemDetails
+}
@@ -0,0 +1,79 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+
+// TpmQuoteResponse request payload
+// swagger:parameters TpmQuoteResponse
+type TpmQuoteResponse struct {
+	// in:body
+	Body taModel.TpmQuoteResponse
+}
+
+// ---
+
+// swagger:operation POST /quote-callbacks/{correlation_id} QuoteCallbacks Create-QuoteCallback
+// ---
+// description: |
+//   Posts the TPM quote of an asynchronous quote request back to HVS. When fvs.async-quote-callback-url is
+//   configured, HVS requests the quotes of the hosts with a correlation id and a callback url instead of waiting
+//   on the connection to the trust agent. The trust agent then posts the quote to the callback url once it is
+//   created. The quote is verified the same way as a quote retrieved synchronously, requests whose quote is not
+//   posted within fvs.async-quote-timeout fail.
+//
+// x-permissions: quote_callbacks:create
+// security:
+//   - bearerAuth: []
+// consumes:
+//   - application/xml
+// parameters:
+//   - name: correlation_id
+//     description: Correlation id of the quote request.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: request body
+//     required: true
+//     in: body
+//     schema:
+//       "$ref": "#/definitions/TpmQuoteResponse"
+//   - name: Content-Type
+//     description: Content-Type header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/xml
+// responses:
+//   '204':
+//     description: Successfully delivered the quote to the pending request.
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: No pending request with the correlation id, or asynchronous quote collection is not enabled
+//   '415':
+//     description: Invalid Content-Type Header in Request
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/quote-callbacks/ee37c360-7eae-4250-a677-6ee12adce8e2
+// x-sample-call-input: |
+//   <tpm_quote_response>
+//       <timestamp>1605051563853</timestamp>
+//       <errorCode>0</errorCode>
+//       <errorMessage>OK</errorMessage>
+//       <aik>MIIDSjCCAbKgAwIBAgIGAXWxkMJ...</aik>
+//       <quote>AIv/VUNHgBgAIgALN5Ot...</quote>
+//       <eventLog>PG1lYXN1cmVMb2c+PHR4dD4...</eventLog>
+//       <tcbMeasurements></tcbMeasurements>
+//       <selectedPcrBanks>
+//           <selectedPcrBanks>SHA1</selectedPcrBanks>
+//           <selectedPcrBanks>SHA256</selectedPcrBanks>
+//       </selectedPcrBanks>
+//       <isTagProvisioned>false</isTagProvisioned>
+//   </tpm_quote_response>
+
+// ---
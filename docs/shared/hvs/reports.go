@@ -28,6 +28,13 @@ type ReportCreateRequest struct {
 	Body hvs.ReportCreateRequest
 }
 
+// TrustHistory response payload
+// swagger:parameters TrustHistory
+type TrustHistory struct {
+	// in:body
+	Body hvs.TrustHistory
+}
+
 // ---
 
 // swagger:operation GET /reports Reports Search-Reports
@@ -44,6 +51,32 @@ type ReportCreateRequest struct {
 //   Reports have a configurable validity period with default period of 24 hours or 86400 seconds. The Host Verification service has a background refresh process that queries for reports where the expiration time is within the next 5 minutes, and triggers generation of a new report for all results.
 //   This is checked every 2 minutes by default, and can be configured by changing property in the configuration. In this way fresh reports are generated before older reports expire.
 //
+//   The trust information of a report includes the trust of each flavor part evaluated for the host, e.g. PLATFORM trusted and SOFTWARE untrusted, with:
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | trust                          | Whether the flavor part of the host is trusted. |
+//    | valid_until                    | Time until which the trust of the flavor part holds, the expiration of the report or of the AIK or tag certificate the part depends on, whichever comes first. |
+//    | flavor_ids                     | IDs of the flavors the flavor part was evaluated against. |
+//    | rules                          | Results of the rules of the flavor part, a rule depending on a certificate has the valid_until of the certificate. |
+//
+//   Different rules can raise faults for the same underlying issue, e.g. a PCR value mismatch is raised by both the PcrMatchesConstant and PcrEventLogIntegrity rules.
+//   Each fault has a fault_key identifying its issue, such as pcr/SHA256/18 or xml-measurement-log/<flavor id>, and the grouped_faults of the trust information
+//   list one fault per issue with the rules that raised it.
+//
+//   Each rule result records the provenance of the values it compared. The expected_provenance identifies the flavor the expected values come from with its
+//   flavor_id, flavor_label, flavor_part, flavor_version (the SHA384 digest of the signed content of the flavor), base_flavor_id for delta flavors, template
+//   (the schema of the flavor) and import_source (the host the flavor was imported from). The actual_provenance lists the manifest_path of the actual values in
+//   the host manifest, e.g. pcr_manifest.sha2pcrs, with the pcr_bank and pcr_index of the PCR values and event logs.
+//
+//   When the flavorgroups of the host have compliance profiles, the compliance of the trust information lists the result of each profile, e.g.
+//   {"profile": "NIST-boot-integrity", "flavorgroup_id": "...", "flavorgroup_name": "automatic", "compliant": false, "failed_flavor_parts": ["OS"]}.
+//   A profile fails when one of its flavor parts is not trusted or has no results, or when one of its rules raised faults or was not applied.
+//
+//   When the trust score is configured (fvs.trust-score), the trust information has a trust_score from 0 to 100 alongside the OVERALL trust: the share of the
+//   weight of the rule results that passed, so that the schedulers can prefer the most trusted hosts. The weight of a result is the weight of the severity of its
+//   rule (critical 10, high 5, medium 3 and low 1 by default) times the weight of its flavor part (1 by default), both configurable.
+//
 //   <b>Searches for reports</b>
 //
 // x-permissions: reports:search
@@ -111,6 +144,13 @@ type ReportCreateRequest struct {
 //   type: string
 //   format: date-time
 //   required: false
+// - name: untrustedFlavorPart
+//   description: |
+//     Returns only the reports in which the flavor part was evaluated and is not trusted. One of PLATFORM, OS, HOST_UNIQUE, SOFTWARE or ASSET_TAG.
+//     When combined with a date filter, the reports are filtered after the limit is applied.
+//   in: query
+//   type: string
+//   required: false
 // - name: latestPerHost
 //   description:  Returns only the latest report for each host. If latestPerHost is specified in conjuction with a date filter, it will return the latest report for within the specified date range per host.
 //   in: query
@@ -154,6 +194,8 @@ type ReportCreateRequest struct {
 //                             "HOST_UNIQUE":
 //                                 {
 //                                   "trust": true,
+//                                   "valid_until": "2020-06-22T07:18:00.57Z",
+//                                   "flavor_ids": ["a774ddad-fca1-4670-86b2-605c88a16dab"],
 //                                    "rules":
 //                                        [
 //                                         {
@@ -200,7 +242,16 @@ type ReportCreateRequest struct {
 //                             },
 //         "OVERALL": true,
 //         "created": "2018-07-23T16:39:52-0700",
-//         "expiration": "2018-07-23T17:39:52-0700"
+//         "expiration": "2018-07-23T17:39:52-0700",
+//         "stage_timings": {
+//             "queue_wait_ms": 30,
+//             "quote_retrieval_ms": 1500,
+//             "flavor_match_ms": 14,
+//             "rule_evaluation_ms": 9,
+//             "signing_ms": 5,
+//             "persistence_ms": 12,
+//             "total_ms": 1570
+//         }
 //     }
 
 // ---
@@ -423,3 +474,97 @@ type ReportCreateRequest struct {
 //       "expiration": "2018-07-23T17:39:52-0700"
 //     }
 //   }
+
+// ---
+
+// swagger:operation GET /hosts/{host_id}/trust-history Reports Retrieve-Trust-History
+// ---
+//
+// description: |
+//   Retrieves the trust history of a host over a period. The trust of the host is summed up per hour, day or week,
+//   the buckets in which no report was created for the host are left out. The trust of the hosts is summed up when
+//   their reports are created and kept for the hrrs-trust-history-retention period, the reports are not kept.
+//   Returns - The serialized TrustHistory Go struct object that was retrieved.
+// x-permissions: reports:search
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: fromDate
+//   description: |
+//     The start of the period. The date can be in format yyyy-MM-dd, yyyy-MM-dd hh:mm:ss or RFC3339.
+//     Defaults to 30 days before toDate.
+//   in: query
+//   type: string
+//   required: false
+// - name: toDate
+//   description: |
+//     The end of the period. The date can be in format yyyy-MM-dd, yyyy-MM-dd hh:mm:ss or RFC3339.
+//     Defaults to the current time. The period must not be longer than 365 days.
+//   in: query
+//   type: string
+//   required: false
+// - name: interval
+//   description: The interval the trust history is summed up by, the weeks start on Monday UTC.
+//   in: query
+//   type: string
+//   required: false
+//   default: day
+//   enum:
+//     - hour
+//     - day
+//     - week
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the trust history of the host.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TrustHistory"
+//   '400':
+//     description: Invalid values for request params
+//   '404':
+//     description: No relevant host record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/94824cb6-d6c8-4faf-83b0-125996ceebe2/trust-history?fromDate=2021-03-01&toDate=2021-03-08&interval=day
+// x-sample-call-output: |
+//   {
+//     "host_id": "94824cb6-d6c8-4faf-83b0-125996ceebe2",
+//     "from_date": "2021-03-01T00:00:00Z",
+//     "to_date": "2021-03-08T00:00:00Z",
+//     "interval": "day",
+//     "buckets": [
+//       {
+//         "start": "2021-03-01T00:00:00Z",
+//         "trusted": true,
+//         "trusted_reports": 24,
+//         "untrusted_reports": 0
+//       },
+//       {
+//         "start": "2021-03-02T00:00:00Z",
+//         "trusted": false,
+//         "trusted_reports": 20,
+//         "untrusted_reports": 4,
+//         "faults": {
+//           "PcrValueMismatch": 4
+//         }
+//       }
+//     ]
+//   }
@@ -0,0 +1,68 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// RuleDefinitionCollection response payload
+// swagger:parameters RuleDefinitionCollection
+type RuleDefinitionCollection struct {
+	//	in:body
+	Body hvs.RuleDefinitionCollection
+}
+
+// ---
+
+// swagger:operation GET /rule-definitions RuleDefinitions Search-RuleDefinitions
+// ---
+// description: |
+//   Retrieves the catalog of rules applied by the verifier. Each rule definition lists the rule name reported
+//   in trust reports, the flavor parts the rule is applied for, the fault names the rule may raise and a
+//   description of the verification performed by the rule.
+//
+// x-permissions: rule_definitions:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully retrieved the rule definitions.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/RuleDefinitionCollection"
+//   '415':
+//     description: Invalid Accept Header in Request
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/rule-definitions
+// x-sample-call-output: |
+//   {
+//        "rule_definitions": [
+//           {
+//               "rule_name": "com.intel.mtwilson.core.verifier.policy.rule.AikCertificateTrusted",
+//               "flavor_parts": [
+//                   "PLATFORM",
+//                   "OS",
+//                   "HOST_UNIQUE"
+//               ],
+//               "faults": [
+//                   "com.intel.mtwilson.core.verifier.policy.fault.AikCertificateMissing",
+//                   "com.intel.mtwilson.core.verifier.policy.fault.AikCertificateExpired",
+//                   "com.intel.mtwilson.core.verifier.policy.fault.AikCertificateNotYetValid",
+//                   "com.intel.mtwilson.core.verifier.policy.fault.AikCertificateNotTrusted"
+//               ],
+//               "description": "Verifies that the host's AIK certificate is present, within its validity period and issued by a trusted privacy CA."
+//           }
+//        ]
+//   }
@@ -30,6 +30,13 @@ type TagCertificateDeployCriteria struct {
 	Body models.TagCertificateDeployCriteria
 }
 
+// TagCertificateRevoke request payload
+// swagger:parameters TagCertificateRevokeCriteria
+type TagCertificateRevokeCriteria struct {
+	// in:body
+	Body models.TagCertificateRevokeCriteria
+}
+
 // TagCertificateCollection response payload
 // swagger:parameters TagCertificateCollection
 type TagCertificateCollection struct {
@@ -104,6 +111,11 @@ type TagCertificateCollection struct {
 //   type: string
 //   format: uuid
 //   required: false
+// - name: revokedEqualTo
+//   description: Filters the revoked or the non revoked TagCertificates.
+//   in: query
+//   type: boolean
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -176,6 +188,10 @@ type TagCertificateCollection struct {
 //    | hardware_uuid     | The hardware UUID of the host to which the tag certificate is associated. |
 //    | selection_content | an array of one or more key-value pairs with the tag selection attributes. |
 //
+//   A tag can be scoped to an activation window with not_before and/or not_after (RFC3339 times), for temporary
+//   classifications like "maintenance" or "forensic-hold". Outside of its window the tag is not reported in the
+//   attestation reports, as if it was not in the certificate, without redeploying the Tag Certificate.
+//
 //
 //
 // x-permissions: tag_certificates:create
@@ -231,6 +247,12 @@ type TagCertificateCollection struct {
 //            {
 //                "name": "Company",
 //                "value": "SantaClauseWorkshop"
+//            },
+//            {
+//                "name": "Maintenance",
+//                "value": "true",
+//                "not_before": "2020-07-21T08:00:00Z",
+//                "not_after": "2020-07-21T20:00:00Z"
 //            }
 //        ]
 //    }
@@ -402,3 +424,80 @@ type TagCertificateCollection struct {
 //          "signature": "Pauz4EN6RtpWuyyFZpI/S8cXia2qqAnbOmWLHzZzLEfx0D4D1zr/Soj35aN0BnngNUw4fxGcSv0oUrq5DNc0TrVf+/Doc/KcU74Iwm2+wR8MOzHAoOzW/LNlcpMOv13SabTjhJ6eQpcIoYz4XrqmMC+s3jiYnyhQ5PzFnd4K2BoJWT7hj5gvjXYX1Ccss/4Cunt3zkQsc5fnXf/ask9Gz4WqR6Qra5DQQsYKp0qdaKA4skKJVFWWDsrks+0HvXPkSLDa11xA9lq45YPJU9vPX0SMyu7txfeBeVEJ7Ov1kkE+H2ukOtiHwZZdkcuOh9h64D6q7qzTjRjjeOntgJjrooXRDsFE8SCpTh5clKLTaK+0mJCGsdcvbrBtH/UCNMHZWtB5/b+uaXeCbamOiN7oAgqI0I4ttcEonehn3HaXiwAgLbkrW1LgxWODGlUpogheCDMAjkOHyl2nwpeqjIq4n5WFfVo2NUQv5JnEJ2QZYNCEd+rOKIkCqgmoc9gCq6DM"
 //      }
 // ---
+
+// ---
+//
+// swagger:operation POST /rpc/revoke-tag-certificate TagCertificates RevokeTagCertificate
+// ---
+//
+// description: |
+//   Revokes a Tag Certificate. A revoked Tag Certificate can not be deployed anymore, the ASSET_TAG flavors created
+//   when it was deployed are deleted and the hosts they were linked to are queued for flavor verification.
+//
+//   The serialized TagCertificateRevokeCriteria Go struct object represents the content of the request body.
+//
+//    | Attribute         | Description |
+//    |-------------------|-------------|
+//    | certificate_id    | ID of TagCertificate to be revoked. |
+//
+// x-permissions: tag_certificates:revoke
+// security:
+//  - bearerAuth: []
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     $ref: "#/definitions/TagCertificateRevokeCriteria"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully revoked the TagCertificate.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TagCertificate"
+//   '400':
+//     description: Error decoding the TagCertificateRevokeCriteria.
+//   '404':
+//     description: TagCertificate does not exist.
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Error revoking the TagCertificate.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/rpc/revoke-tag-certificate
+// x-sample-call-input: |
+//    {
+//        "certificate_id": "ec7a9f98-0c79-4856-994c-b1a2087d03d1"
+//    }
+// x-sample-call-output: |
+//    {
+//        "id": "ec7a9f98-0c79-4856-994c-b1a2087d03d1",
+//        "certificate": "MIIEQTCCAqmgAwIBAgIQVGCFNpSOXMOmAC9Hh/wd1TANBgkqhkiG9w0BAQwFADAxMS8wLQYDVQQDDCYTJDAwZWNkM2FiLTlhZjQtZTcxMS05MDZlLTAwMTU2MGEwNDA2MjAeFw0yMDA3MjAxMzU1MDBaFw0yMTA3MjAxMzU1MDBaMDExLzAtBgNVBAMMJhMkMDBlY2QzYWItOWFmNC1lNzExLTkwNmUtMDAxNTYwYTA0MDYy...",
+//        "subject": "00ecd3ab-9af4-e711-906e-001560a04062",
+//        "issuer": "HVS Tag Certificate",
+//        "not_before": "2020-07-20T13:55:00Z",
+//        "not_after": "2021-07-20T13:55:00Z",
+//        "hardware_uuid": "00ecd3ab-9af4-e711-906e-001560a04062",
+//        "asset_tag_digest": "LyAgoHDmNoCxIBvrkDnv+neoXHd3hefsUU5ZQpPOMq4bgW/qBKNIhm16LZwEaVxb",
+//        "revoked": true
+//    }
+// ---
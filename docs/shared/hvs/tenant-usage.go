@@ -0,0 +1,106 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+
+// TenantUsageReport response payload
+// swagger:parameters TenantUsageReport
+type TenantUsageReport struct {
+	//	in:body
+	Body hvs.TenantUsageReport
+}
+
+// ---
+
+// swagger:operation GET /tenant-usage TenantUsage Search-TenantUsage
+// ---
+// description: |
+//   Reports the usage of HVS per tenant over a period, for the chargeback of a shared attestation infrastructure.
+//   Every call to an authenticated HVS API is counted with the subject of the token of the user that made it and the
+//   tenant of the user. The reports generated for the hosts of a tenant are counted for the tenant. The usage is
+//   recorded per day (UTC). The users of a tenant only get the usage of their tenant, the users that do not belong to
+//   a tenant get the usage of all the tenants. The calls and hosts of the default namespace are reported under the
+//   empty tenant.
+//
+//   With the Accept header text/csv, the daily usage records are exported as CSV with the columns day, tenant_id,
+//   identity, api_calls and report_generations, the report generations are recorded without an identity.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | tenant_id                      | ID of the tenant. |
+//    | api_calls                      | Number of calls made by the users of the tenant over the period. |
+//    | report_generations             | Number of reports generated for the hosts of the tenant over the period. |
+//    | host_count                     | Number of hosts registered by the tenant when the usage is reported. |
+//    | identities                     | Number of calls made by each user of the tenant over the period. |
+//
+// x-permissions: tenant_usage:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+//   - text/csv
+// parameters:
+//   - name: tenantId
+//     description: ID of the tenant, an empty tenantId selects the default namespace.
+//     in: query
+//     type: string
+//     required: false
+//   - name: fromDate
+//     description: |
+//       Usage recorded from this date. Date must be in one of the formats yyyy-MM-ddTHH:mm:ss.SSSZ,
+//       yyyy-MM-dd HH:mm:ss or yyyy-MM-dd.
+//     in: query
+//     type: string
+//     required: false
+//   - name: toDate
+//     description: Usage recorded until this date, in the formats of fromDate.
+//     in: query
+//     type: string
+//     required: false
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+//       - text/csv
+// responses:
+//   "200":
+//     description: Successfully reported the tenant usage.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/TenantUsageReport"
+//   '400':
+//     description: Invalid search criteria provided
+//   '401':
+//     description: Usage of another tenant requested
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/tenant-usage?fromDate=2020-10-01&toDate=2020-10-31
+// x-sample-call-output: |
+//   {
+//        "from_date" : "2020-10-01T00:00:00Z",
+//        "to_date"   : "2020-10-31T00:00:00Z",
+//        "tenants"   : [
+//            {
+//                "tenant_id"          : "tenant-a",
+//                "api_calls"          : 1520,
+//                "report_generations" : 96,
+//                "host_count"         : 4,
+//                "identities"         : [
+//                    {
+//                        "identity"  : "orchestrator@tenant-a",
+//                        "api_calls" : 1520
+//                    }
+//                ]
+//            }
+//        ]
+//   }
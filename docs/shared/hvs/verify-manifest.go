@@ -0,0 +1,129 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// VerifyManifest API request payload
+// swagger:parameters VerifyManifestRequest
+type VerifyManifestRequest struct {
+	// in:body
+	Body hvs.VerifyManifestRequest
+}
+
+// VerifyManifest API response payload
+// swagger:parameters TrustReport
+type TrustReport struct {
+	// in:body
+	Body hvs.TrustReport
+}
+
+// ---
+//
+// swagger:operation POST /rpc/verify-manifest Verify-Manifest Verify-Manifest
+// ---
+//
+// description: |
+//              Verifies a captured host manifest against stored flavors, given by their IDs, and/or proposed
+//              flavors, given inline, and returns the trust report. Nothing is persisted: the proposed flavors are
+//              not stored and the report is not saved for any host. Operators use it to test flavors against the
+//              manifests of their hosts before rolling the flavors out.
+//              The proposed flavors are signed with the flavor signing key of the Verification Service for the
+//              verification only, a proposed flavor without an ID is given a random one. A delta flavor is verified
+//              merged with its base flavor, which must be one of the flavors of the request. The quotes of captured
+//              manifests are old, their age is not verified.
+//
+// x-permissions: flavors:verify
+// security:
+//  - bearerAuth: []
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/VerifyManifestRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Verified the host manifest, the trust report is in the response.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TrustReport"
+//   '400':
+//     description: Invalid request body provided, a flavor does not exist or the manifest could not be verified
+//   '415':
+//     description: Invalid Content-Type Header
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/rpc/verify-manifest
+// x-sample-call-input: |
+//      {
+//         "host_manifest": {
+//             "aik_certificate": "MIIDTDCCAbSgAwIBAgIGAXQ...",
+//             "host_info": {
+//                 "os_name": "RedHatEnterprise",
+//                 "hardware_uuid": "8032632b-8fa4-e811-906e-00163566263e",
+//                 ...
+//             },
+//             "pcr_manifest": { ... },
+//             ...
+//         },
+//         "flavor_ids": [
+//             "890bc756-40da-4bde-a707-3b27b23e0149"
+//         ],
+//         "flavors": [
+//             {
+//                 "flavor": {
+//                     "meta": {
+//                         "description": {
+//                             "flavor_part": "OS",
+//                             "label": "proposed_os_flavor",
+//                             ...
+//                         }
+//                     },
+//                     "pcrs": [ ... ]
+//                 }
+//             }
+//         ]
+//      }
+// x-sample-call-output: |
+//      {
+//         "policy_name": "Intel Host Trust Policy",
+//         "results": [
+//             {
+//                 "rule": {
+//                     "rule_name": "PcrMatchesConstant",
+//                     ...
+//                 },
+//                 "flavor_id": "890bc756-40da-4bde-a707-3b27b23e0149",
+//                 "trusted": true
+//             },
+//             ...
+//         ],
+//         "trusted": false
+//      }
+// ---
@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+
+type ApprovalRequests []kbs.ApprovalRequest
+
+// ApprovalRequest response payload
+// swagger:parameters ApprovalRequest
+type ApprovalRequest struct {
+	// in:body
+	Body kbs.ApprovalRequest
+}
+
+// ApprovalRequestCollection response payload
+// swagger:parameters ApprovalRequestCollection
+type ApprovalRequestCollection struct {
+	// in:body
+	Body ApprovalRequests
+}
+
+// ApprovalDecision request payload
+// swagger:parameters ApprovalDecision
+type ApprovalDecision struct {
+	// in:body
+	Body kbs.ApprovalDecision
+}
+
+// ---
+
+// swagger:operation GET /approvals Approvals SearchApprovalRequests
+// ---
+//
+// description: |
+//   Retrieves the approval requests of the tenant of the user, the oldest first. With dual control enabled
+//   ("dual-control: true" in the configuration), the deletion of a key and the deletion and update of a key
+//   transfer policy create an approval request instead of being executed. The request is executed once approved
+//   by an administrator other than the requester.
+//   Returns - The collection of serialized ApprovalRequest Go struct objects.
+// x-permissions: approvals:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: status
+//   description: Status of the approval requests, one of pending, executed, rejected or failed.
+//   in: query
+//   type: string
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the approval requests.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ApprovalRequests"
+//   '400':
+//     description: Invalid search criteria provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/approvals?status=pending
+// x-sample-call-output: |
+//    [
+//        {
+//            "id": "0a8ba5d1-4e04-4bbb-a6a3-0b7d0c1ea2d6",
+//            "operation": "delete-key",
+//            "resource_id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//            "status": "pending",
+//            "requested_by": "admin-a",
+//            "requested_at": "2021-06-01T10:12:31.5839174Z",
+//            "audit_records": [
+//                {
+//                    "action": "requested",
+//                    "actor": "admin-a",
+//                    "time": "2021-06-01T10:12:31.5839174Z"
+//                }
+//            ]
+//        }
+//    ]
+
+// ---
+
+// swagger:operation GET /approvals/{id} Approvals RetrieveApprovalRequest
+// ---
+//
+// description: |
+//   Retrieves an approval request with its audit records.
+//   Returns - The serialized ApprovalRequest Go struct object that was retrieved.
+// x-permissions: approvals:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the approval request.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the approval request.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ApprovalRequest"
+//   '404':
+//     description: ApprovalRequest record not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/approvals/0a8ba5d1-4e04-4bbb-a6a3-0b7d0c1ea2d6
+
+// ---
+
+// swagger:operation POST /approvals/{id}/approve Approvals ApproveApprovalRequest
+// ---
+//
+// description: |
+//   Approves a pending approval request and executes its operation. The request cannot be approved by its
+//   requester. The update of a key transfer policy fails when the policy has been modified since the update was
+//   requested. The request is marked failed when its operation fails, with the error of the operation.
+//   The request body, with the comment of the approver, is optional.
+//   Returns - The serialized ApprovalRequest Go struct object that was executed.
+// x-permissions: approvals:approve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// consumes:
+//  - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the approval request.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: false
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/ApprovalDecision"
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully approved the request and executed its operation.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ApprovalRequest"
+//   '401':
+//     description: The approver could not be identified
+//   '403':
+//     description: The approver is the requester
+//   '404':
+//     description: ApprovalRequest record not found
+//   '409':
+//     description: The approval request is not pending
+//   '412':
+//     description: The key transfer policy has been modified since the update was requested
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/approvals/0a8ba5d1-4e04-4bbb-a6a3-0b7d0c1ea2d6/approve
+// x-sample-call-input: |
+//    {
+//        "comment": "key retired"
+//    }
+
+// ---
+
+// swagger:operation POST /approvals/{id}/reject Approvals RejectApprovalRequest
+// ---
+//
+// description: |
+//   Rejects a pending approval request, its operation is not executed. The requester can reject its own request
+//   to withdraw it. The request body, with the comment of the reviewer, is optional.
+//   Returns - The serialized ApprovalRequest Go struct object that was rejected.
+// x-permissions: approvals:approve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// consumes:
+//  - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the approval request.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: false
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/ApprovalDecision"
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully rejected the request.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ApprovalRequest"
+//   '401':
+//     description: The reviewer could not be identified
+//   '404':
+//     description: ApprovalRequest record not found
+//   '409':
+//     description: The approval request is not pending
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/approvals/0a8ba5d1-4e04-4bbb-a6a3-0b7d0c1ea2d6/reject
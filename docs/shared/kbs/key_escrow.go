@@ -0,0 +1,284 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+
+// KeyEscrowRequest request payload
+// swagger:parameters KeyEscrowRequest
+type KeyEscrowRequest struct {
+	// in:body
+	Body kbs.KeyEscrowRequest
+}
+
+// KeyEscrowShares response payload
+// swagger:parameters KeyEscrowShares
+type KeyEscrowShares struct {
+	// in:body
+	Body kbs.KeyEscrowShares
+}
+
+// KeyEscrowStatus response payload
+// swagger:parameters KeyEscrowStatus
+type KeyEscrowStatus struct {
+	// in:body
+	Body kbs.KeyEscrowStatus
+}
+
+// KeyEscrowShare request payload
+// swagger:parameters KeyEscrowShare
+type KeyEscrowShare struct {
+	// in:body
+	Body kbs.KeyEscrowShare
+}
+
+// KeyEscrowRecovery response payload
+// swagger:parameters KeyEscrowRecovery
+type KeyEscrowRecovery struct {
+	// in:body
+	Body kbs.KeyEscrowRecovery
+}
+
+// ---
+
+// swagger:operation POST /key-escrow KeyEscrow InitializeKeyEscrow
+// ---
+//
+// description: |
+//   Creates the escrow KEK, the private key of a P-384 key pair, escrows the keys with its public key and splits it
+//   among the custodians. From then on the keys created are escrowed as well. The shares are only returned by this
+//   call, each custodian keeps one of them, KBS only keeps the public key.
+//
+//   The serialized KeyEscrowRequest Go struct object represents the content of the request body.
+//
+//    | Attribute  | Description |
+//    |------------|-------------|
+//    | custodians | Number of shares the escrow KEK is split in, at most 255. |
+//    | threshold  | Number of shares needed to recover the escrowed keys, at least 2 and at most custodians. |
+//
+// x-permissions: key_escrow:create
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/KeyEscrowRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '201':
+//     description: Successfully initialized the key escrow.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyEscrowShares"
+//   '400':
+//     description: Invalid request body provided
+//   '409':
+//     description: Key escrow is already initialized
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/key-escrow
+// x-sample-call-input: |
+//    {
+//        "custodians": 3,
+//        "threshold": 2
+//    }
+// x-sample-call-output: |
+//    {
+//        "kek_id": "f0a4d3b8-67c7-4a40-9f47-b5a2a4a1e2c5",
+//        "custodians": 3,
+//        "threshold": 2,
+//        "shares": [
+//            "shamir1.2.ib5PS3tQbjYrsSQJv-hlCA.g33LC17gcaT_3EiDVv8t9b_IG_NWoEY_x-A3cT6T2AcL.BQjXjrS1Ei0Bo5lk48xk7Q",
+//            "shamir1.2.ib5PS3tQbjYrsSQJv-hlCA.vmAqLy4x3kz5NRISCV0tW67qtpcFDBbAFU8hsFQhePUw.8NoqTy2Mi3O11Yhu0Mmhvg",
+//            "shamir1.2.ib5PS3tQbjYrsSQJv-hlCA.TWdV9TZYUgfEVyiknrIifYUkprIetAJKd0x8im7Iyo5V.9NhQ568pcxo3otwh3EthCA"
+//        ]
+//    }
+
+// ---
+
+// swagger:operation GET /key-escrow KeyEscrow RetrieveKeyEscrow
+// ---
+//
+// description: |
+//   Retrieves the status of the key escrow, with the number of keys in escrow and of shares submitted for a recovery.
+//   Returns - The serialized KeyEscrowStatus Go struct object that was retrieved.
+// x-permissions: key_escrow:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the status of the key escrow.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyEscrowStatus"
+//   '404':
+//     description: Key escrow is not initialized
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/key-escrow
+// x-sample-call-output: |
+//    {
+//        "kek_id": "f0a4d3b8-67c7-4a40-9f47-b5a2a4a1e2c5",
+//        "custodians": 3,
+//        "threshold": 2,
+//        "created_at": "2020-09-23T11:12:26.112Z",
+//        "escrowed_keys": 12,
+//        "submitted_shares": 0
+//    }
+
+// ---
+
+// swagger:operation POST /key-escrow/shares KeyEscrow SubmitKeyEscrowShare
+// ---
+//
+// description: |
+//   Submits the share of a custodian for the recovery of the escrowed keys. The shares are only kept in memory.
+//   The share is submitted as it was returned when the escrow was initialized, a corrupted share is refused.
+//   Returns - The serialized KeyEscrowStatus Go struct object with the number of submitted shares.
+// x-permissions: key_escrow:recover
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/KeyEscrowShare"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully submitted the share.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyEscrowStatus"
+//   '400':
+//     description: Invalid share or share already submitted
+//   '404':
+//     description: Key escrow is not initialized
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/key-escrow/shares
+// x-sample-call-input: |
+//    {
+//        "share": "shamir1.2.ib5PS3tQbjYrsSQJv-hlCA.g33LC17gcaT_3EiDVv8t9b_IG_NWoEY_x-A3cT6T2AcL.BQjXjrS1Ei0Bo5lk48xk7Q"
+//    }
+
+// ---
+
+// swagger:operation DELETE /key-escrow/shares KeyEscrow DiscardKeyEscrowShares
+// ---
+//
+// description: |
+//   Discards the shares submitted for a recovery.
+// x-permissions: key_escrow:recover
+// security:
+//  - bearerAuth: []
+// responses:
+//   '204':
+//     description: Successfully discarded the shares.
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/key-escrow/shares
+
+// ---
+
+// swagger:operation POST /key-escrow/recover KeyEscrow RecoverKeyEscrow
+// ---
+//
+// description: |
+//   Reconstructs the escrow KEK from the submitted shares and restores the escrowed keys missing from the key store.
+//   The keys still in the key store are left as they are. The submitted shares are discarded.
+//   Returns - The serialized KeyEscrowRecovery Go struct object with the restored keys.
+// x-permissions: key_escrow:recover
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully recovered the escrowed keys.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyEscrowRecovery"
+//   '400':
+//     description: Not enough shares submitted or the shares do not reconstruct the escrow KEK
+//   '404':
+//     description: Key escrow is not initialized
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/key-escrow/recover
+// x-sample-call-output: |
+//    {
+//        "restored_keys": [
+//            "e3a3ed5e-1f0d-4c57-8a3b-7d0a5e6c7a40"
+//        ],
+//        "skipped_keys": 11
+//    }
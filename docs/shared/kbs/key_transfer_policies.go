@@ -46,6 +46,12 @@ type KeyTransferPolicyCollection struct {
 //    | tls_client_certificate_san_allof             | Array of Subject Alternative Name to expect in client certificate's extensions. Expect client certificate to have all of these names. |
 //    | attestation_type_anyof                       | Array of Attestation Type identifiers that client must support to get the key expect client to advertise these with the key request e.g. "SGX", "KPT2" (note that if key server needs to restrict technologies, then it should list only the ones that can receive the key). |
 //    | sgx_enforce_tcb_up_to_date                   | Boolean. |
+//    | key_caching_allowed                          | Boolean. Allows the client enclave to cache the released keys. |
+//    | key_cache_timeout                            | Number of seconds the client enclave may cache a released key for, requires key_caching_allowed. A cached key is discarded at the expiry of the session at the latest. |
+//    | key_reattest_on_reuse                        | Boolean. Requires the client enclave to be attested again before it reuses a cached key, the session is ended after each key transfer. |
+//
+//   The policy is created in the namespace of the tenant set in the KBS role context of the user as "tenant=<id>",
+//   and is only visible to the users of that tenant.
 //
 // x-permissions: keys-transfer-policies:create
 // security:
@@ -83,6 +89,8 @@ type KeyTransferPolicyCollection struct {
 //       $ref: "#/definitions/KeyTransferPolicyAttributes"
 //   '400':
 //     description: Invalid request body provided
+//   '403':
+//     description: Key transfer policy quota of the tenant has been reached
 //   '415':
 //     description: Invalid Accept Header in Request
 //   '500':
@@ -125,7 +133,7 @@ type KeyTransferPolicyCollection struct {
 //
 // description: |
 //   Retrieves a key transfer policy.
-//   Returns - The serialized KeyTransferPolicyAttributes Go struct object that was retrieved.
+//   Returns - The serialized KeyTransferPolicyAttributes Go struct object that was retrieved, with its version as ETag.
 // x-permissions: keys-transfer-policies:retrieve
 // security:
 //  - bearerAuth: []
@@ -173,7 +181,98 @@ type KeyTransferPolicyCollection struct {
 //        "tls_client_certificate_issuer_cn_anyof":["CMSCA", "CMS TLS Client CA"],
 //        "tls_client_certificate_san_allof":["nginx","USA"],
 //        "attestation_type_anyof":["SGX"],
-//        "created_at": "2020-06-09T01:05:47-0700"
+//        "created_at": "2020-06-09T01:05:47-0700",
+//        "version": 0
+//    }
+
+// ---
+
+// swagger:operation PUT /key-transfer-policies/{id} KeyTransferPolicies UpdateKeyTransferPolicy
+// ---
+//
+// description: |
+//   Replaces a key transfer policy, the id, creation time and tenant of the policy are kept.
+//   The request body is the same as for the creation of a key transfer policy. Each update increments the
+//   version of the policy, which is returned as ETag. With If-Match the update only succeeds when the policy
+//   has not been modified since that version was retrieved, otherwise 412 is returned with the current version.
+//   With dual control enabled, an approval request of the update is created instead and the policy is updated
+//   once the request is approved, unless the policy has been modified since the request.
+//   Returns - The serialized KeyTransferPolicyAttributes Go struct object that was updated.
+// x-permissions: key_transfer_policies:update
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key transfer policy.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/KeyTransferPolicyAttributes"
+// - name: If-Match
+//   description: Version of the key transfer policy returned as ETag, the update fails with 412 when it has been modified since.
+//   in: header
+//   type: string
+//   required: false
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully updated the key transfer policy.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyTransferPolicyAttributes"
+//   '202':
+//     description: Successfully created the approval request of the update, with dual control enabled.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ApprovalRequest"
+//   '400':
+//     description: Invalid request body or If-Match header provided
+//   '404':
+//     description: KeyTransferPolicy record not found
+//   '412':
+//     description: KeyTransferPolicy has been modified, its current version is returned as ETag
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/key-transfer-policies/75d34bf4-80fb-4ca5-8602-a8d82e56b30d
+// x-sample-call-input: |
+//    {
+//        "sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+//        "sgx_enclave_issuer_product_id_anyof": [1]
+//    }
+// x-sample-call-output: |
+//    {
+//        "id": "75d34bf4-80fb-4ca5-8602-a8d82e56b30d",
+//        "sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+//        "sgx_enclave_issuer_product_id_anyof": [1],
+//        "created_at": "2020-06-09T01:05:47-0700",
+//        "version": 1
 //    }
 
 // ---
@@ -182,7 +281,8 @@ type KeyTransferPolicyCollection struct {
 // ---
 //
 // description: |
-//   Deletes a key transfer policy.
+//   Deletes a key transfer policy. With dual control enabled, an approval request of the deletion is created
+//   instead and the policy is deleted once the request is approved through the approvals API.
 // x-permissions: keys-transfer-policies:delete
 // security:
 //  - bearerAuth: []
@@ -196,6 +296,10 @@ type KeyTransferPolicyCollection struct {
 // responses:
 //   '204':
 //     description: Successfully deleted the key transfer policy.
+//   '202':
+//     description: Successfully created the approval request of the deletion, with dual control enabled.
+//     schema:
+//       $ref: "#/definitions/ApprovalRequest"
 //   '404':
 //     description: KeyTransferPolicy record not found
 //   '500':
@@ -29,6 +29,34 @@ type KeyCollection struct {
 	Body KeyResponses
 }
 
+// KeyImageFlavorBinding request payload
+// swagger:parameters KeyImageFlavorBinding
+type KeyImageFlavorBinding struct {
+	// in:body
+	Body kbs.KeyImageFlavorBinding
+}
+
+// KeyAttributesUpdate request payload
+// swagger:parameters KeyAttributesUpdate
+type KeyAttributesUpdate struct {
+	// in:body
+	Body kbs.KeyAttributesUpdate
+}
+
+// Tpm2KeyTransfer request payload
+// swagger:parameters Tpm2KeyTransferRequest
+type Tpm2KeyTransferRequest struct {
+	// in:body
+	Body kbs.Tpm2KeyTransferRequest
+}
+
+// Tpm2KeyTransfer response payload
+// swagger:parameters Tpm2KeyTransferResponse
+type Tpm2KeyTransferResponse struct {
+	// in:body
+	Body kbs.Tpm2KeyTransferResponse
+}
+
 // KeyTransfer response payload
 // swagger:parameters KeyTransferAttributes
 type KeyTransferAttributes struct {
@@ -52,17 +80,35 @@ type KeyTransferAttributes struct {
 //    | transfer_policy_id | Unique identifier of the transfer policy to apply to this key. |
 //    | label              | String to attach optionally a text description to the key, e.g. "US Nginx key". |
 //    | usage              | String to attach optionally a usage criteria for the key, e.g. "Country:US,State:CA". |
+//    | attributes         | Optional name/value pairs the keys can be searched with, e.g. {"env": "prod"}. |
 //
 //   The serialized KeyInformation Go struct object represents the content of the key_information field.
 //
 //    | Attribute   | Description |
 //    |-------------|-------------|
-//    | algorithm   | Encryption algorithm used to create or register key. Supported algorithms are AES, RSA and EC. |
-//    | key_length  | Key length used to create key. Supported key lengths are 128,192,256 bits for AES and 2048,3072,4096,7680,15360 bits for RSA. |
+//    | algorithm   | Encryption algorithm used to create or register key. Supported algorithms are AES, AES-XTS, RSA and EC. AES-XTS volume keys can only be created. |
+//    | key_length  | Key length used to create key. Supported key lengths are 128,192,256 bits for AES, 256,512 bits for AES-XTS and 2048,3072,4096,7680,15360 bits for RSA. |
 //    | curve_type  | Elliptic curve used to create key. Supported curves are secp256r1, secp384r1 and secp521r1. |
 //    | key_string  | Base64 encoded private key to be registered. Supported only if key is created locally. |
 //    | kmip_key_id | Unique KMIP identifier of key to be registered. Supported only if key is created on KMIP server. |
 //
+//   The optional injection_targets list where the agent on the attested node delivers the key once it is
+//   transferred, for the workloads that cannot embed the key transfer client. The targets are returned with the
+//   transferred key and the agent writes the unwrapped key to each of them.
+//
+//    | Attribute   | Description |
+//    |-------------|-------------|
+//    | type        | kubernetes-secret, file or env-template. |
+//    | name        | Name of the kubernetes Secret created or replaced with the key. |
+//    | namespace   | Namespace of the kubernetes Secret. |
+//    | key         | Entry of the kubernetes Secret holding the key, defaults to the key id. |
+//    | path        | Path of the file written with the key or the rendered template, relative to the secrets directory of the agent. |
+//    | template    | Go template of an env-template target, e.g. "APP_KEY={{.SecretBase64}}". {{.Secret}} and {{.KeyId}} are also available. |
+//
+//   The key is created in the namespace of the tenant set in the KBS role context of the user as "tenant=<id>".
+//   Keys can only be retrieved, transferred and deleted by the users of their tenant, and only use the key
+//   transfer policies of their tenant or the default key transfer policy.
+//
 // x-permissions: keys:create,keys:register
 // security:
 //  - bearerAuth: []
@@ -99,6 +145,8 @@ type KeyTransferAttributes struct {
 //       $ref: "#/definitions/KeyResponse"
 //   '400':
 //     description: Invalid request body provided
+//   '403':
+//     description: Key quota of the tenant has been reached
 //   '415':
 //     description: Invalid Accept Header in Request
 //   '500':
@@ -185,12 +233,19 @@ type KeyTransferAttributes struct {
 //
 // description: |
 //   Transfers a key.
-//   Returns - The serialized KeyTransferAttributes Go struct object that was retrieved.
+//   Returns - The serialized KeyTransferAttributes Go struct object that was retrieved. The injection targets of
+//   the key are returned in injection_targets.
+//
+//   With the application/jose Accept header, the key is instead returned as an RFC 7516 JWE object in compact
+//   serialization, that off-the-shelf JOSE libraries decrypt with the private key of the envelope key. The content
+//   encryption key is wrapped with RSA-OAEP-256, the key is encrypted with A256GCM and the kid of the protected
+//   header is the ID of the key.
 // x-permissions: keys:transfer
 // security:
 //  - bearerAuth: []
 // produces:
 // - application/json
+// - application/jose
 // consumes:
 // - text/plain
 // parameters:
@@ -214,13 +269,25 @@ type KeyTransferAttributes struct {
 //   required: true
 //   enum:
 //     - application/json
+//     - application/jose
+// - name: Image-Flavor-Id
+//   description: |
+//     Unique ID of the image flavor of the workload requesting the key. Required for the keys bound to an
+//     image flavor, those keys are only transferred to the workloads of that image flavor.
+//   in: header
+//   type: string
+//   format: uuid
+//   required: false
 // responses:
 //   '200':
 //     description: Successfully transferred the key.
 //     content:
 //       application/json
+//       application/jose
 //     schema:
 //       $ref: "#/definitions/KeyTransferAttributes"
+//   '401':
+//     description: Workload image flavor does not match the key binding
 //   '404':
 //     description: Key record not found
 //   '415':
@@ -249,11 +316,321 @@ type KeyTransferAttributes struct {
 
 // ---
 
+// swagger:operation POST /keys/{id}/tpm2-transfer Keys TransferKeyToTpm2
+// ---
+//
+// description: |
+//   Transfers a key to a host with a TPM 2.0 as a duplication blob wrapped to a storage key of its TPM, the host
+//   imports the key in its TPM with TPM2_Import and the key is never exposed in the memory of the host.
+//   The request includes the trust report of the host and the public area of the storage key certified by the AIK
+//   of the host with TPM2_Certify. The storage key must be an RSA restricted decryption key with the fixedTPM and
+//   fixedParent attributes and an AES CFB symmetric algorithm, as the SRKs created with the default template.
+//   The transfer succeeds only if the trust report is signed by a trusted HVS, the host is trusted, its AIK
+//   certificate is issued by a trusted privacy CA, the certification of the storage key is signed by the AIK and
+//   the tags deployed on the host match the usage policy of the key.
+//
+//   | Attribute                | Description |
+//   |--------------------------|-------------|
+//   | saml_report              | Trust report of the host. |
+//   | parent_public            | Base64 encoded TPM2B_PUBLIC of the storage key. |
+//   | parent_certify_info      | Base64 encoded TPMS_ATTEST of the certification of the storage key by the AIK. |
+//   | parent_certify_signature | Base64 encoded TPMT_SIGNATURE of the certification. |
+//   | auth_policy              | (Optional) Base64 encoded policy digest the imported key is restricted to. Without it the key is usable with an empty authorization value. |
+//
+//   AES keys are imported as symmetric cipher objects and RSA and EC keys as unrestricted keys, AES-XTS keys cannot
+//   be imported. The response holds the base64 encoded TPM2B_PUBLIC, TPM2B_PRIVATE and TPM2B_ENCRYPTED_SECRET
+//   parameters of TPM2_Import.
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/Tpm2KeyTransferRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Image-Flavor-Id
+//   description: |
+//     Unique ID of the image flavor of the workload requesting the key. Required for the keys bound to an
+//     image flavor, those keys are only transferred to the workloads of that image flavor.
+//   in: header
+//   type: string
+//   format: uuid
+//   required: false
+// responses:
+//   '200':
+//     description: Successfully transferred the key.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Tpm2KeyTransferResponse"
+//   '400':
+//     description: Invalid request body provided
+//   '401':
+//     description: Client or storage key not trusted, or workload image flavor does not match the key binding
+//   '404':
+//     description: Key record not found
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/tpm2-transfer
+// x-sample-call-input: |
+//    {
+//        "saml_report": "<?xml version=\"1.0\" encoding=\"UTF-8\"?><saml2:Assertion ...</saml2:Assertion>",
+//        "parent_public": "ARoAAQALAAMEcgAAAAYAgABDABAIAAAAAAABADLLO+2npTcGVUwXkn5OBJZBryUvZhDgqcztUe9W1vcDbVO52RmWLAN1ji1XmiqnCCuuBwOp/RwmEXMhSIwfgCKfrIaXGNHV82j9EXr6OrkXse9mokUfi3+Mfum7jAx60IZAP7Yr1lozulMgbgG2zm/qW8QankZ9Ko5LyYNhponS7C1gQ3xt0mPOMvf0Q3zMKryODsJAIAKZ27iCwRBFxQbg7KU/yzxkFdV98nYdyunHPvN6oWuwbJtEBnnzhcDbQvG30OZNv7fBxINQ4DojZgtDBU7LDFwCTwoRW7+GBHHHyvr8M86ptPVPnADxQK7IkOSTHXjqUUfc2RfCnQtlRog=",
+//        "parent_certify_info": "/1RDR4AXACIAC31Zy/mbaQ7a027mhahlgSD0mdhUtzlkUizHKquAF3p+AAClLL0+P+RWntrZ7U4ObhBvxywHhc7kD/W6ACIAC0GZTsPvnk6HmbDnvVyIhBxrbSkDnX8Tj206kRVWUFw1ACIAC2XyexLLRbys2htJYorkS6/G+50jiZErfN02C+P5gXrJ",
+//        "parent_certify_signature": "ABQACwEAC3MGumKuy4bXMGP98tBSSRvJqcpkpr9veQQdAGFM1feJU+qeoDE0jWN227j6ELmKVzekvahlm+G30deUjnoMk8aZpCe2EYVbJBJZLsSkhxdVf++prZdxRD5YtVICcGDUixnfS1eVOKbUAKibAmKYLMQTiACEW9jUnE45H+C4+h3p6U11h9Uy1M2ulyybGGwiYqUXWzQQos3OKFQSefIYUKCSuJsmHLVBxNvmCf3uaC4S8YI2ZcenrTE2rYVD6us6EB2gNQM/CeYFg3NtP4TYTPMC70jBvp/tb0tCMlqLVGAAQgDqbls+f8w852Nv91vcsp4wZwYyWnxnmVu/PoyyMA=="
+//    }
+// x-sample-call-output: |
+//    {
+//        "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//        "algorithm": "AES",
+//        "key_length": 256,
+//        "object_public": "ADIAJQALAAYAQAAAAAYBAABDACDjDyRkMhjAMq+2QTmxHq262FjncPI8jU8lZXdDnpipfw==",
+//        "duplicate": "AGoAIO4JyAgC/2KAKomH1hFSHrp9OstAhD2Dp98bnv8TW03RM4+bqxX6uOyzdtT/Sjx4mgHkeNRUZoASD45PDEm9FlYVyyAHPiqlTT+tlkGSbBAIvMsrJrNTqEtrQRZMZvBcKC9xVKXCA0Fv",
+//        "in_sym_seed": "AQD2SwQz9Yxq23g6DvyFyCPK7HA+wiblgg0+izUnsCVRZLv1FCAnh5dcLHASphpYji7Wgc4R+aq54pdvwwITiiQ1EF7aQJ/MXUVABuaTNAOz186agKdEWU/fGJRFrnSz8SPW0KRmNqgpZ4QqSn9yfxvnXTw6B7q/erGoBMcHFB4x/l4YRv7l9D9HqyAz8TliVGF7hUK4dlOCTmH7B9kRS4PmZicuoseLEFuWjPIBG61LGET5QU1yFJq2dzQqrx6gFY44xTwFOch1mqsrb2eujTMzRX4DfG2j0M1Kp+oiJQiex+UF4zyFjKaEOtA3iBRu7r2BPm1gIRpgu1L/So0h3hDJ"
+//    }
+
+// ---
+
+// swagger:operation PUT /keys/{id}/image-flavor-binding Keys BindKeyImageFlavor
+// ---
+//
+// description: |
+//   Binds a key to the image flavor of the workloads it is created for. The workload service (WLS) sends the
+//   image flavor id of the workload in the Image-Flavor-Id header of its transfer requests, a bound key is only
+//   transferred when the header matches the image flavor id of the binding. An existing binding is replaced.
+//   Returns - The serialized KeyResponse Go struct object of the bound key, with its new version as ETag.
+// x-permissions: keys:bind_image_flavor
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: If-Match
+//   description: Version of the key returned as ETag, the update fails with 412 when it has been modified since.
+//   in: header
+//   type: string
+//   required: false
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/KeyImageFlavorBinding"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully bound the key to the image flavor.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyResponse"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Key record not found
+//   '412':
+//     description: Key has been modified, its current version is returned as ETag
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/image-flavor-binding
+// x-sample-call-input: |
+//    {
+//        "image_flavor_id": "4bcaef54-01a5-45e6-9c25-4b7b4a6a2cc3"
+//    }
+// x-sample-call-output: |
+//    {
+//        "key_information": {
+//            "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//            "algorithm": "AES",
+//            "key_length": 256
+//        },
+//        "transfer_policy_id": "3ce27bbd-3c5f-4b15-8c0a-44310f0f83d9",
+//        "transfer_link": "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+//        "created_at": "2020-09-23T11:16:26.738467277Z",
+//        "image_flavor_id": "4bcaef54-01a5-45e6-9c25-4b7b4a6a2cc3",
+//        "version": 1
+//    }
+
+// ---
+
+// swagger:operation DELETE /keys/{id}/image-flavor-binding Keys UnbindKeyImageFlavor
+// ---
+//
+// description: |
+//   Removes the image flavor binding of a key, the new version of the key is returned as ETag.
+// x-permissions: keys:bind_image_flavor
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: If-Match
+//   description: Version of the key returned as ETag, the update fails with 412 when it has been modified since.
+//   in: header
+//   type: string
+//   required: false
+// responses:
+//   '204':
+//     description: Successfully removed the image flavor binding of the key.
+//   '404':
+//     description: Key record not found
+//   '412':
+//     description: Key has been modified, its current version is returned as ETag
+//   '500':
+//     description: Internal server error
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/image-flavor-binding
+
+// ---
+
+// swagger:operation PUT /keys/{id}/attributes Keys UpdateKeyAttributes
+// ---
+//
+// description: |
+//   Replaces the attributes of a key, the keys can be searched with their attributes. The names of the attributes
+//   are alphanumeric with -, _, . or / inside and at most 63 characters, a key has at most 32 attributes. The
+//   attributes are removed when the attributes of the request are empty.
+//   Returns - The serialized KeyResponse Go struct object of the updated key, with its new version as ETag.
+// x-permissions: keys:update_attributes
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: If-Match
+//   description: Version of the key returned as ETag, the update fails with 412 when it has been modified since.
+//   in: header
+//   type: string
+//   required: false
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/KeyAttributesUpdate"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully updated the attributes of the key.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyResponse"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Key record not found
+//   '412':
+//     description: Key has been modified, its current version is returned as ETag
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/attributes
+// x-sample-call-input: |
+//    {
+//        "attributes": {
+//            "env": "prod",
+//            "team": "payments"
+//        }
+//    }
+// x-sample-call-output: |
+//    {
+//        "key_information": {
+//            "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//            "algorithm": "AES",
+//            "key_length": 256
+//        },
+//        "transfer_policy_id": "3ce27bbd-3c5f-4b15-8c0a-44310f0f83d9",
+//        "transfer_link": "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+//        "created_at": "2020-09-23T11:16:26.738467277Z",
+//        "version": 1,
+//        "attributes": {
+//            "env": "prod",
+//            "team": "payments"
+//        }
+//    }
+
+// ---
+
 // swagger:operation DELETE /keys/{id} Keys DeleteKey
 // ---
 //
 // description: |
-//   Deletes a key.
+//   Deletes a key. With dual control enabled, an approval request of the deletion is created instead and the key
+//   is deleted once the request is approved through the approvals API.
 // x-permissions: keys:delete
 // security:
 //  - bearerAuth: []
@@ -267,6 +644,10 @@ type KeyTransferAttributes struct {
 // responses:
 //   '204':
 //     description: Successfully deleted the key.
+//   '202':
+//     description: Successfully created the approval request of the deletion, with dual control enabled.
+//     schema:
+//       $ref: "#/definitions/ApprovalRequest"
 //   '404':
 //     description: Key record not found
 //   '500':
@@ -292,7 +673,7 @@ type KeyTransferAttributes struct {
 //   in: query
 //   type: string
 //   required: false
-//   enum: [AES, RSA, EC, aes, rsa, ec]
+//   enum: [AES, AES-XTS, RSA, EC, aes, aes-xts, rsa, ec]
 // - name: keyLength
 //   description: Key length.
 //   in: query
@@ -311,6 +692,26 @@ type KeyTransferAttributes struct {
 //   type: string
 //   format: uuid
 //   required: false
+// - name: attribute
+//   description: Attribute of the keys as name:value, the keys with all the attributes are returned when it is repeated.
+//   in: query
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   required: false
+// - name: createdAfter
+//   description: Returns the keys created after the date (YYYY-MM-DDThh:mm:ssZ).
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// - name: createdBefore
+//   description: Returns the keys created before the date (YYYY-MM-DDThh:mm:ssZ).
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -332,7 +733,7 @@ type KeyTransferAttributes struct {
 //   '500':
 //     description: Internal server error
 //
-// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys?attribute=env:prod&createdAfter=2020-09-01T00:00:00Z
 // x-sample-call-output: |
 //    [
 //        {
@@ -343,6 +744,10 @@ type KeyTransferAttributes struct {
 //            },
 //            "transfer_policy_id": "3ce27bbd-3c5f-4b15-8c0a-44310f0f83d9",
 //            "transfer_link": "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
-//            "created_at": "2020-09-23T11:16:26.738467277Z"
+//            "created_at": "2020-09-23T11:16:26.738467277Z",
+//            "version": 1,
+//            "attributes": {
+//                "env": "prod"
+//            }
 //        }
 //    ]
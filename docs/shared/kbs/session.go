@@ -35,6 +35,16 @@ type SessionResponseAttributes struct {
 //    | challenge_type     | String to identify Security Technology Module(STM) label, e.g. "SGX" or "SW". |
 //    | challenge          | Base64-encoded unique ID shared by KBS. |
 //    | quote              | Base64-encoded string containing SGX attributes and public key certificate. Quote can be retrieved by printing it in the KBS/SQVS logs. |
+//    | compression        | (Optional) Payload compression algorithms accepted by the client, in order of preference. Only "gzip" is supported. |
+//
+//   The challenge is issued by KBS with a nonce, the nonce is the challenge UUID without '-'. The SGX quote must bind the
+//   public key and the nonce in its report data. The session must be created with the challenge before the
+//   challenge_expiry returned with it (nonce-expiry-time seconds, 120 by default) and only one request is accepted per
+//   challenge, a request replaying the challenge is rejected even if the previous request failed.
+//
+//   When a compression algorithm is negotiated it is returned in the compression field of the response. Key payloads of 4096 bytes
+//   or more transferred within the session are then compressed before they are encrypted with the session key, the compression
+//   field of the transferred key information is set when the payload was compressed.
 //
 // security:
 //  - bearerAuth: []
@@ -74,7 +84,7 @@ type SessionResponseAttributes struct {
 //     schema:
 //       $ref: "#/definitions/SessionResponseAttributes"
 //   '400':
-//     description: Invalid session create request
+//     description: Invalid session create request, or the challenge nonce is expired or has already been used
 //   '401':
 //     description: Unauthorized request
 //   '500':
@@ -85,13 +95,15 @@ type SessionResponseAttributes struct {
 //  {
 //      "challenge_type": "SGX",
 //      "challenge": "MTRjZmNlZDEtMDNlZS00YTY4LThiNTAtNmQ0NTY0MjNiMDc4",
-//      "quote": "AQAAAAAAAAB7EwAAAQAAAAEAAAADAAAAAAEAANwGAAAtLS0tLUJFR0lOI...."
+//      "quote": "AQAAAAAAAAB7EwAAAQAAAAEAAAADAAAAAAEAANwGAAAtLS0tLUJFR0lOI....",
+//      "compression": ["gzip"]
 //  }
 // x-sample-call-output: |
 //  {
 //      "data": {
 //              "swk": "sOCGP84HrADhs8VAmVrYA25w2yQEdMZMLS3il6g2fY0xDucCvRJWapmETaz7Au8t/zDkgVpT9StR6qpscxkTTkk0hE8tD4Lk8ArQ3SBp6a+kOf5Qwj30P/Zsv1WejhoVI/k+PFoMeCDxpqSG9mSKTAYLqFQtnnJGYOIWaIHHn6PARDEvVMFSMD3uqdqPwyx9cx+rt9n8oIcdraYEfpUWzv4uVaDOQj0I/+8WjFL8JgGOdl0n91eo3WGUHFgEjvBNeWdrwvvEMp6GusId4gIuascjpqFrGzjDSuXaLcY00lZIqe9PlyqHMSJg5Q1/QBvz7X4E2iPMU20EoxlOI2QPyg==",
-//              "type": "AES256-GCM"
+//              "type": "AES256-GCM",
+//              "compression": "gzip"
 //      },
 //      "operation": "establish session key",
 //      "status": "success"
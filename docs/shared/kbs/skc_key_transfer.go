@@ -20,6 +20,15 @@ type KeyTransferResponse struct {
 //
 // description: |
 //   Transfers a key to the SKC-Library. TLS-Mutual authentication happens between KBS and SKC-Library, hence skc-client certificate and root-ca certificate needs to be provided in the request.
+
+//   When the skc-client certificate is an RA-TLS certificate embedding an SGX or TDX quote, the quote is verified with the first key transfer
+//   request of the certificate and a session bound to the certificate is established. The Session-Id header can then be omitted and the session key, wrapped with the certificate
+//   public key, is returned in the swk field of the key information. The payload compression of such a session is negotiated with the
+//   Accept-Compression header of the first key transfer request.
+//
+//   The cache_policy of the key information tells the client enclave whether it may cache the key (cacheable), until when (cache_until)
+//   and whether it must be attested again before it reuses the key (reattest_on_reuse), as set by the key transfer policy. When the
+//   re-attestation is required the session ends with the transfer, and the next transfer of the client starts a new session.
 //
 //   Returns - The serialized KeyTransferResponse Go struct object that was retrieved.
 // security:
@@ -40,12 +49,20 @@ type KeyTransferResponse struct {
 //   required: true
 //   enum:
 //     - SGX
+//     - TDX
 //     - SW
 // - name: Session-Id
 //   description: Mapping of challenge-type and session-id. KBS returns base64-encoded session-id in the form of challenge. Provide decoded session-id value in header, e.g. SGX:19c3f009-39c9-4734-a535-edb42c76dfa8.
 //   in: header
 //   type: string
 //   required: true
+// - name: Accept-Compression
+//   description: Comma separated payload compression algorithms accepted by the client, for sessions established over RA-TLS.
+//   in: header
+//   type: string
+//   required: false
+//   enum:
+//     - gzip
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+
+type TenantQuotas []kbs.TenantQuota
+
+// TenantQuota request/response payload
+// swagger:parameters TenantQuota
+type TenantQuota struct {
+	// in:body
+	Body kbs.TenantQuota
+}
+
+// TenantQuotaCollection response payload
+// swagger:parameters TenantQuotaCollection
+type TenantQuotaCollection struct {
+	// in:body
+	Body TenantQuotas
+}
+
+// ---
+
+// swagger:operation PUT /tenant-quotas/{tenantId} TenantQuotas UpdateTenantQuota
+// ---
+//
+// description: |
+//   Sets the quota of a tenant, replacing its existing quota.
+//
+//   The serialized TenantQuota Go struct object represents the content of the request body.
+//
+//    | Attribute                 | Description |
+//    |---------------------------|-------------|
+//    | tenant_id                 | Optional, must match the tenant in path when provided. |
+//    | max_keys                  | Maximum number of keys of the tenant, zero for no limit. |
+//    | max_key_transfer_policies | Maximum number of key transfer policies of the tenant, zero for no limit. |
+//
+// x-permissions: tenant_quotas:update
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: tenantId
+//   description: Tenant set in the KBS role context of its users as "tenant=<id>".
+//   in: path
+//   required: true
+//   type: string
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/TenantQuota"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully set the quota of the tenant.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TenantQuota"
+//   '400':
+//     description: Invalid request body provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/tenant-quotas/tenant-a
+// x-sample-call-input: |
+//    {
+//        "max_keys": 100,
+//        "max_key_transfer_policies": 10
+//    }
+// x-sample-call-output: |
+//    {
+//        "tenant_id": "tenant-a",
+//        "max_keys": 100,
+//        "max_key_transfer_policies": 10
+//    }
+
+// ---
+
+// swagger:operation GET /tenant-quotas/{tenantId} TenantQuotas RetrieveTenantQuota
+// ---
+//
+// description: |
+//   Retrieves the quota of a tenant.
+//   Returns - The serialized TenantQuota Go struct object that was retrieved.
+// x-permissions: tenant_quotas:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: tenantId
+//   description: Tenant set in the KBS role context of its users as "tenant=<id>".
+//   in: path
+//   required: true
+//   type: string
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the quota of the tenant.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TenantQuota"
+//   '404':
+//     description: TenantQuota record not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/tenant-quotas/tenant-a
+// x-sample-call-output: |
+//    {
+//        "tenant_id": "tenant-a",
+//        "max_keys": 100,
+//        "max_key_transfer_policies": 10
+//    }
+
+// ---
+
+// swagger:operation DELETE /tenant-quotas/{tenantId} TenantQuotas DeleteTenantQuota
+// ---
+//
+// description: |
+//   Deletes the quota of a tenant, the tenant is then not limited. The keys and key transfer policies of the tenant are not deleted.
+// x-permissions: tenant_quotas:delete
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: tenantId
+//   description: Tenant set in the KBS role context of its users as "tenant=<id>".
+//   in: path
+//   required: true
+//   type: string
+// responses:
+//   '204':
+//     description: Successfully deleted the quota of the tenant.
+//   '404':
+//     description: TenantQuota record not found
+//   '500':
+//     description: Internal server error
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/tenant-quotas/tenant-a
+
+// ---
+
+// swagger:operation GET /tenant-quotas TenantQuotas SearchTenantQuotas
+// ---
+//
+// description: |
+//   Retrieves the quotas of all the tenants.
+//   Returns - The collection of serialized TenantQuota Go struct objects.
+// x-permissions: tenant_quotas:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the tenant quotas.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TenantQuotas"
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/tenant-quotas
+// x-sample-call-output: |
+//    [
+//        {
+//            "tenant_id": "tenant-a",
+//            "max_keys": 100,
+//            "max_key_transfer_policies": 10
+//        }
+//    ]
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+// swagger:operation GET /tls-certificate TlsCertificate RetrieveTlsCertificate
+// ---
+//
+// description: |
+//   Retrieves the details of the TLS certificate currently served by KBS.
+//   Returns - The serialized Certificate Go struct object of the served certificate.
+// x-permissions: tls_certificate:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the TLS certificate.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Certificate"
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/tls-certificate
+// x-sample-call-output: |
+//    {
+//        "certificate": "MIIENDCCApygAwIBAgIBAzANBgkqhkiG9w0BAQwFADBQMQswCQYDVQQGEwJVUzELMAkGA1UE...",
+//        "subject": "KBS TLS Certificate",
+//        "issuer": "CMS TLS CA",
+//        "not_before": "2021-03-01T10:12:45Z",
+//        "not_after": "2022-03-01T10:12:45Z",
+//        "revoked": false,
+//        "digest": "b5e39cdd0a0c5d0a2a1e7d4c1a4f6b8e0e5f8e6f7d91a44b0f1a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6"
+//    }
+
+// ---
+
+// swagger:operation POST /tls-certificate/reload TlsCertificate ReloadTlsCertificate
+// ---
+//
+// description: |
+//   Reloads the TLS certificate and key from the configured files without restarting the service.
+//   New connections are served with the reloaded certificate while established connections are kept.
+//   If the files on disk do not contain a valid key pair, the previous certificate remains in use.
+//   KBS also checks the files periodically as configured by tls-reload-interval.
+//   The replaced key pair is still served, for tls-key-overlap, to the clients that do not support the new one,
+//   e.g. when the key moves from RSA to ECDSA.
+//   Returns - The serialized Certificate Go struct object of the reloaded certificate.
+// x-permissions: tls_certificate:reload
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully reloaded the TLS certificate.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Certificate"
+//   '400':
+//     description: Certificate or key on disk is invalid
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/tls-certificate/reload
+// x-sample-call-output: |
+//    {
+//        "certificate": "MIIENDCCApygAwIBAgIBBDANBgkqhkiG9w0BAQwFADBQMQswCQYDVQQGEwJVUzELMAkGA1UE...",
+//        "subject": "KBS TLS Certificate",
+//        "issuer": "CMS TLS CA",
+//        "not_before": "2021-06-01T08:02:11Z",
+//        "not_after": "2022-06-01T08:02:11Z",
+//        "revoked": false,
+//        "digest": "0c1d2e3f405162738495a6b7c8d9eaf0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6"
+//    }
@@ -5,14 +5,12 @@
 package authservice
 
 import (
-	"flag"
 	"fmt"
 	"github.com/intel-secl/intel-secl/v3/pkg/authservice/config"
-	"github.com/intel-secl/intel-secl/v3/pkg/authservice/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	commLogInt "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/setup"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -24,8 +22,6 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/postgres"
 )
 
-var errInvalidCmd = errors.New("Invalid input after command")
-
 type App struct {
 	HomeDir        string
 	ConfigDir      string
@@ -49,62 +45,12 @@ func (a *App) Run(args []string) error {
 			defaultLog.Errorf("Panic occurred: %+v", err)
 		}
 	}()
-	if len(args) < 2 {
-		err := errors.New("Invalid usage of " + constants.ServiceCommand)
-		a.printUsageWithError(err)
-		return err
+	runner := &commCmd.Runner{
+		Root:          a.commands(),
+		ConsoleWriter: a.consoleWriter(),
+		PrintUsage:    a.printUsageWithError,
 	}
-
-	cmd := args[1]
-	switch cmd {
-	default:
-		err := errors.New("Invalid command: " + cmd)
-		a.printUsageWithError(err)
-		return err
-	case "run":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.startServer()
-	case "help", "-h", "--help":
-		a.printUsage()
-	case "start":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.start()
-	case "stop":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.stop()
-	case "status":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.status()
-	case "uninstall":
-		var purge bool
-		flag.CommandLine.BoolVar(&purge, "purge", false, "purge config when uninstalling")
-		err := flag.CommandLine.Parse(args[2:])
-		if err != nil {
-			return err
-		}
-		return a.uninstall(purge)
-	case "version", "--version", "-v":
-		a.printVersion()
-		return nil
-	case "setup":
-		if err := a.setup(args[1:]); err != nil {
-			if errors.Cause(err) == setup.ErrTaskNotFound {
-				a.printUsageWithError(err)
-			} else {
-				fmt.Fprintln(a.errorWriter(), err.Error())
-			}
-			return err
-		}
-	}
-	return nil
+	return runner.Run(args)
 }
 
 func (a *App) consoleWriter() io.Writer {
@@ -207,7 +153,14 @@ func (a *App) stop() error {
 	return cmd.Run()
 }
 
-func (a *App) status() error {
+func (a *App) status(ctx *commCmd.Context) error {
+	if ctx.JSONOutput() {
+		status, err := commCmd.GetServiceStatus("authservice")
+		if err != nil {
+			return err
+		}
+		return ctx.Print("", status)
+	}
 	fmt.Fprintln(a.consoleWriter(), `Forwarding to "systemctl status authservice"`)
 	systemctl, err := exec.LookPath("systemctl")
 	if err != nil {
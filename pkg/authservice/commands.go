@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package authservice
+
+import (
+	"fmt"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/authservice/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+// setupTaskNames are completed after the setup command
+var setupTaskNames = []string{"all", "download-ca-cert", "download-cert-tls", "database", "admin", "jwt",
+	"update-service-config"}
+
+// commands lists the commands of the authservice CLI
+func (a *App) commands() *commCmd.Cmd {
+	return &commCmd.Cmd{
+		Name:  constants.ServiceCommand,
+		Flags: []commCmd.CmdFlag{commCmd.OutputFlag(constants.ServiceName)},
+		SubCmd: []commCmd.Cmd{
+			{Name: "help", Aliases: []string{"-h", "--help"}, Exec: func(ctx *commCmd.Context) error {
+				a.printUsage()
+				return nil
+			}},
+			{Name: "version", Aliases: []string{"-v", "--version"}, Exec: a.printVersion},
+			{Name: "run", Exec: func(ctx *commCmd.Context) error {
+				return a.startServer()
+			}},
+			{Name: "start", Exec: func(ctx *commCmd.Context) error {
+				return a.start()
+			}},
+			{Name: "stop", Exec: func(ctx *commCmd.Context) error {
+				return a.stop()
+			}},
+			{Name: "status", Exec: a.status},
+			{Name: "uninstall", Flags: []commCmd.CmdFlag{{Name: "purge", Bool: true}}, Exec: func(ctx *commCmd.Context) error {
+				return a.uninstall(ctx.Bool("purge"))
+			}},
+			{Name: "setup", PassThrough: true, Completions: setupTaskNames,
+				Flags: []commCmd.CmdFlag{{Name: "help"}, {Name: "force"}, {Name: "file", Short: "f"}, {Name: "interactive"},
+					{Name: "fix-drift"}},
+				Exec: func(ctx *commCmd.Context) error {
+					if err := a.setup(ctx.Args); err != nil {
+						if errors.Cause(err) == setup.ErrTaskNotFound {
+							a.printUsageWithError(err)
+						} else {
+							fmt.Fprintln(a.errorWriter(), err.Error())
+						}
+						return err
+					}
+					return nil
+				}},
+			commCmd.CompletionCmd,
+		},
+	}
+}
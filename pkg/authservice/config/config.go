@@ -26,6 +26,10 @@ type Configuration struct {
 	JWT              JWT                      `yaml:"jwt" mapstructure:"jwt"`
 	TLS              commConfig.TLSCertConfig `yaml:"tls" mapstructure:"tls"`
 	Server           commConfig.ServerConfig  `yaml:"server" mapstructure:"server"`
+
+	// FipsMode restricts the service to the FIPS approved algorithms and TLS cipher suites, it is always enabled
+	// in the binaries built with the fips tag
+	FipsMode bool `yaml:"fips-mode" mapstructure:"fips-mode"`
 }
 
 type AASConfig struct {
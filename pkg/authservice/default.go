@@ -31,6 +31,8 @@ func init() {
 	viper.SetDefault("server-read-header-timeout", constants.DefaultReadHeaderTimeout)
 	viper.SetDefault("server-write-timeout", constants.DefaultWriteTimeout)
 	viper.SetDefault("server-idle-timeout", constants.DefaultIdleTimeout)
+	viper.SetDefault("server-drain-timeout", constants.DefaultDrainTimeout)
+	viper.SetDefault("server-body-read-timeout", constants.DefaultBodyReadTimeout)
 	viper.SetDefault("server-max-header-bytes", constants.DefaultMaxHeaderBytes)
 
 	// set default for database config
@@ -81,6 +83,7 @@ func defaultConfig() *config.Configuration {
 			IntervalMins:        viper.GetInt("auth-defender-interval-mins"),
 			LockoutDurationMins: viper.GetInt("auth-defender-lockout-duration-mins"),
 		},
+		FipsMode: viper.GetBool("fips-mode"),
 	}
 }
 
@@ -102,6 +105,9 @@ func loadAlias() {
 		"server-read-header-timeout": "AAS_SERVER_READ_HEADER_TIMEOUT",
 		"server-write-timeout":       "AAS_SERVER_WRITE_TIMEOUT",
 		"server-idle-timeout":        "AAS_SERVER_IDLE_TIMEOUT",
+		"server-drain-timeout":       "AAS_SERVER_DRAIN_TIMEOUT",
+		"server-body-read-timeout":   "AAS_SERVER_BODY_READ_TIMEOUT",
+		"server-max-response-bytes":  "AAS_SERVER_MAX_RESPONSE_BYTES",
 		"server-max-header-bytes":    "AAS_SERVER_MAX_HEADER_BYTES",
 		"aas-service-username":       "AAS_ADMIN_USERNAME",
 		"aas-service-password":       "AAS_ADMIN_PASSWORD",
@@ -6,7 +6,10 @@ package authservice
 
 import (
 	"fmt"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/authservice/version"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 )
 
 const helpStr = `Usage:
@@ -20,12 +23,23 @@ Available Commands:
 	stop                             Stop authservice
 	uninstall [--purge]              Uninstall authservice. --purge option needs to be applied to remove configuration and data files
 	-v|--version | version           Show the version of authservice
+	completion <bash|zsh>            Print the shell completion script, load it with: source <(authservice completion bash)
+
+Global Flags:
+	--output <text|json>             output format of the version and status commands (env: AAS_OUTPUT)
 
 Usage of authservice setup:
-	authservice setup [task] [--help] [--force] [-f <answer-file>]
+	authservice setup [task] [--help] [--force] [-f <answer-file>] [--interactive] [--fix-drift]
+	authservice setup --interactive
+	authservice setup --fix-drift
 		--help                      show help message for setup task
 		--force                     existing configuration will be overwritten if this flag is set
 		-f|--file <answer-file>     the answer file with required arguments
+		--interactive               prompt for the required arguments not in the answer file or environment,
+		                            all setup tasks are run if no task is given
+		--fix-drift                 run again the tasks whose configuration has drifted, like expired or
+		                            reissued certificates or missing database tables, all tasks are checked
+		                            if no task is given
 
 	Available Tasks for setup:
 		all                      Runs all setup tasks
@@ -47,6 +61,11 @@ func (a *App) printUsageWithError(err error) {
 	fmt.Fprintln(a.errorWriter(), helpStr)
 }
 
-func (a *App) printVersion() {
-	fmt.Fprintf(a.consoleWriter(), version.GetVersion())
+func (a *App) printVersion(ctx *commCmd.Context) error {
+	return ctx.Print(version.GetVersion(), commCmd.VersionInfo{
+		ServiceName: constants.ExplicitServiceName,
+		Version:     version.Version,
+		GitHash:     version.GitHash,
+		BuildDate:   version.BuildDate,
+	})
 }
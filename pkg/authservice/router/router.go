@@ -35,6 +35,10 @@ func InitRoutes(cfg *config.Configuration, dataStore *postgres.PostgresDatabase,
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
+
+	// Reject oversized, compressed and slowly sent request bodies before they reach any handler and bound the
+	// size of the responses
+	router.Use(cmw.NewRequestLimits(cfg.Server))
 	defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, tokenFactory)
 	return router
 }
@@ -7,7 +7,6 @@ package authservice
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/gorilla/handlers"
 	"github.com/intel-secl/intel-secl/v3/pkg/authservice/constants"
@@ -17,6 +16,7 @@ import (
 	jwtauth "github.com/intel-secl/intel-secl/v3/pkg/lib/common/jwt"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	"github.com/pkg/errors"
 	"io/ioutil"
 	stdlog "log"
@@ -66,6 +66,8 @@ func (a *App) startServer() error {
 	if err := a.configureLogs(c.Log.EnableStdout, true); err != nil {
 		return err
 	}
+	crypt.SetFipsMode(c.FipsMode)
+	defaultLog.Infof("FIPS mode enabled: %t", crypt.FipsModeEnabled())
 
 	defaultLog.Info("Starting server")
 
@@ -87,13 +89,7 @@ func (a *App) startServer() error {
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	routes.SkipClean(true)
 
-	tlsconfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
-	}
+	tlsconfig := crypt.TLSConfig()
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -108,6 +104,7 @@ func (a *App) startServer() error {
 		WriteTimeout:      c.Server.WriteTimeout,
 		IdleTimeout:       c.Server.IdleTimeout,
 		MaxHeaderBytes:    c.Server.MaxHeaderBytes,
+		ConnContext:       cmw.ConnContext,
 	}
 
 	// dispatch web server go routine
@@ -123,7 +120,12 @@ func (a *App) startServer() error {
 	secLog.Info(commLogMsg.ServiceStart)
 	// TODO dispatch Service status checker goroutine
 	<-stop
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// the requests in progress are drained within the drain timeout
+	drainTimeout := c.Server.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = constants.DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 	if err := h.Shutdown(ctx); err != nil {
 		defaultLog.WithError(err).Info("Failed to gracefully shutdown webserver")
@@ -29,7 +29,7 @@ func (a *App) setup(args []string) error {
 	}
 	// look for cli flags
 	var ansFile string
-	var force bool
+	var force, interactive, fixDrift bool
 	for i, s := range args {
 		if s == "-f" || s == "--file" {
 			if i+1 < len(args) {
@@ -41,6 +41,17 @@ func (a *App) setup(args []string) error {
 		if s == "--force" {
 			force = true
 		}
+		if s == setup.InteractiveFlag {
+			interactive = true
+		}
+		if s == setup.FixDriftFlag {
+			fixDrift = true
+		}
+	}
+	cmd := args[1]
+	// "authservice setup --interactive" and "authservice setup --fix-drift" run all the setup tasks
+	if cmd == setup.InteractiveFlag || cmd == setup.FixDriftFlag {
+		cmd = "all"
 	}
 	// dump answer file to env
 	if ansFile != "" {
@@ -49,11 +60,17 @@ func (a *App) setup(args []string) error {
 			return errors.Wrap(err, "Failed to read answer file")
 		}
 	}
+	// prompt for the settings not in the answer file
+	if interactive && !(len(args) > 2 && args[2] == "--help") {
+		err := setup.NewConsoleInteractive(a.consoleWriter()).ReadAnswersToEnv(cmd, setupPrompts())
+		if err != nil {
+			return errors.Wrap(err, "Failed to read setup settings")
+		}
+	}
 	runner, err := a.setupTaskRunner()
 	if err != nil {
 		return err
 	}
-	cmd := args[1]
 	// print help and return if applicable
 	if len(args) > 2 && args[2] == "--help" {
 		if cmd == "all" {
@@ -69,7 +86,15 @@ func (a *App) setup(args []string) error {
 		}
 		return nil
 	}
-	if cmd == "all" {
+	if fixDrift {
+		if err = runner.FixDrift(cmd); err != nil {
+			fmt.Fprintln(a.errorWriter(), "Error(s) encountered when fixing setup drift:")
+			for errCmd, failErr := range runner.FailedCommands() {
+				fmt.Fprintln(a.errorWriter(), errCmd+": "+failErr.Error())
+			}
+			return err
+		}
+	} else if cmd == "all" {
 		if err = runner.RunAll(force); err != nil {
 			errCmds := runner.FailedCommands()
 			fmt.Fprintln(a.errorWriter(), "Error(s) encountered when running all setup commands:")
@@ -207,6 +232,9 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 			ReadHeaderTimeout: viper.GetDuration("server-read-header-timeout"),
 			WriteTimeout:      viper.GetDuration("server-write-timeout"),
 			IdleTimeout:       viper.GetDuration("server-idle-timeout"),
+			DrainTimeout:      viper.GetDuration("server-drain-timeout"),
+			BodyReadTimeout:   viper.GetDuration("server-body-read-timeout"),
+			MaxResponseBytes:  viper.GetInt64("server-max-response-bytes"),
 			MaxHeaderBytes:    viper.GetInt("server-max-header-bytes"),
 		},
 		DefaultPort: constants.DefaultPort,
@@ -125,6 +125,9 @@ func (c *JwtClient) fetchToken(userCred *types.UserCred) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
 	if rsp.StatusCode != http.StatusOK {
 		ErrHTTPFetchJWTToken.RetCode = rsp.StatusCode
 		return nil, ErrHTTPFetchJWTToken
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package aas
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	types "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// tokenRefreshMinFraction and tokenRefreshJitterFraction set the refresh of a token between 10% and 20% of
+	// its lifetime before it expires, the jitter keeps the services sharing AAS from refreshing together
+	tokenRefreshMinFraction    = 0.1
+	tokenRefreshJitterFraction = 0.1
+)
+
+// TokenProvider fetches the bearer token of a service user from AAS and caches it for the service to service
+// calls. The token is refreshed ahead of its expiry, the callers keep using the cached token while it is being
+// refreshed and the concurrent callers waiting for a token share a single fetch.
+type TokenProvider struct {
+	aasURL string
+
+	mtx        sync.Mutex
+	username   string
+	password   string
+	httpClient *http.Client
+	token      []byte
+	refreshAt  time.Time
+	expiresAt  time.Time
+	fetch      *tokenFetch
+}
+
+// tokenFetch is a fetch of the token in progress, done is closed once token or err is set
+type tokenFetch struct {
+	done  chan struct{}
+	token []byte
+	err   error
+}
+
+var tokenProviders = sync.Map{}
+
+// NewTokenProvider returns a provider of the tokens of the user, the tokens are fetched with the http client
+func NewTokenProvider(aasURL, username, password string, httpClient *http.Client) *TokenProvider {
+	return &TokenProvider{
+		aasURL:     aasURL,
+		username:   username,
+		password:   password,
+		httpClient: httpClient,
+	}
+}
+
+// SharedTokenProvider returns the provider of the tokens of the user shared by the clients of the process, so that
+// they all use the same cached token. The password and the http client replace the ones of the provider, a new
+// password drops the cached token.
+func SharedTokenProvider(aasURL, username, password string, httpClient *http.Client) *TokenProvider {
+	value, _ := tokenProviders.LoadOrStore(aasURL+"|"+username, NewTokenProvider(aasURL, username, password, httpClient))
+	provider := value.(*TokenProvider)
+
+	provider.mtx.Lock()
+	defer provider.mtx.Unlock()
+	if provider.password != password {
+		provider.password = password
+		provider.token = nil
+	}
+	if httpClient != nil {
+		provider.httpClient = httpClient
+	}
+	return provider
+}
+
+// Token returns the cached token of the user, it is fetched from AAS when there is none or when it is due for
+// refresh. A token that failed to refresh is still returned until it expires.
+func (p *TokenProvider) Token() ([]byte, error) {
+	p.mtx.Lock()
+	now := time.Now()
+	if p.token != nil && now.Before(p.refreshAt) {
+		defer p.mtx.Unlock()
+		return p.token, nil
+	}
+	if fetch := p.fetch; fetch != nil {
+		if p.token != nil && now.Before(p.expiresAt) {
+			defer p.mtx.Unlock()
+			return p.token, nil
+		}
+		p.mtx.Unlock()
+		<-fetch.done
+		return fetch.token, fetch.err
+	}
+
+	fetch := &tokenFetch{done: make(chan struct{})}
+	p.fetch = fetch
+	jwtClient := JwtClient{BaseURL: p.aasURL, HTTPClient: p.httpClient}
+	userCred := types.UserCred{UserName: p.username, Password: p.password}
+	p.mtx.Unlock()
+
+	token, err := jwtClient.fetchToken(&userCred)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.fetch = nil
+	if err != nil {
+		if p.token != nil && time.Now().Before(p.expiresAt) {
+			log.WithError(err).Warnf("clients/aas:Token() Failed to refresh the token of %s, using the cached token", p.username)
+			fetch.token = p.token
+		} else {
+			fetch.err = err
+		}
+	} else {
+		p.setToken(token)
+		fetch.token = token
+	}
+	close(fetch.done)
+	return fetch.token, fetch.err
+}
+
+// Invalidate drops the cached token when it is the token rejected by a service, the next call to Token fetches a
+// new one
+func (p *TokenProvider) Invalidate(token []byte) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.token != nil && bytes.Equal(p.token, token) {
+		p.token = nil
+	}
+}
+
+// Authenticate sets the token of the user as the bearer token of the request
+func (p *TokenProvider) Authenticate(req *http.Request) ([]byte, error) {
+	token, err := p.Token()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+string(token))
+	return token, nil
+}
+
+// setToken caches the token and schedules its refresh from its expiry, the tokens without an expiry are kept
+// until they are invalidated
+func (p *TokenProvider) setToken(token []byte) {
+	now := time.Now()
+	p.token = token
+	issuedAt, expiresAt, ok := tokenValidity(token)
+	if !ok || !expiresAt.After(now) {
+		p.refreshAt = now.Add(100 * 365 * 24 * time.Hour)
+		p.expiresAt = p.refreshAt
+		return
+	}
+	if issuedAt.IsZero() || issuedAt.After(now) {
+		issuedAt = now
+	}
+	lifetime := expiresAt.Sub(issuedAt)
+	margin := time.Duration(float64(lifetime) * (tokenRefreshMinFraction + tokenRefreshJitterFraction*rand.Float64()))
+	p.refreshAt = expiresAt.Add(-margin)
+	p.expiresAt = expiresAt
+}
+
+// tokenValidity returns the iat and exp claims of the token, the token is not verified
+func tokenValidity(token []byte) (time.Time, time.Time, bool) {
+	parts := strings.Split(strings.TrimSpace(string(token)), ".")
+	if len(parts) != 3 {
+		return time.Time{}, time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	var claims struct {
+		IssuedAt  json.Number `json:"iat"`
+		ExpiresAt json.Number `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	exp, err := claims.ExpiresAt.Float64()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	var issuedAt time.Time
+	if iat, err := claims.IssuedAt.Float64(); err == nil {
+		issuedAt = time.Unix(int64(iat), 0)
+	}
+	return issuedAt, time.Unix(int64(exp), 0), true
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package aas
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testToken(issuedAt time.Time, lifetime time.Duration, id int32) string {
+	payload := fmt.Sprintf(`{"iat":%d,"exp":%d,"jti":"%d"}`, issuedAt.Unix(), issuedAt.Add(lifetime).Unix(), id)
+	return "eyJhbGciOiJSUzM4NCJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+func TestTokenProvider(t *testing.T) {
+	var fetches int32
+	var failing int32
+	aas := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" || atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		id := atomic.AddInt32(&fetches, 1)
+		// slow down the fetch so that the concurrent callers wait for it
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(testToken(time.Now(), time.Hour, id)))
+	}))
+	defer aas.Close()
+
+	provider := NewTokenProvider(aas.URL+"/", "hvs-service", "password", aas.Client())
+
+	// the concurrent callers share a single fetch
+	var wg sync.WaitGroup
+	tokens := make([][]byte, 10)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := provider.Token()
+			assert.NoError(t, err)
+			tokens[i] = token
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	for _, token := range tokens {
+		assert.Equal(t, tokens[0], token)
+	}
+
+	// the token is cached and refreshed between 10% and 20% of its lifetime before it expires
+	token, err := provider.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, tokens[0], token)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	assert.True(t, provider.refreshAt.After(provider.expiresAt.Add(-12*time.Minute-time.Second)))
+	assert.True(t, provider.refreshAt.Before(provider.expiresAt.Add(-6*time.Minute+time.Second)))
+
+	// a rejected token is fetched again, the other tokens are not invalidated
+	provider.Invalidate([]byte("another token"))
+	token, err = provider.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, tokens[0], token)
+	provider.Invalidate(token)
+	token, err = provider.Token()
+	assert.NoError(t, err)
+	assert.NotEqual(t, tokens[0], token)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// a token due for refresh is still used while AAS is unavailable, until it expires
+	atomic.StoreInt32(&failing, 1)
+	provider.refreshAt = time.Now().Add(-time.Second)
+	cachedToken, err := provider.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, token, cachedToken)
+	provider.expiresAt = time.Now().Add(-time.Second)
+	_, err = provider.Token()
+	assert.Error(t, err)
+
+	// the provider refreshes the token once AAS is back
+	atomic.StoreInt32(&failing, 0)
+	req, err := http.NewRequest(http.MethodGet, "https://ta.server.com:1443/v2/host", nil)
+	assert.NoError(t, err)
+	token, err = provider.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer "+string(token), req.Header.Get("Authorization"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fetches))
+}
+
+func TestSharedTokenProvider(t *testing.T) {
+	provider := SharedTokenProvider("https://aas.server.com:8444/aas/v1/", "kbs-service", "password", nil)
+	provider.setToken([]byte(testToken(time.Now(), time.Hour, 1)))
+	assert.Equal(t, provider, SharedTokenProvider("https://aas.server.com:8444/aas/v1/", "kbs-service", "password", nil))
+	assert.NotNil(t, provider.token)
+
+	// a new password drops the cached token
+	assert.Equal(t, provider, SharedTokenProvider("https://aas.server.com:8444/aas/v1/", "kbs-service", "new-password", nil))
+	assert.Nil(t, provider.token)
+	assert.NotEqual(t, provider, SharedTokenProvider("https://aas.server.com:8444/aas/v1/", "wls-service", "password", nil))
+}
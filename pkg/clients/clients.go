@@ -5,11 +5,12 @@
 package clients
 
 import (
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 )
 
 type HTTPClientErr struct {
@@ -28,21 +29,18 @@ func HTTPClient() *http.Client {
 
 func HTTPClientTLSNoVerify() *http.Client {
 	//InsecureSkipVerify is set to true as connection is established from utility script and k8s plugin
+	config := crypt.TLSConfig()
+	config.InsecureSkipVerify = true
 	return &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: true},
+			TLSClientConfig: config,
 		},
 	}
 }
 
 func HTTPClientWithCA(caCertificates []x509.Certificate) (*http.Client, error) {
-	config := &tls.Config{
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: false,
-		RootCAs:            GetCertPool(caCertificates),
-	}
+	config := crypt.TLSConfig()
+	config.RootCAs = GetCertPool(caCertificates)
 	tr := &http.Transport{TLSClientConfig: config}
 	return &http.Client{Transport: tr}, nil
 }
@@ -6,9 +6,9 @@ package cms
 
 import (
 	"bytes"
-	"crypto/tls"
 	"errors"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	"net/http"
 )
 
@@ -25,13 +25,11 @@ var (
 
 func (c *Client) httpClient() *http.Client {
 	if c.HTTPClient == nil {
-		tlsConfig := tls.Config{
-			MinVersion:         tls.VersionTLS12,
-			InsecureSkipVerify: true,
-		}
+		tlsConfig := crypt.TLSConfig()
+		tlsConfig.InsecureSkipVerify = true
 		// Skipping verification as it is done manually using digest of the TLS certificate as this is step of setting up service
 		transport := http.Transport{
-			TLSClientConfig: &tlsConfig,
+			TLSClientConfig: tlsConfig,
 		}
 		c.HTTPClient = &http.Client{Transport: &transport}
 	}
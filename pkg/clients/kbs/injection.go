@@ -0,0 +1,274 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/k8s"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// tmpfsMagic is the filesystem type reported by statfs for tmpfs mounts
+const tmpfsMagic = 0x01021994
+
+// SecretInjector delivers a released secret to an injection target on the attested node
+type SecretInjector interface {
+	Inject(target kbs.InjectionTarget, secret *ReleasedSecret) error
+}
+
+// ReleasedSecret is the unwrapped secret of a key transfer
+type ReleasedSecret struct {
+	KeyId  string
+	Secret []byte
+}
+
+// SecretBase64 returns the secret base64 encoded, for the targets that cannot hold binary data
+func (rs *ReleasedSecret) SecretBase64() string {
+	return base64.StdEncoding.EncodeToString(rs.Secret)
+}
+
+// SecretInjectors holds the injectors of the agent keyed by the injection target type they support
+type SecretInjectors map[string]SecretInjector
+
+// NewSecretInjectors returns the injectors delivering secrets as files below the secrets directory and, when
+// a kubernetes client is provided, as kubernetes Secrets
+func NewSecretInjectors(secretsDir string, requireTmpfs bool, k8sClient *k8s.Client) SecretInjectors {
+	injectors := SecretInjectors{
+		kbs.InjectionTargetFile:        &FileInjector{Dir: secretsDir, RequireTmpfs: requireTmpfs},
+		kbs.InjectionTargetEnvTemplate: &EnvTemplateInjector{FileInjector{Dir: secretsDir, RequireTmpfs: requireTmpfs}},
+	}
+	if k8sClient != nil {
+		injectors[kbs.InjectionTargetKubernetesSecret] = &KubernetesSecretInjector{Client: k8sClient, BaseURL: k8sClient.BaseURL}
+	}
+	return injectors
+}
+
+// UnwrapAndInject unwraps the transferred key with the private key its transfer was requested with and delivers
+// it to each of its injection targets
+func (si SecretInjectors) UnwrapAndInject(transfer *kbs.KeyTransferAttributes, privateKey *rsa.PrivateKey) error {
+	log.Trace("kbs/injection:UnwrapAndInject() Entering")
+	defer log.Trace("kbs/injection:UnwrapAndInject() Leaving")
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(transfer.KeyData)
+	if err != nil {
+		return errors.Wrap(err, "Error decoding transferred key")
+	}
+
+	secret, err := rsa.DecryptOAEP(sha512.New384(), nil, privateKey, wrappedKey, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error unwrapping transferred key")
+	}
+
+	return si.Inject(transfer.InjectionTargets, &ReleasedSecret{KeyId: transfer.KeyId.String(), Secret: secret})
+}
+
+// Inject delivers the secret to each of the injection targets, it stops at the first target that fails
+func (si SecretInjectors) Inject(targets []kbs.InjectionTarget, secret *ReleasedSecret) error {
+	log.Trace("kbs/injection:Inject() Entering")
+	defer log.Trace("kbs/injection:Inject() Leaving")
+
+	for _, target := range targets {
+		injector, ok := si[target.Type]
+		if !ok {
+			return errors.Errorf("Injection target type %s is not supported by the agent", target.Type)
+		}
+		if err := injector.Inject(target, secret); err != nil {
+			return errors.Wrapf(err, "Error injecting key %s into %s target", secret.KeyId, target.Type)
+		}
+	}
+	return nil
+}
+
+// FileInjector writes the secret to a file readable by the owner only below Dir, which should be a tmpfs mount
+// so that the secret never reaches the disk
+type FileInjector struct {
+	Dir          string
+	RequireTmpfs bool
+}
+
+func (fi *FileInjector) Inject(target kbs.InjectionTarget, secret *ReleasedSecret) error {
+	log.Trace("kbs/injection:FileInjector.Inject() Entering")
+	defer log.Trace("kbs/injection:FileInjector.Inject() Leaving")
+
+	return fi.writeFile(target.Path, secret.Secret)
+}
+
+// writeFile replaces the file atomically so that workloads never read a partially written secret
+func (fi *FileInjector) writeFile(path string, content []byte) error {
+	if path == "" || filepath.IsAbs(path) || filepath.Clean(path) != path || path == ".." || strings.HasPrefix(path, "../") {
+		return errors.Errorf("Injection path %s must be relative to the secrets directory", path)
+	}
+
+	if fi.RequireTmpfs {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(fi.Dir, &stat); err != nil {
+			return errors.Wrap(err, "Error reading the filesystem of the secrets directory")
+		}
+		if stat.Type != tmpfsMagic {
+			return errors.Errorf("Secrets directory %s is not a tmpfs mount", fi.Dir)
+		}
+	}
+
+	filePath := filepath.Join(fi.Dir, path)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return errors.Wrap(err, "Error creating the directory of the injected secret")
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath))
+	if err != nil {
+		return errors.Wrap(err, "Error creating the injected secret file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "Error writing the injected secret file")
+	}
+
+	if err = os.Rename(tmpFile.Name(), filePath); err != nil {
+		return errors.Wrap(err, "Error replacing the injected secret file")
+	}
+	return nil
+}
+
+// EnvTemplateInjector renders the template of the target with the secret into a file, e.g. an environment file
+// sourced by the workload
+type EnvTemplateInjector struct {
+	FileInjector
+}
+
+func (ei *EnvTemplateInjector) Inject(target kbs.InjectionTarget, secret *ReleasedSecret) error {
+	log.Trace("kbs/injection:EnvTemplateInjector.Inject() Entering")
+	defer log.Trace("kbs/injection:EnvTemplateInjector.Inject() Leaving")
+
+	tmpl, err := template.New(target.Path).Option("missingkey=error").Parse(target.Template)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing the template of the injection target")
+	}
+
+	var content bytes.Buffer
+	err = tmpl.Execute(&content, struct {
+		KeyId        string
+		Secret       string
+		SecretBase64 string
+	}{secret.KeyId, string(secret.Secret), secret.SecretBase64()})
+	if err != nil {
+		return errors.Wrap(err, "Error rendering the template of the injection target")
+	}
+
+	return ei.writeFile(target.Path, content.Bytes())
+}
+
+// KubernetesClient sends requests to the kubernetes API server, it is implemented by k8s.Client
+type KubernetesClient interface {
+	SendRequest(reqParams *k8s.RequestParams) (*http.Response, error)
+}
+
+// KubernetesSecretInjector creates or replaces an Opaque kubernetes Secret holding the secret
+type KubernetesSecretInjector struct {
+	Client  KubernetesClient
+	BaseURL *url.URL
+}
+
+type kubernetesSecret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   map[string]string `json:"metadata"`
+	Type       string            `json:"type"`
+	Data       map[string][]byte `json:"data"`
+}
+
+func (ki *KubernetesSecretInjector) Inject(target kbs.InjectionTarget, secret *ReleasedSecret) error {
+	log.Trace("kbs/injection:KubernetesSecretInjector.Inject() Entering")
+	defer log.Trace("kbs/injection:KubernetesSecretInjector.Inject() Leaving")
+
+	key := target.Key
+	if key == "" {
+		key = secret.KeyId
+	}
+
+	payload, err := json.Marshal(kubernetesSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   map[string]string{"name": target.Name, "namespace": target.Namespace},
+		Type:       "Opaque",
+		Data:       map[string][]byte{key: secret.Secret},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error marshalling kubernetes secret")
+	}
+
+	secretsURL, err := ki.secretsURL(target.Namespace, "")
+	if err != nil {
+		return err
+	}
+	secretURL, err := ki.secretsURL(target.Namespace, target.Name)
+	if err != nil {
+		return err
+	}
+
+	// replace the secret and create it when it does not exist yet
+	res, err := ki.Client.SendRequest(&k8s.RequestParams{
+		Method:            "PUT",
+		URL:               secretURL,
+		Body:              bytes.NewReader(payload),
+		AdditionalHeaders: map[string]string{"Content-Type": "application/json"},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error replacing kubernetes secret")
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		return nil
+	}
+
+	res, err = ki.Client.SendRequest(&k8s.RequestParams{
+		Method:            "POST",
+		URL:               secretsURL,
+		Body:              bytes.NewReader(payload),
+		AdditionalHeaders: map[string]string{"Content-Type": "application/json"},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error creating kubernetes secret")
+	}
+	res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return errors.Errorf("Kubernetes namespace %s does not exist", target.Namespace)
+	}
+	return nil
+}
+
+func (ki *KubernetesSecretInjector) secretsURL(namespace, name string) (*url.URL, error) {
+	path := fmt.Sprintf("api/v1/namespaces/%s/secrets", url.PathEscape(namespace))
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	secretsURL, err := url.Parse(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing kubernetes secrets URL")
+	}
+	if ki.BaseURL == nil {
+		return nil, errors.New("Kubernetes API server URL is not set")
+	}
+	return ki.BaseURL.ResolveReference(secretsURL), nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/k8s"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKubernetesClient struct {
+	secrets map[string]kubernetesSecret
+}
+
+func (f *fakeKubernetesClient) SendRequest(reqParams *k8s.RequestParams) (*http.Response, error) {
+	var secret kubernetesSecret
+	if err := json.NewDecoder(reqParams.Body).Decode(&secret); err != nil {
+		return nil, err
+	}
+	w := httptest.NewRecorder()
+	name := reqParams.URL.Path
+	if reqParams.Method == "POST" {
+		name += "/" + secret.Metadata["name"]
+		w.WriteHeader(http.StatusCreated)
+	} else if _, ok := f.secrets[name]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return w.Result(), nil
+	}
+	f.secrets[name] = secret
+	return w.Result(), nil
+}
+
+func TestSecretInjectors(t *testing.T) {
+	secretsDir, err := ioutil.TempDir("", "secrets")
+	assert.NoError(t, err)
+	defer os.RemoveAll(secretsDir)
+
+	k8sClient := &fakeKubernetesClient{secrets: map[string]kubernetesSecret{}}
+	baseURL, _ := url.Parse("https://kubernetes:6443/")
+	injectors := NewSecretInjectors(secretsDir, false, nil)
+	injectors[kbs.InjectionTargetKubernetesSecret] = &KubernetesSecretInjector{Client: k8sClient, BaseURL: baseURL}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	wrappedKey, err := rsa.EncryptOAEP(sha512.New384(), rand.Reader, &privateKey.PublicKey, []byte("s3cret"), nil)
+	assert.NoError(t, err)
+
+	keyId := uuid.New()
+	transfer := &kbs.KeyTransferAttributes{
+		KeyId:   keyId,
+		KeyData: base64.StdEncoding.EncodeToString(wrappedKey),
+		InjectionTargets: []kbs.InjectionTarget{
+			{Type: kbs.InjectionTargetFile, Path: "app/key"},
+			{Type: kbs.InjectionTargetEnvTemplate, Path: "app/key.env", Template: "APP_KEY={{.SecretBase64}}\n"},
+			{Type: kbs.InjectionTargetKubernetesSecret, Name: "app-key", Namespace: "default"},
+		},
+	}
+	assert.NoError(t, injectors.UnwrapAndInject(transfer, privateKey))
+
+	content, err := ioutil.ReadFile(filepath.Join(secretsDir, "app/key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", string(content))
+	info, err := os.Stat(filepath.Join(secretsDir, "app/key"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	content, err = ioutil.ReadFile(filepath.Join(secretsDir, "app/key.env"))
+	assert.NoError(t, err)
+	assert.Equal(t, "APP_KEY=czNjcmV0\n", string(content))
+
+	// the secret is created first and then replaced
+	secret := k8sClient.secrets["/api/v1/namespaces/default/secrets/app-key"]
+	assert.Equal(t, []byte("s3cret"), secret.Data[keyId.String()])
+	assert.NoError(t, injectors.Inject(transfer.InjectionTargets[2:], &ReleasedSecret{KeyId: keyId.String(), Secret: []byte("rotated")}))
+	secret = k8sClient.secrets["/api/v1/namespaces/default/secrets/app-key"]
+	assert.Equal(t, []byte("rotated"), secret.Data[keyId.String()])
+
+	// files are only written below the secrets directory
+	err = injectors.Inject([]kbs.InjectionTarget{{Type: kbs.InjectionTargetFile, Path: "../escaped"}}, &ReleasedSecret{Secret: []byte("s3cret")})
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(filepath.Dir(secretsDir), "escaped"))
+	assert.True(t, os.IsNotExist(err))
+
+	// the kubernetes secret injector is only available with a kubernetes client
+	err = NewSecretInjectors(secretsDir, false, nil).Inject(transfer.InjectionTargets[2:], &ReleasedSecret{Secret: []byte("s3cret")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package clients
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// DirectProxy is the host proxy of the servers reached without a proxy
+const DirectProxy = "direct"
+
+// ProxyFunc returns the proxy a request is sent through, nil when it is sent directly, as http.Transport.Proxy
+type ProxyFunc func(req *http.Request) (*url.URL, error)
+
+// hostProxy is the proxy of the servers matching a host name, a domain suffix or a network, nil for the direct
+// connections
+type hostProxy struct {
+	suffix  string
+	network *net.IPNet
+	proxy   *url.URL
+}
+
+// NewProxyFunc returns the function selecting the proxy of the requests with the configuration, nil is returned when
+// no proxy is configured. The most specific host proxy matching the server of a request is used, the exact host name
+// first, then the longest domain suffix and the narrowest network.
+func NewProxyFunc(cfg commConfig.ProxyConfig) (ProxyFunc, error) {
+	if cfg.HTTPProxy == "" && cfg.HTTPSProxy == "" && len(cfg.HostProxies) == 0 {
+		return nil, nil
+	}
+	for _, proxy := range []string{cfg.HTTPProxy, cfg.HTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+		if _, err := parseProxyURL(proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	hosts := make(map[string]*url.URL)
+	var overrides []hostProxy
+	for pattern, proxy := range cfg.HostProxies {
+		var proxyURL *url.URL
+		if !strings.EqualFold(proxy, DirectProxy) {
+			var err error
+			if proxyURL, err = parseProxyURL(proxy); err != nil {
+				return nil, errors.Wrapf(err, "Invalid proxy of %s", pattern)
+			}
+		}
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if _, network, err := net.ParseCIDR(pattern); err == nil {
+			overrides = append(overrides, hostProxy{network: network, proxy: proxyURL})
+		} else if strings.HasPrefix(pattern, ".") && len(pattern) > 1 {
+			overrides = append(overrides, hostProxy{suffix: pattern, proxy: proxyURL})
+		} else if pattern != "" {
+			hosts[pattern] = proxyURL
+		} else {
+			return nil, errors.New("Empty host of host proxy")
+		}
+	}
+
+	proxyForURL := (&httpproxy.Config{
+		HTTPProxy:  cfg.HTTPProxy,
+		HTTPSProxy: cfg.HTTPSProxy,
+		NoProxy:    cfg.NoProxy,
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		host := strings.ToLower(req.URL.Hostname())
+		if proxy, ok := hosts[host]; ok {
+			return proxy, nil
+		}
+		if override := matchHostProxy(overrides, host); override != nil {
+			return override.proxy, nil
+		}
+		return proxyForURL(req.URL)
+	}, nil
+}
+
+// matchHostProxy returns the most specific host proxy matching the host, nil when there is none
+func matchHostProxy(overrides []hostProxy, host string) *hostProxy {
+	ip := net.ParseIP(host)
+	var match *hostProxy
+	matchSize := -1
+	for i, override := range overrides {
+		size := -1
+		if override.network != nil && ip != nil && override.network.Contains(ip) {
+			size, _ = override.network.Mask.Size()
+		} else if override.suffix != "" && (strings.HasSuffix(host, override.suffix) || host == override.suffix[1:]) {
+			// the domain suffixes are more specific than the networks
+			size = 1000 + len(override.suffix)
+		}
+		if size > matchSize {
+			match, matchSize = &overrides[i], size
+		}
+	}
+	return match
+}
+
+// parseProxyURL parses a proxy URL, the http, https and socks5 proxies are supported
+func parseProxyURL(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid proxy URL %s", proxy)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("Unsupported scheme of proxy URL %s, http, https or socks5 is expected", proxy)
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.Errorf("Invalid proxy URL %s, the host is missing", proxy)
+	}
+	return proxyURL, nil
+}
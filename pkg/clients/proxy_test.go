@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package clients
+
+import (
+	"net/http"
+	"testing"
+
+	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProxyFunc(t *testing.T) {
+	proxyOf := func(proxy ProxyFunc, rawURL string) string {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		assert.NoError(t, err)
+		proxyURL, err := proxy(req)
+		assert.NoError(t, err)
+		if proxyURL == nil {
+			return DirectProxy
+		}
+		return proxyURL.String()
+	}
+
+	// nothing is proxied without a configuration
+	proxy, err := NewProxyFunc(commConfig.ProxyConfig{NoProxy: "ta.server.com"})
+	assert.NoError(t, err)
+	assert.Nil(t, proxy)
+
+	proxy, err = NewProxyFunc(commConfig.ProxyConfig{
+		HTTPProxy:  "http://proxy.mgmt.com:3128",
+		HTTPSProxy: "socks5://socks.mgmt.com:1080",
+		NoProxy:    ".lab.com,10.1.0.0/16",
+		HostProxies: map[string]string{
+			"vcenter.dc1.com": "http://vcenter-proxy.mgmt.com:3128",
+			".dc1.com":        "https://dc1-proxy.mgmt.com:3129",
+			".agents.dc1.com": DirectProxy,
+			"192.168.0.0/16":  "socks5://dc2-proxy.mgmt.com:1080",
+			"192.168.10.0/24": "http://rack10-proxy.mgmt.com:3128",
+			"ta.lab.com":      "http://lab-proxy.mgmt.com:3128",
+		},
+	})
+	assert.NoError(t, err)
+
+	// the requests are sent through the proxy of their scheme unless the server is in the no proxy list
+	assert.Equal(t, "http://proxy.mgmt.com:3128", proxyOf(proxy, "http://ta.server.com:1443/v2/host"))
+	assert.Equal(t, "socks5://socks.mgmt.com:1080", proxyOf(proxy, "https://ta.server.com:1443/v2/host"))
+	assert.Equal(t, DirectProxy, proxyOf(proxy, "https://ta1.lab.com:1443/v2/host"))
+	assert.Equal(t, DirectProxy, proxyOf(proxy, "https://10.1.2.3:1443/v2/host"))
+
+	// the most specific host proxy is used, the exact host name, the longest domain suffix then the narrowest network
+	assert.Equal(t, "http://vcenter-proxy.mgmt.com:3128", proxyOf(proxy, "https://vcenter.dc1.com/sdk"))
+	assert.Equal(t, "https://dc1-proxy.mgmt.com:3129", proxyOf(proxy, "https://VCENTER2.dc1.com/sdk"))
+	assert.Equal(t, "https://dc1-proxy.mgmt.com:3129", proxyOf(proxy, "https://dc1.com/sdk"))
+	assert.Equal(t, DirectProxy, proxyOf(proxy, "https://ta1.agents.dc1.com:1443/v2/host"))
+	assert.Equal(t, "socks5://dc2-proxy.mgmt.com:1080", proxyOf(proxy, "https://192.168.1.5:1443/v2/host"))
+	assert.Equal(t, "http://rack10-proxy.mgmt.com:3128", proxyOf(proxy, "https://192.168.10.5:1443/v2/host"))
+
+	// the host proxies take precedence over the no proxy list
+	assert.Equal(t, "http://lab-proxy.mgmt.com:3128", proxyOf(proxy, "https://ta.lab.com:1443/v2/host"))
+
+	// the proxies of unsupported schemes or without a host are rejected
+	_, err = NewProxyFunc(commConfig.ProxyConfig{HTTPSProxy: "ftp://proxy.mgmt.com:21"})
+	assert.Error(t, err)
+	_, err = NewProxyFunc(commConfig.ProxyConfig{HTTPProxy: "socks5://"})
+	assert.Error(t, err)
+	_, err = NewProxyFunc(commConfig.ProxyConfig{HostProxies: map[string]string{".dc1.com": "socks4://proxy.mgmt.com"}})
+	assert.Error(t, err)
+}
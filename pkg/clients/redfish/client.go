@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package redfish
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/clients"
+	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+)
+
+var log = commLog.GetDefaultLogger()
+
+const (
+	// InventorySource is the source of the platform inventories read by the Redfish clients
+	InventorySource = "redfish"
+
+	systemsPath           = "/redfish/v1/Systems"
+	firmwareInventoryPath = "/redfish/v1/UpdateService/FirmwareInventory"
+	stateEnabled          = "Enabled"
+)
+
+// RedfishClient reads the platform inventory of a host from the Redfish service of its BMC, the requests are canceled
+// when their context is done
+type RedfishClient interface {
+	GetPlatformInventory(ctx context.Context) (*taModel.PlatformInventory, error)
+}
+
+// NewRedfishClient creates a client of the Redfish service at bmcURL, the requests are sent with the basic
+// authentication of the BMC user
+func NewRedfishClient(bmcURL *url.URL, username, password string, trustedCaCerts []x509.Certificate) (RedfishClient, error) {
+	if bmcURL == nil || bmcURL.Scheme != "https" {
+		return nil, errors.New("redfish/client:NewRedfishClient() The BMC URL must be an https URL")
+	}
+	httpClient, err := clients.HTTPClientWithCA(trustedCaCerts)
+	if err != nil {
+		return nil, errors.Wrap(err, "redfish/client:NewRedfishClient() Error creating http client")
+	}
+	return &redfishClient{
+		BaseURL:    &url.URL{Scheme: bmcURL.Scheme, Host: bmcURL.Host},
+		Username:   username,
+		Password:   password,
+		httpClient: httpClient,
+	}, nil
+}
+
+type redfishClient struct {
+	BaseURL    *url.URL
+	Username   string
+	Password   string
+	httpClient *http.Client
+}
+
+// odataLink references another Redfish resource
+type odataLink struct {
+	ID string `json:"@odata.id"`
+}
+
+type resourceStatus struct {
+	State string `json:"State"`
+}
+
+type collection struct {
+	Members []odataLink `json:"Members"`
+}
+
+type computerSystem struct {
+	TrustedModules []struct {
+		InterfaceType string         `json:"InterfaceType"`
+		Status        resourceStatus `json:"Status"`
+	} `json:"TrustedModules"`
+	SecureBoot *odataLink `json:"SecureBoot"`
+}
+
+type secureBoot struct {
+	SecureBootEnable      *bool  `json:"SecureBootEnable"`
+	SecureBootCurrentBoot string `json:"SecureBootCurrentBoot"`
+	SecureBootMode        string `json:"SecureBootMode"`
+}
+
+type softwareInventory struct {
+	Name    string         `json:"Name"`
+	Version string         `json:"Version"`
+	Status  resourceStatus `json:"Status"`
+}
+
+// GetPlatformInventory reads the TPM presence and the secure boot state of the first computer system of the BMC and
+// the firmware inventory of its update service. The firmware that is not enabled, such as the previous or the
+// staged firmware images some BMCs list, is not part of the inventory.
+func (rc *redfishClient) GetPlatformInventory(ctx context.Context) (*taModel.PlatformInventory, error) {
+	log.Trace("clients/redfish_client:GetPlatformInventory() Entering")
+	defer log.Trace("clients/redfish_client:GetPlatformInventory() Leaving")
+
+	inventory := taModel.PlatformInventory{Source: InventorySource}
+
+	var systems collection
+	if err := rc.get(ctx, systemsPath, &systems); err != nil {
+		return nil, errors.Wrap(err, "clients/redfish_client:GetPlatformInventory() Error getting the computer systems")
+	}
+	if len(systems.Members) == 0 {
+		return nil, errors.New("clients/redfish_client:GetPlatformInventory() The BMC does not report any computer system")
+	}
+	var system computerSystem
+	if err := rc.get(ctx, systems.Members[0].ID, &system); err != nil {
+		return nil, errors.Wrap(err, "clients/redfish_client:GetPlatformInventory() Error getting the computer system")
+	}
+	for _, trustedModule := range system.TrustedModules {
+		if strings.HasPrefix(trustedModule.InterfaceType, "TPM") && trustedModule.Status.State == stateEnabled {
+			inventory.TPMPresent = true
+			inventory.TPMInterfaceType = trustedModule.InterfaceType
+			break
+		}
+	}
+
+	if system.SecureBoot != nil && system.SecureBoot.ID != "" {
+		var secureBootState secureBoot
+		if err := rc.get(ctx, system.SecureBoot.ID, &secureBootState); err != nil {
+			return nil, errors.Wrap(err, "clients/redfish_client:GetPlatformInventory() Error getting the secure boot state")
+		}
+		inventory.SecureBoot = &taModel.SecureBootState{
+			Enabled: secureBootState.SecureBootCurrentBoot == stateEnabled ||
+				(secureBootState.SecureBootCurrentBoot == "" && secureBootState.SecureBootEnable != nil && *secureBootState.SecureBootEnable),
+			Mode: secureBootState.SecureBootMode,
+		}
+	}
+
+	var firmwareInventory collection
+	if err := rc.get(ctx, firmwareInventoryPath, &firmwareInventory); err != nil {
+		return nil, errors.Wrap(err, "clients/redfish_client:GetPlatformInventory() Error getting the firmware inventory")
+	}
+	for _, member := range firmwareInventory.Members {
+		var firmware softwareInventory
+		if err := rc.get(ctx, member.ID, &firmware); err != nil {
+			return nil, errors.Wrap(err, "clients/redfish_client:GetPlatformInventory() Error getting the firmware inventory")
+		}
+		if firmware.Status.State != "" && firmware.Status.State != stateEnabled {
+			continue
+		}
+		inventory.Firmware = append(inventory.Firmware, taModel.FirmwareComponent{
+			Name:    firmware.Name,
+			Version: firmware.Version,
+		})
+	}
+
+	log.Debugf("clients/redfish_client:GetPlatformInventory() Read %d firmware components from the BMC", len(inventory.Firmware))
+	return &inventory, nil
+}
+
+// get reads the Redfish resource at path, the resources referencing another BMC are not followed
+func (rc *redfishClient) get(ctx context.Context, path string, resource interface{}) error {
+	resourceURL, err := rc.BaseURL.Parse(path)
+	if err != nil || resourceURL.Host != rc.BaseURL.Host {
+		return errors.Errorf("Invalid Redfish resource path %s", path)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Accept", "application/json")
+	httpRequest.SetBasicAuth(rc.Username, rc.Password)
+
+	httpResponse, err := rc.httpClient.Do(httpRequest)
+	if err != nil {
+		return errors.Wrap(err, "Error sending the request to the BMC")
+	}
+	defer httpResponse.Body.Close()
+	body, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return errors.Wrap(err, "Error reading the response of the BMC")
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return &clients.HTTPClientErr{
+			ErrMessage: "Redfish request failed for " + path,
+			RetCode:    httpResponse.StatusCode,
+			RetMessage: httpResponse.Status,
+		}
+	}
+	if err := json.Unmarshal(body, resource); err != nil {
+		return errors.Wrap(err, "Error unmarshalling the Redfish resource "+path)
+	}
+	return nil
+}
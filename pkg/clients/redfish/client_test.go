@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package redfish
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+var redfishResources = map[string]string{
+	"/redfish/v1/Systems": `{"Members": [{"@odata.id": "/redfish/v1/Systems/System.Embedded.1"}]}`,
+	"/redfish/v1/Systems/System.Embedded.1": `{
+		"TrustedModules": [{"InterfaceType": "TPM2_0", "Status": {"State": "Enabled"}}],
+		"SecureBoot": {"@odata.id": "/redfish/v1/Systems/System.Embedded.1/SecureBoot"}
+	}`,
+	"/redfish/v1/Systems/System.Embedded.1/SecureBoot": `{"SecureBootEnable": true, "SecureBootCurrentBoot": "Enabled", "SecureBootMode": "DeployedMode"}`,
+	"/redfish/v1/UpdateService/FirmwareInventory": `{"Members": [
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/Installed-159-2.10.2"},
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/Previous-159-2.9.4"},
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/Installed-25227-5.00.00.00"}
+	]}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/Installed-159-2.10.2":       `{"Name": "BIOS", "Version": "2.10.2", "Status": {"State": "Enabled"}}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/Previous-159-2.9.4":         `{"Name": "BIOS", "Version": "2.9.4", "Status": {"State": "Disabled"}}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/Installed-25227-5.00.00.00": `{"Name": "Integrated Remote Access Controller", "Version": "5.00.00.00"}`,
+}
+
+func TestGetPlatformInventory(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "root" || password != "calvin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resource, ok := redfishResources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, resource)
+	}))
+	defer server.Close()
+
+	bmcURL, _ := url.Parse(server.URL)
+	client, err := NewRedfishClient(bmcURL, "root", "calvin", []x509.Certificate{*server.Certificate()})
+	assert.NoError(err)
+	inventory, err := client.GetPlatformInventory(context.Background())
+	assert.NoError(err)
+	assert.Equal(&taModel.PlatformInventory{
+		Source: InventorySource,
+		Firmware: []taModel.FirmwareComponent{
+			{Name: "BIOS", Version: "2.10.2"},
+			{Name: "Integrated Remote Access Controller", Version: "5.00.00.00"},
+		},
+		SecureBoot:       &taModel.SecureBootState{Enabled: true, Mode: "DeployedMode"},
+		TPMPresent:       true,
+		TPMInterfaceType: "TPM2_0",
+	}, inventory)
+
+	// the BMCs are authenticated with the trusted CA certificates
+	client, err = NewRedfishClient(bmcURL, "root", "calvin", nil)
+	assert.NoError(err)
+	_, err = client.GetPlatformInventory(context.Background())
+	assert.Error(err)
+
+	client, err = NewRedfishClient(bmcURL, "root", "wrong", []x509.Certificate{*server.Certificate()})
+	assert.NoError(err)
+	_, err = client.GetPlatformInventory(context.Background())
+	assert.Error(err)
+
+	_, err = NewRedfishClient(&url.URL{Scheme: "http", Host: "bmc.ip.com"}, "root", "calvin", nil)
+	assert.Error(err)
+}
@@ -0,0 +1,25 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package redfish
+
+import (
+	"context"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRedfishClient struct {
+	mock.Mock
+}
+
+func NewMockRedfishClient() *MockRedfishClient {
+	return &MockRedfishClient{}
+}
+
+func (rc *MockRedfishClient) GetPlatformInventory(ctx context.Context) (*taModel.PlatformInventory, error) {
+	args := rc.Called()
+	return args.Get(0).(*taModel.PlatformInventory), args.Error(1)
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ta
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/util"
+	"github.com/pkg/errors"
+)
+
+// AuthPlugin injects custom authentication into the requests sent to trust agents, for environments that front
+// the agents with their own auth layer such as API gateways
+type AuthPlugin interface {
+	// Authenticate is called before a request is sent to the trust agent, once the AAS bearer token and the
+	// custom headers are set
+	Authenticate(req *http.Request) error
+	// ClientCertificate returns the TLS client certificate presented to the trust agent at taApiUrl, nil when the
+	// agent does not require TLS client authentication
+	ClientCertificate(taApiUrl *url.URL) (*tls.Certificate, error)
+}
+
+// AuthPluginFactory creates an AuthPlugin from its settings in the configuration
+type AuthPluginFactory func(config map[string]string) (AuthPlugin, error)
+
+// RequestAuth is the custom authentication of the requests sent to trust agents
+type RequestAuth struct {
+	// Headers are added to every request sent to the trust agents
+	Headers map[string]string
+	Plugin  AuthPlugin
+}
+
+const (
+	BearerTokenFileAuthPlugin   = "bearer-token-file"
+	ClientCertificateAuthPlugin = "client-certificate"
+)
+
+var authPluginFactories = sync.Map{}
+
+func init() {
+	RegisterAuthPlugin(BearerTokenFileAuthPlugin, newBearerTokenFilePlugin)
+	RegisterAuthPlugin(ClientCertificateAuthPlugin, newClientCertificatePlugin)
+}
+
+// RegisterAuthPlugin makes an AuthPlugin available under name, registering a name again replaces its factory
+func RegisterAuthPlugin(name string, factory AuthPluginFactory) {
+	authPluginFactories.Store(name, factory)
+}
+
+// NewRequestAuth creates the custom authentication of the trust agent requests from the custom headers and the
+// registered AuthPlugin named pluginName, nil is returned when neither is configured
+func NewRequestAuth(headers map[string]string, pluginName string, pluginConfig map[string]string) (*RequestAuth, error) {
+	log.Trace("clients/trust_agent_client:NewRequestAuth() Entering")
+	defer log.Trace("clients/trust_agent_client:NewRequestAuth() Leaving")
+
+	if len(headers) == 0 && pluginName == "" {
+		return nil, nil
+	}
+	requestAuth := RequestAuth{Headers: headers}
+	if pluginName != "" {
+		factory, ok := authPluginFactories.Load(pluginName)
+		if !ok {
+			return nil, errors.Errorf("client/trust_agent_client:NewRequestAuth() Auth plugin %s is not registered", pluginName)
+		}
+		plugin, err := factory.(AuthPluginFactory)(pluginConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "client/trust_agent_client:NewRequestAuth() Error creating auth plugin %s", pluginName)
+		}
+		requestAuth.Plugin = plugin
+	}
+	return &requestAuth, nil
+}
+
+// requestOptions returns the options sending a request to the trust agent at taApiUrl with the custom authentication
+func (ra *RequestAuth) requestOptions(taApiUrl *url.URL) (*util.RequestOptions, error) {
+	if ra == nil {
+		return nil, nil
+	}
+	options := util.RequestOptions{Headers: ra.Headers}
+	if ra.Plugin != nil {
+		clientCert, err := ra.Plugin.ClientCertificate(taApiUrl)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error selecting the TLS client certificate")
+		}
+		options.ClientCertificate = clientCert
+		options.Authenticate = ra.Plugin.Authenticate
+	}
+	return &options, nil
+}
+
+// bearerTokenFilePlugin sets the bearer token read from a file, which is kept up to date by an external vault agent,
+// in the header of the requests
+type bearerTokenFilePlugin struct {
+	tokenFile string
+	header    string
+}
+
+func newBearerTokenFilePlugin(config map[string]string) (AuthPlugin, error) {
+	if config["token-file"] == "" {
+		return nil, errors.New("token-file is not set")
+	}
+	header := config["header"]
+	if header == "" {
+		header = "Authorization"
+	}
+	return &bearerTokenFilePlugin{tokenFile: config["token-file"], header: header}, nil
+}
+
+func (p *bearerTokenFilePlugin) Authenticate(req *http.Request) error {
+	// the token is read for every request as the vault agent rotates it
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return errors.Wrap(err, "Error reading bearer token file")
+	}
+	req.Header.Set(p.header, "Bearer "+strings.TrimSpace(string(token)))
+	return nil
+}
+
+func (p *bearerTokenFilePlugin) ClientCertificate(taApiUrl *url.URL) (*tls.Certificate, error) {
+	return nil, nil
+}
+
+// clientCertificatePlugin presents a TLS client certificate selected per host, <cert-dir>/<hostname>.pem and
+// <cert-dir>/<hostname>.key are used when present and the default certificate otherwise
+type clientCertificatePlugin struct {
+	certDir        string
+	defaultCert    string
+	defaultCertKey string
+}
+
+func newClientCertificatePlugin(config map[string]string) (AuthPlugin, error) {
+	if config["cert-dir"] == "" && config["cert-file"] == "" {
+		return nil, errors.New("either cert-dir or cert-file must be set")
+	}
+	if (config["cert-file"] == "") != (config["key-file"] == "") {
+		return nil, errors.New("cert-file and key-file must be set together")
+	}
+	return &clientCertificatePlugin{
+		certDir:        config["cert-dir"],
+		defaultCert:    config["cert-file"],
+		defaultCertKey: config["key-file"],
+	}, nil
+}
+
+func (p *clientCertificatePlugin) Authenticate(req *http.Request) error {
+	return nil
+}
+
+func (p *clientCertificatePlugin) ClientCertificate(taApiUrl *url.URL) (*tls.Certificate, error) {
+	if p.certDir != "" {
+		hostName := filepath.Base(taApiUrl.Hostname())
+		certFile := filepath.Join(p.certDir, hostName+".pem")
+		keyFile := filepath.Join(p.certDir, hostName+".key")
+		if _, err := os.Stat(certFile); err == nil {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error loading client certificate of host %s", hostName)
+			}
+			return &cert, nil
+		}
+	}
+	if p.defaultCert == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(p.defaultCert, p.defaultCertKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading default client certificate")
+	}
+	return &cert, nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestAuth(t *testing.T) {
+
+	requestAuth, err := NewRequestAuth(nil, "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, requestAuth)
+
+	requestAuth, err = NewRequestAuth(map[string]string{"X-Gateway-Key": "key"}, "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, requestAuth.Plugin)
+
+	_, err = NewRequestAuth(nil, "unknown-plugin", nil)
+	assert.Error(t, err)
+
+	_, err = NewRequestAuth(nil, BearerTokenFileAuthPlugin, map[string]string{})
+	assert.Error(t, err)
+
+	_, err = NewRequestAuth(nil, ClientCertificateAuthPlugin, map[string]string{"cert-file": "client.pem"})
+	assert.Error(t, err)
+}
+
+func TestBearerTokenFileAuthPlugin(t *testing.T) {
+
+	tempDir, err := ioutil.TempDir("", "ta-auth")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	tokenFile := filepath.Join(tempDir, "token")
+	err = ioutil.WriteFile(tokenFile, []byte("gateway-token\n"), 0600)
+	assert.NoError(t, err)
+
+	requestAuth, err := NewRequestAuth(map[string]string{"X-Gateway-Key": "key"}, BearerTokenFileAuthPlugin,
+		map[string]string{"token-file": tokenFile, "header": "X-Gateway-Authorization"})
+	assert.NoError(t, err)
+
+	taApiUrl, _ := url.Parse("https://ta.ip.com:1443/v2")
+	options, err := requestAuth.requestOptions(taApiUrl)
+	assert.NoError(t, err)
+	assert.Nil(t, options.ClientCertificate)
+
+	req, _ := http.NewRequest("GET", taApiUrl.String()+"/host", nil)
+	req.Header.Set("Authorization", "Bearer aas-token")
+	for name, value := range options.Headers {
+		req.Header.Set(name, value)
+	}
+	err = options.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "key", req.Header.Get("X-Gateway-Key"))
+	assert.Equal(t, "Bearer gateway-token", req.Header.Get("X-Gateway-Authorization"))
+	assert.Equal(t, "Bearer aas-token", req.Header.Get("Authorization"))
+}
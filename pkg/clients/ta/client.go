@@ -6,26 +6,41 @@ package ta
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/util"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
+// ErrCapabilitiesNotSupported is returned by GetCapabilities when the trust agent predates the capabilities API
+var ErrCapabilitiesNotSupported = errors.New("Trust agent does not support the capabilities API")
+
+// ErrAttestationBundleNotSupported is returned by GetAttestationBundle when the trust agent predates the attestation
+// bundle API
+var ErrAttestationBundleNotSupported = errors.New("Trust agent does not support the attestation bundle API")
+
+// TAClient sends the requests to the trust agent, the requests are canceled when their context is done
 type TAClient interface {
-	GetHostInfo() (taModel.HostInfo, error)
-	GetTPMQuote(nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error)
-	GetAIK() ([]byte, error)
-	GetBindingKeyCertificate() ([]byte, error)
-	DeployAssetTag(hardwareUUID, tag string) error
-	DeploySoftwareManifest(manifest taModel.Manifest) error
-	GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error)
+	GetHostInfo(ctx context.Context) (taModel.HostInfo, error)
+	GetCapabilities(ctx context.Context) (taModel.HostCapabilities, error)
+	GetTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error)
+	RequestTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string, correlationID, callbackURL string) error
+	GetAttestationBundle(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.AttestationBundle, error)
+	GetAIK(ctx context.Context) ([]byte, error)
+	GetBindingKeyCertificate(ctx context.Context) ([]byte, error)
+	DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error
+	DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error
+	GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error)
 	GetBaseURL() *url.URL
 }
 
@@ -43,18 +58,44 @@ func NewTAClient(aasApiUrl string, taApiUrl *url.URL, serviceUserName, serviceUs
 	return &taClient, nil
 }
 
+// NewTAClientWithAuth creates a TAClient sending its requests with the custom authentication of requestAuth
+func NewTAClientWithAuth(aasApiUrl string, taApiUrl *url.URL, serviceUserName, serviceUserPassword string,
+	trustedCaCerts []x509.Certificate, requestAuth *RequestAuth) (TAClient, error) {
+	return NewTAClientWithProxy(aasApiUrl, taApiUrl, serviceUserName, serviceUserPassword, trustedCaCerts, requestAuth, nil)
+}
+
+// NewTAClientWithProxy creates a TAClient sending its requests with the custom authentication of requestAuth through
+// the proxy selected by proxy, the requests are sent directly when it is nil
+func NewTAClientWithProxy(aasApiUrl string, taApiUrl *url.URL, serviceUserName, serviceUserPassword string,
+	trustedCaCerts []x509.Certificate, requestAuth *RequestAuth, proxy clients.ProxyFunc) (TAClient, error) {
+
+	taClient := taClient{
+		AasURL:          aasApiUrl,
+		BaseURL:         taApiUrl,
+		ServiceUsername: serviceUserName,
+		ServicePassword: serviceUserPassword,
+		TrustedCaCerts:  trustedCaCerts,
+		RequestAuth:     requestAuth,
+		Proxy:           proxy,
+	}
+
+	return &taClient, nil
+}
+
 type taClient struct {
 	AasURL          string
 	BaseURL         *url.URL
 	ServiceUsername string
 	ServicePassword string
 	TrustedCaCerts  []x509.Certificate
+	RequestAuth     *RequestAuth
+	Proxy           clients.ProxyFunc
 }
 
 var log = commLog.GetDefaultLogger()
 var secLog = commLog.GetSecurityLogger()
 
-func (tc *taClient) GetHostInfo() (taModel.HostInfo, error) {
+func (tc *taClient) GetHostInfo(ctx context.Context) (taModel.HostInfo, error) {
 	log.Trace("clients/trust_agent_client:GetHostInfo() Entering")
 	defer log.Trace("clients/trust_agent_client:GetHostInfo() Leaving")
 
@@ -65,7 +106,7 @@ func (tc *taClient) GetHostInfo() (taModel.HostInfo, error) {
 		return hostInfo, errors.New("client/trust_agent_client:GetHostInfo() error forming GET host info URL")
 	}
 	log.Debug("client/trust_agent_client:GetHostInfo() Request URL created for host info")
-	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", requestURL.String(), nil)
 	if err != nil {
 		return hostInfo, err
 	}
@@ -73,7 +114,7 @@ func (tc *taClient) GetHostInfo() (taModel.HostInfo, error) {
 	log.Debugf("clients/trust_agent_client:GetHostInfo() TA host info retrieval GET request URL: %s", requestURL.String())
 	httpRequest.Header.Set("Accept", "application/json")
 
-	httpResponse, err := util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	httpResponse, err := tc.sendRequest(httpRequest)
 	if err != nil {
 		return hostInfo, errors.Wrap(err, "client/trust_agent_client:GetHostInfo() Error while getting response"+
 			" from Get host info from TA API")
@@ -87,7 +128,42 @@ func (tc *taClient) GetHostInfo() (taModel.HostInfo, error) {
 	return hostInfo, nil
 }
 
-func (tc *taClient) GetTPMQuote(nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error) {
+func (tc *taClient) GetCapabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	log.Trace("clients/trust_agent_client:GetCapabilities() Entering")
+	defer log.Trace("clients/trust_agent_client:GetCapabilities() Leaving")
+
+	var capabilities taModel.HostCapabilities
+
+	requestURL, err := url.Parse(tc.BaseURL.String() + "/host/capabilities")
+	if err != nil {
+		return capabilities, errors.New("client/trust_agent_client:GetCapabilities() error forming GET host capabilities URL")
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", requestURL.String(), nil)
+	if err != nil {
+		return capabilities, err
+	}
+
+	log.Debugf("clients/trust_agent_client:GetCapabilities() TA host capabilities retrieval GET request URL: %s", requestURL.String())
+	httpRequest.Header.Set("Accept", "application/json")
+
+	httpResponse, err := tc.sendRequest(httpRequest)
+	if err != nil {
+		if strings.Contains(err.Error(), "HTTP Status :"+strconv.Itoa(http.StatusNotFound)) {
+			return capabilities, ErrCapabilitiesNotSupported
+		}
+		return capabilities, errors.Wrap(err, "client/trust_agent_client:GetCapabilities() Error while getting response"+
+			" from Get host capabilities from TA API")
+	}
+	err = json.Unmarshal(httpResponse, &capabilities)
+	if err != nil {
+		return capabilities, errors.Wrap(err, "client/trust_agent_client:GetCapabilities() Error while unmarshalling"+
+			" response from Get host capabilities from TA API")
+	}
+	log.Info("client/trust_agent_client:GetCapabilities() Successfully received host capabilities from TA")
+	return capabilities, nil
+}
+
+func (tc *taClient) GetTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error) {
 	log.Trace("clients/trust_agent_client:GetTPMQuote() Entering")
 	defer log.Trace("clients/trust_agent_client:GetTPMQuote() Leaving")
 
@@ -109,7 +185,7 @@ func (tc *taClient) GetTPMQuote(nonce string, pcrList []int, pcrBankList []strin
 	buffer := new(bytes.Buffer)
 	err = json.NewEncoder(buffer).Encode(quoteRequest)
 	secLog.Debugf("client/trust_agent_client:GetTPMQuote() TPM quote request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return quoteResponse, err
 	}
@@ -117,7 +193,7 @@ func (tc *taClient) GetTPMQuote(nonce string, pcrList []int, pcrBankList []strin
 	log.Debugf("clients/trust_agent_client:GetTPMQuote() TA host manifest retrieval POST request URL: %s", requestURL.String())
 	httpRequest.Header.Set("Content-Type", "application/json")
 
-	httpResponse, err := util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	httpResponse, err := tc.sendRequest(httpRequest)
 	if err != nil {
 		return quoteResponse, errors.Wrap(err, "client/trust_agent_client:GetTPMQuote() Error while getting response"+
 			" from Get host manifest from TA API")
@@ -132,7 +208,101 @@ func (tc *taClient) GetTPMQuote(nonce string, pcrList []int, pcrBankList []strin
 	return quoteResponse, nil
 }
 
-func (tc *taClient) GetAIK() ([]byte, error) {
+// RequestTPMQuote requests a TPM quote asynchronously, the trust agent accepts the request and posts
+// the quote response to the callback url once it is ready
+func (tc *taClient) RequestTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string, correlationID, callbackURL string) error {
+	log.Trace("clients/trust_agent_client:RequestTPMQuote() Entering")
+	defer log.Trace("clients/trust_agent_client:RequestTPMQuote() Leaving")
+
+	var quoteRequest taModel.TpmQuoteRequest
+
+	requestURL, err := url.Parse(tc.BaseURL.String() + "/tpm/quote")
+	if err != nil {
+		return errors.New("client/trust_agent_client:RequestTPMQuote() error forming tpm quote URL")
+	}
+	quoteRequest.Nonce, err = base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return errors.New("client/trust_agent_client:RequestTPMQuote() Error decoding nonce from base64 to bytes")
+	}
+	quoteRequest.Pcrs = pcrList
+	quoteRequest.PcrBanks = pcrBankList
+	quoteRequest.CorrelationID = correlationID
+	quoteRequest.CallbackURL = callbackURL
+	buffer := new(bytes.Buffer)
+	err = json.NewEncoder(buffer).Encode(quoteRequest)
+	if err != nil {
+		return errors.Wrap(err, "client/trust_agent_client:RequestTPMQuote() Error encoding tpm quote request")
+	}
+	secLog.Debugf("client/trust_agent_client:RequestTPMQuote() TPM quote request: %s", buffer.String())
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), buffer)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("clients/trust_agent_client:RequestTPMQuote() TA async tpm quote POST request URL: %s, correlation id: %s", requestURL.String(), correlationID)
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	_, err = tc.sendRequest(httpRequest)
+	if err != nil {
+		return errors.Wrap(err, "client/trust_agent_client:RequestTPMQuote() Error while requesting"+
+			" an asynchronous TPM quote from TA API")
+	}
+	log.Infof("client/trust_agent_client:RequestTPMQuote() TA accepted the TPM quote request %s", correlationID)
+	return nil
+}
+
+// GetAttestationBundle retrieves the quote, the event logs, the IMA log and the host info of the host in a single
+// request, the quote covers the digests of all of them
+func (tc *taClient) GetAttestationBundle(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.AttestationBundle, error) {
+	log.Trace("clients/trust_agent_client:GetAttestationBundle() Entering")
+	defer log.Trace("clients/trust_agent_client:GetAttestationBundle() Leaving")
+
+	var bundleRequest taModel.TpmQuoteRequest
+	var bundle taModel.AttestationBundle
+
+	requestURL, err := url.Parse(tc.BaseURL.String() + "/tpm/attestation-bundle")
+	if err != nil {
+		return bundle, errors.New("client/trust_agent_client:GetAttestationBundle() error forming attestation bundle URL")
+	}
+	bundleRequest.Nonce, err = base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return bundle, errors.New("client/trust_agent_client:GetAttestationBundle() Error decoding nonce from base64 to bytes")
+	}
+	bundleRequest.Pcrs = pcrList
+	bundleRequest.PcrBanks = pcrBankList
+	buffer := new(bytes.Buffer)
+	err = json.NewEncoder(buffer).Encode(bundleRequest)
+	if err != nil {
+		return bundle, errors.Wrap(err, "client/trust_agent_client:GetAttestationBundle() Error encoding attestation bundle request")
+	}
+	secLog.Debugf("client/trust_agent_client:GetAttestationBundle() Attestation bundle request: %s", buffer.String())
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), buffer)
+	if err != nil {
+		return bundle, err
+	}
+
+	log.Debugf("clients/trust_agent_client:GetAttestationBundle() TA attestation bundle POST request URL: %s", requestURL.String())
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := tc.sendRequest(httpRequest)
+	if err != nil {
+		if strings.Contains(err.Error(), "HTTP Status :"+strconv.Itoa(http.StatusNotFound)) {
+			return bundle, ErrAttestationBundleNotSupported
+		}
+		return bundle, errors.Wrap(err, "client/trust_agent_client:GetAttestationBundle() Error while getting response"+
+			" from Get attestation bundle from TA API")
+	}
+	secLog.Debugf("client/trust_agent_client:GetAttestationBundle() Attestation bundle response: %s", string(httpResponse))
+	err = xml.Unmarshal(httpResponse, &bundle)
+	if err != nil {
+		return bundle, errors.Wrap(err, "client/trust_agent_client:GetAttestationBundle() Error while unmarshalling"+
+			" response from Get attestation bundle from TA API")
+	}
+	log.Info("client/trust_agent_client:GetAttestationBundle() Successfully received attestation bundle from TA")
+	return bundle, nil
+}
+
+func (tc *taClient) GetAIK(ctx context.Context) ([]byte, error) {
 	log.Trace("clients/trust_agent_client:GetAIK() Entering")
 	defer log.Trace("clients/trust_agent_client:GetAIK() Leaving")
 
@@ -142,14 +312,14 @@ func (tc *taClient) GetAIK() ([]byte, error) {
 	}
 	log.Debug("clients/trust_agent_client:GetAIK() Request URL created for AIK certificate")
 
-	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", requestURL.String(), nil)
 	if err != nil {
 		return []byte{}, err
 	}
 
 	log.Debugf("clients/trust_agent_client:GetAIK() TA AIK certificate retrieval GET request URL: %s", requestURL.String())
 
-	httpResponse, err := util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	httpResponse, err := tc.sendRequest(httpRequest)
 	if err != nil {
 		return []byte{}, errors.Wrap(err, "client/trust_agent_client:GetAIK() Error while getting response"+
 			" from Get AIK API")
@@ -158,7 +328,7 @@ func (tc *taClient) GetAIK() ([]byte, error) {
 	return httpResponse, nil
 }
 
-func (tc *taClient) GetBindingKeyCertificate() ([]byte, error) {
+func (tc *taClient) GetBindingKeyCertificate(ctx context.Context) ([]byte, error) {
 	log.Trace("clients/trust_agent_client:GetBindingKeyCertificate() Entering")
 	defer log.Trace("clients/trust_agent_client:GetBindingKeyCertificate() Leaving")
 
@@ -168,7 +338,7 @@ func (tc *taClient) GetBindingKeyCertificate() ([]byte, error) {
 			"certificate URL")
 	}
 	log.Debug("clients/trust_agent_client:GetBindingKeyCertificate() Request URL created for Binding Key certificate")
-	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", requestURL.String(), nil)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -177,7 +347,7 @@ func (tc *taClient) GetBindingKeyCertificate() ([]byte, error) {
 	secLog.Debugf("clients/trust_agent_client:GetBindingKeyCertificate() TA Binding key certificate retrieval "+
 		"GET request URL: %s", requestURL.String())
 
-	httpResponse, err := util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	httpResponse, err := tc.sendRequest(httpRequest)
 	if err != nil {
 		return []byte{}, errors.Wrap(err, "client/trust_agent_client:GetBindingKeyCertificate() Error while "+
 			"getting response  from Get Binding key certificate API")
@@ -186,7 +356,7 @@ func (tc *taClient) GetBindingKeyCertificate() ([]byte, error) {
 	return httpResponse, nil
 }
 
-func (tc *taClient) DeployAssetTag(hardwareUUID, tag string) error {
+func (tc *taClient) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
 	log.Trace("clients/trust_agent_client:DeployAssetTag() Entering")
 	defer log.Trace("clients/trust_agent_client:DeployAssetTag() Leaving")
 
@@ -206,7 +376,7 @@ func (tc *taClient) DeployAssetTag(hardwareUUID, tag string) error {
 	buffer := new(bytes.Buffer)
 	err = json.NewEncoder(buffer).Encode(tagWriteRequest)
 	secLog.Debugf("TAG request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return err
 	}
@@ -214,7 +384,7 @@ func (tc *taClient) DeployAssetTag(hardwareUUID, tag string) error {
 	log.Debugf("clients/trust_agent_client:DeployAssetTag() TA asset tag deploy POST request URL: %s", requestURL.String())
 	httpRequest.Header.Set("Content-Type", "application/json")
 
-	_, err = util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	_, err = tc.sendRequest(httpRequest)
 	if err != nil {
 		return errors.Wrap(err, "client/trust_agent_client:DeployAssetTag() Error while getting response"+
 			" from Deploy asset tag from TA API")
@@ -223,7 +393,7 @@ func (tc *taClient) DeployAssetTag(hardwareUUID, tag string) error {
 	return nil
 }
 
-func (tc *taClient) DeploySoftwareManifest(manifest taModel.Manifest) error {
+func (tc *taClient) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
 	log.Trace("clients/trust_agent_client:DeploySoftwareManifest() Entering")
 	defer log.Trace("clients/trust_agent_client:DeploySoftwareManifest() Leaving")
 
@@ -237,7 +407,7 @@ func (tc *taClient) DeploySoftwareManifest(manifest taModel.Manifest) error {
 	//This is added due to bug in xml encode where LF is escaped into &#xA;
 	buffer = bytes.NewBuffer(bytes.Replace(buffer.Bytes(), []byte("&#xA;"), []byte("\n"), -1))
 	log.Debugf("Manifest request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return err
 	}
@@ -245,7 +415,7 @@ func (tc *taClient) DeploySoftwareManifest(manifest taModel.Manifest) error {
 	log.Debugf("clients/trust_agent_client:DeploySoftwareManifest() TA software manifest deploy POST request URL: %s", requestURL.String())
 	httpRequest.Header.Set("Content-Type", "application/xml")
 
-	_, err = util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	_, err = tc.sendRequest(httpRequest)
 	if err != nil {
 		return errors.Wrap(err, "client/trust_agent_client:DeploySoftwareManifest() Error while getting response"+
 			" from Deploy software manifest from TA API")
@@ -254,7 +424,7 @@ func (tc *taClient) DeploySoftwareManifest(manifest taModel.Manifest) error {
 	return nil
 }
 
-func (tc *taClient) GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error) {
+func (tc *taClient) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
 	log.Trace("clients/trust_agent_client:GetMeasurementFromManifest() Entering")
 	defer log.Trace("clients/trust_agent_client:GetMeasurementFromManifest() Leaving")
 
@@ -269,7 +439,7 @@ func (tc *taClient) GetMeasurementFromManifest(manifest taModel.Manifest) (taMod
 	//This is added due to bug in xml encode where LF is escaped into &#xA;
 	buffer = bytes.NewBuffer(bytes.Replace(buffer.Bytes(), []byte("&#xA;"), []byte("\n"), -1))
 	log.Debugf("Manifest request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return measurement, err
 	}
@@ -277,7 +447,7 @@ func (tc *taClient) GetMeasurementFromManifest(manifest taModel.Manifest) (taMod
 	log.Debugf("clients/trust_agent_client:GetMeasurementFromManifest() TA host application measurement POST request URL: %s", requestURL.String())
 	httpRequest.Header.Set("Content-Type", "application/xml")
 
-	httpResponse, err := util.SendRequest(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts)
+	httpResponse, err := tc.sendRequest(httpRequest)
 	if err != nil {
 		return measurement, errors.Wrap(err, "client/trust_agent_client:GetMeasurementFromManifest() Error while getting response"+
 			" from Host application measurement from TA API")
@@ -296,3 +466,18 @@ func (tc *taClient) GetMeasurementFromManifest(manifest taModel.Manifest) (taMod
 func (ta *taClient) GetBaseURL() *url.URL {
 	return ta.BaseURL
 }
+
+// sendRequest sends the request to the trust agent with the AAS bearer token and the custom authentication of the client
+func (tc *taClient) sendRequest(httpRequest *http.Request) ([]byte, error) {
+	options, err := tc.RequestAuth.requestOptions(tc.BaseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "client/trust_agent_client:sendRequest() Error applying custom authentication")
+	}
+	if tc.Proxy != nil {
+		if options == nil {
+			options = &util.RequestOptions{}
+		}
+		options.Proxy = tc.Proxy
+	}
+	return util.SendRequestWithOptions(httpRequest, tc.AasURL, tc.ServiceUsername, tc.ServicePassword, tc.TrustedCaCerts, options)
+}
@@ -7,6 +7,8 @@ package ta
 //go:generate mockgen -destination=mock_taclient.go -package=ta github.com/intel-secl/intel-secl/v3/pkg/lib/clients/ta TAClient
 
 import (
+	"context"
+
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/stretchr/testify/mock"
 	"net/url"
@@ -21,37 +23,52 @@ func NewMockTAClient() (*MockTAClient, error) {
 	return &mockTAClient, nil
 }
 
-func (ta *MockTAClient) GetHostInfo() (taModel.HostInfo, error) {
+func (ta *MockTAClient) GetHostInfo(ctx context.Context) (taModel.HostInfo, error) {
 	args := ta.Called()
 	return args.Get(0).(taModel.HostInfo), args.Error(1)
 }
 
-func (ta *MockTAClient) GetTPMQuote(nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error) {
+func (ta *MockTAClient) GetCapabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	args := ta.Called()
+	return args.Get(0).(taModel.HostCapabilities), args.Error(1)
+}
+
+func (ta *MockTAClient) GetTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error) {
 	args := ta.Called(nonce, pcrList, pcrBankList)
 	return args.Get(0).(taModel.TpmQuoteResponse), args.Error(1)
 }
 
-func (ta *MockTAClient) GetAIK() ([]byte, error) {
+func (ta *MockTAClient) RequestTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string, correlationID, callbackURL string) error {
+	args := ta.Called(nonce, pcrList, pcrBankList, correlationID, callbackURL)
+	return args.Error(0)
+}
+
+func (ta *MockTAClient) GetAttestationBundle(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.AttestationBundle, error) {
+	args := ta.Called(nonce, pcrList, pcrBankList)
+	return args.Get(0).(taModel.AttestationBundle), args.Error(1)
+}
+
+func (ta *MockTAClient) GetAIK(ctx context.Context) ([]byte, error) {
 	args := ta.Called()
 	return args.Get(0).([]byte), args.Error(1)
 }
 
-func (ta *MockTAClient) GetBindingKeyCertificate() ([]byte, error) {
+func (ta *MockTAClient) GetBindingKeyCertificate(ctx context.Context) ([]byte, error) {
 	args := ta.Called()
 	return args.Get(0).([]byte), args.Error(1)
 }
 
-func (ta *MockTAClient) DeployAssetTag(hardwareUUID, tag string) error {
+func (ta *MockTAClient) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
 	args := ta.Called(hardwareUUID, tag)
 	return args.Error(0)
 }
 
-func (ta *MockTAClient) DeploySoftwareManifest(manifest taModel.Manifest) error {
+func (ta *MockTAClient) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
 	args := ta.Called(manifest)
 	return args.Error(0)
 }
 
-func (ta *MockTAClient) GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error) {
+func (ta *MockTAClient) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
 	args := ta.Called(manifest)
 	return args.Get(0).(taModel.Measurement), args.Error(1)
 }
@@ -0,0 +1,377 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ta
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"time"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	DefaultSshPort        = "22"
+	DefaultSshTagent      = "tagent"
+	DefaultSshDialTimeout = 30 * time.Second
+)
+
+// The trust agent commands run over ssh, they read the request of the matching API from their standard input and
+// write its response to their standard output
+const (
+	sshHostInfoCommand               = "host-info"
+	sshCapabilitiesCommand           = "capabilities"
+	sshQuoteCommand                  = "quote"
+	sshAttestationBundleCommand      = "attestation-bundle"
+	sshAikCommand                    = "aik"
+	sshBindingKeyCertificateCommand  = "binding-key-certificate"
+	sshDeployAssetTagCommand         = "deploy-asset-tag"
+	sshDeployManifestCommand         = "deploy-manifest"
+	sshApplicationMeasurementCommand = "application-measurement"
+	sshCommandNotFoundExitStatus     = 127
+	sshUnsupportedCommandsExitStatus = 2
+)
+
+// SshConfig is the configuration of the trust agent clients running the tagent CLI over ssh, for the hosts whose
+// trust agent API cannot be exposed
+type SshConfig struct {
+	// KnownHostsFile holds the keys of the hosts, the connections to the hosts without a known key are refused
+	KnownHostsFile string
+	// PrivateKeyFile is the key the client authenticates with, when there is no password in the connection string
+	PrivateKeyFile string
+	// Tagent is the command the trust agent CLI is run with, e.g. "sudo tagent", DefaultSshTagent when it is not set
+	Tagent string
+	// DialTimeout is DefaultSshDialTimeout when it is not set
+	DialTimeout time.Duration
+}
+
+// sshCommandRunner runs a command on the host and returns its standard output, the command is aborted when the
+// context is done
+type sshCommandRunner func(ctx context.Context, command string, stdin []byte) ([]byte, error)
+
+type sshTAClient struct {
+	BaseURL *url.URL
+	tagent  string
+	run     sshCommandRunner
+}
+
+// NewSshTAClient creates a TAClient running the tagent CLI on the host of the ssh url, e.g. ssh://host.ip:22
+func NewSshTAClient(sshUrl *url.URL, username, password string, config SshConfig) (TAClient, error) {
+	if sshUrl.Scheme != "ssh" || sshUrl.Hostname() == "" {
+		return nil, errors.New("client/ssh_client:NewSshTAClient() Invalid ssh url")
+	}
+	if username == "" {
+		return nil, errors.New("client/ssh_client:NewSshTAClient() The ssh username must be provided")
+	}
+	if config.KnownHostsFile == "" {
+		return nil, errors.New("client/ssh_client:NewSshTAClient() The ssh known hosts file must be configured")
+	}
+	hostKeyCallback, err := knownhosts.New(config.KnownHostsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "client/ssh_client:NewSshTAClient() Error reading the ssh known hosts file")
+	}
+
+	var authMethods []ssh.AuthMethod
+	if password != "" {
+		authMethods = append(authMethods, ssh.Password(password))
+	} else if config.PrivateKeyFile != "" {
+		pemKey, err := ioutil.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "client/ssh_client:NewSshTAClient() Error reading the ssh private key")
+		}
+		signer, err := ssh.ParsePrivateKey(pemKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "client/ssh_client:NewSshTAClient() Error parsing the ssh private key")
+		}
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	} else {
+		return nil, errors.New("client/ssh_client:NewSshTAClient() Either the ssh password or private key must be provided")
+	}
+
+	dialTimeout := config.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = DefaultSshDialTimeout
+	}
+	port := sshUrl.Port()
+	if port == "" {
+		port = DefaultSshPort
+	}
+	clientConfig := &ssh.ClientConfig{
+		User:            username,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}
+	address := net.JoinHostPort(sshUrl.Hostname(), port)
+
+	tagent := config.Tagent
+	if tagent == "" {
+		tagent = DefaultSshTagent
+	}
+	return &sshTAClient{
+		BaseURL: sshUrl,
+		tagent:  tagent,
+		run: func(ctx context.Context, command string, stdin []byte) ([]byte, error) {
+			return runSshCommand(ctx, address, clientConfig, command, stdin)
+		},
+	}, nil
+}
+
+// runSshCommand runs the command in a connection of its own, the connectors are not closed by their users. The
+// connection is closed when the context is done, which aborts the command
+func runSshCommand(ctx context.Context, address string, clientConfig *ssh.ClientConfig, command string, stdin []byte) ([]byte, error) {
+	dialer := net.Dialer{Timeout: clientConfig.Timeout}
+	netConnection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error connecting to "+address)
+	}
+	sshConnection, channels, requests, err := ssh.NewClientConn(netConnection, address, clientConfig)
+	if err != nil {
+		netConnection.Close()
+		return nil, errors.Wrap(err, "Error connecting to "+address)
+	}
+	connection := ssh.NewClient(sshConnection, channels, requests)
+	defer func() {
+		if err := connection.Close(); err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("client/ssh_client:runSshCommand() Error closing the ssh connection")
+		}
+	}()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			connection.Close()
+		case <-stop:
+		}
+	}()
+
+	session, err := connection.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error opening an ssh session")
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdin = bytes.NewReader(stdin)
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err = session.Run(command); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Wrapf(ctx.Err(), "Error running %s", command)
+		}
+		return nil, errors.Wrapf(err, "Error running %s: %s", command, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+func (sc *sshTAClient) runTagent(ctx context.Context, command string, stdin []byte) ([]byte, error) {
+	log.Debugf("client/ssh_client:runTagent() Running %s %s on %s", sc.tagent, command, sc.BaseURL.Host)
+	return sc.run(ctx, sc.tagent+" "+command, stdin)
+}
+
+func (sc *sshTAClient) GetHostInfo(ctx context.Context) (taModel.HostInfo, error) {
+	log.Trace("clients/ssh_client:GetHostInfo() Entering")
+	defer log.Trace("clients/ssh_client:GetHostInfo() Leaving")
+
+	var hostInfo taModel.HostInfo
+	output, err := sc.runTagent(ctx, sshHostInfoCommand, nil)
+	if err != nil {
+		return hostInfo, errors.Wrap(err, "client/ssh_client:GetHostInfo() Error getting the host info")
+	}
+	if err = json.Unmarshal(output, &hostInfo); err != nil {
+		return hostInfo, errors.Wrap(err, "client/ssh_client:GetHostInfo() Error while unmarshalling the host info")
+	}
+	return hostInfo, nil
+}
+
+func (sc *sshTAClient) GetCapabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	log.Trace("clients/ssh_client:GetCapabilities() Entering")
+	defer log.Trace("clients/ssh_client:GetCapabilities() Leaving")
+
+	var capabilities taModel.HostCapabilities
+	output, err := sc.runTagent(ctx, sshCapabilitiesCommand, nil)
+	if err != nil {
+		// the trust agents that predate the capabilities command reject it as a usage error
+		if exitError, ok := errors.Cause(err).(*ssh.ExitError); ok &&
+			(exitError.ExitStatus() == sshUnsupportedCommandsExitStatus || exitError.ExitStatus() == sshCommandNotFoundExitStatus) {
+			return capabilities, ErrCapabilitiesNotSupported
+		}
+		return capabilities, errors.Wrap(err, "client/ssh_client:GetCapabilities() Error getting the host capabilities")
+	}
+	if err = json.Unmarshal(output, &capabilities); err != nil {
+		return capabilities, errors.Wrap(err, "client/ssh_client:GetCapabilities() Error while unmarshalling the host capabilities")
+	}
+	return capabilities, nil
+}
+
+func (sc *sshTAClient) GetTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error) {
+	log.Trace("clients/ssh_client:GetTPMQuote() Entering")
+	defer log.Trace("clients/ssh_client:GetTPMQuote() Leaving")
+
+	var quoteRequest taModel.TpmQuoteRequest
+	var quoteResponse taModel.TpmQuoteResponse
+
+	var err error
+	quoteRequest.Nonce, err = base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return quoteResponse, errors.New("client/ssh_client:GetTPMQuote() Error decoding nonce from base64 to bytes")
+	}
+	quoteRequest.Pcrs = pcrList
+	quoteRequest.PcrBanks = pcrBankList
+	request, err := json.Marshal(quoteRequest)
+	if err != nil {
+		return quoteResponse, errors.Wrap(err, "client/ssh_client:GetTPMQuote() Error encoding the tpm quote request")
+	}
+
+	output, err := sc.runTagent(ctx, sshQuoteCommand, request)
+	if err != nil {
+		return quoteResponse, errors.Wrap(err, "client/ssh_client:GetTPMQuote() Error getting the tpm quote")
+	}
+	if err = xml.Unmarshal(output, &quoteResponse); err != nil {
+		return quoteResponse, errors.Wrap(err, "client/ssh_client:GetTPMQuote() Error while unmarshalling the tpm quote")
+	}
+	log.Info("client/ssh_client:GetTPMQuote() Successfully received TPM quote response over ssh")
+	return quoteResponse, nil
+}
+
+// RequestTPMQuote is not supported, the hosts reached over ssh cannot post the quotes back to HVS
+func (sc *sshTAClient) RequestTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string, correlationID, callbackURL string) error {
+	return errors.New("client/ssh_client:RequestTPMQuote() Asynchronous quotes are not supported over ssh")
+}
+
+func (sc *sshTAClient) GetAttestationBundle(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.AttestationBundle, error) {
+	log.Trace("clients/ssh_client:GetAttestationBundle() Entering")
+	defer log.Trace("clients/ssh_client:GetAttestationBundle() Leaving")
+
+	var bundleRequest taModel.TpmQuoteRequest
+	var bundle taModel.AttestationBundle
+
+	var err error
+	bundleRequest.Nonce, err = base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return bundle, errors.New("client/ssh_client:GetAttestationBundle() Error decoding nonce from base64 to bytes")
+	}
+	bundleRequest.Pcrs = pcrList
+	bundleRequest.PcrBanks = pcrBankList
+	request, err := json.Marshal(bundleRequest)
+	if err != nil {
+		return bundle, errors.Wrap(err, "client/ssh_client:GetAttestationBundle() Error encoding the attestation bundle request")
+	}
+
+	output, err := sc.runTagent(ctx, sshAttestationBundleCommand, request)
+	if err != nil {
+		// the trust agents that predate the attestation bundle command reject it as a usage error
+		if exitError, ok := errors.Cause(err).(*ssh.ExitError); ok &&
+			(exitError.ExitStatus() == sshUnsupportedCommandsExitStatus || exitError.ExitStatus() == sshCommandNotFoundExitStatus) {
+			return bundle, ErrAttestationBundleNotSupported
+		}
+		return bundle, errors.Wrap(err, "client/ssh_client:GetAttestationBundle() Error getting the attestation bundle")
+	}
+	if err = xml.Unmarshal(output, &bundle); err != nil {
+		return bundle, errors.Wrap(err, "client/ssh_client:GetAttestationBundle() Error while unmarshalling the attestation bundle")
+	}
+	log.Info("client/ssh_client:GetAttestationBundle() Successfully received attestation bundle over ssh")
+	return bundle, nil
+}
+
+func (sc *sshTAClient) GetAIK(ctx context.Context) ([]byte, error) {
+	log.Trace("clients/ssh_client:GetAIK() Entering")
+	defer log.Trace("clients/ssh_client:GetAIK() Leaving")
+
+	output, err := sc.runTagent(ctx, sshAikCommand, nil)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "client/ssh_client:GetAIK() Error getting the AIK certificate")
+	}
+	return output, nil
+}
+
+func (sc *sshTAClient) GetBindingKeyCertificate(ctx context.Context) ([]byte, error) {
+	log.Trace("clients/ssh_client:GetBindingKeyCertificate() Entering")
+	defer log.Trace("clients/ssh_client:GetBindingKeyCertificate() Leaving")
+
+	output, err := sc.runTagent(ctx, sshBindingKeyCertificateCommand, nil)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "client/ssh_client:GetBindingKeyCertificate() Error getting the binding key certificate")
+	}
+	return output, nil
+}
+
+func (sc *sshTAClient) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
+	log.Trace("clients/ssh_client:DeployAssetTag() Entering")
+	defer log.Trace("clients/ssh_client:DeployAssetTag() Leaving")
+
+	var tagWriteRequest taModel.TagWriteRequest
+	var err error
+	tagWriteRequest.Tag, err = base64.StdEncoding.DecodeString(tag)
+	if err != nil {
+		return errors.New("client/ssh_client:DeployAssetTag() Error decoding tag from base64 to bytes")
+	}
+	tagWriteRequest.HardwareUUID = hardwareUUID
+	request, err := json.Marshal(tagWriteRequest)
+	if err != nil {
+		return errors.Wrap(err, "client/ssh_client:DeployAssetTag() Error encoding the asset tag")
+	}
+
+	if _, err = sc.runTagent(ctx, sshDeployAssetTagCommand, request); err != nil {
+		return errors.Wrap(err, "client/ssh_client:DeployAssetTag() Error deploying the asset tag")
+	}
+	return nil
+}
+
+func (sc *sshTAClient) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
+	log.Trace("clients/ssh_client:DeploySoftwareManifest() Entering")
+	defer log.Trace("clients/ssh_client:DeploySoftwareManifest() Leaving")
+
+	request, err := encodeManifest(manifest)
+	if err != nil {
+		return errors.Wrap(err, "client/ssh_client:DeploySoftwareManifest() Error encoding the software manifest")
+	}
+	if _, err = sc.runTagent(ctx, sshDeployManifestCommand, request); err != nil {
+		return errors.Wrap(err, "client/ssh_client:DeploySoftwareManifest() Error deploying the software manifest")
+	}
+	return nil
+}
+
+func (sc *sshTAClient) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
+	log.Trace("clients/ssh_client:GetMeasurementFromManifest() Entering")
+	defer log.Trace("clients/ssh_client:GetMeasurementFromManifest() Leaving")
+
+	var measurement taModel.Measurement
+	request, err := encodeManifest(manifest)
+	if err != nil {
+		return measurement, errors.Wrap(err, "client/ssh_client:GetMeasurementFromManifest() Error encoding the manifest")
+	}
+	output, err := sc.runTagent(ctx, sshApplicationMeasurementCommand, request)
+	if err != nil {
+		return measurement, errors.Wrap(err, "client/ssh_client:GetMeasurementFromManifest() Error measuring the manifest")
+	}
+	if err = xml.Unmarshal(output, &measurement); err != nil {
+		return measurement, errors.Wrap(err, "client/ssh_client:GetMeasurementFromManifest() Error while unmarshalling the measurement")
+	}
+	return measurement, nil
+}
+
+func (sc *sshTAClient) GetBaseURL() *url.URL {
+	return sc.BaseURL
+}
+
+func encodeManifest(manifest taModel.Manifest) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if err := xml.NewEncoder(buffer).Encode(manifest); err != nil {
+		return nil, err
+	}
+	//This is added due to bug in xml encode where LF is escaped into &#xA;
+	return bytes.Replace(buffer.Bytes(), []byte("&#xA;"), []byte("\n"), -1), nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ta
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSshTAClient(t *testing.T) {
+
+	tempDir, err := ioutil.TempDir("", "ta-ssh")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	knownHostsFile := filepath.Join(tempDir, "known_hosts")
+	assert.NoError(t, ioutil.WriteFile(knownHostsFile, []byte{}, 0600))
+
+	sshUrl, _ := url.Parse("ssh://ta.ip.com:2222")
+	taClient, err := NewSshTAClient(sshUrl, "admin", "password", SshConfig{KnownHostsFile: knownHostsFile})
+	assert.NoError(t, err)
+	assert.Equal(t, sshUrl, taClient.GetBaseURL())
+
+	// the host keys must be known
+	_, err = NewSshTAClient(sshUrl, "admin", "password", SshConfig{})
+	assert.Error(t, err)
+
+	// the client must authenticate with a password or a key
+	_, err = NewSshTAClient(sshUrl, "admin", "", SshConfig{KnownHostsFile: knownHostsFile})
+	assert.Error(t, err)
+
+	httpsUrl, _ := url.Parse("https://ta.ip.com:1443")
+	_, err = NewSshTAClient(httpsUrl, "admin", "password", SshConfig{KnownHostsFile: knownHostsFile})
+	assert.Error(t, err)
+}
+
+func TestSshTAClientCommands(t *testing.T) {
+
+	quote, err := ioutil.ReadFile("../../lib/host-connector/test/sample_tpm_quote.xml")
+	assert.NoError(t, err)
+
+	var commands []string
+	var quoteRequest taModel.TpmQuoteRequest
+	taClient := &sshTAClient{
+		BaseURL: &url.URL{Scheme: "ssh", Host: "ta.ip.com"},
+		tagent:  "sudo tagent",
+		run: func(ctx context.Context, command string, stdin []byte) ([]byte, error) {
+			commands = append(commands, command)
+			switch command {
+			case "sudo tagent host-info":
+				return []byte(`{"hardware_uuid": "8032632b-8fa4-e811-906e-00163566263e"}`), nil
+			case "sudo tagent quote":
+				assert.NoError(t, json.Unmarshal(stdin, &quoteRequest))
+				return quote, nil
+			}
+			return nil, nil
+		},
+	}
+
+	hostInfo, err := taClient.GetHostInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "8032632b-8fa4-e811-906e-00163566263e", hostInfo.HardwareUUID)
+
+	nonce := base64.StdEncoding.EncodeToString([]byte("nonce"))
+	quoteResponse, err := taClient.GetTPMQuote(context.Background(), nonce, []int{0, 17}, []string{"SHA256"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, quoteResponse.Quote)
+	assert.Equal(t, []byte("nonce"), quoteRequest.Nonce)
+	assert.Equal(t, []int{0, 17}, quoteRequest.Pcrs)
+
+	assert.Error(t, taClient.RequestTPMQuote(context.Background(), nonce, nil, nil, "id", "https://hvs/callback"))
+	assert.Equal(t, []string{"sudo tagent host-info", "sudo tagent quote"}, commands)
+}
@@ -5,101 +5,101 @@
 package util
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"io/ioutil"
 	"net/http"
 	"strconv"
-	"sync"
 
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/aas"
 
 	"github.com/pkg/errors"
 )
 
-var jwtTokenMap = sync.Map{}
-
 var log = commLog.GetDefaultLogger()
 var secLog = commLog.GetSecurityLogger()
 
-func addJWTToken(aasClient *aas.JwtClient, req *http.Request, aasURL, serviceUsername, servicePassword string,
-	trustedCaCerts []x509.Certificate, forceFetch bool) error {
+// addJWTToken sets the bearer token of the service user on the request, the token is cached and refreshed by the
+// token provider of the user shared by all the requests. The token set on the request is returned.
+func addJWTToken(req *http.Request, aasURL, serviceUsername, servicePassword string,
+	trustedCaCerts []x509.Certificate) ([]byte, error) {
 	log.Trace("clients/send_http_request:addJWTToken() Entering")
 	defer log.Trace("clients/send_http_request:addJWTToken() Leaving")
 
 	var err error
-	if aasClient.BaseURL == "" {
-		aasClient = aas.NewJWTClient(aasURL)
-		if aasClient.HTTPClient == nil {
-			if len(trustedCaCerts) == 0 {
-				aasClient.HTTPClient = clients.HTTPClientTLSNoVerify()
-			} else {
-				aasClient.HTTPClient, err = clients.HTTPClientWithCA(trustedCaCerts)
-				if err != nil {
-					return errors.Wrap(err, "clients/send_http_request.go:addJWTToken() Error initializing http client")
-				}
-			}
-		}
-	}
-	var jwtToken []byte
-	token, ok := jwtTokenMap.Load(serviceUsername)
-	if forceFetch || !ok {
-		jwtToken, err = fetchJwtToken(aasClient, serviceUsername, servicePassword)
+	var aasHTTPClient *http.Client
+	if len(trustedCaCerts) == 0 {
+		aasHTTPClient = clients.HTTPClientTLSNoVerify()
+	} else {
+		aasHTTPClient, err = clients.HTTPClientWithCA(trustedCaCerts)
 		if err != nil {
-			return errors.Wrap(err, "clients/send_http_request.go:addJWTToken() Could not fetch token")
+			return nil, errors.Wrap(err, "clients/send_http_request.go:addJWTToken() Error initializing http client")
 		}
-		jwtTokenMap.Store(serviceUsername, jwtToken)
-	} else {
-		jwtToken = token.([]byte)
-	}
-	secLog.Debug("clients/send_http_request:addJWTToken() successfully added jwt bearer token")
-	req.Header.Set("Authorization", "Bearer "+string(jwtToken))
-	return nil
-}
-
-func fetchJwtToken(aasClient *aas.JwtClient, serviceUsername string, servicePassword string) ([]byte, error) {
-	log.Trace("clients/send_http_request:fetchJwtToken() Entering")
-	defer log.Trace("clients/send_http_request:fetchJwtToken() Leaving")
-
-	aasClient.AddUser(serviceUsername, servicePassword)
-	err := aasClient.FetchAllTokens()
-	if err != nil {
-		return nil, errors.Wrap(err, "clients/send_http_request.go:fetchJwtToken() Could not fetch token")
 	}
-	jwtToken, err := aasClient.GetUserToken(serviceUsername)
+	jwtToken, err := aas.SharedTokenProvider(aasURL, serviceUsername, servicePassword, aasHTTPClient).Authenticate(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "clients/send_http_request.go:fetchJwtToken() Error retrieving token from cache")
+		return nil, errors.Wrap(err, "clients/send_http_request.go:addJWTToken() Could not fetch token")
 	}
+	secLog.Debug("clients/send_http_request:addJWTToken() successfully added jwt bearer token")
 	return jwtToken, nil
 }
 
+// RequestOptions customize the authentication of a request sent with SendRequestWithOptions
+type RequestOptions struct {
+	// Headers are set on the request along with the AAS bearer token
+	Headers map[string]string
+	// ClientCertificate is presented to servers requesting TLS client authentication
+	ClientCertificate *tls.Certificate
+	// Authenticate is called once the AAS bearer token and the headers are set, it can replace both
+	Authenticate func(req *http.Request) error
+	// Proxy selects the proxy the request is sent through, the request is sent directly when it is nil
+	Proxy clients.ProxyFunc
+}
+
 //SendRequest method is used to create an http client object and send the request to the server
 func SendRequest(req *http.Request, aasURL, serviceUsername, servicePassword string,
 	trustedCaCerts []x509.Certificate) ([]byte, error) {
-	log.Trace("clients/send_http_request:SendRequest() Entering")
-	defer log.Trace("clients/send_http_request:SendRequest() Leaving")
+	return SendRequestWithOptions(req, aasURL, serviceUsername, servicePassword, trustedCaCerts, nil)
+}
+
+//SendRequestWithOptions method sends the request to the server like SendRequest, with the custom authentication of the options
+func SendRequestWithOptions(req *http.Request, aasURL, serviceUsername, servicePassword string,
+	trustedCaCerts []x509.Certificate, options *RequestOptions) ([]byte, error) {
+	log.Trace("clients/send_http_request:SendRequestWithOptions() Entering")
+	defer log.Trace("clients/send_http_request:SendRequestWithOptions() Leaving")
 
 	var err error
+	var client *http.Client
 	//This has to be done for dynamic loading or unloading of certificates
-	var aasClient = aas.NewJWTClient("")
 	if len(trustedCaCerts) == 0 {
-		aasClient.HTTPClient = clients.HTTPClientTLSNoVerify()
+		client = clients.HTTPClientTLSNoVerify()
 	} else {
-		aasClient.HTTPClient, err = clients.HTTPClientWithCA(trustedCaCerts)
+		client, err = clients.HTTPClientWithCA(trustedCaCerts)
 		if err != nil {
-			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequest() Failed to create http client")
+			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Failed to create http client")
 		}
 	}
-	err = addJWTToken(aasClient, req, aasURL, serviceUsername, servicePassword, trustedCaCerts, false)
+	if options != nil && options.ClientCertificate != nil {
+		client.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{*options.ClientCertificate}
+	}
+	if options != nil && options.Proxy != nil {
+		client.Transport.(*http.Transport).Proxy = options.Proxy
+	}
+	jwtToken, err := addJWTToken(req, aasURL, serviceUsername, servicePassword, trustedCaCerts)
+	if err != nil {
+		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Failed to add JWT token")
+	}
+	err = applyRequestOptions(req, options)
 	if err != nil {
-		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequest() Failed to add JWT token")
+		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Failed to authenticate request")
 	}
 
-	log.Debug("clients/send_http_request:SendRequest() AAS client successfully created")
-	response, err := aasClient.HTTPClient.Do(req)
+	log.Debug("clients/send_http_request:SendRequestWithOptions() HTTP client successfully created")
+	response, err := client.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequest() Error from response")
+		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Error from response")
 	}
 	defer func() {
 		derr := response.Body.Close()
@@ -108,30 +108,50 @@ func SendRequest(req *http.Request, aasURL, serviceUsername, servicePassword str
 		}
 	}()
 	if response.StatusCode == http.StatusUnauthorized {
-		// fetch token and try again
-		err = addJWTToken(aasClient, req, aasURL, serviceUsername, servicePassword, trustedCaCerts, true)
+		// the token was rejected, fetch a new one and try again
+		aas.SharedTokenProvider(aasURL, serviceUsername, servicePassword, nil).Invalidate(jwtToken)
+		_, err = addJWTToken(req, aasURL, serviceUsername, servicePassword, trustedCaCerts)
 		if err != nil {
-			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequest() Failed to add JWT token")
+			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Failed to add JWT token")
 		}
-		response, err = aasClient.HTTPClient.Do(req)
+		err = applyRequestOptions(req, options)
 		if err != nil {
-			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequest() Error from response")
+			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Failed to authenticate request")
+		}
+		response, err = client.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Error from response")
 		}
 	}
-	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusNoContent {
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusNoContent &&
+		response.StatusCode != http.StatusAccepted {
 		return nil, errors.Wrap(errors.New("HTTP Status :"+strconv.Itoa(response.StatusCode)),
-			"clients/send_http_request.go:SendRequest() Error from response")
+			"clients/send_http_request.go:SendRequestWithOptions() Error from response")
 	}
 
 	//create byte array of HTTP response body
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequest() Error from response")
+		return nil, errors.Wrap(err, "clients/send_http_request.go:SendRequestWithOptions() Error from response")
 	}
-	log.Debug("clients/send_http_request.go:SendRequest() Received the response successfully")
+	log.Debug("clients/send_http_request.go:SendRequestWithOptions() Received the response successfully")
 	return body, nil
 }
 
+// applyRequestOptions sets the custom headers of the options on the request and runs their authentication
+func applyRequestOptions(req *http.Request, options *RequestOptions) error {
+	if options == nil {
+		return nil
+	}
+	for name, value := range options.Headers {
+		req.Header.Set(name, value)
+	}
+	if options.Authenticate != nil {
+		return options.Authenticate(req)
+	}
+	return nil
+}
+
 //SendNoAuthRequest method is used to create an http client object and send the request to the server
 func SendNoAuthRequest(req *http.Request, trustedCaCerts []x509.Certificate) ([]byte, error) {
 	log.Trace("clients/send_http_request:SendNoAuthRequest() Entering")
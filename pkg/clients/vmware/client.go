@@ -22,14 +22,16 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"net/url"
 	"strings"
+	"sync"
 )
 
 var log = commLog.GetDefaultLogger()
 
+// VMWareClient sends the requests to vCenter, the requests are canceled when their context is done
 type VMWareClient interface {
-	GetHostInfo() (taModel.HostInfo, error)
-	GetTPMAttestationReport() (*types.QueryTpmAttestationReportResponse, error)
-	GetVmwareClusterReference(string) ([]mo.HostSystem, error)
+	GetHostInfo(ctx context.Context) (taModel.HostInfo, error)
+	GetTPMAttestationReport(ctx context.Context) (*types.QueryTpmAttestationReportResponse, error)
+	GetVmwareClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error)
 }
 
 const (
@@ -38,6 +40,13 @@ const (
 )
 
 func NewVMwareClient(vcenterApiUrl *url.URL, vcenterUserName, vcenterPassword, hostName string, trustedCaCerts []x509.Certificate) (VMWareClient, error) {
+	return NewVMwareClientWithProxy(vcenterApiUrl, vcenterUserName, vcenterPassword, hostName, trustedCaCerts, nil)
+}
+
+// NewVMwareClientWithProxy creates a VMWareClient reaching vCenter through the proxy selected by proxy, vCenter is
+// reached directly when it is nil
+func NewVMwareClientWithProxy(vcenterApiUrl *url.URL, vcenterUserName, vcenterPassword, hostName string,
+	trustedCaCerts []x509.Certificate, proxy clients.ProxyFunc) (VMWareClient, error) {
 
 	vmwareClient := vmwareClient{
 		BaseURL:         vcenterApiUrl,
@@ -45,25 +54,12 @@ func NewVMwareClient(vcenterApiUrl *url.URL, vcenterUserName, vcenterPassword, h
 		vCenterUsername: vcenterUserName,
 		vCenterPassword: vcenterPassword,
 		TrustedCaCerts:  trustedCaCerts,
+		Proxy:           proxy,
 	}
 	//Set username and password in the same URL struct
 	vmwareClient.BaseURL.User = url.UserPassword(vmwareClient.vCenterUsername, vmwareClient.vCenterPassword)
 
-	ctx, _ := context.WithCancel(context.Background())
-	vmwareClient.Context = ctx
-	if hostName != "" {
-		host, vCenterClient, err := getVmwareHostReference(&vmwareClient)
-		if err != nil {
-			return nil, errors.Wrap(err, "vmware/client:NewVMwareClient() Error creating Vmware client")
-		}
-		if host.Config == nil {
-			return nil, errors.New("vmware/client:NewVMwareClient() Unable to connect to Vmware host : " + hostName)
-		}
-
-		vmwareClient.hostReference = host
-		vmwareClient.vCenterClient = vCenterClient
-	}
-
+	// the connection to vCenter is made by the first request to the host, under the context of the request
 	return &vmwareClient, nil
 }
 
@@ -73,17 +69,50 @@ type vmwareClient struct {
 	vCenterUsername string
 	vCenterPassword string
 	TrustedCaCerts  []x509.Certificate
-	hostReference   mo.HostSystem
-	vCenterClient   *govmomi.Client
-	Context         context.Context
+	Proxy           clients.ProxyFunc
+
+	// connectMutex guards the host reference and the vCenter client, they are set by the first request to the host
+	connectMutex  sync.Mutex
+	hostReference mo.HostSystem
+	vCenterClient *govmomi.Client
 }
 
-func (vc *vmwareClient) GetHostInfo() (taModel.HostInfo, error) {
+// connect logs in to vCenter and looks up the host, the connection is kept for the next requests to the host
+func (vc *vmwareClient) connect(ctx context.Context) error {
+	log.Trace("vmware/client:connect() Entering ")
+	defer log.Trace("vmware/client:connect() Leaving ")
+
+	vc.connectMutex.Lock()
+	defer vc.connectMutex.Unlock()
+	if vc.vCenterClient != nil {
+		return nil
+	}
+	if vc.HostName == "" {
+		return errors.New("vmware/client:connect() Host name is required to connect to a Vmware host")
+	}
+
+	host, vCenterClient, err := getVmwareHostReference(ctx, vc)
+	if err != nil {
+		return errors.Wrap(err, "vmware/client:connect() Error creating Vmware client")
+	}
+	if host.Config == nil {
+		return errors.New("vmware/client:connect() Unable to connect to Vmware host : " + vc.HostName)
+	}
+	vc.hostReference = host
+	vc.vCenterClient = vCenterClient
+	return nil
+}
+
+func (vc *vmwareClient) GetHostInfo(ctx context.Context) (taModel.HostInfo, error) {
 
 	log.Trace("vmware/client:GetHostInfo() Entering ")
 	defer log.Trace("vmware/client:GetHostInfo() Leaving ")
 	var hostInfo taModel.HostInfo
 
+	if err := vc.connect(ctx); err != nil {
+		return hostInfo, err
+	}
+
 	vcenterVersion := vc.vCenterClient.ServiceContent.About.Version
 	hostInfo.HostName = vc.hostReference.Name
 	hostInfo.VMMName = vc.hostReference.Config.Product.Name
@@ -101,7 +130,7 @@ func (vc *vmwareClient) GetHostInfo() (taModel.HostInfo, error) {
 	}
 	if strings.Contains(vcenterVersion, "6.5") && hostInfo.HardwareFeatures.TPM.Enabled {
 		hostInfo.HardwareFeatures.TPM.Meta.TPMVersion = "1.2"
-		attestationReport, err := vc.GetTPMAttestationReport()
+		attestationReport, err := vc.GetTPMAttestationReport(ctx)
 		if err != nil {
 			return taModel.HostInfo{}, errors.Wrap(err, "vmware/client:GetHostInfo() Error getting attestation"+
 				"report from vcenter api")
@@ -122,42 +151,46 @@ func (vc *vmwareClient) GetHostInfo() (taModel.HostInfo, error) {
 	return hostInfo, nil
 }
 
-func (vc *vmwareClient) GetTPMAttestationReport() (*types.QueryTpmAttestationReportResponse, error) {
+func (vc *vmwareClient) GetTPMAttestationReport(ctx context.Context) (*types.QueryTpmAttestationReportResponse, error) {
 
 	log.Trace("vmware/client:GetTPMAttestationReport() Entering ")
 	defer log.Trace("vmware/client:GetTPMAttestationReport() Leaving ")
 
+	if err := vc.connect(ctx); err != nil {
+		return nil, err
+	}
+
 	query := types.QueryTpmAttestationReport{This: vc.hostReference.Reference()}
-	attestationReport, err := methods.QueryTpmAttestationReport(vc.Context, vc.vCenterClient.RoundTripper, &query)
+	attestationReport, err := methods.QueryTpmAttestationReport(ctx, vc.vCenterClient.RoundTripper, &query)
 	if err != nil {
 		return attestationReport, err
 	}
 	return attestationReport, nil
 }
 
-func getVmwareHostReference(vc *vmwareClient) (mo.HostSystem, *govmomi.Client, error) {
+func getVmwareHostReference(ctx context.Context, vc *vmwareClient) (mo.HostSystem, *govmomi.Client, error) {
 	log.Trace("vmware/client:getVmwareHostReference() Entering ")
 	defer log.Trace("vmware/client:getVmwareHostReference() Leaving ")
 
-	vmwareClient, err := getGovmomiClient(vc)
+	vmwareClient, err := getGovmomiClient(ctx, vc)
 	if err != nil {
 		return mo.HostSystem{}, vmwareClient, err
 	}
 	viewManager := view.NewManager(vmwareClient.Client)
 	defer func() {
-		_, derr := viewManager.Destroy(vc.Context)
+		_, derr := viewManager.Destroy(ctx)
 		if derr != nil {
 			log.WithError(derr).Error("Error destroying context")
 		}
 	}()
-	viewer, err := viewManager.CreateContainerView(vc.Context, vmwareClient.ServiceContent.RootFolder,
+	viewer, err := viewManager.CreateContainerView(ctx, vmwareClient.ServiceContent.RootFolder,
 		[]string{HOST_SYSTEM_PROPERTY}, true)
 	if err != nil {
 		return mo.HostSystem{}, vmwareClient, errors.Wrap(err, "vmware/client:getVmwareHostReference() Error "+
 			"creating container view from client")
 	}
 	defer func() {
-		derr := viewer.Destroy(vc.Context)
+		derr := viewer.Destroy(ctx)
 		if derr != nil {
 			log.WithError(derr).Error("Error destroying context")
 		}
@@ -165,7 +198,7 @@ func getVmwareHostReference(vc *vmwareClient) (mo.HostSystem, *govmomi.Client, e
 
 	var hs []mo.HostSystem
 
-	err = viewer.Retrieve(vc.Context, []string{HOST_SYSTEM_PROPERTY}, []string{"name", "summary", "config",
+	err = viewer.Retrieve(ctx, []string{HOST_SYSTEM_PROPERTY}, []string{"name", "summary", "config",
 		"capability", "hardware", "runtime", "parent"}, &hs)
 	if err != nil {
 		return mo.HostSystem{}, vmwareClient, err
@@ -181,37 +214,37 @@ func getVmwareHostReference(vc *vmwareClient) (mo.HostSystem, *govmomi.Client, e
 		"hostname " + vc.HostName + " found in cluster")
 }
 
-func (vc *vmwareClient) GetVmwareClusterReference(clusterName string) ([]mo.HostSystem, error) {
+func (vc *vmwareClient) GetVmwareClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error) {
 	log.Trace("vmware/client:GetVmwareClusterReference() Entering ")
 	defer log.Trace("vmware/client:GetVmwareClusterReference() Leaving ")
 
-	vmwareClient, err := getGovmomiClient(vc)
+	vmwareClient, err := getGovmomiClient(ctx, vc)
 	if err != nil {
 		return nil, errors.Wrap(err, "vmware/client:getVmwareClusterReference() Error "+
 			"creating vsphere client")
 	}
 	viewManager := view.NewManager(vmwareClient.Client)
 	defer func() {
-		_, derr := viewManager.Destroy(vc.Context)
+		_, derr := viewManager.Destroy(ctx)
 		if derr != nil {
 			log.WithError(derr).Error("Error destroying context")
 		}
 	}()
-	viewer, err := viewManager.CreateContainerView(vc.Context, vmwareClient.ServiceContent.RootFolder,
+	viewer, err := viewManager.CreateContainerView(ctx, vmwareClient.ServiceContent.RootFolder,
 		[]string{CLUSTER_SYSTEM_PROPERTY}, true)
 	if err != nil {
 		return nil, errors.Wrap(err, "vmware/client:getVmwareClusterReference() Error "+
 			"creating container view from client")
 	}
 	defer func() {
-		derr := viewer.Destroy(vc.Context)
+		derr := viewer.Destroy(ctx)
 		if derr != nil {
 			log.WithError(derr).Error("Error destroying context")
 		}
 	}()
 	var ccr []mo.ClusterComputeResource
 
-	err = viewer.Retrieve(vc.Context, []string{CLUSTER_SYSTEM_PROPERTY}, []string{"name", "host"}, &ccr)
+	err = viewer.Retrieve(ctx, []string{CLUSTER_SYSTEM_PROPERTY}, []string{"name", "host"}, &ccr)
 	if err != nil {
 		return nil, errors.Wrap(err, "vmware/client:getVmwareClusterReference() Error "+
 			"getting cluster properties")
@@ -220,7 +253,7 @@ func (vc *vmwareClient) GetVmwareClusterReference(clusterName string) ([]mo.Host
 	var hostInfo []mo.HostSystem
 	for _, cluster := range ccr {
 		if cluster.Name == clusterName {
-			err := vmwareClient.Retrieve(vc.Context, cluster.Host, []string{"name", "summary", "config",
+			err := vmwareClient.Retrieve(ctx, cluster.Host, []string{"name", "summary", "config",
 				"capability", "hardware", "runtime", "parent"}, &hostInfo)
 			if err != nil {
 				return nil, errors.Wrap(err, "vmware/client:getVmwareClusterReference() Error "+
@@ -232,14 +265,17 @@ func (vc *vmwareClient) GetVmwareClusterReference(clusterName string) ([]mo.Host
 	return hostInfo, nil
 }
 
-func getGovmomiClient(vc *vmwareClient) (*govmomi.Client, error) {
+func getGovmomiClient(ctx context.Context, vc *vmwareClient) (*govmomi.Client, error) {
 	log.Trace("vmware/client:getGovmomiClient() Entering ")
 	defer log.Trace("vmware/client:getGovmomiClient() Leaving ")
 
 	soapClient := soap.NewClient(vc.BaseURL, false)
 	soapClient.DefaultTransport().TLSClientConfig.RootCAs = clients.GetCertPool(vc.TrustedCaCerts)
+	if vc.Proxy != nil {
+		soapClient.DefaultTransport().Proxy = vc.Proxy
+	}
 
-	vimClient, err := vim25.NewClient(vc.Context, soapClient)
+	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return &govmomi.Client{}, errors.Wrap(err, "vmware/client:getGovmomiClient() Error "+
 			"creating vim25 client")
@@ -251,7 +287,7 @@ func getGovmomiClient(vc *vmwareClient) (*govmomi.Client, error) {
 
 	// Only login if the URL contains user information.
 	if vc.BaseURL.User != nil || vc.BaseURL.User.String() != "" {
-		err = vmwareClient.Login(vc.Context, vc.BaseURL.User)
+		err = vmwareClient.Login(ctx, vc.BaseURL.User)
 		if err != nil {
 			return vmwareClient, errors.Wrap(err, "vmware/client:getGovmomiClient() Error "+
 				"creating vcenter session")
@@ -7,6 +7,8 @@ package vmware
 //go:generate mockgen -destination=mock_client.go -package=vmware github.com/intel-secl/intel-secl/v3/pkg/lib/clients/vmware VMWareClient
 
 import (
+	"context"
+
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/stretchr/testify/mock"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -22,17 +24,17 @@ func NewMockVMWareClient() (*MockVMWareClient, error) {
 	return &mockVMWareClient, nil
 }
 
-func (vm *MockVMWareClient) GetHostInfo() (taModel.HostInfo, error) {
+func (vm *MockVMWareClient) GetHostInfo(ctx context.Context) (taModel.HostInfo, error) {
 	args := vm.Called()
 	return args.Get(0).(taModel.HostInfo), args.Error(1)
 }
 
-func (vm *MockVMWareClient) GetTPMAttestationReport() (*types.QueryTpmAttestationReportResponse, error) {
+func (vm *MockVMWareClient) GetTPMAttestationReport(ctx context.Context) (*types.QueryTpmAttestationReportResponse, error) {
 	args := vm.Called()
 	return args.Get(0).(*types.QueryTpmAttestationReportResponse), args.Error(1)
 }
 
-func (vm *MockVMWareClient) GetVmwareClusterReference(string) ([]mo.HostSystem, error) {
+func (vm *MockVMWareClient) GetVmwareClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error) {
 	args := vm.Called()
 	return args.Get(0).([]mo.HostSystem), args.Error(1)
 }
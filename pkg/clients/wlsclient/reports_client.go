@@ -7,7 +7,9 @@ package wlsclient
 import (
 	"bytes"
 	"crypto/x509"
+	"encoding/json"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/util"
+	wlsModel "github.com/intel-secl/intel-secl/v3/pkg/model/wls"
 	"net/http"
 	"net/url"
 	"path"
@@ -17,6 +19,7 @@ import (
 
 type ReportsClient interface {
 	PostVMReport([]byte) error
+	GetInstanceTrustReport(instanceID string) (wlsModel.SignedInstanceTrustReport, error)
 }
 
 type reportsClientImpl struct {
@@ -55,3 +58,39 @@ func (client reportsClientImpl) PostVMReport(report []byte) error {
 
 	return nil
 }
+
+// GetInstanceTrustReport method is used to get the signed trust report of a workload instance from the workload
+// service, the report combines the trust of the host of the instance with the measurements of the instance
+func (client reportsClientImpl) GetInstanceTrustReport(instanceID string) (wlsModel.SignedInstanceTrustReport, error) {
+	log.Trace("wlsclient/reports_client:GetInstanceTrustReport() Entering")
+	defer log.Trace("wlsclient/reports_client:GetInstanceTrustReport() Leaving")
+
+	var report wlsModel.SignedInstanceTrustReport
+	requestURL, err := url.Parse(client.cfg.BaseURL)
+	if err != nil {
+		return report, errors.New("wlsclient/reports_client:GetInstanceTrustReport() error retrieving WLS API URL")
+	}
+	requestURL.Path = path.Join(requestURL.Path, "instances/"+instanceID+"/trust-report")
+
+	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	if err != nil {
+		return report, errors.Wrap(err, "wlsclient/reports_client:GetInstanceTrustReport() Failed to create WLS GET API request for instance trust report")
+	}
+
+	log.Debugf("wlsclient/reports_client:GetInstanceTrustReport() WLS instance trust report GET request URL: %s", requestURL.String())
+	httpRequest.Header.Set("Accept", "application/json")
+
+	httpResponse, err := util.SendRequest(httpRequest, client.cfg.AasApiURL, client.cfg.Username, client.cfg.Password, client.caCerts)
+	if err != nil {
+		return report, errors.Wrap(err, "wlsclient/reports_client:GetInstanceTrustReport() Error while getting response for Get WLS instance trust report API")
+	}
+
+	if httpResponse != nil {
+		err = json.Unmarshal(httpResponse, &report)
+		if err != nil {
+			return report, errors.Wrap(err, "wlsclient/reports_client:GetInstanceTrustReport() Failed to unmarshal response into instance trust report")
+		}
+	}
+	log.Debug("wlsclient/reports_client:GetInstanceTrustReport() Successfully retrieved instance trust report")
+	return report, nil
+}
@@ -7,24 +7,19 @@ package cms
 import (
 	"fmt"
 	"github.com/intel-secl/intel-secl/v3/pkg/cms/config"
-	"github.com/intel-secl/intel-secl/v3/pkg/cms/constants"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"io"
 	"os"
 	"os/exec"
-	"time"
 
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogInt "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/setup"
 	"github.com/pkg/errors"
 )
 
-var errInvalidCmd = errors.New("Invalid input after command")
-
 type App struct {
 	HomeDir        string
 	ConfigDir      string
@@ -117,78 +112,12 @@ func (a *App) configureLogs(isStdOut bool, isFileOut bool) error {
 
 func (a *App) Run(args []string) error {
 
-	if len(args) < 2 {
-		a.printUsage()
-		return nil
-	}
-	cmd := args[1]
-	switch cmd {
-	default:
-		err := errors.New("Invalid command: " + cmd)
-		a.printUsageWithError(err)
-		return err
-	case "tlscertsha384":
-		hash, err := crypt.GetCertHexSha384(constants.TLSCertPath)
-		if err != nil {
-			return errors.Wrap(err, "app:Run() Could not derive tls certificate digest")
-		}
-		fmt.Println(hash)
-		return nil
-	case "run":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		if err := a.startServer(); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: daemon did not start - ", err.Error())
-			// wait some time for logs to flush - otherwise, there will be no entry in syslog
-			time.Sleep(10 * time.Millisecond)
-			return errors.Wrap(err, "app:Run() Error starting CMS service")
-		}
-	case "help", "-h", "--help":
-		a.printUsage()
-		return nil
-	case "start":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.start()
-	case "stop":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.stop()
-	case "status":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.status()
-	case "uninstall":
-		// the only allowed flag is --purge
-		purge := false
-		if len(args) == 3 {
-			if args[2] != "--purge" {
-				return errors.New("Invalid flag: " + args[2])
-			}
-			purge = true
-		} else if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.uninstall(purge)
-	case "version", "--version", "-v":
-		a.printVersion()
-		return nil
-	case "setup":
-		if err := a.setup(args[1:]); err != nil {
-			if errors.Cause(err) == setup.ErrTaskNotFound {
-				a.printUsageWithError(err)
-			} else {
-				fmt.Fprintln(a.errorWriter(), err.Error())
-			}
-			return err
-		}
-
+	runner := &commCmd.Runner{
+		Root:          a.commands(),
+		ConsoleWriter: a.consoleWriter(),
+		PrintUsage:    a.printUsageWithError,
 	}
-	return nil
+	return runner.Run(args)
 }
 
 func (a *App) start() error {
@@ -217,7 +146,14 @@ func (a *App) stop() error {
 	return cmd.Run()
 }
 
-func (a *App) status() error {
+func (a *App) status(ctx *commCmd.Context) error {
+	if ctx.JSONOutput() {
+		status, err := commCmd.GetServiceStatus("cms")
+		if err != nil {
+			return errors.Wrap(err, "app:status() Could not read status of application service")
+		}
+		return ctx.Print("", status)
+	}
 	fmt.Fprintln(a.consoleWriter(), `Forwarding to "systemctl status cms"`)
 	systemctl, err := exec.LookPath("systemctl")
 	if err != nil {
@@ -0,0 +1,79 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cms
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/cms/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+// setupTaskNames are completed after the setup command
+var setupTaskNames = []string{"all", "root-ca", "intermediate-ca", "tls", "cms-auth-token", "update-service-config"}
+
+// commands lists the commands of the cms CLI
+func (a *App) commands() *commCmd.Cmd {
+	return &commCmd.Cmd{
+		Name:  constants.ServiceUserName,
+		Flags: []commCmd.CmdFlag{commCmd.OutputFlag(constants.ServiceName)},
+		SubCmd: []commCmd.Cmd{
+			{Name: "help", Aliases: []string{"-h", "--help"}, Exec: func(ctx *commCmd.Context) error {
+				a.printUsage()
+				return nil
+			}},
+			{Name: "version", Aliases: []string{"-v", "--version"}, Exec: a.printVersion},
+			{Name: "tlscertsha384", Exec: a.printTLSCertDigest},
+			{Name: "run", Exec: func(ctx *commCmd.Context) error {
+				if err := a.startServer(); err != nil {
+					fmt.Fprintln(os.Stderr, "Error: daemon did not start - ", err.Error())
+					// wait some time for logs to flush - otherwise, there will be no entry in syslog
+					time.Sleep(10 * time.Millisecond)
+					return errors.Wrap(err, "app:Run() Error starting CMS service")
+				}
+				return nil
+			}},
+			{Name: "start", Exec: func(ctx *commCmd.Context) error {
+				return a.start()
+			}},
+			{Name: "stop", Exec: func(ctx *commCmd.Context) error {
+				return a.stop()
+			}},
+			{Name: "status", Exec: a.status},
+			{Name: "uninstall", Flags: []commCmd.CmdFlag{{Name: "purge", Bool: true}}, Exec: func(ctx *commCmd.Context) error {
+				return a.uninstall(ctx.Bool("purge"))
+			}},
+			{Name: "setup", PassThrough: true, Completions: setupTaskNames,
+				Flags: []commCmd.CmdFlag{{Name: "help"}, {Name: "force"}, {Name: "file", Short: "f"}, {Name: "interactive"},
+					{Name: "fix-drift"}},
+				Exec: func(ctx *commCmd.Context) error {
+					if err := a.setup(ctx.Args); err != nil {
+						if errors.Cause(err) == setup.ErrTaskNotFound {
+							a.printUsageWithError(err)
+						} else {
+							fmt.Fprintln(a.errorWriter(), err.Error())
+						}
+						return err
+					}
+					return nil
+				}},
+			commCmd.CompletionCmd,
+		},
+	}
+}
+
+func (a *App) printTLSCertDigest(ctx *commCmd.Context) error {
+	hash, err := crypt.GetCertHexSha384(constants.TLSCertPath)
+	if err != nil {
+		return errors.Wrap(err, "app:printTLSCertDigest() Could not derive tls certificate digest")
+	}
+	return ctx.Print(hash+"\n", map[string]string{"tls_cert_sha384": hash})
+}
@@ -26,6 +26,10 @@ type Configuration struct {
 	AasJwtCn          string                  `yaml:"aas-jwt-cn" mapstructure:"aas-jwt-cn"`
 	AasTlsCn          string                  `yaml:"aas-tls-cn" mapstructure:"aas-tls-cn"`
 	AasTlsSan         string                  `yaml:"aas-tls-san" mapstructure:"aas-tls-san"`
+
+	// FipsMode restricts the service to the FIPS approved algorithms and TLS cipher suites, it is always enabled
+	// in the binaries built with the fips tag
+	FipsMode bool `yaml:"fips-mode" mapstructure:"fips-mode"`
 }
 
 type CACertConfig struct {
@@ -52,6 +52,8 @@ const (
 	DefaultReadHeaderTimeout       = 10 * time.Second
 	DefaultWriteTimeout            = 10 * time.Second
 	DefaultIdleTimeout             = 10 * time.Second
+	DefaultDrainTimeout            = 30 * time.Second
+	DefaultBodyReadTimeout         = 20 * time.Second
 	DefaultMaxHeaderBytes          = 1 << 20
 	DefaultLogEntryMaxlength       = 300
 )
@@ -31,6 +31,8 @@ func init() {
 	viper.SetDefault("server-read-header-timeout", constants.DefaultReadHeaderTimeout)
 	viper.SetDefault("server-write-timeout", constants.DefaultWriteTimeout)
 	viper.SetDefault("server-idle-timeout", constants.DefaultIdleTimeout)
+	viper.SetDefault("server-drain-timeout", constants.DefaultDrainTimeout)
+	viper.SetDefault("server-body-read-timeout", constants.DefaultBodyReadTimeout)
 	viper.SetDefault("server-max-header-bytes", constants.DefaultMaxHeaderBytes)
 
 	viper.SetDefault("cms-ca-cert-validity", constants.DefaultCACertValidity)
@@ -67,6 +69,7 @@ func defaultConfig() *config.Configuration {
 		AasTlsSan:         viper.GetString("aas-tls-san"),
 		TlsSanList:        viper.GetString("san-list"),
 		TokenDurationMins: viper.GetInt("token-duration-mins"),
+		FipsMode:          viper.GetBool("fips-mode"),
 	}
 }
 
@@ -77,6 +80,9 @@ func loadAlias() {
 		"server-read-header-timeout": "CMS_SERVER_READ_HEADER_TIMEOUT",
 		"server-write-timeout":       "CMS_SERVER_WRITE_TIMEOUT",
 		"server-idle-timeout":        "CMS_SERVER_IDLE_TIMEOUT",
+		"server-drain-timeout":       "CMS_SERVER_DRAIN_TIMEOUT",
+		"server-body-read-timeout":   "CMS_SERVER_BODY_READ_TIMEOUT",
+		"server-max-response-bytes":  "CMS_SERVER_MAX_RESPONSE_BYTES",
 		"server-max-header-bytes":    "CMS_SERVER_MAX_HEADER_BYTES",
 		"log-enable-stdout":          "CMS_ENABLE_CONSOLE_LOG",
 		"aas-base-url":               "AAS_API_URL",
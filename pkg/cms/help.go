@@ -7,7 +7,10 @@ package cms
 
 import (
 	"fmt"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/cms/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/cms/version"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 )
 
 const helpStr = `
@@ -23,12 +26,23 @@ Available Commands:
     tlscertsha384                  Show the SHA384 digest of the certificate used for TLS
     uninstall [--purge]            Uninstall cms. --purge option needs to be applied to remove configuration and data files
     -v|--version | version         Show the version of cms
+    completion <bash|zsh>          Print the shell completion script, load it with: source <(cms completion bash)
+
+Global Flags:
+    --output <text|json>           output format of the version, status and tlscertsha384 commands (env: CMS_OUTPUT)
 
 Usage of cms setup:
-	cms setup <task> [--help] [--force] [-f <answer-file>]
+	cms setup <task> [--help] [--force] [-f <answer-file>] [--interactive] [--fix-drift]
+	cms setup --interactive
+	cms setup --fix-drift
 		--help                      show help message for setup task
 		--force                     existing configuration will be overwritten if this flag is set
 		-f|--file <answer-file>     the answer file with required arguments
+		--interactive               prompt for the required arguments not in the answer file or environment,
+		                            all setup tasks are run if no task is given
+		--fix-drift                 run again the tasks whose configuration has drifted, like expired or
+		                            reissued certificates or missing database tables, all tasks are checked
+		                            if no task is given
 
 Available Tasks for setup:
     all                       Runs all setup tasks
@@ -48,6 +62,11 @@ func (a *App) printUsageWithError(err error) {
 	fmt.Fprintln(a.errorWriter(), helpStr)
 }
 
-func (a *App) printVersion() {
-	fmt.Fprintf(a.consoleWriter(), version.GetVersion())
+func (a *App) printVersion(ctx *commCmd.Context) error {
+	return ctx.Print(version.GetVersion(), commCmd.VersionInfo{
+		ServiceName: constants.ExplicitServiceName,
+		Version:     version.Version,
+		GitHash:     version.GitHash,
+		BuildDate:   version.BuildDate,
+	})
 }
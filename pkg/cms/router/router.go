@@ -5,7 +5,6 @@
 package router
 
 import (
-	"crypto/tls"
 	"crypto/x509"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/cms/config"
@@ -36,6 +35,10 @@ func InitRoutes(cfg *config.Configuration) *mux.Router {
 	router := mux.NewRouter()
 
 	router.SkipClean(true)
+
+	// Reject oversized, compressed and slowly sent request bodies before they reach any handler and bound the
+	// size of the responses
+	router.Use(middleware.NewRequestLimits(cfg.Server))
 	defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg)
 	return router
 }
@@ -90,13 +93,11 @@ func (r *Router) fnGetJwtCerts() error {
 			return err
 		}
 	}
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.RootCAs = rootCAs
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: false,
-				RootCAs:            rootCAs,
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 
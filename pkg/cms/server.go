@@ -6,16 +6,16 @@ package cms
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/intel-secl/intel-secl/v3/pkg/cms/router"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/pkg/errors"
 
@@ -41,17 +41,13 @@ func (a *App) startServer() error {
 	if err := a.configureLogs(c.Log.EnableStdout, true); err != nil {
 		return err
 	}
+	crypt.SetFipsMode(c.FipsMode)
+	defaultLog.Infof("app:startServer() FIPS mode enabled: %t", crypt.FipsModeEnabled())
 
 	// Initialize routes
 	routes := router.InitRoutes(c)
 
-	tlsconfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
-	}
+	tlsconfig := crypt.TLSConfig()
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -67,6 +63,7 @@ func (a *App) startServer() error {
 		WriteTimeout:      c.Server.WriteTimeout,
 		IdleTimeout:       c.Server.IdleTimeout,
 		MaxHeaderBytes:    c.Server.MaxHeaderBytes,
+		ConnContext:       cmw.ConnContext,
 	}
 
 	tlsCert := constants.TLSCertPath
@@ -82,7 +79,12 @@ func (a *App) startServer() error {
 
 	slog.Info(message.ServiceStart)
 	<-stop
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// the requests in progress are drained within the drain timeout
+	drainTimeout := c.Server.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = constants.DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 	if err := h.Shutdown(ctx); err != nil {
 		return errors.Wrap(err, "app:startServer() Failed to gracefully shutdown webserver")
@@ -0,0 +1,20 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cms
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/spf13/viper"
+)
+
+// setupPrompts lists the settings prompted for by the interactive setup, in the order of the setup tasks
+func setupPrompts() []setup.Prompt {
+	return []setup.Prompt{
+		{EnvName: "AAS_TLS_SAN", Description: "Subject alternative names of the AAS TLS certificate",
+			Default: viper.GetString("aas-tls-san"), Tasks: []string{"cms-auth-token"}},
+		{EnvName: "AAS_API_URL", Description: "AAS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"update-service-config"}},
+	}
+}
@@ -36,6 +36,9 @@ var envHelp = map[string]string{
 	"SERVER_READ_HEADER_TIMEOUT": "Request Read Header Timeout Duration in Seconds",
 	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
+	"SERVER_DRAIN_TIMEOUT":       "Maximum Duration to Drain the In-Flight Requests on Shutdown",
+	"SERVER_BODY_READ_TIMEOUT":   "Maximum Duration to Receive a Request Body",
+	"SERVER_MAX_RESPONSE_BYTES":  "Max Length Of Response Body in Bytes, 0 for no limit",
 	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes",
 }
 
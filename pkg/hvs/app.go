@@ -11,18 +11,15 @@ import (
 	"os/exec"
 
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/config"
-	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	commLogInt "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/setup"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-var errInvalidCmd = errors.New("Invalid input after command")
-
 type App struct {
 	HomeDir        string
 	ConfigDir      string
@@ -46,76 +43,12 @@ func (a *App) Run(args []string) error {
 			defaultLog.Errorf("Panic occurred: %+v", err)
 		}
 	}()
-	if len(args) < 2 {
-		err := errors.New("Invalid usage of " + constants.ServiceName)
-		a.printUsageWithError(err)
-		return err
+	runner := &commCmd.Runner{
+		Root:          a.commands(),
+		ConsoleWriter: a.consoleWriter(),
+		PrintUsage:    a.printUsageWithError,
 	}
-	cmd := args[1]
-	switch cmd {
-	default:
-		err := errors.New("Invalid command: " + cmd)
-		a.printUsageWithError(err)
-		return err
-	case "help", "-h", "--help":
-		a.printUsage()
-		return nil
-	case "version", "--version", "-v":
-		a.printVersion()
-		return nil
-	case "run":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.startServer()
-	case "start":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.start()
-	case "stop":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.stop()
-	case "status":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.status()
-	case "erase-data":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.eraseData()
-	case "config-db-rotation":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.configDBRotation()
-	case "uninstall":
-		// the only allowed flag is --purge
-		purge := false
-		if len(args) == 3 {
-			if args[2] != "--purge" {
-				return errors.New("Invalid flag: " + args[2])
-			}
-			purge = true
-		} else if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return a.uninstall(purge)
-	case "setup":
-		if err := a.setup(args[1:]); err != nil {
-			if errors.Cause(err) == setup.ErrTaskNotFound {
-				a.printUsageWithError(err)
-			} else {
-				fmt.Fprintln(a.errorWriter(), err.Error())
-			}
-			return err
-		}
-	}
-	return nil
+	return runner.Run(args)
 }
 
 func (a *App) consoleWriter() io.Writer {
@@ -176,7 +109,9 @@ func (a *App) configureLogs(stdOut, logFile bool) error {
 			ioWriterDefault = os.Stdout
 		}
 	}
-	ioWriterSecurity := io.MultiWriter(ioWriterDefault, a.secLogWriter())
+	// the entries are sanitized so that user controlled values cannot forge entries and secrets are not logged
+	ioWriterSecurity := commLog.NewSanitizingWriter(io.MultiWriter(ioWriterDefault, a.secLogWriter()))
+	ioWriterDefault = commLog.NewSanitizingWriter(ioWriterDefault)
 
 	logConfig := a.Config.Log
 	lv, err := logrus.ParseLevel(logConfig.Level)
@@ -218,7 +153,14 @@ func (a *App) stop() error {
 	return cmd.Run()
 }
 
-func (a *App) status() error {
+func (a *App) status(ctx *commCmd.Context) error {
+	if ctx.JSONOutput() {
+		status, err := commCmd.GetServiceStatus("hvs")
+		if err != nil {
+			return err
+		}
+		return ctx.Print("", status)
+	}
 	fmt.Fprintln(a.consoleWriter(), `Forwarding to "systemctl status hvs"`)
 	systemctl, err := exec.LookPath("systemctl")
 	if err != nil {
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"fmt"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+// setupTaskNames are completed after the setup command
+var setupTaskNames = []string{"all", "database", "create-default-flavorgroup", "create-dek", "download-ca-cert",
+	"download-cert-tls", "download-cert-saml", "download-cert-flavor-signing", "create-endorsement-ca",
+	"create-privacy-ca", "create-tag-ca", "update-service-config"}
+
+// commands lists the commands of the hvs CLI
+func (a *App) commands() *commCmd.Cmd {
+	return &commCmd.Cmd{
+		Name:  constants.ServiceUserName,
+		Flags: []commCmd.CmdFlag{commCmd.OutputFlag(constants.ServiceName)},
+		SubCmd: []commCmd.Cmd{
+			{Name: "help", Aliases: []string{"-h", "--help"}, Exec: func(ctx *commCmd.Context) error {
+				a.printUsage()
+				return nil
+			}},
+			{Name: "version", Aliases: []string{"-v", "--version"}, Exec: a.printVersion},
+			{Name: "run", Flags: []commCmd.CmdFlag{{Name: "standalone", Bool: true, DefInEnv: true, Env: "HVS_STANDALONE"}},
+				Exec: func(ctx *commCmd.Context) error {
+					return a.startServer(ctx.Bool("standalone"))
+				}},
+			{Name: "start", Exec: func(ctx *commCmd.Context) error {
+				return a.start()
+			}},
+			{Name: "stop", Exec: func(ctx *commCmd.Context) error {
+				return a.stop()
+			}},
+			{Name: "status", Exec: a.status},
+			{Name: "erase-data", Exec: func(ctx *commCmd.Context) error {
+				return a.eraseData()
+			}},
+			{Name: "config-db-rotation", Exec: func(ctx *commCmd.Context) error {
+				return a.configDBRotation()
+			}},
+			{Name: "uninstall", Flags: []commCmd.CmdFlag{{Name: "purge", Bool: true}}, Exec: func(ctx *commCmd.Context) error {
+				return a.uninstall(ctx.Bool("purge"))
+			}},
+			{Name: "setup", PassThrough: true, Completions: setupTaskNames,
+				Flags: []commCmd.CmdFlag{{Name: "help"}, {Name: "force"}, {Name: "file", Short: "f"}, {Name: "interactive"},
+					{Name: "fix-drift"}},
+				Exec: func(ctx *commCmd.Context) error {
+					if err := a.setup(ctx.Args); err != nil {
+						if errors.Cause(err) == setup.ErrTaskNotFound {
+							a.printUsageWithError(err)
+						} else {
+							fmt.Fprintln(a.errorWriter(), err.Error())
+						}
+						return err
+					}
+					return nil
+				}},
+			commCmd.CompletionCmd,
+		},
+	}
+}
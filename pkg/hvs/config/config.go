@@ -12,6 +12,8 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hrrs"
 	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
+	fm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
@@ -42,6 +44,23 @@ type Configuration struct {
 	HRRS   hrrs.HRRSConfig         `yaml:"hrrs" mapstructure:"hrrs"`
 	FVS    FVSConfig               `yaml:"fvs" mapstructure:"fvs"`
 	VCSS   VCSSConfig              `yaml:"vcss" mapstructure:"vcss"`
+	HPRS   HPRSConfig              `yaml:"hprs" mapstructure:"hprs"`
+
+	HostConnector HostConnectorConfig `yaml:"host-connector" mapstructure:"host-connector"`
+
+	// HardwareUuidCollisionPolicy is applied when a host is registered with the hardware UUID of a registered host:
+	// reject, auto-suffix or merge
+	HardwareUuidCollisionPolicy string `yaml:"hardware-uuid-collision-policy" mapstructure:"hardware-uuid-collision-policy"`
+
+	// FlavorMetadataSchema defines the custom metadata fields operators can set on flavors
+	FlavorMetadataSchema fm.MetadataSchema `yaml:"flavor-metadata-schema" mapstructure:"flavor-metadata-schema"`
+
+	// QuoteCallbackAuth selects the authentication of the quotes pushed by the trust agents to the quote callbacks
+	QuoteCallbackAuth commConfig.RouteAuthConfig `yaml:"quote-callback-auth" mapstructure:"quote-callback-auth"`
+
+	// FipsMode restricts the service to the FIPS approved algorithms and TLS cipher suites, it is always enabled
+	// in the binaries built with the fips tag
+	FipsMode bool `yaml:"fips-mode" mapstructure:"fips-mode"`
 }
 
 type FVSConfig struct {
@@ -49,12 +68,64 @@ type FVSConfig struct {
 	NumberOfDataFetchers            int  `yaml:"number-of-data-fetchers" mapstructure:"number-of-data-fetchers"`
 	SkipFlavorSignatureVerification bool `yaml:"skip-flavor-signature-verification" mapstructure:"skip-flavor-signature-verification"`
 	HostTrustCacheThreshold         int  `yaml:"host-trust-cache-threshold" mapstructure:"host-trust-cache-threshold"`
+	// AttestationLatencyBudget is the end to end latency above which the stage breakdown of a report is logged, zero disables it
+	AttestationLatencyBudget time.Duration `yaml:"attestation-latency-budget" mapstructure:"attestation-latency-budget"`
+	// AsyncQuoteCallbackURL is the quote-callbacks url of HVS the trust agents post the quotes to, when set the
+	// quotes are collected asynchronously
+	AsyncQuoteCallbackURL string        `yaml:"async-quote-callback-url" mapstructure:"async-quote-callback-url"`
+	AsyncQuoteTimeout     time.Duration `yaml:"async-quote-timeout" mapstructure:"async-quote-timeout"`
+	// DecisionLogFile is the file the decision log of every flavor verification is appended to, for the replayed
+	// audits of the verifier-replay tool, empty disables it
+	DecisionLogFile string `yaml:"decision-log-file" mapstructure:"decision-log-file"`
+	// FlavorSignatureQuorum is the number of the trusted flavor signers, HVS and the co-signers, that must have
+	// signed a flavor for it to be trusted
+	FlavorSignatureQuorum int `yaml:"flavor-signature-quorum" mapstructure:"flavor-signature-quorum"`
+	// MaxQuoteAge is the maximum age of a host quote, compensated for the skew of the host clock, zero disables it.
+	// ClockSkewThreshold is the skew of a host clock above which a warning fault is reported, zero disables it.
+	MaxQuoteAge        time.Duration `yaml:"max-quote-age" mapstructure:"max-quote-age"`
+	ClockSkewThreshold time.Duration `yaml:"clock-skew-threshold" mapstructure:"clock-skew-threshold"`
+	// RevocationCheck enables the CRL checks of the AIK and flavor signing certificates, the CRLs are cached for
+	// CrlCacheTime at most. OcspCheck queries the OCSP responders first and falls back to the CRLs.
+	RevocationCheck bool          `yaml:"revocation-check" mapstructure:"revocation-check"`
+	CrlCacheTime    time.Duration `yaml:"crl-cache-time" mapstructure:"crl-cache-time"`
+	OcspCheck       bool          `yaml:"ocsp-check" mapstructure:"ocsp-check"`
+	// QueueVisibilityTimeout is the time after which the flavor verifications that were started but did not
+	// complete are queued again, zero disables it
+	QueueVisibilityTimeout time.Duration `yaml:"queue-visibility-timeout" mapstructure:"queue-visibility-timeout"`
+	// TrustScore enables the trust scores of the reports, weighted by rule severity and flavor part
+	TrustScore *hvs.TrustScoreWeights `yaml:"trust-score" mapstructure:"trust-score"`
+}
+
+// HostConnectorConfig customizes the authentication of the requests sent to the trust agents, for agents fronted by
+// an API gateway or another auth layer of their own
+type HostConnectorConfig struct {
+	// CustomHeaders are added to every request sent to the trust agents
+	CustomHeaders map[string]string `yaml:"custom-headers" mapstructure:"custom-headers"`
+	// AuthPlugin is the name of a registered trust agent auth plugin, it is set up with the AuthPluginConfig settings
+	AuthPlugin       string            `yaml:"auth-plugin" mapstructure:"auth-plugin"`
+	AuthPluginConfig map[string]string `yaml:"auth-plugin-config" mapstructure:"auth-plugin-config"`
+	// SshKnownHostsFile holds the keys of the hosts registered with ssh:// connection strings, constants.SshKnownHostsFile
+	// when it is not set
+	SshKnownHostsFile string `yaml:"ssh-known-hosts-file" mapstructure:"ssh-known-hosts-file"`
+	// SshTagent is the command the trust agent CLI is run with on the hosts reached over ssh, e.g. "sudo tagent"
+	SshTagent string `yaml:"ssh-tagent" mapstructure:"ssh-tagent"`
+	// BmcUsername and BmcPassword authenticate the Redfish requests to the BMCs of the hosts registered with a
+	// bmc=https://bmc.ip.com option in their connection string
+	BmcUsername string `yaml:"bmc-username" mapstructure:"bmc-username"`
+	BmcPassword string `yaml:"bmc-password" mapstructure:"bmc-password"`
+	// CallTimeout bounds every call of the host connectors to the trust agents and vCenter, the calls are also
+	// canceled when the request of HVS they are made for is done
+	CallTimeout time.Duration `yaml:"call-timeout" mapstructure:"call-timeout"`
+	// Proxies are the proxies the connectors reach the hosts through, by vendor: intel, microsoft or vmware
+	Proxies map[string]commConfig.ProxyConfig `yaml:"proxies" mapstructure:"proxies"`
 }
 
 type SAMLConfig struct {
 	CommonConfig    commConfig.SigningCertConfig `yaml:"common" mapstructure:"common"`
 	Issuer          string                       `yaml:"issuer" mapstructure:"issuer"`
 	ValiditySeconds int                          `yaml:"validity-seconds" mapstructure:"validity-seconds"`
+	// KeyURI references the signing key when it is not the key file, e.g. a key in a PKCS#11 token or the TPM
+	KeyURI string `yaml:"key-uri,omitempty" mapstructure:"key-uri"`
 }
 
 type AuditLogConfig struct {
@@ -68,6 +139,11 @@ type VCSSConfig struct {
 	RefreshPeriod time.Duration `yaml:"refresh-period" mapstructure:"refresh-period"`
 }
 
+type HPRSConfig struct {
+	// ProbePeriod determines how frequently the HPRS connects to the pre-registered hosts
+	ProbePeriod time.Duration `yaml:"probe-period" mapstructure:"probe-period"`
+}
+
 // this function sets the configure file name and type
 func init() {
 	viper.SetConfigName(constants.ConfigFile)
@@ -18,6 +18,7 @@ const (
 	ServiceDir          = "hvs/"
 	OldServiceName      = "mtwilson"
 	ApiVersion          = "/v2"
+	ApiVersionV3        = "/v3"
 	ServiceUserName     = "hvs"
 
 	// Timestamp operations
@@ -50,6 +51,9 @@ const (
 
 	TrustedKeysDir = ConfigDir + "trusted-keys/"
 
+	// keys of the hosts reached over ssh
+	SshKnownHostsFile = ConfigDir + "ssh_known_hosts"
+
 	// saml key and cert
 	SAMLCertFile = TrustedCaCertsDir + "saml-cert.pem"
 	SAMLKeyFile  = TrustedKeysDir + "saml.key"
@@ -58,6 +62,9 @@ const (
 	FlavorSigningCertFile = TrustedCaCertsDir + "flavor-signing.pem"
 	FlavorSigningKeyFile  = TrustedKeysDir + "flavor-signing.key"
 
+	// certificates of the other trusted flavor signers
+	FlavorCoSigningCertsDir = TrustedCaCertsDir + "flavor-co-signing/"
+
 	// privacy ca key and cert
 	PrivacyCACertFile = TrustedCaCertsDir + "privacy-ca/privacy-ca-cert.pem"
 	PrivacyCAKeyFile  = TrustedKeysDir + "privacy-ca.key"
@@ -69,6 +76,12 @@ const (
 	SelfEndorsementCACertFile = EndorsementCACertDir + "EndorsementCA.pem"          //Self signed ECA
 	EndorsementCAKeyFile      = TrustedKeysDir + "endorsement-ca.key"
 
+	// platform certificate and IDevID issuing CAs
+	PlatformCACertDir = ConfigDir + "certs/platform/"
+
+	// reference integrity manifest signer issuing CAs
+	RimCACertDir = ConfigDir + "certs/rim/"
+
 	TagCACertFile = TrustedCaCertsDir + "tag-ca-cert.pem"
 	TagCAKeyFile  = TrustedKeysDir + "tag-ca.key"
 
@@ -121,12 +134,21 @@ const (
 	DefaultReadHeaderTimeout = 10 * time.Second
 	DefaultWriteTimeout      = 30 * time.Second
 	DefaultIdleTimeout       = 10 * time.Second
+	DefaultDrainTimeout      = 30 * time.Second
+	DefaultBodyReadTimeout   = 20 * time.Second
 	DefaultMaxHeaderBytes    = 1 << 20
+	DefaultMaxBodyBytes      = 1 << 22
 )
 
 // db constants
 const (
 	DBTypePostgres = "postgres"
+	DBTypeMySQL    = "mysql"
+	// DBTypeSQLite is the database of the standalone mode
+	DBTypeSQLite = "sqlite3"
+
+	// StandaloneDBFile is the SQLite database file of the standalone mode
+	StandaloneDBFile = HomeDir + "hvs.db"
 
 	DefaultDbConnRetryAttempts  = 4
 	DefaultDbConnRetryTime      = 1
@@ -145,6 +167,16 @@ const (
 	DefaultLogEntryMaxlength = 1500
 )
 
+// v3 API constants, the collections are paginated and the POST responses are replayed for the same idempotency key
+const (
+	DefaultPageLimit         = 100
+	MaxPageLimit             = 1000
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotencyKeyExpiry     = time.Duration(24) * time.Hour
+	IdempotencyKeyMaxLength  = 255
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 // jwt constants
 const (
 	JWTCertsCacheTime = "1m"
@@ -156,6 +188,16 @@ const (
 	DefaultFvsNumberOfDataFetchers         = 20
 	DefaultSkipFlavorSignatureVerification = false
 	DefaultHostTrustCacheThreshold         = 100000
+	DefaultAttestationLatencyBudget        = time.Duration(0)
+	DefaultAsyncQuoteTimeout               = time.Duration(2) * time.Minute
+	DefaultClockSkewThreshold              = time.Duration(5) * time.Minute
+	DefaultCrlCacheTime                    = time.Duration(1) * time.Hour
+	DefaultQueueVisibilityTimeout          = time.Duration(10) * time.Minute
+)
+
+// host connector constants
+const (
+	DefaultHostConnectorCallTimeout = time.Duration(2) * time.Minute
 )
 
 //VCSS constants
@@ -163,6 +205,16 @@ const (
 	DefaultVcssRefreshPeriod = time.Duration(2) * time.Minute
 )
 
+//HPRS constants
+const (
+	DefaultHprsProbePeriod = time.Duration(1) * time.Minute
+)
+
+// usage metering constants, the usage counted in memory is persisted at every flush period
+const (
+	DefaultUsageFlushPeriod = time.Duration(1) * time.Minute
+)
+
 // audit log constants
 const (
 	DefaultMaxRowCount       = 10000
@@ -173,6 +225,19 @@ const (
 // Search APIs filter constants
 const (
 	MaxNumDaysSearchLimit = 365
+	// DefaultTrustHistoryDays is the number of days of trust history returned when no fromDate is given
+	DefaultTrustHistoryDays = 30
+)
+
+// flavorgroup hierarchy constants, the depth counts the flavorgroup and all of its ancestors
+const (
+	MaxFlavorgroupHierarchyDepth = 8
+)
+
+// tenant constants, the tenant of a request is set in the context of one of its HVS roles as tenant=<id>
+const (
+	TenantIdPattern    = "[a-zA-Z0-9][a-zA-Z0-9_-]{0,63}"
+	TenantContextRegex = "^tenant=(" + TenantIdPattern + ")$"
 )
 
 const (
@@ -180,6 +245,23 @@ const (
 	FvsNumberOfDataFetchers            = "fvs-number-of-data-fetchers"
 	FvsSkipFlavorSignatureVerification = "fvs-skip-flavor-signature-verification"
 	FvsHostTrustCacheThreshold         = "fvs-host-trust-cache-threshold"
+	FvsAttestationLatencyBudget        = "fvs-attestation-latency-budget"
+	FvsAsyncQuoteCallbackUrl           = "fvs-async-quote-callback-url"
+	FvsAsyncQuoteTimeout               = "fvs-async-quote-timeout"
+	FvsDecisionLogFile                 = "fvs-decision-log-file"
+	FvsFlavorSignatureQuorum           = "fvs-flavor-signature-quorum"
+	FvsMaxQuoteAge                     = "fvs-max-quote-age"
+	FvsClockSkewThreshold              = "fvs-clock-skew-threshold"
+	FvsRevocationCheck                 = "fvs-revocation-check"
+	FvsCrlCacheTime                    = "fvs-crl-cache-time"
+	FvsOcspCheck                       = "fvs-ocsp-check"
+	FvsQueueVisibilityTimeout          = "fvs-queue-visibility-timeout"
+	HostConnectorCallTimeout           = "host-connector-call-timeout"
+	HardwareUuidCollisionPolicy        = "hardware-uuid-collision-policy"
 	HrrsRefreshPeriod                  = "hrrs-refresh-period"
+	HrrsTrustHistoryRetention          = "hrrs-trust-history-retention"
 	VcssRefreshPeriod                  = "vcss-refresh-period"
+	HprsProbePeriod                    = "hprs-probe-period"
+	QuoteCallbackAuthMode              = "quote-callback-auth-mode"
+	QuoteCallbackAuthCommonNames       = "quote-callback-auth-allowed-common-names"
 )
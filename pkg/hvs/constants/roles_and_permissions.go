@@ -32,7 +32,10 @@ const (
 	FlavorCreate   = "flavors:create"
 	FlavorRetrieve = "flavors:retrieve"
 	FlavorSearch   = "flavors:search"
+	FlavorUpdate   = "flavors:store"
 	FlavorDelete   = "flavors:delete"
+	FlavorSign     = "flavors:sign"
+	FlavorVerify   = "flavors:verify"
 
 	TagFlavorCreate        = "tag_flavors:create"
 	HostUniqueFlavorCreate = "host_unique_flavors:create"
@@ -51,15 +54,41 @@ const (
 	TpmEndorsementSearch   = "tpm_endorsements:search"
 	TpmEndorsementDelete   = "tpm_endorsements:delete"
 
+	PlatformCertificateCreate   = "platform_certificates:create"
+	PlatformCertificateRetrieve = "platform_certificates:retrieve"
+	PlatformCertificateSearch   = "platform_certificates:search"
+	PlatformCertificateDelete   = "platform_certificates:delete"
+	PlatformCertificateRevoke   = "platform_certificates:revoke"
+
+	FlavorLearningCreate   = "flavor_learning:create"
+	FlavorLearningRetrieve = "flavor_learning:retrieve"
+	FlavorLearningSearch   = "flavor_learning:search"
+	FlavorLearningDelete   = "flavor_learning:delete"
+
+	FlavorPruneCreate   = "flavor_prunes:create"
+	FlavorPruneRetrieve = "flavor_prunes:retrieve"
+	FlavorPruneSearch   = "flavor_prunes:search"
+
+	AuditEventSearch = "audit_events:search"
+
+	TenantUsageSearch = "tenant_usage:search"
+
+	RuleDefinitionSearch = "rule_definitions:search"
+
 	ReportCreate   = "reports:create"
 	ReportRetrieve = "reports:retrieve"
 	ReportSearch   = "reports:search"
 
+	AttestationLatencyRetrieve = "attestation_latency:retrieve"
+
+	QuoteCallbackCreate = "quote_callbacks:create"
+
 	// AssetTagAPI
 	TagCertificateCreate = "tag_certificates:create"
 	TagCertificateDelete = "tag_certificates:delete"
 	TagCertificateSearch = "tag_certificates:search"
 	TagCertificateDeploy = "tag_certificates:deploy"
+	TagCertificateRevoke = "tag_certificates:revoke"
 
 	// Tag Certificates Requests API
 	TagCertificateRequestsStore = "tag_certificate_requests:store"
@@ -22,6 +22,12 @@ const (
 	RuleXmlMeasurementLogEquals     = RulePrefix + "XmlMeasurementLogEquals"
 	RulePcrEventLogEqualsExcluding  = RulePrefix + "PcrEventLogEqualsExcluding"
 	RuleXmlMeasurementLogIntegrity  = RulePrefix + "XmlMeasurementLogIntegrity"
+	RuleStrictEventLog              = RulePrefix + "StrictEventLog"
+	RuleQuoteNonceBound             = RulePrefix + "QuoteNonceBound"
+	RuleQuoteDigestMatches          = RulePrefix + "QuoteDigestMatches"
+	RuleQuoteFresh                  = RulePrefix + "QuoteFresh"
+	RuleContainerImagesMatch        = RulePrefix + "ContainerImagesMatch"
+	RuleFirmwareVersionsMatch       = RulePrefix + "FirmwareVersionsMatch"
 )
 
 // Verifier Faults
@@ -31,27 +37,45 @@ const (
 	FaultAikCertificateMissing                      = FaultPrefix + "AikCertificateMissing"
 	FaultAikCertificateNotTrusted                   = FaultPrefix + "AikCertificateNotTrusted"
 	FaultAikCertificateNotYetValid                  = FaultPrefix + "AikCertificateNotYetValid"
+	FaultAikCertificateRevoked                      = FaultPrefix + "AikCertificateRevoked"
 	FaultAllofFlavorsMissing                        = FaultPrefix + "AllOfFlavorsMissing"
 	FaultAssetTagMismatch                           = FaultPrefix + "AssetTagMismatch"
 	FaultAssetTagMissing                            = FaultPrefix + "AssetTagMissing"
 	FaultAssetTagNotProvisioned                     = FaultPrefix + "AssetTagNotProvisioned"
+	FaultContainerImageDigestMismatch               = FaultPrefix + "ContainerImageDigestMismatch"
+	FaultContainerImageMeasurementsMissing          = FaultPrefix + "ContainerImageMeasurementsMissing"
+	FaultContainerImageMissing                      = FaultPrefix + "ContainerImageMissing"
+	FaultContainerImageRootHashMismatch             = FaultPrefix + "ContainerImageRootHashMismatch"
+	FaultFirmwareMissing                            = FaultPrefix + "FirmwareMissing"
+	FaultFirmwareVersionMismatch                    = FaultPrefix + "FirmwareVersionMismatch"
 	FaultFlavorSignatureMissing                     = FaultPrefix + "FlavorSignatureMissing"
 	FaultRequiredFlavorTypeMissing                  = FaultPrefix + "RequiredFlavorTypeMissing"
 	FaultFlavorSignatureNotTrusted                  = FaultPrefix + "FlavorSignatureNotTrusted"
 	FaultFlavorSignatureVerificationFailed          = FaultPrefix + "FlavorSignatureVerificationFailed"
+	FaultFlavorSigningCertificateRevoked            = FaultPrefix + "FlavorSigningCertificateRevoked"
+	FaultHostClockSkewed                            = FaultPrefix + "HostClockSkewed"
 	FaultPcrEventLogContainsUnexpectedEntries       = FaultPrefix + "PcrEventLogContainsUnexpectedEntries"
 	FaultPcrEventLogInvalid                         = FaultPrefix + "PcrEventLogInvalid"
 	FaultPcrEventLogMissing                         = FaultPrefix + "PcrEventLogMissing"
 	FaultPcrEventLogMissingExpectedEntries          = FaultPrefix + "PcrEventLogMissingExpectedEntries"
+	FaultPcrEventLogUnrecognizedEntry               = FaultPrefix + "PcrEventLogUnrecognizedEntry"
 	FaultPcrManifestMissing                         = FaultPrefix + "PcrManifestMissing"
 	FaultPcrValueMismatch                           = FaultPrefix + "PcrValueMismatch"
 	FaultPcrValueMismatchSHA1                       = FaultPcrValueMismatch + "SHA1"
 	FaultPcrValueMismatchSHA256                     = FaultPcrValueMismatch + "SHA256"
 	FaultPcrValueMissing                            = FaultPrefix + "PcrValueMissing"
+	FaultPlatformInventoryMissing                   = FaultPrefix + "PlatformInventoryMissing"
+	FaultQuoteDigestMismatch                        = FaultPrefix + "QuoteDigestMismatch"
+	FaultQuoteDigestMissing                         = FaultPrefix + "QuoteDigestMissing"
+	FaultQuoteExpired                               = FaultPrefix + "QuoteExpired"
+	FaultQuoteNonceMissing                          = FaultPrefix + "QuoteNonceMissing"
+	FaultQuoteNonceNotBound                         = FaultPrefix + "QuoteNonceNotBound"
+	FaultQuoteTimestampMissing                      = FaultPrefix + "QuoteTimestampMissing"
 	FaultTagCertificateExpired                      = FaultPrefix + "TagCertificateExpired"
 	FaultTagCertificateMissing                      = FaultPrefix + "TagCertificateMissing"
 	FaultTagCertificateNotTrusted                   = FaultPrefix + "TagCertificateNotTrusted"
 	FaultTagCertificateNotYetValid                  = FaultPrefix + "TagCertificateNotYetValid"
+	FaultTagCertificateRevoked                      = FaultPrefix + "TagCertificateRevoked"
 	FaultXmlMeasurementLogContainsUnexpectedEntries = FaultPrefix + "XmlMeasurementLogContainsUnexpectedEntries"
 	FaultXmlMeasurementLogInvalid                   = FaultPrefix + "XmlMeasurementLogInvalid"
 	FaultXmlMeasurementLogMissing                   = FaultPrefix + "XmlMeasurementLogMissing"
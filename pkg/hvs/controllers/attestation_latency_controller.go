@@ -0,0 +1,23 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+)
+
+// AttestationLatencyController exposes the stage timings aggregated over the reports created by the host trust verifier
+type AttestationLatencyController struct {
+	Recorder domain.AttestationLatencyRecorder
+}
+
+func (controller AttestationLatencyController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/attestation_latency_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/attestation_latency_controller:Retrieve() Leaving")
+
+	return controller.Recorder.Metrics(), http.StatusOK, nil
+}
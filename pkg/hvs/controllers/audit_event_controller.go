@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/pkg/errors"
+)
+
+type AuditEventController struct {
+	Store domain.AuditEventStore
+}
+
+var auditEventSearchParams = map[string]bool{"actor": true, "method": true, "entityType": true, "entityId": true,
+	"fromDate": true, "toDate": true, "limit": true}
+
+func (controller AuditEventController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/audit_event_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/audit_event_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), auditEventSearchParams); err != nil {
+		secLog.Errorf("controllers/audit_event_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getAuditEventFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/audit_event_controller:Search() %s Invalid input provided in filter criteria", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	auditEventCollection, err := controller.Store.Search(filter)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/audit_event_controller:Search() AuditEvent search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search AuditEvents"}
+	}
+
+	secLog.Infof("%s: Return audit-events query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return auditEventCollection, http.StatusOK, nil
+}
+
+func getAuditEventFilterCriteria(params url.Values) (*models.AuditEventFilterCriteria, error) {
+	defaultLog.Trace("controllers/audit_event_controller:getAuditEventFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/audit_event_controller:getAuditEventFilterCriteria() Leaving")
+
+	aefc := models.AuditEventFilterCriteria{}
+
+	actor := strings.TrimSpace(params.Get("actor"))
+	if actor != "" {
+		if err := validation.ValidateStrings([]string{actor}); err != nil {
+			return nil, errors.New("Valid contents for actor must be specified")
+		}
+		aefc.Actor = actor
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(params.Get("method")))
+	if method != "" {
+		if method != http.MethodPost && method != http.MethodPut && method != http.MethodDelete {
+			return nil, errors.New("method must be POST, PUT or DELETE")
+		}
+		aefc.Method = method
+	}
+
+	entityType := strings.TrimSpace(params.Get("entityType"))
+	if entityType != "" {
+		if err := validation.ValidateStrings([]string{entityType}); err != nil {
+			return nil, errors.New("Valid contents for entityType must be specified")
+		}
+		aefc.EntityType = entityType
+	}
+
+	entityId := strings.TrimSpace(params.Get("entityId"))
+	if entityId != "" {
+		id, err := uuid.Parse(entityId)
+		if err != nil {
+			return nil, errors.New("Invalid UUID format of the entityId specified")
+		}
+		aefc.EntityID = id
+	}
+
+	fromDate := strings.TrimSpace(params.Get("fromDate"))
+	if fromDate != "" {
+		pTime, err := utils.ParseDateQueryParam(fromDate)
+		if err != nil {
+			return nil, errors.New("Invalid fromDate specified")
+		}
+		aefc.FromDate = pTime
+	}
+
+	toDate := strings.TrimSpace(params.Get("toDate"))
+	if toDate != "" {
+		pTime, err := utils.ParseDateQueryParam(toDate)
+		if err != nil {
+			return nil, errors.New("Invalid toDate specified")
+		}
+		aefc.ToDate = pTime
+	}
+
+	rowLimit := strings.TrimSpace(params.Get("limit"))
+	if rowLimit != "" {
+		rLimit, err := strconv.Atoi(rowLimit)
+		if err != nil || rLimit <= 0 {
+			return nil, errors.New("Limit must be an integer > 0")
+		}
+		aefc.Limit = rLimit
+	} else {
+		aefc.Limit = consts.DefaultSearchResultRowLimit
+	}
+
+	return &aefc, nil
+}
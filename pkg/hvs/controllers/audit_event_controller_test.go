@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditEventController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var auditEventController *controllers.AuditEventController
+	flavorId := uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3")
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		auditEventStore := mocks2.NewFakeAuditEventStore()
+		_, _ = auditEventStore.Create(&hvs.AuditEvent{Actor: "admin", Method: "POST", Route: "/flavors", EntityType: "flavors"})
+		_, _ = auditEventStore.Create(&hvs.AuditEvent{Actor: "admin", Method: "DELETE", Route: "/flavors/" + flavorId.String(),
+			EntityType: "flavors", EntityID: &flavorId})
+		_, _ = auditEventStore.Create(&hvs.AuditEvent{Actor: "operator", Method: "PUT", Route: "/hosts", EntityType: "hosts"})
+		auditEventController = &controllers.AuditEventController{Store: auditEventStore}
+	})
+
+	searchAuditEvents := func(query string) *httptest.ResponseRecorder {
+		router.Handle("/audit-events", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(auditEventController.Search))).Methods("GET")
+		req, err := http.NewRequest("GET", "/audit-events"+query, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Specs for HTTP Get to "/audit-events"
+	Describe("Search AuditEvents", func() {
+		Context("Search the AuditEvents of an actor", func() {
+			It("Should return the AuditEvents of the actor, the most recent first", func() {
+				w = searchAuditEvents("?actor=admin")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.AuditEventCollection
+				err := json.Unmarshal(w.Body.Bytes(), &collection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(collection.AuditEvents).To(HaveLen(2))
+				Expect(collection.AuditEvents[0].Method).To(Equal("DELETE"))
+			})
+		})
+		Context("Search the AuditEvents of an entity", func() {
+			It("Should return the AuditEvents of the entity", func() {
+				w = searchAuditEvents("?entityType=flavors&entityId=" + flavorId.String())
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.AuditEventCollection
+				err := json.Unmarshal(w.Body.Bytes(), &collection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(collection.AuditEvents).To(HaveLen(1))
+				Expect(*collection.AuditEvents[0].EntityID).To(Equal(flavorId))
+			})
+		})
+		Context("Search the AuditEvents with an invalid method", func() {
+			It("Should fail to search AuditEvents", func() {
+				w = searchAuditEvents("?method=GET")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Search the AuditEvents with an unsupported parameter", func() {
+			It("Should fail to search AuditEvents", func() {
+				w = searchAuditEvents("?route=/flavors")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
@@ -153,8 +153,10 @@ func validateCaCertificates(caCertificate hvs.CaCertificate) (*x509.Certificate,
 
 	if !(models.CaCertTypesRootCa.String() == caCertificate.Type ||
 		models.CaCertTypesEndorsementCa.String() == caCertificate.Type ||
-		models.CaCertTypesEkCa.String() == caCertificate.Type) {
-		return nil, errors.Errorf("Invalid type, only root or endorsement ca certificate can be added")
+		models.CaCertTypesEkCa.String() == caCertificate.Type ||
+		models.CaCertTypesPlatformCa.String() == caCertificate.Type ||
+		models.CaCertTypesRimCa.String() == caCertificate.Type) {
+		return nil, errors.Errorf("Invalid type, only root, endorsement, platform or rim ca certificate can be added")
 	}
 
 	certificate, err := x509.ParseCertificate(caCertificate.Certificate)
@@ -6,6 +6,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
@@ -13,7 +14,10 @@ import (
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	flavorConstants "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	model "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
@@ -86,7 +90,7 @@ func (controller *DeploySoftwareManifestController) DeployManifest(w http.Respon
 	var fmc util.FlavorToManifestConverter
 	manifest := fmc.GetManifestFromFlavor(signedFlavor.Flavor)
 
-	httpStatus, err := controller.deployManifestToHost(reqDeployManifest.HostId, manifest)
+	httpStatus, err := controller.deployManifestToHost(r.Context(), reqDeployManifest.HostId, manifest)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/deploy_software_manifest_controller:"+
 			"DeployManifest() %s : Failed to deploy manifest to host", commLogMsg.AppRuntimeErr)
@@ -95,16 +99,136 @@ func (controller *DeploySoftwareManifestController) DeployManifest(w http.Respon
 	return nil, httpStatus, nil
 }
 
-func (controller *DeploySoftwareManifestController) deployManifestToHost(hostId uuid.UUID, manifest model.Manifest) (int, error) {
+func (controller *DeploySoftwareManifestController) DeployManifests(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/deploy_software_manifest_controller:DeployManifests() Entering")
+	defer defaultLog.Trace("controllers/deploy_software_manifest_controller:DeployManifests() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Errorf("controllers/deploy_software_manifest_controller:DeployManifests() %s : The request body"+
+			" is not provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqDeployManifests *hvs.DeployManifestsRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&reqDeployManifests)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/deploy_software_manifest_controller:"+
+			"DeployManifests() %s : Failed to decode request body as deploy manifests request", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode request body"}
+	}
+
+	err = validateDeployManifestsRequest(reqDeployManifests)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/deploy_software_manifest_controller:"+
+			"DeployManifests() %s : Invalid deploy manifests request provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	hconnector, httpStatus, err := controller.getHostConnector(reqDeployManifests.HostId)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("controllers/deploy_software_manifest_controller:"+
+			"DeployManifests() %s : Failed to connect to host", commLogMsg.AppRuntimeErr)
+		return nil, httpStatus, &commErr.ResourceError{Message: "Failed to deploy manifests to host"}
+	}
+
+	// the flavors that cannot be deployed are reported in their result, the others are deployed together
+	results := make([]hvs.DeployManifestResult, len(reqDeployManifests.FlavorIds))
+	var manifests []model.Manifest
+	var flavors []*hvs.Flavor
+	var deployIndexes []int
+	for i, flavorId := range reqDeployManifests.FlavorIds {
+		results[i].FlavorId = flavorId
+		signedFlavor, err := controller.FlavorStore.Retrieve(flavorId)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				results[i].Error = "Flavor with given ID does not exist"
+				continue
+			}
+			defaultLog.WithError(err).Errorf("controllers/deploy_software_manifest_controller:"+
+				"DeployManifests() %s : Failed to retrieve flavor from store", commLogMsg.AppRuntimeErr)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve flavor from store"}
+		}
+
+		if signedFlavor.Flavor.Meta.Description.FlavorPart != string(common.FlavorPartSoftware) {
+			results[i].Error = "Flavor associated with the provided flavor id is not a SOFTWARE flavor"
+			continue
+		}
+
+		var fmc util.FlavorToManifestConverter
+		manifests = append(manifests, fmc.GetManifestFromFlavor(signedFlavor.Flavor))
+		flavors = append(flavors, &signedFlavor.Flavor)
+		deployIndexes = append(deployIndexes, i)
+	}
+
+	if len(manifests) == 0 {
+		return hvs.DeployManifestsResponse{HostId: reqDeployManifests.HostId, Results: results}, http.StatusOK, nil
+	}
+
+	deployErrs := hconnector.DeploySoftwareManifests(r.Context(), manifests)
+	deployed := false
+	for j, i := range deployIndexes {
+		if j < len(deployErrs) && deployErrs[j] != nil {
+			defaultLog.WithError(deployErrs[j]).WithField("id", results[i].FlavorId).Error(
+				"controllers/deploy_software_manifest_controller:DeployManifests() Error deploying manifest to host")
+			results[i].Error = "Failed to deploy manifest to host"
+			continue
+		}
+		results[i].Deployed = true
+		deployed = true
+	}
+
+	if reqDeployManifests.VerifyMeasurements && deployed {
+		hostManifest, err := hconnector.GetHostManifest(r.Context(), []int{int(types.PCR15)})
+		if err != nil {
+			// the manifests were deployed, only their measurements could not be verified
+			defaultLog.WithError(err).Warn("controllers/deploy_software_manifest_controller:DeployManifests() " +
+				"Failed to retrieve host manifest, the measurements of the deployed manifests are not verified")
+		} else {
+			for j, i := range deployIndexes {
+				if results[i].Deployed {
+					measured := isMeasuredInPcr15(&hostManifest, flavors[j])
+					results[i].Measured = &measured
+				}
+			}
+		}
+	}
+
+	return hvs.DeployManifestsResponse{HostId: reqDeployManifests.HostId, Results: results}, http.StatusOK, nil
+}
+
+func (controller *DeploySoftwareManifestController) deployManifestToHost(ctx context.Context, hostId uuid.UUID, manifest model.Manifest) (int, error) {
 	defaultLog.Trace("controllers/deploy_software_manifest_controller:deployManifestToHost() Entering")
 	defer defaultLog.Trace("controllers/deploy_software_manifest_controller:deployManifestToHost() Leaving")
 
+	hconnector, httpStatus, err := controller.getHostConnector(hostId)
+	if err != nil {
+		return httpStatus, err
+	}
+
+	err = hconnector.DeploySoftwareManifest(ctx, manifest)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Error deploying manifest to host")
+	}
+	return http.StatusOK, nil
+}
+
+func (controller *DeploySoftwareManifestController) getHostConnector(hostId uuid.UUID) (hostConnector.HostConnector, int, error) {
+	defaultLog.Trace("controllers/deploy_software_manifest_controller:getHostConnector() Entering")
+	defer defaultLog.Trace("controllers/deploy_software_manifest_controller:getHostConnector() Leaving")
+
 	host, err := controller.HController.HStore.Retrieve(hostId, nil)
 	if err != nil {
 		if strings.Contains(err.Error(), commErr.RowsNotFound) {
-			return http.StatusBadRequest, &commErr.ResourceError{Message: "Host with given ID does not exist"}
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Host with given ID does not exist"}
 		} else {
-			return http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve host from store")
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve host from store")
 		}
 	}
 
@@ -115,14 +239,54 @@ func (controller *DeploySoftwareManifestController) deployManifestToHost(hostId
 
 	hconnector, err := controller.HController.HCConfig.HostConnectorProvider.NewHostConnector(connectionString)
 	if err != nil {
-		return http.StatusInternalServerError, errors.Wrap(err, "Could not instantiate host connector")
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Could not instantiate host connector")
 	}
+	return hconnector, http.StatusOK, nil
+}
 
-	err = hconnector.DeploySoftwareManifest(manifest)
-	if err != nil {
-		return http.StatusInternalServerError, errors.Wrap(err, "Error deploying manifest to host")
+// isMeasuredInPcr15 checks the PCR 15 event log of the host for the measurement of a software flavor, the event
+// label is the flavor label followed by the flavor id, or only starts with the flavor label for the default flavors
+func isMeasuredInPcr15(hostManifest *types.HostManifest, flavor *hvs.Flavor) bool {
+	pcrEventLogs, err := hostManifest.PcrManifest.GetPcrEventLog(types.SHA256, types.PCR15)
+	if err != nil || pcrEventLogs == nil {
+		return false
 	}
-	return http.StatusOK, nil
+
+	flavorLabel := flavor.Meta.Description.Label
+	labelToMatch := flavorLabel + "-" + flavor.Meta.ID.String()
+	isDefaultFlavor := strings.Contains(flavorLabel, flavorConstants.DefaultSoftwareFlavorPrefix) ||
+		strings.Contains(flavorLabel, flavorConstants.DefaultWorkloadFlavorPrefix)
+	for _, eventLog := range *pcrEventLogs {
+		if eventLog.Label == labelToMatch || (isDefaultFlavor && strings.HasPrefix(eventLog.Label, flavorLabel)) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateDeployManifestsRequest(reqDeployManifests *hvs.DeployManifestsRequest) error {
+	defaultLog.Trace("controllers/deploy_software_manifest_controller:validateDeployManifestsRequest() Entering")
+	defer defaultLog.Trace("controllers/deploy_software_manifest_controller:validateDeployManifestsRequest() Leaving")
+
+	if reqDeployManifests.HostId == uuid.Nil {
+		return errors.New("Invalid Host Id provided in request")
+	}
+	if len(reqDeployManifests.FlavorIds) == 0 {
+		return errors.New("No Flavor Ids provided in request")
+	}
+
+	flavorIds := make(map[uuid.UUID]bool)
+	for _, flavorId := range reqDeployManifests.FlavorIds {
+		if flavorId == uuid.Nil {
+			return errors.New("Invalid Flavor Id provided in request")
+		}
+		if flavorIds[flavorId] {
+			return errors.New("Duplicate Flavor Id provided in request")
+		}
+		flavorIds[flavorId] = true
+	}
+
+	return nil
 }
 
 func validateDeployManifestRequest(reqDeployManifest *hvs.DeployManifestRequest) error {
@@ -6,6 +6,7 @@
 package controllers_test
 
 import (
+	"encoding/json"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
@@ -14,6 +15,7 @@ import (
 	smocks "github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/mocks"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
 	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"net/http"
@@ -259,4 +261,82 @@ var _ = Describe("DeploySoftwareManifestController", func() {
 			})
 		})
 	})
+
+	Describe("Deploy software manifests to host", func() {
+		Context("Provide a valid host ID and a SOFTWARE and an OS flavor ID", func() {
+			It("Should deploy the software manifest and report the OS flavor", func() {
+				router.Handle("/rpc/deploy-software-manifests", hvsRoutes.ErrorHandler(hvsRoutes.
+					JsonResponseHandler(deploySoftwareManifestController.DeployManifests))).Methods("POST")
+				deployManifestsRequestJson := `{
+												"flavor_ids":["339a7ac6-b8be-4356-ab34-be6e3bdfa1ed", "71e4c52e-595a-429d-9917-1965b437c353"],
+												"host_id":"ee37c360-7eae-4250-a677-6ee12adce8e2"
+											  }`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/rpc/deploy-software-manifests",
+					strings.NewReader(deployManifestsRequestJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response hvs.DeployManifestsResponse
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Results).To(HaveLen(2))
+				Expect(response.Results[0].Deployed).To(BeTrue())
+				Expect(response.Results[0].Measured).To(BeNil())
+				Expect(response.Results[1].Deployed).To(BeFalse())
+				Expect(response.Results[1].Error).NotTo(BeEmpty())
+			})
+		})
+		Context("Provide a duplicate flavor ID in request", func() {
+			It("Should fail to deploy software manifests", func() {
+				router.Handle("/rpc/deploy-software-manifests", hvsRoutes.ErrorHandler(hvsRoutes.
+					JsonResponseHandler(deploySoftwareManifestController.DeployManifests))).Methods("POST")
+				deployManifestsRequestJson := `{
+												"flavor_ids":["339a7ac6-b8be-4356-ab34-be6e3bdfa1ed", "339a7ac6-b8be-4356-ab34-be6e3bdfa1ed"],
+												"host_id":"ee37c360-7eae-4250-a677-6ee12adce8e2"
+											  }`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/rpc/deploy-software-manifests",
+					strings.NewReader(deployManifestsRequestJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide host ID for non existent host", func() {
+			It("Should fail to deploy software manifests", func() {
+				router.Handle("/rpc/deploy-software-manifests", hvsRoutes.ErrorHandler(hvsRoutes.
+					JsonResponseHandler(deploySoftwareManifestController.DeployManifests))).Methods("POST")
+				deployManifestsRequestJson := `{
+												"flavor_ids":["339a7ac6-b8be-4356-ab34-be6e3bdfa1ed"],
+												"host_id":"ee37c360-7eae-4250-a677-6ee12adce8e3"
+											  }`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/rpc/deploy-software-manifests",
+					strings.NewReader(deployManifestsRequestJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
 })
@@ -6,6 +6,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -16,6 +17,7 @@ import (
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
 	hcUtil "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
@@ -28,12 +30,14 @@ import (
 
 type ESXiClusterController struct {
 	ECStore     domain.ESXiClusterStore
+	RStore      domain.ReportStore
 	HController HostController
 }
 
-func NewESXiClusterController(ec domain.ESXiClusterStore, hc HostController) *ESXiClusterController {
+func NewESXiClusterController(ec domain.ESXiClusterStore, rs domain.ReportStore, hc HostController) *ESXiClusterController {
 	return &ESXiClusterController{
 		ECStore:     ec,
+		RStore:      rs,
 		HController: hc,
 	}
 }
@@ -80,7 +84,7 @@ func (controller ESXiClusterController) Create(w http.ResponseWriter, r *http.Re
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "ESXi cluster with the same name already exists"}
 	}
 
-	hostInfoList, err := controller.getHostsFromCluster(reqESXiCluster)
+	hostInfoList, err := controller.getHostsFromCluster(r.Context(), reqESXiCluster)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/esxi_cluster_controller:Create() Error getting hosts from " +
 			"ESXi cluster")
@@ -107,7 +111,7 @@ func (controller ESXiClusterController) Create(w http.ResponseWriter, r *http.Re
 			Description:      description,
 			ConnectionString: reqESXiCluster.ConnectionString + ";h=" + hostInfo.Name,
 		}
-		_, _, err := controller.HController.CreateHost(reqHost)
+		_, _, err := controller.HController.CreateHost(r.Context(), reqHost)
 		if err != nil {
 			defaultLog.WithError(err).Errorf("controllers/esxi_cluster_controller:Create() ESXi host registration "+
 				"failed for host : %s", hostInfo.Name)
@@ -258,6 +262,85 @@ func (controller ESXiClusterController) Retrieve(w http.ResponseWriter, r *http.
 	return esxiCluster, http.StatusOK, nil
 }
 
+// RetrieveTrust returns the trust of the cluster rolled up from the latest reports of its hosts
+func (controller ESXiClusterController) RetrieveTrust(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/esxi_cluster_controller:RetrieveTrust() Entering")
+	defer defaultLog.Trace("controllers/esxi_cluster_controller:RetrieveTrust() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+
+	esxiCluster, err := controller.ECStore.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).WithField("id", id).Info(
+				"controllers/esxi_cluster_controller:RetrieveTrust() ESXi cluster with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "ESXi cluster with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Info(
+			"controllers/esxi_cluster_controller:RetrieveTrust() Failed to retrieve ESXi cluster")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve ESXi cluster"}
+	}
+
+	hostNames, err := controller.ECStore.SearchHosts(esxiCluster.Id)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/esxi_cluster_controller:RetrieveTrust() Failed to retrieve host names associated with the " +
+				"cluster")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve host names " +
+			"associated with the cluster"}
+	}
+
+	hostTrusts := make([]hvs.ESXiClusterHostTrust, 0, len(hostNames))
+	for _, hostName := range hostNames {
+		hostTrust, err := controller.getHostTrust(hostName)
+		if err != nil {
+			defaultLog.WithError(err).WithField("id", id).Errorf(
+				"controllers/esxi_cluster_controller:RetrieveTrust() Failed to retrieve the trust of host %s", hostName)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve the " +
+				"trust of the hosts of the cluster"}
+		}
+		hostTrusts = append(hostTrusts, *hostTrust)
+	}
+
+	secLog.WithField("Cluster name", esxiCluster.ClusterName).Infof("ESXi cluster trust retrieved by: %s", r.RemoteAddr)
+	return hvs.NewESXiClusterTrust(*esxiCluster, hostTrusts), http.StatusOK, nil
+}
+
+// getHostTrust returns the trust of a host of a cluster from its latest report, the trust is not set when the host
+// is not registered or has no report
+func (controller ESXiClusterController) getHostTrust(hostName string) (*hvs.ESXiClusterHostTrust, error) {
+	hostTrust := hvs.ESXiClusterHostTrust{HostName: hostName}
+	hosts, err := controller.HController.HStore.Search(&models.HostFilterCriteria{NameEqualTo: hostName}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to search the host")
+	}
+	if len(hosts) == 0 {
+		return &hostTrust, nil
+	}
+	hostId := hosts[0].Id
+	hostTrust.HostId = &hostId
+
+	reports, err := controller.RStore.Search(&models.ReportFilterCriteria{HostID: hostId, LatestPerHost: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to search the latest report of the host")
+	}
+	if len(reports) == 0 {
+		return &hostTrust, nil
+	}
+	report := reports[0]
+	trustInformation := hvs.NewTrustInformation(report.TrustReport, report.Expiration)
+	hostTrust.Trusted = &trustInformation.Overall
+	hostTrust.TrustScore = trustInformation.TrustScore
+	for _, flavorPart := range common.GetFlavorTypes() {
+		if status, ok := trustInformation.FlavorTrust[flavorPart]; ok && !status.Trust {
+			hostTrust.UntrustedFlavorParts = append(hostTrust.UntrustedFlavorParts, flavorPart)
+		}
+	}
+	hostTrust.ReportCreated = &report.CreatedAt
+	hostTrust.ReportExpiration = &report.Expiration
+	return &hostTrust, nil
+}
+
 func validateESXiClusterRequest(esxiCluster hvs.ESXiClusterCreateRequest) error {
 	defaultLog.Trace("controllers/esxi_cluster_controller:ValidateESXiClusterRequest() Entering")
 	defer defaultLog.Trace("controllers/esxi_cluster_controller:ValidateESXiClusterRequest() Leaving")
@@ -308,14 +391,14 @@ func getECCriteria(params url.Values) (*models.ESXiClusterFilterCriteria, error)
 	return &ecfc, nil
 }
 
-func (controller *ESXiClusterController) getHostsFromCluster(reqESXiCluster *hvs.ESXiClusterCreateRequest) ([]mo.HostSystem, error) {
+func (controller *ESXiClusterController) getHostsFromCluster(ctx context.Context, reqESXiCluster *hvs.ESXiClusterCreateRequest) ([]mo.HostSystem, error) {
 	defaultLog.Trace("controllers/esxi_cluster_controller:getHostsFromCluster() Entering")
 	defer defaultLog.Trace("controllers/esxi_cluster_controller:getHostsFromCluster() Leaving")
 	hostConnectorFactory, err := controller.HController.HCConfig.HostConnectorProvider.NewHostConnector(reqESXiCluster.ConnectionString)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error creating host connector instance")
 	}
-	hostInfoList, err := hostConnectorFactory.GetClusterReference(reqESXiCluster.ClusterName)
+	hostInfoList, err := hostConnectorFactory.GetClusterReference(ctx, reqESXiCluster.ClusterName)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error retrieving host info list from cluster")
 	}
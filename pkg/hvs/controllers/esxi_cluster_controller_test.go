@@ -8,6 +8,7 @@ package controllers_test
 import (
 	"encoding/base64"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
@@ -62,7 +63,7 @@ var _ = Describe("ESXiClusterController", func() {
 			HCConfig:  hostControllerConfig,
 		}
 		esxiClusterController = &controllers.ESXiClusterController{ECStore: esxiClusterStore,
-			HController: *hostController}
+			RStore: mocks.NewMockReportStore(), HController: *hostController}
 	})
 
 	// Specs for HTTP Get to "/esxi-cluster"
@@ -192,6 +193,50 @@ var _ = Describe("ESXiClusterController", func() {
 		})
 	})
 
+	Describe("Retrieve ESXi cluster trust", func() {
+		Context("Retrieve the trust of an ESXi cluster with registered and unregistered hosts", func() {
+			It("Should roll up the trust of the hosts from their latest reports", func() {
+				Expect(esxiClusterStore.AddHosts(uuid.MustParse("40c6ec42-ee9a-4d8a-842b-cdcd0fefa9c0"),
+					[]string{"localhost1", "localhost2", "esxi-host3"})).To(Succeed())
+				router.Handle("/esxi-cluster/{id}/trust", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(
+					esxiClusterController.RetrieveTrust))).Methods("GET")
+				req, err := http.NewRequest("GET", "/esxi-cluster/40c6ec42-ee9a-4d8a-842b-cdcd0fefa9c0/trust", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var clusterTrust hvs.ESXiClusterTrust
+				Expect(json.Unmarshal(w.Body.Bytes(), &clusterTrust)).To(Succeed())
+				Expect(clusterTrust.ClusterName).To(Equal("Cluster 1"))
+				Expect(clusterTrust.HostCount).To(Equal(3))
+				Expect(clusterTrust.TrustedCount).To(Equal(2))
+				Expect(clusterTrust.UnknownCount).To(Equal(1))
+				// the cluster is not trusted while the trust of one of its hosts is unknown
+				Expect(clusterTrust.Trusted).To(BeFalse())
+				Expect(clusterTrust.Hosts[0].HostId.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(*clusterTrust.Hosts[0].Trusted).To(BeTrue())
+				Expect(clusterTrust.Hosts[0].ReportExpiration).NotTo(BeNil())
+				Expect(clusterTrust.Hosts[2].HostId).To(BeNil())
+				Expect(clusterTrust.Hosts[2].Trusted).To(BeNil())
+			})
+		})
+
+		Context("Try to retrieve the trust of a non-existent ESXi cluster", func() {
+			It("Should fail to retrieve the trust", func() {
+				router.Handle("/esxi-cluster/{id}/trust", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(
+					esxiClusterController.RetrieveTrust))).Methods("GET")
+				req, err := http.NewRequest("GET", "/esxi-cluster/73755fda-c910-46be-821f-e8ddeab189e9/trust", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	Describe("Create ESXi cluster entry", func() {
 		Context("Provide a valid ESXi cluster data", func() {
 			It("Should create ESXi cluster entry", func() {
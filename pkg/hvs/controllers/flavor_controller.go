@@ -6,10 +6,12 @@
 package controllers
 
 import (
+	"context"
 	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strings"
@@ -19,12 +21,14 @@ import (
 	consts "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	dm "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/auth"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
 	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/serialize"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor"
 	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
@@ -46,9 +50,15 @@ type FlavorController struct {
 	HTManager domain.HostTrustManager
 	CertStore *dm.CertificatesStore
 	HostCon   HostController
+	// MetadataSchema defines the custom metadata fields allowed in the flavor content
+	MetadataSchema fm.MetadataSchema
 }
 
-var flavorSearchParams = map[string]bool{"id": true, "key": true, "value": true, "flavorgroupId": true, "flavorParts": true}
+// patchableFlavorSections are the top level sections of a flavor that the flavor merge patches can change
+var patchableFlavorSections = map[string]bool{"meta": true, "pcrs": true}
+
+var flavorSearchParams = map[string]bool{"id": true, "key": true, "value": true, "flavorgroupId": true, "flavorParts": true,
+	"metadataKey": true, "metadataValue": true}
 
 func NewFlavorController(fs domain.FlavorStore, fgs domain.FlavorGroupStore, hs domain.HostStore, tcs domain.TagCertificateStore, htm domain.HostTrustManager, certStore *dm.CertificatesStore, hcConfig domain.HostControllerConfig) *FlavorController {
 	// certStore should have an entry for Flavor Signing CA
@@ -84,6 +94,11 @@ func (fcon *FlavorController) Create(w http.ResponseWriter, r *http.Request) (in
 	defaultLog.Trace("controllers/flavor_controller:Create() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:Create() Leaving")
 
+	fcon, status, err := fcon.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	flavorCreateReq, err := getFlavorCreateReq(r)
 	if err != nil {
 		if strings.Contains(err.Error(), "Invalid Content-Type") {
@@ -127,12 +142,53 @@ func (fcon *FlavorController) Create(w http.ResponseWriter, r *http.Request) (in
 		}
 	}
 
-	signedFlavors, err = fcon.createFlavors(flavorCreateReq)
+	for _, flavor := range flavorCreateReq.FlavorCollection.Flavors {
+		if err := fcon.MetadataSchema.Validate(flavor.Flavor.Meta.CustomMetadata); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavor_controller:Create() %s : Invalid flavor custom metadata", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+		}
+		if err := hvs.ValidateLabels(flavor.Flavor.Meta.Labels); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavor_controller:Create() %s : Invalid flavor labels", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+		}
+		if err := validateEventLogExclusions(&flavor.Flavor); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavor_controller:Create() %s : Invalid flavor event log exclusions", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+		}
+	}
+	for _, signedFlavor := range flavorCreateReq.SignedFlavorCollection.SignedFlavors {
+		if err := hvs.ValidateLabels(signedFlavor.Flavor.Meta.Labels); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavor_controller:Create() %s : Invalid flavor labels", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+		}
+		if err := validateEventLogExclusions(&signedFlavor.Flavor); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavor_controller:Create() %s : Invalid flavor event log exclusions", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+		}
+	}
+
+	// the delta flavors are validated whether they are submitted signed or not
+	var flavors []hvs.Flavor
+	for _, flavor := range flavorCreateReq.FlavorCollection.Flavors {
+		flavors = append(flavors, flavor.Flavor)
+	}
+	for _, signedFlavor := range flavorCreateReq.SignedFlavorCollection.SignedFlavors {
+		flavors = append(flavors, signedFlavor.Flavor)
+	}
+	if err := fcon.validateDeltaFlavors(flavors); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Create() %s : Invalid delta flavor content", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	signedFlavors, err = fcon.createFlavors(r.Context(), flavorCreateReq)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/flavor_controller:Create() Error creating flavors")
-		if strings.Contains(err.Error(), "duplicate key") {
+		if postgres.IsDuplicateKeyError(err) {
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with same id/label already exists"}
 		}
+		if flavorCreateReq.HostManifest != nil {
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error creating flavors from host manifest"}
+		}
 		if strings.Contains(err.Error(), "401") {
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Authentication with trust agent failed"}
 		}
@@ -153,7 +209,7 @@ func (fcon *FlavorController) Create(w http.ResponseWriter, r *http.Request) (in
 	return signedFlavorCollection, http.StatusCreated, nil
 }
 
-func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([]hvs.SignedFlavor, error) {
+func (fcon *FlavorController) createFlavors(ctx context.Context, flavorReq dm.FlavorCreateRequest) ([]hvs.SignedFlavor, error) {
 	defaultLog.Trace("controllers/flavor_controller:createFlavors() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:createFlavors() Leaving")
 
@@ -161,24 +217,30 @@ func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([
 	var platformFlavor *fType.PlatformFlavor
 	flavorFlavorPartMap := make(map[fc.FlavorPart][]hvs.SignedFlavor)
 
-	if flavorReq.ConnectionString != "" {
-		// get flavor from host
-		// get host manifest from the host
-		defaultLog.Debug("Host connection string given, trying to create flavors from host")
-		connectionString, _, err := GenerateConnectionString(flavorReq.ConnectionString,
-			fcon.HostCon.HCConfig.Username,
-			fcon.HostCon.HCConfig.Password,
-			fcon.HostCon.HCStore)
+	if flavorReq.ConnectionString != "" || flavorReq.HostManifest != nil {
+		hostManifest := flavorReq.HostManifest
+		if hostManifest != nil {
+			// the manifest of a good known host captured offline, for the hosts HVS cannot connect to
+			defaultLog.Debug("Host manifest given, trying to create flavors from host manifest")
+		} else {
+			// get flavor from host
+			// get host manifest from the host
+			defaultLog.Debug("Host connection string given, trying to create flavors from host")
+			connectionString, _, err := GenerateConnectionString(flavorReq.ConnectionString,
+				fcon.HostCon.HCConfig.Username,
+				fcon.HostCon.HCConfig.Password,
+				fcon.HostCon.HCStore)
 
-		if err != nil {
-			defaultLog.Error("controllers/flavor_controller:CreateFlavors() Could not generate formatted connection string")
-			return nil, errors.Wrap(err, "Error while generating a formatted connection string")
-		}
-		defaultLog.Debug("Getting manifest from host...")
-		hostManifest, err := fcon.getHostManifest(connectionString)
-		if err != nil {
-			defaultLog.Error("controllers/flavor_controller:CreateFlavors() Error getting host manifest")
-			return nil, errors.Wrap(err, "Error getting host manifest")
+			if err != nil {
+				defaultLog.Error("controllers/flavor_controller:CreateFlavors() Could not generate formatted connection string")
+				return nil, errors.Wrap(err, "Error while generating a formatted connection string")
+			}
+			defaultLog.Debug("Getting manifest from host...")
+			hostManifest, err = fcon.getHostManifest(ctx, connectionString)
+			if err != nil {
+				defaultLog.Error("controllers/flavor_controller:CreateFlavors() Error getting host manifest")
+				return nil, errors.Wrap(err, "Error getting host manifest")
+			}
 		}
 		tagCertificate := hvs.TagCertificate{}
 		var tagX509Certificate *x509.Certificate
@@ -223,6 +285,17 @@ func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([
 			}
 		}
 
+	} else if flavorReq.FirmwareManifest != nil {
+		// get the PLATFORM flavor from the vendor published firmware measurements
+		defaultLog.Debug("Firmware manifest given, trying to create PLATFORM flavor from firmware manifest")
+		var err error
+		platformFlavor, err = flavor.GetFirmwarePlatformFlavor(flavorReq.FirmwareManifest)
+		if err != nil {
+			defaultLog.Errorf("controllers/flavor_controller:createFlavors() Error while creating platform flavor from firmware manifest")
+			return nil, errors.Wrap(err, "Error while creating platform flavor from firmware manifest")
+		}
+		flavorParts = append(flavorParts, fc.FlavorPartPlatform)
+
 	} else if len(flavorReq.FlavorCollection.Flavors) >= 1 || len(flavorReq.SignedFlavorCollection.SignedFlavors) >= 1 {
 		defaultLog.Debug("Creating flavors from flavor content")
 		flavorSignKey, _, _ := (*fcon.CertStore).GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
@@ -267,7 +340,7 @@ func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([
 	}
 	var err error
 	// add all flavorparts to default flavorgroups if flavorgroup name is not given
-	if flavorReq.FlavorgroupNames == nil && len(flavorReq.FlavorParts) == 0 {
+	if flavorReq.FlavorgroupNames == nil && len(flavorReq.FlavorParts) == 0 && flavorReq.FirmwareManifest == nil {
 		for _, flavorPart := range fc.GetFlavorTypes() {
 			flavorParts = append(flavorParts, flavorPart)
 		}
@@ -343,14 +416,14 @@ func orderFlavorsPerFlavorParts(parts []fc.FlavorPart, signedFlavorCollection hv
 	}
 }
 
-func (fcon *FlavorController) getHostManifest(cs string) (*hcType.HostManifest, error) {
+func (fcon *FlavorController) getHostManifest(ctx context.Context, cs string) (*hcType.HostManifest, error) {
 	defaultLog.Trace("controllers/flavor_controller:getHostManifest() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:getHostManifest() Leaving")
 	hostConnector, err := fcon.HostCon.HCConfig.HostConnectorProvider.NewHostConnector(cs)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not instantiate host connector")
 	}
-	hostManifest, err := hostConnector.GetHostManifest(nil)
+	hostManifest, err := hostConnector.GetHostManifest(ctx, nil)
 	return &hostManifest, err
 }
 
@@ -491,6 +564,19 @@ func (fcon *FlavorController) addFlavorToFlavorgroup(flavorFlavorPartMap map[fc.
 			}
 		}
 	}
+	// the flavorgroups whose flavor selector matches the labels of the flavors have new flavors too
+	for _, signedFlavor := range returnSignedFlavors {
+		if len(signedFlavor.Flavor.Meta.Labels) == 0 {
+			continue
+		}
+		flavorId := signedFlavor.Flavor.Meta.ID
+		flavorgroups, err := fcon.FGStore.Search(&dm.FlavorGroupFilterCriteria{FlavorId: &flavorId})
+		if err != nil {
+			defaultLog.WithError(err).Errorf("controllers/flavor_controller: addFlavorToFlavorgroup(): Error retrieving the flavorgroups of flavor %s", flavorId)
+			continue
+		}
+		flavorgroupsForQueue = append(flavorgroupsForQueue, flavorgroups...)
+	}
 	// get all the hosts that belong to the same flavor group and add them to flavor-verify queue
 	go fcon.addFlavorgroupHostsToFlavorVerifyQueue(flavorgroupsForQueue, fgHostIds, flavorgroupFlavorMap, fetchHostData)
 	return returnSignedFlavors, nil
@@ -585,6 +671,11 @@ func (fcon *FlavorController) Search(w http.ResponseWriter, r *http.Request) (in
 	defaultLog.Trace("controllers/flavor_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:Search() Leaving")
 
+	fcon, status, err := fcon.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	// check for query parameters
 	defaultLog.WithField("query", r.URL.Query()).Trace("query flavors")
 
@@ -598,12 +689,22 @@ func (fcon *FlavorController) Search(w http.ResponseWriter, r *http.Request) (in
 	value := r.URL.Query().Get("value")
 	flavorgroupId := r.URL.Query().Get("flavorgroupId")
 	flavorParts := r.URL.Query()["flavorParts"]
+	metadataKey := r.URL.Query().Get("metadataKey")
+	metadataValue := r.URL.Query().Get("metadataValue")
 
 	filterCriteria, err := validateFlavorFilterCriteria(key, value, flavorgroupId, ids, flavorParts)
 	if err != nil {
 		secLog.Errorf("controllers/flavor_controller:Search()  %s", err.Error())
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
+	if metadataKey != "" || metadataValue != "" {
+		if err := fcon.validateMetadataFilterCriteria(metadataKey, metadataValue); err != nil {
+			secLog.Errorf("controllers/flavor_controller:Search()  %s", err.Error())
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+		}
+		filterCriteria.MetadataKey = metadataKey
+		filterCriteria.MetadataValue = metadataValue
+	}
 
 	signedFlavors, err := fcon.FStore.Search(&dm.FlavorVerificationFC{
 		FlavorFC: *filterCriteria,
@@ -621,6 +722,11 @@ func (fcon *FlavorController) Delete(w http.ResponseWriter, r *http.Request) (in
 	defaultLog.Trace("controllers/flavor_controller:Delete() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:Delete() Leaving")
 
+	fcon, status, err := fcon.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	flavorId := uuid.MustParse(mux.Vars(r)["id"])
 	signedFlavor, err := fcon.FStore.Retrieve(flavorId)
 	if err != nil {
@@ -702,6 +808,11 @@ func (fcon *FlavorController) Retrieve(w http.ResponseWriter, r *http.Request) (
 	defaultLog.Trace("controllers/flavor_controller:Retrieve() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:Retrieve() Leaving")
 
+	fcon, status, err := fcon.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	id := uuid.MustParse(mux.Vars(r)["id"])
 	signedFlavor, err := fcon.FStore.Retrieve(id)
 	if err != nil {
@@ -718,6 +829,255 @@ func (fcon *FlavorController) Retrieve(w http.ResponseWriter, r *http.Request) (
 	return signedFlavor, http.StatusOK, nil
 }
 
+// AddSignature adds a co-signer's signature to a flavor, the signature must be made by one of the trusted flavor
+// co-signing certificates. The hosts of the flavor are verified again as the flavor may now meet the signature quorum.
+func (fcon *FlavorController) AddSignature(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:AddSignature() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:AddSignature() Leaving")
+
+	fcon, status, err := fcon.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_controller:AddSignature() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var signatureReq dm.FlavorSignatureCreateRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&signatureReq); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:AddSignature() %s : Failed to decode request body as flavor signature", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+	if signatureReq.Signature == "" {
+		secLog.Errorf("controllers/flavor_controller:AddSignature() %s : The signature is not provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The signature must be provided"}
+	}
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	signedFlavor, err := fcon.FStore.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Info(
+				"controllers/flavor_controller:AddSignature() Flavor with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Flavor with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/flavor_controller:AddSignature() failed to retrieve Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Flavor with the given ID"}
+	}
+
+	// the signature must be made by a co-signer that has not signed the flavor yet
+	var signer *rsa.PublicKey
+	if coSigningCerts := (*fcon.CertStore)[dm.CertTypesFlavorCoSigning.String()]; coSigningCerts != nil {
+		for _, cert := range coSigningCerts.Certificates {
+			if publicKey, ok := cert.PublicKey.(*rsa.PublicKey); ok && signedFlavor.VerifySignature(signatureReq.Signature, publicKey) == nil {
+				signer = publicKey
+				break
+			}
+		}
+	}
+	if signer == nil {
+		secLog.WithField("id", id).Errorf("controllers/flavor_controller:AddSignature() %s : The signature is not made by a trusted flavor co-signer", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The signature is not made by a trusted flavor co-signer"}
+	}
+	if signedFlavor.CountSigners([]*rsa.PublicKey{signer}) != 0 {
+		return nil, http.StatusConflict, &commErr.ResourceError{Message: "The flavor is already signed by the co-signer"}
+	}
+
+	signedFlavor, err = fcon.FStore.AddSignature(id, signatureReq.Signature)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/flavor_controller:AddSignature() failed to add the signature to the Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to add the signature to the Flavor"}
+	}
+
+	hostIdsForQueue, err := getHostsAssociatedWithFlavor(fcon.HStore, fcon.FGStore, signedFlavor)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:AddSignature() Failed to retrieve hosts " +
+			"associated with flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve hosts " +
+			"associated with flavor for trust re-verification"}
+	}
+	if len(hostIdsForQueue) >= 1 {
+		err := fcon.HTManager.VerifyHostsAsync(hostIdsForQueue, false, false)
+		if err != nil {
+			defaultLog.Error("controllers/flavor_controller:AddSignature() Host to Flavor Verify Queue addition failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+				"associated with the Flavor"}
+		}
+	}
+
+	secLog.WithField("id", id).Infof("%s: Flavor co-signed by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return signedFlavor, http.StatusCreated, nil
+}
+
+// Update applies a JSON merge patch (RFC 7386) to the meta and pcrs sections of a flavor, e.g. to fix a single
+// expected PCR value or event, and signs the patched flavor again with the flavor signing key. The co-signatures
+// are dropped as they do not sign the patched flavor, and the hosts of the flavor are verified again.
+func (fcon *FlavorController) Update(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:Update() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:Update() Leaving")
+
+	fcon, status, err := fcon.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeMergePatch {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_controller:Update() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	patch, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : Failed to read request body", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to read request body"}
+	}
+	var patchSections map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &patchSections); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : Failed to decode request body as JSON merge patch", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON merge patch request body"}
+	}
+	for section := range patchSections {
+		if !patchableFlavorSections[section] {
+			secLog.Errorf("controllers/flavor_controller:Update() %s : The flavor section %s cannot be patched", commLogMsg.InvalidInputBadParam, section)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Only the meta and pcrs sections of a flavor can be patched"}
+		}
+	}
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	signedFlavor, err := fcon.FStore.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Info(
+				"controllers/flavor_controller:Update() Flavor with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Flavor with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/flavor_controller:Update() failed to retrieve Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Flavor with the given ID"}
+	}
+
+	flavorJSON, err := json.Marshal(signedFlavor.Flavor)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("controllers/flavor_controller:Update() failed to marshal Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to patch the Flavor"}
+	}
+	patchedJSON, err := serialize.MergePatch(flavorJSON, patch)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : Failed to apply JSON merge patch", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to apply JSON merge patch to the Flavor"}
+	}
+	var patchedFlavor hvs.Flavor
+	if err := json.Unmarshal(patchedJSON, &patchedFlavor); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : The patched flavor is invalid", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The patched Flavor is invalid"}
+	}
+	if err := validatePatchedFlavor(&signedFlavor.Flavor, &patchedFlavor); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : The patched flavor is invalid", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if err := fcon.MetadataSchema.Validate(patchedFlavor.Meta.CustomMetadata); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : Invalid flavor custom metadata", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if err := validateEventLogExclusions(&patchedFlavor); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : Invalid flavor event log exclusions", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if err := hvs.ValidateLabels(patchedFlavor.Meta.Labels); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Update() %s : Invalid flavor labels", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	// the hosts of the flavorgroups that no longer select the flavor once its labels are patched are verified too
+	previousHostIds, err := getHostsAssociatedWithFlavor(fcon.HStore, fcon.FGStore, signedFlavor)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:Update() Failed to retrieve hosts " +
+			"associated with flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve hosts " +
+			"associated with flavor for trust re-verification"}
+	}
+
+	flavorSignKey, _, _ := (*fcon.CertStore).GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+	signedFlavor, err = fu.PlatformFlavorUtil{}.GetSignedFlavor(&patchedFlavor, flavorSignKey.(*rsa.PrivateKey))
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("controllers/flavor_controller:Update() failed to sign the patched Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to sign the patched Flavor"}
+	}
+	signedFlavor, err = fcon.FStore.Update(signedFlavor)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("controllers/flavor_controller:Update() failed to update the Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update the Flavor"}
+	}
+
+	hostIdsForQueue, err := getHostsAssociatedWithFlavor(fcon.HStore, fcon.FGStore, signedFlavor)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:Update() Failed to retrieve hosts " +
+			"associated with flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve hosts " +
+			"associated with flavor for trust re-verification"}
+	}
+	queuedHostIds := make(map[uuid.UUID]bool)
+	for _, hostId := range hostIdsForQueue {
+		queuedHostIds[hostId] = true
+	}
+	for _, hostId := range previousHostIds {
+		if !queuedHostIds[hostId] {
+			hostIdsForQueue = append(hostIdsForQueue, hostId)
+		}
+	}
+	if len(hostIdsForQueue) >= 1 {
+		err := fcon.HTManager.VerifyHostsAsync(hostIdsForQueue, false, false)
+		if err != nil {
+			defaultLog.Error("controllers/flavor_controller:Update() Host to Flavor Verify Queue addition failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+				"associated with the Flavor"}
+		}
+	}
+
+	secLog.WithField("id", id).Infof("%s: Flavor patched by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return signedFlavor, http.StatusOK, nil
+}
+
+// validatePatchedFlavor checks that the patch keeps the identity of the flavor, its id, flavor part and the
+// label it is searched by
+func validatePatchedFlavor(flavor, patchedFlavor *hvs.Flavor) error {
+	defaultLog.Trace("controllers/flavor_controller:validatePatchedFlavor() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:validatePatchedFlavor() Leaving")
+
+	if patchedFlavor.Meta.ID != flavor.Meta.ID {
+		return errors.New("The id of a flavor cannot be patched")
+	}
+	if patchedFlavor.Meta.Description.FlavorPart != flavor.Meta.Description.FlavorPart {
+		return errors.New("The flavor part of a flavor cannot be patched")
+	}
+	if patchedFlavor.Meta.Description.Label == "" {
+		return errors.New("The label of a flavor cannot be removed")
+	}
+	if err := validation.ValidateTextString(patchedFlavor.Meta.Description.Label); err != nil {
+		return errors.Wrap(err, "Valid contents for the flavor label must be specified")
+	}
+	for bank, pcrs := range patchedFlavor.Pcrs {
+		for index, pcr := range pcrs {
+			if err := validation.ValidateHexString(pcr.Value); err != nil {
+				return errors.Errorf("The value of %s %s must be a hex string", bank, index)
+			}
+		}
+	}
+	return nil
+}
+
 func validateFlavorFilterCriteria(key, value, flavorgroupId string, ids, flavorParts []string) (*dm.FlavorFilterCriteria, error) {
 	defaultLog.Trace("controllers/flavor_controller:validateFlavorFilterCriteria() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:validateFlavorFilterCriteria() Leaving")
@@ -758,13 +1118,56 @@ func validateFlavorFilterCriteria(key, value, flavorgroupId string, ids, flavorP
 	return &filterCriteria, nil
 }
 
+// validateMetadataFilterCriteria checks that the custom metadata search key is a field of the flavor metadata schema
+func (fcon *FlavorController) validateMetadataFilterCriteria(metadataKey, metadataValue string) error {
+	defaultLog.Trace("controllers/flavor_controller:validateMetadataFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:validateMetadataFilterCriteria() Leaving")
+
+	if metadataKey == "" || metadataValue == "" {
+		return errors.New("Both metadataKey and metadataValue must be specified")
+	}
+	if err := fm.ValidateFieldName(metadataKey); err != nil {
+		return err
+	}
+	for _, field := range fcon.MetadataSchema {
+		if field.Name == metadataKey {
+			if err := validation.ValidateStrings([]string{metadataValue}); err != nil {
+				return errors.Wrap(err, "Valid contents for filter metadataValue must be specified")
+			}
+			return nil
+		}
+	}
+	return errors.Errorf("Custom metadata field '%s' is not defined in the flavor metadata schema", metadataKey)
+}
+
 func validateFlavorCreateRequest(criteria dm.FlavorCreateRequest) error {
 	defaultLog.Trace("controllers/flavor_controller:validateFlavorCreateRequest() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:validateFlavorCreateRequest() Leaving")
 
-	if criteria.ConnectionString == "" && len(criteria.FlavorCollection.Flavors) == 0 && len(criteria.SignedFlavorCollection.SignedFlavors) == 0 {
-		secLog.Error("controllers/flavor_controller: validateFlavorCreateCriteria() Valid host connection string or flavor content must be given")
-		return errors.New("Valid host connection string or flavor content must be given")
+	if criteria.ConnectionString == "" && criteria.FirmwareManifest == nil && criteria.HostManifest == nil && len(criteria.FlavorCollection.Flavors) == 0 && len(criteria.SignedFlavorCollection.SignedFlavors) == 0 {
+		secLog.Error("controllers/flavor_controller: validateFlavorCreateCriteria() Valid host connection string, host manifest, firmware manifest or flavor content must be given")
+		return errors.New("Valid host connection string, host manifest, firmware manifest or flavor content must be given")
+	}
+	if criteria.HostManifest != nil {
+		if criteria.ConnectionString != "" || criteria.FirmwareManifest != nil {
+			return errors.New("Host manifest cannot be given with a host connection string or a firmware manifest")
+		}
+		if _, err := uuid.Parse(criteria.HostManifest.HostInfo.HardwareUUID); err != nil {
+			return errors.New("Host manifest must hold a valid hardware UUID")
+		}
+		if len(criteria.HostManifest.PcrManifest.Sha1Pcrs) == 0 && len(criteria.HostManifest.PcrManifest.Sha256Pcrs) == 0 {
+			return errors.New("Host manifest must hold the PCR values of the host")
+		}
+	}
+	if criteria.FirmwareManifest != nil {
+		if criteria.ConnectionString != "" {
+			return errors.New("Host connection string and firmware manifest cannot be given together")
+		}
+		for _, fp := range criteria.FlavorParts {
+			if fp != fc.FlavorPartPlatform {
+				return errors.New("Only PLATFORM flavor can be created from a firmware manifest")
+			}
+		}
 	}
 	if criteria.ConnectionString != "" {
 		err := utils.ValidateConnectionString(criteria.ConnectionString)
@@ -812,6 +1215,39 @@ func validateFlavorMetaContent(meta *fm.Meta) error {
 	return nil
 }
 
+// validateEventLogExclusions checks that the event log exclusion patterns of the flavor pcrs can be compiled
+func validateEventLogExclusions(flavor *hvs.Flavor) error {
+	for bank, pcrs := range flavor.Pcrs {
+		for index, pcr := range pcrs {
+			if _, err := hcType.NewEventLogExcluder(pcr.Exclude); err != nil {
+				return errors.Wrapf(err, "Invalid event log exclusion for bank '%s', pcr %s of flavor %s", bank, index, flavor.Meta.Description.Label)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDeltaFlavors checks that each delta flavor references an existing base flavor it can be merged with
+func (fcon *FlavorController) validateDeltaFlavors(flavors []hvs.Flavor) error {
+	defaultLog.Trace("controllers/flavor_controller:validateDeltaFlavors() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:validateDeltaFlavors() Leaving")
+
+	for i := range flavors {
+		flavor := &flavors[i]
+		if !flavor.IsDelta() {
+			continue
+		}
+		baseFlavor, err := fcon.FStore.Retrieve(*flavor.Meta.BaseFlavorID)
+		if err != nil {
+			return errors.Errorf("Base flavor %s of delta flavor %s does not exist", flavor.Meta.BaseFlavorID, flavor.Meta.Description.Label)
+		}
+		if _, err := fm.MergeDelta(&baseFlavor.Flavor, flavor); err != nil {
+			return errors.Wrapf(err, "Invalid delta flavor %s", flavor.Meta.Description.Label)
+		}
+	}
+	return nil
+}
+
 func parseFlavorParts(flavorParts []string) ([]fc.FlavorPart, error) {
 	defaultLog.Trace("controllers/flavor_controller:parseFlavorParts() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:parseFlavorParts() Leaving")
@@ -902,3 +1338,22 @@ func checkValidFlavorPermission(privileges []ct.PermissionInfo, requiredPermissi
 	}
 	return true
 }
+
+// forTenant returns a copy of the controller whose flavor, flavorgroup and host stores are restricted to the tenant
+// of the user making the request
+func (fcon *FlavorController) forTenant(r *http.Request) (*FlavorController, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:forTenant() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:forTenant() Leaving")
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:forTenant() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	scoped := *fcon
+	scoped.FStore = fcon.FStore.ForTenant(tenantId)
+	scoped.FGStore = fcon.FGStore.ForTenant(tenantId)
+	scoped.HStore = fcon.HStore.ForTenant(tenantId)
+	return &scoped, http.StatusOK, nil
+}
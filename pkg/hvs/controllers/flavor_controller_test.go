@@ -5,19 +5,28 @@
 package controllers_test
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/mocks"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	fm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
 	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/mocks"
+	ct "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -130,6 +139,34 @@ var _ = Describe("FlavorController", func() {
 				Expect(len(sfs.SignedFlavors)).To(Equal(0))
 			})
 		})
+		Context("When filtered by a custom metadata field defined in the schema", func() {
+			It("Should return the flavors with a matching custom metadata value", func() {
+				flavorController.MetadataSchema = fm.MetadataSchema{{Name: "change_ticket", Type: fm.MetadataFieldTypeString}}
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/flavors?metadataKey=change_ticket&metadataValue=CHG0012345", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var sfs *hvs.SignedFlavorCollection
+				err = json.Unmarshal(w.Body.Bytes(), &sfs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(sfs.SignedFlavors)).To(Equal(0))
+			})
+		})
+		Context("When filtered by a custom metadata field not defined in the schema", func() {
+			It("Should return 400 Error code", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/flavors?metadataKey=change_ticket&metadataValue=CHG0012345", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 
 	// Specs for HTTP Get to "/flavors/{flavor_id}"
@@ -187,6 +224,136 @@ var _ = Describe("FlavorController", func() {
 		})
 	})
 
+	// Specs for HTTP Post to "/flavors/{flavorId}/signatures"
+	Describe("Add a co-signer's signature to a Flavor", func() {
+		var coSignerKey *rsa.PrivateKey
+		var coSignature string
+
+		BeforeEach(func() {
+			var coSignerCertPem string
+			var err error
+			coSignerKey, coSignerCertPem, err = crypt.CreateSelfSignedCertAndRSAPrivKeys(2048)
+			Expect(err).NotTo(HaveOccurred())
+			coSignerCert, err := crypt.GetCertFromPem([]byte(coSignerCertPem))
+			Expect(err).NotTo(HaveOccurred())
+			(*flavorController.CertStore)[models.CertTypesFlavorCoSigning.String()] = &models.CertificateStore{
+				Certificates: []x509.Certificate{*coSignerCert},
+			}
+
+			signedFlavor, err := flavorStore.Retrieve(uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signedFlavor.AddSignature(coSignerKey)).To(Succeed())
+			coSignature = signedFlavor.AdditionalSignatures[len(signedFlavor.AdditionalSignatures)-1]
+		})
+
+		postSignature := func(signature string) *httptest.ResponseRecorder {
+			router.Handle("/flavors/{id}/signatures", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.AddSignature))).Methods("POST")
+			body, _ := json.Marshal(models.FlavorSignatureCreateRequest{Signature: signature})
+			req, err := http.NewRequest("POST", "/flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3/signatures", strings.NewReader(string(body)))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Provide the signature of a trusted co-signer", func() {
+			It("Should add the signature to the Flavor once", func() {
+				w = postSignature(coSignature)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var signedFlavor hvs.SignedFlavor
+				Expect(json.Unmarshal(w.Body.Bytes(), &signedFlavor)).To(Succeed())
+				Expect(signedFlavor.AdditionalSignatures).To(Equal([]string{coSignature}))
+				Expect(signedFlavor.CountSigners([]*rsa.PublicKey{&coSignerKey.PublicKey})).To(Equal(1))
+
+				w = postSignature(coSignature)
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+		Context("Provide the signature of an untrusted signer", func() {
+			It("Should return 400 response code", func() {
+				untrustedKey, _, err := crypt.CreateSelfSignedCertAndRSAPrivKeys(2048)
+				Expect(err).NotTo(HaveOccurred())
+				signedFlavor, err := flavorStore.Retrieve(uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(signedFlavor.AddSignature(untrustedKey)).To(Succeed())
+
+				w = postSignature(signedFlavor.AdditionalSignatures[0])
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Patch to "/flavors/{flavorId}"
+	Describe("Patch a Flavor", func() {
+		var flavorSigningKey *rsa.PrivateKey
+
+		BeforeEach(func() {
+			var err error
+			flavorSigningKey, _, err = crypt.CreateSelfSignedCertAndRSAPrivKeys(2048)
+			Expect(err).NotTo(HaveOccurred())
+			(*flavorController.CertStore)[models.CertTypesFlavorSigning.String()].Key = flavorSigningKey
+		})
+
+		patchFlavor := func(id, contentType, patch string) *httptest.ResponseRecorder {
+			router.Handle("/flavors/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Update))).Methods("PATCH")
+			req, err := http.NewRequest("PATCH", "/flavors/"+id, strings.NewReader(patch))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Provide a merge patch of the label and a PCR value", func() {
+			It("Should patch and sign the Flavor again", func() {
+				w = patchFlavor("c36b5412-8c02-4e08-8a74-8bfa40425cf3", consts.HTTPMediaTypeMergePatch,
+					`{"meta":{"description":{"label":"patched_label"}},"pcrs":{"SHA1":{"pcr_0":{"value":"1111111111111111111111111111111111111111"}}}}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var signedFlavor hvs.SignedFlavor
+				Expect(json.Unmarshal(w.Body.Bytes(), &signedFlavor)).To(Succeed())
+				Expect(signedFlavor.Flavor.Meta.Description.Label).To(Equal("patched_label"))
+				Expect(signedFlavor.Flavor.Pcrs["SHA1"]["pcr_0"].Value).To(Equal("1111111111111111111111111111111111111111"))
+				// the other PCRs are kept
+				Expect(signedFlavor.Flavor.Pcrs["SHA1"]).To(HaveKey("pcr_17"))
+
+				Expect(signedFlavor.Verify(&flavorSigningKey.PublicKey)).To(Succeed())
+			})
+		})
+		Context("Provide a merge patch of a section that cannot be patched", func() {
+			It("Should return 400 response code", func() {
+				w = patchFlavor("c36b5412-8c02-4e08-8a74-8bfa40425cf3", consts.HTTPMediaTypeMergePatch,
+					`{"hardware":{"processor_info":"patched"}}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a merge patch of the flavor part", func() {
+			It("Should return 400 response code", func() {
+				w = patchFlavor("c36b5412-8c02-4e08-8a74-8bfa40425cf3", consts.HTTPMediaTypeMergePatch,
+					`{"meta":{"description":{"flavor_part":"OS"}}}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a merge patch with the JSON Content-Type", func() {
+			It("Should return 415 response code", func() {
+				w = patchFlavor("c36b5412-8c02-4e08-8a74-8bfa40425cf3", consts.HTTPMediaTypeJson,
+					`{"meta":{"description":{"label":"patched_label"}}}`)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+		Context("Provide a merge patch of a non-existent Flavor", func() {
+			It("Should return 404 response code", func() {
+				w = patchFlavor("73755fda-c910-46be-821f-e8ddeab189e9", consts.HTTPMediaTypeMergePatch,
+					`{"meta":{"description":{"label":"patched_label"}}}`)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	// Specs for HTTP Post to "/flavor"
 	Describe("Create a new flavor", func() {
 		Context("Provide a invalid Create request with XSS Attack Strings", func() {
@@ -1008,5 +1175,295 @@ var _ = Describe("FlavorController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+
+		Context("Provide a delta Flavor request for a base flavor that does not exist", func() {
+			It("Should return 400 Error code", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				flavorJson := `{
+								"flavor_collection": {
+									"flavors": [
+										{
+											"flavor": {
+												"meta": {
+													"description": {
+														"flavor_part": "PLATFORM",
+														"label": "ImportDeltaFlavor"
+													},
+													"vendor": "INTEL",
+													"base_flavor_id": "73755fda-c910-46be-821f-e8ddeab189e9"
+												},
+												"pcrs": {
+													"SHA1": {
+														"pcr_0": {
+															"value": "308c314172d79c8ed0c91d91eb6d6b78a2a451a0"
+														}
+													}
+												}
+											}
+										}
+									]
+								},
+								"flavorgroup_names": ["custom-flavorgroup"]
+							}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a delta Flavor request with a flavor part different from the base flavor", func() {
+			It("Should return 400 Error code", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				flavorJson := `{
+								"flavor_collection": {
+									"flavors": [
+										{
+											"flavor": {
+												"meta": {
+													"description": {
+														"flavor_part": "OS",
+														"label": "ImportDeltaFlavor"
+													},
+													"vendor": "INTEL",
+													"base_flavor_id": "c36b5412-8c02-4e08-8a74-8bfa40425cf3"
+												},
+												"pcrs": {
+													"SHA1": {
+														"pcr_0": {
+															"value": "308c314172d79c8ed0c91d91eb6d6b78a2a451a0"
+														}
+													}
+												}
+											}
+										}
+									]
+								},
+								"flavorgroup_names": ["custom-flavorgroup"]
+							}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a signed delta Flavor request with a flavor part different from the base flavor", func() {
+			It("Should return 400 Error code", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				flavorJson := `{
+								"signed_flavor_collection": {
+									"signed_flavors": [
+										{
+											"flavor": {
+												"meta": {
+													"description": {
+														"flavor_part": "OS",
+														"label": "ImportDeltaFlavor"
+													},
+													"vendor": "INTEL",
+													"base_flavor_id": "c36b5412-8c02-4e08-8a74-8bfa40425cf3"
+												},
+												"pcrs": {
+													"SHA1": {
+														"pcr_0": {
+															"value": "308c314172d79c8ed0c91d91eb6d6b78a2a451a0"
+														}
+													}
+												}
+											},
+											"signature": "c2lnbmF0dXJl"
+										}
+									]
+								},
+								"flavorgroup_names": ["custom-flavorgroup"]
+							}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a Flavor request with custom metadata that does not match the schema", func() {
+			It("Should return 400 Error code", func() {
+				flavorController.MetadataSchema = fm.MetadataSchema{
+					{Name: "owner", Type: fm.MetadataFieldTypeString, Required: true},
+					{Name: "baseline_version", Type: fm.MetadataFieldTypeInteger},
+				}
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				flavorJson := `{
+								"flavor_collection": {
+									"flavors": [
+										{
+											"flavor": {
+												"meta": {
+													"description": {
+														"flavor_part": "PLATFORM",
+														"label": "ImportMetadataFlavor"
+													},
+													"vendor": "INTEL",
+													"custom_metadata": {
+														"owner": "platform-team",
+														"baseline_version": "v3"
+													}
+												},
+												"pcrs": {
+													"SHA1": {
+														"pcr_0": {
+															"value": "308c314172d79c8ed0c91d91eb6d6b78a2a451a0"
+														}
+													}
+												}
+											}
+										}
+									]
+								},
+								"flavorgroup_names": ["custom-flavorgroup"]
+							}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a Flavor request with an invalid event log exclusion pattern", func() {
+			It("Should return 400 Error code", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				flavorJson := `{
+								"flavor_collection": {
+									"flavors": [
+										{
+											"flavor": {
+												"meta": {
+													"description": {
+														"flavor_part": "PLATFORM",
+														"label": "ImportExclusionFlavor"
+													},
+													"vendor": "INTEL"
+												},
+												"pcrs": {
+													"SHA256": {
+														"pcr_17": {
+															"value": "d2a7b9c5e07f2ed0ecb9ac1f7c3a38cc9e5ab30cd27b0c4c3d9d7d2c2c2ab0b3",
+															"exclude": [
+																{
+																	"label": "LCP_[POLICY"
+																}
+															]
+														}
+													}
+												}
+											}
+										}
+									]
+								},
+								"flavorgroup_names": ["custom-flavorgroup"]
+							}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a Create request with the host manifest of a good known host", func() {
+			It("Should return 201 Response code and the flavors of the host", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				flavorSigningKey, _, err := crypt.CreateSelfSignedCertAndRSAPrivKeys(2048)
+				Expect(err).NotTo(HaveOccurred())
+				(*flavorController.CertStore)[models.CertTypesFlavorSigning.String()].Key = flavorSigningKey
+				manifestJson, err := ioutil.ReadFile("../../lib/verifier/test_data/intel20/host_manifest.json")
+				Expect(err).NotTo(HaveOccurred())
+				flavorJson := `{
+						"host_manifest": ` + string(manifestJson) + `,
+						"partial_flavor_types": ["PLATFORM", "OS", "HOST_UNIQUE"]
+					}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var sfs hvs.SignedFlavorCollection
+				Expect(json.Unmarshal(w.Body.Bytes(), &sfs)).To(Succeed())
+				Expect(sfs.GetFlavors("PLATFORM")).To(HaveLen(1))
+				Expect(sfs.GetFlavors("OS")).To(HaveLen(1))
+				Expect(sfs.GetFlavors("HOST_UNIQUE")).To(HaveLen(1))
+				Expect(sfs.GetFlavors("HOST_UNIQUE")[0].Flavor.Meta.Description.HardwareUUID.String()).To(Equal("0005ae6e-36d6-e711-906e-001560a04062"))
+			})
+		})
+
+		Context("Provide a Create request with both a host manifest and a connection string", func() {
+			It("Should return 400 Error code", func() {
+				router.Handle("/flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Create))).Methods("POST")
+				manifestJson, err := ioutil.ReadFile("../../lib/verifier/test_data/intel20/host_manifest.json")
+				Expect(err).NotTo(HaveOccurred())
+				flavorJson := `{
+						"connection_string": "intel:https://another.ta.ip.com:1443",
+						"host_manifest": ` + string(manifestJson) + `
+					}`
+				req, err := http.NewRequest(
+					"POST",
+					"/flavors",
+					strings.NewReader(flavorJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserPermissions(req, []ct.PermissionInfo{{Service: "HVS", Rules: []string{"flavors:create"}}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 })
@@ -6,17 +6,21 @@
 package controllers
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"io/ioutil"
 	"net/http"
 	"strings"
 )
@@ -45,9 +49,23 @@ func (controller FlavorFromAppManifestController) CreateSoftwareFlavor(w http.Re
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
 	}
 
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_app_manifest_controller:"+
+			"CreateSoftwareFlavor() %s : Unable to read request body", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to read request body"}
+	}
+
+	err = validation.ValidateXMLDocument(body)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_app_manifest_controller:"+
+			"CreateSoftwareFlavor() %s : Invalid XML request body", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode XML request body"}
+	}
+
 	var appManifestRequest *hvs.ManifestRequest
-	dec := xml.NewDecoder(r.Body)
-	err := dec.Decode(&appManifestRequest)
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	err = dec.Decode(&appManifestRequest)
 	if err != nil {
 		secLog.WithError(err).Errorf("controllers/flavor_from_app_manifest_controller:"+
 			"CreateSoftwareFlavor() %s : Failed to decode request body as manifest request", commLogMsg.InvalidInputBadEncoding)
@@ -91,7 +109,7 @@ func (controller FlavorFromAppManifestController) CreateSoftwareFlavor(w http.Re
 			"CreateSoftwareFlavor() %s : Failed to get host connector instance", commLogMsg.AppRuntimeErr)
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to get host connector instance"}
 	}
-	measurement, err := hcInstance.GetMeasurementFromManifest(appManifestRequest.Manifest)
+	measurement, err := hcInstance.GetMeasurementFromManifest(r.Context(), appManifestRequest.Manifest)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/flavor_from_app_manifest_controller:"+
 			"CreateSoftwareFlavor() %s : Failed to get measurement from manifest", commLogMsg.AppRuntimeErr)
@@ -112,11 +130,11 @@ func (controller FlavorFromAppManifestController) CreateSoftwareFlavor(w http.Re
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error getting software flavor from measurement"}
 	}
 
-	_, err = controller.FlavorController.createFlavors(models.FlavorCreateRequest{FlavorCollection: hvs.FlavorCollection{Flavors: []hvs.Flavors{{Flavor: *softwareFlavor}}}, FlavorgroupNames: appManifestRequest.FlavorGroupNames})
+	_, err = controller.FlavorController.createFlavors(r.Context(), models.FlavorCreateRequest{FlavorCollection: hvs.FlavorCollection{Flavors: []hvs.Flavors{{Flavor: *softwareFlavor}}}, FlavorgroupNames: appManifestRequest.FlavorGroupNames})
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/flavor_from_app_manifest_controller:"+
 			"CreateSoftwareFlavor() %s : Error creating new SOFTWARE flavor", commLogMsg.AppRuntimeErr)
-		if strings.Contains(err.Error(), "duplicate key") {
+		if postgres.IsDuplicateKeyError(err) {
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with same id/label already exists"}
 		}
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error creating new SOFTWARE flavor"}
@@ -0,0 +1,126 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/rim"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// FlavorFromRimController creates PLATFORM flavors from the reference integrity manifests (RIM) of platform vendors
+type FlavorFromRimController struct {
+	FlavorController FlavorController
+}
+
+func NewFlavorFromRimController(fc FlavorController) *FlavorFromRimController {
+	return &FlavorFromRimController{
+		FlavorController: fc,
+	}
+}
+
+// CreatePlatformFlavor verifies the signature of the base RIM against the trusted RIM CAs and creates the
+// PLATFORM flavor from the reference measurements of the support RIM
+func (controller FlavorFromRimController) CreatePlatformFlavor(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_from_rim_controller:CreatePlatformFlavor() Entering")
+	defer defaultLog.Trace("controllers/flavor_from_rim_controller:CreatePlatformFlavor() Leaving")
+
+	fcon, status, err := controller.FlavorController.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : The request body"+
+			" is not provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var rimImportRequest hvs.RimImportRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rimImportRequest); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : "+
+			"Failed to decode request body as RIM import request", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if len(rimImportRequest.BaseRim) == 0 || len(rimImportRequest.SupportRim) == 0 {
+		secLog.Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : base_rim and support_rim "+
+			"must be specified", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "base_rim and support_rim must be specified"}
+	}
+
+	var rimCaCerts []x509.Certificate
+	if caStore, ok := (*fcon.CertStore)[models.CaCertTypesRimCa.String()]; ok && caStore != nil {
+		rimCaCerts = caStore.Certificates
+	}
+	signerCert, err := rim.VerifyBaseRimSignature(rimImportRequest.BaseRim, rimCaCerts)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : "+
+			"Base RIM signature verification failed", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Base RIM is not signed by a trusted RIM signer"}
+	}
+
+	swid, err := rim.ParseBaseRim(rimImportRequest.BaseRim)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : "+
+			"Invalid base RIM", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	firmwareManifest, err := swid.GetFirmwareManifest(rimImportRequest.SupportRim, rimImportRequest.Feature)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : "+
+			"Invalid support RIM", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	// the expected event log of the support RIM must replay to a consistent PLATFORM flavor
+	if _, err = types.NewFirmwarePlatformFlavor(firmwareManifest); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : "+
+			"Invalid reference measurements in support RIM", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	flavorCreateRequest := models.FlavorCreateRequest{
+		FirmwareManifest: firmwareManifest,
+		FlavorgroupNames: rimImportRequest.FlavorgroupNames,
+		FlavorParts:      []fc.FlavorPart{fc.FlavorPartPlatform},
+	}
+	if err := validateFlavorCreateRequest(flavorCreateRequest); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:CreatePlatformFlavor() %s : %s",
+			commLogMsg.InvalidInputBadParam, err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	signedFlavors, err := fcon.createFlavors(r.Context(), flavorCreateRequest)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("controllers/flavor_from_rim_controller:"+
+			"CreatePlatformFlavor() %s : Error creating new PLATFORM flavor", commLogMsg.AppRuntimeErr)
+		if postgres.IsDuplicateKeyError(err) {
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with same id/label already exists"}
+		}
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error creating new PLATFORM flavor"}
+	}
+
+	secLog.WithField("tagId", swid.TagID).WithField("signer", signerCert.Subject.String()).
+		Info("controllers/flavor_from_rim_controller:CreatePlatformFlavor() PLATFORM flavor created from RIM")
+	return hvs.SignedFlavorCollection{SignedFlavors: signedFlavors}, http.StatusCreated, nil
+}
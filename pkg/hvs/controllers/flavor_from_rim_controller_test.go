@@ -0,0 +1,209 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	dm "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+type rimSignerKeyStore struct {
+	key  *rsa.PrivateKey
+	cert []byte
+}
+
+func (ks rimSignerKeyStore) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
+	return ks.key, ks.cert, nil
+}
+
+// newRimCertificates returns a RIM CA certificate and the key store of a RIM signer issued by the CA
+func newRimCertificates() (*x509.Certificate, rimSignerKeyStore) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "RIM CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caCertBytes, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	caCert, err := x509.ParseCertificate(caCertBytes)
+	Expect(err).NotTo(HaveOccurred())
+
+	signerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	signerTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "RIM Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signerCertBytes, err := x509.CreateCertificate(rand.Reader, &signerTemplate, caCert, &signerKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	return caCert, rimSignerKeyStore{key: signerKey, cert: signerCertBytes}
+}
+
+// newRimImportRequest returns a RIM import request with a support RIM measuring the firmware into PCR0
+// and a base RIM referencing it, signed with the key store
+func newRimImportRequest(ks rimSignerKeyStore) hvs.RimImportRequest {
+	var supportRim bytes.Buffer
+	specIdEvent := bytes.NewBufferString("Spec ID Event03\x00")
+	binary.Write(specIdEvent, binary.LittleEndian, []uint32{0, 0x00020000, 2})
+	binary.Write(specIdEvent, binary.LittleEndian, []uint16{0x0004, sha1.Size, 0x000B, sha256.Size})
+	specIdEvent.WriteByte(0)
+	binary.Write(&supportRim, binary.LittleEndian, []uint32{0, 0x00000003})
+	supportRim.Write(make([]byte, sha1.Size))
+	binary.Write(&supportRim, binary.LittleEndian, uint32(specIdEvent.Len()))
+	supportRim.Write(specIdEvent.Bytes())
+
+	firmwareBlob := []byte("firmware blob")
+	sha1Digest := sha1.Sum(firmwareBlob)
+	sha256Digest := sha256.Sum256(firmwareBlob)
+	binary.Write(&supportRim, binary.LittleEndian, []uint32{0, 0x80000008, 2})
+	binary.Write(&supportRim, binary.LittleEndian, uint16(0x0004))
+	supportRim.Write(sha1Digest[:])
+	binary.Write(&supportRim, binary.LittleEndian, uint16(0x000B))
+	supportRim.Write(sha256Digest[:])
+	binary.Write(&supportRim, binary.LittleEndian, uint32(len(firmwareBlob)))
+	supportRim.Write(firmwareBlob)
+
+	supportRimHash := sha256.Sum256(supportRim.Bytes())
+	swid := fmt.Sprintf(`<SoftwareIdentity xmlns="http://standards.iso.org/iso/19770/-2/2015/schema.xsd" `+
+		`xmlns:SHA256="http://www.w3.org/2001/04/xmlenc#sha256" xmlns:rim="https://trustedcomputinggroup.org/resource/tcg-reference-integrity-manifest-rim-information-model/" `+
+		`name="Example.com BIOS" tagId="94f6b457-9ac9-4d35-9b3f-78804173b65a" tagVersion="0" version="01">`+
+		`<Meta rim:firmwareManufacturerStr="Example.com" rim:firmwareVersion="1.0.13"/>`+
+		`<Payload><File name="Example.com.BIOS.01.rimel" size="%d" SHA256:hash="%s"/></Payload>`+
+		`</SoftwareIdentity>`, supportRim.Len(), hex.EncodeToString(supportRimHash[:]))
+
+	doc := etree.NewDocument()
+	Expect(doc.ReadFromString(swid)).To(Succeed())
+	ctx := dsig.NewDefaultSigningContext(ks)
+	ctx.IdAttribute = "tagId"
+	signed, err := ctx.SignEnveloped(doc.Root())
+	Expect(err).NotTo(HaveOccurred())
+	doc.SetRoot(signed)
+	baseRim, err := doc.WriteToBytes()
+	Expect(err).NotTo(HaveOccurred())
+
+	return hvs.RimImportRequest{
+		BaseRim:    baseRim,
+		SupportRim: supportRim.Bytes(),
+	}
+}
+
+var _ = Describe("FlavorFromRimController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var certStore *dm.CertificatesStore
+	var rimCaCert *x509.Certificate
+	var rimSigner rimSignerKeyStore
+	var flavorFromRimController *controllers.FlavorFromRimController
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		certStore = mocks.NewFakeCertificatesStore()
+		(*certStore)[dm.CertTypesFlavorSigning.String()].Key, _ = rsa.GenerateKey(rand.Reader, 3072)
+
+		rimCaCert, rimSigner = newRimCertificates()
+		(*certStore)[dm.CaCertTypesRimCa.String()] = &dm.CertificateStore{
+			Certificates: []x509.Certificate{*rimCaCert},
+		}
+
+		flavorFromRimController = controllers.NewFlavorFromRimController(controllers.FlavorController{
+			FStore:    mocks.NewMockFlavorStore(),
+			FGStore:   mocks.NewFakeFlavorgroupStore(),
+			HStore:    mocks.NewMockHostStore(),
+			CertStore: certStore,
+		})
+		router.Handle("/flavor-from-rim", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorFromRimController.
+			CreatePlatformFlavor))).Methods("POST")
+	})
+
+	Describe("Create a new platform flavor from RIM", func() {
+		Context("Provide a RIM signed by a trusted RIM signer", func() {
+			It("Should create a new PLATFORM flavor", func() {
+				requestBody, err := json.Marshal(newRimImportRequest(rimSigner))
+				Expect(err).NotTo(HaveOccurred())
+
+				req, err := http.NewRequest("POST", "/flavor-from-rim", bytes.NewReader(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var signedFlavors hvs.SignedFlavorCollection
+				Expect(json.Unmarshal(w.Body.Bytes(), &signedFlavors)).To(Succeed())
+				Expect(signedFlavors.SignedFlavors).To(HaveLen(1))
+				Expect(signedFlavors.SignedFlavors[0].Flavor.Meta.Description.FlavorPart).To(Equal("PLATFORM"))
+				Expect(signedFlavors.SignedFlavors[0].Flavor.Bios.BiosVersion).To(Equal("1.0.13"))
+			})
+		})
+
+		Context("Provide a RIM signed by an untrusted RIM signer", func() {
+			It("Should fail to create the flavor", func() {
+				_, untrustedSigner := newRimCertificates()
+				requestBody, err := json.Marshal(newRimImportRequest(untrustedSigner))
+				Expect(err).NotTo(HaveOccurred())
+
+				req, err := http.NewRequest("POST", "/flavor-from-rim", bytes.NewReader(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a support RIM not referenced by the base RIM", func() {
+			It("Should fail to create the flavor", func() {
+				rimImportRequest := newRimImportRequest(rimSigner)
+				rimImportRequest.SupportRim = append(rimImportRequest.SupportRim, 0)
+				requestBody, err := json.Marshal(rimImportRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				req, err := http.NewRequest("POST", "/flavor-from-rim", bytes.NewReader(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
@@ -0,0 +1,352 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	fm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	fu "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
+	hcConstants "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// maxPcrIndex is the highest pcr index that can be learnt
+const maxPcrIndex = 23
+
+type FlavorLearningController struct {
+	Store   domain.FlavorLearningStore
+	HStore  domain.HostStore
+	HSStore domain.HostStatusStore
+}
+
+var flavorLearningSearchParams = map[string]bool{"id": true, "labelEqualTo": true}
+
+func (controller FlavorLearningController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:Create() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_learning_controller:Create() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqFlavorLearning hvs.FlavorLearning
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&reqFlavorLearning)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_learning_controller:Create() %s :  Failed to decode request body as FlavorLearning", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if reqFlavorLearning.StartTime.IsZero() {
+		reqFlavorLearning.StartTime = time.Now().UTC()
+	}
+	if err := validateFlavorLearning(&reqFlavorLearning); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_learning_controller:Create() %s : Invalid FlavorLearning", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	for _, hostId := range reqFlavorLearning.HostIDs {
+		if _, err := controller.HStore.Retrieve(hostId, nil); err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				secLog.WithField("id", hostId).Errorf("controllers/flavor_learning_controller:Create() %s : Host with given ID does not exist", commLogMsg.InvalidInputBadParam)
+				return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Host with given ID does not exist"}
+			}
+			defaultLog.WithError(err).WithField("id", hostId).Error("controllers/flavor_learning_controller:Create() Host retrieve failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Host"}
+		}
+	}
+
+	existingFlavorLearnings, err := controller.Store.Search(&models.FlavorLearningFilterCriteria{
+		LabelEqualTo: reqFlavorLearning.Label,
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_learning_controller:Create() FlavorLearning search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search FlavorLearning for label"}
+	}
+	if existingFlavorLearnings != nil && len(existingFlavorLearnings.FlavorLearnings) > 0 {
+		secLog.WithField("Label", reqFlavorLearning.Label).Warningf("%s: Trying to create duplicated FlavorLearning from addr: %s", commLogMsg.InvalidInputBadParam, r.RemoteAddr)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "FlavorLearning with same label already exist."}
+	}
+
+	newFlavorLearning, err := controller.Store.Create(&reqFlavorLearning)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/flavor_learning_controller:Create() FlavorLearning create failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error on inserting FlavorLearning"}
+	}
+	secLog.WithField("Label", reqFlavorLearning.Label).Infof("%s: FlavorLearning created by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return newFlavorLearning, http.StatusCreated, nil
+}
+
+func (controller FlavorLearningController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), flavorLearningSearchParams); err != nil {
+		secLog.Errorf("controllers/flavor_learning_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getFlavorLearningFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_learning_controller:Search() %s Invalid input provided in filter criteria", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid input provided in filter criteria"}
+	}
+
+	flavorLearningCollection, err := controller.Store.Search(filter)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/flavor_learning_controller:Search() FlavorLearning search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search FlavorLearning"}
+	}
+
+	secLog.Infof("%s: Return flavor-learning query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return flavorLearningCollection, http.StatusOK, nil
+}
+
+func (controller FlavorLearningController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:Retrieve() Leaving")
+
+	flavorLearning, status, err := controller.retrieveFlavorLearning(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("ID", flavorLearning.ID).Infof("FlavorLearning retrieved by: %s", r.RemoteAddr)
+	return flavorLearning, http.StatusOK, nil
+}
+
+func (controller FlavorLearningController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:Delete() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:Delete() Leaving")
+
+	delFlavorLearning, status, err := controller.retrieveFlavorLearning(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if err := controller.Store.Delete(delFlavorLearning.ID); err != nil {
+		defaultLog.WithError(err).WithField("id", delFlavorLearning.ID).Error(
+			"controllers/flavor_learning_controller:Delete() failed to delete FlavorLearning")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete FlavorLearning"}
+	}
+	secLog.WithField("ID", delFlavorLearning.ID).Infof("FlavorLearning deleted by: %s", r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+// Proposal learns a flavor from the host manifests collected on the golden hosts during the learning window
+func (controller FlavorLearningController) Proposal(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:Proposal() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:Proposal() Leaving")
+
+	flavorLearning, status, err := controller.retrieveFlavorLearning(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	now := time.Now().UTC()
+	if now.Before(flavorLearning.StartTime) {
+		secLog.WithField("ID", flavorLearning.ID).Errorf("controllers/flavor_learning_controller:Proposal() %s : Learning window has not started", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The learning window of the FlavorLearning has not started"}
+	}
+	toDate := flavorLearning.EndTime
+	if now.Before(toDate) {
+		toDate = now
+	}
+
+	var hostManifests []hcTypes.HostManifest
+	for _, hostId := range flavorLearning.HostIDs {
+		hostStatuses, err := controller.HSStore.Search(&models.HostStatusFilterCriteria{
+			HostId:   hostId,
+			FromDate: flavorLearning.StartTime,
+			ToDate:   toDate,
+		})
+		if err != nil {
+			defaultLog.WithError(err).WithField("HostId", hostId).Error("controllers/flavor_learning_controller:Proposal() HostStatus search failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search HostStatus of the golden hosts"}
+		}
+		for _, hostStatus := range hostStatuses {
+			if !hostStatus.HostManifest.PcrManifest.IsEmpty() {
+				hostManifests = append(hostManifests, hostStatus.HostManifest)
+			}
+		}
+	}
+	if len(hostManifests) == 0 {
+		secLog.WithField("ID", flavorLearning.ID).Errorf("controllers/flavor_learning_controller:Proposal() %s : No host manifests collected", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "No host manifests were collected from the golden hosts during the learning window"}
+	}
+
+	proposal, err := getFlavorLearningProposal(flavorLearning, hostManifests)
+	if err != nil {
+		defaultLog.WithError(err).WithField("ID", flavorLearning.ID).Error("controllers/flavor_learning_controller:Proposal() Error learning flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error learning flavor from the host manifests"}
+	}
+
+	secLog.WithField("ID", flavorLearning.ID).Infof("FlavorLearning proposal retrieved by: %s", r.RemoteAddr)
+	return proposal, http.StatusOK, nil
+}
+
+func (controller FlavorLearningController) retrieveFlavorLearning(r *http.Request) (*hvs.FlavorLearning, int, error) {
+	id := uuid.MustParse(mux.Vars(r)["id"])
+
+	flavorLearning, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/flavor_learning_controller:retrieveFlavorLearning() FlavorLearning with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "FlavorLearning with given ID does not exist"}
+		}
+		secLog.WithError(err).WithField("id", id).Error(
+			"controllers/flavor_learning_controller:retrieveFlavorLearning() failed to retrieve FlavorLearning")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve FlavorLearning"}
+	}
+	return flavorLearning, http.StatusOK, nil
+}
+
+// getFlavorLearningProposal builds the proposed flavor from the pcrs learnt from the host manifests
+func getFlavorLearningProposal(flavorLearning *hvs.FlavorLearning, hostManifests []hcTypes.HostManifest) (*hvs.FlavorLearningProposal, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:getFlavorLearningProposal() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:getFlavorLearningProposal() Leaving")
+
+	var pfutil fu.PlatformFlavorUtil
+	var pcrManifests []hcTypes.PcrManifest
+	for _, hostManifest := range hostManifests {
+		pcrManifests = append(pcrManifests, hostManifest.PcrManifest)
+	}
+	learned := pfutil.LearnPcrDetails(pcrManifests, flavorLearning.Pcrs)
+
+	var flavorPart fc.FlavorPart
+	if err := (&flavorPart).Parse(flavorLearning.FlavorPart); err != nil {
+		return nil, errors.Wrap(err, "Invalid flavor part")
+	}
+	hostInfo := hostManifests[0].HostInfo
+	var vendor hcConstants.Vendor
+	if err := (&vendor).GetVendorFromOSName(hostInfo.OSName); err != nil {
+		defaultLog.WithError(err).Warn("controllers/flavor_learning_controller:getFlavorLearningProposal() Could not determine vendor of the golden hosts")
+	}
+	meta, err := pfutil.GetMetaSectionDetails(&hostInfo, nil, "", flavorPart, vendor)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating meta section of the flavor")
+	}
+	meta.Description.Label = flavorLearning.Label
+	meta.Description.Source = ""
+	var bios *fm.Bios
+	if flavorPart == fc.FlavorPartPlatform {
+		bios = pfutil.GetBiosSectionDetails(&hostInfo)
+	}
+
+	proposal := hvs.FlavorLearningProposal{
+		FlavorLearningID:  flavorLearning.ID,
+		HostManifestCount: len(hostManifests),
+		Flavor:            *fm.NewFlavor(meta, bios, nil, learned.Pcrs, nil, nil),
+		VolatilePcrs:      make(map[string][]string),
+		VolatileEvents:    make(map[string]map[string][]hcTypes.EventLog),
+	}
+	for digestAlgorithm, pcrIndices := range learned.VolatilePcrs {
+		for _, pcrIndex := range pcrIndices {
+			proposal.VolatilePcrs[digestAlgorithm.String()] = append(proposal.VolatilePcrs[digestAlgorithm.String()], pcrIndex.String())
+		}
+	}
+	for digestAlgorithm, pcrs := range learned.VolatileEvents {
+		proposal.VolatileEvents[digestAlgorithm.String()] = make(map[string][]hcTypes.EventLog)
+		for pcrIndex, events := range pcrs {
+			proposal.VolatileEvents[digestAlgorithm.String()][pcrIndex.String()] = events
+		}
+	}
+	return &proposal, nil
+}
+
+func validateFlavorLearning(flavorLearning *hvs.FlavorLearning) error {
+	defaultLog.Trace("controllers/flavor_learning_controller:validateFlavorLearning() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:validateFlavorLearning() Leaving")
+
+	if flavorLearning.Label == "" {
+		return errors.New("label must be specified")
+	}
+	if err := validation.ValidateStrings([]string{flavorLearning.Label}); err != nil {
+		return errors.New("Valid contents for label must be specified")
+	}
+
+	var flavorPart fc.FlavorPart
+	if err := (&flavorPart).Parse(flavorLearning.FlavorPart); err != nil ||
+		(flavorPart != fc.FlavorPartPlatform && flavorPart != fc.FlavorPartOs) {
+		return errors.New("flavor_part must be PLATFORM or OS")
+	}
+	flavorLearning.FlavorPart = flavorPart.String()
+
+	if len(flavorLearning.Pcrs) == 0 {
+		return errors.New("pcrs must be specified")
+	}
+	pcrs := make(map[int]bool)
+	for _, pcr := range flavorLearning.Pcrs {
+		if pcr < 0 || pcr > maxPcrIndex || pcrs[pcr] {
+			return errors.Errorf("Invalid or duplicated pcr index %d", pcr)
+		}
+		pcrs[pcr] = true
+	}
+	sort.Ints(flavorLearning.Pcrs)
+
+	if len(flavorLearning.HostIDs) == 0 {
+		return errors.New("host_ids must be specified")
+	}
+	hostIds := make(map[uuid.UUID]bool)
+	for _, hostId := range flavorLearning.HostIDs {
+		if hostId == uuid.Nil || hostIds[hostId] {
+			return errors.New("Invalid or duplicated host id in host_ids")
+		}
+		hostIds[hostId] = true
+	}
+
+	if flavorLearning.EndTime.IsZero() || !flavorLearning.EndTime.After(flavorLearning.StartTime) {
+		return errors.New("end_time must be specified and be after start_time")
+	}
+	return nil
+}
+
+func getFlavorLearningFilterCriteria(params url.Values) (*models.FlavorLearningFilterCriteria, error) {
+	defaultLog.Trace("controllers/flavor_learning_controller:getFlavorLearningFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/flavor_learning_controller:getFlavorLearningFilterCriteria() Leaving")
+
+	var criteria models.FlavorLearningFilterCriteria
+	if id := params.Get("id"); id != "" {
+		id, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errors.New("Invalid id query param value, must be UUID")
+		}
+		criteria.Id = id
+	}
+	if label := params.Get("labelEqualTo"); label != "" {
+		if err := validation.ValidateStrings([]string{label}); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for LabelEqualTo must be specified")
+		}
+		criteria.LabelEqualTo = label
+	}
+	return &criteria, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FlavorLearningController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var flavorLearningStore *mocks2.MockFlavorLearningStore
+	var flavorLearningController *controllers.FlavorLearningController
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		flavorLearningStore = mocks2.NewFakeFlavorLearningStore()
+		flavorLearningController = &controllers.FlavorLearningController{
+			Store:   flavorLearningStore,
+			HStore:  mocks2.NewMockHostStore(),
+			HSStore: mocks2.NewMockHostStatusStore(),
+		}
+	})
+
+	createFlavorLearning := func(flavorLearning hvs.FlavorLearning) *httptest.ResponseRecorder {
+		router.Handle("/flavor-learning", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorLearningController.Create))).Methods("POST")
+		body, _ := json.Marshal(flavorLearning)
+		req, err := http.NewRequest("POST", "/flavor-learning", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Specs for HTTP Post to "/flavor-learning"
+	Describe("Create FlavorLearning", func() {
+		Context("Provide a valid FlavorLearning for registered golden hosts", func() {
+			It("Should create FlavorLearning", func() {
+				w = createFlavorLearning(hvs.FlavorLearning{
+					Label:      "rhel-kernel-update",
+					FlavorPart: "OS",
+					Pcrs:       []int{18, 17},
+					HostIDs:    []uuid.UUID{uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")},
+					EndTime:    time.Now().Add(24 * time.Hour),
+				})
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var flavorLearning hvs.FlavorLearning
+				err := json.Unmarshal(w.Body.Bytes(), &flavorLearning)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorLearning.ID).NotTo(Equal(uuid.Nil))
+				Expect(flavorLearning.Pcrs).To(Equal([]int{17, 18}))
+				Expect(flavorLearning.StartTime.IsZero()).To(BeFalse())
+			})
+		})
+		Context("Provide a FlavorLearning for a host that is not registered", func() {
+			It("Should fail to create FlavorLearning", func() {
+				w = createFlavorLearning(hvs.FlavorLearning{
+					Label:      "rhel-kernel-update",
+					FlavorPart: "OS",
+					Pcrs:       []int{17},
+					HostIDs:    []uuid.UUID{uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e5")},
+					EndTime:    time.Now().Add(24 * time.Hour),
+				})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a FlavorLearning with an invalid pcr index", func() {
+			It("Should fail to create FlavorLearning", func() {
+				w = createFlavorLearning(hvs.FlavorLearning{
+					Label:      "rhel-kernel-update",
+					FlavorPart: "OS",
+					Pcrs:       []int{24},
+					HostIDs:    []uuid.UUID{uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")},
+					EndTime:    time.Now().Add(24 * time.Hour),
+				})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a FlavorLearning with an end time before the start time", func() {
+			It("Should fail to create FlavorLearning", func() {
+				w = createFlavorLearning(hvs.FlavorLearning{
+					Label:      "rhel-kernel-update",
+					FlavorPart: "PLATFORM",
+					Pcrs:       []int{0},
+					HostIDs:    []uuid.UUID{uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")},
+					StartTime:  time.Now(),
+					EndTime:    time.Now().Add(-time.Hour),
+				})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/flavor-learning/{id}"
+	Describe("Retrieve FlavorLearning", func() {
+		Context("Retrieve a FlavorLearning that does not exist", func() {
+			It("Should return 404", func() {
+				router.Handle("/flavor-learning/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorLearningController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/flavor-learning/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/flavor-learning/{id}/proposal"
+	Describe("Retrieve FlavorLearning proposal", func() {
+		Context("Retrieve the proposal before the learning window starts", func() {
+			It("Should return 400", func() {
+				flavorLearning, err := flavorLearningStore.Create(&hvs.FlavorLearning{
+					Label:      "rhel-kernel-update",
+					FlavorPart: "OS",
+					Pcrs:       []int{17},
+					HostIDs:    []uuid.UUID{uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")},
+					StartTime:  time.Now().Add(time.Hour),
+					EndTime:    time.Now().Add(24 * time.Hour),
+				})
+				Expect(err).NotTo(HaveOccurred())
+				router.Handle("/flavor-learning/{id}/proposal", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorLearningController.Proposal))).Methods("GET")
+				req, err := http.NewRequest("GET", "/flavor-learning/"+flavorLearning.ID.String()+"/proposal", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
@@ -0,0 +1,301 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	fu "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+type FlavorPruneController struct {
+	Store     domain.FlavorPruneStore
+	RStore    domain.ReportStore
+	FGStore   domain.FlavorGroupStore
+	HTManager domain.HostTrustManager
+}
+
+// Create selects the superseded flavor versions per the policy and, unless a preview is requested, retires them.
+// The versions referenced by a trusted rule result of a host's latest report are never retired.
+func (controller FlavorPruneController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_prune_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/flavor_prune_controller:Create() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_prune_controller:Create() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqFlavorPrune hvs.FlavorPrune
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&reqFlavorPrune)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_prune_controller:Create() %s :  Failed to decode request body as FlavorPrune", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	flavorParts, err := validateFlavorPrunePolicy(&reqFlavorPrune.Policy)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_prune_controller:Create() %s : Invalid FlavorPrunePolicy", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	versions, err := controller.Store.SearchFlavorVersions(flavorParts)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_prune_controller:Create() Flavor versions search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search flavor versions"}
+	}
+
+	inUse, err := controller.getFlavorsInUse()
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_prune_controller:Create() Report search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search the latest reports of the hosts"}
+	}
+
+	reqFlavorPrune.Created = time.Now().UTC()
+	reqFlavorPrune.Undone = nil
+	pruner := fu.FlavorPruner{Policy: reqFlavorPrune.Policy}
+	reqFlavorPrune.RetiredFlavors, reqFlavorPrune.InUseFlavors = pruner.SelectSuperseded(versions, inUse, reqFlavorPrune.Created)
+	if reqFlavorPrune.RetiredFlavors == nil {
+		reqFlavorPrune.RetiredFlavors = []hvs.FlavorVersion{}
+	}
+
+	if reqFlavorPrune.Preview {
+		secLog.Infof("%s: FlavorPrune preview retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+		return &reqFlavorPrune, http.StatusOK, nil
+	}
+
+	newFlavorPrune, err := controller.Store.Create(&reqFlavorPrune)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/flavor_prune_controller:Create() FlavorPrune create failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error on retiring flavors"}
+	}
+	secLog.WithField("ID", newFlavorPrune.ID).Infof("%s: %d flavors retired by: %s", commLogMsg.PrivilegeModified,
+		len(newFlavorPrune.RetiredFlavors), r.RemoteAddr)
+
+	if err := controller.verifyHosts(newFlavorPrune.RetiredFlavors); err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_prune_controller:Create() Host to Flavor Verify Queue addition failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+			"associated with retired flavors"}
+	}
+	return newFlavorPrune, http.StatusCreated, nil
+}
+
+func (controller FlavorPruneController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_prune_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/flavor_prune_controller:Search() Leaving")
+
+	if len(r.URL.Query()) != 0 {
+		secLog.Errorf("controllers/flavor_prune_controller:Search() %s : Query parameters are not supported", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Query parameters are not supported"}
+	}
+
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	flavorPruneCollection, err := controller.Store.Search()
+	if err != nil {
+		secLog.WithError(err).Error("controllers/flavor_prune_controller:Search() FlavorPrune search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search FlavorPrune"}
+	}
+
+	secLog.Infof("%s: Return flavor-prunes query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return flavorPruneCollection, http.StatusOK, nil
+}
+
+func (controller FlavorPruneController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_prune_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/flavor_prune_controller:Retrieve() Leaving")
+
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	flavorPrune, status, err := controller.retrieveFlavorPrune(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("ID", flavorPrune.ID).Infof("FlavorPrune retrieved by: %s", r.RemoteAddr)
+	return flavorPrune, http.StatusOK, nil
+}
+
+// Undo restores the flavors retired by the prune and re-verifies the hosts of their flavorgroups
+func (controller FlavorPruneController) Undo(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_prune_controller:Undo() Entering")
+	defer defaultLog.Trace("controllers/flavor_prune_controller:Undo() Leaving")
+
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	flavorPrune, status, err := controller.retrieveFlavorPrune(r)
+	if err != nil {
+		return nil, status, err
+	}
+	if flavorPrune.Undone != nil {
+		secLog.WithField("ID", flavorPrune.ID).Errorf("controllers/flavor_prune_controller:Undo() %s : FlavorPrune is already undone", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusConflict, &commErr.ResourceError{Message: "FlavorPrune with given ID is already undone"}
+	}
+
+	undoneFlavorPrune, err := controller.Store.Undo(flavorPrune.ID)
+	if err != nil {
+		defaultLog.WithError(err).WithField("ID", flavorPrune.ID).Error("controllers/flavor_prune_controller:Undo() FlavorPrune undo failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to restore the retired flavors"}
+	}
+	secLog.WithField("ID", flavorPrune.ID).Infof("%s: FlavorPrune undone by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+
+	if err := controller.verifyHosts(undoneFlavorPrune.RetiredFlavors); err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_prune_controller:Undo() Host to Flavor Verify Queue addition failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+			"associated with restored flavors"}
+	}
+	return undoneFlavorPrune, http.StatusOK, nil
+}
+
+// forTenant returns a copy of the controller whose stores are restricted to the tenant of the user making the
+// request, the flavors of a tenant are only pruned by the tenant and the prunes are only seen by the tenant
+func (controller FlavorPruneController) forTenant(r *http.Request) (FlavorPruneController, int, error) {
+	defaultLog.Trace("controllers/flavor_prune_controller:forTenant() Entering")
+	defer defaultLog.Trace("controllers/flavor_prune_controller:forTenant() Leaving")
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_prune_controller:forTenant() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return controller, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	controller.Store = controller.Store.ForTenant(tenantId)
+	controller.RStore = controller.RStore.ForTenant(tenantId)
+	controller.FGStore = controller.FGStore.ForTenant(tenantId)
+	return controller, http.StatusOK, nil
+}
+
+func (controller FlavorPruneController) retrieveFlavorPrune(r *http.Request) (*hvs.FlavorPrune, int, error) {
+	id := uuid.MustParse(mux.Vars(r)["id"])
+
+	flavorPrune, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/flavor_prune_controller:retrieveFlavorPrune() FlavorPrune with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "FlavorPrune with given ID does not exist"}
+		}
+		secLog.WithError(err).WithField("id", id).Error(
+			"controllers/flavor_prune_controller:retrieveFlavorPrune() failed to retrieve FlavorPrune")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve FlavorPrune"}
+	}
+	return flavorPrune, http.StatusOK, nil
+}
+
+// getFlavorsInUse returns the flavors referenced by a trusted rule result of the latest report of any host
+func (controller FlavorPruneController) getFlavorsInUse() (map[uuid.UUID]bool, error) {
+	reports, err := controller.RStore.Search(&models.ReportFilterCriteria{
+		LatestPerHost: true,
+		Limit:         consts.DefaultSearchResultRowLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[uuid.UUID]bool)
+	for _, report := range reports {
+		for _, result := range report.TrustReport.Results {
+			if result.Trusted && result.FlavorId != nil {
+				inUse[*result.FlavorId] = true
+			}
+		}
+	}
+	return inUse, nil
+}
+
+// verifyHosts queues the hosts of the flavorgroups of the flavor versions for trust re-verification
+func (controller FlavorPruneController) verifyHosts(versions []hvs.FlavorVersion) error {
+	flavorgroupIds := make(map[uuid.UUID]bool)
+	for _, version := range versions {
+		for _, flavorgroupId := range version.FlavorgroupIDs {
+			flavorgroupIds[flavorgroupId] = true
+		}
+	}
+
+	hostIds := make(map[uuid.UUID]bool)
+	var hostIdsForQueue []uuid.UUID
+	for flavorgroupId := range flavorgroupIds {
+		fgHostIds, err := controller.FGStore.SearchHostsByFlavorGroup(flavorgroupId)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to retrieve hosts associated with flavorgroup %v for trust re-verification", flavorgroupId)
+		}
+		for _, hostId := range fgHostIds {
+			if !hostIds[hostId] {
+				hostIds[hostId] = true
+				hostIdsForQueue = append(hostIdsForQueue, hostId)
+			}
+		}
+	}
+
+	defaultLog.Debugf("Found %v hosts to be added to flavor-verify queue", len(hostIdsForQueue))
+	if len(hostIdsForQueue) == 0 {
+		return nil
+	}
+	return controller.HTManager.VerifyHostsAsync(hostIdsForQueue, false, false)
+}
+
+func validateFlavorPrunePolicy(policy *hvs.FlavorPrunePolicy) ([]fc.FlavorPart, error) {
+	defaultLog.Trace("controllers/flavor_prune_controller:validateFlavorPrunePolicy() Entering")
+	defer defaultLog.Trace("controllers/flavor_prune_controller:validateFlavorPrunePolicy() Leaving")
+
+	if policy.KeepVersions < 1 {
+		return nil, errors.New("keep_versions must be at least 1")
+	}
+	if policy.MinAgeDays < 0 {
+		return nil, errors.New("min_age_days cannot be negative")
+	}
+
+	if len(policy.FlavorParts) == 0 {
+		return []fc.FlavorPart{fc.FlavorPartPlatform, fc.FlavorPartOs}, nil
+	}
+	var flavorParts []fc.FlavorPart
+	for i, part := range policy.FlavorParts {
+		var flavorPart fc.FlavorPart
+		if err := (&flavorPart).Parse(part); err != nil ||
+			(flavorPart != fc.FlavorPartPlatform && flavorPart != fc.FlavorPartOs) {
+			return nil, errors.New("flavor_parts must be PLATFORM or OS")
+		}
+		policy.FlavorParts[i] = flavorPart.String()
+		flavorParts = append(flavorParts, flavorPart)
+	}
+	return flavorParts, nil
+}
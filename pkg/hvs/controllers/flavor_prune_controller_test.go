@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	smocks "github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/mocks"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FlavorPruneController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var flavorPruneStore *mocks2.MockFlavorPruneStore
+	var flavorPruneController *controllers.FlavorPruneController
+
+	// the oldest version is referenced by the trusted report of a host
+	inUseVersion := uuid.MustParse("1108e0f4-96ee-4839-9bf7-a5a25457797f")
+	supersededVersion := uuid.MustParse("5e1cbd5a-8ad2-4ae5-9dbc-6cb8ed3e2b71")
+	latestVersion := uuid.MustParse("0d6d6d5a-5d1a-4a6a-8a5c-3a3ba5e1e6c4")
+	osVersion := uuid.MustParse("e5574d3a-3a9f-4f5d-9a8e-13ad4ac47d43")
+	family := "PLATFORM_INTEL_host-1_bios_2.0"
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		created := time.Now().Add(-30 * 24 * time.Hour)
+		flavorPruneStore = mocks2.NewFakeFlavorPruneStore([]hvs.FlavorVersion{
+			{FlavorID: inUseVersion, Label: "platform-v1", FlavorPart: "PLATFORM", Family: family, Created: created},
+			{FlavorID: supersededVersion, Label: "platform-v2", FlavorPart: "PLATFORM", Family: family, Created: created.Add(time.Hour)},
+			{FlavorID: latestVersion, Label: "platform-v3", FlavorPart: "PLATFORM", Family: family, Created: created.Add(2 * time.Hour)},
+			{FlavorID: osVersion, Label: "os-v1", FlavorPart: "OS", Family: "OS_INTEL_host-1_RedHatEnterprise_", Created: created},
+		})
+		flavorPruneController = &controllers.FlavorPruneController{
+			Store:     flavorPruneStore,
+			RStore:    mocks2.NewMockReportStore(),
+			FGStore:   mocks2.NewFakeFlavorgroupStore(),
+			HTManager: &smocks.MockHostTrustManager{},
+		}
+	})
+
+	createTenantFlavorPrune := func(flavorPrune hvs.FlavorPrune, roles []aas.RoleInfo) *httptest.ResponseRecorder {
+		router.Handle("/flavor-prunes", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorPruneController.Create))).Methods("POST")
+		body, _ := json.Marshal(flavorPrune)
+		req, err := http.NewRequest("POST", "/flavor-prunes", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+		if roles != nil {
+			req = comctx.SetUserRoles(req, roles)
+		}
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	createFlavorPrune := func(flavorPrune hvs.FlavorPrune) *httptest.ResponseRecorder {
+		return createTenantFlavorPrune(flavorPrune, nil)
+	}
+
+	undoTenantFlavorPrune := func(id uuid.UUID, roles []aas.RoleInfo) *httptest.ResponseRecorder {
+		router.Handle("/flavor-prunes/{id}/undo", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorPruneController.Undo))).Methods("POST")
+		req, err := http.NewRequest("POST", "/flavor-prunes/"+id.String()+"/undo", nil)
+		Expect(err).NotTo(HaveOccurred())
+		if roles != nil {
+			req = comctx.SetUserRoles(req, roles)
+		}
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	undoFlavorPrune := func(id uuid.UUID) *httptest.ResponseRecorder {
+		return undoTenantFlavorPrune(id, nil)
+	}
+
+	// Specs for HTTP Post to "/flavor-prunes"
+	Describe("Create FlavorPrune", func() {
+		Context("Preview the prune of the superseded flavor versions", func() {
+			It("Should select the versions that are not in use without retiring them", func() {
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1}, Preview: true})
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var flavorPrune hvs.FlavorPrune
+				err := json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorPrune.ID).To(Equal(uuid.Nil))
+				Expect(flavorPrune.RetiredFlavors).To(HaveLen(1))
+				Expect(flavorPrune.RetiredFlavors[0].FlavorID).To(Equal(supersededVersion))
+				Expect(flavorPrune.InUseFlavors).To(HaveLen(1))
+				Expect(flavorPrune.InUseFlavors[0].FlavorID).To(Equal(inUseVersion))
+				Expect(flavorPruneStore.FlavorVersions).To(HaveLen(4))
+			})
+		})
+		Context("Prune the superseded flavor versions", func() {
+			It("Should retire the versions that are not in use", func() {
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1}})
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var flavorPrune hvs.FlavorPrune
+				err := json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorPrune.ID).NotTo(Equal(uuid.Nil))
+				Expect(flavorPrune.RetiredFlavors).To(HaveLen(1))
+				Expect(flavorPruneStore.FlavorVersions).To(HaveLen(3))
+			})
+		})
+		Context("Prune the flavor versions younger than the minimum age", func() {
+			It("Should not retire any version", func() {
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1, MinAgeDays: 60}})
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var flavorPrune hvs.FlavorPrune
+				err := json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorPrune.RetiredFlavors).To(BeEmpty())
+				Expect(flavorPruneStore.FlavorVersions).To(HaveLen(4))
+			})
+		})
+		Context("Prune the flavor versions of a tenant", func() {
+			It("Should only select the versions of the tenant", func() {
+				tenantVersion := uuid.MustParse("9b0a6f4e-2d9b-4f45-8a51-7d2f4e9f6c11")
+				flavorPruneStore.FlavorVersions = append(flavorPruneStore.FlavorVersions, hvs.FlavorVersion{
+					FlavorID: tenantVersion, Label: "platform-tenant-v1", FlavorPart: "PLATFORM", Family: family,
+					Created: time.Now().Add(-20 * 24 * time.Hour),
+				})
+				flavorPruneStore.VersionTenants[tenantVersion] = "tenant-a"
+				tenantRoles := []aas.RoleInfo{{Service: "HVS", Name: "FlavorManager", Context: "tenant=tenant-a"}}
+
+				// the only version of the tenant is kept
+				w = createTenantFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1}}, tenantRoles)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				var flavorPrune hvs.FlavorPrune
+				err := json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorPrune.RetiredFlavors).To(BeEmpty())
+				Expect(flavorPruneStore.FlavorVersions).To(HaveLen(5))
+
+				// the default namespace does not see the versions of the tenant
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1}})
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				err = json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorPrune.RetiredFlavors).To(HaveLen(1))
+				Expect(flavorPrune.RetiredFlavors[0].FlavorID).To(Equal(supersededVersion))
+
+				// the prune of the default namespace can not be undone by the tenant
+				w = undoTenantFlavorPrune(flavorPrune.ID, tenantRoles)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Provide a policy that keeps no version", func() {
+			It("Should fail to create FlavorPrune", func() {
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 0}})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a policy with an unsupported flavor part", func() {
+			It("Should fail to create FlavorPrune", func() {
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1, FlavorParts: []string{"HOST_UNIQUE"}}})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/flavor-prunes/{id}/undo"
+	Describe("Undo FlavorPrune", func() {
+		Context("Undo a FlavorPrune", func() {
+			It("Should restore the retired versions once", func() {
+				w = createFlavorPrune(hvs.FlavorPrune{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1}})
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				var flavorPrune hvs.FlavorPrune
+				err := json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+
+				w = undoFlavorPrune(flavorPrune.ID)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				err = json.Unmarshal(w.Body.Bytes(), &flavorPrune)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorPrune.Undone).NotTo(BeNil())
+				Expect(flavorPruneStore.FlavorVersions).To(HaveLen(4))
+
+				w = undoFlavorPrune(flavorPrune.ID)
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+		Context("Undo a FlavorPrune that does not exist", func() {
+			It("Should fail with not found", func() {
+				w = undoFlavorPrune(uuid.New())
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -6,8 +6,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
@@ -15,6 +17,8 @@ import (
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/pkg/errors"
 	"net/http"
@@ -35,6 +39,11 @@ func (controller FlavorgroupController) Create(w http.ResponseWriter, r *http.Re
 	defaultLog.Trace("controllers/flavorgroup_controller:Create() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:Create() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -49,7 +58,7 @@ func (controller FlavorgroupController) Create(w http.ResponseWriter, r *http.Re
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 
-	err := dec.Decode(&reqFlavorGroup)
+	err = dec.Decode(&reqFlavorGroup)
 	if err != nil {
 		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:Create() %s :  Failed to decode request body as FlavorGroup", commLogMsg.InvalidInputBadEncoding)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
@@ -68,6 +77,22 @@ func (controller FlavorgroupController) Create(w http.ResponseWriter, r *http.Re
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "FlavorGroup with same name already exist"}
 	}
 
+	if reqFlavorGroup.ParentId != nil {
+		_, err := controller.FlavorGroupStore.Retrieve(*reqFlavorGroup.ParentId)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				secLog.WithError(err).WithField("parentId", *reqFlavorGroup.ParentId).Errorf("controllers/flavorgroup_controller:Create() %s : Parent FlavorGroup does not exist", commLogMsg.InvalidInputBadParam)
+				return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Parent FlavorGroup with given ID does not exist"}
+			}
+			defaultLog.WithError(err).Error("controllers/flavorgroup_controller:Create() Parent FlavorGroup retrieve failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve parent FlavorGroup"}
+		}
+		if _, err := utils.GetFlavorGroupAncestors(controller.FlavorGroupStore, reqFlavorGroup); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavorgroup_controller:Create() %s : Invalid FlavorGroup hierarchy", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: fmt.Sprintf("FlavorGroup hierarchy cannot be deeper than %d FlavorGroups", constants.MaxFlavorgroupHierarchyDepth)}
+		}
+	}
+
 	// Persistence
 	newFlavorGroup, err := controller.FlavorGroupStore.Create(&reqFlavorGroup)
 	if err != nil {
@@ -82,6 +107,11 @@ func (controller FlavorgroupController) Search(w http.ResponseWriter, r *http.Re
 	defaultLog.Trace("controllers/flavorgroup_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:Search() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	// check for query parameters
 	defaultLog.WithField("query", r.URL.Query()).Trace("query flavorgroups")
 
@@ -139,6 +169,11 @@ func (controller FlavorgroupController) Delete(w http.ResponseWriter, r *http.Re
 	defaultLog.Trace("controllers/flavorgroup_controller:Delete() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:Delete() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	id := uuid.MustParse(mux.Vars(r)["id"])
 
 	delFlavorGroup, err := controller.FlavorGroupStore.Retrieve(id)
@@ -160,6 +195,19 @@ func (controller FlavorgroupController) Delete(w http.ResponseWriter, r *http.Re
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: errorMsg}
 	}
 
+	children, err := controller.FlavorGroupStore.Search(&models.FlavorGroupFilterCriteria{ParentId: &id})
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Info(
+			"controllers/flavorgroup_controller:Delete() failed to get FlavorGroups inheriting from FlavorGroup")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete FlavorGroup"}
+	}
+	if len(children) > 0 {
+		defaultLog.WithField("id", id).Info(
+			"controllers/flavorgroup_controller:Delete() one or more than one FlavorGroups still inherit from FlavorGroup")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to delete FlavorGroup, " +
+			"one or more than one FlavorGroups still inherit from FlavorGroup"}
+	}
+
 	hostsExist, err := controller.FlavorGroupStore.HasAssociatedHosts(id)
 	if err != nil {
 		defaultLog.WithError(err).WithField("id", id).Info(
@@ -186,6 +234,11 @@ func (controller FlavorgroupController) Retrieve(w http.ResponseWriter, r *http.
 	defaultLog.Trace("controllers/flavorgroup_controller:Retrieve() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:Retrieve() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	id := uuid.MustParse(mux.Vars(r)["id"])
 
 	flavorGroup, err := controller.FlavorGroupStore.Retrieve(id)
@@ -217,9 +270,61 @@ func ValidateFlavorGroup(flavorGroup hvs.FlavorGroup) error {
 			return errors.Wrap(errs, "Valid FlavorGroup Name must be specified")
 		}
 	}
-	if len(flavorGroup.MatchPolicies) == 0 {
+	// a flavorgroup with a parent can inherit all its match policies
+	if len(flavorGroup.MatchPolicies) == 0 && flavorGroup.ParentId == nil {
 		return errors.New("Flavor Type Match Policy Collection must be specified")
 	}
+	if err := validateComplianceProfiles(flavorGroup.ComplianceProfiles); err != nil {
+		return errors.Wrap(err, "Valid compliance profiles must be specified")
+	}
+	if flavorGroup.FlavorSelector != nil {
+		if err := flavorGroup.FlavorSelector.Validate(); err != nil {
+			return errors.Wrap(err, "Valid flavor selector must be specified")
+		}
+	}
+	if flavorGroup.HostSelector != nil {
+		if err := flavorGroup.HostSelector.Validate(); err != nil {
+			return errors.Wrap(err, "Valid host selector must be specified")
+		}
+	}
+	return nil
+}
+
+// validateComplianceProfiles checks that the profiles have unique names and require known flavor parts and rules
+func validateComplianceProfiles(profiles []hvs.ComplianceProfile) error {
+	ruleNames := make(map[string]bool)
+	for _, definition := range verifier.GetRuleDefinitions() {
+		ruleNames[definition.Name] = true
+	}
+
+	profileNames := make(map[string]bool)
+	for _, profile := range profiles {
+		if profile.Name == "" {
+			return errors.New("Compliance profile name must be specified")
+		}
+		if errs := validation.ValidateStrings([]string{profile.Name}); errs != nil {
+			return errors.Wrapf(errs, "Invalid compliance profile name %s", profile.Name)
+		}
+		if profileNames[profile.Name] {
+			return errors.Errorf("Duplicate compliance profile %s", profile.Name)
+		}
+		profileNames[profile.Name] = true
+
+		if len(profile.FlavorParts) == 0 && len(profile.Rules) == 0 {
+			return errors.Errorf("Compliance profile %s must require flavor parts or rules", profile.Name)
+		}
+		for _, flavorPart := range profile.FlavorParts {
+			var fp cf.FlavorPart
+			if err := (&fp).Parse(flavorPart.String()); err != nil || fp != flavorPart {
+				return errors.Errorf("Compliance profile %s requires invalid flavor part %s", profile.Name, flavorPart)
+			}
+		}
+		for _, rule := range profile.Rules {
+			if !ruleNames[rule] {
+				return errors.Errorf("Compliance profile %s requires unknown rule %s", profile.Name, rule)
+			}
+		}
+	}
 	return nil
 }
 
@@ -245,6 +350,11 @@ func (controller FlavorgroupController) AddFlavor(w http.ResponseWriter, r *http
 	defaultLog.Trace("controllers/flavorgroup_controller:AddFlavor() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:AddFlavor() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -260,7 +370,7 @@ func (controller FlavorgroupController) AddFlavor(w http.ResponseWriter, r *http
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 
-	err := dec.Decode(&linkRequest)
+	err = dec.Decode(&linkRequest)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:AddFlavor() %s :  Failed to decode request body as FlavorgroupFlavorLinkCriteria", commLogMsg.InvalidInputBadParam)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
@@ -353,12 +463,20 @@ func (controller FlavorgroupController) RemoveFlavor(w http.ResponseWriter, r *h
 	defaultLog.Trace("controllers/flavorgroup_controller:RemoveFlavor() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:RemoveFlavor() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	// Get the FlavorGroup, Flavor ID from the URL
 	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
 	fID := uuid.MustParse(mux.Vars(r)["fID"])
 
-	// check if link exists
-	_, err := controller.FlavorGroupStore.RetrieveFlavor(fgID, fID)
+	// check if link exists, the FlavorGroup must belong to the tenant of the user
+	_, err = controller.FlavorGroupStore.Retrieve(fgID)
+	if err == nil {
+		_, err = controller.FlavorGroupStore.RetrieveFlavor(fgID, fID)
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), commErr.RowsNotFound) {
 			defaultLog.WithError(err).WithField("flavorGroup", fID).WithField("flavor", fID).WithError(err).Errorf("controllers/flavorgroup_controller:RemoveFlavor() %s : FlavorGroup-Flavor link %s does not exist", commLogMsg.AppRuntimeErr, fgID)
@@ -401,6 +519,11 @@ func (controller FlavorgroupController) SearchFlavors(w http.ResponseWriter, r *
 	defaultLog.Trace("controllers/flavorgroup_controller:SearchFlavors() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:SearchFlavors() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	// Get the FlavorGroup, Flavor ID from the URL
 	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
 
@@ -408,7 +531,7 @@ func (controller FlavorgroupController) SearchFlavors(w http.ResponseWriter, r *
 	searchResults := []hvs.FlavorgroupFlavorLink{}
 
 	// check if FlavorGroup exists
-	_, err := controller.FlavorGroupStore.Retrieve(fgID)
+	_, err = controller.FlavorGroupStore.Retrieve(fgID)
 	if err != nil {
 		if strings.Contains(err.Error(), commErr.RowsNotFound) {
 			defaultLog.WithField("flavorGroup", fgID).WithField("flavorGroup", fgID).WithError(err).Errorf("controllers/flavorgroup_controller:SearchFlavor() %s :  FlavorGroup not found ", commLogMsg.AppRuntimeErr)
@@ -448,12 +571,21 @@ func (controller FlavorgroupController) RetrieveFlavor(w http.ResponseWriter, r
 	defaultLog.Trace("controllers/flavorgroup_controller:RetrieveFlavor() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:RetrieveFlavor() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	// Get the FlavorGroup, Flavor ID from the URL
 	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
 	fID := uuid.MustParse(mux.Vars(r)["fID"])
 
-	// Retrieve flavor links
-	fgl, err := controller.FlavorGroupStore.RetrieveFlavor(fgID, fID)
+	// Retrieve flavor links, the FlavorGroup must belong to the tenant of the user
+	var fgl *hvs.FlavorgroupFlavorLink
+	_, err = controller.FlavorGroupStore.Retrieve(fgID)
+	if err == nil {
+		fgl, err = controller.FlavorGroupStore.RetrieveFlavor(fgID, fID)
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), commErr.RowsNotFound) {
 			defaultLog.WithField("flavorGroup", fID).WithField("flavor", fID).WithError(err).Errorf("controllers/flavorgroup_controller:RetrieveFlavor() %s :  Linked Flavors not found ", commLogMsg.AppRuntimeErr)
@@ -495,3 +627,21 @@ func (controller FlavorgroupController) getAssociatedFlavor(flavorgroupList []hv
 	flavorgroupCollection := &hvs.FlavorgroupCollection{Flavorgroups: flavorgroupList}
 	return flavorgroupCollection, nil
 }
+
+// forTenant returns a copy of the controller whose flavorgroup, flavor and host stores are restricted to the tenant
+// of the user making the request
+func (controller FlavorgroupController) forTenant(r *http.Request) (FlavorgroupController, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:forTenant() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:forTenant() Leaving")
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:forTenant() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return controller, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	controller.FlavorGroupStore = controller.FlavorGroupStore.ForTenant(tenantId)
+	controller.FlavorStore = controller.FlavorStore.ForTenant(tenantId)
+	controller.HostStore = controller.HostStore.ForTenant(tenantId)
+	return controller, http.StatusOK, nil
+}
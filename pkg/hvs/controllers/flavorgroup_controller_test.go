@@ -173,6 +173,140 @@ var _ = Describe("FlavorgroupController", func() {
 				Expect(w.Code).To(Equal(404))
 			})
 		})
+
+		Context("Delete FlavorGroup that other FlavorGroups inherit from", func() {
+			It("Should fail to delete FlavorGroup", func() {
+				parentId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				_, err := flavorgroupStore.Create(&hvs.FlavorGroup{
+					Name:     "hvs_flavorgroup_child",
+					ParentId: &parentId,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				router.Handle("/flavorgroups/{id}", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(flavorgroupController.Delete))).Methods("DELETE")
+				req, err := http.NewRequest("DELETE", "/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(400))
+			})
+		})
+
+		Context("Provide a Flavorgroup data with compliance profiles", func() {
+			It("Should create a new Flavorgroup with the profiles and get HTTP Status: 201", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Create))).Methods("POST")
+				flavorgroupJson := `{
+								"name": "hvs_flavorgroup_compliance",
+								"parent_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+								"compliance_profiles": [{
+									"name": "NIST-boot-integrity",
+									"flavor_parts": ["PLATFORM", "OS"],
+									"rules": ["com.intel.mtwilson.core.verifier.policy.rule.PcrEventLogIntegrity"]
+								}]
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/flavorgroups",
+					strings.NewReader(flavorgroupJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(201))
+
+				var flavorgroup hvs.FlavorGroup
+				err = json.Unmarshal(w.Body.Bytes(), &flavorgroup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorgroup.ComplianceProfiles).To(HaveLen(1))
+				Expect(flavorgroup.ComplianceProfiles[0].Name).To(Equal("NIST-boot-integrity"))
+			})
+		})
+
+		Context("Provide a Flavorgroup data with a compliance profile requiring an unknown rule", func() {
+			It("Should get HTTP Status: 400", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Create))).Methods("POST")
+				flavorgroupJson := `{
+								"name": "hvs_flavorgroup_compliance",
+								"parent_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+								"compliance_profiles": [{
+									"name": "NIST-boot-integrity",
+									"rules": ["PcrMatchesEverything"]
+								}]
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/flavorgroups",
+					strings.NewReader(flavorgroupJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(400))
+			})
+		})
+
+		Context("Provide a Flavorgroup data with flavor and host selectors", func() {
+			It("Should create a new Flavorgroup with the selectors and get HTTP Status: 201", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Create))).Methods("POST")
+				flavorgroupJson := `{
+								"name": "hvs_flavorgroup_selector",
+								"parent_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+								"flavor_selector": {
+									"match_labels": {"intel.com/datacenter": "dc1"}
+								},
+								"host_selector": {
+									"match_expressions": [{"key": "os", "operator": "In", "values": ["rhel", "ubuntu"]}]
+								}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/flavorgroups",
+					strings.NewReader(flavorgroupJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(201))
+
+				var flavorgroup hvs.FlavorGroup
+				err = json.Unmarshal(w.Body.Bytes(), &flavorgroup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorgroup.FlavorSelector.MatchLabels).To(HaveKeyWithValue("intel.com/datacenter", "dc1"))
+				Expect(flavorgroup.HostSelector.MatchExpressions).To(HaveLen(1))
+			})
+		})
+
+		Context("Provide a Flavorgroup data with an empty flavor selector", func() {
+			It("Should get HTTP Status: 400", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Create))).Methods("POST")
+				flavorgroupJson := `{
+								"name": "hvs_flavorgroup_selector",
+								"parent_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+								"flavor_selector": {}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/flavorgroups",
+					strings.NewReader(flavorgroupJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(400))
+			})
+		})
 	})
 
 	// Specs for HTTP Post to "/flavorgroups"
@@ -254,6 +388,50 @@ var _ = Describe("FlavorgroupController", func() {
 				Expect(w.Code).To(Equal(400))
 			})
 		})
+
+		Context("Provide a Flavorgroup data that inherits the policies of its parent", func() {
+			It("Should create a new Flavorgroup and get HTTP Status: 201", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Create))).Methods("POST")
+				flavorgroupJson := `{
+								"name": "hvs_flavorgroup_site",
+								"parent_id": "ee37c360-7eae-4250-a677-6ee12adce8e2"
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/flavorgroups",
+					strings.NewReader(flavorgroupJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(201))
+			})
+		})
+
+		Context("Provide a Flavorgroup data with a parent that does not exist", func() {
+			It("Should get HTTP Status: 400", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Create))).Methods("POST")
+				flavorgroupJson := `{
+								"name": "hvs_flavorgroup_site",
+								"parent_id": "73755fda-c910-46be-821f-e8ddeab189e9"
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/flavorgroups",
+					strings.NewReader(flavorgroupJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(400))
+			})
+		})
 	})
 
 	Context("Provide a empty data  in request", func() {
@@ -5,6 +5,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
@@ -25,6 +26,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type HostController struct {
@@ -35,6 +37,7 @@ type HostController struct {
 	HCStore   domain.HostCredentialStore
 	HTManager domain.HostTrustManager
 	HCConfig  domain.HostControllerConfig
+	PCStore   domain.PlatformCertificateStore
 }
 
 func NewHostController(hs domain.HostStore, hss domain.HostStatusStore, fs domain.FlavorStore,
@@ -52,7 +55,7 @@ func NewHostController(hs domain.HostStore, hss domain.HostStatusStore, fs domai
 }
 
 var hostSearchParams = map[string]bool{"id": true, "nameEqualTo": true, "nameContains": true, "hostHardwareId": true,
-	"key": true, "value": true, "trusted": true, "getTrustStatus": true, "getHostStatus": true, "orderBy": true}
+	"key": true, "value": true, "trusted": true, "lifecycle": true, "getTrustStatus": true, "getHostStatus": true, "orderBy": true}
 
 var hostRetrieveParams = map[string]bool{"getReport": true, "getHostStatus": true}
 
@@ -60,6 +63,11 @@ func (hc *HostController) Create(w http.ResponseWriter, r *http.Request) (interf
 	defaultLog.Trace("controllers/host_controller:Create() Entering")
 	defer defaultLog.Trace("controllers/host_controller:Create() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -73,13 +81,13 @@ func (hc *HostController) Create(w http.ResponseWriter, r *http.Request) (interf
 	dec.DisallowUnknownFields()
 
 	var reqHost hvs.HostCreateRequest
-	err := dec.Decode(&reqHost)
+	err = dec.Decode(&reqHost)
 	if err != nil {
 		secLog.WithError(err).Errorf("controllers/host_controller:Create() %s :  Failed to decode request body as HostCreateRequest", commLogMsg.InvalidInputBadEncoding)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
 	}
 
-	createdHost, status, err := hc.CreateHost(reqHost)
+	createdHost, status, err := hc.CreateHost(r.Context(), reqHost)
 	if err != nil {
 		return nil, status, err
 	}
@@ -92,6 +100,11 @@ func (hc *HostController) Retrieve(w http.ResponseWriter, r *http.Request) (inte
 	defaultLog.Trace("controllers/host_controller:Retrieve() Entering")
 	defer defaultLog.Trace("controllers/host_controller:Retrieve() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if err := utils.ValidateQueryParams(r.URL.Query(), hostRetrieveParams); err != nil {
 		secLog.Errorf("controllers/host_controller:Retrieve() %s", err.Error())
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
@@ -120,6 +133,11 @@ func (hc *HostController) Update(w http.ResponseWriter, r *http.Request) (interf
 	defaultLog.Trace("controllers/host_controller:Update() Entering")
 	defer defaultLog.Trace("controllers/host_controller:Update() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -133,7 +151,7 @@ func (hc *HostController) Update(w http.ResponseWriter, r *http.Request) (interf
 	dec.DisallowUnknownFields()
 
 	var reqHost hvs.Host
-	err := dec.Decode(&reqHost)
+	err = dec.Decode(&reqHost)
 	if err != nil {
 		secLog.WithError(err).Errorf("controllers/host_controller:Update() %s :  Failed to decode request body as Host", commLogMsg.InvalidInputBadEncoding)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
@@ -144,6 +162,7 @@ func (hc *HostController) Update(w http.ResponseWriter, r *http.Request) (interf
 		Description:      reqHost.Description,
 		ConnectionString: reqHost.ConnectionString,
 		FlavorgroupNames: reqHost.FlavorgroupNames,
+		Labels:           reqHost.Labels,
 	}
 
 	if err := validateHostCreateCriteria(criteria); err != nil {
@@ -151,12 +170,23 @@ func (hc *HostController) Update(w http.ResponseWriter, r *http.Request) (interf
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
 
+	// the lifecycle and the capabilities of a host are maintained by HVS, its maintenance is set with the
+	// maintenance endpoints
+	reqHost.Lifecycle = ""
+	reqHost.Capabilities = nil
+	reqHost.Maintenance = nil
 	reqHost.Id = uuid.MustParse(mux.Vars(r)["hId"])
-	updatedHost, status, err := hc.UpdateHost(reqHost)
+	updatedHost, status, err := hc.UpdateHost(r.Context(), reqHost)
 	if err != nil {
 		return nil, status, err
 	}
 
+	// pre-registered hosts are verified once their registration is completed
+	if host, ok := updatedHost.(*hvs.Host); ok && host.Lifecycle != "" && host.Lifecycle != hvs.HostLifecycleRegistered {
+		secLog.WithField("host", updatedHost).Infof("%s: Host updated by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+		return updatedHost, status, nil
+	}
+
 	defaultLog.Debugf("Adding host %v to flavor-verify queue", reqHost.Id)
 	// Since the host has been updated, add it to the verify queue
 	err = hc.HTManager.VerifyHostsAsync([]uuid.UUID{reqHost.Id}, true, false)
@@ -173,6 +203,11 @@ func (hc *HostController) Delete(w http.ResponseWriter, r *http.Request) (interf
 	defaultLog.Trace("controllers/host_controller:Delete() Entering")
 	defer defaultLog.Trace("controllers/host_controller:Delete() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	id := uuid.MustParse(mux.Vars(r)["hId"])
 	host, status, err := hc.retrieveHost(id, nil)
 	if err != nil {
@@ -188,10 +223,117 @@ func (hc *HostController) Delete(w http.ResponseWriter, r *http.Request) (interf
 	return nil, http.StatusNoContent, nil
 }
 
+// SetMaintenance puts the host in maintenance mode, its scheduled attestation is paused and its reports are
+// suppressed until the resume time or until the maintenance is ended
+func (hc *HostController) SetMaintenance(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_controller:SetMaintenance() Entering")
+	defer defaultLog.Trace("controllers/host_controller:SetMaintenance() Leaving")
+
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/host_controller:SetMaintenance() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	var reqMaintenance hvs.HostMaintenanceRequest
+	err = dec.Decode(&reqMaintenance)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/host_controller:SetMaintenance() %s :  Failed to decode request body as HostMaintenanceRequest", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	now := time.Now().UTC()
+	if err := validateHostMaintenanceRequest(reqMaintenance, now); err != nil {
+		secLog.WithError(err).Errorf("controllers/host_controller:SetMaintenance() %s : Invalid request body", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	hId := uuid.MustParse(mux.Vars(r)["hId"])
+	host, status, err := hc.retrieveHost(hId, nil)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// extending the maintenance of a host keeps the time it started
+	maintenance := &hvs.HostMaintenance{
+		Reason:    reqMaintenance.Reason,
+		StartedAt: now,
+		ResumeAt:  reqMaintenance.ResumeAt,
+	}
+	if existing := host.(*hvs.Host).Maintenance; existing.Active(now) {
+		maintenance.StartedAt = existing.StartedAt
+	}
+	if err := hc.HStore.SetMaintenance(hId, maintenance); err != nil {
+		defaultLog.WithError(err).WithField("id", hId).Error("controllers/host_controller:SetMaintenance() Host maintenance update failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to put Host in maintenance mode"}
+	}
+
+	host, status, err = hc.retrieveHost(hId, nil)
+	if err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("host", host).Infof("%s: Host put in maintenance mode by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return host, http.StatusOK, nil
+}
+
+// EndMaintenance ends the maintenance of the host and queues it for attestation
+func (hc *HostController) EndMaintenance(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_controller:EndMaintenance() Entering")
+	defer defaultLog.Trace("controllers/host_controller:EndMaintenance() Leaving")
+
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	hId := uuid.MustParse(mux.Vars(r)["hId"])
+	host, status, err := hc.retrieveHost(hId, nil)
+	if err != nil {
+		return nil, status, err
+	}
+
+	ended, err := hc.HStore.EndMaintenance(hId)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", hId).Error("controllers/host_controller:EndMaintenance() Host maintenance delete failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to end Host maintenance"}
+	}
+	if !ended {
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Host with specified id is not in maintenance mode"}
+	}
+
+	// the reports of the host were not refreshed during the maintenance
+	defaultLog.Debugf("Adding host %v to flavor-verify queue", hId)
+	err = hc.HTManager.VerifyHostsAsync([]uuid.UUID{hId}, true, false)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:EndMaintenance() Host to Flavor Verify Queue addition failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to add Host to Flavor Verify Queue"}
+	}
+
+	secLog.WithField("host", host).Infof("%s: Host maintenance ended by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
 func (hc *HostController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/host_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/host_controller:Search() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if err := utils.ValidateQueryParams(r.URL.Query(), hostSearchParams); err != nil {
 		secLog.Errorf("controllers/host_controller:Search() %s", err.Error())
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
@@ -238,7 +380,7 @@ func (hc *HostController) Search(w http.ResponseWriter, r *http.Request) (interf
 	return hostCollection, http.StatusOK, nil
 }
 
-func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}, int, error) {
+func (hc *HostController) CreateHost(ctx context.Context, reqHost hvs.HostCreateRequest) (interface{}, int, error) {
 	defaultLog.Trace("controllers/host_controller:CreateHost() Entering")
 	defer defaultLog.Trace("controllers/host_controller:CreateHost() Leaving")
 
@@ -274,37 +416,51 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
 
-	defaultLog.Debugf("Connecting to host to get the hardware UUID of the host : %s", reqHost.HostName)
-	var hostState hvs.HostState
-	// connect to the host and retrieve the host info
-	hostInfo, err := hc.getHostInfo(connectionString)
-	if err != nil {
-		hostState = utils.DetermineHostState(err)
-		defaultLog.Warnf("Could not connect to host, hardware UUID will not be set: %s", hostState.String())
+	var hostInfo *model.HostInfo
+	var capabilities *model.HostCapabilities
+	var hwUuid *uuid.UUID = nil
+	lifecycle := hvs.HostLifecycleRegistered
+	if reqHost.PreRegister {
+		// the host is not expected to be reachable, the default flavorgroups are associated with it when its
+		// registration is completed
+		defaultLog.Debugf("Pre-registering host %s with hardware UUID %s", reqHost.HostName, reqHost.HardwareUuid)
+		hwUuid = reqHost.HardwareUuid
+		lifecycle = hvs.HostLifecyclePreRegistered
+	} else {
+		defaultLog.Debugf("Connecting to host to get the hardware UUID of the host : %s", reqHost.HostName)
+		var hostState hvs.HostState
+		// connect to the host and retrieve the host info
+		hostInfo, capabilities, err = hc.getHostInfo(ctx, connectionString)
+		if err != nil {
+			hostState = utils.DetermineHostState(err)
+			defaultLog.Warnf("Could not connect to host, hardware UUID will not be set: %s", hostState.String())
+		}
+
+		if hostInfo != nil && hostInfo.HardwareUUID != "" {
+			hwid, err := uuid.Parse(hostInfo.HardwareUUID)
+			if err == nil {
+				hwUuid = &hwid
+			}
+		}
 	}
 
-	var hwUuid *uuid.UUID = nil
-	if hostInfo != nil && hostInfo.HardwareUUID != "" {
-		hwid, err := uuid.Parse(hostInfo.HardwareUUID)
-		if err == nil {
-			hwUuid = &hwid
+	if hwUuid != nil {
+		var mergeHost *hvs.Host
+		var status int
+		hwUuid, mergeHost, status, err = hc.applyHardwareUuidCollisionPolicy(reqHost.HostName, *hwUuid)
+		if err != nil {
+			return nil, status, err
+		}
+		if mergeHost != nil {
+			return hc.mergeHostRegistration(ctx, mergeHost, reqHost)
 		}
 	}
 
 	var fgNames []string
 	if len(reqHost.FlavorgroupNames) != 0 {
 		fgNames = reqHost.FlavorgroupNames
-	} else if hostInfo != nil && hostInfo.HardwareUUID != "" {
-		// if the host is reachable and no flavorgroups are provided in the request, assign the host to "automatic" flavorgroup
-		defaultLog.Debug("Flavorgroup names not present in request, associating with default ones")
-		fgNames = append(fgNames, models.FlavorGroupsAutomatic.String())
-	}
-
-	// Link to default software and workload groups if host is linux and if the specific flavorgroups weren't a part of host create criteria
-	if hostInfo != nil && utils.IsLinuxHost(hostInfo) && len(reqHost.FlavorgroupNames) == 0 {
-		defaultLog.Debug("Host is linux, associating with default software flavorgroups")
-		swFgs := utils.GetDefaultSoftwareFlavorGroups(hostInfo.InstalledComponents)
-		fgNames = append(fgNames, swFgs...)
+	} else if hostInfo != nil {
+		fgNames = defaultFlavorgroupNames(hostInfo)
 	}
 
 	// remove credentials from connection string for host table storage
@@ -317,6 +473,9 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 		ConnectionString: csWithoutCredentials,
 		HardwareUuid:     hwUuid,
 		FlavorgroupNames: fgNames,
+		Lifecycle:        lifecycle,
+		Capabilities:     capabilities,
+		Labels:           reqHost.Labels,
 	}
 
 	createdHost, err := hc.HStore.Create(host)
@@ -354,6 +513,14 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Host with host unique flavors"}
 	}
 
+	if createdHost.Lifecycle == hvs.HostLifecyclePreRegistered {
+		return createdHost, http.StatusCreated, nil
+	}
+
+	if err := hc.HSStore.UpdateLifecycleState(createdHost.Id, hvs.HostLifecycleStateRegistered, ""); err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:CreateHost() Could not update host lifecycle state")
+	}
+
 	defaultLog.Debugf("Adding host %s to flavor-verify queue", reqHost.HostName)
 	// Since we are adding a new host, the forceUpdate flag should be set to true so that
 	// we connect to the host and get the latest host manifest to verify against.
@@ -367,15 +534,21 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 	return createdHost, http.StatusCreated, nil
 }
 
-func (hc *HostController) UpdateHost(reqHost hvs.Host) (interface{}, int, error) {
+func (hc *HostController) UpdateHost(ctx context.Context, reqHost hvs.Host) (interface{}, int, error) {
 	defaultLog.Trace("controllers/host_controller:UpdateHost() Entering")
 	defer defaultLog.Trace("controllers/host_controller:UpdateHost() Leaving")
 
-	_, status, err := hc.retrieveHost(reqHost.Id, nil)
+	existingHost, status, err := hc.retrieveHost(reqHost.Id, nil)
 	if err != nil {
 		return nil, status, err
 	}
 
+	// a host whose registration failed is probed again once its hardware UUID is corrected
+	if host, ok := existingHost.(*hvs.Host); ok && host.Lifecycle == hvs.HostLifecycleRegistrationFailed &&
+		reqHost.HardwareUuid != nil {
+		reqHost.Lifecycle = hvs.HostLifecyclePreRegistered
+	}
+
 	if reqHost.ConnectionString != "" {
 		connectionString, credential, err := GenerateConnectionString(reqHost.ConnectionString,
 			hc.HCConfig.Username,
@@ -393,6 +566,11 @@ func (hc *HostController) UpdateHost(reqHost hvs.Host) (interface{}, int, error)
 
 		reqHost.ConnectionString = csWithoutCredentials
 
+		// the host may be reached through another agent, its capabilities are discovered again
+		if _, capabilities, err := hc.getHostInfo(ctx, connectionString); err == nil {
+			reqHost.Capabilities = capabilities
+		}
+
 		// update credential
 		hostCredential, err := hc.HCStore.FindByHostId(reqHost.Id)
 		if err != nil {
@@ -437,6 +615,304 @@ func (hc *HostController) UpdateHost(reqHost hvs.Host) (interface{}, int, error)
 	return updatedHost, http.StatusOK, nil
 }
 
+// CompleteHostRegistration connects to a pre-registered host and completes its registration when the host is
+// reachable. It returns false without an error when the host is not reachable yet. A host reporting a hardware UUID
+// other than the one it was pre-registered with is marked as failed and is not probed anymore.
+func (hc *HostController) CompleteHostRegistration(ctx context.Context, host *hvs.Host) (bool, error) {
+	defaultLog.Trace("controllers/host_controller:CompleteHostRegistration() Entering")
+	defer defaultLog.Trace("controllers/host_controller:CompleteHostRegistration() Leaving")
+
+	if host.Lifecycle != hvs.HostLifecyclePreRegistered {
+		return false, errors.Errorf("Host %s is not pre-registered", host.HostName)
+	}
+
+	connectionString, _, err := GenerateConnectionString(host.ConnectionString,
+		hc.HCConfig.Username,
+		hc.HCConfig.Password,
+		hc.HCStore)
+	if err != nil {
+		return false, errors.Wrap(err, "Could not generate formatted connection string")
+	}
+
+	hostInfo, capabilities, err := hc.getHostInfo(ctx, connectionString)
+	if err != nil {
+		defaultLog.Debugf("controllers/host_controller:CompleteHostRegistration() Host %s is not reachable: %s",
+			host.HostName, utils.DetermineHostState(err).String())
+		return false, nil
+	}
+
+	hwUuid, err := uuid.Parse(hostInfo.HardwareUUID)
+	if err != nil || host.HardwareUuid == nil || hwUuid != *host.HardwareUuid {
+		host.Lifecycle = hvs.HostLifecycleRegistrationFailed
+		if err := hc.HStore.Update(host); err != nil {
+			return false, errors.Wrap(err, "Could not update host lifecycle")
+		}
+		return false, errors.Errorf("Host %s reported hardware UUID '%s', it was pre-registered with %s",
+			host.HostName, hostInfo.HardwareUUID, host.HardwareUuid)
+	}
+
+	fgIds, err := hc.HStore.SearchFlavorgroups(host.Id)
+	if err != nil {
+		return false, errors.Wrap(err, "Could not retrieve the flavorgroups of the host")
+	}
+	if len(fgIds) == 0 {
+		fgNames := defaultFlavorgroupNames(hostInfo)
+		defaultLog.Debugf("Associating host %s with flavorgroups %+q", host.HostName, fgNames)
+		if err := hc.linkFlavorgroupsToHost(fgNames, host.Id); err != nil {
+			return false, errors.Wrap(err, "Host FlavorGroup association failed")
+		}
+	}
+
+	host.Lifecycle = hvs.HostLifecycleRegistered
+	host.Capabilities = capabilities
+	if err := hc.HStore.Update(host); err != nil {
+		return false, errors.Wrap(err, "Could not update host lifecycle")
+	}
+
+	// flavors unique to the host may have been imported since it was pre-registered
+	if err := hc.linkHostUniqueFlavorsToHost(host); err != nil {
+		return false, errors.Wrap(err, "Host Unique flavor association failed")
+	}
+
+	if err := hc.HSStore.UpdateLifecycleState(host.Id, hvs.HostLifecycleStateRegistered, "pre-registration completed"); err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:CompleteHostRegistration() Could not update host lifecycle state")
+	}
+
+	defaultLog.Debugf("Adding host %s to flavor-verify queue", host.HostName)
+	if err := hc.HTManager.VerifyHostsAsync([]uuid.UUID{host.Id}, true, false); err != nil {
+		return true, errors.Wrap(err, "Host to Flavor Verify Queue addition failed")
+	}
+	return true, nil
+}
+
+// applyHardwareUuidCollisionPolicy applies the hardware UUID collision policy to a host registered with the hardware
+// UUID of a registered host. It returns the hardware UUID to register the host with, or the registered host the
+// registration is merged into.
+func (hc *HostController) applyHardwareUuidCollisionPolicy(hostName string, hwUuid uuid.UUID) (*uuid.UUID, *hvs.Host, int, error) {
+	defaultLog.Trace("controllers/host_controller:applyHardwareUuidCollisionPolicy() Entering")
+	defer defaultLog.Trace("controllers/host_controller:applyHardwareUuidCollisionPolicy() Leaving")
+
+	existingHosts, err := hc.HStore.Search(&models.HostFilterCriteria{HostHardwareId: hwUuid}, nil)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:applyHardwareUuidCollisionPolicy() Host search failed")
+		return nil, nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create Host"}
+	}
+	if len(existingHosts) == 0 {
+		return &hwUuid, nil, http.StatusOK, nil
+	}
+
+	switch hc.HCConfig.HardwareUuidCollisionPolicy {
+	case hvs.HardwareUuidCollisionAutoSuffix:
+		suffixedUuid := suffixedHardwareUuid(hwUuid, hostName)
+		defaultLog.Warnf("Host %s has the hardware UUID %s of host %s, it is registered with the hardware UUID %s",
+			hostName, hwUuid, existingHosts[0].HostName, suffixedUuid)
+		return &suffixedUuid, nil, http.StatusOK, nil
+	case hvs.HardwareUuidCollisionMerge:
+		defaultLog.Warnf("Host %s has the hardware UUID %s of host %s, its registration is merged into it",
+			hostName, hwUuid, existingHosts[0].HostName)
+		return &hwUuid, existingHosts[0], http.StatusOK, nil
+	default:
+		secLog.WithField("Name", existingHosts[0].HostName).Warningf("%s: Trying to register a host with the hardware UUID of a registered Host",
+			commLogMsg.InvalidInputBadParam)
+		return nil, nil, http.StatusConflict, &commErr.ResourceError{
+			Message: fmt.Sprintf("Host %s is registered with the same hardware UUID", existingHosts[0].HostName)}
+	}
+}
+
+// mergeHostRegistration updates the registered host with the name, description, connection string and flavorgroups
+// of the host registered with its hardware UUID
+func (hc *HostController) mergeHostRegistration(ctx context.Context, host *hvs.Host, reqHost hvs.HostCreateRequest) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_controller:mergeHostRegistration() Entering")
+	defer defaultLog.Trace("controllers/host_controller:mergeHostRegistration() Leaving")
+
+	mergedHost, status, err := hc.UpdateHost(ctx, hvs.Host{
+		Id:               host.Id,
+		HostName:         reqHost.HostName,
+		Description:      reqHost.Description,
+		ConnectionString: reqHost.ConnectionString,
+		HardwareUuid:     host.HardwareUuid,
+		FlavorgroupNames: reqHost.FlavorgroupNames,
+		Lifecycle:        host.Lifecycle,
+		Capabilities:     host.Capabilities,
+		Labels:           reqHost.Labels,
+	})
+	if err != nil {
+		return nil, status, err
+	}
+
+	if host.Lifecycle != hvs.HostLifecyclePreRegistered {
+		defaultLog.Debugf("Adding host %s to flavor-verify queue", reqHost.HostName)
+		if err := hc.HTManager.VerifyHostsAsync([]uuid.UUID{host.Id}, true, false); err != nil {
+			defaultLog.WithError(err).Error("controllers/host_controller:mergeHostRegistration() Host to Flavor Verify Queue addition failed")
+		}
+	}
+	return mergedHost, http.StatusOK, nil
+}
+
+// suffixedHardwareUuid derives the hardware UUID a host is registered with from the hardware UUID it shares with a
+// registered host and its name
+func suffixedHardwareUuid(hwUuid uuid.UUID, hostName string) uuid.UUID {
+	return uuid.NewSHA1(hwUuid, []byte(hostName))
+}
+
+// SearchHardwareUuidConflicts lists the hosts registered with the same hardware UUID
+func (hc *HostController) SearchHardwareUuidConflicts(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_controller:SearchHardwareUuidConflicts() Entering")
+	defer defaultLog.Trace("controllers/host_controller:SearchHardwareUuidConflicts() Leaving")
+
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	hosts, err := hc.HStore.Search(nil, nil)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:SearchHardwareUuidConflicts() Host search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search Hosts"}
+	}
+
+	hostsByHardwareUuid := make(map[uuid.UUID][]*hvs.Host)
+	var hardwareUuids []uuid.UUID
+	for _, host := range hosts {
+		if host.HardwareUuid == nil {
+			continue
+		}
+		if _, ok := hostsByHardwareUuid[*host.HardwareUuid]; !ok {
+			hardwareUuids = append(hardwareUuids, *host.HardwareUuid)
+		}
+		hostsByHardwareUuid[*host.HardwareUuid] = append(hostsByHardwareUuid[*host.HardwareUuid], host)
+	}
+
+	conflicts := []hvs.HardwareUuidConflict{}
+	for _, hwUuid := range hardwareUuids {
+		if len(hostsByHardwareUuid[hwUuid]) > 1 {
+			conflicts = append(conflicts, hvs.HardwareUuidConflict{HardwareUuid: hwUuid, Hosts: hostsByHardwareUuid[hwUuid]})
+		}
+	}
+
+	secLog.Infof("%s: Hardware UUID conflicts searched by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.HardwareUuidConflictCollection{Conflicts: conflicts}, http.StatusOK, nil
+}
+
+// ResolveHardwareUuidConflict resolves the conflict of the hosts registered with the same hardware UUID. The host
+// chosen keeps the hardware UUID, the other hosts are given a hardware UUID derived from their name with the
+// auto-suffix resolution or are deleted with the merge resolution.
+func (hc *HostController) ResolveHardwareUuidConflict(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_controller:ResolveHardwareUuidConflict() Entering")
+	defer defaultLog.Trace("controllers/host_controller:ResolveHardwareUuidConflict() Leaving")
+
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/host_controller:ResolveHardwareUuidConflict() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	var resolution hvs.HardwareUuidConflictResolution
+	if err := dec.Decode(&resolution); err != nil {
+		secLog.WithError(err).Errorf("controllers/host_controller:ResolveHardwareUuidConflict() %s :  Failed to decode request body as HardwareUuidConflictResolution", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if resolution.Resolution != hvs.HardwareUuidCollisionAutoSuffix && resolution.Resolution != hvs.HardwareUuidCollisionMerge {
+		secLog.Errorf("controllers/host_controller:ResolveHardwareUuidConflict() %s : Invalid resolution", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Resolution must be auto-suffix or merge"}
+	}
+
+	if resolution.HardwareUuid == uuid.Nil {
+		secLog.Errorf("controllers/host_controller:ResolveHardwareUuidConflict() %s : Hardware UUID must be specified", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Hardware UUID must be specified"}
+	}
+
+	hosts, err := hc.HStore.Search(&models.HostFilterCriteria{HostHardwareId: resolution.HardwareUuid}, nil)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:ResolveHardwareUuidConflict() Host search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search Hosts"}
+	}
+	if len(hosts) < 2 {
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "No hosts conflict on the hardware UUID"}
+	}
+
+	var keptHost *hvs.Host
+	for _, host := range hosts {
+		if host.Id == resolution.HostId {
+			keptHost = host
+		}
+	}
+	if keptHost == nil {
+		secLog.Errorf("controllers/host_controller:ResolveHardwareUuidConflict() %s : Host is not registered with the hardware UUID", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Host with specified id is not registered with the hardware UUID"}
+	}
+
+	resolvedHosts := []*hvs.Host{keptHost}
+	for _, host := range hosts {
+		if host.Id == keptHost.Id {
+			continue
+		}
+		if resolution.Resolution == hvs.HardwareUuidCollisionMerge {
+			if err := hc.HStore.Delete(host.Id); err != nil {
+				defaultLog.WithError(err).WithField("id", host.Id).Error("controllers/host_controller:ResolveHardwareUuidConflict() Host delete failed")
+				return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete Host"}
+			}
+			secLog.WithField("host", host).Infof("Host merged into host %s by: %s", keptHost.HostName, r.RemoteAddr)
+			continue
+		}
+
+		suffixedUuid := suffixedHardwareUuid(resolution.HardwareUuid, host.HostName)
+		if err := hc.updateHardwareUuid(host, suffixedUuid); err != nil {
+			defaultLog.WithError(err).WithField("id", host.Id).Error("controllers/host_controller:ResolveHardwareUuidConflict() Host update failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update Host"}
+		}
+		resolvedHosts = append(resolvedHosts, host)
+	}
+
+	var hostIds []uuid.UUID
+	for _, host := range resolvedHosts {
+		if host.Lifecycle != hvs.HostLifecyclePreRegistered {
+			hostIds = append(hostIds, host.Id)
+		}
+	}
+	if len(hostIds) > 0 {
+		if err := hc.HTManager.VerifyHostsAsync(hostIds, true, false); err != nil {
+			defaultLog.WithError(err).Error("controllers/host_controller:ResolveHardwareUuidConflict() Host to Flavor Verify Queue addition failed")
+		}
+	}
+
+	secLog.Infof("%s: Hardware UUID conflict resolved by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return hvs.HostCollection{Hosts: resolvedHosts}, http.StatusOK, nil
+}
+
+// updateHardwareUuid updates the hardware UUID of the host and of its credential
+func (hc *HostController) updateHardwareUuid(host *hvs.Host, hwUuid uuid.UUID) error {
+	defaultLog.Trace("controllers/host_controller:updateHardwareUuid() Entering")
+	defer defaultLog.Trace("controllers/host_controller:updateHardwareUuid() Leaving")
+
+	host.HardwareUuid = &hwUuid
+	if err := hc.HStore.Update(host); err != nil {
+		return errors.Wrap(err, "Could not update the hardware UUID of the host")
+	}
+
+	hostCredential, err := hc.HCStore.FindByHostId(host.Id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "Could not retrieve the host credential")
+	}
+	hostCredential.HardwareUuid = models.NewHwUUID(hwUuid)
+	return errors.Wrap(hc.HCStore.Update(hostCredential), "Could not update the hardware UUID of the host credential")
+}
+
 func (hc *HostController) retrieveHost(id uuid.UUID, criteria *models.HostInfoFetchCriteria) (interface{}, int, error) {
 	defaultLog.Trace("controllers/host_controller:retrieveHost() Entering")
 	defer defaultLog.Trace("controllers/host_controller:retrieveHost() Leaving")
@@ -451,9 +927,31 @@ func (hc *HostController) retrieveHost(id uuid.UUID, criteria *models.HostInfoFe
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Host from database"}
 		}
 	}
+	if host.HardwareUuid != nil {
+		host.PlatformAttributes = GetPlatformAttributes(hc.PCStore, *host.HardwareUuid)
+	}
 	return host, http.StatusOK, nil
 }
 
+// forTenant returns a copy of the controller whose host, flavor and flavorgroup stores are restricted to the tenant
+// of the user making the request
+func (hc *HostController) forTenant(r *http.Request) (*HostController, int, error) {
+	defaultLog.Trace("controllers/host_controller:forTenant() Entering")
+	defer defaultLog.Trace("controllers/host_controller:forTenant() Leaving")
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/host_controller:forTenant() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	tenantController := *hc
+	tenantController.HStore = hc.HStore.ForTenant(tenantId)
+	tenantController.FStore = hc.FStore.ForTenant(tenantId)
+	tenantController.FGStore = hc.FGStore.ForTenant(tenantId)
+	return &tenantController, http.StatusOK, nil
+}
+
 // GenerateConnectionString creates a formatted connection string. If the username and password are not specified, then it would retrieve it
 // from the credential table and forms the complete connection string.
 func GenerateConnectionString(cs, username, password string, hc domain.HostCredentialStore) (string, string, error) {
@@ -466,7 +964,9 @@ func GenerateConnectionString(cs, username, password string, hc domain.HostCrede
 	}
 
 	var credential string
-	if vc.Vendor != hcConstants.VendorVMware {
+	// the trust agents are reached with the HVS credentials, the VMware hosts and the hosts reached over ssh with
+	// credentials of their own
+	if vc.Vendor != hcConstants.VendorVMware && !hcUtil.IsSshURL(vc.Url) {
 		credential = fmt.Sprintf("u=%s;p=%s", username, password)
 		cs = fmt.Sprintf("%s;%s", cs, credential)
 	} else {
@@ -474,7 +974,7 @@ func GenerateConnectionString(cs, username, password string, hc domain.HostCrede
 		if !strings.Contains(cs, "u=") || !strings.Contains(cs, "p=") {
 			var hostname string
 			// If the connection string is for VMware, we would have this substring from which we need to extract
-			// the host name. Otherwise we can extract the host name after the https:// in the connection string.
+			// the host name. Otherwise we can extract the host name after the https:// or ssh:// in the connection string.
 			if strings.Contains(cs, "h=") {
 				hostname = vc.Configuration.Hostname
 			} else {
@@ -518,17 +1018,47 @@ func GenerateConnectionString(cs, username, password string, hc domain.HostCrede
 	return cs, credential, nil
 }
 
-func (hc *HostController) getHostInfo(cs string) (*model.HostInfo, error) {
+// getHostInfo returns the host info and the capabilities of the host
+func (hc *HostController) getHostInfo(ctx context.Context, cs string) (*model.HostInfo, *model.HostCapabilities, error) {
 	defaultLog.Trace("controllers/host_controller:getHostInfo() Entering")
 	defer defaultLog.Trace("controllers/host_controller:getHostInfo() Leaving")
 
 	hostConnector, err := hc.HCConfig.HostConnectorProvider.NewHostConnector(cs)
 	if err != nil {
-		return nil, errors.Wrap(err, "Could not instantiate host connector")
+		return nil, nil, errors.Wrap(err, "Could not instantiate host connector")
 	}
 
-	hostInfo, err := hostConnector.GetHostDetails()
-	return &hostInfo, err
+	hostInfo, err := hostConnector.GetHostDetails(ctx)
+	if err != nil {
+		return &hostInfo, nil, err
+	}
+
+	// the host is registered without its capabilities when they cannot be discovered, they are discovered when
+	// the host data is fetched
+	capabilities, err := hostConnector.Capabilities(ctx)
+	if err != nil {
+		defaultLog.WithError(err).Warn("controllers/host_controller:getHostInfo() Could not discover the host capabilities")
+		return &hostInfo, nil, nil
+	}
+	return &hostInfo, &capabilities, nil
+}
+
+// defaultFlavorgroupNames returns the flavorgroups a host is associated with when none are requested
+func defaultFlavorgroupNames(hostInfo *model.HostInfo) []string {
+	var fgNames []string
+	if hostInfo.HardwareUUID != "" {
+		// if the host is reachable and no flavorgroups are provided in the request, assign the host to "automatic" flavorgroup
+		defaultLog.Debug("Flavorgroup names not present in request, associating with default ones")
+		fgNames = append(fgNames, models.FlavorGroupsAutomatic.String())
+	}
+
+	// Link to default software and workload groups if host is linux
+	if utils.IsLinuxHost(hostInfo) {
+		defaultLog.Debug("Host is linux, associating with default software flavorgroups")
+		swFgs := utils.GetDefaultSoftwareFlavorGroups(hostInfo.InstalledComponents)
+		fgNames = append(fgNames, swFgs...)
+	}
+	return fgNames
 }
 
 func (hc *HostController) linkFlavorgroupsToHost(flavorgroupNames []string, hostId uuid.UUID) error {
@@ -662,6 +1192,12 @@ func validateHostCreateCriteria(host hvs.HostCreateRequest) error {
 			return errors.Wrap(err, "Valid Host Description must be specified")
 		}
 	}
+	if host.PreRegister && (host.HardwareUuid == nil || *host.HardwareUuid == uuid.Nil) {
+		return errors.New("Valid Hardware UUID must be specified to pre-register a host")
+	}
+	if !host.PreRegister && host.HardwareUuid != nil {
+		return errors.New("Hardware UUID can only be specified to pre-register a host")
+	}
 	if len(host.FlavorgroupNames) != 0 {
 		for _, flavorgroup := range host.FlavorgroupNames {
 			if flavorgroup == "" {
@@ -672,6 +1208,27 @@ func validateHostCreateCriteria(host hvs.HostCreateRequest) error {
 			return errors.Wrap(err, "Valid Flavorgroup Names must be specified")
 		}
 	}
+	if err := hvs.ValidateLabels(host.Labels); err != nil {
+		return errors.Wrap(err, "Valid Host Labels must be specified")
+	}
+	return nil
+}
+
+func validateHostMaintenanceRequest(maintenance hvs.HostMaintenanceRequest, now time.Time) error {
+	defaultLog.Trace("controllers/host_controller:validateHostMaintenanceRequest() Entering")
+	defer defaultLog.Trace("controllers/host_controller:validateHostMaintenanceRequest() Leaving")
+
+	if maintenance.Reason != "" {
+		if len(maintenance.Reason) > 255 {
+			return errors.New("Maintenance reason must not exceed 255 characters")
+		}
+		if err := validation.ValidateStrings([]string{maintenance.Reason}); err != nil {
+			return errors.Wrap(err, "Valid Maintenance reason must be specified")
+		}
+	}
+	if maintenance.ResumeAt != nil && !maintenance.ResumeAt.After(now) {
+		return errors.New("Maintenance resume time must be in the future")
+	}
 	return nil
 }
 
@@ -725,6 +1282,15 @@ func populateHostFilterCriteria(params url.Values) (*models.HostFilterCriteria,
 		criteria.Trusted = &trustStatus
 	}
 
+	if params.Get("lifecycle") != "" {
+		lifecycle := hvs.HostLifecycle(params.Get("lifecycle"))
+		if lifecycle != hvs.HostLifecyclePreRegistered && lifecycle != hvs.HostLifecycleRegistered &&
+			lifecycle != hvs.HostLifecycleRegistrationFailed {
+			return nil, errors.New("Invalid lifecycle query param value, must be PRE_REGISTERED/REGISTERED/REGISTRATION_FAILED")
+		}
+		criteria.Lifecycle = lifecycle
+	}
+
 	if params.Get("orderBy") != "" {
 		orderType, err := models.GetOrderType(params.Get("orderBy"))
 		if err != nil {
@@ -774,6 +1340,11 @@ func (hc *HostController) AddFlavorgroup(w http.ResponseWriter, r *http.Request)
 	defaultLog.Trace("controllers/host_controller:AddFlavorgroup() Entering")
 	defer defaultLog.Trace("controllers/host_controller:AddFlavorgroup() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -787,7 +1358,7 @@ func (hc *HostController) AddFlavorgroup(w http.ResponseWriter, r *http.Request)
 	dec.DisallowUnknownFields()
 
 	var reqHostFlavorgroup hvs.HostFlavorgroupCreateRequest
-	err := dec.Decode(&reqHostFlavorgroup)
+	err = dec.Decode(&reqHostFlavorgroup)
 	if err != nil {
 		secLog.WithError(err).Errorf("controllers/host_controller:AddFlavorgroup() %s :  Failed to decode request body as HostFlavorgroupCreateRequest", commLogMsg.InvalidInputBadEncoding)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
@@ -799,7 +1370,7 @@ func (hc *HostController) AddFlavorgroup(w http.ResponseWriter, r *http.Request)
 	}
 
 	hId := uuid.MustParse(mux.Vars(r)["hId"])
-	_, status, err := hc.retrieveHost(hId, nil)
+	_, status, err = hc.retrieveHost(hId, nil)
 	if err != nil {
 		return nil, status, err
 	}
@@ -852,6 +1423,11 @@ func (hc *HostController) RetrieveFlavorgroup(w http.ResponseWriter, r *http.Req
 	defaultLog.Trace("controllers/host_controller:RetrieveFlavorgroup() Entering")
 	defer defaultLog.Trace("controllers/host_controller:RetrieveFlavorgroup() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	hId := uuid.MustParse(mux.Vars(r)["hId"])
 	fgId := uuid.MustParse(mux.Vars(r)["fgId"])
 	hostFlavorgroup, status, err := hc.retrieveFlavorgroup(hId, fgId)
@@ -867,6 +1443,11 @@ func (hc *HostController) RemoveFlavorgroup(w http.ResponseWriter, r *http.Reque
 	defaultLog.Trace("controllers/host_controller:RemoveFlavorgroup() Entering")
 	defer defaultLog.Trace("controllers/host_controller:RemoveFlavorgroup() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	hId := uuid.MustParse(mux.Vars(r)["hId"])
 	fgId := uuid.MustParse(mux.Vars(r)["fgId"])
 	hostFlavorgroup, status, err := hc.retrieveFlavorgroup(hId, fgId)
@@ -898,7 +1479,17 @@ func (hc *HostController) SearchFlavorgroups(w http.ResponseWriter, r *http.Requ
 	defaultLog.Trace("controllers/host_controller:SearchFlavorgroups() Entering")
 	defer defaultLog.Trace("controllers/host_controller:SearchFlavorgroups() Leaving")
 
+	hc, status, err := hc.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	hId := uuid.MustParse(mux.Vars(r)["hId"])
+	_, status, err = hc.retrieveHost(hId, nil)
+	if err != nil {
+		return nil, status, err
+	}
+
 	fgIds, err := hc.HStore.SearchFlavorgroups(hId)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/host_controller:SearchFlavorgroups() Host Flavorgroup links search failed")
@@ -922,6 +1513,10 @@ func (hc *HostController) retrieveFlavorgroup(hId, fgId uuid.UUID) (interface{},
 	defaultLog.Trace("controllers/host_controller:retrieveFlavorgroup() Entering")
 	defer defaultLog.Trace("controllers/host_controller:retrieveFlavorgroup() Leaving")
 
+	if _, status, err := hc.retrieveHost(hId, nil); err != nil {
+		return nil, status, err
+	}
+
 	hostFlavorgroup, err := hc.HStore.RetrieveFlavorgroup(hId, fgId)
 	if err != nil {
 		if strings.Contains(err.Error(), commErr.RowsNotFound) {
@@ -5,19 +5,25 @@
 package controllers_test
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/mocks"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
 	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	. "github.com/onsi/ginkgo"
@@ -88,6 +94,36 @@ var _ = Describe("HostController", func() {
 				router.ServeHTTP(w, req)
 				Expect(w.Code).To(Equal(http.StatusCreated))
 			})
+			It("Should cache the capabilities of the host in the host record", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost3",
+								"connection_string": "intel:https://another.ta.ip.com:1443"
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var host hvs.Host
+				err = json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.Capabilities).NotTo(BeNil())
+				Expect(host.Capabilities.TPMVersion).To(Equal("2.0"))
+				Expect(host.Capabilities.PCRBanks).To(Equal([]string{"SHA1", "SHA256"}))
+
+				storedHost, err := hostStore.Retrieve(host.Id, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(storedHost.Capabilities).To(Equal(host.Capabilities))
+			})
 		})
 		Context("Provide a Create request that contains duplicate hostname", func() {
 			It("Should fail to create new Host", func() {
@@ -238,9 +274,318 @@ var _ = Describe("HostController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Provide a valid pre-registration request", func() {
+			It("Should pre-register a new Host", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost3",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"pre_register": true,
+								"hardware_uuid": "e84df613-180c-49ca-b2c7-3e5517a3cfb5"
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var host hvs.Host
+				err = json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.Lifecycle).To(Equal(hvs.HostLifecyclePreRegistered))
+				Expect(host.HardwareUuid.String()).To(Equal("e84df613-180c-49ca-b2c7-3e5517a3cfb5"))
+			})
+		})
+		Context("Provide a pre-registration request without hardware UUID", func() {
+			It("Should fail to pre-register new Host", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost3",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"pre_register": true
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a pre-registration request with labels", func() {
+			It("Should pre-register a new Host with the labels", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost4",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"pre_register": true,
+								"hardware_uuid": "2b3a5bd4-0d39-44c4-8d5a-4a4b0e9a3ff1",
+								"labels": {"intel.com/datacenter": "dc1", "os": "rhel"}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var host hvs.Host
+				err = json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.Labels).To(HaveKeyWithValue("intel.com/datacenter", "dc1"))
+			})
+		})
+		Context("Provide a Create request with an invalid label", func() {
+			It("Should fail to create new Host", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost4",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"pre_register": true,
+								"hardware_uuid": "2b3a5bd4-0d39-44c4-8d5a-4a4b0e9a3ff1",
+								"labels": {"os": "rhel 8"}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("Complete the registration of a pre-registered Host", func() {
+		preRegister := func(hardwareUuid string) *hvs.Host {
+			hwUuid := uuid.MustParse(hardwareUuid)
+			createdHost, _, err := hostController.CreateHost(context.Background(), hvs.HostCreateRequest{
+				HostName:         "localhost3",
+				ConnectionString: "intel:https://another.ta.ip.com:1443",
+				PreRegister:      true,
+				HardwareUuid:     &hwUuid,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return createdHost.(*hvs.Host)
+		}
+
+		Context("The host reports the hardware UUID it was pre-registered with", func() {
+			It("Should complete the registration of the Host", func() {
+				host := preRegister("e84df613-180c-49ca-b2c7-3e5517a3cfb5")
+
+				registered, err := hostController.CompleteHostRegistration(context.Background(), host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(registered).To(BeTrue())
+
+				registeredHost, err := hostStore.Retrieve(host.Id, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(registeredHost.Lifecycle).To(Equal(hvs.HostLifecycleRegistered))
+				// the default flavorgroups are associated with the host
+				fgIds, err := hostStore.SearchFlavorgroups(host.Id)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fgIds).NotTo(BeEmpty())
+
+				preRegisteredHosts, err := hostStore.Search(&models.HostFilterCriteria{Lifecycle: hvs.HostLifecyclePreRegistered}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(preRegisteredHosts).To(BeEmpty())
+			})
+		})
+		Context("The host reports another hardware UUID", func() {
+			It("Should fail the registration of the Host", func() {
+				host := preRegister("7a569dad-2d82-49e4-9156-069b0065b262")
+
+				registered, err := hostController.CompleteHostRegistration(context.Background(), host)
+				Expect(err).To(HaveOccurred())
+				Expect(registered).To(BeFalse())
+
+				failedHost, err := hostStore.Retrieve(host.Id, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedHost.Lifecycle).To(Equal(hvs.HostLifecycleRegistrationFailed))
+			})
+		})
 	})
 
 	// Specs for HTTP Get to "/hosts/{hId}"
+	// Specs for the hosts registered with the hardware UUID of a registered host
+	Describe("Register a Host with the hardware UUID of a registered Host", func() {
+		const localhost1HardwareUuid = "e57e5ea0-d465-461e-882d-1600090caa0d"
+		register := func() *httptest.ResponseRecorder {
+			router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+			hostJson := `{
+							"host_name": "localhost3",
+							"connection_string": "intel:https://another.ta.ip.com:1443",
+							"pre_register": true,
+							"hardware_uuid": "` + localhost1HardwareUuid + `"
+						}`
+
+			req, err := http.NewRequest(
+				"POST",
+				"/hosts",
+				strings.NewReader(hostJson),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("The collision policy is not set", func() {
+			It("Should reject the Host", func() {
+				w = register()
+				Expect(w.Code).To(Equal(http.StatusConflict))
+				Expect(w.Body.String()).To(ContainSubstring("localhost1"))
+
+				hosts, err := hostStore.Search(&models.HostFilterCriteria{NameEqualTo: "localhost3"}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hosts).To(BeEmpty())
+			})
+		})
+		Context("The collision policy is auto-suffix", func() {
+			It("Should register the Host with a derived hardware UUID", func() {
+				hostController.HCConfig.HardwareUuidCollisionPolicy = hvs.HardwareUuidCollisionAutoSuffix
+				w = register()
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var host hvs.Host
+				err := json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.HardwareUuid).NotTo(BeNil())
+				Expect(host.HardwareUuid.String()).NotTo(Equal(localhost1HardwareUuid))
+
+				// the hardware UUID is derived the same way every time
+				Expect(*host.HardwareUuid).To(Equal(uuid.NewSHA1(uuid.MustParse(localhost1HardwareUuid), []byte("localhost3"))))
+			})
+		})
+		Context("The collision policy is merge", func() {
+			It("Should update the registered Host", func() {
+				hostController.HCConfig.HardwareUuidCollisionPolicy = hvs.HardwareUuidCollisionMerge
+				w = register()
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var host hvs.Host
+				err := json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.Id.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(host.HostName).To(Equal("localhost3"))
+				Expect(host.HardwareUuid.String()).To(Equal(localhost1HardwareUuid))
+
+				hosts, err := hostStore.Search(&models.HostFilterCriteria{HostHardwareId: uuid.MustParse(localhost1HardwareUuid)}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hosts).To(HaveLen(1))
+			})
+		})
+	})
+
+	// Specs for "/hosts/hardware-uuid-conflicts"
+	Describe("Resolve the hardware UUID conflicts of the Hosts", func() {
+		hardwareUuid := uuid.MustParse("e57e5ea0-d465-461e-882d-1600090caa0d")
+		cloneId := uuid.MustParse("4f3c1d2e-9a8b-4c7d-b6e5-f4a3b2c1d0e9")
+		BeforeEach(func() {
+			_, err := hostStore.Create(&hvs.Host{
+				Id:               cloneId,
+				HostName:         "localhost1-clone",
+				HardwareUuid:     &hardwareUuid,
+				ConnectionString: "intel:https://clone.ta.ip.com:1443",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+		resolve := func(resolution string) *httptest.ResponseRecorder {
+			router.Handle("/hosts/hardware-uuid-conflicts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.ResolveHardwareUuidConflict))).Methods("POST")
+			resolutionJson := `{
+							"hardware_uuid": "` + hardwareUuid.String() + `",
+							"host_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+							"resolution": "` + resolution + `"
+						}`
+
+			req, err := http.NewRequest(
+				"POST",
+				"/hosts/hardware-uuid-conflicts",
+				strings.NewReader(resolutionJson),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Search the hardware UUID conflicts", func() {
+			It("Should list the Hosts registered with the same hardware UUID", func() {
+				router.Handle("/hosts/hardware-uuid-conflicts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.SearchHardwareUuidConflicts))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts/hardware-uuid-conflicts", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var conflicts hvs.HardwareUuidConflictCollection
+				err = json.Unmarshal(w.Body.Bytes(), &conflicts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(conflicts.Conflicts).To(HaveLen(1))
+				Expect(conflicts.Conflicts[0].HardwareUuid).To(Equal(hardwareUuid))
+				Expect(conflicts.Conflicts[0].Hosts).To(HaveLen(2))
+			})
+		})
+		Context("Resolve the conflict with the auto-suffix resolution", func() {
+			It("Should derive the hardware UUID of the other Hosts", func() {
+				w = resolve(hvs.HardwareUuidCollisionAutoSuffix)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				clone, err := hostStore.Retrieve(cloneId, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*clone.HardwareUuid).To(Equal(uuid.NewSHA1(hardwareUuid, []byte("localhost1-clone"))))
+
+				hosts, err := hostStore.Search(&models.HostFilterCriteria{HostHardwareId: hardwareUuid}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hosts).To(HaveLen(1))
+				Expect(hosts[0].HostName).To(Equal("localhost1"))
+			})
+		})
+		Context("Resolve the conflict with the merge resolution", func() {
+			It("Should delete the other Hosts", func() {
+				w = resolve(hvs.HardwareUuidCollisionMerge)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				_, err := hostStore.Retrieve(cloneId, nil)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+		Context("Resolve the conflict with an invalid resolution", func() {
+			It("Should fail to resolve the conflict", func() {
+				w = resolve("reject")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
 	Describe("Retrieve an existing Host", func() {
 		Context("Retrieve Host by ID", func() {
 			It("Should retrieve a Host", func() {
@@ -264,6 +609,33 @@ var _ = Describe("HostController", func() {
 				Expect(w.Code).To(Equal(http.StatusNotFound))
 			})
 		})
+		Context("Retrieve Host of another tenant", func() {
+			It("Should fail to retrieve Host", func() {
+				router.Handle("/hosts/{hId}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserRoles(req, []aas.RoleInfo{{Service: "HVS", Name: "HostManager", Context: "tenant=tenant-a"}})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Retrieve Host with roles of more than one tenant", func() {
+			It("Should fail to retrieve Host", func() {
+				router.Handle("/hosts/{hId}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserRoles(req, []aas.RoleInfo{
+					{Service: "HVS", Name: "HostManager", Context: "tenant=tenant-a"},
+					{Service: "HVS", Name: "ReportRetriever", Context: "tenant=tenant-b"},
+				})
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
 	})
 
 	// Specs for HTTP Put to "/hosts/{hId}"
@@ -354,6 +726,80 @@ var _ = Describe("HostController", func() {
 		})
 	})
 
+	// Specs for HTTP Put and Delete to "/hosts/{hId}/maintenance"
+	Describe("Put a Host in maintenance mode", func() {
+		BeforeEach(func() {
+			router.Handle("/hosts/{hId}/maintenance", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.SetMaintenance))).Methods("PUT")
+			router.Handle("/hosts/{hId}/maintenance", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(hostController.EndMaintenance))).Methods("DELETE")
+		})
+		Context("Provide a valid maintenance request", func() {
+			It("Should put the Host in maintenance mode until it is ended", func() {
+				resumeAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+				req, err := http.NewRequest(
+					"PUT",
+					"/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2/maintenance",
+					strings.NewReader(`{"reason": "BIOS update", "resume_at": "`+resumeAt+`"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var host hvs.Host
+				err = json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.Maintenance).NotTo(BeNil())
+				Expect(host.Maintenance.Reason).To(Equal("BIOS update"))
+				Expect(host.Maintenance.ResumeAt.Format(time.RFC3339)).To(Equal(resumeAt))
+
+				req, err = http.NewRequest("DELETE", "/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2/maintenance", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				req, err = http.NewRequest("DELETE", "/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2/maintenance", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Provide a maintenance request with a resume time in the past", func() {
+			It("Should fail to put the Host in maintenance mode", func() {
+				resumeAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+				req, err := http.NewRequest(
+					"PUT",
+					"/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2/maintenance",
+					strings.NewReader(`{"resume_at": "`+resumeAt+`"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a maintenance request for a non-existent Host", func() {
+			It("Should fail to put the Host in maintenance mode", func() {
+				req, err := http.NewRequest(
+					"PUT",
+					"/hosts/73755fda-c910-46be-821f-e8ddeab189e9/maintenance",
+					strings.NewReader(`{"reason": "OS update"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	// Specs for HTTP Delete to "/hosts/{hId}"
 	Describe("Delete an existing Host", func() {
 		Context("Delete Host by ID", func() {
@@ -397,6 +843,24 @@ var _ = Describe("HostController", func() {
 				Expect(len(hostCollection.Hosts)).To(Equal(2))
 			})
 		})
+		Context("Get all the Hosts of a tenant", func() {
+			It("Should get only the Hosts of the tenant", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req = comctx.SetUserRoles(req, []aas.RoleInfo{{Service: "HVS", Name: "HostManager", Context: "tenant=tenant-a"}})
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var hostCollection hvs.HostCollection
+				err = json.Unmarshal(w.Body.Bytes(), &hostCollection)
+				Expect(err).NotTo(HaveOccurred())
+				// mocked hosts belong to the default namespace
+				Expect(len(hostCollection.Hosts)).To(Equal(0))
+			})
+		})
 		Context("Get all the Hosts with key value params", func() {
 			It("Should get list of all the filtered Hosts", func() {
 				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
@@ -30,7 +30,9 @@ type HostStatusController struct {
 }
 
 var hostStatusSearchParams = map[string]bool{"id": true, "hostId": true, "hostHardwareId": true, "hostName": true, "hostStatus": true,
-	"fromDate": true, "toDate": true, "latestPerHost": true, "numberOfDays": true, "limit": true}
+	"lifecycleState": true, "fromDate": true, "toDate": true, "latestPerHost": true, "numberOfDays": true, "limit": true}
+
+var lifecycleHistorySearchParams = map[string]bool{"hostId": true, "limit": true}
 
 // Search returns a collection of HostStatus based on HostStatusFilter criteria
 func (controller HostStatusController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -87,6 +89,42 @@ func (controller HostStatusController) Retrieve(w http.ResponseWriter, r *http.R
 	return hostStatus, http.StatusOK, nil
 }
 
+// SearchLifecycleHistory returns the lifecycle state transitions of a host, most recent transition first
+func (controller HostStatusController) SearchLifecycleHistory(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/hoststatus_controller:SearchLifecycleHistory() Entering")
+	defer defaultLog.Trace("controllers/hoststatus_controller:SearchLifecycleHistory() Leaving")
+
+	params := r.URL.Query()
+	if err := utils.ValidateQueryParams(params, lifecycleHistorySearchParams); err != nil {
+		secLog.Errorf("controllers/hoststatus_controller:SearchLifecycleHistory() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	hostId, err := uuid.Parse(strings.TrimSpace(params.Get("hostId")))
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/hoststatus_controller:SearchLifecycleHistory() %s : Invalid hostId", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Valid UUID format of the Host Identifier must be specified"}
+	}
+
+	limit := constants.DefaultSearchResultRowLimit
+	if rowLimit := strings.TrimSpace(params.Get("limit")); rowLimit != "" {
+		limit, err = strconv.Atoi(rowLimit)
+		if err != nil || limit <= 0 {
+			secLog.Warnf("controllers/hoststatus_controller:SearchLifecycleHistory() %s : Invalid limit", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Limit must be an integer > 0"}
+		}
+	}
+
+	transitions, err := controller.Store.SearchLifecycleTransitions(hostId, limit)
+	if err != nil {
+		defaultLog.WithError(err).Warnf("controllers/hoststatus_controller:SearchLifecycleHistory() Host lifecycle history search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Host lifecycle history search operation failed"}
+	}
+
+	secLog.Infof("%s: Return Host lifecycle history query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.HostLifecycleTransitionCollection{Transitions: transitions}, http.StatusOK, nil
+}
+
 // getHSFilterCriteria checks for set filter params in the Search request and returns a valid HostStatusFilterCriteria
 func getHSFilterCriteria(params url.Values) (*models.HostStatusFilterCriteria, error) {
 	defaultLog.Trace("controllers/hoststatus_controller:getHSFilterCriteria() Entering")
@@ -142,6 +180,16 @@ func getHSFilterCriteria(params url.Values) (*models.HostStatusFilterCriteria, e
 		hfc.HostStatus = hostState
 	}
 
+	// Host Lifecycle State
+	lifecycleState := strings.TrimSpace(params.Get("lifecycleState"))
+	if lifecycleState != "" {
+		state, ok := hvs.GetHostLifecycleState(lifecycleState)
+		if !ok {
+			return nil, errors.New("Valid contents for LifecycleState must be specified")
+		}
+		hfc.LifecycleState = state
+	}
+
 	// fromDate
 	fromDate := strings.TrimSpace(params.Get("fromDate"))
 	if fromDate != "" {
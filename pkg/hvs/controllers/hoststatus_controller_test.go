@@ -408,6 +408,18 @@ var _ = Describe("HostStatusController", func() {
 			})
 		})
 
+		Context("When searching with an invalid lifecycleState", func() {
+			It("Should get an 400 error", func() {
+				router.Handle("/host-status", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostStatusController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-status?lifecycleState=QUEUE", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
 		Context("When searching with filter latestPerHost=false", func() {
 			It("Should return list of Host Status records from HostStatus Audit Table", func() {
 				router.Handle("/host-status", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostStatusController.Search))).Methods("GET")
@@ -467,4 +479,47 @@ var _ = Describe("HostStatusController", func() {
 			})
 		})
 	})
+
+	// Specs for HTTP Get to "/host-status/lifecycle-history"
+	Describe("Search Host lifecycle history", func() {
+		Context("When a valid hostId is passed", func() {
+			It("Should return the lifecycle transitions of the host", func() {
+				router.Handle("/host-status/lifecycle-history", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostStatusController.SearchLifecycleHistory))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-status/lifecycle-history?hostId=47a3b602-f321-4e03-b3b2-8f3ca3cde128", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var transitions *hvs.HostLifecycleTransitionCollection
+				err = json.Unmarshal(w.Body.Bytes(), &transitions)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(transitions.Transitions)).To(Equal(2))
+				Expect(transitions.Transitions[0].To).To(Equal(hvs.HostLifecycleStateAttested))
+			})
+		})
+		Context("When no hostId is passed", func() {
+			It("Should get an 400 error", func() {
+				router.Handle("/host-status/lifecycle-history", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostStatusController.SearchLifecycleHistory))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-status/lifecycle-history", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("When an invalid limit is passed", func() {
+			It("Should get an 400 error", func() {
+				router.Handle("/host-status/lifecycle-history", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostStatusController.SearchLifecycleHistory))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-status/lifecycle-history?hostId=47a3b602-f321-4e03-b3b2-8f3ca3cde128&limit=-1", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
 })
@@ -0,0 +1,389 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+type PlatformCertificateController struct {
+	Store     domain.PlatformCertificateStore
+	TEStore   domain.TpmEndorsementStore
+	CertStore *models.CertificatesStore
+}
+
+var platformCertificateSearchParams = map[string]bool{"id": true, "hardwareUuidEqualTo": true, "manufacturerEqualTo": true,
+	"modelEqualTo": true, "serialEqualTo": true, "revokedEqualTo": true, "certificateDigestEqualTo": true}
+
+func (controller PlatformCertificateController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/platform_certificate_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:Create() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/platform_certificate_controller:Create() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqPlatformCertificate hvs.PlatformCertificate
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&reqPlatformCertificate)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/platform_certificate_controller:Create() %s :  Failed to decode request body as PlatformCertificate", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if reqPlatformCertificate.HardwareUUID == uuid.Nil || reqPlatformCertificate.Certificate == "" {
+		secLog.Errorf("controllers/platform_certificate_controller:Create() %s : hardware_uuid and certificate must be specified", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "hardware_uuid and certificate must be specified"}
+	}
+
+	cert, err := getPlatformCertificate(reqPlatformCertificate.Certificate)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/platform_certificate_controller:Create() %s : Invalid platform certificate", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Valid contents for certificate must be specified"}
+	}
+
+	if time.Now().After(cert.NotAfter) || time.Now().Before(cert.NotBefore) {
+		secLog.Errorf("controllers/platform_certificate_controller:Create() %s : Platform certificate is not within its validity period", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Platform certificate is expired or not yet valid"}
+	}
+
+	var platformCaCerts []x509.Certificate
+	if caStore, ok := (*controller.CertStore)[models.CaCertTypesPlatformCa.String()]; ok && caStore != nil {
+		platformCaCerts = caStore.Certificates
+	}
+	if err = utils.VerifyPlatformCertificate(cert, platformCaCerts); err != nil {
+		secLog.WithError(err).Errorf("controllers/platform_certificate_controller:Create() %s : Platform certificate verification failed", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Platform certificate is not issued by a trusted platform CA"}
+	}
+
+	attributes, err := utils.GetPlatformAttributes(cert)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/platform_certificate_controller:Create() %s : Invalid platform attributes", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	// the platform certificate is bound to the TPM through the registered, non revoked EK certificate of the host
+	tpmEndorsements, err := controller.TEStore.Search(&models.TpmEndorsementFilterCriteria{
+		HardwareUuidEqualTo: reqPlatformCertificate.HardwareUUID,
+		RevokedEqualTo:      false,
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/platform_certificate_controller:Create() TpmEndorsement search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search TpmEndorsement for hardware_uuid"}
+	}
+	if tpmEndorsements == nil || len(tpmEndorsements.TpmEndorsement) == 0 {
+		secLog.WithField("HardwareUUID", reqPlatformCertificate.HardwareUUID).Errorf("controllers/platform_certificate_controller:Create() %s : No registered EK certificate for hardware_uuid", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "No valid TpmEndorsement is registered for hardware_uuid"}
+	}
+
+	// the holder of the platform certificate must be one of the EK certificates of the host
+	var holderEndorsement *hvs.TpmEndorsement
+	for _, tpmEndorsement := range tpmEndorsements.TpmEndorsement {
+		ekCert, err := getEkCertificate(tpmEndorsement)
+		if err != nil {
+			defaultLog.WithError(err).WithField("id", tpmEndorsement.ID).Warn("controllers/platform_certificate_controller:Create() Invalid EK certificate")
+			continue
+		}
+		if utils.VerifyPlatformCertificateHolder(cert, ekCert) == nil {
+			holderEndorsement = tpmEndorsement
+			break
+		}
+	}
+	if holderEndorsement == nil {
+		secLog.WithField("HardwareUUID", reqPlatformCertificate.HardwareUUID).Errorf("controllers/platform_certificate_controller:Create() %s : Platform certificate holder is not a registered EK certificate of hardware_uuid", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Platform certificate is not held by the TpmEndorsement registered for hardware_uuid"}
+	}
+
+	existingPlatformCertificates, err := controller.Store.Search(&models.PlatformCertificateFilterCriteria{
+		HardwareUuidEqualTo: reqPlatformCertificate.HardwareUUID,
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/platform_certificate_controller:Create() PlatformCertificate search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search PlatformCertificate for hardware_uuid"}
+	}
+	if existingPlatformCertificates != nil && len(existingPlatformCertificates.PlatformCertificates) > 0 {
+		secLog.WithField("HardwareUUID", reqPlatformCertificate.HardwareUUID).Warningf("%s: Trying to create duplicated PlatformCertificate from addr: %s", commLogMsg.InvalidInputBadParam, r.RemoteAddr)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "PlatformCertificate with same hardware_uuid already exist."}
+	}
+
+	certificateDigest, err := crypt.GetHashData(cert.Raw, crypto.SHA384)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/platform_certificate_controller:Create() Error while generating certificate digest")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while generating certificate digest"}
+	}
+	reqPlatformCertificate.CertificateDigest = hex.EncodeToString(certificateDigest)
+	reqPlatformCertificate.Issuer = cert.Issuer.String()
+	reqPlatformCertificate.Manufacturer = attributes.Manufacturer
+	reqPlatformCertificate.Model = attributes.Model
+	reqPlatformCertificate.Serial = attributes.Serial
+	reqPlatformCertificate.Version = attributes.Version
+	reqPlatformCertificate.EkCertificateDigest = holderEndorsement.CertificateDigest
+	reqPlatformCertificate.NotBefore = cert.NotBefore
+	reqPlatformCertificate.NotAfter = cert.NotAfter
+
+	newPlatformCertificate, err := controller.Store.Create(&reqPlatformCertificate)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/platform_certificate_controller:Create() PlatformCertificate create failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error on inserting PlatformCertificate"}
+	}
+	secLog.WithField("HardwareUUID", reqPlatformCertificate.HardwareUUID).Infof("%s: PlatformCertificate created by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return newPlatformCertificate, http.StatusCreated, nil
+}
+
+func (controller PlatformCertificateController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/platform_certificate_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), platformCertificateSearchParams); err != nil {
+		secLog.Errorf("controllers/platform_certificate_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getPlatformCertificateFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/platform_certificate_controller:Search() %s Invalid input provided in filter criteria", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid input provided in filter criteria"}
+	}
+
+	platformCertificateCollection, err := controller.Store.Search(filter)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/platform_certificate_controller:Search() PlatformCertificate search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search PlatformCertificate"}
+	}
+
+	secLog.Infof("%s: Return platform-certificate query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return platformCertificateCollection, http.StatusOK, nil
+}
+
+func (controller PlatformCertificateController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/platform_certificate_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:Retrieve() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+
+	platformCertificate, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Retrieve() PlatformCertificate with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "PlatformCertificate with given ID does not exist"}
+		} else {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Retrieve() failed to retrieve PlatformCertificate")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve PlatformCertificate"}
+		}
+	}
+
+	secLog.WithField("ID", platformCertificate.ID).Infof("PlatformCertificate retrieved by: %s", r.RemoteAddr)
+	return platformCertificate, http.StatusOK, nil
+}
+
+func (controller PlatformCertificateController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/platform_certificate_controller:Delete() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:Delete() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+
+	delPlatformCertificate, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Delete() PlatformCertificate with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "PlatformCertificate with given ID does not exist"}
+		} else {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Delete() attempt to delete invalid PlatformCertificate")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete PlatformCertificate"}
+		}
+	}
+
+	if err := controller.Store.Delete(id); err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/platform_certificate_controller:Delete() failed to delete PlatformCertificate")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete PlatformCertificate"}
+	}
+	secLog.WithField("ID", delPlatformCertificate.ID).Infof("PlatformCertificate deleted by: %s", r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+// Revoke revokes a platform certificate, the platform attributes are no longer reported from it and another platform
+// certificate can be registered for the host
+func (controller PlatformCertificateController) Revoke(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/platform_certificate_controller:Revoke() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:Revoke() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+
+	platformCertificate, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Revoke() PlatformCertificate with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "PlatformCertificate with given ID does not exist"}
+		} else {
+			secLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Revoke() failed to retrieve PlatformCertificate")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to revoke PlatformCertificate"}
+		}
+	}
+
+	if !platformCertificate.Revoked {
+		platformCertificate.Revoked = true
+		platformCertificate, err = controller.Store.Update(platformCertificate)
+		if err != nil {
+			defaultLog.WithError(err).WithField("id", id).Error(
+				"controllers/platform_certificate_controller:Revoke() failed to revoke PlatformCertificate")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to revoke PlatformCertificate"}
+		}
+	}
+	secLog.WithField("ID", platformCertificate.ID).Infof("%s: PlatformCertificate revoked by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return platformCertificate, http.StatusOK, nil
+}
+
+// GetPlatformAttributes returns the verified platform attributes for the given hardware UUID, or nil when no
+// valid platform certificate has been registered for the host
+func GetPlatformAttributes(store domain.PlatformCertificateStore, hardwareUUID uuid.UUID) *hvs.PlatformAttributes {
+	defaultLog.Trace("controllers/platform_certificate_controller:GetPlatformAttributes() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:GetPlatformAttributes() Leaving")
+
+	if store == nil || hardwareUUID == uuid.Nil {
+		return nil
+	}
+	platformCertificates, err := store.Search(&models.PlatformCertificateFilterCriteria{
+		HardwareUuidEqualTo: hardwareUUID,
+		RevokedEqualTo:      false,
+	})
+	if err != nil {
+		defaultLog.WithError(err).WithField("HardwareUUID", hardwareUUID).Warn(
+			"controllers/platform_certificate_controller:GetPlatformAttributes() Failed to search PlatformCertificate")
+		return nil
+	}
+	for _, pc := range platformCertificates.PlatformCertificates {
+		if time.Now().After(pc.NotAfter) {
+			continue
+		}
+		return &hvs.PlatformAttributes{
+			Manufacturer:  pc.Manufacturer,
+			Model:         pc.Model,
+			Serial:        pc.Serial,
+			Version:       pc.Version,
+			CertificateID: pc.ID,
+		}
+	}
+	return nil
+}
+
+// getPlatformCertificate decodes a base64 encoded PEM or DER platform attribute certificate
+func getPlatformCertificate(certificate string) (*utils.PlatformCertificate, error) {
+	certBytes, err := base64.StdEncoding.DecodeString(certificate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while base64 decoding platform certificate")
+	}
+	if block, _ := pem.Decode(certBytes); block != nil {
+		certBytes = block.Bytes
+	}
+	cert, err := utils.ParsePlatformCertificate(certBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while parsing platform certificate")
+	}
+	return cert, nil
+}
+
+// getEkCertificate decodes the base64 encoded PEM EK certificate of a TpmEndorsement
+func getEkCertificate(tpmEndorsement *hvs.TpmEndorsement) (*x509.Certificate, error) {
+	certPem, err := base64.StdEncoding.DecodeString(tpmEndorsement.Certificate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while base64 decoding ek certificate")
+	}
+	cert, err := crypt.GetCertFromPem(certPem)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while parsing ek certificate")
+	}
+	return cert, nil
+}
+
+func getPlatformCertificateFilterCriteria(params url.Values) (*models.PlatformCertificateFilterCriteria, error) {
+	defaultLog.Trace("controllers/platform_certificate_controller:getPlatformCertificateFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/platform_certificate_controller:getPlatformCertificateFilterCriteria() Leaving")
+
+	var criteria models.PlatformCertificateFilterCriteria
+	if id := params.Get("id"); id != "" {
+		id, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errors.New("Invalid id query param value, must be UUID")
+		}
+		criteria.Id = id
+	}
+	if hwId := params.Get("hardwareUuidEqualTo"); hwId != "" {
+		hwId, err := uuid.Parse(hwId)
+		if err != nil {
+			return nil, errors.New("Invalid hardwareUuidEqualTo query param value, must be UUID")
+		}
+		criteria.HardwareUuidEqualTo = hwId
+	}
+	if manufacturer := params.Get("manufacturerEqualTo"); manufacturer != "" {
+		if err := validation.ValidateStrings(strings.Split(manufacturer, " ")); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for ManufacturerEqualTo must be specified")
+		}
+		criteria.ManufacturerEqualTo = manufacturer
+	}
+	if model := params.Get("modelEqualTo"); model != "" {
+		if err := validation.ValidateStrings(strings.Split(model, " ")); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for ModelEqualTo must be specified")
+		}
+		criteria.ModelEqualTo = model
+	}
+	if serial := params.Get("serialEqualTo"); serial != "" {
+		if err := validation.ValidateStrings([]string{serial}); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for SerialEqualTo must be specified")
+		}
+		criteria.SerialEqualTo = serial
+	}
+	if revokedEqualTo := params.Get("revokedEqualTo"); revokedEqualTo != "" {
+		revoked, err := strconv.ParseBool(revokedEqualTo)
+		if err != nil {
+			return nil, errors.Wrap(err, "Valid contents for RevokedEqualTo must be specified")
+		}
+		criteria.RevokedEqualTo = revoked
+	}
+	if certificateDigestEqualTo := params.Get("certificateDigestEqualTo"); certificateDigestEqualTo != "" {
+		if err := validation.ValidateHexString(certificateDigestEqualTo); err != nil {
+			return nil, errors.New("Valid contents for CertificateDigestEqualTo must be specified")
+		}
+		criteria.CertificateDigestEqualTo = certificateDigestEqualTo
+	}
+	return &criteria, nil
+}
@@ -0,0 +1,368 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type platformAttributeCertificate struct {
+	Info               asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type platformAttributeCertificateInfo struct {
+	Version            int
+	Holder             platformHolder
+	Issuer             platformIssuer `asn1:"tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SerialNumber       *big.Int
+	Validity           platformValidity
+	Attributes         []platformAttribute
+	Extensions         []pkix.Extension
+}
+
+type platformHolder struct {
+	BaseCertificateID platformHolderIssuerSerial `asn1:"tag:0"`
+}
+
+type platformHolderIssuerSerial struct {
+	Issuer []asn1.RawValue
+	Serial *big.Int
+}
+
+type platformIssuer struct {
+	IssuerName []asn1.RawValue
+}
+
+type platformValidity struct {
+	NotBefore time.Time `asn1:"generalized"`
+	NotAfter  time.Time `asn1:"generalized"`
+}
+
+type platformAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// getMockEkCertificate returns the EK certificate registered in the mock TpmEndorsement store with the given ID
+func getMockEkCertificate(id uuid.UUID) *x509.Certificate {
+	tpmEndorsement, err := mocks2.NewFakeTpmEndorsementStore().Retrieve(id)
+	Expect(err).NotTo(HaveOccurred())
+	certPem, err := base64.StdEncoding.DecodeString(tpmEndorsement.Certificate)
+	Expect(err).NotTo(HaveOccurred())
+	block, _ := pem.Decode(certPem)
+	Expect(block).NotTo(BeNil())
+	ekCert, err := x509.ParseCertificate(block.Bytes)
+	Expect(err).NotTo(HaveOccurred())
+	return ekCert
+}
+
+// platformDirectoryName returns the directoryName GeneralName of the DER encoded name
+func platformDirectoryName(rawName []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: rawName}
+}
+
+// createPlatformCertificate creates a CA and a platform attribute certificate signed by it carrying the TCG platform
+// attributes, held by the EK certificate
+func createPlatformCertificate(ekCert *x509.Certificate) (*x509.Certificate, []byte) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Platform CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	caCert, err := x509.ParseCertificate(caDer)
+	Expect(err).NotTo(HaveOccurred())
+
+	// the platform manufacturer, model and version are in the directory name of the subject alternative name
+	platformName, err := asn1.Marshal(pkix.Name{ExtraNames: []pkix.AttributeTypeAndValue{
+		{Type: asn1.ObjectIdentifier{2, 23, 133, 5, 1, 1}, Value: "Intel Corporation"},
+		{Type: asn1.ObjectIdentifier{2, 23, 133, 5, 1, 4}, Value: "S2600WF"},
+		{Type: asn1.ObjectIdentifier{2, 23, 133, 5, 1, 5}, Value: "H48104-850"},
+	}}.ToRDNSequence())
+	Expect(err).NotTo(HaveOccurred())
+	subjectAltName, err := asn1.Marshal([]asn1.RawValue{platformDirectoryName(platformName)})
+	Expect(err).NotTo(HaveOccurred())
+	platformSerial, err := asn1.MarshalWithParams("BQWL83250078", "utf8")
+	Expect(err).NotTo(HaveOccurred())
+
+	signatureAlgorithm := pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}, Parameters: asn1.NullRawValue}
+	info, err := asn1.Marshal(platformAttributeCertificateInfo{
+		Version: 1,
+		Holder: platformHolder{BaseCertificateID: platformHolderIssuerSerial{
+			Issuer: []asn1.RawValue{platformDirectoryName(ekCert.RawIssuer)},
+			Serial: ekCert.SerialNumber,
+		}},
+		Issuer:             platformIssuer{IssuerName: []asn1.RawValue{platformDirectoryName(caCert.RawSubject)}},
+		SignatureAlgorithm: signatureAlgorithm,
+		SerialNumber:       big.NewInt(2),
+		Validity: platformValidity{
+			NotBefore: time.Now().Add(-time.Hour).UTC().Truncate(time.Second),
+			NotAfter:  time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second),
+		},
+		Attributes: []platformAttribute{
+			{Type: asn1.ObjectIdentifier{2, 23, 133, 5, 1, 6}, Values: []asn1.RawValue{{FullBytes: platformSerial}}},
+		},
+		Extensions: []pkix.Extension{{Id: asn1.ObjectIdentifier{2, 5, 29, 17}, Value: subjectAltName}},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	digest := sha256.Sum256(info)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, caKey, crypto.SHA256, digest[:])
+	Expect(err).NotTo(HaveOccurred())
+	certDer, err := asn1.Marshal(platformAttributeCertificate{
+		Info:               asn1.RawValue{FullBytes: info},
+		SignatureAlgorithm: signatureAlgorithm,
+		SignatureValue:     asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return caCert, certDer
+}
+
+var _ = Describe("PlatformCertificateController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var platformCertificateStore *mocks2.MockPlatformCertificateStore
+	var platformCertificateController *controllers.PlatformCertificateController
+	var platformCertificate []byte
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		var caCert *x509.Certificate
+		caCert, platformCertificate = createPlatformCertificate(getMockEkCertificate(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")))
+		platformCertificateStore = mocks2.NewFakePlatformCertificateStore()
+		platformCertificateController = &controllers.PlatformCertificateController{
+			Store:   platformCertificateStore,
+			TEStore: mocks2.NewFakeTpmEndorsementStore(),
+			CertStore: &models.CertificatesStore{
+				models.CaCertTypesPlatformCa.String(): &models.CertificateStore{
+					Certificates: []x509.Certificate{*caCert},
+				},
+			},
+		}
+	})
+
+	// Specs for HTTP Post to "/platform-certificates"
+	Describe("Create PlatformCertificate", func() {
+		Context("Provide a platform certificate for a host with a registered EK", func() {
+			It("Should create PlatformCertificate with the verified platform attributes", func() {
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Create))).Methods("POST")
+				body, _ := json.Marshal(hvs.PlatformCertificate{
+					HardwareUUID: uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e3"),
+					Certificate:  base64.StdEncoding.EncodeToString(platformCertificate),
+				})
+				req, err := http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var pc hvs.PlatformCertificate
+				err = json.Unmarshal(w.Body.Bytes(), &pc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pc.Manufacturer).To(Equal("Intel Corporation"))
+				Expect(pc.Model).To(Equal("S2600WF"))
+				Expect(pc.Serial).To(Equal("BQWL83250078"))
+				Expect(pc.EkCertificateDigest).NotTo(BeEmpty())
+
+				attributes := controllers.GetPlatformAttributes(platformCertificateStore, pc.HardwareUUID)
+				Expect(attributes).NotTo(BeNil())
+				Expect(attributes.CertificateID).To(Equal(pc.ID))
+			})
+		})
+		Context("Provide a platform certificate for a host without a registered EK", func() {
+			It("Should fail to create PlatformCertificate", func() {
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Create))).Methods("POST")
+				body, _ := json.Marshal(hvs.PlatformCertificate{
+					HardwareUUID: uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e5"),
+					Certificate:  base64.StdEncoding.EncodeToString(platformCertificate),
+				})
+				req, err := http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a platform certificate held by another EK", func() {
+			It("Should fail to create PlatformCertificate", func() {
+				caCert, otherCertificate := createPlatformCertificate(getMockEkCertificate(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e4")))
+				platformCertificateController.CertStore = &models.CertificatesStore{
+					models.CaCertTypesPlatformCa.String(): &models.CertificateStore{
+						Certificates: []x509.Certificate{*caCert},
+					},
+				}
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Create))).Methods("POST")
+				body, _ := json.Marshal(hvs.PlatformCertificate{
+					HardwareUUID: uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e3"),
+					Certificate:  base64.StdEncoding.EncodeToString(otherCertificate),
+				})
+				req, err := http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a platform certificate issued by an untrusted CA", func() {
+			It("Should fail to create PlatformCertificate", func() {
+				_, untrustedCertificate := createPlatformCertificate(getMockEkCertificate(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")))
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Create))).Methods("POST")
+				body, _ := json.Marshal(hvs.PlatformCertificate{
+					HardwareUUID: uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e3"),
+					Certificate:  base64.StdEncoding.EncodeToString(untrustedCertificate),
+				})
+				req, err := http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide an X.509 certificate instead of a platform attribute certificate", func() {
+			It("Should fail to create PlatformCertificate", func() {
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Create))).Methods("POST")
+				body, _ := json.Marshal(hvs.PlatformCertificate{
+					HardwareUUID: uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e3"),
+					Certificate:  base64.StdEncoding.EncodeToString(getMockEkCertificate(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")).Raw),
+				})
+				req, err := http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/platform-certificates/{id}/revoke"
+	Describe("Revoke PlatformCertificate", func() {
+		Context("Revoke the PlatformCertificate of a host", func() {
+			It("Should no longer report the platform attributes and accept another certificate", func() {
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Create))).Methods("POST")
+				router.Handle("/platform-certificates/{id}/revoke", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Revoke))).Methods("POST")
+				hardwareUUID := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e3")
+				body, _ := json.Marshal(hvs.PlatformCertificate{
+					HardwareUUID: hardwareUUID,
+					Certificate:  base64.StdEncoding.EncodeToString(platformCertificate),
+				})
+				req, err := http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				var pc hvs.PlatformCertificate
+				err = json.Unmarshal(w.Body.Bytes(), &pc)
+				Expect(err).NotTo(HaveOccurred())
+
+				req, err = http.NewRequest("POST", "/platform-certificates/"+pc.ID.String()+"/revoke", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var revoked hvs.PlatformCertificate
+				err = json.Unmarshal(w.Body.Bytes(), &revoked)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(revoked.Revoked).To(BeTrue())
+				Expect(controllers.GetPlatformAttributes(platformCertificateStore, hardwareUUID)).To(BeNil())
+
+				req, err = http.NewRequest("POST", "/platform-certificates", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+			})
+		})
+		Context("Revoke a PlatformCertificate that does not exist", func() {
+			It("Should return 404", func() {
+				router.Handle("/platform-certificates/{id}/revoke", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Revoke))).Methods("POST")
+				req, err := http.NewRequest("POST", "/platform-certificates/ee37c360-7eae-4250-a677-6ee12adce8e2/revoke", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/platform-certificates"
+	Describe("Search PlatformCertificates", func() {
+		Context("Search with an invalid hardware UUID", func() {
+			It("Should fail to search PlatformCertificates", func() {
+				router.Handle("/platform-certificates", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/platform-certificates?hardwareUuidEqualTo=abc", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/platform-certificates/{id}"
+	Describe("Retrieve PlatformCertificate", func() {
+		Context("Retrieve a PlatformCertificate that does not exist", func() {
+			It("Should return 404", func() {
+				router.Handle("/platform-certificates/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(platformCertificateController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/platform-certificates/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
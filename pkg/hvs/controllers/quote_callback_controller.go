@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+)
+
+// QuoteCallbackController receives the TPM quotes the trust agents post back for asynchronous quote requests
+type QuoteCallbackController struct {
+	Callbacks *hostConnector.QuoteCallbacks
+}
+
+func (controller QuoteCallbackController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/quote_callback_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/quote_callback_controller:Create() Leaving")
+
+	if controller.Callbacks == nil {
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Asynchronous quote collection is not enabled"}
+	}
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeXml {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/quote_callback_controller:Create() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	correlationID := mux.Vars(r)["id"]
+
+	var quote taModel.TpmQuoteResponse
+	if err := xml.NewDecoder(r.Body).Decode(&quote); err != nil {
+		secLog.WithError(err).Errorf("controllers/quote_callback_controller:Create() %s :  Failed to decode request body as TpmQuoteResponse", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode XML request body"}
+	}
+
+	if err := controller.Callbacks.Deliver(correlationID, quote); err != nil {
+		secLog.WithField("id", correlationID).Warningf("controllers/quote_callback_controller:Create() %s : Quote posted for unknown request from addr: %s", commLogMsg.InvalidInputBadParam, r.RemoteAddr)
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: err.Error()}
+	}
+	return nil, http.StatusNoContent, nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuoteCallbackController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var quoteCallbackController *controllers.QuoteCallbackController
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		quoteCallbackController = &controllers.QuoteCallbackController{
+			Callbacks: hostConnector.NewQuoteCallbacks("https://hvs.server.com:8443/hvs/v2/quote-callbacks", time.Minute),
+		}
+	})
+
+	postQuote := func(contentType string) *httptest.ResponseRecorder {
+		router.Handle("/quote-callbacks/{id}", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(quoteCallbackController.Create))).Methods("POST")
+		body, _ := xml.Marshal(taModel.TpmQuoteResponse{Quote: "AIv/VUNHgBgAIgALN5Ot"})
+		req, err := http.NewRequest("POST", "/quote-callbacks/ee37c360-7eae-4250-a677-6ee12adce8e2", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", contentType)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Specs for HTTP Post to "/quote-callbacks/{id}"
+	Describe("Post a TPM quote", func() {
+		Context("Provide a quote for a request that is not pending", func() {
+			It("Should return 404", func() {
+				w = postQuote(consts.HTTPMediaTypeXml)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Provide a quote with an invalid Content-Type", func() {
+			It("Should return 415", func() {
+				w = postQuote(consts.HTTPMediaTypeJson)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+		Context("Provide a quote when asynchronous quote collection is not enabled", func() {
+			It("Should return 404", func() {
+				quoteCallbackController.Callbacks = nil
+				w = postQuote(consts.HTTPMediaTypeXml)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -5,6 +5,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -23,6 +24,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ReportController struct {
@@ -30,16 +32,22 @@ type ReportController struct {
 	HostStore       domain.HostStore
 	HostStatusStore domain.HostStatusStore
 	HTManager       domain.HostTrustManager
+	PCStore         domain.PlatformCertificateStore
 }
 
 func NewReportController(rs domain.ReportStore, hs domain.HostStore, hsts domain.HostStatusStore, ht domain.HostTrustManager) *ReportController {
-	return &ReportController{rs, hs, hsts, ht}
+	return &ReportController{ReportStore: rs, HostStore: hs, HostStatusStore: hsts, HTManager: ht}
 }
 
 func (controller ReportController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/report_controller:Create() Entering")
 	defer defaultLog.Trace("controllers/report_controller:Create() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -54,7 +62,7 @@ func (controller ReportController) Create(w http.ResponseWriter, r *http.Request
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 
-	err := dec.Decode(&reqReportCreateRequest)
+	err = dec.Decode(&reqReportCreateRequest)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/report_controller:Create() %s :  Failed to decode request body as Report Create Criteria", commLogMsg.AppRuntimeErr)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
@@ -65,7 +73,7 @@ func (controller ReportController) Create(w http.ResponseWriter, r *http.Request
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Bad input given in input request"}
 	}
 
-	hvsReport, err := controller.createReport(reqReportCreateRequest)
+	hvsReport, err := controller.createReport(r.Context(), reqReportCreateRequest)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/report_controller:Create() Error while creating report")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
@@ -75,12 +83,12 @@ func (controller ReportController) Create(w http.ResponseWriter, r *http.Request
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while creating report"}
 	}
 
-	report := ConvertToReport(hvsReport)
+	report := controller.convertToReport(hvsReport)
 	secLog.WithField("Name", report.HostInfo.HostName).Infof("%s: report created by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
 	return report, http.StatusCreated, nil
 }
 
-func (controller ReportController) createReport(rsCriteria hvs.ReportCreateRequest) (*models.HVSReport, error) {
+func (controller ReportController) createReport(ctx context.Context, rsCriteria hvs.ReportCreateRequest) (*models.HVSReport, error) {
 	defaultLog.Trace("controllers/report_controller:createReport() Entering")
 	defer defaultLog.Trace("controllers/report_controller:createReport() Leaving")
 	hsCriteria := getHostFilterCriteria(rsCriteria)
@@ -94,7 +102,7 @@ func (controller ReportController) createReport(rsCriteria hvs.ReportCreateReque
 	}
 	//Always only one record is returned for the particular criteria
 	hostId := hosts[0].Id
-	hvsReport, err := controller.HTManager.VerifyHost(hostId, true, true)
+	hvsReport, err := controller.HTManager.VerifyHost(ctx, hostId, true, true)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/report_controller:createReport() Failed to create a trust report, flavor verification failed")
 	} else if hvsReport == nil {
@@ -116,6 +124,11 @@ func (controller ReportController) CreateSaml(w http.ResponseWriter, r *http.Req
 	defaultLog.Trace("controllers/report_controller:CreateSaml() Entering")
 	defer defaultLog.Trace("controllers/report_controller:CreateSaml() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
@@ -134,7 +147,7 @@ func (controller ReportController) CreateSaml(w http.ResponseWriter, r *http.Req
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 
-	err := dec.Decode(&reqReportCreateRequest)
+	err = dec.Decode(&reqReportCreateRequest)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/report_controller:CreateSaml() %s :  Failed to decode request body as Report Create Criteria", commLogMsg.AppRuntimeErr)
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
@@ -145,7 +158,7 @@ func (controller ReportController) CreateSaml(w http.ResponseWriter, r *http.Req
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Bad input given in input request"}
 	}
 
-	hvsReport, err := controller.createReport(reqReportCreateRequest)
+	hvsReport, err := controller.createReport(r.Context(), reqReportCreateRequest)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/report_controller:CreateSaml() Error while creating SAML report")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
@@ -163,6 +176,11 @@ func (controller ReportController) Retrieve(w http.ResponseWriter, r *http.Reque
 	defaultLog.Trace("controllers/report_controller:Retrieve() Entering")
 	defer defaultLog.Trace("controllers/report_controller:Retrieve() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	id := uuid.MustParse(mux.Vars(r)["id"])
 
 	hvsReport, err := controller.ReportStore.Retrieve(id)
@@ -178,7 +196,7 @@ func (controller ReportController) Retrieve(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	report := ConvertToReport(hvsReport)
+	report := controller.convertToReport(hvsReport)
 	secLog.WithField("report", report).Infof("%s: Report retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
 	return report, http.StatusOK, nil
 }
@@ -186,8 +204,12 @@ func (controller ReportController) Retrieve(w http.ResponseWriter, r *http.Reque
 func (controller ReportController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/report_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/report_controller:Search() Leaving")
-	//Search params for reports is same as that of host status APIs
-	if err := utils.ValidateQueryParams(r.URL.Query(), hostStatusSearchParams); err != nil {
+
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+	if err := utils.ValidateQueryParams(r.URL.Query(), reportSearchParams); err != nil {
 		secLog.Errorf("controllers/report_controller:Search() %s", err.Error())
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
@@ -209,7 +231,7 @@ func (controller ReportController) Search(w http.ResponseWriter, r *http.Request
 		Reports: []*hvs.Report{},
 	}
 	for _, hvsReport := range hvsReportCollection {
-		reportCollection.Reports = append(reportCollection.Reports, ConvertToReport(&hvsReport))
+		reportCollection.Reports = append(reportCollection.Reports, controller.convertToReport(&hvsReport))
 	}
 	secLog.Infof("%s: Reports searched by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
 	return reportCollection, http.StatusOK, nil
@@ -219,14 +241,18 @@ func (controller ReportController) SearchSaml(w http.ResponseWriter, r *http.Req
 	defaultLog.Trace("controllers/report_controller:SearchSaml() Entering")
 	defer defaultLog.Trace("controllers/report_controller:SearchSaml() Leaving")
 
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
 	if r.Header.Get("Accept") != constants.HTTPMediaTypeSaml {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{
 			Message: "Invalid Accept type",
 		}
 	}
 
-	//Search params for reports is same as that of host status APIs
-	if err := utils.ValidateQueryParams(r.URL.Query(), hostStatusSearchParams); err != nil {
+	if err := utils.ValidateQueryParams(r.URL.Query(), reportSearchParams); err != nil {
 		secLog.Errorf("controllers/report_controller:Search() %s", err.Error())
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
@@ -254,6 +280,145 @@ func (controller ReportController) SearchSaml(w http.ResponseWriter, r *http.Req
 	return samlCollection.String(), http.StatusOK, nil
 }
 
+// TrustHistory returns the trust of a host between two dates, summed up by hour, day or week
+func (controller ReportController) TrustHistory(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/report_controller:TrustHistory() Entering")
+	defer defaultLog.Trace("controllers/report_controller:TrustHistory() Leaving")
+
+	controller, status, err := controller.forTenant(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), trustHistoryParams); err != nil {
+		secLog.Errorf("controllers/report_controller:TrustHistory() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	history, err := getTrustHistoryCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/report_controller:TrustHistory() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	history.HostId = uuid.MustParse(mux.Vars(r)["hId"])
+	if _, err := controller.HostStore.Retrieve(history.HostId, nil); err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", history.HostId).Info(
+				"controllers/report_controller:TrustHistory() Host with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Host with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", history.HostId).Error(
+			"controllers/report_controller:TrustHistory() Host retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Host"}
+	}
+
+	hourly, err := controller.ReportStore.SearchTrustHistory(history.HostId, history.FromDate, history.ToDate)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/report_controller:TrustHistory() Trust history search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search trust history"}
+	}
+	history.Buckets = bucketTrustHistory(hourly, history.Interval)
+
+	secLog.Infof("%s: Trust history of host %s retrieved by: %s", commLogMsg.AuthorizedAccess, history.HostId, r.RemoteAddr)
+	return history, http.StatusOK, nil
+}
+
+var trustHistoryParams = map[string]bool{"fromDate": true, "toDate": true, "interval": true}
+
+// getTrustHistoryCriteria returns the period and interval of the trust history request, the last
+// DefaultTrustHistoryDays by day when they are not given
+func getTrustHistoryCriteria(params url.Values) (*hvs.TrustHistory, error) {
+	defaultLog.Trace("controllers/report_controller:getTrustHistoryCriteria() Entering")
+	defer defaultLog.Trace("controllers/report_controller:getTrustHistoryCriteria() Leaving")
+
+	history := hvs.TrustHistory{
+		ToDate:   time.Now().UTC(),
+		Interval: hvs.TrustHistoryIntervalDay,
+	}
+
+	toDate := strings.TrimSpace(params.Get("toDate"))
+	if toDate != "" {
+		pTime, err := utils.ParseDateQueryParam(toDate)
+		if err != nil {
+			return nil, errors.New("Invalid toDate specified")
+		}
+		history.ToDate = pTime.UTC()
+	}
+
+	history.FromDate = history.ToDate.AddDate(0, 0, -consts.DefaultTrustHistoryDays)
+	fromDate := strings.TrimSpace(params.Get("fromDate"))
+	if fromDate != "" {
+		pTime, err := utils.ParseDateQueryParam(fromDate)
+		if err != nil {
+			return nil, errors.New("Invalid fromDate specified")
+		}
+		history.FromDate = pTime.UTC()
+	}
+
+	if history.ToDate.Before(history.FromDate) {
+		return nil, errors.New("toDate must not be before fromDate")
+	}
+	if history.ToDate.Sub(history.FromDate) > time.Duration(consts.MaxNumDaysSearchLimit*24)*time.Hour {
+		return nil, errors.Errorf("The period must not be longer than %d days", consts.MaxNumDaysSearchLimit)
+	}
+
+	interval := strings.TrimSpace(strings.ToLower(params.Get("interval")))
+	switch interval {
+	case "":
+	case hvs.TrustHistoryIntervalHour, hvs.TrustHistoryIntervalDay, hvs.TrustHistoryIntervalWeek:
+		history.Interval = interval
+	default:
+		return nil, errors.New("interval must be hour, day or week")
+	}
+	return &history, nil
+}
+
+// bucketTrustHistory sums up the hourly trust history by the interval
+func bucketTrustHistory(hourly []hvs.TrustHistoryBucket, interval string) []hvs.TrustHistoryBucket {
+	buckets := []hvs.TrustHistoryBucket{}
+	for _, hour := range hourly {
+		start := trustHistoryBucketStart(hour.Start, interval)
+		if len(buckets) == 0 || !buckets[len(buckets)-1].Start.Equal(start) {
+			buckets = append(buckets, hvs.TrustHistoryBucket{Start: start, Trusted: true})
+		}
+		bucket := &buckets[len(buckets)-1]
+		bucket.Trusted = bucket.Trusted && hour.Trusted
+		bucket.TrustedReports += hour.TrustedReports
+		bucket.UntrustedReports += hour.UntrustedReports
+		for fault, count := range hour.Faults {
+			if bucket.Faults == nil {
+				bucket.Faults = map[string]int64{}
+			}
+			bucket.Faults[fault] += count
+		}
+	}
+	return buckets
+}
+
+// trustHistoryBucketStart returns the start of the bucket of the interval the hour is in, the weeks start on Monday
+func trustHistoryBucketStart(hour time.Time, interval string) time.Time {
+	hour = hour.UTC()
+	switch interval {
+	case hvs.TrustHistoryIntervalHour:
+		return hour.Truncate(time.Hour)
+	case hvs.TrustHistoryIntervalWeek:
+		day := time.Date(hour.Year(), hour.Month(), hour.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(hour.Year(), hour.Month(), hour.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// reportSearchParams are the search params of the host status APIs and the flavor part trust of the reports
+var reportSearchParams = func() map[string]bool {
+	params := map[string]bool{"untrustedFlavorPart": true}
+	for param := range hostStatusSearchParams {
+		params[param] = true
+	}
+	return params
+}()
+
 // getReportFilterCriteria checks for set filter params in the Search request and returns a valid ReportFilterCriteria
 func getReportFilterCriteria(params url.Values) (*models.ReportFilterCriteria, error) {
 	defaultLog.Trace("controllers/report_controller:getReportFilterCriteria() Entering")
@@ -309,6 +474,16 @@ func getReportFilterCriteria(params url.Values) (*models.ReportFilterCriteria, e
 		rfc.HostStatus = hostState
 	}
 
+	// Untrusted flavor part
+	untrustedFlavorPart := strings.TrimSpace(params.Get("untrustedFlavorPart"))
+	if untrustedFlavorPart != "" {
+		var flavorPart common.FlavorPart
+		if err := flavorPart.Parse(untrustedFlavorPart); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for untrustedFlavorPart must be specified")
+		}
+		rfc.UntrustedFlavorPart = flavorPart.String()
+	}
+
 	// fromDate
 	fromDate := strings.TrimSpace(params.Get("fromDate"))
 	if fromDate != "" {
@@ -382,7 +557,7 @@ func validateReportCreateCriteria(re hvs.ReportCreateRequest) error {
 }
 
 func ConvertToReport(hvsReport *models.HVSReport) *hvs.Report {
-	trustInformation := buildTrustInformation(hvsReport.TrustReport)
+	trustInformation := hvs.NewTrustInformation(hvsReport.TrustReport, hvsReport.Expiration)
 
 	report := hvs.Report{
 		ID:               hvsReport.ID,
@@ -392,24 +567,19 @@ func ConvertToReport(hvsReport *models.HVSReport) *hvs.Report {
 		TrustReport:      hvsReport.TrustReport,
 		TrustInformation: *trustInformation,
 		HostInfo:         hvsReport.TrustReport.HostManifest.HostInfo,
+		StageTimings:     hvsReport.TrustReport.StageTimings,
+		Status:           hvsReport.TrustReport.Status,
 	}
 	return &report
 }
 
-func buildTrustInformation(trustReport hvs.TrustReport) *hvs.TrustInformation {
-
-	flavorParts := common.GetFlavorTypes()
-	flavorsTrustStatus := make(map[common.FlavorPart]hvs.FlavorTrustStatus)
-	tr := hvs.NewTrustReport(trustReport)
-	for _, flavorPart := range flavorParts {
-		if len(tr.GetResultsForMarker(flavorPart.String())) > 0 {
-			flavorsTrustStatus[flavorPart] = hvs.FlavorTrustStatus{
-				Trust:                tr.IsTrustedForMarker(flavorPart.String()),
-				RuleResultCollection: tr.GetResultsForMarker(flavorPart.String()),
-			}
-		}
+// convertToReport converts the HVSReport and adds the verified platform attributes of the host
+func (controller ReportController) convertToReport(hvsReport *models.HVSReport) *hvs.Report {
+	report := ConvertToReport(hvsReport)
+	if hwUuid, err := uuid.Parse(report.HostInfo.HardwareUUID); err == nil {
+		report.PlatformAttributes = GetPlatformAttributes(controller.PCStore, hwUuid)
 	}
-	return &hvs.TrustInformation{Overall: tr.IsTrusted(), FlavorTrust: flavorsTrustStatus}
+	return report
 }
 
 func getHostFilterCriteria(rsCriteria hvs.ReportCreateRequest) models.HostFilterCriteria {
@@ -425,3 +595,20 @@ func getHostFilterCriteria(rsCriteria hvs.ReportCreateRequest) models.HostFilter
 	}
 	return hsCriteria
 }
+
+// forTenant returns a copy of the controller whose report and host stores are restricted to the tenant of the user
+// making the request
+func (controller ReportController) forTenant(r *http.Request) (ReportController, int, error) {
+	defaultLog.Trace("controllers/report_controller:forTenant() Entering")
+	defer defaultLog.Trace("controllers/report_controller:forTenant() Leaving")
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/report_controller:forTenant() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return controller, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	controller.ReportStore = controller.ReportStore.ForTenant(tenantId)
+	controller.HostStore = controller.HostStore.ForTenant(tenantId)
+	return controller, http.StatusOK, nil
+}
@@ -7,18 +7,22 @@ package controllers_test
 import (
 	"encoding/json"
 	"encoding/xml"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/mocks"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 )
 
 var _ = Describe("ReportController", func() {
@@ -278,6 +282,59 @@ var _ = Describe("ReportController", func() {
 
 			})
 		})
+
+		Context("Get the trust of each flavor part of the Reports", func() {
+			It("Should return the trust, expiry and flavors of each flavor part", func() {
+				router.Handle("/reports", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports?hostId=ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var reportCollection hvs.ReportCollection
+				err = json.Unmarshal(w.Body.Bytes(), &reportCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reportCollection.Reports).To(HaveLen(1))
+				report := reportCollection.Reports[0]
+				platformTrust := report.TrustInformation.FlavorTrust[common.FlavorPartPlatform]
+				Expect(platformTrust.Trust).To(BeTrue())
+				Expect(platformTrust.ValidUntil).NotTo(BeNil())
+				Expect(platformTrust.ValidUntil.Equal(report.Expiration)).To(BeTrue())
+				Expect(platformTrust.FlavorIds).To(Equal([]uuid.UUID{uuid.MustParse("1108e0f4-96ee-4839-9bf7-a5a25457797f")}))
+				Expect(report.TrustInformation.FlavorTrust[common.FlavorPartSoftware].FlavorIds).To(HaveLen(2))
+			})
+		})
+
+		Context("Get the Reports in which a flavor part is not trusted", func() {
+			It("Should only return the Reports in which the flavor part is not trusted", func() {
+				router.Handle("/reports", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports?untrustedFlavorPart=SOFTWARE", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var reportCollection hvs.ReportCollection
+				err = json.Unmarshal(w.Body.Bytes(), &reportCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reportCollection.Reports).To(BeEmpty())
+			})
+		})
+
+		Context("Search Reports for an invalid flavor part", func() {
+			It("Should respond with bad request", func() {
+				router.Handle("/reports", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports?untrustedFlavorPart=FIRMWARE", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 
 	// Specs for HTTP Post to "/reports" for accept:samlassertion+xml
@@ -347,4 +404,80 @@ var _ = Describe("ReportController", func() {
 			})
 		})
 	})
+	// Specs for HTTP Get to "/hosts/{hId}/trust-history"
+	Describe("Get the trust history of a Host", func() {
+		trustHistory := func(query string) *httptest.ResponseRecorder {
+			router.Handle("/hosts/{hId}/trust-history", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.TrustHistory))).Methods("GET")
+			req, err := http.NewRequest("GET", "/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2/trust-history"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Get the trust history by day", func() {
+			It("Should return the reports of the Host summed up by day", func() {
+				w = trustHistory("?fromDate=2020-06-20&toDate=2020-06-30")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var history hvs.TrustHistory
+				err := json.Unmarshal(w.Body.Bytes(), &history)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history.Interval).To(Equal(hvs.TrustHistoryIntervalDay))
+				Expect(history.Buckets).To(HaveLen(1))
+				Expect(history.Buckets[0].Start.Format("2006-01-02")).To(Equal("2020-06-21"))
+				Expect(history.Buckets[0].Trusted).To(BeTrue())
+				Expect(history.Buckets[0].TrustedReports).To(Equal(int64(1)))
+			})
+		})
+		Context("Get the trust history by week", func() {
+			It("Should sum up the trusted and untrusted reports and their faults by week", func() {
+				created, err := time.Parse(time.RFC3339, "2020-06-20T10:00:00Z")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reportStore.Create(&models.HVSReport{
+					HostID:    uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"),
+					CreatedAt: created,
+					TrustReport: hvs.TrustReport{
+						Results: []hvs.RuleResult{{
+							Faults: []hvs.Fault{{Name: "PcrValueMismatch"}, {Name: "PcrValueMismatch"}, {Name: "Warning", Warning: true}},
+						}},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				w = trustHistory("?fromDate=2020-06-20&toDate=2020-06-30&interval=week")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var history hvs.TrustHistory
+				err = json.Unmarshal(w.Body.Bytes(), &history)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history.Buckets).To(HaveLen(1))
+				// the week starts on Monday
+				Expect(history.Buckets[0].Start.Format("2006-01-02")).To(Equal("2020-06-15"))
+				Expect(history.Buckets[0].Trusted).To(BeFalse())
+				Expect(history.Buckets[0].TrustedReports).To(Equal(int64(1)))
+				Expect(history.Buckets[0].UntrustedReports).To(Equal(int64(1)))
+				Expect(history.Buckets[0].Faults).To(Equal(map[string]int64{"PcrValueMismatch": 2}))
+			})
+		})
+		Context("Get the trust history of an unknown Host", func() {
+			It("Should return not found", func() {
+				router.Handle("/hosts/{hId}/trust-history", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.TrustHistory))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts/ee37c370-7ece-4250-a677-6ee12adce8e2/trust-history", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Get the trust history with invalid parameters", func() {
+			It("Should return bad request", func() {
+				Expect(trustHistory("?interval=month").Code).To(Equal(http.StatusBadRequest))
+				Expect(trustHistory("?fromDate=2020-06-30&toDate=2020-06-20").Code).To(Equal(http.StatusBadRequest))
+				Expect(trustHistory("?fromDate=2019-01-01&toDate=2020-06-20").Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
 })
@@ -0,0 +1,24 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+type RuleDefinitionController struct {
+}
+
+// Search returns the catalog of verifier rules along with the flavor parts they are applied for
+// and the faults they may raise
+func (controller RuleDefinitionController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/rule_definition_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/rule_definition_controller:Search() Leaving")
+
+	return hvs.RuleDefinitionCollection{RuleDefinitions: verifier.GetRuleDefinitions()}, http.StatusOK, nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RuleDefinitionController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var ruleDefinitionController *controllers.RuleDefinitionController
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		ruleDefinitionController = &controllers.RuleDefinitionController{}
+	})
+
+	// Specs for HTTP Get to "/rule-definitions"
+	Describe("Search RuleDefinitions", func() {
+		Context("Get the rule catalog", func() {
+			It("Should return all verifier rule definitions", func() {
+				router.Handle("/rule-definitions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(ruleDefinitionController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/rule-definitions", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var ruleDefinitions hvs.RuleDefinitionCollection
+				err = json.Unmarshal(w.Body.Bytes(), &ruleDefinitions)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ruleDefinitions.RuleDefinitions).To(Equal(verifier.GetRuleDefinitions()))
+				for _, ruleDefinition := range ruleDefinitions.RuleDefinitions {
+					Expect(ruleDefinition.Name).NotTo(BeEmpty())
+					Expect(ruleDefinition.FlavorParts).NotTo(BeEmpty())
+				}
+			})
+		})
+	})
+})
@@ -5,6 +5,7 @@
 package controllers
 
 import (
+	"bytes"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
@@ -14,6 +15,7 @@ import (
 	consts "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
 	asset_tag "github.com/intel-secl/intel-secl/v3/pkg/lib/asset-tag"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
@@ -30,6 +32,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -180,7 +183,7 @@ func (controller TagCertificateController) Search(w http.ResponseWriter, r *http
 	defer defaultLog.Trace("controllers/tagcertificate_controller:Search() Leaving")
 
 	var tagCertSearchParams = map[string]bool{"id": true, "hardwareUuid": true, "subjectContains": true, "subjectEqualTo": true,
-		"issuerContains": true, "issuerEqualTo": true, "validOn": true, "validBefore": true, "validAfter": true}
+		"issuerContains": true, "issuerEqualTo": true, "validOn": true, "validBefore": true, "validAfter": true, "revokedEqualTo": true}
 
 	if err := utils.ValidateQueryParams(r.URL.Query(), tagCertSearchParams); err != nil {
 		secLog.Errorf("controllers/tagcertificate_controller:Search() %s", err.Error())
@@ -266,6 +269,9 @@ func validateTagCertCreateCriteria(tcCreateCriteria models.TagCertificateCreateC
 		if err := validation.ValidateTextString(tagAttribute.Value); err != nil {
 			return errors.New("Valid contents for Value must be specified")
 		}
+		if tagAttribute.NotBefore != nil && tagAttribute.NotAfter != nil && !tagAttribute.NotBefore.Before(*tagAttribute.NotAfter) {
+			return errors.New("The activation window of tag " + tagAttribute.Key + " must end after it starts")
+		}
 	}
 
 	return nil
@@ -357,6 +363,15 @@ func getTCFilterCriteria(params url.Values) (*models.TagCertificateFilterCriteri
 		tagCertFc.HardwareUUID = hwUUID
 	}
 
+	// revokedEqualTo
+	if param := strings.TrimSpace(params.Get("revokedEqualTo")); param != "" {
+		revoked, err := strconv.ParseBool(param)
+		if err != nil {
+			return nil, errors.New("Valid boolean value for revokedEqualTo must be specified")
+		}
+		tagCertFc.RevokedEqualTo = &revoked
+	}
+
 	return &tagCertFc, nil
 }
 
@@ -409,6 +424,11 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	}
 	tc.SetAssetTagDigest()
 
+	if tc.Revoked {
+		secLog.WithField("Certid", dtcReq.CertID).Errorf("controllers/tagcertificate_controller:Deploy() %s : Certificate with Subject %s is revoked", commLogMsg.InvalidInputBadParam, tc.Subject)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Tag Certificate Deploy failure: Tag Certificate is revoked"}
+	}
+
 	// Ascertain Validity of Tag Certificate
 	log.Debug("controllers/tagcertificate_controller:Deploy() Got tagCertificate with ID {}. Checking validity.", dtcReq.CertID)
 	// verify certificate validity
@@ -452,7 +472,7 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	}
 
 	// DeployAssetTag
-	err = asset_tag.NewAssetTag().DeployAssetTag(hc, tc.TagCertDigest, targetHost.HardwareUuid.String())
+	err = asset_tag.NewAssetTag().DeployAssetTag(r.Context(), hc, tc.TagCertDigest, targetHost.HardwareUuid.String())
 	if err != nil {
 		defaultLog.WithError(err).WithField("Certid", dtcReq.CertID).Error("controllers/tagcertificate_controller:Deploy() Failed "+
 			"to deploy Asset Tag on Host %s", targetHost.HardwareUuid)
@@ -460,7 +480,7 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	}
 
 	// get Host Manifest
-	hmanifest, err := hc.GetHostManifest(nil)
+	hmanifest, err := hc.GetHostManifest(r.Context(), nil)
 	if err != nil {
 		defaultLog.WithField("id", dtcReq.CertID).Error("controllers/tagcertificate_controller:Deploy() Failed "+
 			"to get the HostManifest from Host %s", targetHost.HardwareUuid.String())
@@ -512,7 +532,7 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 		defaultLog.WithError(err).WithField("Certid", dtcReq.CertID).WithField("flavorID", sf.Flavor.Meta.ID).
 			Errorf("controllers/tagcertificate_controller:Deploy() %s : Failed to link SignedFlavor to Host "+
 				"Unique FlavorGroup", commLogMsg.AppRuntimeErr)
-		if strings.Contains(err.Error(), "duplicate key") {
+		if postgres.IsDuplicateKeyError(err) {
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with same id/label already exists"}
 		}
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error during Tag Certificate Deploy"}
@@ -523,3 +543,107 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	secLog.WithField("Certid", dtcReq.CertID).WithField("HardwareUUID", targetHost.HardwareUuid).Infof("%s: TagCertificate deployed by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
 	return sf, http.StatusOK, nil
 }
+
+// Revoke marks the specified asset tag certificate as revoked so that it can no longer be deployed, and deletes the
+// ASSET_TAG flavors created from it when it was deployed. The hosts linked to the deleted flavors are queued for
+// flavor verification.
+func (controller TagCertificateController) Revoke(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tagcertificate_controller:Revoke() Entering")
+	defer defaultLog.Trace("controllers/tagcertificate_controller:Revoke() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/tagcertificate_controller:Revoke() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	var rtcReq models.TagCertificateRevokeCriteria
+	if err := dec.Decode(&rtcReq); err != nil {
+		secLog.WithError(err).Errorf("controllers/tagcertificate_controller:Revoke() %s : Failed to decode request body as TagCertificateRevokeCriteria", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if rtcReq.CertID == uuid.Nil {
+		secLog.Errorf("controllers/tagcertificate_controller:Revoke() %s : Invalid UUID format of the identifier provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid UUID format of the Tag Certificate identifier provided"}
+	}
+
+	tc, err := controller.Store.Retrieve(rtcReq.CertID)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", rtcReq.CertID).Info(
+				"controllers/tagcertificate_controller:Revoke() TagCertificate with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "TagCertificate with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", rtcReq.CertID).Error(
+			"controllers/tagcertificate_controller:Revoke() Error retrieving TagCertificate")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to revoke TagCertificate"}
+	}
+
+	if !tc.Revoked {
+		if err := controller.Store.Revoke(tc.ID); err != nil {
+			defaultLog.WithError(err).WithField("id", tc.ID).Error(
+				"controllers/tagcertificate_controller:Revoke() Failed to revoke TagCertificate")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to revoke TagCertificate"}
+		}
+		tc.Revoked = true
+	}
+
+	// delete the asset tag flavors of the tag certificate, they are not trusted anymore
+	hostIdsForQueue, err := controller.deleteAssetTagFlavors(tc)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", tc.ID).Error(
+			"controllers/tagcertificate_controller:Revoke() Failed to delete the Asset Tag flavors of the TagCertificate")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete the Asset Tag flavors of the revoked TagCertificate"}
+	}
+	if len(hostIdsForQueue) > 0 {
+		if err := controller.FlavorController.HTManager.VerifyHostsAsync(hostIdsForQueue, false, false); err != nil {
+			defaultLog.WithError(err).WithField("id", tc.ID).Error(
+				"controllers/tagcertificate_controller:Revoke() Host to Flavor Verify Queue addition failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+				"associated with the revoked TagCertificate"}
+		}
+	}
+	tc.SetAssetTagDigest()
+
+	secLog.WithField("Certid", tc.ID).WithField("HardwareUUID", tc.HardwareUUID).Infof("%s: TagCertificate revoked by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return tc, http.StatusOK, nil
+}
+
+// deleteAssetTagFlavors deletes the ASSET_TAG flavors holding the tag certificate and returns the hosts they were linked to
+func (controller TagCertificateController) deleteAssetTagFlavors(tc *hvs.TagCertificate) ([]uuid.UUID, error) {
+	signedFlavors, err := controller.FlavorController.FStore.Search(&models.FlavorVerificationFC{
+		FlavorFC: models.FlavorFilterCriteria{
+			Key:   "hardware_uuid",
+			Value: tc.HardwareUUID.String(),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error searching the flavors of the host")
+	}
+
+	var hostIds []uuid.UUID
+	for i := range signedFlavors {
+		flavor := signedFlavors[i].Flavor
+		if flavor.Meta.Description.FlavorPart != fc.FlavorPartAssetTag.String() || flavor.External == nil ||
+			!bytes.Equal(flavor.External.AssetTag.TagCertificate.Encoded, tc.Certificate) {
+			continue
+		}
+		flavorHostIds, err := getHostsAssociatedWithFlavor(controller.HostStore, controller.FlavorController.FGStore, &signedFlavors[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := controller.FlavorController.FStore.Delete(flavor.Meta.ID); err != nil {
+			return nil, errors.Wrapf(err, "Error deleting the Asset Tag flavor %s", flavor.Meta.ID)
+		}
+		defaultLog.WithField("flavorID", flavor.Meta.ID).Debugf("controllers/tagcertificate_controller:deleteAssetTagFlavors() Deleted the Asset Tag flavor of TagCertificate %s", tc.ID)
+		hostIds = append(hostIds, flavorHostIds...)
+	}
+	return hostIds, nil
+}
@@ -790,6 +790,70 @@ var _ = Describe("TagCertificateController", func() {
 			})
 		})
 	})
+
+	//-------TagCertificate REVOKE Tests---------------------
+
+	// Specs for HTTP POST to "/rpc/revoke-tag-certificate"
+	Describe("Revoke TagCertificate", func() {
+		Context("Revoke a valid TagCertificate", func() {
+			It("Should revoke the TagCertificate and return a 200 response code", func() {
+				router.Handle(hvsRoutes.TagCertificateRevokeEndpointPath, hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(tagCertController.Revoke))).Methods("POST")
+				revokeTcReq := `{ "certificate_id" : "cf197a51-8362-465f-9ec1-d88ad0023a27" }`
+				req, err := http.NewRequest(
+					"POST",
+					hvsRoutes.TagCertificateRevokeEndpointPath,
+					strings.NewReader(revokeTcReq),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var revokedTC hvs.TagCertificate
+				err = json.Unmarshal(w.Body.Bytes(), &revokedTC)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(revokedTC.Revoked).To(BeTrue())
+			})
+		})
+
+		Context("Revoke a non-existent TagCertificate", func() {
+			It("Should fail to revoke the TagCertificate and return a 404 response code", func() {
+				router.Handle(hvsRoutes.TagCertificateRevokeEndpointPath, hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(tagCertController.Revoke))).Methods("POST")
+				revokeTcReq := `{ "certificate_id" : "c00135a8-f5e9-4860-ae6c-4acce525d340" }`
+				req, err := http.NewRequest(
+					"POST",
+					hvsRoutes.TagCertificateRevokeEndpointPath,
+					strings.NewReader(revokeTcReq),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("Revoke a TagCertificate with an invalid identifier", func() {
+			It("Should fail to revoke the TagCertificate and return a 400 response code", func() {
+				router.Handle(hvsRoutes.TagCertificateRevokeEndpointPath, hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(tagCertController.Revoke))).Methods("POST")
+				revokeTcReq := `{ "certificate_id" : "73755fda-c910-46be-821f-xyxyz" }`
+				req, err := http.NewRequest(
+					"POST",
+					hvsRoutes.TagCertificateRevokeEndpointPath,
+					strings.NewReader(revokeTcReq),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
 })
 
 func TestNewTagCertificateController(t *testing.T) {
@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	consts "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// TenantUsageController reports the usage of HVS per tenant. The users of a tenant only see the usage of their
+// tenant, the users that do not belong to a tenant see the usage of all of them.
+type TenantUsageController struct {
+	Store domain.TenantUsageStore
+	Meter domain.UsageMeter
+}
+
+var tenantUsageSearchParams = map[string]bool{"tenantId": true, "fromDate": true, "toDate": true}
+
+var tenantIdRegex = regexp.MustCompile("^" + consts.TenantIdPattern + "$")
+
+// Search returns the usage of each tenant summed up over the period of the filter criteria
+func (controller TenantUsageController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tenant_usage_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/tenant_usage_controller:Search() Leaving")
+
+	filter, status, err := controller.getFilterCriteria(r)
+	if err != nil {
+		return nil, status, err
+	}
+	usage, status, err := controller.search(filter)
+	if err != nil {
+		return nil, status, err
+	}
+
+	hostCounts, err := controller.Store.HostCounts()
+	if err != nil {
+		secLog.WithError(err).Error("controllers/tenant_usage_controller:Search() Host count failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search tenant usage"}
+	}
+
+	report := hvs.TenantUsageReport{Tenants: []hvs.TenantUsageSummary{}}
+	if !filter.FromDate.IsZero() {
+		report.FromDate = &filter.FromDate
+	}
+	if !filter.ToDate.IsZero() {
+		report.ToDate = &filter.ToDate
+	}
+	report.Tenants = summarizeTenantUsage(usage, hostCounts, filter.TenantId)
+
+	secLog.Infof("%s: Return tenant-usage query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return report, http.StatusOK, nil
+}
+
+// Export returns the daily usage records matching the filter criteria as CSV, for the billing systems
+func (controller TenantUsageController) Export(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tenant_usage_controller:Export() Entering")
+	defer defaultLog.Trace("controllers/tenant_usage_controller:Export() Leaving")
+
+	filter, status, err := controller.getFilterCriteria(r)
+	if err != nil {
+		return nil, status, err
+	}
+	usage, status, err := controller.search(filter)
+	if err != nil {
+		return nil, status, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	records := [][]string{{"day", "tenant_id", "identity", "api_calls", "report_generations"}}
+	for _, u := range usage {
+		records = append(records, []string{u.Day.Format("2006-01-02"), u.TenantId, u.Identity,
+			strconv.FormatInt(u.ApiCalls, 10), strconv.FormatInt(u.ReportGenerations, 10)})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		defaultLog.WithError(err).Error("controllers/tenant_usage_controller:Export() Error writing CSV")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to export tenant usage"}
+	}
+
+	secLog.Infof("%s: Return tenant-usage export to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	w.Header().Set("Content-Type", constants.HTTPMediaTypeCsv)
+	return buf.String(), http.StatusOK, nil
+}
+
+// search returns the usage matching the filter criteria including the usage counted since the last flush of the
+// meter
+func (controller TenantUsageController) search(filter *models.TenantUsageFilterCriteria) ([]hvs.TenantUsage, int, error) {
+	if controller.Meter != nil {
+		if err := controller.Meter.Flush(); err != nil {
+			// the usage persisted so far is still reported
+			defaultLog.WithError(err).Warn("controllers/tenant_usage_controller:search() Error flushing tenant usage")
+		}
+	}
+
+	usage, err := controller.Store.Search(filter)
+	if err != nil {
+		secLog.WithError(err).Error("controllers/tenant_usage_controller:search() Tenant usage search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search tenant usage"}
+	}
+	return usage, http.StatusOK, nil
+}
+
+// getFilterCriteria returns the filter criteria of the request, restricted to the tenant of the user making it
+func (controller TenantUsageController) getFilterCriteria(r *http.Request) (*models.TenantUsageFilterCriteria, int, error) {
+	defaultLog.Trace("controllers/tenant_usage_controller:getFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/tenant_usage_controller:getFilterCriteria() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), tenantUsageSearchParams); err != nil {
+		secLog.Errorf("controllers/tenant_usage_controller:getFilterCriteria() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getTenantUsageFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/tenant_usage_controller:getFilterCriteria() %s Invalid input provided in filter criteria", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/tenant_usage_controller:getFilterCriteria() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+	if tenantId != "" {
+		if filter.TenantId != nil && *filter.TenantId != tenantId {
+			secLog.Errorf("controllers/tenant_usage_controller:getFilterCriteria() %s : Usage of tenant %s requested by tenant %s", commLogMsg.UnauthorizedAccess, *filter.TenantId, tenantId)
+			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Usage of other tenants cannot be requested"}
+		}
+		filter.TenantId = &tenantId
+	}
+	return filter, http.StatusOK, nil
+}
+
+func getTenantUsageFilterCriteria(params url.Values) (*models.TenantUsageFilterCriteria, error) {
+	defaultLog.Trace("controllers/tenant_usage_controller:getTenantUsageFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/tenant_usage_controller:getTenantUsageFilterCriteria() Leaving")
+
+	tufc := models.TenantUsageFilterCriteria{}
+
+	if _, ok := params["tenantId"]; ok {
+		// an empty tenantId selects the usage of the default namespace
+		tenantId := strings.TrimSpace(params.Get("tenantId"))
+		if tenantId != "" && !tenantIdRegex.MatchString(tenantId) {
+			return nil, errors.New("Valid contents for tenantId must be specified")
+		}
+		tufc.TenantId = &tenantId
+	}
+
+	fromDate := strings.TrimSpace(params.Get("fromDate"))
+	if fromDate != "" {
+		pTime, err := utils.ParseDateQueryParam(fromDate)
+		if err != nil {
+			return nil, errors.New("Invalid fromDate specified")
+		}
+		tufc.FromDate = pTime
+	}
+
+	toDate := strings.TrimSpace(params.Get("toDate"))
+	if toDate != "" {
+		pTime, err := utils.ParseDateQueryParam(toDate)
+		if err != nil {
+			return nil, errors.New("Invalid toDate specified")
+		}
+		tufc.ToDate = pTime
+	}
+
+	if !tufc.FromDate.IsZero() && !tufc.ToDate.IsZero() && tufc.ToDate.Before(tufc.FromDate) {
+		return nil, errors.New("toDate must not be before fromDate")
+	}
+	return &tufc, nil
+}
+
+// summarizeTenantUsage sums up the usage records of each tenant, the tenants that have hosts but no usage over the
+// period are reported too
+func summarizeTenantUsage(usage []hvs.TenantUsage, hostCounts map[string]int, tenantId *string) []hvs.TenantUsageSummary {
+	summaries := map[string]*hvs.TenantUsageSummary{}
+	identities := map[string]map[string]int64{}
+	summary := func(tenantId string) *hvs.TenantUsageSummary {
+		s, ok := summaries[tenantId]
+		if !ok {
+			s = &hvs.TenantUsageSummary{TenantId: tenantId, HostCount: hostCounts[tenantId]}
+			summaries[tenantId] = s
+			identities[tenantId] = map[string]int64{}
+		}
+		return s
+	}
+
+	for _, u := range usage {
+		s := summary(u.TenantId)
+		s.ApiCalls += u.ApiCalls
+		s.ReportGenerations += u.ReportGenerations
+		if u.Identity != "" && u.ApiCalls > 0 {
+			identities[u.TenantId][u.Identity] += u.ApiCalls
+		}
+	}
+	for id := range hostCounts {
+		if tenantId == nil || *tenantId == id {
+			summary(id)
+		}
+	}
+
+	result := make([]hvs.TenantUsageSummary, 0, len(summaries))
+	for id, s := range summaries {
+		for identity, apiCalls := range identities[id] {
+			s.Identities = append(s.Identities, hvs.IdentityUsage{Identity: identity, ApiCalls: apiCalls})
+		}
+		sort.Slice(s.Identities, func(i, j int) bool {
+			return s.Identities[i].Identity < s.Identities[j].Identity
+		})
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TenantId < result[j].TenantId
+	})
+	return result
+}
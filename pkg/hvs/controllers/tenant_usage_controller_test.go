@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/usage"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TenantUsageController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var tenantUsageStore *mocks2.MockTenantUsageStore
+	var meter *usage.Meter
+	var tenantUsageController *controllers.TenantUsageController
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		tenantUsageStore = mocks2.NewFakeTenantUsageStore()
+		tenantUsageStore.Usage = []hvs.TenantUsage{
+			{TenantId: "tenant-a", Identity: "alice", Day: yesterday, ApiCalls: 10},
+			{TenantId: "tenant-a", Day: yesterday, ReportGenerations: 4},
+			{TenantId: "tenant-b", Identity: "bob", Day: yesterday, ApiCalls: 3},
+		}
+		tenantUsageStore.HostCount = map[string]int{"tenant-a": 2, "tenant-c": 1}
+		meter = usage.NewMeter(tenantUsageStore, mocks2.NewMockHostStore(), time.Minute)
+		tenantUsageController = &controllers.TenantUsageController{Store: tenantUsageStore, Meter: meter}
+		router.Handle("/tenant-usage", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(tenantUsageController.Export))).
+			Methods("GET").Headers("Accept", consts.HTTPMediaTypeCsv)
+		router.Handle("/tenant-usage", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(tenantUsageController.Search))).Methods("GET")
+	})
+
+	getTenantUsage := func(query, accept string, roles ...aas.RoleInfo) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/tenant-usage"+query, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", accept)
+		if len(roles) > 0 {
+			req = comctx.SetUserRoles(req, roles)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Specs for HTTP Get to "/tenant-usage"
+	Describe("Search TenantUsage", func() {
+		Context("Search the usage of all the tenants", func() {
+			It("Should return the usage of each tenant including the usage not yet flushed", func() {
+				meter.RecordAPICall("tenant-b", "bob")
+				w = getTenantUsage("", consts.HTTPMediaTypeJson)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var report hvs.TenantUsageReport
+				err := json.Unmarshal(w.Body.Bytes(), &report)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Tenants).To(HaveLen(3))
+				Expect(report.Tenants[0]).To(Equal(hvs.TenantUsageSummary{TenantId: "tenant-a", ApiCalls: 10,
+					ReportGenerations: 4, HostCount: 2, Identities: []hvs.IdentityUsage{{Identity: "alice", ApiCalls: 10}}}))
+				Expect(report.Tenants[1].ApiCalls).To(Equal(int64(4)))
+				Expect(report.Tenants[2].TenantId).To(Equal("tenant-c"))
+				Expect(report.Tenants[2].HostCount).To(Equal(1))
+			})
+		})
+		Context("Search the usage as a user of a tenant", func() {
+			It("Should only return the usage of the tenant of the user", func() {
+				w = getTenantUsage("", consts.HTTPMediaTypeJson, aas.RoleInfo{Service: "HVS", Name: "UsageRetriever", Context: "tenant=tenant-b"})
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var report hvs.TenantUsageReport
+				err := json.Unmarshal(w.Body.Bytes(), &report)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Tenants).To(HaveLen(1))
+				Expect(report.Tenants[0].TenantId).To(Equal("tenant-b"))
+			})
+			It("Should fail to return the usage of another tenant", func() {
+				w = getTenantUsage("?tenantId=tenant-a", consts.HTTPMediaTypeJson, aas.RoleInfo{Service: "HVS", Name: "UsageRetriever", Context: "tenant=tenant-b"})
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("Search the usage of a period", func() {
+			It("Should return the tenants without usage in the period with their host count", func() {
+				w = getTenantUsage("?fromDate="+time.Now().UTC().Format("2006-01-02"), consts.HTTPMediaTypeJson)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var report hvs.TenantUsageReport
+				err := json.Unmarshal(w.Body.Bytes(), &report)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Tenants).To(HaveLen(2))
+				Expect(report.Tenants[0].ApiCalls).To(BeZero())
+			})
+			It("Should fail when the period ends before it starts", func() {
+				w = getTenantUsage("?fromDate=2020-10-02&toDate=2020-10-01", consts.HTTPMediaTypeJson)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Export the usage as CSV", func() {
+			It("Should return the daily usage records", func() {
+				w = getTenantUsage("?tenantId=tenant-a", consts.HTTPMediaTypeCsv)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("Content-Type")).To(Equal(consts.HTTPMediaTypeCsv))
+
+				lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+				Expect(lines).To(HaveLen(3))
+				Expect(lines[0]).To(Equal("day,tenant_id,identity,api_calls,report_generations"))
+				Expect(lines[1]).To(Equal(yesterday.Format("2006-01-02") + ",tenant-a,alice,10,0"))
+			})
+		})
+	})
+})
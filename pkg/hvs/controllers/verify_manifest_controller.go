@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	dm "github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	fu "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// VerifyManifestController replays the verification of captured host manifests against stored or proposed flavors
+type VerifyManifestController struct {
+	FlavorStore domain.FlavorStore
+	CertStore   *dm.CertificatesStore
+}
+
+// VerifyManifest verifies the host manifest of the request against its flavors and returns the trust report, neither
+// the proposed flavors nor the report are persisted. The quotes of captured manifests are old, their age is not
+// verified.
+func (controller VerifyManifestController) VerifyManifest(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/verify_manifest_controller:VerifyManifest() Entering")
+	defer defaultLog.Trace("controllers/verify_manifest_controller:VerifyManifest() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : The request body is not provided",
+			commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqVerifyManifest hvs.VerifyManifestRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&reqVerifyManifest)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Failed to decode "+
+			"request body as verify manifest request", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateVerifyManifestRequest(&reqVerifyManifest); err != nil {
+		secLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Invalid verify "+
+			"manifest request provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	tenantId, err := utils.GetTenantId(r)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+	flavorStore := controller.FlavorStore.ForTenant(tenantId)
+
+	var signedFlavors []hvs.SignedFlavor
+	for _, flavorId := range reqVerifyManifest.FlavorIds {
+		signedFlavor, err := flavorStore.Retrieve(flavorId)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				defaultLog.WithError(err).WithField("id", flavorId).Info(
+					"controllers/verify_manifest_controller:VerifyManifest() Flavor with given ID does not exist")
+				return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with ID " + flavorId.String() + " does not exist"}
+			}
+			defaultLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Failed to "+
+				"retrieve flavor from store", commLogMsg.AppRuntimeErr)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve flavor from store"}
+		}
+		signedFlavors = append(signedFlavors, *signedFlavor)
+	}
+
+	if len(reqVerifyManifest.Flavors) > 0 {
+		// the proposed flavors are signed so that they are verified as the stored ones
+		key, _, err := (*controller.CertStore).GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+		flavorSignKey, ok := key.(*rsa.PrivateKey)
+		if err != nil || !ok {
+			defaultLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Flavor "+
+				"signing key not found in the certificate store", commLogMsg.AppRuntimeErr)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to sign the flavors"}
+		}
+		var platformFlavorUtil fu.PlatformFlavorUtil
+		for i := range reqVerifyManifest.Flavors {
+			flavor := &reqVerifyManifest.Flavors[i].Flavor
+			if flavor.Meta.ID == uuid.Nil {
+				flavor.Meta.ID = uuid.New()
+			}
+			signedFlavor, err := platformFlavorUtil.GetSignedFlavor(flavor, flavorSignKey)
+			if err != nil {
+				defaultLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Error "+
+					"signing the flavor", commLogMsg.AppRuntimeErr)
+				return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to sign the flavors"}
+			}
+			signedFlavors = append(signedFlavors, *signedFlavor)
+		}
+	}
+
+	verifierCerts := utils.GetVerifierCertificates(controller.CertStore)
+	if verifierCerts.FlavorSigningCertificate == nil {
+		defaultLog.Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Flavor signing certificate "+
+			"not found in the certificate store", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to verify the host manifest"}
+	}
+
+	trustReport, err := verifier.VerifyEvidence(verifierCerts, &reqVerifyManifest.HostManifest, signedFlavors, false)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("controllers/verify_manifest_controller:VerifyManifest() %s : Error "+
+			"verifying the host manifest", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to verify the host manifest: " + err.Error()}
+	}
+
+	secLog.Infof("%s: Host manifest verified against %d flavors by: %s", commLogMsg.AuthorizedAccess,
+		len(signedFlavors), r.RemoteAddr)
+	return trustReport, http.StatusOK, nil
+}
+
+func validateVerifyManifestRequest(req *hvs.VerifyManifestRequest) error {
+	defaultLog.Trace("controllers/verify_manifest_controller:validateVerifyManifestRequest() Entering")
+	defer defaultLog.Trace("controllers/verify_manifest_controller:validateVerifyManifestRequest() Leaving")
+
+	if len(req.FlavorIds) == 0 && len(req.Flavors) == 0 {
+		return errors.New("At least one flavor ID or flavor must be provided")
+	}
+	for _, flavorId := range req.FlavorIds {
+		if flavorId == uuid.Nil {
+			return errors.New("Invalid flavor ID provided")
+		}
+	}
+	for i := range req.Flavors {
+		if err := validateFlavorMetaContent(&req.Flavors[i].Flavor.Meta); err != nil {
+			return errors.Wrap(err, "Invalid flavor content")
+		}
+	}
+	if req.HostManifest.HostInfo.HardwareUUID == "" {
+		return errors.New("The host manifest must have the host info of the host")
+	}
+	return nil
+}
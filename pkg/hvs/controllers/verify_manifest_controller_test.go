@@ -0,0 +1,131 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VerifyManifestController", func() {
+	const testDataDir = "../../lib/verifier/test_data/intel20/"
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var verifyManifestController controllers.VerifyManifestController
+	var hostManifest types.HostManifest
+
+	loadCertificates := func(file string) *models.CertificateStore {
+		certs, err := crypt.GetSubjectCertsMapFromPemFile(testDataDir + file)
+		Expect(err).NotTo(HaveOccurred())
+		return &models.CertificateStore{Certificates: certs}
+	}
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		manifestJSON, err := ioutil.ReadFile(testDataDir + "host_manifest.json")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(manifestJSON, &hostManifest)).To(Succeed())
+
+		verifyManifestController = controllers.VerifyManifestController{
+			FlavorStore: mocks.NewFakeFlavorStoreWithAllFlavors(testDataDir + "signed_flavors.json"),
+			CertStore: &models.CertificatesStore{
+				models.CaCertTypesRootCa.String():      loadCertificates("cms-ca-cert.pem"),
+				models.CaCertTypesPrivacyCa.String():   loadCertificates("PrivacyCA.pem"),
+				models.CaCertTypesTagCa.String():       loadCertificates("tag-cacerts.pem"),
+				models.CertTypesFlavorSigning.String(): loadCertificates("flavor-signer.crt.pem"),
+			},
+		}
+		router.Handle("/rpc/verify-manifest", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(
+			verifyManifestController.VerifyManifest))).Methods("POST")
+	})
+
+	verifyManifest := func(request hvs.VerifyManifestRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(request)
+		Expect(err).NotTo(HaveOccurred())
+		req, err := http.NewRequest("POST", "/rpc/verify-manifest", strings.NewReader(string(body)))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Describe("Verify a host manifest", func() {
+		Context("Against stored flavors", func() {
+			It("Should return the trust report of the host manifest", func() {
+				softwareFlavor, otherSoftwareFlavor := "339a7ac6-b8be-4356-ab34-be6e3bdfa1ed", "7b9e937c-aaa7-4de1-a175-d2634b44e60a"
+				w = verifyManifest(hvs.VerifyManifestRequest{
+					HostManifest: hostManifest,
+					FlavorIds:    []uuid.UUID{uuid.MustParse(softwareFlavor), uuid.MustParse(otherSoftwareFlavor)},
+				})
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var trustReport hvs.TrustReport
+				Expect(json.Unmarshal(w.Body.Bytes(), &trustReport)).To(Succeed())
+				Expect(trustReport.Results).NotTo(BeEmpty())
+				for _, result := range trustReport.Results {
+					Expect(result.FlavorId.String()).To(BeElementOf(softwareFlavor, otherSoftwareFlavor))
+				}
+			})
+		})
+		Context("Against a flavor that does not exist", func() {
+			It("Should return bad request", func() {
+				w = verifyManifest(hvs.VerifyManifestRequest{
+					HostManifest: hostManifest,
+					FlavorIds:    []uuid.UUID{uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")},
+				})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Without any flavor", func() {
+			It("Should return bad request", func() {
+				w = verifyManifest(hvs.VerifyManifestRequest{HostManifest: hostManifest})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Against inline flavors without a flavor signing key", func() {
+			It("Should return internal server error", func() {
+				var signedFlavors []hvs.SignedFlavor
+				flavorsJSON, err := ioutil.ReadFile(testDataDir + "signed_flavors.json")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(flavorsJSON, &signedFlavors)).To(Succeed())
+
+				w = verifyManifest(hvs.VerifyManifestRequest{
+					HostManifest: hostManifest,
+					Flavors:      []hvs.Flavors{{Flavor: signedFlavors[0].Flavor}},
+				})
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+		Context("With an invalid Content-Type", func() {
+			It("Should return unsupported media type", func() {
+				req, err := http.NewRequest("POST", "/rpc/verify-manifest", strings.NewReader("{}"))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeXml)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+	})
+})
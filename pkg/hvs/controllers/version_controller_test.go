@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
 	hvsRoutes "github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"net/http"
@@ -38,6 +39,18 @@ var _ = Describe("VersionController", func() {
 				version = string(w.Body.Bytes())
 				Expect(version).NotTo(Equal(""))
 			})
+			It("Should report the FIPS mode", func() {
+				crypt.SetFipsMode(true)
+				defer crypt.SetFipsMode(false)
+
+				router.Handle("/version", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(versionController.GetVersion))).Methods("GET")
+				req, err := http.NewRequest("GET", "/version", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Body.String()).To(ContainSubstring("FIPS Mode: enabled"))
+			})
 		})
 	})
 
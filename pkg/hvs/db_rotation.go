@@ -128,6 +128,11 @@ func (a *App) configDBRotation() error {
 	if c == nil {
 		return errors.New("Failed to load configuration file")
 	}
+	// the rotation is implemented with a plpgsql trigger
+	if c.DB.Vendor == constants.DBTypeMySQL {
+		defaultLog.Info("Audit log rotation is only supported with postgres, skipping its configuration")
+		return nil
+	}
 	dataStore, err := postgres.NewDataStore(postgres.NewDatabaseConfig(constants.DBTypePostgres, &c.DB))
 	if err != nil {
 		return errors.Wrap(err, "Failed to connect database")
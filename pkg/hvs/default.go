@@ -9,6 +9,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hrrs"
 	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
+	hvsModel "github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/spf13/viper"
 	"os"
 )
@@ -73,7 +74,10 @@ func init() {
 	viper.SetDefault("server-read-header-timeout", constants.DefaultReadHeaderTimeout)
 	viper.SetDefault("server-write-timeout", constants.DefaultWriteTimeout)
 	viper.SetDefault("server-idle-timeout", constants.DefaultIdleTimeout)
+	viper.SetDefault("server-drain-timeout", constants.DefaultDrainTimeout)
+	viper.SetDefault("server-body-read-timeout", constants.DefaultBodyReadTimeout)
 	viper.SetDefault("server-max-header-bytes", constants.DefaultMaxHeaderBytes)
+	viper.SetDefault("server-max-body-bytes", constants.DefaultMaxBodyBytes)
 
 	// set default for database ssl certificate
 	viper.SetDefault("db-vendor", "postgres")
@@ -90,10 +94,23 @@ func init() {
 	viper.SetDefault(constants.FvsNumberOfDataFetchers, constants.DefaultFvsNumberOfDataFetchers)
 	viper.SetDefault(constants.FvsSkipFlavorSignatureVerification, constants.DefaultSkipFlavorSignatureVerification)
 	viper.SetDefault(constants.FvsHostTrustCacheThreshold, constants.DefaultHostTrustCacheThreshold)
+	viper.SetDefault(constants.FvsAttestationLatencyBudget, constants.DefaultAttestationLatencyBudget)
+	viper.SetDefault(constants.FvsAsyncQuoteTimeout, constants.DefaultAsyncQuoteTimeout)
+	viper.SetDefault(constants.FvsClockSkewThreshold, constants.DefaultClockSkewThreshold)
+	viper.SetDefault(constants.FvsCrlCacheTime, constants.DefaultCrlCacheTime)
+	viper.SetDefault(constants.FvsQueueVisibilityTimeout, constants.DefaultQueueVisibilityTimeout)
+
+	viper.SetDefault(constants.HostConnectorCallTimeout, constants.DefaultHostConnectorCallTimeout)
+	viper.SetDefault(constants.HardwareUuidCollisionPolicy, hvsModel.HardwareUuidCollisionReject)
 
 	viper.SetDefault(constants.HrrsRefreshPeriod, hrrs.DefaultRefreshPeriod)
+	viper.SetDefault(constants.HrrsTrustHistoryRetention, hrrs.DefaultTrustHistoryRetention)
 
 	viper.SetDefault(constants.VcssRefreshPeriod, constants.DefaultVcssRefreshPeriod)
+
+	viper.SetDefault(constants.HprsProbePeriod, constants.DefaultHprsProbePeriod)
+
+	viper.SetDefault(constants.QuoteCallbackAuthMode, "jwt")
 }
 
 func defaultConfig() *config.Configuration {
@@ -128,6 +145,7 @@ func defaultConfig() *config.Configuration {
 			},
 			Issuer:          viper.GetString("saml-issuer-name"),
 			ValiditySeconds: viper.GetInt("saml-validity-seconds"),
+			KeyURI:          viper.GetString("saml-key-uri"),
 		},
 		FlavorSigning: commConfig.SigningCertConfig{
 			CertFile:   viper.GetString("flavor-signing-cert-file"),
@@ -161,17 +179,41 @@ func defaultConfig() *config.Configuration {
 			Level:        viper.GetString("log-level"),
 		},
 		HRRS: hrrs.HRRSConfig{
-			RefreshPeriod: viper.GetDuration(constants.HrrsRefreshPeriod),
+			RefreshPeriod:         viper.GetDuration(constants.HrrsRefreshPeriod),
+			TrustHistoryRetention: viper.GetDuration(constants.HrrsTrustHistoryRetention),
 		},
 		VCSS: config.VCSSConfig{
 			RefreshPeriod: viper.GetDuration(constants.VcssRefreshPeriod),
 		},
+		HPRS: config.HPRSConfig{
+			ProbePeriod: viper.GetDuration(constants.HprsProbePeriod),
+		},
 		FVS: config.FVSConfig{
 			NumberOfVerifiers:               viper.GetInt(constants.FvsNumberOfVerifiers),
 			NumberOfDataFetchers:            viper.GetInt(constants.FvsNumberOfDataFetchers),
 			SkipFlavorSignatureVerification: viper.GetBool(constants.FvsSkipFlavorSignatureVerification),
 			HostTrustCacheThreshold:         viper.GetInt(constants.FvsHostTrustCacheThreshold),
+			AttestationLatencyBudget:        viper.GetDuration(constants.FvsAttestationLatencyBudget),
+			AsyncQuoteCallbackURL:           viper.GetString(constants.FvsAsyncQuoteCallbackUrl),
+			AsyncQuoteTimeout:               viper.GetDuration(constants.FvsAsyncQuoteTimeout),
+			DecisionLogFile:                 viper.GetString(constants.FvsDecisionLogFile),
+			FlavorSignatureQuorum:           viper.GetInt(constants.FvsFlavorSignatureQuorum),
+			MaxQuoteAge:                     viper.GetDuration(constants.FvsMaxQuoteAge),
+			ClockSkewThreshold:              viper.GetDuration(constants.FvsClockSkewThreshold),
+			RevocationCheck:                 viper.GetBool(constants.FvsRevocationCheck),
+			CrlCacheTime:                    viper.GetDuration(constants.FvsCrlCacheTime),
+			OcspCheck:                       viper.GetBool(constants.FvsOcspCheck),
+			QueueVisibilityTimeout:          viper.GetDuration(constants.FvsQueueVisibilityTimeout),
+		},
+		HostConnector: config.HostConnectorConfig{
+			CallTimeout: viper.GetDuration(constants.HostConnectorCallTimeout),
+		},
+		HardwareUuidCollisionPolicy: viper.GetString(constants.HardwareUuidCollisionPolicy),
+		QuoteCallbackAuth: commConfig.RouteAuthConfig{
+			Mode:               viper.GetString(constants.QuoteCallbackAuthMode),
+			AllowedCommonNames: viper.GetStringSlice(constants.QuoteCallbackAuthCommonNames),
 		},
+		FipsMode: viper.GetBool("fips-mode"),
 	}
 }
 
@@ -192,6 +234,9 @@ func loadAlias() {
 		"server-read-header-timeout": "HVS_SERVER_READ_HEADER_TIMEOUT",
 		"server-write-timeout":       "HVS_SERVER_WRITE_TIMEOUT",
 		"server-idle-timeout":        "HVS_SERVER_IDLE_TIMEOUT",
+		"server-drain-timeout":       "HVS_SERVER_DRAIN_TIMEOUT",
+		"server-body-read-timeout":   "HVS_SERVER_BODY_READ_TIMEOUT",
+		"server-max-response-bytes":  "HVS_SERVER_MAX_RESPONSE_BYTES",
 		"server-max-header-bytes":    "HVS_SERVER_MAX_HEADER_BYTES",
 	}
 	for k, v := range alias {
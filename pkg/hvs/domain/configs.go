@@ -11,6 +11,8 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/saml"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"time"
 )
 
 type HostTrustVerifierConfig struct {
@@ -23,6 +25,10 @@ type HostTrustVerifierConfig struct {
 	SamlIssuerConfig                saml.IssuerConfiguration
 	SkipFlavorSignatureVerification bool
 	HostTrustCache                  *lru.Cache
+	LatencyRecorder                 AttestationLatencyRecorder
+	ReportHooks                     []ReportHook
+	// TrustScoreWeights configure the trust score of the reports, no score is computed when it is nil
+	TrustScoreWeights *hvs.TrustScoreWeights
 }
 
 type HostTrustMgrConfig struct {
@@ -32,6 +38,8 @@ type HostTrustMgrConfig struct {
 	HostFetcher       HostDataFetcher
 	Verifiers         int
 	HostTrustVerifier HostTrustVerifier
+	// VisibilityTimeout is the time after which the in progress queue entries are queued again, zero disables it
+	VisibilityTimeout time.Duration
 }
 
 type HostDataFetcherConfig struct {
@@ -50,6 +58,9 @@ type HostControllerConfig struct {
 	DataEncryptionKey     []byte
 	Username              string
 	Password              string
+	// HardwareUuidCollisionPolicy is applied when a host is registered with the hardware UUID of a registered host,
+	// the registration is rejected when it is not set
+	HardwareUuidCollisionPolicy string
 }
 
 type TagCertControllerConfig struct {
@@ -28,6 +28,9 @@ type (
 		RetrieveFlavor(uuid.UUID, uuid.UUID) (*hvs.FlavorgroupFlavorLink, error)
 		SearchHostsByFlavorGroup(fgID uuid.UUID) ([]uuid.UUID, error)
 		GetFlavorTypesInFlavorGroup(flvGrpId uuid.UUID) (map[cf.FlavorPart]bool, error)
+		// ForTenant returns a view of the store that creates, retrieves, searches and deletes only the
+		// flavorgroups of the tenant
+		ForTenant(tenantId string) FlavorGroupStore
 	}
 
 	HostStore interface {
@@ -53,6 +56,15 @@ type (
 		RemoveHostUniqueFlavors(hId uuid.UUID, fIds []uuid.UUID) error
 		RetrieveHostUniqueFlavors(hId uuid.UUID) ([]uuid.UUID, error)
 		RetrieveDistinctUniqueFlavorParts(hId uuid.UUID) ([]string, error)
+		// SetMaintenance puts the host in maintenance mode, replacing its maintenance if it is already in maintenance
+		SetMaintenance(hId uuid.UUID, maintenance *hvs.HostMaintenance) error
+		// EndMaintenance ends the maintenance of the host, false is returned when the host was not in maintenance
+		EndMaintenance(hId uuid.UUID) (bool, error)
+		// EndElapsedMaintenances ends the maintenances whose resume time has passed and returns the ids of their hosts
+		EndElapsedMaintenances(now time.Time) ([]uuid.UUID, error)
+		// ForTenant returns a view of the store that creates, retrieves, updates, searches and deletes only the
+		// hosts of the tenant
+		ForTenant(tenantId string) HostStore
 	}
 
 	HostCredentialStore interface {
@@ -69,6 +81,13 @@ type (
 		Retrieve(uuid.UUID) (*hvs.SignedFlavor, error)
 		Search(*models.FlavorVerificationFC) ([]hvs.SignedFlavor, error)
 		Delete(uuid.UUID) error
+		// AddSignature adds a co-signer's signature to the additional signatures of a flavor
+		AddSignature(uuid.UUID, string) (*hvs.SignedFlavor, error)
+		// Update replaces the content and the signatures of a flavor
+		Update(*hvs.SignedFlavor) (*hvs.SignedFlavor, error)
+		// ForTenant returns a view of the store that creates, retrieves, searches and deletes only the
+		// flavors of the tenant
+		ForTenant(tenantId string) FlavorStore
 	}
 
 	TpmEndorsementStore interface {
@@ -79,6 +98,38 @@ type (
 		Delete(uuid.UUID) error
 	}
 
+	PlatformCertificateStore interface {
+		Create(*hvs.PlatformCertificate) (*hvs.PlatformCertificate, error)
+		Retrieve(uuid.UUID) (*hvs.PlatformCertificate, error)
+		Update(*hvs.PlatformCertificate) (*hvs.PlatformCertificate, error)
+		Search(*models.PlatformCertificateFilterCriteria) (*hvs.PlatformCertificateCollection, error)
+		Delete(uuid.UUID) error
+	}
+
+	FlavorLearningStore interface {
+		Create(*hvs.FlavorLearning) (*hvs.FlavorLearning, error)
+		Retrieve(uuid.UUID) (*hvs.FlavorLearning, error)
+		Search(*models.FlavorLearningFilterCriteria) (*hvs.FlavorLearningCollection, error)
+		Delete(uuid.UUID) error
+	}
+
+	// FlavorPruneStore retires the superseded flavor versions selected by the flavor pruner and restores them
+	// when a prune is undone
+	FlavorPruneStore interface {
+		// SearchFlavorVersions returns the flavors of the flavor parts with their creation time and flavorgroups
+		SearchFlavorVersions([]cf.FlavorPart) ([]hvs.FlavorVersion, error)
+		// Create deletes the retired flavors of the prune and records them with the prune
+		Create(*hvs.FlavorPrune) (*hvs.FlavorPrune, error)
+		Retrieve(uuid.UUID) (*hvs.FlavorPrune, error)
+		Search() (*hvs.FlavorPruneCollection, error)
+		// Undo restores the retired flavors of the prune, with their creation time, in the flavorgroups that
+		// still exist
+		Undo(uuid.UUID) (*hvs.FlavorPrune, error)
+		// ForTenant returns a view of the store that searches the flavors and creates, retrieves, searches and
+		// undoes the prunes of the tenant only
+		ForTenant(tenantId string) FlavorPruneStore
+	}
+
 	// HostStatusStore specifies the DB operations that must be implemented for the Host Status API
 	HostStatusStore interface {
 		Create(*hvs.HostStatus) (*hvs.HostStatus, error)
@@ -87,6 +138,10 @@ type (
 		Delete(uuid.UUID) error
 		Persist(*hvs.HostStatus) error
 		FindHostIdsByKeyValue(key, value string) ([]uuid.UUID, error)
+		// UpdateLifecycleState moves the host to the lifecycle state and records the transition, it fails if the
+		// transition is not allowed from the current state of the host
+		UpdateLifecycleState(hostId uuid.UUID, state hvs.HostLifecycleState, reason string) error
+		SearchLifecycleTransitions(hostId uuid.UUID, limit int) ([]hvs.HostLifecycleTransition, error)
 	}
 
 	QueueStore interface {
@@ -104,6 +159,14 @@ type (
 		Update(*models.HVSReport) (*models.HVSReport, error)
 		Delete(uuid.UUID) error
 		FindHostIdsFromExpiredReports(fromTime time.Time, toTime time.Time) ([]uuid.UUID, error)
+		UpdateStageTimings(uuid.UUID, *hvs.ReportStageTimings) error
+		// SearchTrustHistory returns the hourly trust summary of the reports created for a host between two dates
+		SearchTrustHistory(hostId uuid.UUID, fromDate, toDate time.Time) ([]hvs.TrustHistoryBucket, error)
+		// DeleteTrustHistory deletes the trust summary of the hours before the time
+		DeleteTrustHistory(before time.Time) error
+		// ForTenant returns a view of the store that retrieves and searches only the reports of the hosts
+		// of the tenant
+		ForTenant(tenantId string) ReportStore
 	}
 
 	ESXiClusterStore interface {
@@ -121,12 +184,14 @@ type (
 		Retrieve(uuid.UUID) (*hvs.TagCertificate, error)
 		Delete(uuid.UUID) error
 		Search(*models.TagCertificateFilterCriteria) ([]*hvs.TagCertificate, error)
+		// Revoke marks a tag certificate as revoked
+		Revoke(uuid.UUID) error
 	}
 
 	HostTrustManager interface {
 		// Verify the trust of the a host.
 		//Returns the host trust report. For now marking this as interface since we have not defined the report structure
-		VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error)
+		VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error)
 
 		// This method is an asynchronous method meant to do the verify the trust of the host
 		// asynchronously. The requests are persisted to Store in case the server is taken down.
@@ -146,8 +211,8 @@ type (
 	}
 
 	HostDataFetcher interface {
-		// Synchronous method that blocks till the data is retrieved from the host.
-		Retrieve(host hvs.Host) (*types.HostManifest, error)
+		// Synchronous method that blocks till the data is retrieved from the host or the context is done.
+		Retrieve(ctx context.Context, host hvs.Host) (*types.HostManifest, error)
 
 		// Asynchronous method to be used to fetch data from hosts. As soon as the request is registered,
 		// the method returns. The result is returned individually as they are processed.
@@ -160,7 +225,25 @@ type (
 	}
 
 	HostTrustVerifier interface {
-		Verify(hostId uuid.UUID, hostData *types.HostManifest, newData bool, preferHashMatch bool) (*models.HVSReport, error)
+		// Verify creates the trust report of the host. The timings of the attestation stages that preceded
+		// the verification can be provided, the verification stages are then added to them.
+		Verify(hostId uuid.UUID, hostData *types.HostManifest, newData bool, preferHashMatch bool, timings *hvs.ReportStageTimings) (*models.HVSReport, error)
+	}
+
+	// AttestationLatencyRecorder aggregates the stage timings of the reports created by the host trust verifier
+	AttestationLatencyRecorder interface {
+		Record(hostId uuid.UUID, timings *hvs.ReportStageTimings)
+		Metrics() hvs.AttestationLatencyMetrics
+	}
+
+	// ReportHook is invoked with the report of a host before and after it is persisted by the host trust verifier.
+	// BeforeReportPersisted can enrich the trust report, it is persisted with the changes but the SAML report is
+	// already signed. Errors of the hooks are logged and do not prevent the report from being persisted.
+	// The reports of the hosts in maintenance mode have the suppressed-maintenance status, the hooks notifying of
+	// trust changes do not notify of them.
+	ReportHook interface {
+		BeforeReportPersisted(report *models.HVSReport) error
+		AfterReportPersisted(report *models.HVSReport) error
 	}
 
 	AuditLogWriter interface {
@@ -171,6 +254,28 @@ type (
 		Stop()
 	}
 
+	// AuditEventStore records the mutating calls to the HVS API
+	AuditEventStore interface {
+		Create(*hvs.AuditEvent) (*hvs.AuditEvent, error)
+		Search(*models.AuditEventFilterCriteria) (*hvs.AuditEventCollection, error)
+	}
+
+	// TenantUsageStore persists the usage of HVS per tenant, identity and day
+	TenantUsageStore interface {
+		// Add adds the API calls and report generations of the usage records to the persisted ones
+		Add([]hvs.TenantUsage) error
+		Search(*models.TenantUsageFilterCriteria) ([]hvs.TenantUsage, error)
+		// HostCounts returns the number of hosts registered by each tenant
+		HostCounts() (map[string]int, error)
+	}
+
+	// UsageMeter counts the usage of HVS per tenant and identity
+	UsageMeter interface {
+		RecordAPICall(tenantId, identity string)
+		// Flush persists the usage counted since the last flush
+		Flush() error
+	}
+
 	AuditLogEntryStore interface {
 		Create(*models.AuditLogEntry) (*models.AuditLogEntry, error)
 		Retrieve(*models.AuditLogEntry) ([]models.AuditLogEntry, error)
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// MockAuditEventStore provides a mocked implementation of interface domain.AuditEventStore
+type MockAuditEventStore struct {
+	AuditEvents []*hvs.AuditEvent
+}
+
+// Create inserts an AuditEvent
+func (store *MockAuditEventStore) Create(ae *hvs.AuditEvent) (*hvs.AuditEvent, error) {
+	ae.ID = uuid.New()
+	if ae.Created.IsZero() {
+		ae.Created = time.Now().UTC()
+	}
+	store.AuditEvents = append(store.AuditEvents, ae)
+	return ae, nil
+}
+
+// Search returns the AuditEvents matching the AuditEventFilterCriteria, the most recent first
+func (store *MockAuditEventStore) Search(criteria *models.AuditEventFilterCriteria) (*hvs.AuditEventCollection, error) {
+	collection := hvs.AuditEventCollection{AuditEvents: []*hvs.AuditEvent{}}
+	for i := len(store.AuditEvents) - 1; i >= 0; i-- {
+		ae := store.AuditEvents[i]
+		if criteria != nil {
+			if (criteria.Actor != "" && ae.Actor != criteria.Actor) ||
+				(criteria.Method != "" && ae.Method != criteria.Method) ||
+				(criteria.EntityType != "" && ae.EntityType != criteria.EntityType) ||
+				(criteria.EntityID != uuid.Nil && (ae.EntityID == nil || *ae.EntityID != criteria.EntityID)) ||
+				(!criteria.FromDate.IsZero() && ae.Created.Before(criteria.FromDate)) ||
+				(!criteria.ToDate.IsZero() && ae.Created.After(criteria.ToDate)) {
+				continue
+			}
+			if criteria.Limit > 0 && len(collection.AuditEvents) == criteria.Limit {
+				break
+			}
+		}
+		collection.AuditEvents = append(collection.AuditEvents, ae)
+	}
+	return &collection, nil
+}
+
+// NewFakeAuditEventStore provides an empty MockAuditEventStore
+func NewFakeAuditEventStore() *MockAuditEventStore {
+	return &MockAuditEventStore{}
+}
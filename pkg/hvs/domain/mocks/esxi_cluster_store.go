@@ -24,6 +24,7 @@ var (
 
 type MockESXiClusterStore struct {
 	ESXiClusterStore []hvs.ESXiCluster
+	ClusterHosts     map[uuid.UUID][]string
 }
 
 // Retrieve returns ESXi Cluster
@@ -76,14 +77,18 @@ func (store *MockESXiClusterStore) Create(ec *hvs.ESXiCluster) (*hvs.ESXiCluster
 	return ec, nil
 }
 
-func (store *MockESXiClusterStore) AddHosts(esxiClusterId uuid.UUID, hostName []string) error {
-	//TODO Implement mock for AddHosts
+// AddHosts links the hosts to the ESXi cluster
+func (store *MockESXiClusterStore) AddHosts(esxiClusterId uuid.UUID, hostNames []string) error {
+	if store.ClusterHosts == nil {
+		store.ClusterHosts = make(map[uuid.UUID][]string)
+	}
+	store.ClusterHosts[esxiClusterId] = append(store.ClusterHosts[esxiClusterId], hostNames...)
 	return nil
 }
 
+// SearchHosts returns the names of the hosts linked to the ESXi cluster
 func (store *MockESXiClusterStore) SearchHosts(clusterId uuid.UUID) ([]string, error) {
-	//TODO Implement mock for SearchHosts
-	return nil, nil
+	return store.ClusterHosts[clusterId], nil
 }
 
 // NewFakeESXiClusterStore loads dummy data into MockESXiClusterStore
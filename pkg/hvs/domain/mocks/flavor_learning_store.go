@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockFlavorLearningStore provides a mocked implementation of interface domain.FlavorLearningStore
+type MockFlavorLearningStore struct {
+	flavorLearnings map[uuid.UUID]*hvs.FlavorLearning
+}
+
+// Create and inserts a FlavorLearning
+func (store *MockFlavorLearningStore) Create(fl *hvs.FlavorLearning) (*hvs.FlavorLearning, error) {
+	if fl.ID == uuid.Nil {
+		fl.ID = uuid.New()
+	}
+	store.flavorLearnings[fl.ID] = fl
+	return fl, nil
+}
+
+// Retrieve returns FlavorLearning
+func (store *MockFlavorLearningStore) Retrieve(id uuid.UUID) (*hvs.FlavorLearning, error) {
+	if fl, ok := store.flavorLearnings[id]; ok {
+		return fl, nil
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+// Search returns a filtered list of FlavorLearnings per the provided FlavorLearningFilterCriteria
+func (store *MockFlavorLearningStore) Search(criteria *models.FlavorLearningFilterCriteria) (*hvs.FlavorLearningCollection, error) {
+	collection := hvs.FlavorLearningCollection{FlavorLearnings: []*hvs.FlavorLearning{}}
+	for _, fl := range store.flavorLearnings {
+		if criteria != nil {
+			if (criteria.Id != uuid.Nil && fl.ID != criteria.Id) ||
+				(criteria.LabelEqualTo != "" && fl.Label != criteria.LabelEqualTo) {
+				continue
+			}
+		}
+		collection.FlavorLearnings = append(collection.FlavorLearnings, fl)
+	}
+	return &collection, nil
+}
+
+// Delete FlavorLearning
+func (store *MockFlavorLearningStore) Delete(id uuid.UUID) error {
+	if _, ok := store.flavorLearnings[id]; !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	delete(store.flavorLearnings, id)
+	return nil
+}
+
+// NewFakeFlavorLearningStore provides an empty MockFlavorLearningStore
+func NewFakeFlavorLearningStore() *MockFlavorLearningStore {
+	return &MockFlavorLearningStore{
+		flavorLearnings: make(map[uuid.UUID]*hvs.FlavorLearning),
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockFlavorPruneStore provides a mocked implementation of interface domain.FlavorPruneStore
+type MockFlavorPruneStore struct {
+	FlavorVersions []hvs.FlavorVersion
+	// VersionTenants are the tenants of the flavor versions, the versions not listed belong to the default namespace
+	VersionTenants map[uuid.UUID]string
+	flavorPrunes   map[uuid.UUID]*hvs.FlavorPrune
+	pruneTenants   map[uuid.UUID]string
+}
+
+// SearchFlavorVersions returns the flavor versions of the flavor parts
+func (store *MockFlavorPruneStore) SearchFlavorVersions(flavorParts []cf.FlavorPart) ([]hvs.FlavorVersion, error) {
+	var versions []hvs.FlavorVersion
+	for _, version := range store.FlavorVersions {
+		for _, flavorPart := range flavorParts {
+			if version.FlavorPart == flavorPart.String() {
+				versions = append(versions, version)
+				break
+			}
+		}
+	}
+	return versions, nil
+}
+
+// Create removes the retired flavor versions and inserts a FlavorPrune
+func (store *MockFlavorPruneStore) Create(fp *hvs.FlavorPrune) (*hvs.FlavorPrune, error) {
+	fp.ID = uuid.New()
+	retired := make(map[uuid.UUID]bool)
+	for _, version := range fp.RetiredFlavors {
+		retired[version.FlavorID] = true
+	}
+	var versions []hvs.FlavorVersion
+	for _, version := range store.FlavorVersions {
+		if !retired[version.FlavorID] {
+			versions = append(versions, version)
+		}
+	}
+	store.FlavorVersions = versions
+	store.flavorPrunes[fp.ID] = fp
+	return fp, nil
+}
+
+// Retrieve returns FlavorPrune
+func (store *MockFlavorPruneStore) Retrieve(id uuid.UUID) (*hvs.FlavorPrune, error) {
+	if fp, ok := store.flavorPrunes[id]; ok {
+		return fp, nil
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+// Search returns all the FlavorPrunes
+func (store *MockFlavorPruneStore) Search() (*hvs.FlavorPruneCollection, error) {
+	collection := hvs.FlavorPruneCollection{FlavorPrunes: []*hvs.FlavorPrune{}}
+	for _, fp := range store.flavorPrunes {
+		collection.FlavorPrunes = append(collection.FlavorPrunes, fp)
+	}
+	return &collection, nil
+}
+
+// Undo restores the retired flavor versions of the FlavorPrune
+func (store *MockFlavorPruneStore) Undo(id uuid.UUID) (*hvs.FlavorPrune, error) {
+	fp, ok := store.flavorPrunes[id]
+	if !ok {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	if fp.Undone != nil {
+		return nil, errors.New("FlavorPrune is already undone")
+	}
+	store.FlavorVersions = append(store.FlavorVersions, fp.RetiredFlavors...)
+	undone := time.Now().UTC()
+	fp.Undone = &undone
+	return fp, nil
+}
+
+// NewFakeFlavorPruneStore provides a MockFlavorPruneStore with the flavor versions
+func NewFakeFlavorPruneStore(versions []hvs.FlavorVersion) *MockFlavorPruneStore {
+	return &MockFlavorPruneStore{
+		FlavorVersions: versions,
+		VersionTenants: make(map[uuid.UUID]string),
+		flavorPrunes:   make(map[uuid.UUID]*hvs.FlavorPrune),
+		pruneTenants:   make(map[uuid.UUID]string),
+	}
+}
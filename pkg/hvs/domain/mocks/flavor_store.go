@@ -22,6 +22,8 @@ type MockFlavorStore struct {
 	flavorStore            []hvs.SignedFlavor
 	FlavorFlavorGroupStore map[uuid.UUID][]uuid.UUID
 	FlavorgroupStore       map[uuid.UUID]*hvs.FlavorGroup
+	// flavorTenants holds the tenant of the flavors created through a tenant view of the store
+	flavorTenants map[uuid.UUID]string
 }
 
 var flavor = ` {
@@ -386,6 +388,28 @@ func (store *MockFlavorStore) Retrieve(id uuid.UUID) (*hvs.SignedFlavor, error)
 	return nil, errors.New(commErr.RowsNotFound)
 }
 
+// AddSignature adds a signature to the additional signatures of a Flavor
+func (store *MockFlavorStore) AddSignature(id uuid.UUID, signature string) (*hvs.SignedFlavor, error) {
+	for i, f := range store.flavorStore {
+		if f.Flavor.Meta.ID == id {
+			store.flavorStore[i].AdditionalSignatures = append(append([]string{}, f.AdditionalSignatures...), signature)
+			return &store.flavorStore[i], nil
+		}
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+// Update replaces the content and the signatures of a Flavor
+func (store *MockFlavorStore) Update(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
+	for i, f := range store.flavorStore {
+		if f.Flavor.Meta.ID == signedFlavor.Flavor.Meta.ID {
+			store.flavorStore[i] = *signedFlavor
+			return &store.flavorStore[i], nil
+		}
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
 // Search returns a filtered list of flavors per the provided FlavorFilterCriteria
 func (store *MockFlavorStore) Search(criteria *models.FlavorVerificationFC) ([]hvs.SignedFlavor, error) {
 	var sfs []hvs.SignedFlavor
@@ -431,6 +455,20 @@ func (store *MockFlavorStore) Search(criteria *models.FlavorVerificationFC) ([]h
 				}
 			}
 		}
+	} else if criteria.FlavorFC.MetadataKey != "" {
+		sfs = store.flavorStore
+	}
+
+	// Custom metadata filter
+	if criteria.FlavorFC.MetadataKey != "" {
+		sfFiltered = nil
+		for _, f := range sfs {
+			if value, ok := f.Flavor.Meta.CustomMetadata[criteria.FlavorFC.MetadataKey]; ok &&
+				fmt.Sprint(value) == criteria.FlavorFC.MetadataValue {
+				sfFiltered = append(sfFiltered, f)
+			}
+		}
+		sfs = sfFiltered
 	}
 	return sfs, nil
 }
@@ -439,8 +477,9 @@ func (store *MockFlavorStore) Search(criteria *models.FlavorVerificationFC) ([]h
 func (store *MockFlavorStore) Create(sf *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
 	//It is not right way to directly append the pointer, reference will be copied. Copy only the values.
 	rec := hvs.SignedFlavor{
-		Flavor:    sf.Flavor,
-		Signature: sf.Signature,
+		Flavor:               sf.Flavor,
+		Signature:            sf.Signature,
+		AdditionalSignatures: sf.AdditionalSignatures,
 	}
 	store.flavorStore = append(store.flavorStore, rec)
 	return sf, nil
@@ -75,6 +75,14 @@ func (store *MockFlavorgroupStore) Search(criteria *models.FlavorGroupFilterCrit
 			}
 		}
 		return flavorgroups, nil
+	} else if criteria.ParentId != nil {
+		var flavorgroups []hvs.FlavorGroup
+		for _, fg := range store.FlavorgroupStore {
+			if fg.ParentId != nil && *fg.ParentId == *criteria.ParentId {
+				flavorgroups = append(flavorgroups, *fg)
+			}
+		}
+		return flavorgroups, nil
 	}
 	return nil, nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // MockHostStore provides a mocked implementation of interface domain.HostStore
@@ -40,6 +41,8 @@ func (store *MockHostStore) Retrieve(id uuid.UUID, criteria *models.HostInfoFetc
 func (store *MockHostStore) Update(host *hvs.Host) error {
 	for i, h := range store.hostStore {
 		if h.Id == host.Id {
+			// the maintenance is not updated with the host
+			host.Maintenance = h.Maintenance
 			store.hostStore[i] = host
 			return nil
 		}
@@ -82,7 +85,7 @@ func (store *MockHostStore) Search(criteria *models.HostFilterCriteria, hostInfo
 		}
 	} else if criteria.HostHardwareId != uuid.Nil {
 		for _, h := range store.hostStore {
-			if *h.HardwareUuid == criteria.HostHardwareId {
+			if h.HardwareUuid != nil && *h.HardwareUuid == criteria.HostHardwareId {
 				hosts = append(hosts, h)
 			}
 		}
@@ -98,6 +101,12 @@ func (store *MockHostStore) Search(criteria *models.HostFilterCriteria, hostInfo
 				hosts = append(hosts, h)
 			}
 		}
+	} else if criteria.Lifecycle != "" {
+		for _, h := range store.hostStore {
+			if h.Lifecycle == criteria.Lifecycle {
+				hosts = append(hosts, h)
+			}
+		}
 	}
 	return hosts, nil
 }
@@ -183,6 +192,40 @@ func (store *MockHostStore) RetrieveDistinctUniqueFlavorParts(uuid.UUID) ([]stri
 	return nil, nil
 }
 
+// SetMaintenance puts a Host in maintenance mode
+func (store *MockHostStore) SetMaintenance(hId uuid.UUID, maintenance *hvs.HostMaintenance) error {
+	for _, h := range store.hostStore {
+		if h.Id == hId {
+			h.Maintenance = maintenance
+			return nil
+		}
+	}
+	return errors.New(commErr.RecordNotFound)
+}
+
+// EndMaintenance ends the maintenance of a Host
+func (store *MockHostStore) EndMaintenance(hId uuid.UUID) (bool, error) {
+	for _, h := range store.hostStore {
+		if h.Id == hId && h.Maintenance != nil {
+			h.Maintenance = nil
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EndElapsedMaintenances ends the maintenances whose resume time has passed
+func (store *MockHostStore) EndElapsedMaintenances(now time.Time) ([]uuid.UUID, error) {
+	var hostIds []uuid.UUID
+	for _, h := range store.hostStore {
+		if h.Maintenance != nil && h.Maintenance.ResumeAt != nil && !h.Maintenance.ResumeAt.After(now) {
+			h.Maintenance = nil
+			hostIds = append(hostIds, h.Id)
+		}
+	}
+	return hostIds, nil
+}
+
 // NewMockHostStore provides two dummy data for Hosts
 func NewMockHostStore() *MockHostStore {
 	store := &MockHostStore{}
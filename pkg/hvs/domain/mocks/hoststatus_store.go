@@ -264,6 +264,41 @@ func (store *MockHostStatusStore) FindHostIdsByKeyValue(key, value string) ([]uu
 	return store.HostStatusStore.FindHostIdsByKeyValue(key, value)
 }
 
+// UpdateLifecycleState moves the host to the lifecycle state and records the transition
+func (store *MockHostStatusStore) UpdateLifecycleState(hostId uuid.UUID, state hvs.HostLifecycleState, reason string) error {
+	// Mock Retrieve Query Response
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\)`).
+		WithArgs(hs1.HostID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
+			AddRow(hs1.ID.String(), hs1.HostID.String(), hsi1, hsm1, hs1.Created))
+
+	// Mock Update Response
+	store.Mock.ExpectBegin()
+	updateResult := sqlmock.NewResult(1, 1)
+	store.Mock.ExpectExec(`^UPDATE "host_status" (.+)`).WillReturnResult(updateResult).WillReturnError(nil)
+	store.Mock.ExpectCommit()
+
+	// Mock Transition Create Response
+	store.Mock.ExpectBegin()
+	store.Mock.ExpectQuery(`INSERT INTO "host_lifecycle_transition" (.+)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New().String()))
+	store.Mock.ExpectCommit()
+
+	return store.HostStatusStore.UpdateLifecycleState(hostId, state, reason)
+}
+
+// SearchLifecycleTransitions returns the lifecycle history of a host
+func (store *MockHostStatusStore) SearchLifecycleTransitions(hostId uuid.UUID, limit int) ([]hvs.HostLifecycleTransition, error) {
+	// Mock Search Query Response
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_lifecycle_transition" WHERE \(host_id = \$1\) ORDER BY created DESC`).
+		WithArgs(hs1.HostID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "from_state", "to_state", "reason", "created"}).
+			AddRow("8b1b9e2e-30a6-4dcc-9a6e-5a7e9e0c0c3d", hs1.HostID.String(), "CONNECTED", "ATTESTED", "host is trusted", hs1.Created).
+			AddRow("3f63d4ee-4e8a-4bc9-8c06-1ea7f5d2f0a5", hs1.HostID.String(), "QUEUED", "CONNECTED", "", hs1.Created))
+
+	return store.HostStatusStore.SearchLifecycleTransitions(hostId, limit)
+}
+
 // NewMockHostStatusStore initializes the mock datastore and prepares the MockHostStatusStore
 func NewMockHostStatusStore() *MockHostStatusStore {
 	datastore, mock := postgres.NewSQLMockDataStore()
@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockPlatformCertificateStore provides a mocked implementation of interface domain.PlatformCertificateStore
+type MockPlatformCertificateStore struct {
+	platformCertificates map[uuid.UUID]*hvs.PlatformCertificate
+}
+
+// Create and inserts a PlatformCertificate
+func (store *MockPlatformCertificateStore) Create(pc *hvs.PlatformCertificate) (*hvs.PlatformCertificate, error) {
+	if pc.ID == uuid.Nil {
+		pc.ID = uuid.New()
+	}
+	store.platformCertificates[pc.ID] = pc
+	return pc, nil
+}
+
+// Retrieve returns PlatformCertificate
+func (store *MockPlatformCertificateStore) Retrieve(id uuid.UUID) (*hvs.PlatformCertificate, error) {
+	if pc, ok := store.platformCertificates[id]; ok {
+		return pc, nil
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+// Update PlatformCertificate
+func (store *MockPlatformCertificateStore) Update(pc *hvs.PlatformCertificate) (*hvs.PlatformCertificate, error) {
+	if _, ok := store.platformCertificates[pc.ID]; !ok {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	store.platformCertificates[pc.ID] = pc
+	return pc, nil
+}
+
+// Search returns a filtered list of PlatformCertificates per the provided PlatformCertificateFilterCriteria
+func (store *MockPlatformCertificateStore) Search(criteria *models.PlatformCertificateFilterCriteria) (*hvs.PlatformCertificateCollection, error) {
+	collection := hvs.PlatformCertificateCollection{PlatformCertificates: []*hvs.PlatformCertificate{}}
+	for _, pc := range store.platformCertificates {
+		if criteria != nil {
+			if pc.Revoked != criteria.RevokedEqualTo ||
+				(criteria.Id != uuid.Nil && pc.ID != criteria.Id) ||
+				(criteria.HardwareUuidEqualTo != uuid.Nil && pc.HardwareUUID != criteria.HardwareUuidEqualTo) ||
+				(criteria.ManufacturerEqualTo != "" && pc.Manufacturer != criteria.ManufacturerEqualTo) ||
+				(criteria.ModelEqualTo != "" && pc.Model != criteria.ModelEqualTo) ||
+				(criteria.SerialEqualTo != "" && pc.Serial != criteria.SerialEqualTo) ||
+				(criteria.CertificateDigestEqualTo != "" && pc.CertificateDigest != criteria.CertificateDigestEqualTo) {
+				continue
+			}
+		}
+		collection.PlatformCertificates = append(collection.PlatformCertificates, pc)
+	}
+	return &collection, nil
+}
+
+// Delete PlatformCertificate
+func (store *MockPlatformCertificateStore) Delete(id uuid.UUID) error {
+	if _, ok := store.platformCertificates[id]; !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	delete(store.platformCertificates, id)
+	return nil
+}
+
+// NewFakePlatformCertificateStore provides an empty MockPlatformCertificateStore
+func NewFakePlatformCertificateStore() *MockPlatformCertificateStore {
+	return &MockPlatformCertificateStore{
+		platformCertificates: make(map[uuid.UUID]*hvs.PlatformCertificate),
+	}
+}
@@ -10,22 +10,29 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"sync"
 	"time"
 )
 
 type qStore struct {
-	m map[uuid.UUID]models.Queue
+	mtx sync.Mutex
+	m   map[uuid.UUID]models.Queue
 }
 
 func NewQueueStore() domain.QueueStore {
 
-	return &qStore{make(map[uuid.UUID]models.Queue)}
+	return &qStore{m: make(map[uuid.UUID]models.Queue)}
 }
 
 func (qs *qStore) Search(criteria *models.QueueFilterCriteria) ([]*models.Queue, error) {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
 	if criteria == nil || criteria.Id == uuid.Nil {
 		rslt := make([]*models.Queue, 0, len(qs.m))
 		for _, v := range qs.m {
+			if criteria != nil && !matchesQueueCriteria(v, criteria) {
+				continue
+			}
 			rslt = append(rslt, &v)
 		}
 		return rslt, nil
@@ -38,6 +45,8 @@ func (qs *qStore) Search(criteria *models.QueueFilterCriteria) ([]*models.Queue,
 }
 
 func (qs *qStore) Retrieve(uuid uuid.UUID) (*models.Queue, error) {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
 	if _, ok := qs.m[uuid]; ok {
 		cp := qs.m[uuid]
 		return &cp, nil
@@ -46,6 +55,8 @@ func (qs *qStore) Retrieve(uuid uuid.UUID) (*models.Queue, error) {
 }
 
 func (qs *qStore) Update(queue *models.Queue) error {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
 	if rec, ok := qs.m[queue.Id]; ok {
 
 		for k, v := range queue.Params {
@@ -66,6 +77,8 @@ func (qs *qStore) Update(queue *models.Queue) error {
 }
 
 func (qs *qStore) Create(queue *models.Queue) (*models.Queue, error) {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
 	rec := *queue
 	newUuid, err := uuid.NewRandom()
 	if err != nil {
@@ -80,9 +93,26 @@ func (qs *qStore) Create(queue *models.Queue) (*models.Queue, error) {
 }
 
 func (qs *qStore) Delete(uuid uuid.UUID) error {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
 	if _, ok := qs.m[uuid]; ok {
 		delete(qs.m, uuid)
 		return nil
 	}
 	return errors.New("Record not found")
 }
+
+func matchesQueueCriteria(queue models.Queue, criteria *models.QueueFilterCriteria) bool {
+	if len(criteria.QueueStates) > 0 {
+		stateFound := false
+		for _, state := range criteria.QueueStates {
+			if queue.State == state {
+				stateFound = true
+			}
+		}
+		if !stateFound {
+			return false
+		}
+	}
+	return criteria.UpdatedBefore.IsZero() || queue.Updated.Before(criteria.UpdatedBefore)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +23,11 @@ import (
 // MockReportStore provides a mocked implementation of interface postgres.ReportStore
 type MockReportStore struct {
 	reportStore map[uuid.UUID]models.HVSReport
+	// HostTenants holds the tenant of the hosts the reports belong to, hosts without an entry are in the
+	// default namespace
+	HostTenants map[uuid.UUID]string
+	// trustHistoryStart is the time the trust history was deleted before
+	trustHistoryStart time.Time
 }
 
 // Create inserts a HVSReport
@@ -121,6 +127,17 @@ func (store *MockReportStore) Search(criteria *models.ReportFilterCriteria) ([]m
 
 	}
 
+	if criteria.UntrustedFlavorPart != "" {
+		var untrustedReports []models.HVSReport
+		for _, r := range reports {
+			tr := hvs.NewTrustReport(r.TrustReport)
+			if len(tr.GetResultsForMarker(criteria.UntrustedFlavorPart)) > 0 && !tr.IsTrustedForMarker(criteria.UntrustedFlavorPart) {
+				untrustedReports = append(untrustedReports, r)
+			}
+		}
+		reports = untrustedReports
+	}
+
 	return reports, nil
 }
 
@@ -136,6 +153,65 @@ func (store *MockReportStore) FindHostIdsFromExpiredReports(fromTime time.Time,
 	return hostIDs, nil
 }
 
+func (store *MockReportStore) UpdateStageTimings(id uuid.UUID, timings *hvs.ReportStageTimings) error {
+	rs, found := store.reportStore[id]
+	if !found {
+		return errors.New(commErr.RowsNotFound)
+	}
+	rs.TrustReport.StageTimings = timings
+	store.reportStore[id] = rs
+	return nil
+}
+
+// SearchTrustHistory sums up the stored reports of the host by the hour they were created in
+func (store *MockReportStore) SearchTrustHistory(hostId uuid.UUID, fromDate, toDate time.Time) ([]hvs.TrustHistoryBucket, error) {
+	buckets := map[time.Time]*hvs.TrustHistoryBucket{}
+	for _, r := range store.reportStore {
+		if r.HostID != hostId || r.CreatedAt.Before(fromDate.Truncate(time.Hour)) || r.CreatedAt.After(toDate) ||
+			r.CreatedAt.Before(store.trustHistoryStart) {
+			continue
+		}
+		hour := r.CreatedAt.UTC().Truncate(time.Hour)
+		bucket, ok := buckets[hour]
+		if !ok {
+			bucket = &hvs.TrustHistoryBucket{Start: hour, Trusted: true}
+			buckets[hour] = bucket
+		}
+		if r.TrustReport.Trusted {
+			bucket.TrustedReports++
+			continue
+		}
+		bucket.Trusted = false
+		bucket.UntrustedReports++
+		for _, result := range r.TrustReport.Results {
+			for _, fault := range result.Faults {
+				if fault.Warning {
+					continue
+				}
+				if bucket.Faults == nil {
+					bucket.Faults = map[string]int64{}
+				}
+				bucket.Faults[fault.Name]++
+			}
+		}
+	}
+
+	history := make([]hvs.TrustHistoryBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		history = append(history, *bucket)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Start.Before(history[j].Start)
+	})
+	return history, nil
+}
+
+// DeleteTrustHistory leaves the reports created before the time out of the trust history
+func (store *MockReportStore) DeleteTrustHistory(before time.Time) error {
+	store.trustHistoryStart = before.UTC().Truncate(time.Hour)
+	return nil
+}
+
 // NewMockReportStore provides two dummy data for Reports
 func NewMockReportStore() *MockReportStore {
 	//TODO add more data
@@ -0,0 +1,307 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// tenantHostStore is the view of a MockHostStore restricted to the hosts of a tenant
+type tenantHostStore struct {
+	*MockHostStore
+	tenantId string
+}
+
+// ForTenant returns a view of the store restricted to the hosts of the tenant
+func (store *MockHostStore) ForTenant(tenantId string) domain.HostStore {
+	return &tenantHostStore{MockHostStore: store, tenantId: tenantId}
+}
+
+func (store *tenantHostStore) Create(host *hvs.Host) (*hvs.Host, error) {
+	host.TenantId = store.tenantId
+	return store.MockHostStore.Create(host)
+}
+
+func (store *tenantHostStore) Retrieve(id uuid.UUID, criteria *models.HostInfoFetchCriteria) (*hvs.Host, error) {
+	host, err := store.MockHostStore.Retrieve(id, criteria)
+	if err != nil {
+		return nil, err
+	}
+	if host.TenantId != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return host, nil
+}
+
+func (store *tenantHostStore) Update(host *hvs.Host) error {
+	if _, err := store.Retrieve(host.Id, nil); err != nil {
+		return errors.New(commErr.RecordNotFound)
+	}
+	host.TenantId = store.tenantId
+	return store.MockHostStore.Update(host)
+}
+
+func (store *tenantHostStore) Delete(id uuid.UUID) error {
+	if _, err := store.Retrieve(id, nil); err != nil {
+		return errors.New(commErr.RecordNotFound)
+	}
+	return store.MockHostStore.Delete(id)
+}
+
+func (store *tenantHostStore) DeleteByHostName(hostName string) error {
+	hosts, err := store.Search(&models.HostFilterCriteria{NameEqualTo: hostName}, nil)
+	if err != nil || len(hosts) == 0 {
+		return errors.New(commErr.RecordNotFound)
+	}
+	return store.MockHostStore.DeleteByHostName(hostName)
+}
+
+func (store *tenantHostStore) Search(criteria *models.HostFilterCriteria, hostInfoFetchCriteria *models.HostInfoFetchCriteria) ([]*hvs.Host, error) {
+	hosts, err := store.MockHostStore.Search(criteria, hostInfoFetchCriteria)
+	if err != nil {
+		return nil, err
+	}
+	var tenantHosts []*hvs.Host
+	for _, host := range hosts {
+		if host.TenantId == store.tenantId && host.Id != uuid.Nil {
+			tenantHosts = append(tenantHosts, host)
+		}
+	}
+	return tenantHosts, nil
+}
+
+// tenantFlavorgroupStore is the view of a MockFlavorgroupStore restricted to the flavorgroups of a tenant
+type tenantFlavorgroupStore struct {
+	*MockFlavorgroupStore
+	tenantId string
+}
+
+// ForTenant returns a view of the store restricted to the flavorgroups of the tenant
+func (store *MockFlavorgroupStore) ForTenant(tenantId string) domain.FlavorGroupStore {
+	return &tenantFlavorgroupStore{MockFlavorgroupStore: store, tenantId: tenantId}
+}
+
+func (store *tenantFlavorgroupStore) Create(flavorgroup *hvs.FlavorGroup) (*hvs.FlavorGroup, error) {
+	flavorgroup.TenantId = store.tenantId
+	return store.MockFlavorgroupStore.Create(flavorgroup)
+}
+
+func (store *tenantFlavorgroupStore) Retrieve(id uuid.UUID) (*hvs.FlavorGroup, error) {
+	flavorgroup, err := store.MockFlavorgroupStore.Retrieve(id)
+	if err != nil {
+		return nil, err
+	}
+	if flavorgroup.TenantId != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return flavorgroup, nil
+}
+
+func (store *tenantFlavorgroupStore) Search(criteria *models.FlavorGroupFilterCriteria) ([]hvs.FlavorGroup, error) {
+	flavorgroups, err := store.MockFlavorgroupStore.Search(criteria)
+	if err != nil {
+		return nil, err
+	}
+	var tenantFlavorgroups []hvs.FlavorGroup
+	for _, flavorgroup := range flavorgroups {
+		if flavorgroup.TenantId == store.tenantId {
+			tenantFlavorgroups = append(tenantFlavorgroups, flavorgroup)
+		}
+	}
+	return tenantFlavorgroups, nil
+}
+
+func (store *tenantFlavorgroupStore) Delete(id uuid.UUID) error {
+	if _, err := store.Retrieve(id); err != nil {
+		return err
+	}
+	return store.MockFlavorgroupStore.Delete(id)
+}
+
+// tenantFlavorStore is the view of a MockFlavorStore restricted to the flavors of a tenant
+type tenantFlavorStore struct {
+	*MockFlavorStore
+	tenantId string
+}
+
+// ForTenant returns a view of the store restricted to the flavors of the tenant
+func (store *MockFlavorStore) ForTenant(tenantId string) domain.FlavorStore {
+	return &tenantFlavorStore{MockFlavorStore: store, tenantId: tenantId}
+}
+
+func (store *tenantFlavorStore) Create(sf *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
+	if sf.Flavor.Meta.ID == uuid.Nil {
+		newUuid, err := uuid.NewRandom()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create new UUID")
+		}
+		sf.Flavor.Meta.ID = newUuid
+	}
+	if store.flavorTenants == nil {
+		store.flavorTenants = make(map[uuid.UUID]string)
+	}
+	store.flavorTenants[sf.Flavor.Meta.ID] = store.tenantId
+	return store.MockFlavorStore.Create(sf)
+}
+
+func (store *tenantFlavorStore) Retrieve(id uuid.UUID) (*hvs.SignedFlavor, error) {
+	if store.flavorTenants[id] != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return store.MockFlavorStore.Retrieve(id)
+}
+
+func (store *tenantFlavorStore) Search(criteria *models.FlavorVerificationFC) ([]hvs.SignedFlavor, error) {
+	signedFlavors, err := store.MockFlavorStore.Search(criteria)
+	if err != nil {
+		return nil, err
+	}
+	var tenantFlavors []hvs.SignedFlavor
+	for _, signedFlavor := range signedFlavors {
+		if store.flavorTenants[signedFlavor.Flavor.Meta.ID] == store.tenantId {
+			tenantFlavors = append(tenantFlavors, signedFlavor)
+		}
+	}
+	return tenantFlavors, nil
+}
+
+func (store *tenantFlavorStore) Delete(id uuid.UUID) error {
+	if store.flavorTenants[id] != store.tenantId {
+		return errors.New(commErr.RowsNotFound)
+	}
+	return store.MockFlavorStore.Delete(id)
+}
+
+func (store *tenantFlavorStore) AddSignature(id uuid.UUID, signature string) (*hvs.SignedFlavor, error) {
+	if store.flavorTenants[id] != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return store.MockFlavorStore.AddSignature(id, signature)
+}
+
+func (store *tenantFlavorStore) Update(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
+	if store.flavorTenants[signedFlavor.Flavor.Meta.ID] != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return store.MockFlavorStore.Update(signedFlavor)
+}
+
+// tenantReportStore is the view of a MockReportStore restricted to the reports of the hosts of a tenant
+type tenantReportStore struct {
+	*MockReportStore
+	tenantId string
+}
+
+// ForTenant returns a view of the store restricted to the reports of the hosts of the tenant
+func (store *MockReportStore) ForTenant(tenantId string) domain.ReportStore {
+	return &tenantReportStore{MockReportStore: store, tenantId: tenantId}
+}
+
+func (store *tenantReportStore) Retrieve(id uuid.UUID) (*models.HVSReport, error) {
+	report, err := store.MockReportStore.Retrieve(id)
+	if err != nil {
+		return nil, err
+	}
+	if store.HostTenants[report.HostID] != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return report, nil
+}
+
+func (store *tenantReportStore) SearchTrustHistory(hostId uuid.UUID, fromDate, toDate time.Time) ([]hvs.TrustHistoryBucket, error) {
+	if store.HostTenants[hostId] != store.tenantId {
+		return []hvs.TrustHistoryBucket{}, nil
+	}
+	return store.MockReportStore.SearchTrustHistory(hostId, fromDate, toDate)
+}
+
+func (store *tenantReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HVSReport, error) {
+	reports, err := store.MockReportStore.Search(criteria)
+	if err != nil {
+		return nil, err
+	}
+	var tenantReports []models.HVSReport
+	for _, report := range reports {
+		if store.HostTenants[report.HostID] == store.tenantId {
+			tenantReports = append(tenantReports, report)
+		}
+	}
+	return tenantReports, nil
+}
+
+// tenantFlavorPruneStore is the view of a MockFlavorPruneStore restricted to the flavors and the prunes of a tenant
+type tenantFlavorPruneStore struct {
+	*MockFlavorPruneStore
+	tenantId string
+}
+
+// ForTenant returns a view of the store restricted to the flavors and the prunes of the tenant
+func (store *MockFlavorPruneStore) ForTenant(tenantId string) domain.FlavorPruneStore {
+	return &tenantFlavorPruneStore{MockFlavorPruneStore: store, tenantId: tenantId}
+}
+
+func (store *tenantFlavorPruneStore) SearchFlavorVersions(flavorParts []cf.FlavorPart) ([]hvs.FlavorVersion, error) {
+	versions, err := store.MockFlavorPruneStore.SearchFlavorVersions(flavorParts)
+	if err != nil {
+		return nil, err
+	}
+	var tenantVersions []hvs.FlavorVersion
+	for _, version := range versions {
+		if store.VersionTenants[version.FlavorID] == store.tenantId {
+			tenantVersions = append(tenantVersions, version)
+		}
+	}
+	return tenantVersions, nil
+}
+
+func (store *tenantFlavorPruneStore) Create(fp *hvs.FlavorPrune) (*hvs.FlavorPrune, error) {
+	for _, version := range fp.RetiredFlavors {
+		if store.VersionTenants[version.FlavorID] != store.tenantId {
+			return nil, errors.New(commErr.RowsNotFound)
+		}
+	}
+	fp, err := store.MockFlavorPruneStore.Create(fp)
+	if err != nil {
+		return nil, err
+	}
+	store.pruneTenants[fp.ID] = store.tenantId
+	return fp, nil
+}
+
+func (store *tenantFlavorPruneStore) Retrieve(id uuid.UUID) (*hvs.FlavorPrune, error) {
+	if store.pruneTenants[id] != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return store.MockFlavorPruneStore.Retrieve(id)
+}
+
+func (store *tenantFlavorPruneStore) Search() (*hvs.FlavorPruneCollection, error) {
+	collection, err := store.MockFlavorPruneStore.Search()
+	if err != nil {
+		return nil, err
+	}
+	tenantCollection := hvs.FlavorPruneCollection{FlavorPrunes: []*hvs.FlavorPrune{}}
+	for _, fp := range collection.FlavorPrunes {
+		if store.pruneTenants[fp.ID] == store.tenantId {
+			tenantCollection.FlavorPrunes = append(tenantCollection.FlavorPrunes, fp)
+		}
+	}
+	return &tenantCollection, nil
+}
+
+func (store *tenantFlavorPruneStore) Undo(id uuid.UUID) (*hvs.FlavorPrune, error) {
+	if store.pruneTenants[id] != store.tenantId {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return store.MockFlavorPruneStore.Undo(id)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// MockTenantUsageStore provides a mocked implementation of interface domain.TenantUsageStore
+type MockTenantUsageStore struct {
+	Usage     []hvs.TenantUsage
+	HostCount map[string]int
+}
+
+// Add adds the counts of the usage records to the ones of the same tenant, identity and day
+func (store *MockTenantUsageStore) Add(usage []hvs.TenantUsage) error {
+	for _, u := range usage {
+		found := false
+		for i := range store.Usage {
+			s := &store.Usage[i]
+			if s.TenantId == u.TenantId && s.Identity == u.Identity && s.Day.Equal(u.Day) {
+				s.ApiCalls += u.ApiCalls
+				s.ReportGenerations += u.ReportGenerations
+				found = true
+				break
+			}
+		}
+		if !found {
+			store.Usage = append(store.Usage, u)
+		}
+	}
+	return nil
+}
+
+// Search returns the usage records matching the TenantUsageFilterCriteria
+func (store *MockTenantUsageStore) Search(criteria *models.TenantUsageFilterCriteria) ([]hvs.TenantUsage, error) {
+	usage := []hvs.TenantUsage{}
+	for _, u := range store.Usage {
+		if criteria != nil {
+			if (criteria.TenantId != nil && u.TenantId != *criteria.TenantId) ||
+				(!criteria.FromDate.IsZero() && u.Day.Before(criteria.FromDate.UTC().Truncate(24*time.Hour))) ||
+				(!criteria.ToDate.IsZero() && u.Day.After(criteria.ToDate)) {
+				continue
+			}
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// HostCounts returns the host counts of the tenants
+func (store *MockTenantUsageStore) HostCounts() (map[string]int, error) {
+	hostCounts := map[string]int{}
+	for tenantId, count := range store.HostCount {
+		hostCounts[tenantId] = count
+	}
+	return hostCounts, nil
+}
+
+// NewFakeTenantUsageStore provides an empty MockTenantUsageStore
+func NewFakeTenantUsageStore() *MockTenantUsageStore {
+	return &MockTenantUsageStore{HostCount: map[string]int{}}
+}
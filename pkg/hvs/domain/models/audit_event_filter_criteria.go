@@ -0,0 +1,22 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEventFilterCriteria struct {
+	Actor      string
+	Method     string
+	EntityType string
+	EntityID   uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	Limit      int
+}
@@ -14,6 +14,9 @@ const (
 	CaCertTypesPrivacyCa     CaCertTypes = "privacy"
 	CaCertTypesAikCa         CaCertTypes = "aik" //privacy is used instead to store cert
 	CaCertTypesTagCa         CaCertTypes = "tag"
+	CaCertTypesPlatformCa    CaCertTypes = "platform"
+	// CaCertTypesRimCa are the CAs issuing the signers of reference integrity manifests
+	CaCertTypesRimCa CaCertTypes = "rim"
 )
 
 func (cct CaCertTypes) String() string {
@@ -27,7 +30,9 @@ func GetCaCertTypes() []CaCertTypes {
 		CaCertTypesEkCa,
 		CaCertTypesPrivacyCa,
 		CaCertTypesAikCa,
-		CaCertTypesTagCa}
+		CaCertTypesTagCa,
+		CaCertTypesPlatformCa,
+		CaCertTypesRimCa}
 }
 
 // CaCertTypes is an enumerated set of certificate types
@@ -37,6 +42,8 @@ const (
 	CertTypesSaml          CertTypes = "saml"
 	CertTypesTls           CertTypes = "tls"
 	CertTypesFlavorSigning CertTypes = "flavor-signing"
+	// CertTypesFlavorCoSigning are the certificates of the other trusted flavor signers
+	CertTypesFlavorCoSigning CertTypes = "flavor-co-signing"
 )
 
 func (ct CertTypes) String() string {
@@ -53,9 +60,12 @@ func GetUniqueCertTypes() []string {
 		CaCertTypesEndorsementCa.String(),
 		CaCertTypesPrivacyCa.String(),
 		CaCertTypesTagCa.String(),
+		CaCertTypesPlatformCa.String(),
+		CaCertTypesRimCa.String(),
 		CertTypesSaml.String(),
 		CertTypesTls.String(),
-		CertTypesFlavorSigning.String()}
+		CertTypesFlavorSigning.String(),
+		CertTypesFlavorCoSigning.String()}
 }
 
 // GetUniqueCertTypes returns a list of unique certificate types as strings
@@ -97,5 +107,7 @@ func IsValidDomainType(domain string) bool {
 		(domain == CaCertTypesRootCa.String() ||
 			domain == CaCertTypesEkCa.String() ||
 			domain == CaCertTypesEndorsementCa.String() ||
+			domain == CaCertTypesPlatformCa.String() ||
+			domain == CaCertTypesRimCa.String() ||
 			domain == CertTypesSaml.String())
 }
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"github.com/google/uuid"
 	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	fm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	hcType "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/pkg/errors"
 )
@@ -19,8 +21,19 @@ type FlavorCreateRequest struct {
 	ConnectionString       string                     `json:"connection_string,omitempty"`
 	FlavorCollection       hvs.FlavorCollection       `json:"flavor_collection,omitempty"`
 	SignedFlavorCollection hvs.SignedFlavorCollection `json:"signed_flavor_collection,omitempty"`
-	FlavorgroupNames       []string                   `json:"flavorgroup_names,omitempty"`
-	FlavorParts            []cf.FlavorPart            `json:"partial_flavor_types,omitempty"`
+	// FirmwareManifest holds the vendor published golden firmware measurements the PLATFORM flavor is built from
+	FirmwareManifest *fm.FirmwareManifest `json:"firmware_manifest,omitempty"`
+	// HostManifest holds the manifest of a good known host captured offline, the flavors are created from it as
+	// from the manifest retrieved with the connection string of the host
+	HostManifest     *hcType.HostManifest `json:"host_manifest,omitempty"`
+	FlavorgroupNames []string             `json:"flavorgroup_names,omitempty"`
+	FlavorParts      []cf.FlavorPart      `json:"partial_flavor_types,omitempty"`
+}
+
+// FlavorSignatureCreateRequest adds a flavor co-signer's signature of the flavor, the base64 encoded PKCS1 v1.5
+// signature of the flavor's SHA384 digest
+type FlavorSignatureCreateRequest struct {
+	Signature string `json:"signature"`
 }
 
 type FlavorFilterCriteria struct {
@@ -29,12 +42,17 @@ type FlavorFilterCriteria struct {
 	Value         string
 	FlavorgroupID uuid.UUID
 	FlavorParts   []cf.FlavorPart
+	MetadataKey   string
+	MetadataValue string
 }
 
 type FlavorVerificationFC struct {
 	FlavorFC              FlavorFilterCriteria
 	FlavorMeta            map[cf.FlavorPart][]FlavorMetaKv
 	FlavorPartsWithLatest map[cf.FlavorPart]bool
+	// InheritedFlavorgroups maps the flavor parts the flavorgroup of FlavorFC inherits to the ancestor
+	// flavorgroup the flavors of the part are searched in
+	InheritedFlavorgroups map[cf.FlavorPart]uuid.UUID
 }
 
 type FlavorMetaKv struct {
@@ -47,12 +65,14 @@ func (fcr FlavorCreateRequest) MarshalJSON() ([]byte, error) {
 		ConnectionString       string                     `json:"connection_string,omitempty"`
 		FlavorCollection       hvs.FlavorCollection       `json:"flavor_collection,omitempty"`
 		SignedFlavorCollection hvs.SignedFlavorCollection `json:"signed_flavor_collection,omitempty"`
+		HostManifest           *hcType.HostManifest       `json:"host_manifest,omitempty"`
 		FlavorgroupNames       []string                   `json:"flavorgroup_names,omitempty"`
 		FlavorParts            []cf.FlavorPart            `json:"partial_flavor_types,omitempty"`
 	}{
 		ConnectionString:       fcr.ConnectionString,
 		FlavorCollection:       fcr.FlavorCollection,
 		SignedFlavorCollection: fcr.SignedFlavorCollection,
+		HostManifest:           fcr.HostManifest,
 		FlavorgroupNames:       fcr.FlavorgroupNames,
 		FlavorParts:            fcr.FlavorParts,
 	})
@@ -60,7 +80,7 @@ func (fcr FlavorCreateRequest) MarshalJSON() ([]byte, error) {
 
 func (fcr *FlavorCreateRequest) UnmarshalJSON(b []byte) error {
 	//Validate the FlavorCreateRequest keys as here it is overridden with custom UnmarshalJSON decoder.DisallowUnknownFields doesnt work
-	validKeys := map[string]bool{"connection_string": true, "flavor_collection": true, "signed_flavor_collection": true, "flavorgroup_names": true, "partial_flavor_types": true}
+	validKeys := map[string]bool{"connection_string": true, "flavor_collection": true, "signed_flavor_collection": true, "host_manifest": true, "flavorgroup_names": true, "partial_flavor_types": true}
 	fcrKeysMap := map[string]interface{}{}
 	if err := json.Unmarshal(b, &fcrKeysMap); err != nil {
 		return err
@@ -75,6 +95,7 @@ func (fcr *FlavorCreateRequest) UnmarshalJSON(b []byte) error {
 		ConnectionString       string                     `json:"connection_string,omitempty"`
 		FlavorCollection       hvs.FlavorCollection       `json:"flavor_collection,omitempty"`
 		SignedFlavorCollection hvs.SignedFlavorCollection `json:"signed_flavor_collection,omitempty"`
+		HostManifest           *hcType.HostManifest       `json:"host_manifest,omitempty"`
 		FlavorgroupNames       []string                   `json:"flavorgroup_names,omitempty"`
 		FlavorParts            []cf.FlavorPart            `json:"partial_flavor_types,omitempty"`
 	})
@@ -84,6 +105,7 @@ func (fcr *FlavorCreateRequest) UnmarshalJSON(b []byte) error {
 		fcr.FlavorgroupNames = decoded.FlavorgroupNames
 		fcr.FlavorCollection = decoded.FlavorCollection
 		fcr.SignedFlavorCollection = decoded.SignedFlavorCollection
+		fcr.HostManifest = decoded.HostManifest
 		fcr.FlavorParts = decoded.FlavorParts
 	}
 	return err
@@ -0,0 +1,13 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import "github.com/google/uuid"
+
+type FlavorLearningFilterCriteria struct {
+	Id           uuid.UUID
+	LabelEqualTo string
+}
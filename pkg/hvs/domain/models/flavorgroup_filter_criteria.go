@@ -11,4 +11,6 @@ type FlavorGroupFilterCriteria struct {
 	FlavorId     *uuid.UUID
 	NameEqualTo  string
 	NameContains string
+	// ParentId restricts the flavorgroups to the ones inheriting directly from the flavorgroup
+	ParentId *uuid.UUID
 }
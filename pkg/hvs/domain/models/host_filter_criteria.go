@@ -7,6 +7,7 @@ package models
 import (
 	"errors"
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 )
 
 type HostFilterCriteria struct {
@@ -18,6 +19,7 @@ type HostFilterCriteria struct {
 	Value          string
 	IdList         []uuid.UUID
 	Trusted        *bool
+	Lifecycle      hvs.HostLifecycle
 	OrderBy        OrderType
 }
 
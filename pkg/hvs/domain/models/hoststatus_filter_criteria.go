@@ -6,6 +6,7 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"time"
 )
 
@@ -16,6 +17,7 @@ type HostStatusFilterCriteria struct {
 	HostHardwareId uuid.UUID
 	HostName       string
 	HostStatus     string
+	LifecycleState hvs.HostLifecycleState
 	FromDate       time.Time
 	ToDate         time.Time
 	LatestPerHost  bool
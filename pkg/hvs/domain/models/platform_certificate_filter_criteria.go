@@ -0,0 +1,18 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import "github.com/google/uuid"
+
+type PlatformCertificateFilterCriteria struct {
+	Id                       uuid.UUID
+	HardwareUuidEqualTo      uuid.UUID
+	ManufacturerEqualTo      string
+	ModelEqualTo             string
+	SerialEqualTo            string
+	RevokedEqualTo           bool
+	CertificateDigestEqualTo string
+}
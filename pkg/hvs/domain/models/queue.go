@@ -20,7 +20,9 @@ type QueueFilterCriteria struct {
 	ParamValue  string
 	ParamMap    map[string]string
 	QueueStates []QueueState
-	Limit       int
+	// UpdatedBefore filters the entries last updated before the time when set
+	UpdatedBefore time.Time
+	Limit         int
 }
 
 type QueueState int
@@ -34,6 +36,7 @@ const (
 	QueueStateTimeout
 	QueueStateConnectionFailure
 	QueueStateError
+	QueueStateInProgress
 )
 
 var qstatusToString = [...]string{
@@ -44,6 +47,7 @@ var qstatusToString = [...]string{
 	QueueStateTimeout:           "Timeout",
 	QueueStateConnectionFailure: "ConnectionFailure",
 	QueueStateError:             "Error",
+	QueueStateInProgress:        "InProgress",
 }
 
 var qstatusToID = map[string]QueueState{
@@ -54,6 +58,7 @@ var qstatusToID = map[string]QueueState{
 	"Timeout":           QueueStateTimeout,
 	"ConnectionFailure": QueueStateConnectionFailure,
 	"Error":             QueueStateError,
+	"InProgress":        QueueStateInProgress,
 }
 
 func (s *QueueState) Unmarshal(str string) {
@@ -65,7 +70,7 @@ func (s *QueueState) Unmarshal(str string) {
 }
 
 func (s QueueState) Valid() bool {
-	return s >= QueueStateNew && s <= QueueStateError
+	return s >= QueueStateNew && s <= QueueStateInProgress
 }
 
 // MarshalJSON marshals the enum as a quoted json string
@@ -20,7 +20,9 @@ type ReportFilterCriteria struct {
 	FromDate       time.Time
 	ToDate         time.Time
 	LatestPerHost  bool
-	Limit          int
+	// UntrustedFlavorPart selects the reports in which the flavor part is evaluated and not trusted
+	UntrustedFlavorPart string
+	Limit               int
 }
 
 type ReportLocator struct {
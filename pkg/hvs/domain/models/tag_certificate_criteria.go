@@ -23,6 +23,8 @@ type TagCertificateFilterCriteria struct {
 	ValidAfter      time.Time `json:"validAfter"`
 	// swagger:strfmt uuid
 	HardwareUUID uuid.UUID `json:"hardwareUuid"`
+	// RevokedEqualTo filters the revoked or the non revoked tag certificates when set
+	RevokedEqualTo *bool `json:"revokedEqualTo"`
 }
 
 // TagCertificateCreateCriteria holds the data used to create a TagCertificate
@@ -34,6 +36,12 @@ type TagCertificateCreateCriteria struct {
 	SelectionContent []asset_tag.TagKvAttribute `json:"selection_content,omitempty"`
 }
 
+// TagCertificateRevokeCriteria holds the data used to revoke a TagCertificate
+type TagCertificateRevokeCriteria struct {
+	// swagger:strfmt uuid
+	CertID uuid.UUID `json:"certificate_id,omitempty"`
+}
+
 // TagCertificateDeployCriteria holds the data used to deploy a TagCertificate onto a host
 type TagCertificateDeployCriteria struct {
 	// swagger:strfmt uuid
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package taskstage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timer records the time a job entered each stage. The job context carries the timer across the
+// host trust manager and host data fetcher so that the attestation latency can be broken down per stage.
+type Timer struct {
+	mtx     sync.Mutex
+	created time.Time
+	entered map[Stage]time.Time
+}
+
+const timerKey key = 1
+
+func NewTimerContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, timerKey, &Timer{created: time.Now(), entered: make(map[Stage]time.Time)})
+}
+
+func TimerFromContext(ctx context.Context) (*Timer, bool) {
+	t, ok := ctx.Value(timerKey).(*Timer)
+	return t, ok
+}
+
+// Created returns the time the job was queued
+func (t *Timer) Created() time.Time {
+	return t.created
+}
+
+// Enter records the time the job entered the stage
+func (t *Timer) Enter(stg Stage) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.entered[stg] = time.Now()
+}
+
+// Entered returns the time the job last entered the stage
+func (t *Timer) Entered(stg Stage) (time.Time, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	tm, ok := t.entered[stg]
+	return tm, ok
+}
@@ -38,6 +38,9 @@ func FromContext(ctx context.Context) (Stage, bool) {
 }
 
 func StoreInContext(ctx context.Context, stg Stage) bool {
+	if t, ok := TimerFromContext(ctx); ok {
+		t.Enter(stg)
+	}
 	if s, ok := ctx.Value(stageKey).(*Stage); !ok {
 		return ok
 	} else {
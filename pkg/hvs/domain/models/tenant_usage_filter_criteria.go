@@ -0,0 +1,14 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package models
+
+import "time"
+
+// TenantUsageFilterCriteria selects the usage of a tenant, or of all tenants when TenantId is nil, between two days
+type TenantUsageFilterCriteria struct {
+	TenantId *string
+	FromDate time.Time
+	ToDate   time.Time
+}
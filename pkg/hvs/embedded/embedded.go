@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package embedded exposes the verification pipeline of HVS as a library for single node appliances. It registers
+// hosts, creates flavors from their host manifests and verifies the hosts against their flavors without the HVS
+// service, its postgres database or the AAS and CMS services.
+package embedded
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+
+	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+type Config struct {
+	// DBFile is the SQLite database the hosts and flavors are stored in, they are kept in memory when it is empty
+	DBFile string
+	// Store overrides DBFile with a store provided by the appliance
+	Store Store
+
+	// HostConnectorProvider creates the connections to the hosts. When it is not set the trust agents are
+	// connected to with the AAS at AASApiUrl and the TLS certificates in TrustedCaCerts.
+	HostConnectorProvider hostConnector.HostConnectorProvider
+	AASApiUrl             string
+	TrustedCaCerts        []x509.Certificate
+
+	PrivacyCACertificates  *x509.CertPool
+	AssetTagCACertificates *x509.CertPool
+
+	// FlavorSigningKey signs the flavors created from the hosts, the flavors are verified with
+	// FlavorSigningCertificate which must be issued by one of FlavorCACertificates
+	FlavorSigningKey         *rsa.PrivateKey
+	FlavorSigningCertificate *x509.Certificate
+	FlavorCACertificates     *x509.CertPool
+}
+
+// HVS verifies the hosts registered to it against the flavors in its store
+type HVS struct {
+	store                 Store
+	hostConnectorProvider hostConnector.HostConnectorProvider
+	flavorVerifier        verifier.Verifier
+	flavorSigningKey      *rsa.PrivateKey
+}
+
+func New(cfg Config) (*HVS, error) {
+	defaultLog.Trace("embedded/embedded:New() Entering")
+	defer defaultLog.Trace("embedded/embedded:New() Leaving")
+
+	if cfg.FlavorSigningKey == nil {
+		return nil, errors.New("The flavor signing key cannot be nil")
+	}
+
+	assetTagCACertificates := cfg.AssetTagCACertificates
+	if assetTagCACertificates == nil {
+		assetTagCACertificates = x509.NewCertPool()
+	}
+
+	flavorVerifier, err := verifier.NewVerifier(verifier.VerifierCertificates{
+		PrivacyCACertificates:    cfg.PrivacyCACertificates,
+		AssetTagCACertificates:   assetTagCACertificates,
+		FlavorSigningCertificate: cfg.FlavorSigningCertificate,
+		FlavorCACertificates:     cfg.FlavorCACertificates,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the flavor verifier")
+	}
+
+	hostConnectorProvider := cfg.HostConnectorProvider
+	if hostConnectorProvider == nil {
+		hostConnectorProvider = hostConnector.NewHostConnectorFactory(cfg.AASApiUrl, cfg.TrustedCaCerts)
+	}
+
+	store := cfg.Store
+	if store == nil {
+		if cfg.DBFile == "" {
+			store = NewMemoryStore()
+		} else {
+			store, err = NewSqliteStore(cfg.DBFile)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error opening the SQLite store")
+			}
+		}
+	}
+
+	return &HVS{
+		store:                 store,
+		hostConnectorProvider: hostConnectorProvider,
+		flavorVerifier:        flavorVerifier,
+		flavorSigningKey:      cfg.FlavorSigningKey,
+	}, nil
+}
+
+// Close closes the store of the HVS
+func (h *HVS) Close() error {
+	return h.store.Close()
+}
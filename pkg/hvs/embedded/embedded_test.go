@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package embedded
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+type testHostConnectorProvider struct {
+	hostInfo     taModel.HostInfo
+	hostManifest types.HostManifest
+}
+
+func (p testHostConnectorProvider) NewHostConnector(string) (hostConnector.HostConnector, error) {
+	connector := &mocks.MockIntelConnector{}
+	connector.On("GetHostDetails").Return(p.hostInfo, nil)
+	connector.On("GetHostManifest").Return(p.hostManifest, nil)
+	return connector, nil
+}
+
+func newTestHostConnectorProvider(t *testing.T) testHostConnectorProvider {
+	var provider testHostConnectorProvider
+	hostInfoJson, err := ioutil.ReadFile("../../lib/host-connector/test/sample_platform_info.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(hostInfoJson, &provider.hostInfo))
+
+	hostManifestJson, err := ioutil.ReadFile("../../lib/host-connector/test/sample_host_manifest.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(hostManifestJson, &provider.hostManifest))
+	// the host is not trusted without an AIK certificate
+	provider.hostManifest.AIKCertificate = ""
+	return provider
+}
+
+func newTestHVS(t *testing.T, dbFile string) *HVS {
+	certDer, keyDer, err := crypt.CreateKeyPairAndCertificate("Flavor Signing", "", "rsa", 3072)
+	assert.NoError(t, err)
+	flavorSigningCert, err := x509.ParseCertificate(certDer)
+	assert.NoError(t, err)
+	flavorSigningKey, err := x509.ParsePKCS8PrivateKey(keyDer)
+	assert.NoError(t, err)
+
+	flavorCACertificates := x509.NewCertPool()
+	flavorCACertificates.AddCert(flavorSigningCert)
+
+	hvs, err := New(Config{
+		DBFile:                   dbFile,
+		HostConnectorProvider:    newTestHostConnectorProvider(t),
+		PrivacyCACertificates:    x509.NewCertPool(),
+		FlavorSigningKey:         flavorSigningKey.(*rsa.PrivateKey),
+		FlavorSigningCertificate: flavorSigningCert,
+		FlavorCACertificates:     flavorCACertificates,
+	})
+	if err != nil {
+		t.Fatal("Failed to create the embedded HVS", err)
+	}
+	return hvs
+}
+
+func TestNewWithoutFlavorSigningKey(t *testing.T) {
+	_, err := New(Config{PrivacyCACertificates: x509.NewCertPool()})
+	assert.Error(t, err)
+}
+
+func TestEmbeddedHVSPipeline(t *testing.T) {
+	hvs := newTestHVS(t, "")
+	defer hvs.Close()
+	testPipeline(t, hvs)
+}
+
+func TestEmbeddedHVSPipelineSqlite(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "embedded")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// the SQLite driver needs cgo
+	probe, err := NewSqliteStore(filepath.Join(tempDir, "probe.db"))
+	if err != nil && strings.Contains(err.Error(), "cgo") {
+		t.Skip("SQLite is not available in this build")
+	}
+	assert.NoError(t, err)
+	assert.NoError(t, probe.Close())
+	hvs := newTestHVS(t, filepath.Join(tempDir, "hvs.db"))
+	defer hvs.Close()
+	testPipeline(t, hvs)
+
+	flavors, err := hvs.store.SearchFlavors(cf.FlavorPartPlatform)
+	assert.NoError(t, err)
+	assert.Len(t, flavors, 1)
+	assert.Error(t, hvs.store.DeleteFlavor(uuid.New()))
+}
+
+func testPipeline(t *testing.T, hvs *HVS) {
+	host, err := hvs.RegisterHost(context.Background(), "host-1", "", "intel:https://ta.ip.com:1443")
+	assert.NoError(t, err)
+	assert.Equal(t, "e84df613-180c-49ca-b2c7-3e5517a3cfb5", host.HardwareUuid.String())
+
+	retrievedHost, err := hvs.RetrieveHost(host.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, host.HostName, retrievedHost.HostName)
+
+	signedFlavors, err := hvs.ImportFlavors(context.Background(), host.Id, []cf.FlavorPart{cf.FlavorPartPlatform, cf.FlavorPartOs})
+	assert.NoError(t, err)
+	assert.Len(t, signedFlavors, 2)
+
+	flavor, err := hvs.RetrieveFlavor(signedFlavors[0].Flavor.Meta.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, signedFlavors[0].Signature, flavor.Signature)
+
+	trustReport, err := hvs.Verify(context.Background(), host.Id)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, trustReport.Results)
+	assert.NotEmpty(t, trustReport.GetResultsForMarker(cf.FlavorPartPlatform.String()))
+	assert.NotEmpty(t, trustReport.GetResultsForMarker(cf.FlavorPartOs.String()))
+	assert.False(t, trustReport.Trusted)
+
+	assert.NoError(t, hvs.DeleteHost(host.Id))
+	_, err = hvs.Verify(context.Background(), host.Id)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), commErr.RowsNotFound))
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.RetrieveHost(uuid.New())
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), commErr.RowsNotFound))
+
+	flavors, err := store.SearchFlavors(cf.FlavorPartPlatform)
+	assert.NoError(t, err)
+	assert.Empty(t, flavors)
+
+	assert.Error(t, store.DeleteFlavor(uuid.New()))
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package embedded
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	fu "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// verifiedFlavorParts are the flavor parts a host is verified against, in the order of the report results
+var verifiedFlavorParts = []cf.FlavorPart{cf.FlavorPartPlatform, cf.FlavorPartOs, cf.FlavorPartHostUnique,
+	cf.FlavorPartSoftware, cf.FlavorPartAssetTag, cf.FlavorPartContainerImage}
+
+// RegisterHost connects to the host to retrieve its hardware uuid and stores it
+func (h *HVS) RegisterHost(ctx context.Context, hostName, description, connectionString string) (*hvs.Host, error) {
+	defaultLog.Trace("embedded/pipeline:RegisterHost() Entering")
+	defer defaultLog.Trace("embedded/pipeline:RegisterHost() Leaving")
+
+	connector, err := h.hostConnectorProvider.NewHostConnector(connectionString)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not instantiate host connector")
+	}
+
+	hostInfo, err := connector.GetHostDetails(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error retrieving the host details")
+	}
+	hardwareUuid, err := uuid.Parse(hostInfo.HardwareUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid hardware uuid in the host details")
+	}
+
+	host := hvs.Host{
+		Id:               uuid.New(),
+		HostName:         hostName,
+		Description:      description,
+		ConnectionString: connectionString,
+		HardwareUuid:     &hardwareUuid,
+	}
+	if err = h.store.CreateHost(&host); err != nil {
+		return nil, errors.Wrap(err, "Error storing the host")
+	}
+	return &host, nil
+}
+
+func (h *HVS) RetrieveHost(hostId uuid.UUID) (*hvs.Host, error) {
+	return h.store.RetrieveHost(hostId)
+}
+
+func (h *HVS) DeleteHost(hostId uuid.UUID) error {
+	return h.store.DeleteHost(hostId)
+}
+
+// ImportFlavors creates the flavors of the flavor parts from the host manifest of a registered host, signs them
+// and stores them. All the flavor parts the host supports are created when flavorParts is empty.
+func (h *HVS) ImportFlavors(ctx context.Context, hostId uuid.UUID, flavorParts []cf.FlavorPart) ([]hvs.SignedFlavor, error) {
+	defaultLog.Trace("embedded/pipeline:ImportFlavors() Entering")
+	defer defaultLog.Trace("embedded/pipeline:ImportFlavors() Leaving")
+
+	_, hostManifest, err := h.getHostManifest(ctx, hostId)
+	if err != nil {
+		return nil, err
+	}
+
+	flavorProvider, err := flavor.NewPlatformFlavorProvider(hostManifest, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error instantiating the platform flavor provider")
+	}
+	platformFlavor, err := flavorProvider.GetPlatformFlavor()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the platform flavor")
+	}
+
+	if len(flavorParts) == 0 {
+		flavorParts, err = (*platformFlavor).GetFlavorPartNames()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error retrieving the flavor parts of the host")
+		}
+	}
+
+	var signedFlavors []hvs.SignedFlavor
+	for _, flavorPart := range flavorParts {
+		unsignedFlavors, err := (*platformFlavor).GetFlavorPartRaw(flavorPart)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error building a flavor for flavor part %s", flavorPart)
+		}
+
+		partSignedFlavors, err := fu.PlatformFlavorUtil{}.GetSignedFlavorList(unsignedFlavors, h.flavorSigningKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error signing the flavors of flavor part %s", flavorPart)
+		}
+		signedFlavors = append(signedFlavors, partSignedFlavors...)
+	}
+
+	for i := range signedFlavors {
+		if err = h.store.CreateFlavor(&signedFlavors[i]); err != nil {
+			return nil, errors.Wrapf(err, "Error storing flavor %s", signedFlavors[i].Flavor.Meta.ID)
+		}
+	}
+	return signedFlavors, nil
+}
+
+// CreateFlavor stores a flavor created outside of the embedded HVS, it must be signed by the flavor signing key
+func (h *HVS) CreateFlavor(signedFlavor *hvs.SignedFlavor) error {
+	var flavorPart cf.FlavorPart
+	if err := (&flavorPart).Parse(signedFlavor.Flavor.Meta.Description.FlavorPart); err != nil {
+		return errors.Wrap(err, "Invalid flavor part")
+	}
+	if signedFlavor.Flavor.Meta.ID == uuid.Nil {
+		return errors.New("Invalid flavor id")
+	}
+	return h.store.CreateFlavor(signedFlavor)
+}
+
+func (h *HVS) RetrieveFlavor(flavorId uuid.UUID) (*hvs.SignedFlavor, error) {
+	return h.store.RetrieveFlavor(flavorId)
+}
+
+func (h *HVS) DeleteFlavor(flavorId uuid.UUID) error {
+	return h.store.DeleteFlavor(flavorId)
+}
+
+// Verify retrieves the host manifest of a registered host and verifies it against the stored flavors. A flavor part
+// is trusted when the host matches any of its flavors, the HOST_UNIQUE and ASSET_TAG flavors are only matched against
+// the host they were created for. The host is trusted when all the flavor parts with flavors are trusted.
+func (h *HVS) Verify(ctx context.Context, hostId uuid.UUID) (*hvs.TrustReport, error) {
+	defaultLog.Trace("embedded/pipeline:Verify() Entering")
+	defer defaultLog.Trace("embedded/pipeline:Verify() Leaving")
+
+	host, hostManifest, err := h.getHostManifest(ctx, hostId)
+	if err != nil {
+		return nil, err
+	}
+
+	trustReport := hvs.TrustReport{
+		HostManifest: *hostManifest,
+	}
+	for _, flavorPart := range verifiedFlavorParts {
+		signedFlavors, err := h.store.SearchFlavors(flavorPart)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error searching the flavors of flavor part %s", flavorPart)
+		}
+
+		var partReport *hvs.TrustReport
+		for i := range signedFlavors {
+			if !flavorMatchesHost(&signedFlavors[i], flavorPart, host) {
+				continue
+			}
+
+			report, err := h.flavorVerifier.Verify(hostManifest, &signedFlavors[i], false)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error verifying the host against flavor %s", signedFlavors[i].Flavor.Meta.ID)
+			}
+			// the results of the first flavor are reported when none of the flavors is trusted
+			if partReport == nil || report.Trusted {
+				partReport = report
+			}
+			if report.Trusted {
+				break
+			}
+		}
+
+		if partReport != nil {
+			if trustReport.PolicyName == "" {
+				trustReport.PolicyName = partReport.PolicyName
+			}
+			trustReport.AddResults(partReport.Results)
+		}
+	}
+
+	trustReport.Trusted = trustReport.IsTrusted()
+	return &trustReport, nil
+}
+
+func (h *HVS) getHostManifest(ctx context.Context, hostId uuid.UUID) (*hvs.Host, *types.HostManifest, error) {
+	host, err := h.store.RetrieveHost(hostId)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Error retrieving host %s", hostId)
+	}
+
+	var connector hostConnector.HostConnector
+	connector, err = h.hostConnectorProvider.NewHostConnector(host.ConnectionString)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Could not instantiate host connector")
+	}
+
+	hostManifest, err := connector.GetHostManifest(ctx, nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Error retrieving the host manifest of host %s", hostId)
+	}
+	return host, &hostManifest, nil
+}
+
+func flavorMatchesHost(signedFlavor *hvs.SignedFlavor, flavorPart cf.FlavorPart, host *hvs.Host) bool {
+	if flavorPart != cf.FlavorPartHostUnique && flavorPart != cf.FlavorPartAssetTag {
+		return true
+	}
+	hardwareUuid := signedFlavor.Flavor.Meta.Description.HardwareUUID
+	return hardwareUuid != nil && host.HardwareUuid != nil && *hardwareUuid == *host.HardwareUuid
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package embedded
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// sqliteStore keeps the hosts and the flavors in the schema of the HVS service, through the SQLite dialect of its
+// stores, so that the database of an appliance can be read by the standalone HVS
+type sqliteStore struct {
+	dataStore   *postgres.DataStore
+	hostStore   *postgres.HostStore
+	flavorStore *postgres.FlavorStore
+}
+
+// NewSqliteStore opens the SQLite database in dbFile, the file is created if it does not exist. The connection
+// strings of the hosts are stored in the database, so the file is only readable by its owner.
+func NewSqliteStore(dbFile string) (Store, error) {
+	defaultLog.Trace("embedded/sqlite_store:NewSqliteStore() Entering")
+	defer defaultLog.Trace("embedded/sqlite_store:NewSqliteStore() Leaving")
+
+	dataStore, err := postgres.New(&postgres.Config{
+		Vendor:            constants.DBTypeSQLite,
+		Dbname:            dbFile,
+		ConnRetryAttempts: 1,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening the database file %s", dbFile)
+	}
+	if err = dataStore.Migrate(); err != nil {
+		dataStore.Close()
+		return nil, errors.Wrap(err, "Error migrating the database")
+	}
+	return &sqliteStore{
+		dataStore:   dataStore,
+		hostStore:   postgres.NewHostStore(dataStore),
+		flavorStore: postgres.NewFlavorStore(dataStore),
+	}, nil
+}
+
+// CreateHost stores the host, the host store of HVS gives it a new id
+func (s *sqliteStore) CreateHost(host *hvs.Host) error {
+	if _, err := s.hostStore.Create(host); err != nil {
+		return errors.Wrap(err, "Failed to create host")
+	}
+	return nil
+}
+
+func (s *sqliteStore) RetrieveHost(id uuid.UUID) (*hvs.Host, error) {
+	host, err := s.hostStore.Retrieve(id, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve host")
+	}
+	return host, nil
+}
+
+func (s *sqliteStore) DeleteHost(id uuid.UUID) error {
+	// the host store does not report the hosts that do not exist
+	if _, err := s.RetrieveHost(id); err != nil {
+		return err
+	}
+	if err := s.hostStore.Delete(id); err != nil {
+		return errors.Wrap(err, "Failed to delete host")
+	}
+	return nil
+}
+
+func (s *sqliteStore) CreateFlavor(signedFlavor *hvs.SignedFlavor) error {
+	if _, err := s.flavorStore.Create(signedFlavor); err != nil {
+		return errors.Wrap(err, "Failed to create flavor")
+	}
+	return nil
+}
+
+func (s *sqliteStore) RetrieveFlavor(id uuid.UUID) (*hvs.SignedFlavor, error) {
+	signedFlavor, err := s.flavorStore.Retrieve(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve flavor")
+	}
+	return signedFlavor, nil
+}
+
+func (s *sqliteStore) DeleteFlavor(id uuid.UUID) error {
+	// the flavor store does not report the flavors that do not exist
+	if _, err := s.RetrieveFlavor(id); err != nil {
+		return err
+	}
+	if err := s.flavorStore.Delete(id); err != nil {
+		return errors.Wrap(err, "Failed to delete flavor")
+	}
+	return nil
+}
+
+// SearchFlavors reads the flavor table directly, the flavors of the embedded HVS are not linked to flavorgroups
+// which the flavor part searches of the flavor store go through
+func (s *sqliteStore) SearchFlavors(flavorPart cf.FlavorPart) ([]hvs.SignedFlavor, error) {
+	rows, err := s.dataStore.Db.Table("flavor").Select("content, signature, additional_signatures").
+		Where("flavor_part = ?", flavorPart.String()).Order("created_at").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to search flavors")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	var signedFlavors []hvs.SignedFlavor
+	for rows.Next() {
+		signedFlavor := hvs.SignedFlavor{}
+		if err := rows.Scan((*postgres.PGFlavorContent)(&signedFlavor.Flavor), &signedFlavor.Signature,
+			(*postgres.PGFlavorSignatures)(&signedFlavor.AdditionalSignatures)); err != nil {
+			return nil, errors.Wrap(err, "Failed to scan flavor")
+		}
+		signedFlavors = append(signedFlavors, signedFlavor)
+	}
+	return signedFlavors, rows.Err()
+}
+
+func (s *sqliteStore) Close() error {
+	s.dataStore.Close()
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package embedded
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// Store keeps the hosts and the flavors of the embedded HVS. The Retrieve methods return an error containing
+// commErr.RowsNotFound when there is no record with the id.
+type Store interface {
+	CreateHost(*hvs.Host) error
+	RetrieveHost(uuid.UUID) (*hvs.Host, error)
+	DeleteHost(uuid.UUID) error
+	CreateFlavor(*hvs.SignedFlavor) error
+	RetrieveFlavor(uuid.UUID) (*hvs.SignedFlavor, error)
+	DeleteFlavor(uuid.UUID) error
+	// SearchFlavors returns the flavors of a flavor part in the order they were created
+	SearchFlavors(cf.FlavorPart) ([]hvs.SignedFlavor, error)
+	Close() error
+}
+
+type memoryStore struct {
+	mutex   sync.RWMutex
+	hosts   map[uuid.UUID]hvs.Host
+	flavors []hvs.SignedFlavor
+}
+
+// NewMemoryStore returns a Store that keeps the hosts and flavors in memory only
+func NewMemoryStore() Store {
+	return &memoryStore{
+		hosts: make(map[uuid.UUID]hvs.Host),
+	}
+}
+
+func (s *memoryStore) CreateHost(host *hvs.Host) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.hosts[host.Id]; ok {
+		return errors.Errorf("Host with id %s already exists", host.Id)
+	}
+	s.hosts[host.Id] = *host
+	return nil
+}
+
+func (s *memoryStore) RetrieveHost(id uuid.UUID) (*hvs.Host, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	host, ok := s.hosts[id]
+	if !ok {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return &host, nil
+}
+
+func (s *memoryStore) DeleteHost(id uuid.UUID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.hosts[id]; !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	delete(s.hosts, id)
+	return nil
+}
+
+func (s *memoryStore) CreateFlavor(signedFlavor *hvs.SignedFlavor) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, flavor := range s.flavors {
+		if flavor.Flavor.Meta.ID == signedFlavor.Flavor.Meta.ID {
+			return errors.Errorf("Flavor with id %s already exists", signedFlavor.Flavor.Meta.ID)
+		}
+	}
+	s.flavors = append(s.flavors, *signedFlavor)
+	return nil
+}
+
+func (s *memoryStore) RetrieveFlavor(id uuid.UUID) (*hvs.SignedFlavor, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, flavor := range s.flavors {
+		if flavor.Flavor.Meta.ID == id {
+			return &flavor, nil
+		}
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+func (s *memoryStore) DeleteFlavor(id uuid.UUID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, flavor := range s.flavors {
+		if flavor.Flavor.Meta.ID == id {
+			s.flavors = append(s.flavors[:i], s.flavors[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New(commErr.RowsNotFound)
+}
+
+func (s *memoryStore) SearchFlavors(flavorPart cf.FlavorPart) ([]hvs.SignedFlavor, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var flavors []hvs.SignedFlavor
+	for _, flavor := range s.flavors {
+		if flavor.Flavor.Meta.Description.FlavorPart == flavorPart.String() {
+			flavors = append(flavors, flavor)
+		}
+	}
+	return flavors, nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
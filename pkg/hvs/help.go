@@ -7,7 +7,9 @@ package hvs
 import (
 	"fmt"
 
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/version"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 )
 
 const helpStr = `Usage:
@@ -17,6 +19,8 @@ Available Commands:
 	help|-h|--help         Show this help message
 	version|-v|--version   Show the version of current hvs build
 	setup <task>           Run setup task
+	run [--standalone]     Run hvs in the foreground
+		--standalone       the data is kept in the local SQLite database /opt/hvs/hvs.db, no database server is needed (env: HVS_STANDALONE)
 	start                  Start hvs
 	status                 Show the status of hvs
 	stop                   Stop hvs
@@ -24,12 +28,23 @@ Available Commands:
 	config-db-rotation     Configure database table rotaition for audit log table, reference db_rotation.sql in documents
 	uninstall [--purge]    Uninstall hvs
 		--purge            all configuration and data files will be removed if this flag is set
+	completion <bash|zsh>  Print the shell completion script, load it with: source <(hvs completion bash)
+
+Global Flags:
+	--output <text|json>   output format of the version and status commands (env: HVS_OUTPUT)
 
 Usage of hvs setup:
-	hvs setup <task> [--help] [--force] [-f <answer-file>]
+	hvs setup <task> [--help] [--force] [-f <answer-file>] [--interactive] [--fix-drift]
+	hvs setup --interactive
+	hvs setup --fix-drift
 		--help                      show help message for setup task
 		--force                     existing configuration will be overwritten if this flag is set
 		-f|--file <answer-file>     the answer file with required arguments
+		--interactive               prompt for the required arguments not in the answer file or environment,
+		                            all setup tasks are run if no task is given
+		--fix-drift                 run again the tasks whose configuration has drifted, like expired or
+		                            reissued certificates or missing database tables, all tasks are checked
+		                            if no task is given
 
 Available Tasks for setup:
 	all                             Runs all setup tasks
@@ -55,6 +70,11 @@ func (a *App) printUsageWithError(err error) {
 	fmt.Fprintln(a.errorWriter(), helpStr)
 }
 
-func (a *App) printVersion() {
-	fmt.Fprintf(a.consoleWriter(), version.GetVersion())
+func (a *App) printVersion(ctx *commCmd.Context) error {
+	return ctx.Print(version.GetVersion(), commCmd.VersionInfo{
+		ServiceName: constants.ExplicitServiceName,
+		Version:     version.Version,
+		GitHash:     version.GitHash,
+		BuildDate:   version.BuildDate,
+	})
 }
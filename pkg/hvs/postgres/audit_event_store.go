@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type AuditEventStore struct {
+	Store *DataStore
+}
+
+func NewAuditEventStore(store *DataStore) *AuditEventStore {
+	return &AuditEventStore{store}
+}
+
+func (a *AuditEventStore) Create(ae *hvs.AuditEvent) (*hvs.AuditEvent, error) {
+	defaultLog.Trace("postgres/audit_event_store:Create() Entering")
+	defer defaultLog.Trace("postgres/audit_event_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/audit_event_store:Create() failed to create new UUID")
+	}
+	ae.ID = newUuid
+	if ae.Created.IsZero() {
+		ae.Created = time.Now().UTC()
+	}
+
+	dbAuditEvent := auditEvent{
+		ID:         ae.ID,
+		CreatedAt:  ae.Created,
+		Actor:      ae.Actor,
+		Method:     ae.Method,
+		EntityType: ae.EntityType,
+		EntityID:   ae.EntityID,
+		Content:    PGAuditEvent(*ae),
+	}
+	if err := a.Store.Db.Create(&dbAuditEvent).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/audit_event_store:Create() failed to create AuditEvent")
+	}
+	return ae, nil
+}
+
+// Search returns the audit events matching the filter criteria, the most recent first
+func (a *AuditEventStore) Search(aeFilter *models.AuditEventFilterCriteria) (*hvs.AuditEventCollection, error) {
+	defaultLog.Trace("postgres/audit_event_store:Search() Entering")
+	defer defaultLog.Trace("postgres/audit_event_store:Search() Leaving")
+
+	tx := buildAuditEventSearchQuery(a.Store.Db, aeFilter)
+	if tx == nil {
+		return nil, errors.New("postgres/audit_event_store:Search() Unexpected Error. Could not build" +
+			" a gorm query object in AuditEvent Search function.")
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/audit_event_store:Search() failed to retrieve audit_event from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	auditEventCollection := hvs.AuditEventCollection{AuditEvents: []*hvs.AuditEvent{}}
+	for rows.Next() {
+		ae := hvs.AuditEvent{}
+		if err := rows.Scan((*PGAuditEvent)(&ae)); err != nil {
+			return nil, errors.Wrap(err, "postgres/audit_event_store:Search() - Could not scan record ")
+		}
+		auditEventCollection.AuditEvents = append(auditEventCollection.AuditEvents, &ae)
+	}
+	return &auditEventCollection, nil
+}
+
+func buildAuditEventSearchQuery(tx *gorm.DB, aeFilter *models.AuditEventFilterCriteria) *gorm.DB {
+	defaultLog.Trace("postgres/audit_event_store:buildAuditEventSearchQuery() Entering")
+	defer defaultLog.Trace("postgres/audit_event_store:buildAuditEventSearchQuery() Leaving")
+
+	if tx == nil {
+		return nil
+	}
+	tx = tx.Model(&auditEvent{}).Select("content").Order("created desc")
+	if aeFilter == nil {
+		defaultLog.Info("postgres/audit_event_store:buildAuditEventSearchQuery() No criteria specified in search query" +
+			". Returning all rows.")
+		return tx
+	}
+	if aeFilter.Actor != "" {
+		tx = tx.Where("actor = ?", aeFilter.Actor)
+	}
+	if aeFilter.Method != "" {
+		tx = tx.Where("method = ?", aeFilter.Method)
+	}
+	if aeFilter.EntityType != "" {
+		tx = tx.Where("entity_type = ?", aeFilter.EntityType)
+	}
+	if aeFilter.EntityID != uuid.Nil {
+		tx = tx.Where("entity_id = ?", aeFilter.EntityID)
+	}
+	if !aeFilter.FromDate.IsZero() {
+		tx = tx.Where("created >= ?", aeFilter.FromDate)
+	}
+	if !aeFilter.ToDate.IsZero() {
+		tx = tx.Where("created <= ?", aeFilter.ToDate)
+	}
+	if aeFilter.Limit > 0 {
+		tx = tx.Limit(aeFilter.Limit)
+	}
+	return tx
+}
@@ -14,9 +14,8 @@ func InitDatabase(cfg *commConfig.DBConfig) (*DataStore, error) {
 	defaultLog.Trace("postgres/database:InitDatabase() Entering")
 	defer defaultLog.Trace("postgres/database:InitDatabase() Leaving")
 
-	// Create conf for DBTypePostgres
 	conf := Config{
-		Vendor:            constants.DBTypePostgres,
+		Vendor:            cfg.Vendor,
 		Host:              cfg.Host,
 		Port:              cfg.Port,
 		User:              cfg.Username,
@@ -28,19 +27,22 @@ func InitDatabase(cfg *commConfig.DBConfig) (*DataStore, error) {
 		ConnRetryTime:     cfg.ConnectionRetryTime,
 	}
 
-	// Creates a DBTypePostgres DB instance
+	// Creates a DB instance of the configured vendor
 	dataStore, err := NewDataStore(&conf)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error instantiating Database")
 	}
 	defaultLog.Info("Migrating Database")
-	dataStore.Migrate()
+	if err = dataStore.Migrate(); err != nil {
+		return nil, errors.Wrap(err, "Error migrating Database")
+	}
 
 	return dataStore, nil
 }
 
 func NewDataStore(config *Config) (*DataStore, error) {
-	if config.Vendor == constants.DBTypePostgres {
+	if config.Vendor == "" || config.Vendor == constants.DBTypePostgres || config.Vendor == constants.DBTypeMySQL ||
+		config.Vendor == constants.DBTypeSQLite {
 		return New(config)
 	}
 	return nil, errors.Errorf("Unsupported database vendor")
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/jinzhu/gorm"
+)
+
+// sqlDialect renders the parts of the HVS queries that differ between the supported database vendors. The stores
+// are written against postgres, the dialect of the connection is used wherever the SQL is vendor specific.
+type sqlDialect interface {
+	// connectionString returns the data source name gorm connects to the database of cfg with
+	connectionString(cfg *Config) (string, error)
+	// migrate creates the HVS tables, or updates them to the current schema
+	migrate(db *gorm.DB) error
+
+	// jsonText returns the expression selecting the text at path in a JSON column, int path elements index arrays
+	jsonText(column string, path ...interface{}) string
+	// jsonTextAtParam returns jsonText for a path ending with a key bound as a query parameter
+	jsonTextAtParam(column string, path ...interface{}) string
+	// jsonNotNull returns the condition matching the rows whose JSON column is not a JSON null
+	jsonNotNull(column string) string
+	// jsonSet returns the expression setting the top level key of a JSON column to the JSON bound as a query parameter
+	jsonSet(column, key string) string
+
+	castToText(expr string) string
+	castToUUID(expr string) string
+	castToTimestamp(expr string) string
+
+	// insertIgnore returns the statement inserting the values into the table, skipping the rows conflicting on the
+	// unique key made of the conflict columns
+	insertIgnore(table, values string, conflictColumns ...string) string
+	// isDuplicateKeyError tells if err is the violation of a unique key
+	isDuplicateKeyError(err error) bool
+}
+
+// dialects are the supported database vendors, the names match the gorm dialect names
+var dialects = map[string]sqlDialect{
+	constants.DBTypePostgres: postgresDialect{},
+	constants.DBTypeMySQL:    mysqlDialect{},
+	constants.DBTypeSQLite:   sqliteDialect{},
+}
+
+// dialectOf returns the dialect of the database tx is connected to
+func dialectOf(tx *gorm.DB) sqlDialect {
+	if tx != nil {
+		if d, ok := dialects[tx.Dialect().GetName()]; ok {
+			return d
+		}
+	}
+	return postgresDialect{}
+}
+
+// IsDuplicateKeyError tells if err, returned by one of the stores, is the violation of a unique key
+func IsDuplicateKeyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, d := range dialects {
+		if d.isDuplicateKeyError(err) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type FlavorLearningStore struct {
+	Store *DataStore
+}
+
+func NewFlavorLearningStore(store *DataStore) *FlavorLearningStore {
+	return &FlavorLearningStore{store}
+}
+
+func (f *FlavorLearningStore) Create(fl *hvs.FlavorLearning) (*hvs.FlavorLearning, error) {
+	defaultLog.Trace("postgres/flavor_learning_store:Create() Entering")
+	defer defaultLog.Trace("postgres/flavor_learning_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_learning_store:Create() failed to create new UUID")
+	}
+	fl.ID = newUuid
+
+	dbFlavorLearning := flavorLearning{
+		ID:      fl.ID,
+		Label:   fl.Label,
+		Content: PGFlavorLearning(*fl),
+	}
+
+	if err := f.Store.Db.Create(&dbFlavorLearning).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_learning_store:Create() failed to create FlavorLearning")
+	}
+	return fl, nil
+}
+
+func (f *FlavorLearningStore) Retrieve(id uuid.UUID) (*hvs.FlavorLearning, error) {
+	defaultLog.Trace("postgres/flavor_learning_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/flavor_learning_store:Retrieve() Leaving")
+
+	fl := hvs.FlavorLearning{}
+	row := f.Store.Db.Model(flavorLearning{}).Select("content").Where(flavorLearning{ID: id}).Row()
+	if err := row.Scan((*PGFlavorLearning)(&fl)); err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_learning_store:Retrieve() - Could not scan record ")
+	}
+	return &fl, nil
+}
+
+func (f *FlavorLearningStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/flavor_learning_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/flavor_learning_store:Delete() Leaving")
+
+	if err := f.Store.Db.Delete(&flavorLearning{ID: id}).Error; err != nil {
+		return errors.Wrap(err, "postgres/flavor_learning_store:Delete() failed to delete FlavorLearning")
+	}
+	return nil
+}
+
+func (f *FlavorLearningStore) Search(flFilter *models.FlavorLearningFilterCriteria) (*hvs.FlavorLearningCollection, error) {
+	defaultLog.Trace("postgres/flavor_learning_store:Search() Entering")
+	defer defaultLog.Trace("postgres/flavor_learning_store:Search() Leaving")
+
+	tx := buildFlavorLearningSearchQuery(f.Store.Db, flFilter)
+	if tx == nil {
+		return nil, errors.New("postgres/flavor_learning_store:Search() Unexpected Error. Could not build" +
+			" a gorm query object in FlavorLearning Search function.")
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_learning_store:Search() failed to retrieve flavor_learning from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	flavorLearningCollection := hvs.FlavorLearningCollection{FlavorLearnings: []*hvs.FlavorLearning{}}
+	for rows.Next() {
+		fl := hvs.FlavorLearning{}
+		if err := rows.Scan((*PGFlavorLearning)(&fl)); err != nil {
+			return nil, errors.Wrap(err, "postgres/flavor_learning_store:Search() - Could not scan record ")
+		}
+		flavorLearningCollection.FlavorLearnings = append(flavorLearningCollection.FlavorLearnings, &fl)
+	}
+	return &flavorLearningCollection, nil
+}
+
+func buildFlavorLearningSearchQuery(tx *gorm.DB, flFilter *models.FlavorLearningFilterCriteria) *gorm.DB {
+	defaultLog.Trace("postgres/flavor_learning_store:buildFlavorLearningSearchQuery() Entering")
+	defer defaultLog.Trace("postgres/flavor_learning_store:buildFlavorLearningSearchQuery() Leaving")
+
+	if tx == nil {
+		return nil
+	}
+	tx = tx.Model(&flavorLearning{}).Select("content")
+	if flFilter == nil {
+		defaultLog.Info("postgres/flavor_learning_store:buildFlavorLearningSearchQuery() No criteria specified in search query" +
+			". Returning all rows.")
+		return tx
+	}
+	if flFilter.Id != uuid.Nil {
+		tx = tx.Where("id = ?", flFilter.Id)
+	}
+	if flFilter.LabelEqualTo != "" {
+		tx = tx.Where("label = ?", flFilter.LabelEqualTo)
+	}
+	return tx
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	fu "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+type FlavorPruneStore struct {
+	Store    *DataStore
+	tenantId *string
+}
+
+func NewFlavorPruneStore(store *DataStore) *FlavorPruneStore {
+	return &FlavorPruneStore{Store: store}
+}
+
+// ForTenant returns a view of the store restricted to the flavors and the prunes of the tenant
+func (f *FlavorPruneStore) ForTenant(tenantId string) domain.FlavorPruneStore {
+	return &FlavorPruneStore{Store: f.Store, tenantId: &tenantId}
+}
+
+// SearchFlavorVersions returns the flavors of the flavor parts with their family, creation time and flavorgroups
+func (f *FlavorPruneStore) SearchFlavorVersions(flavorParts []fc.FlavorPart) ([]hvs.FlavorVersion, error) {
+	defaultLog.Trace("postgres/flavor_prune_store:SearchFlavorVersions() Entering")
+	defer defaultLog.Trace("postgres/flavor_prune_store:SearchFlavorVersions() Leaving")
+
+	var parts []string
+	for _, flavorPart := range flavorParts {
+		parts = append(parts, flavorPart.String())
+	}
+	rows, err := scopeToTenant(f.Store.Db.Model(&flavor{}), "tenant_id", f.tenantId).
+		Select("id, content, created_at, label, flavor_part").Where("flavor_part IN (?)", parts).Order("created_at").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:SearchFlavorVersions() failed to retrieve flavors")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	var pruner fu.FlavorPruner
+	var versions []hvs.FlavorVersion
+	versionIndex := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var version hvs.FlavorVersion
+		var content hvs.Flavor
+		if err := rows.Scan(&version.FlavorID, (*PGFlavorContent)(&content), &version.Created, &version.Label, &version.FlavorPart); err != nil {
+			return nil, errors.Wrap(err, "postgres/flavor_prune_store:SearchFlavorVersions() failed to scan record")
+		}
+		version.Family = pruner.GetFlavorFamily(&content)
+		versionIndex[version.FlavorID] = len(versions)
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return versions, nil
+	}
+
+	var links []flavorgroupFlavor
+	if err := f.Store.Db.Where("flavor_id IN (?)", keysOf(versionIndex)).Find(&links).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:SearchFlavorVersions() failed to retrieve flavorgroup-flavor associations")
+	}
+	for _, link := range links {
+		version := &versions[versionIndex[link.FlavorId]]
+		version.FlavorgroupIDs = append(version.FlavorgroupIDs, link.FlavorgroupId)
+	}
+	return versions, nil
+}
+
+// Create deletes the retired flavors of the prune and records them with the prune, so that they can be restored
+func (f *FlavorPruneStore) Create(fp *hvs.FlavorPrune) (*hvs.FlavorPrune, error) {
+	defaultLog.Trace("postgres/flavor_prune_store:Create() Entering")
+	defer defaultLog.Trace("postgres/flavor_prune_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Create() failed to create new UUID")
+	}
+	fp.ID = newUuid
+	if fp.Created.IsZero() {
+		fp.Created = time.Now().UTC()
+	}
+
+	tx := f.Store.Db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "postgres/flavor_prune_store:Create() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	var retiredFlavors PGRetiredFlavors
+	for _, version := range fp.RetiredFlavors {
+		var dbFlavor flavor
+		if err := scopeToTenant(tx, "tenant_id", f.tenantId).Where(&flavor{ID: version.FlavorID}).First(&dbFlavor).Error; err != nil {
+			return nil, errors.Wrapf(err, "postgres/flavor_prune_store:Create() failed to retrieve flavor %s", version.FlavorID)
+		}
+		var links []flavorgroupFlavor
+		if err := tx.Where("flavor_id = ?", version.FlavorID).Find(&links).Error; err != nil {
+			return nil, errors.Wrap(err, "postgres/flavor_prune_store:Create() failed to retrieve flavorgroup-flavor associations")
+		}
+		retiredFlavor := retiredFlavor{Flavor: dbFlavor}
+		for _, link := range links {
+			retiredFlavor.FlavorgroupIds = append(retiredFlavor.FlavorgroupIds, link.FlavorgroupId)
+		}
+		// the flavorgroup, trust cache and host unique associations are deleted with the flavor
+		if err := tx.Delete(&flavor{ID: version.FlavorID}).Error; err != nil {
+			return nil, errors.Wrapf(err, "postgres/flavor_prune_store:Create() failed to delete flavor %s", version.FlavorID)
+		}
+		retiredFlavors = append(retiredFlavors, retiredFlavor)
+	}
+
+	dbFlavorPrune := flavorPrune{
+		ID:             fp.ID,
+		CreatedAt:      fp.Created,
+		Content:        PGFlavorPrune(*fp),
+		RetiredFlavors: retiredFlavors,
+	}
+	if f.tenantId != nil {
+		dbFlavorPrune.TenantId = *f.tenantId
+	}
+	if err := tx.Create(&dbFlavorPrune).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Create() failed to create FlavorPrune")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Create() failed to commit transaction")
+	}
+	return fp, nil
+}
+
+func (f *FlavorPruneStore) Retrieve(id uuid.UUID) (*hvs.FlavorPrune, error) {
+	defaultLog.Trace("postgres/flavor_prune_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/flavor_prune_store:Retrieve() Leaving")
+
+	fp := hvs.FlavorPrune{}
+	row := scopeToTenant(f.Store.Db.Model(flavorPrune{}), "tenant_id", f.tenantId).Select("content").Where(flavorPrune{ID: id}).Row()
+	if err := row.Scan((*PGFlavorPrune)(&fp)); err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Retrieve() - Could not scan record ")
+	}
+	return &fp, nil
+}
+
+// Search returns the flavor prunes, the most recent first
+func (f *FlavorPruneStore) Search() (*hvs.FlavorPruneCollection, error) {
+	defaultLog.Trace("postgres/flavor_prune_store:Search() Entering")
+	defer defaultLog.Trace("postgres/flavor_prune_store:Search() Leaving")
+
+	rows, err := scopeToTenant(f.Store.Db.Model(&flavorPrune{}), "tenant_id", f.tenantId).Select("content").Order("created desc").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Search() failed to retrieve flavor_prune from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	flavorPruneCollection := hvs.FlavorPruneCollection{FlavorPrunes: []*hvs.FlavorPrune{}}
+	for rows.Next() {
+		fp := hvs.FlavorPrune{}
+		if err := rows.Scan((*PGFlavorPrune)(&fp)); err != nil {
+			return nil, errors.Wrap(err, "postgres/flavor_prune_store:Search() - Could not scan record ")
+		}
+		flavorPruneCollection.FlavorPrunes = append(flavorPruneCollection.FlavorPrunes, &fp)
+	}
+	return &flavorPruneCollection, nil
+}
+
+// Undo restores the retired flavors of the prune with their creation time, so that the LATEST match policy is not
+// affected, and links them to the flavorgroups that still exist
+func (f *FlavorPruneStore) Undo(id uuid.UUID) (*hvs.FlavorPrune, error) {
+	defaultLog.Trace("postgres/flavor_prune_store:Undo() Entering")
+	defer defaultLog.Trace("postgres/flavor_prune_store:Undo() Leaving")
+
+	tx := f.Store.Db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "postgres/flavor_prune_store:Undo() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	var dbFlavorPrune flavorPrune
+	if err := scopeToTenant(tx, "tenant_id", f.tenantId).Where(&flavorPrune{ID: id}).First(&dbFlavorPrune).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Undo() failed to retrieve FlavorPrune")
+	}
+	if dbFlavorPrune.Content.Undone != nil {
+		return nil, errors.New("postgres/flavor_prune_store:Undo() FlavorPrune is already undone")
+	}
+
+	for _, retiredFlavor := range dbFlavorPrune.RetiredFlavors {
+		restoredFlavor := retiredFlavor.Flavor
+		if err := tx.Create(&restoredFlavor).Error; err != nil {
+			return nil, errors.Wrapf(err, "postgres/flavor_prune_store:Undo() failed to restore flavor %s", restoredFlavor.ID)
+		}
+		if err := replaceLabels(tx, "flavor_label", "flavor_id", restoredFlavor.ID, restoredFlavor.Content.Meta.Labels); err != nil {
+			return nil, errors.Wrapf(err, "postgres/flavor_prune_store:Undo() failed to restore labels of flavor %s", restoredFlavor.ID)
+		}
+		if len(retiredFlavor.FlavorgroupIds) == 0 {
+			continue
+		}
+		var flavorGroups []flavorGroup
+		if err := scopeToTenant(tx, "tenant_id", f.tenantId).Where("id IN (?)", retiredFlavor.FlavorgroupIds).Find(&flavorGroups).Error; err != nil {
+			return nil, errors.Wrap(err, "postgres/flavor_prune_store:Undo() failed to retrieve flavorgroups")
+		}
+		for _, fg := range flavorGroups {
+			if err := tx.Create(&flavorgroupFlavor{FlavorgroupId: fg.ID, FlavorId: restoredFlavor.ID}).Error; err != nil {
+				return nil, errors.Wrap(err, "postgres/flavor_prune_store:Undo() failed to create flavorgroup-flavor association")
+			}
+		}
+	}
+
+	undone := time.Now().UTC()
+	dbFlavorPrune.Content.Undone = &undone
+	if err := tx.Model(&flavorPrune{ID: id}).Update("content", dbFlavorPrune.Content).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Undo() failed to update FlavorPrune")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_prune_store:Undo() failed to commit transaction")
+	}
+	fp := hvs.FlavorPrune(dbFlavorPrune.Content)
+	return &fp, nil
+}
+
+func keysOf(index map[uuid.UUID]int) []uuid.UUID {
+	keys := make([]uuid.UUID, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	return keys
+}
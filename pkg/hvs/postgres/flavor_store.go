@@ -5,8 +5,8 @@
 package postgres
 
 import (
-	"fmt"
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
@@ -17,11 +17,17 @@ import (
 )
 
 type FlavorStore struct {
-	Store *DataStore
+	Store    *DataStore
+	tenantId *string
 }
 
 func NewFlavorStore(store *DataStore) *FlavorStore {
-	return &FlavorStore{store}
+	return &FlavorStore{Store: store}
+}
+
+// ForTenant returns a view of the store restricted to the flavors of the tenant
+func (f *FlavorStore) ForTenant(tenantId string) domain.FlavorStore {
+	return &FlavorStore{Store: f.Store, tenantId: &tenantId}
 }
 
 // create flavors
@@ -41,17 +47,33 @@ func (f *FlavorStore) Create(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor,
 	}
 
 	dbf := flavor{
-		ID:         signedFlavor.Flavor.Meta.ID,
-		Content:    PGFlavorContent(signedFlavor.Flavor),
-		CreatedAt:  time.Now(),
-		Label:      signedFlavor.Flavor.Meta.Description.Label,
-		FlavorPart: signedFlavor.Flavor.Meta.Description.FlavorPart,
-		Signature:  signedFlavor.Signature,
+		ID:                   signedFlavor.Flavor.Meta.ID,
+		Content:              PGFlavorContent(signedFlavor.Flavor),
+		CreatedAt:            time.Now(),
+		Label:                signedFlavor.Flavor.Meta.Description.Label,
+		FlavorPart:           signedFlavor.Flavor.Meta.Description.FlavorPart,
+		Signature:            signedFlavor.Signature,
+		AdditionalSignatures: signedFlavor.AdditionalSignatures,
+	}
+	if f.tenantId != nil {
+		dbf.TenantId = *f.tenantId
 	}
 
-	if err := f.Store.Db.Create(&dbf).Error; err != nil {
+	tx := f.Store.Db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "postgres/flavor_store:Create() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if err := tx.Create(&dbf).Error; err != nil {
 		return nil, errors.Wrap(err, "postgres/flavor_store:Create() failed to create flavor")
 	}
+	if err := replaceLabels(tx, "flavor_label", "flavor_id", dbf.ID, signedFlavor.Flavor.Meta.Labels); err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_store:Create() failed to create flavor labels")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_store:Create() failed to commit transaction")
+	}
 	return signedFlavor, nil
 }
 
@@ -62,7 +84,7 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 	var tx *gorm.DB
 	var err error
 
-	tx = f.Store.Db.Table("flavor f").Select("f.id, f.content, f.signature")
+	tx = f.Store.Db.Table("flavor f").Select("f.id, f.content, f.signature, f.additional_signatures")
 	// build partial query with all the given flavor Id's
 	if len(flavorFilter.FlavorFC.Ids) > 0 {
 		var flavorIds []string
@@ -73,7 +95,11 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 	}
 	// build partial query with the given key-value pair from falvor description
 	if flavorFilter.FlavorFC.Key != "" && flavorFilter.FlavorFC.Value != "" {
-		tx = tx.Where(convertToPgJsonqueryString("f.content", "meta.description."+flavorFilter.FlavorFC.Key)+" = ?", flavorFilter.FlavorFC.Value)
+		tx = tx.Where(jsonQueryString(f.Store.Db, "f.content", "meta.description."+flavorFilter.FlavorFC.Key)+" = ?", flavorFilter.FlavorFC.Value)
+	}
+	// build partial query with the given key-value pair from flavor custom metadata
+	if flavorFilter.FlavorFC.MetadataKey != "" && flavorFilter.FlavorFC.MetadataValue != "" {
+		tx = tx.Where(jsonQueryString(f.Store.Db, "f.content", "meta.custom_metadata."+flavorFilter.FlavorFC.MetadataKey)+" = ?", flavorFilter.FlavorFC.MetadataValue)
 	}
 	if flavorFilter.FlavorFC.FlavorgroupID.String() != "" ||
 		len(flavorFilter.FlavorFC.FlavorParts) >= 1 || len(flavorFilter.FlavorPartsWithLatest) >= 1 || flavorFilter.FlavorMeta != nil || len(flavorFilter.FlavorMeta) >= 1 {
@@ -81,13 +107,18 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 			flavorFilter.FlavorPartsWithLatest = getFlavorPartsWithLatestMap(flavorFilter.FlavorFC.FlavorParts, flavorFilter.FlavorPartsWithLatest)
 		}
 		// add all flavor parts in list of flavor Parts
-		tx = f.buildMultipleFlavorPartQueryString(tx, flavorFilter.FlavorFC.FlavorgroupID, flavorFilter.FlavorMeta, flavorFilter.FlavorPartsWithLatest)
+		tx = f.buildMultipleFlavorPartQueryString(tx, flavorFilter.FlavorFC.FlavorgroupID, flavorFilter.InheritedFlavorgroups, flavorFilter.FlavorMeta, flavorFilter.FlavorPartsWithLatest)
 	}
 
 	if tx == nil {
 		return nil, errors.New("postgres/flavor_store:Search() Unexpected Error. Could not build gorm query" +
 			" object in flavor Search function")
 	}
+	// the flavor part queries are combined with OR, restrict the flavors they match to the tenant in an outer query
+	if f.tenantId != nil {
+		tx = f.Store.Db.Table("flavor f").Select("f.id, f.content, f.signature, f.additional_signatures").
+			Where("f.tenant_id = ?", *f.tenantId).Where("f.id IN ?", tx.Select("f.id").SubQuery())
+	}
 
 	rows, err := tx.Rows()
 	if err != nil {
@@ -104,7 +135,7 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 
 	for rows.Next() {
 		sf := hvs.SignedFlavor{}
-		if err := rows.Scan(&sf.Flavor.Meta.ID, (*PGFlavorContent)(&sf.Flavor), &sf.Signature); err != nil {
+		if err := rows.Scan(&sf.Flavor.Meta.ID, (*PGFlavorContent)(&sf.Flavor), &sf.Signature, (*PGFlavorSignatures)(&sf.AdditionalSignatures)); err != nil {
 			return nil, errors.Wrap(err, "postgres/flavor_store:Search() failed to scan record")
 		}
 		signedFlavors = append(signedFlavors, sf)
@@ -112,26 +143,38 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 	return signedFlavors, nil
 }
 
-func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.UUID, flavorMetaInfo map[fc.FlavorPart][]models.FlavorMetaKv, flavorPartsWithLatest map[fc.FlavorPart]bool) *gorm.DB {
+func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.UUID, inheritedFgIds map[fc.FlavorPart]uuid.UUID, flavorMetaInfo map[fc.FlavorPart][]models.FlavorMetaKv, flavorPartsWithLatest map[fc.FlavorPart]bool) *gorm.DB {
 	defaultLog.Trace("postgres/flavor_store:buildMultipleFlavorPartQueryString() Entering")
 	defer defaultLog.Trace("postgres/flavor_store:buildMultipleFlavorPartQueryString() Leaving")
 
+	// the flavors of an inherited flavor part are the ones of the ancestor flavorgroup
+	flavorgroupOf := func(flavorPart fc.FlavorPart) string {
+		if inheritedFgId, ok := inheritedFgIds[flavorPart]; ok {
+			return inheritedFgId.String()
+		}
+		return fgId.String()
+	}
+
 	var biosQuery *gorm.DB
 	var osQuery *gorm.DB
 	var aTagQuery *gorm.DB
 	var softwareQuery *gorm.DB
 	var hostUniqueQuery *gorm.DB
+	var containerImageQuery *gorm.DB
 
 	if flavorPartsWithLatest != nil && len(flavorPartsWithLatest) >= 1 {
 		for flavorPart := range flavorPartsWithLatest {
 			switch flavorPart {
 			case fc.FlavorPartPlatform:
 				biosQuery = f.Store.Db
-				biosQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartPlatform.String(), fgId.String(), biosQuery)
+				biosQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartPlatform.String(), flavorgroupOf(fc.FlavorPartPlatform), biosQuery)
+				if biosQuery == nil {
+					return nil
+				}
 				// build biosQuery with all the platform flavor query attributes from host manifest
 				pfQueryAttributes := flavorMetaInfo[fc.FlavorPartPlatform]
 				for _, pfQueryAttribute := range pfQueryAttributes {
-					biosQuery = biosQuery.Where(convertToPgJsonqueryString("f.content", pfQueryAttribute.Key)+" = ?", pfQueryAttribute.Value)
+					biosQuery = biosQuery.Where(jsonQueryString(f.Store.Db, "f.content", pfQueryAttribute.Key)+" = ?", pfQueryAttribute.Value)
 				}
 				// apply limit if latest
 				if flavorPartsWithLatest[fc.FlavorPartPlatform] {
@@ -140,11 +183,14 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 
 			case fc.FlavorPartOs:
 				osQuery = f.Store.Db
-				osQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartOs.String(), fgId.String(), osQuery)
+				osQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartOs.String(), flavorgroupOf(fc.FlavorPartOs), osQuery)
+				if osQuery == nil {
+					return nil
+				}
 				// build osQuery with all the OS flavor query attributes from host manifest
 				osfQueryAttributes := flavorMetaInfo[fc.FlavorPartOs]
 				for _, osfQueryAttribute := range osfQueryAttributes {
-					osQuery = osQuery.Where(convertToPgJsonqueryString("f.content", osfQueryAttribute.Key)+" = ?", osfQueryAttribute.Value)
+					osQuery = osQuery.Where(jsonQueryString(f.Store.Db, "f.content", osfQueryAttribute.Key)+" = ?", osfQueryAttribute.Value)
 				}
 				// apply limit if latest
 				if flavorPartsWithLatest[fc.FlavorPartOs] {
@@ -155,11 +201,11 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 				hostUniqueQuery = f.Store.Db
 				hostUniqueQuery = hostUniqueQuery.Table("flavor f")
 				hostUniqueQuery = hostUniqueQuery.Select("f.id")
-				hostUniqueQuery = hostUniqueQuery.Where(convertToPgJsonqueryString("f.content", "meta.description.flavor_part")+" = ?", fc.FlavorPartHostUnique.String())
+				hostUniqueQuery = hostUniqueQuery.Where(jsonQueryString(f.Store.Db, "f.content", "meta.description.flavor_part")+" = ?", fc.FlavorPartHostUnique.String())
 				// build host unique Query with all the host unique flavor query attributes from host manifest
 				hufQueryAttributes := flavorMetaInfo[fc.FlavorPartHostUnique]
 				for _, hufQueryAttribute := range hufQueryAttributes {
-					hostUniqueQuery = hostUniqueQuery.Where(convertToPgJsonqueryString("f.content", hufQueryAttribute.Key)+" = ?", hufQueryAttribute.Value)
+					hostUniqueQuery = hostUniqueQuery.Where(jsonQueryString(f.Store.Db, "f.content", hufQueryAttribute.Key)+" = ?", hufQueryAttribute.Value)
 				}
 				// apply limit if latest
 				if flavorPartsWithLatest[fc.FlavorPartHostUnique] {
@@ -168,7 +214,10 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 
 			case fc.FlavorPartSoftware:
 				softwareQuery = f.Store.Db
-				softwareQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartSoftware.String(), fgId.String(), softwareQuery)
+				softwareQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartSoftware.String(), flavorgroupOf(fc.FlavorPartSoftware), softwareQuery)
+				if softwareQuery == nil {
+					return nil
+				}
 				sfQueryAttributes := flavorMetaInfo[fc.FlavorPartSoftware]
 				// build software Query with all the software flavor query attributes from host manifest
 				for _, sfQueryAttribute := range sfQueryAttributes {
@@ -182,17 +231,29 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 			case fc.FlavorPartAssetTag:
 				aTagQuery = f.Store.Db
 				aTagQuery = aTagQuery.Table("flavor f").Select("f.id")
-				aTagQuery = aTagQuery.Where(convertToPgJsonqueryString("f.content", "meta.description.flavor_part")+" = ?", fc.FlavorPartAssetTag)
+				aTagQuery = aTagQuery.Where(jsonQueryString(f.Store.Db, "f.content", "meta.description.flavor_part")+" = ?", fc.FlavorPartAssetTag)
 				// build assetTag Query with all the assetTag flavor query attributes from host manifest
 				atfQueryAttributes := flavorMetaInfo[fc.FlavorPartAssetTag]
 				for _, atfQueryAttribute := range atfQueryAttributes {
-					aTagQuery = aTagQuery.Where(convertToPgJsonqueryString("f.content", atfQueryAttribute.Key)+" = ?", atfQueryAttribute.Value)
+					aTagQuery = aTagQuery.Where(jsonQueryString(f.Store.Db, "f.content", atfQueryAttribute.Key)+" = ?", atfQueryAttribute.Value)
 				}
 				// apply limit if latest
 				if flavorPartsWithLatest[fc.FlavorPartAssetTag] {
 					aTagQuery = aTagQuery.Order("f.created_at desc").Limit(1)
 				}
 
+			case fc.FlavorPartContainerImage:
+				// the container image flavors do not depend on the host, all the flavors of the flavorgroup apply
+				containerImageQuery = f.Store.Db
+				containerImageQuery = buildFlavorPartQueryStringWithFlavorParts(fc.FlavorPartContainerImage.String(), flavorgroupOf(fc.FlavorPartContainerImage), containerImageQuery)
+				if containerImageQuery == nil {
+					return nil
+				}
+				// apply limit if latest
+				if flavorPartsWithLatest[fc.FlavorPartContainerImage] {
+					containerImageQuery = containerImageQuery.Order("f.created_at desc").Limit(1)
+				}
+
 			default:
 				defaultLog.Error("postgres/flavor_store:buildMultipleFlavorPartQueryString() Invalid flavor part")
 				return nil
@@ -242,24 +303,35 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 			subQuery = subQuery.Where("f.id IN ?", hostUniqueSubQuery)
 		}
 	}
+	// add container image query to sub query
+	if containerImageQuery != nil {
+		containerImageSubQuery := containerImageQuery.SubQuery()
+		if biosQuery != nil || osQuery != nil || softwareQuery != nil || aTagQuery != nil || hostUniqueQuery != nil {
+			subQuery = subQuery.Or("f.id IN ?", containerImageSubQuery)
+		} else {
+			subQuery = subQuery.Where("f.id IN ?", containerImageSubQuery)
+		}
+	}
 	// check if none of the flavor part queries are not formed,
-	if subQuery != nil && (biosQuery != nil || aTagQuery != nil || softwareQuery != nil || hostUniqueQuery != nil || osQuery != nil) {
+	if subQuery != nil && (biosQuery != nil || aTagQuery != nil || softwareQuery != nil || hostUniqueQuery != nil || osQuery != nil || containerImageQuery != nil) {
 		tx = subQuery
 	} else if fgId != uuid.Nil {
-		fgSubQuery := buildFlavorPartQueryStringWithFlavorgroup(fgId.String(), tx).SubQuery()
-		tx = tx.Where("f.id IN ?", fgSubQuery)
+		fgQuery := buildFlavorPartQueryStringWithFlavorgroup(fgId.String(), tx)
+		if fgQuery == nil {
+			return nil
+		}
+		tx = tx.Where("f.id IN ?", fgQuery.SubQuery())
 	}
 	return tx
 }
 
-func convertToPgJsonqueryString(queryHead string, jsonKeyPath string) string {
-	jsonQueryStr := queryHead
-	flavorMetaPath := strings.Split(jsonKeyPath, ".")
-	for i := 0; i < len(flavorMetaPath)-1; i++ {
-		jsonQueryStr = fmt.Sprintf("%s -> '%s'", jsonQueryStr, flavorMetaPath[i])
+// jsonQueryString returns the expression selecting the text at the dotted jsonKeyPath of the JSON column queryHead
+func jsonQueryString(tx *gorm.DB, queryHead string, jsonKeyPath string) string {
+	var path []interface{}
+	for _, key := range strings.Split(jsonKeyPath, ".") {
+		path = append(path, key)
 	}
-	jsonQueryStr = fmt.Sprintf("%s ->> '%s'", jsonQueryStr, flavorMetaPath[len(flavorMetaPath)-1])
-	return jsonQueryStr
+	return dialectOf(tx).jsonText(queryHead, path...)
 }
 
 func buildFlavorPartQueryStringWithFlavorParts(flavorpart, flavorgroupId string, tx *gorm.DB) *gorm.DB {
@@ -276,22 +348,36 @@ func buildFlavorPartQueryStringWithFlavorParts(flavorpart, flavorgroupId string,
 	}
 	if flavorgroupUuid != uuid.Nil {
 		subQuery := buildFlavorPartQueryStringWithFlavorgroup(flavorgroupId, tx)
-		tx = subQuery.Where(convertToPgJsonqueryString("f.content", "meta.description.flavor_part")+" = ?", flavorpart)
+		if subQuery == nil {
+			return nil
+		}
+		tx = subQuery.Where(jsonQueryString(tx, "f.content", "meta.description.flavor_part")+" = ?", flavorpart)
 	} else {
 		tx = tx.Table("flavor f").Select("f.id").Joins("INNER JOIN flavorgroup_flavor fgf ON f.id = fgf.flavor_id")
 		tx = tx.Joins("INNER JOIN flavor_group fg ON fgf.flavorgroup_id = fg.id")
-		tx = tx.Where(convertToPgJsonqueryString("f.content", "meta.description.flavor_part")+" = ?", flavorpart)
+		tx = tx.Where(jsonQueryString(tx, "f.content", "meta.description.flavor_part")+" = ?", flavorpart)
 	}
 	return tx
 }
 
+// buildFlavorPartQueryStringWithFlavorgroup selects the flavors linked to the flavorgroup and the ones selected by its
+// flavor selector, it returns nil when the selector of the flavorgroup can not be retrieved
 func buildFlavorPartQueryStringWithFlavorgroup(flavorgroupId string, tx *gorm.DB) *gorm.DB {
 	defaultLog.Trace("postgres/flavor_store:buildFlavorPartQueryStringWithFlavorgroup() Entering")
 	defer defaultLog.Trace("postgres/flavor_store:buildFlavorPartQueryStringWithFlavorgroup() Leaving")
 
-	tx = tx.Table("flavor f").Select("f.id").Joins("INNER JOIN flavorgroup_flavor fgf ON f.id = fgf.flavor_id")
-	tx = tx.Joins("INNER JOIN flavor_group fg ON fgf.flavorgroup_id = fg.id")
-	tx = tx.Where("fg.id = ?", flavorgroupId)
+	fgId, err := uuid.Parse(flavorgroupId)
+	if err != nil {
+		defaultLog.WithError(err).Error("postgres/flavor_store:buildFlavorPartQueryStringWithFlavorgroup() Failed to parse flavor group ID")
+		return nil
+	}
+	selectors, err := retrieveFlavorgroupSelectors(tx.New(), fgId)
+	if err != nil {
+		defaultLog.WithError(err).Error("postgres/flavor_store:buildFlavorPartQueryStringWithFlavorgroup() Failed to retrieve flavor group selectors")
+		return nil
+	}
+	condition, args := selectors.flavorsCondition("f.id", "f.tenant_id")
+	tx = tx.Table("flavor f").Select("f.id").Where(condition, args...)
 	return tx
 }
 
@@ -318,13 +404,70 @@ func (f *FlavorStore) Retrieve(flavorId uuid.UUID) (*hvs.SignedFlavor, error) {
 	defer defaultLog.Trace("postgres/flavor_store:Retrieve() Leaving")
 
 	sf := hvs.SignedFlavor{}
-	row := f.Store.Db.Model(flavor{}).Select("content, signature").Where(&flavor{ID: flavorId}).Row()
-	if err := row.Scan((*PGFlavorContent)(&sf.Flavor), &sf.Signature); err != nil {
+	row := scopeToTenant(f.Store.Db.Model(flavor{}).Select("content, signature, additional_signatures").Where(&flavor{ID: flavorId}), "tenant_id", f.tenantId).Row()
+	if err := row.Scan((*PGFlavorContent)(&sf.Flavor), &sf.Signature, (*PGFlavorSignatures)(&sf.AdditionalSignatures)); err != nil {
 		return nil, errors.Wrap(err, "postgres/flavor_store:Retrieve() - Could not scan record ")
 	}
 	return &sf, nil
 }
 
+// add a co-signer's signature to a flavor
+func (f *FlavorStore) AddSignature(flavorId uuid.UUID, signature string) (*hvs.SignedFlavor, error) {
+	defaultLog.Trace("postgres/flavor_store:AddSignature() Entering")
+	defer defaultLog.Trace("postgres/flavor_store:AddSignature() Leaving")
+
+	sf, err := f.Retrieve(flavorId)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_store:AddSignature() failed to retrieve flavor")
+	}
+	sf.AdditionalSignatures = append(sf.AdditionalSignatures, signature)
+
+	db := scopeToTenant(f.Store.Db.Model(&flavor{ID: flavorId}), "tenant_id", f.tenantId).
+		Update("additional_signatures", PGFlavorSignatures(sf.AdditionalSignatures))
+	if db.Error != nil {
+		return nil, errors.Wrap(db.Error, "postgres/flavor_store:AddSignature() failed to update flavor")
+	} else if db.RowsAffected != 1 {
+		return nil, errors.New("postgres/flavor_store:AddSignature() - no rows affected - Record not found = id : " + flavorId.String())
+	}
+	return sf, nil
+}
+
+// update the content and the signatures of a flavor
+func (f *FlavorStore) Update(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
+	defaultLog.Trace("postgres/flavor_store:Update() Entering")
+	defer defaultLog.Trace("postgres/flavor_store:Update() Leaving")
+	if signedFlavor == nil || signedFlavor.Signature == "" || signedFlavor.Flavor.Meta.Description.Label == "" {
+		return nil, errors.New("postgres/flavor_store:Update()- invalid input : must have content, signature and the label for the flavor")
+	}
+
+	tx := f.Store.Db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "postgres/flavor_store:Update() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	db := scopeToTenant(tx.Model(&flavor{ID: signedFlavor.Flavor.Meta.ID}), "tenant_id", f.tenantId).
+		Updates(map[string]interface{}{
+			"content":               PGFlavorContent(signedFlavor.Flavor),
+			"label":                 signedFlavor.Flavor.Meta.Description.Label,
+			"signature":             signedFlavor.Signature,
+			"additional_signatures": PGFlavorSignatures(signedFlavor.AdditionalSignatures),
+		})
+	if db.Error != nil {
+		return nil, errors.Wrap(db.Error, "postgres/flavor_store:Update() failed to update flavor")
+	} else if db.RowsAffected != 1 {
+		return nil, errors.New("postgres/flavor_store:Update() - no rows affected - Record not found = id : " + signedFlavor.Flavor.Meta.ID.String())
+	}
+	// the labels are part of the flavor meta, they are replaced with the content
+	if err := replaceLabels(tx, "flavor_label", "flavor_id", signedFlavor.Flavor.Meta.ID, signedFlavor.Flavor.Meta.Labels); err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_store:Update() failed to update flavor labels")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_store:Update() failed to commit transaction")
+	}
+	return signedFlavor, nil
+}
+
 // delete flavors
 func (f *FlavorStore) Delete(flavorId uuid.UUID) error {
 	defaultLog.Trace("postgres/flavor_store:Delete() Entering")
@@ -333,7 +476,7 @@ func (f *FlavorStore) Delete(flavorId uuid.UUID) error {
 	dbFlavor := flavor{
 		ID: flavorId,
 	}
-	if err := f.Store.Db.Where(&dbFlavor).Delete(&dbFlavor).Error; err != nil {
+	if err := scopeToTenant(f.Store.Db, "tenant_id", f.tenantId).Where(&dbFlavor).Delete(&dbFlavor).Error; err != nil {
 		return errors.Wrap(err, "postgres/flavor_store:Delete() failed to delete Flavor")
 	}
 	return nil
@@ -7,6 +7,7 @@ package postgres
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	fc "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
@@ -16,15 +17,29 @@ import (
 	"sync"
 )
 
+// flavorGroupColumns are the columns scanned into a FlavorGroup, in order
+const flavorGroupColumns = "id, name, flavor_type_match_policy, strict_event_log, tenant_id, parent_id, compliance_profiles, flavor_selector, host_selector"
+
 type FlavorGroupStore struct {
 	Store            *DataStore
-	flavorPartsCache sync.Map
+	flavorPartsCache *sync.Map
+	tenantId         *string
 }
 
 func NewFlavorGroupStore(store *DataStore) *FlavorGroupStore {
 	return &FlavorGroupStore{
 		Store:            store,
-		flavorPartsCache: sync.Map{},
+		flavorPartsCache: &sync.Map{},
+	}
+}
+
+// ForTenant returns a view of the store restricted to the flavorgroups of the tenant, the view shares the flavor
+// parts cache of the store
+func (f *FlavorGroupStore) ForTenant(tenantId string) domain.FlavorGroupStore {
+	return &FlavorGroupStore{
+		Store:            f.Store,
+		flavorPartsCache: f.flavorPartsCache,
+		tenantId:         &tenantId,
 	}
 }
 
@@ -41,6 +56,16 @@ func (f *FlavorGroupStore) Create(fg *hvs.FlavorGroup) (*hvs.FlavorGroup, error)
 		ID:                    fg.ID,
 		Name:                  fg.Name,
 		FlavorTypeMatchPolicy: PGFlavorMatchPolicies(fg.MatchPolicies),
+		StrictEventLog:        fg.StrictEventLogVerification,
+		TenantId:              fg.TenantId,
+		ParentId:              fg.ParentId,
+		ComplianceProfiles:    PGComplianceProfiles(fg.ComplianceProfiles),
+		FlavorSelector:        (*PGLabelSelector)(fg.FlavorSelector),
+		HostSelector:          (*PGLabelSelector)(fg.HostSelector),
+	}
+	if f.tenantId != nil {
+		dbFlavorGroup.TenantId = *f.tenantId
+		fg.TenantId = *f.tenantId
 	}
 
 	if err := f.Store.Db.Create(&dbFlavorGroup).Error; err != nil {
@@ -54,8 +79,10 @@ func (f *FlavorGroupStore) Retrieve(flavorGroupId uuid.UUID) (*hvs.FlavorGroup,
 	defer defaultLog.Trace("postgres/flavorgroup_store:Retrieve() Leaving")
 
 	fg := hvs.FlavorGroup{}
-	row := f.Store.Db.Model(&flavorGroup{}).Where(&flavorGroup{ID: flavorGroupId}).Row()
-	if err := row.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies)); err != nil {
+	tx := f.Store.Db.Model(&flavorGroup{}).Select(flavorGroupColumns).Where(&flavorGroup{ID: flavorGroupId})
+	row := scopeToTenant(tx, "tenant_id", f.tenantId).Row()
+	if err := row.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies), &fg.StrictEventLogVerification, &fg.TenantId, &fg.ParentId,
+		(*PGComplianceProfiles)(&fg.ComplianceProfiles), labelSelector{&fg.FlavorSelector}, labelSelector{&fg.HostSelector}); err != nil {
 		return nil, errors.Wrap(err, "postgres/flavorgroup_store:Retrieve() failed to scan record")
 	}
 	return &fg, nil
@@ -73,11 +100,11 @@ func (f *FlavorGroupStore) Search(fgFilter *models.FlavorGroupFilterCriteria) ([
 				"Error getting associated flavorgroups")
 		}
 		//If filter is only on the basis of flavor Id and no records are there then return
-		if fgFilter.NameEqualTo == "" && fgFilter.NameContains == "" && len(fgFilter.Ids) == 0 {
+		if fgFilter.NameEqualTo == "" && fgFilter.NameContains == "" && fgFilter.ParentId == nil && len(fgFilter.Ids) == 0 {
 			return []hvs.FlavorGroup{}, nil
 		}
 	}
-	tx := buildFlavorGroupSearchQuery(f.Store.Db, fgFilter)
+	tx := buildFlavorGroupSearchQuery(scopeToTenant(f.Store.Db, "tenant_id", f.tenantId), fgFilter)
 
 	if tx == nil {
 		return nil, errors.New("postgres/flavorgroup_store:Search() Unexpected Error. Could not build" +
@@ -98,7 +125,8 @@ func (f *FlavorGroupStore) Search(fgFilter *models.FlavorGroupFilterCriteria) ([
 	flavorgroupList := []hvs.FlavorGroup{}
 	for rows.Next() {
 		fg := hvs.FlavorGroup{}
-		if err := rows.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies)); err != nil {
+		if err := rows.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies), &fg.StrictEventLogVerification, &fg.TenantId, &fg.ParentId,
+			(*PGComplianceProfiles)(&fg.ComplianceProfiles), labelSelector{&fg.FlavorSelector}, labelSelector{&fg.HostSelector}); err != nil {
 			return nil, errors.Wrap(err, "postgres/flavorgroup_store:Search() failed to scan record")
 		}
 		flavorgroupList = append(flavorgroupList, fg)
@@ -114,7 +142,7 @@ func (f *FlavorGroupStore) Delete(flavorGroupId uuid.UUID) error {
 	dbFlavorGroup := flavorGroup{
 		ID: flavorGroupId,
 	}
-	if err := f.Store.Db.Delete(&dbFlavorGroup).Error; err != nil {
+	if err := scopeToTenant(f.Store.Db, "tenant_id", f.tenantId).Delete(&dbFlavorGroup).Error; err != nil {
 		return errors.Wrap(err, "postgres/flavorgroup_store:Delete() failed to delete Flavorgroup")
 	}
 
@@ -127,11 +155,19 @@ func (f *FlavorGroupStore) HasAssociatedHosts(fgId uuid.UUID) (bool, error) {
 	defaultLog.Trace("postgres/flavorgroup_store:HasAssociatedHosts() Entering")
 	defer defaultLog.Trace("postgres/flavorgroup_store:HasAssociatedHosts() Leaving")
 
+	selectors, err := retrieveFlavorgroupSelectors(f.Store.Db, fgId)
+	if err != nil {
+		return false, errors.Wrap(err, "postgres/flavorgroup_store:HasAssociatedHosts() failed to retrieve flavorgroup selectors")
+	}
+	condition, args := selectors.hostsCondition("id", "tenant_id")
+
 	count := 0
-	tx := f.Store.Db.Model(&hostFlavorgroup{}).Where("flavorgroup_id = ?", fgId).Count(&count)
+	tx := f.Store.Db.Model(&host{}).Where(condition, args...).Count(&count)
 	if tx == nil {
 		return false, errors.New("postgres/flavorgroup_store:HasAssociatedHosts() Unexpected Error. Could not get" +
 			"hosts associated with flavorgroup.")
+	} else if tx.Error != nil {
+		return false, errors.Wrap(tx.Error, "postgres/flavorgroup_store:HasAssociatedHosts() failed to count hosts")
 	} else if count > 0 {
 		return true, nil
 	}
@@ -148,7 +184,7 @@ func buildFlavorGroupSearchQuery(tx *gorm.DB, fgFilter *models.FlavorGroupFilter
 		return nil
 	}
 
-	tx = tx.Model(&flavorGroup{})
+	tx = tx.Model(&flavorGroup{}).Select(flavorGroupColumns)
 	if fgFilter == nil {
 		return tx
 	}
@@ -160,6 +196,9 @@ func buildFlavorGroupSearchQuery(tx *gorm.DB, fgFilter *models.FlavorGroupFilter
 	} else if fgFilter.NameContains != "" {
 		tx = tx.Where("name like ? ", "%"+fgFilter.NameContains+"%")
 	}
+	if fgFilter.ParentId != nil {
+		tx = tx.Where("parent_id = ?", *fgFilter.ParentId)
+	}
 	return tx
 }
 
@@ -222,14 +261,20 @@ func (f *FlavorGroupStore) RemoveFlavors(fgId uuid.UUID, fIds []uuid.UUID) error
 	return nil
 }
 
-// SearchFlavors returns a list of flavors linked to flavorgroup
+// SearchFlavors returns a list of flavors linked to flavorgroup or selected by its flavor selector
 func (f *FlavorGroupStore) SearchFlavors(fgId uuid.UUID) ([]uuid.UUID, error) {
 	defaultLog.Trace("postgres/flavorgroup_store:SearchFlavors() Entering")
 	defer defaultLog.Trace("postgres/flavorgroup_store:SearchFlavors() Leaving")
 
+	selectors, err := retrieveFlavorgroupSelectors(f.Store.Db, fgId)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavorgroup_store:SearchFlavors() failed to retrieve flavorgroup selectors")
+	}
+	condition, args := selectors.flavorsCondition("id", "tenant_id")
+
 	// filter by flavorgroup id
-	tx := f.Store.Db.Model(&flavorgroupFlavor{})
-	tx = tx.Select("flavor_id").Where("flavorgroup_id = ?", fgId)
+	tx := f.Store.Db.Model(&flavor{})
+	tx = tx.Select("id").Where(condition, args...)
 	if tx == nil {
 		return nil, errors.New("postgres/flavorgroup_store:SearchFlavors() Unexpected Error. Could not build" +
 			" a gorm query object in FlavorGroupsFlavors Search function.")
@@ -273,12 +318,19 @@ func (f *FlavorGroupStore) RetrieveFlavor(fgId uuid.UUID, fId uuid.UUID) (*hvs.F
 	return &result, nil
 }
 
-// SearchHostsByFlavorGroup is used to fetch a list of hosts which are linked to the provided FlavorGroup
+// SearchHostsByFlavorGroup is used to fetch a list of hosts which are linked to the provided FlavorGroup or selected
+// by its host selector
 func (f *FlavorGroupStore) SearchHostsByFlavorGroup(fgID uuid.UUID) ([]uuid.UUID, error) {
 	defaultLog.Trace("postgres/flavorgroup_store:SearchHostsByFlavorGroups() Entering")
 	defer defaultLog.Trace("postgres/flavorgroup_store:SearchHostsByFlavorGroups() Leaving")
 
-	rows, err := f.Store.Db.Model(&hostFlavorgroup{}).Select("host_id").Where(&hostFlavorgroup{FlavorgroupId: fgID}).Rows()
+	selectors, err := retrieveFlavorgroupSelectors(f.Store.Db, fgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavorgroup_store:SearchHostsByFlavorGroup() failed to retrieve flavorgroup selectors")
+	}
+	condition, args := selectors.hostsCondition("id", "tenant_id")
+
+	rows, err := f.Store.Db.Model(&host{}).Select("id").Where(condition, args...).Rows()
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/flavorgroup_store:SearchHostsByFlavorGroup() failed to retrieve records from db")
 	}
@@ -301,7 +353,7 @@ func (f *FlavorGroupStore) SearchHostsByFlavorGroup(fgID uuid.UUID) ([]uuid.UUID
 	return hIDs, nil
 }
 
-// searchFlavorGroups returns a list of flavorgroups linked to flavor
+// searchFlavorGroups returns a list of flavorgroups linked to flavor or whose flavor selector matches its labels
 func (f *FlavorGroupStore) searchFlavorGroups(flavorId *uuid.UUID) ([]uuid.UUID, error) {
 	defaultLog.Trace("postgres/flavorgroup_store:searchFlavorGroups() Entering")
 	defer defaultLog.Trace("postgres/flavorgroup_store:searchFlavorGroups() Leaving")
@@ -326,12 +378,39 @@ func (f *FlavorGroupStore) searchFlavorGroups(flavorId *uuid.UUID) ([]uuid.UUID,
 	}()
 
 	flavorGroupIds := []uuid.UUID{}
+	linked := make(map[uuid.UUID]bool)
 	for rows.Next() {
 		flavorGroupId := uuid.UUID{}
 		if err := rows.Scan(&flavorGroupId); err != nil {
 			return nil, errors.Wrap(err, "postgres/flavorgroup_store:searchFlavorGroups() failed to scan record")
 		}
 		flavorGroupIds = append(flavorGroupIds, flavorGroupId)
+		linked[flavorGroupId] = true
+	}
+
+	// add the flavorgroups of the tenant of the flavor whose flavor selector matches its labels
+	labels, err := searchLabels(f.Store.Db, "flavor_label", "flavor_id", []uuid.UUID{*flavorId})
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavorgroup_store:searchFlavorGroups() failed to retrieve flavor labels")
+	}
+	if len(labels[*flavorId]) == 0 {
+		return flavorGroupIds, nil
+	}
+	var tenantIds []string
+	if err := f.Store.Db.Model(&flavor{}).Where("id = ?", *flavorId).Pluck("tenant_id", &tenantIds).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavorgroup_store:searchFlavorGroups() failed to retrieve flavor tenant")
+	}
+	if len(tenantIds) == 0 {
+		return flavorGroupIds, nil
+	}
+	selectingFgIds, err := selectingFlavorgroups(f.Store.Db, "flavor_selector", tenantIds[0], labels[*flavorId])
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/flavorgroup_store:searchFlavorGroups() failed to retrieve selecting flavorgroups")
+	}
+	for _, fgId := range selectingFgIds {
+		if !linked[fgId] {
+			flavorGroupIds = append(flavorGroupIds, fgId)
+		}
 	}
 	return flavorGroupIds, nil
 }
@@ -347,9 +426,15 @@ func (f *FlavorGroupStore) GetFlavorTypesInFlavorGroup(fgId uuid.UUID) (map[fc.F
 	if flavorParts, exists := f.flavorPartsCache.Load(fgId); exists {
 		return flavorParts.(map[fc.FlavorPart]bool), nil
 	} else {
+		selectors, err := retrieveFlavorgroupSelectors(f.Store.Db, fgId)
+		if err != nil {
+			return nil, errors.Wrap(err, "postgres/host_store:GetFlavorTypesInFlavorGroup() failed to retrieve flavorgroup selectors")
+		}
+		condition, args := selectors.flavorsCondition("id", "tenant_id")
+
 		// create the map first.. the map itself might be empty if there are flavors in the flavorgroup
 		var flavorParts []string
-		err := f.Store.Db.Model(&flavor{}).Where("id in (select flavor_id from flavorgroup_flavor where flavorgroup_id = ?)", fgId).Pluck(("DISTINCT(flavor_part)"), &flavorParts).Error
+		err = f.Store.Db.Model(&flavor{}).Where(condition, args...).Pluck(("DISTINCT(flavor_part)"), &flavorParts).Error
 		if err != nil {
 			return nil, errors.Wrap(err, "postgres/host_store:GetFlavorTypesInFlavorGroup() failed to retrieve records from db")
 		}
@@ -358,7 +443,10 @@ func (f *FlavorGroupStore) GetFlavorTypesInFlavorGroup(fgId uuid.UUID) (map[fc.F
 			fpMap[fc.FlavorPart(fp)] = true
 		}
 
-		f.flavorPartsCache.Store(fgId, fpMap)
+		// the flavors selected by labels change with the flavors, without the flavorgroup knowing, they are not cached
+		if selectors.flavorSelector.IsEmpty() {
+			f.flavorPartsCache.Store(fgId, fpMap)
+		}
 		return fpMap, nil
 	}
 
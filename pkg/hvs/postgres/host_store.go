@@ -8,26 +8,54 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	"reflect"
 	"strings"
+	"time"
 )
 
 type HostStore struct {
-	Store *DataStore
+	Store    *DataStore
+	tenantId *string
 }
 
 func NewHostStore(store *DataStore) *HostStore {
-	return &HostStore{store}
+	return &HostStore{Store: store}
 }
 
 const (
-	hostFields = "host.id, host.name, host.description, host.connection_string, host.hardware_uuid"
+	hostFields = "host.id, host.name, host.description, host.connection_string, host.hardware_uuid, host.tenant_id, host.lifecycle, host.capabilities"
 )
 
+// ForTenant returns a view of the store restricted to the hosts of the tenant
+func (hs *HostStore) ForTenant(tenantId string) domain.HostStore {
+	return &HostStore{Store: hs.Store, tenantId: &tenantId}
+}
+
+// hostCapabilities scans the capabilities column into the host, the capabilities of the hosts that have not been
+// reached yet are null
+type hostCapabilities struct {
+	host *hvs.Host
+}
+
+func (hc hostCapabilities) Scan(value interface{}) error {
+	if value == nil {
+		hc.host.Capabilities = nil
+		return nil
+	}
+	var capabilities PGHostCapabilities
+	if err := capabilities.Scan(value); err != nil {
+		return err
+	}
+	hc.host.Capabilities = (*taModel.HostCapabilities)(&capabilities)
+	return nil
+}
+
 func (hs *HostStore) Create(h *hvs.Host) (*hvs.Host, error) {
 	defaultLog.Trace("postgres/host_store:Create() Entering")
 	defer defaultLog.Trace("postgres/host_store:Create() Leaving")
@@ -42,15 +70,34 @@ func (hs *HostStore) Create(h *hvs.Host) (*hvs.Host, error) {
 		Name:             h.HostName,
 		Description:      h.Description,
 		ConnectionString: h.ConnectionString,
+		TenantId:         h.TenantId,
+		Lifecycle:        string(h.Lifecycle),
+		Capabilities:     (*PGHostCapabilities)(h.Capabilities),
+	}
+	if hs.tenantId != nil {
+		dbHost.TenantId = *hs.tenantId
+		h.TenantId = *hs.tenantId
 	}
 
 	if h.HardwareUuid != nil {
 		dbHost.HardwareUuid = models.NewHwUUID(*h.HardwareUuid)
 	}
 
-	if err := hs.Store.Db.Create(&dbHost).Error; err != nil {
+	tx := hs.Store.Db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "postgres/host_store:Create() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if err := tx.Create(&dbHost).Error; err != nil {
 		return nil, errors.Wrap(err, "postgres/host_store:Create() failed to create Host")
 	}
+	if err := replaceLabels(tx, "host_label", "host_id", h.Id, h.Labels); err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:Create() failed to create Host labels")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:Create() failed to commit transaction")
+	}
 	return h, nil
 }
 
@@ -58,7 +105,7 @@ func (hs *HostStore) Retrieve(id uuid.UUID, criteria *models.HostInfoFetchCriter
 	defaultLog.Trace("postgres/host_store:Retrieve() Entering")
 	defer defaultLog.Trace("postgres/host_store:Retrieve() Leaving")
 
-	tx := hs.Store.Db.Model(&host{}).Where(&host{Id: id})
+	tx := scopeToTenant(hs.Store.Db.Model(&host{}).Where(&host{Id: id}), "host.tenant_id", hs.tenantId)
 
 	h := hvs.Host{}
 	report := hvs.TrustReport{}
@@ -67,31 +114,41 @@ func (hs *HostStore) Retrieve(id uuid.UUID, criteria *models.HostInfoFetchCriter
 	if criteria != nil && (criteria.GetReport || criteria.GetHostStatus) {
 		row := buildInfoFetchQuery(tx, criteria, nil).Row()
 		if criteria.GetReport && criteria.GetHostStatus {
-			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid,
+			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &h.TenantId, &h.Lifecycle, hostCapabilities{&h},
 				(*PGTrustReport)(&report), (*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 			}
 			h.Report = &report
 			h.ConnectionStatus = &connectionStatus
 		} else if criteria.GetReport {
-			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid,
+			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &h.TenantId, &h.Lifecycle, hostCapabilities{&h},
 				(*PGTrustReport)(&report)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 			}
 			h.Report = &report
 		} else if criteria.GetHostStatus {
-			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid,
+			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &h.TenantId, &h.Lifecycle, hostCapabilities{&h},
 				(*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 			}
 			h.ConnectionStatus = &connectionStatus
 		}
 	} else {
-		if err := tx.Row().Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid); err != nil {
+		if err := tx.Select(hostFields).Row().Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &h.TenantId, &h.Lifecycle, hostCapabilities{&h}); err != nil {
 			return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 		}
 	}
 
+	labels, err := searchLabels(hs.Store.Db, "host_label", "host_id", []uuid.UUID{h.Id})
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to retrieve Host labels")
+	}
+	h.Labels = labels[h.Id]
+	maintenances, err := searchMaintenances(hs.Store.Db, []uuid.UUID{h.Id})
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to retrieve Host maintenance")
+	}
+	h.Maintenance = maintenances[h.Id]
 	return &h, nil
 }
 
@@ -104,19 +161,37 @@ func (hs *HostStore) Update(h *hvs.Host) error {
 		Name:             h.HostName,
 		Description:      h.Description,
 		ConnectionString: h.ConnectionString,
+		Lifecycle:        string(h.Lifecycle),
+		// the capabilities are kept when the host has not been reached
+		Capabilities: (*PGHostCapabilities)(h.Capabilities),
 	}
 
 	if h.HardwareUuid != nil {
 		dbHost.HardwareUuid = models.NewHwUUID(*h.HardwareUuid)
 	}
 
-	if db := hs.Store.Db.Model(&dbHost).Updates(&dbHost); db.Error != nil || db.RowsAffected != 1 {
+	tx := hs.Store.Db.Begin()
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "postgres/host_store:Update() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if db := scopeToTenant(tx.Model(&dbHost), "tenant_id", hs.tenantId).Updates(&dbHost); db.Error != nil || db.RowsAffected != 1 {
 		if db.Error != nil {
 			return errors.Wrap(db.Error, "postgres/host_store:Update() failed to update Host  "+dbHost.Id.String())
 		} else {
 			return errors.New("postgres/host_store:Update() - no rows affected - Record not found = id :  " + dbHost.Id.String())
 		}
 	}
+	// the labels are kept when they are not provided, empty labels remove them
+	if h.Labels != nil {
+		if err := replaceLabels(tx, "host_label", "host_id", h.Id, h.Labels); err != nil {
+			return errors.Wrap(err, "postgres/host_store:Update() failed to update Host labels")
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return errors.Wrap(err, "postgres/host_store:Update() failed to commit transaction")
+	}
 	return nil
 }
 
@@ -124,7 +199,7 @@ func (hs *HostStore) Delete(id uuid.UUID) error {
 	defaultLog.Trace("postgres/host_store:Delete() Entering")
 	defer defaultLog.Trace("postgres/host_store:Delete() Leaving")
 
-	if err := hs.Store.Db.Delete(&host{Id: id}).Error; err != nil {
+	if err := scopeToTenant(hs.Store.Db, "tenant_id", hs.tenantId).Delete(&host{Id: id}).Error; err != nil {
 		return errors.Wrap(err, "postgres/host_store:Delete() failed to delete Host")
 	}
 	return nil
@@ -134,7 +209,7 @@ func (hs *HostStore) DeleteByHostName(hostName string) error {
 	defaultLog.Trace("postgres/host_store:DeleteByHostName() Entering")
 	defer defaultLog.Trace("postgres/host_store:DeleteByHostName() Leaving")
 
-	if err := hs.Store.Db.Where("name=?", hostName).Delete(&host{}).Error; err != nil {
+	if err := scopeToTenant(hs.Store.Db, "tenant_id", hs.tenantId).Where("name=?", hostName).Delete(&host{}).Error; err != nil {
 		return errors.Wrap(err, "postgres/host_store:DeleteByHostName() failed to delete Host")
 	}
 	return nil
@@ -144,7 +219,7 @@ func (hs *HostStore) Search(filterCriteria *models.HostFilterCriteria, infoFetch
 	defaultLog.Trace("postgres/host_store:Search() Entering")
 	defer defaultLog.Trace("postgres/host_store:Search() Leaving")
 
-	tx := buildHostSearchQuery(hs.Store.Db, filterCriteria)
+	tx := buildHostSearchQuery(scopeToTenant(hs.Store.Db, "host.tenant_id", hs.tenantId), filterCriteria)
 	if tx == nil {
 		return nil, errors.New("postgres/host_store:Search() Unexpected Error. Could not build" +
 			" a gorm query object.")
@@ -171,12 +246,29 @@ func (hs *HostStore) Search(filterCriteria *models.HostFilterCriteria, infoFetch
 	} else {
 		for rows.Next() {
 			host := hvs.Host{}
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid); err != nil {
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &host.TenantId, &host.Lifecycle, hostCapabilities{&host}); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
 			hosts = append(hosts, &host)
 		}
 	}
+
+	hostIds := make([]uuid.UUID, 0, len(hosts))
+	for _, host := range hosts {
+		hostIds = append(hostIds, host.Id)
+	}
+	labels, err := searchLabels(hs.Store.Db, "host_label", "host_id", hostIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:Search() failed to retrieve Host labels")
+	}
+	maintenances, err := searchMaintenances(hs.Store.Db, hostIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:Search() failed to retrieve Host maintenance")
+	}
+	for _, host := range hosts {
+		host.Labels = labels[host.Id]
+		host.Maintenance = maintenances[host.Id]
+	}
 	return hosts, nil
 }
 
@@ -189,7 +281,7 @@ func buildHostSearchQuery(tx *gorm.DB, criteria *models.HostFilterCriteria) *gor
 		return nil
 	}
 
-	tx = tx.Model(&host{})
+	tx = tx.Model(&host{}).Select(hostFields)
 
 	if criteria == nil || reflect.DeepEqual(*criteria, models.HostFilterCriteria{}) {
 		tx = tx.Order("name asc")
@@ -210,6 +302,10 @@ func buildHostSearchQuery(tx *gorm.DB, criteria *models.HostFilterCriteria) *gor
 		tx = tx.Joins("join report on report.host_id = host.id AND report.trusted = ?", criteria.Trusted)
 	}
 
+	if criteria.Lifecycle != "" {
+		tx = tx.Where("host.lifecycle = ?", criteria.Lifecycle)
+	}
+
 	if criteria.OrderBy == models.Descending {
 		tx = tx.Order("name desc")
 	} else {
@@ -256,18 +352,18 @@ func getAdditionalHostInfo(criteria *models.HostInfoFetchCriteria, rows *sql.Row
 		host := hvs.Host{}
 		connectionStatus := hvs.HostStatusInformation{}
 		if criteria.GetTrustStatus && criteria.GetHostStatus {
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid,
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &host.TenantId, &host.Lifecycle, hostCapabilities{&host},
 				&host.Trusted, (*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
 			host.ConnectionStatus = &connectionStatus
 		} else if criteria.GetTrustStatus {
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid,
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &host.TenantId, &host.Lifecycle, hostCapabilities{&host},
 				&host.Trusted); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
 		} else if criteria.GetHostStatus {
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid,
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &host.TenantId, &host.Lifecycle, hostCapabilities{&host},
 				(*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
@@ -348,12 +444,39 @@ func (hs *HostStore) SearchFlavorgroups(hId uuid.UUID) ([]uuid.UUID, error) {
 	}()
 
 	var fgIds []uuid.UUID
+	linked := make(map[uuid.UUID]bool)
 	for rows.Next() {
 		var fgId uuid.UUID
 		if err := rows.Scan(&fgId); err != nil {
 			return nil, errors.Wrap(err, "postgres/host_store:SearchFlavorgroups() failed to scan record")
 		}
 		fgIds = append(fgIds, fgId)
+		linked[fgId] = true
+	}
+
+	// add the flavorgroups of the tenant of the host whose host selector matches its labels
+	labels, err := searchLabels(hs.Store.Db, "host_label", "host_id", []uuid.UUID{hId})
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:SearchFlavorgroups() failed to retrieve Host labels")
+	}
+	if len(labels[hId]) == 0 {
+		return fgIds, nil
+	}
+	var tenantIds []string
+	if err := hs.Store.Db.Model(&host{}).Where("id = ?", hId).Pluck("tenant_id", &tenantIds).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:SearchFlavorgroups() failed to retrieve Host tenant")
+	}
+	if len(tenantIds) == 0 {
+		return fgIds, nil
+	}
+	selectingFgIds, err := selectingFlavorgroups(hs.Store.Db, "host_selector", tenantIds[0], labels[hId])
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:SearchFlavorgroups() failed to retrieve selecting flavorgroups")
+	}
+	for _, fgId := range selectingFgIds {
+		if !linked[fgId] {
+			fgIds = append(fgIds, fgId)
+		}
 	}
 	return fgIds, nil
 }
@@ -374,7 +497,7 @@ func (hs *HostStore) AddTrustCacheFlavors(hId uuid.UUID, fIds []uuid.UUID) ([]uu
 		trustCacheValueArgs = append(trustCacheValueArgs, hId)
 	}
 
-	insertQuery := fmt.Sprintf("INSERT INTO trust_cache VALUES %s on conflict (flavor_id, host_id) do nothing", strings.Join(trustCacheValues, ","))
+	insertQuery := dialectOf(hs.Store.Db).insertIgnore("trust_cache", strings.Join(trustCacheValues, ","), "flavor_id", "host_id")
 	err := hs.Store.Db.Model(trustCache{}).Exec(insertQuery, trustCacheValueArgs...).Error
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/host_store:AddTrustCacheFlavors() failed to create trust cache")
@@ -416,7 +539,14 @@ func (hs *HostStore) RetrieveTrustCacheFlavors(hId, fgId uuid.UUID) ([]uuid.UUID
 		return nil, errors.New("postgres/host_store:RetrieveTrustCacheFlavors() Host ID and Flavorgroup ID must be set to get the list of flavors for a host belonging to a flavorgroup ID")
 	}
 
-	rows, err := hs.Store.Db.Model(&trustCache{}).Select("trust_cache.flavor_id").Joins("INNER JOIN flavorgroup_flavor ON trust_cache.flavor_id = flavorgroup_flavor.flavor_id").Where("flavorgroup_flavor.flavorgroup_id = ? AND trust_cache.host_id = ?", fgId, hId).Rows()
+	selectors, err := retrieveFlavorgroupSelectors(hs.Store.Db, fgId)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:RetrieveTrustCacheFlavors() failed to retrieve flavorgroup selectors")
+	}
+	condition, args := selectors.flavorsCondition("flavor.id", "flavor.tenant_id")
+
+	rows, err := hs.Store.Db.Model(&trustCache{}).Select("trust_cache.flavor_id").Joins("INNER JOIN flavor ON trust_cache.flavor_id = flavor.id").
+		Where("trust_cache.host_id = ?", hId).Where(condition, args...).Rows()
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/host_store:RetrieveTrustCacheFlavors() failed to retrieve records from db")
 	}
@@ -453,7 +583,7 @@ func (hs *HostStore) AddHostUniqueFlavors(hId uuid.UUID, fIds []uuid.UUID) ([]uu
 		uniqueFlavorsValueArgs = append(uniqueFlavorsValueArgs, fId)
 	}
 
-	insertQuery := fmt.Sprintf("INSERT INTO hostunique_flavor VALUES %s on conflict (host_id, flavor_id) do nothing", strings.Join(uniqueFlavorsValues, ","))
+	insertQuery := dialectOf(hs.Store.Db).insertIgnore("hostunique_flavor", strings.Join(uniqueFlavorsValues, ","), "host_id", "flavor_id")
 	err := hs.Store.Db.Model(hostuniqueFlavor{}).Exec(insertQuery, uniqueFlavorsValueArgs...).Error
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/host_store:AddHostUniqueFlavors() failed to add host unique flavors")
@@ -516,3 +646,91 @@ func (hs *HostStore) RetrieveDistinctUniqueFlavorParts(hId uuid.UUID) ([]string,
 	}
 	return uniqueFlavorParts, nil
 }
+
+// SetMaintenance puts the host in maintenance mode, the maintenance of a host already in maintenance is replaced
+func (hs *HostStore) SetMaintenance(hId uuid.UUID, maintenance *hvs.HostMaintenance) error {
+	defaultLog.Trace("postgres/host_store:SetMaintenance() Entering")
+	defer defaultLog.Trace("postgres/host_store:SetMaintenance() Leaving")
+
+	tx := hs.Store.Db.Begin()
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "postgres/host_store:SetMaintenance() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if err := tx.Where("host_id = ?", hId).Delete(&hostMaintenance{}).Error; err != nil {
+		return errors.Wrap(err, "postgres/host_store:SetMaintenance() failed to delete Host maintenance")
+	}
+	dbMaintenance := hostMaintenance{
+		HostId:    hId,
+		Reason:    maintenance.Reason,
+		StartedAt: maintenance.StartedAt,
+		ResumeAt:  maintenance.ResumeAt,
+	}
+	if err := tx.Create(&dbMaintenance).Error; err != nil {
+		return errors.Wrap(err, "postgres/host_store:SetMaintenance() failed to create Host maintenance")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return errors.Wrap(err, "postgres/host_store:SetMaintenance() failed to commit transaction")
+	}
+	return nil
+}
+
+// EndMaintenance ends the maintenance of the host, false is returned when the host was not in maintenance
+func (hs *HostStore) EndMaintenance(hId uuid.UUID) (bool, error) {
+	defaultLog.Trace("postgres/host_store:EndMaintenance() Entering")
+	defer defaultLog.Trace("postgres/host_store:EndMaintenance() Leaving")
+
+	db := hs.Store.Db.Where("host_id = ?", hId).Delete(&hostMaintenance{})
+	if db.Error != nil {
+		return false, errors.Wrap(db.Error, "postgres/host_store:EndMaintenance() failed to delete Host maintenance")
+	}
+	return db.RowsAffected > 0, nil
+}
+
+// EndElapsedMaintenances ends the maintenances whose resume time has passed and returns the ids of their hosts
+func (hs *HostStore) EndElapsedMaintenances(now time.Time) ([]uuid.UUID, error) {
+	defaultLog.Trace("postgres/host_store:EndElapsedMaintenances() Entering")
+	defer defaultLog.Trace("postgres/host_store:EndElapsedMaintenances() Leaving")
+
+	// the hosts in maintenance are few, the resume times are compared here rather than by each database
+	var dbMaintenances []hostMaintenance
+	if err := hs.Store.Db.Where("resume_at IS NOT NULL").Find(&dbMaintenances).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:EndElapsedMaintenances() failed to retrieve Host maintenances")
+	}
+	var hostIds []uuid.UUID
+	for _, dbMaintenance := range dbMaintenances {
+		if dbMaintenance.ResumeAt.After(now) {
+			continue
+		}
+		ended, err := hs.EndMaintenance(dbMaintenance.HostId)
+		if err != nil {
+			return hostIds, errors.Wrap(err, "postgres/host_store:EndElapsedMaintenances() failed to end Host maintenance")
+		}
+		// the maintenance may have been ended in the meantime
+		if ended {
+			hostIds = append(hostIds, dbMaintenance.HostId)
+		}
+	}
+	return hostIds, nil
+}
+
+// searchMaintenances returns the maintenances of the hosts in maintenance mode, keyed by host id
+func searchMaintenances(db *gorm.DB, hostIds []uuid.UUID) (map[uuid.UUID]*hvs.HostMaintenance, error) {
+	maintenances := make(map[uuid.UUID]*hvs.HostMaintenance)
+	if len(hostIds) == 0 {
+		return maintenances, nil
+	}
+	var dbMaintenances []hostMaintenance
+	if err := db.Where("host_id IN (?)", hostIds).Find(&dbMaintenances).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/host_store:searchMaintenances() failed to retrieve Host maintenances")
+	}
+	for _, dbMaintenance := range dbMaintenances {
+		maintenances[dbMaintenance.HostId] = &hvs.HostMaintenance{
+			Reason:    dbMaintenance.Reason,
+			StartedAt: dbMaintenance.StartedAt.UTC(),
+			ResumeAt:  dbMaintenance.ResumeAt,
+		}
+	}
+	return maintenances, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
@@ -170,6 +171,12 @@ func (hss *HostStatusStore) Persist(hs *hvs.HostStatus) error {
 		}
 	}
 
+	// the lifecycle state is only changed through UpdateLifecycleState
+	if hs.HostStatusInformation.LifecycleState == "" {
+		hs.HostStatusInformation.LifecycleState = oldHs.Status.LifecycleState
+		hs.HostStatusInformation.LifecycleStateChanged = oldHs.Status.LifecycleStateChanged
+	}
+
 	// record already exists, update the record
 	dbHostStatus := hostStatus{
 		ID:         oldHs.ID,
@@ -197,6 +204,118 @@ func (hss *HostStatusStore) Persist(hs *hvs.HostStatus) error {
 	return nil
 }
 
+// UpdateLifecycleState moves the host to the lifecycle state and records the transition in the lifecycle history, a
+// host status record is created if the host does not have one yet
+func (hss *HostStatusStore) UpdateLifecycleState(hostId uuid.UUID, state hvs.HostLifecycleState, reason string) error {
+	defaultLog.Trace("postgres/hoststatus_store:UpdateLifecycleState() Entering")
+	defer defaultLog.Trace("postgres/hoststatus_store:UpdateLifecycleState() Leaving")
+
+	if hostId == uuid.Nil {
+		return errors.New("postgres/hoststatus_store:UpdateLifecycleState() - HostID is missing")
+	}
+
+	oldHs := hostStatus{}
+	err := hss.Store.Db.Where("host_id = ?", hostId).First(&oldHs).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return errors.Wrap(err, "postgres/hoststatus_store:UpdateLifecycleState() failed to retrieve HostStatus")
+	}
+
+	from := oldHs.Status.LifecycleState
+	if from == state {
+		return nil
+	}
+	if !from.CanTransitionTo(state) {
+		return errors.Errorf("postgres/hoststatus_store:UpdateLifecycleState() Host %s can not transition from %s to %s",
+			hostId, from, state)
+	}
+
+	now := time.Now()
+	if oldHs.ID == uuid.Nil {
+		_, err = hss.Create(&hvs.HostStatus{
+			HostID: hostId,
+			HostStatusInformation: hvs.HostStatusInformation{
+				HostState:             hvs.HostStateUnknown,
+				LifecycleState:        state,
+				LifecycleStateChanged: &now,
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "postgres/hoststatus_store:UpdateLifecycleState() failed to create HostStatus")
+		}
+	} else {
+		status := hvs.HostStatusInformation(oldHs.Status)
+		status.LifecycleState = state
+		status.LifecycleStateChanged = &now
+		db := hss.Store.Db.Model(&hostStatus{}).Where("id = ?", oldHs.ID).Update("status", PGHostStatusInformation(status))
+		if db.Error != nil {
+			return errors.Wrap(db.Error, "postgres/hoststatus_store:UpdateLifecycleState() failed to update HostStatus")
+		}
+		// log to audit log
+		if hss.AuditLogWriter != nil {
+			before := hvs.HostStatus{
+				ID:                    oldHs.ID,
+				HostID:                oldHs.HostID,
+				Created:               oldHs.CreatedAt,
+				HostStatusInformation: hvs.HostStatusInformation(oldHs.Status),
+				HostManifest:          types.HostManifest(oldHs.HostReport),
+			}
+			after := before
+			after.HostStatusInformation = status
+			auditEntry, err := hss.AuditLogWriter.CreateEntry("update", &before, &after)
+			if err == nil {
+				hss.AuditLogWriter.Log(auditEntry)
+			}
+		}
+	}
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Wrap(err, "postgres/hoststatus_store:UpdateLifecycleState() failed to create new UUID")
+	}
+	transition := hostLifecycleTransition{
+		ID:        newUuid,
+		HostID:    hostId,
+		FromState: string(from),
+		ToState:   string(state),
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	if err := hss.Store.Db.Create(&transition).Error; err != nil {
+		return errors.Wrap(err, "postgres/hoststatus_store:UpdateLifecycleState() failed to record lifecycle transition")
+	}
+	return nil
+}
+
+// SearchLifecycleTransitions returns the lifecycle history of a host, most recent transition first
+func (hss *HostStatusStore) SearchLifecycleTransitions(hostId uuid.UUID, limit int) ([]hvs.HostLifecycleTransition, error) {
+	defaultLog.Trace("postgres/hoststatus_store:SearchLifecycleTransitions() Entering")
+	defer defaultLog.Trace("postgres/hoststatus_store:SearchLifecycleTransitions() Leaving")
+
+	if limit == 0 {
+		limit = constants.DefaultSearchResultRowLimit
+	}
+
+	var dbTransitions []hostLifecycleTransition
+	err := hss.Store.Db.Where("host_id = ?", hostId).Order("created DESC").Limit(limit).Find(&dbTransitions).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/hoststatus_store:SearchLifecycleTransitions() failed to retrieve records from db")
+	}
+
+	// setting to empty array
+	transitions := []hvs.HostLifecycleTransition{}
+	for _, t := range dbTransitions {
+		transitions = append(transitions, hvs.HostLifecycleTransition{
+			ID:      t.ID,
+			HostID:  t.HostID,
+			From:    hvs.HostLifecycleState(t.FromState),
+			To:      hvs.HostLifecycleState(t.ToState),
+			Reason:  t.Reason,
+			Created: t.CreatedAt,
+		})
+	}
+	return transitions, nil
+}
+
 func (hss *HostStatusStore) Delete(hostStatusId uuid.UUID) error {
 	defaultLog.Trace("postgres/hoststatus_store:Delete() Entering")
 	defer defaultLog.Trace("postgres/hoststatus_store:Delete() Leaving")
@@ -223,7 +342,9 @@ func (hss *HostStatusStore) FindHostIdsByKeyValue(key, value string) ([]uuid.UUI
 	defaultLog.Trace("postgres/hoststatus_store:FindHostIdsByKeyValue() Entering")
 	defer defaultLog.Trace("postgres/hoststatus_store:FindHostIdsByKeyValue() Leaving")
 
-	rows, err := hss.Store.Db.Raw("SELECT host_id FROM host_status WHERE host_report::text != 'null' AND host_report -> 'host_info' ->> ? = ?", key, value).Rows()
+	d := dialectOf(hss.Store.Db)
+	rows, err := hss.Store.Db.Raw("SELECT host_id FROM host_status WHERE "+d.jsonNotNull("host_report")+" AND "+
+		d.jsonTextAtParam("host_report", "host_info")+" = ?", key, value).Rows()
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/hoststatus_store:FindHostIdsByKeyValue() failed to retrieve records from db")
 	}
@@ -253,6 +374,8 @@ func buildHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFilterCr
 
 	var tableJoinString, additionalOptionsQueryString string
 
+	d := dialectOf(tx)
+
 	// define joins
 	auditLogAbbrv := "au"
 	if hsFilter.LatestPerHost {
@@ -265,7 +388,7 @@ func buildHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFilterCr
 
 	// Build table join string with host table if host identifier is set
 	if hsFilter.HostName != "" {
-		tableJoinString = fmt.Sprintf("INNER JOIN host h on CAST(h.id AS VARCHAR) = %s.data -> 'Columns' -> 1 ->> 'Value'", auditLogAbbrv)
+		tableJoinString = fmt.Sprintf("INNER JOIN host h on %s = %s", d.castToText("h.id"), d.jsonText(auditLogAbbrv+".data", "Columns", 1, "Value"))
 	}
 
 	//Build additional options query string is table join string set
@@ -287,29 +410,35 @@ func buildHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFilterCr
 	} else {
 		//Build host ID partial query string and add it to the additional options query string
 		if hsFilter.HostId != uuid.Nil {
-			hostIdQueryString := fmt.Sprintf("%s.data -> 'Columns' -> 1 ->> 'Value' = '%s'", auditLogAbbrv, hsFilter.HostId.String())
+			hostIdQueryString := fmt.Sprintf("%s = '%s'", d.jsonText(auditLogAbbrv+".data", "Columns", 1, "Value"), hsFilter.HostId.String())
 			additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, hostIdQueryString)
 		}
 
 		//Build host name partial query string and add it to the additional options query string
 		if hsFilter.HostName != "" {
-			hostNameQueryString := fmt.Sprintf("%s.data -> 'Columns' -> 4 -> 'Value' -> 'host_info' ->> 'host_name' = '%s'", auditLogAbbrv, hsFilter.HostName)
+			hostNameQueryString := fmt.Sprintf("%s = '%s'", d.jsonText(auditLogAbbrv+".data", "Columns", 4, "Value", "host_info", "host_name"), hsFilter.HostName)
 			additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, hostNameQueryString)
 		}
 
 		//Build hardware uuid partial query string and add it to the additional options query string
 		if hsFilter.HostHardwareId != uuid.Nil {
-			hostHWUUIDQueryString := fmt.Sprintf("LOWER(%s.data -> 'Columns' -> 4 -> 'Value' -> 'host_info' ->> 'hardware_uuid') = '%s' ", auditLogAbbrv, strings.ToLower(hsFilter.HostHardwareId.String()))
+			hostHWUUIDQueryString := fmt.Sprintf("LOWER(%s) = '%s' ", d.jsonText(auditLogAbbrv+".data", "Columns", 4, "Value", "host_info", "hardware_uuid"), strings.ToLower(hsFilter.HostHardwareId.String()))
 			additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, hostHWUUIDQueryString)
 		}
 	}
 
 	//Build host state partial query string and add it to the additional options query string
 	if hsFilter.HostStatus != "" {
-		hostStateQueryString := fmt.Sprintf("%s.data -> 'Columns' -> 2 -> 'Value' ->> 'host_state' = '%s'", auditLogAbbrv, strings.ToUpper(hsFilter.HostStatus))
+		hostStateQueryString := fmt.Sprintf("%s = '%s'", d.jsonText(auditLogAbbrv+".data", "Columns", 2, "Value", "host_state"), strings.ToUpper(hsFilter.HostStatus))
 		additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, hostStateQueryString)
 	}
 
+	//Build lifecycle state partial query string and add it to the additional options query string
+	if hsFilter.LifecycleState != "" {
+		lifecycleStateQueryString := fmt.Sprintf("%s = '%s'", d.jsonText(auditLogAbbrv+".data", "Columns", 2, "Value", "lifecycle_state"), hsFilter.LifecycleState)
+		additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, lifecycleStateQueryString)
+	}
+
 	//Build host status ID partial query string and add it to the additional options query string
 	if hsFilter.Id != uuid.Nil {
 		hostStatusIDQueryString := fmt.Sprintf("%s.entity_id = '%s'", auditLogAbbrv, hsFilter.Id.String())
@@ -326,13 +455,14 @@ func buildHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFilterCr
 	if !hsFilter.FromDate.IsZero() || !hsFilter.ToDate.IsZero() {
 		// determine what dates params are set - try all combinations till one matches up
 		if !hsFilter.FromDate.IsZero() && hsFilter.ToDate.IsZero() {
-			fromDateQueryString := fmt.Sprintf("CAST(%s.created AS TIMESTAMP) >= CAST('%s' AS TIMESTAMP)", auditLogAbbrv, hsFilter.FromDate.Format(constants.ParamDateTimeFormatUTC))
+			fromDateQueryString := fmt.Sprintf("%s >= %s", d.castToTimestamp(auditLogAbbrv+".created"), d.castToTimestamp("'"+hsFilter.FromDate.Format(constants.ParamDateTimeFormatUTC)+"'"))
 			additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, fromDateQueryString)
 		} else if hsFilter.FromDate.IsZero() && !hsFilter.ToDate.IsZero() {
-			toDateQueryString := fmt.Sprintf("CAST(%s.created AS TIMESTAMP) <= CAST('%s' AS TIMESTAMP)", auditLogAbbrv, hsFilter.ToDate.Format(constants.ParamDateTimeFormatUTC))
+			toDateQueryString := fmt.Sprintf("%s <= %s", d.castToTimestamp(auditLogAbbrv+".created"), d.castToTimestamp("'"+hsFilter.ToDate.Format(constants.ParamDateTimeFormatUTC)+"'"))
 			additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, toDateQueryString)
 		} else if !hsFilter.FromDate.IsZero() && !hsFilter.ToDate.IsZero() {
-			fromToDateQueryString := fmt.Sprintf("CAST(%s.created AS TIMESTAMP) >= CAST('%s' AS TIMESTAMP) AND CAST(%s.created AS TIMESTAMP) <= CAST('%s' AS TIMESTAMP) ", auditLogAbbrv, hsFilter.FromDate.Format(constants.ParamDateTimeFormatUTC), auditLogAbbrv, hsFilter.ToDate.Format(constants.ParamDateTimeFormatUTC))
+			fromToDateQueryString := fmt.Sprintf("%s >= %s AND %s <= %s ", d.castToTimestamp(auditLogAbbrv+".created"), d.castToTimestamp("'"+hsFilter.FromDate.Format(constants.ParamDateTimeFormatUTC)+"'"),
+				d.castToTimestamp(auditLogAbbrv+".created"), d.castToTimestamp("'"+hsFilter.ToDate.Format(constants.ParamDateTimeFormatUTC)+"'"))
 			additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, fromToDateQueryString)
 		}
 	}
@@ -406,7 +536,12 @@ func buildLatestHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFi
 
 	// Host Connection Status
 	if hsFilter.HostStatus != "" {
-		tx = tx.Where(`status @> '{"host_state": "` + strings.ToUpper(hsFilter.HostStatus) + `"}'`)
+		tx = tx.Where(dialectOf(tx).jsonText("status", "host_state") + " = '" + strings.ToUpper(hsFilter.HostStatus) + "'")
+	}
+
+	// Host Lifecycle State
+	if hsFilter.LifecycleState != "" {
+		tx = tx.Where(dialectOf(tx).jsonText("status", "lifecycle_state")+" = ?", string(hsFilter.LifecycleState))
 	}
 
 	// Apply default row limit when called internally
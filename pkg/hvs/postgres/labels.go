@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+//
+// The members of a flavorgroup are the flavors and hosts linked to it, and the flavors and hosts of its tenant whose
+// labels match its flavor and host selectors. The labels are kept in the flavor_label and host_label tables, indexed
+// by key and value, so that the selectors are evaluated by the database.
+//
+
+// labelSelector scans a label selector column into a flavorgroup, the flavorgroups without a selector have a null
+// column
+type labelSelector struct {
+	selector **hvs.LabelSelector
+}
+
+func (ls labelSelector) Scan(value interface{}) error {
+	if value == nil {
+		*ls.selector = nil
+		return nil
+	}
+	var selector PGLabelSelector
+	if err := selector.Scan(value); err != nil {
+		return err
+	}
+	*ls.selector = (*hvs.LabelSelector)(&selector)
+	return nil
+}
+
+// flavorgroupSelectors are the selectors of a flavorgroup and the tenant of the flavors and hosts they select
+type flavorgroupSelectors struct {
+	fgId           uuid.UUID
+	tenantId       string
+	flavorSelector *hvs.LabelSelector
+	hostSelector   *hvs.LabelSelector
+}
+
+// retrieveFlavorgroupSelectors returns the selectors of the flavorgroup, a flavorgroup that does not exist selects
+// nothing
+func retrieveFlavorgroupSelectors(db *gorm.DB, fgId uuid.UUID) (*flavorgroupSelectors, error) {
+	selectors := flavorgroupSelectors{fgId: fgId}
+	row := db.Model(&flavorGroup{}).Select("tenant_id, flavor_selector, host_selector").Where("id = ?", fgId).Row()
+	if err := row.Scan(&selectors.tenantId, labelSelector{&selectors.flavorSelector}, labelSelector{&selectors.hostSelector}); err != nil {
+		if err == sql.ErrNoRows {
+			return &selectors, nil
+		}
+		return nil, errors.Wrap(err, "postgres/labels:retrieveFlavorgroupSelectors() failed to scan record")
+	}
+	return &selectors, nil
+}
+
+// labelSelectorCondition returns the condition on the id column selecting the rows whose labels, kept in the label
+// table, satisfy all the requirements of the selector
+func labelSelectorCondition(idColumn, labelTable, labelIdColumn string, selector *hvs.LabelSelector) (string, []interface{}) {
+	labelQuery := fmt.Sprintf("SELECT %s FROM %s WHERE label_key = ?", labelIdColumn, labelTable)
+
+	var conditions []string
+	var args []interface{}
+	keys := make([]string, 0, len(selector.MatchLabels))
+	for key := range selector.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s AND label_value = ?)", idColumn, labelQuery))
+		args = append(args, key, selector.MatchLabels[key])
+	}
+	for _, requirement := range selector.MatchExpressions {
+		switch requirement.Operator {
+		case hvs.LabelSelectorOpIn:
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s AND label_value IN (?))", idColumn, labelQuery))
+			args = append(args, requirement.Key, requirement.Values)
+		case hvs.LabelSelectorOpNotIn:
+			conditions = append(conditions, fmt.Sprintf("%s NOT IN (%s AND label_value IN (?))", idColumn, labelQuery))
+			args = append(args, requirement.Key, requirement.Values)
+		case hvs.LabelSelectorOpExists:
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s)", idColumn, labelQuery))
+			args = append(args, requirement.Key)
+		case hvs.LabelSelectorOpDoesNotExist:
+			conditions = append(conditions, fmt.Sprintf("%s NOT IN (%s)", idColumn, labelQuery))
+			args = append(args, requirement.Key)
+		default:
+			// the selectors are validated when the flavorgroup is created, an unknown operator selects nothing
+			conditions = append(conditions, "1 = 0")
+		}
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args
+}
+
+// flavorsCondition returns the condition on the flavor columns selecting the flavors of the flavorgroup
+func (selectors *flavorgroupSelectors) flavorsCondition(idColumn, tenantColumn string) (string, []interface{}) {
+	condition := idColumn + " IN (SELECT flavor_id FROM flavorgroup_flavor WHERE flavorgroup_id = ?)"
+	args := []interface{}{selectors.fgId}
+	if !selectors.flavorSelector.IsEmpty() {
+		selectorCondition, selectorArgs := labelSelectorCondition(idColumn, "flavor_label", "flavor_id", selectors.flavorSelector)
+		condition = fmt.Sprintf("(%s OR (%s = ? AND %s))", condition, tenantColumn, selectorCondition)
+		args = append(append(args, selectors.tenantId), selectorArgs...)
+	}
+	return condition, args
+}
+
+// hostsCondition returns the condition on the host columns selecting the hosts of the flavorgroup
+func (selectors *flavorgroupSelectors) hostsCondition(idColumn, tenantColumn string) (string, []interface{}) {
+	condition := idColumn + " IN (SELECT host_id FROM host_flavorgroup WHERE flavorgroup_id = ?)"
+	args := []interface{}{selectors.fgId}
+	if !selectors.hostSelector.IsEmpty() {
+		selectorCondition, selectorArgs := labelSelectorCondition(idColumn, "host_label", "host_id", selectors.hostSelector)
+		condition = fmt.Sprintf("(%s OR (%s = ? AND %s))", condition, tenantColumn, selectorCondition)
+		args = append(append(args, selectors.tenantId), selectorArgs...)
+	}
+	return condition, args
+}
+
+// selectingFlavorgroups returns the flavorgroups of the tenant whose selector, in the selector column, matches the
+// labels of a flavor or host
+func selectingFlavorgroups(db *gorm.DB, selectorColumn, tenantId string, labels map[string]string) ([]uuid.UUID, error) {
+	rows, err := db.Model(&flavorGroup{}).Select("id, "+selectorColumn).
+		Where("tenant_id = ? AND "+selectorColumn+" IS NOT NULL", tenantId).Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/labels:selectingFlavorgroups() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	var fgIds []uuid.UUID
+	for rows.Next() {
+		var fgId uuid.UUID
+		var selector *hvs.LabelSelector
+		if err := rows.Scan(&fgId, labelSelector{&selector}); err != nil {
+			return nil, errors.Wrap(err, "postgres/labels:selectingFlavorgroups() failed to scan record")
+		}
+		if selector.Matches(labels) {
+			fgIds = append(fgIds, fgId)
+		}
+	}
+	return fgIds, nil
+}
+
+// replaceLabels replaces the labels of a flavor or host, the rows of the label table are keyed by the id column
+func replaceLabels(db *gorm.DB, labelTable, idColumn string, id uuid.UUID, labels map[string]string) error {
+	if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", labelTable, idColumn), id).Error; err != nil {
+		return errors.Wrapf(err, "postgres/labels:replaceLabels() failed to delete %s", labelTable)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	labelValues := []string{}
+	labelValueArgs := []interface{}{}
+	for key, value := range labels {
+		labelValues = append(labelValues, "(?, ?, ?)")
+		labelValueArgs = append(labelValueArgs, id, key, value)
+	}
+	insertQuery := fmt.Sprintf("INSERT INTO %s (%s, label_key, label_value) VALUES %s", labelTable, idColumn, strings.Join(labelValues, ","))
+	if err := db.Exec(insertQuery, labelValueArgs...).Error; err != nil {
+		return errors.Wrapf(err, "postgres/labels:replaceLabels() failed to create %s", labelTable)
+	}
+	return nil
+}
+
+// searchLabels returns the labels of the flavors or hosts, keyed by their id
+func searchLabels(db *gorm.DB, labelTable, idColumn string, ids []uuid.UUID) (map[uuid.UUID]map[string]string, error) {
+	labels := make(map[uuid.UUID]map[string]string)
+	if len(ids) == 0 {
+		return labels, nil
+	}
+	rows, err := db.Table(labelTable).Select(idColumn+", label_key, label_value").Where(idColumn+" IN (?)", ids).Rows()
+	if err != nil {
+		return nil, errors.Wrapf(err, "postgres/labels:searchLabels() failed to retrieve %s", labelTable)
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var key, value string
+		if err := rows.Scan(&id, &key, &value); err != nil {
+			return nil, errors.Wrapf(err, "postgres/labels:searchLabels() failed to scan %s", labelTable)
+		}
+		if labels[id] == nil {
+			labels[id] = make(map[string]string)
+		}
+		labels[id][key] = value
+	}
+	return labels, nil
+}
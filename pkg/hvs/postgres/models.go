@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 )
 
@@ -22,12 +23,22 @@ type (
 	PGFlavorMatchPolicies   hvs.FlavorMatchPolicies
 	PGHostManifest          types.HostManifest
 	PGHostStatusInformation hvs.HostStatusInformation
+	PGHostCapabilities      taModel.HostCapabilities
 	PGFlavorContent         hvs.Flavor
+	PGFlavorSignatures      []string
+	PGComplianceProfiles    []hvs.ComplianceProfile
+	PGLabelSelector         hvs.LabelSelector
 
 	flavorGroup struct {
 		ID                    uuid.UUID             `json:"id" gorm:"primary_key;type:uuid"`
 		Name                  string                `json:"name" gorm:"type:varchar(255);not null;index:idx_flavorgroup_name"`
 		FlavorTypeMatchPolicy PGFlavorMatchPolicies `json:"flavor_type_match_policy,omitempty" sql:"type:JSONB"`
+		StrictEventLog        bool                  `json:"strict_event_log" gorm:"not null;default:false"`
+		TenantId              string                `json:"tenant_id" gorm:"type:varchar(64);not null;default:'';index:idx_flavorgroup_tenant_id"`
+		ParentId              *uuid.UUID            `json:"parent_id,omitempty" gorm:"type:uuid;index:idx_flavorgroup_parent_id"`
+		ComplianceProfiles    PGComplianceProfiles  `json:"compliance_profiles,omitempty" sql:"type:JSONB"`
+		FlavorSelector        *PGLabelSelector      `json:"flavor_selector,omitempty" sql:"type:JSONB"`
+		HostSelector          *PGLabelSelector      `json:"host_selector,omitempty" sql:"type:JSONB"`
 	}
 
 	flavor struct {
@@ -37,6 +48,9 @@ type (
 		Label      string          `gorm:"unique;not null"`
 		FlavorPart string          `json:"flavor_part"`
 		Signature  string          `json:"signature"`
+		// AdditionalSignatures are the signatures of the flavor co-signers
+		AdditionalSignatures PGFlavorSignatures `json:"additional_signatures" sql:"type:JSONB"`
+		TenantId             string             `json:"tenant_id" gorm:"type:varchar(64);not null;default:'';index:idx_flavor_tenant_id"`
 	}
 
 	host struct {
@@ -45,6 +59,10 @@ type (
 		Description      string
 		ConnectionString string        `gorm:"not null"`
 		HardwareUuid     models.HwUUID `gorm:"type:uuid;index:idx_host_hardware_uuid"`
+		TenantId         string        `gorm:"type:varchar(64);not null;default:'';index:idx_host_tenant_id"`
+		Lifecycle        string        `gorm:"type:varchar(32);not null;default:'REGISTERED'"`
+		// Capabilities are null until the host has been reached
+		Capabilities *PGHostCapabilities `gorm:"column:capabilities" sql:"type:JSONB"`
 	}
 
 	hostFlavorgroup struct {
@@ -57,6 +75,28 @@ type (
 		FlavorId      uuid.UUID `gorm:"type:uuid REFERENCES flavor(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;unique_index:idx_flavor_flavorgroup"`
 	}
 
+	// flavorLabel holds the labels of the flavor meta, indexed to evaluate the flavor selectors of the flavorgroups
+	flavorLabel struct {
+		FlavorId   uuid.UUID `gorm:"type:uuid REFERENCES flavor(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;unique_index:idx_flavor_label"`
+		LabelKey   string    `gorm:"type:varchar(317);not null;unique_index:idx_flavor_label;index:idx_flavor_label_value"`
+		LabelValue string    `gorm:"type:varchar(63);not null;index:idx_flavor_label_value"`
+	}
+
+	// hostLabel holds the labels of the hosts, indexed to evaluate the host selectors of the flavorgroups
+	hostLabel struct {
+		HostId     uuid.UUID `gorm:"type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;unique_index:idx_host_label"`
+		LabelKey   string    `gorm:"type:varchar(317);not null;unique_index:idx_host_label;index:idx_host_label_value"`
+		LabelValue string    `gorm:"type:varchar(63);not null;index:idx_host_label_value"`
+	}
+
+	// hostMaintenance holds the hosts in maintenance mode, the record of a host is removed when its maintenance ends
+	hostMaintenance struct {
+		HostId    uuid.UUID  `gorm:"type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE;primary_key"`
+		Reason    string     `gorm:"type:varchar(255);not null;default:''"`
+		StartedAt time.Time  `gorm:"not null"`
+		ResumeAt  *time.Time `gorm:"index:idx_host_maintenance_resume_at"`
+	}
+
 	trustCache struct {
 		FlavorId uuid.UUID `gorm:"type:uuid REFERENCES flavor(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;unique_index:idx_flavor_host"`
 		HostId   uuid.UUID `gorm:"type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;unique_index:idx_flavor_host"`
@@ -85,6 +125,16 @@ type (
 		CreatedAt  time.Time               `gorm:"column:created;not null"`
 	}
 
+	// hostLifecycleTransition holds the history of the lifecycle states of the hosts
+	hostLifecycleTransition struct {
+		ID        uuid.UUID `gorm:"primary_key;type:uuid"`
+		HostID    uuid.UUID `sql:"type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;index:idx_host_lifecycle_transition_host_id"`
+		FromState string    `gorm:"column:from_state;type:varchar(32);not null;default:''"`
+		ToState   string    `gorm:"column:to_state;type:varchar(32);not null"`
+		Reason    string    `gorm:"column:reason"`
+		CreatedAt time.Time `gorm:"column:created;not null"`
+	}
+
 	esxiCluster struct {
 		Id               uuid.UUID `gorm:"primary_key;type:uuid"`
 		ConnectionString string    `gorm:"column:connection_string;not null"`
@@ -117,6 +167,30 @@ type (
 		Saml        string        `gorm:"column:saml;not null"`
 	}
 
+	// reportFlavorPart is the trust of a flavor part in a report, stored apart from the trust report to search
+	// the reports by the trust of their flavor parts
+	reportFlavorPart struct {
+		ReportID   uuid.UUID  `gorm:"column:report_id;type:uuid REFERENCES report(Id) ON UPDATE CASCADE ON DELETE CASCADE;primary_key"`
+		FlavorPart string     `gorm:"type:varchar(32);primary_key"`
+		Trusted    bool       `gorm:"not null;index:idx_report_flavor_part_trusted"`
+		ValidUntil *time.Time `gorm:"column:valid_until"`
+	}
+
+	// trustSummary counts the trusted and untrusted reports created for a host in an hour, trustSummaryFault the
+	// faults of its untrusted reports by fault name, for the trust history of the host
+	trustSummary struct {
+		HostId           uuid.UUID `gorm:"column:host_id;type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE;primary_key"`
+		Hour             time.Time `gorm:"primary_key;index:idx_trust_summary_hour"`
+		TrustedReports   int64     `gorm:"not null;default:0"`
+		UntrustedReports int64     `gorm:"not null;default:0"`
+	}
+	trustSummaryFault struct {
+		HostId uuid.UUID `gorm:"column:host_id;type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE;primary_key"`
+		Hour   time.Time `gorm:"primary_key;index:idx_trust_summary_fault_hour"`
+		Fault  string    `gorm:"type:varchar(255);primary_key"`
+		Faults int64     `gorm:"not null;default:0"`
+	}
+
 	tpmEndorsement struct {
 		ID                uuid.UUID `gorm:"primary_key;type:uuid"`
 		HardwareUUID      uuid.UUID `gorm:"column:hardware_uuid;not null;type:uuid"`
@@ -127,6 +201,63 @@ type (
 		CertificateDigest string    `gorm:"column:certificate_digest;not null"`
 	}
 
+	platformCertificate struct {
+		ID                  uuid.UUID `gorm:"primary_key;type:uuid"`
+		HardwareUUID        uuid.UUID `gorm:"column:hardware_uuid;not null;type:uuid;index:idx_platform_certificate_hardware_uuid"`
+		Certificate         string    `gorm:"column:certificate;not null"`
+		Issuer              string    `gorm:"column:issuer;not null"`
+		Manufacturer        string    `gorm:"column:manufacturer"`
+		Model               string    `gorm:"column:model"`
+		Serial              string    `gorm:"column:serial"`
+		Version             string    `gorm:"column:version"`
+		CertificateDigest   string    `gorm:"column:certificate_digest;not null"`
+		EkCertificateDigest string    `gorm:"column:ek_certificate_digest;not null"`
+		NotBefore           time.Time `gorm:"column:not_before"`
+		NotAfter            time.Time `gorm:"column:not_after"`
+		Revoked             bool      `gorm:"column:revoked"`
+	}
+
+	PGFlavorLearning hvs.FlavorLearning
+	flavorLearning   struct {
+		ID      uuid.UUID        `gorm:"primary_key;type:uuid"`
+		Label   string           `gorm:"column:label;not null;unique"`
+		Content PGFlavorLearning `gorm:"column:content;not null" sql:"type:JSONB"`
+	}
+
+	PGFlavorPrune hvs.FlavorPrune
+	// PGRetiredFlavors are the flavor records retired by a prune, with the flavorgroups they were linked to
+	PGRetiredFlavors []retiredFlavor
+	retiredFlavor    struct {
+		Flavor         flavor      `json:"flavor"`
+		FlavorgroupIds []uuid.UUID `json:"flavorgroup_ids,omitempty"`
+	}
+	flavorPrune struct {
+		ID             uuid.UUID        `gorm:"primary_key;type:uuid"`
+		TenantId       string           `gorm:"type:varchar(64);not null;default:'';index:idx_flavor_prune_tenant_id"`
+		CreatedAt      time.Time        `gorm:"column:created;not null"`
+		Content        PGFlavorPrune    `gorm:"column:content;not null" sql:"type:JSONB"`
+		RetiredFlavors PGRetiredFlavors `gorm:"column:retired_flavors;not null" sql:"type:JSONB"`
+	}
+
+	auditEvent struct {
+		ID         uuid.UUID    `gorm:"primary_key;type:uuid"`
+		CreatedAt  time.Time    `gorm:"column:created;not null;index:idx_audit_event_created"`
+		Actor      string       `gorm:"type:varchar(255);index:idx_audit_event_actor"`
+		Method     string       `gorm:"type:varchar(10)"`
+		EntityType string       `gorm:"type:varchar(255)"`
+		EntityID   *uuid.UUID   `gorm:"type:uuid;index:idx_audit_event_entity_id"`
+		Content    PGAuditEvent `gorm:"column:content;not null" sql:"type:JSONB"`
+	}
+	PGAuditEvent hvs.AuditEvent
+
+	tenantUsage struct {
+		TenantId          string    `gorm:"type:varchar(64);primary_key"`
+		Identity          string    `gorm:"type:varchar(255);primary_key"`
+		Day               time.Time `gorm:"primary_key"`
+		ApiCalls          int64     `gorm:"not null;default:0"`
+		ReportGenerations int64     `gorm:"not null;default:0"`
+	}
+
 	//TODO add triggers
 	PGAuditLogData models.AuditTableData
 	auditLogEntry  struct {
@@ -146,6 +277,7 @@ type (
 		Issuer       string    `gorm:"not null"`
 		NotBefore    time.Time `gorm:"not null; column:notbefore"`
 		NotAfter     time.Time `gorm:"not null; column:notafter"`
+		Revoked      bool      `gorm:"column:revoked; type:boolean not null default false"`
 	}
 )
 
@@ -190,6 +322,18 @@ func (hm *PGHostStatusInformation) Scan(value interface{}) error {
 	return json.Unmarshal(b, &hm)
 }
 
+func (hc PGHostCapabilities) Value() (driver.Value, error) {
+	return json.Marshal(hc)
+}
+
+func (hc *PGHostCapabilities) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGHostCapabilities_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &hc)
+}
+
 func (fmp PGFlavorMatchPolicies) Value() (driver.Value, error) {
 	return json.Marshal(fmp)
 }
@@ -214,6 +358,18 @@ func (trp *PGTrustReport) Scan(value interface{}) error {
 	return json.Unmarshal(b, &trp)
 }
 
+func (ae PGAuditEvent) Value() (driver.Value, error) {
+	return json.Marshal(ae)
+}
+
+func (ae *PGAuditEvent) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGAuditEvent_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &ae)
+}
+
 func (alp PGAuditLogData) Value() (driver.Value, error) {
 	return json.Marshal(alp)
 }
@@ -237,3 +393,85 @@ func (fl *PGFlavorContent) Scan(value interface{}) error {
 	}
 	return json.Unmarshal(b, &fl)
 }
+
+func (fs PGFlavorSignatures) Value() (driver.Value, error) {
+	return json.Marshal(fs)
+}
+
+func (fs *PGFlavorSignatures) Scan(value interface{}) error {
+	// the flavors created before the co-signers were supported have no additional signatures
+	if value == nil {
+		*fs = nil
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGFlavorSignatures_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, fs)
+}
+
+func (fp PGFlavorPrune) Value() (driver.Value, error) {
+	return json.Marshal(fp)
+}
+
+func (fp *PGFlavorPrune) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGFlavorPrune_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &fp)
+}
+
+func (rf PGRetiredFlavors) Value() (driver.Value, error) {
+	return json.Marshal(rf)
+}
+
+func (rf *PGRetiredFlavors) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGRetiredFlavors_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, rf)
+}
+
+func (fl PGFlavorLearning) Value() (driver.Value, error) {
+	return json.Marshal(fl)
+}
+
+func (fl *PGFlavorLearning) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGFlavorLearning_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &fl)
+}
+
+func (cp PGComplianceProfiles) Value() (driver.Value, error) {
+	return json.Marshal(cp)
+}
+
+func (cp *PGComplianceProfiles) Scan(value interface{}) error {
+	// the flavorgroups created before the compliance profiles were supported have no profiles
+	if value == nil {
+		*cp = nil
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGComplianceProfiles_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, cp)
+}
+
+func (ls PGLabelSelector) Value() (driver.Value, error) {
+	return json.Marshal(ls)
+}
+
+func (ls *PGLabelSelector) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGLabelSelector_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, ls)
+}
@@ -0,0 +1,367 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// mysqlTLSConfigName is the name the TLS configuration verifying the database server is registered with
+const mysqlTLSConfigName = "hvs-db"
+
+// registerMySQLTLSConfig registers the TLS configuration trusting the CA certificate of cfg with the MySQL driver,
+// it is only set when HVS is built with the mysql build tag
+var registerMySQLTLSConfig func(cfg *Config) error
+
+// mysqlDialect supports MySQL 5.7+ and MariaDB 10.2+, uuids are stored as CHAR(36) and the JSONB columns of
+// postgres as JSON
+type mysqlDialect struct{}
+
+func (mysqlDialect) connectionString(cfg *Config) (string, error) {
+	if registerMySQLTLSConfig == nil {
+		return "", errors.New("HVS is built without MySQL support, rebuild it with the mysql build tag")
+	}
+
+	var tlsParam string
+	cfg.SslMode = strings.TrimSpace(strings.ToLower(cfg.SslMode))
+	switch cfg.SslMode {
+	case constants.SslModeAllow, constants.SslModePrefer:
+		tlsParam = "preferred"
+	case constants.SslModeRequire:
+		tlsParam = "skip-verify"
+	default:
+		if err := registerMySQLTLSConfig(cfg); err != nil {
+			return "", errors.Wrap(err, "Error configuring TLS connection to MySQL")
+		}
+		tlsParam = mysqlTLSConfigName
+	}
+	params := url.Values{}
+	params.Set("parseTime", "true")
+	params.Set("loc", "UTC")
+	params.Set("charset", "utf8mb4")
+	params.Set("tls", tlsParam)
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Dbname, params.Encode()), nil
+}
+
+func (mysqlDialect) migrate(db *gorm.DB) error {
+	for _, ddl := range mysqlSchema {
+		if err := db.Exec(ddl).Error; err != nil {
+			return errors.Wrapf(err, "Error running migration: %s", strings.Fields(ddl)[5])
+		}
+	}
+	return nil
+}
+
+// jsonPath returns the MySQL JSON path of the path elements, keys are quoted as the audit log uses capitalized keys
+func (mysqlDialect) jsonPath(path ...interface{}) string {
+	jsonPath := "$"
+	for _, key := range path {
+		if index, ok := key.(int); ok {
+			jsonPath = fmt.Sprintf("%s[%d]", jsonPath, index)
+		} else {
+			jsonPath = fmt.Sprintf(`%s."%s"`, jsonPath, key)
+		}
+	}
+	return jsonPath
+}
+
+func (d mysqlDialect) jsonText(column string, path ...interface{}) string {
+	return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, '%s'))", column, d.jsonPath(path...))
+}
+
+func (d mysqlDialect) jsonTextAtParam(column string, path ...interface{}) string {
+	return fmt.Sprintf(`JSON_UNQUOTE(JSON_EXTRACT(%s, CONCAT('%s."', ?, '"')))`, column, d.jsonPath(path...))
+}
+
+func (mysqlDialect) jsonNotNull(column string) string {
+	return "JSON_TYPE(" + column + ") != 'NULL'"
+}
+
+func (d mysqlDialect) jsonSet(column, key string) string {
+	return fmt.Sprintf("JSON_SET(%s, '%s', CAST(? AS JSON))", column, d.jsonPath(key))
+}
+
+func (mysqlDialect) castToText(expr string) string {
+	return "CAST(" + expr + " AS CHAR)"
+}
+
+func (mysqlDialect) castToUUID(expr string) string {
+	return expr
+}
+
+func (mysqlDialect) castToTimestamp(expr string) string {
+	return "CAST(" + expr + " AS DATETIME(6))"
+}
+
+func (mysqlDialect) insertIgnore(table, values string, conflictColumns ...string) string {
+	return fmt.Sprintf("INSERT IGNORE INTO %s VALUES %s", table, values)
+}
+
+func (mysqlDialect) isDuplicateKeyError(err error) bool {
+	return strings.Contains(err.Error(), "Error 1062")
+}
+
+// mysqlSchema creates the tables of the models, in the column order of the postgres tables created by gorm
+var mysqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS flavor_group (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		flavor_type_match_policy JSON,
+		strict_event_log BOOLEAN NOT NULL DEFAULT FALSE,
+		tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+		parent_id CHAR(36),
+		compliance_profiles JSON,
+		flavor_selector JSON,
+		host_selector JSON,
+		INDEX idx_flavorgroup_name (name),
+		INDEX idx_flavorgroup_tenant_id (tenant_id),
+		INDEX idx_flavorgroup_parent_id (parent_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		description TEXT,
+		connection_string TEXT NOT NULL,
+		hardware_uuid CHAR(36),
+		tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+		lifecycle VARCHAR(32) NOT NULL DEFAULT 'REGISTERED',
+		capabilities JSON,
+		INDEX idx_host_hardware_uuid (hardware_uuid),
+		INDEX idx_host_tenant_id (tenant_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS flavor (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		content JSON,
+		created_at DATETIME(6),
+		label VARCHAR(255) NOT NULL UNIQUE,
+		flavor_part VARCHAR(255),
+		signature TEXT,
+		additional_signatures JSON,
+		tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+		INDEX idx_flavor_tenant_id (tenant_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS trust_cache (
+		flavor_id CHAR(36) NOT NULL,
+		host_id CHAR(36) NOT NULL,
+		UNIQUE INDEX idx_flavor_host (flavor_id, host_id),
+		FOREIGN KEY (flavor_id) REFERENCES flavor(id) ON UPDATE CASCADE ON DELETE CASCADE,
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS hostunique_flavor (
+		host_id CHAR(36) NOT NULL,
+		flavor_id CHAR(36) NOT NULL,
+		UNIQUE INDEX idx_hostunique_flavor (host_id, flavor_id),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE,
+		FOREIGN KEY (flavor_id) REFERENCES flavor(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS flavorgroup_flavor (
+		flavorgroup_id CHAR(36) NOT NULL,
+		flavor_id CHAR(36) NOT NULL,
+		UNIQUE INDEX idx_flavor_flavorgroup (flavorgroup_id, flavor_id),
+		FOREIGN KEY (flavorgroup_id) REFERENCES flavor_group(id) ON UPDATE CASCADE ON DELETE CASCADE,
+		FOREIGN KEY (flavor_id) REFERENCES flavor(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS flavor_label (
+		flavor_id CHAR(36) NOT NULL,
+		label_key VARCHAR(317) NOT NULL,
+		label_value VARCHAR(63) NOT NULL,
+		UNIQUE INDEX idx_flavor_label (flavor_id, label_key),
+		INDEX idx_flavor_label_value (label_key, label_value),
+		FOREIGN KEY (flavor_id) REFERENCES flavor(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host_status (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		host_id CHAR(36) NOT NULL,
+		status JSON,
+		host_report JSON,
+		created DATETIME(6) NOT NULL,
+		INDEX idx_host_status_host_id (host_id),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host_lifecycle_transition (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		host_id CHAR(36) NOT NULL,
+		from_state VARCHAR(32) NOT NULL DEFAULT '',
+		to_state VARCHAR(32) NOT NULL,
+		reason TEXT,
+		created DATETIME(6) NOT NULL,
+		INDEX idx_host_lifecycle_transition_host_id (host_id),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS esxi_cluster (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		connection_string TEXT NOT NULL,
+		cluster_name VARCHAR(255) NOT NULL,
+		INDEX idx_esxi_cluster_name (cluster_name)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS esxi_cluster_host (
+		cluster_id CHAR(36),
+		hostname VARCHAR(255),
+		FOREIGN KEY (cluster_id) REFERENCES esxi_cluster(id) ON UPDATE CASCADE ON DELETE CASCADE,
+		FOREIGN KEY (hostname) REFERENCES host(name) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS tag_certificate (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		hardware_uuid CHAR(36) NOT NULL,
+		certificate LONGBLOB NOT NULL,
+		subject VARCHAR(255) NOT NULL,
+		issuer VARCHAR(255) NOT NULL,
+		notbefore DATETIME(6) NOT NULL,
+		notafter DATETIME(6) NOT NULL,
+		revoked BOOLEAN NOT NULL DEFAULT FALSE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS tpm_endorsement (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		hardware_uuid CHAR(36) NOT NULL,
+		issuer VARCHAR(255) NOT NULL,
+		revoked BOOLEAN,
+		certificate TEXT NOT NULL,
+		comment TEXT,
+		certificate_digest VARCHAR(255) NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS platform_certificate (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		hardware_uuid CHAR(36) NOT NULL,
+		certificate TEXT NOT NULL,
+		issuer VARCHAR(255) NOT NULL,
+		manufacturer VARCHAR(255),
+		model VARCHAR(255),
+		serial VARCHAR(255),
+		version VARCHAR(255),
+		certificate_digest VARCHAR(255) NOT NULL,
+		ek_certificate_digest VARCHAR(255) NOT NULL,
+		not_before DATETIME(6),
+		not_after DATETIME(6),
+		revoked BOOLEAN,
+		INDEX idx_platform_certificate_hardware_uuid (hardware_uuid)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS flavor_learning (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		label VARCHAR(255) NOT NULL UNIQUE,
+		content JSON NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS flavor_prune (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		created DATETIME(6) NOT NULL,
+		content JSON NOT NULL,
+		retired_flavors JSON NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS report (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		host_id CHAR(36) NOT NULL,
+		trust_report JSON NOT NULL,
+		trusted BOOLEAN NOT NULL,
+		created DATETIME(6) NOT NULL,
+		expiration DATETIME(6) NOT NULL,
+		saml LONGTEXT NOT NULL,
+		INDEX idx_report_host_id (host_id),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS report_flavor_part (
+		report_id CHAR(36) NOT NULL,
+		flavor_part VARCHAR(32) NOT NULL,
+		trusted BOOLEAN NOT NULL,
+		valid_until DATETIME(6),
+		PRIMARY KEY (report_id, flavor_part),
+		INDEX idx_report_flavor_part_trusted (trusted),
+		FOREIGN KEY (report_id) REFERENCES report(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS trust_summary (
+		host_id CHAR(36) NOT NULL,
+		hour DATETIME(6) NOT NULL,
+		trusted_reports BIGINT NOT NULL DEFAULT 0,
+		untrusted_reports BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (host_id, hour),
+		INDEX idx_trust_summary_hour (hour),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS trust_summary_fault (
+		host_id CHAR(36) NOT NULL,
+		hour DATETIME(6) NOT NULL,
+		fault VARCHAR(255) NOT NULL,
+		faults BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (host_id, hour, fault),
+		INDEX idx_trust_summary_fault_hour (hour),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host_credential (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		host_id CHAR(36),
+		host_name VARCHAR(255),
+		hardware_uuid CHAR(36),
+		credential TEXT,
+		created_ts DATETIME(6),
+		INDEX idx_host_credential_host_id (host_id),
+		INDEX idx_host_credential_hostname (host_name),
+		INDEX idx_host_credential_hardware_uuid (hardware_uuid),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host_flavorgroup (
+		host_id CHAR(36) NOT NULL,
+		flavorgroup_id CHAR(36) NOT NULL,
+		UNIQUE INDEX idx_flavorgroup_host (host_id, flavorgroup_id),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE,
+		FOREIGN KEY (flavorgroup_id) REFERENCES flavor_group(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host_label (
+		host_id CHAR(36) NOT NULL,
+		label_key VARCHAR(317) NOT NULL,
+		label_value VARCHAR(63) NOT NULL,
+		UNIQUE INDEX idx_host_label (host_id, label_key),
+		INDEX idx_host_label_value (label_key, label_value),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS host_maintenance (
+		host_id CHAR(36) NOT NULL PRIMARY KEY,
+		reason VARCHAR(255) NOT NULL DEFAULT '',
+		started_at DATETIME(6) NOT NULL,
+		resume_at DATETIME(6),
+		INDEX idx_host_maintenance_resume_at (resume_at),
+		FOREIGN KEY (host_id) REFERENCES host(id) ON UPDATE CASCADE ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS audit_log_entry (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		entity_id CHAR(36),
+		entity_type VARCHAR(255),
+		created DATETIME(6) NOT NULL,
+		action VARCHAR(50),
+		data JSON
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS audit_event (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		created DATETIME(6) NOT NULL,
+		actor VARCHAR(255),
+		method VARCHAR(10),
+		entity_type VARCHAR(255),
+		entity_id CHAR(36),
+		content JSON NOT NULL,
+		INDEX idx_audit_event_created (created),
+		INDEX idx_audit_event_actor (actor),
+		INDEX idx_audit_event_entity_id (entity_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS tenant_usage (
+		tenant_id VARCHAR(64) NOT NULL,
+		identity VARCHAR(255) NOT NULL,
+		day DATETIME(6) NOT NULL,
+		api_calls BIGINT NOT NULL DEFAULT 0,
+		report_generations BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (tenant_id, identity, day)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS queue (
+		id CHAR(36) NOT NULL PRIMARY KEY,
+		action VARCHAR(255),
+		params JSON NOT NULL,
+		created_at DATETIME(6),
+		updated_at DATETIME(6),
+		state INT,
+		message TEXT
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+}
@@ -0,0 +1,65 @@
+//go:build mysql
+// +build mysql
+
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/pkg/errors"
+
+	// Import driver for GORM
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+)
+
+func init() {
+	registerMySQLTLSConfig = func(cfg *Config) error {
+		caCert, err := ioutil.ReadFile(cfg.SslCert)
+		if err != nil {
+			return errors.Wrapf(err, "Error reading database CA certificate %s", cfg.SslCert)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return errors.Errorf("No certificates found in database CA certificate %s", cfg.SslCert)
+		}
+		tlsConfig := crypt.TLSConfig()
+		tlsConfig.RootCAs = rootCAs
+		tlsConfig.ServerName = cfg.Host
+		if cfg.SslMode == constants.SslModeVerifyCa {
+			// the certificate chain is verified, the host name is not
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				return verifyCertificateChain(rawCerts, rootCAs)
+			}
+		}
+		return mysql.RegisterTLSConfig(mysqlTLSConfigName, tlsConfig)
+	}
+}
+
+func verifyCertificateChain(rawCerts [][]byte, rootCAs *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return errors.New("Database server did not present a certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, rawCert := range rawCerts {
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return errors.Wrap(err, "Error parsing database server certificate")
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{Roots: rootCAs, Intermediates: intermediates})
+	return err
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type PlatformCertificateStore struct {
+	Store *DataStore
+}
+
+func NewPlatformCertificateStore(store *DataStore) *PlatformCertificateStore {
+	return &PlatformCertificateStore{store}
+}
+
+func (p *PlatformCertificateStore) Create(pc *hvs.PlatformCertificate) (*hvs.PlatformCertificate, error) {
+	defaultLog.Trace("postgres/platform_certificate_store:Create() Entering")
+	defer defaultLog.Trace("postgres/platform_certificate_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/platform_certificate_store:Create() failed to create new UUID")
+	}
+	pc.ID = newUuid
+
+	dbPlatformCertificate := platformCertificate{
+		ID:                  pc.ID,
+		HardwareUUID:        pc.HardwareUUID,
+		Certificate:         pc.Certificate,
+		Issuer:              pc.Issuer,
+		Manufacturer:        pc.Manufacturer,
+		Model:               pc.Model,
+		Serial:              pc.Serial,
+		Version:             pc.Version,
+		CertificateDigest:   pc.CertificateDigest,
+		EkCertificateDigest: pc.EkCertificateDigest,
+		NotBefore:           pc.NotBefore,
+		NotAfter:            pc.NotAfter,
+		Revoked:             pc.Revoked,
+	}
+
+	if err := p.Store.Db.Create(&dbPlatformCertificate).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/platform_certificate_store:Create() failed to create PlatformCertificate")
+	}
+	return pc, nil
+}
+
+func (p *PlatformCertificateStore) Retrieve(id uuid.UUID) (*hvs.PlatformCertificate, error) {
+	defaultLog.Trace("postgres/platform_certificate_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/platform_certificate_store:Retrieve() Leaving")
+
+	row := p.Store.Db.Model(platformCertificate{}).Where(platformCertificate{ID: id}).Row()
+	pc, err := scanPlatformCertificate(row)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/platform_certificate_store:Retrieve() - Could not scan record ")
+	}
+	return pc, nil
+}
+
+func (p *PlatformCertificateStore) Update(pc *hvs.PlatformCertificate) (*hvs.PlatformCertificate, error) {
+	defaultLog.Trace("postgres/platform_certificate_store:Update() Entering")
+	defer defaultLog.Trace("postgres/platform_certificate_store:Update() Leaving")
+
+	dbPlatformCertificate := platformCertificate{
+		ID:                  pc.ID,
+		HardwareUUID:        pc.HardwareUUID,
+		Certificate:         pc.Certificate,
+		Issuer:              pc.Issuer,
+		Manufacturer:        pc.Manufacturer,
+		Model:               pc.Model,
+		Serial:              pc.Serial,
+		Version:             pc.Version,
+		CertificateDigest:   pc.CertificateDigest,
+		EkCertificateDigest: pc.EkCertificateDigest,
+		NotBefore:           pc.NotBefore,
+		NotAfter:            pc.NotAfter,
+		Revoked:             pc.Revoked,
+	}
+	if err := p.Store.Db.Save(&dbPlatformCertificate).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/platform_certificate_store:Update() failed to save PlatformCertificate")
+	}
+	return pc, nil
+}
+
+func (p *PlatformCertificateStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/platform_certificate_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/platform_certificate_store:Delete() Leaving")
+
+	if err := p.Store.Db.Delete(&platformCertificate{ID: id}).Error; err != nil {
+		return errors.Wrap(err, "postgres/platform_certificate_store:Delete() failed to delete PlatformCertificate")
+	}
+	return nil
+}
+
+func (p *PlatformCertificateStore) Search(pcFilter *models.PlatformCertificateFilterCriteria) (*hvs.PlatformCertificateCollection, error) {
+	defaultLog.Trace("postgres/platform_certificate_store:Search() Entering")
+	defer defaultLog.Trace("postgres/platform_certificate_store:Search() Leaving")
+
+	tx := buildPlatformCertificateSearchQuery(p.Store.Db, pcFilter)
+	if tx == nil {
+		return nil, errors.New("postgres/platform_certificate_store:Search() Unexpected Error. Could not build" +
+			" a gorm query object in PlatformCertificate Search function.")
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/platform_certificate_store:Search() failed to retrieve platform_certificates from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	platformCertificateCollection := hvs.PlatformCertificateCollection{PlatformCertificates: []*hvs.PlatformCertificate{}}
+	for rows.Next() {
+		pc, err := scanPlatformCertificate(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "postgres/platform_certificate_store:Search() - Could not scan record ")
+		}
+		platformCertificateCollection.PlatformCertificates = append(platformCertificateCollection.PlatformCertificates, pc)
+	}
+	return &platformCertificateCollection, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPlatformCertificate(row rowScanner) (*hvs.PlatformCertificate, error) {
+	pc := hvs.PlatformCertificate{}
+	err := row.Scan(&pc.ID, &pc.HardwareUUID, &pc.Certificate, &pc.Issuer, &pc.Manufacturer, &pc.Model, &pc.Serial,
+		&pc.Version, &pc.CertificateDigest, &pc.EkCertificateDigest, &pc.NotBefore, &pc.NotAfter, &pc.Revoked)
+	if err != nil {
+		return nil, err
+	}
+	return &pc, nil
+}
+
+func buildPlatformCertificateSearchQuery(tx *gorm.DB, pcFilter *models.PlatformCertificateFilterCriteria) *gorm.DB {
+	defaultLog.Trace("postgres/platform_certificate_store:buildPlatformCertificateSearchQuery() Entering")
+	defer defaultLog.Trace("postgres/platform_certificate_store:buildPlatformCertificateSearchQuery() Leaving")
+
+	if tx == nil {
+		return nil
+	}
+	tx = tx.Model(&platformCertificate{})
+	if pcFilter == nil {
+		defaultLog.Info("postgres/platform_certificate_store:buildPlatformCertificateSearchQuery() No criteria specified in search query" +
+			". Returning all rows.")
+		return tx
+	}
+	tx = tx.Where("revoked = ? ", pcFilter.RevokedEqualTo)
+	if pcFilter.Id != uuid.Nil {
+		tx = tx.Where("id = ?", pcFilter.Id)
+	}
+	if pcFilter.HardwareUuidEqualTo != uuid.Nil {
+		tx = tx.Where("hardware_uuid = ?", pcFilter.HardwareUuidEqualTo)
+	}
+	if pcFilter.ManufacturerEqualTo != "" {
+		tx = tx.Where("manufacturer = ?", pcFilter.ManufacturerEqualTo)
+	}
+	if pcFilter.ModelEqualTo != "" {
+		tx = tx.Where("model = ?", pcFilter.ModelEqualTo)
+	}
+	if pcFilter.SerialEqualTo != "" {
+		tx = tx.Where("serial = ?", pcFilter.SerialEqualTo)
+	}
+	if pcFilter.CertificateDigestEqualTo != "" {
+		tx = tx.Where("certificate_digest = ?", pcFilter.CertificateDigestEqualTo)
+	}
+	return tx
+}
@@ -5,14 +5,13 @@
 package postgres
 
 import (
-	"fmt"
 	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
 	"io/ioutil"
-	"strings"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"github.com/jinzhu/gorm"
@@ -49,34 +48,49 @@ type DataStore struct {
 	Db *gorm.DB
 }
 
-// New returns a DataStore instance with the gorm.DB set with the postgres
+// New returns a DataStore instance with the gorm.DB connected to the postgres, MySQL or SQLite database of cfg
 func New(cfg *Config) (*DataStore, error) {
 	defaultLog.Trace("postgres/postgres:New() Entering")
 	defer defaultLog.Trace("postgres/postgres:New() Leaving")
 
 	var store DataStore
 
-	if cfg.Host == "" || cfg.Port == 0 || cfg.User == "" ||
-		cfg.Password == "" || cfg.Dbname == "" {
-		err := errors.Errorf("postgres/postgres:New() All fields must be set (%s)", spew.Sdump(cfg))
-		defaultLog.Error(err)
-		secLog.Warningf("%s: Failed to connect to db, missing configuration - %s", commLogMsg.BadConnection, err)
-		return nil, err
-	}
+	// the standalone SQLite database is a local file, only its name is needed
+	if cfg.Vendor == constants.DBTypeSQLite {
+		if cfg.Dbname == "" {
+			return nil, errors.New("postgres/postgres:New() The database file must be set")
+		}
+	} else {
+		if cfg.Host == "" || cfg.Port == 0 || cfg.User == "" ||
+			cfg.Password == "" || cfg.Dbname == "" {
+			err := errors.Errorf("postgres/postgres:New() All fields must be set (%s)", spew.Sdump(cfg))
+			defaultLog.Error(err)
+			secLog.Warningf("%s: Failed to connect to db, missing configuration - %s", commLogMsg.BadConnection, err)
+			return nil, err
+		}
 
-	if cfg.Port > 65535 || cfg.Port <= 1024 {
-		return nil, errors.New("Invalid or reserved port")
+		if cfg.Port > 65535 || cfg.Port <= 1024 {
+			return nil, errors.New("Invalid or reserved port")
+		}
 	}
 
-	cfg.SslMode = strings.TrimSpace(strings.ToLower(cfg.SslMode))
-	if cfg.SslMode != constants.SslModeAllow && cfg.SslMode != constants.SslModePrefer &&
-		cfg.SslMode != constants.SslModeVerifyCa && cfg.SslMode != constants.SslModeRequire {
-		cfg.SslMode = constants.SslModeVerifyFull
+	if cfg.Vendor == "" {
+		cfg.Vendor = constants.DBTypePostgres
 	}
-
-	var sslCertParams string
-	if cfg.SslMode == "verify-ca" || cfg.SslMode == "verify-full" {
-		sslCertParams = " sslrootcert=" + cfg.SslCert
+	dialect, ok := dialects[cfg.Vendor]
+	if !ok {
+		return nil, errors.Errorf("Unsupported database vendor %s", cfg.Vendor)
+	}
+	// a password sealed to the TPM at setup is only unsealed to connect, cfg keeps the sealed value
+	connConfig := *cfg
+	password, err := crypt.ResolveSecret(cfg.Password)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/postgres:New() Error resolving the database password")
+	}
+	connConfig.Password = password
+	connectionString, err := dialect.connectionString(&connConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/postgres:New() Error configuring database connection")
 	}
 
 	var db *gorm.DB
@@ -87,8 +101,7 @@ func New(cfg *Config) (*DataStore, error) {
 	}
 	for i := 0; i < numAttempts; i = i + 1 {
 		retryTime := time.Duration(cfg.ConnRetryTime)
-		db, dbErr = gorm.Open("postgres", fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s cfg.SslMode=%s%s",
-			cfg.Host, cfg.Port, cfg.User, cfg.Dbname, cfg.Password, cfg.SslMode, sslCertParams))
+		db, dbErr = gorm.Open(cfg.Vendor, connectionString)
 		if dbErr != nil {
 			defaultLog.WithError(dbErr).Infof("postgres/postgres:New() Failed to connect to DB, retrying attempt %d/%d", i, numAttempts)
 		} else {
@@ -137,13 +150,28 @@ func (ds *DataStore) ExecuteSqlFile(file string) error {
 	return nil
 }
 
-func (ds *DataStore) Migrate() {
+func (ds *DataStore) Migrate() error {
 	defaultLog.Trace("postgres/postgres:Migrate() Entering")
 	defer defaultLog.Trace("postgres/postgres:Migrate() Leaving")
 
-	ds.Db.AutoMigrate(flavorGroup{}, host{}, flavor{}, trustCache{}, hostuniqueFlavor{}, flavorgroupFlavor{}, hostStatus{}, esxiCluster{},
-		esxiClusterHost{}, tagCertificate{}, tpmEndorsement{}, report{}, hostCredential{}, hostFlavorgroup{}, auditLogEntry{},
-		queue{})
+	return dialectOf(ds.Db).migrate(ds.Db)
+}
+
+// MissingTables returns the names of the HVS tables that are not in the database, they are created by Migrate
+func (ds *DataStore) MissingTables() []string {
+	defaultLog.Trace("postgres/postgres:MissingTables() Entering")
+	defer defaultLog.Trace("postgres/postgres:MissingTables() Leaving")
+
+	var missing []string
+	for _, model := range []interface{}{flavorGroup{}, host{}, flavor{}, trustCache{}, hostuniqueFlavor{},
+		flavorgroupFlavor{}, flavorLabel{}, hostLabel{}, hostMaintenance{}, hostStatus{}, hostLifecycleTransition{}, esxiCluster{}, esxiClusterHost{},
+		tagCertificate{}, tpmEndorsement{}, platformCertificate{}, flavorLearning{}, flavorPrune{}, report{}, reportFlavorPart{}, trustSummary{}, trustSummaryFault{},
+		hostCredential{}, hostFlavorgroup{}, auditLogEntry{}, auditEvent{}, tenantUsage{}, queue{}} {
+		if !ds.Db.HasTable(model) {
+			missing = append(missing, ds.Db.NewScope(model).TableName())
+		}
+	}
+	return missing
 }
 
 func (ds *DataStore) Close() {
@@ -157,3 +185,12 @@ func (ds *DataStore) Close() {
 		}
 	}
 }
+
+// scopeToTenant restricts a query to the rows of the tenant a store is scoped to. Stores that are not scoped to a
+// tenant, as used by the services of HVS, see the rows of all the tenants.
+func scopeToTenant(tx *gorm.DB, column string, tenantId *string) *gorm.DB {
+	if tenantId == nil {
+		return tx
+	}
+	return tx.Where(column+" = ?", *tenantId)
+}
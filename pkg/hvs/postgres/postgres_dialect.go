@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/jinzhu/gorm"
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) connectionString(cfg *Config) (string, error) {
+	cfg.SslMode = strings.TrimSpace(strings.ToLower(cfg.SslMode))
+	if cfg.SslMode != constants.SslModeAllow && cfg.SslMode != constants.SslModePrefer &&
+		cfg.SslMode != constants.SslModeVerifyCa && cfg.SslMode != constants.SslModeRequire {
+		cfg.SslMode = constants.SslModeVerifyFull
+	}
+
+	var sslCertParams string
+	if cfg.SslMode == "verify-ca" || cfg.SslMode == "verify-full" {
+		sslCertParams = " sslrootcert=" + cfg.SslCert
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s cfg.SslMode=%s%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Dbname, cfg.Password, cfg.SslMode, sslCertParams), nil
+}
+
+func (postgresDialect) migrate(db *gorm.DB) error {
+	return db.AutoMigrate(flavorGroup{}, host{}, flavor{}, trustCache{}, hostuniqueFlavor{}, flavorgroupFlavor{}, flavorLabel{}, hostLabel{}, hostMaintenance{}, hostStatus{}, hostLifecycleTransition{}, esxiCluster{},
+		esxiClusterHost{}, tagCertificate{}, tpmEndorsement{}, platformCertificate{}, flavorLearning{}, flavorPrune{}, report{}, reportFlavorPart{}, trustSummary{}, trustSummaryFault{}, hostCredential{}, hostFlavorgroup{}, auditLogEntry{}, auditEvent{},
+		tenantUsage{}, queue{}).Error
+}
+
+func (postgresDialect) jsonText(column string, path ...interface{}) string {
+	expr := column
+	for i, key := range path {
+		operator := "->"
+		if i == len(path)-1 {
+			operator = "->>"
+		}
+		if index, ok := key.(int); ok {
+			expr = fmt.Sprintf("%s %s %d", expr, operator, index)
+		} else {
+			expr = fmt.Sprintf("%s %s '%s'", expr, operator, key)
+		}
+	}
+	return expr
+}
+
+func (d postgresDialect) jsonTextAtParam(column string, path ...interface{}) string {
+	expr := column
+	for _, key := range path {
+		if index, ok := key.(int); ok {
+			expr = fmt.Sprintf("%s -> %d", expr, index)
+		} else {
+			expr = fmt.Sprintf("%s -> '%s'", expr, key)
+		}
+	}
+	return expr + " ->> ?"
+}
+
+func (postgresDialect) jsonNotNull(column string) string {
+	return column + "::text != 'null'"
+}
+
+func (postgresDialect) jsonSet(column, key string) string {
+	return fmt.Sprintf("jsonb_set(%s, '{%s}', CAST(? AS JSONB))", column, key)
+}
+
+func (postgresDialect) castToText(expr string) string {
+	return "CAST(" + expr + " AS VARCHAR)"
+}
+
+func (postgresDialect) castToUUID(expr string) string {
+	return "CAST(" + expr + " AS uuid)"
+}
+
+func (postgresDialect) castToTimestamp(expr string) string {
+	return "CAST(" + expr + " AS TIMESTAMP)"
+}
+
+func (postgresDialect) insertIgnore(table, values string, conflictColumns ...string) string {
+	return fmt.Sprintf("INSERT INTO %s VALUES %s on conflict (%s) do nothing", table, values, strings.Join(conflictColumns, ", "))
+}
+
+func (postgresDialect) isDuplicateKeyError(err error) bool {
+	return strings.Contains(err.Error(), "duplicate key")
+}
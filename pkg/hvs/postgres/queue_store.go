@@ -158,16 +158,19 @@ func buildQueueSearchQuery(tx *gorm.DB, qf *models.QueueFilterCriteria) *gorm.DB
 		tx = tx.Where("action = ?", qf.Action)
 
 		if qf.ParamKey != "" && qf.ParamValue != "" {
-			tx = tx.Where("params ->> ? = ?", qf.ParamKey, qf.ParamValue)
+			tx = tx.Where(dialectOf(tx).jsonTextAtParam("params")+" = ?", qf.ParamKey, qf.ParamValue)
 		} else if len(qf.ParamMap) > 0 {
 			for k, v := range qf.ParamMap {
-				tx = tx.Where("params ->> ? = ?", k, v)
+				tx = tx.Where(dialectOf(tx).jsonTextAtParam("params")+" = ?", k, v)
 			}
 		}
 	}
 	if len(qf.QueueStates) > 0 {
 		tx = tx.Where("state in (?)", qf.QueueStates)
 	}
+	if !qf.UpdatedBefore.IsZero() {
+		tx = tx.Where("updated_at < ?", qf.UpdatedBefore)
+	}
 
 	// apply limit
 	if qf.Limit > 0 {
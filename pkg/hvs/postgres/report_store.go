@@ -17,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
@@ -25,12 +26,19 @@ type ReportStore struct {
 	Store          *DataStore
 	AuditLogWriter domain.AuditLogWriter
 	dbLock         sync.Mutex
+	tenantId       *string
 }
 
 func NewReportStore(store *DataStore) *ReportStore {
 	return &ReportStore{Store: store}
 }
 
+// ForTenant returns a view of the store restricted to the reports of the hosts of the tenant. Reports do not have a
+// tenant of their own, Retrieve and Search of the view are restricted through the host of the reports.
+func (r *ReportStore) ForTenant(tenantId string) domain.ReportStore {
+	return &ReportStore{Store: r.Store, AuditLogWriter: r.AuditLogWriter, tenantId: &tenantId}
+}
+
 // Retrieve method fetches report for a given Id
 func (r *ReportStore) Retrieve(reportId uuid.UUID) (*models.HVSReport, error) {
 	defaultLog.Trace("postgres/report_store:Retrieve() Entering")
@@ -38,7 +46,11 @@ func (r *ReportStore) Retrieve(reportId uuid.UUID) (*models.HVSReport, error) {
 
 	re := models.HVSReport{}
 
-	row := r.Store.Db.Model(&report{}).Where(&report{ID: reportId}).Row()
+	tx := r.Store.Db.Model(&report{}).Where(&report{ID: reportId})
+	if r.tenantId != nil {
+		tx = tx.Where("host_id IN ?", tenantHostsQuery(r.Store.Db, "id", *r.tenantId).SubQuery())
+	}
+	row := tx.Row()
 	ignoreMe := false //The new 'Trusted' field was introduced to v3.5, ignore that field in the query so it returns the correct results
 	if err := row.Scan(&re.ID, &re.HostID, (*PGTrustReport)(&re.TrustReport), &ignoreMe, &re.CreatedAt, &re.Expiration, &re.Saml); err != nil {
 		return nil, errors.Wrap(err, "postgres/report_store:Retrieve() failed to scan record")
@@ -104,9 +116,33 @@ func (r *ReportStore) Create(re *models.HVSReport) (*models.HVSReport, error) {
 		TrustReport: PGTrustReport(re.TrustReport),
 		Trusted:     re.TrustReport.Trusted,
 	}
-	if err := r.Store.Db.Create(&dbReport).Error; err != nil {
+	tx := r.Store.Db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "postgres/report_store:Create() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if err := tx.Create(&dbReport).Error; err != nil {
 		return nil, errors.Wrap(err, "postgres/report_store:Create() failed to create HVSReport")
 	}
+	trustInformation := hvs.NewTrustInformation(re.TrustReport, re.Expiration)
+	for flavorPart, status := range trustInformation.FlavorTrust {
+		dbReportFlavorPart := reportFlavorPart{
+			ReportID:   re.ID,
+			FlavorPart: flavorPart.String(),
+			Trusted:    status.Trust,
+			ValidUntil: status.ValidUntil,
+		}
+		if err := tx.Create(&dbReportFlavorPart).Error; err != nil {
+			return nil, errors.Wrapf(err, "postgres/report_store:Create() failed to create %s trust of HVSReport", flavorPart)
+		}
+	}
+	if err := addTrustSummary(tx, re); err != nil {
+		return nil, errors.Wrap(err, "postgres/report_store:Create() failed to add HVSReport to the trust summary")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/report_store:Create() failed to commit transaction")
+	}
 	// log to audit log
 	if r.AuditLogWriter != nil {
 		auditEntry, err := r.AuditLogWriter.CreateEntry("create", re)
@@ -164,7 +200,7 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 
 	var tx *gorm.DB
 	if fromDate.IsZero() && toDate.IsZero() && criteria.LatestPerHost {
-		tx = buildLatestReportSearchQuery(r.Store.Db, reportID, hostID, hostHardwareUUID, hostName, hostStatus, criteria.Limit)
+		tx = buildLatestReportSearchQuery(r.Store.Db, reportID, hostID, hostHardwareUUID, hostName, hostStatus, criteria.UntrustedFlavorPart, criteria.Limit, r.tenantId)
 
 		if tx == nil {
 			return nil, errors.New("postgres/report_store:Search() Unexpected Error. Could not build" +
@@ -195,7 +231,7 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 
 		return reports, nil
 	} else {
-		tx = buildReportSearchQuery(r.Store.Db, hostID, hostHardwareUUID, hostName, hostStatus, fromDate, toDate, latestPerHost, criteria.Limit)
+		tx = buildReportSearchQuery(r.Store.Db, hostID, hostHardwareUUID, hostName, hostStatus, fromDate, toDate, latestPerHost, criteria.Limit, r.tenantId)
 		if tx == nil {
 			return nil, errors.New("postgres/report_store:Search() Unexpected Error. Could not build" +
 				" a gorm query object in HVSReport Search function.")
@@ -225,6 +261,10 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 			if err != nil {
 				return nil, errors.Wrap(err, "postgres/report_store:Search() convert auditloag entry into report")
 			}
+			// the trust of the flavor parts of the reports in the audit log is read from their trust report
+			if criteria.UntrustedFlavorPart != "" && !isFlavorPartUntrusted(hvsReport.TrustReport, criteria.UntrustedFlavorPart) {
+				continue
+			}
 			reports = append(reports, *hvsReport)
 		}
 
@@ -232,20 +272,172 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 	}
 }
 
+// UpdateStageTimings records the attestation stage timings in the trust report of an existing report
+func (r *ReportStore) UpdateStageTimings(reportId uuid.UUID, timings *hvs.ReportStageTimings) error {
+	defaultLog.Trace("postgres/report_store:UpdateStageTimings() Entering")
+	defer defaultLog.Trace("postgres/report_store:UpdateStageTimings() Leaving")
+
+	timingsJson, err := json.Marshal(timings)
+	if err != nil {
+		return errors.Wrap(err, "postgres/report_store:UpdateStageTimings() failed to marshal stage timings")
+	}
+	if err := r.Store.Db.Exec("UPDATE report SET trust_report = "+dialectOf(r.Store.Db).jsonSet("trust_report", "stage_timings")+" WHERE id = ?",
+		string(timingsJson), reportId).Error; err != nil {
+		return errors.Wrap(err, "postgres/report_store:UpdateStageTimings() failed to update stage timings")
+	}
+	return nil
+}
+
+// SearchTrustHistory returns the trust summary of the host for each hour between the dates in which reports were
+// created for it, ordered by hour
+func (r *ReportStore) SearchTrustHistory(hostId uuid.UUID, fromDate, toDate time.Time) ([]hvs.TrustHistoryBucket, error) {
+	defaultLog.Trace("postgres/report_store:SearchTrustHistory() Entering")
+	defer defaultLog.Trace("postgres/report_store:SearchTrustHistory() Leaving")
+
+	tx := r.Store.Db.Model(&trustSummary{}).Where("host_id = ? AND hour >= ? AND hour <= ?",
+		hostId, fromDate.UTC().Truncate(time.Hour), toDate.UTC()).Order("hour")
+	if r.tenantId != nil {
+		tx = tx.Where("host_id IN ?", tenantHostsQuery(r.Store.Db, "id", *r.tenantId).SubQuery())
+	}
+	var summaries []trustSummary
+	if err := tx.Find(&summaries).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/report_store:SearchTrustHistory() failed to retrieve trust summary")
+	}
+
+	var faults []trustSummaryFault
+	if len(summaries) > 0 {
+		if err := r.Store.Db.Model(&trustSummaryFault{}).Where("host_id = ? AND hour >= ? AND hour <= ?",
+			hostId, summaries[0].Hour, summaries[len(summaries)-1].Hour).Find(&faults).Error; err != nil {
+			return nil, errors.Wrap(err, "postgres/report_store:SearchTrustHistory() failed to retrieve trust summary faults")
+		}
+	}
+
+	buckets := make([]hvs.TrustHistoryBucket, 0, len(summaries))
+	bucketIndex := make(map[time.Time]int, len(summaries))
+	for _, summary := range summaries {
+		hour := summary.Hour.UTC()
+		bucketIndex[hour] = len(buckets)
+		buckets = append(buckets, hvs.TrustHistoryBucket{
+			Start:            hour,
+			Trusted:          summary.UntrustedReports == 0,
+			TrustedReports:   summary.TrustedReports,
+			UntrustedReports: summary.UntrustedReports,
+		})
+	}
+	for _, fault := range faults {
+		i, ok := bucketIndex[fault.Hour.UTC()]
+		if !ok {
+			continue
+		}
+		if buckets[i].Faults == nil {
+			buckets[i].Faults = map[string]int64{}
+		}
+		buckets[i].Faults[fault.Fault] += fault.Faults
+	}
+	return buckets, nil
+}
+
+// DeleteTrustHistory deletes the trust summary of the hours before the time
+func (r *ReportStore) DeleteTrustHistory(before time.Time) error {
+	defaultLog.Trace("postgres/report_store:DeleteTrustHistory() Entering")
+	defer defaultLog.Trace("postgres/report_store:DeleteTrustHistory() Leaving")
+
+	before = before.UTC().Truncate(time.Hour)
+	if err := r.Store.Db.Where("hour < ?", before).Delete(&trustSummaryFault{}).Error; err != nil {
+		return errors.Wrap(err, "postgres/report_store:DeleteTrustHistory() failed to delete trust summary faults")
+	}
+	if err := r.Store.Db.Where("hour < ?", before).Delete(&trustSummary{}).Error; err != nil {
+		return errors.Wrap(err, "postgres/report_store:DeleteTrustHistory() failed to delete trust summary")
+	}
+	return nil
+}
+
+// addTrustSummary adds the report to the trust summary of its host for the hour it was created in, the records of
+// the hour are created with its first report. The reports suppressed by maintenance are left out.
+func addTrustSummary(tx *gorm.DB, re *models.HVSReport) error {
+	if re.TrustReport.Status == hvs.ReportStatusSuppressedMaintenance {
+		return nil
+	}
+	hour := re.CreatedAt.UTC().Truncate(time.Hour)
+	var trusted, untrusted int64 = 0, 1
+	if re.TrustReport.Trusted {
+		trusted, untrusted = 1, 0
+	}
+	db := tx.Model(&trustSummary{}).
+		Where("host_id = ? AND hour = ?", re.HostID, hour).
+		UpdateColumns(map[string]interface{}{
+			"trusted_reports":   gorm.Expr("trusted_reports + ?", trusted),
+			"untrusted_reports": gorm.Expr("untrusted_reports + ?", untrusted),
+		})
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "failed to update trust summary")
+	}
+	if db.RowsAffected == 0 {
+		dbTrustSummary := trustSummary{
+			HostId:           re.HostID,
+			Hour:             hour,
+			TrustedReports:   trusted,
+			UntrustedReports: untrusted,
+		}
+		if err := tx.Create(&dbTrustSummary).Error; err != nil {
+			return errors.Wrap(err, "failed to create trust summary")
+		}
+	}
+
+	for fault, count := range reportFaultCounts(re.TrustReport) {
+		db := tx.Model(&trustSummaryFault{}).
+			Where("host_id = ? AND hour = ? AND fault = ?", re.HostID, hour, fault).
+			UpdateColumn("faults", gorm.Expr("faults + ?", count))
+		if db.Error != nil {
+			return errors.Wrap(db.Error, "failed to update trust summary faults")
+		}
+		if db.RowsAffected > 0 {
+			continue
+		}
+		dbTrustSummaryFault := trustSummaryFault{
+			HostId: re.HostID,
+			Hour:   hour,
+			Fault:  fault,
+			Faults: count,
+		}
+		if err := tx.Create(&dbTrustSummaryFault).Error; err != nil {
+			return errors.Wrap(err, "failed to create trust summary faults")
+		}
+	}
+	return nil
+}
+
+// reportFaultCounts counts the faults of the trust report by fault name, the warnings are not counted
+func reportFaultCounts(trustReport hvs.TrustReport) map[string]int64 {
+	counts := map[string]int64{}
+	for _, result := range trustReport.Results {
+		for _, fault := range result.Faults {
+			if !fault.Warning {
+				counts[fault.Name]++
+			}
+		}
+	}
+	return counts
+}
+
 // FindHostIdsFromExpiredReports searches the report table for reports that have an
 // 'expiration' between 'fromTime' and 'toTime'.
 // It also discovers hosts that do not have a corresponding report in the table.
+// The hosts in maintenance mode are left out.
 func (r *ReportStore) FindHostIdsFromExpiredReports(fromTime time.Time, toTime time.Time) ([]uuid.UUID, error) {
 
 	var tx *gorm.DB
+	d := dialectOf(r.Store.Db)
 	tx = r.Store.Db.Raw("SELECT h.id from host h "+
 		"INNER JOIN report r ON h.id = r.host_id "+
-		"WHERE CAST(expiration AS TIMESTAMP) > CAST(? AS TIMESTAMP) "+
-		"AND CAST(expiration AS TIMESTAMP) <= CAST(? AS TIMESTAMP) "+
-		"AND h.id NOT IN (SELECT CAST(params ->> 'host_id' AS uuid) from queue) "+
+		"WHERE "+d.castToTimestamp("expiration")+" > "+d.castToTimestamp("?")+" "+
+		"AND "+d.castToTimestamp("expiration")+" <= "+d.castToTimestamp("?")+" "+
+		"AND h.id NOT IN (SELECT "+d.castToUUID(d.jsonText("params", "host_id"))+" from queue) "+
+		"AND h.id NOT IN (SELECT host_id FROM host_maintenance) "+
 		"UNION "+
 		"SELECT h.id FROM host h LEFT JOIN report r ON h.id = r.host_id "+
-		"WHERE r.id IS NULL", fromTime, toTime)
+		"WHERE r.id IS NULL "+
+		"AND h.id NOT IN (SELECT host_id FROM host_maintenance)", fromTime, toTime)
 	rows, err := tx.Rows()
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/report_store:FindHostIdsFromExpiredReports() failed to retrieve records from db")
@@ -343,38 +535,41 @@ func (r *ReportStore) Delete(reportId uuid.UUID) error {
 }
 
 // buildReportSearchQuery is a helper function to build the query object for a report search.
-func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostName, hostState string, fromDate, toDate time.Time, latestPerHost bool, limit int) *gorm.DB {
+func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostName, hostState string, fromDate, toDate time.Time, latestPerHost bool, limit int, tenantId *string) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildReportSearchQuery() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildReportSearchQuery() Leaving")
 	if tx == nil {
 		return nil
 	}
+	d := dialectOf(tx)
 	if latestPerHost {
 		entity := "auj"
-		txSubQuery := tx.Table("audit_log_entry auj").Select("data -> 'Columns' -> 1 ->> 'Value' AS host_id, max(auj.created) AS max_date ")
-		txSubQuery = buildReportSearchQueryWithCriteria(txSubQuery, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
+		txSubQuery := tx.Table("audit_log_entry auj").Select(d.jsonText("data", "Columns", 1, "Value") + " AS host_id, max(auj.created) AS max_date ")
+		txSubQuery = buildReportSearchQueryWithCriteria(txSubQuery, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate, tenantId)
 		txSubQuery = txSubQuery.Group("host_id")
 		subQuery := txSubQuery.SubQuery()
-		tx = tx.Table("audit_log_entry au").Select("au.*").Joins("INNER JOIN ? a ON a.host_id = au.data -> 'Columns' -> 1 ->> 'Value' AND a.max_date = au.created", subQuery)
+		tx = tx.Table("audit_log_entry au").Select("au.*").Joins("INNER JOIN ? a ON a.host_id = "+d.jsonText("au.data", "Columns", 1, "Value")+" AND a.max_date = au.created", subQuery)
 	} else {
 		entity := "au"
 		tx = tx.Table("audit_log_entry au").Select("au.*")
-		tx = buildReportSearchQueryWithCriteria(tx, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
+		tx = buildReportSearchQueryWithCriteria(tx, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate, tenantId)
 	}
 	tx = tx.Limit(limit)
 	return tx
 }
 
-func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, entity, hostName string, hostState string, fromDate, toDate time.Time) *gorm.DB {
+func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, entity, hostName string, hostState string, fromDate, toDate time.Time, tenantId *string) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildReportSearchQueryWithCriteria() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildReportSearchQueryWithCriteria() Leaving")
 
+	d := dialectOf(tx)
+	entityHostId := d.jsonText(entity+".data", "Columns", 1, "Value")
 	if hostState != "" {
-		tx = tx.Joins("INNER JOIN host_status hs on CAST(hs.host_id AS VARCHAR) = " + entity + ".data -> 'Columns' -> 1 ->> 'Value'")
+		tx = tx.Joins("INNER JOIN host_status hs on " + d.castToText("hs.host_id") + " = " + entityHostId)
 	}
 
 	if hostName != "" || hostHardwareID != uuid.Nil {
-		tx = tx.Joins("INNER JOIN host h on CAST(h.id AS VARCHAR) = " + entity + ".data -> 'Columns' -> 1 ->> 'Value'")
+		tx = tx.Joins("INNER JOIN host h on " + d.castToText("h.id") + " = " + entityHostId)
 	}
 
 	//TODO rename after testing
@@ -389,26 +584,30 @@ func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid
 	}
 
 	if hostID != uuid.Nil {
-		tx = tx.Where(entity+".data -> 'Columns' -> 1 ->> 'Value' = ?", hostID.String())
+		tx = tx.Where(entityHostId+" = ?", hostID.String())
 	}
 
 	if hostState != "" {
-		tx = tx.Where("hs.status ->> 'host_state' = ?", strings.ToUpper(hostState))
+		tx = tx.Where(d.jsonText("hs.status", "host_state")+" = ?", strings.ToUpper(hostState))
+	}
+
+	if tenantId != nil {
+		tx = tx.Where(entityHostId+" IN ?", tenantHostsQuery(tx, d.castToText("id"), *tenantId).SubQuery())
 	}
 
 	if !fromDate.IsZero() {
-		tx = tx.Where("CAST("+entity+".created AS TIMESTAMP) >= CAST(? AS TIMESTAMP)", fromDate)
+		tx = tx.Where(d.castToTimestamp(entity+".created")+" >= "+d.castToTimestamp("?"), fromDate)
 	}
 
 	if !toDate.IsZero() {
-		tx = tx.Where("CAST("+entity+".created AS TIMESTAMP) < CAST(? AS TIMESTAMP)", toDate)
+		tx = tx.Where(d.castToTimestamp(entity+".created")+" < "+d.castToTimestamp("?"), toDate)
 	}
 
 	return tx
 }
 
 // buildLatestReportSearchQuery is a helper function to build the query object for a latest report search.
-func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID uuid.UUID, hostName, hostState string, limit int) *gorm.DB {
+func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID uuid.UUID, hostName, hostState, untrustedFlavorPart string, limit int, tenantId *string) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildLatestReportSearchQuery() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildLatestReportSearchQuery() Leaving")
 
@@ -416,6 +615,9 @@ func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID
 		return nil
 	}
 	tx = tx.Model(&report{})
+	if tenantId != nil {
+		tx = tx.Where("report.host_id IN ?", tenantHostsQuery(tx, "id", *tenantId).SubQuery())
+	}
 
 	// Since report id is unique and only one record can be returned by the query.
 	if reportID != uuid.Nil {
@@ -429,7 +631,7 @@ func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID
 
 	if hostState != "" {
 		tx = tx.Joins("INNER JOIN host_status hs on hs.host_id = report.host_id")
-		tx = tx.Where(`hs.status ->> 'host_state'=?`, strings.ToUpper(hostState))
+		tx = tx.Where(dialectOf(tx).jsonText("hs.status", "host_state")+" = ?", strings.ToUpper(hostState))
 	}
 
 	if hostName != "" {
@@ -444,6 +646,22 @@ func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID
 		tx = tx.Where("host_id = ?", hostID.String())
 	}
 
+	if untrustedFlavorPart != "" {
+		tx = tx.Where("report.id IN ?", tx.New().Model(&reportFlavorPart{}).Select("report_id").
+			Where("flavor_part = ? AND trusted = ?", untrustedFlavorPart, false).SubQuery())
+	}
+
 	tx = tx.Limit(limit)
 	return tx
 }
+
+// isFlavorPartUntrusted returns true when the trust report has results for the flavor part and the part is not trusted
+func isFlavorPartUntrusted(trustReport hvs.TrustReport, flavorPart string) bool {
+	tr := hvs.NewTrustReport(trustReport)
+	return len(tr.GetResultsForMarker(flavorPart)) > 0 && !tr.IsTrustedForMarker(flavorPart)
+}
+
+// tenantHostsQuery builds the query selecting the given column of the hosts of a tenant
+func tenantHostsQuery(tx *gorm.DB, column, tenantId string) *gorm.DB {
+	return tx.New().Model(&host{}).Select(column).Where("tenant_id = ?", tenantId)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	// Import driver for GORM
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// sqliteDialect backs the standalone mode, the database is the file named by the Dbname of the configuration. The
+// JSON queries need SQLite built with the JSON1 extension, the sqlite_json build tag of the driver.
+type sqliteDialect struct{}
+
+func (sqliteDialect) connectionString(cfg *Config) (string, error) {
+	// the hosts connection strings are stored in the database, only the service user can read it
+	file, err := os.OpenFile(cfg.Dbname, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error creating the database file %s", cfg.Dbname)
+	}
+	if err = file.Close(); err != nil {
+		return "", errors.Wrapf(err, "Error closing the database file %s", cfg.Dbname)
+	}
+	return "file:" + cfg.Dbname + "?_foreign_keys=1&_busy_timeout=5000", nil
+}
+
+func (sqliteDialect) migrate(db *gorm.DB) error {
+	// the default of the queue params is a postgres cast, the table is created without it
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS queue (
+		id uuid NOT NULL PRIMARY KEY,
+		action VARCHAR(255),
+		params JSON NOT NULL DEFAULT '{}',
+		created_at DATETIME,
+		updated_at DATETIME,
+		state INTEGER,
+		message TEXT
+	)`).Error; err != nil {
+		return errors.Wrap(err, "Error running migration: queue")
+	}
+	return db.AutoMigrate(flavorGroup{}, host{}, flavor{}, trustCache{}, hostuniqueFlavor{}, flavorgroupFlavor{}, flavorLabel{}, hostLabel{}, hostMaintenance{}, hostStatus{}, hostLifecycleTransition{}, esxiCluster{},
+		esxiClusterHost{}, tagCertificate{}, tpmEndorsement{}, platformCertificate{}, flavorLearning{}, flavorPrune{}, report{}, reportFlavorPart{}, trustSummary{}, trustSummaryFault{}, hostCredential{}, hostFlavorgroup{}, auditLogEntry{}, auditEvent{},
+		tenantUsage{}).Error
+}
+
+func (sqliteDialect) jsonPath(path ...interface{}) string {
+	return mysqlDialect{}.jsonPath(path...)
+}
+
+func (d sqliteDialect) jsonText(column string, path ...interface{}) string {
+	return fmt.Sprintf("json_extract(%s, '%s')", column, d.jsonPath(path...))
+}
+
+func (d sqliteDialect) jsonTextAtParam(column string, path ...interface{}) string {
+	return fmt.Sprintf(`json_extract(%s, '%s."' || ? || '"')`, column, d.jsonPath(path...))
+}
+
+func (sqliteDialect) jsonNotNull(column string) string {
+	return "json_type(" + column + ") != 'null'"
+}
+
+func (d sqliteDialect) jsonSet(column, key string) string {
+	return fmt.Sprintf("json_set(%s, '%s', json(?))", column, d.jsonPath(key))
+}
+
+func (sqliteDialect) castToText(expr string) string {
+	return "CAST(" + expr + " AS TEXT)"
+}
+
+func (sqliteDialect) castToUUID(expr string) string {
+	return expr
+}
+
+func (sqliteDialect) castToTimestamp(expr string) string {
+	return "datetime(" + expr + ")"
+}
+
+func (sqliteDialect) insertIgnore(table, values string, conflictColumns ...string) string {
+	return fmt.Sprintf("INSERT OR IGNORE INTO %s VALUES %s", table, values)
+}
+
+func (sqliteDialect) isDuplicateKeyError(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
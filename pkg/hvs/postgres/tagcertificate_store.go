@@ -43,6 +43,7 @@ func (tcs *TagCertificateStore) Create(tc *hvs.TagCertificate) (*hvs.TagCertific
 		Issuer:       tc.Issuer,
 		NotBefore:    tc.NotBefore,
 		NotAfter:     tc.NotAfter,
+		Revoked:      tc.Revoked,
 	}
 
 	if err := tcs.Store.Db.Create(&dbTagCert).Error; err != nil {
@@ -79,7 +80,7 @@ func (tcs *TagCertificateStore) Search(tcFilter *models.TagCertificateFilterCrit
 
 	for rows.Next() {
 		hvsTC := hvs.TagCertificate{}
-		if err := rows.Scan(&hvsTC.ID, &hvsTC.HardwareUUID, &hvsTC.Certificate, &hvsTC.Subject, &hvsTC.Issuer, &hvsTC.NotBefore, &hvsTC.NotAfter); err != nil {
+		if err := rows.Scan(&hvsTC.ID, &hvsTC.HardwareUUID, &hvsTC.Certificate, &hvsTC.Subject, &hvsTC.Issuer, &hvsTC.NotBefore, &hvsTC.NotAfter, &hvsTC.Revoked); err != nil {
 			return nil, errors.Wrap(err, "postgres/tagcertificate_store:Search() failed to scan record")
 		}
 		tcResultSet = append(tcResultSet, &hvsTC)
@@ -95,12 +96,23 @@ func (tcs *TagCertificateStore) Retrieve(tagCertId uuid.UUID) (*hvs.TagCertifica
 
 	hvsTC := hvs.TagCertificate{}
 	row := tcs.Store.Db.Model(&tagCertificate{}).Where(&tagCertificate{ID: tagCertId}).Row()
-	if err := row.Scan(&hvsTC.ID, &hvsTC.HardwareUUID, &hvsTC.Certificate, &hvsTC.Subject, &hvsTC.Issuer, &hvsTC.NotBefore, &hvsTC.NotAfter); err != nil {
+	if err := row.Scan(&hvsTC.ID, &hvsTC.HardwareUUID, &hvsTC.Certificate, &hvsTC.Subject, &hvsTC.Issuer, &hvsTC.NotBefore, &hvsTC.NotAfter, &hvsTC.Revoked); err != nil {
 		return nil, errors.Wrap(err, "postgres/tagcertificate_store:Retrieve() failed to scan record")
 	}
 	return &hvsTC, nil
 }
 
+// Revoke marks a TagCertificate record as revoked
+func (tcs *TagCertificateStore) Revoke(tagCertificateId uuid.UUID) error {
+	defaultLog.Trace("postgres/tagcertificate_store:Revoke() Entering")
+	defer defaultLog.Trace("postgres/tagcertificate_store:Revoke() Leaving")
+
+	if err := tcs.Store.Db.Model(&tagCertificate{ID: tagCertificateId}).Update("revoked", true).Error; err != nil {
+		return errors.Wrap(err, "postgres/tagcertificate_store:Revoke() failed to revoke TagCertificate")
+	}
+	return nil
+}
+
 func (tcs *TagCertificateStore) Delete(tagCertificateId uuid.UUID) error {
 	defaultLog.Trace("postgres/tagcertificate_store:Delete() Entering")
 	defer defaultLog.Trace("postgres/tagcertificate_store:Delete() Leaving")
@@ -157,19 +169,25 @@ func buildTagCertificateSearchQuery(tx *gorm.DB, tcFilter *models.TagCertificate
 		tx = tx.Where("hardware_uuid = ?", tcFilter.HardwareUUID.String())
 	}
 
+	// revoked
+	if tcFilter.RevokedEqualTo != nil {
+		tx = tx.Where("revoked = ?", *tcFilter.RevokedEqualTo)
+	}
+
 	// ValidOn
 	if !tcFilter.ValidOn.IsZero() {
 		validOnTs := tcFilter.ValidOn.Format(constants.ParamDateTimeFormatUTC)
-		tx = tx.Where("CAST(notbefore AS TIMESTAMP) <= CAST(? AS TIMESTAMP) AND CAST(? AS TIMESTAMP) <= CAST(notafter AS TIMESTAMP)", validOnTs, validOnTs)
+		d := dialectOf(tx)
+		tx = tx.Where(d.castToTimestamp("notbefore")+" <= "+d.castToTimestamp("?")+" AND "+d.castToTimestamp("?")+" <= "+d.castToTimestamp("notafter"), validOnTs, validOnTs)
 	}
 
 	if !tcFilter.ValidBefore.IsZero() {
 		validBeforeTs := tcFilter.ValidBefore.Format(constants.ParamDateTimeFormatUTC)
-		tx = tx.Where("CAST(? as timestamp) >= notbefore", validBeforeTs)
+		tx = tx.Where(dialectOf(tx).castToTimestamp("?")+" >= notbefore", validBeforeTs)
 	}
 	if !tcFilter.ValidAfter.IsZero() {
 		validAfterTs := tcFilter.ValidAfter.Format(constants.ParamDateTimeFormatUTC)
-		tx = tx.Where("CAST(? as timestamp) <= notafter", validAfterTs)
+		tx = tx.Where(dialectOf(tx).castToTimestamp("?")+" <= notafter", validAfterTs)
 	}
 
 	// ORDER BY
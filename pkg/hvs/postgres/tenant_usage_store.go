@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type TenantUsageStore struct {
+	Store *DataStore
+}
+
+func NewTenantUsageStore(store *DataStore) *TenantUsageStore {
+	return &TenantUsageStore{store}
+}
+
+// Add adds the counts of the usage records to the persisted ones, the record of a tenant, identity and day is
+// created on its first usage
+func (t *TenantUsageStore) Add(usage []hvs.TenantUsage) error {
+	defaultLog.Trace("postgres/tenant_usage_store:Add() Entering")
+	defer defaultLog.Trace("postgres/tenant_usage_store:Add() Leaving")
+
+	tx := t.Store.Db.Begin()
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "postgres/tenant_usage_store:Add() failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	for _, u := range usage {
+		day := u.Day.UTC().Truncate(24 * time.Hour)
+		db := tx.Model(&tenantUsage{}).
+			Where("tenant_id = ? AND identity = ? AND day = ?", u.TenantId, u.Identity, day).
+			UpdateColumns(map[string]interface{}{
+				"api_calls":          gorm.Expr("api_calls + ?", u.ApiCalls),
+				"report_generations": gorm.Expr("report_generations + ?", u.ReportGenerations),
+			})
+		if db.Error != nil {
+			return errors.Wrap(db.Error, "postgres/tenant_usage_store:Add() failed to update tenant usage")
+		}
+		if db.RowsAffected > 0 {
+			continue
+		}
+		dbTenantUsage := tenantUsage{
+			TenantId:          u.TenantId,
+			Identity:          u.Identity,
+			Day:               day,
+			ApiCalls:          u.ApiCalls,
+			ReportGenerations: u.ReportGenerations,
+		}
+		if err := tx.Create(&dbTenantUsage).Error; err != nil {
+			return errors.Wrap(err, "postgres/tenant_usage_store:Add() failed to create tenant usage")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return errors.Wrap(err, "postgres/tenant_usage_store:Add() failed to commit transaction")
+	}
+	return nil
+}
+
+// Search returns the usage records matching the filter criteria ordered by day, tenant and identity
+func (t *TenantUsageStore) Search(criteria *models.TenantUsageFilterCriteria) ([]hvs.TenantUsage, error) {
+	defaultLog.Trace("postgres/tenant_usage_store:Search() Entering")
+	defer defaultLog.Trace("postgres/tenant_usage_store:Search() Leaving")
+
+	tx := t.Store.Db.Model(&tenantUsage{}).Order("day, tenant_id, identity")
+	if criteria != nil {
+		tx = scopeToTenant(tx, "tenant_id", criteria.TenantId)
+		if !criteria.FromDate.IsZero() {
+			tx = tx.Where("day >= ?", criteria.FromDate.UTC().Truncate(24*time.Hour))
+		}
+		if !criteria.ToDate.IsZero() {
+			tx = tx.Where("day <= ?", criteria.ToDate.UTC())
+		}
+	}
+
+	var dbTenantUsage []tenantUsage
+	if err := tx.Find(&dbTenantUsage).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/tenant_usage_store:Search() failed to retrieve tenant usage")
+	}
+
+	usage := make([]hvs.TenantUsage, 0, len(dbTenantUsage))
+	for _, u := range dbTenantUsage {
+		usage = append(usage, hvs.TenantUsage{
+			TenantId:          u.TenantId,
+			Identity:          u.Identity,
+			Day:               u.Day.UTC(),
+			ApiCalls:          u.ApiCalls,
+			ReportGenerations: u.ReportGenerations,
+		})
+	}
+	return usage, nil
+}
+
+// HostCounts returns the number of hosts registered by each tenant, the hosts of the default namespace are counted
+// under the empty tenant
+func (t *TenantUsageStore) HostCounts() (map[string]int, error) {
+	defaultLog.Trace("postgres/tenant_usage_store:HostCounts() Entering")
+	defer defaultLog.Trace("postgres/tenant_usage_store:HostCounts() Leaving")
+
+	rows, err := t.Store.Db.Model(&host{}).Select("tenant_id, count(*)").Group("tenant_id").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/tenant_usage_store:HostCounts() failed to count hosts")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	hostCounts := map[string]int{}
+	for rows.Next() {
+		var tenantId string
+		var count int
+		if err := rows.Scan(&tenantId, &count); err != nil {
+			return nil, errors.Wrap(err, "postgres/tenant_usage_store:HostCounts() failed to scan host count")
+		}
+		hostCounts[tenantId] = count
+	}
+	return hostCounts, nil
+}
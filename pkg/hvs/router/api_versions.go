@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// apiVersion is a version of the REST API served side by side with the other versions. The routes and controllers
+// of the versions are the same, the middlewares of a version adapt the requests and responses to its conventions.
+type apiVersion struct {
+	path string
+	// middlewares wrap the routes of the version once the caller is authenticated
+	middlewares []mux.MiddlewareFunc
+}
+
+// v2API is the API the clients have been using, its responses are the ones of the controllers
+func v2API() apiVersion {
+	return apiVersion{path: constants.ApiVersion}
+}
+
+// v3API returns the collections in paginated envelopes and replays the responses of the POST requests made again
+// with the same idempotency key
+func v3API(idempotencyCache *IdempotencyCache) apiVersion {
+	return apiVersion{
+		path: constants.ApiVersionV3,
+		middlewares: []mux.MiddlewareFunc{
+			NewIdempotencyHandler(idempotencyCache),
+			NewCollectionPageHandler(),
+		},
+	}
+}
+
+// NewCollectionPageHandler returns a middleware paginating the collections returned by the GET routes. The page is
+// selected with the limit and offset query parameters and returned in a hvs.CollectionPage envelope. The responses
+// of a single resource are returned as they are.
+func NewCollectionPageHandler() mux.MiddlewareFunc {
+	defaultLog.Trace("router/api_versions:NewCollectionPageHandler() Entering")
+	defer defaultLog.Trace("router/api_versions:NewCollectionPageHandler() Leaving")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			query := r.URL.Query()
+			limit, offset := constants.DefaultPageLimit, 0
+			var err error
+			if limitParam := strings.TrimSpace(query.Get("limit")); limitParam != "" {
+				limit, err = strconv.Atoi(limitParam)
+				if err != nil || limit <= 0 || limit > constants.MaxPageLimit {
+					cmw.WriteProblem(w, r, commErr.NewProblemDetails("", http.StatusBadRequest,
+						"Invalid limit, it must be between 1 and "+strconv.Itoa(constants.MaxPageLimit)))
+					return
+				}
+			}
+			if offsetParam := strings.TrimSpace(query.Get("offset")); offsetParam != "" {
+				offset, err = strconv.Atoi(offsetParam)
+				if err != nil || offset < 0 {
+					cmw.WriteProblem(w, r, commErr.NewProblemDetails("", http.StatusBadRequest, "Invalid offset"))
+					return
+				}
+			}
+
+			// the controllers search the whole collection, the page is cut from their response
+			searchRequest := r.Clone(r.Context())
+			searchQuery := searchRequest.URL.Query()
+			searchQuery.Del("limit")
+			searchQuery.Del("offset")
+			searchRequest.URL.RawQuery = searchQuery.Encode()
+
+			recorder := &pageResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(recorder, searchRequest)
+
+			items, isCollection := collectionItems(recorder)
+			if !isCollection {
+				recorder.flush()
+				return
+			}
+			page := hvs.CollectionPage{
+				Items:  []json.RawMessage{},
+				Total:  len(items),
+				Offset: offset,
+				Limit:  limit,
+			}
+			if offset < len(items) {
+				end := offset + limit
+				if end > len(items) {
+					end = len(items)
+				}
+				page.Items = items[offset:end]
+				if end < len(items) {
+					query.Set("offset", strconv.Itoa(end))
+					query.Set("limit", strconv.Itoa(limit))
+					page.Next = r.URL.Path + "?" + query.Encode()
+				}
+			}
+
+			w.Header().Del("Content-Length")
+			w.Header().Set("Content-Type", consts.HTTPMediaTypeJson)
+			w.WriteHeader(http.StatusOK)
+			if err = json.NewEncoder(w).Encode(page); err != nil {
+				defaultLog.WithError(err).Error("router/api_versions:NewCollectionPageHandler() Error writing collection page")
+			}
+		})
+	}
+}
+
+// collectionItems returns the items of a collection response, either a JSON array or an object with a single
+// array, e.g. {"hosts":[...]}
+func collectionItems(recorder *pageResponseWriter) ([]json.RawMessage, bool) {
+	if recorder.statusCode() != http.StatusOK ||
+		!strings.HasPrefix(recorder.Header().Get("Content-Type"), consts.HTTPMediaTypeJson) {
+		return nil, false
+	}
+	body := bytes.TrimSpace(recorder.body.Bytes())
+	if len(body) == 0 {
+		return nil, false
+	}
+
+	var items []json.RawMessage
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, false
+		}
+		return items, true
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || len(fields) != 1 {
+		return nil, false
+	}
+	for _, field := range fields {
+		field = bytes.TrimSpace(field)
+		if bytes.Equal(field, []byte("null")) {
+			return []json.RawMessage{}, true
+		}
+		if len(field) == 0 || field[0] != '[' {
+			return nil, false
+		}
+		if err := json.Unmarshal(field, &items); err != nil {
+			return nil, false
+		}
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+	return items, true
+}
+
+// pageResponseWriter holds back the response of a GET route until it is known whether it is a collection
+type pageResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *pageResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+func (w *pageResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *pageResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// flush writes the response held back as it is
+func (w *pageResponseWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.statusCode())
+	if _, err := w.ResponseWriter.Write(w.body.Bytes()); err != nil {
+		defaultLog.WithError(err).Error("router/api_versions:flush() Error writing response")
+	}
+}
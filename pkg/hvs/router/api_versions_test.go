@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newV3TestRouter(t *testing.T, created *int) *mux.Router {
+	router := mux.NewRouter().PathPrefix("/hvs/v3").Subrouter()
+	version := v3API(NewIdempotencyCache(time.Hour))
+	router.Use(cmw.NewCorrelationID(), cmw.NewProblemDetails())
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, comctx.SetTokenSubject(r, r.Header.Get("X-Test-Subject")))
+		})
+	})
+	router.Use(version.middlewares...)
+
+	router.Handle("/hosts", ErrorHandler(ResponseHandler(func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+		assert.Empty(t, r.URL.Query().Get("limit"))
+		assert.Empty(t, r.URL.Query().Get("offset"))
+		if r.URL.Query().Get("nameContains") == "invalid" {
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid search criteria"}
+		}
+		hosts := hvs.HostCollection{Hosts: []*hvs.Host{}}
+		for i := 0; i < 5; i++ {
+			hosts.Hosts = append(hosts.Hosts, &hvs.Host{HostName: fmt.Sprintf("host%d", i)})
+		}
+		w.Header().Set("Content-Type", consts.HTTPMediaTypeJson)
+		body, _ := json.Marshal(hosts)
+		return string(body), http.StatusOK, nil
+	}))).Methods("GET")
+	router.Handle("/hosts/{id}", ErrorHandler(ResponseHandler(func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+		w.Header().Set("Content-Type", consts.HTTPMediaTypeJson)
+		return `{"host_name":"host0","description":"Intel Host"}`, http.StatusOK, nil
+	}))).Methods("GET")
+	router.Handle("/hosts", ErrorHandler(ResponseHandler(func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+		*created++
+		w.Header().Set("Content-Type", consts.HTTPMediaTypeJson)
+		return fmt.Sprintf(`{"host_name":"host%d"}`, *created), http.StatusCreated, nil
+	}))).Methods("POST")
+	return router
+}
+
+func TestV3InvalidPage(t *testing.T) {
+	created := 0
+	router := newV3TestRouter(t, &created)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/hvs/v3/hosts?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, consts.HTTPMediaTypeProblemJson, w.Header().Get("Content-Type"))
+	var problem commErr.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, commErr.ProblemTypeBase+"bad-request", problem.Type)
+	assert.Equal(t, "Invalid limit, it must be between 1 and 1000", problem.Detail)
+
+	// the errors of the controllers are not paginated
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/hvs/v3/hosts?nameContains=invalid&limit=2", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	problem = commErr.ProblemDetails{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "Invalid search criteria", problem.Detail)
+}
+
+func TestV3CollectionPage(t *testing.T) {
+	created := 0
+	router := newV3TestRouter(t, &created)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/hvs/v3/hosts?limit=2&offset=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page hvs.CollectionPage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, 5, page.Total)
+	assert.Equal(t, 1, page.Offset)
+	assert.Equal(t, 2, page.Limit)
+	assert.Equal(t, "/hvs/v3/hosts?limit=2&offset=3", page.Next)
+	if assert.Len(t, page.Items, 2) {
+		var host hvs.Host
+		assert.NoError(t, json.Unmarshal(page.Items[0], &host))
+		assert.Equal(t, "host1", host.HostName)
+	}
+
+	// the last page has no next link
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", page.Next, nil))
+	page = hvs.CollectionPage{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Items, 2)
+	assert.Empty(t, page.Next)
+
+	// an offset past the end returns an empty page
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/hvs/v3/hosts?offset=10", nil))
+	assert.JSONEq(t, `{"items":[],"total":5,"offset":10,"limit":100}`, w.Body.String())
+
+	// a single resource is not enveloped
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/hvs/v3/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2", nil))
+	assert.JSONEq(t, `{"host_name":"host0","description":"Intel Host"}`, w.Body.String())
+}
+
+func TestV3Idempotency(t *testing.T) {
+	created := 0
+	router := newV3TestRouter(t, &created)
+
+	post := func(subject, key, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/hvs/v3/hosts", bytes.NewBufferString(body))
+		r.Header.Set("X-Test-Subject", subject)
+		if key != "" {
+			r.Header.Set(constants.IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := post("admin", "key-1", `{"host_name":"host1"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"host_name":"host1"}`, w.Body.String())
+
+	// the same request is replayed and not processed again
+	w = post("admin", "key-1", `{"host_name":"host1"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"host_name":"host1"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(constants.IdempotentReplayedHeader))
+	assert.Equal(t, 1, created)
+
+	// the key can not be reused with another request
+	w = post("admin", "key-1", `{"host_name":"host2"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, consts.HTTPMediaTypeProblemJson, w.Header().Get("Content-Type"))
+	var problem commErr.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, commErr.ProblemTypeIdempotencyKeyReused, problem.Type)
+	assert.Equal(t, 1, created)
+
+	// the keys are scoped to the caller and the requests without a key are always processed
+	assert.Equal(t, http.StatusCreated, post("operator", "key-1", `{"host_name":"host1"}`).Code)
+	assert.Equal(t, http.StatusCreated, post("admin", "", `{"host_name":"host1"}`).Code)
+	assert.Equal(t, 3, created)
+}
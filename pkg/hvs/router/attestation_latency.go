@@ -0,0 +1,28 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+)
+
+// SetAttestationLatencyRoutes registers routes for attestation-latency
+func SetAttestationLatencyRoutes(router *mux.Router, latencyRecorder domain.AttestationLatencyRecorder) *mux.Router {
+	defaultLog.Trace("router/attestation_latency:SetAttestationLatencyRoutes() Entering")
+	defer defaultLog.Trace("router/attestation_latency:SetAttestationLatencyRoutes() Leaving")
+
+	attestationLatencyController := controllers.AttestationLatencyController{
+		Recorder: latencyRecorder,
+	}
+
+	router.Handle("/attestation-latency",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(attestationLatencyController.Retrieve),
+			[]string{constants.AttestationLatencyRetrieve}))).Methods("GET")
+
+	return router
+}
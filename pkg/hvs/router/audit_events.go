@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// maxAuditBodyBytes is the largest response body recorded as the state of a created resource
+const maxAuditBodyBytes = 1 << 20
+
+// AuditSnapshot returns the state of the resource with the given ID that is recorded before and after it is changed
+type AuditSnapshot func(uuid.UUID) (interface{}, error)
+
+// SetAuditEventRoutes registers routes for audit-events
+func SetAuditEventRoutes(router *mux.Router, store *postgres.DataStore) *mux.Router {
+	defaultLog.Trace("router/audit_events:SetAuditEventRoutes() Entering")
+	defer defaultLog.Trace("router/audit_events:SetAuditEventRoutes() Leaving")
+
+	auditEventController := controllers.AuditEventController{
+		Store: postgres.NewAuditEventStore(store),
+	}
+
+	router.Handle("/audit-events",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(auditEventController.Search),
+			[]string{constants.AuditEventSearch}))).Methods("GET")
+
+	return router
+}
+
+// NewAuditHandler returns a middleware recording every POST, PUT and DELETE call to the routes below the path
+// prefix, with the user that made it and its outcome. The state of the resources with a snapshot, keyed by the
+// first segment of their path, is recorded before and after the call.
+func NewAuditHandler(store domain.AuditEventStore, pathPrefix string, snapshots map[string]AuditSnapshot) mux.MiddlewareFunc {
+	defaultLog.Trace("router/audit_events:NewAuditHandler() Entering")
+	defer defaultLog.Trace("router/audit_events:NewAuditHandler() Leaving")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodDelete {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			route := strings.TrimPrefix(r.URL.Path, pathPrefix)
+			event := hvs.AuditEvent{
+				Created:    time.Now().UTC(),
+				RemoteAddr: r.RemoteAddr,
+				Method:     r.Method,
+				Route:      route,
+			}
+			event.Actor, _ = comctx.GetTokenSubject(r)
+			event.TenantId, _ = utils.GetTenantId(r)
+
+			segments := strings.Split(strings.Trim(route, "/"), "/")
+			event.EntityType = segments[0]
+			if len(segments) > 1 {
+				if id, err := uuid.Parse(segments[1]); err == nil {
+					event.EntityID = &id
+				}
+			}
+
+			snapshot := snapshots[event.EntityType]
+			if snapshot != nil && event.EntityID != nil {
+				event.Before = takeAuditSnapshot(snapshot, *event.EntityID)
+			}
+
+			recorder := &auditResponseWriter{
+				ResponseWriter: w,
+				captureBody:    snapshot != nil && event.EntityID == nil && r.Method == http.MethodPost,
+			}
+			next.ServeHTTP(recorder, r)
+
+			event.StatusCode = recorder.statusCode()
+			succeeded := event.StatusCode >= http.StatusOK && event.StatusCode < http.StatusMultipleChoices
+			if snapshot != nil && succeeded {
+				if event.EntityID != nil && r.Method != http.MethodDelete {
+					event.After = takeAuditSnapshot(snapshot, *event.EntityID)
+				} else if recorder.captureBody && json.Valid(recorder.body.Bytes()) {
+					event.After = json.RawMessage(recorder.body.Bytes())
+				}
+			}
+			event.Summary = auditSummary(r.Method, route, event.StatusCode, succeeded)
+
+			if _, err := store.Create(&event); err != nil {
+				defaultLog.WithError(err).Errorf("router/audit_events:NewAuditHandler() Failed to record audit event of %s %s", r.Method, route)
+			}
+		})
+	}
+}
+
+func takeAuditSnapshot(snapshot AuditSnapshot, id uuid.UUID) json.RawMessage {
+	state, err := snapshot(id)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Debug("router/audit_events:takeAuditSnapshot() Resource state not recorded")
+		return nil
+	}
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("router/audit_events:takeAuditSnapshot() Failed to marshal resource state")
+		return nil
+	}
+	return stateBytes
+}
+
+func auditSummary(method, route string, statusCode int, succeeded bool) string {
+	action := map[string]string{
+		http.MethodPost:   "create",
+		http.MethodPut:    "update",
+		http.MethodDelete: "delete",
+	}[method]
+	if succeeded {
+		return fmt.Sprintf("%s %s succeeded", action, route)
+	}
+	return fmt.Sprintf("%s %s failed with status %d", action, route, statusCode)
+}
+
+// auditResponseWriter records the status code of the response and, when requested, its body
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	captureBody bool
+	body        bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.captureBody && w.body.Len()+len(b) <= maxAuditBodyBytes {
+		w.body.Write(b)
+	} else {
+		// a truncated body is not recorded
+		w.captureBody = false
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
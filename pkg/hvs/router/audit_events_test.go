@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHandler(t *testing.T) {
+	store := mocks.NewFakeAuditEventStore()
+	flavorId := uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3")
+	flavors := map[uuid.UUID]string{flavorId: "old"}
+
+	router := mux.NewRouter().PathPrefix("/hvs/v2").Subrouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, comctx.SetTokenSubject(r, "admin"))
+		})
+	})
+	router.Use(NewAuditHandler(store, "/hvs/v2", map[string]AuditSnapshot{
+		"flavors": func(id uuid.UUID) (interface{}, error) {
+			if label, ok := flavors[id]; ok {
+				return map[string]string{"label": label}, nil
+			}
+			return nil, errors.New("no rows in result set")
+		},
+	}))
+	router.HandleFunc("/flavors", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	router.HandleFunc("/flavors", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"label":"new"}`))
+	}).Methods("POST")
+	router.HandleFunc("/flavors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		delete(flavors, uuid.MustParse(mux.Vars(r)["id"]))
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+	router.HandleFunc("/hosts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}).Methods("PUT")
+
+	// read only calls are not recorded
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/hvs/v2/flavors", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, store.AuditEvents)
+
+	// the created resource is recorded from the response
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/hvs/v2/flavors", bytes.NewBufferString("{}")))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"label":"new"}`, w.Body.String())
+	assert.Len(t, store.AuditEvents, 1)
+	event := store.AuditEvents[0]
+	assert.Equal(t, "admin", event.Actor)
+	assert.Equal(t, "flavors", event.EntityType)
+	assert.Nil(t, event.EntityID)
+	assert.Equal(t, http.StatusCreated, event.StatusCode)
+	assert.Equal(t, "create /flavors succeeded", event.Summary)
+	assert.Nil(t, event.Before)
+	assert.JSONEq(t, `{"label":"new"}`, string(event.After))
+
+	// the deleted resource is recorded before the call
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/hvs/v2/flavors/"+flavorId.String(), nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, store.AuditEvents, 2)
+	event = store.AuditEvents[1]
+	assert.Equal(t, flavorId, *event.EntityID)
+	assert.JSONEq(t, `{"label":"old"}`, string(event.Before))
+	assert.Nil(t, event.After)
+
+	// the state of the resources without a snapshot is not recorded
+	hostId := uuid.New()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/hvs/v2/hosts/"+hostId.String(), bytes.NewBufferString("{}")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, store.AuditEvents, 3)
+	event = store.AuditEvents[2]
+	assert.Equal(t, "hosts", event.EntityType)
+	assert.Equal(t, hostId, *event.EntityID)
+	assert.Equal(t, "update /hosts/"+hostId.String()+" failed with status 400", event.Summary)
+	assert.Nil(t, event.Before)
+	assert.Nil(t, event.After)
+	assert.Equal(t, "/hosts/"+hostId.String(), event.Route)
+}
@@ -33,5 +33,9 @@ func SetDeploySoftwareManifestRoute(router *mux.Router, store *postgres.DataStor
 		ErrorHandler(permissionsHandler(ResponseHandler(dsmController.DeployManifest),
 			[]string{constants.SoftwareFlavorDeploy}))).Methods("POST")
 
+	router.Handle("/rpc/deploy-software-manifests",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(dsmController.DeployManifests),
+			[]string{constants.SoftwareFlavorDeploy}))).Methods("POST")
+
 	return router
 }
@@ -28,10 +28,11 @@ func SetESXiClusterRoutes(router *mux.Router, store *postgres.DataStore,
 	hostStatusStore := postgres.NewHostStatusStore(store)
 	flavorStore := postgres.NewFlavorStore(store)
 	flavorGroupStore := postgres.NewFlavorGroupStore(store)
+	reportStore := postgres.NewReportStore(store)
 	hostCredentialStore := postgres.NewHostCredentialStore(store, hostControllerConfig.DataEncryptionKey)
 	hc := controllers.NewHostController(hostStore, hostStatusStore, flavorStore,
 		flavorGroupStore, hostCredentialStore, hostTrustManager, hostControllerConfig)
-	esxiClusterController := controllers.NewESXiClusterController(esxiClusterStore, *hc)
+	esxiClusterController := controllers.NewESXiClusterController(esxiClusterStore, reportStore, *hc)
 
 	esxiClusterIdExpr := fmt.Sprintf("%s%s", "/esxi-cluster/", validation.IdReg)
 
@@ -51,5 +52,9 @@ func SetESXiClusterRoutes(router *mux.Router, store *postgres.DataStore,
 		ErrorHandler(permissionsHandler(JsonResponseHandler(esxiClusterController.Retrieve),
 			[]string{constants.ESXiClusterRetrieve}))).Methods("GET")
 
+	router.Handle(esxiClusterIdExpr+"/trust",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(esxiClusterController.RetrieveTrust),
+			[]string{constants.ESXiClusterRetrieve}))).Methods("GET")
+
 	return router
 }
@@ -0,0 +1,34 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+)
+
+// SetFlavorFromRimRoute registers routes for APIs that create platform flavor from reference integrity manifests
+func SetFlavorFromRimRoute(router *mux.Router, store *postgres.DataStore, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore,
+	hostTrustManager domain.HostTrustManager, hcConfig domain.HostControllerConfig) *mux.Router {
+	defaultLog.Trace("router/flavor-from-rim:SetFlavorFromRimRoute() Entering")
+	defer defaultLog.Trace("router/flavor-from-rim:SetFlavorFromRimRoute() Leaving")
+
+	flavorStore := postgres.NewFlavorStore(store)
+	hostStore := postgres.NewHostStore(store)
+	tagCertStore := postgres.NewTagCertificateStore(store)
+	flavorController := controllers.NewFlavorController(flavorStore, flavorGroupStore, hostStore, tagCertStore, hostTrustManager, certStore, hcConfig)
+	flavorFromRimController := controllers.NewFlavorFromRimController(*flavorController)
+
+	router.Handle("/flavor-from-rim",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorFromRimController.CreatePlatformFlavor),
+			[]string{constants.FlavorCreate}))).Methods("POST")
+
+	return router
+}
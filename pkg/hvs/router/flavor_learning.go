@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+)
+
+// SetFlavorLearningRoutes registers routes for flavor-learning
+func SetFlavorLearningRoutes(router *mux.Router, store *postgres.DataStore) *mux.Router {
+	defaultLog.Trace("router/flavor_learning:SetFlavorLearningRoutes() Entering")
+	defer defaultLog.Trace("router/flavor_learning:SetFlavorLearningRoutes() Leaving")
+
+	flavorLearningController := controllers.FlavorLearningController{
+		Store:   postgres.NewFlavorLearningStore(store),
+		HStore:  postgres.NewHostStore(store),
+		HSStore: postgres.NewHostStatusStore(store),
+	}
+	flavorLearningIdExpr := fmt.Sprintf("%s%s", "/flavor-learning/", validation.IdReg)
+
+	router.Handle("/flavor-learning",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorLearningController.Create),
+			[]string{constants.FlavorLearningCreate}))).Methods("POST")
+
+	router.Handle("/flavor-learning",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorLearningController.Search),
+			[]string{constants.FlavorLearningSearch}))).Methods("GET")
+
+	router.Handle(flavorLearningIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorLearningController.Retrieve),
+			[]string{constants.FlavorLearningRetrieve}))).Methods("GET")
+
+	router.Handle(flavorLearningIdExpr+"/proposal",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorLearningController.Proposal),
+			[]string{constants.FlavorLearningRetrieve}))).Methods("GET")
+
+	router.Handle(flavorLearningIdExpr,
+		ErrorHandler(permissionsHandler(ResponseHandler(flavorLearningController.Delete),
+			[]string{constants.FlavorLearningDelete}))).Methods("DELETE")
+
+	return router
+}
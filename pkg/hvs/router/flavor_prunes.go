@@ -0,0 +1,48 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+)
+
+// SetFlavorPruneRoutes registers routes for flavor-prunes
+func SetFlavorPruneRoutes(router *mux.Router, store *postgres.DataStore, fgs *postgres.FlavorGroupStore, hostTrustManager domain.HostTrustManager) *mux.Router {
+	defaultLog.Trace("router/flavor_prunes:SetFlavorPruneRoutes() Entering")
+	defer defaultLog.Trace("router/flavor_prunes:SetFlavorPruneRoutes() Leaving")
+
+	flavorPruneController := controllers.FlavorPruneController{
+		Store:     postgres.NewFlavorPruneStore(store),
+		RStore:    postgres.NewReportStore(store),
+		FGStore:   fgs,
+		HTManager: hostTrustManager,
+	}
+	flavorPruneIdExpr := fmt.Sprintf("%s%s", "/flavor-prunes/", validation.IdReg)
+
+	router.Handle("/flavor-prunes",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorPruneController.Create),
+			[]string{constants.FlavorPruneCreate}))).Methods("POST")
+
+	router.Handle("/flavor-prunes",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorPruneController.Search),
+			[]string{constants.FlavorPruneSearch}))).Methods("GET")
+
+	router.Handle(flavorPruneIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorPruneController.Retrieve),
+			[]string{constants.FlavorPruneRetrieve}))).Methods("GET")
+
+	router.Handle(flavorPruneIdExpr+"/undo",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorPruneController.Undo),
+			[]string{constants.FlavorPruneCreate}))).Methods("POST")
+
+	return router
+}
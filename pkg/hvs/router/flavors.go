@@ -13,10 +13,11 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	fm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
 )
 
 // SetFlavorRoutes registers routes for flavors
-func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, flavorControllerConfig domain.HostControllerConfig) *mux.Router {
+func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, flavorControllerConfig domain.HostControllerConfig, metadataSchema fm.MetadataSchema) *mux.Router {
 	defaultLog.Trace("router/flavors:SetFlavorRoutes() Entering")
 	defer defaultLog.Trace("router/flavors:SetFlavorRoutes() Leaving")
 
@@ -24,6 +25,9 @@ func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupS
 	flavorStore := postgres.NewFlavorStore(store)
 	tagCertStore := postgres.NewTagCertificateStore(store)
 	flavorController := controllers.NewFlavorController(flavorStore, flavorGroupStore, hostStore, tagCertStore, hostTrustManager, certStore, flavorControllerConfig)
+	if flavorController != nil {
+		flavorController.MetadataSchema = metadataSchema
+	}
 
 	flavorIdExpr := fmt.Sprintf("%s%s", "/flavors/", validation.IdReg)
 
@@ -44,5 +48,13 @@ func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupS
 		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.Retrieve),
 			[]string{constants.FlavorRetrieve}))).Methods("GET")
 
+	router.Handle(flavorIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.Update),
+			[]string{constants.FlavorUpdate}))).Methods("PATCH")
+
+	router.Handle(flavorIdExpr+"/signatures",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.AddSignature),
+			[]string{constants.FlavorSign}))).Methods("POST")
+
 	return router
 }
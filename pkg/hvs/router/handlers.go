@@ -14,6 +14,7 @@ import (
 	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	ct "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
@@ -170,6 +171,8 @@ func ErrorHandler(eh endpointHandler) http.HandlerFunc {
 				return
 			}
 			switch t := err.(type) {
+			case *commErr.ProblemError:
+				cmw.WriteProblem(w, r, commErr.NewProblemDetails(t.Type, t.StatusCode, t.Message))
 			case *commErr.HandledError:
 				http.Error(w, t.Message, t.StatusCode)
 			case *commErr.PrivilegeError:
@@ -29,11 +29,14 @@ func SetHostRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManag
 	hostController := controllers.NewHostController(hostStore, hostStatusStore,
 		flavorStore, flavorGroupStore, hostCredentialStore,
 		hostTrustManager, hostControllerConfig)
+	hostController.PCStore = postgres.NewPlatformCertificateStore(store)
 
 	hostExpr := "/hosts"
 	hostIdExpr := fmt.Sprintf("%s/{hId:%s}", hostExpr, validation.UUIDReg)
 	flavorgroupExpr := fmt.Sprintf("%s/flavorgroups", hostIdExpr)
 	flavorgroupIdExpr := fmt.Sprintf("%s/{fgId:%s}", flavorgroupExpr, validation.UUIDReg)
+	hardwareUuidConflictExpr := fmt.Sprintf("%s/hardware-uuid-conflicts", hostExpr)
+	maintenanceExpr := fmt.Sprintf("%s/maintenance", hostIdExpr)
 
 	router.Handle(hostExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.Create),
 		[]string{constants.HostCreate}))).Methods("POST")
@@ -46,6 +49,16 @@ func SetHostRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManag
 	router.Handle(hostExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.Search),
 		[]string{constants.HostSearch}))).Methods("GET")
 
+	router.Handle(maintenanceExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.SetMaintenance),
+		[]string{constants.HostUpdate}))).Methods("PUT")
+	router.Handle(maintenanceExpr, ErrorHandler(permissionsHandler(ResponseHandler(hostController.EndMaintenance),
+		[]string{constants.HostUpdate}))).Methods("DELETE")
+
+	router.Handle(hardwareUuidConflictExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.SearchHardwareUuidConflicts),
+		[]string{constants.HostSearch}))).Methods("GET")
+	router.Handle(hardwareUuidConflictExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.ResolveHardwareUuidConflict),
+		[]string{constants.HostUpdate}))).Methods("POST")
+
 	router.Handle(flavorgroupExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.AddFlavorgroup),
 		[]string{constants.HostCreate}))).Methods("POST")
 	router.Handle(flavorgroupIdExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.RetrieveFlavorgroup),
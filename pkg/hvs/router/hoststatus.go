@@ -25,6 +25,9 @@ func SetHostStatusRoutes(router *mux.Router, store *postgres.DataStore) *mux.Rou
 	router.Handle("/host-status", ErrorHandler(permissionsHandler(JsonResponseHandler(hoststatusController.Search),
 		[]string{constants.HostStatusSearch}))).Methods("GET")
 
+	router.Handle("/host-status/lifecycle-history", ErrorHandler(permissionsHandler(JsonResponseHandler(hoststatusController.SearchLifecycleHistory),
+		[]string{constants.HostStatusSearch}))).Methods("GET")
+
 	hostStatusIdExpr := fmt.Sprintf("%s%s", "/host-status/", validation.IdReg)
 	router.Handle(hostStatusIdExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hoststatusController.Retrieve),
 		[]string{constants.HostStatusRetrieve}))).Methods("GET")
@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
+)
+
+// idempotentResponse is the response of a POST request made with an idempotency key, the response is not done while
+// the request is in progress
+type idempotentResponse struct {
+	requestDigest [sha256.Size]byte
+	done          bool
+	status        int
+	contentType   string
+	location      string
+	body          []byte
+	expiry        time.Time
+}
+
+// IdempotencyCache keeps the responses of the POST requests made with an idempotency key until they expire. The keys
+// are scoped to the caller and the route, two callers can use the same key.
+type IdempotencyCache struct {
+	mutex     sync.Mutex
+	expiry    time.Duration
+	responses map[string]*idempotentResponse
+}
+
+// NewIdempotencyCache returns a cache keeping the responses for the expiry duration
+func NewIdempotencyCache(expiry time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		expiry:    expiry,
+		responses: make(map[string]*idempotentResponse),
+	}
+}
+
+// begin returns the response of the earlier request made with the key, or records the request as in progress when
+// there was none
+func (c *IdempotencyCache) begin(key string, requestDigest [sha256.Size]byte) (*idempotentResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for k, response := range c.responses {
+		if response.done && response.expiry.Before(now) {
+			delete(c.responses, k)
+		}
+	}
+	if response, ok := c.responses[key]; ok {
+		return response, true
+	}
+	c.responses[key] = &idempotentResponse{requestDigest: requestDigest}
+	return nil, false
+}
+
+// complete keeps the response of the request made with the key, the server errors are not kept so that the request
+// can be retried
+func (c *IdempotencyCache) complete(key string, recorder *idempotentResponseWriter) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	response, ok := c.responses[key]
+	if !ok {
+		return
+	}
+	if recorder.statusCode() >= http.StatusInternalServerError || recorder.truncated {
+		delete(c.responses, key)
+		return
+	}
+	response.done = true
+	response.status = recorder.statusCode()
+	response.contentType = recorder.Header().Get("Content-Type")
+	response.location = recorder.Header().Get("Location")
+	response.body = recorder.body.Bytes()
+	response.expiry = time.Now().Add(c.expiry)
+}
+
+// NewIdempotencyHandler returns a middleware replaying the response of a POST request made again with the same
+// Idempotency-Key header. A key reused with a different request or while the first request is in progress is
+// rejected, the POST requests without a key are always processed.
+func NewIdempotencyHandler(cache *IdempotencyCache) mux.MiddlewareFunc {
+	defaultLog.Trace("router/idempotency:NewIdempotencyHandler() Entering")
+	defer defaultLog.Trace("router/idempotency:NewIdempotencyHandler() Leaving")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := strings.TrimSpace(r.Header.Get(constants.IdempotencyKeyHeader))
+			if r.Method != http.MethodPost || idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idempotencyKey) > constants.IdempotencyKeyMaxLength {
+				cmw.WriteProblem(w, r, commErr.NewProblemDetails("", http.StatusBadRequest,
+					"Invalid "+constants.IdempotencyKeyHeader))
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cmw.WriteProblem(w, r, commErr.NewProblemDetails("", http.StatusBadRequest,
+					"Unable to read the request body"))
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			requestDigest := sha256.Sum256(append([]byte(r.URL.RequestURI()+"\n"), body...))
+
+			subject, _ := comctx.GetTokenSubject(r)
+			key := subject + "\n" + r.URL.Path + "\n" + idempotencyKey
+			response, found := cache.begin(key, requestDigest)
+			if found {
+				switch {
+				case response.requestDigest != requestDigest:
+					cmw.WriteProblem(w, r, commErr.NewProblemDetails(commErr.ProblemTypeIdempotencyKeyReused,
+						http.StatusUnprocessableEntity, constants.IdempotencyKeyHeader+" was already used with a different request"))
+				case !response.done:
+					cmw.WriteProblem(w, r, commErr.NewProblemDetails(commErr.ProblemTypeIdempotencyKeyInProgress,
+						http.StatusConflict, "A request with the same "+constants.IdempotencyKeyHeader+" is in progress"))
+				default:
+					replayResponse(w, response)
+				}
+				return
+			}
+
+			recorder := &idempotentResponseWriter{ResponseWriter: w}
+			defer cache.complete(key, recorder)
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+func replayResponse(w http.ResponseWriter, response *idempotentResponse) {
+	if response.contentType != "" {
+		w.Header().Set("Content-Type", response.contentType)
+	}
+	if response.location != "" {
+		w.Header().Set("Location", response.location)
+	}
+	w.Header().Set(constants.IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.status)
+	if _, err := w.Write(response.body); err != nil {
+		defaultLog.WithError(err).Error("router/idempotency:replayResponse() Error writing response")
+	}
+}
+
+// idempotentResponseWriter records the response of a POST request while it is written
+type idempotentResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *idempotentResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *idempotentResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.body.Len()+len(b) <= maxAuditBodyBytes {
+		w.body.Write(b)
+	} else {
+		// a response too large to be kept is not replayed
+		w.truncated = true
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotentResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+)
+
+// SetPlatformCertificateRoutes registers routes for platform-certificates
+func SetPlatformCertificateRoutes(router *mux.Router, store *postgres.DataStore, certStore *models.CertificatesStore) *mux.Router {
+	defaultLog.Trace("router/platform_certificates:SetPlatformCertificateRoutes() Entering")
+	defer defaultLog.Trace("router/platform_certificates:SetPlatformCertificateRoutes() Leaving")
+
+	platformCertificateController := controllers.PlatformCertificateController{
+		Store:     postgres.NewPlatformCertificateStore(store),
+		TEStore:   postgres.NewTpmEndorsementStore(store),
+		CertStore: certStore,
+	}
+	platformCertificateIdExpr := fmt.Sprintf("%s%s", "/platform-certificates/", validation.IdReg)
+
+	router.Handle("/platform-certificates",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(platformCertificateController.Create),
+			[]string{constants.PlatformCertificateCreate}))).Methods("POST")
+
+	router.Handle("/platform-certificates",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(platformCertificateController.Search),
+			[]string{constants.PlatformCertificateSearch}))).Methods("GET")
+
+	router.Handle(platformCertificateIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(platformCertificateController.Retrieve),
+			[]string{constants.PlatformCertificateRetrieve}))).Methods("GET")
+
+	router.Handle(platformCertificateIdExpr,
+		ErrorHandler(permissionsHandler(ResponseHandler(platformCertificateController.Delete),
+			[]string{constants.PlatformCertificateDelete}))).Methods("DELETE")
+
+	router.Handle(platformCertificateIdExpr+"/revoke",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(platformCertificateController.Revoke),
+			[]string{constants.PlatformCertificateRevoke}))).Methods("POST")
+
+	return router
+}
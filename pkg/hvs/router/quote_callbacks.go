@@ -0,0 +1,32 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+)
+
+// SetQuoteCallbackRoutes registers routes for quote-callbacks
+func SetQuoteCallbackRoutes(router *mux.Router, quoteCallbacks *hostConnector.QuoteCallbacks) *mux.Router {
+	defaultLog.Trace("router/quote_callbacks:SetQuoteCallbackRoutes() Entering")
+	defer defaultLog.Trace("router/quote_callbacks:SetQuoteCallbackRoutes() Leaving")
+
+	quoteCallbackController := controllers.QuoteCallbackController{
+		Callbacks: quoteCallbacks,
+	}
+	quoteCallbackIdExpr := fmt.Sprintf("%s%s", "/quote-callbacks/", validation.IdReg)
+
+	router.Handle(quoteCallbackIdExpr,
+		ErrorHandler(permissionsHandler(ResponseHandler(quoteCallbackController.Create),
+			[]string{constants.QuoteCallbackCreate}))).Methods("POST")
+
+	return router
+}
@@ -25,6 +25,7 @@ func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustMan
 	hostStore := postgres.NewHostStore(store)
 	hostStatusStore := postgres.NewHostStatusStore(store)
 	reportController := controllers.NewReportController(reportStore, hostStore, hostStatusStore, hostTrustManager)
+	reportController.PCStore = postgres.NewPlatformCertificateStore(store)
 
 	reportIdExpr := fmt.Sprintf("%s%s", "/reports/", validation.IdReg)
 
@@ -48,5 +49,9 @@ func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustMan
 		ErrorHandler(permissionsHandler(JsonResponseHandler(reportController.Search),
 			[]string{constants.ReportSearch}))).Methods("GET")
 
+	router.Handle(fmt.Sprintf("/hosts/{hId:%s}/trust-history", validation.UUIDReg),
+		ErrorHandler(permissionsHandler(JsonResponseHandler(reportController.TrustHistory),
+			[]string{constants.ReportSearch}))).Methods("GET")
+
 	return router
 }
@@ -5,7 +5,6 @@
 package router
 
 import (
-	"crypto/tls"
 	"crypto/x509"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"io/ioutil"
@@ -13,15 +12,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v3/pkg/lib/common/os"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/pkg/errors"
 )
 
@@ -33,7 +35,7 @@ type Router struct {
 }
 
 // InitRoutes registers all routes for the application.
-func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) (*mux.Router, error) {
+func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, latencyRecorder domain.AttestationLatencyRecorder, quoteCallbacks *hostConnector.QuoteCallbacks, usageMeter domain.UsageMeter) (*mux.Router, error) {
 	defaultLog.Trace("router/router:InitRoutes() Entering")
 	defer defaultLog.Trace("router/router:InitRoutes() Leaving")
 
@@ -43,49 +45,106 @@ func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *p
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
 
-	err := defineSubRoutes(router, constants.OldServiceName, cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	// Render the errors of all the routes as problem details with the correlation ID of the request
+	router.Use(cmw.NewCorrelationID(), cmw.NewProblemDetails())
+
+	// Reject oversized, compressed and slowly sent request bodies before they reach any handler and bound the
+	// size of the responses
+	router.Use(cmw.NewRequestLimits(cfg.Server))
+
+	err := defineSubRoutes(router, constants.OldServiceName, v2API(), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, latencyRecorder, quoteCallbacks, usageMeter)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
 	}
-	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), v2API(), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, latencyRecorder, quoteCallbacks, usageMeter)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
 	}
+	// the v3 API is served next to v2 so that the clients can migrate one call at a time
+	idempotencyCache := NewIdempotencyCache(constants.IdempotencyKeyExpiry)
+	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), v3API(idempotencyCache), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, latencyRecorder, quoteCallbacks, usageMeter)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not define v3 sub routes")
+	}
 	return router, nil
 }
 
-func defineSubRoutes(router *mux.Router, service string, cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) error {
+func defineSubRoutes(router *mux.Router, service string, version apiVersion, cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, latencyRecorder domain.AttestationLatencyRecorder, quoteCallbacks *hostConnector.QuoteCallbacks, usageMeter domain.UsageMeter) error {
 	defaultLog.Trace("router/router:defineSubRoutes() Entering")
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
-	serviceApi := "/" + service + constants.ApiVersion
+	serviceApi := "/" + service + version.path
 	subRouter := router.PathPrefix(serviceApi).Subrouter()
+	subRouter.Use(version.middlewares...)
 	subRouter = SetVersionRoutes(subRouter)
 	subRouter = SetCaCertificatesRoutes(subRouter, certStore)
 
-	subRouter = router.PathPrefix(serviceApi).Subrouter()
 	cfgRouter := Router{cfg: cfg}
 	var cacheTime, err = time.ParseDuration(constants.JWTCertsCacheTime)
 	if err != nil {
 		return errors.Wrap(err, "Could not parse JWT Certificate cache time")
 	}
-	subRouter.Use(cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
+	tokenAuth := cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
 		constants.TrustedRootCACertsDir, cfgRouter.fnGetJwtCerts,
-		cacheTime))
+		cacheTime)
+
+	fStore := postgres.NewFlavorStore(dataStore)
+	auditHandler := NewAuditHandler(postgres.NewAuditEventStore(dataStore), serviceApi, map[string]AuditSnapshot{
+		"flavors": func(id uuid.UUID) (interface{}, error) {
+			return fStore.Retrieve(id)
+		},
+		"flavorgroups": func(id uuid.UUID) (interface{}, error) {
+			return fgs.Retrieve(id)
+		},
+	})
+
+	// the trust agents authenticated by a certificate only are granted the quote callback permission
+	subRouter = router.PathPrefix(serviceApi).Subrouter()
+	err = cmw.UseRouteGroupAuth(subRouter, cmw.RouteGroupAuth{
+		Mode:               cmw.AuthMode(cfg.QuoteCallbackAuth.Mode),
+		TrustedCAsDir:      constants.TrustedRootCACertsDir,
+		AllowedCommonNames: cfg.QuoteCallbackAuth.AllowedCommonNames,
+		Permissions: []aas.PermissionInfo{{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.QuoteCallbackCreate},
+		}},
+	}, tokenAuth)
+	if err != nil {
+		return errors.Wrap(err, "Invalid quote callback authentication")
+	}
+	subRouter.Use(version.middlewares...)
+	subRouter.Use(NewUsageMeterHandler(usageMeter))
+	subRouter.Use(auditHandler)
+	subRouter = SetQuoteCallbackRoutes(subRouter, quoteCallbacks)
+
+	subRouter = router.PathPrefix(serviceApi).Subrouter()
+	subRouter.Use(tokenAuth)
+	subRouter.Use(version.middlewares...)
+	subRouter.Use(NewUsageMeterHandler(usageMeter))
+	subRouter.Use(auditHandler)
 	subRouter = SetFlavorGroupRoutes(subRouter, dataStore, fgs, hostTrustManager)
-	subRouter = SetFlavorRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	subRouter = SetFlavorRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, cfg.FlavorMetadataSchema)
 	subRouter = SetTpmEndorsementRoutes(subRouter, dataStore)
+	subRouter = SetPlatformCertificateRoutes(subRouter, dataStore, certStore)
+	subRouter = SetFlavorLearningRoutes(subRouter, dataStore)
+	subRouter = SetFlavorPruneRoutes(subRouter, dataStore, fgs, hostTrustManager)
+	subRouter = SetAuditEventRoutes(subRouter, dataStore)
+	subRouter = SetTenantUsageRoutes(subRouter, dataStore, usageMeter)
 	subRouter = SetCertifyAiksRoutes(subRouter, dataStore, certStore, cfg.AikCertValidity)
 	subRouter = SetHostStatusRoutes(subRouter, dataStore)
 	subRouter = SetCertifyHostKeysRoutes(subRouter, certStore)
 	subRouter = SetHostRoutes(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetReportRoutes(subRouter, dataStore, hostTrustManager)
+	subRouter = SetAttestationLatencyRoutes(subRouter, latencyRecorder)
+	subRouter = SetRuleDefinitionRoutes(subRouter)
 	subRouter = SetCreateCaCertificatesRoutes(subRouter, certStore)
-	subRouter = SetTagCertificateRoutes(subRouter, cfg, fgs, certStore, hostTrustManager, dataStore)
+	subRouter = SetTagCertificateRoutes(subRouter, cfg, fgs, certStore, hostTrustManager, dataStore, hostControllerConfig)
 	subRouter = SetESXiClusterRoutes(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetDeploySoftwareManifestRoute(subRouter, dataStore, hostTrustManager, hostControllerConfig)
+	subRouter = SetVerifyManifestRoute(subRouter, dataStore, certStore)
 	subRouter = SetManifestsRoute(subRouter, dataStore)
 	subRouter = SetFlavorFromAppManifestRoute(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	subRouter = SetFlavorFromRimRoute(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
 	return nil
 }
 
@@ -123,13 +182,11 @@ func (r *Router) fnGetJwtCerts() error {
 			return err
 		}
 	}
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.RootCAs = rootCAs
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: false,
-				RootCAs:            rootCAs,
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 
@@ -0,0 +1,22 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+)
+
+func SetRuleDefinitionRoutes(router *mux.Router) *mux.Router {
+	defaultLog.Trace("router/rule_definitions:SetRuleDefinitionRoutes() Entering")
+	defer defaultLog.Trace("router/rule_definitions:SetRuleDefinitionRoutes() Leaving")
+
+	ruleDefinitionController := controllers.RuleDefinitionController{}
+
+	router.Handle("/rule-definitions", ErrorHandler(permissionsHandler(JsonResponseHandler(ruleDefinitionController.Search),
+		[]string{consts.RuleDefinitionSearch}))).Methods("GET")
+	return router
+}
@@ -15,23 +15,21 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
-	hostConnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
 )
 
 const (
 	TagCertificateEndpointPath       = "/tag-certificates"
 	TagCertificateDeployEndpointPath = "/rpc/deploy-tag-certificate"
+	TagCertificateRevokeEndpointPath = "/rpc/revoke-tag-certificate"
 )
 
 // SetTagCertificateRoutes registers routes for tag-certificates API
-func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, store *postgres.DataStore) *mux.Router {
+func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, store *postgres.DataStore, hostControllerConfig domain.HostControllerConfig) *mux.Router {
 	defaultLog.Trace("router/tag_certificates:SetTagCertificateRoutes() Entering")
 	defer defaultLog.Trace("router/tag_certificates:SetTagCertificateRoutes() Leaving")
 
-	// set up the HostConnectorProvider for the Controller
-	rootCAs := (*certStore)[models.CaCertTypesRootCa.String()].Certificates
-	var hcp hostConnector.HostConnectorProvider
-	hcp = hostConnector.NewHostConnectorFactory(cfg.AASApiUrl, rootCAs)
+	// the HostConnectorProvider of the Controller shares the trust agent authentication of the host controllers
+	hcp := hostControllerConfig.HostConnectorProvider
 
 	if hcp == nil {
 		defaultLog.Errorf("router/tag_certificates:SetTagCertificateRoutes() %s : Error initializing the Host Connector Factory", commLogMsg.AppRuntimeErr)
@@ -68,6 +66,10 @@ func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flav
 		router.Handle(TagCertificateDeployEndpointPath,
 			ErrorHandler(permissionsHandler(JsonResponseHandler(tagCertificateController.Deploy),
 				[]string{constants.TagCertificateDeploy}))).Methods("POST")
+
+		router.Handle(TagCertificateRevokeEndpointPath,
+			ErrorHandler(permissionsHandler(JsonResponseHandler(tagCertificateController.Revoke),
+				[]string{constants.TagCertificateRevoke}))).Methods("POST")
 	}
 	return router
 }
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+)
+
+// SetTenantUsageRoutes registers routes for tenant-usage
+func SetTenantUsageRoutes(router *mux.Router, store *postgres.DataStore, meter domain.UsageMeter) *mux.Router {
+	defaultLog.Trace("router/tenant_usage:SetTenantUsageRoutes() Entering")
+	defer defaultLog.Trace("router/tenant_usage:SetTenantUsageRoutes() Leaving")
+
+	tenantUsageController := controllers.TenantUsageController{
+		Store: postgres.NewTenantUsageStore(store),
+		Meter: meter,
+	}
+
+	router.Handle("/tenant-usage",
+		ErrorHandler(permissionsHandler(ResponseHandler(tenantUsageController.Export),
+			[]string{constants.TenantUsageSearch}))).Methods("GET").Headers("Accept", consts.HTTPMediaTypeCsv)
+	router.Handle("/tenant-usage",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tenantUsageController.Search),
+			[]string{constants.TenantUsageSearch}))).Methods("GET")
+
+	return router
+}
+
+// NewUsageMeterHandler returns a middleware counting the calls to the routes with the identity and the tenant of the
+// user that made them, it must follow the token authentication
+func NewUsageMeterHandler(meter domain.UsageMeter) mux.MiddlewareFunc {
+	defaultLog.Trace("router/tenant_usage:NewUsageMeterHandler() Entering")
+	defer defaultLog.Trace("router/tenant_usage:NewUsageMeterHandler() Leaving")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, _ := comctx.GetTokenSubject(r)
+			// the calls of users with roles of several tenants are rejected by the controllers, they are counted
+			// under the empty tenant
+			tenantId, _ := utils.GetTenantId(r)
+			meter.RecordAPICall(tenantId, identity)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/usage"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageMeterHandler(t *testing.T) {
+	store := mocks.NewFakeTenantUsageStore()
+	meter := usage.NewMeter(store, mocks.NewMockHostStore(), time.Minute)
+
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = comctx.SetTokenSubject(r, r.Header.Get("X-Subject"))
+			if r.Header.Get("X-Subject") == "alice" {
+				r = comctx.SetUserRoles(r, []aas.RoleInfo{{Service: "HVS", Name: "HostManager", Context: "tenant=tenant-a"}})
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Use(NewUsageMeterHandler(meter))
+	router.HandleFunc("/hosts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, subject := range []string{"alice", "alice", "admin"} {
+		req := httptest.NewRequest("GET", "/hosts", nil)
+		req.Header.Set("X-Subject", subject)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.NoError(t, meter.Flush())
+
+	assert.Len(t, store.Usage, 2)
+	calls := map[string]int64{}
+	for _, u := range store.Usage {
+		calls[u.TenantId+"/"+u.Identity] = u.ApiCalls
+	}
+	assert.Equal(t, map[string]int64{"tenant-a/alice": 2, "/admin": 1}, calls)
+
+	// the counts are added to the persisted usage with each flush
+	req := httptest.NewRequest("GET", "/hosts", nil)
+	req.Header.Set("X-Subject", "admin")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.NoError(t, meter.Flush())
+	assert.Len(t, store.Usage, 2)
+}
@@ -0,0 +1,31 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+)
+
+// SetVerifyManifestRoute registers the route for the API verifying captured host manifests against flavors
+func SetVerifyManifestRoute(router *mux.Router, store *postgres.DataStore, certStore *models.CertificatesStore) *mux.Router {
+	defaultLog.Trace("router/verify_manifest:SetVerifyManifestRoute() Entering")
+	defer defaultLog.Trace("router/verify_manifest:SetVerifyManifestRoute() Leaving")
+
+	verifyManifestController := controllers.VerifyManifestController{
+		FlavorStore: postgres.NewFlavorStore(store),
+		CertStore:   certStore,
+	}
+
+	router.Handle("/rpc/verify-manifest",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(verifyManifestController.VerifyManifest),
+			[]string{constants.FlavorVerify}))).Methods("POST")
+
+	return router
+}
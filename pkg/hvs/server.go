@@ -20,15 +20,22 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/intel-secl/intel-secl/v3/pkg/clients"
+	taclient "github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/auditlog"
 	hostfetcher "github.com/intel-secl/intel-secl/v3/pkg/hvs/services/host-fetcher"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hprs"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hrrs"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/usage"
+	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	hostconnector "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
+	hcConstants "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/saml"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
 
@@ -36,6 +43,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/tasks"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
 
 	stdlog "log"
@@ -47,7 +55,7 @@ import (
 var defaultLog = commLog.GetDefaultLogger()
 var secLog = commLog.GetSecurityLogger()
 
-func (a *App) startServer() error {
+func (a *App) startServer(standalone bool) error {
 	defaultLog.Trace("app:startServer() Entering")
 	defer defaultLog.Trace("app:startServer() Leaving")
 
@@ -59,12 +67,50 @@ func (a *App) startServer() error {
 	if err := a.configureLogs(c.Log.EnableStdout, true); err != nil {
 		return err
 	}
+	crypt.SetFipsMode(c.FipsMode)
+	defaultLog.Infof("app:startServer() FIPS mode enabled: %t", crypt.FipsModeEnabled())
+
+	defaultLog.Infof("app:startServer() Event log replay is hashing with the %s implementation", crypt.GetHashImplementation())
+
+	// the service password sealed to the TPM at setup is unsealed once, the database password on every connection
+	servicePassword, err := crypt.ResolveSecret(c.HVS.Password)
+	if err != nil {
+		return errors.Wrap(err, "Failed to unseal the HVS service password")
+	}
+	c.HVS.Password = servicePassword
+
+	if err := c.FlavorMetadataSchema.Check(); err != nil {
+		return errors.Wrap(err, "Invalid flavor metadata schema in configuration")
+	}
+	if c.FVS.TrustScore != nil {
+		if err := c.FVS.TrustScore.Check(); err != nil {
+			return errors.Wrap(err, "Invalid trust score weights in configuration")
+		}
+	}
+
+	if standalone {
+		// the standalone mode keeps the data in a local SQLite database instead of the configured database server
+		defaultLog.Infof("app:startServer() Running standalone with the database %s", constants.StandaloneDBFile)
+		c.DB = commConfig.DBConfig{
+			Vendor:                  constants.DBTypeSQLite,
+			DBName:                  constants.StandaloneDBFile,
+			ConnectionRetryAttempts: constants.DefaultDbConnRetryAttempts,
+			ConnectionRetryTime:     constants.DefaultDbConnRetryTime,
+		}
+	}
 
 	// Initialize Database
 	dataStore, err := postgres.InitDatabase(&c.DB)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing Database")
 	}
+	if standalone {
+		// there is no setup of the standalone database, the default flavor groups are created on start
+		createDefaultFlavor := tasks.CreateDefaultFlavor{DBConfig: c.DB}
+		if err := createDefaultFlavor.Run(); err != nil {
+			return errors.Wrap(err, "An error occurred while creating the default flavor groups")
+		}
+	}
 
 	// Initialize audit log
 	als := postgres.NewAuditLogEntryStore(dataStore)
@@ -75,13 +121,34 @@ func (a *App) startServer() error {
 
 	// Initialize Host trust manager
 	fgs := postgres.NewFlavorGroupStore(dataStore)
-	hostTrustManager := initHostTrustManager(c, dataStore, fgs, certStore, alw)
+	latencyRecorder := hosttrust.NewAttestationLatency(c.FVS.AttestationLatencyBudget)
+	var quoteCallbacks *hostconnector.QuoteCallbacks
+	if c.FVS.AsyncQuoteCallbackURL != "" {
+		quoteCallbacks = hostconnector.NewQuoteCallbacks(c.FVS.AsyncQuoteCallbackURL, c.FVS.AsyncQuoteTimeout)
+	}
+	taRequestAuth, err := taclient.NewRequestAuth(c.HostConnector.CustomHeaders, c.HostConnector.AuthPlugin, c.HostConnector.AuthPluginConfig)
+	if err != nil {
+		return errors.Wrap(err, "Invalid host connector authentication in configuration")
+	}
+	connectorProxies, err := getConnectorProxies(c.HostConnector.Proxies)
+	if err != nil {
+		return errors.Wrap(err, "Invalid host connector proxies in configuration")
+	}
+	// Initialize usage metering, the report generations are counted by a report hook
+	usageMeter := usage.NewMeter(postgres.NewTenantUsageStore(dataStore), postgres.NewHostStore(dataStore), constants.DefaultUsageFlushPeriod)
+	hosttrust.RegisterReportHook(usageMeter)
+	err = usageMeter.Run()
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing Usage Meter")
+	}
+
+	hostTrustManager, hostFetcher := initHostTrustManager(c, dataStore, fgs, certStore, alw, latencyRecorder, quoteCallbacks, taRequestAuth, connectorProxies)
 	go hostTrustManager.ProcessQueue()
 
 	// create an instance of the HRRS and start it...
 	reportStore := postgres.NewReportStore(dataStore)
 	reportStore.AuditLogWriter = alw
-	reportRefresher, err := hrrs.NewHostReportRefresher(c.HRRS, reportStore, hostTrustManager)
+	reportRefresher, err := hrrs.NewHostReportRefresher(c.HRRS, reportStore, postgres.NewHostStore(dataStore), hostTrustManager)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing HRRS")
 	}
@@ -92,7 +159,7 @@ func (a *App) startServer() error {
 	}
 
 	// Initialize Host controller config
-	hostControllerConfig := initHostControllerConfig(c, certStore)
+	hostControllerConfig := initHostControllerConfig(c, certStore, taRequestAuth, connectorProxies)
 
 	//Create an instance of VCSS and start the service
 	vcenterClusterSyncer, err := vcss.NewVCenterClusterSyncer(c.VCSS, hostControllerConfig, dataStore, hostTrustManager)
@@ -105,19 +172,28 @@ func (a *App) startServer() error {
 		return errors.Wrap(err, "An error occurred while initializing vCenter Cluster Syncer")
 	}
 
+	//Create an instance of HPRS and start the service
+	hostRegistrationProber, err := hprs.NewHostRegistrationProber(c.HPRS, hostControllerConfig, dataStore, hostTrustManager)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing HPRS")
+	}
+
+	err = hostRegistrationProber.Run()
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing Host Registration Prober")
+	}
+
 	// Initialize routes
-	routes, err := router.InitRoutes(c, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	routes, err := router.InitRoutes(c, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, latencyRecorder, quoteCallbacks, usageMeter)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing routes")
 	}
 
 	defaultLog.Info("Starting server")
-	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	tlsConfig := crypt.TLSConfig()
+	if cmw.AuthMode(c.QuoteCallbackAuth.Mode).RequiresClientCert() {
+		// the client certificates are verified by the routes authenticating them
+		tlsConfig.ClientAuth = tls.RequestClientCert
 	}
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
@@ -133,6 +209,7 @@ func (a *App) startServer() error {
 		WriteTimeout:      c.Server.WriteTimeout,
 		IdleTimeout:       c.Server.IdleTimeout,
 		MaxHeaderBytes:    c.Server.MaxHeaderBytes,
+		ConnContext:       cmw.ConnContext,
 	}
 
 	tlsCert := c.TLS.CertFile
@@ -149,34 +226,107 @@ func (a *App) startServer() error {
 	// TODO dispatch Service status checker goroutine
 	<-stop
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// the requests, host data fetches and flavor verifications in progress are drained within the drain timeout, so
+	// that the reports being generated are not lost on a restart
+	drainTimeout := c.Server.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = constants.DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 
+	// no more background work is scheduled
 	err = reportRefresher.Stop()
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while stopping Report Refresher")
 	}
+	if err := vcenterClusterSyncer.Stop(); err != nil {
+		defaultLog.WithError(err).Error("Failed to stop vCenter Cluster Syncer")
+	}
+	if err := hostRegistrationProber.Stop(); err != nil {
+		defaultLog.WithError(err).Error("Failed to stop Host Registration Prober")
+	}
 
-	if err := h.Shutdown(ctx); err != nil {
-		defaultLog.WithError(err).Info("Failed to gracefully shutdown webserver")
-		return err
+	shutdownErr := h.Shutdown(ctx)
+	if shutdownErr != nil {
+		defaultLog.WithError(shutdownErr).Info("Failed to gracefully shutdown webserver")
+	}
+	// the host data fetches are drained before the verifications they feed
+	if hostFetcher != nil {
+		if err := hostFetcher.Shutdown(ctx); err != nil {
+			defaultLog.WithError(err).Error("Failed to drain the host data fetches")
+		}
+	}
+	if err := hostTrustManager.Shutdown(ctx); err != nil {
+		defaultLog.WithError(err).Error("Failed to drain the flavor verifications")
+	}
+
+	// the usage of the requests served until the shutdown is persisted
+	if err := usageMeter.Stop(); err != nil {
+		defaultLog.WithError(err).Error("Failed to persist usage on shutdown")
+	}
+	alw.Stop()
+	if shutdownErr != nil {
+		return shutdownErr
 	}
 	secLog.Info(commLogMsg.ServiceStop)
 	return nil
 }
 
-func initHostControllerConfig(cfg *config.Configuration, certStore *models.CertificatesStore) domain.HostControllerConfig {
+// getSshConfig returns the configuration of the connectors of the hosts reached over ssh
+func getSshConfig(cfg *config.Configuration) taclient.SshConfig {
+	sshConfig := taclient.SshConfig{
+		KnownHostsFile: cfg.HostConnector.SshKnownHostsFile,
+		Tagent:         cfg.HostConnector.SshTagent,
+	}
+	if sshConfig.KnownHostsFile == "" {
+		sshConfig.KnownHostsFile = constants.SshKnownHostsFile
+	}
+	return sshConfig
+}
+
+// getConnectorProxies returns the proxies of the host connectors by vendor, the hosts of the vendors without a proxy
+// configured are reached directly
+func getConnectorProxies(proxyConfigs map[string]commConfig.ProxyConfig) (map[hcConstants.Vendor]clients.ProxyFunc, error) {
+	defaultLog.Trace("server:getConnectorProxies() Entering")
+	defer defaultLog.Trace("server:getConnectorProxies() Leaving")
+
+	proxies := make(map[hcConstants.Vendor]clients.ProxyFunc)
+	for vendorName, proxyConfig := range proxyConfigs {
+		var vendor hcConstants.Vendor
+		if err := vendor.GetVendorFromOSName(vendorName); err != nil {
+			return nil, errors.Wrapf(err, "Unknown vendor %s of the host connector proxy", vendorName)
+		}
+		proxy, err := clients.NewProxyFunc(proxyConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid proxy of the %s host connector", vendorName)
+		}
+		if proxy != nil {
+			proxies[vendor] = proxy
+		}
+	}
+	return proxies, nil
+}
+
+func initHostControllerConfig(cfg *config.Configuration, certStore *models.CertificatesStore, taRequestAuth *taclient.RequestAuth, connectorProxies map[hcConstants.Vendor]clients.ProxyFunc) domain.HostControllerConfig {
 	defaultLog.Trace("server:initHostControllerConfig() Entering")
 	defer defaultLog.Trace("server:initHostControllerConfig() Leaving")
 
 	rootCAs := (*certStore)[models.CaCertTypesRootCa.String()]
 	hcProvider := hostconnector.NewHostConnectorFactory(cfg.AASApiUrl, rootCAs.Certificates)
+	hcProvider.SetRequestAuth(taRequestAuth)
+	hcProvider.SetSshConfig(getSshConfig(cfg))
+	hcProvider.SetBmcCredentials(cfg.HostConnector.BmcUsername, cfg.HostConnector.BmcPassword)
+	hcProvider.SetCallTimeout(cfg.HostConnector.CallTimeout)
+	hcProvider.SetProxies(connectorProxies)
 
 	hcc := domain.HostControllerConfig{
 		HostConnectorProvider: hcProvider,
 		DataEncryptionKey:     getDecodedDek(cfg),
 		Username:              cfg.HVS.Username,
 		Password:              cfg.HVS.Password,
+
+		HardwareUuidCollisionPolicy: cfg.HardwareUuidCollisionPolicy,
 	}
 	return hcc
 }
@@ -193,7 +343,18 @@ func getDecodedDek(cfg *config.Configuration) []byte {
 	return dek
 }
 
-func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, alw domain.AuditLogWriter) domain.HostTrustManager {
+// getQuoteRequesterIdentity returns the SHA384 digest of the TLS certificate of HVS, the quote nonces are bound to it
+// so that the quotes requested by this HVS can't be relayed to another one
+func getQuoteRequesterIdentity(cfg *config.Configuration) string {
+	identity, err := crypt.GetCertHexSha384(cfg.TLS.CertFile)
+	if err != nil {
+		defaultLog.WithError(err).Warn("Unable to read the TLS certificate, quote nonces are not bound to this HVS")
+		return ""
+	}
+	return identity
+}
+
+func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, alw domain.AuditLogWriter, latencyRecorder domain.AttestationLatencyRecorder, quoteCallbacks *hostconnector.QuoteCallbacks, taRequestAuth *taclient.RequestAuth, connectorProxies map[hcConstants.Vendor]clients.ProxyFunc) (*hosttrust.Service, *hostfetcher.Service) {
 	defaultLog.Trace("server:InitHostTrustManager() Entering")
 	defer defaultLog.Trace("server:InitHostTrustManager() Leaving")
 
@@ -209,30 +370,54 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 
 	//Load certificates
 	rootCAs := (*certStore)[models.CaCertTypesRootCa.String()]
-	tagCAs := (*certStore)[models.CaCertTypesTagCa.String()]
 	samlCert := (*certStore)[models.CertTypesSaml.String()]
-	privacyCAs := (*certStore)[models.CaCertTypesPrivacyCa.String()]
-	signingCerts := (*certStore)[models.CertTypesFlavorSigning.String()]
-	rootCApool := crypt.GetCertPool(rootCAs.Certificates)
-	for _, val := range signingCerts.Certificates[1:] {
-		rootCApool.AddCert(&val) //Add intermediate CA
-	}
 
-	verifierCerts := verifier.VerifierCertificates{
-		PrivacyCACertificates:    crypt.GetCertPool(privacyCAs.Certificates),
-		AssetTagCACertificates:   crypt.GetCertPool(tagCAs.Certificates),
-		FlavorSigningCertificate: &signingCerts.Certificates[0],
-		FlavorCACertificates:     rootCApool,
+	quoteRequester := getQuoteRequesterIdentity(cfg)
+	verifierCerts := utils.GetVerifierCertificates(certStore)
+	verifierCerts.FlavorSignatureQuorum = cfg.FVS.FlavorSignatureQuorum
+	verifierCerts.QuoteRequesterIdentity = quoteRequester
+	verifierCerts.MaxQuoteAge = cfg.FVS.MaxQuoteAge
+	verifierCerts.ClockSkewThreshold = cfg.FVS.ClockSkewThreshold
+	if cfg.FVS.RevocationCheck {
+		revocationClient := &http.Client{
+			Timeout:   time.Duration(30) * time.Second,
+			Transport: &http.Transport{TLSClientConfig: crypt.TLSConfig()},
+		}
+		verifierCerts.RevocationChecker = verifier.NewRevocationChecker(revocationClient, cfg.FVS.CrlCacheTime, cfg.FVS.OcspCheck)
+	}
+	// the asset tags of the revoked tag certificates are not trusted anymore
+	verifierCerts.TagCertificateRevocationChecker = utils.NewTagCertificateRevocationChecker(postgres.NewTagCertificateStore(dataStore))
+	libVerifier, err := verifier.NewVerifier(verifierCerts)
+	if err != nil {
+		defaultLog.WithError(err).Fatal("Error initializing the flavor verifier")
+	}
+	if cfg.FVS.DecisionLogFile != "" {
+		// the decision log stays open for the lifetime of the service
+		decisionLogFile, err := os.OpenFile(cfg.FVS.DecisionLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+		if err != nil {
+			defaultLog.WithError(err).Fatal("Error opening the decision log file")
+		}
+		libVerifier = verifier.NewDecisionLogger(libVerifier, decisionLogFile)
 	}
-	libVerifier, _ := verifier.NewVerifier(verifierCerts)
-	samlKey := samlCert.Key.(*rsa.PrivateKey)
 	samlIssuerConfig := saml.IssuerConfiguration{
 		IssuerName:        cfg.SAML.Issuer,
 		IssuerServiceName: constants.ServiceName,
 		ValiditySeconds:   cfg.SAML.ValiditySeconds,
-		PrivateKey:        samlKey,
 		Certificate:       &samlCert.Certificates[0],
 	}
+	if cfg.SAML.KeyURI != "" {
+		// the report signing key stays in the PKCS#11 token or the TPM, only the certificate is read from disk
+		samlSigner, err := crypt.GetSigner(cfg.SAML.KeyURI)
+		if err != nil {
+			defaultLog.WithError(err).Fatal("Error loading SAML signing key")
+		}
+		if samlPublicKey, ok := samlSigner.Public().(*rsa.PublicKey); !ok || !samlPublicKey.Equal(samlCert.Certificates[0].PublicKey) {
+			defaultLog.Fatal("SAML signing key does not match the SAML certificate")
+		}
+		samlIssuerConfig.Signer = samlSigner
+	} else {
+		samlIssuerConfig.PrivateKey = samlCert.Key.(*rsa.PrivateKey)
+	}
 
 	hostQuoteTrustCache, err := lru.New(cfg.FVS.HostTrustCacheThreshold)
 	if err != nil {
@@ -248,10 +433,22 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 		SamlIssuerConfig:                samlIssuerConfig,
 		SkipFlavorSignatureVerification: cfg.FVS.SkipFlavorSignatureVerification,
 		HostTrustCache:                  hostQuoteTrustCache,
+		LatencyRecorder:                 latencyRecorder,
+		ReportHooks:                     hosttrust.RegisteredReportHooks(),
+		TrustScoreWeights:               cfg.FVS.TrustScore,
 	}
 
 	// Initialize Host Fetcher service
 	htcFactory := hostconnector.NewHostConnectorFactory(cfg.AASApiUrl, rootCAs.Certificates)
+	if quoteCallbacks != nil {
+		htcFactory.SetQuoteCallbacks(quoteCallbacks)
+	}
+	htcFactory.SetRequestAuth(taRequestAuth)
+	htcFactory.SetQuoteRequester(quoteRequester)
+	htcFactory.SetSshConfig(getSshConfig(cfg))
+	htcFactory.SetBmcCredentials(cfg.HostConnector.BmcUsername, cfg.HostConnector.BmcPassword)
+	htcFactory.SetCallTimeout(cfg.HostConnector.CallTimeout)
+	htcFactory.SetProxies(connectorProxies)
 
 	c := domain.HostDataFetcherConfig{
 		HostConnectorProvider: htcFactory,
@@ -267,21 +464,22 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 		FlavorStore:      fs,
 		HostTrustCache:   hostQuoteTrustCache,
 	}
-	_, hf, err := hostfetcher.NewService(c, cfg.FVS.NumberOfDataFetchers)
+	hfs, hf, err := hostfetcher.NewService(c, cfg.FVS.NumberOfDataFetchers)
 	if err != nil {
 		defaultLog.WithError(err).Error("Error initializing host fetcher")
 	}
 	// Initialize Host Trust service
-	_, htm, _ := hosttrust.NewService(domain.HostTrustMgrConfig{
+	hts, _, _ := hosttrust.NewService(domain.HostTrustMgrConfig{
 		PersistStore:      qs,
 		HostStore:         hs,
 		HostStatusStore:   hss,
 		HostFetcher:       hf,
 		Verifiers:         cfg.FVS.NumberOfVerifiers,
 		HostTrustVerifier: hosttrust.NewVerifier(htv),
+		VisibilityTimeout: cfg.FVS.QueueVisibilityTimeout,
 	})
 
-	return htm
+	return hts, hfs
 }
 
 func (a *App) loadCertPathStore() *models.CertificatesPathStore {
@@ -302,6 +500,14 @@ func (a *App) loadCertPathStore() *models.CertificatesPathStore {
 			KeyFile:  constants.PrivacyCAKeyFile,
 			CertPath: constants.PrivacyCACertFile,
 		},
+		models.CaCertTypesPlatformCa.String(): models.CertLocation{
+			KeyFile:  "",
+			CertPath: constants.PlatformCACertDir,
+		},
+		models.CaCertTypesRimCa.String(): models.CertLocation{
+			KeyFile:  "",
+			CertPath: constants.RimCACertDir,
+		},
 		models.CaCertTypesTagCa.String(): models.CertLocation{
 			KeyFile:  constants.TagCAKeyFile,
 			CertPath: constants.TagCACertFile,
@@ -318,5 +524,9 @@ func (a *App) loadCertPathStore() *models.CertificatesPathStore {
 			KeyFile:  constants.FlavorSigningKeyFile,
 			CertPath: constants.FlavorSigningCertFile,
 		},
+		models.CertTypesFlavorCoSigning.String(): models.CertLocation{
+			KeyFile:  "",
+			CertPath: constants.FlavorCoSigningCertsDir,
+		},
 	}
 }
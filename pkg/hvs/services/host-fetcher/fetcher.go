@@ -65,6 +65,7 @@ type Service struct {
 	wg sync.WaitGroup
 
 	quit              chan struct{}
+	abort             chan struct{}
 	serviceDone       bool
 	retryIntervalMins int
 	hcCfg             domain.HostConnectionConfig
@@ -84,6 +85,7 @@ func NewService(cfg domain.HostDataFetcherConfig, workers int) (*Service, domain
 	// this way, go routine can start work as soon as a current work is done
 	svc := &Service{workMap: syncmap.Map{},
 		quit:              make(chan struct{}),
+		abort:             make(chan struct{}),
 		hcf:               cfg.HostConnectorProvider,
 		retryIntervalMins: cfg.RetryTimeMinutes,
 		hss:               cfg.HostStatusStore,
@@ -118,17 +120,29 @@ func NewService(cfg domain.HostDataFetcherConfig, workers int) (*Service, domain
 	return svc, svc.Fetcher, nil
 }
 
-// Function to Shutdown service. Will wait for pending host data fetch jobs to complete
-// Will not process any further requests. Calling interface Async methods will result in error
-func (svc *Service) Shutdown() error {
+// Function to Shutdown service. Will wait for pending host data fetch jobs to complete until ctx is done, the
+// connector calls still in flight then are cancelled. Will not process any further requests. Calling interface
+// Async methods will result in error
+func (svc *Service) Shutdown(ctx context.Context) error {
 	defaultLog.Trace("hostfetcher/Service:Shutdown() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:Shutdown() Leaving")
 
 	svc.serviceDone = true
 	close(svc.quit)
-	svc.wg.Wait()
 
-	return nil
+	done := make(chan struct{})
+	go func() {
+		svc.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		close(svc.abort)
+		<-done
+		return errors.Wrap(ctx.Err(), "Host data fetches cancelled before completion")
+	}
 }
 
 func (svc *Service) startRetryChannelProcessor(retryMins int) {
@@ -221,10 +235,11 @@ func (svc *Service) doWork() {
 			// iterate through work requests for this host. Usually, there will only be a single element in the
 			// work list.
 			var preferHashMatch bool
+			var frs []*fetchRequest
 			getData := false
 			workEntry, ok := svc.workMap.Load(hId)
 			if ok {
-				frs := workEntry.([]*fetchRequest)
+				frs = workEntry.([]*fetchRequest)
 				connUrl = frs[0].host.ConnectionString
 				preferHashMatch = frs[0].preferHashMatch
 				for i, req := range frs {
@@ -242,7 +257,9 @@ func (svc *Service) doWork() {
 			}
 
 			if getData {
-				svc.FetchDataAndRespond(hId, connUrl, preferHashMatch)
+				ctx, cancel := svc.fetchContext(frs)
+				svc.FetchDataAndRespond(ctx, hId, connUrl, preferHashMatch)
+				cancel()
 			} else {
 				defaultLog.Info("Fetch data for ", hId, "cancelled")
 			}
@@ -250,12 +267,32 @@ func (svc *Service) doWork() {
 	}
 }
 
-func (svc *Service) Retrieve(host hvs.Host) (*types.HostManifest, error) {
+// fetchContext returns the context of a host data fetch, it is done when all the requests the data is fetched for
+// are done or when the shutdown of the service runs out of time
+func (svc *Service) fetchContext(frs []*fetchRequest) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for _, fr := range frs {
+			select {
+			case <-fr.ctx.Done():
+			case <-svc.abort:
+				cancel()
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+func (svc *Service) Retrieve(ctx context.Context, host hvs.Host) (*types.HostManifest, error) {
 	defaultLog.Trace("hostfetcher/Service:Retrieve() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:Retrieve() Leaving")
 
 	trustPcrList := svc.getTrustPcrListFromCache(host.Id)
-	hostData, err := svc.GetHostData(host.ConnectionString, trustPcrList)
+	hostData, err := svc.GetHostData(ctx, host.Id, host.ConnectionString, trustPcrList)
 	hostStatus := &hvs.HostStatus{
 		HostID:                host.Id,
 		HostStatusInformation: hvs.HostStatusInformation{},
@@ -267,6 +304,7 @@ func (svc *Service) Retrieve(host hvs.Host) (*types.HostManifest, error) {
 		if err := svc.hss.Persist(hostStatus); err != nil {
 			defaultLog.Error("hostfetcher/Service:Retrieve() could not update host status to store")
 		}
+		svc.updateLifecycleState(host.Id, hvs.HostLifecycleStateConnectionFailed, hostState.String())
 		return nil, err
 	}
 
@@ -277,6 +315,7 @@ func (svc *Service) Retrieve(host hvs.Host) (*types.HostManifest, error) {
 	if err := svc.hss.Persist(hostStatus); err != nil {
 		defaultLog.Error("hostfetcher/Service:Retrieve() could not update host status and manifest to store")
 	}
+	svc.updateLifecycleState(host.Id, hvs.HostLifecycleStateConnected, "")
 
 	return hostData, nil
 }
@@ -294,14 +333,14 @@ func (svc *Service) RetrieveAsync(ctx context.Context, host hvs.Host, preferHash
 	return nil
 }
 
-func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHashMatch bool) {
+func (svc *Service) FetchDataAndRespond(ctx context.Context, hId uuid.UUID, connUrl string, preferHashMatch bool) {
 	defaultLog.Trace("hostfetcher/Service:FetchDataAndRespond() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:FetchDataAndRespond() Leaving")
 
 	defaultLog.Debugf("hostfetcher/fetcher:FetchDataAndRespond()  start for host - %s", hId.String())
 
 	trustPcrList := svc.getTrustPcrListFromCache(hId)
-	hostData, err := svc.GetHostData(connUrl, trustPcrList)
+	hostData, err := svc.GetHostData(ctx, hId, connUrl, trustPcrList)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("hostfetcher/Service:FetchDataAndRespond() Failed to get data for host %s", hId.String())
 		// we have an error. Make sure that the host still exists.
@@ -326,9 +365,13 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 			return
 		}
 		//TODO - presume that error is due to connection failure and we need to retry operation
-		svc.retryRqstChan <- retryRequest{
+		select {
+		case svc.retryRqstChan <- retryRequest{
 			retryTime: time.Now().Add(time.Duration(svc.retryIntervalMins) * time.Minute),
 			hostId:    hId,
+		}:
+		case <-svc.quit:
+			// no retries are scheduled once the service is shutting down
 		}
 		hostState := utils.DetermineHostState(err)
 		defaultLog.Warnf("hostfetcher/Service:FetchDataAndRespond() Could not connect to host : %s", hostState.String())
@@ -342,6 +385,7 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 		if err != nil {
 			defaultLog.WithError(err).Errorf("could not persist host status for host %s", hId.String())
 		}
+		svc.updateLifecycleState(hId, hvs.HostLifecycleStateConnectionFailed, hostState.String())
 		return
 	}
 
@@ -359,12 +403,14 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 		HostStatusInformation: hvs.HostStatusInformation{
 			HostState:         hvs.HostStateConnected,
 			LastTimeConnected: time.Now(),
+			ClockSkew:         hostData.ClockSkew,
 		},
 		HostManifest: *hostData,
 	})
 	if err != nil {
 		defaultLog.WithError(err).Errorf("could not persist host status for host %s", hId.String())
 	}
+	svc.updateLifecycleState(hId, hvs.HostLifecycleStateConnected, "")
 
 	for _, fr := range frs {
 		select {
@@ -383,6 +429,13 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 
 }
 
+// updateLifecycleState moves the host to the lifecycle state, a failure is logged as the host status has been persisted
+func (svc *Service) updateLifecycleState(hostId uuid.UUID, state hvs.HostLifecycleState, reason string) {
+	if err := svc.hss.UpdateLifecycleState(hostId, state, reason); err != nil {
+		defaultLog.WithError(err).Errorf("hostfetcher/Service:updateLifecycleState() could not update lifecycle state of host %s", hostId.String())
+	}
+}
+
 func (svc *Service) getTrustPcrListFromCache(hId uuid.UUID) []int {
 	defaultLog.Trace("hostfetcher/Service:getTrustPcrListFromCache() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:getTrustPcrListFromCache() Leaving")
@@ -400,7 +453,7 @@ func (svc *Service) getTrustPcrListFromCache(hId uuid.UUID) []int {
 	return trustPcrList
 }
 
-func (svc *Service) GetHostData(connUrl string, pcrList []int) (*types.HostManifest, error) {
+func (svc *Service) GetHostData(ctx context.Context, hostId uuid.UUID, connUrl string, pcrList []int) (*types.HostManifest, error) {
 	defaultLog.Trace("hostfetcher/Service:GetHostData() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:GetHostData() Leaving")
 
@@ -420,10 +473,61 @@ func (svc *Service) GetHostData(connUrl string, pcrList []int) (*types.HostManif
 		return nil, err
 	}
 
-	data, err := connector.GetHostManifest(pcrList)
+	// the quote nonce is bound to the host record, the quote is only accepted for this host
+	if binder, ok := connector.(hc.QuoteNonceBinder); ok {
+		binder.BindQuoteNonce(hostId.String())
+	}
+
+	selector, isSelector := connector.(hc.PCRBankSelector)
+	bundleProvider, isBundleProvider := connector.(hc.AttestationBundleProvider)
+	var capabilities *taModel.HostCapabilities
+	if isSelector || isBundleProvider {
+		capabilities = svc.getHostCapabilities(ctx, hostId, connector)
+	}
+
+	// the quote is restricted to the PCR banks supported by the host
+	if isSelector && capabilities != nil {
+		selector.SelectPCRBanks(capabilities)
+	}
+
+	// the hosts collecting attestation bundles report the quote, the logs and the host info in a single request, so
+	// that the PCRs cannot change between the requests
+	if isBundleProvider && capabilities != nil && capabilities.AttestationBundle {
+		data, err := bundleProvider.GetAttestationBundle(ctx, pcrList)
+		return &data, err
+	}
+
+	data, err := connector.GetHostManifest(ctx, pcrList)
 	return &data, err
 }
 
+// getHostCapabilities returns the capabilities cached in the host record, the capabilities of the hosts that have
+// not been reached before are discovered and cached. It returns nil when the capabilities cannot be discovered.
+func (svc *Service) getHostCapabilities(ctx context.Context, hostId uuid.UUID, connector hc.HostConnector) *taModel.HostCapabilities {
+	defaultLog.Trace("hostfetcher/Service:getHostCapabilities() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:getHostCapabilities() Leaving")
+
+	host, err := svc.hs.Retrieve(hostId, nil)
+	if err != nil {
+		defaultLog.WithError(err).Debugf("hostfetcher/Service:getHostCapabilities() Could not retrieve host %s", hostId.String())
+		return nil
+	}
+	if host.Capabilities != nil {
+		return host.Capabilities
+	}
+
+	capabilities, err := connector.Capabilities(ctx)
+	if err != nil {
+		defaultLog.WithError(err).Debugf("hostfetcher/Service:getHostCapabilities() Could not discover the capabilities of host %s", hostId.String())
+		return nil
+	}
+	host.Capabilities = &capabilities
+	if err := svc.hs.Update(host); err != nil {
+		defaultLog.WithError(err).Errorf("hostfetcher/Service:getHostCapabilities() Could not cache the capabilities of host %s", hostId.String())
+	}
+	return host.Capabilities
+}
+
 func (svc *Service) updateMissingHostDetails(hostId uuid.UUID, manifest *types.HostManifest) {
 	defaultLog.Trace("hostfetcher/Service:updateMissingHostDetails() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:updateMissingHostDetails() Leaving")
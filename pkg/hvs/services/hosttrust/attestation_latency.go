@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hosttrust
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// attestation stages, as reported in the attestation latency metrics
+const (
+	stageQueueWait      = "queue_wait"
+	stageQuoteRetrieval = "quote_retrieval"
+	stageFlavorMatch    = "flavor_match"
+	stageRuleEvaluation = "rule_evaluation"
+	stageSigning        = "signing"
+	stagePersistence    = "persistence"
+	stageTotal          = "total"
+)
+
+// AttestationLatency aggregates the stage timings of the reports created by the host trust verifier in memory.
+// Reports whose end to end latency exceeds the latency budget are logged with their per stage breakdown.
+type AttestationLatency struct {
+	mtx              sync.Mutex
+	budget           time.Duration
+	reportCount      int64
+	overBudgetCount  int64
+	stages           map[string]hvs.AttestationLatencyStage
+	overBudgetStages map[string]hvs.AttestationLatencyStage
+}
+
+// NewAttestationLatency creates an AttestationLatency with the given latency budget, a budget of zero disables it
+func NewAttestationLatency(budget time.Duration) *AttestationLatency {
+	return &AttestationLatency{
+		budget:           budget,
+		stages:           make(map[string]hvs.AttestationLatencyStage),
+		overBudgetStages: make(map[string]hvs.AttestationLatencyStage),
+	}
+}
+
+func stageTimingsByName(timings *hvs.ReportStageTimings) map[string]int64 {
+	return map[string]int64{
+		stageQueueWait:      timings.QueueWait,
+		stageQuoteRetrieval: timings.QuoteRetrieval,
+		stageFlavorMatch:    timings.FlavorMatch,
+		stageRuleEvaluation: timings.RuleEvaluation,
+		stageSigning:        timings.Signing,
+		stagePersistence:    timings.Persistence,
+		stageTotal:          timings.Total,
+	}
+}
+
+func addStageTiming(stages map[string]hvs.AttestationLatencyStage, name string, ms, count int64) {
+	stage := stages[name]
+	stage.TotalMs += ms
+	stage.AverageMs = stage.TotalMs / count
+	stage.LastMs = ms
+	if ms > stage.MaxMs {
+		stage.MaxMs = ms
+	}
+	stages[name] = stage
+}
+
+// Record adds the stage timings of a report to the metrics
+func (al *AttestationLatency) Record(hostId uuid.UUID, timings *hvs.ReportStageTimings) {
+	defaultLog.Trace("hosttrust/attestation_latency:Record() Entering")
+	defer defaultLog.Trace("hosttrust/attestation_latency:Record() Leaving")
+
+	if timings == nil {
+		return
+	}
+
+	al.mtx.Lock()
+	defer al.mtx.Unlock()
+
+	al.reportCount++
+	overBudget := al.budget > 0 && timings.Total > al.budget.Milliseconds()
+	if overBudget {
+		al.overBudgetCount++
+	}
+	for name, ms := range stageTimingsByName(timings) {
+		addStageTiming(al.stages, name, ms, al.reportCount)
+		if overBudget {
+			addStageTiming(al.overBudgetStages, name, ms, al.overBudgetCount)
+		}
+	}
+
+	if overBudget {
+		defaultLog.Warnf("hosttrust/attestation_latency:Record() Attestation of host %s took %dms, exceeding the latency budget of %dms "+
+			"| queue wait: %dms, quote retrieval: %dms, flavor match: %dms, rule evaluation: %dms, signing: %dms, persistence: %dms",
+			hostId, timings.Total, al.budget.Milliseconds(), timings.QueueWait, timings.QuoteRetrieval, timings.FlavorMatch,
+			timings.RuleEvaluation, timings.Signing, timings.Persistence)
+	}
+}
+
+// Metrics returns a snapshot of the aggregated stage timings
+func (al *AttestationLatency) Metrics() hvs.AttestationLatencyMetrics {
+	al.mtx.Lock()
+	defer al.mtx.Unlock()
+
+	metrics := hvs.AttestationLatencyMetrics{
+		ReportCount:     al.reportCount,
+		BudgetMs:        al.budget.Milliseconds(),
+		OverBudgetCount: al.overBudgetCount,
+		Stages:          make(map[string]hvs.AttestationLatencyStage, len(al.stages)),
+	}
+	for name, stage := range al.stages {
+		metrics.Stages[name] = stage
+	}
+	if al.overBudgetCount > 0 {
+		metrics.OverBudgetStages = make(map[string]hvs.AttestationLatencyStage, len(al.overBudgetStages))
+		for name, stage := range al.overBudgetStages {
+			metrics.OverBudgetStages[name] = stage
+		}
+	}
+	return metrics
+}
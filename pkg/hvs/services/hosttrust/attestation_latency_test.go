@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hosttrust_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttestationLatencyRecord(t *testing.T) {
+	al := hosttrust.NewAttestationLatency(100 * time.Millisecond)
+
+	al.Record(uuid.New(), &hvs.ReportStageTimings{QueueWait: 10, QuoteRetrieval: 20, FlavorMatch: 5, RuleEvaluation: 5, Signing: 5, Persistence: 5, Total: 50})
+	al.Record(uuid.New(), &hvs.ReportStageTimings{QueueWait: 10, QuoteRetrieval: 120, FlavorMatch: 5, RuleEvaluation: 5, Signing: 5, Persistence: 5, Total: 150})
+	al.Record(uuid.New(), nil)
+
+	metrics := al.Metrics()
+	assert.Equal(t, int64(2), metrics.ReportCount)
+	assert.Equal(t, int64(100), metrics.BudgetMs)
+	assert.Equal(t, int64(1), metrics.OverBudgetCount)
+	assert.Equal(t, hvs.AttestationLatencyStage{TotalMs: 140, AverageMs: 70, MaxMs: 120, LastMs: 120}, metrics.Stages["quote_retrieval"])
+	assert.Equal(t, int64(100), metrics.Stages["total"].AverageMs)
+	assert.Equal(t, int64(120), metrics.OverBudgetStages["quote_retrieval"].AverageMs)
+}
+
+func TestAttestationLatencyNoBudget(t *testing.T) {
+	al := hosttrust.NewAttestationLatency(0)
+
+	al.Record(uuid.New(), &hvs.ReportStageTimings{QuoteRetrieval: 5000, Total: 5000})
+
+	metrics := al.Metrics()
+	assert.Equal(t, int64(1), metrics.ReportCount)
+	assert.Equal(t, int64(0), metrics.OverBudgetCount)
+	assert.Nil(t, metrics.OverBudgetStages)
+}
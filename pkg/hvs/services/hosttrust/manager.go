@@ -19,6 +19,7 @@ import (
 	"golang.org/x/sync/syncmap"
 	"strconv"
 	"sync"
+	"time"
 )
 
 var defaultLog = commLog.GetDefaultLogger()
@@ -60,6 +61,8 @@ type Service struct {
 	hostStore       domain.HostStore
 	verifier        domain.HostTrustVerifier
 	hostStatusStore domain.HostStatusStore
+	// time after which the in progress queue entries are queued again
+	visibilityTimeout time.Duration
 	// waitgroup used to wait for workers to finish up when signal for shutdown comes in
 	wg          sync.WaitGroup
 	quit        chan struct{}
@@ -71,12 +74,13 @@ func NewService(cfg domain.HostTrustMgrConfig) (*Service, domain.HostTrustManage
 	defer defaultLog.Trace("hosttrust/manager:NewService() Leaving")
 
 	svc := &Service{prstStor: cfg.PersistStore,
-		hdFetcher:       cfg.HostFetcher,
-		hostStore:       cfg.HostStore,
-		verifier:        cfg.HostTrustVerifier,
-		hostStatusStore: cfg.HostStatusStore,
-		quit:            make(chan struct{}),
-		hosts:           syncmap.Map{},
+		hdFetcher:         cfg.HostFetcher,
+		hostStore:         cfg.HostStore,
+		verifier:          cfg.HostTrustVerifier,
+		hostStatusStore:   cfg.HostStatusStore,
+		visibilityTimeout: cfg.VisibilityTimeout,
+		quit:              make(chan struct{}),
+		hosts:             syncmap.Map{},
 	}
 	var err error
 	nw := cfg.Verifiers
@@ -89,20 +93,34 @@ func NewService(cfg domain.HostTrustMgrConfig) (*Service, domain.HostTrustManage
 
 	// start go routines
 	svc.startWorkers(cfg.Verifiers)
+	if svc.visibilityTimeout > 0 {
+		svc.wg.Add(1)
+		go svc.requeueExpiredEntries()
+	}
 	return svc, svc, nil
 }
 
-// Function to Shutdown service. Will wait for pending host data fetch jobs to complete
+// Function to Shutdown service. Will wait for the flavor verifications in progress to complete until ctx is done,
+// the queued ones remain in the queue store and are processed on the next start.
 // Will not process any further requests. Calling interface Async methods will result in error
-func (svc *Service) Shutdown() error {
+func (svc *Service) Shutdown(ctx context.Context) error {
 	defaultLog.Trace("hosttrust/manager:Shutdown() Entering")
 	defer defaultLog.Trace("hosttrust/manager:Shutdown() Leaving")
 
 	svc.serviceDone = true
 	close(svc.quit)
-	svc.wg.Wait()
 
-	return nil
+	done := make(chan struct{})
+	go func() {
+		svc.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "Flavor verifications still in progress")
+	}
 }
 
 func (svc *Service) startWorkers(workers int) {
@@ -117,9 +135,11 @@ func (svc *Service) startWorkers(workers int) {
 	}
 }
 
-func (svc *Service) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
+func (svc *Service) VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
 	var hostData *types.HostManifest
 
+	timings := &hvs.ReportStageTimings{}
+	quoteRetrievalStart := time.Now()
 	if fetchHostData {
 		var host *hvs.Host
 		host, err := svc.hostStore.Retrieve(hostId, nil)
@@ -127,7 +147,7 @@ func (svc *Service) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashM
 			return nil, errors.Wrap(err, "could not retrieve host id "+hostId.String())
 		}
 
-		hostData, err = svc.hdFetcher.Retrieve(hvs.Host{
+		hostData, err = svc.hdFetcher.Retrieve(ctx, hvs.Host{
 			Id:               host.Id,
 			ConnectionString: host.ConnectionString})
 	} else {
@@ -141,10 +161,17 @@ func (svc *Service) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashM
 
 		hostData = &hostStatusCollection[0].HostManifest
 	}
+	timings.QuoteRetrieval = elapsedMs(quoteRetrievalStart)
 	newData := fetchHostData
-	return svc.verifier.Verify(hostId, hostData, newData, preferHashMatch)
+	report, err := svc.verifier.Verify(hostId, hostData, newData, preferHashMatch, timings)
+	if err == nil {
+		svc.updateVerifiedLifecycleState(hostId, report)
+	}
+	return report, err
 }
 
+// ProcessQueue submits the entries of the queue store, it is called on startup to resume the flavor verifications
+// queued before the service was stopped. The entries that were in progress are queued again.
 func (svc *Service) ProcessQueue() error {
 	defaultLog.Trace("hosttrust/manager:ProcessQueue() Entering")
 	defer defaultLog.Trace("hosttrust/manager:ProcessQueue() Leaving")
@@ -153,45 +180,103 @@ func (svc *Service) ProcessQueue() error {
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while searching for records in queue")
 	}
+	return svc.resubmitQueueEntries(records)
+}
+
+// requeueExpiredEntries periodically queues again the flavor verifications that did not complete within the
+// visibility timeout, until the service is shutdown
+func (svc *Service) requeueExpiredEntries() {
+	defaultLog.Trace("hosttrust/manager:requeueExpiredEntries() Entering")
+	defer defaultLog.Trace("hosttrust/manager:requeueExpiredEntries() Leaving")
+
+	defer svc.wg.Done()
+	ticker := time.NewTicker(svc.visibilityTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-svc.quit:
+			return
+		case <-ticker.C:
+			if err := svc.requeueExpired(time.Now().Add(-svc.visibilityTimeout)); err != nil {
+				defaultLog.WithError(err).Error("hosttrust/manager:requeueExpiredEntries() Error queueing the expired entries again")
+			}
+		}
+	}
+}
+
+// requeueExpired queues again the in progress entries last updated before the time
+func (svc *Service) requeueExpired(updatedBefore time.Time) error {
+	defaultLog.Trace("hosttrust/manager:requeueExpired() Entering")
+	defer defaultLog.Trace("hosttrust/manager:requeueExpired() Leaving")
+
+	records, err := svc.prstStor.Search(&models.QueueFilterCriteria{
+		QueueStates:   []models.QueueState{models.QueueStateInProgress},
+		UpdatedBefore: updatedBefore,
+	})
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while searching for expired records in queue")
+	}
+	if len(records) > 0 {
+		defaultLog.Warnf("hosttrust/manager:requeueExpired() %d flavor verifications did not complete within %s, queueing them again",
+			len(records), svc.visibilityTimeout)
+	}
+	return svc.resubmitQueueEntries(records)
+}
+
+// resubmitQueueEntries moves the queue entries back to the pending state and submits them to the workers, the
+// jobs in progress for the same hosts are cancelled
+func (svc *Service) resubmitQueueEntries(records []*models.Queue) error {
+	defaultLog.Trace("hosttrust/manager:resubmitQueueEntries() Entering")
+	defer defaultLog.Trace("hosttrust/manager:resubmitQueueEntries() Leaving")
+
+	svc.syncMtx.Lock()
+	defer svc.syncMtx.Unlock()
+
+	if svc.serviceDone {
+		return errors.New("hosttrust/manager:resubmitQueueEntries() Service already shutdown")
+	}
 
 	verifyWithFetchDataHostIds := map[uuid.UUID]bool{}
 	verifyHostIds := map[uuid.UUID]bool{}
-	if len(records) > 0 {
-		for _, queue := range records {
-			if queue.Params != nil {
-				var hostId uuid.UUID
-				fetchHostData := false
-				preferHashMatch := false
-				for key, value := range queue.Params {
-					if key == "host_id" {
-						if _, ok := value.(string); ok {
-							hostId, err = uuid.Parse(value.(string))
-							if err != nil {
-								return errors.Wrap(err, "hosttrust/manager:ProcessQueue() - parsing hostid failed")
-							}
-						} else {
-							hostId = value.(uuid.UUID)
-						}
-					}
-					if key == "fetch_host_data" {
-						fetchHostData = value.(bool)
-					}
-					if key == "prefer_hash_match" {
-						preferHashMatch = value.(bool)
-					}
-				}
-				if fetchHostData {
-					verifyWithFetchDataHostIds[hostId] = preferHashMatch
-				} else {
-					verifyHostIds[hostId] = true
+	for _, queue := range records {
+		if queue.Params == nil {
+			continue
+		}
+		hostId, fetchHostData, preferHashMatch, err := parseQueueParams(queue.Params)
+		if err != nil {
+			return errors.Wrap(err, "hosttrust/manager:resubmitQueueEntries() - parsing hostid failed")
+		}
+
+		if vt, found := svc.hosts.Load(hostId); found {
+			vtj := vt.(*verifyTrustJob)
+			if vtj.storPersistId != queue.Id {
+				// the host has another job, the entry was left behind
+				defaultLog.Debugf("hosttrust/manager:resubmitQueueEntries() Deleting dangling queue entry %v for host %v", queue.Id, hostId)
+				if err := svc.prstStor.Delete(queue.Id); err != nil {
+					defaultLog.WithError(err).Errorf("hosttrust/manager:resubmitQueueEntries() Could not delete queue entry %v", queue.Id)
 				}
-				ctx, cancel := context.WithCancel(context.Background())
+				continue
+			}
+			vtj.cancelFn()
+		}
 
-				// the host field is not filled at this stage since it requires a trip to the host store
-				svc.hosts.Store(hostId, &verifyTrustJob{ctx, cancel, nil, queue.Id,
-					fetchHostData, preferHashMatch})
+		if queue.State == models.QueueStateInProgress {
+			queue.State = models.QueueStatePending
+			if err := svc.prstStor.Update(queue); err != nil {
+				defaultLog.WithError(err).Errorf("hosttrust/manager:resubmitQueueEntries() Could not update queue entry %v", queue.Id)
 			}
 		}
+
+		if fetchHostData {
+			verifyWithFetchDataHostIds[hostId] = preferHashMatch
+		} else {
+			verifyHostIds[hostId] = true
+		}
+		ctx, cancel := context.WithCancel(taskstage.NewTimerContext(context.Background()))
+
+		// the host field is not filled at this stage since it requires a trip to the host store
+		svc.hosts.Store(hostId, &verifyTrustJob{ctx, cancel, nil, queue.Id,
+			fetchHostData, preferHashMatch})
 	}
 
 	if len(verifyWithFetchDataHostIds) > 0 {
@@ -204,6 +289,32 @@ func (svc *Service) ProcessQueue() error {
 	return nil
 }
 
+// parseQueueParams returns the host id and the options of a flavor-verify queue entry
+func parseQueueParams(params map[string]interface{}) (uuid.UUID, bool, bool, error) {
+	var hostId uuid.UUID
+	var err error
+	fetchHostData := false
+	preferHashMatch := false
+	for key, value := range params {
+		switch key {
+		case "host_id":
+			if id, ok := value.(string); ok {
+				hostId, err = uuid.Parse(id)
+				if err != nil {
+					return uuid.Nil, false, false, err
+				}
+			} else if id, ok := value.(uuid.UUID); ok {
+				hostId = id
+			}
+		case "fetch_host_data":
+			fetchHostData, _ = value.(bool)
+		case "prefer_hash_match":
+			preferHashMatch, _ = value.(bool)
+		}
+	}
+	return hostId, fetchHostData, preferHashMatch, nil
+}
+
 func (svc *Service) VerifyHostsAsync(hostIds []uuid.UUID, fetchHostData, preferHashMatch bool) error {
 	defaultLog.Trace("hosttrust/manager:VerifyHostsAsync() Entering")
 	defer defaultLog.Trace("hosttrust/manager:VerifyHostsAsync() Leaving")
@@ -252,6 +363,12 @@ func (svc *Service) VerifyHostsAsync(hostIds []uuid.UUID, fetchHostData, preferH
 		return errors.Wrap(err, "hosttrust/manager:VerifyHostsAsync() persistRequest - error in Persisting to Store")
 	}
 
+	for _, hostsList := range []map[uuid.UUID]bool{adds, updates} {
+		for hid := range hostsList {
+			svc.updateLifecycleState(hid, hvs.HostLifecycleStateQueued, "")
+		}
+	}
+
 	// at this point, it is safe to return the async call as the records have been persisted.
 	if fetchHostData {
 		svc.wg.Add(1)
@@ -330,7 +447,7 @@ func (svc *Service) persistToStore(additions, updates map[uuid.UUID]bool, fetchH
 		if !htvJobExists {
 			defaultLog.Debugf("hosttrust/manager:persistToStore() Create for host %s ", hid.String())
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(taskstage.NewTimerContext(context.Background()))
 			if strRec, err = svc.prstStor.Create(strRec); err != nil {
 				defaultLog.Errorf("hosttrust/manager:persistToStore() Queue store persist failed for host %s - %s", hid.String(), err.Error())
 				cancel()
@@ -380,7 +497,7 @@ func (svc *Service) persistToStore(additions, updates map[uuid.UUID]bool, fetchH
 			}
 
 			// update work map
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(taskstage.NewTimerContext(context.Background()))
 			existingHTVJob.ctx = ctx
 			existingHTVJob.cancelFn = cancel
 			existingHTVJob.getNewHostData = fetchHostData
@@ -438,8 +555,10 @@ func (svc *Service) doWork() {
 					LatestPerHost: true,
 				})
 				if err != nil || len(hostStatusCollection) == 0 || hostStatusCollection[0].HostStatusInformation.HostState != hvs.HostStateConnected {
-					defaultLog.Errorf("hosttrust/manager:doWork() - could not retrieve host data from store for host - %s  | error: %s ", hostId.String(), err.Error())
-					return
+					defaultLog.WithError(err).Errorf("hosttrust/manager:doWork() - could not retrieve host data from store for host - %s", hId.String())
+					// the host can not be verified without its data, remove its queue entry instead of leaving it behind
+					svc.deleteEntry(hId)
+					continue
 				}
 				hostId = hId
 				hostData = &hostStatusCollection[0].HostManifest
@@ -485,15 +604,76 @@ func (svc *Service) verifyHostData(hostId uuid.UUID, data *types.HostManifest, n
 	default:
 		taskstage.StoreInContext(vtj.ctx, taskstage.FlavorVerifyStarted)
 	}
+	// the entry is queued again when the verification does not complete within the visibility timeout
+	svc.updateQueueState(vtj.storPersistId, models.QueueStateInProgress)
 
-	_, err := svc.verifier.Verify(hostId, data, newData, preferHashMatch)
+	report, err := svc.verifier.Verify(hostId, data, newData, preferHashMatch, jobStageTimings(vtj.ctx))
 	if err != nil {
 		defaultLog.WithError(err).Errorf("hosttrust/manager:verifyHostData() Error while verification: %s", hostId.String())
+	} else {
+		svc.updateVerifiedLifecycleState(hostId, report)
 	}
 	// verify is completed - delete the entry
 	svc.deleteEntry(hostId)
 }
 
+// updateQueueState moves the queue entry to the state, a failure is logged and does not fail the attestation
+func (svc *Service) updateQueueState(queueId uuid.UUID, state models.QueueState) {
+	svc.syncMtx.Lock()
+	defer svc.syncMtx.Unlock()
+
+	queue, err := svc.prstStor.Retrieve(queueId)
+	if err == nil {
+		queue.State = state
+		err = svc.prstStor.Update(queue)
+	}
+	if err != nil {
+		defaultLog.WithError(err).Errorf("hosttrust/manager:updateQueueState() Could not update queue entry %v", queueId)
+	}
+}
+
+// updateVerifiedLifecycleState moves the host to the lifecycle state matching the trust status of its report
+func (svc *Service) updateVerifiedLifecycleState(hostId uuid.UUID, report *models.HVSReport) {
+	if report == nil {
+		return
+	}
+	if report.TrustReport.Trusted {
+		svc.updateLifecycleState(hostId, hvs.HostLifecycleStateAttested, "")
+	} else {
+		svc.updateLifecycleState(hostId, hvs.HostLifecycleStateUntrusted, "one or more flavor parts are not trusted")
+	}
+}
+
+// updateLifecycleState moves the host to the lifecycle state, a failure is logged and does not fail the attestation
+func (svc *Service) updateLifecycleState(hostId uuid.UUID, state hvs.HostLifecycleState, reason string) {
+	if err := svc.hostStatusStore.UpdateLifecycleState(hostId, state, reason); err != nil {
+		defaultLog.WithError(err).Errorf("hosttrust/manager:updateLifecycleState() Could not update lifecycle state of host %s", hostId.String())
+	}
+}
+
+// jobStageTimings computes the time the job spent waiting in the queues and retrieving the host data
+// from the stage timer of the job context
+func jobStageTimings(ctx context.Context) *hvs.ReportStageTimings {
+	timings := &hvs.ReportStageTimings{}
+	t, ok := taskstage.TimerFromContext(ctx)
+	if !ok {
+		return timings
+	}
+	verifyStarted, ok := t.Entered(taskstage.FlavorVerifyStarted)
+	if !ok {
+		return timings
+	}
+	fetchStarted, fetchStartedOk := t.Entered(taskstage.GetHostDataStarted)
+	verifyQueued, verifyQueuedOk := t.Entered(taskstage.FlavorVerifyQueued)
+	if fetchStartedOk && verifyQueuedOk {
+		timings.QuoteRetrieval = verifyQueued.Sub(fetchStarted).Milliseconds()
+		timings.QueueWait = fetchStarted.Sub(t.Created()).Milliseconds() + verifyStarted.Sub(verifyQueued).Milliseconds()
+	} else {
+		timings.QueueWait = verifyStarted.Sub(t.Created()).Milliseconds()
+	}
+	return timings
+}
+
 // This function is the implementation of the HostDataReceiver interface method. Just create a new request
 // to process the newly obtained data and it will be submitted to the verification queue
 func (svc *Service) ProcessHostData(ctx context.Context, host hvs.Host, data *types.HostManifest, preferHashMatch bool, err error) error {
@@ -513,11 +693,15 @@ func (svc *Service) ProcessHostData(ctx context.Context, host hvs.Host, data *ty
 
 	// queue the new data to be processed by one of the worker threads by adding this to the queue
 	taskstage.StoreInContext(ctx, taskstage.FlavorVerifyQueued)
-	svc.hfRqstChan <- newHostFetch{
+	select {
+	case svc.hfRqstChan <- newHostFetch{
 		ctx:             ctx,
 		hostId:          host.Id,
 		data:            data,
 		preferHashMatch: preferHashMatch,
+	}:
+	case <-svc.quit:
+		// the queue entry is kept and the host is verified on the next start
 	}
 	return nil
 }
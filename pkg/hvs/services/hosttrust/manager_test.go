@@ -6,6 +6,7 @@
 package hosttrust_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
@@ -172,7 +173,7 @@ func TestHostTrustManagerNewService(t *testing.T) {
 
 func TestVerifier_Verify_UntrustedHost(t *testing.T) {
 	SetupManagerTests()
-	report, err := v.Verify(hostId, &hostManifest, false, false)
+	report, err := v.Verify(hostId, &hostManifest, false, false, nil)
 	assert.NoError(t, err)
 	fmt.Println(report.TrustReport.Trusted)
 	assert.Equal(t, report.TrustReport.Trusted, false)
@@ -201,7 +202,7 @@ func TestHostTrustManagerShutdown(t *testing.T) {
 	assert.NoError(t, ht.VerifyHostsAsync([]uuid.UUID{hwUuid}, true, false), "Async calls pre-shutdown should not return error")
 
 	// call shutdown signal
-	err = service.Shutdown()
+	err = service.Shutdown(context.Background())
 	assert.NoError(t, err)
 
 	// check if the service has been shutdown
@@ -211,13 +212,13 @@ func TestHostTrustManagerShutdown(t *testing.T) {
 func TestManager_VerifyHostSyncWithHostDataFetch(t *testing.T) {
 	SetupManagerTests()
 
-	_, err := service.VerifyHost(hostId, true, false)
+	_, err := service.VerifyHost(context.Background(), hostId, true, false)
 	assert.NoError(t, err, "VerifyHost should not return an error when HostData is fetched")
 }
 
 func TestManager_VerifyHostSyncWithoutHostDataFetch(t *testing.T) {
 	SetupManagerTests()
-	_, err := service.VerifyHost(hostId, false, false)
+	_, err := service.VerifyHost(context.Background(), hostId, false, false)
 	assert.Error(t, err, "VerifyHost should error out when the Host manifest is not present in HostStatus")
 }
 
@@ -259,11 +260,11 @@ func TestManager_VerifyNonExistentHost(t *testing.T) {
 
 	newId, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	_, err = service.VerifyHost(newId, true, false)
+	_, err = service.VerifyHost(context.Background(), newId, true, false)
 	assert.Error(t, err, "VerifyHost should error out when the Host does not exist")
 	newId, err = uuid.NewRandom()
 	assert.NoError(t, err)
-	_, err = service.VerifyHost(newId, false, false)
+	_, err = service.VerifyHost(context.Background(), newId, false, false)
 	assert.Error(t, err, "VerifyHost should error out when the Host does not exist")
 	newId, err = uuid.NewRandom()
 	assert.NoError(t, err)
@@ -272,3 +273,32 @@ func TestManager_VerifyNonExistentHost(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, service.VerifyHostsAsync([]uuid.UUID{newId}, false, false), "VerifyHostsAsync should error out when the Host does not exist")
 }
+
+func TestManager_RequeueExpiredEntries(t *testing.T) {
+	SetupManagerTests()
+
+	// a flavor verification left in progress, e.g. by a worker that never completed it
+	qrec, err := qs.Create(&models.Queue{Action: "flavor-verify",
+		Params: map[string]interface{}{"host_id": hostId, "fetch_host_data": false, "prefer_hash_match": false},
+		State:  models.QueueStateInProgress,
+	})
+	assert.NoError(t, err)
+
+	requeueService, _, err := hosttrust.NewService(domain.HostTrustMgrConfig{
+		PersistStore:      qs,
+		HostStore:         hs,
+		HostStatusStore:   hss,
+		HostFetcher:       f,
+		Verifiers:         1,
+		HostTrustVerifier: v,
+		VisibilityTimeout: 100 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	// the entry is queued again once the visibility timeout expires and removed once processed
+	assert.Eventually(t, func() bool {
+		_, err := qs.Retrieve(qrec.Id)
+		return err != nil
+	}, 5*time.Second, 50*time.Millisecond, "The expired queue entry should be processed")
+	assert.NoError(t, requeueService.Shutdown(context.Background()))
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
@@ -13,7 +14,7 @@ import (
 
 type MockHostTrustManager struct{}
 
-func (mock *MockHostTrustManager) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
+func (mock *MockHostTrustManager) VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
 	store := mocks.NewMockReportStore()
 	report, _ := store.Search(&models.ReportFilterCriteria{HostID: hostId})
 	return &report[0], nil
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hosttrust
+
+import (
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	reportHooksLock sync.RWMutex
+	reportHooks     []domain.ReportHook
+)
+
+// RegisterReportHook registers a hook invoked before and after the reports are persisted. It is meant to be called
+// from the init function of a package compiled into the HVS, the hooks registered once the verifier is created are
+// not invoked.
+func RegisterReportHook(hook domain.ReportHook) {
+	reportHooksLock.Lock()
+	defer reportHooksLock.Unlock()
+	reportHooks = append(reportHooks, hook)
+}
+
+// RegisteredReportHooks returns the hooks registered with RegisterReportHook in their order of registration
+func RegisteredReportHooks() []domain.ReportHook {
+	reportHooksLock.RLock()
+	defer reportHooksLock.RUnlock()
+	return append([]domain.ReportHook(nil), reportHooks...)
+}
+
+// runReportHooks invokes the hooks in turn, a hook that fails or panics does not prevent the others from running
+func runReportHooks(hooks []domain.ReportHook, report *models.HVSReport, before bool) {
+	for _, hook := range hooks {
+		if err := runReportHook(hook, report, before); err != nil {
+			defaultLog.WithError(err).Errorf("hosttrust/report_hooks:runReportHooks() Report hook failed for host %s", report.HostID)
+		}
+	}
+}
+
+func runReportHook(hook domain.ReportHook, report *models.HVSReport, before bool) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("report hook panicked: %v", r)
+		}
+	}()
+	if before {
+		return hook.BeforeReportPersisted(report)
+	}
+	return hook.AfterReportPersisted(report)
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hosttrust
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/saml"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testReportHook struct {
+	calls []string
+	err   error
+	panic bool
+}
+
+func (hook *testReportHook) BeforeReportPersisted(report *models.HVSReport) error {
+	hook.calls = append(hook.calls, "before")
+	if hook.panic {
+		panic("before hook panicked")
+	}
+	report.TrustReport.Trusted = false
+	return hook.err
+}
+
+func (hook *testReportHook) AfterReportPersisted(report *models.HVSReport) error {
+	hook.calls = append(hook.calls, "after")
+	return hook.err
+}
+
+func TestStoreTrustReportRunsHooks(t *testing.T) {
+	failing := &testReportHook{err: errors.New("export failed")}
+	panicking := &testReportHook{panic: true}
+	enriching := &testReportHook{}
+	v := &Verifier{
+		HostStore:   mocks.NewMockHostStore(),
+		ReportStore: mocks.NewMockReportStore(),
+		ReportHooks: []domain.ReportHook{failing, panicking, enriching},
+	}
+
+	report := v.storeTrustReport(uuid.New(), &hvs.TrustReport{Trusted: true}, &saml.SamlAssertion{}, &hvs.ReportStageTimings{})
+
+	// the hooks that fail do not prevent the others from running nor the report from being persisted
+	assert.Equal(t, []string{"before", "after"}, failing.calls)
+	assert.Equal(t, []string{"before", "after"}, panicking.calls)
+	assert.Equal(t, []string{"before", "after"}, enriching.calls)
+	assert.NotNil(t, report)
+	assert.False(t, report.TrustReport.Trusted)
+}
+
+func TestStoreTrustReportSuppressedByMaintenance(t *testing.T) {
+	hook := &testReportHook{}
+	hostStore := mocks.NewMockHostStore()
+	v := &Verifier{
+		HostStore:   hostStore,
+		ReportStore: mocks.NewMockReportStore(),
+		ReportHooks: []domain.ReportHook{hook},
+	}
+	hostId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+
+	report := v.storeTrustReport(hostId, &hvs.TrustReport{Trusted: true}, &saml.SamlAssertion{}, &hvs.ReportStageTimings{})
+	assert.Empty(t, report.TrustReport.Status)
+
+	// the reports of a host in maintenance are suppressed until its resume time
+	resumeAt := time.Now().Add(time.Hour)
+	assert.NoError(t, hostStore.SetMaintenance(hostId, &hvs.HostMaintenance{StartedAt: time.Now(), ResumeAt: &resumeAt}))
+	report = v.storeTrustReport(hostId, &hvs.TrustReport{Trusted: true}, &saml.SamlAssertion{}, &hvs.ReportStageTimings{})
+	assert.Equal(t, hvs.ReportStatusSuppressedMaintenance, report.TrustReport.Status)
+	assert.Equal(t, []string{"before", "after", "before", "after"}, hook.calls)
+
+	resumeAt = time.Now().Add(-time.Minute)
+	report = v.storeTrustReport(hostId, &hvs.TrustReport{Trusted: true}, &saml.SamlAssertion{}, &hvs.ReportStageTimings{})
+	assert.Empty(t, report.TrustReport.Status)
+}
+
+func TestRegisterReportHook(t *testing.T) {
+	hook := &testReportHook{}
+	RegisterReportHook(hook)
+	defer func() { reportHooks = nil }()
+
+	hooks := RegisteredReportHooks()
+	assert.Equal(t, []domain.ReportHook{hook}, hooks)
+}
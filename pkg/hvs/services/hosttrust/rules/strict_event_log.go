@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rules
+
+import (
+	"fmt"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// StrictEventLog is applied to the reports of the flavorgroups with strict event log verification. It faults
+// the events of the evaluated pcr event logs that have an unrecognized type or fields that cannot be parsed,
+// such events would otherwise not be reported unless they happen to differ from the flavor.
+type StrictEventLog struct {
+	HostManifest *types.HostManifest
+}
+
+func NewStrictEventLog(hostManifest *types.HostManifest) *StrictEventLog {
+	return &StrictEventLog{
+		HostManifest: hostManifest,
+	}
+}
+
+type strictEventLogPcr struct {
+	pcrBank  types.SHAAlgorithm
+	pcrIndex types.PcrIndex
+	marker   cf.FlavorPart
+}
+
+func (r *StrictEventLog) Apply(trustReport hvs.TrustReport) *hvs.TrustReport {
+
+	var faultedResults []hvs.RuleResult
+	checked := make(map[strictEventLogPcr]bool)
+	for _, result := range trustReport.Results {
+		bank, index, ok := getEventLogPcr(result.Rule)
+		if !ok {
+			continue
+		}
+
+		for _, marker := range result.Rule.Markers {
+			pcr := strictEventLogPcr{pcrBank: bank, pcrIndex: index, marker: marker}
+			if checked[pcr] {
+				continue
+			}
+			checked[pcr] = true
+
+			if faults := r.getFaults(bank, index); len(faults) > 0 {
+				defaultLog.Debugf("Event log of PCR %d in bank %s has %d events that cannot be evaluated", index, bank, len(faults))
+				faultedResults = append(faultedResults, hvs.RuleResult{
+					Rule: hvs.RuleInfo{
+						Name:    constants.RuleStrictEventLog,
+						Markers: []cf.FlavorPart{marker},
+					},
+					Faults: faults,
+				})
+			}
+		}
+	}
+
+	for _, result := range faultedResults {
+		trustReport.AddResult(result)
+	}
+	return &trustReport
+}
+
+func (r *StrictEventLog) getFaults(bank types.SHAAlgorithm, index types.PcrIndex) []hvs.Fault {
+	if r.HostManifest == nil {
+		return nil
+	}
+
+	eventLogEntry, err := r.HostManifest.PcrManifest.PcrEventLogMap.GetEventLog(bank, index)
+	if err != nil || eventLogEntry == nil {
+		// a missing event log is faulted by the event log rules
+		return nil
+	}
+
+	var faults []hvs.Fault
+	for _, eventLog := range eventLogEntry.EventLogs {
+		if err := eventLog.Validate(bank); err != nil {
+			pcrIndex := index
+			faults = append(faults, hvs.Fault{
+				Name: constants.FaultPcrEventLogUnrecognizedEntry,
+				Description: fmt.Sprintf("Event '%s' of PCR %d in bank %s cannot be evaluated: %s",
+					eventLog.Label, index, bank, err.Error()),
+				PcrIndex:          &pcrIndex,
+				UnexpectedEntries: []types.EventLog{eventLog},
+			})
+		}
+	}
+	return faults
+}
+
+// getEventLogPcr returns the pcr of the event log evaluated by an event log rule
+func getEventLogPcr(rule hvs.RuleInfo) (types.SHAAlgorithm, types.PcrIndex, bool) {
+	switch rule.Name {
+	case constants.RulePcrEventLogEquals,
+		constants.RulePcrEventLogEqualsExcluding,
+		constants.RulePcrEventLogIncludes,
+		constants.RulePcrEventLogIntegrity:
+		if rule.ExpectedEventLogEntry != nil {
+			return rule.ExpectedEventLogEntry.PcrBank, rule.ExpectedEventLogEntry.PcrIndex, true
+		}
+		if rule.ExpectedPcr != nil {
+			return rule.ExpectedPcr.PcrBank, rule.ExpectedPcr.Index, true
+		}
+	}
+	return "", 0, false
+}
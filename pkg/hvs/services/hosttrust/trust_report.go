@@ -94,7 +94,7 @@ func (v *Verifier) createTrustReport(hostId uuid.UUID, hostData *types.HostManif
 	if err != nil {
 		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error while creating host manifest map")
 	}
-	flavorsToVerify, err := v.findFlavors(reqs.FlavorGroupId, latestReqAndDefFlavorTypes, hostManifestMap)
+	flavorsToVerify, err := v.findFlavors(reqs.FlavorGroupId, reqs.InheritedFlavorgroups, latestReqAndDefFlavorTypes, hostManifestMap)
 	if err != nil {
 		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error while finding flavors")
 	}
@@ -165,7 +165,7 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 			flvPart := signedFlavor.Flavor.Meta.Description.FlavorPart
 			if flvPart == flvMatchPolicy.FlavorPart.String() {
 
-				individualTrustReport, err := v.FlavorVerifier.Verify(hostData, &signedFlavor, v.SkipFlavorSignatureVerification)
+				individualTrustReport, err := v.verifyFlavor(hostData, &signedFlavor)
 				if err != nil {
 					return &hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:verifyFlavors() Error verifying flavor")
 				}
@@ -180,7 +180,7 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 					// will need the fault count later on... just iterate through the results and determine the fault count
 					faults := 0
 					for _, result := range individualTrustReport.Results {
-						faults += len(result.Faults)
+						faults += result.FaultCount()
 						for _, fault := range result.Faults {
 							log.Debugf("Flavor [%s] did not match host [%s] due to fault: %s",
 								signedFlavor.Flavor.Meta.ID, hostID, fault.Name)
@@ -247,7 +247,7 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 
 // FlavorVerify.java: 684
 //TODO find flavors by required key value
-func (v *Verifier) findFlavors(flavorGroupID uuid.UUID, latestReqAndDefFlavorTypes map[cf.FlavorPart]bool, hostManifestMap map[cf.FlavorPart][]models.FlavorMetaKv) ([]hvs.SignedFlavor, error) {
+func (v *Verifier) findFlavors(flavorGroupID uuid.UUID, inheritedFlavorGroupIDs map[cf.FlavorPart]uuid.UUID, latestReqAndDefFlavorTypes map[cf.FlavorPart]bool, hostManifestMap map[cf.FlavorPart][]models.FlavorMetaKv) ([]hvs.SignedFlavor, error) {
 	defaultLog.Trace("hosttrust/trust_report:findFlavors() Entering")
 	defer defaultLog.Trace("hosttrust/trust_report:findFlavors() Leaving")
 
@@ -257,6 +257,7 @@ func (v *Verifier) findFlavors(flavorGroupID uuid.UUID, latestReqAndDefFlavorTyp
 		},
 		FlavorPartsWithLatest: latestReqAndDefFlavorTypes,
 		FlavorMeta:            hostManifestMap,
+		InheritedFlavorgroups: inheritedFlavorGroupIDs,
 	}
 
 	signedFlavors, err := v.FlavorStore.Search(&flvrFilterCriteria)
@@ -399,6 +400,9 @@ func getHostManifestMap(hostManifest *types.HostManifest, flavorParts []cf.Flavo
 					})
 				}
 				hostInfoValues[cf.FlavorPartSoftware] = sfQueryAttrs
+			} else if fp == cf.FlavorPartContainerImage {
+				// the container image flavors are not selected by the host info
+				hostInfoValues[cf.FlavorPartContainerImage] = nil
 			} else {
 				return nil, errors.New("Invalid flavor part - " + fp.String())
 			}
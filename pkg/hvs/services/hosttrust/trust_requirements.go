@@ -10,6 +10,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/rules"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
 	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	flavorVerifier "github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
@@ -27,12 +28,27 @@ type flvGrpHostTrustReqs struct {
 	DefinedAndRequiredFlavorTypes   map[cf.FlavorPart]bool
 	FlavorPartMatchPolicy           map[cf.FlavorPart]hvs.MatchPolicy
 	SkipFlavorSignatureVerification bool
+	StrictEventLogVerification      bool
+	// InheritedFlavorgroups maps the flavor parts without flavors in the flavorgroup to the nearest ancestor
+	// flavorgroup having flavors of the part
+	InheritedFlavorgroups map[cf.FlavorPart]uuid.UUID
 }
 
 func NewFlvGrpHostTrustReqs(hostId uuid.UUID, definedUniqueFlavorParts map[cf.FlavorPart]bool, fg hvs.FlavorGroup, fs domain.FlavorStore, fgs domain.FlavorGroupStore, hostData *types.HostManifest, SkipFlavorSignatureVerification bool) (*flvGrpHostTrustReqs, error) {
 	defaultLog.Trace("hosttrust/trust_requirements:NewFlvGrpHostTrustReqs() Entering")
 	defer defaultLog.Trace("hosttrust/trust_requirements:NewFlvGrpHostTrustReqs() Leaving")
 
+	// the flavorgroup is verified with the policies and flavors it inherits from its ancestors, a strict
+	// ancestor cannot be relaxed by its descendants
+	ancestors, err := utils.GetFlavorGroupAncestors(fgs, fg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving the ancestors of flavorgroup "+fg.ID.String())
+	}
+	fg.MatchPolicies = utils.InheritMatchPolicies(fg, ancestors)
+	for _, ancestor := range ancestors {
+		fg.StrictEventLogVerification = fg.StrictEventLogVerification || ancestor.StrictEventLogVerification
+	}
+
 	reqs := flvGrpHostTrustReqs{
 		HostId:              hostId,
 		FlavorGroupId:       fg.ID,
@@ -40,6 +56,30 @@ func NewFlvGrpHostTrustReqs(hostId uuid.UUID, definedUniqueFlavorParts map[cf.Fl
 		//Initialize empty map.
 		DefinedAndRequiredFlavorTypes:   make(map[cf.FlavorPart]bool),
 		SkipFlavorSignatureVerification: SkipFlavorSignatureVerification,
+		StrictEventLogVerification:      fg.StrictEventLogVerification,
+		InheritedFlavorgroups:           make(map[cf.FlavorPart]uuid.UUID),
+	}
+
+	// the flavor types of the flavorgroup include the ones it inherits
+	flavorPartsInFlavorGroup, err := fgs.GetFlavorTypesInFlavorGroup(fg.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error searching flavor types in flavorgroup ")
+	}
+	definedFlavorParts := make(map[cf.FlavorPart]bool)
+	for part := range flavorPartsInFlavorGroup {
+		definedFlavorParts[part] = true
+	}
+	for _, ancestor := range ancestors {
+		ancestorFlavorParts, err := fgs.GetFlavorTypesInFlavorGroup(ancestor.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "error searching flavor types in flavorgroup "+ancestor.ID.String())
+		}
+		for part := range ancestorFlavorParts {
+			if !definedFlavorParts[part] {
+				definedFlavorParts[part] = true
+				reqs.InheritedFlavorgroups[part] = ancestor.ID
+			}
+		}
 	}
 
 	var fgRequirePolicyMap map[hvs.FlavorRequiredPolicy][]cf.FlavorPart
@@ -50,7 +90,6 @@ func NewFlvGrpHostTrustReqs(hostId uuid.UUID, definedUniqueFlavorParts map[cf.Fl
 		// TODO: this should really be a search on the flavorgroup store which should be able to retrieve a list
 		// of flavor ids in the flavorgroup and then call the flavor store with a list of ids. Right now, it only
 		// support one flavor id
-		var err error
 		hostManifestMap, err := getHostManifestMap(hostData, reqs.MatchTypeFlavorParts[hvs.MatchTypeAllOf])
 		if err != nil {
 			return nil, errors.Wrap(err, "error while creating host manifest map")
@@ -64,6 +103,7 @@ func NewFlvGrpHostTrustReqs(hostId uuid.UUID, definedUniqueFlavorParts map[cf.Fl
 			},
 			FlavorMeta:            hostManifestMap,
 			FlavorPartsWithLatest: nil,
+			InheritedFlavorgroups: reqs.InheritedFlavorgroups,
 		})
 		if err != nil {
 			return nil, errors.Wrap(err, "error searching flavor for host id "+hostId.String())
@@ -79,15 +119,10 @@ func NewFlvGrpHostTrustReqs(hostId uuid.UUID, definedUniqueFlavorParts map[cf.Fl
 		reqs.DefinedAndRequiredFlavorTypes[part] = true
 	}
 
-	// now add defined if required flavor parts
-	flavorPartsInFlavorGroup, err := fgs.GetFlavorTypesInFlavorGroup(fg.ID)
-	if err != nil {
-		return nil, errors.Wrap(err, "error searching flavor types in flavorgroup ")
-	}
-	// since the above query returns all the different flavors types in the flavorgroup, filter only
-	// those flavor parts that are required if defined
+	// now add defined if required flavor parts, filter only those flavor parts of the flavorgroup that are
+	// required if defined
 	for _, part := range reqIfdefPartsMap {
-		if _, exists := flavorPartsInFlavorGroup[part]; exists {
+		if _, exists := definedFlavorParts[part]; exists {
 			reqs.DefinedAndRequiredFlavorTypes[part] = true
 		}
 	}
@@ -10,6 +10,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hosttrust/rules"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/utils"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
@@ -19,10 +20,12 @@ import (
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"time"
 )
 
 var ErrInvalidHostManiFest = errors.New("invalid host data")
 var ErrManifestMissingHwUUID = errors.New("host data missing hardware uuid")
+var ErrManifestOfAnotherHost = errors.New("host data was requested for another host")
 
 type Verifier struct {
 	FlavorStore                     domain.FlavorStore
@@ -35,6 +38,9 @@ type Verifier struct {
 	SkipFlavorSignatureVerification bool
 	hostQuoteReportCache            map[uuid.UUID]*models.QuoteReportCache
 	HostTrustCache                  *lru.Cache
+	LatencyRecorder                 domain.AttestationLatencyRecorder
+	ReportHooks                     []domain.ReportHook
+	TrustScoreWeights               *hvs.TrustScoreWeights
 }
 
 func NewVerifier(cfg domain.HostTrustVerifierConfig) domain.HostTrustVerifier {
@@ -48,6 +54,9 @@ func NewVerifier(cfg domain.HostTrustVerifierConfig) domain.HostTrustVerifier {
 		SamlIssuer:                      cfg.SamlIssuerConfig,
 		SkipFlavorSignatureVerification: cfg.SkipFlavorSignatureVerification,
 		HostTrustCache:                  cfg.HostTrustCache,
+		LatencyRecorder:                 cfg.LatencyRecorder,
+		ReportHooks:                     cfg.ReportHooks,
+		TrustScoreWeights:               cfg.TrustScoreWeights,
 		hostQuoteReportCache:            make(map[uuid.UUID]*models.QuoteReportCache),
 	}
 }
@@ -74,7 +83,10 @@ func getTrustPcrListReport(hostInfo taModel.HostInfo, report *hvs.TrustReport) [
 	return trustPcrList
 }
 
-func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newData bool, preferHashMatch bool) (*models.HVSReport, error) {
+// Verify creates the trust report of the host from the host manifest. The time spent matching flavors, evaluating rules,
+// signing and persisting the report is added to the stage timings, which hold the time spent in the earlier stages of
+// the attestation, if any.
+func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newData bool, preferHashMatch bool, timings *hvs.ReportStageTimings) (*models.HVSReport, error) {
 	defaultLog.Trace("hosttrust/verifier:Verify() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:Verify() Leaving")
 
@@ -90,6 +102,12 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 		return nil, ErrManifestMissingHwUUID
 	}
 
+	// the quote nonce binding is checked against the host record the quote was requested for
+	if hostData.HostId != "" && hostData.HostId != hostId.String() {
+		defaultLog.Errorf("hosttrust/verifier:Verify() host - %s, %s", hostId.String(), ErrManifestOfAnotherHost)
+		return nil, ErrManifestOfAnotherHost
+	}
+
 	// check if the data has not changed
 	if preferHashMatch {
 		cacheEntry, ok := v.HostTrustCache.Get(hostId)
@@ -99,7 +117,7 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 			if cachedQuote.QuoteDigest != "" && hostData.QuoteDigest == cachedQuote.QuoteDigest {
 				// retrieve the stored report
 				log.Debugf("hosttrust/verifier:Verify() Quote values matches cached value for host %s - skipping flavor verification", hostId.String())
-				if report, err := v.refreshTrustReport(hostId, cachedQuote); err == nil {
+				if report, err := v.refreshTrustReport(hostId, cachedQuote, timings); err == nil {
 					return report, err
 				} else {
 					// log warning message here - continue as normal and create a report from newly fetched data
@@ -108,6 +126,10 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 			}
 		}
 	}
+	if timings == nil {
+		timings = &hvs.ReportStageTimings{}
+	}
+	stageStart := time.Now()
 	// TODO : remove this when we remove the intermediate collection
 	flvGroupIds, err := v.HostStore.SearchFlavorgroups(hostId)
 	flvGroups, err := v.FlavorGroupStore.Search(&models.FlavorGroupFilterCriteria{Ids: flvGroupIds})
@@ -136,7 +158,10 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 		hostUniqueFlavorPartsMap[common.FlavorPart(flavorPart)] = true
 	}
 
+	timings.FlavorMatch += elapsedMs(stageStart)
+
 	for _, fg := range flvGroups {
+		stageStart = time.Now()
 		//TODO - handle errors in case of DB transaction
 		fgTrustReqs, err := NewFlvGrpHostTrustReqs(hostId, hostUniqueFlavorPartsMap, fg, v.FlavorStore, v.FlavorGroupStore, hostData, v.SkipFlavorSignatureVerification)
 		if err != nil {
@@ -146,6 +171,14 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 		if err != nil {
 			return nil, errors.Wrap(err, "hosttrust/verifier:Verify() Error while retrieving getCachedFlavors")
 		}
+		inheritedCachedFlavors, err := v.getInheritedCachedFlavors(hostId, fgTrustReqs.InheritedFlavorgroups)
+		if err != nil {
+			return nil, errors.Wrap(err, "hosttrust/verifier:Verify() Error while retrieving getInheritedCachedFlavors")
+		}
+		fgCachedFlavors = append(fgCachedFlavors, inheritedCachedFlavors...)
+		timings.FlavorMatch += elapsedMs(stageStart)
+
+		stageStart = time.Now()
 
 		var fgTrustCache hostTrustCache
 		if len(fgCachedFlavors) > 0 {
@@ -164,9 +197,21 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 				return nil, errors.Wrap(err, "hosttrust/verifier:Verify() Error while creating flavorgroup report")
 			}
 		}
+		if fgTrustReqs.StrictEventLogVerification {
+			strictTrustReport := rules.NewStrictEventLog(hostData).Apply(fgTrustReport)
+			if len(strictTrustReport.Results) != len(fgTrustReport.Results) {
+				log.Debugf("hosttrust/verifier:Verify() Event logs of host %s have events that cannot be evaluated for strict flavorgroup %s", hostId, fg.ID)
+				finalReportValid = false
+			}
+			fgTrustReport = *strictTrustReport
+		}
 		log.Debug("hosttrust/verifier:Verify() Trust status for host id ", hostId, " for flavorgroup ", fg.ID, " is ", fgTrustReport.IsTrusted())
 		// append the results
 		finalTrustReport.AddResults(fgTrustReport.Results)
+		for _, profile := range fg.ComplianceProfiles {
+			finalTrustReport.ComplianceResults = append(finalTrustReport.ComplianceResults, profile.Evaluate(fg, &fgTrustReport))
+		}
+		timings.RuleEvaluation += elapsedMs(stageStart)
 	}
 	// create a new report if we actually have any results and either the Final Report is untrusted or
 	// we have new Data from the host and therefore need to update based on the new report.
@@ -174,8 +219,10 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 	log.Debugf("hosttrust/verifier:Verify() Final results in report: %d", len(finalTrustReport.Results))
 	if len(finalTrustReport.Results) > 0 && (!finalReportValid || newData) {
 		log.Debugf("hosttrust/verifier:Verify() Generating new SAML for host: %s", hostId)
+		stageStart = time.Now()
 		samlReportGen := NewSamlReportGenerator(&v.SamlIssuer)
 		samlReport := samlReportGen.GenerateSamlReport(&finalTrustReport)
+		timings.Signing = elapsedMs(stageStart)
 		finalTrustReport.Trusted = finalTrustReport.IsTrusted()
 		log.Debugf("hosttrust/verifier:Verify() Saving new report for host: %s", hostId)
 		// new report - save it to the cache
@@ -187,7 +234,7 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *types.HostManifest, newDat
 			TrustReport:  &finalTrustReport,
 		}
 		v.HostTrustCache.Add(hostId, newCacheEntry)
-		hvsReport = v.storeTrustReport(hostId, &finalTrustReport, &samlReport)
+		hvsReport = v.storeTrustReport(hostId, &finalTrustReport, &samlReport, timings)
 	}
 	if hvsReport == nil {
 		log.Infof("hosttrust/verifier:Verify() Unable to generate report for the host : %v as no rules found to be applied", hostId)
@@ -212,6 +259,32 @@ func (v *Verifier) getCachedFlavors(hostId uuid.UUID, flavGrpId uuid.UUID) ([]hv
 	}
 }
 
+// getInheritedCachedFlavors returns the cached flavors of the ancestor flavorgroups, only for the flavor parts
+// inherited from them
+func (v *Verifier) getInheritedCachedFlavors(hostId uuid.UUID, inheritedFlavorgroups map[common.FlavorPart]uuid.UUID) ([]hvs.SignedFlavor, error) {
+	defaultLog.Trace("hosttrust/verifier:getInheritedCachedFlavors() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:getInheritedCachedFlavors() Leaving")
+
+	var result []hvs.SignedFlavor
+	ancestorIds := make(map[uuid.UUID]bool)
+	for _, ancestorId := range inheritedFlavorgroups {
+		ancestorIds[ancestorId] = true
+	}
+	for ancestorId := range ancestorIds {
+		cachedFlavors, err := v.getCachedFlavors(hostId, ancestorId)
+		if err != nil {
+			return nil, err
+		}
+		for _, cachedFlavor := range cachedFlavors {
+			flavorPart := common.FlavorPart(cachedFlavor.Flavor.Meta.Description.FlavorPart)
+			if inheritedFlavorgroups[flavorPart] == ancestorId {
+				result = append(result, cachedFlavor)
+			}
+		}
+	}
+	return result, nil
+}
+
 func (v *Verifier) validateCachedFlavors(hostId uuid.UUID,
 	hostData *types.HostManifest,
 	cachedFlavors []hvs.SignedFlavor) (hostTrustCache, error) {
@@ -225,7 +298,7 @@ func (v *Verifier) validateCachedFlavors(hostId uuid.UUID,
 	var trustCachesToDelete []uuid.UUID
 	for _, cachedFlavor := range cachedFlavors {
 		//TODO: change the signature verification depending on decision on signed flavors
-		report, err := v.FlavorVerifier.Verify(hostData, &cachedFlavor, v.SkipFlavorSignatureVerification)
+		report, err := v.verifyFlavor(hostData, &cachedFlavor)
 		if err != nil {
 			return hostTrustCache{}, errors.Wrap(err, "hosttrust/verifier:validateCachedFlavors() Error from flavor verifier")
 		}
@@ -247,17 +320,40 @@ func (v *Verifier) validateCachedFlavors(hostId uuid.UUID,
 	return htc, nil
 }
 
-func (v *Verifier) refreshTrustReport(hostID uuid.UUID, cache *models.QuoteReportCache) (*models.HVSReport, error) {
+// verifyFlavor verifies the host manifest against the signed flavor. A delta flavor is verified
+// merged with the base flavor it references.
+func (v *Verifier) verifyFlavor(hostData *types.HostManifest, signedFlavor *hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/verifier:verifyFlavor() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:verifyFlavor() Leaving")
+
+	if !signedFlavor.Flavor.IsDelta() {
+		return v.FlavorVerifier.Verify(hostData, signedFlavor, v.SkipFlavorSignatureVerification)
+	}
+
+	baseFlavor, err := v.FlavorStore.Retrieve(*signedFlavor.Flavor.Meta.BaseFlavorID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "hosttrust/verifier:verifyFlavor() Error retrieving base flavor %s of delta flavor %s",
+			signedFlavor.Flavor.Meta.BaseFlavorID, signedFlavor.Flavor.Meta.ID)
+	}
+	return v.FlavorVerifier.VerifyDelta(hostData, baseFlavor, signedFlavor, v.SkipFlavorSignatureVerification)
+}
+
+func (v *Verifier) refreshTrustReport(hostID uuid.UUID, cache *models.QuoteReportCache, timings *hvs.ReportStageTimings) (*models.HVSReport, error) {
 	defaultLog.Trace("hosttrust/verifier:refreshTrustReport() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:refreshTrustReport() Leaving")
 	log.Debugf("hosttrust/verifier:refreshTrustReport() Generating SAML for host: %s using existing trust report", hostID)
 
+	if timings == nil {
+		timings = &hvs.ReportStageTimings{}
+	}
+	stageStart := time.Now()
 	samlReportGen := NewSamlReportGenerator(&v.SamlIssuer)
 	samlReport := samlReportGen.GenerateSamlReport(cache.TrustReport)
-	return v.storeTrustReport(hostID, cache.TrustReport, &samlReport), nil
+	timings.Signing = elapsedMs(stageStart)
+	return v.storeTrustReport(hostID, cache.TrustReport, &samlReport, timings), nil
 }
 
-func (v *Verifier) storeTrustReport(hostID uuid.UUID, trustReport *hvs.TrustReport, samlReport *saml.SamlAssertion) *models.HVSReport {
+func (v *Verifier) storeTrustReport(hostID uuid.UUID, trustReport *hvs.TrustReport, samlReport *saml.SamlAssertion, timings *hvs.ReportStageTimings) *models.HVSReport {
 	defaultLog.Trace("hosttrust/verifier:storeTrustReport() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:storeTrustReport() Leaving")
 
@@ -269,9 +365,47 @@ func (v *Verifier) storeTrustReport(hostID uuid.UUID, trustReport *hvs.TrustRepo
 		Expiration:  samlReport.ExpiryTime,
 		Saml:        samlReport.Assertion,
 	}
+	if v.TrustScoreWeights != nil {
+		trustScore := v.TrustScoreWeights.Score(trustReport)
+		hvsReport.TrustReport.TrustScore = &trustScore
+	}
+	if v.inMaintenance(hostID) {
+		hvsReport.TrustReport.Status = hvs.ReportStatusSuppressedMaintenance
+	}
+	runReportHooks(v.ReportHooks, &hvsReport, true)
+	stageStart := time.Now()
 	report, err := v.ReportStore.Update(&hvsReport)
 	if err != nil {
 		log.WithError(err).Errorf("hosttrust/verifier:storeTrustReport() Failed to store Report")
+		return report
 	}
+	timings.Persistence = elapsedMs(stageStart)
+	timings.Total = timings.QueueWait + timings.QuoteRetrieval + timings.FlavorMatch + timings.RuleEvaluation +
+		timings.Signing + timings.Persistence
+
+	// the timings are stored once the report is persisted so that they include the persistence stage
+	report.TrustReport.StageTimings = timings
+	if err := v.ReportStore.UpdateStageTimings(report.ID, timings); err != nil {
+		log.WithError(err).Errorf("hosttrust/verifier:storeTrustReport() Failed to store stage timings of report %s", report.ID)
+	}
+	if v.LatencyRecorder != nil {
+		v.LatencyRecorder.Record(hostID, timings)
+	}
+	runReportHooks(v.ReportHooks, report, false)
 	return report
 }
+
+// inMaintenance tells whether the host is in maintenance mode, a host whose maintenance cannot be retrieved is
+// considered out of maintenance so that its report is not suppressed
+func (v *Verifier) inMaintenance(hostID uuid.UUID) bool {
+	host, err := v.HostStore.Retrieve(hostID, nil)
+	if err != nil {
+		log.WithError(err).Errorf("hosttrust/verifier:inMaintenance() Failed to retrieve host %s", hostID)
+		return false
+	}
+	return host.Maintenance.Active(time.Now())
+}
+
+func elapsedMs(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
@@ -0,0 +1,132 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hprs
+
+import (
+	"context"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/config"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/postgres"
+	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// HostRegistrationProber runs in the background and periodically connects to the hosts that were pre-registered
+// with HVS. When the trust agent of a host comes online, the registration of the host is completed and the host is
+// added to the flavor-verify queue.
+
+type HostRegistrationProber interface {
+	Run() error
+	Stop() error
+}
+
+var (
+	defaultLog = commLog.GetDefaultLogger()
+)
+
+func NewHostRegistrationProber(cfg config.HPRSConfig, hcConfig domain.HostControllerConfig,
+	dataStore *postgres.DataStore, hostTrustManager domain.HostTrustManager) (HostRegistrationProber, error) {
+	defaultLog.Trace("hprs/host_registration_prober:NewHostRegistrationProber() Entering")
+	defer defaultLog.Trace("hprs/host_registration_prober:NewHostRegistrationProber() Leaving")
+
+	hostStore := postgres.NewHostStore(dataStore)
+	hostStatusStore := postgres.NewHostStatusStore(dataStore)
+
+	flavorStore := postgres.NewFlavorStore(dataStore)
+	flavorGroupStore := postgres.NewFlavorGroupStore(dataStore)
+	hostCredentialStore := postgres.NewHostCredentialStore(dataStore, hcConfig.DataEncryptionKey)
+
+	hostController := controllers.NewHostController(hostStore, hostStatusStore,
+		flavorStore, flavorGroupStore, hostCredentialStore,
+		hostTrustManager, hcConfig)
+
+	return &hostRegistrationProberImpl{
+		hostController: hostController,
+		cfg:            cfg,
+	}, nil
+}
+
+type hostRegistrationProberImpl struct {
+	hostController *controllers.HostController
+	cfg            config.HPRSConfig
+	cancel         context.CancelFunc
+}
+
+func (prober *hostRegistrationProberImpl) Run() error {
+	defaultLog.Trace("hprs/host_registration_prober:Run() Entering")
+	defer defaultLog.Trace("hprs/host_registration_prober:Run() Leaving")
+
+	defaultLog.Infof("hprs/host_registration_prober:Run() HPRS is starting with probe period '%s'", prober.cfg.ProbePeriod)
+
+	if prober.cfg.ProbePeriod == 0 {
+		defaultLog.Info("hprs/host_registration_prober:Run() The HPRS probe period is 0 mins. HPRS will now exit")
+		return nil
+	}
+
+	var ctx context.Context
+	ctx, prober.cancel = context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			err := prober.probeHosts(ctx)
+			if err != nil {
+				defaultLog.Errorf("hprs/host_registration_prober:Run() HPRS encountered an error while probing hosts...\n%+v\n", err)
+			}
+			select {
+			case <-time.After(prober.cfg.ProbePeriod):
+			case <-ctx.Done():
+				defaultLog.Info("hprs/host_registration_prober:Run() The HPRS has been stopped and will now exit")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (prober *hostRegistrationProberImpl) Stop() error {
+	defaultLog.Trace("hprs/host_registration_prober:Stop() Entering")
+	defer defaultLog.Trace("hprs/host_registration_prober:Stop() Leaving")
+
+	if prober.cancel != nil {
+		prober.cancel()
+	} else {
+		defaultLog.Debug("hprs/host_registration_prober:Stop() HPRS is not running")
+	}
+	return nil
+}
+
+// probeHosts tries to complete the registration of all the pre-registered hosts, a host that cannot be registered
+// does not prevent the registration of the others
+func (prober *hostRegistrationProberImpl) probeHosts(ctx context.Context) error {
+	defaultLog.Trace("hprs/host_registration_prober:probeHosts() Entering")
+	defer defaultLog.Trace("hprs/host_registration_prober:probeHosts() Leaving")
+
+	hosts, err := prober.hostController.HStore.Search(&models.HostFilterCriteria{
+		Lifecycle: hvs.HostLifecyclePreRegistered,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "hprs/host_registration_prober:probeHosts() Error searching for pre-registered hosts in DB")
+	}
+
+	if len(hosts) > 0 {
+		defaultLog.Debugf("hprs/host_registration_prober:probeHosts() Probing %d pre-registered host(s) ...", len(hosts))
+	}
+	for _, host := range hosts {
+		registered, err := prober.hostController.CompleteHostRegistration(ctx, host)
+		if err != nil {
+			defaultLog.WithError(err).Errorf("hprs/host_registration_prober:probeHosts() Error completing the "+
+				"registration of host with host name %s", host.HostName)
+		} else if registered {
+			defaultLog.Infof("hprs/host_registration_prober:probeHosts() Host with name %s is online, its "+
+				"registration has been completed", host.HostName)
+		}
+	}
+	return nil
+}
@@ -17,7 +17,9 @@ import (
 
 // HostReportRefresher runs in the background and periodically queries HVS'
 // reports to see if they have been expired.  If so, they are passed to
-// the HostTrustManager queue to be updated.
+// the HostTrustManager queue to be updated.  The hosts in maintenance mode
+// are not refreshed, they are queued when their maintenance resume time has
+// passed.
 type HostReportRefresher interface {
 	Run() error
 	Stop() error
@@ -27,10 +29,11 @@ var (
 	firstFromTime, _ = time.Parse(time.RFC3339, "1970-01-01T00:00:00Z") // i.e. epoch
 )
 
-func NewHostReportRefresher(cfg HRRSConfig, reportStore domain.ReportStore, hostTrustManager domain.HostTrustManager) (HostReportRefresher, error) {
+func NewHostReportRefresher(cfg HRRSConfig, reportStore domain.ReportStore, hostStore domain.HostStore, hostTrustManager domain.HostTrustManager) (HostReportRefresher, error) {
 
 	return &hostReportRefresherImpl{
 		reportStore:      reportStore,
+		hostStore:        hostStore,
 		hostTrustManager: hostTrustManager,
 		cfg:              cfg,
 		fromTime:         firstFromTime,
@@ -43,9 +46,10 @@ var (
 
 type hostReportRefresherImpl struct {
 	reportStore      domain.ReportStore
+	hostStore        domain.HostStore
 	hostTrustManager domain.HostTrustManager
 	cfg              HRRSConfig
-	ctx              context.Context
+	cancel           context.CancelFunc
 	fromTime         time.Time
 }
 
@@ -58,7 +62,8 @@ func (refresher *hostReportRefresherImpl) Run() error {
 		return nil
 	}
 
-	refresher.ctx = context.Background()
+	var ctx context.Context
+	ctx, refresher.cancel = context.WithCancel(context.Background())
 
 	go func() {
 		for {
@@ -71,8 +76,9 @@ func (refresher *hostReportRefresherImpl) Run() error {
 			select {
 			case <-time.After(refresher.cfg.RefreshPeriod):
 				// continue with the loop and refresh reports again
-			case <-refresher.ctx.Done():
+			case <-ctx.Done():
 				defaultLog.Info("The HRRS has been stopped and will now exit")
+				return
 			}
 		}
 	}()
@@ -81,8 +87,8 @@ func (refresher *hostReportRefresherImpl) Run() error {
 }
 
 func (refresher *hostReportRefresherImpl) Stop() error {
-	if refresher.ctx != nil {
-		refresher.ctx.Done()
+	if refresher.cancel != nil {
+		refresher.cancel()
 	} else {
 		defaultLog.Debug("The HRRS is not running")
 	}
@@ -101,6 +107,20 @@ func (refresher *hostReportRefresherImpl) Stop() error {
 // HostTrustManage queue.
 func (refresher *hostReportRefresherImpl) refreshReports() error {
 
+	// the reports of the hosts in maintenance were not refreshed when they expired,
+	// the hosts are queued as soon as their maintenance ends
+	resumedHostIDs, err := refresher.hostStore.EndElapsedMaintenances(time.Now().UTC())
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while HRRS ended the elapsed host maintenances")
+	}
+	if len(resumedHostIDs) > 0 {
+		err = refresher.hostTrustManager.VerifyHostsAsync(resumedHostIDs, true, false)
+		if err != nil {
+			return errors.Wrap(err, "HRRS encountered an error calling the host trust manager")
+		}
+		defaultLog.Infof("HRRS queued %d hosts resuming from maintenance", len(resumedHostIDs))
+	}
+
 	toTime := time.Now().UTC().Add(refresher.cfg.RefreshPeriod)
 	defaultLog.Debugf("HRRS is refreshing hosts that have expired reports between %s and %s", refresher.fromTime, toTime)
 
@@ -122,5 +142,12 @@ func (refresher *hostReportRefresherImpl) refreshReports() error {
 	defaultLog.Infof("HRRS queued %d hosts from reports that were expiring between %s and %s", len(hostIDs), refresher.fromTime, toTime)
 	refresher.fromTime = toTime
 
+	if refresher.cfg.TrustHistoryRetention > 0 {
+		err = refresher.reportStore.DeleteTrustHistory(time.Now().UTC().Add(-refresher.cfg.TrustHistoryRetention))
+		if err != nil {
+			return errors.Wrap(err, "HRRS encountered an error deleting the trust history")
+		}
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@
 package hrrs
 
 import (
+	"context"
 	log "github.com/sirupsen/logrus"
 	"testing"
 	"time"
@@ -66,6 +67,14 @@ func TestHostReportRefresher(t *testing.T) {
 		},
 	})
 
+	// Put a host in maintenance until a time that has passed.
+	// Expect that its maintenance is ended and that it is updated on the first pass.
+	hostStore := mocks.NewMockHostStore()
+	host3UUID := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+	resumeAt := time.Now().Add(-twoSeconds)
+	err = hostStore.SetMaintenance(host3UUID, &hvs.HostMaintenance{StartedAt: time.Now().Add(-twentyFourHours), ResumeAt: &resumeAt})
+	assert.NoError(t, err)
+
 	hostTrustManager := MockHostTrustManager{
 		reportStore: reportStore,
 	}
@@ -73,7 +82,7 @@ func TestHostReportRefresher(t *testing.T) {
 	// create a new HostReportRefresher, 'run' the backgound thread and then
 	// sleep for ten seconds.  We expect the expired report to be updated
 	// in the report store.
-	refresher, err := NewHostReportRefresher(cfg, reportStore, hostTrustManager)
+	refresher, err := NewHostReportRefresher(cfg, reportStore, hostStore, hostTrustManager)
 	assert.NoError(t, err)
 	err = refresher.Run()
 	assert.NoError(t, err)
@@ -85,7 +94,7 @@ func TestHostReportRefresher(t *testing.T) {
 	assert.NoError(t, err)
 
 	// make sure both hosts have updated reports with future expiration dates
-	hostsToCheck := []uuid.UUID{host1UUID, host2UUID}
+	hostsToCheck := []uuid.UUID{host1UUID, host2UUID, host3UUID}
 	for _, hostId := range hostsToCheck {
 		criteria := models.ReportFilterCriteria{
 			HostID: hostId,
@@ -98,6 +107,10 @@ func TestHostReportRefresher(t *testing.T) {
 		assert.Equal(t, len(reports), 1)
 		assert.True(t, reports[0].Expiration.After(time.Now()))
 	}
+
+	host3, err := hostStore.Retrieve(host3UUID, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, host3.Maintenance)
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -107,7 +120,7 @@ type MockHostTrustManager struct {
 	reportStore domain.ReportStore
 }
 
-func (htm MockHostTrustManager) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
+func (htm MockHostTrustManager) VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
 	return nil, errors.New("VerifyHost is not implemented")
 }
 
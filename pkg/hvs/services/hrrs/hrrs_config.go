@@ -9,10 +9,15 @@ import "time"
 var (
 	// DefaultRefreshPeriod by default check for expired reports every five minutes
 	DefaultRefreshPeriod, _ = time.ParseDuration("5m")
+	// DefaultTrustHistoryRetention by default keeps the trust history of the hosts for 90 days
+	DefaultTrustHistoryRetention = time.Duration(90*24) * time.Hour
 )
 
 type HRRSConfig struct {
 	// RefreshPeriod determines how frequently the HRRS checks for expired reports (defaults to
 	// DefaultRefreshPeriod).
 	RefreshPeriod time.Duration `yaml:"refresh-period" mapstructure:"refresh-period"`
+	// TrustHistoryRetention is how long the trust history of the hosts is kept, the HRRS deletes the older history
+	// at every refresh. Zero keeps it forever.
+	TrustHistoryRetention time.Duration `yaml:"trust-history-retention" mapstructure:"trust-history-retention"`
 }
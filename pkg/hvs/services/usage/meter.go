@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+type usageKey struct {
+	tenantId string
+	identity string
+	day      time.Time
+}
+
+// Meter counts the API calls of each identity of the tenants and the reports generated for the hosts of the
+// tenants. The counts are kept in memory and added to the persisted usage at every flush period so that metering
+// does not add a database write to every request.
+type Meter struct {
+	store       domain.TenantUsageStore
+	hostStore   domain.HostStore
+	flushPeriod time.Duration
+
+	lock    sync.Mutex
+	pending map[usageKey]*hvs.TenantUsage
+	cancel  context.CancelFunc
+}
+
+// NewMeter returns a meter persisting the usage in the store, the host store is used to find the tenant of the
+// hosts the reports are generated for
+func NewMeter(store domain.TenantUsageStore, hostStore domain.HostStore, flushPeriod time.Duration) *Meter {
+	return &Meter{
+		store:       store,
+		hostStore:   hostStore,
+		flushPeriod: flushPeriod,
+		pending:     map[usageKey]*hvs.TenantUsage{},
+	}
+}
+
+// RecordAPICall counts an API call of an identity of a tenant, the calls of the identities that do not belong to
+// a tenant are counted under the empty tenant
+func (m *Meter) RecordAPICall(tenantId, identity string) {
+	m.add(tenantId, identity, 1, 0, today())
+}
+
+// BeforeReportPersisted implements domain.ReportHook, the reports are only counted once persisted
+func (m *Meter) BeforeReportPersisted(*models.HVSReport) error {
+	return nil
+}
+
+// AfterReportPersisted implements domain.ReportHook and counts the report generation for the tenant of the host
+func (m *Meter) AfterReportPersisted(report *models.HVSReport) error {
+	defaultLog.Trace("usage/meter:AfterReportPersisted() Entering")
+	defer defaultLog.Trace("usage/meter:AfterReportPersisted() Leaving")
+
+	host, err := m.hostStore.Retrieve(report.HostID, nil)
+	if err != nil {
+		return errors.Wrapf(err, "usage/meter:AfterReportPersisted() Error retrieving host %s", report.HostID)
+	}
+	m.add(host.TenantId, "", 0, 1, today())
+	return nil
+}
+
+// add adds the counts to the pending usage of the identity of the tenant on the day
+func (m *Meter) add(tenantId, identity string, apiCalls, reportGenerations int64, day time.Time) {
+	key := usageKey{tenantId: tenantId, identity: identity, day: day}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	u, ok := m.pending[key]
+	if !ok {
+		u = &hvs.TenantUsage{TenantId: tenantId, Identity: identity, Day: day}
+		m.pending[key] = u
+	}
+	u.ApiCalls += apiCalls
+	u.ReportGenerations += reportGenerations
+}
+
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// Flush persists the usage counted since the last flush, the usage is kept in memory when it cannot be persisted
+// and is persisted with the next flush
+func (m *Meter) Flush() error {
+	defaultLog.Trace("usage/meter:Flush() Entering")
+	defer defaultLog.Trace("usage/meter:Flush() Leaving")
+
+	// the requests are not held up while the usage is persisted
+	m.lock.Lock()
+	pending := m.pending
+	m.pending = map[usageKey]*hvs.TenantUsage{}
+	m.lock.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usage := make([]hvs.TenantUsage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, *u)
+	}
+	if err := m.store.Add(usage); err != nil {
+		for key, u := range pending {
+			m.add(key.tenantId, key.identity, u.ApiCalls, u.ReportGenerations, key.day)
+		}
+		return errors.Wrap(err, "usage/meter:Flush() Error persisting tenant usage")
+	}
+	return nil
+}
+
+// Run flushes the usage at every flush period until the meter is stopped
+func (m *Meter) Run() error {
+	defaultLog.Trace("usage/meter:Run() Entering")
+	defer defaultLog.Trace("usage/meter:Run() Leaving")
+
+	if m.flushPeriod <= 0 {
+		return errors.New("usage/meter:Run() The usage flush period must be greater than 0")
+	}
+
+	var ctx context.Context
+	ctx, m.cancel = context.WithCancel(context.Background())
+
+	go func() {
+		ticker := time.NewTicker(m.flushPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.Flush(); err != nil {
+					defaultLog.WithError(err).Error("usage/meter:Run() Error flushing tenant usage")
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic flush and persists the pending usage
+func (m *Meter) Stop() error {
+	defaultLog.Trace("usage/meter:Stop() Entering")
+	defer defaultLog.Trace("usage/meter:Stop() Leaving")
+
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return m.Flush()
+}
@@ -62,7 +62,7 @@ type vCenterClusterSyncerImpl struct {
 	esxiClusterStore domain.ESXiClusterStore
 	hostController   controllers.HostController
 	cfg              config.VCSSConfig
-	ctx              context.Context
+	cancel           context.CancelFunc
 }
 
 func (syncer *vCenterClusterSyncerImpl) Run() error {
@@ -76,18 +76,20 @@ func (syncer *vCenterClusterSyncerImpl) Run() error {
 		return nil
 	}
 
-	syncer.ctx = context.Background()
+	var ctx context.Context
+	ctx, syncer.cancel = context.WithCancel(context.Background())
 
 	go func() {
 		for {
-			err := syncer.syncHosts()
+			err := syncer.syncHosts(ctx)
 			if err != nil {
 				defaultLog.Errorf("vcss/vcenter_cluster_syncer:Run() VCSS encountered an error while syncing hosts...\n%+v\n", err)
 			}
 			select {
 			case <-time.After(syncer.cfg.RefreshPeriod):
-			case <-syncer.ctx.Done():
+			case <-ctx.Done():
 				defaultLog.Info("vcss/vcenter_cluster_syncer:Run() The VCSS has been stopped and will now exit")
+				return
 			}
 		}
 	}()
@@ -98,15 +100,15 @@ func (syncer *vCenterClusterSyncerImpl) Stop() error {
 	defaultLog.Trace("vcss/vcenter_cluster_syncer:Stop() Entering")
 	defer defaultLog.Trace("vcss/vcenter_cluster_syncer:Stop() Leaving")
 
-	if syncer.ctx != nil {
-		syncer.ctx.Done()
+	if syncer.cancel != nil {
+		syncer.cancel()
 	} else {
 		defaultLog.Debug("vcss/vcenter_cluster_syncer:Stop() VCSS is not running")
 	}
 	return nil
 }
 
-func (syncer *vCenterClusterSyncerImpl) syncHosts() error {
+func (syncer *vCenterClusterSyncerImpl) syncHosts(ctx context.Context) error {
 	defaultLog.Trace("vcss/vcenter_cluster_syncer:syncHosts() Entering")
 	defer defaultLog.Trace("vcss/vcenter_cluster_syncer:syncHosts() Leaving")
 
@@ -122,7 +124,7 @@ func (syncer *vCenterClusterSyncerImpl) syncHosts() error {
 			defaultLog.WithError(err).Error("vcss/vcenter_cluster_syncer:syncHosts() Error creating host connector instance")
 			continue
 		}
-		hostListFromVcenter, err := hostConnector.GetClusterReference(cluster.ClusterName)
+		hostListFromVcenter, err := hostConnector.GetClusterReference(ctx, cluster.ClusterName)
 		if err != nil {
 			defaultLog.WithError(err).Error("vcss/vcenter_cluster_syncer:syncHosts() Error getting cluster reference from vCenter")
 			continue
@@ -144,7 +146,7 @@ func (syncer *vCenterClusterSyncerImpl) syncHosts() error {
 			defaultLog.Infof("vcss/vcenter_cluster_syncer:syncHosts() Registering %d new host(s) with HVS ...", len(hostsToRegister))
 		}
 		for _, host := range hostsToRegister {
-			_, _, err := syncer.hostController.CreateHost(hvs.HostCreateRequest{
+			_, _, err := syncer.hostController.CreateHost(ctx, hvs.HostCreateRequest{
 				HostName:         host.Name,
 				Description:      host.Name + " in ESX Cluster " + cluster.ClusterName,
 				ConnectionString: fmt.Sprint(cluster.ConnectionString, ";h=", host.Name),
@@ -29,7 +29,7 @@ func (a *App) setup(args []string) error {
 	}
 	// look for cli flags
 	var ansFile string
-	var force bool
+	var force, interactive, fixDrift bool
 	for i, s := range args {
 		if s == "-f" || s == "--file" {
 			if i+1 < len(args) {
@@ -41,6 +41,17 @@ func (a *App) setup(args []string) error {
 		if s == "--force" {
 			force = true
 		}
+		if s == setup.InteractiveFlag {
+			interactive = true
+		}
+		if s == setup.FixDriftFlag {
+			fixDrift = true
+		}
+	}
+	cmd := args[1]
+	// "hvs setup --interactive" and "hvs setup --fix-drift" run all the setup tasks
+	if cmd == setup.InteractiveFlag || cmd == setup.FixDriftFlag {
+		cmd = "all"
 	}
 	// dump answer file to env
 	if ansFile != "" {
@@ -49,11 +60,17 @@ func (a *App) setup(args []string) error {
 			return errors.Wrap(err, "Failed to read answer file")
 		}
 	}
+	// prompt for the settings not in the answer file
+	if interactive && !(len(args) > 2 && args[2] == "--help") {
+		err := setup.NewConsoleInteractive(a.consoleWriter()).ReadAnswersToEnv(cmd, setupPrompts())
+		if err != nil {
+			return errors.Wrap(err, "Failed to read setup settings")
+		}
+	}
 	runner, err := a.setupTaskRunner()
 	if err != nil {
 		return err
 	}
-	cmd := args[1]
 	// print help and return if applicable
 	if len(args) > 2 && args[2] == "--help" {
 		if cmd == "all" {
@@ -69,7 +86,15 @@ func (a *App) setup(args []string) error {
 		}
 		return nil
 	}
-	if cmd == "all" {
+	if fixDrift {
+		if err = runner.FixDrift(cmd); err != nil {
+			fmt.Fprintln(a.errorWriter(), "Error(s) encountered when fixing setup drift:")
+			for errCmd, failErr := range runner.FailedCommands() {
+				fmt.Fprintln(a.errorWriter(), errCmd+": "+failErr.Error())
+			}
+			return err
+		}
+	} else if cmd == "all" {
 		if err = runner.RunAll(force); err != nil {
 			errCmds := runner.FailedCommands()
 			fmt.Fprintln(a.errorWriter(), "Error(s) encountered when running all setup commands:")
@@ -135,10 +160,15 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 		ConnectionRetryAttempts: viper.GetInt("db-conn-retry-attempts"),
 		ConnectionRetryTime:     viper.GetInt("db-conn-retry-time"),
 	}
+	secretSealing := setup.SecretSealing{
+		Enabled: viper.GetBool("tpm-seal-secrets"),
+		PCRs:    viper.GetString("tpm-seal-pcrs"),
+	}
 	runner.AddTask("database", "", &tasks.DBSetup{
 		DBConfigPtr:   &a.Config.DB,
 		DBConfig:      dbConf,
 		SSLCertSource: viper.GetString("db-ssl-cert-source"),
+		SecretSealing: secretSealing,
 		ConsoleWriter: a.consoleWriter(),
 	})
 	if reflect.DeepEqual(a.Config.DB, commConfig.DBConfig{}) {
@@ -176,14 +206,19 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 			Username: viper.GetString("hvs-service-username"),
 			Password: viper.GetString("hvs-service-password"),
 		},
-		AASApiUrl: viper.GetString("aas-base-url"),
+		AASApiUrl:     viper.GetString("aas-base-url"),
+		SecretSealing: secretSealing,
 		ServerConfig: commConfig.ServerConfig{
 			Port:              viper.GetInt("server-port"),
 			ReadTimeout:       viper.GetDuration("server-read-timeout"),
 			ReadHeaderTimeout: viper.GetDuration("server-read-header-timeout"),
 			WriteTimeout:      viper.GetDuration("server-write-timeout"),
 			IdleTimeout:       viper.GetDuration("server-idle-timeout"),
+			DrainTimeout:      viper.GetDuration("server-drain-timeout"),
+			BodyReadTimeout:   viper.GetDuration("server-body-read-timeout"),
+			MaxResponseBytes:  viper.GetInt64("server-max-response-bytes"),
 			MaxHeaderBytes:    viper.GetInt("server-max-header-bytes"),
+			MaxBodyBytes:      viper.GetInt64("server-max-body-bytes"),
 		},
 		DefaultPort:   constants.DefaultHVSListenerPort,
 		AppConfig:     &a.Config,
@@ -220,6 +255,7 @@ func (a *App) downloadCertTask(certType string) setup.Task {
 		updateSAMLConfig.CommonConfig = *updateConfig
 		updateSAMLConfig.ValiditySeconds = viper.GetInt("saml-validity-seconds")
 		updateSAMLConfig.Issuer = viper.GetString("saml-issuer-name")
+		updateSAMLConfig.KeyURI = viper.GetString("saml-key-uri")
 	}
 	return &setup.DownloadCert{
 		KeyFile:      viper.GetString(certType + "-key-file"),
@@ -286,4 +322,9 @@ func (a *App) setupHRRSConfig() {
 	if refreshPeriod != hrrs.DefaultRefreshPeriod {
 		a.Config.HRRS.RefreshPeriod = refreshPeriod
 	}
+
+	trustHistoryRetention := viper.GetDuration(constants.HrrsTrustHistoryRetention)
+	if trustHistoryRetention != hrrs.DefaultTrustHistoryRetention {
+		a.Config.HRRS.TrustHistoryRetention = trustHistoryRetention
+	}
 }
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/spf13/viper"
+)
+
+var certDownloadTasks = []string{"download-cert-tls", "download-cert-saml", "download-cert-flavor-signing"}
+
+// setupPrompts lists the settings prompted for by the interactive setup, in the order of the setup tasks
+func setupPrompts() []setup.Prompt {
+	dbTasks := []string{"database", "create-default-flavorgroup"}
+	return []setup.Prompt{
+		{EnvName: "HVS_DB_HOSTNAME", Description: "Database hostname", Default: viper.GetString("db-host"),
+			Validate: validation.ValidateHostname, Tasks: dbTasks},
+		{EnvName: "HVS_DB_PORT", Description: "Database port", Default: viper.GetString("db-port"),
+			Validate: validation.ValidatePort, Tasks: dbTasks},
+		{EnvName: "HVS_DB_NAME", Description: "Database name", Default: viper.GetString("db-name"),
+			Validate: validation.ValidateNameString, Tasks: dbTasks},
+		{EnvName: "HVS_DB_USERNAME", Description: "Database username", Validate: validation.ValidateUserNameString,
+			Tasks: dbTasks},
+		{EnvName: "HVS_DB_PASSWORD", Description: "Database password", Secret: true, Tasks: dbTasks},
+		{EnvName: "HVS_DB_SSLCERTSRC", Description: "Database server TLS certificate file", Optional: true,
+			Tasks: dbTasks},
+		{EnvName: "CMS_BASE_URL", Description: "CMS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: append([]string{"download-ca-cert"}, certDownloadTasks...)},
+		{EnvName: "CMS_TLS_CERT_SHA384", Description: "SHA384 digest of the CMS TLS certificate",
+			Validate: setup.ValidateCertDigest, Tasks: []string{"download-ca-cert"}},
+		{EnvName: "BEARER_TOKEN", Description: "Installation bearer token", Secret: true,
+			Validate: validation.ValidateJWT, Tasks: certDownloadTasks},
+		{EnvName: "SAN_LIST", Description: "Subject alternative names of the TLS certificate",
+			Default: viper.GetString("tls-san-list"), Tasks: []string{"download-cert-tls"}},
+		{EnvName: "AAS_API_URL", Description: "AAS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"update-service-config"}},
+		{EnvName: "HVS_SERVICE_USERNAME", Description: "HVS service username",
+			Validate: validation.ValidateUserNameString, Tasks: []string{"update-service-config"}},
+		{EnvName: "HVS_SERVICE_PASSWORD", Description: "HVS service password", Secret: true,
+			Tasks: []string{"update-service-config"}},
+	}
+}
@@ -6,7 +6,6 @@ package tasks
 
 import (
 	"fmt"
-	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
 	log "github.com/sirupsen/logrus"
 	"io"
 
@@ -78,7 +77,7 @@ func (t *CreateDefaultFlavor) SetName(n, e string) {
 
 func (t *CreateDefaultFlavor) flvGroupStore() (*postgres.FlavorGroupStore, error) {
 	if t.flvGroupStorePtr == nil {
-		dataStore, err := postgres.NewDataStore(postgres.NewDatabaseConfig(constants.DBTypePostgres, &t.DBConfig))
+		dataStore, err := postgres.NewDataStore(postgres.NewDatabaseConfig(t.DBConfig.Vendor, &t.DBConfig))
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to connect database")
 		}
@@ -24,6 +24,8 @@ type DBSetup struct {
 	// embedded structure for holding new configuation
 	commConfig.DBConfig
 	SSLCertSource string
+	// SecretSealing seals the database password saved in the configuration to the local TPM
+	SecretSealing setup.SecretSealing
 
 	// the pointer to configuration structure
 	DBConfigPtr   *commConfig.DBConfig
@@ -39,7 +41,7 @@ const defaultSSLCertFilePath = constants.ConfigDir + "hvsdbsslcert.pem"
 const DbEnvHelpPrompt = "Following environment variables are required for Database related setups:"
 
 var DbEnvHelp = map[string]string{
-	"DB_VENDOR":              "Vendor of database, or use HVS_DB_VENDOR alternatively",
+	"DB_VENDOR":              "Vendor of database (postgres or mysql), or use HVS_DB_VENDOR alternatively",
 	"DB_HOST":                "Database host name, or use HVS_DB_HOSTNAME alternatively",
 	"DB_PORT":                "Database port, or use HVS_DB_PORT alternatively",
 	"DB_NAME":                "Database name, or use HVS_DB_NAME alternatively",
@@ -52,6 +54,13 @@ var DbEnvHelp = map[string]string{
 	"DB_CONN_RETRY_TIME":     "Database connection retry time",
 }
 
+func init() {
+	for k, d := range setup.SecretSealingEnvHelp {
+		DbEnvHelp[k] = d
+		envHelp[k] = d
+	}
+}
+
 func (t *DBSetup) Run() error {
 	if t.DBConfigPtr == nil {
 		return errors.New("Pointer to database configuration structure can not be nil")
@@ -60,6 +69,10 @@ func (t *DBSetup) Run() error {
 	if t.Vendor == "" {
 		return errors.New("DB_VENDOR is not set, or use HVS_DB_VENDOR alternatively")
 	}
+	if t.Vendor != constants.DBTypePostgres && t.Vendor != constants.DBTypeMySQL {
+		return errors.Errorf("DB_VENDOR %s is not supported, supported vendors are %s and %s", t.Vendor,
+			constants.DBTypePostgres, constants.DBTypeMySQL)
+	}
 	if t.Host == "" {
 		return errors.New("DB_HOST is not set, or use HVS_DB_HOSTNAME alternatively")
 	}
@@ -124,8 +137,12 @@ func (t *DBSetup) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "Failed to connect database")
 	}
-	dataStore.Migrate()
-	return nil
+	if err = dataStore.Migrate(); err != nil {
+		return errors.Wrap(err, "Failed to create schemas")
+	}
+	// the password is sealed once the connection is tested, the database connections unseal it
+	t.DBConfigPtr.Password, err = t.SecretSealing.Seal(t.Password)
+	return errors.Wrap(err, "setup database: Failed to seal the db password")
 }
 
 func (t *DBSetup) Validate() error {
@@ -158,6 +175,19 @@ func (t *DBSetup) Validate() error {
 	return nil
 }
 
+// DetectDrift checks that all the HVS tables are in the database
+func (t *DBSetup) DetectDrift() error {
+	dataStore, err := postgres.New(pgConfig(t.DBConfigPtr))
+	if err != nil {
+		return errors.Wrap(err, "Failed to connect database")
+	}
+	defer dataStore.Close()
+	if missing := dataStore.MissingTables(); len(missing) != 0 {
+		return errors.New("Missing database tables: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func (t *DBSetup) PrintHelp(w io.Writer) {
 	setup.PrintEnvHelp(w, DbEnvHelpPrompt, t.envPrefix, DbEnvHelp)
 	fmt.Fprintln(w, "")
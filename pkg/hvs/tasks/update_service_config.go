@@ -12,6 +12,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/services/hrrs"
 	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -21,6 +22,8 @@ import (
 type UpdateServiceConfig struct {
 	ServiceConfig commConfig.ServiceConfig
 	AASApiUrl     string
+	// SecretSealing seals the service password saved in the configuration to the local TPM
+	SecretSealing setup.SecretSealing
 	AppConfig     **config.Configuration
 	ServerConfig  commConfig.ServerConfig
 	DefaultPort   int
@@ -37,17 +40,28 @@ var envHelp = map[string]string{
 	"LOG_ENABLE_STDOUT":                      "Enable console log",
 	"AAS_BASE_URL":                           "AAS Base URL",
 	"HRRS_REFRESH_PERIOD":                    "Host report refresh service period",
+	"HRRS_TRUST_HISTORY_RETENTION":           "Duration the trust history of the hosts is kept",
 	"VCSS_REFRESH_PERIOD":                    "VCenter refresh service period",
+	"HPRS_PROBE_PERIOD":                      "Host pre-registration service probe period",
 	"FVS_NUMBER_OF_VERIFIERS":                "NUmber of Flavor verification verifier threads",
 	"FVS_NUMBER_OF_DATA_FETCHERS":            "Number of Flavor verification data fetcher threads",
 	"FVS_SKIP_FLAVOR_SIGNATURE_VERIFICATION": "Skips flavor signature verification when set to true",
 	"HOST_TRUST_CACHE_THRESHOLD":             "Maximum number of entries to be cached in the Trust/Flavor caches",
+	"FVS_ASYNC_QUOTE_CALLBACK_URL":           "HVS quote-callbacks URL the trust agents post the TPM quotes to, enables asynchronous quote collection",
+	"FVS_ASYNC_QUOTE_TIMEOUT":                "Maximum time to wait for a trust agent to post back an asynchronous TPM quote",
+	"FVS_DECISION_LOG_FILE":                  "File the decision log of every flavor verification is appended to, for replayed audits",
+	"HOST_CONNECTOR_CALL_TIMEOUT":            "Maximum duration of every call to the trust agents and vCenter",
+	"HARDWARE_UUID_COLLISION_POLICY":         "Policy applied to the hosts registered with the hardware UUID of a registered host: reject, auto-suffix or merge",
 	"SERVER_PORT":                            "The Port on which Server Listens to",
 	"SERVER_READ_TIMEOUT":                    "Request Read Timeout Duration in Seconds",
 	"SERVER_READ_HEADER_TIMEOUT":             "Request Read Header Timeout Duration in Seconds",
 	"SERVER_WRITE_TIMEOUT":                   "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":                    "Request Idle Timeout in Seconds",
+	"SERVER_DRAIN_TIMEOUT":                   "Maximum Duration to Drain the In-Flight Requests on Shutdown",
+	"SERVER_BODY_READ_TIMEOUT":               "Maximum Duration to Receive a Request Body",
+	"SERVER_MAX_RESPONSE_BYTES":              "Max Length Of Response Body in Bytes, 0 for no limit",
 	"SERVER_MAX_HEADER_BYTES":                "Max Length Of Request Header in Bytes",
+	"SERVER_MAX_BODY_BYTES":                  "Max Length Of Request Body in Bytes",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -73,20 +87,42 @@ func (uc UpdateServiceConfig) Run() error {
 		uc.ServerConfig.Port > 65535 {
 		uc.ServerConfig.Port = uc.DefaultPort
 	}
+	servicePassword, err := uc.SecretSealing.Seal(uc.ServiceConfig.Password)
+	if err != nil {
+		return errors.Wrap(err, "Failed to seal the HVS service password")
+	}
+	uc.ServiceConfig.Password = servicePassword
 	(*uc.AppConfig).Server = uc.ServerConfig
 	(*uc.AppConfig).HVS = uc.ServiceConfig
 	(*uc.AppConfig).HRRS = hrrs.HRRSConfig{
-		RefreshPeriod: viper.GetDuration(constants.HrrsRefreshPeriod),
+		RefreshPeriod:         viper.GetDuration(constants.HrrsRefreshPeriod),
+		TrustHistoryRetention: viper.GetDuration(constants.HrrsTrustHistoryRetention),
 	}
 	(*uc.AppConfig).VCSS = config.VCSSConfig{
 		RefreshPeriod: viper.GetDuration(constants.VcssRefreshPeriod),
 	}
+	(*uc.AppConfig).HPRS = config.HPRSConfig{
+		ProbePeriod: viper.GetDuration(constants.HprsProbePeriod),
+	}
 	(*uc.AppConfig).FVS = config.FVSConfig{
 		NumberOfVerifiers:               viper.GetInt(constants.FvsNumberOfVerifiers),
 		NumberOfDataFetchers:            viper.GetInt(constants.FvsNumberOfDataFetchers),
 		SkipFlavorSignatureVerification: viper.GetBool(constants.FvsSkipFlavorSignatureVerification),
 		HostTrustCacheThreshold:         viper.GetInt(constants.FvsHostTrustCacheThreshold),
+		AttestationLatencyBudget:        viper.GetDuration(constants.FvsAttestationLatencyBudget),
+		AsyncQuoteCallbackURL:           viper.GetString(constants.FvsAsyncQuoteCallbackUrl),
+		AsyncQuoteTimeout:               viper.GetDuration(constants.FvsAsyncQuoteTimeout),
+		DecisionLogFile:                 viper.GetString(constants.FvsDecisionLogFile),
+		FlavorSignatureQuorum:           viper.GetInt(constants.FvsFlavorSignatureQuorum),
+		MaxQuoteAge:                     viper.GetDuration(constants.FvsMaxQuoteAge),
+		ClockSkewThreshold:              viper.GetDuration(constants.FvsClockSkewThreshold),
+		RevocationCheck:                 viper.GetBool(constants.FvsRevocationCheck),
+		CrlCacheTime:                    viper.GetDuration(constants.FvsCrlCacheTime),
+		OcspCheck:                       viper.GetBool(constants.FvsOcspCheck),
+		QueueVisibilityTimeout:          viper.GetDuration(constants.FvsQueueVisibilityTimeout),
 	}
+	(*uc.AppConfig).HostConnector.CallTimeout = viper.GetDuration(constants.HostConnectorCallTimeout)
+	(*uc.AppConfig).HardwareUuidCollisionPolicy = viper.GetString(constants.HardwareUuidCollisionPolicy)
 
 	return nil
 }
@@ -105,6 +141,11 @@ func (uc UpdateServiceConfig) Validate() error {
 		(*uc.AppConfig).Server.Port > 65535 {
 		return errors.New("Configured port is not valid")
 	}
+	switch (*uc.AppConfig).HardwareUuidCollisionPolicy {
+	case "", hvs.HardwareUuidCollisionReject, hvs.HardwareUuidCollisionAutoSuffix, hvs.HardwareUuidCollisionMerge:
+	default:
+		return errors.New("Configured hardware UUID collision policy is not valid")
+	}
 	return nil
 }
 
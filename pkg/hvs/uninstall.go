@@ -137,7 +137,7 @@ func (a *App) eraseData() error {
 	}
 	dbConf := a.configuration().DB
 	// test connection and create schemas
-	dataStore, err := postgres.NewDataStore(postgres.NewDatabaseConfig(constants.DBTypePostgres, &dbConf))
+	dataStore, err := postgres.NewDataStore(postgres.NewDatabaseConfig(dbConf.Vendor, &dbConf))
 	if err != nil {
 		return errors.Wrap(err, "Failed to connect database")
 	}
@@ -148,7 +148,9 @@ func (a *App) eraseData() error {
 			return errors.Wrap(err, "Failed to execute query")
 		}
 	}
-	dataStore.Migrate()
+	if err = dataStore.Migrate(); err != nil {
+		return errors.Wrap(err, "Failed to create schemas")
+	}
 	// create default flavor group
 	t := tasks.CreateDefaultFlavor{
 		DBConfig: dbConf,
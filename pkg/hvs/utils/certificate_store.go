@@ -6,8 +6,10 @@ package utils
 
 import (
 	"crypto"
+	"crypto/x509"
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier"
 )
 
 func LoadCertificates(certificatePaths *models.CertificatesPathStore) *models.CertificatesStore {
@@ -17,7 +19,9 @@ func LoadCertificates(certificatePaths *models.CertificatesPathStore) *models.Ce
 	certificateStore := make(models.CertificatesStore)
 	for _, certType := range models.GetUniqueCertTypes() {
 		certloc := (*certificatePaths)[certType]
-		if certType == models.CaCertTypesRootCa.String() || certType == models.CaCertTypesEndorsementCa.String() {
+		if certType == models.CaCertTypesRootCa.String() || certType == models.CaCertTypesEndorsementCa.String() ||
+			certType == models.CaCertTypesPlatformCa.String() || certType == models.CaCertTypesRimCa.String() ||
+			certType == models.CertTypesFlavorCoSigning.String() {
 			certificateStore[certType] = loadCertificatesFromDir(&certloc)
 		} else {
 			certificateStore[certType] = loadCertificatesFromFile(&certloc)
@@ -82,3 +86,39 @@ func loadKey(keyFile string) crypto.PrivateKey {
 	}
 	return key
 }
+
+// GetVerifierCertificates returns the certificates the flavors are verified with from the certificate store, the
+// certificates following the flavor signing certificate are its intermediate CAs and are trusted with the root CAs.
+// The flavor signing certificate is nil when the store does not have one.
+func GetVerifierCertificates(certStore *models.CertificatesStore) verifier.VerifierCertificates {
+	defaultLog.Trace("utils/certificate_store:GetVerifierCertificates() Entering")
+	defer defaultLog.Trace("utils/certificate_store:GetVerifierCertificates() Leaving")
+
+	rootCAs := (*certStore)[models.CaCertTypesRootCa.String()]
+	tagCAs := (*certStore)[models.CaCertTypesTagCa.String()]
+	privacyCAs := (*certStore)[models.CaCertTypesPrivacyCa.String()]
+	signingCerts := (*certStore)[models.CertTypesFlavorSigning.String()]
+	rootCApool := crypt.GetCertPool(rootCAs.Certificates)
+	var signingCert *x509.Certificate
+	if len(signingCerts.Certificates) > 0 {
+		signingCert = &signingCerts.Certificates[0]
+		for i := range signingCerts.Certificates[1:] {
+			rootCApool.AddCert(&signingCerts.Certificates[i+1]) //Add intermediate CA
+		}
+	}
+
+	var coSigningCerts []*x509.Certificate
+	if coSigningStore := (*certStore)[models.CertTypesFlavorCoSigning.String()]; coSigningStore != nil {
+		for i := range coSigningStore.Certificates {
+			coSigningCerts = append(coSigningCerts, &coSigningStore.Certificates[i])
+		}
+	}
+
+	return verifier.VerifierCertificates{
+		PrivacyCACertificates:       crypt.GetCertPool(privacyCAs.Certificates),
+		AssetTagCACertificates:      crypt.GetCertPool(tagCAs.Certificates),
+		FlavorSigningCertificate:    signingCert,
+		FlavorCACertificates:        rootCApool,
+		FlavorCoSigningCertificates: coSigningCerts,
+	}
+}
@@ -5,9 +5,13 @@
 package utils
 
 import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
 )
 
 var defaultLog = log.GetDefaultLogger()
@@ -32,6 +36,55 @@ func GetAutomaticFlavorMatchPolicy() []hvs.FlavorMatchPolicy {
 	policies = append(policies, hvs.NewFlavorMatchPolicy(cf.FlavorPartSoftware, hvs.NewMatchPolicy(hvs.MatchTypeAllOf, hvs.FlavorRequiredIfDefined)))
 	policies = append(policies, hvs.NewFlavorMatchPolicy(cf.FlavorPartAssetTag, hvs.NewMatchPolicy(hvs.MatchTypeLatest, hvs.FlavorRequiredIfDefined)))
 	policies = append(policies, hvs.NewFlavorMatchPolicy(cf.FlavorPartHostUnique, hvs.NewMatchPolicy(hvs.MatchTypeLatest, hvs.FlavorRequiredIfDefined)))
+	policies = append(policies, hvs.NewFlavorMatchPolicy(cf.FlavorPartContainerImage, hvs.NewMatchPolicy(hvs.MatchTypeAllOf, hvs.FlavorRequiredIfDefined)))
 
 	return policies
 }
+
+// GetFlavorGroupAncestors returns the flavorgroups the flavorgroup inherits from, the parent first. It fails when the
+// hierarchy is deeper than MaxFlavorgroupHierarchyDepth or when a flavorgroup inherits from itself
+func GetFlavorGroupAncestors(fgs domain.FlavorGroupStore, flavorgroup hvs.FlavorGroup) ([]hvs.FlavorGroup, error) {
+	defaultLog.Trace("utils/flavor_group:GetFlavorGroupAncestors() Entering")
+	defer defaultLog.Trace("utils/flavor_group:GetFlavorGroupAncestors() Leaving")
+
+	var ancestors []hvs.FlavorGroup
+	visited := map[uuid.UUID]bool{flavorgroup.ID: true}
+	for parentId := flavorgroup.ParentId; parentId != nil; {
+		if visited[*parentId] {
+			return nil, errors.Errorf("Flavorgroup %s inherits from itself", parentId.String())
+		}
+		if len(ancestors)+1 >= constants.MaxFlavorgroupHierarchyDepth {
+			return nil, errors.Errorf("Flavorgroup hierarchy is deeper than %d flavorgroups", constants.MaxFlavorgroupHierarchyDepth)
+		}
+		parent, err := fgs.Retrieve(*parentId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve parent flavorgroup %s", parentId.String())
+		}
+		visited[*parentId] = true
+		ancestors = append(ancestors, *parent)
+		parentId = parent.ParentId
+	}
+	return ancestors, nil
+}
+
+// InheritMatchPolicies returns the match policies of the flavorgroup completed with the policies of the flavor parts
+// it does not define itself, taken from the nearest ancestor defining them
+func InheritMatchPolicies(flavorgroup hvs.FlavorGroup, ancestors []hvs.FlavorGroup) hvs.FlavorMatchPolicies {
+	defaultLog.Trace("utils/flavor_group:InheritMatchPolicies() Entering")
+	defer defaultLog.Trace("utils/flavor_group:InheritMatchPolicies() Leaving")
+
+	policies := append(hvs.FlavorMatchPolicies{}, flavorgroup.MatchPolicies...)
+	defined := make(map[cf.FlavorPart]bool)
+	for _, policy := range policies {
+		defined[policy.FlavorPart] = true
+	}
+	for _, ancestor := range ancestors {
+		for _, policy := range ancestor.MatchPolicies {
+			if !defined[policy.FlavorPart] {
+				defined[policy.FlavorPart] = true
+				policies = append(policies, policy)
+			}
+		}
+	}
+	return policies
+}
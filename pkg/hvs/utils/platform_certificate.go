@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+var (
+	// TCG platform attribute OIDs (tcg-at-platformManufacturerStr, tcg-at-platformModel,
+	// tcg-at-platformVersion and tcg-at-platformSerial)
+	oidTcgPlatformManufacturer = asn1.ObjectIdentifier{2, 23, 133, 5, 1, 1}
+	oidTcgPlatformModel        = asn1.ObjectIdentifier{2, 23, 133, 5, 1, 4}
+	oidTcgPlatformVersion      = asn1.ObjectIdentifier{2, 23, 133, 5, 1, 5}
+	oidTcgPlatformSerial       = asn1.ObjectIdentifier{2, 23, 133, 5, 1, 6}
+
+	// Oid "2.5.29.17" is for SubjectAltName extension
+	oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+	// the signature algorithms of the platform certificates, by OID
+	platformCertificateSignatureAlgorithms = map[string]x509.SignatureAlgorithm{
+		"1.2.840.113549.1.1.11": x509.SHA256WithRSA,
+		"1.2.840.113549.1.1.12": x509.SHA384WithRSA,
+		"1.2.840.113549.1.1.13": x509.SHA512WithRSA,
+		"1.2.840.10045.4.3.2":   x509.ECDSAWithSHA256,
+		"1.2.840.10045.4.3.3":   x509.ECDSAWithSHA384,
+		"1.2.840.10045.4.3.4":   x509.ECDSAWithSHA512,
+	}
+)
+
+// attributeCertificateVersion is the v2 version of the RFC 5755 attribute certificates
+const attributeCertificateVersion = 1
+
+// PlatformCertificate is a TCG platform certificate, an RFC 5755 attribute certificate issued to the EK certificate
+// of the TPM of the platform
+type PlatformCertificate struct {
+	Raw          []byte
+	RawIssuer    []byte
+	Issuer       pkix.Name
+	SerialNumber *big.Int
+	NotBefore    time.Time
+	NotAfter     time.Time
+
+	rawInfo            []byte
+	signatureAlgorithm x509.SignatureAlgorithm
+	signature          []byte
+	holder             issuerSerial
+	attributes         []directoryAttribute
+	extensions         []pkix.Extension
+}
+
+type attributeCertificate struct {
+	Info               asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type attributeCertificateInfo struct {
+	Version            int
+	Holder             holder
+	Issuer             asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SerialNumber       *big.Int
+	Validity           attributeCertificateValidity
+	Attributes         []directoryAttribute
+	IssuerUniqueID     asn1.BitString   `asn1:"optional"`
+	Extensions         []pkix.Extension `asn1:"optional"`
+}
+
+// holder is the RFC 5755 Holder, the TCG platform certificates name the EK certificate with the baseCertificateID
+type holder struct {
+	BaseCertificateID issuerSerial `asn1:"optional,tag:0"`
+}
+
+// v2Form is the v2Form [0] of the RFC 5755 AttCertIssuer, the issuer is named by the issuerName
+type v2Form struct {
+	IssuerName []asn1.RawValue `asn1:"optional"`
+}
+
+type attributeCertificateValidity struct {
+	NotBefore time.Time `asn1:"generalized"`
+	NotAfter  time.Time `asn1:"generalized"`
+}
+
+type directoryAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// issuerSerial is the RFC 5755 IssuerSerial naming a certificate by the GeneralNames of its issuer and its serial
+// number, as the holder baseCertificateID of the TCG platform certificates
+type issuerSerial struct {
+	Issuer    []asn1.RawValue
+	Serial    *big.Int
+	IssuerUID asn1.BitString `asn1:"optional"`
+}
+
+// ParsePlatformCertificate parses a DER encoded TCG platform certificate
+func ParsePlatformCertificate(der []byte) (*PlatformCertificate, error) {
+	defaultLog.Trace("utils/platform_certificate:ParsePlatformCertificate() Entering")
+	defer defaultLog.Trace("utils/platform_certificate:ParsePlatformCertificate() Leaving")
+
+	var ac attributeCertificate
+	if rest, err := asn1.Unmarshal(der, &ac); err != nil {
+		return nil, errors.Wrap(err, "Error parsing platform attribute certificate")
+	} else if len(rest) != 0 {
+		return nil, errors.New("Trailing data after platform attribute certificate")
+	}
+	var info attributeCertificateInfo
+	if _, err := asn1.Unmarshal(ac.Info.FullBytes, &info); err != nil {
+		return nil, errors.Wrap(err, "Error parsing platform attribute certificate info")
+	}
+	if info.Version != attributeCertificateVersion {
+		return nil, errors.Errorf("Unsupported platform attribute certificate version %d", info.Version+1)
+	}
+	if !info.SignatureAlgorithm.Algorithm.Equal(ac.SignatureAlgorithm.Algorithm) {
+		return nil, errors.New("Platform attribute certificate signature algorithms do not match")
+	}
+	signatureAlgorithm, ok := platformCertificateSignatureAlgorithms[ac.SignatureAlgorithm.Algorithm.String()]
+	if !ok {
+		return nil, errors.Errorf("Unsupported platform attribute certificate signature algorithm %s", ac.SignatureAlgorithm.Algorithm.String())
+	}
+	if info.Holder.BaseCertificateID.Serial == nil {
+		return nil, errors.New("Platform attribute certificate holder does not name a base certificate")
+	}
+
+	// the issuer is named by the single directoryName of the v2Form
+	if info.Issuer.Class != asn1.ClassContextSpecific || info.Issuer.Tag != 0 {
+		return nil, errors.New("Platform attribute certificate issuer is not of the v2Form")
+	}
+	var issuer v2Form
+	if _, err := asn1.UnmarshalWithParams(info.Issuer.FullBytes, &issuer, "tag:0"); err != nil {
+		return nil, errors.Wrap(err, "Error parsing platform attribute certificate issuer")
+	}
+	rawIssuer := directoryName(issuer.IssuerName)
+	if rawIssuer == nil {
+		return nil, errors.New("Platform attribute certificate issuer is not named by a directory name")
+	}
+	var issuerRDNs pkix.RDNSequence
+	if _, err := asn1.Unmarshal(rawIssuer, &issuerRDNs); err != nil {
+		return nil, errors.Wrap(err, "Error parsing platform attribute certificate issuer name")
+	}
+
+	cert := PlatformCertificate{
+		Raw:                der,
+		RawIssuer:          rawIssuer,
+		SerialNumber:       info.SerialNumber,
+		NotBefore:          info.Validity.NotBefore,
+		NotAfter:           info.Validity.NotAfter,
+		rawInfo:            ac.Info.FullBytes,
+		signatureAlgorithm: signatureAlgorithm,
+		signature:          ac.SignatureValue.RightAlign(),
+		holder:             info.Holder.BaseCertificateID,
+		attributes:         info.Attributes,
+		extensions:         info.Extensions,
+	}
+	cert.Issuer.FillFromRDNSequence(&issuerRDNs)
+	return &cert, nil
+}
+
+// GetPlatformAttributes extracts the platform manufacturer, model, version and serial from a
+// platform certificate. The TCG platform attributes of the directory name of the subject alternative
+// name extension are preferred, the attributes of the attribute certificate are used when they are absent.
+func GetPlatformAttributes(cert *PlatformCertificate) (*hvs.PlatformAttributes, error) {
+	defaultLog.Trace("utils/platform_certificate:GetPlatformAttributes() Entering")
+	defer defaultLog.Trace("utils/platform_certificate:GetPlatformAttributes() Leaving")
+
+	if cert == nil {
+		return nil, errors.New("Platform certificate must be provided")
+	}
+
+	attributes := hvs.PlatformAttributes{}
+	for _, ext := range cert.extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+		var generalNames []asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &generalNames); err != nil {
+			return nil, errors.Wrap(err, "Error parsing subject alternative names of platform certificate")
+		}
+		rawName := directoryName(generalNames)
+		if rawName == nil {
+			continue
+		}
+		var rdns pkix.RDNSequence
+		if _, err := asn1.Unmarshal(rawName, &rdns); err != nil {
+			return nil, errors.Wrap(err, "Error parsing platform attributes of platform certificate")
+		}
+		for _, rdn := range rdns {
+			for _, atv := range rdn {
+				if value, ok := atv.Value.(string); ok {
+					setPlatformAttribute(&attributes, atv.Type, value)
+				}
+			}
+		}
+	}
+
+	for _, attribute := range cert.attributes {
+		if len(attribute.Values) == 0 {
+			continue
+		}
+		var value string
+		if _, err := asn1.Unmarshal(attribute.Values[0].FullBytes, &value); err != nil {
+			// the other attributes of the platform certificates are not strings
+			continue
+		}
+		setPlatformAttribute(&attributes, attribute.Type, value)
+	}
+
+	if attributes.Manufacturer == "" || attributes.Serial == "" {
+		return nil, errors.New("Platform certificate does not contain platform manufacturer and serial number")
+	}
+	return &attributes, nil
+}
+
+// setPlatformAttribute sets the platform attribute of the OID unless it is already set
+func setPlatformAttribute(attributes *hvs.PlatformAttributes, oid asn1.ObjectIdentifier, value string) {
+	var attribute *string
+	switch {
+	case oid.Equal(oidTcgPlatformManufacturer):
+		attribute = &attributes.Manufacturer
+	case oid.Equal(oidTcgPlatformModel):
+		attribute = &attributes.Model
+	case oid.Equal(oidTcgPlatformVersion):
+		attribute = &attributes.Version
+	case oid.Equal(oidTcgPlatformSerial):
+		attribute = &attributes.Serial
+	default:
+		return
+	}
+	if *attribute == "" {
+		*attribute = strings.TrimSpace(value)
+	}
+}
+
+// VerifyPlatformCertificate verifies that the platform certificate has been signed by one of the
+// given platform CA certificates, and that the CA certificate is issued by a trusted platform CA
+func VerifyPlatformCertificate(cert *PlatformCertificate, caCerts []x509.Certificate) error {
+	defaultLog.Trace("utils/platform_certificate:VerifyPlatformCertificate() Entering")
+	defer defaultLog.Trace("utils/platform_certificate:VerifyPlatformCertificate() Leaving")
+
+	if len(caCerts) == 0 {
+		return errors.New("No platform CA certificates are loaded")
+	}
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for i := range caCerts {
+		if caCerts[i].IsCA && string(caCerts[i].RawIssuer) == string(caCerts[i].RawSubject) {
+			roots.AddCert(&caCerts[i])
+		} else {
+			intermediates.AddCert(&caCerts[i])
+		}
+	}
+	verifyOpts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for i := range caCerts {
+		if !bytes.Equal(caCerts[i].RawSubject, cert.RawIssuer) {
+			continue
+		}
+		if caCerts[i].CheckSignature(cert.signatureAlgorithm, cert.rawInfo, cert.signature) != nil {
+			continue
+		}
+		if _, err := caCerts[i].Verify(verifyOpts); err != nil {
+			return errors.Wrap(err, "Platform certificate issuer is not issued by a trusted platform CA")
+		}
+		return nil
+	}
+	return errors.New("Platform certificate is not signed by a trusted platform CA")
+}
+
+// VerifyPlatformCertificateHolder verifies that the platform certificate is held by the TPM of the EK certificate,
+// the holder baseCertificateID of the platform certificate is the IssuerSerial of the EK certificate
+func VerifyPlatformCertificateHolder(cert *PlatformCertificate, ekCert *x509.Certificate) error {
+	defaultLog.Trace("utils/platform_certificate:VerifyPlatformCertificateHolder() Entering")
+	defer defaultLog.Trace("utils/platform_certificate:VerifyPlatformCertificateHolder() Leaving")
+
+	if cert == nil || ekCert == nil {
+		return errors.New("Platform certificate and EK certificate must be provided")
+	}
+	if cert.holder.Serial == nil || cert.holder.Serial.Cmp(ekCert.SerialNumber) != 0 {
+		return errors.New("Platform certificate holder serial number does not match the EK certificate")
+	}
+	if !bytes.Equal(directoryName(cert.holder.Issuer), ekCert.RawIssuer) {
+		return errors.New("Platform certificate holder issuer does not match the EK certificate")
+	}
+	return nil
+}
+
+// directoryName returns the DER encoded Name of the directoryName [4] of the GeneralNames, nil when there is none
+func directoryName(generalNames []asn1.RawValue) []byte {
+	for _, name := range generalNames {
+		if name.Class == asn1.ClassContextSpecific && name.Tag == 4 && name.IsCompound {
+			return name.Bytes
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"bytes"
+	"crypto/x509"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier/rules"
+	"github.com/pkg/errors"
+)
+
+type tagCertificateRevocationChecker struct {
+	tagCertificateStore domain.TagCertificateStore
+}
+
+// NewTagCertificateRevocationChecker returns a TagCertificateRevocationChecker looking up the tag certificates
+// revoked in the tag certificate store. The tag certificates unknown to the store are not considered revoked.
+func NewTagCertificateRevocationChecker(tagCertificateStore domain.TagCertificateStore) rules.TagCertificateRevocationChecker {
+	return &tagCertificateRevocationChecker{tagCertificateStore: tagCertificateStore}
+}
+
+func (rc *tagCertificateRevocationChecker) IsTagCertificateRevoked(tagCertificate *x509.Certificate) (bool, error) {
+	defaultLog.Trace("utils/tag_certificate:IsTagCertificateRevoked() Entering")
+	defer defaultLog.Trace("utils/tag_certificate:IsTagCertificateRevoked() Leaving")
+
+	revoked := true
+	revokedTagCertificates, err := rc.tagCertificateStore.Search(&models.TagCertificateFilterCriteria{
+		SubjectEqualTo: tagCertificate.Subject.CommonName,
+		RevokedEqualTo: &revoked,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "Error searching the revoked tag certificates of '%s'", tagCertificate.Subject.CommonName)
+	}
+
+	for _, revokedTagCertificate := range revokedTagCertificates {
+		if bytes.Equal(revokedTagCertificate.Certificate, tagCertificate.Raw) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/pkg/errors"
+)
+
+var tenantContextRegex = regexp.MustCompile(constants.TenantContextRegex)
+
+// GetTenantId returns the tenant set in the context of the HVS roles of the user making the request. Users
+// without a tenant, and requests without roles in their context, work in the default namespace which is
+// returned as an empty tenant.
+func GetTenantId(r *http.Request) (string, error) {
+	defaultLog.Trace("utils/tenant:GetTenantId() Entering")
+	defer defaultLog.Trace("utils/tenant:GetTenantId() Leaving")
+
+	roles, err := comctx.GetUserRoles(r)
+	if err != nil {
+		return "", nil
+	}
+
+	tenantId := ""
+	for _, role := range roles {
+		if role.Service != constants.ServiceName {
+			continue
+		}
+		match := tenantContextRegex.FindStringSubmatch(role.Context)
+		if match == nil {
+			continue
+		}
+		if tenantId != "" && tenantId != match[1] {
+			return "", errors.New("HVS roles of the user belong to more than one tenant")
+		}
+		tenantId = match[1]
+	}
+	return tenantId, nil
+}
@@ -9,24 +9,21 @@ import (
 
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	"io"
 	"os"
 	"os/exec"
-	"time"
 
 	"github.com/pkg/errors"
 
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	commLogInt "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/setup"
 )
 
-var errInvalidCmd = errors.New("Invalid input after command")
-
 type App struct {
 	HomeDir        string
 	InstanceName   string
@@ -43,76 +40,12 @@ type App struct {
 }
 
 func (app *App) Run(args []string) error {
-	if len(args) < 2 {
-		err := errors.New("Invalid usage of " + constants.ServiceName)
-		app.printUsageWithError(err)
-		return err
+	runner := &commCmd.Runner{
+		Root:          app.commands(),
+		ConsoleWriter: app.consoleWriter(),
+		PrintUsage:    app.printUsageWithError,
 	}
-	cmd := args[1]
-	switch cmd {
-	case "run":
-		if len(args) >= constants.MaxArguments {
-			return errInvalidCmd
-		}
-		if err := app.startDaemon(); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: daemon did not start - ", err.Error())
-			// wait some time for logs to flush - otherwise, there will be no entry in syslog
-			time.Sleep(10 * time.Millisecond)
-			return errors.Wrap(err, "Error starting IHUB Service")
-		}
-	case "help", "-h", "--help":
-		app.printUsage()
-		return nil
-	case "start":
-		if len(args) >= constants.MaxArguments {
-			return errInvalidCmd
-		}
-		return app.start()
-	case "stop":
-		if len(args) >= constants.MaxArguments {
-			return errInvalidCmd
-		}
-		return app.stop()
-	case "status":
-		if len(args) >= constants.MaxArguments {
-			return errInvalidCmd
-		}
-		return app.status()
-	case "uninstall":
-		purge := false
-		exec := false
-		if len(args) >= constants.MaxArguments+2 { //For additional purge and all
-			return errInvalidCmd
-		}
-
-		for _, flag := range args {
-			if flag == "--purge" {
-				purge = true
-			} else if flag == "--exec" {
-				exec = true
-			}
-		}
-
-		app.uninstall(purge, exec)
-		return nil
-	case "version", "-v", "--version":
-		app.printVersion()
-		return nil
-	case "setup":
-		if err := app.setup(args[1:]); err != nil {
-			if errors.Cause(err) == setup.ErrTaskNotFound {
-				app.printUsageWithError(err)
-			} else {
-				fmt.Fprintln(app.errorWriter(), err.Error())
-			}
-			return err
-		}
-	default:
-		err := errors.New("Unrecognized command: " + cmd)
-		app.printUsageWithError(err)
-		return err
-	}
-	return nil
+	return runner.Run(args)
 }
 
 func (app *App) consoleWriter() io.Writer {
@@ -210,8 +143,15 @@ func (app *App) stop() error {
 	return cmd.Run()
 }
 
-func (app *App) status() error {
+func (app *App) status(ctx *commCmd.Context) error {
 	serviceName := constants.InstancePrefix + app.InstanceName
+	if ctx.JSONOutput() {
+		status, err := commCmd.GetServiceStatus(serviceName)
+		if err != nil {
+			return err
+		}
+		return ctx.Print("", status)
+	}
 	fmt.Fprintln(app.consoleWriter(), `Forwarding to "systemctl status `+serviceName+`"`)
 	systemctl, err := exec.LookPath("systemctl")
 	if err != nil {
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ihub
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+// setupTaskNames are completed after the setup command
+var setupTaskNames = []string{"all", "download-ca-cert", "download-cert-tls", "attestation-service-connection",
+	"tenant-service-connection", "create-signing-key", "download-saml-cert", "update-service-config"}
+
+// instanceFlag selects the instance of ihub, it is read by main before the app is created
+var instanceFlag = commCmd.CmdFlag{Name: "instance", Short: "i"}
+
+// commands lists the commands of the ihub CLI
+func (app *App) commands() *commCmd.Cmd {
+	return &commCmd.Cmd{
+		Name:  constants.ServiceName,
+		Flags: []commCmd.CmdFlag{commCmd.OutputFlag(constants.ServiceName)},
+		SubCmd: []commCmd.Cmd{
+			{Name: "help", Aliases: []string{"-h", "--help"}, Exec: func(ctx *commCmd.Context) error {
+				app.printUsage()
+				return nil
+			}},
+			{Name: "version", Aliases: []string{"-v", "--version"}, Exec: app.printVersion},
+			{Name: "run", Flags: []commCmd.CmdFlag{instanceFlag}, Exec: func(ctx *commCmd.Context) error {
+				if err := app.startDaemon(); err != nil {
+					fmt.Fprintln(os.Stderr, "Error: daemon did not start - ", err.Error())
+					// wait some time for logs to flush - otherwise, there will be no entry in syslog
+					time.Sleep(10 * time.Millisecond)
+					return errors.Wrap(err, "Error starting IHUB Service")
+				}
+				return nil
+			}},
+			{Name: "start", Flags: []commCmd.CmdFlag{instanceFlag}, Exec: func(ctx *commCmd.Context) error {
+				return app.start()
+			}},
+			{Name: "stop", Flags: []commCmd.CmdFlag{instanceFlag}, Exec: func(ctx *commCmd.Context) error {
+				return app.stop()
+			}},
+			{Name: "status", Flags: []commCmd.CmdFlag{instanceFlag}, Exec: app.status},
+			{Name: "uninstall", Flags: []commCmd.CmdFlag{instanceFlag, {Name: "purge", Bool: true}, {Name: "exec", Bool: true}},
+				Exec: func(ctx *commCmd.Context) error {
+					app.uninstall(ctx.Bool("purge"), ctx.Bool("exec"))
+					return nil
+				}},
+			{Name: "setup", PassThrough: true, Completions: setupTaskNames,
+				Flags: []commCmd.CmdFlag{{Name: "help"}, {Name: "force"}, {Name: "file", Short: "f"}, {Name: "interactive"},
+					{Name: "fix-drift"}, instanceFlag},
+				Exec: func(ctx *commCmd.Context) error {
+					if err := app.setup(ctx.Args); err != nil {
+						if errors.Cause(err) == setup.ErrTaskNotFound {
+							app.printUsageWithError(err)
+						} else {
+							fmt.Fprintln(app.errorWriter(), err.Error())
+						}
+						return err
+					}
+					return nil
+				}},
+			commCmd.CompletionCmd,
+		},
+	}
+}
@@ -24,9 +24,14 @@ type Configuration struct {
 
 	Log                commConfig.LogConfig     `yaml:"log" mapstructure:"log"`
 	IHUB               commConfig.ServiceConfig `yaml:"ihub" mapstructure:"ihub"`
+	Server             commConfig.ServerConfig  `yaml:"server" mapstructure:"server"`
 	AttestationService AttestationConfig        `yaml:"attestation-service" mapstructure:"attestation-service"`
 	Endpoint           Endpoint                 `yaml:"end-point" mapstructure:"end-point"`
 	TLS                commConfig.TLSCertConfig `yaml:"tls" mapstructure:"tls"`
+
+	// FipsMode restricts the service to the FIPS approved algorithms and TLS cipher suites, it is always enabled
+	// in the binaries built with the fips tag
+	FipsMode bool `yaml:"fips-mode" mapstructure:"fips-mode"`
 }
 
 type AttestationConfig struct {
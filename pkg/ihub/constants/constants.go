@@ -4,6 +4,8 @@
  */
 package constants
 
+import "time"
+
 const (
 	ServiceName                 = "ihub"
 	InstancePrefix              = "ihub@"
@@ -51,6 +53,17 @@ const (
 	MaxArguments                = 5
 )
 
+// Status API server defaults, the server is not started when the port is 0
+const (
+	DefaultPort              = 10443
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 10 * time.Second
+	DefaultDrainTimeout      = 30 * time.Second
+	DefaultMaxHeaderBytes    = 1 << 20
+)
+
 const (
 	/*Open Stack Specific Constants */
 	SgxTraitPrefix              = "SGX_"
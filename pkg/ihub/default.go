@@ -23,6 +23,15 @@ func init() {
 	viper.SetDefault("tls-common-name", constants.DefaultIHUBTlsCn)
 	viper.SetDefault("tls-san-list", constants.DefaultTLSSan)
 
+	//Set default values for the status API server
+	viper.SetDefault("server-port", constants.DefaultPort)
+	viper.SetDefault("server-read-timeout", constants.DefaultReadTimeout)
+	viper.SetDefault("server-read-header-timeout", constants.DefaultReadHeaderTimeout)
+	viper.SetDefault("server-write-timeout", constants.DefaultWriteTimeout)
+	viper.SetDefault("server-idle-timeout", constants.DefaultIdleTimeout)
+	viper.SetDefault("server-drain-timeout", constants.DefaultDrainTimeout)
+	viper.SetDefault("server-max-header-bytes", constants.DefaultMaxHeaderBytes)
+
 	//Set default values for log
 	viper.SetDefault("log-max-length", constants.DefaultLogEntryMaxlength)
 	viper.SetDefault("log-enable-stdout", true)
@@ -40,6 +49,15 @@ func defaultConfig() *config.Configuration {
 			Username: viper.GetString("ihub-service-username"),
 			Password: viper.GetString("ihub-service-password"),
 		},
+		Server: commConfig.ServerConfig{
+			Port:              viper.GetInt("server-port"),
+			ReadTimeout:       viper.GetDuration("server-read-timeout"),
+			ReadHeaderTimeout: viper.GetDuration("server-read-header-timeout"),
+			WriteTimeout:      viper.GetDuration("server-write-timeout"),
+			IdleTimeout:       viper.GetDuration("server-idle-timeout"),
+			DrainTimeout:      viper.GetDuration("server-drain-timeout"),
+			MaxHeaderBytes:    viper.GetInt("server-max-header-bytes"),
+		},
 		TLS: commConfig.TLSCertConfig{
 			CertFile:   viper.GetString("tls-cert-file"),
 			KeyFile:    viper.GetString("tls-key-file"),
@@ -55,6 +73,7 @@ func defaultConfig() *config.Configuration {
 			Level:        viper.GetString("log-level"),
 			EnableStdout: viper.GetBool("log-enable-stdout"),
 		},
+		FipsMode: viper.GetBool("fips-mode"),
 	}
 }
 
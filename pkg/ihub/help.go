@@ -7,7 +7,9 @@ package ihub
 import (
 	"fmt"
 
+	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/version"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 )
 
 const helpStr = `Usage:
@@ -27,13 +29,24 @@ Available Commands:
 		-i|--instance <instance-name>      the instance name to execute command against specific instance
 		--purge                            all configuration and data files will be removed for instance if this flag is set
 		--exec                             executable will be removed which is common for all instances if this flag is set
+	completion <bash|zsh>        Print the shell completion script, load it with: source <(ihub completion bash)
+
+Global Flags:
+	--output <text|json>         output format of the version and status commands (env: IHUB_OUTPUT)
 
 Usage of ihub setup:
-	ihub setup <task> [--help] [--force] [-f <answer-file>] [-i <instance-name>]
+	ihub setup <task> [--help] [--force] [-f <answer-file>] [-i <instance-name>] [--interactive] [--fix-drift]
+	ihub setup --interactive [-i <instance-name>]
+	ihub setup --fix-drift [-i <instance-name>]
 		-i|--instance <instance-name>      the instance name to execute command against specific instance
 		--help                             show help message for setup task
 		--force                            existing configuration will e overwritten if this flag is set
 		-f|--file <answer-file>            the answer file with required arguments
+		--interactive                      prompt for the required arguments not in the answer file or environment,
+		                                   all setup tasks are run if no task is given
+		--fix-drift                        run again the tasks whose configuration has drifted, like expired or
+		                                   reissued certificates or missing database tables, all tasks are checked
+		                                   if no task is given
 
 Available Tasks for setup:
 	all                                 Runs all setup tasks
@@ -55,6 +68,11 @@ func (app *App) printUsageWithError(err error) {
 	fmt.Fprintln(app.errorWriter(), helpStr)
 }
 
-func (app *App) printVersion() {
-	fmt.Fprintf(app.consoleWriter(), version.GetVersion())
+func (app *App) printVersion(ctx *commCmd.Context) error {
+	return ctx.Print(version.GetVersion(), commCmd.VersionInfo{
+		ServiceName: constants.ExplicitServiceName,
+		Version:     version.Version,
+		GitHash:     version.GitHash,
+		BuildDate:   version.BuildDate,
+	})
 }
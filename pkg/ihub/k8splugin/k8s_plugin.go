@@ -20,6 +20,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
 	types "github.com/intel-secl/intel-secl/v3/pkg/ihub/model"
+	"github.com/intel-secl/intel-secl/v3/pkg/ihub/status"
 	model "github.com/intel-secl/intel-secl/v3/pkg/model/k8s"
 
 	"io/ioutil"
@@ -45,6 +46,7 @@ type KubernetesDetails struct {
 	K8sClient          *k8s.Client
 	TrustedCAsStoreDir string
 	SamlCertFilePath   string
+	Status             *status.Tracker
 }
 
 var (
@@ -164,6 +166,7 @@ func FilterHostReports(k8sDetails *KubernetesDetails, hostDetails *types.HostDet
 	hostDetails.Trust = trustMap
 	hostDetails.HardwareFeatures = hardwareFeaturesMap
 	hostDetails.Trusted = overAllTrust
+	hostDetails.AttestedAt = samlReport.Subject.NotBefore
 	hostDetails.ValidTo = samlReport.Subject.NotOnOrAfter
 
 	return nil
@@ -444,12 +447,14 @@ func SendDataToEndPoint(kubernetes KubernetesDetails) error {
 				err = json.Unmarshal(platformData, &sgxData)
 				if err != nil {
 					log.WithError(err).Error("k8splugin/k8s_plugin:SendDataToEndPoint() SGX Platform data unmarshal failed")
+					kubernetes.Status.NodeFailed(hostDetails.HostName, errors.Wrap(err, "SGX Platform data unmarshal failed"))
 					continue
 				}
 
 				// need to validate contents of EpcSize
 				if !osRegexEpcSize.MatchString(sgxData[0].EpcSize) {
 					log.WithError(err).Error("k8splugin/k8s_plugin:SendDataToEndPoint() Invalid EPC Size value")
+					kubernetes.Status.NodeFailed(hostDetails.HostName, errors.New("Invalid EPC Size value"))
 					continue
 				}
 				hostDetails.EpcSize = sgxData[0].EpcSize
@@ -467,6 +472,7 @@ func SendDataToEndPoint(kubernetes KubernetesDetails) error {
 		}
 		// cannot find this host in HVS or SHVS, remove host from map
 		if hvsFail && shvsFail {
+			kubernetes.Status.NodeFailed(hostDetails.HostName, errors.New("No attestation data for the host in HVS or SHVS"))
 			delete(kubernetes.HostDetailsMap, key)
 		} else {
 			kubernetes.HostDetailsMap[key] = hostDetails
@@ -475,6 +481,13 @@ func SendDataToEndPoint(kubernetes KubernetesDetails) error {
 
 	if len(kubernetes.HostDetailsMap) > 0 {
 		err = UpdateCRD(&kubernetes)
+		for _, hostDetails := range kubernetes.HostDetailsMap {
+			if err != nil {
+				kubernetes.Status.NodeFailed(hostDetails.HostName, err)
+			} else {
+				kubernetes.Status.NodeSynced(hostDetails.HostName, hostDetails.HostID.String(), hostDetails.AttestedAt, hostDetails.ValidTo)
+			}
+		}
 		if err != nil {
 			return errors.Wrap(err, "k8splugin/k8s_plugin:SendDataToEndPoint() Error in Updating CRDs for Kubernetes")
 		}
@@ -20,6 +20,7 @@ type HostDetails struct {
 	HardwareFeatures  map[string]string
 	Trust             map[string]string
 	SignedTrustReport string
+	AttestedAt        time.Time
 	ValidTo           time.Time
 	SgxSupported      bool
 	SgxEnabled        bool
@@ -20,6 +20,7 @@ import (
 	vsPlugin "github.com/intel-secl/intel-secl/v3/pkg/ihub/attestationPlugin"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/ihub/status"
 	commonLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/saml"
 	model "github.com/intel-secl/intel-secl/v3/pkg/model/openstack"
@@ -45,6 +46,7 @@ type OpenstackDetails struct {
 	OpenstackClient    *openstackClient.Client
 	TrustedCAsStoreDir string
 	SamlCertFilePath   string
+	Status             *status.Tracker
 }
 
 var (
@@ -130,6 +132,8 @@ func filterHostReportsForOpenstack(hostDetails *openstackHostDetails, openstackD
 		if err != nil {
 			return errors.Wrap(err, "openstackplugin/openstack_plugin:filterHostReportsForOpenstack() : Error in generating custom traits from trust report")
 		}
+		hostDetails.AttestedAt = samlReport.Subject.NotBefore
+		hostDetails.ValidTo = samlReport.Subject.NotOnOrAfter
 	}
 	if openstackDetails.Config.AttestationService.SHVSBaseURL != "" {
 		platformData, err := vsPlugin.GetHostPlatformData(hostDetails.HostName, openstackDetails.Config, constants.TrustedCAsStoreDir)
@@ -597,16 +601,30 @@ func SendDataToEndPoint(openstack OpenstackDetails) error {
 	}
 
 	log.Debug("openstackplugin/openstack_plugin:SendDataToEndPoint() Filtering Hosts from Openstack")
+	filtered := make([]bool, len(openstack.HostDetails))
 	for index := range openstack.HostDetails {
 		err := filterHostReportsForOpenstack(&openstack.HostDetails[index], &openstack)
 		if err != nil {
 			log.WithError(err).Errorf("openstackplugin/openstack_plugin:SendDataToEndPoint() Error in Filtering"+
 				" Host details for Openstack host %s", openstack.HostDetails[index].HostID.String())
+			openstack.Status.NodeFailed(openstack.HostDetails[index].HostName, err)
+		} else {
+			filtered[index] = true
 		}
 	}
 
 	log.Info("openstackplugin/openstack_plugin:SendDataToEndPoint() Updating traits to Openstack for host : ", openstack.HostDetails)
 	err = updateOpenstackTraits(&openstack)
+	for index, hostDetails := range openstack.HostDetails {
+		if !filtered[index] {
+			continue
+		}
+		if err != nil {
+			openstack.Status.NodeFailed(hostDetails.HostName, err)
+		} else {
+			openstack.Status.NodeSynced(hostDetails.HostName, hostDetails.HostID.String(), hostDetails.AttestedAt, hostDetails.ValidTo)
+		}
+	}
 	if err != nil {
 		return errors.Wrap(err, "openstackplugin/openstack_plugin:SendDataToEndPoint() Error in Filtering Host details for Openstack")
 	}
@@ -26,7 +26,7 @@ func (app *App) setup(args []string) error {
 	}
 	// look for cli flags
 	var ansFile string
-	var force bool
+	var force, interactive, fixDrift bool
 	for i, flag := range args {
 		if flag == "--force" {
 			force = true
@@ -38,6 +38,17 @@ func (app *App) setup(args []string) error {
 				return errors.New("Invalid answer file name")
 			}
 		}
+		if flag == setup.InteractiveFlag {
+			interactive = true
+		}
+		if flag == setup.FixDriftFlag {
+			fixDrift = true
+		}
+	}
+	cmd := args[1]
+	// "ihub setup --interactive" and "ihub setup --fix-drift" run all the setup tasks
+	if cmd == setup.InteractiveFlag || cmd == setup.FixDriftFlag {
+		cmd = "all"
 	}
 	// dump answer file to env
 	if ansFile != "" {
@@ -46,11 +57,17 @@ func (app *App) setup(args []string) error {
 			return errors.Wrap(err, "Failed to read answer file")
 		}
 	}
+	// prompt for the settings not in the answer file
+	if interactive && !(len(args) > 2 && args[2] == "--help") {
+		err := setup.NewConsoleInteractive(app.consoleWriter()).ReadAnswersToEnv(cmd, setupPrompts())
+		if err != nil {
+			return errors.Wrap(err, "Failed to read setup settings")
+		}
+	}
 	runner, err := app.setupTaskRunner()
 	if err != nil {
 		return err
 	}
-	cmd := args[1]
 	// print help and return if applicable
 	if len(args) > 2 && args[2] == "--help" {
 		if cmd == "all" {
@@ -66,7 +83,15 @@ func (app *App) setup(args []string) error {
 		}
 		return nil
 	}
-	if cmd == "all" {
+	if fixDrift {
+		if err = runner.FixDrift(cmd); err != nil {
+			fmt.Fprintln(app.errorWriter(), "Error(s) encountered when fixing setup drift:")
+			for errCmd, failErr := range runner.FailedCommands() {
+				fmt.Fprintln(app.errorWriter(), errCmd+": "+failErr.Error())
+			}
+			return err
+		}
+	} else if cmd == "all" {
 		if err = runner.RunAll(force); err != nil {
 			errCmds := runner.FailedCommands()
 			fmt.Fprintln(app.errorWriter(), "Error(s) encountered when running all setup commands:")
@@ -169,6 +194,15 @@ func (app *App) setupTaskRunner() (*setup.Runner, error) {
 			Username: viper.GetString("ihub-service-username"),
 			Password: viper.GetString("ihub-service-password"),
 		},
+		ServerConfig: commConfig.ServerConfig{
+			Port:              viper.GetInt("server-port"),
+			ReadTimeout:       viper.GetDuration("server-read-timeout"),
+			ReadHeaderTimeout: viper.GetDuration("server-read-header-timeout"),
+			WriteTimeout:      viper.GetDuration("server-write-timeout"),
+			IdleTimeout:       viper.GetDuration("server-idle-timeout"),
+			DrainTimeout:      viper.GetDuration("server-drain-timeout"),
+			MaxHeaderBytes:    viper.GetInt("server-max-header-bytes"),
+		},
 		AASApiUrl: viper.GetString("aas-base-url"),
 		AppConfig: &app.Config,
 	})
@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ihub
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/spf13/viper"
+)
+
+// setupPrompts lists the settings prompted for by the interactive setup, in the order of the setup tasks. The
+// settings of the tenant endpoint depend on its type and are read from the answer file.
+func setupPrompts() []setup.Prompt {
+	return []setup.Prompt{
+		{EnvName: "CMS_BASE_URL", Description: "CMS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"download-ca-cert", "download-cert-tls"}},
+		{EnvName: "CMS_TLS_CERT_SHA384", Description: "SHA384 digest of the CMS TLS certificate",
+			Validate: setup.ValidateCertDigest, Tasks: []string{"download-ca-cert"}},
+		{EnvName: "BEARER_TOKEN", Description: "Installation bearer token", Secret: true,
+			Validate: validation.ValidateJWT, Tasks: []string{"download-cert-tls"}},
+		{EnvName: "SAN_LIST", Description: "Subject alternative names of the TLS certificate",
+			Default: viper.GetString("tls-san-list"), Tasks: []string{"download-cert-tls"}},
+		{EnvName: "HVS_BASE_URL", Description: "HVS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"attestation-service-connection", "download-saml-cert"}},
+		{EnvName: "TENANT", Description: "Tenant type (OPENSTACK or KUBERNETES)",
+			Tasks: []string{"tenant-service-connection"}},
+		{EnvName: "AAS_API_URL", Description: "AAS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"update-service-config"}},
+		{EnvName: "IHUB_SERVICE_USERNAME", Description: "IHUB service username",
+			Validate: validation.ValidateUserNameString, Tasks: []string{"update-service-config"}},
+		{EnvName: "IHUB_SERVICE_PASSWORD", Description: "IHUB service password", Secret: true,
+			Tasks: []string{"update-service-config"}},
+	}
+}
@@ -5,12 +5,15 @@
 package ihub
 
 import (
+	"context"
 	"encoding/pem"
+	"fmt"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/k8s"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/openstack"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
@@ -20,6 +23,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/k8splugin"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/openstackplugin"
+	"github.com/intel-secl/intel-secl/v3/pkg/ihub/status"
 	"github.com/pkg/errors"
 
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
@@ -38,6 +42,8 @@ func (app *App) startDaemon() error {
 		return errors.New("Failed to load configuration")
 	}
 	app.configureLogs(configuration.Log.EnableStdout, true)
+	crypt.SetFipsMode(configuration.FipsMode)
+	log.Infof("startService:startDaemon() FIPS mode enabled: %t", crypt.FipsModeEnabled())
 
 	if configuration.PollIntervalMinutes < constants.PollingIntervalMinutes {
 		secLog.Infof("startService:startDaemon() POLL_INTERVAL_MINUTES value is less than %v mins. Setting it to "+
@@ -143,10 +149,34 @@ func (app *App) startDaemon() error {
 		return errors.Errorf("startService:startDaemon() Endpoint type '%s' is not supported", configuration.Endpoint.Type)
 	}
 
+	syncStatus := status.NewTracker(configuration.Endpoint.Type)
+	k.Status = syncStatus
+	o.Status = syncStatus
+
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
+	var httpServer *http.Server
+	if configuration.Server.Port > 0 {
+		httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", configuration.Server.Port),
+			Handler:           status.NewHandler(syncStatus),
+			TLSConfig:         crypt.TLSConfig(),
+			ReadTimeout:       configuration.Server.ReadTimeout,
+			ReadHeaderTimeout: configuration.Server.ReadHeaderTimeout,
+			WriteTimeout:      configuration.Server.WriteTimeout,
+			IdleTimeout:       configuration.Server.IdleTimeout,
+			MaxHeaderBytes:    configuration.Server.MaxHeaderBytes,
+		}
+		go func() {
+			if err := httpServer.ListenAndServeTLS(configuration.TLS.CertFile, configuration.TLS.KeyFile); err != nil && err != http.ErrServerClosed {
+				log.WithError(err).Error("startService:startDaemon() Failed to start the status API server")
+				stop <- syscall.SIGTERM
+			}
+		}()
+	}
+
 	// invoke for the first time before scheduling regular runs
 	app.kickOffPlugins(k, o)
 
@@ -165,6 +195,19 @@ func (app *App) startDaemon() error {
 	<-stop
 	tick.Stop()
 
+	if httpServer != nil {
+		// the status requests in progress are drained within the drain timeout
+		drainTimeout := configuration.Server.DrainTimeout
+		if drainTimeout <= 0 {
+			drainTimeout = constants.DefaultDrainTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			log.WithError(err).Error("startService:startDaemon() Failed to gracefully shutdown the status API server")
+		}
+	}
+
 	secLog.Info(commLogMsg.ServiceStop)
 	return nil
 }
@@ -178,11 +221,12 @@ func (app *App) kickOffPlugins(k k8splugin.KubernetesDetails, o openstackplugin.
 		if err != nil {
 			log.WithError(err).Error("startService:kickOffPlugins() Error in pushing OpenStack traits")
 		}
+		o.Status.SyncCompleted(err)
 	} else {
 		err := k8splugin.SendDataToEndPoint(k)
 		if err != nil {
 			log.WithError(err).Error("startService:kickOffPlugins() : Error in pushing Kubernetes CRDs")
 		}
-
+		k.Status.SyncCompleted(err)
 	}
 }
@@ -0,0 +1,39 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	commonLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+)
+
+var log = commonLog.GetDefaultLogger()
+
+// Routes of the status API
+const (
+	StatusPath  = "/ihub/v1/status"
+	MetricsPath = "/ihub/v1/metrics"
+)
+
+// NewHandler returns the handler of the status API and of the Prometheus metrics
+func NewHandler(tracker *Tracker) http.Handler {
+	router := mux.NewRouter()
+	router.HandleFunc(StatusPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(tracker.Status()); err != nil {
+			log.WithError(err).Error("status/handler:NewHandler() Error writing the sync status")
+		}
+	}).Methods(http.MethodGet)
+	router.HandleFunc(MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MetricsContentType)
+		if err := tracker.WriteMetrics(w); err != nil {
+			log.WithError(err).Error("status/handler:NewHandler() Error writing the metrics")
+		}
+	}).Methods(http.MethodGet)
+	return router
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MetricsContentType is the content type of the Prometheus text exposition format
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	samples []string
+}
+
+func (m *metric) add(labels string, value float64) {
+	m.samples = append(m.samples, fmt.Sprintf("%s{%s} %g", m.name, labels, value))
+}
+
+func timestamp(t *time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// WriteMetrics writes the sync status in the Prometheus text exposition format. The timestamps of the syncs that
+// never happened are not reported so that absent() can be alerted on.
+func (t *Tracker) WriteMetrics(w io.Writer) error {
+	status := t.Status()
+	now := t.now()
+
+	endpointLabel := fmt.Sprintf(`endpoint="%s"`, labelEscaper.Replace(status.Endpoint))
+	lastSuccess := &metric{name: "ihub_last_successful_sync_timestamp_seconds", kind: "gauge",
+		help: "Time of the last successful push of the attestation data to the orchestrator"}
+	pushErrors := &metric{name: "ihub_push_errors_total", kind: "counter",
+		help: "Number of failed pushes of the attestation data to the orchestrator"}
+	nodeLastSuccess := &metric{name: "ihub_node_last_successful_sync_timestamp_seconds", kind: "gauge",
+		help: "Time of the last successful push of the attestation data of the node"}
+	nodeAttestationAge := &metric{name: "ihub_node_attestation_age_seconds", kind: "gauge",
+		help: "Age of the attestation data of the node at the time of the scrape"}
+	nodeValidTo := &metric{name: "ihub_node_attestation_valid_to_timestamp_seconds", kind: "gauge",
+		help: "Time until which the attestation data of the node is valid"}
+	nodeErrors := &metric{name: "ihub_node_sync_errors_total", kind: "counter",
+		help: "Number of failed syncs of the attestation data of the node"}
+
+	if status.LastSuccessfulSync != nil {
+		lastSuccess.add(endpointLabel, timestamp(status.LastSuccessfulSync))
+	}
+	pushErrors.add(endpointLabel, float64(status.PushErrors))
+	for _, node := range status.Nodes {
+		labels := fmt.Sprintf(`%s,node="%s"`, endpointLabel, labelEscaper.Replace(node.Name))
+		if node.LastSuccessfulSync != nil {
+			nodeLastSuccess.add(labels, timestamp(node.LastSuccessfulSync))
+		}
+		if node.AttestedAt != nil {
+			nodeAttestationAge.add(labels, now.Sub(*node.AttestedAt).Seconds())
+		}
+		if node.AttestationValidTo != nil {
+			nodeValidTo.add(labels, timestamp(node.AttestationValidTo))
+		}
+		nodeErrors.add(labels, float64(node.SyncErrors))
+	}
+
+	for _, m := range []*metric{lastSuccess, pushErrors, nodeLastSuccess, nodeAttestationAge, nodeValidTo, nodeErrors} {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, sample := range m.samples {
+			if _, err := fmt.Fprintln(w, sample); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package status
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// NodeStatus is the sync status of a node of the orchestrator
+type NodeStatus struct {
+	Name               string     `json:"name"`
+	HostID             string     `json:"host_id,omitempty"`
+	LastSync           *time.Time `json:"last_sync,omitempty"`
+	LastSuccessfulSync *time.Time `json:"last_successful_sync,omitempty"`
+	AttestedAt         *time.Time `json:"attested_at,omitempty"`
+	AttestationValidTo *time.Time `json:"attestation_valid_to,omitempty"`
+	SyncErrors         uint64     `json:"sync_errors"`
+	LastError          string     `json:"last_error,omitempty"`
+}
+
+// SyncStatus is the sync status of the orchestrator and of its nodes
+type SyncStatus struct {
+	Endpoint           string       `json:"endpoint"`
+	LastSync           *time.Time   `json:"last_sync,omitempty"`
+	LastSuccessfulSync *time.Time   `json:"last_successful_sync,omitempty"`
+	PushErrors         uint64       `json:"push_errors"`
+	LastError          string       `json:"last_error,omitempty"`
+	Nodes              []NodeStatus `json:"nodes"`
+}
+
+// Tracker records the outcome of the syncs with the orchestrator. A nil Tracker records nothing so that the plugins
+// can be run without one.
+type Tracker struct {
+	mu     sync.Mutex
+	status SyncStatus
+	nodes  map[string]*NodeStatus
+	now    func() time.Time
+}
+
+// NewTracker returns a Tracker for the orchestrator endpoint type
+func NewTracker(endpoint string) *Tracker {
+	return &Tracker{
+		status: SyncStatus{Endpoint: endpoint},
+		nodes:  make(map[string]*NodeStatus),
+		now:    time.Now,
+	}
+}
+
+func (t *Tracker) node(name string) *NodeStatus {
+	node, ok := t.nodes[name]
+	if !ok {
+		node = &NodeStatus{Name: name}
+		t.nodes[name] = node
+	}
+	return node
+}
+
+// NodeSynced records that the attestation data of the node was pushed to the orchestrator, attestedAt is zero when
+// the attestation service does not report when the data was created
+func (t *Tracker) NodeSynced(name, hostID string, attestedAt, validTo time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	node := t.node(name)
+	node.HostID = hostID
+	node.LastSync = &now
+	node.LastSuccessfulSync = &now
+	node.AttestedAt = nil
+	if !attestedAt.IsZero() {
+		node.AttestedAt = &attestedAt
+	}
+	node.AttestationValidTo = nil
+	if !validTo.IsZero() {
+		node.AttestationValidTo = &validTo
+	}
+	node.LastError = ""
+}
+
+// NodeFailed records that the attestation data of the node could not be fetched or pushed
+func (t *Tracker) NodeFailed(name string, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	node := t.node(name)
+	node.LastSync = &now
+	node.SyncErrors++
+	if err != nil {
+		node.LastError = err.Error()
+	}
+}
+
+// SyncCompleted records the outcome of a sync with the orchestrator
+func (t *Tracker) SyncCompleted(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.status.LastSync = &now
+	if err != nil {
+		t.status.PushErrors++
+		t.status.LastError = err.Error()
+		return
+	}
+	t.status.LastSuccessfulSync = &now
+	t.status.LastError = ""
+}
+
+// Status returns a snapshot of the sync status, the nodes are sorted by name
+func (t *Tracker) Status() SyncStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status := t.status
+	status.Nodes = make([]NodeStatus, 0, len(t.nodes))
+	for _, node := range t.nodes {
+		status.Nodes = append(status.Nodes, *node)
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool {
+		return status.Nodes[i].Name < status.Nodes[j].Name
+	})
+	return status
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracker(now time.Time) *Tracker {
+	tracker := NewTracker("KUBERNETES")
+	tracker.now = func() time.Time { return now }
+	return tracker
+}
+
+func TestTrackerStatus(t *testing.T) {
+	now := time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(now)
+
+	tracker.NodeSynced("worker-2", "ef3e4f1c-8b3c-4e3b-8d2a-1f6c0f0a6a51", now.Add(-time.Hour), now.Add(time.Hour))
+	tracker.NodeFailed("worker-1", errors.New("host not found"))
+	tracker.SyncCompleted(nil)
+
+	status := tracker.Status()
+	assert.Equal(t, "KUBERNETES", status.Endpoint)
+	assert.Equal(t, now, *status.LastSuccessfulSync)
+	assert.Len(t, status.Nodes, 2)
+	assert.Equal(t, "worker-1", status.Nodes[0].Name)
+	assert.Nil(t, status.Nodes[0].LastSuccessfulSync)
+	assert.Equal(t, uint64(1), status.Nodes[0].SyncErrors)
+	assert.Equal(t, "host not found", status.Nodes[0].LastError)
+	assert.Equal(t, now, *status.Nodes[1].LastSuccessfulSync)
+
+	// a failed push keeps the time of the last successful one
+	tracker.SyncCompleted(errors.New("connection refused"))
+	status = tracker.Status()
+	assert.Equal(t, now, *status.LastSuccessfulSync)
+	assert.Equal(t, uint64(1), status.PushErrors)
+	assert.Equal(t, "connection refused", status.LastError)
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	tracker.NodeSynced("worker-1", "", time.Now(), time.Now())
+	tracker.NodeFailed("worker-1", nil)
+	tracker.SyncCompleted(nil)
+}
+
+func TestWriteMetrics(t *testing.T) {
+	now := time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(now)
+	tracker.NodeSynced("worker-1", "", now.Add(-90*time.Second), time.Time{})
+	tracker.NodeFailed(`worker-"2"`, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tracker.WriteMetrics(&buf))
+	metrics := buf.String()
+	assert.Contains(t, metrics, "# TYPE ihub_push_errors_total counter\n")
+	assert.Contains(t, metrics, `ihub_push_errors_total{endpoint="KUBERNETES"} 0`)
+	assert.NotContains(t, metrics, "ihub_last_successful_sync_timestamp_seconds{")
+	assert.Contains(t, metrics, `ihub_node_attestation_age_seconds{endpoint="KUBERNETES",node="worker-1"} 90`)
+	assert.Contains(t, metrics, `ihub_node_sync_errors_total{endpoint="KUBERNETES",node="worker-\"2\""} 1`)
+}
+
+func TestStatusHandler(t *testing.T) {
+	tracker := NewTracker("OPENSTACK")
+	tracker.SyncCompleted(nil)
+	handler := NewHandler(tracker)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, StatusPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var status SyncStatus
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, "OPENSTACK", status.Endpoint)
+	assert.NotNil(t, status.LastSuccessfulSync)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, MetricsContentType, recorder.Header().Get("Content-Type"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, StatusPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -18,6 +18,7 @@ import (
 
 type UpdateServiceConfig struct {
 	ServiceConfig commConfig.ServiceConfig
+	ServerConfig  commConfig.ServerConfig
 	AASApiUrl     string
 	AppConfig     **config.Configuration
 	ConsoleWriter io.Writer
@@ -32,6 +33,7 @@ var envHelp = map[string]string{
 	"LOG_MAX_LENGTH":    "Max length of log statement",
 	"LOG_ENABLE_STDOUT": "Enable console log",
 	"AAS_BASE_URL":      "AAS Base URL",
+	"SERVER_PORT":       "The port of the status API, 0 disables it",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -53,6 +55,7 @@ func (uc UpdateServiceConfig) Run() error {
 	}
 
 	(*uc.AppConfig).IHUB = uc.ServiceConfig
+	(*uc.AppConfig).Server = uc.ServerConfig
 	(*uc.AppConfig).AASApiUrl = uc.AASApiUrl
 	(*uc.AppConfig).Log = commConfig.LogConfig{
 		MaxLength:    viper.GetInt("log-max-length"),
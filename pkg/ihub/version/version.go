@@ -7,6 +7,7 @@ package version
 import (
 	"fmt"
 	"github.com/intel-secl/intel-secl/v3/pkg/ihub/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 )
 
 // Automatically filled in by linker
@@ -24,5 +25,13 @@ func GetVersion() string {
 	verStr := fmt.Sprintf("Service Name: %s\n", constants.ExplicitServiceName)
 	verStr = verStr + fmt.Sprintf("Version: %s-%s\n", Version, GitHash)
 	verStr = verStr + fmt.Sprintf("Build Date: %s\n", BuildDate)
+	verStr = verStr + fmt.Sprintf("FIPS Mode: %s\n", fipsStatus())
 	return verStr
 }
+
+func fipsStatus() string {
+	if crypt.FipsModeEnabled() {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -11,18 +11,15 @@ import (
 	"os/exec"
 
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
-	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	commLogInt "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/setup"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-var errInvalidCmd = errors.New("Invalid input after command")
-
 type App struct {
 	HomeDir        string
 	ConfigDir      string
@@ -46,66 +43,12 @@ func (app *App) Run(args []string) error {
 			defaultLog.Errorf("Panic occurred: %+v", err)
 		}
 	}()
-	if len(args) < 2 {
-		err := errors.New("Invalid usage of " + constants.ServiceName)
-		app.printUsageWithError(err)
-		return err
+	runner := &commCmd.Runner{
+		Root:          app.commands(),
+		ConsoleWriter: app.consoleWriter(),
+		PrintUsage:    app.printUsageWithError,
 	}
-	cmd := args[1]
-	switch cmd {
-	default:
-		err := errors.New("Invalid command: " + cmd)
-		app.printUsageWithError(err)
-		return err
-	case "help", "-h", "--help":
-		app.printUsage()
-		return nil
-	case "version", "-v", "--version":
-		app.printVersion()
-		return nil
-	case "run":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return app.startServer()
-	case "start":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return app.start()
-	case "stop":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return app.stop()
-	case "status":
-		if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return app.status()
-	case "uninstall":
-		// the only allowed flag is --purge
-		purge := false
-		if len(args) == 3 {
-			if args[2] != "--purge" {
-				return errors.New("Invalid flag: " + args[2])
-			}
-			purge = true
-		} else if len(args) != 2 {
-			return errInvalidCmd
-		}
-		return app.uninstall(purge)
-	case "setup":
-		if err := app.setup(args[1:]); err != nil {
-			if errors.Cause(err) == setup.ErrTaskNotFound {
-				app.printUsageWithError(err)
-			} else {
-				fmt.Fprintln(app.errorWriter(), err.Error())
-			}
-			return err
-		}
-	}
-	return nil
+	return runner.Run(args)
 }
 
 func (app *App) consoleWriter() io.Writer {
@@ -166,7 +109,9 @@ func (app *App) configureLogs(stdOut, logFile bool) error {
 			ioWriterDefault = os.Stdout
 		}
 	}
-	ioWriterSecurity := io.MultiWriter(ioWriterDefault, app.secLogWriter())
+	// the entries are sanitized so that user controlled values cannot forge entries and secrets are not logged
+	ioWriterSecurity := commLog.NewSanitizingWriter(io.MultiWriter(ioWriterDefault, app.secLogWriter()))
+	ioWriterDefault = commLog.NewSanitizingWriter(ioWriterDefault)
 
 	logConfig := app.Config.Log
 	lv, err := logrus.ParseLevel(logConfig.Level)
@@ -208,7 +153,14 @@ func (app *App) stop() error {
 	return cmd.Run()
 }
 
-func (app *App) status() error {
+func (app *App) status(ctx *commCmd.Context) error {
+	if ctx.JSONOutput() {
+		status, err := commCmd.GetServiceStatus("kbs")
+		if err != nil {
+			return err
+		}
+		return ctx.Print("", status)
+	}
 	fmt.Fprintln(app.consoleWriter(), `Forwarding to "systemctl status kbs"`)
 	systemctl, err := exec.LookPath("systemctl")
 	if err != nil {
@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cloudkms
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	awsMetadataURL     = "http://169.254.169.254"
+	awsKmsService      = "kms"
+	awsSigningAlg      = "AWS4-HMAC-SHA256"
+	awsAmzDateFormat   = "20060102T150405Z"
+	awsKmsContentType  = "application/x-amz-json-1.1"
+	awsMetadataTTL     = "21600"
+	awsEncryptTarget   = "TrentService.Encrypt"
+	awsDecryptTarget   = "TrentService.Decrypt"
+	awsCredentialsPath = "/latest/meta-data/iam/security-credentials/"
+)
+
+type awsCredentials struct {
+	AccessKeyId     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// awsKekProvider wraps the keys with the Encrypt and Decrypt operations of AWS KMS, signed with the credentials of
+// the IAM role of the EC2 instance retrieved from the instance metadata service (IMDSv2)
+type awsKekProvider struct {
+	keyId       string
+	region      string
+	endpoint    string
+	metadataURL string
+	client      *http.Client
+	credentials roleToken
+}
+
+func newAwsKekProvider(cfg *config.CloudKmsConfig, client *http.Client, metadataURL string) *awsKekProvider {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", cfg.Region)
+	}
+	return &awsKekProvider{
+		keyId:       cfg.KeyID,
+		region:      cfg.Region,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		metadataURL: metadataURL,
+		client:      client,
+	}
+}
+
+func (ap *awsKekProvider) WrapKey(key []byte) (string, []byte, error) {
+	defaultLog.Trace("cloudkms/aws:WrapKey() Entering")
+	defer defaultLog.Trace("cloudkms/aws:WrapKey() Leaving")
+
+	var resp struct {
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+		KeyId          string `json:"KeyId"`
+	}
+	err := ap.call(awsEncryptTarget, map[string]interface{}{"KeyId": ap.keyId, "Plaintext": key}, &resp)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "cloudkms/aws:WrapKey() Failed to encrypt key with AWS KMS")
+	}
+	return resp.KeyId, resp.CiphertextBlob, nil
+}
+
+func (ap *awsKekProvider) UnwrapKey(kekId string, wrappedKey []byte) ([]byte, error) {
+	defaultLog.Trace("cloudkms/aws:UnwrapKey() Entering")
+	defer defaultLog.Trace("cloudkms/aws:UnwrapKey() Leaving")
+
+	var resp struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	err := ap.call(awsDecryptTarget, map[string]interface{}{"KeyId": kekId, "CiphertextBlob": wrappedKey}, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "cloudkms/aws:UnwrapKey() Failed to decrypt key with AWS KMS")
+	}
+	return resp.Plaintext, nil
+}
+
+// call sends the request of the KMS operation signed with signature version 4
+func (ap *awsKekProvider) call(target string, body interface{}, out interface{}) error {
+	cred, err := ap.credentials.get(ap.getRoleCredentials)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get the credentials of the instance role")
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal request body")
+	}
+	req, err := http.NewRequest(http.MethodPost, ap.endpoint+"/", bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "Failed to create request")
+	}
+	req.Header.Set("Content-Type", awsKmsContentType)
+	req.Header.Set("X-Amz-Target", target)
+	signAwsRequest(req, reqBody, cred.(*awsCredentials), ap.region, time.Now().UTC())
+
+	_, err = sendRequest(ap.client, req, out)
+	return err
+}
+
+// getRoleCredentials returns the temporary credentials of the IAM role attached to the instance
+func (ap *awsKekProvider) getRoleCredentials() (interface{}, time.Time, error) {
+	req, err := http.NewRequest(http.MethodPut, ap.metadataURL+"/latest/api/token", nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to create metadata token request")
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", awsMetadataTTL)
+	token, err := sendRequest(ap.client, req, nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to get metadata token")
+	}
+
+	req, err = http.NewRequest(http.MethodGet, ap.metadataURL+awsCredentialsPath, nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to create role request")
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+	roles, err := sendRequest(ap.client, req, nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to get the role of the instance")
+	}
+	role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+	if role == "" {
+		return nil, time.Time{}, errors.New("No IAM role is attached to the instance")
+	}
+
+	req, err = http.NewRequest(http.MethodGet, ap.metadataURL+awsCredentialsPath+role, nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to create credentials request")
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+	var cred awsCredentials
+	if _, err = sendRequest(ap.client, req, &cred); err != nil {
+		return nil, time.Time{}, errors.Wrapf(err, "Failed to get the credentials of role %s", role)
+	}
+	return &cred, cred.Expiration, nil
+}
+
+// signAwsRequest adds the signature version 4 authorization header of the request to the service
+func signAwsRequest(req *http.Request, body []byte, cred *awsCredentials, region string, now time.Time) {
+	amzDate := now.Format(awsAmzDateFormat)
+	date := amzDate[:8]
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if cred.Token != "" {
+		req.Header.Set("X-Amz-Security-Token", cred.Token)
+	}
+
+	signedHeaders := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	headerValues := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	if cred.Token != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+		headerValues["x-amz-security-token"] = cred.Token
+	}
+	// the signed headers are in the alphabetical order of their lowercase names
+	sort.Strings(signedHeaders)
+	var canonicalHeaders strings.Builder
+	for _, header := range signedHeaders {
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(headerValues[header]) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{req.Method, "/", "", canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"), hex.EncodeToString(payloadHash[:])}, "\n")
+
+	scope := strings.Join([]string{date, region, awsKmsService, "aws4_request"}, "/")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{awsSigningAlg, amzDate, scope, hex.EncodeToString(canonicalRequestHash[:])}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+cred.SecretAccessKey), date)
+	for _, part := range []string{region, awsKmsService, "aws4_request"} {
+		signingKey = hmacSha256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlg, cred.AccessKeyId, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cloudkms
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	azureMetadataURL      = "http://169.254.169.254"
+	azureTokenPath        = "/metadata/identity/oauth2/token"
+	azureMetadataVersion  = "2018-02-01"
+	azureKeyVaultVersion  = "7.2"
+	azureKeyVaultResource = "https://vault.azure.net"
+	azureWrapAlgorithm    = "RSA-OAEP-256"
+)
+
+// azureKekProvider wraps the keys with the wrapkey and unwrapkey operations of an Azure Key Vault RSA key, with the
+// access token of the managed identity of the virtual machine retrieved from the instance metadata service
+type azureKekProvider struct {
+	keyURL      string
+	metadataURL string
+	client      *http.Client
+	token       roleToken
+}
+
+func newAzureKekProvider(cfg *config.CloudKmsConfig, client *http.Client, metadataURL string) *azureKekProvider {
+	keyURL := strings.TrimSuffix(cfg.KeyID, "/")
+	// the key vault of a private endpoint has another host name than the public one
+	if cfg.Endpoint != "" {
+		if parsed, err := url.Parse(keyURL); err == nil {
+			keyURL = strings.TrimSuffix(cfg.Endpoint, "/") + parsed.Path
+		}
+	}
+	return &azureKekProvider{
+		keyURL:      keyURL,
+		metadataURL: metadataURL,
+		client:      client,
+	}
+}
+
+type azureKeyOperation struct {
+	Kid       string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Value     string `json:"value"`
+}
+
+func (zp *azureKekProvider) WrapKey(key []byte) (string, []byte, error) {
+	defaultLog.Trace("cloudkms/azure:WrapKey() Entering")
+	defer defaultLog.Trace("cloudkms/azure:WrapKey() Leaving")
+
+	resp, err := zp.call(zp.keyURL+"/wrapkey", key)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "cloudkms/azure:WrapKey() Failed to wrap key with Azure Key Vault")
+	}
+	wrappedKey, err := base64.RawURLEncoding.DecodeString(resp.Value)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "cloudkms/azure:WrapKey() Failed to decode wrapped key")
+	}
+	// the kid of the response is the key version the key is wrapped with
+	return resp.Kid, wrappedKey, nil
+}
+
+func (zp *azureKekProvider) UnwrapKey(kekId string, wrappedKey []byte) ([]byte, error) {
+	defaultLog.Trace("cloudkms/azure:UnwrapKey() Entering")
+	defer defaultLog.Trace("cloudkms/azure:UnwrapKey() Leaving")
+
+	// the key version is unwrapped through the configured vault host, which is the private endpoint if any
+	kekURL, err := url.Parse(kekId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cloudkms/azure:UnwrapKey() Invalid key id %s", kekId)
+	}
+	keyURL, err := url.Parse(zp.keyURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cloudkms/azure:UnwrapKey() Invalid key URL %s", zp.keyURL)
+	}
+	kekURL.Scheme, kekURL.Host = keyURL.Scheme, keyURL.Host
+	resp, err := zp.call(strings.TrimSuffix(kekURL.String(), "/")+"/unwrapkey", wrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "cloudkms/azure:UnwrapKey() Failed to unwrap key with Azure Key Vault")
+	}
+	key, err := base64.RawURLEncoding.DecodeString(resp.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "cloudkms/azure:UnwrapKey() Failed to decode unwrapped key")
+	}
+	return key, nil
+}
+
+func (zp *azureKekProvider) call(operationURL string, value []byte) (*azureKeyOperation, error) {
+	token, err := zp.token.get(zp.getIdentityToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get the access token of the managed identity")
+	}
+	req, err := newJsonRequest(http.MethodPost, operationURL+"?api-version="+azureKeyVaultVersion, azureKeyOperation{
+		Algorithm: azureWrapAlgorithm,
+		Value:     base64.RawURLEncoding.EncodeToString(value),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.(string))
+
+	var resp azureKeyOperation
+	if _, err = sendRequest(zp.client, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// getIdentityToken returns the access token of the managed identity of the virtual machine for Azure Key Vault
+func (zp *azureKekProvider) getIdentityToken() (interface{}, time.Time, error) {
+	query := url.Values{}
+	query.Set("api-version", azureMetadataVersion)
+	query.Set("resource", azureKeyVaultResource)
+	req, err := http.NewRequest(http.MethodGet, zp.metadataURL+azureTokenPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to create token request")
+	}
+	req.Header.Set("Metadata", "true")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if _, err = sendRequest(zp.client, req, &resp); err != nil {
+		return nil, time.Time{}, err
+	}
+	expiresOn, err := strconv.ParseInt(resp.ExpiresOn, 10, 64)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to parse the expiry of the access token")
+	}
+	return resp.AccessToken, time.Unix(expiresOn, 0), nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cloudkms
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = log.GetDefaultLogger()
+
+// tokenExpiryMargin is how long before their expiry the role credentials are refreshed
+const tokenExpiryMargin = 5 * time.Minute
+
+// KekProvider wraps the data encryption keys of KBS with a key encryption key (KEK) kept in a cloud KMS,
+// the KEK never leaves the KMS. WrapKey returns the identifier of the KEK version to unwrap the key with.
+type KekProvider interface {
+	WrapKey(key []byte) (string, []byte, error)
+	UnwrapKey(kekId string, wrappedKey []byte) ([]byte, error)
+}
+
+// NewKekProvider returns the provider of the cloud KMS selected in the configuration, authenticated with the role
+// of the instance KBS runs on
+func NewKekProvider(cfg *config.CloudKmsConfig) (KekProvider, error) {
+	defaultLog.Trace("cloudkms/client:NewKekProvider() Entering")
+	defer defaultLog.Trace("cloudkms/client:NewKekProvider() Leaving")
+
+	if cfg.KeyID == "" {
+		return nil, errors.New("cloudkms/client:NewKekProvider() The key id of the cloud KMS is not configured")
+	}
+
+	httpClient := &http.Client{Timeout: constants.CloudKmsTimeout}
+	switch strings.ToLower(cfg.Provider) {
+	case constants.AwsKmsProvider:
+		if cfg.Region == "" {
+			return nil, errors.New("cloudkms/client:NewKekProvider() The region of AWS KMS is not configured")
+		}
+		return newAwsKekProvider(cfg, httpClient, awsMetadataURL), nil
+	case constants.AzureKmsProvider:
+		return newAzureKekProvider(cfg, httpClient, azureMetadataURL), nil
+	case constants.GcpKmsProvider:
+		return newGcpKekProvider(cfg, httpClient, gcpMetadataURL), nil
+	default:
+		return nil, errors.Errorf("cloudkms/client:NewKekProvider() Unsupported cloud KMS provider %s", cfg.Provider)
+	}
+}
+
+// roleToken caches the credentials of the instance role until they are about to expire
+type roleToken struct {
+	mutex     sync.Mutex
+	value     interface{}
+	expiresAt time.Time
+}
+
+func (rt *roleToken) get(refresh func() (interface{}, time.Time, error)) (interface{}, error) {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	if rt.value != nil && time.Now().Add(tokenExpiryMargin).Before(rt.expiresAt) {
+		return rt.value, nil
+	}
+	value, expiresAt, err := refresh()
+	if err != nil {
+		return nil, err
+	}
+	rt.value = value
+	rt.expiresAt = expiresAt
+	return value, nil
+}
+
+// newJsonRequest returns a request with the body marshalled to JSON, the request has no body if body is nil
+func newJsonRequest(method, url string, body interface{}) (*http.Request, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to marshal request body")
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create request")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// sendRequest sends the request and unmarshals the JSON response body into out, unless out is nil in which case
+// the raw response body is returned
+func sendRequest(client *http.Client, req *http.Request, out interface{}) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to send request to %s", req.URL.Host)
+	}
+	defer func() {
+		derr := resp.Body.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing response body")
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read response body")
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, errors.Errorf("Request to %s failed with HTTP Status :%d %s", req.URL.Host, resp.StatusCode, string(body))
+	}
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal response body")
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cloudkms
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// reverse stands for the KEK of the fake cloud KMS servers
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestAwsKekProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	metadataCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/latest/api/token":
+			assert.Equal(http.MethodPut, r.Method)
+			metadataCalls++
+			fmt.Fprint(w, "imds-token")
+		case r.URL.Path == awsCredentialsPath:
+			assert.Equal("imds-token", r.Header.Get("X-aws-ec2-metadata-token"))
+			fmt.Fprint(w, "kbs-role")
+		case r.URL.Path == awsCredentialsPath+"kbs-role":
+			json.NewEncoder(w).Encode(awsCredentials{AccessKeyId: "AKID", SecretAccessKey: "secret",
+				Token: "session", Expiration: time.Now().Add(time.Hour)})
+		default:
+			assert.Contains(r.Header.Get("Authorization"), "Credential=AKID/")
+			assert.Contains(r.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target")
+			assert.Equal("session", r.Header.Get("X-Amz-Security-Token"))
+			var req map[string][]byte
+			json.NewDecoder(r.Body).Decode(&req)
+			if r.Header.Get("X-Amz-Target") == awsEncryptTarget {
+				json.NewEncoder(w).Encode(map[string]interface{}{"CiphertextBlob": reverse(req["Plaintext"]), "KeyId": "arn:key/1"})
+			} else {
+				json.NewEncoder(w).Encode(map[string]interface{}{"Plaintext": reverse(req["CiphertextBlob"])})
+			}
+		}
+	}))
+	defer server.Close()
+
+	provider := newAwsKekProvider(&config.CloudKmsConfig{KeyID: "alias/kbs", Region: "us-west-2", Endpoint: server.URL},
+		server.Client(), server.URL)
+	kekId, wrappedKey, err := provider.WrapKey([]byte("dek"))
+	assert.NoError(err)
+	assert.Equal("arn:key/1", kekId)
+	assert.Equal([]byte("ked"), wrappedKey)
+
+	key, err := provider.UnwrapKey(kekId, wrappedKey)
+	assert.NoError(err)
+	assert.Equal([]byte("dek"), key)
+	// the role credentials are cached until they expire
+	assert.Equal(1, metadataCalls)
+}
+
+func TestAzureKekProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == azureTokenPath {
+			assert.Equal("true", r.Header.Get("Metadata"))
+			assert.Equal(azureKeyVaultResource, r.URL.Query().Get("resource"))
+			expiresOn := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+			json.NewEncoder(w).Encode(map[string]string{"access_token": "identity-token", "expires_on": expiresOn})
+			return
+		}
+		assert.Equal("Bearer identity-token", r.Header.Get("Authorization"))
+		var req azureKeyOperation
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(azureWrapAlgorithm, req.Algorithm)
+		value, _ := base64.RawURLEncoding.DecodeString(req.Value)
+		resp := azureKeyOperation{Value: base64.RawURLEncoding.EncodeToString(reverse(value))}
+		if strings.HasSuffix(r.URL.Path, "/wrapkey") {
+			assert.Equal("/keys/kbs-kek/wrapkey", r.URL.Path)
+			resp.Kid = "https://kbs.vault.azure.net/keys/kbs-kek/v1"
+		} else {
+			// the key version is unwrapped through the configured endpoint
+			assert.Equal("/keys/kbs-kek/v1/unwrapkey", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := newAzureKekProvider(&config.CloudKmsConfig{KeyID: "https://kbs.vault.azure.net/keys/kbs-kek", Endpoint: server.URL},
+		server.Client(), server.URL)
+	kekId, wrappedKey, err := provider.WrapKey([]byte("dek"))
+	assert.NoError(err)
+	assert.Equal("https://kbs.vault.azure.net/keys/kbs-kek/v1", kekId)
+
+	key, err := provider.UnwrapKey(kekId, wrappedKey)
+	assert.NoError(err)
+	assert.Equal([]byte("dek"), key)
+}
+
+func TestGcpKekProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	keyName := "projects/p/locations/global/keyRings/kbs/cryptoKeys/kek"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == gcpTokenPath {
+			assert.Equal("Google", r.Header.Get("Metadata-Flavor"))
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "sa-token", "expires_in": 3600})
+			return
+		}
+		assert.Equal("Bearer sa-token", r.Header.Get("Authorization"))
+		var req map[string][]byte
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/v1/" + keyName + ":encrypt":
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": keyName + "/cryptoKeyVersions/3", "ciphertext": reverse(req["plaintext"])})
+		case "/v1/" + keyName + ":decrypt":
+			json.NewEncoder(w).Encode(map[string]interface{}{"plaintext": reverse(req["ciphertext"])})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := newGcpKekProvider(&config.CloudKmsConfig{KeyID: keyName, Endpoint: server.URL}, server.Client(), server.URL)
+	kekId, wrappedKey, err := provider.WrapKey([]byte("dek"))
+	assert.NoError(err)
+	assert.Equal(keyName+"/cryptoKeyVersions/3", kekId)
+
+	key, err := provider.UnwrapKey(kekId, wrappedKey)
+	assert.NoError(err)
+	assert.Equal([]byte("dek"), key)
+}
+
+func TestNewKekProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewKekProvider(&config.CloudKmsConfig{Provider: "AWS", KeyID: "alias/kbs", Region: "us-west-2"})
+	assert.NoError(err)
+	_, err = NewKekProvider(&config.CloudKmsConfig{Provider: "aws", KeyID: "alias/kbs"})
+	assert.Error(err)
+	_, err = NewKekProvider(&config.CloudKmsConfig{Provider: "gcp"})
+	assert.Error(err)
+	_, err = NewKekProvider(&config.CloudKmsConfig{Provider: "vault", KeyID: "kek"})
+	assert.Error(err)
+}
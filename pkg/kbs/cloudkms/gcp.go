@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cloudkms
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	gcpMetadataURL = "http://metadata.google.internal"
+	gcpTokenPath   = "/computeMetadata/v1/instance/service-accounts/default/token"
+	gcpKmsEndpoint = "https://cloudkms.googleapis.com"
+	gcpKeyVersions = "/cryptoKeyVersions/"
+)
+
+// gcpKekProvider wraps the keys with the encrypt and decrypt operations of a Cloud KMS symmetric crypto key, with
+// the access token of the service account of the compute instance retrieved from the metadata server
+type gcpKekProvider struct {
+	keyName     string
+	endpoint    string
+	metadataURL string
+	client      *http.Client
+	token       roleToken
+}
+
+func newGcpKekProvider(cfg *config.CloudKmsConfig, client *http.Client, metadataURL string) *gcpKekProvider {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = gcpKmsEndpoint
+	}
+	return &gcpKekProvider{
+		keyName:     strings.Trim(cfg.KeyID, "/"),
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		metadataURL: metadataURL,
+		client:      client,
+	}
+}
+
+func (gp *gcpKekProvider) WrapKey(key []byte) (string, []byte, error) {
+	defaultLog.Trace("cloudkms/gcp:WrapKey() Entering")
+	defer defaultLog.Trace("cloudkms/gcp:WrapKey() Leaving")
+
+	var resp struct {
+		Name       string `json:"name"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	err := gp.call(gp.keyName+":encrypt", map[string]interface{}{"plaintext": key}, &resp)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "cloudkms/gcp:WrapKey() Failed to encrypt key with Cloud KMS")
+	}
+	// the name of the response is the key version the key is encrypted with, the ciphertext carries the version
+	// so that it is decrypted with the crypto key itself
+	return resp.Name, resp.Ciphertext, nil
+}
+
+func (gp *gcpKekProvider) UnwrapKey(kekId string, wrappedKey []byte) ([]byte, error) {
+	defaultLog.Trace("cloudkms/gcp:UnwrapKey() Entering")
+	defer defaultLog.Trace("cloudkms/gcp:UnwrapKey() Leaving")
+
+	keyName := kekId
+	if index := strings.Index(keyName, gcpKeyVersions); index >= 0 {
+		keyName = keyName[:index]
+	}
+	var resp struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	err := gp.call(keyName+":decrypt", map[string]interface{}{"ciphertext": wrappedKey}, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "cloudkms/gcp:UnwrapKey() Failed to decrypt key with Cloud KMS")
+	}
+	return resp.Plaintext, nil
+}
+
+func (gp *gcpKekProvider) call(operation string, body interface{}, out interface{}) error {
+	token, err := gp.token.get(gp.getServiceAccountToken)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get the access token of the service account")
+	}
+	req, err := newJsonRequest(http.MethodPost, gp.endpoint+"/v1/"+operation, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.(string))
+
+	_, err = sendRequest(gp.client, req, out)
+	return err
+}
+
+// getServiceAccountToken returns the access token of the service account attached to the compute instance
+func (gp *gcpKekProvider) getServiceAccountToken() (interface{}, time.Time, error) {
+	req, err := http.NewRequest(http.MethodGet, gp.metadataURL+gcpTokenPath, nil)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "Failed to create token request")
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if _, err = sendRequest(gp.client, req, &resp); err != nil {
+		return nil, time.Time{}, err
+	}
+	return resp.AccessToken, time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second), nil
+}
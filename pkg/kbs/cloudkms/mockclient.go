@@ -0,0 +1,31 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cloudkms
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// MockKekProvider is a mock of KekProvider interface
+type MockKekProvider struct {
+	mock.Mock
+}
+
+// NewMockKekProvider creates a new mock instance
+func NewMockKekProvider() *MockKekProvider {
+	return &MockKekProvider{}
+}
+
+// WrapKey mocks base method
+func (m *MockKekProvider) WrapKey(key []byte) (string, []byte, error) {
+	args := m.Called(key)
+	return args.String(0), args.Get(1).([]byte), args.Error(2)
+}
+
+// UnwrapKey mocks base method
+func (m *MockKekProvider) UnwrapKey(kekId string, wrappedKey []byte) ([]byte, error) {
+	args := m.Called(kekId, wrappedKey)
+	return args.Get(0).([]byte), args.Error(1)
+}
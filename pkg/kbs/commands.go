@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"fmt"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+// setupTaskNames are completed after the setup command
+var setupTaskNames = []string{"all", "download-ca-cert", "download-cert-tls", "create-default-key-transfer-policy",
+	"update-service-config"}
+
+// commands lists the commands of the kbs CLI
+func (app *App) commands() *commCmd.Cmd {
+	return &commCmd.Cmd{
+		Name:  constants.ServiceUserName,
+		Flags: []commCmd.CmdFlag{commCmd.OutputFlag(constants.ServiceName)},
+		SubCmd: []commCmd.Cmd{
+			{Name: "help", Aliases: []string{"-h", "--help"}, Exec: func(ctx *commCmd.Context) error {
+				app.printUsage()
+				return nil
+			}},
+			{Name: "version", Aliases: []string{"-v", "--version"}, Exec: app.printVersion},
+			{Name: "run", Flags: []commCmd.CmdFlag{{Name: "standalone", Bool: true, DefInEnv: true, Env: "KBS_STANDALONE"}},
+				Exec: func(ctx *commCmd.Context) error {
+					return app.startServer(ctx.Bool("standalone"))
+				}},
+			{Name: "start", Exec: func(ctx *commCmd.Context) error {
+				return app.start()
+			}},
+			{Name: "stop", Exec: func(ctx *commCmd.Context) error {
+				return app.stop()
+			}},
+			{Name: "status", Exec: app.status},
+			{Name: "uninstall", Flags: []commCmd.CmdFlag{{Name: "purge", Bool: true}}, Exec: func(ctx *commCmd.Context) error {
+				return app.uninstall(ctx.Bool("purge"))
+			}},
+			{Name: "setup", PassThrough: true, Completions: setupTaskNames,
+				Flags: []commCmd.CmdFlag{{Name: "help"}, {Name: "force"}, {Name: "file", Short: "f"}, {Name: "interactive"},
+					{Name: "fix-drift"}},
+				Exec: func(ctx *commCmd.Context) error {
+					if err := app.setup(ctx.Args); err != nil {
+						if errors.Cause(err) == setup.ErrTaskNotFound {
+							app.printUsageWithError(err)
+						} else {
+							fmt.Fprintln(app.errorWriter(), err.Error())
+						}
+						return err
+					}
+					return nil
+				}},
+			commCmd.CompletionCmd,
+		},
+	}
+}
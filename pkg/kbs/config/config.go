@@ -6,6 +6,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	commConfig "github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
@@ -31,8 +32,31 @@ type Configuration struct {
 	Log    commConfig.LogConfig     `yaml:"log" mapstructure:"log"`
 	Server commConfig.ServerConfig  `yaml:"server" mapstructure:"server"`
 
-	Kmip KmipConfig `yaml:"kmip" mapstructure:"kmip"`
-	Skc  SKCConfig  `yaml:"skc" mapstructure:"skc"`
+	// TLSReloadInterval is the interval at which the TLS certificate and key files are checked for changes.
+	// A zero value disables the watcher, rotation is then only possible through the reload API.
+	TLSReloadInterval time.Duration `yaml:"tls-reload-interval" mapstructure:"tls-reload-interval"`
+	// TLSKeyOverlap is the time a replaced TLS key pair is still served to the clients that do not support the
+	// new one. A zero value retires it as soon as the new key pair is loaded.
+	TLSKeyOverlap time.Duration `yaml:"tls-key-overlap" mapstructure:"tls-key-overlap"`
+
+	Kmip     KmipConfig     `yaml:"kmip" mapstructure:"kmip"`
+	CloudKms CloudKmsConfig `yaml:"cloud-kms" mapstructure:"cloud-kms"`
+	Skc      SKCConfig      `yaml:"skc" mapstructure:"skc"`
+
+	// KeyTransferAuth selects the authentication of the key transfer requests carrying a bearer token
+	KeyTransferAuth commConfig.RouteAuthConfig `yaml:"key-transfer-auth" mapstructure:"key-transfer-auth"`
+
+	// OIDC authorizes the clients presenting an access token of an enterprise identity provider, such as the key
+	// transfer clients of the client credentials grant, in addition to the AAS tokens
+	OIDC commConfig.OIDCConfig `yaml:"oidc" mapstructure:"oidc"`
+
+	// FipsMode restricts the service to the FIPS approved algorithms and TLS cipher suites, it is always enabled
+	// in the binaries built with the fips tag
+	FipsMode bool `yaml:"fips-mode" mapstructure:"fips-mode"`
+
+	// DualControl makes the deletion of the keys and the deletion and update of the key transfer policies create
+	// approval requests, the operations are executed once approved by an administrator other than the requester
+	DualControl bool `yaml:"dual-control" mapstructure:"dual-control"`
 }
 
 type KBSConfig struct {
@@ -49,10 +73,22 @@ type KmipConfig struct {
 	RootCert   string `yaml:"root-cert-path" mapstructure:"root-cert-path"`
 }
 
+// CloudKmsConfig selects the cloud KMS key wrapping the keys of the cloudkms key manager, the KBS authenticates
+// with the role of the instance it runs on. KeyID is the key ARN on AWS, the key URL on Azure Key Vault and the
+// crypto key resource name on GCP. Endpoint overrides the public endpoint of the provider, e.g. for private links.
+type CloudKmsConfig struct {
+	Provider string `yaml:"provider" mapstructure:"provider"`
+	KeyID    string `yaml:"key-id" mapstructure:"key-id"`
+	Region   string `yaml:"region" mapstructure:"region"`
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+}
+
 type SKCConfig struct {
 	StmLabel          string `yaml:"challenge-type" mapstructure:"challenge-type"`
 	SQVSUrl           string `yaml:"sqvs-url" mapstructure:"sqvs-url"`
 	SessionExpiryTime int    `yaml:"session-expiry-time" mapstructure:"session-expiry-time"`
+	// NonceExpiryTime is the time in seconds the client has to create the session with the nonce of a challenge
+	NonceExpiryTime int `yaml:"nonce-expiry-time" mapstructure:"nonce-expiry-time"`
 }
 
 // init sets the configuration file name and type
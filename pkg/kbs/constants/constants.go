@@ -23,6 +23,10 @@ const (
 
 	KeysDir               = HomeDir + "keys/"
 	KeysTransferPolicyDir = HomeDir + "keys-transfer-policy/"
+	TenantQuotasDir       = HomeDir + "tenant-quotas/"
+	ApprovalsDir          = HomeDir + "approvals/"
+	StandaloneDBFile      = HomeDir + "kbs.db"
+	KeyEscrowDir          = HomeDir + "escrow/"
 
 	// certificates' path
 	TrustedJWTSigningCertsDir = ConfigDir + "certs/trustedjwt/"
@@ -45,14 +49,20 @@ const (
 	ServiceRemoveCmd = "systemctl disable kbs"
 
 	// tls constants
-	DefaultKbsTlsCn     = "KBS TLS Certificate"
-	DefaultKbsTlsSan    = "127.0.0.1,localhost"
-	DefaultKeyAlgorithm = "rsa"
-	DefaultKeyLength    = 3072
+	DefaultKbsTlsCn          = "KBS TLS Certificate"
+	DefaultKbsTlsSan         = "127.0.0.1,localhost"
+	DefaultTLSReloadInterval = 1 * time.Minute
+	DefaultTLSKeyOverlap     = 24 * time.Hour
+	DefaultKeyAlgorithm      = "rsa"
+	DefaultKeyLength         = 3072
 
 	// jwt constants
 	JWTCertsCacheTime = "1m"
 
+	// oidc constants
+	DefaultOIDCJWKSCacheTime  = 1 * time.Hour
+	DefaultOIDCRequestTimeout = 10 * time.Second
+
 	// log constants
 	DefaultLogLevel     = "info"
 	DefaultLogMaxlength = 1500
@@ -62,17 +72,37 @@ const (
 	DefaultReadHeaderTimeout = 10 * time.Second
 	DefaultWriteTimeout      = 30 * time.Second
 	DefaultIdleTimeout       = 10 * time.Second
+	DefaultDrainTimeout      = 30 * time.Second
+	DefaultBodyReadTimeout   = 20 * time.Second
 	DefaultMaxHeaderBytes    = 1 << 20
+	DefaultMaxBodyBytes      = 1 << 20
 	DefaultKBSListenerPort   = 9443
 
 	// keymanager constants
 	DirectoryKeyManager = "directory"
 	KmipKeyManager      = "kmip"
+	CloudKmsKeyManager  = "cloudkms"
+
+	// cloud KMS providers of the cloudkms key manager
+	AwsKmsProvider    = "aws"
+	AzureKmsProvider  = "azure"
+	GcpKmsProvider    = "gcp"
+	CloudKmsTimeout   = 30 * time.Second
+	CloudKmsDekLength = 32
+
+	// key storage classes, ephemeral keys are only kept in memory until their ttl expires
+	KeyStoragePersistent    = "persistent"
+	KeyStorageEphemeral     = "ephemeral"
+	MaxEphemeralKeyTTLInSec = 24 * 60 * 60
+
+	// MaxKeyAttributes is the maximum number of attributes of a key
+	MaxKeyAttributes = 32
 
 	// algorithm constants
-	CRYPTOALG_AES = "AES"
-	CRYPTOALG_RSA = "RSA"
-	CRYPTOALG_EC  = "EC"
+	CRYPTOALG_AES     = "AES"
+	CRYPTOALG_AES_XTS = "AES-XTS"
+	CRYPTOALG_RSA     = "RSA"
+	CRYPTOALG_EC      = "EC"
 
 	// kmip constants
 	KMIP_CRYPTOALG_AES  = 0x03
@@ -81,12 +111,19 @@ const (
 	KMIP_CLIENT_SUCCESS = 0x00
 
 	NonceLength = 32
+	// DefaultNonceExpiryTime is the default time in seconds to create the session with the nonce of a challenge
+	DefaultNonceExpiryTime = 120
+
+	// tenant constants, the tenant of a request is set in the context of one of its KBS roles as tenant=<id>
+	TenantIdPattern    = "[a-zA-Z0-9][a-zA-Z0-9_-]{0,63}"
+	TenantContextRegex = "^tenant=(" + TenantIdPattern + ")$"
 )
 
 ///SKC Specific constants
 const (
 	DefaultSWLabel          = "SW"
 	DefaultSGXLabel         = "SGX"
+	DefaultTDXLabel         = "TDX"
 	VerifyQuote             = "/sgx_qv_verify_quote"
 	KeyTransferOpertaion    = "transfer key"
 	SessionOperation        = "establish session key"
@@ -97,4 +134,12 @@ const (
 	TransferRoleType        = "KeyTransfer"
 	ContextPermissionsRegex = "^(permissions=)(.*)$"
 	TCBLevelOutOfDate       = "OutOfDate"
+
+	// payload compression negotiated per session, applied before the key data is encrypted with the swk
+	CompressionGzip      = "gzip"
+	CompressionThreshold = 4096
+
+	// the successful key transfers are cached for at most this many seconds, only to absorb the transfer requests
+	// of a restarting client
+	MaxTransferCacheTimeout = 300
 )
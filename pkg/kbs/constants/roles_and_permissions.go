@@ -15,6 +15,9 @@ const (
 	KeyRegister = "keys:register"
 	KeyTransfer = "keys:transfer"
 
+	KeyImageFlavorBind  = "keys:bind_image_flavor"
+	KeyAttributesUpdate = "keys:update_attributes"
+
 	SamlCertCreate   = "saml_certificates:create"
 	SamlCertRetrieve = "saml_certificates:retrieve"
 	SamlCertDelete   = "saml_certificates:delete"
@@ -26,9 +29,26 @@ const (
 	TpmIdentityCertSearch   = "tpm_identity_certificates:search"
 
 	KeyTransferPolicyCreate   = "key_transfer_policies:create"
+	KeyTransferPolicyUpdate   = "key_transfer_policies:update"
 	KeyTransferPolicyRetrieve = "key_transfer_policies:retrieve"
 	KeyTransferPolicyDelete   = "key_transfer_policies:delete"
 	KeyTransferPolicySearch   = "key_transfer_policies:search"
 
+	TenantQuotaUpdate   = "tenant_quotas:update"
+	TenantQuotaRetrieve = "tenant_quotas:retrieve"
+	TenantQuotaDelete   = "tenant_quotas:delete"
+	TenantQuotaSearch   = "tenant_quotas:search"
+
+	ApprovalRetrieve = "approvals:retrieve"
+	ApprovalSearch   = "approvals:search"
+	ApprovalApprove  = "approvals:approve"
+
+	KeyEscrowCreate   = "key_escrow:create"
+	KeyEscrowRetrieve = "key_escrow:retrieve"
+	KeyEscrowRecover  = "key_escrow:recover"
+
 	SessionCreate = "key-session-api:create"
+
+	TlsCertificateRetrieve = "tls_certificate:retrieve"
+	TlsCertificateReload   = "tls_certificate:reload"
 )
@@ -0,0 +1,381 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+)
+
+// approvalMutex serializes the reviews of the approval requests, so that a request is executed at most once
+var approvalMutex sync.Mutex
+
+// ApprovalController puts the deletion of the keys and the deletion and update of the key transfer policies under
+// dual control: the operations create approval requests which are executed once approved by an administrator other
+// than the requester
+type ApprovalController struct {
+	approvalStore    domain.ApprovalStore
+	keyController    *KeyController
+	policyController *KeyTransferPolicyController
+}
+
+func NewApprovalController(as domain.ApprovalStore, kc *KeyController, ktpc *KeyTransferPolicyController) *ApprovalController {
+	return &ApprovalController{
+		approvalStore:    as,
+		keyController:    kc,
+		policyController: ktpc,
+	}
+}
+
+var approvalStatuses = map[kbs.ApprovalStatus]bool{kbs.ApprovalStatusPending: true, kbs.ApprovalStatusExecuted: true,
+	kbs.ApprovalStatusRejected: true, kbs.ApprovalStatusFailed: true}
+
+// RequestKeyDeletion : Function to request the deletion of a key
+func (ac ApprovalController) RequestKeyDeletion(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:RequestKeyDeletion() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:RequestKeyDeletion() Leaving")
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	if _, status, err := ac.keyController.retrieveTenantKey(request, id); err != nil {
+		return nil, status, err
+	}
+
+	return ac.createApproval(request, &kbs.ApprovalRequest{
+		Operation:  kbs.ApprovalOperationDeleteKey,
+		ResourceID: id,
+	})
+}
+
+// RequestKeyTransferPolicyDeletion : Function to request the deletion of a key transfer policy
+func (ac ApprovalController) RequestKeyTransferPolicyDeletion(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:RequestKeyTransferPolicyDeletion() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:RequestKeyTransferPolicyDeletion() Leaving")
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	if _, status, err := ac.policyController.retrieveTenantPolicy(request, id); err != nil {
+		return nil, status, err
+	}
+	// the policies in use are rejected upfront, they are checked again when the deletion is approved
+	if status, err := ac.policyController.checkPolicyNotInUse(id); err != nil {
+		return nil, status, err
+	}
+
+	return ac.createApproval(request, &kbs.ApprovalRequest{
+		Operation:  kbs.ApprovalOperationDeleteKeyTransferPolicy,
+		ResourceID: id,
+	})
+}
+
+// RequestKeyTransferPolicyUpdate : Function to request the update of a key transfer policy, If-Match is matched
+// against the version of the policy
+func (ac ApprovalController) RequestKeyTransferPolicyUpdate(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:RequestKeyTransferPolicyUpdate() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:RequestKeyTransferPolicyUpdate() Leaving")
+
+	requestPolicy, status, err := readKeyTransferPolicyUpdate(request)
+	if err != nil {
+		return nil, status, err
+	}
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	currentPolicy, status, err := ac.policyController.retrieveTenantPolicy(request, id)
+	if err != nil {
+		return nil, status, err
+	}
+	if status, err := checkIfMatch(responseWriter, request, currentPolicy.Version); err != nil {
+		return nil, status, err
+	}
+
+	payload, err := json.Marshal(requestPolicy)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/approval_controller:RequestKeyTransferPolicyUpdate() Failed to marshal key transfer policy")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create approval request"}
+	}
+
+	return ac.createApproval(request, &kbs.ApprovalRequest{
+		Operation:       kbs.ApprovalOperationUpdateKeyTransferPolicy,
+		ResourceID:      id,
+		ResourceVersion: currentPolicy.Version,
+		Payload:         payload,
+	})
+}
+
+// Retrieve : Function to retrieve an approval request
+func (ac ApprovalController) Retrieve(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:Retrieve() Leaving")
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	approval, status, err := ac.retrieveTenantApproval(request, id)
+	if err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/approval_controller:Retrieve() %s: Approval request retrieved by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return approval, http.StatusOK, nil
+}
+
+// Search : Function to search the approval requests of the tenant, optionally by status
+func (ac ApprovalController) Search(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:Search() Leaving")
+
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/approval_controller:Search() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	criteria := &models.ApprovalFilterCriteria{
+		TenantID: &tenantId,
+	}
+	for param := range request.URL.Query() {
+		if param != "status" {
+			secLog.Errorf("controllers/approval_controller:Search() %s : Invalid query parameter %s", commLogMsg.InvalidInputBadParam, param)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid query parameter provided"}
+		}
+	}
+	if status := request.URL.Query().Get("status"); status != "" {
+		if !approvalStatuses[kbs.ApprovalStatus(status)] {
+			secLog.Errorf("controllers/approval_controller:Search() %s : Invalid status %s", commLogMsg.InvalidInputBadParam, status)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Valid status must be specified"}
+		}
+		criteria.Status = kbs.ApprovalStatus(status)
+	}
+
+	approvals, err := ac.approvalStore.Search(criteria)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/approval_controller:Search() Approval request search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search approval requests"}
+	}
+
+	secLog.Infof("controllers/approval_controller:Search() %s: Approval requests searched by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return approvals, http.StatusOK, nil
+}
+
+// Approve : Function to approve a pending request and execute its operation, the approver cannot be the requester
+func (ac ApprovalController) Approve(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:Approve() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:Approve() Leaving")
+
+	approval, reviewer, decision, status, err := ac.reviewApproval(request)
+	if err != nil {
+		return nil, status, err
+	}
+	defer approvalMutex.Unlock()
+
+	if reviewer == approval.RequestedBy {
+		secLog.WithField("Id", approval.ID).Errorf("controllers/approval_controller:Approve() %s: Approval request approved by its requester %s", commLogMsg.UnauthorizedAccess, reviewer)
+		return nil, http.StatusForbidden, &commErr.ResourceError{Message: "The request must be approved by an administrator other than the requester"}
+	}
+
+	now := time.Now().UTC()
+	approval.ReviewedBy = reviewer
+	approval.ReviewedAt = &now
+	approval.Comment = decision.Comment
+	approval.AddAuditRecord(kbs.ApprovalActionApproved, reviewer, decision.Comment)
+	secLog.WithField("Id", approval.ID).Infof("controllers/approval_controller:Approve() %s: %s of %s requested by %s approved by %s, from: %s", commLogMsg.PrivilegeModified, approval.Operation, approval.ResourceID, approval.RequestedBy, reviewer, request.RemoteAddr)
+
+	execStatus, execErr := ac.execute(approval)
+	if execErr != nil {
+		approval.Status = kbs.ApprovalStatusFailed
+		approval.Error = execErr.Error()
+		approval.AddAuditRecord(kbs.ApprovalActionFailed, reviewer, execErr.Error())
+	} else {
+		approval.Status = kbs.ApprovalStatusExecuted
+		approval.AddAuditRecord(kbs.ApprovalActionExecuted, reviewer, "")
+	}
+
+	updatedApproval, err := ac.approvalStore.Update(approval)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/approval_controller:Approve() Approval request update failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update approval request"}
+	}
+	if execErr != nil {
+		secLog.WithError(execErr).WithField("Id", approval.ID).Errorf("controllers/approval_controller:Approve() %s of %s failed", approval.Operation, approval.ResourceID)
+		return nil, execStatus, execErr
+	}
+
+	secLog.WithField("Id", approval.ID).Infof("controllers/approval_controller:Approve() %s: %s of %s executed", commLogMsg.PrivilegeModified, approval.Operation, approval.ResourceID)
+	return updatedApproval, http.StatusOK, nil
+}
+
+// Reject : Function to reject a pending request, the requester can reject its own request to withdraw it
+func (ac ApprovalController) Reject(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:Reject() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:Reject() Leaving")
+
+	approval, reviewer, decision, status, err := ac.reviewApproval(request)
+	if err != nil {
+		return nil, status, err
+	}
+	defer approvalMutex.Unlock()
+
+	now := time.Now().UTC()
+	approval.Status = kbs.ApprovalStatusRejected
+	approval.ReviewedBy = reviewer
+	approval.ReviewedAt = &now
+	approval.Comment = decision.Comment
+	approval.AddAuditRecord(kbs.ApprovalActionRejected, reviewer, decision.Comment)
+
+	updatedApproval, err := ac.approvalStore.Update(approval)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/approval_controller:Reject() Approval request update failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update approval request"}
+	}
+
+	secLog.WithField("Id", approval.ID).Infof("controllers/approval_controller:Reject() %s: %s of %s requested by %s rejected by %s, from: %s", commLogMsg.PrivilegeModified, approval.Operation, approval.ResourceID, approval.RequestedBy, reviewer, request.RemoteAddr)
+	return updatedApproval, http.StatusOK, nil
+}
+
+// createApproval stores a pending approval request of the user making the request in the namespace of its tenant
+func (ac ApprovalController) createApproval(request *http.Request, approval *kbs.ApprovalRequest) (interface{}, int, error) {
+	defaultLog.Trace("controllers/approval_controller:createApproval() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:createApproval() Leaving")
+
+	requester, err := comctx.GetTokenSubject(request)
+	if err != nil || requester == "" {
+		secLog.Errorf("controllers/approval_controller:createApproval() %s : The requester of the %s could not be identified", commLogMsg.UnauthorizedAccess, approval.Operation)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "The requester could not be identified"}
+	}
+
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/approval_controller:createApproval() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	approval.TenantID = tenantId
+	approval.Status = kbs.ApprovalStatusPending
+	approval.RequestedBy = requester
+	approval.AddAuditRecord(kbs.ApprovalActionRequested, requester, "")
+	createdApproval, err := ac.approvalStore.Create(approval)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/approval_controller:createApproval() Approval request create failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create approval request"}
+	}
+
+	secLog.WithField("Id", createdApproval.ID).Infof("controllers/approval_controller:createApproval() %s: %s of %s requested by %s, from: %s", commLogMsg.PrivilegeModified, approval.Operation, approval.ResourceID, requester, request.RemoteAddr)
+	return createdApproval, http.StatusAccepted, nil
+}
+
+// reviewApproval identifies the reviewer of a pending approval request and decodes its decision. approvalMutex is
+// held on success and must be released by the caller.
+func (ac ApprovalController) reviewApproval(request *http.Request) (*kbs.ApprovalRequest, string, *kbs.ApprovalDecision, int, error) {
+	defaultLog.Trace("controllers/approval_controller:reviewApproval() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:reviewApproval() Leaving")
+
+	reviewer, err := comctx.GetTokenSubject(request)
+	if err != nil || reviewer == "" {
+		secLog.Errorf("controllers/approval_controller:reviewApproval() %s : The reviewer could not be identified", commLogMsg.UnauthorizedAccess)
+		return nil, "", nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "The reviewer could not be identified"}
+	}
+
+	// the decision is optional
+	var decision kbs.ApprovalDecision
+	if request.ContentLength != 0 {
+		if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+			return nil, "", nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+		}
+		dec := json.NewDecoder(request.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&decision); err != nil {
+			secLog.WithError(err).Errorf("controllers/approval_controller:reviewApproval() %s : Failed to decode request body as ApprovalDecision", commLogMsg.InvalidInputBadEncoding)
+			return nil, "", nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+		}
+	}
+
+	approvalMutex.Lock()
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	approval, status, err := ac.retrieveTenantApproval(request, id)
+	if err != nil {
+		approvalMutex.Unlock()
+		return nil, "", nil, status, err
+	}
+	if approval.Status != kbs.ApprovalStatusPending {
+		approvalMutex.Unlock()
+		defaultLog.Errorf("controllers/approval_controller:reviewApproval() Approval request is %s", approval.Status)
+		return nil, "", nil, http.StatusConflict, &commErr.ResourceError{Message: "Approval request is not pending"}
+	}
+	return approval, reviewer, &decision, http.StatusOK, nil
+}
+
+// execute executes the operation of an approved request
+func (ac ApprovalController) execute(approval *kbs.ApprovalRequest) (int, error) {
+	defaultLog.Trace("controllers/approval_controller:execute() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:execute() Leaving")
+
+	switch approval.Operation {
+	case kbs.ApprovalOperationDeleteKey:
+		return ac.keyController.deleteKey(approval.ResourceID)
+	case kbs.ApprovalOperationDeleteKeyTransferPolicy:
+		return ac.policyController.deletePolicy(approval.ResourceID)
+	case kbs.ApprovalOperationUpdateKeyTransferPolicy:
+		var requestPolicy kbs.KeyTransferPolicyAttributes
+		if err := json.Unmarshal(approval.Payload, &requestPolicy); err != nil {
+			defaultLog.WithError(err).Error("controllers/approval_controller:execute() Failed to unmarshal key transfer policy")
+			return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to decode key transfer policy of approval request"}
+		}
+
+		updateMutex.Lock()
+		defer updateMutex.Unlock()
+
+		currentPolicy, err := ac.policyController.policyStore.Retrieve(approval.ResourceID)
+		if err != nil {
+			if err.Error() == commErr.RecordNotFound {
+				return http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
+			}
+			defaultLog.WithError(err).Error("controllers/approval_controller:execute() Key transfer policy retrieve failed")
+			return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key transfer policy"}
+		}
+		if currentPolicy.Version != approval.ResourceVersion {
+			return http.StatusPreconditionFailed, &commErr.ResourceError{Message: "Key transfer policy was modified after the update was requested"}
+		}
+		_, status, err := ac.policyController.replacePolicy(currentPolicy, &requestPolicy)
+		return status, err
+	default:
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Unknown operation of approval request"}
+	}
+}
+
+// retrieveTenantApproval retrieves an approval request of the tenant of the request, the requests of other tenants
+// are reported as not found
+func (ac ApprovalController) retrieveTenantApproval(request *http.Request, id uuid.UUID) (*kbs.ApprovalRequest, int, error) {
+	defaultLog.Trace("controllers/approval_controller:retrieveTenantApproval() Entering")
+	defer defaultLog.Trace("controllers/approval_controller:retrieveTenantApproval() Leaving")
+
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/approval_controller:retrieveTenantApproval() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	approval, err := ac.approvalStore.Retrieve(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/approval_controller:retrieveTenantApproval() Approval request with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Approval request with specified id does not exist"}
+		}
+		defaultLog.WithError(err).Error("controllers/approval_controller:retrieveTenantApproval() Approval request retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve approval request"}
+	}
+	if approval.TenantID != tenantId {
+		secLog.WithField("Id", id).Errorf("controllers/approval_controller:retrieveTenantApproval() %s : Approval request of another tenant", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Approval request with specified id does not exist"}
+	}
+	return approval, http.StatusOK, nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/memory"
+	kbsRoutes "github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ApprovalController", func() {
+	var router *mux.Router
+	var keyStore *mocks.MockKeyStore
+	var policyStore *mocks.MockKeyTransferPolicyStore
+	var approvalStore *mocks.MockApprovalStore
+	var approvalController *controllers.ApprovalController
+
+	const (
+		usedPolicyId   = "ee37c360-7eae-4250-a677-6ee12adce8e2"
+		unusedPolicyId = "73755fda-c910-46be-821f-e8ddeab189e9"
+		keyId          = "ee37c360-7eae-4250-a677-6ee12adce8e2"
+	)
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		keyStore = mocks.NewFakeKeyStore()
+		policyStore = mocks.NewFakeKeyTransferPolicyStore()
+		quotaStore := mocks.NewFakeTenantQuotaStore()
+		approvalStore = mocks.NewFakeApprovalStore()
+
+		remoteManager := keymanager.NewRemoteManager(keyStore, memory.NewKeyStore(), &keymanager.DirectoryManager{}, endpointUrl)
+		keyController := controllers.NewKeyController(remoteManager, policyStore, quotaStore, domain.KeyControllerConfig{})
+		policyController := controllers.NewKeyTransferPolicyController(policyStore, keyStore, quotaStore)
+		approvalController = controllers.NewApprovalController(approvalStore, keyController, policyController)
+
+		router.Handle("/keys/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.RequestKeyDeletion))).Methods("DELETE")
+		router.Handle("/key-transfer-policies/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.RequestKeyTransferPolicyUpdate))).Methods("PUT")
+		router.Handle("/key-transfer-policies/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.RequestKeyTransferPolicyDeletion))).Methods("DELETE")
+		router.Handle("/approvals", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.Search))).Methods("GET")
+		router.Handle("/approvals/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.Retrieve))).Methods("GET")
+		router.Handle("/approvals/{id}/approve", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.Approve))).Methods("POST")
+		router.Handle("/approvals/{id}/reject", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(approvalController.Reject))).Methods("POST")
+	})
+
+	serve := func(method, path, user string, body io.Reader) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, body)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		if body != nil {
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		}
+		if user != "" {
+			req = context.SetTokenSubject(req, user)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	requestApproval := func(method, path string, body io.Reader) kbs.ApprovalRequest {
+		w := serve(method, path, "admin-a", body)
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+		var approval kbs.ApprovalRequest
+		Expect(json.Unmarshal(w.Body.Bytes(), &approval)).To(Succeed())
+		Expect(approval.Status).To(Equal(kbs.ApprovalStatusPending))
+		Expect(approval.RequestedBy).To(Equal("admin-a"))
+		return approval
+	}
+
+	Describe("Request the deletion of a Key", func() {
+		Context("Approve the deletion by another administrator", func() {
+			It("Should delete the Key once approved and record the audit trail", func() {
+				approval := requestApproval("DELETE", "/keys/"+keyId, nil)
+				Expect(approval.Operation).To(Equal(kbs.ApprovalOperationDeleteKey))
+				Expect(keyStore.KeyStore).To(HaveKey(uuid.MustParse(keyId)))
+
+				w := serve("POST", "/approvals/"+approval.ID.String()+"/approve", "admin-b", strings.NewReader(`{"comment": "key retired"}`))
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(keyStore.KeyStore).NotTo(HaveKey(uuid.MustParse(keyId)))
+
+				var approved kbs.ApprovalRequest
+				Expect(json.Unmarshal(w.Body.Bytes(), &approved)).To(Succeed())
+				Expect(approved.Status).To(Equal(kbs.ApprovalStatusExecuted))
+				Expect(approved.ReviewedBy).To(Equal("admin-b"))
+				Expect(approved.Comment).To(Equal("key retired"))
+				Expect(approved.AuditRecords).To(HaveLen(3))
+				Expect(approved.AuditRecords[0].Action).To(Equal(kbs.ApprovalActionRequested))
+				Expect(approved.AuditRecords[1].Action).To(Equal(kbs.ApprovalActionApproved))
+				Expect(approved.AuditRecords[2].Action).To(Equal(kbs.ApprovalActionExecuted))
+
+				// the executed requests cannot be reviewed again
+				w = serve("POST", "/approvals/"+approval.ID.String()+"/approve", "admin-c", nil)
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+		Context("Approve the deletion by the requester", func() {
+			It("Should fail to approve the request", func() {
+				approval := requestApproval("DELETE", "/keys/"+keyId, nil)
+				w := serve("POST", "/approvals/"+approval.ID.String()+"/approve", "admin-a", nil)
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+				Expect(keyStore.KeyStore).To(HaveKey(uuid.MustParse(keyId)))
+			})
+		})
+		Context("Reject the deletion", func() {
+			It("Should keep the Key", func() {
+				approval := requestApproval("DELETE", "/keys/"+keyId, nil)
+				w := serve("POST", "/approvals/"+approval.ID.String()+"/reject", "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(keyStore.KeyStore).To(HaveKey(uuid.MustParse(keyId)))
+
+				w = serve("GET", "/approvals/"+approval.ID.String(), "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var rejected kbs.ApprovalRequest
+				Expect(json.Unmarshal(w.Body.Bytes(), &rejected)).To(Succeed())
+				Expect(rejected.Status).To(Equal(kbs.ApprovalStatusRejected))
+			})
+		})
+		Context("Request the deletion without an authenticated user", func() {
+			It("Should fail to create the approval request", func() {
+				w := serve("DELETE", "/keys/"+keyId, "", nil)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+				Expect(approvalStore.ApprovalStore).To(BeEmpty())
+			})
+		})
+		Context("Request the deletion of a non-existent Key", func() {
+			It("Should fail to create the approval request", func() {
+				w := serve("DELETE", "/keys/"+unusedPolicyId, "admin-a", nil)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("Request changes of a Key Transfer Policy", func() {
+		Context("Request the deletion of a Key Transfer Policy associated with Keys", func() {
+			It("Should fail to create the approval request", func() {
+				w := serve("DELETE", "/key-transfer-policies/"+usedPolicyId, "admin-a", nil)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Approve the deletion of a Key Transfer Policy", func() {
+			It("Should delete the Key Transfer Policy", func() {
+				approval := requestApproval("DELETE", "/key-transfer-policies/"+unusedPolicyId, nil)
+				w := serve("POST", "/approvals/"+approval.ID.String()+"/approve", "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(policyStore.KeyTransferPolicyStore).NotTo(HaveKey(uuid.MustParse(unusedPolicyId)))
+			})
+		})
+		Context("Approve the update of a Key Transfer Policy", func() {
+			It("Should update the Key Transfer Policy unless it changed after the request", func() {
+				policyJson := `{
+								"sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+								"sgx_enclave_issuer_product_id_anyof": [1]
+						}`
+				approval := requestApproval("PUT", "/key-transfer-policies/"+unusedPolicyId, strings.NewReader(policyJson))
+				stale := requestApproval("PUT", "/key-transfer-policies/"+unusedPolicyId, strings.NewReader(policyJson))
+				Expect(policyStore.KeyTransferPolicyStore[uuid.MustParse(unusedPolicyId)].SGXEnclaveIssuerProductIDAnyof).To(Equal([]int16{0}))
+
+				w := serve("POST", "/approvals/"+approval.ID.String()+"/approve", "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				policy := policyStore.KeyTransferPolicyStore[uuid.MustParse(unusedPolicyId)]
+				Expect(policy.SGXEnclaveIssuerProductIDAnyof).To(Equal([]int16{1}))
+				Expect(policy.Version).To(Equal(1))
+
+				w = serve("POST", "/approvals/"+stale.ID.String()+"/approve", "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+				Expect(approvalStore.ApprovalStore[stale.ID].Status).To(Equal(kbs.ApprovalStatusFailed))
+				Expect(approvalStore.ApprovalStore[stale.ID].AuditRecords).To(HaveLen(3))
+			})
+		})
+	})
+
+	Describe("Search the approval requests", func() {
+		Context("Search the pending approval requests", func() {
+			It("Should return the pending requests only", func() {
+				approval := requestApproval("DELETE", "/keys/"+keyId, nil)
+				requestApproval("DELETE", "/key-transfer-policies/"+unusedPolicyId, nil)
+				w := serve("POST", "/approvals/"+approval.ID.String()+"/reject", "admin-a", nil)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				w = serve("GET", "/approvals?status=pending", "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var approvals []kbs.ApprovalRequest
+				Expect(json.Unmarshal(w.Body.Bytes(), &approvals)).To(Succeed())
+				Expect(approvals).To(HaveLen(1))
+				Expect(approvals[0].Operation).To(Equal(kbs.ApprovalOperationDeleteKeyTransferPolicy))
+			})
+		})
+		Context("Search the approval requests with an invalid status", func() {
+			It("Should fail to search the approval requests", func() {
+				w := serve("GET", "/approvals?status=approved", "admin-b", nil)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+)
+
+const (
+	eTagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+// updateMutex serializes the updates of the keys and key transfer policies, so that the version matched against
+// If-Match is the version the update replaces
+var updateMutex sync.Mutex
+
+// setETag sets the version of the key or key transfer policy in the response as its ETag
+func setETag(responseWriter http.ResponseWriter, version int) {
+	responseWriter.Header().Set(eTagHeader, strconv.Quote(strconv.Itoa(version)))
+}
+
+// checkIfMatch matches the If-Match header of the request against the current version, requests without If-Match
+// or with "*" update whatever the version. On mismatch the current version is returned as ETag with status 412.
+func checkIfMatch(responseWriter http.ResponseWriter, request *http.Request, version int) (int, error) {
+	defaultLog.Trace("controllers/conditional_update:checkIfMatch() Entering")
+	defer defaultLog.Trace("controllers/conditional_update:checkIfMatch() Leaving")
+
+	ifMatch := strings.TrimSpace(request.Header.Get(ifMatchHeader))
+	if ifMatch == "" || ifMatch == "*" {
+		return http.StatusOK, nil
+	}
+
+	for _, eTag := range strings.Split(ifMatch, ",") {
+		unquoted, err := strconv.Unquote(strings.TrimSpace(eTag))
+		if err != nil {
+			secLog.WithError(err).Errorf("controllers/conditional_update:checkIfMatch() %s : Invalid If-Match header", commLogMsg.InvalidInputBadParam)
+			return http.StatusBadRequest, &commErr.ResourceError{Message: "If-Match must be a list of quoted versions"}
+		}
+		if unquoted == strconv.Itoa(version) {
+			return http.StatusOK, nil
+		}
+	}
+
+	setETag(responseWriter, version)
+	defaultLog.Errorf("controllers/conditional_update:checkIfMatch() If-Match %s does not match the current version %d", ifMatch, version)
+	return http.StatusPreconditionFailed, &commErr.ResourceError{Message: "The resource has been modified, its current version is " + strconv.Itoa(version)}
+}
@@ -16,8 +16,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -31,6 +35,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
 	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt/tpm2"
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
@@ -43,22 +48,32 @@ import (
 type KeyController struct {
 	remoteManager *keymanager.RemoteManager
 	policyStore   domain.KeyTransferPolicyStore
+	quotaStore    domain.TenantQuotaStore
 	config        domain.KeyControllerConfig
 }
 
-func NewKeyController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyStore, kc domain.KeyControllerConfig) *KeyController {
+func NewKeyController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyStore, qs domain.TenantQuotaStore, kc domain.KeyControllerConfig) *KeyController {
 	return &KeyController{
 		remoteManager: rm,
 		policyStore:   ps,
+		quotaStore:    qs,
 		config:        kc,
 	}
 }
 
-var keySearchParams = map[string]bool{"algorithm": true, "keyLength": true, "curveType": true, "transferPolicyId": true}
-var allowedAlgorithms = map[string]bool{"AES": true, "RSA": true, "EC": true, "aes": true, "rsa": true, "ec": true}
+var keySearchParams = map[string]bool{"algorithm": true, "keyLength": true, "curveType": true, "transferPolicyId": true,
+	"attribute": true, "createdAfter": true, "createdBefore": true}
+var allowedAlgorithms = map[string]bool{"AES": true, "AES-XTS": true, "RSA": true, "EC": true, "aes": true, "aes-xts": true, "rsa": true, "ec": true}
 var allowedCurveTypes = map[string]bool{"secp256r1": true, "secp384r1": true, "secp521r1": true, "prime256v1": true}
 var allowedKeyLengths = map[int]bool{128: true, 192: true, 256: true, 2048: true, 3072: true, 4096: true, 7680: true, 15360: true}
 
+// AES-XTS volume keys hold a data key and a tweak key, 256 bits for AES-128-XTS and 512 bits for AES-256-XTS
+var allowedXtsKeyLengths = map[int]bool{256: true, 512: true}
+
+var kubernetesNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+var kubernetesSecretKeyRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]{1,253}$`)
+var keyAttributeNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-._/a-zA-Z0-9]{0,61}[a-zA-Z0-9])?$`)
+
 //Create : Function to create key
 func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_controller:Create() Entering")
@@ -90,6 +105,12 @@ func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
 
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:Create() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
 	if requestKey.TransferPolicyID == uuid.Nil {
 		defaultLog.Debug("controllers/key_controller:Create() TransferPolicy ID is not provided : Proceeding with DefaultTransferPolicy")
 		requestKey.TransferPolicyID = kc.config.DefaultTransferPolicyId
@@ -100,12 +121,30 @@ func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key transfer policy"}
 		}
 
-		if transferPolicy == nil {
+		// keys can only use the policies of their tenant and the default transfer policy
+		if transferPolicy == nil || (transferPolicy.TenantID != tenantId && transferPolicy.ID != kc.config.DefaultTransferPolicyId) {
 			defaultLog.Errorf("controllers/key_controller:Create() Key transfer policy with specified id could not be located")
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
 		}
 	}
 
+	quota, err := retrieveTenantQuota(kc.quotaStore, tenantId)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_controller:Create() Tenant quota retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve tenant quota"}
+	}
+	if quota != nil && quota.MaxKeys > 0 {
+		keys, err := kc.remoteManager.SearchKeys(&models.KeyFilterCriteria{TenantID: &tenantId})
+		if err != nil {
+			defaultLog.WithError(err).Error("controllers/key_controller:Create() Key search failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search keys"}
+		}
+		if len(keys) >= quota.MaxKeys {
+			secLog.WithField("TenantId", tenantId).Errorf("controllers/key_controller:Create() Key quota of tenant reached, request from: %s", request.RemoteAddr)
+			return nil, http.StatusForbidden, &commErr.ResourceError{Message: "Key quota of the tenant has been reached"}
+		}
+	}
+
 	privileges, err := comctx.GetUserPermissions(request)
 	if err != nil {
 		secLog.Errorf("controllers/key_controller:Create() %s", commLogMsg.AuthenticationFailed)
@@ -121,7 +160,7 @@ func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http
 		}
 
 		defaultLog.Debug("controllers/key_controller:Create() Create key request received")
-		createdKey, err = kc.remoteManager.CreateKey(&requestKey)
+		createdKey, err = kc.remoteManager.CreateKey(&requestKey, tenantId)
 		if err != nil {
 			defaultLog.WithError(err).Error("controllers/key_controller:Create() Key create failed")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create key"}
@@ -136,7 +175,7 @@ func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http
 		}
 
 		defaultLog.Debug("controllers/key_controller:Create() Register key request received")
-		createdKey, err = kc.remoteManager.RegisterKey(&requestKey)
+		createdKey, err = kc.remoteManager.RegisterKey(&requestKey, tenantId)
 		if err != nil {
 			defaultLog.WithError(err).Error("controllers/key_controller:Create() Key register failed")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to register key"}
@@ -145,6 +184,7 @@ func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http
 		secLog.WithField("Id", createdKey.KeyInformation.ID).Infof("controllers/key_controller:Create() %s: Key registered by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
 	}
 
+	setETag(responseWriter, createdKey.Version)
 	return createdKey, http.StatusCreated, nil
 }
 
@@ -154,18 +194,13 @@ func (kc KeyController) Retrieve(responseWriter http.ResponseWriter, request *ht
 	defer defaultLog.Trace("controllers/key_controller:Retrieve() Leaving")
 
 	id := uuid.MustParse(mux.Vars(request)["id"])
-	key, err := kc.remoteManager.RetrieveKey(id)
+	key, status, err := kc.retrieveTenantKey(request, id)
 	if err != nil {
-		if err.Error() == commErr.RecordNotFound {
-			defaultLog.Error("controllers/key_controller:Retrieve() Key with specified id could not be located")
-			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
-		} else {
-			defaultLog.WithError(err).Error("controllers/key_controller:Retrieve() Key retrieve failed")
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
-		}
+		return nil, status, err
 	}
 
 	secLog.WithField("Id", id).Infof("controllers/key_controller:Retrieve() Key Retrieved by: %s", request.RemoteAddr)
+	setETag(responseWriter, key.Version)
 	return key, http.StatusOK, nil
 }
 
@@ -175,19 +210,34 @@ func (kc KeyController) Delete(responseWriter http.ResponseWriter, request *http
 	defer defaultLog.Trace("controllers/key_controller:Delete() Leaving")
 
 	id := uuid.MustParse(mux.Vars(request)["id"])
+	if _, status, err := kc.retrieveTenantKey(request, id); err != nil {
+		return nil, status, err
+	}
+
+	if status, err := kc.deleteKey(id); err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:Delete() Key deleted by: %s", request.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+// deleteKey deletes a key from the key store and the key manager
+func (kc KeyController) deleteKey(id uuid.UUID) (int, error) {
+	defaultLog.Trace("controllers/key_controller:deleteKey() Entering")
+	defer defaultLog.Trace("controllers/key_controller:deleteKey() Leaving")
+
 	err := kc.remoteManager.DeleteKey(id)
 	if err != nil {
 		if err.Error() == commErr.RecordNotFound {
-			defaultLog.Error("controllers/key_controller:Delete() Key with specified id could not be located")
-			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+			defaultLog.Error("controllers/key_controller:deleteKey() Key with specified id could not be located")
+			return http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
 		} else {
-			defaultLog.WithError(err).Error("controllers/key_controller:Delete() Key delete failed")
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete key"}
+			defaultLog.WithError(err).Error("controllers/key_controller:deleteKey() Key delete failed")
+			return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete key"}
 		}
 	}
-
-	secLog.WithField("Id", id).Infof("controllers/key_controller:Delete() Key deleted by: %s", request.RemoteAddr)
-	return nil, http.StatusNoContent, nil
+	return http.StatusNoContent, nil
 }
 
 //Search : Function to search keys
@@ -208,6 +258,13 @@ func (kc KeyController) Search(responseWriter http.ResponseWriter, request *http
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid filter criteria"}
 	}
 
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:Search() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+	criteria.TenantID = &tenantId
+
 	keys, err := kc.remoteManager.SearchKeys(criteria)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/key_controller:Search() Key search failed")
@@ -218,51 +275,260 @@ func (kc KeyController) Search(responseWriter http.ResponseWriter, request *http
 	return keys, http.StatusOK, nil
 }
 
-//Transfer : Function to perform key transfer with public key
-func (kc KeyController) Transfer(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
-	defaultLog.Trace("controllers/key_controller:Transfer() Entering")
-	defer defaultLog.Trace("controllers/key_controller:Transfer() Leaving")
+//BindImageFlavor : Function to bind key to a workload service image flavor
+func (kc KeyController) BindImageFlavor(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:BindImageFlavor() Entering")
+	defer defaultLog.Trace("controllers/key_controller:BindImageFlavor() Leaving")
 
-	if request.Header.Get("Content-Type") != constants.HTTPMediaTypePlain {
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
 		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
 	}
 
 	if request.ContentLength == 0 {
-		secLog.Error("controllers/key_controller:Transfer() The request body was not provided")
+		secLog.Error("controllers/key_controller:BindImageFlavor() The request body was not provided")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
 	}
 
+	var binding kbs.KeyImageFlavorBinding
 	// Decode the incoming json data to note struct
-	bytes, err := ioutil.ReadAll(request.Body)
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&binding)
 	if err != nil {
-		secLog.WithError(err).Errorf("controllers/key_controller:Transfer() %s : Unable to read request body", commLogMsg.InvalidInputBadEncoding)
-		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to read request body"}
+		secLog.WithError(err).Errorf("controllers/key_controller:BindImageFlavor() %s : Failed to decode request body as KeyImageFlavorBinding", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
 	}
 
-	// Decode public key in request
-	key, err := crypt.GetPublicKeyFromPem(bytes)
+	if binding.ImageFlavorID == uuid.Nil {
+		secLog.Errorf("controllers/key_controller:BindImageFlavor() %s : Image flavor ID is not provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Image flavor ID must be specified"}
+	}
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	currentKey, status, err := kc.retrieveTenantKey(request, id)
 	if err != nil {
-		secLog.WithError(err).Errorf("controllers/key_controller:Transfer() %s : Public key decode failed", commLogMsg.InvalidInputBadParam)
-		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to decode public key"}
+		return nil, status, err
+	}
+	if status, err := checkIfMatch(responseWriter, request, currentKey.Version); err != nil {
+		return nil, status, err
 	}
-	envelopeKey := key.(*rsa.PublicKey)
 
-	// Wrap key with public key
+	key, err := kc.remoteManager.BindImageFlavor(id, &binding.ImageFlavorID)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:BindImageFlavor() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_controller:BindImageFlavor() Key image flavor binding failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to bind key to image flavor"}
+		}
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:BindImageFlavor() %s: Key bound to image flavor %s by: %s", commLogMsg.PrivilegeModified, binding.ImageFlavorID, request.RemoteAddr)
+	setETag(responseWriter, key.Version)
+	return key, http.StatusOK, nil
+}
+
+//UnbindImageFlavor : Function to remove the image flavor binding of key
+func (kc KeyController) UnbindImageFlavor(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:UnbindImageFlavor() Entering")
+	defer defaultLog.Trace("controllers/key_controller:UnbindImageFlavor() Leaving")
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
 	id := uuid.MustParse(mux.Vars(request)["id"])
+	currentKey, status, err := kc.retrieveTenantKey(request, id)
+	if err != nil {
+		return nil, status, err
+	}
+	if status, err := checkIfMatch(responseWriter, request, currentKey.Version); err != nil {
+		return nil, status, err
+	}
+
+	key, err := kc.remoteManager.BindImageFlavor(id, nil)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:UnbindImageFlavor() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_controller:UnbindImageFlavor() Key image flavor unbinding failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to remove image flavor binding of key"}
+		}
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:UnbindImageFlavor() %s: Key image flavor binding removed by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	setETag(responseWriter, key.Version)
+	return nil, http.StatusNoContent, nil
+}
+
+//UpdateAttributes : Function to replace the attributes of key
+func (kc KeyController) UpdateAttributes(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:UpdateAttributes() Entering")
+	defer defaultLog.Trace("controllers/key_controller:UpdateAttributes() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_controller:UpdateAttributes() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var update kbs.KeyAttributesUpdate
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&update)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:UpdateAttributes() %s : Failed to decode request body as KeyAttributesUpdate", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err = validateKeyAttributes(update.Attributes); err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:UpdateAttributes() %s : Invalid key attributes", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	currentKey, status, err := kc.retrieveTenantKey(request, id)
+	if err != nil {
+		return nil, status, err
+	}
+	if status, err := checkIfMatch(responseWriter, request, currentKey.Version); err != nil {
+		return nil, status, err
+	}
+
+	key, err := kc.remoteManager.UpdateAttributes(id, update.Attributes)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:UpdateAttributes() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_controller:UpdateAttributes() Key attributes update failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update key attributes"}
+		}
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:UpdateAttributes() %s: Key attributes updated by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	setETag(responseWriter, key.Version)
+	return key, http.StatusOK, nil
+}
+
+//Transfer : Function to perform key transfer with public key
+func (kc KeyController) Transfer(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:Transfer() Entering")
+	defer defaultLog.Trace("controllers/key_controller:Transfer() Leaving")
+
+	id, envelopeKey, currentKey, status, err := kc.readEnvelopeKeyTransferRequest(request)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// Wrap key with public key
 	wrappedKey, status, err := kc.wrapSecretKey(id, envelopeKey, sha512.New384(), nil)
 	if err != nil {
 		return nil, status, err
 	}
 
 	transferKeyResponse := kbs.KeyTransferAttributes{
-		KeyId:   id,
-		KeyData: base64.StdEncoding.EncodeToString(wrappedKey.([]byte)),
+		KeyId:            id,
+		KeyData:          base64.StdEncoding.EncodeToString(wrappedKey.([]byte)),
+		InjectionTargets: currentKey.InjectionTargets,
 	}
 
 	secLog.WithField("Id", id).Infof("controllers/key_controller:Transfer() %s: Key transferred using Envelope key by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
 	return transferKeyResponse, http.StatusOK, nil
 }
 
+//TransferAsJwe : Function to perform key transfer with public key, the key is returned as an RFC 7516 JWE object in
+//compact serialization (RSA-OAEP-256 and A256GCM) whose key ID is the ID of the transferred key
+func (kc KeyController) TransferAsJwe(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:TransferAsJwe() Entering")
+	defer defaultLog.Trace("controllers/key_controller:TransferAsJwe() Leaving")
+
+	id, envelopeKey, _, status, err := kc.readEnvelopeKeyTransferRequest(request)
+	if err != nil {
+		return nil, status, err
+	}
+
+	secretKey, err := kc.remoteManager.TransferKey(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:TransferAsJwe() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		}
+		defaultLog.WithError(err).Error("controllers/key_controller:TransferAsJwe() Key transfer failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to transfer Key"}
+	}
+	defer crypt.Zeroize(secretKey)
+
+	jwe, err := crypt.EncryptJwe(secretKey, envelopeKey, id.String(), "")
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_controller:TransferAsJwe() Key encryption failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to wrap key"}
+	}
+
+	responseWriter.Header().Set("Content-Type", constants.HTTPMediaTypeJose)
+	secLog.WithField("Id", id).Infof("controllers/key_controller:TransferAsJwe() %s: Key transferred as JWE using Envelope key by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	return []byte(jwe), http.StatusOK, nil
+}
+
+// readEnvelopeKeyTransferRequest reads the envelope public key of a key transfer request and retrieves the key of
+// the tenant of the request
+func (kc KeyController) readEnvelopeKeyTransferRequest(request *http.Request) (uuid.UUID, *rsa.PublicKey, *kbs.KeyResponse, int, error) {
+	defaultLog.Trace("controllers/key_controller:readEnvelopeKeyTransferRequest() Entering")
+	defer defaultLog.Trace("controllers/key_controller:readEnvelopeKeyTransferRequest() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypePlain {
+		return uuid.Nil, nil, nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_controller:readEnvelopeKeyTransferRequest() The request body was not provided")
+		return uuid.Nil, nil, nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	// Decode the incoming json data to note struct
+	bytes, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:readEnvelopeKeyTransferRequest() %s : Unable to read request body", commLogMsg.InvalidInputBadEncoding)
+		return uuid.Nil, nil, nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to read request body"}
+	}
+
+	// Decode public key in request
+	key, err := crypt.GetPublicKeyFromPem(bytes)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:readEnvelopeKeyTransferRequest() %s : Public key decode failed", commLogMsg.InvalidInputBadParam)
+		return uuid.Nil, nil, nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to decode public key"}
+	}
+	envelopeKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		secLog.Errorf("controllers/key_controller:readEnvelopeKeyTransferRequest() %s : Public key is not an RSA key", commLogMsg.InvalidInputBadParam)
+		return uuid.Nil, nil, nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to decode public key"}
+	}
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	currentKey, status, err := kc.retrieveTenantKey(request, id)
+	if err != nil {
+		return uuid.Nil, nil, nil, status, err
+	}
+
+	if status, err := kc.validateImageFlavorBinding(request, id); err != nil {
+		return uuid.Nil, nil, nil, status, err
+	}
+	return id, envelopeKey, currentKey, http.StatusOK, nil
+}
+
 //TransferWithSaml : Function to perform key transfer with saml report
 func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_controller:TransferWithSaml() Entering")
@@ -284,6 +550,12 @@ func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, req
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to read request body"}
 	}
 
+	err = validation.ValidateXMLDocument(bytes)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:TransferWithSaml() %s : Invalid saml report", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid saml report"}
+	}
+
 	// Unmarshal saml report in request
 	var samlReport *saml.Saml
 	err = xml.Unmarshal(bytes, &samlReport)
@@ -299,6 +571,10 @@ func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, req
 		secLog.Error("controllers/key_controller:TransferWithSaml() Saml report is not trusted")
 		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Client not trusted by Hvs"}
 	}
+
+	if status, err := kc.validateImageFlavorBinding(request, id); err != nil {
+		return nil, status, err
+	}
 	envelopeKey := bindingCert.PublicKey.(*rsa.PublicKey)
 
 	// Wrap key with binding key
@@ -311,6 +587,156 @@ func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, req
 	return wrappedKey, http.StatusOK, nil
 }
 
+//TransferWithTpm2 : Function to perform key transfer as a duplication blob to the storage key of an attested TPM
+func (kc KeyController) TransferWithTpm2(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:TransferWithTpm2() Entering")
+	defer defaultLog.Trace("controllers/key_controller:TransferWithTpm2() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_controller:TransferWithTpm2() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var transferRequest kbs.Tpm2KeyTransferRequest
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&transferRequest)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:TransferWithTpm2() %s : Failed to decode request body as Tpm2KeyTransferRequest", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if len(transferRequest.ParentPublic) == 0 || len(transferRequest.ParentCertifyInfo) == 0 || len(transferRequest.ParentCertifySignature) == 0 {
+		secLog.Errorf("controllers/key_controller:TransferWithTpm2() %s : Storage key public area or certification not provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Storage key public area and certification must be specified"}
+	}
+
+	err = validation.ValidateXMLDocument([]byte(transferRequest.SamlReport))
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:TransferWithTpm2() %s : Invalid saml report", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid saml report"}
+	}
+
+	// Unmarshal saml report in request
+	var samlReport *saml.Saml
+	err = xml.Unmarshal([]byte(transferRequest.SamlReport), &samlReport)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:TransferWithTpm2() %s : Saml report unmarshal failed", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to unmarshal saml report"}
+	}
+
+	// Validate saml report and storage key in request
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	trusted, parent := keytransfer.IsTpmParentTrustedByHvs(samlReport, &transferRequest, id, kc.config, kc.remoteManager)
+	if !trusted {
+		secLog.Error("controllers/key_controller:TransferWithTpm2() Saml report or storage key is not trusted")
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Client not trusted by Hvs"}
+	}
+
+	if status, err := kc.validateImageFlavorBinding(request, id); err != nil {
+		return nil, status, err
+	}
+
+	key, err := kc.remoteManager.RetrieveKey(id)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_controller:TransferWithTpm2() Key retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
+	}
+
+	secretKey, err := kc.remoteManager.TransferKey(id)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_controller:TransferWithTpm2() Key transfer failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to transfer Key"}
+	}
+	defer crypt.Zeroize(secretKey)
+
+	// Wrap key to the storage key
+	object, sensitive, err := keytransfer.NewTpm2Object(key.KeyInformation.Algorithm, secretKey, transferRequest.AuthPolicy)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_controller:TransferWithTpm2() Key can not be imported in a TPM")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Key can not be imported in a TPM"}
+	}
+	duplication, err := tpm2.Duplicate(parent, object, sensitive)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_controller:TransferWithTpm2() Wrap key failed")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to wrap key to the storage key"}
+	}
+
+	transferKeyResponse := kbs.Tpm2KeyTransferResponse{
+		KeyId:        id,
+		KeyAlgorithm: key.KeyInformation.Algorithm,
+		KeyLength:    key.KeyInformation.KeyLength,
+		ObjectPublic: duplication.ObjectPublic,
+		Duplicate:    duplication.Duplicate,
+		InSymSeed:    duplication.InSymSeed,
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:TransferWithTpm2() %s: Key transferred to TPM storage key by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	return transferKeyResponse, http.StatusOK, nil
+}
+
+// retrieveTenantKey retrieves a key of the tenant of the request, the keys of other tenants are reported as not found
+func (kc KeyController) retrieveTenantKey(request *http.Request, id uuid.UUID) (*kbs.KeyResponse, int, error) {
+	defaultLog.Trace("controllers/key_controller:retrieveTenantKey() Entering")
+	defer defaultLog.Trace("controllers/key_controller:retrieveTenantKey() Leaving")
+
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:retrieveTenantKey() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	key, err := kc.remoteManager.RetrieveKey(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:retrieveTenantKey() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		}
+		defaultLog.WithError(err).Error("controllers/key_controller:retrieveTenantKey() Key retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
+	}
+
+	if key.TenantID != tenantId {
+		secLog.WithField("Id", id).Errorf("controllers/key_controller:retrieveTenantKey() %s : Key belongs to another tenant, request from: %s", commLogMsg.UnauthorizedAccess, request.RemoteAddr)
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+	}
+	return key, http.StatusOK, nil
+}
+
+// validateImageFlavorBinding checks that the image flavor reported for the workload requesting the key matches
+// the image flavor the key is bound to, keys that are not bound can be transferred for any workload
+func (kc KeyController) validateImageFlavorBinding(request *http.Request, id uuid.UUID) (int, error) {
+	defaultLog.Trace("controllers/key_controller:validateImageFlavorBinding() Entering")
+	defer defaultLog.Trace("controllers/key_controller:validateImageFlavorBinding() Leaving")
+
+	key, err := kc.remoteManager.RetrieveKey(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:validateImageFlavorBinding() Key with specified id could not be located")
+			return http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		}
+		defaultLog.WithError(err).Error("controllers/key_controller:validateImageFlavorBinding() Key retrieve failed")
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
+	}
+
+	if key.ImageFlavorID == nil {
+		return http.StatusOK, nil
+	}
+
+	imageFlavorId, err := uuid.Parse(request.Header.Get(kbs.ImageFlavorIDHeader))
+	if err != nil || imageFlavorId != *key.ImageFlavorID {
+		secLog.WithField("Id", id).Errorf("controllers/key_controller:validateImageFlavorBinding() %s : Image flavor of the workload does not match the image flavor the key is bound to, request from: %s", commLogMsg.UnauthorizedAccess, request.RemoteAddr)
+		return http.StatusUnauthorized, &commErr.ResourceError{Message: "Workload image flavor does not match the key binding"}
+	}
+	return http.StatusOK, nil
+}
+
 func (kc KeyController) wrapSecretKey(id uuid.UUID, publicKey *rsa.PublicKey, hash hash.Hash, label []byte) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_controller:wrapSecretKey() Entering")
 	defer defaultLog.Trace("controllers/key_controller:wrapSecretKey() Leaving")
@@ -325,6 +751,7 @@ func (kc KeyController) wrapSecretKey(id uuid.UUID, publicKey *rsa.PublicKey, ha
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to transfer Key"}
 		}
 	}
+	defer crypt.Zeroize(secretKey)
 
 	// Wrap secret key with public key
 	wrappedKey, err := rsa.EncryptOAEP(hash, rand.Reader, publicKey, secretKey, label)
@@ -354,6 +781,16 @@ func validateKeyCreateRequest(requestKey kbs.KeyRequest) error {
 		} else if !allowedCurveTypes[requestKey.KeyInformation.CurveType] {
 			return errors.New("curve_type is not supported")
 		}
+	} else if strings.ToUpper(algorithm) == consts.CRYPTOALG_AES_XTS {
+		if requestKey.KeyInformation.KeyLength == 0 {
+			return errors.New("Key length is missing")
+		} else if !allowedXtsKeyLengths[requestKey.KeyInformation.KeyLength] {
+			return errors.New("key_length is not supported")
+		}
+		// the volume keys are generated by KBS, so that the data key and the tweak key are different
+		if requestKey.KeyInformation.KeyString != "" || requestKey.KeyInformation.KmipKeyID != "" {
+			return errors.New("AES-XTS keys cannot be registered")
+		}
 	} else {
 		if requestKey.KeyInformation.KeyLength == 0 {
 			return errors.New("Key length is missing")
@@ -386,9 +823,105 @@ func validateKeyCreateRequest(requestKey kbs.KeyRequest) error {
 		}
 	}
 
+	switch requestKey.StorageClass {
+	case "", consts.KeyStoragePersistent:
+		if requestKey.TTL != 0 {
+			return errors.New("ttl is only supported for ephemeral keys")
+		}
+	case consts.KeyStorageEphemeral:
+		if requestKey.TTL <= 0 || requestKey.TTL > consts.MaxEphemeralKeyTTLInSec {
+			return errors.Errorf("ttl of ephemeral keys must be between 1 and %d seconds", consts.MaxEphemeralKeyTTLInSec)
+		}
+		// keys of the kmip server are already persisted outside of KBS
+		if kmipKeyID != "" {
+			return errors.New("kmip_key_id cannot be registered as an ephemeral key")
+		}
+	default:
+		return errors.New("storage_class is not supported")
+	}
+
+	for _, target := range requestKey.InjectionTargets {
+		if err := validateInjectionTarget(target); err != nil {
+			return err
+		}
+	}
+
+	return validateKeyAttributes(requestKey.Attributes)
+}
+
+//validateKeyAttributes checks the names and values of the attributes of a key
+func validateKeyAttributes(attributes map[string]string) error {
+	defaultLog.Trace("controllers/key_controller:validateKeyAttributes() Entering")
+	defer defaultLog.Trace("controllers/key_controller:validateKeyAttributes() Leaving")
+
+	if len(attributes) > consts.MaxKeyAttributes {
+		return errors.Errorf("a key can have at most %d attributes", consts.MaxKeyAttributes)
+	}
+	for name, value := range attributes {
+		if err := validateKeyAttribute(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateKeyAttribute(name, value string) error {
+	if !keyAttributeNameRegex.MatchString(name) {
+		return errors.New("attribute names must be alphanumeric, with -, _, . or / inside and at most 63 characters")
+	}
+	if len(value) >= validation.MaxLen || validation.ValidateStrings([]string{value}) != nil {
+		return errors.Errorf("valid contents for the value of attribute %s must be specified", name)
+	}
 	return nil
 }
 
+//validateInjectionTarget checks that the agent on the attested node can deliver the secret to the target
+func validateInjectionTarget(target kbs.InjectionTarget) error {
+	defaultLog.Trace("controllers/key_controller:validateInjectionTarget() Entering")
+	defer defaultLog.Trace("controllers/key_controller:validateInjectionTarget() Leaving")
+
+	switch target.Type {
+	case kbs.InjectionTargetKubernetesSecret:
+		if !kubernetesNameRegex.MatchString(target.Name) || !kubernetesNameRegex.MatchString(target.Namespace) {
+			return errors.New("name and namespace of kubernetes-secret injection targets must be valid kubernetes names")
+		}
+		if target.Key != "" && !kubernetesSecretKeyRegex.MatchString(target.Key) {
+			return errors.New("key of kubernetes-secret injection targets must be a valid kubernetes secret key")
+		}
+		if target.Path != "" || target.Template != "" {
+			return errors.New("path and template are not supported for kubernetes-secret injection targets")
+		}
+	case kbs.InjectionTargetFile, kbs.InjectionTargetEnvTemplate:
+		if !isRelativeInjectionPath(target.Path) {
+			return errors.Errorf("path of %s injection targets must be a relative path without parent directories", target.Type)
+		}
+		if target.Name != "" || target.Namespace != "" || target.Key != "" {
+			return errors.Errorf("name, namespace and key are not supported for %s injection targets", target.Type)
+		}
+		if target.Type == kbs.InjectionTargetFile && target.Template != "" {
+			return errors.New("template is not supported for file injection targets")
+		}
+		if target.Type == kbs.InjectionTargetEnvTemplate {
+			if target.Template == "" {
+				return errors.New("template of env-template injection targets must be specified")
+			}
+			if _, err := template.New(target.Path).Parse(target.Template); err != nil {
+				return errors.New("template of env-template injection targets must be a valid template")
+			}
+		}
+	default:
+		return errors.New("injection target type is not supported")
+	}
+	return nil
+}
+
+func isRelativeInjectionPath(path string) bool {
+	if path == "" || filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return false
+	}
+	return path != ".." && !strings.HasPrefix(path, "../") && validation.ValidateStrings([]string{path}) == nil
+}
+
 //getKeyFilterCriteria checks for set filter params in the Search request and returns a valid KeyFilterCriteria
 func getKeyFilterCriteria(params url.Values) (*models.KeyFilterCriteria, error) {
 	defaultLog.Trace("controllers/key_controller:getKeyFilterCriteria() Entering")
@@ -410,7 +943,7 @@ func getKeyFilterCriteria(params url.Values) (*models.KeyFilterCriteria, error)
 		if err != nil {
 			return nil, errors.Wrap(err, "Invalid keyLength query param value, must be Integer")
 		}
-		if !allowedKeyLengths[length] {
+		if !allowedKeyLengths[length] && !allowedXtsKeyLengths[length] {
 			return nil, errors.New("Valid keyLength must be specified")
 		}
 		criteria.KeyLength = length
@@ -433,6 +966,39 @@ func getKeyFilterCriteria(params url.Values) (*models.KeyFilterCriteria, error)
 		criteria.TransferPolicyId = id
 	}
 
+	// attribute, repeated as name:value
+	for _, param := range params["attribute"] {
+		nameValue := strings.SplitN(strings.TrimSpace(param), ":", 2)
+		if len(nameValue) != 2 {
+			return nil, errors.New("Valid attribute (name:value) must be specified")
+		}
+		if err := validateKeyAttribute(nameValue[0], nameValue[1]); err != nil {
+			return nil, errors.Wrap(err, "Valid attribute (name:value) must be specified")
+		}
+		if criteria.Attributes == nil {
+			criteria.Attributes = make(map[string]string)
+		}
+		criteria.Attributes[nameValue[0]] = nameValue[1]
+	}
+
+	// createdAfter
+	if param := strings.TrimSpace(params.Get("createdAfter")); param != "" {
+		pTime, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Valid date (YYYY-MM-DDThh:mm:ssZ) for createdAfter must be specified")
+		}
+		criteria.CreatedAfter = pTime
+	}
+
+	// createdBefore
+	if param := strings.TrimSpace(params.Get("createdBefore")); param != "" {
+		pTime, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Valid date (YYYY-MM-DDThh:mm:ssZ) for createdBefore must be specified")
+		}
+		criteria.CreatedBefore = pTime
+	}
+
 	return &criteria, nil
 }
 
@@ -22,9 +22,11 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/mocks"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/memory"
 	kbsRoutes "github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
 	. "github.com/onsi/ginkgo"
@@ -45,6 +47,7 @@ var _ = Describe("KeyController", func() {
 	var w *httptest.ResponseRecorder
 	var keyStore *mocks.MockKeyStore
 	var policyStore *mocks.MockKeyTransferPolicyStore
+	var quotaStore *mocks.MockTenantQuotaStore
 	var remoteManager *keymanager.RemoteManager
 	var keyController *controllers.KeyController
 	var keyControllerConfig domain.KeyControllerConfig
@@ -75,6 +78,7 @@ var _ = Describe("KeyController", func() {
 		router = mux.NewRouter()
 		keyStore = mocks.NewFakeKeyStore()
 		policyStore = mocks.NewFakeKeyTransferPolicyStore()
+		quotaStore = mocks.NewFakeTenantQuotaStore()
 		newId, err := uuid.NewRandom()
 		Expect(err).NotTo(HaveOccurred())
 		keyControllerConfig = domain.KeyControllerConfig{
@@ -85,8 +89,8 @@ var _ = Describe("KeyController", func() {
 		}
 
 		keyManager := &keymanager.DirectoryManager{}
-		remoteManager = keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
-		keyController = controllers.NewKeyController(remoteManager, policyStore, keyControllerConfig)
+		remoteManager = keymanager.NewRemoteManager(keyStore, memory.NewKeyStore(), keyManager, endpointUrl)
+		keyController = controllers.NewKeyController(remoteManager, policyStore, quotaStore, keyControllerConfig)
 	})
 
 	// Specs for HTTP Post to "/keys"
@@ -235,6 +239,53 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Provide a Create request that contains invalid AES-XTS key length", func() {
+			It("Should fail to create new Key", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				keyJson := `{
+								"key_information": {
+									"algorithm": "AES-XTS",
+									"key_length": 128
+								}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a Create request that registers an AES-XTS key", func() {
+			It("Should fail to create new Key", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				keyJson := `{
+								"key_information": {
+									"algorithm": "AES-XTS",
+									"key_length": 512,
+									"kmip_key_id": "1"
+								}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 		Context("Provide a Create request without curve type", func() {
 			It("Should fail to create new Key", func() {
 				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
@@ -280,6 +331,148 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Provide a valid Create request for an ephemeral key", func() {
+			It("Should create a new Key that is not kept in the key store", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				keyJson := `{
+								"key_information": {
+									"algorithm": "AES",
+									"key_length": 256
+								},
+								"storage_class": "ephemeral",
+								"ttl": 300
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+
+				permissions := aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.KeyCreate},
+				}
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{permissions})
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var keyResponse kbs.KeyResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &keyResponse)).To(Succeed())
+				Expect(keyResponse.StorageClass).To(Equal(constants.KeyStorageEphemeral))
+				Expect(keyResponse.ExpiresAt).NotTo(BeNil())
+
+				_, err = keyStore.Retrieve(keyResponse.KeyInformation.ID)
+				Expect(err).To(HaveOccurred())
+				_, err = remoteManager.RetrieveKey(keyResponse.KeyInformation.ID)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		Context("Provide a Create request for an ephemeral key without ttl", func() {
+			It("Should fail to create new Key", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				keyJson := `{
+								"key_information": {
+									"algorithm": "AES",
+									"key_length": 256
+								},
+								"storage_class": "ephemeral"
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a valid Create request with injection targets", func() {
+			It("Should create a new Key with its injection targets", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				keyJson := `{
+								"key_information": {
+									"algorithm": "AES",
+									"key_length": 256
+								},
+								"injection_targets": [
+									{
+										"type": "kubernetes-secret",
+										"name": "db-credentials",
+										"namespace": "default"
+									},
+									{
+										"type": "env-template",
+										"path": "db/credentials.env",
+										"template": "DB_KEY={{.SecretBase64}}"
+									}
+								]
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+
+				permissions := aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.KeyCreate},
+				}
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{permissions})
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var keyResponse kbs.KeyResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &keyResponse)).To(Succeed())
+				Expect(keyResponse.InjectionTargets).To(HaveLen(2))
+				Expect(keyResponse.InjectionTargets[0].Name).To(Equal("db-credentials"))
+			})
+		})
+		Context("Provide a Create request with a file injection target outside of the secrets directory", func() {
+			It("Should fail to create new Key", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				keyJson := `{
+								"key_information": {
+									"algorithm": "AES",
+									"key_length": 256
+								},
+								"injection_targets": [
+									{
+										"type": "file",
+										"path": "../etc/passwd"
+									}
+								]
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 
 	Describe("Register a new Key", func() {
@@ -435,6 +628,207 @@ var _ = Describe("KeyController", func() {
 		})
 	})
 
+	// Specs for HTTP Put to "/keys/{id}/image-flavor-binding"
+	Describe("Bind a Key to an image flavor", func() {
+		bindImageFlavor := func(keyId string) *httptest.ResponseRecorder {
+			router.Handle("/keys/{id}/image-flavor-binding", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.BindImageFlavor))).Methods("PUT")
+			req, err := http.NewRequest(
+				"PUT",
+				"/keys/"+keyId+"/image-flavor-binding",
+				strings.NewReader(`{"image_flavor_id": "4bcaef54-01a5-45e6-9c25-4b7b4a6a2cc3"}`),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		transferKey := func(imageFlavorId string) *httptest.ResponseRecorder {
+			router.Handle("/keys/{id}/transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Transfer))).Methods("POST")
+			req, err := http.NewRequest(
+				"POST",
+				"/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/transfer",
+				strings.NewReader(string(validEnvelopeKey)),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypePlain)
+			if imageFlavorId != "" {
+				req.Header.Set(kbs.ImageFlavorIDHeader, imageFlavorId)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		Context("Provide an image flavor for an existing Key", func() {
+			It("Should bind the Key and only transfer it for the image flavor", func() {
+				w = bindImageFlavor("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var keyResponse kbs.KeyResponse
+				err := json.Unmarshal(w.Body.Bytes(), &keyResponse)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keyResponse.ImageFlavorID.String()).To(Equal("4bcaef54-01a5-45e6-9c25-4b7b4a6a2cc3"))
+
+				w = transferKey("")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+
+				w = transferKey("1d9b5a1e-4c1e-4f4b-8a8d-2a5f0a3c6e11")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+
+				w = transferKey("4bcaef54-01a5-45e6-9c25-4b7b4a6a2cc3")
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+		Context("Provide an image flavor for a Key modified since it was retrieved", func() {
+			It("Should fail to bind the Key with the current version", func() {
+				w = bindImageFlavor("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("ETag")).To(Equal(`"1"`))
+
+				router.Handle("/keys/{id}/image-flavor-binding", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.BindImageFlavor))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/image-flavor-binding",
+					strings.NewReader(`{"image_flavor_id": "1d9b5a1e-4c1e-4f4b-8a8d-2a5f0a3c6e11"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				req.Header.Set("If-Match", `"0"`)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+				Expect(w.Header().Get("ETag")).To(Equal(`"1"`))
+			})
+		})
+		Context("Provide an image flavor for a non-existent Key", func() {
+			It("Should fail to bind the Key", func() {
+				w = bindImageFlavor("73755fda-c910-46be-821f-e8ddeab189e9")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("Update the attributes of a Key", func() {
+		updateAttributes := func(keyId, body string) *httptest.ResponseRecorder {
+			router.Handle("/keys/{id}/attributes", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.UpdateAttributes))).Methods("PUT")
+			req, err := http.NewRequest(
+				"PUT",
+				"/keys/"+keyId+"/attributes",
+				strings.NewReader(body),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		searchKeys := func(query string) []kbs.KeyResponse {
+			router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+			req, err := http.NewRequest("GET", "/keys?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var keyResponses []kbs.KeyResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &keyResponses)
+			Expect(err).NotTo(HaveOccurred())
+			return keyResponses
+		}
+
+		Context("Provide attributes for an existing Key", func() {
+			It("Should replace the attributes of the Key and find the Key by its attributes", func() {
+				w = updateAttributes("ee37c360-7eae-4250-a677-6ee12adce8e2", `{"attributes": {"env": "prod", "team": "payments"}}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("ETag")).To(Equal(`"1"`))
+
+				var keyResponse kbs.KeyResponse
+				err := json.Unmarshal(w.Body.Bytes(), &keyResponse)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keyResponse.Attributes).To(Equal(map[string]string{"env": "prod", "team": "payments"}))
+
+				w = updateAttributes("e57e5ea0-d465-461e-882d-1600090caa0d", `{"attributes": {"env": "prod"}}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				Expect(searchKeys("attribute=env:prod")).To(HaveLen(2))
+				keyResponses := searchKeys("attribute=env:prod&attribute=team:payments")
+				Expect(keyResponses).To(HaveLen(1))
+				Expect(keyResponses[0].KeyInformation.ID.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(searchKeys("attribute=env:prod&algorithm=EC")).To(HaveLen(1))
+				Expect(searchKeys("attribute=env:dev")).To(BeEmpty())
+
+				w = updateAttributes("ee37c360-7eae-4250-a677-6ee12adce8e2", `{"attributes": {}}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(searchKeys("attribute=team:payments")).To(BeEmpty())
+			})
+		})
+		Context("Provide attributes with an invalid name", func() {
+			It("Should fail to update the attributes of the Key", func() {
+				w = updateAttributes("ee37c360-7eae-4250-a677-6ee12adce8e2", `{"attributes": {"env:name": "prod"}}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide attributes for a non-existent Key", func() {
+			It("Should fail to update the attributes of the Key", func() {
+				w = updateAttributes("73755fda-c910-46be-821f-e8ddeab189e9", `{"attributes": {"env": "prod"}}`)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("Transfer using public key as a JWE object", func() {
+		transferKeyAsJwe := func(id string, envelopeKey string) *httptest.ResponseRecorder {
+			router.Handle("/keys/{id}/transfer", kbsRoutes.ErrorHandler(kbsRoutes.ResponseHandler(keyController.TransferAsJwe))).Methods("POST")
+			req, err := http.NewRequest("POST", "/keys/"+id+"/transfer", strings.NewReader(envelopeKey))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJose)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypePlain)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		Context("Provide a valid public key", func() {
+			It("Should transfer an existing Key as a JWE object the private key decrypts", func() {
+				privateKey, err := rsa.GenerateKey(rand.Reader, 3072)
+				Expect(err).NotTo(HaveOccurred())
+				publicKeyDer, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+				Expect(err).NotTo(HaveOccurred())
+				envelopeKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDer})
+
+				w = transferKeyAsJwe("ee37c360-7eae-4250-a677-6ee12adce8e2", string(envelopeKey))
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("Content-Type")).To(Equal(consts.HTTPMediaTypeJose))
+
+				header, _, err := crypt.DecryptJwe(w.Body.String(), privateKey)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(header.Alg).To(Equal(crypt.JweAlgRsaOaep256))
+				Expect(header.Enc).To(Equal(crypt.JweEncA256Gcm))
+				Expect(header.Kid).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+			})
+		})
+		Context("Provide a public key without PUBLIC KEY headers", func() {
+			It("Should fail to transfer Key", func() {
+				w = transferKeyAsJwe("ee37c360-7eae-4250-a677-6ee12adce8e2", invalidEnvelopeKey)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a valid public key for a non-existent Key", func() {
+			It("Should fail to transfer Key", func() {
+				w = transferKeyAsJwe("73755fda-c910-46be-821f-e8ddeab189e9", string(validEnvelopeKey))
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	Describe("Transfer using saml report", func() {
 		Context("Provide a valid saml report", func() {
 			It("Should transfer an existing Key", func() {
@@ -510,6 +904,72 @@ var _ = Describe("KeyController", func() {
 		})
 	})
 
+	Describe("Transfer to a TPM storage key using saml report", func() {
+		Context("Provide a valid saml report and a storage key not certified by the AIK", func() {
+			It("Should fail to transfer Key", func() {
+				router.Handle("/keys/{id}/tpm2-transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.TransferWithTpm2))).Methods("POST")
+				transferRequest := kbs.Tpm2KeyTransferRequest{
+					SamlReport:             string(validSamlReport),
+					ParentPublic:           []byte("parent public"),
+					ParentCertifyInfo:      []byte("certify info"),
+					ParentCertifySignature: []byte("certify signature"),
+				}
+				transferRequestJson, _ := json.Marshal(transferRequest)
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/tpm2-transfer",
+					strings.NewReader(string(transferRequestJson)),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("Provide a request without the storage key certification", func() {
+			It("Should fail to transfer Key", func() {
+				router.Handle("/keys/{id}/tpm2-transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.TransferWithTpm2))).Methods("POST")
+				transferRequest := kbs.Tpm2KeyTransferRequest{
+					SamlReport:   string(validSamlReport),
+					ParentPublic: []byte("parent public"),
+				}
+				transferRequestJson, _ := json.Marshal(transferRequest)
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/tpm2-transfer",
+					strings.NewReader(string(transferRequestJson)),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a saml report as the request body", func() {
+			It("Should fail to transfer Key", func() {
+				router.Handle("/keys/{id}/tpm2-transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.TransferWithTpm2))).Methods("POST")
+
+				req, err := http.NewRequest(
+					"POST",
+					"/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/tpm2-transfer",
+					strings.NewReader(string(validSamlReport)),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeSaml)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+	})
+
 	// Specs for HTTP Get to "/keys/{id}"
 	Describe("Retrieve an existing Key", func() {
 		Context("Retrieve Key by ID", func() {
@@ -616,6 +1076,41 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Get all the Keys with a creation date range", func() {
+			It("Should get list of all the Keys created in the range", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+				for query, count := range map[string]int{
+					"createdAfter=2020-01-01T00:00:00Z":                                    3,
+					"createdBefore=2020-01-01T00:00:00Z":                                   0,
+					"createdAfter=2020-01-01T00:00:00Z&createdBefore=2999-01-01T00:00:00Z": 3,
+					"createdAfter=2999-01-01T00:00:00Z":                                    0,
+				} {
+					req, err := http.NewRequest("GET", "/keys?"+query, nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+					w = httptest.NewRecorder()
+					router.ServeHTTP(w, req)
+					Expect(w.Code).To(Equal(http.StatusOK))
+
+					var keyResponses []kbs.KeyResponse
+					json.Unmarshal(w.Body.Bytes(), &keyResponses)
+					Expect(keyResponses).To(HaveLen(count), query)
+				}
+			})
+		})
+		Context("Get all the Keys with invalid createdBefore or attribute param", func() {
+			It("Should fail to get Keys", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+				for _, query := range []string{"createdBefore=2020-01-01", "attribute=env", "attribute=env$:prod"} {
+					req, err := http.NewRequest("GET", "/keys?"+query, nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+					w = httptest.NewRecorder()
+					router.ServeHTTP(w, req)
+					Expect(w.Code).To(Equal(http.StatusBadRequest), query)
+				}
+			})
+		})
 		Context("Get all the Keys with valid keyLength param", func() {
 			It("Should get list of all the filtered Keys", func() {
 				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
@@ -698,4 +1193,89 @@ var _ = Describe("KeyController", func() {
 			})
 		})
 	})
+
+	// Specs for the isolation of the keys of the tenants
+	Describe("Manage the Keys of a tenant", func() {
+		tenantRequest := func(method, url, body string, rules []string) *http.Request {
+			req, err := http.NewRequest(method, url, strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req = context.SetUserRoles(req, []aas.RoleInfo{{Service: constants.ServiceName, Name: "KeyManager", Context: "tenant=tenant-a"}})
+			req = context.SetUserPermissions(req, []aas.PermissionInfo{{Service: constants.ServiceName, Rules: rules}})
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			return req
+		}
+		keyJson := `{
+						"key_information": {
+							"algorithm": "AES",
+							"key_length": 256
+						}
+					}`
+
+		Context("Create Keys for a tenant with a quota of two Keys", func() {
+			It("Should create the Keys in the namespace of the tenant until the quota is reached", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+				router.Handle("/keys/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Retrieve))).Methods("GET")
+
+				var tenantKey kbs.KeyResponse
+				for i := 0; i < 2; i++ {
+					w = httptest.NewRecorder()
+					router.ServeHTTP(w, tenantRequest("POST", "/keys", keyJson, []string{constants.KeyCreate}))
+					Expect(w.Code).To(Equal(http.StatusCreated))
+					err := json.Unmarshal(w.Body.Bytes(), &tenantKey)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(tenantKey.TenantID).To(Equal("tenant-a"))
+				}
+
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("POST", "/keys", keyJson, []string{constants.KeyCreate}))
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("GET", "/keys", "", nil))
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var keyResponses []kbs.KeyResponse
+				err := json.Unmarshal(w.Body.Bytes(), &keyResponses)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(keyResponses)).To(Equal(2))
+
+				keyId := tenantKey.KeyInformation.ID.String()
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("GET", "/keys/"+keyId, "", nil))
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				// the key is not visible from the default namespace
+				req, err := http.NewRequest("GET", "/keys/"+keyId, nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Retrieve a Key of the default namespace as a tenant", func() {
+			It("Should fail to retrieve the Key", func() {
+				router.Handle("/keys/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Retrieve))).Methods("GET")
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("GET", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2", "", nil))
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Create a Key with a Key Transfer Policy of another tenant", func() {
+			It("Should fail to create the Key", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods("POST")
+				policyJson := `{
+								"key_information": {
+									"algorithm": "AES",
+									"key_length": 256
+								},
+								"transfer_policy_id": "ee37c360-7eae-4250-a677-6ee12adce8e2"
+							}`
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("POST", "/keys", policyJson, []string{constants.KeyCreate}))
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
 })
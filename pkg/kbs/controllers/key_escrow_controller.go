@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/escrow"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+type KeyEscrowController struct {
+	keyEscrow domain.KeyEscrow
+}
+
+func NewKeyEscrowController(ke domain.KeyEscrow) *KeyEscrowController {
+	return &KeyEscrowController{
+		keyEscrow: ke,
+	}
+}
+
+//Initialize : Function to create the escrow KEK and split it among the custodians
+func (kec KeyEscrowController) Initialize(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_escrow_controller:Initialize() Entering")
+	defer defaultLog.Trace("controllers/key_escrow_controller:Initialize() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_escrow_controller:Initialize() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var escrowRequest kbs.KeyEscrowRequest
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&escrowRequest)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_escrow_controller:Initialize() %s : Failed to decode request body as KeyEscrowRequest", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if escrowRequest.Threshold < 2 || escrowRequest.Threshold > escrowRequest.Custodians || escrowRequest.Custodians > 255 {
+		secLog.Errorf("controllers/key_escrow_controller:Initialize() %s : Invalid number of custodians or threshold", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "threshold must be at least 2 and at most custodians, which cannot exceed 255"}
+	}
+
+	shares, err := kec.keyEscrow.Initialize(escrowRequest.Custodians, escrowRequest.Threshold)
+	if err != nil {
+		if errors.Cause(err) == escrow.ErrAlreadyInitialized {
+			defaultLog.Error("controllers/key_escrow_controller:Initialize() Key escrow is already initialized")
+			return nil, http.StatusConflict, &commErr.ResourceError{Message: "Key escrow is already initialized"}
+		}
+		defaultLog.WithError(err).Error("controllers/key_escrow_controller:Initialize() Key escrow initialize failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to initialize key escrow"}
+	}
+
+	secLog.WithField("KekId", shares.KekID).Infof("controllers/key_escrow_controller:Initialize() %s: Key escrow initialized by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	return shares, http.StatusCreated, nil
+}
+
+//Retrieve : Function to retrieve the status of the key escrow
+func (kec KeyEscrowController) Retrieve(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_escrow_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/key_escrow_controller:Retrieve() Leaving")
+
+	status, err := kec.keyEscrow.Status()
+	if err != nil {
+		return escrowError("Retrieve", err, "Failed to retrieve key escrow status")
+	}
+
+	secLog.Infof("controllers/key_escrow_controller:Retrieve() %s: Key escrow status retrieved by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return status, http.StatusOK, nil
+}
+
+//SubmitShare : Function to submit the share of a custodian for the recovery of the escrowed keys
+func (kec KeyEscrowController) SubmitShare(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_escrow_controller:SubmitShare() Entering")
+	defer defaultLog.Trace("controllers/key_escrow_controller:SubmitShare() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_escrow_controller:SubmitShare() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var escrowShare kbs.KeyEscrowShare
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&escrowShare)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_escrow_controller:SubmitShare() %s : Failed to decode request body as KeyEscrowShare", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	status, err := kec.keyEscrow.SubmitShare(escrowShare.Share)
+	if err != nil {
+		return escrowError("SubmitShare", err, "Failed to submit key escrow share")
+	}
+
+	secLog.Infof("controllers/key_escrow_controller:SubmitShare() %s: Key escrow share submitted by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	return status, http.StatusOK, nil
+}
+
+//DiscardShares : Function to discard the shares submitted for a recovery
+func (kec KeyEscrowController) DiscardShares(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_escrow_controller:DiscardShares() Entering")
+	defer defaultLog.Trace("controllers/key_escrow_controller:DiscardShares() Leaving")
+
+	kec.keyEscrow.DiscardShares()
+
+	secLog.Infof("controllers/key_escrow_controller:DiscardShares() Key escrow shares discarded by: %s", request.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+//Recover : Function to reconstruct the escrow KEK from the submitted shares and restore the escrowed keys
+func (kec KeyEscrowController) Recover(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_escrow_controller:Recover() Entering")
+	defer defaultLog.Trace("controllers/key_escrow_controller:Recover() Leaving")
+
+	recovery, err := kec.keyEscrow.Recover()
+	if err != nil {
+		return escrowError("Recover", err, "Failed to recover escrowed keys")
+	}
+
+	secLog.Infof("controllers/key_escrow_controller:Recover() %s: %d keys recovered from escrow by: %s", commLogMsg.PrivilegeModified, len(recovery.RestoredKeys), request.RemoteAddr)
+	return recovery, http.StatusOK, nil
+}
+
+// escrowError maps the errors of the key escrow to the response status
+func escrowError(function string, err error, message string) (interface{}, int, error) {
+	switch errors.Cause(err) {
+	case escrow.ErrNotInitialized:
+		defaultLog.Errorf("controllers/key_escrow_controller:%s() Key escrow is not initialized", function)
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key escrow is not initialized"}
+	case escrow.ErrInvalidShare, escrow.ErrNotEnoughShares, escrow.ErrInvalidShares:
+		secLog.WithError(err).Errorf("controllers/key_escrow_controller:%s() %s", function, message)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: errors.Cause(err).Error()}
+	}
+	defaultLog.WithError(err).Errorf("controllers/key_escrow_controller:%s() %s", function, message)
+	return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: message}
+}
@@ -6,11 +6,13 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
@@ -22,12 +24,14 @@ import (
 type KeyTransferPolicyController struct {
 	policyStore domain.KeyTransferPolicyStore
 	keyStore    domain.KeyStore
+	quotaStore  domain.TenantQuotaStore
 }
 
-func NewKeyTransferPolicyController(ps domain.KeyTransferPolicyStore, ks domain.KeyStore) *KeyTransferPolicyController {
+func NewKeyTransferPolicyController(ps domain.KeyTransferPolicyStore, ks domain.KeyStore, qs domain.TenantQuotaStore) *KeyTransferPolicyController {
 	return &KeyTransferPolicyController{
 		policyStore: ps,
 		keyStore:    ks,
+		quotaStore:  qs,
 	}
 }
 
@@ -56,18 +60,35 @@ func (ktpc KeyTransferPolicyController) Create(responseWriter http.ResponseWrite
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
 	}
 
-	if requestPolicy.SGXEnclaveIssuerAnyof == nil || requestPolicy.SGXEnclaveIssuerProductIDAnyof == nil {
-		secLog.Errorf("controllers/key_transfer_policy_controller:Create() %s : sgx_enclave_issuer_anyof and sgx_enclave_issuer_product_id_anyof must be specified", commLogMsg.InvalidInputBadParam)
-		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "sgx_enclave_issuer_anyof and sgx_enclave_issuer_product_id_anyof must be specified"}
+	if err := validateKeyTransferPolicy(&requestPolicy); err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
-	for _, enclaveIssuer := range requestPolicy.SGXEnclaveIssuerAnyof {
-		if err := validation.ValidateMrSignerString(enclaveIssuer); err != nil {
-			defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Create() Input validation failed for sgx enclave issuer anyof")
-			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Input validation failed for sgx enclave issuer anyof"}
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_policy_controller:Create() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	quota, err := retrieveTenantQuota(ktpc.quotaStore, tenantId)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Create() Tenant quota retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve tenant quota"}
+	}
+	if quota != nil && quota.MaxKeyTransferPolicies > 0 {
+		policies, err := ktpc.policyStore.Search(&models.KeyTransferPolicyFilterCriteria{TenantID: &tenantId})
+		if err != nil {
+			defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Create() Key transfer policy search failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search key transfer policies"}
+		}
+		if len(policies) >= quota.MaxKeyTransferPolicies {
+			secLog.WithField("TenantId", tenantId).Errorf("controllers/key_transfer_policy_controller:Create() Key transfer policy quota of tenant reached, request from: %s", request.RemoteAddr)
+			return nil, http.StatusForbidden, &commErr.ResourceError{Message: "Key transfer policy quota of the tenant has been reached"}
 		}
 	}
 
+	requestPolicy.TenantID = tenantId
+	requestPolicy.Version = 0
 	createdPolicy, err := ktpc.policyStore.Create(&requestPolicy)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Create() Key transfer policy create failed")
@@ -75,27 +96,55 @@ func (ktpc KeyTransferPolicyController) Create(responseWriter http.ResponseWrite
 	}
 
 	secLog.WithField("Id", createdPolicy.ID).Infof("controllers/key_transfer_policy_controller:Create() %s: Key Transfer Policy created by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	setETag(responseWriter, createdPolicy.Version)
 	return createdPolicy, http.StatusCreated, nil
 }
 
+//Update : Function to replace a key transfer policy, If-Match is matched against the version of the policy
+func (ktpc KeyTransferPolicyController) Update(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:Update() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:Update() Leaving")
+
+	requestPolicy, status, err := readKeyTransferPolicyUpdate(request)
+	if err != nil {
+		return nil, status, err
+	}
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	currentPolicy, status, err := ktpc.retrieveTenantPolicy(request, id)
+	if err != nil {
+		return nil, status, err
+	}
+	if status, err := checkIfMatch(responseWriter, request, currentPolicy.Version); err != nil {
+		return nil, status, err
+	}
+
+	updatedPolicy, status, err := ktpc.replacePolicy(currentPolicy, requestPolicy)
+	if err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_transfer_policy_controller:Update() %s: Key Transfer Policy updated by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	setETag(responseWriter, updatedPolicy.Version)
+	return updatedPolicy, http.StatusOK, nil
+}
+
 //Retrieve : Function to retrieve a key transfer policy
 func (ktpc KeyTransferPolicyController) Retrieve(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_transfer_policy_controller:Retrieve() Entering")
 	defer defaultLog.Trace("controllers/key_transfer_policy_controller:Retrieve() Leaving")
 
 	id := uuid.MustParse(mux.Vars(request)["id"])
-	transferPolicy, err := ktpc.policyStore.Retrieve(id)
+	transferPolicy, status, err := ktpc.retrieveTenantPolicy(request, id)
 	if err != nil {
-		if err.Error() == commErr.RecordNotFound {
-			defaultLog.Errorf("controllers/key_transfer_policy_controller:Retrieve() Key transfer policy with specified id could not be located")
-			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
-		} else {
-			defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Retrieve() Key transfer policy retrieve failed")
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key transfer policy"}
-		}
+		return nil, status, err
 	}
 
 	secLog.WithField("Id", id).Infof("controllers/key_transfer_policy_controller:Retrieve() %s: Key Transfer Policy retrieved by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	setETag(responseWriter, transferPolicy.Version)
 	return transferPolicy, http.StatusOK, nil
 }
 
@@ -105,30 +154,12 @@ func (ktpc KeyTransferPolicyController) Delete(responseWriter http.ResponseWrite
 	defer defaultLog.Trace("controllers/key_transfer_policy_controller:Delete() Leaving")
 
 	id := uuid.MustParse(mux.Vars(request)["id"])
-	criteria := &models.KeyFilterCriteria{
-		TransferPolicyId: id,
-	}
-
-	keys, err := ktpc.keyStore.Search(criteria)
-	if err != nil {
-		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Delete() Key search failed")
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search keys"}
-	}
-
-	if len(keys) > 0 {
-		defaultLog.Error("controllers/key_transfer_policy_controller:Delete() Key transfer policy is associated with existing keys")
-		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Key transfer policy is associated with keys"}
+	if _, status, err := ktpc.retrieveTenantPolicy(request, id); err != nil {
+		return nil, status, err
 	}
 
-	err = ktpc.policyStore.Delete(id)
-	if err != nil {
-		if err.Error() == commErr.RecordNotFound {
-			defaultLog.Error("controllers/key_transfer_policy_controller:Delete() Key transfer policy with specified id could not be located")
-			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
-		} else {
-			defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Delete() Key transfer policy delete failed")
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete key transfer policy"}
-		}
+	if status, err := ktpc.deletePolicy(id); err != nil {
+		return nil, status, err
 	}
 
 	secLog.WithField("Id", id).Infof("controllers/key_transfer_policy_controller:Delete() Key Transfer Policy deleted by: %s", request.RemoteAddr)
@@ -140,8 +171,16 @@ func (ktpc KeyTransferPolicyController) Search(responseWriter http.ResponseWrite
 	defaultLog.Trace("controllers/key_transfer_policy_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/key_transfer_policy_controller:Search() Leaving")
 
-	var criteria *models.KeyTransferPolicyFilterCriteria
-	// Get All Key Transfer Policy Files
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_policy_controller:Search() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	criteria := &models.KeyTransferPolicyFilterCriteria{
+		TenantID: &tenantId,
+	}
+	// Get All Key Transfer Policy Files of the tenant
 	transferPolicies, err := ktpc.policyStore.Search(criteria)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:Search() Key transfer policy search failed")
@@ -151,3 +190,159 @@ func (ktpc KeyTransferPolicyController) Search(responseWriter http.ResponseWrite
 	secLog.Infof("controllers/key_transfer_policy_controller:Search() %s: Key Transfer Policies searched by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
 	return transferPolicies, http.StatusOK, nil
 }
+
+// retrieveTenantPolicy retrieves a key transfer policy of the tenant of the request, the policies of other tenants
+// are reported as not found
+func (ktpc KeyTransferPolicyController) retrieveTenantPolicy(request *http.Request, id uuid.UUID) (*kbs.KeyTransferPolicyAttributes, int, error) {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:retrieveTenantPolicy() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:retrieveTenantPolicy() Leaving")
+
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_policy_controller:retrieveTenantPolicy() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
+
+	transferPolicy, err := ktpc.policyStore.Retrieve(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Errorf("controllers/key_transfer_policy_controller:retrieveTenantPolicy() Key transfer policy with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
+		}
+		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:retrieveTenantPolicy() Key transfer policy retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key transfer policy"}
+	}
+
+	if transferPolicy.TenantID != tenantId {
+		secLog.WithField("Id", id).Errorf("controllers/key_transfer_policy_controller:retrieveTenantPolicy() %s : Key transfer policy belongs to another tenant, request from: %s", commLogMsg.UnauthorizedAccess, request.RemoteAddr)
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
+	}
+	return transferPolicy, http.StatusOK, nil
+}
+
+// readKeyTransferPolicyUpdate decodes and validates the key transfer policy of an update request
+func readKeyTransferPolicyUpdate(request *http.Request) (*kbs.KeyTransferPolicyAttributes, int, error) {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:readKeyTransferPolicyUpdate() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:readKeyTransferPolicyUpdate() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_transfer_policy_controller:readKeyTransferPolicyUpdate() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var requestPolicy kbs.KeyTransferPolicyAttributes
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&requestPolicy)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_policy_controller:readKeyTransferPolicyUpdate() %s : Failed to decode request body as KeyTransferPolicyAttributes", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateKeyTransferPolicy(&requestPolicy); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return &requestPolicy, http.StatusOK, nil
+}
+
+// replacePolicy replaces the current key transfer policy by the policy of an update, updateMutex is held by the
+// caller
+func (ktpc KeyTransferPolicyController) replacePolicy(currentPolicy, requestPolicy *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, int, error) {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:replacePolicy() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:replacePolicy() Leaving")
+
+	// the id, creation time and tenant of the policy are kept, the version is the one of the update
+	requestPolicy.ID = currentPolicy.ID
+	requestPolicy.CreatedAt = currentPolicy.CreatedAt
+	requestPolicy.TenantID = currentPolicy.TenantID
+	requestPolicy.Version = currentPolicy.Version + 1
+	updatedPolicy, err := ktpc.policyStore.Update(requestPolicy)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_transfer_policy_controller:replacePolicy() Key transfer policy with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
+		}
+		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:replacePolicy() Key transfer policy update failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update key transfer policy"}
+	}
+	return updatedPolicy, http.StatusOK, nil
+}
+
+// checkPolicyNotInUse fails when keys are associated with the key transfer policy
+func (ktpc KeyTransferPolicyController) checkPolicyNotInUse(id uuid.UUID) (int, error) {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:checkPolicyNotInUse() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:checkPolicyNotInUse() Leaving")
+
+	// keys of all the tenants are searched, the default transfer policy is shared between the tenants
+	criteria := &models.KeyFilterCriteria{
+		TransferPolicyId: id,
+	}
+
+	keys, err := ktpc.keyStore.Search(criteria)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:checkPolicyNotInUse() Key search failed")
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search keys"}
+	}
+
+	if len(keys) > 0 {
+		defaultLog.Error("controllers/key_transfer_policy_controller:checkPolicyNotInUse() Key transfer policy is associated with existing keys")
+		return http.StatusBadRequest, &commErr.ResourceError{Message: "Key transfer policy is associated with keys"}
+	}
+	return http.StatusOK, nil
+}
+
+// deletePolicy deletes a key transfer policy that is not associated with keys
+func (ktpc KeyTransferPolicyController) deletePolicy(id uuid.UUID) (int, error) {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:deletePolicy() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:deletePolicy() Leaving")
+
+	if status, err := ktpc.checkPolicyNotInUse(id); err != nil {
+		return status, err
+	}
+
+	err := ktpc.policyStore.Delete(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_transfer_policy_controller:deletePolicy() Key transfer policy with specified id could not be located")
+			return http.StatusNotFound, &commErr.ResourceError{Message: "Key transfer policy with specified id does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:deletePolicy() Key transfer policy delete failed")
+			return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete key transfer policy"}
+		}
+	}
+	return http.StatusNoContent, nil
+}
+
+// validateKeyTransferPolicy validates the key transfer policy of a create or update request
+func validateKeyTransferPolicy(policy *kbs.KeyTransferPolicyAttributes) error {
+	defaultLog.Trace("controllers/key_transfer_policy_controller:validateKeyTransferPolicy() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_policy_controller:validateKeyTransferPolicy() Leaving")
+
+	if policy.SGXEnclaveIssuerAnyof == nil || policy.SGXEnclaveIssuerProductIDAnyof == nil {
+		secLog.Errorf("controllers/key_transfer_policy_controller:validateKeyTransferPolicy() %s : sgx_enclave_issuer_anyof and sgx_enclave_issuer_product_id_anyof must be specified", commLogMsg.InvalidInputBadParam)
+		return &commErr.ResourceError{Message: "sgx_enclave_issuer_anyof and sgx_enclave_issuer_product_id_anyof must be specified"}
+	}
+
+	for _, enclaveIssuer := range policy.SGXEnclaveIssuerAnyof {
+		if err := validation.ValidateMrSignerString(enclaveIssuer); err != nil {
+			defaultLog.WithError(err).Error("controllers/key_transfer_policy_controller:validateKeyTransferPolicy() Input validation failed for sgx enclave issuer anyof")
+			return &commErr.ResourceError{Message: "Input validation failed for sgx enclave issuer anyof"}
+		}
+	}
+
+	if policy.KeyCacheTimeout < 0 || (policy.KeyCacheTimeout > 0 && !policy.KeyCachingAllowed) {
+		secLog.Errorf("controllers/key_transfer_policy_controller:validateKeyTransferPolicy() %s : Invalid key_cache_timeout", commLogMsg.InvalidInputBadParam)
+		return &commErr.ResourceError{Message: "key_cache_timeout must be a positive number of seconds and requires key_caching_allowed"}
+	}
+
+	if policy.TransferCacheTimeout < 0 || policy.TransferCacheTimeout > consts.MaxTransferCacheTimeout {
+		secLog.Errorf("controllers/key_transfer_policy_controller:validateKeyTransferPolicy() %s : Invalid transfer_cache_timeout", commLogMsg.InvalidInputBadParam)
+		return &commErr.ResourceError{Message: fmt.Sprintf("transfer_cache_timeout must be between 0 and %d seconds", consts.MaxTransferCacheTimeout)}
+	}
+	return nil
+}
@@ -11,10 +11,13 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/mocks"
 	kbsRoutes "github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -31,7 +34,7 @@ var _ = Describe("KeyTransferPolicyController", func() {
 		keyStore = mocks.NewFakeKeyStore()
 		policyStore = mocks.NewFakeKeyTransferPolicyStore()
 
-		keyTransferPolicyController = controllers.NewKeyTransferPolicyController(policyStore, keyStore)
+		keyTransferPolicyController = controllers.NewKeyTransferPolicyController(policyStore, keyStore, mocks.NewFakeTenantQuotaStore())
 	})
 
 	// Specs for HTTP Post to "/key-transfer-policies"
@@ -77,6 +80,50 @@ var _ = Describe("KeyTransferPolicyController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Provide a Create request with a key cache timeout without key caching", func() {
+			It("Should fail to create new Key Transfer Policy", func() {
+				router.Handle("/key-transfer-policies", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Create))).Methods("POST")
+				policyJson := `{
+									"sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+									"sgx_enclave_issuer_product_id_anyof": [0],
+									"key_cache_timeout": 300
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/key-transfer-policies",
+					strings.NewReader(policyJson),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide a Create request with a transfer cache timeout above the maximum", func() {
+			It("Should fail to create new Key Transfer Policy", func() {
+				router.Handle("/key-transfer-policies", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Create))).Methods("POST")
+				policyJson := `{
+									"sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+									"sgx_enclave_issuer_product_id_anyof": [0],
+									"transfer_cache_timeout": 3600
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/key-transfer-policies",
+					strings.NewReader(policyJson),
+				)
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 		Context("Provide a Create request without sgx_enclave_issuer_product_id_anyof", func() {
 			It("Should fail to create new Key Transfer Policy", func() {
 				router.Handle("/key-transfer-policies", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Create))).Methods("POST")
@@ -125,6 +172,58 @@ var _ = Describe("KeyTransferPolicyController", func() {
 		})
 	})
 
+	// Specs for HTTP Put to "/key-transfer-policies/{id}"
+	Describe("Update an existing Key Transfer Policy", func() {
+		updatePolicy := func(policyId, ifMatch string) *httptest.ResponseRecorder {
+			router.Handle("/key-transfer-policies/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Update))).Methods("PUT")
+			policyJson := `{
+								"sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+								"sgx_enclave_issuer_product_id_anyof": [1]
+						}`
+			req, err := http.NewRequest("PUT", "/key-transfer-policies/"+policyId, strings.NewReader(policyJson))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			if ifMatch != "" {
+				req.Header.Set("If-Match", ifMatch)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		Context("Update Key Transfer Policy with the current version", func() {
+			It("Should update the Key Transfer Policy and its version", func() {
+				w = updatePolicy("ee37c360-7eae-4250-a677-6ee12adce8e2", `"0"`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("ETag")).To(Equal(`"1"`))
+
+				var policy kbs.KeyTransferPolicyAttributes
+				err := json.Unmarshal(w.Body.Bytes(), &policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.ID.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(policy.SGXEnclaveIssuerProductIDAnyof).To(Equal([]int16{1}))
+				Expect(policy.Version).To(Equal(1))
+			})
+		})
+		Context("Update Key Transfer Policy with a stale version", func() {
+			It("Should fail with the current version", func() {
+				w = updatePolicy("ee37c360-7eae-4250-a677-6ee12adce8e2", "")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				w = updatePolicy("ee37c360-7eae-4250-a677-6ee12adce8e2", `"0"`)
+				Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+				Expect(w.Header().Get("ETag")).To(Equal(`"1"`))
+			})
+		})
+		Context("Update Key Transfer Policy by non-existent ID", func() {
+			It("Should fail to update Key Transfer Policy", func() {
+				w = updatePolicy("e57e5ea0-d465-461e-882d-1600090caa0d", "")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	// Specs for HTTP Delete to "/key-transfer-policies/{id}"
 	Describe("Delete an existing Key Transfer Policy", func() {
 		Context("Delete Key Transfer Policy by ID", func() {
@@ -178,4 +277,57 @@ var _ = Describe("KeyTransferPolicyController", func() {
 			})
 		})
 	})
+
+	// Specs for the isolation of the key transfer policies of the tenants
+	Describe("Manage the Key Transfer Policies of a tenant", func() {
+		tenantRequest := func(method, url, body string) *http.Request {
+			req, err := http.NewRequest(method, url, strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req = context.SetUserRoles(req, []aas.RoleInfo{{Service: constants.ServiceName, Name: "KeyManager", Context: "tenant=tenant-a"}})
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			return req
+		}
+
+		Context("Create Key Transfer Policies for a tenant with a quota of one policy", func() {
+			It("Should create the policy in the namespace of the tenant until the quota is reached", func() {
+				router.Handle("/key-transfer-policies", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Create))).Methods("POST")
+				router.Handle("/key-transfer-policies", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Search))).Methods("GET")
+				policyJson := `{
+									"sgx_enclave_issuer_anyof": ["cd171c56941c6ce49690b455f691d9c8a04c2e43e0a4d30f752fa5285c7ee57f"],
+									"sgx_enclave_issuer_product_id_anyof": [0]
+							}`
+
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("POST", "/key-transfer-policies", policyJson))
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var policy kbs.KeyTransferPolicyAttributes
+				err := json.Unmarshal(w.Body.Bytes(), &policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.TenantID).To(Equal("tenant-a"))
+
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("POST", "/key-transfer-policies", policyJson))
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("GET", "/key-transfer-policies", ""))
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var policies []kbs.KeyTransferPolicyAttributes
+				err = json.Unmarshal(w.Body.Bytes(), &policies)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(policies)).To(Equal(1))
+			})
+		})
+		Context("Delete a Key Transfer Policy of the default namespace as a tenant", func() {
+			It("Should fail to delete the Key Transfer Policy", func() {
+				router.Handle("/key-transfer-policies/{id}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferPolicyController.Delete))).Methods("DELETE")
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, tenantRequest("DELETE", "/key-transfer-policies/73755fda-c910-46be-821f-e8ddeab189e9", ""))
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
 })
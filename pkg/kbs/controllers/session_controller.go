@@ -15,6 +15,8 @@ import (
 	"math/big"
 	"net/http"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
@@ -39,22 +41,6 @@ func NewSessionController(kc *config.Configuration, caCertDir string) *SessionCo
 	}
 }
 
-// from challenge uuid remove '-' and prepare nonce
-func getNonce(challengeUUID string) ([]byte, error) {
-	decodedUUID, err := base64.StdEncoding.DecodeString(challengeUUID)
-	if err != nil {
-		return nil, errors.New("not a base64 encoded Challenge UUID")
-	}
-	nonce := make([]byte, constants.NonceLength)
-	for i, j := 0, 0; i < len(decodedUUID); i++ {
-		if decodedUUID[i] != '-' {
-			nonce[j] = decodedUUID[i]
-			j++
-		}
-	}
-	return nonce, nil
-}
-
 // remove public key blob from quote received from skc client
 // send only sgx ecdsa quote to quote verification service
 func extractKeyFromQuote(quote string) (string, []byte, error) {
@@ -132,6 +118,13 @@ func (sc *SessionController) Create(responseWriter http.ResponseWriter, request
 		defaultLog.WithError(err).Error("controllers/session_controller:Create() no session object found.")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "no session object found"}
 	}
+
+	// the nonce is consumed by the first attempt to create the session with the challenge
+	nonce, err := keyInfo.ConsumeChallengeNonce(sessionRequest.Challenge)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/session_controller:Create() %s : Invalid challenge nonce", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "challenge nonce is expired or has already been used"}
+	}
 	var resAttr kbs.QuoteVerifyAttributes
 	var responseAttributes *kbs.QuoteVerifyAttributes
 	rsaKey, err := getRsaPubKey(sessionRequest.Quote)
@@ -144,10 +137,6 @@ func (sc *SessionController) Create(responseWriter http.ResponseWriter, request
 		resAttr.ChallengeRsaPublicKey = string(rsaKey)
 		responseAttributes = &resAttr
 	} else {
-		nonce, err := getNonce(sessionRequest.Challenge)
-		if err != nil {
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in fetching the nonce"}
-		}
 		Quote, Key, err := extractKeyFromQuote(sessionRequest.Quote)
 		if err != nil {
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while extracting public key"}
@@ -162,7 +151,14 @@ func (sc *SessionController) Create(responseWriter http.ResponseWriter, request
 		responseAttributes.ChallengeKeyType = constants.CRYPTOALG_RSA
 		responseAttributes.ChallengeRsaPublicKey = string(rsaKey)
 	}
-	keyInfo.SessionResponseMap[sessionRequest.Challenge] = *responseAttributes
+	keyInfo.SetSessionResponse(sessionRequest.Challenge, *responseAttributes)
+
+	// only the keys of the tenant of the user creating the session are transferred in the session
+	tenantId, err := getTenantID(request)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/session_controller:Create() %s : Invalid tenant in user roles", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Invalid tenant in user roles"}
+	}
 
 	swkKey, err := session.SessionCreateSwk()
 	if err != nil {
@@ -170,8 +166,14 @@ func (sc *SessionController) Create(responseWriter http.ResponseWriter, request
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in getting SWK key"}
 	}
 
-	sessionObj.SWK = swkKey
-	keyInfo.SessionMap[sessionRequest.Challenge] = sessionObj
+	sessionObj.SWK = keyInfo.KeepSessionSwk(sessionRequest.Challenge, swkKey)
+	sessionObj.Compression = keytransfer.NegotiateCompression(sessionRequest.Compression)
+	sessionObj.TenantID = tenantId
+	keyInfo.SetSessionObj(sessionRequest.Challenge, sessionObj)
+
+	// the swk of the session remains valid for its wrapping even if the session is deleted meanwhile
+	sessionObj, releaseSwk := keyInfo.AcquireSessionObj(sessionRequest.Challenge)
+	defer releaseSwk()
 
 	var respAttr kbs.SessionResponseAttributes
 	if responseAttributes.ChallengeKeyType == constants.CRYPTOALG_RSA {
@@ -182,6 +184,7 @@ func (sc *SessionController) Create(responseWriter http.ResponseWriter, request
 
 		}
 		respAttr.SessionData.SWK = wrappedKey
+		respAttr.SessionData.Compression = sessionObj.Compression
 		if sessionRequest.ChallengeType == constants.SWAlgorithmType {
 			respAttr.SessionData.AlgorithmType = constants.SWAlgorithmType
 		} else {
@@ -202,6 +205,101 @@ func (sc *SessionController) Create(responseWriter http.ResponseWriter, request
 	return respAttr, http.StatusCreated, nil
 }
 
+// VerifyPeerCertificate is called during the TLS handshake. When the client certificate embeds an SGX
+// or TDX quote (RA-TLS), the quote type, the validity and the public key of the certificate are checked.
+// The quote is verified by EstablishRATLSSession with the first key transfer request of the certificate,
+// the handshake does not wait for the quote verification service.
+// Client certificates without a quote are left to the regular key transfer flow.
+func (sc *SessionController) VerifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	defaultLog.Trace("controllers/session_controller:VerifyPeerCertificate() Entering")
+	defer defaultLog.Trace("controllers/session_controller:VerifyPeerCertificate() Leaving")
+
+	if len(rawCerts) == 0 {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/session_controller:VerifyPeerCertificate() %s : Failed to parse client certificate", commLogMsg.InvalidInputBadParam)
+		return errors.Wrap(err, "Failed to parse client certificate")
+	}
+
+	_, stmLabel, ok := session.GetQuoteFromCertificate(cert)
+	if !ok {
+		return nil
+	}
+
+	supported := false
+	for _, label := range strings.Split(sc.config.Skc.StmLabel, ",") {
+		if strings.TrimSpace(label) == stmLabel {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		secLog.Errorf("controllers/session_controller:VerifyPeerCertificate() %s : RA-TLS quote type %s is not supported", commLogMsg.InvalidInputBadParam, stmLabel)
+		return errors.Errorf("RA-TLS quote type %s is not supported", stmLabel)
+	}
+
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		secLog.Errorf("controllers/session_controller:VerifyPeerCertificate() %s : RA-TLS certificate is not valid at this time", commLogMsg.InvalidInputBadParam)
+		return errors.New("RA-TLS certificate is not valid at this time")
+	}
+	if _, ok := cert.PublicKey.(*rsa.PublicKey); !ok {
+		secLog.Errorf("controllers/session_controller:VerifyPeerCertificate() %s : RA-TLS certificate does not have an RSA public key", commLogMsg.InvalidInputBadParam)
+		return errors.New("Currently only RSA key support is available")
+	}
+	return nil
+}
+
+// EstablishRATLSSession returns the session bound to an RA-TLS client certificate checked by VerifyPeerCertificate.
+// The session is created on the first use of the certificate once its quote is verified, removing the need for
+// the challenge and session create requests. ok is false for the client certificates without a quote.
+func (sc *SessionController) EstablishRATLSSession(cert *x509.Certificate) (kbs.KeyTransferSession, bool, error) {
+	defaultLog.Trace("controllers/session_controller:EstablishRATLSSession() Entering")
+	defer defaultLog.Trace("controllers/session_controller:EstablishRATLSSession() Leaving")
+
+	quote, stmLabel, ok := session.GetQuoteFromCertificate(cert)
+	if !ok {
+		return kbs.KeyTransferSession{}, false, nil
+	}
+
+	keyInfo := keytransfer.GetKeyInfo()
+	certHash := session.GetCertificateHash(cert)
+	if keyTransferSession, ok := keyInfo.GetRATLSSession(certHash); ok {
+		defaultLog.Debug("controllers/session_controller:EstablishRATLSSession() Reusing the session established for RA-TLS certificate")
+		return keyTransferSession, true, nil
+	}
+
+	// the quote report data binds the enclave to the public key of the certificate
+	responseAttributes, err := session.VerifyQuote(base64.StdEncoding.EncodeToString(quote), session.GetRATLSUserData(cert), sc.config, sc.trustedCaCertDir)
+	if err != nil || responseAttributes == nil {
+		secLog.WithError(err).Error("controllers/session_controller:EstablishRATLSSession() Remote attestation for RA-TLS session failed")
+		return kbs.KeyTransferSession{}, true, errors.New("Remote attestation for RA-TLS session failed")
+	}
+
+	publicKey, err := session.GetCertificatePublicKeyPem(cert)
+	if err != nil {
+		return kbs.KeyTransferSession{}, true, errors.Wrap(err, "Failed to get RA-TLS certificate public key")
+	}
+	responseAttributes.ChallengeKeyType = constants.CRYPTOALG_RSA
+	responseAttributes.ChallengeRsaPublicKey = string(publicKey)
+
+	swkKey, err := session.SessionCreateSwk()
+	if err != nil {
+		secLog.Error("controllers/session_controller:EstablishRATLSSession() Error in getting SWK key")
+		return kbs.KeyTransferSession{}, true, errors.Wrap(err, "Error in getting SWK key")
+	}
+
+	sessionID, err := keyInfo.CreateRATLSSession(certHash, stmLabel, *responseAttributes, swkKey, sc.config.Skc.SessionExpiryTime)
+	if err != nil {
+		return kbs.KeyTransferSession{}, true, errors.Wrap(err, "Error in creating RA-TLS session")
+	}
+
+	secLog.WithField("Session-Id", fmt.Sprintf("%s:%s", stmLabel, sessionID)).Info("controllers/session_controller:EstablishRATLSSession() Successfully created RA-TLS session")
+	return keyInfo.GetSessionObj(sessionID), true, nil
+}
+
 func validateSessionCreateRequest(sessionRequest kbs.SessionManagementAttributes) error {
 	defaultLog.Trace("controllers/session_controller:validateSessionCreateRequest() Entering")
 	defer defaultLog.Trace("controllers/session_controller:validateSessionCreateRequest() Leaving")
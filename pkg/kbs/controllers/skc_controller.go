@@ -9,15 +9,18 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keytransfer"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/session"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
 
@@ -62,19 +65,53 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Stm module requested by skc_library not supported by kbs"}
 	}
 
+	var stmSessionIDs []string
 	if len(sessionId) != 0 {
-		keyInfo.PopulateSessionId(sessionId)
+		stmSessionIDs = keyInfo.PopulateSessionId(sessionId)
 	}
 
+	clientCertHash := session.GetCertificateHash(request.TLS.PeerCertificates[0])
 	keyInfo.IssuerCommonName = request.TLS.PeerCertificates[0].Issuer.CommonName
+	keyInfo.ClientCertSHA = clientCertHash
 	userCommonName := request.TLS.PeerCertificates[0].Subject.CommonName
 
-	err = keyInfo.SetUserContext(userCommonName, kc.config, kc.trustedCaCertDir)
+	// a session established with an RA-TLS client certificate replaces the challenge and session creation round
+	// trips, the quote of the certificate is verified with its first key transfer request
+	var raTLSSession kbs.KeyTransferSession
+	var isRATLSSession bool
+	if len(sessionId) == 0 {
+		raTLSSession, isRATLSSession, err = NewSessionController(kc.config, kc.trustedCaCertDir).EstablishRATLSSession(request.TLS.PeerCertificates[0])
+		if err != nil {
+			secLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() Failed to establish RA-TLS session")
+			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Remote attestation for RA-TLS session failed"}
+		}
+	}
+	if isRATLSSession {
+		defaultLog.Debug("controllers/skc_controller:TransferApplicationKey() Using session established over RA-TLS")
+		keyInfo.AddSessionID(raTLSSession.Stmlabel+raTLSSession.SessionId, raTLSSession.SessionId)
+		stmSessionIDs = append(stmSessionIDs, raTLSSession.Stmlabel+raTLSSession.SessionId)
+
+		// there is no session create request over RA-TLS, the payload compression is negotiated
+		// with the first key transfer request of the session
+		acceptCompression := request.Header.Get("Accept-Compression")
+		if raTLSSession.Compression == "" && acceptCompression != "" {
+			raTLSSession.Compression = keytransfer.NegotiateCompression(strings.Split(acceptCompression, ","))
+			keyInfo.SetSessionObj(raTLSSession.SessionId, raTLSSession)
+		}
+	}
+
+	tenantId, err := keyInfo.SetUserContext(userCommonName, kc.config, kc.trustedCaCertDir)
 	if err != nil {
 		secLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() error while getting common name")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Couldn't fetch common name for specified user"}
 	}
 
+	// there is no session create request over RA-TLS, the session is bound to the tenant of the user on first use
+	if len(sessionId) == 0 && isRATLSSession && raTLSSession.TenantID == "" && tenantId != "" {
+		raTLSSession.TenantID = tenantId
+		keyInfo.SetSessionObj(raTLSSession.SessionId, raTLSSession)
+	}
+
 	key, err := kc.remoteManager.RetrieveKey(keyID)
 	if err != nil {
 		if err.Error() == commErr.RecordNotFound {
@@ -85,6 +122,10 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
 		}
 	}
+	if key.TenantID != tenantId {
+		secLog.WithField("id", keyID).Errorf("controllers/skc_controller:TransferApplicationKey() %s : Key belongs to another tenant", commLogMsg.UnauthorizedAccess)
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+	}
 	transferPolicy, err := kc.policyStore.Retrieve(key.TransferPolicyID)
 	if err != nil {
 		if err.Error() == commErr.RecordNotFound {
@@ -103,7 +144,14 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "client is not valid"}
 	}
 
-	if len(sessionId) == 0 {
+	// a client restarting within the transfer cache timeout of the policy is not attested again
+	if cachedTransfer, ok := keyInfo.GetCachedTransfer(keyID, stmSessionIDs, clientCertHash, tenantId); ok {
+		responseWriter.Header().Add("Session-Id", cachedTransfer.SessionIDHeader)
+		secLog.WithField("Key", keyID).Infof("controllers/skc_controller:TransferApplicationKey(): Successfully transferred the cached key: %s", request.RemoteAddr)
+		return cachedTransfer.Response, http.StatusOK, nil
+	}
+
+	if len(sessionId) == 0 && !isRATLSSession {
 		challenge, err := keyInfo.BuildChallengeJsonRequest(kc.config)
 		if err != nil {
 			secLog.WithError(err).Errorf("controllers/skc_controller:TransferApplicationKey() Failed to generate challenge")
@@ -121,7 +169,7 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 	}
 
 	///check for return value also.
-	isValidSession, isValidSGXAttributes, isSessionActive := keyInfo.IsValidSession(stmChallenge)
+	isValidSession, isValidSGXAttributes, isSessionActive := keyInfo.IsValidSession(stmChallenge, tenantId, stmSessionIDs)
 	if isValidSession {
 		if !isSessionActive {
 			secLog.Info("controllers/skc_controller:TransferApplicationKey() SessionExpired: Session is expired.Hence key transfer unsuccessful.")
@@ -147,6 +195,7 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
 		}
 		applicationKey, err := keyInfo.FetchApplicationKey(keyData, key.KeyInformation.Algorithm)
+		crypt.Zeroize(keyData)
 		if err != nil {
 			secLog.WithError(err).WithField("id", keyID).Error(
 				"controllers/skc_controller:TransferApplicationKey() Failed to fetch the application key")
@@ -159,12 +208,30 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		outputKeyData.KeyInfo.CreatedAt = &key.CreatedAt
 		outputKeyData.KeyInfo.KeyId = keyID
 		outputKeyData.KeyInfo.KeyData = applicationKey
+		outputKeyData.KeyInfo.Compression = keyInfo.PayloadCompression
 		outputKeyData.KeyInfo.KeyLength = key.KeyInformation.KeyLength
+		outputKeyData.KeyInfo.CachePolicy = keyInfo.GetKeyCachePolicy()
 		outputKeyData.KeyInfo.Policy.Link.KeyTransfer.Href = url
 		outputKeyData.KeyInfo.Policy.Link.KeyTransfer.Method = "get"
 		outputKeyData.Operation = constants.KeyTransferOpertaion
 		outputKeyData.Status = constants.SuccessStatus
 
+		if len(sessionId) == 0 && isRATLSSession {
+			// the session key is only known to the client holding the private key of the attested certificate
+			publicKey, err := session.GetCertificatePublicKeyPem(request.TLS.PeerCertificates[0])
+			if err != nil {
+				secLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() Failed to get RA-TLS certificate public key")
+				return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in wrapping SWK key"}
+			}
+			swkSession, releaseSwk := keyInfo.AcquireSessionObj(raTLSSession.SessionId)
+			defer releaseSwk()
+			outputKeyData.KeyInfo.SWK, err = session.SessionWrapSwkWithRSAKey(constants.CRYPTOALG_RSA, publicKey, swkSession.SWK)
+			if err != nil {
+				secLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() Unable to wrap the swk with RA-TLS certificate key")
+				return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in wrapping SWK key"}
+			}
+		}
+
 		sessionID, err := base64.StdEncoding.DecodeString(keyInfo.ActiveSessionID)
 		if err != nil {
 			secLog.WithError(err).Errorf("controllers/skc_controller:TransferApplicationKey() Failed to decode the active session id")
@@ -173,7 +240,12 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		sessionIDStr := fmt.Sprintf("%s:%s", keyInfo.ActiveStmLabel, sessionID)
 		responseWriter.Header().Add("Session-Id", sessionIDStr)
 		secLog.WithField("Key", keyID).Infof("controllers/skc_controller:TransferApplicationKey(): Successfully transferred the key: %s", request.RemoteAddr)
-		delete(keyInfo.SessionIDMap, keyInfo.ActiveStmLabel+keyInfo.ActiveSessionID)
+		keyInfo.CacheTransfer(keyID, keyInfo.ActiveStmLabel+keyInfo.ActiveSessionID, clientCertHash, tenantId, sessionIDStr, outputKeyData)
+		keyInfo.RemoveSessionID(keyInfo.ActiveStmLabel + keyInfo.ActiveSessionID)
+		if outputKeyData.KeyInfo.CachePolicy.ReattestOnReuse {
+			// the client must be attested again before it is given the key again
+			keyInfo.EndActiveSession()
+		}
 		return outputKeyData, http.StatusOK, nil
 	}
 	return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in transferring the application key"}
@@ -13,6 +13,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/mocks"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/memory"
 	kbsRoutes "github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
@@ -59,7 +60,7 @@ var _ = Describe("SKCKeyTransferController", func() {
 		cs.PeerCertificates = append(cs.PeerCertificates, cert)
 
 		keyManager := &keymanager.DirectoryManager{}
-		remoteManager = keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
+		remoteManager = keymanager.NewRemoteManager(keyStore, memory.NewKeyStore(), keyManager, endpointUrl)
 		skcController = controllers.NewSKCController(remoteManager, policyStore, kbsConfig, trustedCaCertsDir)
 		setupServer(server)
 	})
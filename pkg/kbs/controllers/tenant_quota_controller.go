@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/utils"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+)
+
+type TenantQuotaController struct {
+	quotaStore domain.TenantQuotaStore
+}
+
+func NewTenantQuotaController(qs domain.TenantQuotaStore) *TenantQuotaController {
+	return &TenantQuotaController{
+		quotaStore: qs,
+	}
+}
+
+//Update : Function to set the quota of a tenant
+func (tqc TenantQuotaController) Update(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tenant_quota_controller:Update() Entering")
+	defer defaultLog.Trace("controllers/tenant_quota_controller:Update() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/tenant_quota_controller:Update() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var quota kbs.TenantQuota
+	// Decode the incoming json data to note struct
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&quota)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/tenant_quota_controller:Update() %s : Failed to decode request body as TenantQuota", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	tenantId := mux.Vars(request)["tenantId"]
+	if quota.TenantID != "" && quota.TenantID != tenantId {
+		secLog.Errorf("controllers/tenant_quota_controller:Update() %s : Tenant id in request body does not match the tenant id in path", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "tenant_id does not match the tenant in path"}
+	}
+
+	if quota.MaxKeys < 0 || quota.MaxKeyTransferPolicies < 0 {
+		secLog.Errorf("controllers/tenant_quota_controller:Update() %s : Tenant quota limits cannot be negative", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "max_keys and max_key_transfer_policies cannot be negative"}
+	}
+
+	quota.TenantID = tenantId
+	updatedQuota, err := tqc.quotaStore.Create(&quota)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/tenant_quota_controller:Update() Tenant quota update failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update tenant quota"}
+	}
+
+	secLog.WithField("TenantId", tenantId).Infof("controllers/tenant_quota_controller:Update() %s: Tenant quota updated by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	return updatedQuota, http.StatusOK, nil
+}
+
+//Retrieve : Function to retrieve the quota of a tenant
+func (tqc TenantQuotaController) Retrieve(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tenant_quota_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/tenant_quota_controller:Retrieve() Leaving")
+
+	tenantId := mux.Vars(request)["tenantId"]
+	quota, err := tqc.quotaStore.Retrieve(tenantId)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/tenant_quota_controller:Retrieve() Quota of specified tenant could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Quota of specified tenant does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/tenant_quota_controller:Retrieve() Tenant quota retrieve failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve tenant quota"}
+		}
+	}
+
+	secLog.WithField("TenantId", tenantId).Infof("controllers/tenant_quota_controller:Retrieve() %s: Tenant quota retrieved by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return quota, http.StatusOK, nil
+}
+
+//Delete : Function to remove the quota of a tenant
+func (tqc TenantQuotaController) Delete(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tenant_quota_controller:Delete() Entering")
+	defer defaultLog.Trace("controllers/tenant_quota_controller:Delete() Leaving")
+
+	tenantId := mux.Vars(request)["tenantId"]
+	err := tqc.quotaStore.Delete(tenantId)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/tenant_quota_controller:Delete() Quota of specified tenant could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Quota of specified tenant does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/tenant_quota_controller:Delete() Tenant quota delete failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete tenant quota"}
+		}
+	}
+
+	secLog.WithField("TenantId", tenantId).Infof("controllers/tenant_quota_controller:Delete() Tenant quota deleted by: %s", request.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+//Search : Function to retrieve the quotas of all the tenants
+func (tqc TenantQuotaController) Search(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tenant_quota_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/tenant_quota_controller:Search() Leaving")
+
+	quotas, err := tqc.quotaStore.Search()
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/tenant_quota_controller:Search() Tenant quota search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search tenant quotas"}
+	}
+
+	secLog.Infof("controllers/tenant_quota_controller:Search() %s: Tenant quotas searched by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return quotas, http.StatusOK, nil
+}
+
+// getTenantID returns the tenant of the user making the request. Requests without roles in their context are
+// served from the default namespace.
+func getTenantID(request *http.Request) (string, error) {
+	defaultLog.Trace("controllers/tenant_quota_controller:getTenantID() Entering")
+	defer defaultLog.Trace("controllers/tenant_quota_controller:getTenantID() Leaving")
+
+	roles, err := comctx.GetUserRoles(request)
+	if err != nil {
+		defaultLog.Debug("controllers/tenant_quota_controller:getTenantID() No roles in request context, using the default namespace")
+		return "", nil
+	}
+	return utils.GetTenantID(roles)
+}
+
+// retrieveTenantQuota returns the quota of the tenant, nil is returned for the default namespace and for
+// tenants without a quota
+func retrieveTenantQuota(quotaStore domain.TenantQuotaStore, tenantId string) (*kbs.TenantQuota, error) {
+	defaultLog.Trace("controllers/tenant_quota_controller:retrieveTenantQuota() Entering")
+	defer defaultLog.Trace("controllers/tenant_quota_controller:retrieveTenantQuota() Leaving")
+
+	if tenantId == "" {
+		return nil, nil
+	}
+	quota, err := quotaStore.Retrieve(tenantId)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return quota, nil
+}
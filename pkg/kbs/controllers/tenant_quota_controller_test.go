@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/mocks"
+	kbsRoutes "github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TenantQuotaController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var quotaStore *mocks.MockTenantQuotaStore
+	var tenantQuotaController *controllers.TenantQuotaController
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		quotaStore = mocks.NewFakeTenantQuotaStore()
+
+		tenantQuotaController = controllers.NewTenantQuotaController(quotaStore)
+	})
+
+	// Specs for HTTP Put to "/tenant-quotas/{tenantId}"
+	Describe("Update the quota of a tenant", func() {
+		Context("Provide a valid Update request", func() {
+			It("Should set the quota of the tenant", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Update))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/tenant-quotas/tenant-b",
+					strings.NewReader(`{"max_keys": 10, "max_key_transfer_policies": 2}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var quota kbs.TenantQuota
+				err = json.Unmarshal(w.Body.Bytes(), &quota)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(quota.TenantID).To(Equal("tenant-b"))
+				Expect(quota.MaxKeys).To(Equal(10))
+			})
+		})
+		Context("Provide an Update request with a negative limit", func() {
+			It("Should fail to set the quota of the tenant", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Update))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/tenant-quotas/tenant-b",
+					strings.NewReader(`{"max_keys": -1}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide an Update request for another tenant", func() {
+			It("Should fail to set the quota of the tenant", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Update))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/tenant-quotas/tenant-b",
+					strings.NewReader(`{"tenant_id": "tenant-a", "max_keys": 10}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/tenant-quotas/{tenantId}"
+	Describe("Retrieve the quota of a tenant", func() {
+		Context("Retrieve the quota of an existing tenant", func() {
+			It("Should retrieve the quota", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/tenant-quotas/tenant-a", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+		Context("Retrieve the quota of a tenant without a quota", func() {
+			It("Should fail to retrieve the quota", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/tenant-quotas/tenant-b", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Delete to "/tenant-quotas/{tenantId}"
+	Describe("Delete the quota of a tenant", func() {
+		Context("Delete the quota of an existing tenant", func() {
+			It("Should delete the quota", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Delete))).Methods("DELETE")
+				req, err := http.NewRequest("DELETE", "/tenant-quotas/tenant-a", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+		Context("Delete the quota of a tenant without a quota", func() {
+			It("Should fail to delete the quota", func() {
+				router.Handle("/tenant-quotas/{tenantId}", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Delete))).Methods("DELETE")
+				req, err := http.NewRequest("DELETE", "/tenant-quotas/tenant-b", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/tenant-quotas"
+	Describe("Search for all the tenant quotas", func() {
+		Context("Get all the tenant quotas", func() {
+			It("Should get list of all the tenant quotas", func() {
+				router.Handle("/tenant-quotas", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tenantQuotaController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/tenant-quotas", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var quotas []kbs.TenantQuota
+				err = json.Unmarshal(w.Body.Bytes(), &quotas)
+				Expect(err).NotTo(HaveOccurred())
+				// Verifying mocked data of 1 tenant quota
+				Expect(len(quotas)).To(Equal(1))
+			})
+		})
+	})
+})
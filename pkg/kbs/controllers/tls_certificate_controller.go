@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"crypto"
+	"net/http"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	commTls "github.com/intel-secl/intel-secl/v3/pkg/lib/common/tls"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+)
+
+type TLSCertificateController struct {
+	reloader *commTls.CertReloader
+}
+
+func NewTLSCertificateController(reloader *commTls.CertReloader) *TLSCertificateController {
+	return &TLSCertificateController{reloader: reloader}
+}
+
+//Retrieve : Function to retrieve the details of the TLS certificate currently being served
+func (tc TLSCertificateController) Retrieve(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tls_certificate_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/tls_certificate_controller:Retrieve() Leaving")
+
+	certificate, err := tc.certificateInfo()
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/tls_certificate_controller:Retrieve() Failed to read served TLS certificate")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve TLS certificate"}
+	}
+
+	secLog.Infof("controllers/tls_certificate_controller:Retrieve() TLS certificate retrieved by: %s", request.RemoteAddr)
+	return certificate, http.StatusOK, nil
+}
+
+//Reload : Function to load the TLS certificate and key from disk and serve them for new connections
+func (tc TLSCertificateController) Reload(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/tls_certificate_controller:Reload() Entering")
+	defer defaultLog.Trace("controllers/tls_certificate_controller:Reload() Leaving")
+
+	if err := tc.reloader.Reload(); err != nil {
+		defaultLog.WithError(err).Error("controllers/tls_certificate_controller:Reload() TLS certificate reload failed")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to reload TLS certificate, previous certificate remains in use"}
+	}
+
+	certificate, err := tc.certificateInfo()
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/tls_certificate_controller:Reload() Failed to read served TLS certificate")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve TLS certificate"}
+	}
+
+	secLog.Infof("controllers/tls_certificate_controller:Reload() %s: TLS certificate reloaded by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
+	return certificate, http.StatusOK, nil
+}
+
+func (tc TLSCertificateController) certificateInfo() (*kbs.Certificate, error) {
+	leaf, _ := tc.reloader.Leaf()
+	digest, err := crypt.GetCertHashInHex(leaf, crypto.SHA384)
+	if err != nil {
+		return nil, err
+	}
+	return &kbs.Certificate{
+		Certificate: leaf.Raw,
+		Subject:     leaf.Subject.CommonName,
+		Issuer:      leaf.Issuer.CommonName,
+		NotBefore:   &leaf.NotBefore,
+		NotAfter:    &leaf.NotAfter,
+		Digest:      digest,
+	}, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	kbsRoutes "github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
+	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commTls "github.com/intel-secl/intel-secl/v3/pkg/lib/common/tls"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLSCertificateController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var tlsCertDir, certFile, keyFile string
+	var tlsCertController *controllers.TLSCertificateController
+
+	BeforeEach(func() {
+		var err error
+		tlsCertDir, err = ioutil.TempDir("", "kbs-tls")
+		Expect(err).NotTo(HaveOccurred())
+		certFile = filepath.Join(tlsCertDir, "tls-cert.pem")
+		keyFile = filepath.Join(tlsCertDir, "tls.key")
+
+		certDer, keyDer, err := crypt.CreateKeyPairAndCertificate("KBS TLS Certificate", "127.0.0.1", "rsa", 2048)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(crypt.SavePemCert(certDer, certFile)).To(Succeed())
+		Expect(crypt.SavePrivateKeyAsPKCS8(keyDer, keyFile)).To(Succeed())
+
+		reloader, err := commTls.NewCertReloader(certFile, keyFile, 0)
+		Expect(err).NotTo(HaveOccurred())
+		router = mux.NewRouter()
+		tlsCertController = controllers.NewTLSCertificateController(reloader)
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tlsCertDir)
+	})
+
+	// Specs for HTTP Get to "/tls-certificate"
+	Describe("Retrieve TLS certificate", func() {
+		Context("Retrieve the certificate being served", func() {
+			It("Should return the certificate details", func() {
+				router.Handle("/tls-certificate", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tlsCertController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/tls-certificate", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var certificate kbs.Certificate
+				err = json.Unmarshal(w.Body.Bytes(), &certificate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(certificate.Digest).NotTo(BeEmpty())
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/tls-certificate/reload"
+	Describe("Reload TLS certificate", func() {
+		Context("Reload a valid key pair", func() {
+			It("Should reload the certificate", func() {
+				router.Handle("/tls-certificate/reload", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tlsCertController.Reload))).Methods("POST")
+				req, err := http.NewRequest("POST", "/tls-certificate/reload", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		Context("Reload an invalid key pair", func() {
+			It("Should fail to reload the certificate", func() {
+				Expect(ioutil.WriteFile(keyFile, []byte("invalid"), 0600)).To(Succeed())
+				router.Handle("/tls-certificate/reload", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(tlsCertController.Reload))).Methods("POST")
+				req, err := http.NewRequest("POST", "/tls-certificate/reload", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
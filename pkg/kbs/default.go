@@ -23,12 +23,23 @@ func init() {
 	viper.SetDefault("tls-key-file", constants.DefaultTLSKeyPath)
 	viper.SetDefault("tls-common-name", constants.DefaultKbsTlsCn)
 	viper.SetDefault("tls-san-list", constants.DefaultKbsTlsSan)
+	viper.SetDefault("tls-reload-interval", constants.DefaultTLSReloadInterval)
+	viper.SetDefault("tls-key-overlap", constants.DefaultTLSKeyOverlap)
 
 	// Set default values for log
 	viper.SetDefault("log-max-length", constants.DefaultLogMaxlength)
 	viper.SetDefault("log-enable-stdout", true)
 	viper.SetDefault("log-level", constants.DefaultLogLevel)
 
+	// Set default value for the key transfer authentication
+	viper.SetDefault("key-transfer-auth-mode", "jwt")
+
+	// Set default value for the cache time of the OIDC provider signing keys
+	viper.SetDefault("oidc-jwks-cache-time", constants.DefaultOIDCJWKSCacheTime)
+
+	// Set default value for the expiry of the challenge nonces
+	viper.SetDefault("nonce-expiry-time", constants.DefaultNonceExpiryTime)
+
 	// Set default value for kmip version
 	viper.SetDefault("kmip-version", "2.0")
 
@@ -38,7 +49,10 @@ func init() {
 	viper.SetDefault("server-read-header-timeout", constants.DefaultReadHeaderTimeout)
 	viper.SetDefault("server-write-timeout", constants.DefaultWriteTimeout)
 	viper.SetDefault("server-idle-timeout", constants.DefaultIdleTimeout)
+	viper.SetDefault("server-drain-timeout", constants.DefaultDrainTimeout)
+	viper.SetDefault("server-body-read-timeout", constants.DefaultBodyReadTimeout)
 	viper.SetDefault("server-max-header-bytes", constants.DefaultMaxHeaderBytes)
+	viper.SetDefault("server-max-body-bytes", constants.DefaultMaxBodyBytes)
 
 }
 
@@ -62,6 +76,8 @@ func defaultConfig() *config.Configuration {
 			CommonName: viper.GetString("tls-common-name"),
 			SANList:    viper.GetString("tls-san-list"),
 		},
+		TLSReloadInterval: viper.GetDuration("tls-reload-interval"),
+		TLSKeyOverlap:     viper.GetDuration("tls-key-overlap"),
 		Log: commConfig.LogConfig{
 			MaxLength:    viper.GetInt("log-max-length"),
 			EnableStdout: viper.GetBool("log-enable-stdout"),
@@ -73,7 +89,11 @@ func defaultConfig() *config.Configuration {
 			ReadHeaderTimeout: viper.GetDuration("server-read-header-timeout"),
 			WriteTimeout:      viper.GetDuration("server-write-timeout"),
 			IdleTimeout:       viper.GetDuration("server-idle-timeout"),
+			DrainTimeout:      viper.GetDuration("server-drain-timeout"),
+			BodyReadTimeout:   viper.GetDuration("server-body-read-timeout"),
+			MaxResponseBytes:  viper.GetInt64("server-max-response-bytes"),
 			MaxHeaderBytes:    viper.GetInt("server-max-header-bytes"),
+			MaxBodyBytes:      viper.GetInt64("server-max-body-bytes"),
 		},
 		Kmip: config.KmipConfig{
 			Version:    viper.GetString("kmip-version"),
@@ -83,11 +103,30 @@ func defaultConfig() *config.Configuration {
 			ClientKey:  viper.GetString("kmip-client-key-path"),
 			RootCert:   viper.GetString("kmip-root-cert-path"),
 		},
+		CloudKms: config.CloudKmsConfig{
+			Provider: viper.GetString("cloud-kms-provider"),
+			KeyID:    viper.GetString("cloud-kms-key-id"),
+			Region:   viper.GetString("cloud-kms-region"),
+			Endpoint: viper.GetString("cloud-kms-endpoint"),
+		},
 		Skc: config.SKCConfig{
 			StmLabel:          viper.GetString("skc-challenge-type"),
 			SQVSUrl:           viper.GetString("sqvs-url"),
 			SessionExpiryTime: viper.GetInt("session-expiry-time"),
+			NonceExpiryTime:   viper.GetInt("nonce-expiry-time"),
+		},
+		KeyTransferAuth: commConfig.RouteAuthConfig{
+			Mode:               viper.GetString("key-transfer-auth-mode"),
+			AllowedCommonNames: viper.GetStringSlice("key-transfer-auth-allowed-common-names"),
+		},
+		OIDC: commConfig.OIDCConfig{
+			Issuer:           viper.GetString("oidc-issuer"),
+			Audience:         viper.GetString("oidc-audience"),
+			ScopePermissions: viper.GetStringMapStringSlice("oidc-scope-permissions"),
+			JWKSCacheTime:    viper.GetDuration("oidc-jwks-cache-time"),
 		},
+		FipsMode:    viper.GetBool("fips-mode"),
+		DualControl: viper.GetBool("dual-control"),
 	}
 }
 
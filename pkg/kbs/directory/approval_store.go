@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package directory
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// ApprovalStore keeps each approval request in a file named after its id
+type ApprovalStore struct {
+	dir string
+}
+
+func NewApprovalStore(dir string) *ApprovalStore {
+	return &ApprovalStore{dir}
+}
+
+func (as *ApprovalStore) Create(approval *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error) {
+	defaultLog.Trace("directory/approval_store:Create() Entering")
+	defer defaultLog.Trace("directory/approval_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/approval_store:Create() failed to create new UUID")
+	}
+	approval.ID = newUuid
+	approval.RequestedAt = time.Now().UTC()
+	if err = as.write(approval); err != nil {
+		return nil, errors.Wrap(err, "directory/approval_store:Create() Error in saving approval request")
+	}
+
+	return approval, nil
+}
+
+func (as *ApprovalStore) Update(approval *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error) {
+	defaultLog.Trace("directory/approval_store:Update() Entering")
+	defer defaultLog.Trace("directory/approval_store:Update() Leaving")
+
+	if _, err := os.Stat(filepath.Join(as.dir, approval.ID.String())); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "directory/approval_store:Update() Unable to read approval request file : %s", approval.ID.String())
+	}
+
+	if err := as.write(approval); err != nil {
+		return nil, errors.Wrap(err, "directory/approval_store:Update() Error in saving approval request")
+	}
+
+	return approval, nil
+}
+
+func (as *ApprovalStore) Retrieve(id uuid.UUID) (*kbs.ApprovalRequest, error) {
+	defaultLog.Trace("directory/approval_store:Retrieve() Entering")
+	defer defaultLog.Trace("directory/approval_store:Retrieve() Leaving")
+
+	bytes, err := ioutil.ReadFile(filepath.Join(as.dir, id.String()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "directory/approval_store:Retrieve() Unable to read approval request file : %s", id.String())
+	}
+
+	var approval kbs.ApprovalRequest
+	if err = json.Unmarshal(bytes, &approval); err != nil {
+		return nil, errors.Wrap(err, "directory/approval_store:Retrieve() Failed to unmarshal approval request")
+	}
+
+	return &approval, nil
+}
+
+// Search returns the approval requests matching the criteria, the oldest first
+func (as *ApprovalStore) Search(criteria *models.ApprovalFilterCriteria) ([]kbs.ApprovalRequest, error) {
+	defaultLog.Trace("directory/approval_store:Search() Entering")
+	defer defaultLog.Trace("directory/approval_store:Search() Leaving")
+
+	approvalFiles, err := ioutil.ReadDir(as.dir)
+	if err != nil {
+		return nil, errors.New("directory/approval_store:Search() Unable to read the approval request directory")
+	}
+
+	var approvals = []kbs.ApprovalRequest{}
+	for _, approvalFile := range approvalFiles {
+		id, err := uuid.Parse(approvalFile.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "directory/approval_store:Search() Error in parsing approval request file name : %s", approvalFile.Name())
+		}
+		approval, err := as.Retrieve(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "directory/approval_store:Search() Error in retrieving approval request from file : %s", approvalFile.Name())
+		}
+		if criteria != nil && criteria.TenantID != nil && approval.TenantID != *criteria.TenantID {
+			continue
+		}
+		if criteria != nil && criteria.Status != "" && approval.Status != criteria.Status {
+			continue
+		}
+		approvals = append(approvals, *approval)
+	}
+
+	sort.SliceStable(approvals, func(i, j int) bool {
+		return approvals[i].RequestedAt.Before(approvals[j].RequestedAt)
+	})
+	return approvals, nil
+}
+
+func (as *ApprovalStore) write(approval *kbs.ApprovalRequest) error {
+	bytes, err := json.Marshal(approval)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal approval request")
+	}
+	return ioutil.WriteFile(filepath.Join(as.dir, approval.ID.String()), bytes, 0600)
+}
@@ -162,5 +162,27 @@ func filterKeys(keys []models.KeyAttributes, criteria *models.KeyFilterCriteria)
 		keys = filteredKeys
 	}
 
+	// TenantID filter
+	if criteria.TenantID != nil {
+		var filteredKeys []models.KeyAttributes
+		for _, key := range keys {
+			if key.TenantID == *criteria.TenantID {
+				filteredKeys = append(filteredKeys, key)
+			}
+		}
+		keys = filteredKeys
+	}
+
+	// Attributes and creation date filter
+	if len(criteria.Attributes) > 0 || !criteria.CreatedAfter.IsZero() || !criteria.CreatedBefore.IsZero() {
+		var filteredKeys []models.KeyAttributes
+		for i, key := range keys {
+			if criteria.MatchesAttributes(&keys[i]) && criteria.MatchesCreatedAt(&keys[i]) {
+				filteredKeys = append(filteredKeys, key)
+			}
+		}
+		keys = filteredKeys
+	}
+
 	return keys
 }
@@ -50,6 +50,31 @@ func (ktps *KeyTransferPolicyStore) Create(policy *kbs.KeyTransferPolicyAttribut
 	return policy, nil
 }
 
+func (ktps *KeyTransferPolicyStore) Update(policy *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, error) {
+	defaultLog.Trace("directory/key_transfer_policy_store:Update() Entering")
+	defer defaultLog.Trace("directory/key_transfer_policy_store:Update() Leaving")
+
+	policyFile := filepath.Join(ktps.dir, policy.ID.String())
+	if _, err := os.Stat(policyFile); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "directory/key_transfer_policy_store:Update() Unable to read key transfer policy file : %s", policy.ID.String())
+	}
+
+	bytes, err := json.Marshal(policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_transfer_policy_store:Update() Failed to marshal key transfer policy")
+	}
+
+	err = ioutil.WriteFile(policyFile, bytes, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_transfer_policy_store:Update() Error in saving key transfer policy")
+	}
+
+	return policy, nil
+}
+
 func (ktps *KeyTransferPolicyStore) Retrieve(id uuid.UUID) (*kbs.KeyTransferPolicyAttributes, error) {
 	defaultLog.Trace("directory/key_transfer_policy_store:Retrieve() Entering")
 	defer defaultLog.Trace("directory/key_transfer_policy_store:Retrieve() Leaving")
@@ -125,5 +150,17 @@ func filterKeyTransferPolicies(policies []kbs.KeyTransferPolicyAttributes, crite
 	if criteria == nil || reflect.DeepEqual(*criteria, models.KeyTransferPolicyFilterCriteria{}) {
 		return policies
 	}
+
+	// TenantID filter
+	if criteria.TenantID != nil {
+		filteredPolicies := []kbs.KeyTransferPolicyAttributes{}
+		for _, policy := range policies {
+			if policy.TenantID == *criteria.TenantID {
+				filteredPolicies = append(filteredPolicies, policy)
+			}
+		}
+		policies = filteredPolicies
+	}
+
 	return policies
 }
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package directory
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// TenantQuotaStore keeps the quota of each tenant in a file named after the tenant id, the tenant ids are
+// validated against constants.TenantIdPattern before they reach the store
+type TenantQuotaStore struct {
+	dir string
+}
+
+func NewTenantQuotaStore(dir string) *TenantQuotaStore {
+	return &TenantQuotaStore{dir}
+}
+
+func (tqs *TenantQuotaStore) Create(quota *kbs.TenantQuota) (*kbs.TenantQuota, error) {
+	defaultLog.Trace("directory/tenant_quota_store:Create() Entering")
+	defer defaultLog.Trace("directory/tenant_quota_store:Create() Leaving")
+
+	bytes, err := json.Marshal(quota)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/tenant_quota_store:Create() Failed to marshal tenant quota")
+	}
+
+	err = ioutil.WriteFile(filepath.Join(tqs.dir, quota.TenantID), bytes, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/tenant_quota_store:Create() Error in saving tenant quota")
+	}
+
+	return quota, nil
+}
+
+func (tqs *TenantQuotaStore) Retrieve(tenantId string) (*kbs.TenantQuota, error) {
+	defaultLog.Trace("directory/tenant_quota_store:Retrieve() Entering")
+	defer defaultLog.Trace("directory/tenant_quota_store:Retrieve() Leaving")
+
+	bytes, err := ioutil.ReadFile(filepath.Join(tqs.dir, tenantId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		} else {
+			return nil, errors.Wrapf(err, "directory/tenant_quota_store:Retrieve() Unable to read tenant quota file : %s", tenantId)
+		}
+	}
+
+	var quota kbs.TenantQuota
+	err = json.Unmarshal(bytes, &quota)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/tenant_quota_store:Retrieve() Failed to unmarshal tenant quota")
+	}
+
+	return &quota, nil
+}
+
+func (tqs *TenantQuotaStore) Delete(tenantId string) error {
+	defaultLog.Trace("directory/tenant_quota_store:Delete() Entering")
+	defer defaultLog.Trace("directory/tenant_quota_store:Delete() Leaving")
+
+	if err := os.Remove(filepath.Join(tqs.dir, tenantId)); err != nil {
+		if os.IsNotExist(err) {
+			return errors.New(commErr.RecordNotFound)
+		} else {
+			return errors.Wrapf(err, "directory/tenant_quota_store:Delete() Unable to remove tenant quota file : %s", tenantId)
+		}
+	}
+
+	return nil
+}
+
+func (tqs *TenantQuotaStore) Search() ([]kbs.TenantQuota, error) {
+	defaultLog.Trace("directory/tenant_quota_store:Search() Entering")
+	defer defaultLog.Trace("directory/tenant_quota_store:Search() Leaving")
+
+	var quotas = []kbs.TenantQuota{}
+	quotaFiles, err := ioutil.ReadDir(tqs.dir)
+	if err != nil {
+		return nil, errors.New("directory/tenant_quota_store:Search() Unable to read the tenant quota directory")
+	}
+
+	for _, quotaFile := range quotaFiles {
+		quota, err := tqs.Retrieve(quotaFile.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "directory/tenant_quota_store:Search() Error in retrieving tenant quota from file : %s", quotaFile.Name())
+		}
+
+		quotas = append(quotas, *quota)
+	}
+
+	return quotas, nil
+}
@@ -20,6 +20,7 @@ type (
 
 	KeyTransferPolicyStore interface {
 		Create(attributes *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, error)
+		Update(attributes *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, error)
 		Retrieve(uuid.UUID) (*kbs.KeyTransferPolicyAttributes, error)
 		Delete(uuid.UUID) error
 		Search(criteria *models.KeyTransferPolicyFilterCriteria) ([]kbs.KeyTransferPolicyAttributes, error)
@@ -31,4 +32,43 @@ type (
 		Delete(uuid.UUID) error
 		Search(criteria *models.CertificateFilterCriteria) ([]kbs.Certificate, error)
 	}
+
+	TenantQuotaStore interface {
+		// Create stores the quota of a tenant, replacing the existing quota of the tenant
+		Create(quota *kbs.TenantQuota) (*kbs.TenantQuota, error)
+		Retrieve(tenantId string) (*kbs.TenantQuota, error)
+		Delete(tenantId string) error
+		Search() ([]kbs.TenantQuota, error)
+	}
+
+	// ApprovalStore keeps the approval requests of the destructive operations under dual control, with their
+	// audit records. The requests are not deleted.
+	ApprovalStore interface {
+		Create(approval *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error)
+		Update(approval *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error)
+		Retrieve(uuid.UUID) (*kbs.ApprovalRequest, error)
+		Search(criteria *models.ApprovalFilterCriteria) ([]kbs.ApprovalRequest, error)
+	}
+
+	// KeyEscrow keeps a copy of the keys encrypted with an escrow KEK split among custodians, so that the keys can
+	// be recovered into the key store when it is lost. The shares submitted for a recovery are only kept in memory.
+	KeyEscrow interface {
+		Initialize(custodians, threshold int) (*kbs.KeyEscrowShares, error)
+		Status() (*kbs.KeyEscrowStatus, error)
+		SubmitShare(share string) (*kbs.KeyEscrowStatus, error)
+		DiscardShares()
+		Recover() (*kbs.KeyEscrowRecovery, error)
+	}
+
+	// Stores are the stores the routes of KBS keep their records in
+	Stores struct {
+		KeyStore               KeyStore
+		EphemeralKeyStore      KeyStore
+		KeyTransferPolicyStore KeyTransferPolicyStore
+		TenantQuotaStore       TenantQuotaStore
+		ApprovalStore          ApprovalStore
+		SamlCertStore          CertificateStore
+		TpmIdentityCertStore   CertificateStore
+		KeyEscrow              KeyEscrow
+	}
 )
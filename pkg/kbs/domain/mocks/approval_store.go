@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// MockApprovalStore provides a mocked implementation of interface domain.ApprovalStore
+type MockApprovalStore struct {
+	ApprovalStore map[uuid.UUID]*kbs.ApprovalRequest
+}
+
+// Create inserts an ApprovalRequest into the store
+func (store *MockApprovalStore) Create(a *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error) {
+	a.ID = uuid.New()
+	a.RequestedAt = time.Now().UTC()
+	store.ApprovalStore[a.ID] = a
+	return a, nil
+}
+
+// Update replaces an ApprovalRequest of the store
+func (store *MockApprovalStore) Update(a *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error) {
+	if _, ok := store.ApprovalStore[a.ID]; !ok {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+	store.ApprovalStore[a.ID] = a
+	return a, nil
+}
+
+// Retrieve returns a single ApprovalRequest record from the store
+func (store *MockApprovalStore) Retrieve(id uuid.UUID) (*kbs.ApprovalRequest, error) {
+	if a, ok := store.ApprovalStore[id]; ok {
+		// a copy is returned as the controllers modify the requests before updating them
+		approval := *a
+		return &approval, nil
+	}
+	return nil, errors.New(commErr.RecordNotFound)
+}
+
+// Search returns a filtered list of ApprovalRequests per the provided ApprovalFilterCriteria
+func (store *MockApprovalStore) Search(criteria *models.ApprovalFilterCriteria) ([]kbs.ApprovalRequest, error) {
+	approvals := []kbs.ApprovalRequest{}
+	for _, a := range store.ApprovalStore {
+		if criteria != nil && criteria.TenantID != nil && a.TenantID != *criteria.TenantID {
+			continue
+		}
+		if criteria != nil && criteria.Status != "" && a.Status != criteria.Status {
+			continue
+		}
+		approvals = append(approvals, *a)
+	}
+	sort.SliceStable(approvals, func(i, j int) bool {
+		return approvals[i].RequestedAt.Before(approvals[j].RequestedAt)
+	})
+	return approvals, nil
+}
+
+// NewFakeApprovalStore creates an empty MockApprovalStore
+func NewFakeApprovalStore() *MockApprovalStore {
+	return &MockApprovalStore{ApprovalStore: make(map[uuid.UUID]*kbs.ApprovalRequest)}
+}
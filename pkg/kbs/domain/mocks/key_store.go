@@ -104,6 +104,28 @@ func (store *MockKeyStore) Search(criteria *models.KeyFilterCriteria) ([]models.
 		keys = kFiltered
 	}
 
+	// TenantID filter
+	if criteria.TenantID != nil {
+		var kFiltered []models.KeyAttributes
+		for _, k := range keys {
+			if k.TenantID == *criteria.TenantID {
+				kFiltered = append(kFiltered, k)
+			}
+		}
+		keys = kFiltered
+	}
+
+	// Attributes and creation date filter
+	if len(criteria.Attributes) > 0 || !criteria.CreatedAfter.IsZero() || !criteria.CreatedBefore.IsZero() {
+		var kFiltered []models.KeyAttributes
+		for i, k := range keys {
+			if criteria.MatchesAttributes(&keys[i]) && criteria.MatchesCreatedAt(&keys[i]) {
+				kFiltered = append(kFiltered, k)
+			}
+		}
+		keys = kFiltered
+	}
+
 	return keys, nil
 }
 
@@ -27,6 +27,15 @@ func (store *MockKeyTransferPolicyStore) Create(p *kbs.KeyTransferPolicyAttribut
 	return p, nil
 }
 
+// Update replaces a KeyTransferPolicy of the store
+func (store *MockKeyTransferPolicyStore) Update(p *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, error) {
+	if _, ok := store.KeyTransferPolicyStore[p.ID]; !ok {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+	store.KeyTransferPolicyStore[p.ID] = p
+	return p, nil
+}
+
 // Retrieve returns a single KeyTransferPolicy record from the store
 func (store *MockKeyTransferPolicyStore) Retrieve(id uuid.UUID) (*kbs.KeyTransferPolicyAttributes, error) {
 	if p, ok := store.KeyTransferPolicyStore[id]; ok {
@@ -58,6 +67,17 @@ func (store *MockKeyTransferPolicyStore) Search(criteria *models.KeyTransferPoli
 		return policies, nil
 	}
 
+	// TenantID filter
+	if criteria.TenantID != nil {
+		pFiltered := []kbs.KeyTransferPolicyAttributes{}
+		for _, p := range policies {
+			if p.TenantID == *criteria.TenantID {
+				pFiltered = append(pFiltered, p)
+			}
+		}
+		policies = pFiltered
+	}
+
 	return policies, nil
 }
 
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// MockTenantQuotaStore provides a mocked implementation of interface domain.TenantQuotaStore
+type MockTenantQuotaStore struct {
+	TenantQuotaStore map[string]*kbs.TenantQuota
+}
+
+// Create inserts or replaces the TenantQuota of a tenant in the store
+func (store *MockTenantQuotaStore) Create(q *kbs.TenantQuota) (*kbs.TenantQuota, error) {
+	store.TenantQuotaStore[q.TenantID] = q
+	return q, nil
+}
+
+// Retrieve returns the TenantQuota of a tenant from the store
+func (store *MockTenantQuotaStore) Retrieve(tenantId string) (*kbs.TenantQuota, error) {
+	if q, ok := store.TenantQuotaStore[tenantId]; ok {
+		return q, nil
+	}
+	return nil, errors.New(commErr.RecordNotFound)
+}
+
+// Delete deletes the TenantQuota of a tenant from the store
+func (store *MockTenantQuotaStore) Delete(tenantId string) error {
+	if _, ok := store.TenantQuotaStore[tenantId]; ok {
+		delete(store.TenantQuotaStore, tenantId)
+		return nil
+	}
+	return errors.New(commErr.RecordNotFound)
+}
+
+// Search returns all the TenantQuotas in the store
+func (store *MockTenantQuotaStore) Search() ([]kbs.TenantQuota, error) {
+	quotas := []kbs.TenantQuota{}
+	for _, q := range store.TenantQuotaStore {
+		quotas = append(quotas, *q)
+	}
+	return quotas, nil
+}
+
+// NewFakeTenantQuotaStore loads dummy data into MockTenantQuotaStore
+func NewFakeTenantQuotaStore() *MockTenantQuotaStore {
+	store := &MockTenantQuotaStore{}
+	store.TenantQuotaStore = make(map[string]*kbs.TenantQuota)
+
+	_, _ = store.Create(&kbs.TenantQuota{
+		TenantID:               "tenant-a",
+		MaxKeys:                2,
+		MaxKeyTransferPolicies: 1,
+	})
+
+	return store
+}
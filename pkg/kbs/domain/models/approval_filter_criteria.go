@@ -0,0 +1,15 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package models
+
+import "github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+
+// ApprovalFilterCriteria stores the parameters for filtering the approval requests
+type ApprovalFilterCriteria struct {
+	// TenantID restricts the requests to those of a tenant, the empty tenant being the default namespace.
+	// The requests of all tenants are returned when it is nil.
+	TenantID *string
+	Status   kbs.ApprovalStatus
+}
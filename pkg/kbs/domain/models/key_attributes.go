@@ -26,6 +26,22 @@ type KeyAttributes struct {
 	CreatedAt        time.Time `json:"created_at,omitempty"`
 	Label            string    `json:"label,omitempty"`
 	Usage            string    `json:"usage,omitempty"`
+	// ImageFlavorID is the workload service image flavor the key is bound to
+	ImageFlavorID *uuid.UUID `json:"image_flavor_id,omitempty"`
+	// TenantID is the tenant owning the key, keys without a tenant belong to the default namespace
+	TenantID string `json:"tenant_id,omitempty"`
+	// StorageClass is empty for the keys kept in the backing store, ExpiresAt is set for ephemeral keys
+	StorageClass string     `json:"storage_class,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	// Version is incremented by each update of the key
+	Version int `json:"version,omitempty"`
+	// KekID is the cloud KMS key version wrapping WrappedKey, the data encryption key of KeyData or PrivateKey
+	KekID      string `json:"kek_id,omitempty"`
+	WrappedKey string `json:"wrapped_key,omitempty"`
+	// InjectionTargets are where the secret is delivered on the attested node once it is transferred
+	InjectionTargets []kbs.InjectionTarget `json:"injection_targets,omitempty"`
+	// Attributes are arbitrary name/value pairs the keys can be searched with
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 func (ka *KeyAttributes) ToKeyResponse() *kbs.KeyResponse {
@@ -45,6 +61,13 @@ func (ka *KeyAttributes) ToKeyResponse() *kbs.KeyResponse {
 		CreatedAt:        ka.CreatedAt,
 		Label:            ka.Label,
 		Usage:            ka.Usage,
+		ImageFlavorID:    ka.ImageFlavorID,
+		TenantID:         ka.TenantID,
+		StorageClass:     ka.StorageClass,
+		ExpiresAt:        ka.ExpiresAt,
+		Version:          ka.Version,
+		InjectionTargets: ka.InjectionTargets,
+		Attributes:       ka.Attributes,
 	}
 
 	return &keyResponse
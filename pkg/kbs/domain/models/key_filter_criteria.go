@@ -4,7 +4,11 @@
  */
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 //KeyFilterCriteria stores the parameters for filtering the keys
 type KeyFilterCriteria struct {
@@ -12,4 +16,33 @@ type KeyFilterCriteria struct {
 	KeyLength        int
 	CurveType        string
 	TransferPolicyId uuid.UUID
+	// TenantID restricts the keys to those of a tenant, the empty tenant being the default namespace.
+	// The keys of all tenants are returned when it is nil.
+	TenantID *string
+	// Attributes restricts the keys to those with all the attributes, with the same values
+	Attributes map[string]string
+	// CreatedAfter and CreatedBefore restrict the keys to those created in the range, a zero time leaves it open
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// MatchesAttributes returns true when the key has all the attributes of the criteria
+func (kfc *KeyFilterCriteria) MatchesAttributes(key *KeyAttributes) bool {
+	for name, value := range kfc.Attributes {
+		if keyValue, ok := key.Attributes[name]; !ok || keyValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesCreatedAt returns true when the key was created in the range of the criteria
+func (kfc *KeyFilterCriteria) MatchesCreatedAt(key *KeyAttributes) bool {
+	if !kfc.CreatedAfter.IsZero() && !key.CreatedAt.After(kfc.CreatedAfter) {
+		return false
+	}
+	if !kfc.CreatedBefore.IsZero() && !key.CreatedAt.Before(kfc.CreatedBefore) {
+		return false
+	}
+	return true
 }
@@ -6,4 +6,7 @@ package models
 
 //KeyTransferPolicyFilterCriteria stores the parameters for filtering the key transfer policies
 type KeyTransferPolicyFilterCriteria struct {
+	// TenantID restricts the policies to those of a tenant, the empty tenant being the default namespace.
+	// The policies of all tenants are returned when it is nil.
+	TenantID *string
 }
@@ -0,0 +1,454 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package escrow
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = log.GetDefaultLogger()
+
+var (
+	ErrNotInitialized     = errors.New("The key escrow has not been initialized")
+	ErrAlreadyInitialized = errors.New("The key escrow has already been initialized")
+	ErrInvalidShare       = errors.New("The share is not a share of the escrow KEK or has already been submitted")
+	ErrNotEnoughShares    = errors.New("Not enough shares have been submitted to recover the escrow KEK")
+	ErrInvalidShares      = errors.New("The submitted shares do not reconstruct the escrow KEK")
+)
+
+const (
+	recordKeyLength = 32
+	metadataFile    = "escrow.json"
+	recordsDir      = "keys"
+
+	// recordCipherGCMSIV is the cipher of the escrow records
+	recordCipherGCMSIV = "AES-GCM-SIV"
+)
+
+// escrowMetadata describes the escrow KEK, the private key of a P-384 key pair. Only the public key is kept, the KEK
+// itself is split among the custodians.
+type escrowMetadata struct {
+	KekID      uuid.UUID `json:"kek_id"`
+	Custodians int       `json:"custodians"`
+	Threshold  int       `json:"threshold"`
+	CreatedAt  time.Time `json:"created_at"`
+	PublicKey  []byte    `json:"public_key"`
+}
+
+// escrowRecord is a key encrypted with a record key agreed between an ephemeral key pair and the escrow KEK, with
+// AES-GCM-SIV authenticating the key and KEK ids. The record key is derived from the ephemeral public key and the key
+// and KEK ids as well, each record is encrypted with a key of its own.
+type escrowRecord struct {
+	KeyID        uuid.UUID `json:"key_id"`
+	KekID        uuid.UUID `json:"kek_id"`
+	Cipher       string    `json:"cipher,omitempty"`
+	EphemeralKey []byte    `json:"ephemeral_key"`
+	Nonce        []byte    `json:"nonce"`
+	Ciphertext   []byte    `json:"ciphertext"`
+}
+
+// KeyEscrow keeps the escrow records of the keys of the key store in its directory, which is meant to be on another
+// volume than the key store or backed up apart from it. The new keys are escrowed with the public key of the escrow
+// KEK, the KEK is only reconstructed from the shares of the custodians when the keys are recovered.
+type KeyEscrow struct {
+	dir      string
+	keyStore domain.KeyStore
+	mutex    sync.Mutex
+	shares   []string
+}
+
+// NewKeyEscrow returns the escrow of the keys of ks, the escrow records are kept in dir
+func NewKeyEscrow(dir string, ks domain.KeyStore) *KeyEscrow {
+	return &KeyEscrow{
+		dir:      dir,
+		keyStore: ks,
+	}
+}
+
+// Initialize creates the escrow KEK, escrows the keys of the key store with its public key and returns the shares of
+// the KEK, which are not kept by KBS
+func (ke *KeyEscrow) Initialize(custodians, threshold int) (*kbs.KeyEscrowShares, error) {
+	defaultLog.Trace("escrow/key_escrow:Initialize() Entering")
+	defer defaultLog.Trace("escrow/key_escrow:Initialize() Leaving")
+
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+
+	if _, err := ke.readMetadata(); err == nil {
+		return nil, ErrAlreadyInitialized
+	} else if err != ErrNotInitialized {
+		return nil, err
+	}
+
+	kek, err := ecdh.P384().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Initialize() Failed to generate the escrow KEK")
+	}
+	kekBytes := kek.Bytes()
+	shares, err := crypt.SplitKey(kekBytes, custodians, threshold)
+	crypt.Zeroize(kekBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Initialize() Failed to split the escrow KEK")
+	}
+
+	kekId, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Initialize() Failed to create new UUID")
+	}
+	metadata := escrowMetadata{
+		KekID:      kekId,
+		Custodians: custodians,
+		Threshold:  threshold,
+		CreatedAt:  time.Now().UTC(),
+		PublicKey:  kek.PublicKey().Bytes(),
+	}
+
+	if err = os.MkdirAll(filepath.Join(ke.dir, recordsDir), 0700); err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Initialize() Failed to create the escrow directory")
+	}
+	keys, err := ke.keyStore.Search(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Initialize() Failed to search the keys to escrow")
+	}
+	for i := range keys {
+		if err = ke.writeRecord(kek.PublicKey(), kekId, &keys[i]); err != nil {
+			return nil, err
+		}
+	}
+	// the escrow is initialized once its metadata is written, after all the keys have been escrowed
+	if err = writeJSON(filepath.Join(ke.dir, metadataFile), metadata); err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Initialize() Failed to save the escrow metadata")
+	}
+
+	escrowShares := kbs.KeyEscrowShares{
+		KekID:      kekId,
+		Custodians: custodians,
+		Threshold:  threshold,
+		Shares:     shares,
+	}
+	return &escrowShares, nil
+}
+
+// Status returns the escrow KEK description, the number of keys in escrow and of shares submitted for a recovery
+func (ke *KeyEscrow) Status() (*kbs.KeyEscrowStatus, error) {
+	defaultLog.Trace("escrow/key_escrow:Status() Entering")
+	defer defaultLog.Trace("escrow/key_escrow:Status() Leaving")
+
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+
+	metadata, err := ke.readMetadata()
+	if err != nil {
+		return nil, err
+	}
+	records, err := ioutil.ReadDir(filepath.Join(ke.dir, recordsDir))
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Status() Unable to read the escrow directory")
+	}
+
+	return &kbs.KeyEscrowStatus{
+		KekID:           metadata.KekID,
+		Custodians:      metadata.Custodians,
+		Threshold:       metadata.Threshold,
+		CreatedAt:       metadata.CreatedAt,
+		EscrowedKeys:    len(records),
+		SubmittedShares: len(ke.shares),
+	}, nil
+}
+
+// SubmitShare keeps the share of a custodian in memory until the recovery or until the shares are discarded
+func (ke *KeyEscrow) SubmitShare(share string) (*kbs.KeyEscrowStatus, error) {
+	defaultLog.Trace("escrow/key_escrow:SubmitShare() Entering")
+	defer defaultLog.Trace("escrow/key_escrow:SubmitShare() Leaving")
+
+	if err := ke.addShare(share); err != nil {
+		return nil, err
+	}
+	return ke.Status()
+}
+
+func (ke *KeyEscrow) addShare(share string) error {
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+
+	if _, err := ke.readMetadata(); err != nil {
+		return err
+	}
+	if crypt.VerifyKeyShare(share) != nil {
+		return ErrInvalidShare
+	}
+	for _, submitted := range ke.shares {
+		if submitted == share {
+			return ErrInvalidShare
+		}
+	}
+	ke.shares = append(ke.shares, share)
+	return nil
+}
+
+// DiscardShares wipes the shares submitted for a recovery
+func (ke *KeyEscrow) DiscardShares() {
+	defaultLog.Trace("escrow/key_escrow:DiscardShares() Entering")
+	defer defaultLog.Trace("escrow/key_escrow:DiscardShares() Leaving")
+
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+	ke.discardShares()
+}
+
+func (ke *KeyEscrow) discardShares() {
+	ke.shares = nil
+}
+
+// Recover reconstructs the escrow KEK from the submitted shares and restores the escrowed keys missing from the key
+// store, the keys still in the key store are left as they are. The shares are discarded whatever the outcome, the KEK
+// is not kept after the recovery.
+func (ke *KeyEscrow) Recover() (*kbs.KeyEscrowRecovery, error) {
+	defaultLog.Trace("escrow/key_escrow:Recover() Entering")
+	defer defaultLog.Trace("escrow/key_escrow:Recover() Leaving")
+
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+
+	metadata, err := ke.readMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if len(ke.shares) < metadata.Threshold {
+		return nil, ErrNotEnoughShares
+	}
+	combinedKek, err := crypt.CombineKeyShares(ke.shares)
+	ke.discardShares()
+	if err == crypt.ErrNotEnoughShares {
+		return nil, ErrNotEnoughShares
+	} else if err == crypt.ErrInvalidShares {
+		return nil, ErrInvalidShares
+	} else if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Recover() Failed to combine the shares")
+	}
+	kekBuffer, err := crypt.NewSecretBufferFrom(combinedKek)
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Recover() Failed to keep the escrow KEK")
+	}
+	defer kekBuffer.Close()
+	// the shares of a custodian are not authenticated, the reconstructed KEK must be the KEK of the public key
+	kek, err := ecdh.P384().NewPrivateKey(kekBuffer.Bytes())
+	if err != nil || !bytes.Equal(kek.PublicKey().Bytes(), metadata.PublicKey) {
+		return nil, ErrInvalidShares
+	}
+
+	recovery := kbs.KeyEscrowRecovery{RestoredKeys: []uuid.UUID{}}
+	records, err := ioutil.ReadDir(filepath.Join(ke.dir, recordsDir))
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:Recover() Unable to read the escrow directory")
+	}
+	for _, recordFile := range records {
+		key, err := ke.readRecord(kek, metadata.KekID, recordFile.Name())
+		if err != nil {
+			return nil, err
+		}
+		if _, err = ke.keyStore.Retrieve(key.ID); err == nil {
+			recovery.SkippedKeys++
+			continue
+		} else if err.Error() != commErr.RecordNotFound {
+			return nil, errors.Wrapf(err, "escrow/key_escrow:Recover() Failed to retrieve key %s", key.ID)
+		}
+		if _, err = ke.keyStore.Create(key); err != nil {
+			return nil, errors.Wrapf(err, "escrow/key_escrow:Recover() Failed to restore key %s", key.ID)
+		}
+		recovery.RestoredKeys = append(recovery.RestoredKeys, key.ID)
+	}
+	return &recovery, nil
+}
+
+// escrowKey writes the escrow record of the key with the public key of the escrow KEK, nothing is escrowed until the
+// escrow is initialized
+func (ke *KeyEscrow) escrowKey(key *models.KeyAttributes) error {
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+
+	metadata, err := ke.readMetadata()
+	if err == ErrNotInitialized {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	publicKey, err := ecdh.P384().NewPublicKey(metadata.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "escrow/key_escrow:escrowKey() The public key of the escrow KEK is invalid")
+	}
+	return ke.writeRecord(publicKey, metadata.KekID, key)
+}
+
+// removeKey removes the escrow record of a deleted key
+func (ke *KeyEscrow) removeKey(id uuid.UUID) error {
+	ke.mutex.Lock()
+	defer ke.mutex.Unlock()
+
+	err := os.Remove(filepath.Join(ke.dir, recordsDir, id.String()))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "escrow/key_escrow:removeKey() Unable to remove the escrow record of key %s", id)
+	}
+	return nil
+}
+
+func (ke *KeyEscrow) readMetadata() (*escrowMetadata, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(ke.dir, metadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotInitialized
+		}
+		return nil, errors.Wrap(err, "escrow/key_escrow:readMetadata() Unable to read the escrow metadata")
+	}
+	var metadata escrowMetadata
+	if err = json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:readMetadata() Failed to unmarshal the escrow metadata")
+	}
+	return &metadata, nil
+}
+
+func (ke *KeyEscrow) writeRecord(publicKey *ecdh.PublicKey, kekId uuid.UUID, key *models.KeyAttributes) error {
+	plaintext, err := json.Marshal(key)
+	if err != nil {
+		return errors.Wrap(err, "escrow/key_escrow:writeRecord() Failed to marshal key")
+	}
+	defer crypt.Zeroize(plaintext)
+
+	ephemeralKey, err := ecdh.P384().GenerateKey(rand.Reader)
+	if err != nil {
+		return errors.Wrap(err, "escrow/key_escrow:writeRecord() Failed to generate the ephemeral key")
+	}
+	sharedSecret, err := ephemeralKey.ECDH(publicKey)
+	if err != nil {
+		return errors.Wrap(err, "escrow/key_escrow:writeRecord() Failed to agree on the record key")
+	}
+	record := escrowRecord{
+		KeyID:        key.ID,
+		KekID:        kekId,
+		Cipher:       recordCipherGCMSIV,
+		EphemeralKey: ephemeralKey.PublicKey().Bytes(),
+	}
+	recordKey, err := deriveRecordKey(sharedSecret, record.EphemeralKey, key.ID, kekId)
+	if err != nil {
+		return err
+	}
+	defer crypt.Zeroize(recordKey)
+
+	aead, err := newRecordCipher(recordKey, record.Cipher)
+	if err != nil {
+		return err
+	}
+	record.Nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(record.Nonce); err != nil {
+		return errors.Wrap(err, "escrow/key_escrow:writeRecord() Failed to generate nonce")
+	}
+	record.Ciphertext = aead.Seal(nil, record.Nonce, plaintext, recordAAD(key.ID, kekId))
+
+	if err = writeJSON(filepath.Join(ke.dir, recordsDir, key.ID.String()), record); err != nil {
+		return errors.Wrapf(err, "escrow/key_escrow:writeRecord() Failed to save the escrow record of key %s", key.ID)
+	}
+	return nil
+}
+
+func (ke *KeyEscrow) readRecord(kek *ecdh.PrivateKey, kekId uuid.UUID, name string) (*models.KeyAttributes, error) {
+	recordBytes, err := ioutil.ReadFile(filepath.Join(ke.dir, recordsDir, name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "escrow/key_escrow:readRecord() Unable to read escrow record : %s", name)
+	}
+	var record escrowRecord
+	if err = json.Unmarshal(recordBytes, &record); err != nil {
+		return nil, errors.Wrapf(err, "escrow/key_escrow:readRecord() Failed to unmarshal escrow record : %s", name)
+	}
+
+	ephemeralKey, err := ecdh.P384().NewPublicKey(record.EphemeralKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "escrow/key_escrow:readRecord() The ephemeral key of escrow record %s is invalid", name)
+	}
+	sharedSecret, err := kek.ECDH(ephemeralKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "escrow/key_escrow:readRecord() Failed to agree on the key of escrow record %s", name)
+	}
+	recordKey, err := deriveRecordKey(sharedSecret, record.EphemeralKey, record.KeyID, kekId)
+	if err != nil {
+		return nil, err
+	}
+	defer crypt.Zeroize(recordKey)
+
+	aead, err := newRecordCipher(recordKey, record.Cipher)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, record.Nonce, record.Ciphertext, recordAAD(record.KeyID, kekId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "escrow/key_escrow:readRecord() Failed to decrypt escrow record : %s", name)
+	}
+	defer crypt.Zeroize(plaintext)
+
+	var key models.KeyAttributes
+	if err = json.Unmarshal(plaintext, &key); err != nil {
+		return nil, errors.Wrapf(err, "escrow/key_escrow:readRecord() Failed to unmarshal the key of escrow record : %s", name)
+	}
+	if key.ID != record.KeyID {
+		return nil, errors.Errorf("escrow/key_escrow:readRecord() Escrow record %s does not hold key %s", name, record.KeyID)
+	}
+	return &key, nil
+}
+
+// deriveRecordKey derives the key of the escrow record from the secret agreed with its ephemeral key, the shared
+// secret is zeroed
+func deriveRecordKey(sharedSecret, ephemeralKey []byte, keyId, kekId uuid.UUID) ([]byte, error) {
+	defer crypt.Zeroize(sharedSecret)
+	recordKey, err := hkdf.Key(sha512.New384, sharedSecret, ephemeralKey, string(recordAAD(keyId, kekId)), recordKeyLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "escrow/key_escrow:deriveRecordKey() Failed to derive the record key")
+	}
+	return recordKey, nil
+}
+
+// newRecordCipher returns the AEAD of the cipher of an escrow record
+func newRecordCipher(recordKey []byte, recordCipher string) (cipher.AEAD, error) {
+	switch recordCipher {
+	case recordCipherGCMSIV:
+		aead, err := crypt.NewGCMSIV(recordKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "escrow/key_escrow:newRecordCipher() Failed to create GCM-SIV")
+		}
+		return aead, nil
+	default:
+		return nil, errors.Errorf("escrow/key_escrow:newRecordCipher() Unsupported escrow record cipher %s", recordCipher)
+	}
+}
+
+func recordAAD(keyId, kekId uuid.UUID) []byte {
+	return bytes.Join([][]byte{keyId[:], kekId[:]}, nil)
+}
+
+func writeJSON(path string, v interface{}) error {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, jsonBytes, 0600)
+}
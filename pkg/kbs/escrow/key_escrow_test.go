@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package escrow
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKey(t *testing.T) *models.KeyAttributes {
+	return &models.KeyAttributes{
+		ID:        uuid.New(),
+		Algorithm: "AES",
+		KeyLength: 256,
+		KeyData:   base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")),
+	}
+}
+
+func TestKeyEscrowRecovery(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "escrow")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	keysDir := filepath.Join(tempDir, "keys") + "/"
+	assert.NoError(t, os.MkdirAll(keysDir, 0700))
+	keyEscrow := NewKeyEscrow(filepath.Join(tempDir, "escrow"), directory.NewKeyStore(keysDir))
+	keyStore := keyEscrow.KeyStore()
+
+	_, err = keyEscrow.Status()
+	assert.Equal(t, ErrNotInitialized, err)
+
+	// the existing keys are escrowed when the escrow is initialized, the new keys when they are created
+	existingKey := newTestKey(t)
+	_, err = keyStore.Create(existingKey)
+	assert.NoError(t, err)
+	shares, err := keyEscrow.Initialize(3, 2)
+	assert.NoError(t, err)
+	assert.Len(t, shares.Shares, 3)
+	_, err = keyEscrow.Initialize(3, 2)
+	assert.Equal(t, ErrAlreadyInitialized, err)
+
+	newKey := newTestKey(t)
+	_, err = keyStore.Create(newKey)
+	assert.NoError(t, err)
+	deletedKey := newTestKey(t)
+	_, err = keyStore.Create(deletedKey)
+	assert.NoError(t, err)
+	assert.NoError(t, keyStore.Delete(deletedKey.ID))
+
+	status, err := keyEscrow.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, status.EscrowedKeys)
+
+	// only the public key of the KEK is kept, the KEK cannot be found in the escrow directory
+	kek, err := crypt.CombineKeyShares(shares.Shares[:2])
+	assert.NoError(t, err)
+	assert.NoError(t, filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.False(t, bytes.Contains(content, kek), path)
+		assert.False(t, bytes.Contains(content, []byte(base64.StdEncoding.EncodeToString(kek))), path)
+		return nil
+	}))
+
+	// the key store is lost, the new keys are escrowed still
+	assert.NoError(t, os.RemoveAll(keysDir))
+	assert.NoError(t, os.MkdirAll(keysDir, 0700))
+	lostKey := newTestKey(t)
+	_, err = keyStore.Create(lostKey)
+	assert.NoError(t, err)
+	assert.NoError(t, os.RemoveAll(keysDir))
+	assert.NoError(t, os.MkdirAll(keysDir, 0700))
+
+	_, err = keyEscrow.SubmitShare(shares.Shares[2])
+	assert.NoError(t, err)
+	_, err = keyEscrow.SubmitShare(shares.Shares[2])
+	assert.Equal(t, ErrInvalidShare, err)
+	_, err = keyEscrow.Recover()
+	assert.Equal(t, ErrNotEnoughShares, err)
+
+	status, err = keyEscrow.SubmitShare(shares.Shares[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, status.SubmittedShares)
+	recovery, err := keyEscrow.Recover()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, recovery.RestoredKeys, []uuid.UUID{existingKey.ID, newKey.ID, lostKey.ID})
+
+	restoredKey, err := keyStore.Retrieve(newKey.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, newKey.KeyData, restoredKey.KeyData)
+	_, err = keyStore.Retrieve(deletedKey.ID)
+	assert.Error(t, err)
+
+	// the shares are discarded
+	_, err = keyStore.Create(newTestKey(t))
+	assert.NoError(t, err)
+	status, err = keyEscrow.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, status.SubmittedShares)
+	assert.Equal(t, 4, status.EscrowedKeys)
+}
+
+func TestKeyEscrowInvalidShares(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "escrow")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	keysDir := filepath.Join(tempDir, "keys") + "/"
+	assert.NoError(t, os.MkdirAll(keysDir, 0700))
+	keyEscrow := NewKeyEscrow(filepath.Join(tempDir, "escrow"), directory.NewKeyStore(keysDir))
+	shares, err := keyEscrow.Initialize(2, 2)
+	assert.NoError(t, err)
+	_, err = keyEscrow.SubmitShare("short")
+	assert.Equal(t, ErrInvalidShare, err)
+	// a corrupted share is refused when it is submitted
+	corruptedShare := []byte(shares.Shares[0])
+	corruptedShare[len(corruptedShare)-1] ^= 1
+	_, err = keyEscrow.SubmitShare(string(corruptedShare))
+	assert.Equal(t, ErrInvalidShare, err)
+
+	// shares of another KEK do not reconstruct the escrow KEK
+	otherEscrow := NewKeyEscrow(filepath.Join(tempDir, "other"), directory.NewKeyStore(keysDir))
+	otherShares, err := otherEscrow.Initialize(2, 2)
+	assert.NoError(t, err)
+	for _, share := range otherShares.Shares {
+		_, err = keyEscrow.SubmitShare(share)
+		assert.NoError(t, err)
+	}
+	_, err = keyEscrow.Recover()
+	assert.Equal(t, ErrInvalidShares, err)
+
+	status, err := keyEscrow.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, status.SubmittedShares)
+}
+
+func TestKeyEscrowRecords(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "escrow")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	keyEscrow := NewKeyEscrow(tempDir, nil)
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, recordsDir), 0700))
+	kek, err := ecdh.P384().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	kekId := uuid.New()
+
+	// the records are encrypted with AES-GCM-SIV under a key of their own
+	key := newTestKey(t)
+	assert.NoError(t, keyEscrow.writeRecord(kek.PublicKey(), kekId, key))
+	recordBytes, err := ioutil.ReadFile(filepath.Join(tempDir, recordsDir, key.ID.String()))
+	assert.NoError(t, err)
+	var record escrowRecord
+	assert.NoError(t, json.Unmarshal(recordBytes, &record))
+	assert.Equal(t, recordCipherGCMSIV, record.Cipher)
+	assert.NotEmpty(t, record.EphemeralKey)
+	restoredKey, err := keyEscrow.readRecord(kek, kekId, key.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, key.KeyData, restoredKey.KeyData)
+
+	// the records cannot be read with another KEK or as the record of another KEK
+	otherKek, err := ecdh.P384().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, err = keyEscrow.readRecord(otherKek, kekId, key.ID.String())
+	assert.Error(t, err)
+	_, err = keyEscrow.readRecord(kek, uuid.New(), key.ID.String())
+	assert.Error(t, err)
+
+	// the records of an unknown cipher are rejected
+	record.Cipher = "AES-CBC"
+	assert.NoError(t, writeJSON(filepath.Join(tempDir, recordsDir, key.ID.String()), record))
+	_, err = keyEscrow.readRecord(kek, kekId, key.ID.String())
+	assert.Error(t, err)
+}
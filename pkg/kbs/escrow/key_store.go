@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package escrow
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	"github.com/pkg/errors"
+)
+
+// keyStore escrows the keys created in the key store and removes the escrow records of the deleted keys
+type keyStore struct {
+	keyStore domain.KeyStore
+	escrow   *KeyEscrow
+}
+
+// KeyStore returns the key store of the escrow, through which the keys are escrowed once the escrow is initialized
+func (ke *KeyEscrow) KeyStore() domain.KeyStore {
+	return &keyStore{
+		keyStore: ke.keyStore,
+		escrow:   ke,
+	}
+}
+
+func (ks *keyStore) Create(key *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("escrow/key_store:Create() Entering")
+	defer defaultLog.Trace("escrow/key_store:Create() Leaving")
+
+	// the key is escrowed first so that no key is in the key store without being in escrow, the key store updates
+	// the keys through Create as well so the previous escrow record is restored when the update fails
+	previousKey, _ := ks.keyStore.Retrieve(key.ID)
+	if err := ks.escrow.escrowKey(key); err != nil {
+		return nil, errors.Wrap(err, "escrow/key_store:Create() Failed to escrow key")
+	}
+	createdKey, err := ks.keyStore.Create(key)
+	if err != nil {
+		var escrowErr error
+		if previousKey != nil {
+			escrowErr = ks.escrow.escrowKey(previousKey)
+		} else {
+			escrowErr = ks.escrow.removeKey(key.ID)
+		}
+		if escrowErr != nil {
+			defaultLog.WithError(escrowErr).Error("escrow/key_store:Create() Failed to restore the escrow record of key")
+		}
+		return nil, err
+	}
+	return createdKey, nil
+}
+
+func (ks *keyStore) Retrieve(id uuid.UUID) (*models.KeyAttributes, error) {
+	return ks.keyStore.Retrieve(id)
+}
+
+func (ks *keyStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("escrow/key_store:Delete() Entering")
+	defer defaultLog.Trace("escrow/key_store:Delete() Leaving")
+
+	if err := ks.keyStore.Delete(id); err != nil {
+		return err
+	}
+	return ks.escrow.removeKey(id)
+}
+
+func (ks *keyStore) Search(criteria *models.KeyFilterCriteria) ([]models.KeyAttributes, error) {
+	return ks.keyStore.Search(criteria)
+}
@@ -7,7 +7,9 @@ package kbs
 import (
 	"fmt"
 
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/version"
+	commCmd "github.com/intel-secl/intel-secl/v3/pkg/lib/common/cmd"
 )
 
 const helpStr = `Usage:
@@ -17,17 +19,30 @@ Available Commands:
 	help|-h|--help         Show this help message
 	version|-v|--version   Show the version of current kbs build
 	setup <task>           Run setup task
+	run [--standalone]     Run kbs in the foreground
+		--standalone       keys, transfer policies and tenant quotas are kept in the SQLite database /opt/kbs/kbs.db (env: KBS_STANDALONE)
 	start                  Start kbs
 	status                 Show the status of kbs
 	stop                   Stop kbs
 	uninstall [--purge]    Uninstall kbs
 		--purge            all configuration and data files will be removed if this flag is set
+	completion <bash|zsh>  Print the shell completion script, load it with: source <(kbs completion bash)
+
+Global Flags:
+	--output <text|json>   output format of the version and status commands (env: KBS_OUTPUT)
 
 Usage of kbs setup:
-	kbs setup <task> [--help] [--force] [-f <answer-file>]
+	kbs setup <task> [--help] [--force] [-f <answer-file>] [--interactive] [--fix-drift]
+	kbs setup --interactive
+	kbs setup --fix-drift
 		--help                      show help message for setup task
 		--force                     existing configuration will be overwritten if this flag is set
 		-f|--file <answer-file>     the answer file with required arguments
+		--interactive               prompt for the required arguments not in the answer file or environment,
+		                            all setup tasks are run if no task is given
+		--fix-drift                 run again the tasks whose configuration has drifted, like expired or
+		                            reissued certificates or missing database tables, all tasks are checked
+		                            if no task is given
 
 Available Tasks for setup:
 	all                                 Runs all setup tasks
@@ -46,6 +61,11 @@ func (app *App) printUsageWithError(err error) {
 	fmt.Fprintln(app.errorWriter(), helpStr)
 }
 
-func (app *App) printVersion() {
-	fmt.Fprintf(app.consoleWriter(), version.GetVersion())
+func (app *App) printVersion(ctx *commCmd.Context) error {
+	return ctx.Print(version.GetVersion(), commCmd.VersionInfo{
+		ServiceName: constants.ExplicitServiceName,
+		Version:     version.Version,
+		GitHash:     version.GitHash,
+		BuildDate:   version.BuildDate,
+	})
 }
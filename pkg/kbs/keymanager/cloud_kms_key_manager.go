@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/cloudkms"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// CloudKmsManager generates the keys like the directory key manager and encrypts their material with a data
+// encryption key (DEK) per key, the DEK is wrapped by the KEK of the cloud KMS so that the keys kept by KBS cannot
+// be used without the cloud KMS. The KEK never leaves the cloud KMS.
+type CloudKmsManager struct {
+	DirectoryManager
+	provider cloudkms.KekProvider
+}
+
+func (cm *CloudKmsManager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/cloud_kms_key_manager:CreateKey() Entering")
+	defer defaultLog.Trace("keymanager/cloud_kms_key_manager:CreateKey() Leaving")
+
+	keyAttributes, err := cm.DirectoryManager.CreateKey(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.sealKey(keyAttributes); err != nil {
+		return nil, err
+	}
+	return keyAttributes, nil
+}
+
+func (cm *CloudKmsManager) RegisterKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/cloud_kms_key_manager:RegisterKey() Entering")
+	defer defaultLog.Trace("keymanager/cloud_kms_key_manager:RegisterKey() Leaving")
+
+	keyAttributes, err := cm.DirectoryManager.RegisterKey(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.sealKey(keyAttributes); err != nil {
+		return nil, err
+	}
+	return keyAttributes, nil
+}
+
+func (cm *CloudKmsManager) DeleteKey(attributes *models.KeyAttributes) error {
+	defaultLog.Trace("keymanager/cloud_kms_key_manager:DeleteKey() Entering")
+	defer defaultLog.Trace("keymanager/cloud_kms_key_manager:DeleteKey() Leaving")
+
+	// the KEK is shared by all the keys, deleting the key material is enough
+	return nil
+}
+
+// TransferKey unwraps the DEK of the key with the cloud KMS to decrypt the key material. The keys created before
+// the cloud KMS was configured are not wrapped and transferred as they are.
+func (cm *CloudKmsManager) TransferKey(attributes *models.KeyAttributes) ([]byte, error) {
+	defaultLog.Trace("keymanager/cloud_kms_key_manager:TransferKey() Entering")
+	defer defaultLog.Trace("keymanager/cloud_kms_key_manager:TransferKey() Leaving")
+
+	sealedKey, err := cm.DirectoryManager.TransferKey(attributes)
+	if err != nil || attributes.KekID == "" {
+		return sealedKey, err
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(attributes.WrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "keymanager/cloud_kms_key_manager:TransferKey() Failed to decode wrapped key")
+	}
+	unwrappedDek, err := cm.provider.UnwrapKey(attributes.KekID, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := crypt.NewSecretBufferFrom(unwrappedDek)
+	if err != nil {
+		return nil, errors.Wrap(err, "keymanager/cloud_kms_key_manager:TransferKey() Failed to keep DEK")
+	}
+	defer dek.Close()
+
+	gcm, err := newDekGCM(dek.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(sealedKey) < gcm.NonceSize() {
+		return nil, errors.New("keymanager/cloud_kms_key_manager:TransferKey() Invalid sealed key")
+	}
+	nonce, ciphertext := sealedKey[:gcm.NonceSize()], sealedKey[gcm.NonceSize():]
+	key, err := gcm.Open(nil, nonce, ciphertext, []byte(attributes.ID.String()))
+	if err != nil {
+		return nil, errors.Wrap(err, "keymanager/cloud_kms_key_manager:TransferKey() Failed to decrypt key")
+	}
+	return key, nil
+}
+
+// sealKey encrypts the key material with a new DEK, bound to the key id, and wraps the DEK with the KEK
+func (cm *CloudKmsManager) sealKey(attributes *models.KeyAttributes) error {
+	defaultLog.Trace("keymanager/cloud_kms_key_manager:sealKey() Entering")
+	defer defaultLog.Trace("keymanager/cloud_kms_key_manager:sealKey() Leaving")
+
+	keyMaterial := &attributes.PrivateKey
+	if attributes.Algorithm == constants.CRYPTOALG_AES || attributes.Algorithm == constants.CRYPTOALG_AES_XTS {
+		keyMaterial = &attributes.KeyData
+	}
+	key, err := crypt.NewSecretBuffer(base64.StdEncoding.DecodedLen(len(*keyMaterial)))
+	if err != nil {
+		return errors.Wrap(err, "keymanager/cloud_kms_key_manager:sealKey() Failed to allocate key")
+	}
+	defer key.Close()
+	keyLength, err := base64.StdEncoding.Decode(key.Bytes(), []byte(*keyMaterial))
+	if err != nil {
+		return errors.Wrap(err, "keymanager/cloud_kms_key_manager:sealKey() Failed to decode key")
+	}
+
+	dek, err := crypt.NewRandomSecretBuffer(constants.CloudKmsDekLength)
+	if err != nil {
+		return errors.Wrap(err, "keymanager/cloud_kms_key_manager:sealKey() Failed to generate DEK")
+	}
+	defer dek.Close()
+
+	gcm, err := newDekGCM(dek.Bytes())
+	if err != nil {
+		return err
+	}
+	nonce, err := crypt.GetRandomBytes(gcm.NonceSize())
+	if err != nil {
+		return errors.Wrap(err, "keymanager/cloud_kms_key_manager:sealKey() Failed to generate nonce")
+	}
+	sealedKey := gcm.Seal(nonce, nonce, key.Bytes()[:keyLength], []byte(attributes.ID.String()))
+
+	kekId, wrappedKey, err := cm.provider.WrapKey(dek.Bytes())
+	if err != nil {
+		return err
+	}
+	*keyMaterial = base64.StdEncoding.EncodeToString(sealedKey)
+	attributes.KekID = kekId
+	attributes.WrappedKey = base64.StdEncoding.EncodeToString(wrappedKey)
+	return nil
+}
+
+func newDekGCM(dek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, errors.Wrap(err, "keymanager/cloud_kms_key_manager:newDekGCM() Failed to create cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "keymanager/cloud_kms_key_manager:newDekGCM() Failed to create GCM")
+	}
+	return gcm, nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/cloudkms"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCloudKmsManager_CreateAndTransferKey(t *testing.T) {
+	assert := assert.New(t)
+
+	var dek []byte
+	mockProvider := cloudkms.NewMockKekProvider()
+	mockProvider.On("WrapKey", mock.Anything).Run(func(args mock.Arguments) {
+		dek = append([]byte{}, args.Get(0).([]byte)...)
+	}).Return("kek/1", []byte("wrapped"), nil)
+	keyManager := &CloudKmsManager{provider: mockProvider}
+
+	keyRequest := &kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{
+			Algorithm: "AES",
+			KeyLength: 256,
+		},
+	}
+	keyAttributes, err := keyManager.CreateKey(keyRequest)
+	assert.NoError(err)
+	assert.Equal("kek/1", keyAttributes.KekID)
+	assert.Equal(base64.StdEncoding.EncodeToString([]byte("wrapped")), keyAttributes.WrappedKey)
+
+	mockProvider.On("UnwrapKey", "kek/1", []byte("wrapped")).Return(dek, nil)
+	key, err := keyManager.TransferKey(keyAttributes)
+	assert.NoError(err)
+	assert.Len(key, 32)
+
+	// the key material is bound to the key id
+	otherAttributes := *keyAttributes
+	otherAttributes.ID[0] ^= 0xff
+	_, err = keyManager.TransferKey(&otherAttributes)
+	assert.Error(err)
+}
+
+func TestCloudKmsManager_CreateXtsKey(t *testing.T) {
+	assert := assert.New(t)
+
+	var dek []byte
+	mockProvider := cloudkms.NewMockKekProvider()
+	mockProvider.On("WrapKey", mock.Anything).Run(func(args mock.Arguments) {
+		dek = append([]byte{}, args.Get(0).([]byte)...)
+	}).Return("kek/1", []byte("wrapped"), nil)
+	keyManager := &CloudKmsManager{provider: mockProvider}
+
+	keyRequest := &kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{
+			Algorithm: "AES-XTS",
+			KeyLength: 512,
+		},
+	}
+	keyAttributes, err := keyManager.CreateKey(keyRequest)
+	assert.NoError(err)
+	assert.Equal(512, keyAttributes.KeyLength)
+
+	mockProvider.On("UnwrapKey", "kek/1", []byte("wrapped")).Return(dek, nil)
+	key, err := keyManager.TransferKey(keyAttributes)
+	assert.NoError(err)
+	assert.Len(key, 64)
+	// the data key and the tweak key of the volume key are different
+	assert.NotEqual(key[:32], key[32:])
+
+	keyRequest.KeyInformation.KeyLength = 128
+	_, err = keyManager.CreateKey(keyRequest)
+	assert.Error(err)
+}
+
+func TestCloudKmsManager_RegisterKey(t *testing.T) {
+	assert := assert.New(t)
+
+	mockProvider := cloudkms.NewMockKekProvider()
+	mockProvider.On("WrapKey", mock.Anything).Return("", []byte{}, errors.New("access denied"))
+	keyManager := &CloudKmsManager{provider: mockProvider}
+
+	keyRequest := &kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{
+			Algorithm: "AES",
+			KeyLength: 256,
+			KeyString: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+		},
+	}
+	// the key is not registered if the cloud KMS cannot wrap it
+	_, err := keyManager.RegisterKey(keyRequest)
+	assert.Error(err)
+}
+
+func TestCloudKmsManager_TransferUnwrappedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	keyRequest := &kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{
+			Algorithm: "AES",
+			KeyLength: 256,
+		},
+	}
+	directoryManager := &DirectoryManager{}
+	keyAttributes, err := directoryManager.CreateKey(keyRequest)
+	assert.NoError(err)
+
+	// the keys created before the cloud KMS was configured are transferred without the cloud KMS
+	keyManager := &CloudKmsManager{provider: cloudkms.NewMockKekProvider()}
+	key, err := keyManager.TransferKey(keyAttributes)
+	assert.NoError(err)
+	assert.Equal(keyAttributes.KeyData, base64.StdEncoding.EncodeToString(key))
+}
@@ -38,13 +38,22 @@ func (dm *DirectoryManager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttri
 
 	var err error
 	if request.KeyInformation.Algorithm == constants.CRYPTOALG_AES {
-		keyBytes, err := generateAESKey(request.KeyInformation.KeyLength)
+		key, err := generateAESKey(request.KeyInformation.KeyLength)
 		if err != nil {
 			return nil, errors.Wrap(err, "Could not generate AES key")
 		}
+		defer key.Close()
 
 		keyAttributes.KeyLength = request.KeyInformation.KeyLength
-		keyAttributes.KeyData = base64.StdEncoding.EncodeToString(keyBytes)
+		keyAttributes.KeyData = base64.StdEncoding.EncodeToString(key.Bytes())
+	} else if request.KeyInformation.Algorithm == constants.CRYPTOALG_AES_XTS {
+		key, err := crypt.GenerateXtsVolumeKey(request.KeyInformation.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not generate AES-XTS key")
+		}
+		keyAttributes.KeyLength = request.KeyInformation.KeyLength
+		keyAttributes.KeyData = base64.StdEncoding.EncodeToString(key)
+		crypt.Zeroize(key)
 	} else {
 
 		var public crypto.PublicKey
@@ -168,7 +177,7 @@ func (dm *DirectoryManager) TransferKey(attributes *models.KeyAttributes) ([]byt
 	defer defaultLog.Trace("keymanager/directory_key_manager:TransferKey() Leaving")
 
 	var key string
-	if attributes.Algorithm == constants.CRYPTOALG_AES {
+	if attributes.Algorithm == constants.CRYPTOALG_AES || attributes.Algorithm == constants.CRYPTOALG_AES_XTS {
 		key = attributes.KeyData
 	} else {
 		key = attributes.PrivateKey
@@ -177,11 +186,11 @@ func (dm *DirectoryManager) TransferKey(attributes *models.KeyAttributes) ([]byt
 	return base64.StdEncoding.DecodeString(key)
 }
 
-func generateAESKey(length int) ([]byte, error) {
+func generateAESKey(length int) (*crypt.SecretBuffer, error) {
 	defaultLog.Trace("keymanager/directory_key_manager:generateAESKey() Entering")
 	defer defaultLog.Trace("keymanager/directory_key_manager:generateAESKey() Leaving")
 
-	return crypt.GetRandomBytes(length / 8)
+	return crypt.NewRandomSecretBuffer(length / 8)
 }
 
 func generateRSAKeyPair(length int) (crypto.PrivateKey, crypto.PublicKey, error) {
@@ -7,6 +7,7 @@ package keymanager
 import (
 	"strings"
 
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/cloudkms"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
@@ -18,11 +19,17 @@ import (
 
 var defaultLog = log.GetDefaultLogger()
 
-func NewKeyManager(cfg *config.KmipConfig, provider string) (KeyManager, error) {
+func NewKeyManager(cfg *config.KmipConfig, cloudKmsCfg *config.CloudKmsConfig, provider string) (KeyManager, error) {
 	defaultLog.Trace("keymanager/key_manager:NewKeyManager() Entering")
 	defer defaultLog.Trace("keymanager/key_manager:NewKeyManager() Leaving")
 
-	if strings.ToLower(provider) == constants.KmipKeyManager {
+	if strings.ToLower(provider) == constants.CloudKmsKeyManager {
+		kekProvider, err := cloudkms.NewKekProvider(cloudKmsCfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "keymanager/key_manager:NewKeyManager() Failed to initialize cloud KMS provider")
+		}
+		return &CloudKmsManager{provider: kekProvider}, nil
+	} else if strings.ToLower(provider) == constants.KmipKeyManager {
 		kmipClient := kmipclient.NewKmipClient()
 		err := kmipClient.InitializeClient(cfg.Version, cfg.ServerIP, cfg.ServerPort, cfg.ClientCert, cfg.ClientKey, cfg.RootCert)
 		if err != nil {
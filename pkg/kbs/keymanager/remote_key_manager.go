@@ -7,38 +7,48 @@ package keymanager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
 )
 
 type RemoteManager struct {
-	store       domain.KeyStore
-	manager     KeyManager
-	endpointURL string
+	store          domain.KeyStore
+	ephemeralStore domain.KeyStore
+	manager        KeyManager
+	endpointURL    string
 }
 
-func NewRemoteManager(ks domain.KeyStore, km KeyManager, url string) *RemoteManager {
+// NewRemoteManager returns the manager of the keys kept in ks, the ephemeral keys are kept in es and are
+// generated in KBS whatever the key manager, so that they never reach the backing store of km
+func NewRemoteManager(ks domain.KeyStore, es domain.KeyStore, km KeyManager, url string) *RemoteManager {
 	return &RemoteManager{
-		store:       ks,
-		manager:     km,
-		endpointURL: url,
+		store:          ks,
+		ephemeralStore: es,
+		manager:        km,
+		endpointURL:    url,
 	}
 }
 
-func (rm *RemoteManager) CreateKey(request *kbs.KeyRequest) (*kbs.KeyResponse, error) {
+// CreateKey stores the key in the namespace of the tenant, the default namespace being the empty tenant
+func (rm *RemoteManager) CreateKey(request *kbs.KeyRequest, tenantId string) (*kbs.KeyResponse, error) {
 	defaultLog.Trace("keymanager/remote_key_manager:CreateKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:CreateKey() Leaving")
 
-	keyAttributes, err := rm.manager.CreateKey(request)
+	keyAttributes, err := rm.managerFor(request.StorageClass).CreateKey(request)
 	if err != nil {
 		return nil, err
 	}
 
 	keyAttributes.TransferLink = rm.getTransferLink(keyAttributes.ID)
-	storedKey, err := rm.store.Create(keyAttributes)
+	keyAttributes.TenantID = tenantId
+	storedKey, err := rm.createInStore(keyAttributes, request)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +60,7 @@ func (rm *RemoteManager) RetrieveKey(keyId uuid.UUID) (*kbs.KeyResponse, error)
 	defaultLog.Trace("keymanager/remote_key_manager:RetrieveKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:RetrieveKey() Leaving")
 
-	keyAttributes, err := rm.store.Retrieve(keyId)
+	keyAttributes, _, err := rm.retrieve(keyId)
 	if err != nil {
 		return nil, err
 	}
@@ -62,16 +72,16 @@ func (rm *RemoteManager) DeleteKey(keyId uuid.UUID) error {
 	defaultLog.Trace("keymanager/remote_key_manager:DeleteKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:DeleteKey() Leaving")
 
-	keyAttributes, err := rm.store.Retrieve(keyId)
+	keyAttributes, store, err := rm.retrieve(keyId)
 	if err != nil {
 		return err
 	}
 
-	if err := rm.manager.DeleteKey(keyAttributes); err != nil {
+	if err := rm.managerFor(keyAttributes.StorageClass).DeleteKey(keyAttributes); err != nil {
 		return err
 	}
 
-	return rm.store.Delete(keyId)
+	return store.Delete(keyId)
 }
 
 func (rm *RemoteManager) SearchKeys(criteria *models.KeyFilterCriteria) ([]*kbs.KeyResponse, error) {
@@ -82,6 +92,13 @@ func (rm *RemoteManager) SearchKeys(criteria *models.KeyFilterCriteria) ([]*kbs.
 	if err != nil {
 		return nil, err
 	}
+	if rm.ephemeralStore != nil {
+		ephemeralKeys, err := rm.ephemeralStore.Search(criteria)
+		if err != nil {
+			return nil, err
+		}
+		keyAttributesList = append(keyAttributesList, ephemeralKeys...)
+	}
 
 	var keyResponses = []*kbs.KeyResponse{}
 	for _, keyAttributes := range keyAttributesList {
@@ -91,17 +108,62 @@ func (rm *RemoteManager) SearchKeys(criteria *models.KeyFilterCriteria) ([]*kbs.
 	return keyResponses, nil
 }
 
-func (rm *RemoteManager) RegisterKey(request *kbs.KeyRequest) (*kbs.KeyResponse, error) {
+// RegisterKey stores a key created outside of KBS in the namespace of the tenant
+func (rm *RemoteManager) RegisterKey(request *kbs.KeyRequest, tenantId string) (*kbs.KeyResponse, error) {
 	defaultLog.Trace("keymanager/remote_key_manager:RegisterKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:RegisterKey() Leaving")
 
-	keyAttributes, err := rm.manager.RegisterKey(request)
+	keyAttributes, err := rm.managerFor(request.StorageClass).RegisterKey(request)
 	if err != nil {
 		return nil, err
 	}
 
 	keyAttributes.TransferLink = rm.getTransferLink(keyAttributes.ID)
-	storedKey, err := rm.store.Create(keyAttributes)
+	keyAttributes.TenantID = tenantId
+	storedKey, err := rm.createInStore(keyAttributes, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedKey.ToKeyResponse(), nil
+}
+
+// BindImageFlavor binds the key to the image flavor, the binding is removed when imageFlavorId is nil
+func (rm *RemoteManager) BindImageFlavor(keyId uuid.UUID, imageFlavorId *uuid.UUID) (*kbs.KeyResponse, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:BindImageFlavor() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:BindImageFlavor() Leaving")
+
+	keyAttributes, store, err := rm.retrieve(keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	keyAttributes.ImageFlavorID = imageFlavorId
+	keyAttributes.Version++
+	storedKey, err := store.Create(keyAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedKey.ToKeyResponse(), nil
+}
+
+// UpdateAttributes replaces the attributes of the key
+func (rm *RemoteManager) UpdateAttributes(keyId uuid.UUID, attributes map[string]string) (*kbs.KeyResponse, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:UpdateAttributes() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:UpdateAttributes() Leaving")
+
+	keyAttributes, store, err := rm.retrieve(keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributes) == 0 {
+		attributes = nil
+	}
+	keyAttributes.Attributes = attributes
+	keyAttributes.Version++
+	storedKey, err := store.Create(keyAttributes)
 	if err != nil {
 		return nil, err
 	}
@@ -113,12 +175,54 @@ func (rm *RemoteManager) TransferKey(keyId uuid.UUID) ([]byte, error) {
 	defaultLog.Trace("keymanager/remote_key_manager:TransferKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:TransferKey() Leaving")
 
-	keyAttributes, err := rm.store.Retrieve(keyId)
+	keyAttributes, _, err := rm.retrieve(keyId)
 	if err != nil {
 		return nil, err
 	}
 
-	return rm.manager.TransferKey(keyAttributes)
+	return rm.managerFor(keyAttributes.StorageClass).TransferKey(keyAttributes)
+}
+
+// managerFor returns the key manager of the storage class, ephemeral keys are held by KBS itself
+func (rm *RemoteManager) managerFor(storageClass string) KeyManager {
+	if storageClass == constants.KeyStorageEphemeral {
+		return &DirectoryManager{}
+	}
+	return rm.manager
+}
+
+// createInStore keeps the key in the store of the storage class requested, the expiry of ephemeral keys
+// starts when they are created
+func (rm *RemoteManager) createInStore(keyAttributes *models.KeyAttributes, request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	keyAttributes.InjectionTargets = request.InjectionTargets
+	keyAttributes.Attributes = request.Attributes
+	if request.StorageClass != constants.KeyStorageEphemeral {
+		return rm.store.Create(keyAttributes)
+	}
+	if rm.ephemeralStore == nil {
+		return nil, errors.New("Ephemeral keys are not supported")
+	}
+
+	expiresAt := keyAttributes.CreatedAt.Add(time.Duration(request.TTL) * time.Second)
+	keyAttributes.StorageClass = constants.KeyStorageEphemeral
+	keyAttributes.ExpiresAt = &expiresAt
+	return rm.ephemeralStore.Create(keyAttributes)
+}
+
+// retrieve looks the key up in the ephemeral keys first and returns the store it was found in
+func (rm *RemoteManager) retrieve(keyId uuid.UUID) (*models.KeyAttributes, domain.KeyStore, error) {
+	if rm.ephemeralStore != nil {
+		keyAttributes, err := rm.ephemeralStore.Retrieve(keyId)
+		if err == nil {
+			return keyAttributes, rm.ephemeralStore, nil
+		}
+		if err.Error() != commErr.RecordNotFound {
+			return nil, nil, err
+		}
+	}
+
+	keyAttributes, err := rm.store.Retrieve(keyId)
+	return keyAttributes, rm.store, err
 }
 
 func (rm *RemoteManager) getTransferLink(keyId uuid.UUID) string {
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package keytransfer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ChallengeNonce - the nonce issued by KBS with a session challenge. The quote of the client must bind the nonce in
+// its report data before the nonce expires, a nonce is only accepted for one session create request.
+type ChallengeNonce struct {
+	Nonce      []byte
+	ExpiryTime time.Time
+}
+
+// challengeNonceMutex - guards the challenge nonces, the session create requests consume them concurrently
+var challengeNonceMutex sync.Mutex
+
+// issueChallengeNonce - Function to keep the nonce of the challenge until it is consumed or expires
+func (keyInfo KeyDetails) issueChallengeNonce(encSessionID string, nonce []byte, secs int) time.Time {
+	expiryTime := time.Now().Add(time.Second * time.Duration(secs))
+	challengeNonceMutex.Lock()
+	defer challengeNonceMutex.Unlock()
+	keyInfo.ChallengeNonceMap[encSessionID] = ChallengeNonce{
+		Nonce:      nonce,
+		ExpiryTime: expiryTime,
+	}
+	return expiryTime
+}
+
+// ConsumeChallengeNonce - Function to get the nonce issued with the challenge. The nonce is removed whether the
+// attestation of the client succeeds or not, a quote can not be replayed with the same challenge.
+func (keyInfo *KeyDetails) ConsumeChallengeNonce(challenge string) ([]byte, error) {
+	defaultLog.Trace("keytransfer/challenge_nonce:ConsumeChallengeNonce() Entering")
+	defer defaultLog.Trace("keytransfer/challenge_nonce:ConsumeChallengeNonce() Leaving")
+
+	// the lookup and the removal are done at once, two requests can not use the same nonce
+	challengeNonceMutex.Lock()
+	challengeNonce, ok := keyInfo.ChallengeNonceMap[challenge]
+	delete(keyInfo.ChallengeNonceMap, challenge)
+	challengeNonceMutex.Unlock()
+	if !ok {
+		return nil, errors.New("no nonce was issued for the challenge or it has already been used")
+	}
+
+	if challengeNonce.ExpiryTime.Before(time.Now()) {
+		return nil, errors.New("the nonce of the challenge has expired")
+	}
+	return challengeNonce.Nonce, nil
+}
+
+func (keyInfo *KeyDetails) deleteExpiredChallengeNonces() {
+	challengeNonceMutex.Lock()
+	defer challengeNonceMutex.Unlock()
+	for k, challengeNonce := range keyInfo.ChallengeNonceMap {
+		if challengeNonce.ExpiryTime.Before(time.Now()) {
+			delete(keyInfo.ChallengeNonceMap, k)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallengeNonce(t *testing.T) {
+	assert := assert.New(t)
+
+	keyInfo := InitializeKeyInfo()
+	keyInfo.ActiveStmLabel = "SGX"
+	cfg := &config.Configuration{Skc: config.SKCConfig{SessionExpiryTime: 60, NonceExpiryTime: 30}}
+
+	challengeReq, err := keyInfo.BuildChallengeJsonRequest(cfg)
+	assert.NoError(err)
+	assert.NotNil(challengeReq.ChallengeExpiry)
+	assert.True(challengeReq.ChallengeExpiry.Before(keyInfo.SessionMap[challengeReq.Challenge].SessionExpiryTime))
+
+	// the nonce is the UUID of the challenge without '-'
+	challengeUUID, err := base64.StdEncoding.DecodeString(challengeReq.Challenge)
+	assert.NoError(err)
+	nonce, err := keyInfo.ConsumeChallengeNonce(challengeReq.Challenge)
+	assert.NoError(err)
+	assert.Equal(strings.ReplaceAll(string(challengeUUID), "-", ""), string(nonce))
+
+	// the nonce can not be used again
+	_, err = keyInfo.ConsumeChallengeNonce(challengeReq.Challenge)
+	assert.Error(err)
+
+	// the challenge was not issued by KBS
+	_, err = keyInfo.ConsumeChallengeNonce(base64.StdEncoding.EncodeToString([]byte("challenge")))
+	assert.Error(err)
+
+	// the nonce has expired
+	challengeReq, err = keyInfo.BuildChallengeJsonRequest(cfg)
+	assert.NoError(err)
+	keyInfo.ChallengeNonceMap[challengeReq.Challenge] = ChallengeNonce{Nonce: nonce, ExpiryTime: time.Now().Add(-time.Second)}
+	_, err = keyInfo.ConsumeChallengeNonce(challengeReq.Challenge)
+	assert.Error(err)
+	_, ok := keyInfo.ChallengeNonceMap[challengeReq.Challenge]
+	assert.False(ok)
+}
+
+func TestChallengeNonceConsumedOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	keyInfo := InitializeKeyInfo()
+	keyInfo.ActiveStmLabel = "SGX"
+	cfg := &config.Configuration{Skc: config.SKCConfig{SessionExpiryTime: 60, NonceExpiryTime: 30}}
+
+	challengeReq, err := keyInfo.BuildChallengeJsonRequest(cfg)
+	assert.NoError(err)
+
+	// concurrent session create requests replaying the same challenge, only one gets the nonce
+	var wg sync.WaitGroup
+	consumed := make(chan bool, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keyInfo.ConsumeChallengeNonce(challengeReq.Challenge)
+			consumed <- err == nil
+		}()
+	}
+	wg.Wait()
+	close(consumed)
+
+	count := 0
+	for ok := range consumed {
+		if ok {
+			count++
+		}
+	}
+	assert.Equal(1, count)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package keytransfer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/pkg/errors"
+)
+
+// NegotiateCompression - Function to select the payload compression for a session from the list
+// of compression algorithms accepted by the client, in the order of preference of the client.
+// An empty string is returned when none of them is supported by kbs.
+func NegotiateCompression(accepted []string) string {
+	defaultLog.Trace("keytransfer/compression:NegotiateCompression() Entering")
+	defer defaultLog.Trace("keytransfer/compression:NegotiateCompression() Leaving")
+
+	for _, compression := range accepted {
+		if strings.ToLower(strings.TrimSpace(compression)) == constants.CompressionGzip {
+			return constants.CompressionGzip
+		}
+	}
+	return ""
+}
+
+// compressPayload - Function to compress the key data before it is encrypted with the swk. The payload is
+// left as is when it is smaller than the compression threshold or does not shrink when compressed.
+// Returns the payload and the compression applied to it.
+func compressPayload(data []byte, compression string) ([]byte, string, error) {
+	defaultLog.Trace("keytransfer/compression:compressPayload() Entering")
+	defer defaultLog.Trace("keytransfer/compression:compressPayload() Leaving")
+
+	if compression != constants.CompressionGzip || len(data) < constants.CompressionThreshold {
+		return data, "", nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, "", errors.Wrap(err, "keytransfer/compression:compressPayload() Failed to compress payload")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", errors.Wrap(err, "keytransfer/compression:compressPayload() Failed to compress payload")
+	}
+
+	if buf.Len() >= len(data) {
+		defaultLog.Debug("keytransfer/compression:compressPayload() Compressed payload is not smaller, sending it uncompressed")
+		return data, "", nil
+	}
+	return buf.Bytes(), compression, nil
+}
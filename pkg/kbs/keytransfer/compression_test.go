@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(constants.CompressionGzip, NegotiateCompression([]string{"zstd", " GZIP"}))
+	assert.Equal("", NegotiateCompression([]string{"zstd"}))
+	assert.Equal("", NegotiateCompression(nil))
+}
+
+// decryptSGXKeyData reverses the metadata, iv and ciphertext layout built by getKeyForSGX
+func decryptSGXKeyData(t *testing.T, keyData string, swk []byte) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(keyData)
+	assert.NoError(t, err)
+	ivLength := binary.LittleEndian.Uint32(decoded[0:])
+	iv := decoded[12 : 12+ivLength]
+	block, err := aes.NewCipher(swk)
+	assert.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	plain, err := gcm.Open(nil, iv, decoded[12+ivLength:], nil)
+	assert.NoError(t, err)
+	return plain
+}
+
+func TestFetchApplicationKeyCompression(t *testing.T) {
+	assert := assert.New(t)
+
+	swk := make([]byte, 32)
+	keyInfo := InitializeKeyInfo()
+	keyInfo.ActiveStmLabel = constants.DefaultSGXLabel
+	keyInfo.ActiveSessionID = "c2Vzc2lvbg=="
+	keyInfo.SessionMap[keyInfo.ActiveSessionID] = kbs.KeyTransferSession{
+		SWK:         swk,
+		SessionId:   keyInfo.ActiveSessionID,
+		Compression: constants.CompressionGzip,
+	}
+
+	// payloads below the threshold are sent uncompressed
+	smallKey := bytes.Repeat([]byte{0x01}, 32)
+	keyData, err := keyInfo.FetchApplicationKey(smallKey, constants.CRYPTOALG_AES)
+	assert.NoError(err)
+	assert.Equal("", keyInfo.PayloadCompression)
+	assert.Equal(smallKey, decryptSGXKeyData(t, keyData, swk))
+
+	largeKey := bytes.Repeat([]byte("config-bundle"), constants.CompressionThreshold)
+	keyData, err = keyInfo.FetchApplicationKey(largeKey, constants.CRYPTOALG_AES)
+	assert.NoError(err)
+	assert.Equal(constants.CompressionGzip, keyInfo.PayloadCompression)
+
+	reader, err := gzip.NewReader(bytes.NewReader(decryptSGXKeyData(t, keyData, swk)))
+	assert.NoError(err)
+	plain, err := ioutil.ReadAll(reader)
+	assert.NoError(err)
+	assert.Equal(largeKey, plain)
+}
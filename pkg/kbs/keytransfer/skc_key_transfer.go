@@ -18,12 +18,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients"
 	aasClient "github.com/intel-secl/intel-secl/v3/pkg/clients/aas"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/utils"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
@@ -38,41 +40,71 @@ const (
 	wrapSize = 4
 )
 
-// KeyDetails - Key info for skc transfer application key
+// KeyDetails - Key info for skc transfer application key, the key info is kept for one key transfer request
 type KeyDetails struct {
+	*Sessions
 	IssuerCommonName         string
 	ActiveStmLabel           string
 	ActiveSessionID          string
+	PayloadCompression       string
 	ClientCertSHA            string
 	ListOfContexts           []string
 	FinalStmLabels           []string
 	TransferPolicyAttributes *kbs.KeyTransferPolicyAttributes
-	SessionIDMap             map[string]string
-	SessionMap               map[string]kbs.KeyTransferSession
-	SessionResponseMap       map[string]kbs.QuoteVerifyAttributes
 }
 
-var keyInfo *KeyDetails
+// Sessions - the sessions of the skc clients, the sessions are shared by the key transfer and session requests
+type Sessions struct {
+	SessionIDMap       map[string]string
+	SessionMap         map[string]kbs.KeyTransferSession
+	SessionResponseMap map[string]kbs.QuoteVerifyAttributes
+	RATLSSessionMap    map[string]string
+	TransferCacheMap   map[string]TransferCacheEntry
+	ChallengeNonceMap  map[string]ChallengeNonce
+	// SwkBufferMap holds the swk of the sessions in locked memory, the swk of a session refers to its buffer
+	SwkBufferMap map[string]*crypt.SecretBuffer
+}
+
+var sessions *Sessions
+var sessionsOnce sync.Once
+
+// sessionMutex - guards SessionIDMap, SessionMap, SessionResponseMap, RATLSSessionMap, SwkBufferMap and
+// swkReferences, the sessions are created and used by concurrent requests
+var sessionMutex sync.Mutex
+
+// swkReferences - the number of requests using the swk buffers, a buffer of a deleted session is closed by the last
+// request releasing it
+var swkReferences = make(map[*crypt.SecretBuffer]int)
 
 var secLog = log.GetSecurityLogger()
 
+func newSessions() *Sessions {
+	return &Sessions{
+		SessionIDMap:       make(map[string]string),
+		SessionMap:         make(map[string]kbs.KeyTransferSession),
+		SessionResponseMap: make(map[string]kbs.QuoteVerifyAttributes),
+		RATLSSessionMap:    make(map[string]string),
+		TransferCacheMap:   make(map[string]TransferCacheEntry),
+		ChallengeNonceMap:  make(map[string]ChallengeNonce),
+		SwkBufferMap:       make(map[string]*crypt.SecretBuffer),
+	}
+}
+
+// InitializeKeyInfo - Function to create the key info of a request with sessions of its own
 func InitializeKeyInfo() *KeyDetails {
 	defaultLog.Trace("keytransfer/skc_key_transfer:InitializeKeyInfo() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:InitializeKeyInfo() Leaving")
-	keyInfo := new(KeyDetails)
-	keyInfo.SessionIDMap = make(map[string]string)
-	keyInfo.SessionMap = make(map[string]kbs.KeyTransferSession)
-	keyInfo.SessionResponseMap = make(map[string]kbs.QuoteVerifyAttributes)
-	return keyInfo
+	return &KeyDetails{Sessions: newSessions()}
 }
 
+// GetKeyInfo - Function to create the key info of a request, the sessions are shared with the other requests
 func GetKeyInfo() *KeyDetails {
 	defaultLog.Trace("keytransfer/skc_key_transfer:GetKeyInfo() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:GetKeyInfo() Leaving")
-	if keyInfo == nil {
-		keyInfo = InitializeKeyInfo()
-	}
-	return keyInfo
+	sessionsOnce.Do(func() {
+		sessions = newSessions()
+	})
+	return &KeyDetails{Sessions: sessions}
 }
 
 // iterate throuh the slice and append only if value is not present
@@ -115,7 +147,9 @@ func (keyInfo *KeyDetails) PopulateStmLabels(acceptChallenge, stmLabel string) {
 	}
 }
 
-func (keyInfo *KeyDetails) PopulateSessionId(sessionId string) {
+// PopulateSessionId - Function to add the sessions of the Session-Id header of the request, the stm label and
+// session id pairs of the request are returned
+func (keyInfo *KeyDetails) PopulateSessionId(sessionId string) []string {
 	defaultLog.Trace("keytransfer/skc_key_transfer:PopulateSessionId() entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:PopulateSessionId() leaving")
 
@@ -127,6 +161,10 @@ func (keyInfo *KeyDetails) PopulateSessionId(sessionId string) {
 		stmSessionList = appendIfUnique(stmSessionList, sessionId)
 	}
 
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	var stmSessionIDs []string
 	for _, stmSessionStr := range stmSessionList {
 		stmSessionIDPair := strings.Split(stmSessionStr, ":")
 		stmLab := stmSessionIDPair[0]
@@ -134,36 +172,48 @@ func (keyInfo *KeyDetails) PopulateSessionId(sessionId string) {
 		encSessionID := base64.StdEncoding.EncodeToString([]byte(session))
 		if len(stmLab) != 0 {
 			keyInfo.SessionIDMap[stmLab+encSessionID] = encSessionID
+			stmSessionIDs = append(stmSessionIDs, stmLab+encSessionID)
 		}
 	}
+	return stmSessionIDs
+}
+
+// AddSessionID - Function to add a session of the request that was not given in its Session-Id header
+func (keyInfo *KeyDetails) AddSessionID(stmSessionID, encSessionID string) {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	keyInfo.SessionIDMap[stmSessionID] = encSessionID
+}
+
+// RemoveSessionID - Function to remove a session of a request once the key is transferred
+func (keyInfo *KeyDetails) RemoveSessionID(stmSessionID string) {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	delete(keyInfo.SessionIDMap, stmSessionID)
 }
 
-func (keyInfo *KeyDetails) SetUserContext(userCommonName string, cfg *config.Configuration, caCertDir string) error {
+// SetUserContext - Function to get the contexts of the workload role of the user, the tenant of the user is returned
+// for the request
+func (keyInfo *KeyDetails) SetUserContext(userCommonName string, cfg *config.Configuration, caCertDir string) (string, error) {
 	defaultLog.Trace("keytransfer/skc_key_transfer:SetUserContext() entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:SetUserContext() leaving")
 
 	caCerts, err := crypt.GetCertsFromDir(caCertDir)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("keytransfer/skc_key_transfer:SetUserContext() Error while getting certs from %s", constants.TrustedCaCertsDir)
-		return err
+		return "", err
 	}
 
 	client, err := clients.HTTPClientWithCA(caCerts)
 	if err != nil {
 		defaultLog.WithError(err).Error("keytransfer/skc_key_transfer:SetUserContext() Error while creating http client")
-		return err
+		return "", err
 	}
 
-	jwtcl := aasClient.NewJWTClient(cfg.AASApiUrl)
-	jwtcl.HTTPClient = client
-	tokenBytes, err := jwtcl.GetUserToken(cfg.KBS.UserName)
+	tokenBytes, err := aasClient.SharedTokenProvider(cfg.AASApiUrl, cfg.KBS.UserName, cfg.KBS.Password, client).Token()
 	if err != nil {
-		jwtcl.AddUser(cfg.KBS.UserName, cfg.KBS.Password)
-		tokenBytes, err = jwtcl.FetchTokenForUser(cfg.KBS.UserName)
-		if err != nil {
-			defaultLog.WithError(err).Error("keytransfer/skc_key_transfer:SetUserContext() Could not fetch token for user " + cfg.KBS.UserName)
-			return errors.New("Could not fetch token for user " + cfg.KBS.UserName)
-		}
+		defaultLog.WithError(err).Error("keytransfer/skc_key_transfer:SetUserContext() Could not fetch token for user " + cfg.KBS.UserName)
+		return "", errors.New("Could not fetch token for user " + cfg.KBS.UserName)
 	}
 
 	aasClient := aasClient.Client{
@@ -175,12 +225,18 @@ func (keyInfo *KeyDetails) SetUserContext(userCommonName string, cfg *config.Con
 	userDetails, err := aasClient.GetUsers(userCommonName)
 	if err != nil {
 		secLog.WithError(err).Errorf("keytransfer/skc_key_transfer:SetUserContext() Error while getting user details from AAS")
-		return err
+		return "", err
 	}
 	userRoles, err := aasClient.GetRolesForUser(userDetails[0].ID)
 	if err != nil {
 		secLog.WithError(err).Errorf("keytransfer/skc_key_transfer:SetUserContext() Error while getting roles details from AAS")
-		return err
+		return "", err
+	}
+
+	tenantID, err := utils.GetTenantID(userRoles)
+	if err != nil {
+		secLog.WithError(err).Errorf("keytransfer/skc_key_transfer:SetUserContext() %s : Invalid tenant in user roles", commLogMsg.InvalidInputBadParam)
+		return "", err
 	}
 
 	for _, roles := range userRoles {
@@ -190,7 +246,7 @@ func (keyInfo *KeyDetails) SetUserContext(userCommonName string, cfg *config.Con
 			matched, err := regexp.Match(constants.ContextPermissionsRegex, []byte(context))
 			if err != nil {
 				secLog.WithError(err).Errorf("keytransfer/skc_key_transfer:SetUserContext() %s : Context list in workload role does not match that of key transfer policy", commLogMsg.InvalidInputBadParam)
-				return err
+				return "", err
 			}
 
 			if matched {
@@ -202,7 +258,7 @@ func (keyInfo *KeyDetails) SetUserContext(userCommonName string, cfg *config.Con
 		}
 	}
 
-	return nil
+	return tenantID, nil
 }
 
 func (keyInfo *KeyDetails) IsValidClient() bool {
@@ -275,9 +331,11 @@ func prioritizeStmLabels(stmLabels []string) string {
 	defaultLog.Trace("keytransfer/skc_key_transfer:prioritizeStmLabels() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:prioritizeStmLabels() Leaving")
 
-	for _, label := range stmLabels {
-		if label == constants.DefaultSGXLabel {
-			return constants.DefaultSGXLabel
+	for _, preferredLabel := range []string{constants.DefaultSGXLabel, constants.DefaultTDXLabel} {
+		for _, label := range stmLabels {
+			if label == preferredLabel {
+				return preferredLabel
+			}
 		}
 	}
 	return constants.DefaultSWLabel
@@ -343,13 +401,22 @@ func (keyInfo KeyDetails) doesCertcontextListMatchKeyTransferPolicy() bool {
 	return false
 }
 
-func (keyInfo *KeyDetails) IsValidSession(stmLabel string) (validSession, validSGXAttributes, activeSession bool) {
+// IsValidSession - Function to check the session of the request, the session must be one of the sessions of the
+// request and must have been created for the client certificate and the tenant of the request
+func (keyInfo *KeyDetails) IsValidSession(stmLabel, tenantID string, stmSessionIDs []string) (validSession, validSGXAttributes, activeSession bool) {
 	defaultLog.Trace("keytransfer/skc_key_transfer:IsValidSession() entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:IsValidSession() leaving")
 
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
 	var sessionID string
 	sessionFound := false
-	for _, value := range keyInfo.SessionIDMap {
+	for _, stmSessionID := range stmSessionIDs {
+		value, ok := keyInfo.SessionIDMap[stmSessionID]
+		if !ok {
+			continue
+		}
 		sessionID = value
 		_, session := keyInfo.SessionMap[sessionID]
 		// ensure that session id and the stmlabel in key transfer request
@@ -362,7 +429,7 @@ func (keyInfo *KeyDetails) IsValidSession(stmLabel string) (validSession, validS
 			if expiryTime.Before(time.Now()) {
 				defaultLog.Debug("session has expired hence exiting")
 				///delete session from map
-				delete(keyInfo.SessionMap, sessionID)
+				keyInfo.deleteSession(sessionID)
 				return true, true, false
 			}
 			break
@@ -370,9 +437,9 @@ func (keyInfo *KeyDetails) IsValidSession(stmLabel string) (validSession, validS
 	}
 
 	if sessionFound {
-		keyTransferSession := keyInfo.GetSessionObj(sessionID)
-		if keyInfo.ClientCertSHA == keyTransferSession.ClientCertHash {
-			if keyInfo.ActiveStmLabel == constants.DefaultSGXLabel {
+		keyTransferSession := keyInfo.SessionMap[sessionID]
+		if keyInfo.ClientCertSHA == keyTransferSession.ClientCertHash && tenantID == keyTransferSession.TenantID {
+			if keyInfo.ActiveStmLabel == constants.DefaultSGXLabel || keyInfo.ActiveStmLabel == constants.DefaultTDXLabel {
 				attributes := keyInfo.SessionResponseMap[sessionID]
 				if keyInfo.TransferPolicyAttributes.SGXEnforceTCBUptoDate && attributes.TCBLevel == constants.TCBLevelOutOfDate {
 					defaultLog.Debug("keytransfer/skc_key_transfer:IsValidSession() Platform TCB Status is Out of Date")
@@ -390,7 +457,7 @@ func (keyInfo *KeyDetails) IsValidSession(stmLabel string) (validSession, validS
 					return true, true, true
 				} else {
 					///delete session from map
-					delete(keyInfo.SessionMap, sessionID)
+					keyInfo.deleteSession(sessionID)
 					defaultLog.Debug("keytransfer/skc_key_transfer:IsValidSession() Sgx attribute validation failed")
 					return true, false, true
 				}
@@ -410,28 +477,70 @@ func (keyInfo *KeyDetails) deleteExpiredSessions() {
 	defaultLog.Trace("keytransfer/skc_key_transfer:deleteExpiredSessions() entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:deleteExpiredSessions() leaving")
 
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
 	for k := range keyInfo.SessionMap {
 		if keyInfo.SessionMap[k].SessionExpiryTime.Before(time.Now()) {
-			delete(keyInfo.SessionMap, k)
+			keyInfo.deleteSession(k)
 		}
 	}
 }
 
+// KeepSessionSwk - Function to keep the swk buffer of the session until the session is deleted, the returned swk
+// refers to the memory of the buffer
+func (keyInfo *KeyDetails) KeepSessionSwk(encSessionID string, swk *crypt.SecretBuffer) []byte {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	return keyInfo.keepSessionSwk(encSessionID, swk)
+}
+
+func (keyInfo *KeyDetails) keepSessionSwk(encSessionID string, swk *crypt.SecretBuffer) []byte {
+	if previous, ok := keyInfo.SwkBufferMap[encSessionID]; ok && previous != swk {
+		closeSwk(previous)
+	}
+	keyInfo.SwkBufferMap[encSessionID] = swk
+	return swk.Bytes()
+}
+
+// deleteSession - Function to delete the session and zero its swk, sessionMutex is held by the caller
+func (keyInfo *KeyDetails) deleteSession(encSessionID string) {
+	delete(keyInfo.SessionMap, encSessionID)
+	if swk, ok := keyInfo.SwkBufferMap[encSessionID]; ok {
+		delete(keyInfo.SwkBufferMap, encSessionID)
+		closeSwk(swk)
+	}
+}
+
+// closeSwk - Function to zero the swk buffer of a deleted or replaced session, the buffer still in use is closed by
+// the last request releasing it. sessionMutex is held by the caller
+func closeSwk(swk *crypt.SecretBuffer) {
+	if swkReferences[swk] > 0 {
+		return
+	}
+	swk.Close()
+}
+
 func (keyInfo *KeyDetails) BuildChallengeJsonRequest(cfg *config.Configuration) (kbs.ChallengeRequest, error) {
 	defaultLog.Trace("keytransfer/skc_key_transfer:BuildChallengeJsonRequest() entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:BuildChallengeJsonRequest() leaving")
 
 	keyInfo.deleteExpiredSessions()
+	keyInfo.deleteExpiredChallengeNonces()
 
 	var challengeReq kbs.ChallengeRequest
 
 	challengeReq.ChallengeType = keyInfo.ActiveStmLabel
 
-	challenge, err := keyInfo.generateStmChallenge(cfg.Skc.SessionExpiryTime)
+	nonceExpiryTime := cfg.Skc.NonceExpiryTime
+	if nonceExpiryTime <= 0 {
+		nonceExpiryTime = constants.DefaultNonceExpiryTime
+	}
+	challenge, challengeExpiry, err := keyInfo.generateStmChallenge(cfg.Skc.SessionExpiryTime, nonceExpiryTime)
 	if err != nil {
 		return challengeReq, errors.Wrap(err, "Failed to generate challenge")
 	}
 	challengeReq.Challenge = challenge
+	challengeReq.ChallengeExpiry = &challengeExpiry
 	url := cfg.EndpointURL + "/session"
 
 	challengeReq.Link.ChallengeReply.Href = url
@@ -440,14 +549,163 @@ func (keyInfo *KeyDetails) BuildChallengeJsonRequest(cfg *config.Configuration)
 	return challengeReq, nil
 }
 
+// CreateRATLSSession - Function to create a session for a client that presented an attested
+// RA-TLS certificate. The session is bound to the hash of the client certificate.
+func (keyInfo *KeyDetails) CreateRATLSSession(clientCertHash, stmLabel string, attributes kbs.QuoteVerifyAttributes, swk *crypt.SecretBuffer, mins int) (string, error) {
+	defaultLog.Trace("keytransfer/skc_key_transfer:CreateRATLSSession() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:CreateRATLSSession() Leaving")
+
+	keyInfo.deleteExpiredSessions()
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return "", errors.Wrap(err, "keytransfer/skc_key_transfer:CreateRATLSSession() failed to create new UUID")
+	}
+	encSessionID := base64.StdEncoding.EncodeToString([]byte(newUuid.String()))
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	var keytransfer kbs.KeyTransferSession
+	keytransfer.SWK = keyInfo.keepSessionSwk(encSessionID, swk)
+	keytransfer.SessionId = encSessionID
+	keytransfer.ClientCertHash = clientCertHash
+	keytransfer.Stmlabel = stmLabel
+	keytransfer.SessionExpiryTime = time.Now().Add(time.Minute * time.Duration(mins))
+
+	keyInfo.SessionMap[encSessionID] = keytransfer
+	keyInfo.SessionResponseMap[encSessionID] = attributes
+	keyInfo.RATLSSessionMap[clientCertHash] = encSessionID
+
+	return encSessionID, nil
+}
+
+// GetRATLSSession - Function to get the active session established over RA-TLS with the client certificate
+func (keyInfo *KeyDetails) GetRATLSSession(clientCertHash string) (kbs.KeyTransferSession, bool) {
+	defaultLog.Trace("keytransfer/skc_key_transfer:GetRATLSSession() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:GetRATLSSession() Leaving")
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	encSessionID, ok := keyInfo.RATLSSessionMap[clientCertHash]
+	if !ok {
+		return kbs.KeyTransferSession{}, false
+	}
+
+	keyTransferSession, ok := keyInfo.SessionMap[encSessionID]
+	if !ok || keyTransferSession.SessionExpiryTime.Before(time.Now()) {
+		delete(keyInfo.RATLSSessionMap, clientCertHash)
+		return kbs.KeyTransferSession{}, false
+	}
+	return keyTransferSession, true
+}
+
 // GetSessionObj - Function to get the key transfer attributes
 func (keyInfo KeyDetails) GetSessionObj(encSessionID string) kbs.KeyTransferSession {
 	defaultLog.Trace("keytransfer/skc_key_transfer:GetSessionObj() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:GetSessionObj() Leaving")
 
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
 	return keyInfo.SessionMap[encSessionID]
 }
 
+// AcquireSessionObj - Function to get the key transfer attributes of the session for using its swk, the swk remains
+// valid until the returned release function is called even if the session is deleted meanwhile
+func (keyInfo *KeyDetails) AcquireSessionObj(encSessionID string) (kbs.KeyTransferSession, func()) {
+	defaultLog.Trace("keytransfer/skc_key_transfer:AcquireSessionObj() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:AcquireSessionObj() Leaving")
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	keyTransferSession := keyInfo.SessionMap[encSessionID]
+	swk, ok := keyInfo.SwkBufferMap[encSessionID]
+	if !ok {
+		return keyTransferSession, func() {}
+	}
+	swkReferences[swk]++
+
+	return keyTransferSession, func() {
+		sessionMutex.Lock()
+		defer sessionMutex.Unlock()
+
+		swkReferences[swk]--
+		if swkReferences[swk] > 0 {
+			return
+		}
+		delete(swkReferences, swk)
+		if keyInfo.SwkBufferMap[encSessionID] != swk {
+			swk.Close()
+		}
+	}
+}
+
+// SetSessionObj - Function to store the key transfer attributes of the session
+func (keyInfo *KeyDetails) SetSessionObj(encSessionID string, keyTransferSession kbs.KeyTransferSession) {
+	defaultLog.Trace("keytransfer/skc_key_transfer:SetSessionObj() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:SetSessionObj() Leaving")
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	keyInfo.SessionMap[encSessionID] = keyTransferSession
+}
+
+// SetSessionResponse - Function to store the attributes of the quote verified for the session
+func (keyInfo *KeyDetails) SetSessionResponse(encSessionID string, attributes kbs.QuoteVerifyAttributes) {
+	defaultLog.Trace("keytransfer/skc_key_transfer:SetSessionResponse() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:SetSessionResponse() Leaving")
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	keyInfo.SessionResponseMap[encSessionID] = attributes
+}
+
+// GetKeyCachePolicy - Function to get how the key transfer policy lets the client enclave cache the key
+// transferred in the active session, a cached key is discarded at the expiry of the session at the latest
+func (keyInfo *KeyDetails) GetKeyCachePolicy() *kbs.KeyCachePolicy {
+	defaultLog.Trace("keytransfer/skc_key_transfer:GetKeyCachePolicy() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:GetKeyCachePolicy() Leaving")
+
+	policy := keyInfo.TransferPolicyAttributes
+	cachePolicy := &kbs.KeyCachePolicy{
+		Cacheable:       policy.KeyCachingAllowed,
+		ReattestOnReuse: policy.KeyReattestOnReuse,
+	}
+	if !cachePolicy.Cacheable {
+		return cachePolicy
+	}
+
+	cacheUntil := keyInfo.GetSessionObj(keyInfo.ActiveSessionID).SessionExpiryTime
+	if policy.KeyCacheTimeout > 0 {
+		timeout := time.Now().Add(time.Second * time.Duration(policy.KeyCacheTimeout))
+		if cacheUntil.IsZero() || timeout.Before(cacheUntil) {
+			cacheUntil = timeout
+		}
+	}
+	if !cacheUntil.IsZero() {
+		cachePolicy.CacheUntil = &cacheUntil
+	}
+	return cachePolicy
+}
+
+// EndActiveSession - Function to expire the active session, the next key transfer of the client requires a new
+// attestation
+func (keyInfo *KeyDetails) EndActiveSession() {
+	defaultLog.Trace("keytransfer/skc_key_transfer:EndActiveSession() Entering")
+	defer defaultLog.Trace("keytransfer/skc_key_transfer:EndActiveSession() Leaving")
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	keyTransferSession, ok := keyInfo.SessionMap[keyInfo.ActiveSessionID]
+	if !ok {
+		return
+	}
+	keyTransferSession.SessionExpiryTime = time.Now()
+	keyInfo.SessionMap[keyInfo.ActiveSessionID] = keyTransferSession
+}
+
 // validateSgxEnclaveIssuer - Function to Validate SgxEnclaveIssuer
 func (keyInfo KeyDetails) validateSgxEnclaveIssuer(stmSgxEnclaveIssuer string) bool {
 	defaultLog.Trace("keytransfer/skc_key_transfer:validateSgxEnclaveIssuer() Entering")
@@ -589,14 +847,15 @@ func (keyInfo KeyDetails) validateSgxConfigIdSvn(stmSgxConfigSvn string) bool {
 	return false
 }
 
-// generateStmChallenge - Function to generate stm challenge
-func (keyInfo KeyDetails) generateStmChallenge(mins int) (string, error) {
+// generateStmChallenge - Function to generate stm challenge, the random UUID of the challenge without '-' is the
+// nonce the quote of the client must bind within nonceSecs
+func (keyInfo KeyDetails) generateStmChallenge(mins, nonceSecs int) (string, time.Time, error) {
 	defaultLog.Trace("keytransfer/skc_key_transfer:generateStmChallenge() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:generateStmChallenge() Leaving")
 
 	newUuid, err := uuid.NewRandom()
 	if err != nil {
-		return "", errors.Wrap(err, "keytransfer/skc_key_transfer:generateStmChallenge() failed to create new UUID")
+		return "", time.Time{}, errors.Wrap(err, "keytransfer/skc_key_transfer:generateStmChallenge() failed to create new UUID")
 	}
 	encSessionID := base64.StdEncoding.EncodeToString([]byte(newUuid.String()))
 
@@ -606,9 +865,10 @@ func (keyInfo KeyDetails) generateStmChallenge(mins int) (string, error) {
 	keytransfer.Stmlabel = keyInfo.ActiveStmLabel
 	keytransfer.SessionExpiryTime = time.Now().Add(time.Minute * time.Duration(mins))
 
-	keyInfo.SessionMap[encSessionID] = keytransfer
+	keyInfo.SetSessionObj(encSessionID, keytransfer)
+	nonceExpiryTime := keyInfo.issueChallengeNonce(encSessionID, []byte(strings.ReplaceAll(newUuid.String(), "-", "")), nonceSecs)
 
-	return encSessionID, nil
+	return encSessionID, nonceExpiryTime, nil
 }
 
 // FetchApplicationKey - Function to fetch the application key
@@ -619,8 +879,9 @@ func (keyInfo *KeyDetails) FetchApplicationKey(keyData []byte, algorithm string)
 	var transferredKeyData string
 	var err error
 
+	keyInfo.PayloadCompression = ""
 	switch strings.ToUpper(keyInfo.ActiveStmLabel) {
-	case constants.DefaultSGXLabel:
+	case constants.DefaultSGXLabel, constants.DefaultTDXLabel:
 		transferredKeyData, err = keyInfo.getKeyForSGX(keyData, algorithm)
 		if err != nil {
 			return "", errors.Wrap(err, "keytransfer/skc_key_transfer:FetchApplicationKey() Error in getting sgx mode key")
@@ -642,25 +903,19 @@ func (keyInfo *KeyDetails) getKeyForSGX(privateKey []byte, algorithm string) (st
 	defaultLog.Trace("keytransfer/skc_key_transfer:getKeyForSGX() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:getKeyForSGX() Leaving")
 
-	var bytes, nonceByte []byte
+	var bytes, nonceByte, plainBytes []byte
 	var err error
 
-	if err != nil {
-		return "", errors.Wrap(err, "keytransfer/skc_key_transfer:getKeyForSGX() Failed to decode private key")
-	}
-
-	keyTransferSession := keyInfo.GetSessionObj(keyInfo.ActiveSessionID)
+	keyTransferSession, releaseSwk := keyInfo.AcquireSessionObj(keyInfo.ActiveSessionID)
+	defer releaseSwk()
 	if reflect.DeepEqual(keyTransferSession, kbs.KeyTransferSession{}) {
 		defaultLog.Error("keytransfer/skc_key_transfer:getKeyForSGX() session map is empty. Hence can't get swk")
 		return "", errors.New("keytransfer/skc_key_transfer:getKeyForSGX() session map is empty. Hence can't get swk")
 	}
 	swkKey := keyTransferSession.SWK
 
-	if algorithm == constants.CRYPTOALG_AES {
-		bytes, nonceByte, err = AesEncrypt(privateKey, swkKey)
-		if err != nil {
-			return "", errors.Wrap(err, "keytransfer/skc_key_transfer:getKeyForSGX() Failed to encrypt data")
-		}
+	if algorithm == constants.CRYPTOALG_AES || algorithm == constants.CRYPTOALG_AES_XTS {
+		plainBytes = privateKey
 	} else if algorithm == constants.CRYPTOALG_RSA {
 		defaultLog.Trace("RSA key to be transferred")
 		privatePem := pem.EncodeToMemory(
@@ -674,10 +929,7 @@ func (keyInfo *KeyDetails) getKeyForSGX(privateKey []byte, algorithm string) (st
 		if decodedBlock == nil {
 			return "", errors.New("keytransfer/skc_key_transfer:getKeyForSGX() Failed to decode the private key")
 		}
-		bytes, nonceByte, err = AesEncrypt(decodedBlock.Bytes, swkKey)
-		if err != nil {
-			return "", errors.Wrap(err, "keytransfer/skc_key_transfer:getKeyForSGX() Failed to encrypt data")
-		}
+		plainBytes = decodedBlock.Bytes
 	} else if algorithm == constants.CRYPTOALG_EC {
 		///TODO: This needs to be tested.
 		defaultLog.Trace("EC key to be transferred")
@@ -692,10 +944,18 @@ func (keyInfo *KeyDetails) getKeyForSGX(privateKey []byte, algorithm string) (st
 		if decodedBlock == nil {
 			return "", errors.New("keytransfer/skc_key_transfer:getKeyForSGX() Failed to decode the private key")
 		}
-		bytes, nonceByte, err = AesEncrypt(decodedBlock.Bytes, swkKey)
-		if err != nil {
-			return "", errors.Wrap(err, "keytransfer/skc_key_transfer:getKeyForSGX() Failed to encrypt data")
-		}
+		plainBytes = decodedBlock.Bytes
+	}
+
+	// compression is applied before encryption so that the compressed payload is covered by the gcm tag
+	plainBytes, keyInfo.PayloadCompression, err = compressPayload(plainBytes, keyTransferSession.Compression)
+	if err != nil {
+		return "", errors.Wrap(err, "keytransfer/skc_key_transfer:getKeyForSGX() Failed to compress data")
+	}
+
+	bytes, nonceByte, err = AesEncrypt(plainBytes, swkKey)
+	if err != nil {
+		return "", errors.Wrap(err, "keytransfer/skc_key_transfer:getKeyForSGX() Failed to encrypt data")
 	}
 
 	keyMetaDataSize := ivSize + tagSize + wrapSize
@@ -718,7 +978,8 @@ func (keyInfo KeyDetails) getKeyForSW(keyBytes []byte) (string, error) {
 	defaultLog.Trace("keytransfer/skc_key_transfer:getKeyForSW() Entering")
 	defer defaultLog.Trace("keytransfer/skc_key_transfer:getKeyForSW() Leaving")
 
-	keyTransferSession := keyInfo.GetSessionObj(keyInfo.ActiveSessionID)
+	keyTransferSession, releaseSwk := keyInfo.AcquireSessionObj(keyInfo.ActiveSessionID)
+	defer releaseSwk()
 	if reflect.DeepEqual(keyTransferSession, kbs.KeyTransferSession{}) {
 		defaultLog.Error("keytransfer/skc_key_transfer:getKeyForSW() session map is empty. Hence can't get swk")
 		return "", errors.New("keytransfer/skc_key_transfer:getKeyForSW() session map is empty. Hence can't get swk")
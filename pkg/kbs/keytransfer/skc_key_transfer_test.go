@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKeyCachePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	sessionExpiry := time.Now().Add(time.Hour)
+	keyInfo := InitializeKeyInfo()
+	keyInfo.ActiveSessionID = "c2Vzc2lvbg=="
+	keyInfo.SessionMap[keyInfo.ActiveSessionID] = kbs.KeyTransferSession{SessionExpiryTime: sessionExpiry}
+
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{}
+	cachePolicy := keyInfo.GetKeyCachePolicy()
+	assert.False(cachePolicy.Cacheable)
+	assert.Nil(cachePolicy.CacheUntil)
+
+	// the key cannot be cached beyond the session
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{KeyCachingAllowed: true}
+	cachePolicy = keyInfo.GetKeyCachePolicy()
+	assert.True(cachePolicy.Cacheable)
+	assert.Equal(sessionExpiry, *cachePolicy.CacheUntil)
+
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{KeyCachingAllowed: true, KeyCacheTimeout: 60, KeyReattestOnReuse: true}
+	cachePolicy = keyInfo.GetKeyCachePolicy()
+	assert.True(cachePolicy.ReattestOnReuse)
+	assert.True(cachePolicy.CacheUntil.Before(sessionExpiry))
+	assert.WithinDuration(time.Now().Add(time.Minute), *cachePolicy.CacheUntil, 5*time.Second)
+}
+
+func TestEndActiveSession(t *testing.T) {
+	keyInfo := InitializeKeyInfo()
+	keyInfo.ActiveSessionID = "c2Vzc2lvbg=="
+	keyInfo.SessionMap[keyInfo.ActiveSessionID] = kbs.KeyTransferSession{SessionExpiryTime: time.Now().Add(time.Hour)}
+
+	keyInfo.EndActiveSession()
+	assert.False(t, keyInfo.SessionMap[keyInfo.ActiveSessionID].SessionExpiryTime.After(time.Now()))
+}
+
+func TestDeleteExpiredSessionSwk(t *testing.T) {
+	assert := assert.New(t)
+
+	keyInfo := InitializeKeyInfo()
+	swk, err := crypt.NewRandomSecretBuffer(32)
+	assert.NoError(err)
+	sessionID := "c2Vzc2lvbg=="
+	keyInfo.SessionMap[sessionID] = kbs.KeyTransferSession{
+		SessionId:         sessionID,
+		SWK:               keyInfo.KeepSessionSwk(sessionID, swk),
+		SessionExpiryTime: time.Now().Add(-time.Second),
+	}
+	assert.Len(keyInfo.SessionMap[sessionID].SWK, 32)
+
+	// the swk of the expired session is zeroed with the session
+	keyInfo.deleteExpiredSessions()
+	assert.Empty(keyInfo.SessionMap)
+	assert.Empty(keyInfo.SwkBufferMap)
+	assert.Nil(swk.Bytes())
+}
+
+func TestDeleteSessionSwkInUse(t *testing.T) {
+	assert := assert.New(t)
+
+	keyInfo := InitializeKeyInfo()
+	swk, err := crypt.NewRandomSecretBuffer(32)
+	assert.NoError(err)
+	sessionID := "c2Vzc2lvbg=="
+	keyInfo.SessionMap[sessionID] = kbs.KeyTransferSession{
+		SessionId:         sessionID,
+		SWK:               keyInfo.KeepSessionSwk(sessionID, swk),
+		SessionExpiryTime: time.Now().Add(-time.Second),
+	}
+
+	// the swk of a session deleted while a transfer uses it is zeroed when the transfer releases it
+	keyTransferSession, releaseSwk := keyInfo.AcquireSessionObj(sessionID)
+	keyInfo.deleteExpiredSessions()
+	assert.Empty(keyInfo.SessionMap)
+	assert.Len(swk.Bytes(), 32)
+	assert.Len(keyTransferSession.SWK, 32)
+
+	releaseSwk()
+	assert.Nil(swk.Bytes())
+	assert.Empty(swkReferences)
+}
+
+func TestIsValidSessionOfRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	sessions := InitializeKeyInfo().Sessions
+	first := &KeyDetails{Sessions: sessions, ClientCertSHA: "first", TransferPolicyAttributes: &kbs.KeyTransferPolicyAttributes{}}
+	second := &KeyDetails{Sessions: sessions, ClientCertSHA: "second", TransferPolicyAttributes: &kbs.KeyTransferPolicyAttributes{}}
+	for _, keyInfo := range []*KeyDetails{first, second} {
+		stmSessionIDs := keyInfo.PopulateSessionId("SW:" + keyInfo.ClientCertSHA)
+		sessions.SessionMap[sessions.SessionIDMap[stmSessionIDs[0]]] = kbs.KeyTransferSession{
+			Stmlabel:          "SW",
+			ClientCertHash:    keyInfo.ClientCertSHA,
+			TenantID:          "tenant",
+			SessionExpiryTime: time.Now().Add(time.Hour),
+		}
+	}
+
+	// the requests share the sessions, each request only uses its own session
+	validSession, _, activeSession := first.IsValidSession("SW", "tenant", []string{"SW" + "Zmlyc3Q="})
+	assert.True(validSession)
+	assert.True(activeSession)
+	assert.Equal("Zmlyc3Q=", first.ActiveSessionID)
+
+	validSession, _, _ = second.IsValidSession("SW", "tenant", []string{"SW" + "Zmlyc3Q="})
+	assert.False(validSession)
+	assert.Empty(second.ActiveSessionID)
+
+	first.RemoveSessionID("SW" + "Zmlyc3Q=")
+	assert.Len(sessions.SessionIDMap, 1)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package keytransfer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+)
+
+// TransferCacheEntry - a successful key transfer of a session, it is only returned to the client the key was
+// transferred to. The key data is wrapped with the swk of the session.
+type TransferCacheEntry struct {
+	ClientCertHash  string
+	TenantID        string
+	SessionIDHeader string
+	Response        kbs.KeyTransferResponse
+	ExpiryTime      time.Time
+}
+
+// transferCacheMutex - guards TransferCacheMap, the key transfers are cached and looked up by concurrent requests
+var transferCacheMutex sync.Mutex
+
+// CacheTransfer - Function to cache the key transfer of the session for the transfer cache timeout of the key
+// transfer policy, the cached transfer is bound to the client certificate and the tenant of the request
+func (keyInfo *KeyDetails) CacheTransfer(keyID uuid.UUID, stmSessionID, clientCertHash, tenantID, sessionIDHeader string, response kbs.KeyTransferResponse) {
+	defaultLog.Trace("keytransfer/transfer_cache:CacheTransfer() Entering")
+	defer defaultLog.Trace("keytransfer/transfer_cache:CacheTransfer() Leaving")
+
+	transferCacheMutex.Lock()
+	defer transferCacheMutex.Unlock()
+
+	keyInfo.deleteExpiredTransfers()
+
+	timeout := keyInfo.TransferPolicyAttributes.TransferCacheTimeout
+	if timeout <= 0 {
+		return
+	}
+	keyInfo.TransferCacheMap[transferCacheKey(stmSessionID, keyID)] = TransferCacheEntry{
+		ClientCertHash:  clientCertHash,
+		TenantID:        tenantID,
+		SessionIDHeader: sessionIDHeader,
+		Response:        response,
+		ExpiryTime:      time.Now().Add(time.Second * time.Duration(timeout)),
+	}
+}
+
+// GetCachedTransfer - Function to get a cached key transfer of one of the sessions of the request for the client
+// certificate and the tenant of the request, the session does not need to be active anymore. Nothing is returned
+// when the key transfer policy does not allow the caching anymore.
+func (keyInfo *KeyDetails) GetCachedTransfer(keyID uuid.UUID, stmSessionIDs []string, clientCertHash, tenantID string) (TransferCacheEntry, bool) {
+	defaultLog.Trace("keytransfer/transfer_cache:GetCachedTransfer() Entering")
+	defer defaultLog.Trace("keytransfer/transfer_cache:GetCachedTransfer() Leaving")
+
+	if keyInfo.TransferPolicyAttributes == nil || keyInfo.TransferPolicyAttributes.TransferCacheTimeout <= 0 {
+		return TransferCacheEntry{}, false
+	}
+
+	transferCacheMutex.Lock()
+	defer transferCacheMutex.Unlock()
+
+	for _, stmSessionID := range stmSessionIDs {
+		entry, ok := keyInfo.TransferCacheMap[transferCacheKey(stmSessionID, keyID)]
+		if !ok || entry.ExpiryTime.Before(time.Now()) {
+			continue
+		}
+		if entry.ClientCertHash == clientCertHash && entry.TenantID == tenantID {
+			return entry, true
+		}
+	}
+	return TransferCacheEntry{}, false
+}
+
+// deleteExpiredTransfers - Function to delete the expired transfers, transferCacheMutex is held by the caller
+func (keyInfo *KeyDetails) deleteExpiredTransfers() {
+	for k, entry := range keyInfo.TransferCacheMap {
+		if entry.ExpiryTime.Before(time.Now()) {
+			delete(keyInfo.TransferCacheMap, k)
+		}
+	}
+}
+
+func transferCacheKey(stmSessionID string, keyID uuid.UUID) string {
+	return stmSessionID + "/" + keyID.String()
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedTransfer(t *testing.T) {
+	assert := assert.New(t)
+
+	keyID := uuid.New()
+	keyInfo := InitializeKeyInfo()
+	stmSessionIDs := []string{"SGXc2Vzc2lvbg=="}
+	response := kbs.KeyTransferResponse{Status: "success"}
+
+	// nothing is cached without a transfer cache timeout
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{}
+	keyInfo.CacheTransfer(keyID, stmSessionIDs[0], "client", "tenant", "SGX:session", response)
+	_, ok := keyInfo.GetCachedTransfer(keyID, stmSessionIDs, "client", "tenant")
+	assert.False(ok)
+
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{TransferCacheTimeout: 60}
+	keyInfo.CacheTransfer(keyID, stmSessionIDs[0], "client", "tenant", "SGX:session", response)
+	cachedTransfer, ok := keyInfo.GetCachedTransfer(keyID, stmSessionIDs, "client", "tenant")
+	assert.True(ok)
+	assert.Equal("SGX:session", cachedTransfer.SessionIDHeader)
+	assert.Equal(response, cachedTransfer.Response)
+
+	_, ok = keyInfo.GetCachedTransfer(uuid.New(), stmSessionIDs, "client", "tenant")
+	assert.False(ok)
+
+	// the cached transfer is only returned for the sessions of the request
+	_, ok = keyInfo.GetCachedTransfer(keyID, []string{"SGXb3RoZXI="}, "client", "tenant")
+	assert.False(ok)
+
+	// the cached transfer is not returned to another client of the session, nor to another tenant
+	_, ok = keyInfo.GetCachedTransfer(keyID, stmSessionIDs, "other", "tenant")
+	assert.False(ok)
+	_, ok = keyInfo.GetCachedTransfer(keyID, stmSessionIDs, "client", "other")
+	assert.False(ok)
+
+	// the policy no longer allows the caching
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{}
+	_, ok = keyInfo.GetCachedTransfer(keyID, stmSessionIDs, "client", "tenant")
+	assert.False(ok)
+}
+
+func TestCachedTransferConcurrentRequests(t *testing.T) {
+	keyInfo := InitializeKeyInfo()
+	keyInfo.TransferPolicyAttributes = &kbs.KeyTransferPolicyAttributes{TransferCacheTimeout: 60}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				keyID := uuid.New()
+				stmSessionID := "SGX" + keyID.String()
+				keyInfo.CacheTransfer(keyID, stmSessionID, "client", "tenant", "SGX:session", kbs.KeyTransferResponse{})
+				_, ok := keyInfo.GetCachedTransfer(keyID, []string{stmSessionID}, "client", "tenant")
+				assert.True(t, ok)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -33,6 +33,44 @@ func IsTrustedByHvs(saml string, samlReport *samlLib.Saml, keyId uuid.UUID, conf
 	defaultLog.Trace("keytransfer/transfer_with_saml:IsTrustedByHvs() Entering")
 	defer defaultLog.Trace("keytransfer/transfer_with_saml:IsTrustedByHvs() Leaving")
 
+	aikCert, bindingKeyCertBytes, verified := verifyTrustReport(saml, samlReport, keyId, config, remoteManager)
+	if !verified {
+		return false, nil
+	}
+
+	if len(bindingKeyCertBytes) == 0 {
+		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() No binding key certificate in trust report")
+		return false, nil
+	}
+
+	bindingKeyCert, err := x509.ParseCertificate(bindingKeyCertBytes)
+	if err != nil {
+		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Unable to parse Binding Key certificate")
+		return false, nil
+	}
+
+	verified = verifySignature(bindingKeyCert, config.TpmIdentityCertsDir)
+	if !verified {
+		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Binding key certificate not verified by any trusted authority")
+		return false, nil
+	}
+
+	verified = verifyTpmBindingKeyCertificate(bindingKeyCert, aikCert)
+	if !verified {
+		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Binding key certificate has invalid attributes or cannot be verified with the AIK")
+		return false, nil
+	}
+
+	return true, bindingKeyCert
+}
+
+// verifyTrustReport verifies the signature of the trust report, that the host is trusted, that its AIK certificate
+// was issued by a trusted authority and that the tags deployed on the host match the usage policy of the key. It
+// returns the AIK certificate and the binding key certificate of the host.
+func verifyTrustReport(saml string, samlReport *samlLib.Saml, keyId uuid.UUID, config domain.KeyControllerConfig, remoteManager *keymanager.RemoteManager) (*x509.Certificate, []byte, bool) {
+	defaultLog.Trace("keytransfer/transfer_with_saml:verifyTrustReport() Entering")
+	defer defaultLog.Trace("keytransfer/transfer_with_saml:verifyTrustReport() Leaving")
+
 	usagePolicyTags := make(map[string]string, 0)
 	key, _ := remoteManager.RetrieveKey(keyId)
 	if key != nil && key.Usage != "" {
@@ -49,8 +87,8 @@ func IsTrustedByHvs(saml string, samlReport *samlLib.Saml, keyId uuid.UUID, conf
 	saml = pattern.ReplaceAllString(saml, "<")
 	verified := verifySamlSignature(saml, config.SamlCertsDir, config.TrustedCaCertsDir)
 	if !verified {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Invalid signature on trust report")
-		return false, nil
+		defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Invalid signature on trust report")
+		return nil, nil, false
 	}
 
 	var err error
@@ -62,25 +100,25 @@ func IsTrustedByHvs(saml string, samlReport *samlLib.Saml, keyId uuid.UUID, conf
 		switch as.Name {
 		case "TRUST_OVERALL":
 			if as.AttributeValue != "true" {
-				defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Host is not trusted")
-				return false, nil
+				defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Host is not trusted")
+				return nil, nil, false
 			}
 		case "tpmVersion":
 			if as.AttributeValue != "2.0" {
-				defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() TPM version not supported")
-				return false, nil
+				defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() TPM version not supported")
+				return nil, nil, false
 			}
 		case "Binding_Key_Certificate":
 			bindingKeyCertBytes, err = base64.StdEncoding.DecodeString(as.AttributeValue)
 			if err != nil {
-				defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Unable to decode Binding Key Certificate")
-				return false, nil
+				defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Unable to decode Binding Key Certificate")
+				return nil, nil, false
 			}
 		case "AIK_Certificate":
 			aikCertBytes, err = base64.StdEncoding.DecodeString(as.AttributeValue)
 			if err != nil {
-				defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Unable to decode AIK certificate")
-				return false, nil
+				defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Unable to decode AIK certificate")
+				return nil, nil, false
 			}
 		case "TRUST_ASSET_TAG":
 			// check if asset tag is deployed on the host
@@ -96,61 +134,38 @@ func IsTrustedByHvs(saml string, samlReport *samlLib.Saml, keyId uuid.UUID, conf
 	}
 
 	if len(aikCertBytes) == 0 {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Assertion does not include AIK Certificate")
-		return false, nil
+		defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Assertion does not include AIK Certificate")
+		return nil, nil, false
 	}
 
 	aikCert, err := x509.ParseCertificate(aikCertBytes)
 	if err != nil {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Unable to parse AIK certificate")
-		return false, nil
+		defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Unable to parse AIK certificate")
+		return nil, nil, false
 	}
 
 	verified = verifySignature(aikCert, config.TpmIdentityCertsDir)
 	if !verified {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() AIK certificate not verified by any trusted authority")
-		return false, nil
-	}
-
-	if len(bindingKeyCertBytes) == 0 {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() No binding key certificate in trust report")
-		return false, nil
-	}
-
-	bindingKeyCert, err := x509.ParseCertificate(bindingKeyCertBytes)
-	if err != nil {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Unable to parse Binding Key certificate")
-		return false, nil
-	}
-
-	verified = verifySignature(bindingKeyCert, config.TpmIdentityCertsDir)
-	if !verified {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Binding key certificate not verified by any trusted authority")
-		return false, nil
-	}
-
-	verified = verifyTpmBindingKeyCertificate(bindingKeyCert, aikCert)
-	if !verified {
-		defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Binding key certificate has invalid attributes or cannot be verified with the AIK")
-		return false, nil
+		defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() AIK certificate not verified by any trusted authority")
+		return nil, nil, false
 	}
 
 	if len(usagePolicyTags) != 0 {
 		if !assetTagDeployed {
-			defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Asset tags are not deployed on the host, but a usage policy is defined for the requested key")
-			return false, nil
+			defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Asset tags are not deployed on the host, but a usage policy is defined for the requested key")
+			return nil, nil, false
 		}
 
 		// check if all the keys in tagsDeployedOnHost exist in usagePolicyTags and their values match
 		for key, value := range usagePolicyTags {
 			if v, ok := tagsDeployedOnHost[key]; !ok || strings.ToLower(v) != strings.ToLower(value) {
-				defaultLog.Error("keytransfer/transfer_with_saml:IsTrustedByHvs() Usage policy requirements of the key does not match with tags deployed on the host")
-				return false, nil
+				defaultLog.Error("keytransfer/transfer_with_saml:verifyTrustReport() Usage policy requirements of the key does not match with tags deployed on the host")
+				return nil, nil, false
 			}
 		}
 	}
 
-	return true, bindingKeyCert
+	return aikCert, bindingKeyCertBytes, true
 }
 
 //verifySamlSignature verifies signature of the saml report
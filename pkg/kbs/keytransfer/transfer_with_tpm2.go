@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt/tpm2"
+	samlLib "github.com/intel-secl/intel-secl/v3/pkg/lib/saml"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// IsTpmParentTrustedByHvs verifies if the client can be trusted for transfer and that the storage key of the request
+// was created in the TPM of the host of the trust report, it returns the public area of the storage key
+func IsTpmParentTrustedByHvs(samlReport *samlLib.Saml, transferRequest *kbs.Tpm2KeyTransferRequest, keyId uuid.UUID, config domain.KeyControllerConfig, remoteManager *keymanager.RemoteManager) (bool, *tpm2.Public) {
+	defaultLog.Trace("keytransfer/transfer_with_tpm2:IsTpmParentTrustedByHvs() Entering")
+	defer defaultLog.Trace("keytransfer/transfer_with_tpm2:IsTpmParentTrustedByHvs() Leaving")
+
+	aikCert, _, verified := verifyTrustReport(transferRequest.SamlReport, samlReport, keyId, config, remoteManager)
+	if !verified {
+		return false, nil
+	}
+
+	parent, err := tpm2.ParsePublic(transferRequest.ParentPublic)
+	if err != nil {
+		defaultLog.WithError(err).Error("keytransfer/transfer_with_tpm2:IsTpmParentTrustedByHvs() Unable to parse the storage key public area")
+		return false, nil
+	}
+
+	// the key must not leave the TPM once imported, so the parent must be a storage key that can not be duplicated
+	requiredAttributes := tpm2.AttrFixedTPM | tpm2.AttrFixedParent | tpm2.AttrRestricted | tpm2.AttrDecrypt
+	if parent.Attributes&requiredAttributes != requiredAttributes || parent.Attributes&tpm2.AttrSign != 0 {
+		defaultLog.Error("keytransfer/transfer_with_tpm2:IsTpmParentTrustedByHvs() Storage key has incorrect attributes")
+		return false, nil
+	}
+
+	verified = verifyTpmParentCertification(parent, transferRequest.ParentCertifyInfo, transferRequest.ParentCertifySignature, aikCert)
+	if !verified {
+		defaultLog.Error("keytransfer/transfer_with_tpm2:IsTpmParentTrustedByHvs() Storage key cannot be verified with the AIK")
+		return false, nil
+	}
+
+	return true, parent
+}
+
+// verifyTpmParentCertification verifies that the certification is of the storage key and was signed with the AIK
+func verifyTpmParentCertification(parent *tpm2.Public, certifyInfo, certifySignature []byte, aikCert *x509.Certificate) bool {
+	defaultLog.Trace("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Entering")
+	defer defaultLog.Trace("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Leaving")
+
+	attest, err := tpm2.ParseAttest(certifyInfo)
+	if err != nil {
+		defaultLog.WithError(err).Error("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Unable to parse the certify info")
+		return false
+	}
+	if attest.Type != tpm2.StAttestCertify {
+		defaultLog.Errorf("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Attestation of type 0x%04x is not a certification", attest.Type)
+		return false
+	}
+
+	name, err := parent.Name()
+	if err != nil {
+		defaultLog.WithError(err).Error("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Unable to compute the storage key name")
+		return false
+	}
+	if !bytes.Equal(attest.Certify.Name, name) {
+		defaultLog.Error("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Certification is not of the storage key")
+		return false
+	}
+
+	signature, _, err := tpm2.ParseSignature(certifySignature)
+	if err != nil {
+		defaultLog.WithError(err).Error("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Unable to parse the certify signature")
+		return false
+	}
+	if err := signature.Verify(aikCert.PublicKey, certifyInfo); err != nil {
+		defaultLog.WithError(err).Error("keytransfer/transfer_with_tpm2:verifyTpmParentCertification() Invalid certify signature")
+		return false
+	}
+
+	return true
+}
+
+// NewTpm2Object returns the public and sensitive areas of the TPM object of a key, AES keys are transferred as raw
+// bytes and RSA and EC keys as PKCS8, PKCS1 or SEC1 DER. AES-XTS keys are not supported
+func NewTpm2Object(algorithm string, secretKey, authPolicy []byte) (*tpm2.Public, *tpm2.Sensitive, error) {
+	defaultLog.Trace("keytransfer/transfer_with_tpm2:NewTpm2Object() Entering")
+	defer defaultLog.Trace("keytransfer/transfer_with_tpm2:NewTpm2Object() Leaving")
+
+	if strings.ToUpper(algorithm) == "AES" {
+		return tpm2.NewSymCipherObject(secretKey, authPolicy)
+	}
+	// a TPM symmetric object has a single AES key, it cannot hold the data key and the tweak key of a volume key
+	if strings.ToUpper(algorithm) == "AES-XTS" {
+		return nil, nil, errors.New("keytransfer/transfer_with_tpm2:NewTpm2Object() AES-XTS keys cannot be transferred as TPM objects")
+	}
+
+	var privateKey interface{}
+	var err error
+	if privateKey, err = x509.ParsePKCS8PrivateKey(secretKey); err != nil {
+		if privateKey, err = x509.ParsePKCS1PrivateKey(secretKey); err != nil {
+			if privateKey, err = x509.ParseECPrivateKey(secretKey); err != nil {
+				return nil, nil, errors.Wrap(err, "keytransfer/transfer_with_tpm2:NewTpm2Object() Unable to parse the private key")
+			}
+		}
+	}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return tpm2.NewRSAObject(key, authPolicy)
+	case *ecdsa.PrivateKey:
+		return tpm2.NewECCObject(key, authPolicy)
+	}
+	return nil, nil, errors.Errorf("keytransfer/transfer_with_tpm2:NewTpm2Object() Unsupported key algorithm %s", algorithm)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package memory
+
+import (
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = log.GetDefaultLogger()
+
+// ephemeralKey keeps the key material apart from the other attributes, in buffers locked in memory so that it
+// is never swapped out. The buffers are wiped when the key expires or is deleted.
+type ephemeralKey struct {
+	attributes models.KeyAttributes
+	keyData    []byte
+	privateKey []byte
+	expiry     *time.Timer
+}
+
+// KeyStore keeps the ephemeral keys, the keys are never written to disk and are removed once their ExpiresAt is reached
+type KeyStore struct {
+	mutex sync.Mutex
+	keys  map[uuid.UUID]*ephemeralKey
+}
+
+func NewKeyStore() *KeyStore {
+	return &KeyStore{keys: map[uuid.UUID]*ephemeralKey{}}
+}
+
+func (ks *KeyStore) Create(key *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("memory/key_store:Create() Entering")
+	defer defaultLog.Trace("memory/key_store:Create() Leaving")
+
+	if key.ExpiresAt == nil {
+		return nil, errors.New("memory/key_store:Create() Ephemeral key must have an expiry")
+	}
+	ttl := time.Until(*key.ExpiresAt)
+	if ttl <= 0 {
+		return nil, errors.New("memory/key_store:Create() Ephemeral key has already expired")
+	}
+
+	entry := &ephemeralKey{
+		attributes: *key,
+		keyData:    lockedCopy(key.KeyData),
+		privateKey: lockedCopy(key.PrivateKey),
+	}
+	entry.attributes.KeyData = ""
+	entry.attributes.PrivateKey = ""
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	if existing, ok := ks.keys[key.ID]; ok {
+		// updates of the key, such as an image flavor binding, keep its expiry
+		expiresAt := existing.attributes.ExpiresAt
+		existing.attributes = entry.attributes
+		existing.attributes.ExpiresAt = expiresAt
+		wipe(entry.keyData)
+		wipe(entry.privateKey)
+		return key, nil
+	}
+
+	id := key.ID
+	entry.expiry = time.AfterFunc(ttl, func() {
+		defaultLog.Debugf("memory/key_store:Create() Ephemeral key %s expired", id.String())
+		_ = ks.Delete(id)
+	})
+	ks.keys[id] = entry
+
+	return key, nil
+}
+
+func (ks *KeyStore) Retrieve(id uuid.UUID) (*models.KeyAttributes, error) {
+	defaultLog.Trace("memory/key_store:Retrieve() Entering")
+	defer defaultLog.Trace("memory/key_store:Retrieve() Leaving")
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	entry, ok := ks.keys[id]
+	if !ok || !entry.attributes.ExpiresAt.After(time.Now()) {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+
+	key := entry.attributes
+	key.KeyData = string(entry.keyData)
+	key.PrivateKey = string(entry.privateKey)
+	return &key, nil
+}
+
+func (ks *KeyStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("memory/key_store:Delete() Entering")
+	defer defaultLog.Trace("memory/key_store:Delete() Leaving")
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	entry, ok := ks.keys[id]
+	if !ok {
+		return errors.New(commErr.RecordNotFound)
+	}
+
+	entry.expiry.Stop()
+	wipe(entry.keyData)
+	wipe(entry.privateKey)
+	delete(ks.keys, id)
+
+	return nil
+}
+
+func (ks *KeyStore) Search(criteria *models.KeyFilterCriteria) ([]models.KeyAttributes, error) {
+	defaultLog.Trace("memory/key_store:Search() Entering")
+	defer defaultLog.Trace("memory/key_store:Search() Leaving")
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	var keys = []models.KeyAttributes{}
+	now := time.Now()
+	for _, entry := range ks.keys {
+		if entry.attributes.ExpiresAt.After(now) && matches(&entry.attributes, criteria) {
+			keys = append(keys, entry.attributes)
+		}
+	}
+
+	return keys, nil
+}
+
+func matches(key *models.KeyAttributes, criteria *models.KeyFilterCriteria) bool {
+	if criteria == nil {
+		return true
+	}
+	if criteria.Algorithm != "" && key.Algorithm != criteria.Algorithm {
+		return false
+	}
+	if criteria.KeyLength != 0 && key.KeyLength != criteria.KeyLength {
+		return false
+	}
+	if criteria.CurveType != "" && key.CurveType != criteria.CurveType {
+		return false
+	}
+	if criteria.TransferPolicyId != uuid.Nil && key.TransferPolicyId != criteria.TransferPolicyId {
+		return false
+	}
+	if criteria.TenantID != nil && key.TenantID != *criteria.TenantID {
+		return false
+	}
+	return criteria.MatchesAttributes(key) && criteria.MatchesCreatedAt(key)
+}
+
+// lockedCopy copies the key material into a buffer locked in memory, the buffer is still used when the lock
+// fails, e.g. when RLIMIT_MEMLOCK is reached
+func lockedCopy(material string) []byte {
+	if material == "" {
+		return nil
+	}
+	buffer := make([]byte, len(material))
+	copy(buffer, material)
+	if err := syscall.Mlock(buffer); err != nil {
+		defaultLog.WithError(err).Warn("memory/key_store:lockedCopy() Unable to lock the key material in memory")
+	}
+	return buffer
+}
+
+func wipe(buffer []byte) {
+	if buffer == nil {
+		return
+	}
+	for i := range buffer {
+		buffer[i] = 0
+	}
+	_ = syscall.Munlock(buffer)
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+)
+
+// setApprovalRoutes registers routes to review the approval requests of the operations under dual control
+func setApprovalRoutes(router *mux.Router, endpointUrl string, stores *domain.Stores, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+	defaultLog.Trace("router/approvals:setApprovalRoutes() Entering")
+	defer defaultLog.Trace("router/approvals:setApprovalRoutes() Leaving")
+
+	approvalController := newApprovalController(endpointUrl, stores, config, keyManager)
+	approvalIdExpr := "/approvals/" + validation.IdReg
+
+	router.Handle("/approvals",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.Search),
+			[]string{constants.ApprovalSearch}))).Methods("GET")
+
+	router.Handle(approvalIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.Retrieve),
+			[]string{constants.ApprovalRetrieve}))).Methods("GET")
+
+	router.Handle(approvalIdExpr+"/approve",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.Approve),
+			[]string{constants.ApprovalApprove}))).Methods("POST")
+
+	router.Handle(approvalIdExpr+"/reject",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.Reject),
+			[]string{constants.ApprovalApprove}))).Methods("POST")
+
+	return router
+}
+
+// setDualControlRoutes registers the routes of the destructive operations creating approval requests instead of
+// executing the operations, they are registered before the key and key transfer policy routes to take precedence
+func setDualControlRoutes(router *mux.Router, endpointUrl string, stores *domain.Stores, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+	defaultLog.Trace("router/approvals:setDualControlRoutes() Entering")
+	defer defaultLog.Trace("router/approvals:setDualControlRoutes() Leaving")
+
+	approvalController := newApprovalController(endpointUrl, stores, config, keyManager)
+
+	router.Handle("/keys/"+validation.IdReg,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.RequestKeyDeletion),
+			[]string{constants.KeyDelete}))).Methods("DELETE")
+
+	router.Handle("/key-transfer-policies/"+validation.IdReg,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.RequestKeyTransferPolicyUpdate),
+			[]string{constants.KeyTransferPolicyUpdate}))).Methods("PUT")
+
+	router.Handle("/key-transfer-policies/"+validation.IdReg,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(approvalController.RequestKeyTransferPolicyDeletion),
+			[]string{constants.KeyTransferPolicyDelete}))).Methods("DELETE")
+
+	return router
+}
+
+func newApprovalController(endpointUrl string, stores *domain.Stores, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *controllers.ApprovalController {
+	remoteManager := keymanager.NewRemoteManager(stores.KeyStore, stores.EphemeralKeyStore, keyManager, endpointUrl)
+	keyController := controllers.NewKeyController(remoteManager, stores.KeyTransferPolicyStore, stores.TenantQuotaStore, config)
+	policyController := controllers.NewKeyTransferPolicyController(stores.KeyTransferPolicyStore, stores.KeyStore, stores.TenantQuotaStore)
+	return controllers.NewApprovalController(stores.ApprovalStore, keyController, policyController)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	ct "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/pkg/errors"
 )
@@ -166,10 +167,7 @@ func permissionsHandlerUsingTLSMAuth(eh endpointHandler, aasAPIUrl string, kbsCo
 
 		client, err := clients.HTTPClientWithCA(caCerts)
 
-		jwtcl := aasClient.NewJWTClient(aasAPIUrl)
-		jwtcl.HTTPClient = client
-		jwtcl.AddUser(kbsConfig.UserName, kbsConfig.Password)
-		tokenBytes, err := jwtcl.FetchTokenForUser(kbsConfig.UserName)
+		tokenBytes, err := aasClient.SharedTokenProvider(aasAPIUrl, kbsConfig.UserName, kbsConfig.Password, client).Token()
 		if err != nil {
 			secLog.WithError(err).Error("router/handlers:permissionsHandlerUsingTLSMAuth() Could not fetch token for user " + kbsConfig.UserName)
 			return errors.New("Could not fetch token for user " + kbsConfig.UserName)
@@ -207,6 +205,8 @@ func permissionsHandlerUsingTLSMAuth(eh endpointHandler, aasAPIUrl string, kbsCo
 		}
 
 		secLog.Infof("router/handlers:permissionsHandlerUsingTLSMAuth() %s - %s", commLogMsg.AuthorizedAccess, request.RequestURI)
+		// the roles carry the tenant of the user
+		request = comctx.SetUserRoles(request, userRoles)
 		return eh(responseWriter, request)
 	}
 }
@@ -223,6 +223,8 @@ func ErrorHandler(eh endpointHandler) http.HandlerFunc {
 		}()
 		if err := eh(w, r); err != nil {
 			switch t := err.(type) {
+			case *commErr.ProblemError:
+				cmw.WriteProblem(w, r, commErr.NewProblemDetails(t.Type, t.StatusCode, t.Message))
 			case *commErr.HandledError:
 				http.Error(w, t.Message, t.StatusCode)
 			case *commErr.PrivilegeError:
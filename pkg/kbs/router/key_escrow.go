@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+)
+
+//setKeyEscrowRoutes registers routes to escrow the keys and recover them from the custodian shares
+func setKeyEscrowRoutes(router *mux.Router, stores *domain.Stores) *mux.Router {
+	defaultLog.Trace("router/key_escrow:setKeyEscrowRoutes() Entering")
+	defer defaultLog.Trace("router/key_escrow:setKeyEscrowRoutes() Leaving")
+
+	keyEscrowController := controllers.NewKeyEscrowController(stores.KeyEscrow)
+
+	router.Handle("/key-escrow",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyEscrowController.Initialize),
+			[]string{constants.KeyEscrowCreate}))).Methods("POST")
+
+	router.Handle("/key-escrow",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyEscrowController.Retrieve),
+			[]string{constants.KeyEscrowRetrieve}))).Methods("GET")
+
+	router.Handle("/key-escrow/shares",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyEscrowController.SubmitShare),
+			[]string{constants.KeyEscrowRecover}))).Methods("POST")
+
+	router.Handle("/key-escrow/shares",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyEscrowController.DiscardShares),
+			[]string{constants.KeyEscrowRecover}))).Methods("DELETE")
+
+	router.Handle("/key-escrow/recover",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyEscrowController.Recover),
+			[]string{constants.KeyEscrowRecover}))).Methods("POST")
+
+	return router
+}
@@ -8,24 +8,29 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
-	"github.com/intel-secl/intel-secl/v3/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
 )
 
 //setKeyTransferPolicyRoutes registers routes to perform KeyTransferPolicy CRUD operations
-func setKeyTransferPolicyRoutes(router *mux.Router) *mux.Router {
+func setKeyTransferPolicyRoutes(router *mux.Router, stores *domain.Stores) *mux.Router {
 	defaultLog.Trace("router/key_transfer_policy:setKeyTransferPolicyRoutes() Entering")
 	defer defaultLog.Trace("router/key_transfer_policy:setKeyTransferPolicyRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	transferPolicyController := controllers.NewKeyTransferPolicyController(policyStore, keyStore)
+	keyStore := stores.KeyStore
+	policyStore := stores.KeyTransferPolicyStore
+	quotaStore := stores.TenantQuotaStore
+	transferPolicyController := controllers.NewKeyTransferPolicyController(policyStore, keyStore, quotaStore)
 	keyTransferPolicyIdExpr := "/key-transfer-policies/" + validation.IdReg
 
 	router.Handle("/key-transfer-policies",
 		ErrorHandler(permissionsHandler(JsonResponseHandler(transferPolicyController.Create),
 			[]string{constants.KeyTransferPolicyCreate}))).Methods("POST")
 
+	router.Handle(keyTransferPolicyIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(transferPolicyController.Update),
+			[]string{constants.KeyTransferPolicyUpdate}))).Methods("PUT")
+
 	router.Handle(keyTransferPolicyIdExpr,
 		ErrorHandler(permissionsHandler(JsonResponseHandler(transferPolicyController.Retrieve),
 			[]string{constants.KeyTransferPolicyRetrieve}))).Methods("GET")
@@ -9,7 +9,6 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
-	"github.com/intel-secl/intel-secl/v3/pkg/kbs/directory"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
@@ -17,14 +16,15 @@ import (
 )
 
 //setKeyRoutes registers routes to perform Key CRUD operations
-func setKeyRoutes(router *mux.Router, endpointUrl string, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+func setKeyRoutes(router *mux.Router, endpointUrl string, stores *domain.Stores, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
 	defaultLog.Trace("router/keys:setKeyRoutes() Entering")
 	defer defaultLog.Trace("router/keys:setKeyRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	remoteManager := keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
-	keyController := controllers.NewKeyController(remoteManager, policyStore, config)
+	keyStore := stores.KeyStore
+	policyStore := stores.KeyTransferPolicyStore
+	quotaStore := stores.TenantQuotaStore
+	remoteManager := keymanager.NewRemoteManager(keyStore, stores.EphemeralKeyStore, keyManager, endpointUrl)
+	keyController := controllers.NewKeyController(remoteManager, policyStore, quotaStore, config)
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle("/keys",
@@ -43,38 +43,73 @@ func setKeyRoutes(router *mux.Router, endpointUrl string, config domain.KeyContr
 		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Search),
 			[]string{constants.KeySearch}))).Methods("GET")
 
-	router.Handle(keyIdExpr+"/transfer",
-		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Transfer),
-			[]string{constants.KeyTransfer}))).Methods("POST")
+	router.Handle(keyIdExpr+"/image-flavor-binding",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.BindImageFlavor),
+			[]string{constants.KeyImageFlavorBind}))).Methods("PUT")
+
+	router.Handle(keyIdExpr+"/image-flavor-binding",
+		ErrorHandler(permissionsHandler(ResponseHandler(keyController.UnbindImageFlavor),
+			[]string{constants.KeyImageFlavorBind}))).Methods("DELETE")
+
+	router.Handle(keyIdExpr+"/attributes",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.UpdateAttributes),
+			[]string{constants.KeyAttributesUpdate}))).Methods("PUT")
 
 	return router
 }
 
 //setKeyTransferRoutes registers routes to perform Key Transfer operations
-func setKeyTransferRoutes(router *mux.Router, endpointUrl string, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+func setKeyTransferRoutes(router *mux.Router, endpointUrl string, stores *domain.Stores, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
 	defaultLog.Trace("router/keys:setKeyTransferRoutes() Entering")
 	defer defaultLog.Trace("router/keys:setKeyTransferRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	remoteManager := keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
-	keyController := controllers.NewKeyController(remoteManager, policyStore, config)
+	keyStore := stores.KeyStore
+	policyStore := stores.KeyTransferPolicyStore
+	quotaStore := stores.TenantQuotaStore
+	remoteManager := keymanager.NewRemoteManager(keyStore, stores.EphemeralKeyStore, keyManager, endpointUrl)
+	keyController := controllers.NewKeyController(remoteManager, policyStore, quotaStore, config)
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle(keyIdExpr+"/transfer",
 		ErrorHandler(ResponseHandler(keyController.TransferWithSaml))).Methods("POST").Headers("Accept", consts.HTTPMediaTypeOctetStream)
+	router.Handle(keyIdExpr+"/tpm2-transfer",
+		ErrorHandler(JsonResponseHandler(keyController.TransferWithTpm2))).Methods("POST")
+
+	return router
+}
+
+//setEnvelopeKeyTransferRoutes registers routes to transfer keys wrapped by an envelope key, they are authenticated
+//by a bearer token, a client certificate or both
+func setEnvelopeKeyTransferRoutes(router *mux.Router, endpointUrl string, stores *domain.Stores, config domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+	defaultLog.Trace("router/keys:setEnvelopeKeyTransferRoutes() Entering")
+	defer defaultLog.Trace("router/keys:setEnvelopeKeyTransferRoutes() Leaving")
+
+	keyStore := stores.KeyStore
+	policyStore := stores.KeyTransferPolicyStore
+	quotaStore := stores.TenantQuotaStore
+	remoteManager := keymanager.NewRemoteManager(keyStore, stores.EphemeralKeyStore, keyManager, endpointUrl)
+	keyController := controllers.NewKeyController(remoteManager, policyStore, quotaStore, config)
+	keyIdExpr := "/keys/" + validation.IdReg
+
+	router.Handle(keyIdExpr+"/transfer",
+		ErrorHandler(permissionsHandler(ResponseHandler(keyController.TransferAsJwe),
+			[]string{constants.KeyTransfer}))).Methods("POST").Headers("Accept", consts.HTTPMediaTypeJose)
+
+	router.Handle(keyIdExpr+"/transfer",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Transfer),
+			[]string{constants.KeyTransfer}))).Methods("POST")
 
 	return router
 }
 
 //setSKCKeyTransferRoutes registers routes to perform SKC Transfer operations
-func setSKCKeyTransferRoutes(router *mux.Router, kbsConfig *config.Configuration, keyManager keymanager.KeyManager) *mux.Router {
+func setSKCKeyTransferRoutes(router *mux.Router, kbsConfig *config.Configuration, stores *domain.Stores, keyManager keymanager.KeyManager) *mux.Router {
 	defaultLog.Trace("router/keys:setSKCKeyTransferRoutes() Entering")
 	defer defaultLog.Trace("router/keys:setSKCKeyTransferRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	remoteManager := keymanager.NewRemoteManager(keyStore, keyManager, kbsConfig.EndpointURL)
+	keyStore := stores.KeyStore
+	policyStore := stores.KeyTransferPolicyStore
+	remoteManager := keymanager.NewRemoteManager(keyStore, stores.EphemeralKeyStore, keyManager, kbsConfig.EndpointURL)
 	skcController := controllers.NewSKCController(remoteManager, policyStore, kbsConfig, constants.TrustedCaCertsDir)
 	keyIdExpr := "/keys/" + validation.IdReg
 
@@ -5,7 +5,6 @@
 package router
 
 import (
-	"crypto/tls"
 	"crypto/x509"
 	"io/ioutil"
 	"net/http"
@@ -18,9 +17,12 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v3/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v3/pkg/lib/common/os"
+	commTls "github.com/intel-secl/intel-secl/v3/pkg/lib/common/tls"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
 	"github.com/pkg/errors"
 )
 
@@ -32,7 +34,7 @@ type Router struct {
 }
 
 // InitRoutes registers all routes for the application.
-func InitRoutes(cfg *config.Configuration, keyConfig domain.KeyControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+func InitRoutes(cfg *config.Configuration, stores *domain.Stores, keyConfig domain.KeyControllerConfig, keyManager keymanager.KeyManager, certReloader *commTls.CertReloader) (*mux.Router, error) {
 	defaultLog.Trace("router/router:InitRoutes() Entering")
 	defer defaultLog.Trace("router/router:InitRoutes() Leaving")
 
@@ -42,35 +44,81 @@ func InitRoutes(cfg *config.Configuration, keyConfig domain.KeyControllerConfig,
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
 
+	// Render the errors of all the routes as problem details with the correlation ID of the request
+	router.Use(cmw.NewCorrelationID(), cmw.NewProblemDetails())
+
+	// Reject oversized, compressed and slowly sent request bodies before they reach any handler and bound the
+	// size of the responses
+	router.Use(cmw.NewRequestLimits(cfg.Server))
+
 	// Define sub routes for path /kbs/v1
-	defineSubRoutes(router, "/"+strings.ToLower(constants.ServiceName)+constants.ApiVersion, cfg, keyConfig, keyManager)
+	err := defineSubRoutes(router, "/"+strings.ToLower(constants.ServiceName)+constants.ApiVersion, cfg, stores, keyConfig, keyManager, certReloader)
+	if err != nil {
+		return nil, err
+	}
 
 	// Define sub routes for path /v1
-	defineSubRoutes(router, constants.ApiVersion, cfg, keyConfig, keyManager)
+	err = defineSubRoutes(router, constants.ApiVersion, cfg, stores, keyConfig, keyManager, certReloader)
+	if err != nil {
+		return nil, err
+	}
 
-	return router
+	return router, nil
 }
 
-func defineSubRoutes(router *mux.Router, serviceApi string, cfg *config.Configuration, keyConfig domain.KeyControllerConfig, keyManager keymanager.KeyManager) {
+func defineSubRoutes(router *mux.Router, serviceApi string, cfg *config.Configuration, stores *domain.Stores, keyConfig domain.KeyControllerConfig, keyManager keymanager.KeyManager, certReloader *commTls.CertReloader) error {
 	defaultLog.Trace("router/router:defineSubRoutes() Entering")
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
 	subRouter := router.PathPrefix(serviceApi).Subrouter()
 	subRouter = setVersionRoutes(subRouter)
-	subRouter = setKeyTransferRoutes(subRouter, cfg.EndpointURL, keyConfig, keyManager)
-	subRouter = setSKCKeyTransferRoutes(subRouter, cfg, keyManager)
+	subRouter = setKeyTransferRoutes(subRouter, cfg.EndpointURL, stores, keyConfig, keyManager)
+	subRouter = setSKCKeyTransferRoutes(subRouter, cfg, stores, keyManager)
 	subRouter = setSessionRoutes(subRouter, cfg)
-	subRouter = router.PathPrefix(serviceApi).Subrouter()
+
 	cfgRouter := Router{cfg: cfg}
+	var err error
 	var cacheTime, _ = time.ParseDuration(constants.JWTCertsCacheTime)
-
-	subRouter.Use(cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
+	tokenAuth := cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
 		constants.TrustedCaCertsDir, cfgRouter.fnGetJwtCerts,
-		cacheTime))
-	subRouter = setKeyRoutes(subRouter, cfg.EndpointURL, keyConfig, keyManager)
-	subRouter = setKeyTransferPolicyRoutes(subRouter)
-	subRouter = setSamlCertRoutes(subRouter)
-	subRouter = setTpmIdentityCertRoutes(subRouter)
+		cacheTime)
+	if cfg.OIDC.Issuer != "" {
+		tokenAuth, err = newOIDCTokenAuth(cfg, tokenAuth)
+		if err != nil {
+			return errors.Wrap(err, "router/router:defineSubRoutes() Invalid OIDC authentication")
+		}
+	}
+
+	// the clients authenticated by a certificate only are granted the key transfer permission
+	subRouter = router.PathPrefix(serviceApi).Subrouter()
+	err = cmw.UseRouteGroupAuth(subRouter, cmw.RouteGroupAuth{
+		Mode:               cmw.AuthMode(cfg.KeyTransferAuth.Mode),
+		TrustedCAsDir:      constants.TrustedCaCertsDir,
+		AllowedCommonNames: cfg.KeyTransferAuth.AllowedCommonNames,
+		Permissions: []aas.PermissionInfo{{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.KeyTransfer},
+		}},
+	}, tokenAuth)
+	if err != nil {
+		return errors.Wrap(err, "router/router:defineSubRoutes() Invalid key transfer authentication")
+	}
+	subRouter = setEnvelopeKeyTransferRoutes(subRouter, cfg.EndpointURL, stores, keyConfig, keyManager)
+
+	subRouter = router.PathPrefix(serviceApi).Subrouter()
+	subRouter.Use(tokenAuth)
+	if cfg.DualControl {
+		subRouter = setDualControlRoutes(subRouter, cfg.EndpointURL, stores, keyConfig, keyManager)
+	}
+	subRouter = setKeyRoutes(subRouter, cfg.EndpointURL, stores, keyConfig, keyManager)
+	subRouter = setKeyTransferPolicyRoutes(subRouter, stores)
+	subRouter = setTenantQuotaRoutes(subRouter, stores)
+	subRouter = setApprovalRoutes(subRouter, cfg.EndpointURL, stores, keyConfig, keyManager)
+	subRouter = setKeyEscrowRoutes(subRouter, stores)
+	subRouter = setSamlCertRoutes(subRouter, stores)
+	subRouter = setTpmIdentityCertRoutes(subRouter, stores)
+	subRouter = setTLSCertificateRoutes(subRouter, certReloader)
+	return nil
 }
 
 // Fetch JWT certificate from AAS
@@ -103,13 +151,11 @@ func (router *Router) fnGetJwtCerts() error {
 			return err
 		}
 	}
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.RootCAs = rootCAs
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: false,
-				RootCAs:            rootCAs,
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 
@@ -128,3 +174,40 @@ func (router *Router) fnGetJwtCerts() error {
 	}
 	return nil
 }
+
+// newOIDCTokenAuth returns the token middleware accepting the access tokens of the OIDC provider in addition to
+// the AAS tokens authorized by tokenAuth. The provider is reached with the system CAs and the trusted CAs of KBS.
+func newOIDCTokenAuth(cfg *config.Configuration, tokenAuth mux.MiddlewareFunc) (mux.MiddlewareFunc, error) {
+	defaultLog.Trace("router/router:newOIDCTokenAuth() Entering")
+	defer defaultLog.Trace("router/router:newOIDCTokenAuth() Leaving")
+
+	rootCaCertPems, err := cos.GetDirFileContents(constants.TrustedCaCertsDir, "*.pem")
+	if err != nil {
+		return nil, errors.Wrap(err, "router/router:newOIDCTokenAuth() Unable to read root CA certificate")
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if rootCAs == nil || err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	for _, rootCACert := range rootCaCertPems {
+		rootCAs.AppendCertsFromPEM(rootCACert)
+	}
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.RootCAs = rootCAs
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+		Timeout: constants.DefaultOIDCRequestTimeout,
+	}
+
+	verifier, err := jwtauth.NewOIDCVerifier(cfg.OIDC.Issuer, cfg.OIDC.Audience, httpClient, cfg.OIDC.JWKSCacheTime)
+	if err != nil {
+		return nil, err
+	}
+	return cmw.NewOIDCTokenAuth(cmw.OIDCAuth{
+		Verifier:         verifier,
+		Service:          constants.ServiceName,
+		ScopePermissions: cfg.OIDC.ScopePermissions,
+	}, tokenAuth), nil
+}
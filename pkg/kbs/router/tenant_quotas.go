@@ -0,0 +1,40 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+)
+
+//setTenantQuotaRoutes registers routes to manage the quotas of the tenants
+func setTenantQuotaRoutes(router *mux.Router, stores *domain.Stores) *mux.Router {
+	defaultLog.Trace("router/tenant_quotas:setTenantQuotaRoutes() Entering")
+	defer defaultLog.Trace("router/tenant_quotas:setTenantQuotaRoutes() Leaving")
+
+	quotaStore := stores.TenantQuotaStore
+	tenantQuotaController := controllers.NewTenantQuotaController(quotaStore)
+	tenantIdExpr := "/tenant-quotas/{tenantId:" + constants.TenantIdPattern + "}"
+
+	router.Handle(tenantIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tenantQuotaController.Update),
+			[]string{constants.TenantQuotaUpdate}))).Methods("PUT")
+
+	router.Handle(tenantIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tenantQuotaController.Retrieve),
+			[]string{constants.TenantQuotaRetrieve}))).Methods("GET")
+
+	router.Handle(tenantIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tenantQuotaController.Delete),
+			[]string{constants.TenantQuotaDelete}))).Methods("DELETE")
+
+	router.Handle("/tenant-quotas",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tenantQuotaController.Search),
+			[]string{constants.TenantQuotaSearch}))).Methods("GET")
+
+	return router
+}
@@ -0,0 +1,30 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	commTls "github.com/intel-secl/intel-secl/v3/pkg/lib/common/tls"
+)
+
+//setTLSCertificateRoutes registers routes to inspect and rotate the TLS certificate served by KBS
+func setTLSCertificateRoutes(router *mux.Router, reloader *commTls.CertReloader) *mux.Router {
+	defaultLog.Trace("router/tls_certificate:setTLSCertificateRoutes() Entering")
+	defer defaultLog.Trace("router/tls_certificate:setTLSCertificateRoutes() Leaving")
+
+	tlsCertController := controllers.NewTLSCertificateController(reloader)
+
+	router.Handle("/tls-certificate",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tlsCertController.Retrieve),
+			[]string{constants.TlsCertificateRetrieve}))).Methods("GET")
+
+	router.Handle("/tls-certificate/reload",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(tlsCertController.Reload),
+			[]string{constants.TlsCertificateReload}))).Methods("POST")
+
+	return router
+}
@@ -13,23 +13,30 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/directory"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/escrow"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/memory"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/router"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/sqlite"
 	"github.com/intel-secl/intel-secl/v3/pkg/kbs/utils"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	cmw "github.com/intel-secl/intel-secl/v3/pkg/lib/common/middleware"
+	commTls "github.com/intel-secl/intel-secl/v3/pkg/lib/common/tls"
 	"github.com/pkg/errors"
 )
 
 var defaultLog = commLog.GetDefaultLogger()
 var secLog = commLog.GetSecurityLogger()
 
-func (app *App) startServer() error {
+func (app *App) startServer(standalone bool) error {
 	defaultLog.Trace("kbs/server:startServer() Entering")
 	defer defaultLog.Trace("kbs/server:startServer() Leaving")
 
@@ -41,6 +48,18 @@ func (app *App) startServer() error {
 	if err := app.configureLogs(configuration.Log.EnableStdout, true); err != nil {
 		return err
 	}
+	crypt.SetFipsMode(configuration.FipsMode)
+	servicePassword, err := crypt.ResolveSecret(configuration.KBS.Password)
+	if err != nil {
+		return errors.Wrap(err, "kbs/server:startServer() Failed to unseal the KBS service password")
+	}
+	configuration.KBS.Password = servicePassword
+	defaultLog.Infof("kbs/server:startServer() FIPS mode enabled: %t", crypt.FipsModeEnabled())
+
+	// Verify the ciphers of the volume keys and nonce misuse-resistant payloads against their known answers
+	if err := crypt.CipherSelfTest(); err != nil {
+		return errors.Wrap(err, "kbs/server:startServer() Cipher self-test failed")
+	}
 
 	// Initialize KeyControllerConfig
 	kcc, err := initKeyControllerConfig()
@@ -49,23 +68,64 @@ func (app *App) startServer() error {
 	}
 
 	// Initialize KeyManager
-	km, err := keymanager.NewKeyManager(&configuration.Kmip, configuration.KeyManager)
+	km, err := keymanager.NewKeyManager(&configuration.Kmip, &configuration.CloudKms, configuration.KeyManager)
 	if err != nil {
 		return err
 	}
 
+	// Load the TLS key pair through a reloader so that a rotated certificate can be served without restart
+	certReloader, err := commTls.NewCertReloader(configuration.TLS.CertFile, configuration.TLS.KeyFile, configuration.TLSKeyOverlap)
+	if err != nil {
+		return errors.Wrap(err, "kbs/server:startServer() Failed to load TLS key pair")
+	}
+
+	// Initialize stores, the certificates stay in their directories as the key transfer verifies against them
+	stores := &domain.Stores{
+		KeyStore:               directory.NewKeyStore(constants.KeysDir),
+		EphemeralKeyStore:      memory.NewKeyStore(),
+		KeyTransferPolicyStore: directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir),
+		TenantQuotaStore:       directory.NewTenantQuotaStore(constants.TenantQuotasDir),
+		ApprovalStore:          directory.NewApprovalStore(constants.ApprovalsDir),
+		SamlCertStore:          directory.NewCertificateStore(constants.SamlCertsDir),
+		TpmIdentityCertStore:   directory.NewCertificateStore(constants.TpmIdentityCertsDir),
+	}
+	if standalone {
+		defaultLog.Infof("kbs/server:startServer() Running standalone with the database %s", constants.StandaloneDBFile)
+		dataStore, err := sqlite.NewDataStore(constants.StandaloneDBFile)
+		if err != nil {
+			return errors.Wrap(err, "kbs/server:startServer() Failed to initialize the standalone database")
+		}
+		defer func() {
+			if err := dataStore.Close(); err != nil {
+				defaultLog.WithError(err).Error("kbs/server:startServer() Failed to close the standalone database")
+			}
+		}()
+		stores.KeyStore = sqlite.NewKeyStore(dataStore)
+		stores.KeyTransferPolicyStore = sqlite.NewKeyTransferPolicyStore(dataStore)
+		stores.TenantQuotaStore = sqlite.NewTenantQuotaStore(dataStore)
+		stores.ApprovalStore = sqlite.NewApprovalStore(dataStore)
+	}
+	// the keys are escrowed through the key store of the escrow once it is initialized
+	keyEscrow := escrow.NewKeyEscrow(constants.KeyEscrowDir, stores.KeyStore)
+	stores.KeyStore = keyEscrow.KeyStore()
+	stores.KeyEscrow = keyEscrow
+
+	if configuration.DualControl {
+		defaultLog.Info("kbs/server:startServer() Dual control enabled, the deletion of the keys and the changes of the key transfer policies require approval")
+	}
+
 	// Initialize routes
-	routes := router.InitRoutes(configuration, kcc, km)
+	routes, err := router.InitRoutes(configuration, stores, kcc, km, certReloader)
+	if err != nil {
+		return errors.Wrap(err, "kbs/server:startServer() Failed to initialize routes")
+	}
 
 	defaultLog.Info("kbs/server:startServer() Starting server")
-	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
-		ClientAuth: tls.RequestClientCert,
-	}
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.ClientAuth = tls.RequestClientCert
+	tlsConfig.GetCertificate = certReloader.GetCertificate
+	// client certificates carrying an SGX/TDX quote are checked during the handshake, the quote is verified by the key transfer
+	tlsConfig.VerifyPeerCertificate = controllers.NewSessionController(configuration, constants.TrustedCaCertsDir).VerifyPeerCertificate
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -80,13 +140,22 @@ func (app *App) startServer() error {
 		WriteTimeout:      configuration.Server.WriteTimeout,
 		IdleTimeout:       configuration.Server.IdleTimeout,
 		MaxHeaderBytes:    configuration.Server.MaxHeaderBytes,
+		ConnContext:       cmw.ConnContext,
+	}
+
+	// Watch the TLS certificate and key files for rotation
+	stopWatcher := make(chan struct{})
+	defer close(stopWatcher)
+	if configuration.TLSReloadInterval > 0 {
+		go certReloader.Watch(configuration.TLSReloadInterval, stopWatcher, func(err error) {
+			defaultLog.WithError(err).Error("kbs/server:startServer() Failed to reload TLS certificate, previous certificate remains in use")
+		})
 	}
 
-	tlsCert := configuration.TLS.CertFile
-	tlsKey := configuration.TLS.KeyFile
 	// Dispatch web server go routine
 	go func() {
-		if err := httpServer.ListenAndServeTLS(tlsCert, tlsKey); err != nil {
+		// certificate and key are provided by the reloader through tlsConfig.GetCertificate
+		if err := httpServer.ListenAndServeTLS("", ""); err != nil {
 			defaultLog.WithError(err).Error("kbs/server:startServer() Failed to start HTTPS server")
 			stop <- syscall.SIGTERM
 		}
@@ -95,7 +164,12 @@ func (app *App) startServer() error {
 	secLog.Info(commLogMsg.ServiceStart)
 	<-stop
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// the requests in progress are drained within the drain timeout
+	drainTimeout := configuration.Server.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = constants.DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 
 	if err := httpServer.Shutdown(ctx); err != nil {
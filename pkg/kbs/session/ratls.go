@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package session
+
+import (
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/pkg/errors"
+)
+
+var (
+	// RA-TLS certificate extensions carrying an SGX ECDSA quote and a TDX quote
+	oidSgxQuote = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1}
+	oidTdxQuote = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 5, 5, 1, 6}
+)
+
+// GetQuoteFromCertificate returns the quote embedded in an RA-TLS certificate along with the
+// stm label of the quote. The last return value is false if the certificate carries no quote.
+func GetQuoteFromCertificate(cert *x509.Certificate) ([]byte, string, bool) {
+	defaultLog.Trace("session/ratls:GetQuoteFromCertificate() Entering")
+	defer defaultLog.Trace("session/ratls:GetQuoteFromCertificate() Leaving")
+
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidSgxQuote):
+			return ext.Value, constants.DefaultSGXLabel, true
+		case ext.Id.Equal(oidTdxQuote):
+			return ext.Value, constants.DefaultTDXLabel, true
+		}
+	}
+	return nil, "", false
+}
+
+// GetRATLSUserData returns the base64 encoded user data bound to the report data of an RA-TLS
+// quote, which is the DER encoded public key of the certificate carrying the quote
+func GetRATLSUserData(cert *x509.Certificate) string {
+	return base64.StdEncoding.EncodeToString(cert.RawSubjectPublicKeyInfo)
+}
+
+// GetCertificatePublicKeyPem returns the PEM encoded public key of the certificate
+func GetCertificatePublicKeyPem(cert *x509.Certificate) ([]byte, error) {
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "session/ratls:GetCertificatePublicKeyPem() Failed to marshal certificate public key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKeyBytes}), nil
+}
+
+// GetCertificateHash returns the hex encoded SHA384 digest of the certificate, used to bind
+// sessions to the client certificate they were established with
+func GetCertificateHash(cert *x509.Certificate) string {
+	hash := sha512.Sum384(cert.Raw)
+	return hex.EncodeToString(hash[:])
+}
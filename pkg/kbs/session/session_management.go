@@ -20,20 +20,19 @@ type QuoteData struct {
 	UserData  string `json:"userData"`
 }
 
-// SessionCreateSwk - Function to create swk
-func SessionCreateSwk() ([]byte, error) {
+// SessionCreateSwk - Function to create swk, the swk is kept in a secret buffer for the lifetime of the session
+func SessionCreateSwk() (*crypt.SecretBuffer, error) {
 
 	defaultLog.Trace("session/session_management:SessionCreateSwk() Entering")
 	defer defaultLog.Trace("session/session_management:SessionCreateSwk() Leaving")
 
-	//create an AES Key here of 256 bytes
-	keyBytes := make([]byte, 32)
-	_, err := rand.Read(keyBytes)
+	//create an AES Key here of 256 bits
+	swk, err := crypt.NewRandomSecretBuffer(32)
 	if err != nil {
 		return nil, errors.Wrap(err, "session/session_management:SessionCreateSwk() Failed to read the key bytes")
 	}
 
-	return keyBytes, nil
+	return swk, nil
 }
 
 // SessionWrapSwkWithRSAKey - Function to wrap the swk key with rsa key
@@ -27,7 +27,7 @@ func (app *App) setup(args []string) error {
 	}
 	// look for cli flags
 	var ansFile string
-	var force bool
+	var force, interactive, fixDrift bool
 	for i, arg := range args {
 		if arg == "-f" || arg == "--file" {
 			if i+1 < len(args) {
@@ -39,6 +39,17 @@ func (app *App) setup(args []string) error {
 		if arg == "--force" {
 			force = true
 		}
+		if arg == setup.InteractiveFlag {
+			interactive = true
+		}
+		if arg == setup.FixDriftFlag {
+			fixDrift = true
+		}
+	}
+	cmd := args[1]
+	// "kbs setup --interactive" and "kbs setup --fix-drift" run all the setup tasks
+	if cmd == setup.InteractiveFlag || cmd == setup.FixDriftFlag {
+		cmd = "all"
 	}
 	// dump answer file to env
 	if ansFile != "" {
@@ -47,11 +58,17 @@ func (app *App) setup(args []string) error {
 			return errors.Wrap(err, "Failed to read answer file")
 		}
 	}
+	// prompt for the settings not in the answer file
+	if interactive && !(len(args) > 2 && args[2] == "--help") {
+		err := setup.NewConsoleInteractive(app.consoleWriter()).ReadAnswersToEnv(cmd, setupPrompts())
+		if err != nil {
+			return errors.Wrap(err, "Failed to read setup settings")
+		}
+	}
 	runner, err := app.setupTaskRunner()
 	if err != nil {
 		return errors.Wrap(err, "Failed to add setup task runner")
 	}
-	cmd := args[1]
 	// print help and return if applicable
 	if len(args) > 2 && args[2] == "--help" {
 		if cmd == "all" {
@@ -67,7 +84,15 @@ func (app *App) setup(args []string) error {
 		}
 		return nil
 	}
-	if cmd == "all" {
+	if fixDrift {
+		if err = runner.FixDrift(cmd); err != nil {
+			fmt.Fprintln(app.errorWriter(), "Error(s) encountered when fixing setup drift:")
+			for errCmd, failErr := range runner.FailedCommands() {
+				fmt.Fprintln(app.errorWriter(), errCmd+": "+failErr.Error())
+			}
+			return err
+		}
+	} else if cmd == "all" {
 		if err = runner.RunAll(force); err != nil {
 			errCmds := runner.FailedCommands()
 			fmt.Fprintln(app.errorWriter(), "Error(s) encountered when running all setup commands:")
@@ -148,13 +173,21 @@ func (app *App) setupTaskRunner() (*setup.Runner, error) {
 			Password: viper.GetString("kbs-service-password"),
 		},
 		AASApiUrl: viper.GetString("aas-base-url"),
+		SecretSealing: setup.SecretSealing{
+			Enabled: viper.GetBool("tpm-seal-secrets"),
+			PCRs:    viper.GetString("tpm-seal-pcrs"),
+		},
 		ServerConfig: commConfig.ServerConfig{
 			Port:              viper.GetInt("server-port"),
 			ReadTimeout:       viper.GetDuration("server-read-timeout"),
 			ReadHeaderTimeout: viper.GetDuration("server-read-header-timeout"),
 			WriteTimeout:      viper.GetDuration("server-write-timeout"),
 			IdleTimeout:       viper.GetDuration("server-idle-timeout"),
+			DrainTimeout:      viper.GetDuration("server-drain-timeout"),
+			BodyReadTimeout:   viper.GetDuration("server-body-read-timeout"),
+			MaxResponseBytes:  viper.GetInt64("server-max-response-bytes"),
 			MaxHeaderBytes:    viper.GetInt("server-max-header-bytes"),
+			MaxBodyBytes:      viper.GetInt64("server-max-body-bytes"),
 		},
 		DefaultPort: constants.DefaultKBSListenerPort,
 		AppConfig:   &app.Config,
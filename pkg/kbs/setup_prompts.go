@@ -0,0 +1,31 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/spf13/viper"
+)
+
+// setupPrompts lists the settings prompted for by the interactive setup, in the order of the setup tasks
+func setupPrompts() []setup.Prompt {
+	return []setup.Prompt{
+		{EnvName: "CMS_BASE_URL", Description: "CMS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"download-ca-cert", "download-cert-tls"}},
+		{EnvName: "CMS_TLS_CERT_SHA384", Description: "SHA384 digest of the CMS TLS certificate",
+			Validate: setup.ValidateCertDigest, Tasks: []string{"download-ca-cert"}},
+		{EnvName: "BEARER_TOKEN", Description: "Installation bearer token", Secret: true,
+			Validate: validation.ValidateJWT, Tasks: []string{"download-cert-tls"}},
+		{EnvName: "SAN_LIST", Description: "Subject alternative names of the TLS certificate",
+			Default: viper.GetString("tls-san-list"), Tasks: []string{"download-cert-tls"}},
+		{EnvName: "AAS_API_URL", Description: "AAS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"update-service-config"}},
+		{EnvName: "KBS_SERVICE_USERNAME", Description: "KBS service username",
+			Validate: validation.ValidateUserNameString, Tasks: []string{"update-service-config"}},
+		{EnvName: "KBS_SERVICE_PASSWORD", Description: "KBS service password", Secret: true,
+			Tasks: []string{"update-service-config"}},
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package sqlite
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type ApprovalStore struct {
+	Store *DataStore
+}
+
+func NewApprovalStore(store *DataStore) *ApprovalStore {
+	return &ApprovalStore{store}
+}
+
+func (as *ApprovalStore) Create(approvalRequest *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error) {
+	defaultLog.Trace("sqlite/approval_store:Create() Entering")
+	defer defaultLog.Trace("sqlite/approval_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/approval_store:Create() failed to create new UUID")
+	}
+	approvalRequest.ID = newUuid
+	approvalRequest.RequestedAt = time.Now().UTC()
+	bytes, err := json.Marshal(approvalRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/approval_store:Create() Failed to marshal approval request")
+	}
+
+	dbApproval := approval{
+		ID:          approvalRequest.ID.String(),
+		TenantID:    approvalRequest.TenantID,
+		Status:      string(approvalRequest.Status),
+		RequestedAt: approvalRequest.RequestedAt,
+		Content:     string(bytes),
+	}
+	if err = as.Store.Db.Create(&dbApproval).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/approval_store:Create() Error in saving approval request")
+	}
+
+	return approvalRequest, nil
+}
+
+func (as *ApprovalStore) Update(approvalRequest *kbs.ApprovalRequest) (*kbs.ApprovalRequest, error) {
+	defaultLog.Trace("sqlite/approval_store:Update() Entering")
+	defer defaultLog.Trace("sqlite/approval_store:Update() Leaving")
+
+	bytes, err := json.Marshal(approvalRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/approval_store:Update() Failed to marshal approval request")
+	}
+
+	result := as.Store.Db.Model(&approval{}).Where("id = ?", approvalRequest.ID.String()).
+		Updates(map[string]interface{}{"status": string(approvalRequest.Status), "content": string(bytes)})
+	if result.Error != nil {
+		return nil, errors.Wrapf(result.Error, "sqlite/approval_store:Update() Error in saving approval request : %s", approvalRequest.ID.String())
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+
+	return approvalRequest, nil
+}
+
+func (as *ApprovalStore) Retrieve(id uuid.UUID) (*kbs.ApprovalRequest, error) {
+	defaultLog.Trace("sqlite/approval_store:Retrieve() Entering")
+	defer defaultLog.Trace("sqlite/approval_store:Retrieve() Leaving")
+
+	var dbApproval approval
+	if err := as.Store.Db.Where("id = ?", id.String()).First(&dbApproval).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "sqlite/approval_store:Retrieve() Unable to retrieve approval request : %s", id.String())
+	}
+
+	return dbApproval.toApprovalRequest()
+}
+
+// Search returns the approval requests matching the criteria, the oldest first
+func (as *ApprovalStore) Search(criteria *models.ApprovalFilterCriteria) ([]kbs.ApprovalRequest, error) {
+	defaultLog.Trace("sqlite/approval_store:Search() Entering")
+	defer defaultLog.Trace("sqlite/approval_store:Search() Leaving")
+
+	tx := as.Store.Db.Model(&approval{})
+	if criteria != nil && criteria.TenantID != nil {
+		tx = tx.Where("tenant_id = ?", *criteria.TenantID)
+	}
+	if criteria != nil && criteria.Status != "" {
+		tx = tx.Where("status = ?", string(criteria.Status))
+	}
+
+	var dbApprovals []approval
+	if err := tx.Order("requested_at").Find(&dbApprovals).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/approval_store:Search() Error in searching the approval requests")
+	}
+
+	var approvals = []kbs.ApprovalRequest{}
+	for _, dbApproval := range dbApprovals {
+		approvalRequest, err := dbApproval.toApprovalRequest()
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, *approvalRequest)
+	}
+
+	return approvals, nil
+}
+
+func (a *approval) toApprovalRequest() (*kbs.ApprovalRequest, error) {
+	var approvalRequest kbs.ApprovalRequest
+	if err := json.Unmarshal([]byte(a.Content), &approvalRequest); err != nil {
+		return nil, errors.Wrapf(err, "sqlite/approval_store:toApprovalRequest() Failed to unmarshal approval request : %s", a.ID)
+	}
+	return &approvalRequest, nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package sqlite
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type KeyStore struct {
+	Store *DataStore
+}
+
+func NewKeyStore(store *DataStore) *KeyStore {
+	return &KeyStore{store}
+}
+
+func (ks *KeyStore) Create(keyAttributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("sqlite/key_store:Create() Entering")
+	defer defaultLog.Trace("sqlite/key_store:Create() Leaving")
+
+	bytes, err := json.Marshal(keyAttributes)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_store:Create() Failed to marshal key attributes")
+	}
+
+	dbKey := key{
+		ID:               keyAttributes.ID.String(),
+		Algorithm:        keyAttributes.Algorithm,
+		KeyLength:        keyAttributes.KeyLength,
+		CurveType:        keyAttributes.CurveType,
+		TransferPolicyID: keyAttributes.TransferPolicyId.String(),
+		TenantID:         keyAttributes.TenantID,
+		Content:          string(bytes),
+	}
+	if err = ks.Store.Db.Save(&dbKey).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_store:Create() Failed to store key attributes")
+	}
+
+	return keyAttributes, nil
+}
+
+func (ks *KeyStore) Retrieve(id uuid.UUID) (*models.KeyAttributes, error) {
+	defaultLog.Trace("sqlite/key_store:Retrieve() Entering")
+	defer defaultLog.Trace("sqlite/key_store:Retrieve() Leaving")
+
+	var dbKey key
+	if err := ks.Store.Db.Where("id = ?", id.String()).First(&dbKey).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "sqlite/key_store:Retrieve() Unable to retrieve key : %s", id.String())
+	}
+
+	return dbKey.toKeyAttributes()
+}
+
+func (ks *KeyStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("sqlite/key_store:Delete() Entering")
+	defer defaultLog.Trace("sqlite/key_store:Delete() Leaving")
+
+	result := ks.Store.Db.Where("id = ?", id.String()).Delete(&key{})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "sqlite/key_store:Delete() Unable to remove key : %s", id.String())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(commErr.RecordNotFound)
+	}
+
+	return nil
+}
+
+func (ks *KeyStore) Search(criteria *models.KeyFilterCriteria) ([]models.KeyAttributes, error) {
+	defaultLog.Trace("sqlite/key_store:Search() Entering")
+	defer defaultLog.Trace("sqlite/key_store:Search() Leaving")
+
+	tx := ks.Store.Db.Model(&key{})
+	if criteria != nil {
+		if criteria.Algorithm != "" {
+			tx = tx.Where("algorithm = ?", criteria.Algorithm)
+		}
+		if criteria.KeyLength != 0 {
+			tx = tx.Where("key_length = ?", criteria.KeyLength)
+		}
+		if criteria.CurveType != "" {
+			tx = tx.Where("curve_type = ?", criteria.CurveType)
+		}
+		if criteria.TransferPolicyId != uuid.Nil {
+			tx = tx.Where("transfer_policy_id = ?", criteria.TransferPolicyId.String())
+		}
+		if criteria.TenantID != nil {
+			tx = tx.Where("tenant_id = ?", *criteria.TenantID)
+		}
+	}
+
+	var dbKeys []key
+	if err := tx.Find(&dbKeys).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_store:Search() Error in searching the keys")
+	}
+
+	// the attributes and the creation date are only in the content
+	var keys = []models.KeyAttributes{}
+	for _, dbKey := range dbKeys {
+		keyAttributes, err := dbKey.toKeyAttributes()
+		if err != nil {
+			return nil, err
+		}
+		if criteria != nil && (!criteria.MatchesAttributes(keyAttributes) || !criteria.MatchesCreatedAt(keyAttributes)) {
+			continue
+		}
+		keys = append(keys, *keyAttributes)
+	}
+
+	return keys, nil
+}
+
+func (k *key) toKeyAttributes() (*models.KeyAttributes, error) {
+	var keyAttributes models.KeyAttributes
+	if err := json.Unmarshal([]byte(k.Content), &keyAttributes); err != nil {
+		return nil, errors.Wrapf(err, "sqlite/key_store:toKeyAttributes() Failed to unmarshal key attributes : %s", k.ID)
+	}
+	return &keyAttributes, nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package sqlite
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type KeyTransferPolicyStore struct {
+	Store *DataStore
+}
+
+func NewKeyTransferPolicyStore(store *DataStore) *KeyTransferPolicyStore {
+	return &KeyTransferPolicyStore{store}
+}
+
+func (ktps *KeyTransferPolicyStore) Create(policy *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, error) {
+	defaultLog.Trace("sqlite/key_transfer_policy_store:Create() Entering")
+	defer defaultLog.Trace("sqlite/key_transfer_policy_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_transfer_policy_store:Create() failed to create new UUID")
+	}
+	policy.ID = newUuid
+	policy.CreatedAt = time.Now().UTC()
+	bytes, err := json.Marshal(policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_transfer_policy_store:Create() Failed to marshal key transfer policy")
+	}
+
+	dbPolicy := keyTransferPolicy{
+		ID:       policy.ID.String(),
+		TenantID: policy.TenantID,
+		Content:  string(bytes),
+	}
+	if err = ktps.Store.Db.Create(&dbPolicy).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_transfer_policy_store:Create() Error in saving key transfer policy")
+	}
+
+	return policy, nil
+}
+
+func (ktps *KeyTransferPolicyStore) Update(policy *kbs.KeyTransferPolicyAttributes) (*kbs.KeyTransferPolicyAttributes, error) {
+	defaultLog.Trace("sqlite/key_transfer_policy_store:Update() Entering")
+	defer defaultLog.Trace("sqlite/key_transfer_policy_store:Update() Leaving")
+
+	bytes, err := json.Marshal(policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_transfer_policy_store:Update() Failed to marshal key transfer policy")
+	}
+
+	result := ktps.Store.Db.Model(&keyTransferPolicy{}).Where("id = ?", policy.ID.String()).
+		Updates(map[string]interface{}{"tenant_id": policy.TenantID, "content": string(bytes)})
+	if result.Error != nil {
+		return nil, errors.Wrapf(result.Error, "sqlite/key_transfer_policy_store:Update() Error in saving key transfer policy : %s", policy.ID.String())
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+
+	return policy, nil
+}
+
+func (ktps *KeyTransferPolicyStore) Retrieve(id uuid.UUID) (*kbs.KeyTransferPolicyAttributes, error) {
+	defaultLog.Trace("sqlite/key_transfer_policy_store:Retrieve() Entering")
+	defer defaultLog.Trace("sqlite/key_transfer_policy_store:Retrieve() Leaving")
+
+	var dbPolicy keyTransferPolicy
+	if err := ktps.Store.Db.Where("id = ?", id.String()).First(&dbPolicy).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "sqlite/key_transfer_policy_store:Retrieve() Unable to retrieve key transfer policy : %s", id.String())
+	}
+
+	return dbPolicy.toKeyTransferPolicy()
+}
+
+func (ktps *KeyTransferPolicyStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("sqlite/key_transfer_policy_store:Delete() Entering")
+	defer defaultLog.Trace("sqlite/key_transfer_policy_store:Delete() Leaving")
+
+	result := ktps.Store.Db.Where("id = ?", id.String()).Delete(&keyTransferPolicy{})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "sqlite/key_transfer_policy_store:Delete() Unable to remove key transfer policy : %s", id.String())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(commErr.RecordNotFound)
+	}
+
+	return nil
+}
+
+func (ktps *KeyTransferPolicyStore) Search(criteria *models.KeyTransferPolicyFilterCriteria) ([]kbs.KeyTransferPolicyAttributes, error) {
+	defaultLog.Trace("sqlite/key_transfer_policy_store:Search() Entering")
+	defer defaultLog.Trace("sqlite/key_transfer_policy_store:Search() Leaving")
+
+	tx := ktps.Store.Db.Model(&keyTransferPolicy{})
+	if criteria != nil && criteria.TenantID != nil {
+		tx = tx.Where("tenant_id = ?", *criteria.TenantID)
+	}
+
+	var dbPolicies []keyTransferPolicy
+	if err := tx.Find(&dbPolicies).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/key_transfer_policy_store:Search() Error in searching the key transfer policies")
+	}
+
+	var policies = []kbs.KeyTransferPolicyAttributes{}
+	for _, dbPolicy := range dbPolicies {
+		policy, err := dbPolicy.toKeyTransferPolicy()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *policy)
+	}
+
+	return policies, nil
+}
+
+func (p *keyTransferPolicy) toKeyTransferPolicy() (*kbs.KeyTransferPolicyAttributes, error) {
+	var policy kbs.KeyTransferPolicyAttributes
+	if err := json.Unmarshal([]byte(p.Content), &policy); err != nil {
+		return nil, errors.Wrapf(err, "sqlite/key_transfer_policy_store:toKeyTransferPolicy() Failed to unmarshal key transfer policy : %s", p.ID)
+	}
+	return &policy, nil
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package sqlite
+
+import (
+	"os"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	// Import driver for GORM
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+var defaultLog = log.GetDefaultLogger()
+
+// The records are kept as the same JSON documents the directory stores write to files, the columns next to the
+// content are the ones the searches filter on
+type (
+	key struct {
+		ID               string `gorm:"primary_key"`
+		Algorithm        string `gorm:"index:idx_key_algorithm"`
+		KeyLength        int
+		CurveType        string
+		TransferPolicyID string `gorm:"index:idx_key_transfer_policy_id"`
+		TenantID         string `gorm:"index:idx_key_tenant_id"`
+		Content          string `gorm:"not null"`
+	}
+
+	keyTransferPolicy struct {
+		ID       string `gorm:"primary_key"`
+		TenantID string `gorm:"index:idx_key_transfer_policy_tenant_id"`
+		Content  string `gorm:"not null"`
+	}
+
+	tenantQuota struct {
+		TenantID string `gorm:"primary_key"`
+		Content  string `gorm:"not null"`
+	}
+
+	approval struct {
+		ID          string `gorm:"primary_key"`
+		TenantID    string `gorm:"index:idx_approval_tenant_id"`
+		Status      string `gorm:"index:idx_approval_status"`
+		RequestedAt time.Time
+		Content     string `gorm:"not null"`
+	}
+)
+
+// DataStore is the SQLite database the standalone KBS keeps its keys, key transfer policies, tenant quotas and approval
+// requests in
+type DataStore struct {
+	Db *gorm.DB
+}
+
+// NewDataStore opens the SQLite database in dbFile and creates its tables, the file is created if it does not
+// exist and is only readable by its owner
+func NewDataStore(dbFile string) (*DataStore, error) {
+	defaultLog.Trace("sqlite/sqlite:NewDataStore() Entering")
+	defer defaultLog.Trace("sqlite/sqlite:NewDataStore() Leaving")
+
+	file, err := os.OpenFile(dbFile, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sqlite/sqlite:NewDataStore() Error creating the database file %s", dbFile)
+	}
+	if err = file.Close(); err != nil {
+		return nil, errors.Wrapf(err, "sqlite/sqlite:NewDataStore() Error closing the database file %s", dbFile)
+	}
+
+	db, err := gorm.Open("sqlite3", "file:"+dbFile+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, errors.Wrapf(err, "sqlite/sqlite:NewDataStore() Error opening the database file %s", dbFile)
+	}
+	db.SingularTable(true)
+	if err = db.AutoMigrate(key{}, keyTransferPolicy{}, tenantQuota{}, approval{}).Error; err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "sqlite/sqlite:NewDataStore() Error migrating the database")
+	}
+	return &DataStore{Db: db}, nil
+}
+
+func (ds *DataStore) Close() error {
+	return ds.Db.Close()
+}
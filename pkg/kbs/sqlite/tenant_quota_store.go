@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package sqlite
+
+import (
+	"encoding/json"
+
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/kbs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type TenantQuotaStore struct {
+	Store *DataStore
+}
+
+func NewTenantQuotaStore(store *DataStore) *TenantQuotaStore {
+	return &TenantQuotaStore{store}
+}
+
+func (tqs *TenantQuotaStore) Create(quota *kbs.TenantQuota) (*kbs.TenantQuota, error) {
+	defaultLog.Trace("sqlite/tenant_quota_store:Create() Entering")
+	defer defaultLog.Trace("sqlite/tenant_quota_store:Create() Leaving")
+
+	bytes, err := json.Marshal(quota)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite/tenant_quota_store:Create() Failed to marshal tenant quota")
+	}
+
+	// the quota replaces the existing quota of the tenant
+	dbQuota := tenantQuota{
+		TenantID: quota.TenantID,
+		Content:  string(bytes),
+	}
+	if err = tqs.Store.Db.Save(&dbQuota).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/tenant_quota_store:Create() Error in saving tenant quota")
+	}
+
+	return quota, nil
+}
+
+func (tqs *TenantQuotaStore) Retrieve(tenantId string) (*kbs.TenantQuota, error) {
+	defaultLog.Trace("sqlite/tenant_quota_store:Retrieve() Entering")
+	defer defaultLog.Trace("sqlite/tenant_quota_store:Retrieve() Leaving")
+
+	var dbQuota tenantQuota
+	if err := tqs.Store.Db.Where("tenant_id = ?", tenantId).First(&dbQuota).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrapf(err, "sqlite/tenant_quota_store:Retrieve() Unable to retrieve tenant quota : %s", tenantId)
+	}
+
+	return dbQuota.toTenantQuota()
+}
+
+func (tqs *TenantQuotaStore) Delete(tenantId string) error {
+	defaultLog.Trace("sqlite/tenant_quota_store:Delete() Entering")
+	defer defaultLog.Trace("sqlite/tenant_quota_store:Delete() Leaving")
+
+	result := tqs.Store.Db.Where("tenant_id = ?", tenantId).Delete(&tenantQuota{})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "sqlite/tenant_quota_store:Delete() Unable to remove tenant quota : %s", tenantId)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(commErr.RecordNotFound)
+	}
+
+	return nil
+}
+
+func (tqs *TenantQuotaStore) Search() ([]kbs.TenantQuota, error) {
+	defaultLog.Trace("sqlite/tenant_quota_store:Search() Entering")
+	defer defaultLog.Trace("sqlite/tenant_quota_store:Search() Leaving")
+
+	var dbQuotas []tenantQuota
+	if err := tqs.Store.Db.Find(&dbQuotas).Error; err != nil {
+		return nil, errors.Wrap(err, "sqlite/tenant_quota_store:Search() Error in searching the tenant quotas")
+	}
+
+	var quotas = []kbs.TenantQuota{}
+	for _, dbQuota := range dbQuotas {
+		quota, err := dbQuota.toTenantQuota()
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, *quota)
+	}
+
+	return quotas, nil
+}
+
+func (q *tenantQuota) toTenantQuota() (*kbs.TenantQuota, error) {
+	var quota kbs.TenantQuota
+	if err := json.Unmarshal([]byte(q.Content), &quota); err != nil {
+		return nil, errors.Wrapf(err, "sqlite/tenant_quota_store:toTenantQuota() Failed to unmarshal tenant quota : %s", q.TenantID)
+	}
+	return &quota, nil
+}
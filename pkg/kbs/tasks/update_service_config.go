@@ -22,6 +22,8 @@ type UpdateServiceConfig struct {
 	ServiceConfig config.KBSConfig
 	DefaultPort   int
 	AASApiUrl     string
+	// SecretSealing seals the service password saved in the configuration to the local TPM
+	SecretSealing setup.SecretSealing
 	AppConfig     **config.Configuration
 	ConsoleWriter io.Writer
 }
@@ -29,7 +31,8 @@ type UpdateServiceConfig struct {
 const envHelpPrompt = "Following environment variables are required for update-service-config setup:"
 
 var allowedSKCChallengeTypes = map[string]bool{"sgx": true, "sw": true, "sgx,sw": true, "sw,sgx": true}
-var allowedKeyManagers = map[string]bool{"directory": true, "kmip": true}
+var allowedKeyManagers = map[string]bool{"directory": true, "kmip": true, "cloudkms": true}
+var allowedCloudKmsProviders = map[string]bool{"aws": true, "azure": true, "gcp": true}
 
 var envHelp = map[string]string{
 	"SERVICE_USERNAME":           "The service username as configured in AAS",
@@ -43,15 +46,34 @@ var envHelp = map[string]string{
 	"KMIP_CLIENT_CERT_PATH":      "KMIP Client certificate path",
 	"KMIP_CLIENT_KEY_PATH":       "KMIP Client key path",
 	"KMIP_ROOT_CERT_PATH":        "KMIP Root Certificate path",
+	"CLOUD_KMS_PROVIDER":         "Cloud KMS wrapping the keys with the cloudkms key manager: aws, azure or gcp",
+	"CLOUD_KMS_KEY_ID":           "ARN, Key Vault key URL or crypto key name of the cloud KMS key encryption key",
+	"CLOUD_KMS_REGION":           "AWS region of the cloud KMS key encryption key",
+	"CLOUD_KMS_ENDPOINT":         "Private endpoint of the cloud KMS, the public endpoint is used if not set",
 	"SKC_CHALLENGE_TYPE":         "SKC challenge type",
 	"SQVS_URL":                   "SQVS URL",
 	"SESSION_EXPIRY_TIME":        "Session Expiry Time",
+	"NONCE_EXPIRY_TIME":          "Time in seconds to create the session with the nonce of a challenge",
+	"OIDC_ISSUER":                "Issuer URL of the OpenID Connect provider whose access tokens are accepted in addition to the AAS tokens",
+	"OIDC_AUDIENCE":              "Audience of the OIDC access tokens issued for KBS",
+	"OIDC_SCOPE_PERMISSIONS":     "JSON map of the OIDC scopes to the KBS permissions, e.g. {\"kbs.transfer\": [\"keys:transfer\"]}",
+	"OIDC_JWKS_CACHE_TIME":       "Time the signing keys of the OIDC provider are cached, e.g. 1h",
 	"SERVER_PORT":                "The Port on which Server Listens to",
 	"SERVER_READ_TIMEOUT":        "Request Read Timeout Duration in Seconds",
 	"SERVER_READ_HEADER_TIMEOUT": "Request Read Header Timeout Duration in Seconds",
 	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
+	"SERVER_DRAIN_TIMEOUT":       "Maximum Duration to Drain the In-Flight Requests on Shutdown",
+	"SERVER_BODY_READ_TIMEOUT":   "Maximum Duration to Receive a Request Body",
+	"SERVER_MAX_RESPONSE_BYTES":  "Max Length Of Response Body in Bytes, 0 for no limit",
 	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes ",
+	"SERVER_MAX_BODY_BYTES":      "Max Length Of Request Body in Bytes",
+}
+
+func init() {
+	for k, d := range setup.SecretSealingEnvHelp {
+		envHelp[k] = d
+	}
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -66,6 +88,11 @@ func (uc UpdateServiceConfig) Run() error {
 		uc.ServerConfig.Port > 65535 {
 		uc.ServerConfig.Port = uc.DefaultPort
 	}
+	servicePassword, err := uc.SecretSealing.Seal(uc.ServiceConfig.Password)
+	if err != nil {
+		return errors.Wrap(err, "Failed to seal the KBS service password")
+	}
+	uc.ServiceConfig.Password = servicePassword
 	(*uc.AppConfig).KBS = uc.ServiceConfig
 
 	(*uc.AppConfig).Server = uc.ServerConfig
@@ -85,10 +112,23 @@ func (uc UpdateServiceConfig) Run() error {
 		ClientKey:  viper.GetString("kmip-client-key-path"),
 		RootCert:   viper.GetString("kmip-root-cert-path"),
 	}
+	(*uc.AppConfig).CloudKms = config.CloudKmsConfig{
+		Provider: viper.GetString("cloud-kms-provider"),
+		KeyID:    viper.GetString("cloud-kms-key-id"),
+		Region:   viper.GetString("cloud-kms-region"),
+		Endpoint: viper.GetString("cloud-kms-endpoint"),
+	}
 	(*uc.AppConfig).Skc = config.SKCConfig{
 		StmLabel:          viper.GetString("skc-challenge-type"),
 		SQVSUrl:           viper.GetString("sqvs-url"),
 		SessionExpiryTime: viper.GetInt("session-expiry-time"),
+		NonceExpiryTime:   viper.GetInt("nonce-expiry-time"),
+	}
+	(*uc.AppConfig).OIDC = commConfig.OIDCConfig{
+		Issuer:           viper.GetString("oidc-issuer"),
+		Audience:         viper.GetString("oidc-audience"),
+		ScopePermissions: viper.GetStringMapStringSlice("oidc-scope-permissions"),
+		JWKSCacheTime:    viper.GetDuration("oidc-jwks-cache-time"),
 	}
 	(*uc.AppConfig).KeyManager = viper.GetString("key-manager")
 	return nil
@@ -103,13 +143,39 @@ func (uc UpdateServiceConfig) Validate() error {
 		return errors.New("Configured port is not valid")
 	}
 	if _, validInput := allowedKeyManagers[strings.ToLower((*uc.AppConfig).KeyManager)]; !validInput {
-		return errors.New("Invalid value provided for KEY_MANAGER. Value should be either directory or kmip")
+		return errors.New("Invalid value provided for KEY_MANAGER. Value should be either directory, kmip or cloudkms")
+	}
+	if strings.ToLower((*uc.AppConfig).KeyManager) == "cloudkms" {
+		if _, validInput := allowedCloudKmsProviders[strings.ToLower((*uc.AppConfig).CloudKms.Provider)]; !validInput {
+			return errors.New("Invalid value provided for CLOUD_KMS_PROVIDER. Value should be either aws, azure or gcp")
+		}
+		if (*uc.AppConfig).CloudKms.KeyID == "" {
+			return errors.New("KBS configuration not provided: CLOUD_KMS_KEY_ID is not set")
+		}
+		if strings.ToLower((*uc.AppConfig).CloudKms.Provider) == "aws" && (*uc.AppConfig).CloudKms.Region == "" {
+			return errors.New("KBS configuration not provided: CLOUD_KMS_REGION is not set")
+		}
 	}
 	if (*uc.AppConfig).Skc.StmLabel != "" {
 		if _, validInput := allowedSKCChallengeTypes[strings.ToLower((*uc.AppConfig).Skc.StmLabel)]; !validInput {
 			return errors.New("Invalid value provided for SKC_CHALLENGE_TYPE. List of allowed values SGX, SW or any combination for SGX and SW")
 		}
 	}
+	if (*uc.AppConfig).OIDC.Issuer != "" {
+		if !strings.HasPrefix((*uc.AppConfig).OIDC.Issuer, "https://") {
+			return errors.New("Invalid value provided for OIDC_ISSUER. Value should be an https URL")
+		}
+		if (*uc.AppConfig).OIDC.Audience == "" {
+			return errors.New("KBS configuration not provided: OIDC_AUDIENCE is not set")
+		}
+		for scope, rules := range (*uc.AppConfig).OIDC.ScopePermissions {
+			for _, rule := range rules {
+				if len(strings.Split(rule, ":")) < 2 {
+					return errors.Errorf("Invalid value provided for OIDC_SCOPE_PERMISSIONS. Permission %s of scope %s should be resource:action", rule, scope)
+				}
+			}
+		}
+	}
 	return nil
 }
 func (uc UpdateServiceConfig) PrintHelp(w io.Writer) {
@@ -0,0 +1,38 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"regexp"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	"github.com/pkg/errors"
+)
+
+var tenantContextRegex = regexp.MustCompile(constants.TenantContextRegex)
+
+// GetTenantID returns the tenant set in the context of the KBS roles of a user. Users without a tenant work in
+// the default namespace, which is returned as an empty tenant.
+func GetTenantID(roles []aas.RoleInfo) (string, error) {
+	defaultLog.Trace("utils/tenant:GetTenantID() Entering")
+	defer defaultLog.Trace("utils/tenant:GetTenantID() Leaving")
+
+	tenantId := ""
+	for _, role := range roles {
+		if role.Service != constants.ServiceName {
+			continue
+		}
+		match := tenantContextRegex.FindStringSubmatch(role.Context)
+		if match == nil {
+			continue
+		}
+		if tenantId != "" && tenantId != match[1] {
+			return "", errors.New("KBS roles of the user belong to more than one tenant")
+		}
+		tenantId = match[1]
+	}
+	return tenantId, nil
+}
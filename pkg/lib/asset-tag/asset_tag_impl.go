@@ -6,11 +6,13 @@
 package asset_tag
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"fmt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	hc "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector"
 	"github.com/pkg/errors"
 	"math/big"
@@ -42,7 +44,7 @@ func (aTag *atag) CreateAssetTag(tagCertConfig TagCertConfig) ([]byte, error) {
 	var extensions []pkix.Extension
 
 	for _, tagKvAttribute := range tagCertConfig.TagAttributes {
-		derEncodedAttr, err := asn1.Marshal(tagKvAttribute)
+		derEncodedAttr, err := MarshalTagKvAttribute(tagKvAttribute)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to marshal ASN1 Tag Cert attributes")
 		}
@@ -73,17 +75,19 @@ func tagCertificateBuilder(certConfig TagCertBuilderConfig) ([]byte, error) {
 		return nil, errors.New("Missing certificate attributes to create asset tag certificate")
 	}
 
-	certificateTemplate := &x509.Certificate{
-		SerialNumber:       certConfig.SerialNumber,
-		Issuer:             certConfig.TagCertConfig.TagCACert.Issuer,
-		Subject:            certConfig.SubjectName,
-		ExtraExtensions:    certConfig.Extensions,
-		SignatureAlgorithm: x509.SHA384WithRSA,
-		NotBefore:          time.Now(),
-		NotAfter:           time.Now().Add(certConfig.ValidityDuration),
+	var attributes []crypt.Attribute
+	for _, extension := range certConfig.Extensions {
+		attributes = append(attributes, crypt.Attribute{Type: extension.Id, Value: extension.Value})
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, certificateTemplate, certConfig.TagCertConfig.TagCACert, certConfig.TagCertConfig.TagCACert.PublicKey, certConfig.TagCertConfig.PrivateKey)
+	now := time.Now()
+	derBytes, err := crypt.CreateAttributeCertificate(crypt.AttributeCertificateTemplate{
+		SerialNumber: certConfig.SerialNumber,
+		Holder:       certConfig.SubjectName.CommonName,
+		Attributes:   attributes,
+		NotBefore:    now,
+		NotAfter:     now.Add(certConfig.ValidityDuration),
+	}, certConfig.TagCertConfig.TagCACert, certConfig.TagCertConfig.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("Error while creating asset tag certificate: %s", err)
 	}
@@ -92,13 +96,13 @@ func tagCertificateBuilder(certConfig TagCertBuilderConfig) ([]byte, error) {
 }
 
 // DeployAssetTag implements the interface AssetTag to deploy an asset tag certificate on a particular host with custom tag attributes
-func (aTag *atag) DeployAssetTag(connector hc.HostConnector, tagCertDigest, hostHardwareUUID string) error {
+func (aTag *atag) DeployAssetTag(ctx context.Context, connector hc.HostConnector, tagCertDigest, hostHardwareUUID string) error {
 
 	if tagCertDigest == "" || hostHardwareUUID == "" {
 		return errors.New("Invalid input: tag sha384 digest and host hardware UUID must be given to deploy an asset tag")
 	}
 
-	err := connector.DeployAssetTag(hostHardwareUUID, tagCertDigest)
+	err := connector.DeployAssetTag(ctx, hostHardwareUUID, tagCertDigest)
 	if err != nil {
 		return fmt.Errorf("Error while deploying asset tag certificate on host %s: %s", hostHardwareUUID, err)
 	}
@@ -124,3 +128,37 @@ func validateTagCertConfig(tagCertConfig TagCertConfig) error {
 	}
 	return nil
 }
+
+// MarshalTagKvAttribute returns the DER encoding of the tag as a tag certificate extension value
+func MarshalTagKvAttribute(attr TagKvAttribute) ([]byte, error) {
+	asn1Attr := tagKvAttributeASN1{
+		Key:   attr.Key,
+		Value: attr.Value,
+	}
+	if attr.NotBefore != nil {
+		asn1Attr.NotBefore = attr.NotBefore.UTC()
+	}
+	if attr.NotAfter != nil {
+		asn1Attr.NotAfter = attr.NotAfter.UTC()
+	}
+	return asn1.Marshal(asn1Attr)
+}
+
+// UnmarshalTagKvAttribute parses a tag from a tag certificate extension value
+func UnmarshalTagKvAttribute(der []byte) (TagKvAttribute, error) {
+	var asn1Attr tagKvAttributeASN1
+	if _, err := asn1.Unmarshal(der, &asn1Attr); err != nil {
+		return TagKvAttribute{}, err
+	}
+	attr := TagKvAttribute{
+		Key:   asn1Attr.Key,
+		Value: asn1Attr.Value,
+	}
+	if !asn1Attr.NotBefore.IsZero() {
+		attr.NotBefore = &asn1Attr.NotBefore
+	}
+	if !asn1Attr.NotAfter.IsZero() {
+		attr.NotAfter = &asn1Attr.NotAfter
+	}
+	return attr, nil
+}
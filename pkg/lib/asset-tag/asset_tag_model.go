@@ -34,4 +34,28 @@ type TagCertBuilderConfig struct {
 type TagKvAttribute struct {
 	Key   string `json:"name"`
 	Value string `json:"value"`
+	// NotBefore and NotAfter scope the tag to an activation window, the verifier treats the tag as absent outside of
+	// it. Tags without a window are active for the validity of the tag certificate.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+}
+
+// ActiveAt returns true when the tag is within its activation window at t
+func (attr TagKvAttribute) ActiveAt(t time.Time) bool {
+	if attr.NotBefore != nil && t.Before(*attr.NotBefore) {
+		return false
+	}
+	if attr.NotAfter != nil && t.After(*attr.NotAfter) {
+		return false
+	}
+	return true
+}
+
+// tagKvAttributeASN1 is the encoding of TagKvAttribute in the tag certificate extensions, the activation window is
+// optional so that the tags without a window are encoded as they were before windows
+type tagKvAttributeASN1 struct {
+	Key       string
+	Value     string
+	NotBefore time.Time `asn1:"optional,explicit,generalized,tag:0"`
+	NotAfter  time.Time `asn1:"optional,explicit,generalized,tag:1"`
 }
@@ -6,6 +6,7 @@
 package asset_tag
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -57,8 +58,7 @@ func TestAtag_CreateAssetTag(t *testing.T) {
 
 	// validate tag key-value attributes
 	for _, extensions := range parsedCert.Extensions {
-		var tagAttr TagKvAttribute
-		_, err = asn1.Unmarshal(extensions.Value, &tagAttr)
+		tagAttr, err := UnmarshalTagKvAttribute(extensions.Value)
 		assert.NoError(t, err)
 		assert.Equal(t, "Country", tagAttr.Key)
 		assert.Contains(t, "US India", tagAttr.Value)
@@ -152,15 +152,42 @@ func TestAtag_CreateAssetTag(t *testing.T) {
 
 }
 
+// unit test to check that the tags with an activation window are encoded with it, and the tags without as before
+func TestTagKvAttributeActivationWindow(t *testing.T) {
+	legacyDer, err := asn1.Marshal(struct{ Key, Value string }{"Country", "US"})
+	assert.NoError(t, err)
+	der, err := MarshalTagKvAttribute(TagKvAttribute{Key: "Country", Value: "US"})
+	assert.NoError(t, err)
+	assert.Equal(t, legacyDer, der)
+
+	tag, err := UnmarshalTagKvAttribute(legacyDer)
+	assert.NoError(t, err)
+	assert.Equal(t, TagKvAttribute{Key: "Country", Value: "US"}, tag)
+	assert.True(t, tag.ActiveAt(time.Now()))
+
+	notBefore := time.Date(2020, time.October, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(24 * time.Hour)
+	der, err = MarshalTagKvAttribute(TagKvAttribute{Key: "Maintenance", Value: "true", NotBefore: &notBefore, NotAfter: &notAfter})
+	assert.NoError(t, err)
+	tag, err = UnmarshalTagKvAttribute(der)
+	assert.NoError(t, err)
+	assert.Equal(t, "Maintenance", tag.Key)
+	assert.True(t, tag.NotBefore.Equal(notBefore))
+	assert.True(t, tag.NotAfter.Equal(notAfter))
+	assert.False(t, tag.ActiveAt(notBefore.Add(-time.Second)))
+	assert.True(t, tag.ActiveAt(notBefore.Add(time.Hour)))
+	assert.False(t, tag.ActiveAt(notAfter.Add(time.Second)))
+}
+
 func TestAtag_DeployAssetTag(t *testing.T) {
 	newTag := NewAssetTag()
 	var trustedCAcerts []x509.Certificate
 	htcFactory := hc.NewHostConnectorFactory("", trustedCAcerts)
 	connector, err := htcFactory.NewHostConnector("https://ta.ip.com:1443;u=serviceUsername;p=servicePassword")
 	assert.NoError(t, err)
-	dtErr := newTag.DeployAssetTag(connector, "0966d97d182ee8fac40bee16018e762ae46a026f0bb437600e029a755f8745a9a6bb8b3da152ea37ef52f0d855b6622f\n", "803f6068-06da-e811-906e-00163566263e")
+	dtErr := newTag.DeployAssetTag(context.Background(), connector, "0966d97d182ee8fac40bee16018e762ae46a026f0bb437600e029a755f8745a9a6bb8b3da152ea37ef52f0d855b6622f\n", "803f6068-06da-e811-906e-00163566263e")
 	assert.NotNil(t, dtErr)
-	dtErrNew := newTag.DeployAssetTag(connector, "", "")
+	dtErrNew := newTag.DeployAssetTag(context.Background(), connector, "", "")
 	assert.NotNil(t, dtErrNew)
 }
 
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const bashCompletionTemplate = `# bash completion for %[1]s, load it with: source <(%[1]s completion bash)
+_%[2]s_completion() {
+	local cur=${COMP_WORDS[COMP_CWORD]}
+	local words=""
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		words="%[3]s"
+	else
+		case "${COMP_WORDS[1]}" in
+%[4]s		esac
+	fi
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -F _%[2]s_completion %[1]s
+`
+
+const zshCompletionTemplate = `#compdef %[1]s
+# zsh completion for %[1]s, load it with: source <(%[1]s completion zsh)
+autoload -U +X bashcompinit && bashcompinit
+`
+
+// BashCompletion writes a bash completion script of the commands, their flags and completions, to be sourced by
+// the shell
+func (cmd *Cmd) BashCompletion(w io.Writer) error {
+	funcName := strings.NewReplacer("-", "_", ".", "_").Replace(cmd.Name)
+	var cases strings.Builder
+	for _, sub := range cmd.SubCmd {
+		words := sub.completionWords(cmd.Flags)
+		if len(words) == 0 {
+			continue
+		}
+		names := append([]string{sub.Name}, sub.Aliases...)
+		fmt.Fprintf(&cases, "\t\t%s)\n\t\t\twords=\"%s\"\n\t\t\t;;\n", strings.Join(names, "|"),
+			strings.Join(words, " "))
+	}
+	_, err := fmt.Fprintf(w, bashCompletionTemplate, cmd.Name, funcName, strings.Join(cmd.completionWords(nil), " "),
+		cases.String())
+	return err
+}
+
+// ZshCompletion writes a zsh completion script of the commands, it relies on the bash completion support of zsh
+func (cmd *Cmd) ZshCompletion(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, zshCompletionTemplate, cmd.Name); err != nil {
+		return err
+	}
+	return cmd.BashCompletion(w)
+}
+
+// completionWords lists the sub commands of the command, or its completions and flags, along with the global flags
+func (cmd *Cmd) completionWords(globalFlags []CmdFlag) []string {
+	var words []string
+	for _, sub := range cmd.SubCmd {
+		if sub.Exec != nil || sub.Name == completionCmdName {
+			words = append(words, sub.Name)
+		}
+	}
+	words = append(words, cmd.Completions...)
+	for _, flag := range append(cmd.Flags, globalFlags...) {
+		words = append(words, "--"+flag.Name)
+	}
+	return words
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// OutputText is the default output format of the commands
+	OutputText = "text"
+	// OutputJSON prints the result of the commands supporting it as JSON
+	OutputJSON = "json"
+
+	outputFlagName    = "output"
+	completionCmdName = "completion"
+)
+
+var ErrInvalidInput = errors.New("Invalid input after command")
+
+// CompletionCmd lists the shell completion command handled by the Runner in the commands of a service
+var CompletionCmd = Cmd{
+	Name:        completionCmdName,
+	DispStr:     "completion <bash|zsh>",
+	Description: "Print the shell completion script",
+	Completions: []string{"bash", "zsh"},
+}
+
+// Runner dispatches the command line of a service to the commands of its Root, the flags of Root are global and
+// accepted along with any command
+type Runner struct {
+	Root *Cmd
+
+	ConsoleWriter io.Writer
+	// PrintUsage prints the usage of the service, with the error of an invalid command line if it is not nil
+	PrintUsage func(err error)
+}
+
+// Context holds the parsed command line of the command being run
+type Context struct {
+	// Args are the arguments from the command name on
+	Args  []string
+	Flags CmdArgs
+
+	ConsoleWriter io.Writer
+}
+
+// OutputFlag is the global flag selecting the output format of the commands, the env variable is
+// <ENV-PREFIX>_OUTPUT
+func OutputFlag(envPrefix string) CmdFlag {
+	return CmdFlag{
+		Name:        outputFlagName,
+		Description: "output format of the version and status commands, text or json",
+		DefInEnv:    true,
+		Env:         strings.ToUpper(envPrefix) + "_OUTPUT",
+	}
+}
+
+// Bool returns true if the boolean flag is set on the command line or in its env variable
+func (ctx *Context) Bool(name string) bool {
+	return ctx.Flags[name] == "true"
+}
+
+// String returns the value of the flag from the command line or from its env variable
+func (ctx *Context) String(name string) string {
+	return ctx.Flags[name]
+}
+
+// JSONOutput returns true if the command result is to be printed as JSON
+func (ctx *Context) JSONOutput() bool {
+	return ctx.Flags[outputFlagName] == OutputJSON
+}
+
+// Print writes the result of the command to the console, as JSON if the output flag asks for it or as text
+func (ctx *Context) Print(text string, result interface{}) error {
+	if !ctx.JSONOutput() {
+		_, err := fmt.Fprint(ctx.ConsoleWriter, text)
+		return err
+	}
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal command output")
+	}
+	_, err = fmt.Fprintln(ctx.ConsoleWriter, string(out))
+	return err
+}
+
+// Run parses the command line and runs the command it names. An invalid command line is reported with the usage of
+// the service.
+func (r *Runner) Run(args []string) error {
+	globals, args, err := parseFlags(r.Root.Flags, args, true)
+	if err != nil {
+		r.PrintUsage(err)
+		return err
+	}
+	if output := globals[outputFlagName]; output != "" && output != OutputText && output != OutputJSON {
+		err = errors.New("Invalid output format: " + output)
+		r.PrintUsage(err)
+		return err
+	}
+	if len(args) < 2 {
+		err = errors.New("Invalid usage of " + r.Root.Name)
+		r.PrintUsage(err)
+		return err
+	}
+	if args[1] == completionCmdName {
+		return r.completion(args[2:])
+	}
+
+	cmd := r.Root.findSubCmd(args[1])
+	if cmd == nil || cmd.Exec == nil {
+		err = errors.New("Invalid command: " + args[1])
+		r.PrintUsage(err)
+		return err
+	}
+	ctx := &Context{
+		Args:          args[1:],
+		Flags:         globals,
+		ConsoleWriter: r.consoleWriter(),
+	}
+	if !cmd.PassThrough {
+		flags, rest, err := parseFlags(cmd.Flags, args[2:], false)
+		if err != nil {
+			return err
+		}
+		if len(rest) != 0 {
+			return ErrInvalidInput
+		}
+		for name, value := range flags {
+			ctx.Flags[name] = value
+		}
+	}
+	return cmd.Exec(ctx)
+}
+
+func (r *Runner) completion(args []string) error {
+	if len(args) != 1 {
+		return ErrInvalidInput
+	}
+	switch args[0] {
+	case "bash":
+		return r.Root.BashCompletion(r.consoleWriter())
+	case "zsh":
+		return r.Root.ZshCompletion(r.consoleWriter())
+	}
+	return errors.New("Unsupported shell: " + args[0])
+}
+
+func (r *Runner) consoleWriter() io.Writer {
+	if r.ConsoleWriter != nil {
+		return r.ConsoleWriter
+	}
+	return os.Stdout
+}
+
+func (cmd *Cmd) findSubCmd(name string) *Cmd {
+	for i := range cmd.SubCmd {
+		if cmd.SubCmd[i].Name == name {
+			return &cmd.SubCmd[i]
+		}
+		for _, alias := range cmd.SubCmd[i].Aliases {
+			if alias == name {
+				return &cmd.SubCmd[i]
+			}
+		}
+	}
+	return nil
+}
+
+// parseFlags takes the flags out of the arguments and returns them with the remaining arguments. Flags not on the
+// command line are read from their env variable. Unknown flags are left in the arguments if ignoreUnknown is set,
+// otherwise they are an error.
+func parseFlags(flags []CmdFlag, args []string, ignoreUnknown bool) (CmdArgs, []string, error) {
+	parsed := make(CmdArgs)
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := splitFlag(args[i])
+		flag := findFlag(flags, name)
+		if flag == nil {
+			if name != "" && !ignoreUnknown {
+				return nil, nil, errors.New("Invalid flag: " + args[i])
+			}
+			rest = append(rest, args[i])
+			continue
+		}
+		switch {
+		case flag.Bool && hasValue:
+			return nil, nil, errors.New("Invalid flag: " + args[i])
+		case flag.Bool:
+			value = "true"
+		case !hasValue:
+			if i+1 >= len(args) {
+				return nil, nil, errors.New("Missing value for flag: " + args[i])
+			}
+			i++
+			value = args[i]
+		}
+		parsed[flag.Name] = value
+	}
+	for _, flag := range flags {
+		if _, ok := parsed[flag.Name]; !ok && flag.DefInEnv {
+			env := os.Getenv(flag.Env)
+			if flag.Bool {
+				if set, _ := strconv.ParseBool(env); set {
+					parsed[flag.Name] = "true"
+				}
+			} else if env != "" {
+				parsed[flag.Name] = env
+			}
+		}
+	}
+	return parsed, rest, nil
+}
+
+// splitFlag returns the name and the value of "--name=value", "--name" and "-n", the name is empty if the argument
+// is not a flag
+func splitFlag(arg string) (string, string, bool) {
+	switch {
+	case strings.HasPrefix(arg, "--") && len(arg) > 2:
+		arg = arg[2:]
+	case strings.HasPrefix(arg, "-") && len(arg) == 2:
+		return arg, "", false
+	default:
+		return "", "", false
+	}
+	if equalSign := strings.Index(arg, "="); equalSign > -1 {
+		return arg[:equalSign], arg[equalSign+1:], true
+	}
+	return arg, "", false
+}
+
+func findFlag(flags []CmdFlag, name string) *CmdFlag {
+	if name == "" {
+		return nil
+	}
+	for i := range flags {
+		if flags[i].Name == name || (flags[i].Short != "" && "-"+flags[i].Short == name) {
+			return &flags[i]
+		}
+	}
+	return nil
+}
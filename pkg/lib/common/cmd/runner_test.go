@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type runResult struct {
+	cmd   string
+	args  []string
+	flags CmdArgs
+}
+
+func newTestRunner(result *runResult, output *bytes.Buffer, usageErr *error) *Runner {
+	exec := func(name string) func(ctx *Context) error {
+		return func(ctx *Context) error {
+			*result = runResult{cmd: name, args: ctx.Args, flags: ctx.Flags}
+			return ctx.Print("text\n", map[string]string{"cmd": name})
+		}
+	}
+	return &Runner{
+		Root: &Cmd{Name: "app", Flags: []CmdFlag{OutputFlag("app")},
+			SubCmd: []Cmd{
+				{Name: "version", Aliases: []string{"-v", "--version"}, Exec: exec("version")},
+				{Name: "run", Flags: []CmdFlag{{Name: "standalone", Bool: true, DefInEnv: true, Env: "APP_STANDALONE"},
+					{Name: "instance", Short: "i"}}, Exec: exec("run")},
+				{Name: "setup", PassThrough: true, Completions: []string{"all", "task1"},
+					Flags: []CmdFlag{{Name: "force"}}, Exec: exec("setup")},
+				CompletionCmd,
+			}},
+		ConsoleWriter: output,
+		PrintUsage: func(err error) {
+			*usageErr = err
+		},
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+	var result runResult
+	var output bytes.Buffer
+	var usageErr error
+	runner := newTestRunner(&result, &output, &usageErr)
+
+	if err := runner.Run([]string{"app", "-v"}); err != nil || result.cmd != "version" || output.String() != "text\n" {
+		t.Error("Alias of the version command is not run:", err, result, output.String())
+	}
+
+	output.Reset()
+	if err := runner.Run([]string{"app", "--output", "json", "version"}); err != nil ||
+		!strings.Contains(output.String(), `"cmd": "version"`) {
+		t.Error("JSON output is not printed:", err, output.String())
+	}
+
+	if err := runner.Run([]string{"app", "run", "--standalone", "-i", "one"}); err != nil ||
+		!reflect.DeepEqual(result.flags, CmdArgs{"standalone": "true", "instance": "one"}) {
+		t.Error("Flags of the run command are not parsed:", err, result.flags)
+	}
+
+	os.Setenv("APP_STANDALONE", "true")
+	os.Setenv("APP_OUTPUT", "json")
+	defer os.Unsetenv("APP_STANDALONE")
+	defer os.Unsetenv("APP_OUTPUT")
+	if err := runner.Run([]string{"app", "run", "--instance=two"}); err != nil ||
+		!reflect.DeepEqual(result.flags, CmdArgs{"standalone": "true", "instance": "two", "output": "json"}) {
+		t.Error("Flags are not read from the environment:", err, result.flags)
+	}
+
+	if err := runner.Run([]string{"app", "setup", "task1", "--force", "-f", "answers.env"}); err != nil ||
+		!reflect.DeepEqual(result.args, []string{"setup", "task1", "--force", "-f", "answers.env"}) {
+		t.Error("Arguments of the setup command are not passed through:", err, result.args)
+	}
+}
+
+func TestRunnerInvalidInput(t *testing.T) {
+	var result runResult
+	var output bytes.Buffer
+	var usageErr error
+	runner := newTestRunner(&result, &output, &usageErr)
+
+	if err := runner.Run([]string{"app", "bogus"}); err == nil || usageErr == nil {
+		t.Error("Unknown command is not reported with the usage")
+	}
+	usageErr = nil
+	if err := runner.Run([]string{"app"}); err == nil || usageErr == nil {
+		t.Error("Missing command is not reported with the usage")
+	}
+	if err := runner.Run([]string{"app", "run", "--purge"}); err == nil {
+		t.Error("Unknown flag is accepted")
+	}
+	if err := runner.Run([]string{"app", "run", "--standalone=false"}); err == nil {
+		t.Error("Value of a boolean flag is accepted")
+	}
+	if err := runner.Run([]string{"app", "run", "extra"}); err != ErrInvalidInput {
+		t.Error("Input after the command is accepted:", err)
+	}
+	if err := runner.Run([]string{"app", "run", "-i"}); err == nil {
+		t.Error("Missing flag value is accepted")
+	}
+	if err := runner.Run([]string{"app", "--output", "xml", "version"}); err == nil {
+		t.Error("Unknown output format is accepted")
+	}
+}
+
+func TestRunnerCompletion(t *testing.T) {
+	var result runResult
+	var output bytes.Buffer
+	var usageErr error
+	runner := newTestRunner(&result, &output, &usageErr)
+
+	if err := runner.Run([]string{"app", "completion", "bash"}); err != nil {
+		t.Fatal("Failed to print the bash completion:", err)
+	}
+	script := output.String()
+	for _, expected := range []string{`words="version run setup completion --output"`,
+		`version|-v|--version)`, `words="--standalone --instance --output"`,
+		`words="all task1 --force --output"`, "complete -F _app_completion app"} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Completion script is missing %q:\n%s", expected, script)
+		}
+	}
+
+	output.Reset()
+	if err := runner.Run([]string{"app", "completion", "zsh"}); err != nil ||
+		!strings.HasPrefix(output.String(), "#compdef app") {
+		t.Error("Failed to print the zsh completion:", err)
+	}
+	if err := runner.Run([]string{"app", "completion", "fish"}); err == nil {
+		t.Error("Unsupported shell is accepted")
+	}
+}
+
+func TestParseServiceStatus(t *testing.T) {
+	status := parseServiceStatus("app", "MainPID=1234\nActiveState=active\nSubState=running\n")
+	expected := &ServiceStatus{Service: "app", ActiveState: "active", SubState: "running", MainPID: 1234}
+	if !reflect.DeepEqual(status, expected) {
+		t.Error("Unexpected service status:", status)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cmd
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VersionInfo is the JSON output of the version command
+type VersionInfo struct {
+	ServiceName string `json:"service_name"`
+	Version     string `json:"version"`
+	GitHash     string `json:"git_hash"`
+	BuildDate   string `json:"build_date"`
+}
+
+// ServiceStatus is the JSON output of the status command
+type ServiceStatus struct {
+	Service     string `json:"service"`
+	ActiveState string `json:"active_state"`
+	SubState    string `json:"sub_state"`
+	MainPID     int    `json:"main_pid,omitempty"`
+}
+
+// GetServiceStatus reads the state of the systemd service
+func GetServiceStatus(service string) (*ServiceStatus, error) {
+	systemctl, err := exec.LookPath("systemctl")
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not locate systemctl to check status of service")
+	}
+	out, err := exec.Command(systemctl, "show", "--property=ActiveState,SubState,MainPID", service).Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read status of service "+service)
+	}
+	return parseServiceStatus(service, string(out)), nil
+}
+
+func parseServiceStatus(service, properties string) *ServiceStatus {
+	status := &ServiceStatus{Service: service}
+	for _, line := range strings.Split(properties, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "ActiveState":
+			status.ActiveState = kv[1]
+		case "SubState":
+			status.SubState = kv[1]
+		case "MainPID":
+			status.MainPID, _ = strconv.Atoi(kv[1])
+		}
+	}
+	return status
+}
@@ -7,6 +7,7 @@ package cmd
 
 type Cmd struct {
 	Name        string
+	Aliases     []string
 	DispStr     string
 	Description string
 
@@ -15,6 +16,13 @@ type Cmd struct {
 	Flags      []CmdFlag
 
 	AppFuncName string
+
+	// Exec runs the command when it is dispatched by a Runner
+	Exec func(ctx *Context) error
+	// PassThrough commands parse their own arguments, the flags are only listed for the shell completion
+	PassThrough bool
+	// Completions are the words completed after the command other than its flags, like the setup tasks
+	Completions []string
 }
 
 type CmdFlag struct {
@@ -24,4 +32,9 @@ type CmdFlag struct {
 
 	DefInEnv bool
 	Env      string
+
+	// Short is the single letter form of the flag, used with a single dash
+	Short string
+	// Bool flags take no value
+	Bool bool
 }
@@ -17,9 +17,51 @@ type ServerConfig struct {
 	WriteTimeout      time.Duration `yaml:"write-timeout" mapstructure:"write-timeout"`
 	IdleTimeout       time.Duration `yaml:"idle-timeout" mapstructure:"idle-timeout"`
 	MaxHeaderBytes    int           `yaml:"max-header-bytes" mapstructure:"max-header-bytes"`
+	MaxBodyBytes      int64         `yaml:"max-body-bytes" mapstructure:"max-body-bytes"`
+	// MaxBodyBytesByPath overrides MaxBodyBytes for the requests the path of which contains a configured path, e.g.
+	// "/flavors" for the flavor uploads, the longest matching path applies
+	MaxBodyBytesByPath map[string]int64 `yaml:"max-body-bytes-by-path" mapstructure:"max-body-bytes-by-path"`
+	// BodyReadTimeout bounds the time to receive a request body so that slow clients cannot hold the handlers,
+	// zero disables it
+	BodyReadTimeout time.Duration `yaml:"body-read-timeout" mapstructure:"body-read-timeout"`
+	// MaxResponseBytes limits the size of a response, the connection is closed when a handler writes more, zero
+	// disables it
+	MaxResponseBytes int64 `yaml:"max-response-bytes" mapstructure:"max-response-bytes"`
+	// DrainTimeout bounds the graceful shutdown of the service, the requests and background work still in
+	// progress when it expires are cancelled
+	DrainTimeout time.Duration `yaml:"drain-timeout" mapstructure:"drain-timeout"`
 }
 
 type ServiceConfig struct {
 	Username string `yaml:"service-username" mapstructure:"service-username"`
 	Password string `yaml:"service-password" mapstructure:"service-password"`
 }
+
+// RouteAuthConfig selects the authentication of a route group supporting client certificates: "jwt" (default),
+// "mtls" or "jwt+mtls". The client certificates must be issued by the CMS root CA.
+type RouteAuthConfig struct {
+	Mode               string   `yaml:"mode" mapstructure:"mode"`
+	AllowedCommonNames []string `yaml:"allowed-common-names" mapstructure:"allowed-common-names"`
+}
+
+// OIDCConfig accepts the bearer tokens issued by an OpenID Connect provider in addition to the AAS tokens. The
+// tokens must be issued by Issuer for Audience, ScopePermissions grants the permission rules of the service to the
+// scopes of the tokens, e.g. {"kbs.transfer": ["keys:transfer"]}. The OIDC tokens are disabled when Issuer is empty.
+type OIDCConfig struct {
+	Issuer           string              `yaml:"issuer" mapstructure:"issuer"`
+	Audience         string              `yaml:"audience" mapstructure:"audience"`
+	ScopePermissions map[string][]string `yaml:"scope-permissions" mapstructure:"scope-permissions"`
+	// JWKSCacheTime is the time the signing keys of the provider are cached before being downloaded again
+	JWKSCacheTime time.Duration `yaml:"jwks-cache-time" mapstructure:"jwks-cache-time"`
+}
+
+// ProxyConfig selects the proxy the requests to a server are sent through. HTTPProxy and HTTPSProxy are used for the
+// http and https URLs except for the servers of the NoProxy list, with the syntax of the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables. HostProxies override them per server, keyed by host name, domain suffix such as
+// ".lab.example.com" or CIDR, with a http://, https:// or socks5:// proxy URL or "direct".
+type ProxyConfig struct {
+	HTTPProxy   string            `yaml:"http-proxy" mapstructure:"http-proxy"`
+	HTTPSProxy  string            `yaml:"https-proxy" mapstructure:"https-proxy"`
+	NoProxy     string            `yaml:"no-proxy" mapstructure:"no-proxy"`
+	HostProxies map[string]string `yaml:"host-proxies" mapstructure:"host-proxies"`
+}
@@ -12,4 +12,8 @@ const (
 	HTTPMediaTypeSaml        = "application/samlassertion+xml"
 	HTTPMediaTypePemFile     = "application/x-pem-file"
 	HTTPMediaTypeOctetStream = "application/octet-stream"
+	HTTPMediaTypeCsv         = "text/csv"
+	HTTPMediaTypeJose        = "application/jose"
+	HTTPMediaTypeMergePatch  = "application/merge-patch+json"
+	HTTPMediaTypeProblemJson = "application/problem+json"
 )
@@ -16,6 +16,7 @@ const (
 	UserRoles       = "userroles"
 	UserPermissions = "userpermissions"
 	TokenSubject    = "tokensubject"
+	CorrelationID   = "correlationid"
 )
 
 func SetUserRoles(r *http.Request, val []types.RoleInfo) *http.Request {
@@ -62,3 +63,18 @@ func GetTokenSubject(r *http.Request) (string, error) {
 	}
 	return "", fmt.Errorf("could not retrieve token subject from context")
 }
+
+func SetCorrelationID(r *http.Request, val string) *http.Request {
+
+	ctx := context.WithValue(r.Context(), CorrelationID, val)
+	return r.WithContext(ctx)
+}
+
+func GetCorrelationID(r *http.Request) (string, error) {
+	if rv := r.Context().Value(CorrelationID); rv != nil {
+		if id, ok := rv.(string); ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("could not retrieve correlation id from context")
+}
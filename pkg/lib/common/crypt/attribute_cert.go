@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Attribute is an attribute of an attribute certificate, Value is its DER encoding
+type Attribute struct {
+	Type  asn1.ObjectIdentifier
+	Value []byte
+}
+
+// AttributeCertificateTemplate describes the attribute certificate binding the attributes to their holder
+type AttributeCertificateTemplate struct {
+	// SerialNumber is generated randomly when it is not set
+	SerialNumber *big.Int
+	// Holder is the common name of the subject of the certificate, e.g. the hardware UUID of a host
+	Holder     string
+	Attributes []Attribute
+	NotBefore  time.Time
+	NotAfter   time.Time
+}
+
+// CreateAttributeCertificate returns the DER encoding of an attribute certificate signed by the issuer. The
+// certificate is encoded as an X.509 v3 certificate holding one non critical extension per attribute and the public
+// key of its issuer, the encoding the trust agents and the verifiers of the asset tags expect.
+func CreateAttributeCertificate(template AttributeCertificateTemplate, issuer *x509.Certificate, issuerKey crypto.PrivateKey) ([]byte, error) {
+	if issuer == nil || issuerKey == nil {
+		return nil, errors.New("The issuer certificate and key must be provided to create an attribute certificate")
+	}
+	if template.Holder == "" || len(template.Attributes) == 0 {
+		return nil, errors.New("The holder and attributes must be provided to create an attribute certificate")
+	}
+	if !template.NotBefore.Before(template.NotAfter) {
+		return nil, errors.New("The validity period of the attribute certificate must end after it starts")
+	}
+
+	var signatureAlgorithm x509.SignatureAlgorithm
+	switch issuerKey.(type) {
+	case *rsa.PrivateKey:
+		signatureAlgorithm = x509.SHA384WithRSA
+	case *ecdsa.PrivateKey:
+		signatureAlgorithm = x509.ECDSAWithSHA384
+	default:
+		return nil, errors.Errorf("Unsupported attribute certificate issuer key type %T", issuerKey)
+	}
+
+	serialNumber := template.SerialNumber
+	if serialNumber == nil {
+		var err error
+		serialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the serial number of the attribute certificate")
+		}
+	}
+
+	var extensions []pkix.Extension
+	for _, attribute := range template.Attributes {
+		extensions = append(extensions, pkix.Extension{
+			Id:       attribute.Type,
+			Critical: false,
+			Value:    attribute.Value,
+		})
+	}
+
+	certificateTemplate := &x509.Certificate{
+		SerialNumber:       serialNumber,
+		Subject:            pkix.Name{CommonName: template.Holder},
+		ExtraExtensions:    extensions,
+		SignatureAlgorithm: signatureAlgorithm,
+		NotBefore:          template.NotBefore,
+		NotAfter:           template.NotAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, certificateTemplate, issuer, issuer.PublicKey, issuerKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create the attribute certificate")
+	}
+	return der, nil
+}
+
+// AttributeCertificateAttributes returns the attributes of the type of an attribute certificate
+func AttributeCertificateAttributes(cert *x509.Certificate, attributeType asn1.ObjectIdentifier) []Attribute {
+	var attributes []Attribute
+	for _, extension := range cert.Extensions {
+		if extension.Id.Equal(attributeType) {
+			attributes = append(attributes, Attribute{Type: extension.Id, Value: extension.Value})
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAttributeCertificate(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Tag CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDer)
+	assert.NoError(t, err)
+
+	attributeType := asn1.ObjectIdentifier{2, 5, 4, 789, 1}
+	value, err := asn1.Marshal("Location=SantaClara")
+	assert.NoError(t, err)
+
+	der, err := CreateAttributeCertificate(AttributeCertificateTemplate{
+		Holder:     "80ecce40-04b8-e811-906e-00163566263e",
+		Attributes: []Attribute{{Type: attributeType, Value: value}},
+		NotBefore:  time.Now(),
+		NotAfter:   time.Now().Add(time.Hour),
+	}, caCert, caKey)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	assert.NoError(t, cert.CheckSignatureFrom(caCert))
+	assert.Equal(t, "80ecce40-04b8-e811-906e-00163566263e", cert.Subject.CommonName)
+	assert.Equal(t, "Tag CA", cert.Issuer.CommonName)
+	// the certificate carries the public key of its issuer
+	assert.Equal(t, caCert.RawSubjectPublicKeyInfo, cert.RawSubjectPublicKeyInfo)
+
+	attributes := AttributeCertificateAttributes(cert, attributeType)
+	assert.Equal(t, 1, len(attributes))
+	assert.Equal(t, value, attributes[0].Value)
+
+	_, err = CreateAttributeCertificate(AttributeCertificateTemplate{Holder: "host"}, caCert, caKey)
+	assert.Error(t, err)
+}
@@ -72,12 +72,15 @@ func GetHashingAlgorithmName(alg crypto.Hash) string {
 
 // GetHash returns a byte array to the hash of the data.
 // alg indicates the hashing algorithm. Currently, the only supported hashing algorithms
-// are SHA1, SHA256, SHA384 and SHA512
+// are SHA1, SHA256, SHA384 and SHA512, SHA1 is rejected in FIPS mode
 func GetHashData(data []byte, alg crypto.Hash) ([]byte, error) {
 
 	if data == nil {
 		return nil, fmt.Errorf("Error - data pointer is nil")
 	}
+	if err := CheckFipsApproved(alg); err != nil {
+		return nil, err
+	}
 
 	switch alg {
 	case crypto.SHA1:
@@ -175,7 +178,7 @@ func GetCertHexSha384(filePath string) (string, error) {
 	}
 	cert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse certificate: " + err.Error())
+		return "", fmt.Errorf("failed to parse certificate: %s", err.Error())
 	}
 	hash, _ := GetHashData(cert.Raw, crypto.SHA384)
 
@@ -205,9 +208,9 @@ func RetrieveValidatedPeerCert(baseUrl string, trustFirstCert bool, trustedThumb
 	dialString = url_obj.Hostname() + dialString
 
 	//InsecureSkipVerify is set to true as connection is validated manually
-	conn, err := tls.Dial("tcp", dialString, &tls.Config{
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: true})
+	tlsConfig := TLSConfig()
+	tlsConfig.InsecureSkipVerify = true
+	conn, err := tls.Dial("tcp", dialString, tlsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("could not tcp connect to %s, error: %s: ", dialString, err)
 	}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"hash"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// fileHashBufferSize is the size of the reads of the files being hashed
+	fileHashBufferSize = 1024 * 1024
+
+	// DefaultMerkleChunkSize is the size of the leaves of the Merkle trees, the chunk size of the last leaf can be
+	// smaller
+	DefaultMerkleChunkSize = 4096
+
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// HashReader returns the digest of the content read from the reader, the content is hashed as it is read
+func HashReader(reader io.Reader, algorithm DigestAlgorithm) ([]byte, error) {
+	hasher, err := NewHasher(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.CopyBuffer(hasher, reader, make([]byte, fileHashBufferSize)); err != nil {
+		return nil, errors.Wrap(err, "Error reading the content to hash")
+	}
+	return hasher.Sum(nil), nil
+}
+
+// HashFile returns the digest of the file, the file is hashed as it is read instead of being loaded in memory
+func HashFile(path string, algorithm DigestAlgorithm) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening file %s", path)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	digest, err := HashReader(file, algorithm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error hashing file %s", path)
+	}
+	return digest, nil
+}
+
+// MerkleHasher computes the root of the Merkle tree of the content written to it, as defined by RFC 6962: the
+// content is split in chunks of the chunk size, the leaves are hash(0x00 || chunk) and the nodes are
+// hash(0x01 || left || right). Only the roots of the complete subtrees are kept, so the content is hashed in a
+// single pass with memory growing with the logarithm of its size.
+type MerkleHasher struct {
+	chunkSize int
+	hasher    hash.Hash
+	chunk     []byte
+	// subtrees are the roots of the complete subtrees, from the largest to the smallest, with their leaf counts
+	subtrees [][]byte
+	sizes    []int
+	leaves   int
+}
+
+// NewMerkleHasher returns a MerkleHasher of the DigestAlgorithm splitting the content in chunks of chunkSize bytes
+func NewMerkleHasher(algorithm DigestAlgorithm, chunkSize int) (*MerkleHasher, error) {
+	if chunkSize <= 0 {
+		return nil, errors.Errorf("Invalid Merkle tree chunk size %d", chunkSize)
+	}
+	hasher, err := NewHasher(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleHasher{
+		chunkSize: chunkSize,
+		hasher:    hasher,
+		chunk:     make([]byte, 0, chunkSize),
+	}, nil
+}
+
+// Write adds the content to the tree, it never returns an error
+func (m *MerkleHasher) Write(content []byte) (int, error) {
+	written := len(content)
+	for len(content) > 0 {
+		n := m.chunkSize - len(m.chunk)
+		if n > len(content) {
+			n = len(content)
+		}
+		m.chunk = append(m.chunk, content[:n]...)
+		content = content[n:]
+		if len(m.chunk) == m.chunkSize {
+			m.addLeaf()
+		}
+	}
+	return written, nil
+}
+
+// Leaves returns the number of leaves of the tree, including the chunk being filled
+func (m *MerkleHasher) Leaves() int {
+	if len(m.chunk) > 0 {
+		return m.leaves + 1
+	}
+	return m.leaves
+}
+
+// Sum returns the root of the tree of the content written so far, the root of an empty content is the hash of
+// nothing. More content can be written afterwards.
+func (m *MerkleHasher) Sum() []byte {
+	if m.Leaves() == 0 {
+		m.hasher.Reset()
+		return m.hasher.Sum(nil)
+	}
+
+	var root []byte
+	if len(m.chunk) > 0 {
+		root = m.leafHash(m.chunk)
+	}
+	for i := len(m.subtrees) - 1; i >= 0; i-- {
+		if root == nil {
+			root = m.subtrees[i]
+		} else {
+			root = m.nodeHash(m.subtrees[i], root)
+		}
+	}
+	return root
+}
+
+// addLeaf hashes the full chunk as a leaf and merges the complete subtrees of the same size
+func (m *MerkleHasher) addLeaf() {
+	node, size := m.leafHash(m.chunk), 1
+	m.chunk = m.chunk[:0]
+	m.leaves++
+	for len(m.sizes) > 0 && m.sizes[len(m.sizes)-1] == size {
+		last := len(m.subtrees) - 1
+		node, size = m.nodeHash(m.subtrees[last], node), size*2
+		m.subtrees, m.sizes = m.subtrees[:last], m.sizes[:last]
+	}
+	m.subtrees = append(m.subtrees, node)
+	m.sizes = append(m.sizes, size)
+}
+
+func (m *MerkleHasher) leafHash(chunk []byte) []byte {
+	m.hasher.Reset()
+	// hash.Hash writes never return an error
+	_, _ = m.hasher.Write([]byte{merkleLeafPrefix})
+	_, _ = m.hasher.Write(chunk)
+	return m.hasher.Sum(nil)
+}
+
+func (m *MerkleHasher) nodeHash(left, right []byte) []byte {
+	m.hasher.Reset()
+	_, _ = m.hasher.Write([]byte{merkleNodePrefix})
+	_, _ = m.hasher.Write(left)
+	_, _ = m.hasher.Write(right)
+	return m.hasher.Sum(nil)
+}
+
+// MerkleDigest returns the root of the Merkle tree of the content read from the reader, split in chunks of
+// chunkSize bytes
+func MerkleDigest(reader io.Reader, algorithm DigestAlgorithm, chunkSize int) ([]byte, error) {
+	merkleHasher, err := NewMerkleHasher(algorithm, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.CopyBuffer(merkleHasher, reader, make([]byte, fileHashBufferSize)); err != nil {
+		return nil, errors.Wrap(err, "Error reading the content to hash")
+	}
+	return merkleHasher.Sum(), nil
+}
+
+// MerkleFileDigest returns the root of the Merkle tree of the file, split in chunks of chunkSize bytes. The file is
+// hashed as it is read instead of being loaded in memory.
+func MerkleFileDigest(path string, algorithm DigestAlgorithm, chunkSize int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening file %s", path)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	digest, err := MerkleDigest(file, algorithm, chunkSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error hashing file %s", path)
+	}
+	return digest, nil
+}
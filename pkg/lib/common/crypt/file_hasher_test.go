@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// merkleRootNaive is the recursive definition of the Merkle tree hash of RFC 6962
+func merkleRootNaive(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		digest := sha256.Sum256(nil)
+		return digest[:]
+	case 1:
+		digest := sha256.Sum256(append([]byte{0x00}, leaves[0]...))
+		return digest[:]
+	}
+	split := 1
+	for split*2 < len(leaves) {
+		split *= 2
+	}
+	node := append([]byte{0x01}, merkleRootNaive(leaves[:split])...)
+	node = append(node, merkleRootNaive(leaves[split:])...)
+	digest := sha256.Sum256(node)
+	return digest[:]
+}
+
+func chunks(content []byte, chunkSize int) [][]byte {
+	var leaves [][]byte
+	for len(content) > 0 {
+		n := chunkSize
+		if n > len(content) {
+			n = len(content)
+		}
+		leaves = append(leaves, content[:n])
+		content = content[n:]
+	}
+	return leaves
+}
+
+func TestMerkleHasher(t *testing.T) {
+	content := make([]byte, 100*64+17)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	for _, size := range []int{0, 1, 63, 64, 65, 128, 192, 7 * 64, 8 * 64, 9*64 + 3, len(content)} {
+		expected := merkleRootNaive(chunks(content[:size], 64))
+
+		digest, err := MerkleDigest(bytes.NewReader(content[:size]), SHA256(), 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(digest, expected) {
+			t.Errorf("Merkle root of %d bytes does not match the recursive definition", size)
+		}
+
+		// the content written in pieces unaligned with the chunks has the same root
+		merkleHasher, err := NewMerkleHasher(SHA256(), 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for offset := 0; offset < size; offset += 37 {
+			end := offset + 37
+			if end > size {
+				end = size
+			}
+			merkleHasher.Write(content[offset:end])
+		}
+		if !bytes.Equal(merkleHasher.Sum(), expected) {
+			t.Errorf("Merkle root of %d bytes written in pieces does not match the recursive definition", size)
+		}
+		if merkleHasher.Leaves() != len(chunks(content[:size], 64)) {
+			t.Errorf("Merkle tree of %d bytes has %d leaves", size, merkleHasher.Leaves())
+		}
+	}
+
+	if _, err := NewMerkleHasher(SHA256(), 0); err == nil {
+		t.Error("Merkle hasher with an empty chunk size was created")
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-hasher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := make([]byte, 3*fileHashBufferSize+5)
+	rand.New(rand.NewSource(2)).Read(content)
+	path := filepath.Join(dir, "image.qcow2")
+	if err = ioutil.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := HashFile(path, SHA384())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := sha512.Sum384(content)
+	if !bytes.Equal(digest, expected[:]) {
+		t.Error("Streamed digest of the file does not match its digest")
+	}
+
+	merkleDigest, err := MerkleFileDigest(path, SHA256(), DefaultMerkleChunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(merkleDigest, merkleRootNaive(chunks(content, DefaultMerkleChunkSize))) {
+		t.Error("Merkle root of the file does not match the recursive definition")
+	}
+
+	if _, err = HashFile(filepath.Join(dir, "missing"), SHA384()); err == nil {
+		t.Error("Digest of a missing file was returned")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"crypto/tls"
+	"hash"
+
+	"github.com/pkg/errors"
+)
+
+// fipsMode restricts the package to the FIPS 140-2 approved algorithms. It is always on in the binaries built with
+// the fips tag, the other binaries enable it with the fips-mode setting of the services.
+var fipsMode = fipsBuild
+
+// ErrNotFipsApproved is returned when an algorithm which is not FIPS approved is requested in FIPS mode
+var ErrNotFipsApproved = errors.New("The algorithm is not FIPS approved")
+
+// FipsCipherSuites are the TLS 1.2 cipher suites negotiated by the services and their clients, they are all
+// FIPS approved
+var FipsCipherSuites = []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+
+// SetFipsMode enables or disables the FIPS mode, it can not be disabled in the binaries built with the fips tag
+func SetFipsMode(enabled bool) {
+	fipsMode = fipsBuild || enabled
+}
+
+// FipsModeEnabled returns true when the package is restricted to the FIPS approved algorithms
+func FipsModeEnabled() bool {
+	return fipsMode
+}
+
+// CheckFipsApproved returns ErrNotFipsApproved for the MD5 and SHA1 hashing algorithms in FIPS mode
+func CheckFipsApproved(alg crypto.Hash) error {
+	if fipsMode && (alg == crypto.MD5 || alg == crypto.SHA1) {
+		return errors.Wrapf(ErrNotFipsApproved, "%s can not be used in FIPS mode", alg.String())
+	}
+	return nil
+}
+
+// NewHash returns a new hash of the algorithm, MD5 and SHA1 are rejected in FIPS mode
+func NewHash(alg crypto.Hash) (hash.Hash, error) {
+	if err := CheckFipsApproved(alg); err != nil {
+		return nil, err
+	}
+	if !alg.Available() {
+		return nil, errors.Errorf("Unsupported hashing algorithm %s", alg.String())
+	}
+	return alg.New(), nil
+}
+
+// TLSConfig returns the TLS configuration of the servers and clients: TLS 1.2 or later with the FIPS approved
+// cipher suites, and the NIST curves only in FIPS mode
+func TLSConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		CipherSuites: FipsCipherSuites,
+	}
+	if fipsMode {
+		config.CurvePreferences = []tls.CurveID{tls.CurveP384, tls.CurveP256}
+	}
+	return config
+}
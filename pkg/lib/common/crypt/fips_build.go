@@ -0,0 +1,10 @@
+//go:build fips
+// +build fips
+
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+const fipsBuild = true
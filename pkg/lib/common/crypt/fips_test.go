@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"crypto/tls"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFipsMode(t *testing.T) {
+	defer SetFipsMode(false)
+
+	SetFipsMode(false)
+	assert.Equal(t, fipsBuild, FipsModeEnabled())
+	if !fipsBuild {
+		_, err := GetHashData([]byte("data"), crypto.SHA1)
+		assert.NoError(t, err)
+		_, err = NewHash(crypto.MD5)
+		assert.NoError(t, err)
+	}
+
+	SetFipsMode(true)
+	assert.True(t, FipsModeEnabled())
+	_, err := GetHashData([]byte("data"), crypto.SHA1)
+	assert.True(t, errors.Is(err, ErrNotFipsApproved))
+	_, err = NewHash(crypto.MD5)
+	assert.True(t, errors.Is(err, ErrNotFipsApproved))
+	_, err = GetHashData([]byte("data"), crypto.SHA384)
+	assert.NoError(t, err)
+	h, err := NewHash(crypto.SHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, 32, h.Size())
+}
+
+func TestTLSConfig(t *testing.T) {
+	defer SetFipsMode(false)
+
+	SetFipsMode(true)
+	config := TLSConfig()
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, FipsCipherSuites, config.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.CurveP384, tls.CurveP256}, config.CurvePreferences)
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	gcmSivNonceSize = 12
+	gcmSivTagSize   = 16
+	// gcmSivMaxPlaintextSize is the RFC 8452 limit of the plaintext and additional data lengths
+	gcmSivMaxPlaintextSize = 1 << 36
+)
+
+// gcmSiv implements RFC 8452 AES-GCM-SIV, a nonce misuse-resistant AEAD: repeating a nonce only reveals whether
+// the same message was encrypted twice
+type gcmSiv struct {
+	block  cipher.Block
+	keyLen int
+}
+
+// NewGCMSIV returns the AES-GCM-SIV AEAD of a 16 or 32 bytes key, its nonces are 12 bytes and its tags 16 bytes
+func NewGCMSIV(key []byte) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, errors.New("AES-GCM-SIV keys must be 16 or 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize the AES cipher")
+	}
+	return &gcmSiv{block: block, keyLen: len(key)}, nil
+}
+
+func (g *gcmSiv) NonceSize() int {
+	return gcmSivNonceSize
+}
+
+func (g *gcmSiv) Overhead() int {
+	return gcmSivTagSize
+}
+
+func (g *gcmSiv) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != gcmSivNonceSize {
+		panic("crypt: incorrect nonce length given to AES-GCM-SIV")
+	}
+	if uint64(len(plaintext)) > gcmSivMaxPlaintextSize || uint64(len(additionalData)) > gcmSivMaxPlaintextSize {
+		panic("crypt: message too large for AES-GCM-SIV")
+	}
+
+	authKey, encBlock := g.deriveKeys(nonce)
+	tag := g.tag(authKey, encBlock, nonce, plaintext, additionalData)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmSivTagSize)
+	gcmSivCtr(encBlock, tag, out[:len(plaintext)], plaintext)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+func (g *gcmSiv) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != gcmSivNonceSize {
+		return nil, errors.New("crypt: incorrect nonce length given to AES-GCM-SIV")
+	}
+	if len(ciphertext) < gcmSivTagSize || uint64(len(ciphertext)-gcmSivTagSize) > gcmSivMaxPlaintextSize ||
+		uint64(len(additionalData)) > gcmSivMaxPlaintextSize {
+		return nil, errors.New("crypt: message authentication failed")
+	}
+
+	authKey, encBlock := g.deriveKeys(nonce)
+	var expectedTag [gcmSivTagSize]byte
+	copy(expectedTag[:], ciphertext[len(ciphertext)-gcmSivTagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-gcmSivTagSize]
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	gcmSivCtr(encBlock, expectedTag, out, ciphertext)
+
+	tag := g.tag(authKey, encBlock, nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(tag[:], expectedTag[:]) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+		return nil, errors.New("crypt: message authentication failed")
+	}
+	return ret, nil
+}
+
+// deriveKeys derives the message authentication key and the message encryption key of the nonce
+func (g *gcmSiv) deriveKeys(nonce []byte) ([16]byte, cipher.Block) {
+	var input, output [16]byte
+	copy(input[4:], nonce)
+
+	derived := make([]byte, 0, 16+g.keyLen)
+	for counter := uint32(0); len(derived) < 16+g.keyLen; counter++ {
+		binary.LittleEndian.PutUint32(input[:4], counter)
+		g.block.Encrypt(output[:], input[:])
+		derived = append(derived, output[:8]...)
+	}
+
+	var authKey [16]byte
+	copy(authKey[:], derived[:16])
+	// the derived key has a valid length, the cipher can not fail
+	encBlock, _ := aes.NewCipher(derived[16:])
+	return authKey, encBlock
+}
+
+func (g *gcmSiv) tag(authKey [16]byte, encBlock cipher.Block, nonce, plaintext, additionalData []byte) [16]byte {
+	var p polyval
+	p.init(authKey)
+	p.update(additionalData)
+	p.update(plaintext)
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)
+	p.update(lengths[:])
+
+	s := p.sum()
+	for i := range nonce {
+		s[i] ^= nonce[i]
+	}
+	s[15] &= 0x7f
+
+	var tag [16]byte
+	encBlock.Encrypt(tag[:], s[:])
+	return tag
+}
+
+// gcmSivCtr encrypts in with the AES-GCM-SIV counter mode, whose initial counter is the tag with the most
+// significant bit set and whose first 32 bits are incremented as a little-endian integer
+func gcmSivCtr(encBlock cipher.Block, tag [16]byte, out, in []byte) {
+	counter := tag
+	counter[15] |= 0x80
+	var keyStream [16]byte
+	for len(in) > 0 {
+		encBlock.Encrypt(keyStream[:], counter[:])
+		binary.LittleEndian.PutUint32(counter[:4], binary.LittleEndian.Uint32(counter[:4])+1)
+
+		n := len(in)
+		if n > 16 {
+			n = 16
+		}
+		for i := 0; i < n; i++ {
+			out[i] = in[i] ^ keyStream[i]
+		}
+		out, in = out[n:], in[n:]
+	}
+}
+
+// polyval computes the RFC 8452 POLYVAL universal hash. The field elements are little-endian, lo holds the
+// coefficients of x^0 to x^63 and hi those of x^64 to x^127.
+type polyval struct {
+	hLo, hHi uint64
+	sLo, sHi uint64
+}
+
+func (p *polyval) init(key [16]byte) {
+	p.hLo = binary.LittleEndian.Uint64(key[:8])
+	p.hHi = binary.LittleEndian.Uint64(key[8:])
+	p.sLo, p.sHi = 0, 0
+}
+
+// update hashes the data, zero padded to a multiple of the block size
+func (p *polyval) update(data []byte) {
+	var block [16]byte
+	for len(data) > 0 {
+		n := copy(block[:], data)
+		for i := n; i < 16; i++ {
+			block[i] = 0
+		}
+		data = data[n:]
+
+		p.sLo ^= binary.LittleEndian.Uint64(block[:8])
+		p.sHi ^= binary.LittleEndian.Uint64(block[8:])
+		p.sLo, p.sHi = polyvalDot(p.sLo, p.sHi, p.hLo, p.hHi)
+	}
+}
+
+func (p *polyval) sum() [16]byte {
+	var s [16]byte
+	binary.LittleEndian.PutUint64(s[:8], p.sLo)
+	binary.LittleEndian.PutUint64(s[8:], p.sHi)
+	return s
+}
+
+// polyvalDot returns a * b * x^-128 in GF(2^128) modulo x^128 + x^127 + x^126 + x^121 + 1
+func polyvalDot(aLo, aHi, bLo, bHi uint64) (uint64, uint64) {
+	// the secret bits are turned into all-ones or all-zeros masks instead of being branched on, so that
+	// the running time does not depend on the key or the data
+	var rLo, rHi uint64
+	for i := 127; i >= 0; i-- {
+		// r = r * x
+		carry := -(rHi >> 63)
+		rHi = rHi<<1 | rLo>>63
+		rLo <<= 1
+		rHi ^= 0xc200000000000000 & carry
+		rLo ^= 1 & carry
+
+		// the loop index is public, only the selected bit is secret
+		word := bLo
+		shift := uint(i)
+		if i >= 64 {
+			word = bHi
+			shift = uint(i - 64)
+		}
+		bit := -(word >> shift & 1)
+		rLo ^= aLo & bit
+		rHi ^= aHi & bit
+	}
+
+	// r = r * x^-128
+	for i := 0; i < 128; i++ {
+		odd := -(rLo & 1)
+		rLo = rLo>>1 | rHi<<63
+		rHi >>= 1
+		rHi ^= 0xe100000000000000 & odd
+	}
+	return rLo, rHi
+}
+
+// SealGCMSIV encrypts the plaintext with AES-GCM-SIV and a random nonce, the nonce is prepended to the
+// ciphertext. It is meant for the payloads whose nonces could be repeated, like payloads wrapped by several
+// replicas with the same key.
+func SealGCMSIV(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := NewGCMSIV(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate the nonce")
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// OpenGCMSIV decrypts a payload sealed by SealGCMSIV
+func OpenGCMSIV(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := NewGCMSIV(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("The sealed payload is too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
+
+// sliceForAppend extends in by n bytes, it returns the extended slice and the n bytes tail
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolyval(t *testing.T) {
+	// RFC 8452 Appendix A
+	h, _ := hex.DecodeString("25629347589242761d31f826ba4b757b")
+	x, _ := hex.DecodeString("4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362")
+
+	var key [16]byte
+	copy(key[:], h)
+	var p polyval
+	p.init(key)
+	p.update(x)
+	sum := p.sum()
+	assert.Equal(t, "f7a3b47b846119fae5b7866cf5e5b77e", hex.EncodeToString(sum[:]))
+}
+
+func TestCipherSelfTest(t *testing.T) {
+	assert.NoError(t, CipherSelfTest())
+}
+
+func TestGCMSIVRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	key[0] = 1
+	aead, err := NewGCMSIV(key)
+	assert.NoError(t, err)
+
+	nonce := make([]byte, aead.NonceSize())
+	// lengths around the block size and a counter wrapping its first 32 bits
+	for _, length := range []int{0, 1, 15, 16, 17, 64, 1000} {
+		plaintext := make([]byte, length)
+		for i := range plaintext {
+			plaintext[i] = byte(i)
+		}
+		binary.LittleEndian.PutUint32(nonce, uint32(length))
+
+		ciphertext := aead.Seal(nil, nonce, plaintext, []byte("aad"))
+		assert.Equal(t, length+aead.Overhead(), len(ciphertext))
+
+		decrypted, err := aead.Open(nil, nonce, ciphertext, []byte("aad"))
+		assert.NoError(t, err)
+		assert.True(t, bytes.Equal(plaintext, decrypted))
+
+		_, err = aead.Open(nil, nonce, ciphertext, []byte("other aad"))
+		assert.Error(t, err)
+		ciphertext[0] ^= 1
+		_, err = aead.Open(nil, nonce, ciphertext, []byte("aad"))
+		assert.Error(t, err)
+	}
+}
+
+func TestSealOpenGCMSIV(t *testing.T) {
+	key := make([]byte, 16)
+	sealed, err := SealGCMSIV(key, []byte("volume key"), nil)
+	assert.NoError(t, err)
+
+	plaintext, err := OpenGCMSIV(key, sealed, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("volume key"), plaintext)
+
+	_, err = NewGCMSIV(make([]byte, 24))
+	assert.Error(t, err)
+}
+
+func TestXtsVolumeKey(t *testing.T) {
+	key, err := GenerateXtsVolumeKey(512)
+	assert.NoError(t, err)
+	assert.Equal(t, 64, len(key))
+
+	data := make([]byte, 2*XtsSectorSize)
+	ciphertext, err := EncryptXtsSectors(key, data, 7)
+	assert.NoError(t, err)
+	// each sector is encrypted with its own tweak
+	assert.NotEqual(t, ciphertext[:XtsSectorSize], ciphertext[XtsSectorSize:])
+
+	decrypted, err := DecryptXtsSectors(key, ciphertext, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, err = EncryptXtsSectors(key, data[:100], 0)
+	assert.Error(t, err)
+	_, err = NewXtsCipher(make([]byte, 64))
+	assert.Error(t, err)
+	_, err = GenerateXtsVolumeKey(128)
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bufio"
+	"crypto"
+	"hash"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// HashImplementation is the fastest hashing implementation available on the platform
+type HashImplementation string
+
+const (
+	// HashImplementationSHANI hashes with the SHA extensions of the CPU
+	HashImplementationSHANI HashImplementation = "sha-ni"
+	// HashImplementationAVX2 hashes with the AVX2 vector instructions
+	HashImplementationAVX2 HashImplementation = "avx2"
+	// HashImplementationGeneric hashes with the portable implementation
+	HashImplementationGeneric HashImplementation = "generic"
+)
+
+const cpuInfoFile = "/proc/cpuinfo"
+
+var (
+	hashImplementation     HashImplementation
+	hashImplementationOnce sync.Once
+)
+
+// GetHashImplementation returns the hashing implementation selected for the platform. The sha1 and sha256
+// implementations of the standard library already dispatch to the SHA-NI and AVX2 assembly when the CPU supports them,
+// the detected implementation tells which of the fast paths the hashers created by NewHasher are running on.
+func GetHashImplementation() HashImplementation {
+	hashImplementationOnce.Do(func() {
+		hashImplementation = detectHashImplementation(cpuInfoFile)
+	})
+	return hashImplementation
+}
+
+// detectHashImplementation reads the flags of the CPU from cpuInfo, platforms without it use the generic implementation
+func detectHashImplementation(cpuInfo string) HashImplementation {
+	if runtime.GOARCH != "amd64" {
+		return HashImplementationGeneric
+	}
+	file, err := os.Open(cpuInfo)
+	if err != nil {
+		return HashImplementationGeneric
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "flags") {
+			continue
+		}
+		flags := make(map[string]bool)
+		for _, flag := range strings.Fields(line[strings.Index(line, ":")+1:]) {
+			flags[flag] = true
+		}
+		if flags["sha_ni"] {
+			return HashImplementationSHANI
+		}
+		if flags["avx2"] {
+			return HashImplementationAVX2
+		}
+		break
+	}
+	return HashImplementationGeneric
+}
+
+// NewHasher returns a hash of the DigestAlgorithm, backed by the fastest implementation available on the platform
+func NewHasher(algorithm DigestAlgorithm) (hash.Hash, error) {
+	if !algorithm.Algorithm.Available() {
+		return nil, errors.Errorf("Hash algorithm %v is not available", algorithm.Algorithm)
+	}
+	return algorithm.Algorithm.New(), nil
+}
+
+// Extender computes the value of a PCR by extending measurements into it. The hash and the digest buffers are
+// reused between the extend operations, which keeps the replay of long event logs free of allocations.
+type Extender struct {
+	hasher hash.Hash
+	value  []byte
+	next   []byte
+}
+
+// NewExtender returns an Extender of the DigestAlgorithm starting from a PCR value of zeroes
+func NewExtender(algorithm DigestAlgorithm) (*Extender, error) {
+	hasher, err := NewHasher(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	size := algorithm.Algorithm.Size()
+	return &Extender{
+		hasher: hasher,
+		value:  make([]byte, size),
+		next:   make([]byte, 0, size),
+	}, nil
+}
+
+// Extend extends the measurement into the PCR value, value = hash(value || measurement)
+func (e *Extender) Extend(measurement []byte) {
+	e.hasher.Reset()
+	// hash.Hash writes never return an error
+	_, _ = e.hasher.Write(e.value)
+	_, _ = e.hasher.Write(measurement)
+	e.next = e.hasher.Sum(e.next[:0])
+	e.value, e.next = e.next, e.value
+}
+
+// ExtendAll extends the measurements into the PCR value in order
+func (e *Extender) ExtendAll(measurements [][]byte) {
+	for _, measurement := range measurements {
+		e.Extend(measurement)
+	}
+}
+
+// Value returns a copy of the current PCR value
+func (e *Extender) Value() []byte {
+	value := make([]byte, len(e.value))
+	copy(value, e.value)
+	return value
+}
+
+// Reset sets the PCR value back to zeroes
+func (e *Extender) Reset() {
+	for i := range e.value {
+		e.value[i] = 0
+	}
+}
+
+// ExtendAll returns the PCR value obtained by extending the measurements, in order, into a PCR of zeroes
+func ExtendAll(algorithm crypto.Hash, measurements [][]byte) ([]byte, error) {
+	extender, err := NewExtender(DigestAlgorithm{Algorithm: algorithm, Length: algorithm.Size()})
+	if err != nil {
+		return nil, err
+	}
+	extender.ExtendAll(measurements)
+	return extender.value, nil
+}
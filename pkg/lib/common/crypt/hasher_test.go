@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func testMeasurements(count int) [][]byte {
+	measurements := make([][]byte, count)
+	for i := range measurements {
+		measurement := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+		measurements[i] = measurement[:]
+	}
+	return measurements
+}
+
+// extendAllNaive is the replay implementation using a new hash for every event
+func extendAllNaive(measurements [][]byte) []byte {
+	value := make([]byte, sha256.Size)
+	for _, measurement := range measurements {
+		hash := sha256.New()
+		hash.Write(value)
+		hash.Write(measurement)
+		value = hash.Sum(nil)
+	}
+	return value
+}
+
+func TestExtender(t *testing.T) {
+	measurements := testMeasurements(100)
+
+	extender, err := NewExtender(SHA256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	extender.ExtendAll(measurements)
+	if !bytes.Equal(extender.Value(), extendAllNaive(measurements)) {
+		t.Error("Extended PCR value does not match the naive replay")
+	}
+
+	extender.Reset()
+	for _, measurement := range measurements[:10] {
+		extender.Extend(measurement)
+	}
+	if !bytes.Equal(extender.Value(), extendAllNaive(measurements[:10])) {
+		t.Error("Extended PCR value after reset does not match the naive replay")
+	}
+
+	value, err := ExtendAll(crypto.SHA256, measurements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, extendAllNaive(measurements)) {
+		t.Error("ExtendAll PCR value does not match the naive replay")
+	}
+
+	if _, err = NewExtender(DigestAlgorithm{Algorithm: crypto.BLAKE2b_256}); err == nil {
+		t.Error("Expected an error for an unavailable hash algorithm")
+	}
+}
+
+func TestDetectHashImplementation(t *testing.T) {
+	cpuInfo, err := ioutil.TempFile("", "cpuinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(cpuInfo.Name())
+
+	cpuInfo.WriteString("processor\t: 0\nflags\t\t: fpu sse2 avx2 sha_ni\n\nprocessor\t: 1\nflags\t\t: fpu sse2 avx2 sha_ni\n")
+	cpuInfo.Close()
+
+	implementation := detectHashImplementation(cpuInfo.Name())
+	if runtime.GOARCH == "amd64" && implementation != HashImplementationSHANI {
+		t.Errorf("Expected %s, detected %s", HashImplementationSHANI, implementation)
+	}
+	if detectHashImplementation(cpuInfo.Name()+".missing") != HashImplementationGeneric {
+		t.Error("Expected the generic implementation without cpu info")
+	}
+}
+
+func BenchmarkExtenderReplay(b *testing.B) {
+	measurements := testMeasurements(1000)
+	extender, _ := NewExtender(SHA256())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		extender.Reset()
+		extender.ExtendAll(measurements)
+	}
+}
+
+func BenchmarkNaiveReplay(b *testing.B) {
+	measurements := testMeasurements(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		extendAllNaive(measurements)
+	}
+}
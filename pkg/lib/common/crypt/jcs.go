@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// MarshalCanonicalJSON returns the JSON encoding of v canonicalized as defined by the JSON Canonicalization
+// Scheme (JCS, RFC 8785), the encoding to sign so that the signature can be verified by any JCS implementation
+func MarshalCanonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal JSON")
+	}
+	return CanonicalizeJSON(data)
+}
+
+// CanonicalizeJSON returns the JCS canonical form of the JSON document: the object members are sorted by the
+// UTF-16 code units of their names, the numbers are serialized like ECMAScript does and the strings are only
+// escaped where JSON requires it, with no whitespace between the tokens
+func CanonicalizeJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse JSON")
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("Unexpected data after the JSON document")
+	}
+
+	var buffer bytes.Buffer
+	if err := writeCanonicalJSON(&buffer, value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeCanonicalJSON(buffer *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("null")
+	case bool:
+		buffer.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		number, err := canonicalNumber(v)
+		if err != nil {
+			return err
+		}
+		buffer.WriteString(number)
+	case string:
+		writeCanonicalString(buffer, v)
+	case []interface{}:
+		buffer.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buffer, element); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return lessUTF16(names[i], names[j])
+		})
+		buffer.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeCanonicalString(buffer, name)
+			buffer.WriteByte(':')
+			if err := writeCanonicalJSON(buffer, v[name]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	default:
+		return errors.Errorf("Unexpected JSON value of type %T", value)
+	}
+	return nil
+}
+
+// canonicalNumber serializes the number as an IEEE 754 double the way ECMAScript Number.prototype.toString does
+func canonicalNumber(number json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(number), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", errors.Errorf("The number %s cannot be represented as an IEEE 754 double", number)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+	format := byte('e')
+	if f >= 1e-6 && f < 1e21 {
+		format = 'f'
+	}
+	formatted := strconv.FormatFloat(f, format, -1, 64)
+	// ECMAScript writes the exponent without leading zeroes, 1e+21 rather than 1e+021
+	if exponent := strings.IndexByte(formatted, 'e'); exponent > 0 {
+		digits := strings.TrimLeft(formatted[exponent+2:], "0")
+		formatted = formatted[:exponent+2] + digits
+	}
+	return sign + formatted, nil
+}
+
+func writeCanonicalString(buffer *bytes.Buffer, s string) {
+	buffer.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buffer.WriteString(`\"`)
+		case '\\':
+			buffer.WriteString(`\\`)
+		case '\b':
+			buffer.WriteString(`\b`)
+		case '\f':
+			buffer.WriteString(`\f`)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buffer, `\u%04x`, r)
+			} else {
+				buffer.WriteRune(r)
+			}
+		}
+	}
+	buffer.WriteByte('"')
+}
+
+// lessUTF16 compares the strings by their UTF-16 code units, which orders the characters outside of the basic
+// multilingual plane differently than their UTF-8 bytes
+func lessUTF16(a, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	// the example of RFC 8785 section 3.2.2
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`
+	canonical, err := CanonicalizeJSON([]byte(input))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(canonical))
+
+	// the names are sorted by their UTF-16 code units, the emoji sorts before U+FB33
+	canonical, err = CanonicalizeJSON([]byte(`{"\ufb33":1,"\ud83d\ude00":2,"\u00e9":3,"a":4,"A":5}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"A\":5,\"a\":4,\"\u00e9\":3,\"\U0001f600\":2,\"\ufb33\":1}", string(canonical))
+
+	for number, expected := range map[string]string{
+		"0": "0", "-0": "0", "1": "1", "-1.5": "-1.5", "1e21": "1e+21", "1e20": "100000000000000000000",
+		"0.000001": "0.000001", "0.0000001": "1e-7", "9007199254740991": "9007199254740991",
+	} {
+		canonical, err = CanonicalizeJSON([]byte(number))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(canonical), number)
+	}
+
+	_, err = CanonicalizeJSON([]byte(`{"a":1} {"b":2}`))
+	assert.Error(t, err)
+	_, err = CanonicalizeJSON([]byte(`1e400`))
+	assert.Error(t, err)
+}
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	value := struct {
+		Zeta  string            `json:"zeta"`
+		Alpha map[string]string `json:"alpha"`
+	}{
+		Zeta:  "<tag> & more",
+		Alpha: map[string]string{"b": "2", "a": "1"},
+	}
+	canonical, err := MarshalCanonicalJSON(value)
+	assert.NoError(t, err)
+	// Go escapes the HTML characters which JCS does not
+	assert.Equal(t, `{"alpha":{"a":"1","b":"2"},"zeta":"<tag> & more"}`, string(canonical))
+
+	// the canonical form of the canonical form is the same
+	var decoded interface{}
+	assert.NoError(t, json.Unmarshal(canonical, &decoded))
+	again, err := MarshalCanonicalJSON(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, canonical, again)
+}
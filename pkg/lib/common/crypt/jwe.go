@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// JweAlgRsaOaep256 is the RFC 7518 key management algorithm of the JWE objects, RSAES-OAEP with SHA-256
+	JweAlgRsaOaep256 = "RSA-OAEP-256"
+	// JweEncA256Gcm is the RFC 7518 content encryption algorithm of the JWE objects, AES-256 GCM
+	JweEncA256Gcm = "A256GCM"
+
+	jweCekLength = 32
+	jweIvLength  = 12
+	jweTagLength = 16
+)
+
+// JweHeader is the JOSE protected header of a JWE object
+type JweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Cty string `json:"cty,omitempty"`
+}
+
+// EncryptJwe encrypts the plaintext to the public key as an RFC 7516 JWE object in compact serialization. The
+// content encryption key is wrapped with RSA-OAEP-256 and the plaintext is encrypted with A256GCM, so that the
+// object can be decrypted by off-the-shelf JOSE libraries.
+func EncryptJwe(plaintext []byte, publicKey *rsa.PublicKey, kid, cty string) (string, error) {
+	if publicKey == nil {
+		return "", errors.New("The public key must be provided")
+	}
+
+	header, err := json.Marshal(JweHeader{Alg: JweAlgRsaOaep256, Enc: JweEncA256Gcm, Kid: kid, Cty: cty})
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to marshal the JWE header")
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+
+	cek := make([]byte, jweCekLength)
+	if _, err = rand.Read(cek); err != nil {
+		return "", errors.Wrap(err, "Failed to generate the content encryption key")
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, cek, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to wrap the content encryption key")
+	}
+
+	gcm, err := newJweGcm(cek)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, jweIvLength)
+	if _, err = rand.Read(iv); err != nil {
+		return "", errors.Wrap(err, "Failed to generate the initialization vector")
+	}
+	// the additional authenticated data is the ASCII of the encoded protected header
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(encodedHeader))
+	ciphertext, tag := sealed[:len(sealed)-jweTagLength], sealed[len(sealed)-jweTagLength:]
+
+	return strings.Join([]string{
+		encodedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// DecryptJwe decrypts an RSA-OAEP-256 and A256GCM JWE object in compact serialization with the private key, it
+// returns the protected header and the plaintext
+func DecryptJwe(jwe string, privateKey *rsa.PrivateKey) (*JweHeader, []byte, error) {
+	if privateKey == nil {
+		return nil, nil, errors.New("The private key must be provided")
+	}
+
+	parts := strings.Split(jwe, ".")
+	if len(parts) != 5 {
+		return nil, nil, errors.New("The JWE object is not in compact serialization")
+	}
+	var decoded [5][]byte
+	for i, part := range parts {
+		var err error
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(part); err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to decode the JWE object")
+		}
+	}
+
+	var header JweHeader
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to unmarshal the JWE header")
+	}
+	if header.Alg != JweAlgRsaOaep256 || header.Enc != JweEncA256Gcm {
+		return nil, nil, errors.Errorf("Unsupported JWE algorithms %s and %s", header.Alg, header.Enc)
+	}
+
+	cek, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, decoded[1], nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to unwrap the content encryption key")
+	}
+	gcm, err := newJweGcm(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(decoded[2]) != jweIvLength || len(decoded[4]) != jweTagLength {
+		return nil, nil, errors.New("Invalid JWE initialization vector or authentication tag")
+	}
+	plaintext, err := gcm.Open(nil, decoded[2], append(decoded[3], decoded[4]...), []byte(parts[0]))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to decrypt the JWE content")
+	}
+	return &header, plaintext, nil
+}
+
+func newJweGcm(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != jweCekLength {
+		return nil, errors.New("Invalid content encryption key length")
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize the content cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize the content cipher")
+	}
+	return gcm, nil
+}
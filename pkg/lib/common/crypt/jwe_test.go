@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecryptJwe(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	jwe, err := EncryptJwe(secret, &privateKey.PublicKey, "ee37c360-7eae-4250-a677-6ee12adce8e2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(strings.Split(jwe, ".")))
+
+	header, plaintext, err := DecryptJwe(jwe, privateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, plaintext)
+	assert.Equal(t, JweAlgRsaOaep256, header.Alg)
+	assert.Equal(t, JweEncA256Gcm, header.Enc)
+	assert.Equal(t, "ee37c360-7eae-4250-a677-6ee12adce8e2", header.Kid)
+}
+
+func TestDecryptJweTampered(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	jwe, err := EncryptJwe([]byte("secret"), &privateKey.PublicKey, "", "")
+	assert.NoError(t, err)
+
+	// the protected header is authenticated
+	parts := strings.Split(jwe, ".")
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RSA-OAEP-256","enc":"A256GCM","kid":"other"}`))
+	_, _, err = DecryptJwe(strings.Join(parts, "."), privateKey)
+	assert.Error(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, _, err = DecryptJwe(jwe, otherKey)
+	assert.Error(t, err)
+
+	_, _, err = DecryptJwe("not.a.jwe", privateKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// SecretBuffer holds key material outside of the memory managed by the garbage collector, so that the key bytes are
+// not copied around by the runtime and do not linger after they are used. On linux the memory is locked so that it
+// is not written to swap. The key bytes are zeroed when the buffer is closed, the slices returned by Bytes must not be
+// used after that.
+type SecretBuffer struct {
+	mutex  sync.Mutex
+	data   []byte
+	locked bool
+}
+
+// NewSecretBuffer allocates a zeroed buffer of size bytes
+func NewSecretBuffer(size int) (*SecretBuffer, error) {
+	if size < 0 {
+		return nil, errors.New("crypt/secret_buffer:NewSecretBuffer() Invalid secret buffer size")
+	}
+	data, locked, err := allocSecretMemory(size)
+	if err != nil {
+		return nil, errors.Wrap(err, "crypt/secret_buffer:NewSecretBuffer() Failed to allocate secret memory")
+	}
+	return &SecretBuffer{data: data, locked: locked}, nil
+}
+
+// NewSecretBufferFrom moves the secret into a new buffer, the secret is zeroed
+func NewSecretBufferFrom(secret []byte) (*SecretBuffer, error) {
+	buffer, err := NewSecretBuffer(len(secret))
+	if err != nil {
+		return nil, err
+	}
+	copy(buffer.data, secret)
+	Zeroize(secret)
+	return buffer, nil
+}
+
+// NewRandomSecretBuffer allocates a buffer of size random bytes, e.g. for a new symmetric key
+func NewRandomSecretBuffer(size int) (*SecretBuffer, error) {
+	buffer, err := NewSecretBuffer(size)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, buffer.data); err != nil {
+		buffer.Close()
+		return nil, errors.Wrap(err, "crypt/secret_buffer:NewRandomSecretBuffer() Failed to read random bytes")
+	}
+	return buffer, nil
+}
+
+// Bytes returns the key bytes, nil once the buffer is closed
+func (b *SecretBuffer) Bytes() []byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.data
+}
+
+// Len returns the number of key bytes
+func (b *SecretBuffer) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.data)
+}
+
+// Locked tells if the memory of the buffer is locked, the lock fails when it exceeds RLIMIT_MEMLOCK
+func (b *SecretBuffer) Locked() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.locked
+}
+
+// Copy returns a copy of the key bytes in a new buffer, the key bytes are never copied to the memory managed by the
+// garbage collector
+func (b *SecretBuffer) Copy() (*SecretBuffer, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.data == nil {
+		return nil, errors.New("crypt/secret_buffer:Copy() The secret buffer is closed")
+	}
+	buffer, err := NewSecretBuffer(len(b.data))
+	if err != nil {
+		return nil, err
+	}
+	copy(buffer.data, b.data)
+	return buffer, nil
+}
+
+// Close zeroes the key bytes and releases the memory of the buffer, closing a closed buffer does nothing
+func (b *SecretBuffer) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.data == nil {
+		return nil
+	}
+	Zeroize(b.data)
+	err := freeSecretMemory(b.data, b.locked)
+	b.data = nil
+	b.locked = false
+	if err != nil {
+		return errors.Wrap(err, "crypt/secret_buffer:Close() Failed to release secret memory")
+	}
+	return nil
+}
+
+// Zeroize overwrites b with zeros
+func Zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,41 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"syscall"
+)
+
+// madvDontDump excludes the memory from core dumps, it is not defined by syscall on all the architectures
+const madvDontDump = 0x10
+
+// allocSecretMemory maps anonymous memory for the secret and locks it, the secret is kept unlocked when the lock
+// exceeds the RLIMIT_MEMLOCK of the process
+func allocSecretMemory(size int) ([]byte, bool, error) {
+	if size == 0 {
+		return []byte{}, false, nil
+	}
+	data, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, false, err
+	}
+	_ = syscall.Madvise(data, madvDontDump)
+	return data, syscall.Mlock(data) == nil, nil
+}
+
+func freeSecretMemory(data []byte, locked bool) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if locked {
+		if err := syscall.Munlock(data); err != nil {
+			return err
+		}
+	}
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+// allocSecretMemory allocates the secret in the memory managed by the garbage collector, the memory can not be locked
+// on this platform
+func allocSecretMemory(size int) ([]byte, bool, error) {
+	return make([]byte, size), false, nil
+}
+
+func freeSecretMemory([]byte, bool) error {
+	return nil
+}
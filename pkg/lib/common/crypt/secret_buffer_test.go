@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSecretBuffer(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	buffer, err := NewSecretBufferFrom(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secret, make([]byte, len(secret))) {
+		t.Error("the secret is not zeroed once moved to the buffer")
+	}
+	if buffer.Len() != len(secret) || !bytes.Equal(buffer.Bytes(), []byte("0123456789abcdef0123456789abcdef")) {
+		t.Error("the buffer does not hold the secret")
+	}
+
+	bufferCopy, err := buffer.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bufferCopy.Bytes(), buffer.Bytes()) {
+		t.Error("the copy does not hold the secret")
+	}
+
+	if err := buffer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.Bytes() != nil || buffer.Len() != 0 {
+		t.Error("the closed buffer still holds the secret")
+	}
+	if _, err := buffer.Copy(); err == nil {
+		t.Error("a closed buffer is copied")
+	}
+	// the copy is not closed with the buffer
+	if !bytes.Equal(bufferCopy.Bytes(), []byte("0123456789abcdef0123456789abcdef")) {
+		t.Error("the copy does not hold the secret anymore")
+	}
+	if err := bufferCopy.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bufferCopy.Close(); err != nil {
+		t.Error("closing a closed buffer failed")
+	}
+}
+
+func TestNewRandomSecretBuffer(t *testing.T) {
+	first, err := NewRandomSecretBuffer(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := NewRandomSecretBuffer(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	if first.Len() != 32 || bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("the buffers do not hold random bytes")
+	}
+
+	empty, err := NewSecretBuffer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.Len() != 0 || empty.Close() != nil {
+		t.Error("an empty buffer is not allocated")
+	}
+	if _, err := NewSecretBuffer(-1); err == nil {
+		t.Error("a buffer of negative size is allocated")
+	}
+}
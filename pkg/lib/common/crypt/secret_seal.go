@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// SealedSecretPrefix marks the configuration values holding a secret sealed to the local TPM, the rest of the value
+// is the base64 encoded sealed blob
+const SealedSecretPrefix = "tpm-sealed:"
+
+// maxPCRIndex is the highest PCR index of the PC client platform TPMs
+const maxPCRIndex = 23
+
+// SecretSealer seals secrets to the local TPM. The secret can only be unsealed on the same TPM and, when the secret is
+// sealed with a PCR policy, only while the selected PCRs have the values they had when the secret was sealed.
+type SecretSealer interface {
+	Seal(secret []byte, pcrs []int) ([]byte, error)
+	Unseal(blob []byte) ([]byte, error)
+}
+
+var (
+	secretSealer      SecretSealer
+	secretSealerMutex sync.RWMutex
+)
+
+// RegisterSecretSealer makes the sealer available to SealSecret and ResolveSecret. The TPM sealer is registered by
+// the file built with the tpm build tag.
+func RegisterSecretSealer(sealer SecretSealer) {
+	secretSealerMutex.Lock()
+	defer secretSealerMutex.Unlock()
+	secretSealer = sealer
+}
+
+func getSecretSealer() (SecretSealer, error) {
+	secretSealerMutex.RLock()
+	defer secretSealerMutex.RUnlock()
+	if secretSealer == nil {
+		return nil, errors.New("Sealing secrets to the TPM is not supported by this build")
+	}
+	return secretSealer, nil
+}
+
+// SealSecret seals the secret to the local TPM and returns the value to be stored in the configuration in place of
+// the secret. The secret is bound to the values of the PCRs, no PCR policy is set when pcrs is empty.
+func SealSecret(secret []byte, pcrs []int) (string, error) {
+	if len(secret) == 0 {
+		return "", errors.New("crypt/secret_seal:SealSecret() Secret is empty")
+	}
+	for _, pcr := range pcrs {
+		if pcr < 0 || pcr > maxPCRIndex {
+			return "", errors.Errorf("crypt/secret_seal:SealSecret() Invalid PCR index %d", pcr)
+		}
+	}
+	sealer, err := getSecretSealer()
+	if err != nil {
+		return "", errors.Wrap(err, "crypt/secret_seal:SealSecret() Unable to seal the secret")
+	}
+	blob, err := sealer.Seal(secret, pcrs)
+	if err != nil {
+		return "", errors.Wrap(err, "crypt/secret_seal:SealSecret() Unable to seal the secret")
+	}
+	return SealedSecretPrefix + base64.StdEncoding.EncodeToString(blob), nil
+}
+
+// IsSealedSecret returns true when the configuration value holds a sealed secret
+func IsSealedSecret(value string) bool {
+	return strings.HasPrefix(value, SealedSecretPrefix)
+}
+
+// ResolveSecret returns the secret of a configuration value, the sealed secrets are unsealed with the local TPM
+// and the other values are returned as they are
+func ResolveSecret(value string) (string, error) {
+	if !IsSealedSecret(value) {
+		return value, nil
+	}
+	blob, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, SealedSecretPrefix))
+	if err != nil {
+		return "", errors.Wrap(err, "crypt/secret_seal:ResolveSecret() Invalid sealed secret")
+	}
+	sealer, err := getSecretSealer()
+	if err != nil {
+		return "", errors.Wrap(err, "crypt/secret_seal:ResolveSecret() Unable to unseal the secret")
+	}
+	secret, err := sealer.Unseal(blob)
+	if err != nil {
+		return "", errors.Wrap(err, "crypt/secret_seal:ResolveSecret() Unable to unseal the secret")
+	}
+	defer Zeroize(secret)
+	return string(secret), nil
+}
+
+// ParsePCRSelection parses a comma separated list of PCR indexes, e.g. 0,7
+func ParsePCRSelection(selection string) ([]int, error) {
+	var pcrs []int
+	for _, s := range strings.Split(selection, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		pcr, err := strconv.Atoi(s)
+		if err != nil || pcr < 0 || pcr > maxPCRIndex {
+			return nil, errors.Errorf("crypt/secret_seal:ParsePCRSelection() Invalid PCR index %s", s)
+		}
+		pcrs = append(pcrs, pcr)
+	}
+	return pcrs, nil
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// fakeSealer keeps the PCR values the secrets are sealed to, the secrets are not encrypted
+type fakeSealer struct {
+	pcrValues map[int]byte
+}
+
+type fakeSealedBlob struct {
+	Secret    []byte
+	PCRValues map[int]byte
+}
+
+func (s *fakeSealer) Seal(secret []byte, pcrs []int) ([]byte, error) {
+	blob := fakeSealedBlob{Secret: secret, PCRValues: map[int]byte{}}
+	for _, pcr := range pcrs {
+		blob.PCRValues[pcr] = s.pcrValues[pcr]
+	}
+	return json.Marshal(blob)
+}
+
+func (s *fakeSealer) Unseal(b []byte) ([]byte, error) {
+	var blob fakeSealedBlob
+	if err := json.Unmarshal(b, &blob); err != nil {
+		return nil, err
+	}
+	for pcr, value := range blob.PCRValues {
+		if s.pcrValues[pcr] != value {
+			return nil, errors.Errorf("PCR %d does not match", pcr)
+		}
+	}
+	return blob.Secret, nil
+}
+
+func TestSealSecret(t *testing.T) {
+	// the TPM sealer of the builds with the tpm tag is registered again after the test
+	registeredSealer, _ := getSecretSealer()
+	defer RegisterSecretSealer(registeredSealer)
+	RegisterSecretSealer(nil)
+	if _, err := SealSecret([]byte("dbpassword"), nil); err == nil {
+		t.Error("Expected an error when no sealer is registered")
+	}
+	if _, err := ResolveSecret(SealedSecretPrefix + "e30="); err == nil {
+		t.Error("Expected an error when no sealer is registered")
+	}
+
+	sealer := &fakeSealer{pcrValues: map[int]byte{0: 1, 7: 2}}
+	RegisterSecretSealer(sealer)
+
+	sealed, err := SealSecret([]byte("dbpassword"), []int{0, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealedSecret(sealed) || bytes.Contains([]byte(sealed), []byte("dbpassword")) {
+		t.Errorf("Unexpected sealed secret %s", sealed)
+	}
+	secret, err := ResolveSecret(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "dbpassword" {
+		t.Errorf("Expected the unsealed secret dbpassword, got %s", secret)
+	}
+
+	// the plaintext values of the configuration are kept
+	if secret, err = ResolveSecret("dbpassword"); err != nil || secret != "dbpassword" {
+		t.Errorf("Expected the plaintext value to be returned, got %s, %v", secret, err)
+	}
+
+	// the PCR values changed since the secret was sealed
+	sealer.pcrValues[7] = 3
+	if _, err = ResolveSecret(sealed); err == nil {
+		t.Error("Expected an error when the PCR policy is not satisfied")
+	}
+
+	if _, err = SealSecret(nil, nil); err == nil {
+		t.Error("Expected an error for an empty secret")
+	}
+	if _, err = SealSecret([]byte("dbpassword"), []int{24}); err == nil {
+		t.Error("Expected an error for an invalid PCR index")
+	}
+	if _, err = ResolveSecret(SealedSecretPrefix + "not base64"); err == nil {
+		t.Error("Expected an error for an invalid sealed secret")
+	}
+}
+
+func TestParsePCRSelection(t *testing.T) {
+	pcrs, err := ParsePCRSelection("0, 7,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pcrs, []int{0, 7}) {
+		t.Errorf("Unexpected PCR selection %v", pcrs)
+	}
+	if pcrs, err = ParsePCRSelection(""); err != nil || len(pcrs) != 0 {
+		t.Errorf("Expected an empty PCR selection, got %v, %v", pcrs, err)
+	}
+	for _, selection := range []string{"a", "-1", "24"} {
+		if _, err = ParsePCRSelection(selection); err == nil {
+			t.Errorf("Expected an error for the PCR selection %s", selection)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+//go:build tpm
+// +build tpm
+
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterSecretSealer(&tpmSealer{device: defaultTPMDevice})
+}
+
+// srkTemplate is the template of the storage primary key the secrets are sealed under. The primary key is derived
+// from the owner seed, it is created again with the same template to unseal the secrets.
+var srkTemplate = tpm2.Public{
+	Type:    tpm2.AlgRSA,
+	NameAlg: tpm2.AlgSHA256,
+	Attributes: tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin | tpm2.FlagUserWithAuth |
+		tpm2.FlagRestricted | tpm2.FlagDecrypt | tpm2.FlagNoDA,
+	RSAParameters: &tpm2.RSAParams{
+		Symmetric: &tpm2.SymScheme{
+			Alg:     tpm2.AlgAES,
+			KeyBits: 128,
+			Mode:    tpm2.AlgCFB,
+		},
+		KeyBits: 2048,
+	},
+}
+
+// tpmSealedBlob is the sealed data object of a secret, the PCRs are the ones of its policy
+type tpmSealedBlob struct {
+	Public  []byte `json:"public"`
+	Private []byte `json:"private"`
+	PCRs    []int  `json:"pcrs,omitempty"`
+}
+
+// tpmSealer seals the secrets on the TPM resource manager under the storage primary key of the owner hierarchy,
+// the owner hierarchy must have an empty authorization value
+type tpmSealer struct {
+	mutex  sync.Mutex
+	device string
+}
+
+func (s *tpmSealer) Seal(secret []byte, pcrs []int) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rw, err := tpm2.OpenTPM(s.device)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to open TPM device %s", s.device)
+	}
+	defer rw.Close()
+
+	srk, _, err := tpm2.CreatePrimary(rw, tpm2.HandleOwner, tpm2.PCRSelection{}, "", "", srkTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create the storage primary key")
+	}
+	defer tpm2.FlushContext(rw, srk)
+
+	template := tpm2.Public{
+		Type:       tpm2.AlgKeyedHash,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: tpm2.FlagFixedTPM | tpm2.FlagFixedParent,
+	}
+	if len(pcrs) == 0 {
+		template.Attributes |= tpm2.FlagUserWithAuth
+	} else {
+		template.AuthPolicy, err = pcrPolicyDigest(rw, pcrs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	private, public, _, _, _, err := tpm2.CreateKeyWithSensitive(rw, srk, tpm2.PCRSelection{}, "", "", template, secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create the sealed data object")
+	}
+	return json.Marshal(tpmSealedBlob{
+		Public:  public,
+		Private: private,
+		PCRs:    pcrs,
+	})
+}
+
+func (s *tpmSealer) Unseal(blob []byte) ([]byte, error) {
+	var sealed tpmSealedBlob
+	if err := json.Unmarshal(blob, &sealed); err != nil {
+		return nil, errors.Wrap(err, "Invalid sealed data object")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rw, err := tpm2.OpenTPM(s.device)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to open TPM device %s", s.device)
+	}
+	defer rw.Close()
+
+	srk, _, err := tpm2.CreatePrimary(rw, tpm2.HandleOwner, tpm2.PCRSelection{}, "", "", srkTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create the storage primary key")
+	}
+	defer tpm2.FlushContext(rw, srk)
+
+	item, _, err := tpm2.Load(rw, srk, "", sealed.Public, sealed.Private)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to load the sealed data object, it was sealed on another TPM")
+	}
+	defer tpm2.FlushContext(rw, item)
+
+	if len(sealed.PCRs) == 0 {
+		secret, err := tpm2.Unseal(rw, item, "")
+		return secret, errors.Wrap(err, "Unable to unseal the secret")
+	}
+
+	session, err := startPCRPolicySession(rw, tpm2.SessionPolicy, sealed.PCRs)
+	if err != nil {
+		return nil, err
+	}
+	defer tpm2.FlushContext(rw, session)
+	secret, err := tpm2.UnsealWithSession(rw, session, item, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to unseal the secret, the PCR values do not match the policy")
+	}
+	return secret, nil
+}
+
+// pcrPolicyDigest computes the digest of the policy binding a sealed data object to the current values of the PCRs
+func pcrPolicyDigest(rw io.ReadWriter, pcrs []int) ([]byte, error) {
+	session, err := startPCRPolicySession(rw, tpm2.SessionTrial, pcrs)
+	if err != nil {
+		return nil, err
+	}
+	defer tpm2.FlushContext(rw, session)
+
+	digest, err := tpm2.PolicyGetDigest(rw, session)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to get the PCR policy digest")
+	}
+	return digest, nil
+}
+
+func startPCRPolicySession(rw io.ReadWriter, sessionType tpm2.SessionType, pcrs []int) (tpmutil.Handle, error) {
+	session, _, err := tpm2.StartAuthSession(rw, tpm2.HandleNull, tpm2.HandleNull, make([]byte, 16), nil,
+		sessionType, tpm2.AlgNull, tpm2.AlgSHA256)
+	if err != nil {
+		return tpm2.HandleNull, errors.Wrap(err, "Unable to start the policy session")
+	}
+	if err = tpm2.PolicyPCR(rw, session, nil, tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: pcrs}); err != nil {
+		_ = tpm2.FlushContext(rw, session)
+		return tpm2.HandleNull, errors.Wrap(err, "Unable to set the PCR policy")
+	}
+	return session, nil
+}
@@ -0,0 +1,64 @@
+//go:build tpm
+// +build tpm
+
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestTPMSealerRegistered(t *testing.T) {
+	sealer, err := getSecretSealer()
+	if err != nil {
+		t.Fatal("Expected the TPM sealer to be registered by the builds with the tpm tag:", err)
+	}
+	tpmSealer, ok := sealer.(*tpmSealer)
+	if !ok {
+		t.Fatalf("Expected the TPM sealer, got %T", sealer)
+	}
+	if tpmSealer.device != defaultTPMDevice {
+		t.Errorf("Expected the sealer to use %s, got %s", defaultTPMDevice, tpmSealer.device)
+	}
+}
+
+func TestTPMSealerDeviceMissing(t *testing.T) {
+	sealer := &tpmSealer{device: "/dev/tpmrm-missing"}
+	if _, err := sealer.Seal([]byte("dbpassword"), nil); err == nil {
+		t.Error("Expected an error when the TPM device does not exist")
+	}
+	if _, err := sealer.Unseal([]byte(`{"public":"","private":""}`)); err == nil {
+		t.Error("Expected an error when the TPM device does not exist")
+	}
+	if _, err := sealer.Unseal([]byte("not a sealed blob")); err == nil {
+		t.Error("Expected an error for an invalid sealed data object")
+	}
+}
+
+// TestTPMSealerRoundTrip seals and unseals a secret on the TPM of the test host, it is skipped on the hosts without
+// an accessible TPM resource manager
+func TestTPMSealerRoundTrip(t *testing.T) {
+	if _, err := os.Stat(defaultTPMDevice); err != nil {
+		t.Skipf("%s not available: %v", defaultTPMDevice, err)
+	}
+	sealer := &tpmSealer{device: defaultTPMDevice}
+
+	for _, pcrs := range [][]int{nil, {0, 7}} {
+		blob, err := sealer.Seal([]byte("dbpassword"), pcrs)
+		if err != nil {
+			t.Fatal("Failed to seal the secret:", err)
+		}
+		secret, err := sealer.Unseal(blob)
+		if err != nil {
+			t.Fatal("Failed to unseal the secret:", err)
+		}
+		if !bytes.Equal(secret, []byte("dbpassword")) {
+			t.Errorf("Expected the unsealed secret to be dbpassword, got %s", secret)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+// cipherKnownAnswers are the RFC 8452 (AES-GCM-SIV) and IEEE P1619 (AES-XTS) test vectors of the self-test
+var cipherKnownAnswers = []struct {
+	name       string
+	mode       string
+	key        string
+	nonce      string
+	sector     uint64
+	plaintext  string
+	ciphertext string
+}{
+	{
+		name:       "AES-128-GCM-SIV",
+		mode:       "GCM-SIV",
+		key:        "01000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		ciphertext: "dc20e2d83f25705bb49e439eca56de25",
+	}, {
+		name:       "AES-256-GCM-SIV",
+		mode:       "GCM-SIV",
+		key:        "0100000000000000000000000000000000000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		plaintext:  "0100000000000000",
+		ciphertext: "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+	}, {
+		name:       "AES-128-XTS",
+		mode:       "XTS",
+		key:        "1111111111111111111111111111111122222222222222222222222222222222",
+		sector:     0x3333333333,
+		plaintext:  "4444444444444444444444444444444444444444444444444444444444444444",
+		ciphertext: "c454185e6a16936e39334038acef838bfb186fff7480adc4289382ecd6d394f0",
+	},
+}
+
+// CipherSelfTest verifies the AES-GCM-SIV and AES-XTS implementations against known answers, the services
+// using them run it at startup and refuse to start when it fails
+func CipherSelfTest() error {
+	for _, kat := range cipherKnownAnswers {
+		key, _ := hex.DecodeString(kat.key)
+		plaintext, _ := hex.DecodeString(kat.plaintext)
+		expected, _ := hex.DecodeString(kat.ciphertext)
+
+		var ciphertext, decrypted []byte
+		switch kat.mode {
+		case "GCM-SIV":
+			aead, err := NewGCMSIV(key)
+			if err != nil {
+				return errors.Wrapf(err, "%s self-test failed", kat.name)
+			}
+			nonce, _ := hex.DecodeString(kat.nonce)
+			ciphertext = aead.Seal(nil, nonce, plaintext, nil)
+			if decrypted, err = aead.Open(nil, nonce, ciphertext, nil); err != nil {
+				return errors.Wrapf(err, "%s self-test failed", kat.name)
+			}
+		case "XTS":
+			c, err := NewXtsCipher(key)
+			if err != nil {
+				return errors.Wrapf(err, "%s self-test failed", kat.name)
+			}
+			ciphertext = make([]byte, len(plaintext))
+			c.Encrypt(ciphertext, plaintext, kat.sector)
+			decrypted = make([]byte, len(ciphertext))
+			c.Decrypt(decrypted, ciphertext, kat.sector)
+		}
+
+		if !bytes.Equal(ciphertext, expected) || !bytes.Equal(decrypted, plaintext) {
+			return errors.Errorf("%s self-test failed: known answer mismatch", kat.name)
+		}
+	}
+	return nil
+}
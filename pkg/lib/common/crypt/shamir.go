@@ -0,0 +1,262 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// keySharePrefix versions the encoding of the shares of SplitKey
+const keySharePrefix = "shamir1"
+
+// keySplitIDLength is the length of the random id the shares of a SplitKey split share
+const keySplitIDLength = 16
+
+var (
+	ErrNotEnoughShares = errors.New("Not enough shares to reconstruct the key")
+	ErrInvalidShares   = errors.New("The shares are not shares of the same key")
+)
+
+// SplitSecret splits the secret with Shamir secret sharing in parts shares, threshold of which are needed to
+// reconstruct it with CombineShares. Each share is one byte longer than the secret, the last byte being the
+// x coordinate of the share. The arithmetic is done in GF(2^8), bytewise.
+func SplitSecret(secret []byte, parts, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("The secret to split cannot be empty")
+	}
+	if threshold < 2 || threshold > parts || parts > 255 {
+		return nil, errors.New("The threshold must be at least 2 and at most the number of shares, which cannot exceed 255")
+	}
+
+	xCoordinates, err := randomXCoordinates(parts)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([][]byte, parts)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = xCoordinates[i]
+	}
+
+	coefficients := make([]byte, threshold)
+	for b, secretByte := range secret {
+		// the polynomial of each byte has the secret byte as intercept and random coefficients
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the polynomial coefficients")
+		}
+		coefficients[0] = secretByte
+		for i := range shares {
+			shares[i][b] = evaluatePolynomial(coefficients, xCoordinates[i])
+		}
+	}
+	zeroBytes(coefficients)
+	return shares, nil
+}
+
+// CombineShares reconstructs the secret from shares created by SplitSecret. Fewer shares than the threshold the
+// secret was split with reconstruct a wrong secret, the caller verifies the reconstructed secret.
+func CombineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("At least two shares are needed to reconstruct the secret")
+	}
+	shareLength := len(shares[0])
+	if shareLength < 2 {
+		return nil, errors.New("The shares are too short")
+	}
+	xCoordinates := make([]byte, len(shares))
+	seen := make(map[byte]bool)
+	for i, share := range shares {
+		if len(share) != shareLength {
+			return nil, errors.New("The shares are not of the same length")
+		}
+		x := share[shareLength-1]
+		if x == 0 || seen[x] {
+			return nil, errors.New("The shares do not have distinct coordinates")
+		}
+		seen[x] = true
+		xCoordinates[i] = x
+	}
+
+	secret := make([]byte, shareLength-1)
+	for b := range secret {
+		// Lagrange interpolation at x = 0, subtraction in GF(2^8) is the xor
+		var value byte
+		for i, xi := range xCoordinates {
+			basis := byte(1)
+			for j, xj := range xCoordinates {
+				if i == j {
+					continue
+				}
+				basis = gfMultiply(basis, gfMultiply(xj, gfInverse(xj^xi)))
+			}
+			value ^= gfMultiply(shares[i][b], basis)
+		}
+		secret[b] = value
+	}
+	return secret, nil
+}
+
+// SplitKey splits the key like SplitSecret and encodes each share with the threshold, a random id of the split and a
+// checksum of the share keyed by the id, so that CombineKeyShares tells too few, corrupted or mixed shares from the
+// shares of the key without the caller keeping anything about the key. The shares carry nothing computed from the
+// key, the checksum does not authenticate the custodians either, the caller verifies the reconstructed key when a
+// custodian can submit a forged share.
+func SplitKey(key []byte, parts, threshold int) ([]string, error) {
+	shares, err := SplitSecret(key, parts, threshold)
+	if err != nil {
+		return nil, err
+	}
+	splitID := make([]byte, keySplitIDLength)
+	if _, err := rand.Read(splitID); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate the id of the split")
+	}
+	encodedShares := make([]string, len(shares))
+	for i, share := range shares {
+		encodedShares[i] = strings.Join([]string{keySharePrefix, strconv.Itoa(threshold),
+			base64.RawURLEncoding.EncodeToString(splitID), base64.RawURLEncoding.EncodeToString(share),
+			keyShareChecksum(splitID, threshold, share)}, ".")
+		zeroBytes(share)
+	}
+	return encodedShares, nil
+}
+
+// VerifyKeyShare checks that the share is encoded by SplitKey and is not corrupted
+func VerifyKeyShare(encodedShare string) error {
+	_, _, share, err := decodeKeyShare(encodedShare)
+	zeroBytes(share)
+	return err
+}
+
+// CombineKeyShares reconstructs the key from shares encoded by SplitKey, the shares must be of the same split
+func CombineKeyShares(encodedShares []string) ([]byte, error) {
+	if len(encodedShares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	var threshold int
+	var splitID []byte
+	shares := make([][]byte, len(encodedShares))
+	defer func() {
+		for _, share := range shares {
+			zeroBytes(share)
+		}
+	}()
+	for i, encodedShare := range encodedShares {
+		shareThreshold, shareSplitID, share, err := decodeKeyShare(encodedShare)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+		if i == 0 {
+			threshold, splitID = shareThreshold, shareSplitID
+		} else if shareThreshold != threshold || !hmac.Equal(shareSplitID, splitID) {
+			return nil, ErrInvalidShares
+		}
+	}
+	if len(shares) < threshold {
+		return nil, ErrNotEnoughShares
+	}
+
+	// shares of different lengths or duplicated shares are not shares of the key either
+	key, err := CombineShares(shares)
+	if err != nil {
+		return nil, ErrInvalidShares
+	}
+	return key, nil
+}
+
+// decodeKeyShare decodes the threshold, the split id and the share of a share encoded by SplitKey, a share which
+// does not match its checksum is invalid
+func decodeKeyShare(encodedShare string) (int, []byte, []byte, error) {
+	fields := strings.Split(strings.TrimSpace(encodedShare), ".")
+	if len(fields) != 5 || fields[0] != keySharePrefix {
+		return 0, nil, nil, errors.New("The share is not encoded as a key share")
+	}
+	threshold, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "The threshold of the share is invalid")
+	}
+	splitID, err := base64.RawURLEncoding.DecodeString(fields[2])
+	if err != nil || len(splitID) != keySplitIDLength {
+		return 0, nil, nil, errors.New("The split id of the share is invalid")
+	}
+	share, err := base64.RawURLEncoding.DecodeString(fields[3])
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "The share is not base64 encoded")
+	}
+	if !hmac.Equal([]byte(keyShareChecksum(splitID, threshold, share)), []byte(fields[4])) {
+		zeroBytes(share)
+		return 0, nil, nil, ErrInvalidShares
+	}
+	return threshold, splitID, share, nil
+}
+
+// keyShareChecksum returns the truncated HMAC-SHA256 of the threshold and the share keyed by the split id
+func keyShareChecksum(splitID []byte, threshold int, share []byte) string {
+	mac := hmac.New(sha256.New, splitID)
+	mac.Write([]byte(strconv.Itoa(threshold) + "."))
+	mac.Write(share)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// randomXCoordinates returns distinct non zero x coordinates in random order
+func randomXCoordinates(count int) ([]byte, error) {
+	xCoordinates := make([]byte, 255)
+	for i := range xCoordinates {
+		xCoordinates[i] = byte(i + 1)
+	}
+	random := make([]byte, len(xCoordinates))
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate the share coordinates")
+	}
+	for i := len(xCoordinates) - 1; i > 0; i-- {
+		j := int(random[i]) % (i + 1)
+		xCoordinates[i], xCoordinates[j] = xCoordinates[j], xCoordinates[i]
+	}
+	return xCoordinates[:count], nil
+}
+
+// evaluatePolynomial evaluates the polynomial of the coefficients, lowest degree first, at x
+func evaluatePolynomial(coefficients []byte, x byte) byte {
+	var value byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		value = gfMultiply(value, x) ^ coefficients[i]
+	}
+	return value
+}
+
+// gfMultiply multiplies in GF(2^8) modulo the AES polynomial x^8 + x^4 + x^3 + x + 1, without data dependent
+// branches
+func gfMultiply(a, b byte) byte {
+	var product byte
+	for i := 0; i < 8; i++ {
+		product ^= -(b & 1) & a
+		carry := -(a >> 7)
+		a = (a << 1) ^ (carry & 0x1b)
+		b >>= 1
+	}
+	return product
+}
+
+// gfInverse returns the multiplicative inverse in GF(2^8), a^254
+func gfInverse(a byte) byte {
+	inverse := a
+	for i := 0; i < 6; i++ {
+		inverse = gfMultiply(gfMultiply(inverse, inverse), a)
+	}
+	return gfMultiply(inverse, inverse)
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
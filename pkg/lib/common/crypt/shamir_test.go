@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestGFArithmetic(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if gfMultiply(byte(a), gfInverse(byte(a))) != 1 {
+			t.Fatalf("%d times its inverse is not 1", a)
+		}
+	}
+	// 0x57 * 0x83 = 0xc1 in the AES field
+	if gfMultiply(0x57, 0x83) != 0xc1 {
+		t.Error("Unexpected product in GF(2^8)")
+	}
+}
+
+func TestSplitAndCombineShares(t *testing.T) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := SplitSecret(secret, 5, 3)
+	if err != nil {
+		t.Fatal("Failed to split the secret:", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("Expected 5 shares, got %d", len(shares))
+	}
+
+	// any threshold shares reconstruct the secret
+	for _, combination := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var subset [][]byte
+		for _, i := range combination {
+			subset = append(subset, shares[i])
+		}
+		combined, err := CombineShares(subset)
+		if err != nil {
+			t.Fatal("Failed to combine the shares:", err)
+		}
+		if !bytes.Equal(combined, secret) {
+			t.Errorf("Shares %v did not reconstruct the secret", combination)
+		}
+	}
+
+	// fewer shares than the threshold do not
+	combined, err := CombineShares(shares[:2])
+	if err != nil {
+		t.Fatal("Failed to combine the shares:", err)
+	}
+	if bytes.Equal(combined, secret) {
+		t.Error("Shares below the threshold reconstructed the secret")
+	}
+}
+
+func TestSplitAndCombineSharesErrors(t *testing.T) {
+	if _, err := SplitSecret([]byte("secret"), 3, 4); err == nil {
+		t.Error("Expected an error for a threshold above the number of shares")
+	}
+	if _, err := SplitSecret([]byte("secret"), 3, 1); err == nil {
+		t.Error("Expected an error for a threshold of 1")
+	}
+	if _, err := SplitSecret(nil, 3, 2); err == nil {
+		t.Error("Expected an error for an empty secret")
+	}
+
+	shares, err := SplitSecret([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineShares([][]byte{shares[0], shares[0]}); err == nil {
+		t.Error("Expected an error for duplicated shares")
+	}
+	if _, err := CombineShares([][]byte{shares[0], shares[1][1:]}); err == nil {
+		t.Error("Expected an error for shares of different lengths")
+	}
+}
+
+func TestSplitAndCombineKeyShares(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := SplitKey(key, 4, 3)
+	if err != nil {
+		t.Fatal("Failed to split the key:", err)
+	}
+	combined, err := CombineKeyShares([]string{shares[3], shares[0], shares[2]})
+	if err != nil {
+		t.Fatal("Failed to combine the key shares:", err)
+	}
+	if !bytes.Equal(combined, key) {
+		t.Error("The key shares did not reconstruct the key")
+	}
+
+	if _, err = CombineKeyShares(shares[:2]); err != ErrNotEnoughShares {
+		t.Error("Expected ErrNotEnoughShares for shares below the threshold, got", err)
+	}
+
+	otherShares, err := SplitKey(key[1:], 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = CombineKeyShares([]string{shares[0], shares[1], otherShares[2]}); err != ErrInvalidShares {
+		t.Error("Expected ErrInvalidShares for shares of another key, got", err)
+	}
+
+	// the shares of two splits of the same key have nothing in common and cannot be mixed
+	resplitShares, err := SplitKey(key, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Split(resplitShares[0], ".")[2] == strings.Split(shares[0], ".")[2] {
+		t.Error("Expected the splits of the same key to have different ids")
+	}
+	if _, err = CombineKeyShares([]string{shares[0], shares[1], resplitShares[2]}); err != ErrInvalidShares {
+		t.Error("Expected ErrInvalidShares for shares of another split, got", err)
+	}
+
+	// a corrupted share does not reconstruct the key
+	fields := strings.Split(shares[1], ".")
+	share, _ := base64.RawURLEncoding.DecodeString(fields[3])
+	share[0] ^= 1
+	fields[3] = base64.RawURLEncoding.EncodeToString(share)
+	if err = VerifyKeyShare(strings.Join(fields, ".")); err != ErrInvalidShares {
+		t.Error("Expected ErrInvalidShares verifying a corrupted share, got", err)
+	}
+	if _, err = CombineKeyShares([]string{shares[0], strings.Join(fields, "."), shares[2]}); err != ErrInvalidShares {
+		t.Error("Expected ErrInvalidShares for a corrupted share, got", err)
+	}
+}
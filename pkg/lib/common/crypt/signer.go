@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignerSchemeFile loads the private key from a PKCS8 PEM file, e.g. file:/etc/hvs/trusted-keys/saml.key
+	SignerSchemeFile = "file"
+	// SignerSchemePKCS11 uses a private key held in a PKCS#11 token, e.g.
+	// pkcs11:token=hvs;object=saml?module-path=/usr/lib64/libsofthsm2.so&pin-source=/etc/hvs/pkcs11-pin
+	SignerSchemePKCS11 = "pkcs11"
+	// SignerSchemeTPM uses a private key persisted in the TPM, e.g. tpm:0x81000100
+	SignerSchemeTPM = "tpm"
+)
+
+// SignerProvider returns the signer for the key reference, the part of the key URI following the scheme
+type SignerProvider func(keyRef string) (crypto.Signer, error)
+
+var (
+	signerProviders      = map[string]SignerProvider{SignerSchemeFile: fileSigner}
+	signerProvidersMutex sync.RWMutex
+)
+
+// RegisterSignerProvider makes the signers of a key URI scheme available to GetSigner. The PKCS#11 and TPM providers
+// are registered by the files built with the pkcs11 and tpm build tags.
+func RegisterSignerProvider(scheme string, provider SignerProvider) {
+	signerProvidersMutex.Lock()
+	defer signerProvidersMutex.Unlock()
+	signerProviders[scheme] = provider
+}
+
+// GetSigner returns the crypto.Signer of the private key referenced by the key URI. A key URI without a scheme is
+// the path of a PKCS8 PEM file. The signers of hardware backed keys never expose the private key, signing happens
+// within the token or the TPM.
+func GetSigner(keyURI string) (crypto.Signer, error) {
+	if keyURI == "" {
+		return nil, errors.New("crypt/signer:GetSigner() Key URI is empty")
+	}
+	scheme, keyRef := SignerSchemeFile, keyURI
+	if i := strings.Index(keyURI, ":"); i > 0 && !strings.HasPrefix(keyURI, "/") {
+		scheme, keyRef = strings.ToLower(keyURI[:i]), keyURI[i+1:]
+	}
+
+	signerProvidersMutex.RLock()
+	provider, ok := signerProviders[scheme]
+	signerProvidersMutex.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("crypt/signer:GetSigner() Key URI scheme %s is not supported by this build", scheme)
+	}
+
+	signer, err := provider(keyRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "crypt/signer:GetSigner() Unable to load the %s key", scheme)
+	}
+	return signer, nil
+}
+
+func fileSigner(path string) (crypto.Signer, error) {
+	key, err := GetPrivateKeyFromPKCS8File(path)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("private key in %s can not be used for signing", path)
+	}
+	return signer, nil
+}
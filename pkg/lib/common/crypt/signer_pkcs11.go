@@ -0,0 +1,101 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterSignerProvider(SignerSchemePKCS11, pkcs11Signer)
+}
+
+// pkcs11Signer finds the key pair in the token from the path attributes token, object and id of the PKCS#11 URI
+// (RFC 7512), the module-path, pin-value and pin-source query attributes configure the access to the token. The
+// token session stays open for the lifetime of the signer.
+func pkcs11Signer(keyRef string) (crypto.Signer, error) {
+	pathPart, queryPart := keyRef, ""
+	if i := strings.Index(keyRef, "?"); i >= 0 {
+		pathPart, queryPart = keyRef[:i], keyRef[i+1:]
+	}
+	attributes, err := pkcs11Attributes(pathPart, ";")
+	if err != nil {
+		return nil, err
+	}
+	query, err := pkcs11Attributes(queryPart, "&")
+	if err != nil {
+		return nil, err
+	}
+
+	if query["module-path"] == "" {
+		return nil, errors.New("module-path is missing from the PKCS#11 URI")
+	}
+	if attributes["object"] == "" && attributes["id"] == "" {
+		return nil, errors.New("object or id is required in the PKCS#11 URI")
+	}
+	pin := query["pin-value"]
+	if pinSource := query["pin-source"]; pinSource != "" {
+		pinBytes, err := ioutil.ReadFile(strings.TrimPrefix(pinSource, "file:"))
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to read the PKCS#11 token pin")
+		}
+		pin = strings.TrimSpace(string(pinBytes))
+	}
+
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:       query["module-path"],
+		TokenLabel: attributes["token"],
+		Pin:        pin,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open the PKCS#11 token")
+	}
+
+	var id, label []byte
+	if attributes["id"] != "" {
+		id = []byte(attributes["id"])
+	}
+	if attributes["object"] != "" {
+		label = []byte(attributes["object"])
+	}
+	signer, err := ctx.FindKeyPair(id, label)
+	if err != nil {
+		_ = ctx.Close()
+		return nil, errors.Wrap(err, "Unable to find the key pair in the PKCS#11 token")
+	}
+	if signer == nil {
+		_ = ctx.Close()
+		return nil, errors.New("Key pair is not present in the PKCS#11 token")
+	}
+	return signer, nil
+}
+
+func pkcs11Attributes(part, separator string) (map[string]string, error) {
+	attributes := map[string]string{}
+	for _, attribute := range strings.Split(part, separator) {
+		if attribute == "" {
+			continue
+		}
+		nameValue := strings.SplitN(attribute, "=", 2)
+		if len(nameValue) != 2 {
+			return nil, errors.Errorf("Invalid PKCS#11 URI attribute %s", attribute)
+		}
+		value, err := url.PathUnescape(nameValue[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid PKCS#11 URI attribute %s", attribute)
+		}
+		attributes[nameValue[0]] = value
+	}
+	return attributes, nil
+}
@@ -0,0 +1,43 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPKCS11Attributes(t *testing.T) {
+	attributes, err := pkcs11Attributes("token=hvs;object=saml%20key;id=%01", ";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"token": "hvs", "object": "saml key", "id": "\x01"}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("Expected %v, got %v", expected, attributes)
+	}
+
+	for _, part := range []string{"token", "token=hvs;object=%zz"} {
+		if _, err = pkcs11Attributes(part, ";"); err == nil {
+			t.Errorf("Expected an error for the PKCS#11 URI attributes %s", part)
+		}
+	}
+}
+
+func TestPKCS11Signer(t *testing.T) {
+	for _, keyURI := range []string{
+		"pkcs11:token=hvs;object=saml",
+		"pkcs11:token=hvs?module-path=/usr/lib64/libsofthsm2.so",
+		"pkcs11:token=hvs;object=saml?module-path=/usr/lib64/libsofthsm2.so&pin-source=/nonexistent/pkcs11-pin",
+		"pkcs11:token=hvs;object=saml?module-path=/nonexistent/libpkcs11.so&pin-value=1234",
+	} {
+		if _, err := GetSigner(keyURI); err == nil {
+			t.Errorf("Expected an error for the key URI %s", keyURI)
+		}
+	}
+}
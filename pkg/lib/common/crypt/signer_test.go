@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "saml.key")
+	if err = SavePrivateKeyAsPKCS8(keyDer, keyFile); err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte("report"))
+	for _, keyURI := range []string{keyFile, "file:" + keyFile} {
+		signer, err := GetSigner(keyURI)
+		if err != nil {
+			t.Fatalf("GetSigner(%s) failed: %v", keyURI, err)
+		}
+		signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			t.Errorf("Signature of %s does not verify: %v", keyURI, err)
+		}
+	}
+
+	if _, err = GetSigner("unknown:0x81000100"); err == nil {
+		t.Error("Expected an error for an unsupported key URI scheme")
+	}
+	if _, err = GetSigner(""); err == nil {
+		t.Error("Expected an error for an empty key URI")
+	}
+}
@@ -0,0 +1,102 @@
+//go:build tpm
+// +build tpm
+
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+	"github.com/pkg/errors"
+)
+
+const defaultTPMDevice = "/dev/tpmrm0"
+
+func init() {
+	RegisterSignerProvider(SignerSchemeTPM, tpmSigner)
+}
+
+// tpmKey signs with an RSA key persisted in the TPM, the key must have an empty authorization value
+type tpmKey struct {
+	mutex     sync.Mutex
+	rw        io.ReadWriteCloser
+	handle    tpmutil.Handle
+	publicKey crypto.PublicKey
+}
+
+// tpmSigner opens the persistent key handle of the key reference, e.g. 0x81000100, on the TPM resource manager. A
+// different device is set with the device query attribute, e.g. 0x81000100?device=/dev/tpm0.
+func tpmSigner(keyRef string) (crypto.Signer, error) {
+	device := defaultTPMDevice
+	if i := strings.Index(keyRef, "?device="); i >= 0 {
+		keyRef, device = keyRef[:i], keyRef[i+len("?device="):]
+	}
+	handle, err := strconv.ParseUint(keyRef, 0, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid TPM key handle %s", keyRef)
+	}
+
+	rw, err := tpm2.OpenTPM(device)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to open TPM device %s", device)
+	}
+	public, _, _, err := tpm2.ReadPublic(rw, tpmutil.Handle(handle))
+	if err != nil {
+		_ = rw.Close()
+		return nil, errors.Wrapf(err, "Unable to read the public area of TPM key %s", keyRef)
+	}
+	publicKey, err := public.Key()
+	if err != nil {
+		_ = rw.Close()
+		return nil, errors.Wrapf(err, "Unable to decode the public key of TPM key %s", keyRef)
+	}
+	if _, ok := publicKey.(*rsa.PublicKey); !ok {
+		_ = rw.Close()
+		return nil, errors.Errorf("TPM key %s is not an RSA key", keyRef)
+	}
+
+	return &tpmKey{
+		rw:        rw,
+		handle:    tpmutil.Handle(handle),
+		publicKey: publicKey,
+	}, nil
+}
+
+func (k *tpmKey) Public() crypto.PublicKey {
+	return k.publicKey
+}
+
+// Sign creates an RSASSA-PKCS1-v1_5 signature of the digest in the TPM
+func (k *tpmKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if _, ok := opts.(*rsa.PSSOptions); ok {
+		return nil, errors.New("RSA-PSS signatures are not supported by TPM keys")
+	}
+	hashAlg, err := tpm2.HashToAlgorithm(opts.HashFunc())
+	if err != nil {
+		return nil, errors.Wrap(err, "Unsupported hash algorithm for TPM signatures")
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	signature, err := tpm2.Sign(k.rw, k.handle, "", digest, nil, &tpm2.SigScheme{
+		Alg:  tpm2.AlgRSASSA,
+		Hash: hashAlg,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to sign with the TPM key")
+	}
+	if signature.RSA == nil {
+		return nil, errors.New("TPM did not return an RSA signature")
+	}
+	return signature.RSA.Signature, nil
+}
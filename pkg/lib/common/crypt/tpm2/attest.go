@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ClockInfo is the TPMS_CLOCK_INFO of an attestation
+type ClockInfo struct {
+	Clock        uint64
+	ResetCount   uint32
+	RestartCount uint32
+	Safe         bool
+}
+
+// PCRSelection is a TPMS_PCR_SELECTION, the PCRs selected in a bank in ascending order
+type PCRSelection struct {
+	HashAlg uint16
+	PCRs    []int
+}
+
+// QuoteInfo is the TPMS_QUOTE_INFO of a quote, the PCRs quoted and the digest of their values
+type QuoteInfo struct {
+	PCRSelections []PCRSelection
+	PCRDigest     []byte
+}
+
+// CertifyInfo is the TPMS_CERTIFY_INFO of a certified key
+type CertifyInfo struct {
+	Name          []byte
+	QualifiedName []byte
+}
+
+// Attest is a TPMS_ATTEST, Quote or Certify is set depending on the Type
+type Attest struct {
+	Magic           uint32
+	Type            uint16
+	QualifiedSigner []byte
+	ExtraData       []byte
+	ClockInfo       ClockInfo
+	FirmwareVersion uint64
+	Quote           *QuoteInfo
+	Certify         *CertifyInfo
+}
+
+// ParseAttest parses the TPMS_ATTEST of a quote or a certified key, the content of their TPM2B_ATTEST
+func ParseAttest(b []byte) (*Attest, error) {
+	r := &reader{buf: b}
+	attest, err := parseAttest(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.remaining()) != 0 {
+		return nil, errors.Errorf("tpm2/attest:ParseAttest() %d unexpected bytes after the attestation", len(r.remaining()))
+	}
+	return attest, nil
+}
+
+func parseAttest(r *reader) (*Attest, error) {
+	var attest Attest
+	var err error
+
+	if attest.Magic, err = r.uint32("magic"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the magic")
+	}
+	if attest.Magic != GeneratedValue {
+		return nil, errors.Errorf("tpm2/attest:parseAttest() Attestation was not generated by a TPM, magic 0x%08x", attest.Magic)
+	}
+	if attest.Type, err = r.uint16("type"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the attestation type")
+	}
+	if attest.QualifiedSigner, err = r.tpm2b("qualified signer"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the qualified signer")
+	}
+	if attest.ExtraData, err = r.tpm2b("extra data"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the extra data")
+	}
+	if attest.ClockInfo, err = parseClockInfo(r); err != nil {
+		return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the clock info")
+	}
+	if attest.FirmwareVersion, err = r.uint64("firmware version"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the firmware version")
+	}
+
+	switch attest.Type {
+	case StAttestQuote:
+		if attest.Quote, err = parseQuoteInfo(r); err != nil {
+			return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the quote info")
+		}
+	case StAttestCertify:
+		var certify CertifyInfo
+		if certify.Name, err = r.tpm2b("name"); err != nil {
+			return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the certified name")
+		}
+		if certify.QualifiedName, err = r.tpm2b("qualified name"); err != nil {
+			return nil, errors.Wrap(err, "tpm2/attest:parseAttest() Error reading the certified qualified name")
+		}
+		attest.Certify = &certify
+	default:
+		return nil, errors.Errorf("tpm2/attest:parseAttest() Unsupported attestation type 0x%04x", attest.Type)
+	}
+	return &attest, nil
+}
+
+func parseClockInfo(r *reader) (ClockInfo, error) {
+	var clockInfo ClockInfo
+	var err error
+	if clockInfo.Clock, err = r.uint64("clock"); err != nil {
+		return clockInfo, err
+	}
+	if clockInfo.ResetCount, err = r.uint32("reset count"); err != nil {
+		return clockInfo, err
+	}
+	if clockInfo.RestartCount, err = r.uint32("restart count"); err != nil {
+		return clockInfo, err
+	}
+	safe, err := r.uint8("safe")
+	clockInfo.Safe = safe == 1
+	return clockInfo, err
+}
+
+func parseQuoteInfo(r *reader) (*QuoteInfo, error) {
+	count, err := r.uint32("PCR selection count")
+	if err != nil {
+		return nil, err
+	}
+	// a TPM has a handful of PCR banks, a larger count is a malformed quote
+	if count > uint32(len(digestSizes)) {
+		return nil, errors.Errorf("Number of PCR selections %d is greater than %d", count, len(digestSizes))
+	}
+
+	var quoteInfo QuoteInfo
+	for i := uint32(0); i < count; i++ {
+		var selection PCRSelection
+		if selection.HashAlg, err = r.uint16("PCR selection hash algorithm"); err != nil {
+			return nil, err
+		}
+		size, err := r.uint8("PCR selection size")
+		if err != nil {
+			return nil, err
+		}
+		selected, err := r.next(int(size), "PCR selection")
+		if err != nil {
+			return nil, err
+		}
+		selection.PCRs = []int{}
+		for pcr := 0; pcr < 8*int(size); pcr++ {
+			if selected[pcr/8]&(1<<uint(pcr%8)) != 0 {
+				selection.PCRs = append(selection.PCRs, pcr)
+			}
+		}
+		quoteInfo.PCRSelections = append(quoteInfo.PCRSelections, selection)
+	}
+	if quoteInfo.PCRDigest, err = r.tpm2b("PCR digest"); err != nil {
+		return nil, err
+	}
+	return &quoteInfo, nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"crypto"
+
+	"github.com/pkg/errors"
+)
+
+// TPM_ALG_ID values of the algorithms found in the attestation and object structures
+const (
+	AlgRSA       uint16 = 0x0001
+	AlgSHA1      uint16 = 0x0004
+	AlgAES       uint16 = 0x0006
+	AlgKeyedHash uint16 = 0x0008
+	AlgSHA256    uint16 = 0x000B
+	AlgSHA384    uint16 = 0x000C
+	AlgSHA512    uint16 = 0x000D
+	AlgNull      uint16 = 0x0010
+	AlgSM3256    uint16 = 0x0012
+	AlgRSASSA    uint16 = 0x0014
+	AlgRSAES     uint16 = 0x0015
+	AlgRSAPSS    uint16 = 0x0016
+	AlgECDSA     uint16 = 0x0018
+	AlgECDAA     uint16 = 0x001A
+	AlgECC       uint16 = 0x0023
+	AlgSymCipher uint16 = 0x0025
+	AlgCFB       uint16 = 0x0043
+)
+
+// TPM_ECC_CURVE values of the NIST curves
+const (
+	EccNistP256 uint16 = 0x0003
+	EccNistP384 uint16 = 0x0004
+	EccNistP521 uint16 = 0x0005
+)
+
+// TPMA_OBJECT attributes of the objects
+const (
+	AttrFixedTPM             uint32 = 1 << 1
+	AttrFixedParent          uint32 = 1 << 4
+	AttrSensitiveDataOrigin  uint32 = 1 << 5
+	AttrUserWithAuth         uint32 = 1 << 6
+	AttrAdminWithPolicy      uint32 = 1 << 7
+	AttrNoDA                 uint32 = 1 << 10
+	AttrEncryptedDuplication uint32 = 1 << 11
+	AttrRestricted           uint32 = 1 << 16
+	AttrDecrypt              uint32 = 1 << 17
+	AttrSign                 uint32 = 1 << 18
+)
+
+// GeneratedValue is the magic of the structures created by the TPM, TPM_GENERATED_VALUE
+const GeneratedValue uint32 = 0xff544347
+
+// TPM_ST values of the TPMS_ATTEST structures
+const (
+	StAttestCertify uint16 = 0x8017
+	StAttestQuote   uint16 = 0x8018
+)
+
+// digestSizes are the sizes of the PCR values of the PCR banks
+var digestSizes = map[uint16]int{
+	AlgSHA1:   20,
+	AlgSHA256: 32,
+	AlgSHA384: 48,
+	AlgSHA512: 64,
+	AlgSM3256: 32,
+}
+
+// DigestSize returns the size of the digests of the TPM hash algorithm
+func DigestSize(hashAlg uint16) (int, error) {
+	size, ok := digestSizes[hashAlg]
+	if !ok {
+		return 0, errors.Errorf("Unsupported TPM hash algorithm 0x%04x", hashAlg)
+	}
+	return size, nil
+}
+
+// HashAlgorithm returns the crypto.Hash of the TPM hash algorithm, SM3 has no crypto.Hash
+func HashAlgorithm(hashAlg uint16) (crypto.Hash, error) {
+	switch hashAlg {
+	case AlgSHA1:
+		return crypto.SHA1, nil
+	case AlgSHA256:
+		return crypto.SHA256, nil
+	case AlgSHA384:
+		return crypto.SHA384, nil
+	case AlgSHA512:
+		return crypto.SHA512, nil
+	}
+	return 0, errors.Errorf("Unsupported TPM hash algorithm 0x%04x", hashAlg)
+}
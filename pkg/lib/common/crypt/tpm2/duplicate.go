@@ -0,0 +1,230 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// Labels of the KDFa derivations and of the seed encryption of a duplication, they include the terminating zero
+const (
+	duplicateLabel = "DUPLICATE\x00"
+	storageLabel   = "STORAGE\x00"
+	integrityLabel = "INTEGRITY\x00"
+)
+
+// objectNameAlg is the name algorithm of the objects created for a duplication
+const objectNameAlg = AlgSHA256
+
+// KDFa is the key derivation function of the TPM, SP800-108 in counter mode with an HMAC of the hash. The label must
+// include its terminating zero.
+func KDFa(hash crypto.Hash, key []byte, label string, contextU, contextV []byte, bits int) []byte {
+	var out []byte
+	for counter := uint32(1); len(out)*8 < bits; counter++ {
+		mac := hmac.New(hash.New, key)
+		_ = binary.Write(mac, binary.BigEndian, counter)
+		_, _ = mac.Write([]byte(label))
+		_, _ = mac.Write(contextU)
+		_, _ = mac.Write(contextV)
+		_ = binary.Write(mac, binary.BigEndian, uint32(bits))
+		out = mac.Sum(out)
+	}
+	return out[:(bits+7)/8]
+}
+
+// Sensitive is a TPMT_SENSITIVE, the private part of a TPM object
+type Sensitive struct {
+	Type      uint16
+	AuthValue []byte
+	SeedValue []byte
+	// Sensitive is the first prime of the RSA keys, the private scalar of the ECC keys and the key of the symmetric
+	// cipher objects
+	Sensitive []byte
+}
+
+// Marshal returns the TPMT_SENSITIVE of the object
+func (sensitive *Sensitive) Marshal() []byte {
+	var w writer
+	w.uint16(sensitive.Type)
+	w.tpm2b(sensitive.AuthValue)
+	w.tpm2b(sensitive.SeedValue)
+	w.tpm2b(sensitive.Sensitive)
+	return w.Bytes()
+}
+
+// objectAttributes are the attributes of the objects created for a duplication, an object with an auth policy can
+// only be used through it
+func objectAttributes(authPolicy []byte, attributes uint32) uint32 {
+	if len(authPolicy) == 0 {
+		attributes |= AttrUserWithAuth
+	}
+	return attributes
+}
+
+// NewSymCipherObject returns the public and sensitive areas of an AES key to be duplicated
+func NewSymCipherObject(key, authPolicy []byte) (*Public, *Sensitive, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, nil, errors.Errorf("tpm2/duplicate:NewSymCipherObject() Invalid AES key length %d", len(key))
+	}
+	hash, _ := HashAlgorithm(objectNameAlg)
+	seed := make([]byte, hash.Size())
+	if _, err := rand.Read(seed); err != nil {
+		return nil, nil, errors.Wrap(err, "tpm2/duplicate:NewSymCipherObject() Error generating the seed value")
+	}
+
+	// the unique field of a symmetric object is the digest of its seed value and key
+	h := hash.New()
+	_, _ = h.Write(seed)
+	_, _ = h.Write(key)
+
+	public := &Public{
+		Type:       AlgSymCipher,
+		NameAlg:    objectNameAlg,
+		Attributes: objectAttributes(authPolicy, AttrDecrypt|AttrSign),
+		AuthPolicy: authPolicy,
+		Symmetric:  SymDef{Alg: AlgAES, KeyBits: uint16(len(key) * 8), Mode: AlgCFB},
+		Unique:     h.Sum(nil),
+	}
+	sensitive := &Sensitive{
+		Type:      AlgSymCipher,
+		SeedValue: seed,
+		Sensitive: key,
+	}
+	return public, sensitive, nil
+}
+
+// NewRSAObject returns the public and sensitive areas of an RSA key to be duplicated
+func NewRSAObject(key *rsa.PrivateKey, authPolicy []byte) (*Public, *Sensitive, error) {
+	if len(key.Primes) != 2 {
+		return nil, nil, errors.New("tpm2/duplicate:NewRSAObject() Multi-prime RSA keys are not supported")
+	}
+	exponent := uint32(key.E)
+	if key.E == defaultRSAExponent {
+		exponent = 0
+	}
+	public := &Public{
+		Type:        AlgRSA,
+		NameAlg:     objectNameAlg,
+		Attributes:  objectAttributes(authPolicy, AttrDecrypt|AttrSign),
+		AuthPolicy:  authPolicy,
+		Symmetric:   SymDef{Alg: AlgNull},
+		Scheme:      Scheme{Alg: AlgNull},
+		RSAKeyBits:  uint16(key.N.BitLen()),
+		RSAExponent: exponent,
+		Unique:      key.N.Bytes(),
+	}
+	sensitive := &Sensitive{
+		Type:      AlgRSA,
+		Sensitive: key.Primes[0].Bytes(),
+	}
+	return public, sensitive, nil
+}
+
+// NewECCObject returns the public and sensitive areas of an ECC key to be duplicated
+func NewECCObject(key *ecdsa.PrivateKey, authPolicy []byte) (*Public, *Sensitive, error) {
+	curveID, err := eccCurveID(key.Curve)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "tpm2/duplicate:NewECCObject() Invalid ECC key")
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	public := &Public{
+		Type:       AlgECC,
+		NameAlg:    objectNameAlg,
+		Attributes: objectAttributes(authPolicy, AttrSign),
+		AuthPolicy: authPolicy,
+		Symmetric:  SymDef{Alg: AlgNull},
+		Scheme:     Scheme{Alg: AlgNull},
+		CurveID:    curveID,
+		KDF:        Scheme{Alg: AlgNull},
+		X:          key.X.FillBytes(make([]byte, size)),
+		Y:          key.Y.FillBytes(make([]byte, size)),
+	}
+	sensitive := &Sensitive{
+		Type:      AlgECC,
+		Sensitive: key.D.FillBytes(make([]byte, size)),
+	}
+	return public, sensitive, nil
+}
+
+// Duplication holds the parameters of the TPM2_Import of a duplicated object, in the TPM structures they are passed as
+type Duplication struct {
+	// ObjectPublic is the TPM2B_PUBLIC of the object
+	ObjectPublic []byte
+	// Duplicate is the TPM2B_PRIVATE of the object, its sensitive area protected by the outer wrapper
+	Duplicate []byte
+	// InSymSeed is the TPM2B_ENCRYPTED_SECRET, the seed of the outer wrapper encrypted to the parent
+	InSymSeed []byte
+}
+
+// Duplicate wraps the object to the parent storage key, as TPM2_Duplicate does with an outer wrapper and no inner
+// wrapper, so that it can only be imported under that parent. The parent must be an RSA storage key with an AES CFB
+// symmetric algorithm, the SRKs created with the default templates are.
+func Duplicate(parent *Public, object *Public, sensitive *Sensitive) (*Duplication, error) {
+	if parent.Type != AlgRSA {
+		return nil, errors.Errorf("tpm2/duplicate:Duplicate() Unsupported parent type 0x%04x", parent.Type)
+	}
+	if parent.Attributes&(AttrRestricted|AttrDecrypt) != AttrRestricted|AttrDecrypt || parent.Attributes&AttrSign != 0 {
+		return nil, errors.New("tpm2/duplicate:Duplicate() Parent is not a storage key")
+	}
+	if parent.Symmetric.Alg != AlgAES || parent.Symmetric.Mode != AlgCFB {
+		return nil, errors.Errorf("tpm2/duplicate:Duplicate() Unsupported parent symmetric algorithm 0x%04x", parent.Symmetric.Alg)
+	}
+	parentHash, err := HashAlgorithm(parent.NameAlg)
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/duplicate:Duplicate() Invalid parent name algorithm")
+	}
+	parentKey, err := parent.PublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/duplicate:Duplicate() Invalid parent public key")
+	}
+	name, err := object.Name()
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/duplicate:Duplicate() Error computing the object name")
+	}
+
+	seed := make([]byte, parentHash.Size())
+	if _, err := rand.Read(seed); err != nil {
+		return nil, errors.Wrap(err, "tpm2/duplicate:Duplicate() Error generating the seed")
+	}
+	encryptedSeed, err := rsa.EncryptOAEP(parentHash.New(), rand.Reader, parentKey.(*rsa.PublicKey), seed, []byte(duplicateLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/duplicate:Duplicate() Error encrypting the seed to the parent")
+	}
+
+	// the outer wrapper encrypts the TPM2B_SENSITIVE with a zero IV and authenticates it bound to the object name
+	symKey := KDFa(parentHash, seed, storageLabel, name, nil, int(parent.Symmetric.KeyBits))
+	block, err := aes.NewCipher(symKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/duplicate:Duplicate() Error creating the storage cipher")
+	}
+	encSensitive := tpm2b(sensitive.Marshal())
+	cipher.NewCFBEncrypter(block, make([]byte, aes.BlockSize)).XORKeyStream(encSensitive, encSensitive)
+
+	hmacKey := KDFa(parentHash, seed, integrityLabel, nil, nil, parentHash.Size()*8)
+	mac := hmac.New(parentHash.New, hmacKey)
+	_, _ = mac.Write(encSensitive)
+	_, _ = mac.Write(name)
+
+	var duplicate writer
+	duplicate.tpm2b(mac.Sum(nil))
+	_, _ = duplicate.Write(encSensitive)
+
+	return &Duplication{
+		ObjectPublic: tpm2b(object.Marshal()),
+		Duplicate:    tpm2b(duplicate.Bytes()),
+		InSymSeed:    tpm2b(encryptedSeed),
+	}, nil
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestParent returns a storage key in the layout of the SRKs created with the default RSA template
+func newTestParent(t *testing.T) (*rsa.PrivateKey, *Public) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return key, &Public{
+		Type:       AlgRSA,
+		NameAlg:    AlgSHA256,
+		Attributes: AttrFixedTPM | AttrFixedParent | AttrSensitiveDataOrigin | AttrUserWithAuth | AttrNoDA | AttrRestricted | AttrDecrypt,
+		Symmetric:  SymDef{Alg: AlgAES, KeyBits: 128, Mode: AlgCFB},
+		Scheme:     Scheme{Alg: AlgNull},
+		RSAKeyBits: 2048,
+		Unique:     key.N.Bytes(),
+	}
+}
+
+// importDuplicate unwraps the duplicate as TPM2_Import does and returns the sensitive area of the object
+func importDuplicate(t *testing.T, parentKey *rsa.PrivateKey, duplication *Duplication) (*Public, *Sensitive) {
+	r := &reader{buf: duplication.InSymSeed}
+	encryptedSeed, err := r.tpm2b("seed")
+	assert.NoError(t, err)
+	seed, err := rsa.DecryptOAEP(crypto.SHA256.New(), nil, parentKey, encryptedSeed, []byte(duplicateLabel))
+	assert.NoError(t, err)
+
+	object, err := ParsePublic(duplication.ObjectPublic)
+	assert.NoError(t, err)
+	name, err := object.Name()
+	assert.NoError(t, err)
+
+	r = &reader{buf: duplication.Duplicate}
+	duplicate, err := r.tpm2b("duplicate")
+	assert.NoError(t, err)
+	r = &reader{buf: duplicate}
+	outerHMAC, err := r.tpm2b("outer HMAC")
+	assert.NoError(t, err)
+	encSensitive := r.remaining()
+
+	mac := hmac.New(crypto.SHA256.New, KDFa(crypto.SHA256, seed, integrityLabel, nil, nil, 256))
+	mac.Write(encSensitive)
+	mac.Write(name)
+	assert.True(t, hmac.Equal(outerHMAC, mac.Sum(nil)), "outer HMAC does not match")
+
+	block, err := aes.NewCipher(KDFa(crypto.SHA256, seed, storageLabel, name, nil, 128))
+	assert.NoError(t, err)
+	plain := make([]byte, len(encSensitive))
+	cipher.NewCFBDecrypter(block, make([]byte, aes.BlockSize)).XORKeyStream(plain, encSensitive)
+
+	r = &reader{buf: plain}
+	plain, err = r.tpm2b("sensitive")
+	assert.NoError(t, err)
+	r = &reader{buf: plain}
+	var sensitive Sensitive
+	sensitive.Type, _ = r.uint16("type")
+	sensitive.AuthValue, _ = r.tpm2b("auth value")
+	sensitive.SeedValue, _ = r.tpm2b("seed value")
+	sensitive.Sensitive, err = r.tpm2b("sensitive")
+	assert.NoError(t, err)
+	assert.Empty(t, r.remaining())
+	return object, &sensitive
+}
+
+func TestKDFa(t *testing.T) {
+	key := []byte("key")
+	assert.Len(t, KDFa(crypto.SHA256, key, storageLabel, nil, nil, 128), 16)
+	assert.Len(t, KDFa(crypto.SHA256, key, storageLabel, nil, nil, 384), 48)
+	// the derivation depends on the requested size
+	assert.NotEqual(t, KDFa(crypto.SHA256, key, storageLabel, nil, nil, 256)[:16], KDFa(crypto.SHA256, key, storageLabel, nil, nil, 128))
+}
+
+func TestDuplicateSymCipher(t *testing.T) {
+	parentKey, parent := newTestParent(t)
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+
+	public, sensitive, err := NewSymCipherObject(key, nil)
+	assert.NoError(t, err)
+	duplication, err := Duplicate(parent, public, sensitive)
+	assert.NoError(t, err)
+
+	object, imported := importDuplicate(t, parentKey, duplication)
+	assert.Equal(t, AlgSymCipher, object.Type)
+	assert.Equal(t, uint16(256), object.Symmetric.KeyBits)
+	assert.NotZero(t, object.Attributes&AttrUserWithAuth)
+	assert.Equal(t, key, imported.Sensitive)
+}
+
+func TestDuplicateRSA(t *testing.T) {
+	parentKey, parent := newTestParent(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	public, sensitive, err := NewRSAObject(key, []byte("policy-digest-0123456789abcdefgh"))
+	assert.NoError(t, err)
+	duplication, err := Duplicate(parent, public, sensitive)
+	assert.NoError(t, err)
+
+	object, imported := importDuplicate(t, parentKey, duplication)
+	assert.Zero(t, object.Attributes&AttrUserWithAuth)
+	publicKey, err := object.PublicKey()
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+	assert.Equal(t, key.Primes[0].Bytes(), imported.Sensitive)
+}
+
+func TestDuplicateECC(t *testing.T) {
+	parentKey, parent := newTestParent(t)
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+
+	public, sensitive, err := NewECCObject(key, nil)
+	assert.NoError(t, err)
+	duplication, err := Duplicate(parent, public, sensitive)
+	assert.NoError(t, err)
+
+	object, imported := importDuplicate(t, parentKey, duplication)
+	assert.Equal(t, EccNistP384, object.CurveID)
+	publicKey, err := object.PublicKey()
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+	assert.Equal(t, key.D.FillBytes(make([]byte, 48)), imported.Sensitive)
+}
+
+func TestDuplicateInvalidParent(t *testing.T) {
+	_, parent := newTestParent(t)
+	public, sensitive, err := NewSymCipherObject(make([]byte, 16), nil)
+	assert.NoError(t, err)
+
+	// a signing key can not be a parent
+	parent.Attributes |= AttrSign
+	_, err = Duplicate(parent, public, sensitive)
+	assert.Error(t, err)
+}
+
+func TestParsePublic(t *testing.T) {
+	_, parent := newTestParent(t)
+	b := tpm2b(parent.Marshal())
+
+	public, err := ParsePublic(b)
+	assert.NoError(t, err)
+	assert.Equal(t, parent.Marshal(), public.Marshal())
+	assert.Equal(t, parent.Unique, public.Unique)
+
+	_, err = ParsePublic(append(b, 0))
+	assert.Error(t, err)
+	_, err = ParsePublic(b[:len(b)-1])
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// defaultRSAExponent is the exponent of the RSA keys with a zero exponent in their public area
+const defaultRSAExponent = 65537
+
+// SymDef is a TPMT_SYM_DEF_OBJECT, the symmetric algorithm of a storage key or of a symmetric cipher object
+type SymDef struct {
+	Alg     uint16
+	KeyBits uint16
+	Mode    uint16
+}
+
+// Scheme is the signing, encryption or key derivation scheme of an object, Count is only used by ECDAA
+type Scheme struct {
+	Alg     uint16
+	HashAlg uint16
+	Count   uint16
+}
+
+// Public is a TPMT_PUBLIC, the public area of a TPM object. RSA, ECC, symmetric cipher and keyed hash objects are
+// supported, the fields of the other types are zero.
+type Public struct {
+	Type       uint16
+	NameAlg    uint16
+	Attributes uint32
+	AuthPolicy []byte
+
+	// Symmetric is the algorithm of the RSA and ECC storage keys and of the symmetric cipher objects
+	Symmetric SymDef
+	// Scheme is the scheme of the RSA, ECC and keyed hash objects
+	Scheme Scheme
+
+	RSAKeyBits  uint16
+	RSAExponent uint32
+
+	CurveID uint16
+	KDF     Scheme
+
+	// Unique is the modulus of the RSA keys and the digest of the symmetric cipher and keyed hash objects
+	Unique []byte
+	// X and Y are the public point of the ECC keys
+	X []byte
+	Y []byte
+}
+
+// ParsePublic parses a TPM2B_PUBLIC, the format the public area of a key is read from and saved to by the TPM tools
+func ParsePublic(b []byte) (*Public, error) {
+	r := &reader{buf: b}
+	publicArea, err := r.tpm2b("public area")
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/public:ParsePublic() Error reading the public area")
+	}
+	if len(r.remaining()) != 0 {
+		return nil, errors.Errorf("tpm2/public:ParsePublic() %d unexpected bytes after the public area", len(r.remaining()))
+	}
+
+	r = &reader{buf: publicArea}
+	public, err := parsePublic(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/public:ParsePublic() Error reading the public area")
+	}
+	if len(r.remaining()) != 0 {
+		return nil, errors.Errorf("tpm2/public:ParsePublic() %d unexpected bytes in the public area", len(r.remaining()))
+	}
+	return public, nil
+}
+
+func parsePublic(r *reader) (*Public, error) {
+	var public Public
+	var err error
+
+	if public.Type, err = r.uint16("type"); err != nil {
+		return nil, err
+	}
+	if public.NameAlg, err = r.uint16("name algorithm"); err != nil {
+		return nil, err
+	}
+	if public.Attributes, err = r.uint32("object attributes"); err != nil {
+		return nil, err
+	}
+	if public.AuthPolicy, err = r.tpm2b("auth policy"); err != nil {
+		return nil, err
+	}
+
+	switch public.Type {
+	case AlgRSA:
+		if public.Symmetric, err = parseSymDef(r); err != nil {
+			return nil, err
+		}
+		if public.Scheme, err = parseScheme(r, "RSA scheme"); err != nil {
+			return nil, err
+		}
+		if public.RSAKeyBits, err = r.uint16("RSA key bits"); err != nil {
+			return nil, err
+		}
+		if public.RSAExponent, err = r.uint32("RSA exponent"); err != nil {
+			return nil, err
+		}
+		if public.Unique, err = r.tpm2b("RSA modulus"); err != nil {
+			return nil, err
+		}
+	case AlgECC:
+		if public.Symmetric, err = parseSymDef(r); err != nil {
+			return nil, err
+		}
+		if public.Scheme, err = parseScheme(r, "ECC scheme"); err != nil {
+			return nil, err
+		}
+		if public.CurveID, err = r.uint16("ECC curve"); err != nil {
+			return nil, err
+		}
+		if public.KDF, err = parseScheme(r, "KDF scheme"); err != nil {
+			return nil, err
+		}
+		if public.X, err = r.tpm2b("ECC point X"); err != nil {
+			return nil, err
+		}
+		if public.Y, err = r.tpm2b("ECC point Y"); err != nil {
+			return nil, err
+		}
+	case AlgSymCipher:
+		if public.Symmetric, err = parseSymDef(r); err != nil {
+			return nil, err
+		}
+		if public.Unique, err = r.tpm2b("unique"); err != nil {
+			return nil, err
+		}
+	case AlgKeyedHash:
+		if public.Scheme, err = parseScheme(r, "keyed hash scheme"); err != nil {
+			return nil, err
+		}
+		if public.Unique, err = r.tpm2b("unique"); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("Unsupported object type 0x%04x", public.Type)
+	}
+	return &public, nil
+}
+
+func parseSymDef(r *reader) (SymDef, error) {
+	var symDef SymDef
+	var err error
+	if symDef.Alg, err = r.uint16("symmetric algorithm"); err != nil || symDef.Alg == AlgNull {
+		return symDef, err
+	}
+	if symDef.KeyBits, err = r.uint16("symmetric key bits"); err != nil {
+		return symDef, err
+	}
+	symDef.Mode, err = r.uint16("symmetric mode")
+	return symDef, err
+}
+
+// parseScheme reads a scheme, all the schemes but the RSAES and NULL ones have a hash algorithm
+func parseScheme(r *reader, field string) (Scheme, error) {
+	var scheme Scheme
+	var err error
+	if scheme.Alg, err = r.uint16(field); err != nil || scheme.Alg == AlgNull || scheme.Alg == AlgRSAES {
+		return scheme, err
+	}
+	if scheme.HashAlg, err = r.uint16(field + " hash algorithm"); err != nil || scheme.Alg != AlgECDAA {
+		return scheme, err
+	}
+	scheme.Count, err = r.uint16(field + " count")
+	return scheme, err
+}
+
+// Marshal returns the TPMT_PUBLIC of the object
+func (public *Public) Marshal() []byte {
+	var w writer
+	w.uint16(public.Type)
+	w.uint16(public.NameAlg)
+	w.uint32(public.Attributes)
+	w.tpm2b(public.AuthPolicy)
+
+	switch public.Type {
+	case AlgRSA:
+		writeSymDef(&w, public.Symmetric)
+		writeScheme(&w, public.Scheme)
+		w.uint16(public.RSAKeyBits)
+		w.uint32(public.RSAExponent)
+		w.tpm2b(public.Unique)
+	case AlgECC:
+		writeSymDef(&w, public.Symmetric)
+		writeScheme(&w, public.Scheme)
+		w.uint16(public.CurveID)
+		writeScheme(&w, public.KDF)
+		w.tpm2b(public.X)
+		w.tpm2b(public.Y)
+	case AlgSymCipher:
+		writeSymDef(&w, public.Symmetric)
+		w.tpm2b(public.Unique)
+	case AlgKeyedHash:
+		writeScheme(&w, public.Scheme)
+		w.tpm2b(public.Unique)
+	}
+	return w.Bytes()
+}
+
+func writeSymDef(w *writer, symDef SymDef) {
+	w.uint16(symDef.Alg)
+	if symDef.Alg == AlgNull {
+		return
+	}
+	w.uint16(symDef.KeyBits)
+	w.uint16(symDef.Mode)
+}
+
+func writeScheme(w *writer, scheme Scheme) {
+	w.uint16(scheme.Alg)
+	if scheme.Alg == AlgNull || scheme.Alg == AlgRSAES {
+		return
+	}
+	w.uint16(scheme.HashAlg)
+	if scheme.Alg == AlgECDAA {
+		w.uint16(scheme.Count)
+	}
+}
+
+// Name returns the TPM2B_NAME content of the object, its name algorithm followed by the digest of its public area
+func (public *Public) Name() ([]byte, error) {
+	hash, err := HashAlgorithm(public.NameAlg)
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/public:Name() Invalid name algorithm")
+	}
+	h := hash.New()
+	_, _ = h.Write(public.Marshal())
+
+	var w writer
+	w.uint16(public.NameAlg)
+	_, _ = w.Write(h.Sum(nil))
+	return w.Bytes(), nil
+}
+
+// PublicKey returns the public key of the RSA and ECC objects
+func (public *Public) PublicKey() (interface{}, error) {
+	switch public.Type {
+	case AlgRSA:
+		exponent := int(public.RSAExponent)
+		if exponent == 0 {
+			exponent = defaultRSAExponent
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(public.Unique), E: exponent}, nil
+	case AlgECC:
+		curve, err := eccCurve(public.CurveID)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(public.X), Y: new(big.Int).SetBytes(public.Y)}, nil
+	}
+	return nil, errors.Errorf("tpm2/public:PublicKey() Object of type 0x%04x has no public key", public.Type)
+}
+
+func eccCurve(curveID uint16) (elliptic.Curve, error) {
+	switch curveID {
+	case EccNistP256:
+		return elliptic.P256(), nil
+	case EccNistP384:
+		return elliptic.P384(), nil
+	case EccNistP521:
+		return elliptic.P521(), nil
+	}
+	return nil, errors.Errorf("Unsupported ECC curve 0x%04x", curveID)
+}
+
+func eccCurveID(curve elliptic.Curve) (uint16, error) {
+	switch curve {
+	case elliptic.P256():
+		return EccNistP256, nil
+	case elliptic.P384():
+		return EccNistP384, nil
+	case elliptic.P521():
+		return EccNistP521, nil
+	}
+	return 0, errors.Errorf("Unsupported ECC curve %s", curve.Params().Name)
+}
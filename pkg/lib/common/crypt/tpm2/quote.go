@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+
+	"github.com/pkg/errors"
+)
+
+// PCR is a PCR value read from a quote
+type PCR struct {
+	HashAlg uint16
+	Index   int
+	Value   []byte
+}
+
+// Quote is a quote as returned by the trust agent: the TPM2B_ATTEST of the quote, its TPMT_SIGNATURE and the values
+// of the quoted PCRs in the order of the PCR selections
+type Quote struct {
+	AttestBytes []byte
+	Attest      *Attest
+	Signature   *Signature
+	PCRValues   []byte
+}
+
+// ParseQuote parses a quote as returned by the trust agent, it does not verify it
+func ParseQuote(b []byte) (*Quote, error) {
+	var quote Quote
+	var err error
+
+	r := &reader{buf: b}
+	if quote.AttestBytes, err = r.tpm2b("attestation"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/quote:ParseQuote() Error reading the quote attestation")
+	}
+	if quote.Attest, err = ParseAttest(quote.AttestBytes); err != nil {
+		return nil, errors.Wrap(err, "tpm2/quote:ParseQuote() Error parsing the quote attestation")
+	}
+	if quote.Attest.Quote == nil {
+		return nil, errors.Errorf("tpm2/quote:ParseQuote() Attestation of type 0x%04x is not a quote", quote.Attest.Type)
+	}
+	if quote.Signature, err = parseSignature(r); err != nil {
+		return nil, errors.Wrap(err, "tpm2/quote:ParseQuote() Error parsing the quote signature")
+	}
+	quote.PCRValues = r.remaining()
+	return &quote, nil
+}
+
+// PCRs splits the PCR values of the quote according to its PCR selections
+func (quote *Quote) PCRs() ([]PCR, error) {
+	var pcrs []PCR
+	r := &reader{buf: quote.PCRValues}
+	for _, selection := range quote.Attest.Quote.PCRSelections {
+		size, err := DigestSize(selection.HashAlg)
+		if err != nil {
+			return nil, errors.Wrap(err, "tpm2/quote:PCRs() Unsupported PCR bank")
+		}
+		for _, index := range selection.PCRs {
+			value, err := r.next(size, "PCR value")
+			if err != nil {
+				return nil, errors.Wrapf(err, "tpm2/quote:PCRs() Error reading the value of PCR %d", index)
+			}
+			pcrs = append(pcrs, PCR{HashAlg: selection.HashAlg, Index: index, Value: value})
+		}
+	}
+	if len(r.remaining()) != 0 {
+		return nil, errors.Errorf("tpm2/quote:PCRs() %d PCR value bytes are not in the PCR selections", len(r.remaining()))
+	}
+	return pcrs, nil
+}
+
+// Verify checks that the quote is for the nonce, that it is signed by the AIK and that the PCR values match the PCR
+// digest of the quote. It returns the PCR values on success.
+func (quote *Quote) Verify(aikPublicKey crypto.PublicKey, nonce []byte) ([]PCR, error) {
+	if subtle.ConstantTimeCompare(quote.Attest.ExtraData, nonce) != 1 {
+		return nil, errors.New("tpm2/quote:Verify() Challenge and received nonce does not match")
+	}
+	if err := quote.Signature.Verify(aikPublicKey, quote.AttestBytes); err != nil {
+		return nil, errors.Wrap(err, "tpm2/quote:Verify() Error verifying the quote signature")
+	}
+
+	pcrs, err := quote.PCRs()
+	if err != nil {
+		return nil, err
+	}
+	if len(pcrs) == 0 {
+		return nil, errors.New("tpm2/quote:Verify() No PCR values included in quote")
+	}
+	hashAlg, err := HashAlgorithm(quote.Signature.HashAlg)
+	if err != nil {
+		return nil, errors.Wrap(err, "tpm2/quote:Verify() Unsupported quote hash algorithm")
+	}
+	if !bytes.Equal(ComputePCRDigest(hashAlg, pcrs), quote.Attest.Quote.PCRDigest) {
+		return nil, errors.New("tpm2/quote:Verify() Digest of Concatenated PCR values does not match with PCR " +
+			"digest in the quote")
+	}
+	return pcrs, nil
+}
+
+// ComputePCRDigest computes the digest of the concatenated PCR values, the PCR digest of a quote made with hashAlg
+func ComputePCRDigest(hashAlg crypto.Hash, pcrs []PCR) []byte {
+	hash := hashAlg.New()
+	for _, pcr := range pcrs {
+		_, _ = hash.Write(pcr.Value)
+	}
+	return hash.Sum(nil)
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNonce = []byte("0123456789abcdefghij")
+
+func writeTPM2B(buf *bytes.Buffer, b []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(b)))
+	buf.Write(b)
+}
+
+// newTestQuote builds a quote of PCRs 0 and 7 of the SHA256 bank signed with the key, in the trust agent layout
+func newTestQuote(t *testing.T, key *rsa.PrivateKey, nonce []byte) []byte {
+	pcrValues := append(bytes.Repeat([]byte{0x11}, 32), bytes.Repeat([]byte{0x77}, 32)...)
+	pcrDigest := sha256.Sum256(pcrValues)
+
+	var attest bytes.Buffer
+	_ = binary.Write(&attest, binary.BigEndian, GeneratedValue)
+	_ = binary.Write(&attest, binary.BigEndian, StAttestQuote)
+	writeTPM2B(&attest, []byte("signer"))
+	writeTPM2B(&attest, nonce)
+	attest.Write(make([]byte, 17)) // clock info
+	attest.Write(make([]byte, 8))  // firmware version
+	_ = binary.Write(&attest, binary.BigEndian, uint32(1))
+	_ = binary.Write(&attest, binary.BigEndian, AlgSHA256)
+	attest.Write([]byte{3, 0x81, 0x00, 0x00})
+	writeTPM2B(&attest, pcrDigest[:])
+
+	digest := sha256.Sum256(attest.Bytes())
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+
+	var quote bytes.Buffer
+	writeTPM2B(&quote, attest.Bytes())
+	_ = binary.Write(&quote, binary.BigEndian, AlgRSASSA)
+	_ = binary.Write(&quote, binary.BigEndian, AlgSHA256)
+	writeTPM2B(&quote, signature)
+	quote.Write(pcrValues)
+	return quote.Bytes()
+}
+
+func TestQuoteVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	quote, err := ParseQuote(newTestQuote(t, key, testNonce))
+	assert.NoError(t, err)
+	assert.Equal(t, []PCRSelection{{HashAlg: AlgSHA256, PCRs: []int{0, 7}}}, quote.Attest.Quote.PCRSelections)
+
+	pcrs, err := quote.Verify(&key.PublicKey, testNonce)
+	assert.NoError(t, err)
+	assert.Len(t, pcrs, 2)
+	assert.Equal(t, 7, pcrs[1].Index)
+	assert.Equal(t, bytes.Repeat([]byte{0x77}, 32), pcrs[1].Value)
+}
+
+func TestQuoteVerifyInvalidNonce(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	quote, err := ParseQuote(newTestQuote(t, key, testNonce))
+	assert.NoError(t, err)
+	_, err = quote.Verify(&key.PublicKey, []byte("another nonce"))
+	assert.Error(t, err)
+}
+
+func TestQuoteVerifyOtherKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	quote, err := ParseQuote(newTestQuote(t, key, testNonce))
+	assert.NoError(t, err)
+	_, err = quote.Verify(&otherKey.PublicKey, testNonce)
+	assert.Error(t, err)
+}
+
+func TestQuoteVerifyTamperedPCR(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	quoteBytes := newTestQuote(t, key, testNonce)
+	quoteBytes[len(quoteBytes)-1] ^= 0xff
+	quote, err := ParseQuote(quoteBytes)
+	assert.NoError(t, err)
+	_, err = quote.Verify(&key.PublicKey, testNonce)
+	assert.Error(t, err)
+}
+
+func TestParseQuoteTruncated(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	quoteBytes := newTestQuote(t, key, testNonce)
+	for _, size := range []int{0, 1, 10, 60} {
+		_, err = ParseQuote(quoteBytes[:size])
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// reader reads the big endian fields of the TPM structures, every read is checked against the remaining bytes so
+// that truncated structures are reported instead of panicking
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) next(n int, field string) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.buf) {
+		return nil, errors.Errorf("Structure is truncated, %s needs %d bytes at offset %d of %d", field, n, r.pos, len(r.buf))
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) uint8(field string) (uint8, error) {
+	b, err := r.next(1, field)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *reader) uint16(field string) (uint16, error) {
+	b, err := r.next(2, field)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+func (r *reader) uint32(field string) (uint32, error) {
+	b, err := r.next(4, field)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+func (r *reader) uint64(field string) (uint64, error) {
+	b, err := r.next(8, field)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// tpm2b reads a TPM2B structure, a 16 bit size followed by the buffer
+func (r *reader) tpm2b(field string) ([]byte, error) {
+	size, err := r.uint16(field + " size")
+	if err != nil {
+		return nil, err
+	}
+	return r.next(int(size), field)
+}
+
+func (r *reader) remaining() []byte {
+	return r.buf[r.pos:]
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Signature is a TPMT_SIGNATURE, RSA is set for the RSASSA and RSAPSS schemes, R and S for ECDSA
+type Signature struct {
+	Alg     uint16
+	HashAlg uint16
+	RSA     []byte
+	R       *big.Int
+	S       *big.Int
+}
+
+// ParseSignature parses a TPMT_SIGNATURE, it returns the signature and the number of bytes read
+func ParseSignature(b []byte) (*Signature, int, error) {
+	r := &reader{buf: b}
+	signature, err := parseSignature(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return signature, r.pos, nil
+}
+
+func parseSignature(r *reader) (*Signature, error) {
+	var signature Signature
+	var err error
+
+	if signature.Alg, err = r.uint16("signature algorithm"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/signature:parseSignature() Error reading the signature algorithm")
+	}
+	if signature.HashAlg, err = r.uint16("signature hash algorithm"); err != nil {
+		return nil, errors.Wrap(err, "tpm2/signature:parseSignature() Error reading the signature hash algorithm")
+	}
+
+	switch signature.Alg {
+	case AlgRSASSA, AlgRSAPSS:
+		if signature.RSA, err = r.tpm2b("RSA signature"); err != nil {
+			return nil, errors.Wrap(err, "tpm2/signature:parseSignature() Error reading the RSA signature")
+		}
+	case AlgECDSA:
+		rBytes, err := r.tpm2b("ECDSA signature R")
+		if err != nil {
+			return nil, errors.Wrap(err, "tpm2/signature:parseSignature() Error reading the ECDSA signature")
+		}
+		sBytes, err := r.tpm2b("ECDSA signature S")
+		if err != nil {
+			return nil, errors.Wrap(err, "tpm2/signature:parseSignature() Error reading the ECDSA signature")
+		}
+		signature.R = new(big.Int).SetBytes(rBytes)
+		signature.S = new(big.Int).SetBytes(sBytes)
+	default:
+		return nil, errors.Errorf("tpm2/signature:parseSignature() Unsupported signature algorithm 0x%04x", signature.Alg)
+	}
+	return &signature, nil
+}
+
+// Verify checks the signature of the message, e.g. the TPMS_ATTEST of a quote, with the public key of the AIK
+func (signature *Signature) Verify(publicKey crypto.PublicKey, message []byte) error {
+	hashAlg, err := HashAlgorithm(signature.HashAlg)
+	if err != nil {
+		return errors.Wrap(err, "tpm2/signature:Verify() Unsupported signature hash algorithm")
+	}
+	hash := hashAlg.New()
+	_, _ = hash.Write(message)
+	digest := hash.Sum(nil)
+
+	switch signature.Alg {
+	case AlgRSASSA, AlgRSAPSS:
+		rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("tpm2/signature:Verify() RSA signature can't be verified with a non RSA public key")
+		}
+		if signature.Alg == AlgRSAPSS {
+			err = rsa.VerifyPSS(rsaPublicKey, hashAlg, digest, signature.RSA, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+		} else {
+			err = rsa.VerifyPKCS1v15(rsaPublicKey, hashAlg, digest, signature.RSA)
+		}
+		if err != nil {
+			return errors.Wrap(err, "tpm2/signature:Verify() Signature verification failed")
+		}
+	case AlgECDSA:
+		ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("tpm2/signature:Verify() ECDSA signature can't be verified with a non ECDSA public key")
+		}
+		if !ecdsa.Verify(ecdsaPublicKey, digest, signature.R, signature.S) {
+			return errors.New("tpm2/signature:Verify() Signature verification failed")
+		}
+	default:
+		return errors.Errorf("tpm2/signature:Verify() Unsupported signature algorithm 0x%04x", signature.Alg)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpm2
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// writer writes the big endian fields of the TPM structures
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) uint16(v uint16) {
+	_ = binary.Write(w, binary.BigEndian, v)
+}
+
+func (w *writer) uint32(v uint32) {
+	_ = binary.Write(w, binary.BigEndian, v)
+}
+
+// tpm2b writes a TPM2B structure, a 16 bit size followed by the buffer
+func (w *writer) tpm2b(b []byte) {
+	w.uint16(uint16(len(b)))
+	_, _ = w.Write(b)
+}
+
+// tpm2b returns the buffer as a TPM2B structure
+func tpm2b(b []byte) []byte {
+	var w writer
+	w.tpm2b(b)
+	return w.Bytes()
+}
@@ -219,7 +219,7 @@ func GetPrivateKeyFromPem(keyPem []byte) (crypto.PrivateKey, error) {
 	}
 	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: " + err.Error())
+		return nil, fmt.Errorf("failed to parse private key: %s", err.Error())
 	}
 	return key, nil
 }
@@ -232,7 +232,7 @@ func GetPublicKeyFromPem(keyPem []byte) (crypto.PublicKey, error) {
 	}
 	key, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key: " + err.Error())
+		return nil, fmt.Errorf("failed to parse public key: %s", err.Error())
 	}
 	return key, nil
 }
@@ -244,7 +244,7 @@ func GetCertFromPem(certPem []byte) (*x509.Certificate, error) {
 	}
 	cert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse certificate: " + err.Error())
+		return nil, fmt.Errorf("failed to parse certificate: %s", err.Error())
 	}
 	return cert, nil
 }
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/xts"
+)
+
+// XtsSectorSize is the dm-crypt sector size the volumes are encrypted with
+const XtsSectorSize = 512
+
+// GenerateXtsVolumeKey generates a volume encryption key for the dm-crypt aes-xts-plain64 cipher, keyBits is 256
+// (AES-128-XTS) or 512 (AES-256-XTS). The two halves of the key, the data key and the tweak key, are different as
+// required by IEEE 1619.
+func GenerateXtsVolumeKey(keyBits int) ([]byte, error) {
+	if keyBits != 256 && keyBits != 512 {
+		return nil, errors.New("XTS volume keys must be 256 or 512 bits")
+	}
+
+	key := make([]byte, keyBits/8)
+	for {
+		if _, err := rand.Read(key); err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the volume key")
+		}
+		if !bytes.Equal(key[:len(key)/2], key[len(key)/2:]) {
+			return key, nil
+		}
+	}
+}
+
+// NewXtsCipher returns the AES-XTS cipher of a 32 or 64 bytes volume key. The sector numbers are the tweaks of
+// the plain64 IV generator of dm-crypt, so that the sectors it encrypts can be decrypted by dm-crypt.
+func NewXtsCipher(key []byte) (*xts.Cipher, error) {
+	if len(key) != 32 && len(key) != 64 {
+		return nil, errors.New("XTS volume keys must be 32 or 64 bytes")
+	}
+	if bytes.Equal(key[:len(key)/2], key[len(key)/2:]) {
+		return nil, errors.New("The data key and the tweak key of an XTS volume key must be different")
+	}
+	c, err := xts.NewCipher(aes.NewCipher, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize the AES-XTS cipher")
+	}
+	return c, nil
+}
+
+// EncryptXtsSectors encrypts data, a whole number of sectors, with AES-XTS starting at the sector number
+func EncryptXtsSectors(key, data []byte, firstSector uint64) ([]byte, error) {
+	return cryptXtsSectors(key, data, firstSector, true)
+}
+
+// DecryptXtsSectors decrypts data, a whole number of sectors, with AES-XTS starting at the sector number
+func DecryptXtsSectors(key, data []byte, firstSector uint64) ([]byte, error) {
+	return cryptXtsSectors(key, data, firstSector, false)
+}
+
+func cryptXtsSectors(key, data []byte, firstSector uint64, encrypt bool) ([]byte, error) {
+	if len(data)%XtsSectorSize != 0 {
+		return nil, errors.Errorf("The data must be a whole number of %d bytes sectors", XtsSectorSize)
+	}
+	c, err := NewXtsCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(data))
+	for offset, sector := 0, firstSector; offset < len(data); offset, sector = offset+XtsSectorSize, sector+1 {
+		if encrypt {
+			c.Encrypt(out[offset:offset+XtsSectorSize], data[offset:offset+XtsSectorSize], sector)
+		} else {
+			c.Decrypt(out[offset:offset+XtsSectorSize], data[offset:offset+XtsSectorSize], sector)
+		}
+	}
+	return out, nil
+}
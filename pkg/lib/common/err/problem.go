@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package err
+
+import "net/http"
+
+// ProblemTypeBase prefixes the type URIs of the problems returned by the services. The type of a problem never
+// changes for a status, the clients can match it instead of the free-form detail.
+const ProblemTypeBase = "urn:intel-secl:problem:"
+
+// The problem types of the specific errors, the other errors have the type of their status
+const (
+	ProblemTypeIdempotencyKeyReused     = ProblemTypeBase + "idempotency-key-reused"
+	ProblemTypeIdempotencyKeyInProgress = ProblemTypeBase + "idempotency-key-in-progress"
+)
+
+var statusProblemTypes = map[int]string{
+	http.StatusBadRequest:            "bad-request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not-found",
+	http.StatusMethodNotAllowed:      "method-not-allowed",
+	http.StatusNotAcceptable:         "not-acceptable",
+	http.StatusConflict:              "conflict",
+	http.StatusPreconditionFailed:    "precondition-failed",
+	http.StatusRequestEntityTooLarge: "payload-too-large",
+	http.StatusUnsupportedMediaType:  "unsupported-media-type",
+	http.StatusUnprocessableEntity:   "unprocessable-entity",
+	http.StatusTooManyRequests:       "too-many-requests",
+	http.StatusInternalServerError:   "internal-error",
+	http.StatusNotImplemented:        "not-implemented",
+	http.StatusBadGateway:            "bad-gateway",
+	http.StatusServiceUnavailable:    "service-unavailable",
+	http.StatusGatewayTimeout:        "gateway-timeout",
+}
+
+// ProblemDetails is an RFC 7807 error response with the application/problem+json media type. CorrelationID is the
+// ID the service logged the error with, it is also returned in the X-Correlation-ID header of the response.
+type ProblemDetails struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// ProblemError is returned by the handlers to render a problem of a specific type
+type ProblemError struct {
+	Type       string
+	StatusCode int
+	Message    string
+}
+
+func (e ProblemError) Error() string {
+	return e.Message
+}
+
+// ProblemType returns the type URI of the problems of the status
+func ProblemType(status int) string {
+	if problemType, ok := statusProblemTypes[status]; ok {
+		return ProblemTypeBase + problemType
+	}
+	return "about:blank"
+}
+
+// NewProblemDetails returns the problem of the status, the type of the status is used when problemType is empty
+func NewProblemDetails(problemType string, status int, detail string) ProblemDetails {
+	if problemType == "" {
+		problemType = ProblemType(status)
+	}
+	return ProblemDetails{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
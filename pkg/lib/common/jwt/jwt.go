@@ -9,6 +9,7 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
@@ -127,6 +128,13 @@ type Verifier interface {
 	ValidateTokenAndGetClaims(tokenString string, customClaims interface{}) (*Token, error)
 }
 
+// certKeyId returns the key id of a signing certificate, the hex SHA1 of the certificate. It only identifies the
+// certificate, so it is computed outside of the crypt package which rejects SHA1 in FIPS mode.
+func certKeyId(cert *x509.Certificate) string {
+	hash := sha1.Sum(cert.Raw)
+	return hex.EncodeToString(hash[:])
+}
+
 func getJwtSigningMethod(privKey crypto.PrivateKey) (jwt.SigningMethod, error) {
 
 	switch key := privKey.(type) {
@@ -180,8 +188,7 @@ func NewTokenFactory(pkcs8der []byte, includeKeyIdInToken bool, signingCertPem [
 		if err != nil {
 			return nil, fmt.Errorf("NewTokenFactory: failed to parse certificate: " + err.Error())
 		}
-		hash, _ := crypt.GetHashData(cert.Raw, crypto.SHA1)
-		keyId = hex.EncodeToString(hash)
+		keyId = certKeyId(cert)
 
 	}
 
@@ -358,10 +365,7 @@ func NewVerifier(signingCertPems interface{}, rootCAPems [][]byte, cacheTime tim
 			}
 		}
 
-		certHash, err := crypt.GetCertHashInHex(cert, crypto.SHA1)
-		if err != nil {
-			continue
-		}
+		certHash := certKeyId(cert)
 		pubKey, err := crypt.GetPublicKeyFromCert(cert)
 		if err != nil {
 			continue
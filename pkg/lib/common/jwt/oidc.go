@@ -0,0 +1,365 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/Waterdrips/jwt-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	// oidcMinRefreshInterval limits the downloads of the signing keys caused by tokens signed by an unknown key
+	oidcMinRefreshInterval = time.Minute
+	oidcMaxDocumentBytes   = 1 << 20
+)
+
+// oidcSigningMethods are the asymmetric algorithms accepted for the OIDC tokens, the symmetric and none algorithms
+// are rejected
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OIDCClaims are the claims of a validated OIDC access token
+type OIDCClaims struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+	// ClientId is the client the token was issued to, from the azp or client_id claim
+	ClientId string
+	// Scopes are the scopes granted to the client, from the scope or scp claim
+	Scopes []string
+}
+
+// OIDCVerifier validates the access tokens issued by an OpenID Connect provider, e.g. to the clients of the
+// client credentials grant. The signing keys are discovered from the openid-configuration of the issuer when the
+// first token is validated and cached, they are downloaded again when the cache expires or when a token is signed
+// by an unknown key so that the provider can rotate them.
+type OIDCVerifier struct {
+	issuer     string
+	audience   string
+	httpClient *http.Client
+	cacheTime  time.Duration
+
+	refreshMtx sync.Mutex
+	keysMtx    sync.RWMutex
+	keys       map[string]crypto.PublicKey
+	expiration time.Time
+	lastFetch  time.Time
+}
+
+// NewOIDCVerifier returns a verifier of the tokens issued by the issuer for the audience, the discovery document
+// and the signing keys are downloaded with the http client
+func NewOIDCVerifier(issuer, audience string, httpClient *http.Client, cacheTime time.Duration) (*OIDCVerifier, error) {
+	if issuer == "" || audience == "" {
+		return nil, errors.New("OIDC issuer and audience must be specified")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if cacheTime < oidcMinRefreshInterval {
+		cacheTime = oidcMinRefreshInterval
+	}
+	return &OIDCVerifier{
+		issuer:     strings.TrimSuffix(issuer, "/"),
+		audience:   audience,
+		httpClient: httpClient,
+		cacheTime:  cacheTime,
+	}, nil
+}
+
+// IsIssuerOf returns true if the unverified issuer of the token is the issuer of the verifier, the callers use it
+// to select the verifier of a token before validating it
+func (v *OIDCVerifier) IsIssuerOf(tokenString string) bool {
+	var claims jwt.MapClaims
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenString, &claims); err != nil {
+		return false
+	}
+	issuer, _ := claims["iss"].(string)
+	return strings.TrimSuffix(issuer, "/") == v.issuer
+}
+
+// ValidateToken verifies the signature, issuer, audience and validity period of the token and returns its claims
+func (v *OIDCVerifier) ValidateToken(tokenString string) (*OIDCClaims, error) {
+	claims := oidcClaims{}
+	parser := jwt.Parser{ValidMethods: oidcSigningMethods}
+	if _, err := parser.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		keyId, _ := token.Header["kid"].(string)
+		return v.signingKey(keyId)
+	}); err != nil {
+		return nil, errors.Wrap(err, "OIDC token validation failed")
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != v.issuer {
+		return nil, errors.Errorf("OIDC token issuer %s is not trusted", claims.Issuer)
+	}
+	audienceFound := false
+	for _, audience := range claims.Audience {
+		if audience == v.audience {
+			audienceFound = true
+			break
+		}
+	}
+	if !audienceFound {
+		return nil, errors.Errorf("OIDC token is not issued for the audience %s", v.audience)
+	}
+
+	oidcClaims := OIDCClaims{
+		Issuer:   claims.Issuer,
+		Subject:  claims.Subject,
+		Audience: claims.Audience,
+		ClientId: claims.AuthorizedParty,
+		Scopes:   append(claims.Scope, claims.Scp...),
+	}
+	if oidcClaims.ClientId == "" {
+		oidcClaims.ClientId = claims.ClientId
+	}
+	return &oidcClaims, nil
+}
+
+// signingKey returns the key of the issuer with the key id, the only key of the issuer is used for the tokens
+// without a key id
+func (v *OIDCVerifier) signingKey(keyId string) (crypto.PublicKey, error) {
+	v.keysMtx.RLock()
+	key, found := v.lookupKey(keyId)
+	expired := time.Now().After(v.expiration)
+	v.keysMtx.RUnlock()
+	if found && !expired {
+		return key, nil
+	}
+
+	if err := v.refreshKeys(expired); err != nil {
+		return nil, err
+	}
+	v.keysMtx.RLock()
+	defer v.keysMtx.RUnlock()
+	if key, found = v.lookupKey(keyId); !found {
+		return nil, &MatchingCertNotFoundError{keyId}
+	}
+	return key, nil
+}
+
+func (v *OIDCVerifier) lookupKey(keyId string) (crypto.PublicKey, bool) {
+	if keyId == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, found := v.keys[keyId]
+	return key, found && keyId != ""
+}
+
+// refreshKeys downloads the signing keys of the issuer, the keys are not downloaded more than once per
+// oidcMinRefreshInterval unless they expired
+func (v *OIDCVerifier) refreshKeys(expired bool) error {
+	v.refreshMtx.Lock()
+	defer v.refreshMtx.Unlock()
+
+	v.keysMtx.RLock()
+	recentlyFetched := time.Since(v.lastFetch) < oidcMinRefreshInterval
+	stillExpired := time.Now().After(v.expiration)
+	v.keysMtx.RUnlock()
+	if recentlyFetched && !(expired && stillExpired) {
+		return nil
+	}
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JwksUri string `json:"jwks_uri"`
+	}
+	if err := v.getJson(v.issuer+oidcDiscoveryPath, &discovery); err != nil {
+		return errors.Wrap(err, "Failed to retrieve the OIDC provider configuration")
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != v.issuer {
+		return errors.Errorf("OIDC provider configuration is for the issuer %s instead of %s", discovery.Issuer, v.issuer)
+	}
+	if discovery.JwksUri == "" {
+		return errors.New("OIDC provider configuration does not have a jwks_uri")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJson(discovery.JwksUri, &jwks); err != nil {
+		return errors.Wrap(err, "Failed to retrieve the OIDC provider signing keys")
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// the keys of unsupported types are skipped, the tokens signed by them are rejected
+			continue
+		}
+		keys[jwk.KeyId] = key
+	}
+
+	v.keysMtx.Lock()
+	defer v.keysMtx.Unlock()
+	v.keys = keys
+	v.lastFetch = time.Now()
+	v.expiration = v.lastFetch.Add(v.cacheTime)
+	return nil
+}
+
+func (v *OIDCVerifier) getJson(url string, document interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, oidcMaxDocumentBytes)).Decode(document)
+}
+
+// jsonWebKey is a public key of the JWKS of an OIDC provider, RFC 7517
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyId   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeJwkInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJwkInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 {
+			return nil, errors.New("invalid RSA public exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %s", jwk.Curve)
+		}
+		x, err := decodeJwkInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJwkInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC public key is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %s", jwk.KeyType)
+	}
+}
+
+func decodeJwkInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil || len(bytes) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// oidcClaims are the claims of the OIDC access tokens, the audience and scopes are a string or a list
+type oidcClaims struct {
+	Issuer          string      `json:"iss"`
+	Subject         string      `json:"sub"`
+	Audience        stringList  `json:"aud"`
+	AuthorizedParty string      `json:"azp"`
+	ClientId        string      `json:"client_id"`
+	Scope           stringList  `json:"scope"`
+	Scp             stringList  `json:"scp"`
+	ExpiresAt       json.Number `json:"exp"`
+	NotBefore       json.Number `json:"nbf"`
+	IssuedAt        json.Number `json:"iat"`
+}
+
+// Valid checks the validity period of the token, allowing for the clock skew of the provider
+func (c oidcClaims) Valid() error {
+	now := time.Now()
+	expiresAt, err := c.ExpiresAt.Float64()
+	if err != nil {
+		return errors.New("token does not have a valid exp claim")
+	}
+	if now.After(time.Unix(int64(expiresAt), 0).Add(gracePeriodForClockSkew)) {
+		return fmt.Errorf("token expired at %v", time.Unix(int64(expiresAt), 0))
+	}
+	if notBefore, err := c.NotBefore.Float64(); err == nil && now.Add(gracePeriodForClockSkew).Before(time.Unix(int64(notBefore), 0)) {
+		return fmt.Errorf("token is not valid before %v", time.Unix(int64(notBefore), 0))
+	}
+	if issuedAt, err := c.IssuedAt.Float64(); err == nil && now.Add(gracePeriodForClockSkew).Before(time.Unix(int64(issuedAt), 0)) {
+		return fmt.Errorf("token is issued in the future at %v", time.Unix(int64(issuedAt), 0))
+	}
+	return nil
+}
+
+// stringList is a claim holding a string or a list of strings, the strings are split on spaces as in the scope
+// claim
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+	case string:
+		*l = strings.Fields(v)
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return errors.New("claim list must only have strings")
+			}
+			list = append(list, s)
+		}
+		*l = list
+	default:
+		return errors.New("claim must be a string or a list of strings")
+	}
+	return nil
+}
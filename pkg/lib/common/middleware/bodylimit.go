@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+
+	"github.com/gorilla/mux"
+)
+
+// DefaultMaxBodyBytes is the request body size limit applied when no limit is configured
+const DefaultMaxBodyBytes = 1 << 20
+
+// NewBodyLimit returns a middleware that rejects request bodies larger than maxBodyBytes and
+// request bodies sent with a content encoding. Compressed payloads are never decoded by the
+// services, so rejecting them up front prevents decompression bombs from reaching any handler.
+// The request body is wrapped so that handlers reading an unannounced or chunked body also
+// fail once the limit is reached.
+func NewBodyLimit(maxBodyBytes int64) mux.MiddlewareFunc {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentEncoding := strings.TrimSpace(r.Header.Get("Content-Encoding"))
+			if contentEncoding != "" && !strings.EqualFold(contentEncoding, "identity") {
+				slog.Warningf("middleware/bodylimit:NewBodyLimit() %s : Request with Content-Encoding %s rejected from %s",
+					commLogMsg.InvalidInputBadEncoding, contentEncoding, r.RemoteAddr)
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			if r.ContentLength > maxBodyBytes {
+				slog.Warningf("middleware/bodylimit:NewBodyLimit() %s : Request body of %d bytes exceeds the limit of %d bytes from %s",
+					commLogMsg.InvalidInputBadParam, r.ContentLength, maxBodyBytes, r.RemoteAddr)
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	ct "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	"github.com/pkg/errors"
+)
+
+// AuthMode selects how the callers of a route group are authenticated
+type AuthMode string
+
+const (
+	// AuthModeJwt authenticates the callers with a bearer token issued by AAS
+	AuthModeJwt AuthMode = "jwt"
+	// AuthModeMtls authenticates the callers with a client certificate instead of a bearer token
+	AuthModeMtls AuthMode = "mtls"
+	// AuthModeJwtAndMtls requires both a bearer token and a client certificate
+	AuthModeJwtAndMtls AuthMode = "jwt+mtls"
+)
+
+// RequiresClientCert returns true when the mode authenticates the callers with a client certificate, the servers
+// must then request the client certificates during the TLS handshake
+func (m AuthMode) RequiresClientCert() bool {
+	return m == AuthModeMtls || m == AuthModeJwtAndMtls
+}
+
+// RouteGroupAuth declares the authentication of a route group, the routers apply it with UseRouteGroupAuth
+type RouteGroupAuth struct {
+	Mode AuthMode
+	// TrustedCAsDir holds the CA certificates the client certificates are verified against, the CMS root CA
+	TrustedCAsDir string
+	// AllowedCommonNames restricts the client certificates to these subject common names, any client certificate
+	// issued by a trusted CA is accepted when empty
+	AllowedCommonNames []string
+	// Permissions are granted to the callers authenticated by their client certificate only, they replace the
+	// permissions of the bearer token in AuthModeMtls
+	Permissions []ct.PermissionInfo
+}
+
+// UseRouteGroupAuth adds the authentication middlewares of the route group to its router, tokenAuth is the
+// bearer token middleware of the service
+func UseRouteGroupAuth(router *mux.Router, auth RouteGroupAuth, tokenAuth mux.MiddlewareFunc) error {
+	switch auth.Mode {
+	case "", AuthModeJwt:
+		router.Use(tokenAuth)
+	case AuthModeMtls:
+		router.Use(NewClientCertAuth(auth.TrustedCAsDir, auth.AllowedCommonNames))
+		router.Use(grantPermissions(auth.Permissions))
+	case AuthModeJwtAndMtls:
+		router.Use(NewClientCertAuth(auth.TrustedCAsDir, auth.AllowedCommonNames))
+		router.Use(tokenAuth)
+	default:
+		return errors.Errorf("Unsupported authentication mode %s, it must be %s, %s or %s", auth.Mode,
+			AuthModeJwt, AuthModeMtls, AuthModeJwtAndMtls)
+	}
+	return nil
+}
+
+// NewClientCertAuth returns a middleware rejecting the requests without a TLS client certificate issued by one of
+// the CAs in trustedCAsDir for client authentication. The CAs are read on each request so that they can be rotated
+// without restarting the service. The common name of the certificate is the token subject of the request.
+func NewClientCertAuth(trustedCAsDir string, allowedCommonNames []string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+				log.Error("no client certificate provided for authentication")
+				w.WriteHeader(http.StatusUnauthorized)
+				slog.Warningf("%s: No client certificate, requested from %s: ", commLogMsg.AuthenticationFailed, r.RemoteAddr)
+				return
+			}
+
+			caCerts, err := crypt.GetCertsFromDir(trustedCAsDir)
+			if err != nil {
+				log.WithError(err).Errorf("failed to read the trusted CA certificates from %s", trustedCAsDir)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var intermediateCerts []x509.Certificate
+			for _, cert := range r.TLS.PeerCertificates[1:] {
+				intermediateCerts = append(intermediateCerts, *cert)
+			}
+			clientCert := r.TLS.PeerCertificates[0]
+			if _, err = clientCert.Verify(x509.VerifyOptions{
+				Roots:         crypt.GetCertPool(caCerts),
+				Intermediates: crypt.GetCertPool(intermediateCerts),
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}); err != nil {
+				log.WithError(err).Error("client certificate verification failure")
+				w.WriteHeader(http.StatusUnauthorized)
+				slog.Warningf("%s: Invalid client certificate %s, requested from %s: ", commLogMsg.AuthenticationFailed,
+					clientCert.Subject.CommonName, r.RemoteAddr)
+				return
+			}
+
+			if !commonNameAllowed(clientCert.Subject.CommonName, allowedCommonNames) {
+				w.WriteHeader(http.StatusUnauthorized)
+				slog.Warningf("%s: Client certificate %s is not allowed, requested from %s: ", commLogMsg.UnauthorizedAccess,
+					clientCert.Subject.CommonName, r.RemoteAddr)
+				return
+			}
+
+			r = context.SetTokenSubject(r, clientCert.Subject.CommonName)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// grantPermissions returns a middleware setting the permissions of the requests authenticated by a client
+// certificate
+func grantPermissions(permissions []ct.PermissionInfo) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = context.SetUserPermissions(r, permissions)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func commonNameAllowed(commonName string, allowedCommonNames []string) bool {
+	if len(allowedCommonNames) == 0 {
+		return true
+	}
+	for _, allowed := range allowedCommonNames {
+		if commonName == allowed {
+			return true
+		}
+	}
+	return false
+}
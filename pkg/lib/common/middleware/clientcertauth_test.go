@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	ct "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCert(t *testing.T, commonName string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+func TestClientCertAuth(t *testing.T) {
+	caDir, err := ioutil.TempDir("", "trustedca")
+	assert.NoError(t, err)
+	defer os.RemoveAll(caDir)
+
+	caCert, caKey := createTestCert(t, "CMS Root CA", true, nil, nil)
+	err = ioutil.WriteFile(filepath.Join(caDir, "root.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}), 0600)
+	assert.NoError(t, err)
+	agentCert, _ := createTestCert(t, "trust-agent", false, caCert, caKey)
+	otherCert, _ := createTestCert(t, "other", false, caCert, caKey)
+	untrustedCert, _ := createTestCert(t, "trust-agent", false, nil, nil)
+
+	tokenAuth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	newRouter := func(mode AuthMode) *mux.Router {
+		router := mux.NewRouter()
+		err := UseRouteGroupAuth(router, RouteGroupAuth{
+			Mode:               mode,
+			TrustedCAsDir:      caDir,
+			AllowedCommonNames: []string{"trust-agent"},
+			Permissions:        []ct.PermissionInfo{{Service: "HVS", Rules: []string{"quote_callbacks:create"}}},
+		}, tokenAuth)
+		assert.NoError(t, err)
+		router.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+			subject, _ := context.GetTokenSubject(r)
+			assert.Equal(t, "trust-agent", subject)
+			if mode == AuthModeMtls {
+				permissions, err := context.GetUserPermissions(r)
+				assert.NoError(t, err)
+				assert.Equal(t, "quote_callbacks:create", permissions[0].Rules[0])
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		return router
+	}
+	serve := func(router *mux.Router, cert *x509.Certificate, token bool) int {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if cert != nil {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		}
+		if token {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	mtlsRouter := newRouter(AuthModeMtls)
+	assert.Equal(t, http.StatusOK, serve(mtlsRouter, agentCert, false))
+	assert.Equal(t, http.StatusUnauthorized, serve(mtlsRouter, nil, true))
+	assert.Equal(t, http.StatusUnauthorized, serve(mtlsRouter, otherCert, false))
+	assert.Equal(t, http.StatusUnauthorized, serve(mtlsRouter, untrustedCert, false))
+
+	bothRouter := newRouter(AuthModeJwtAndMtls)
+	assert.Equal(t, http.StatusOK, serve(bothRouter, agentCert, true))
+	assert.Equal(t, http.StatusUnauthorized, serve(bothRouter, agentCert, false))
+	assert.Equal(t, http.StatusUnauthorized, serve(bothRouter, nil, true))
+
+	assert.Error(t, UseRouteGroupAuth(mux.NewRouter(), RouteGroupAuth{Mode: "basic"}, tokenAuth))
+	assert.True(t, AuthModeMtls.RequiresClientCert())
+	assert.False(t, AuthModeJwt.RequiresClientCert())
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	jwtauth "github.com/intel-secl/intel-secl/v3/pkg/lib/common/jwt"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	ct "github.com/intel-secl/intel-secl/v3/pkg/model/aas"
+)
+
+// OIDCAuth declares the authorization of the callers presenting an access token of an OpenID Connect provider
+type OIDCAuth struct {
+	Verifier *jwtauth.OIDCVerifier
+	// Service is the service of the permissions granted to the scopes
+	Service string
+	// ScopePermissions are the permission rules granted to each scope of the tokens
+	ScopePermissions map[string][]string
+}
+
+// NewOIDCTokenAuth returns a middleware authorizing the bearer tokens issued by the OIDC provider with the
+// permissions mapped from their scopes. The other requests are authorized by tokenAuth, the AAS token middleware.
+// The client id of the token, or its subject, is the token subject of the request.
+func NewOIDCTokenAuth(auth OIDCAuth, tokenAuth mux.MiddlewareFunc) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		aasTokenAuth := tokenAuth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			splitAuthHeader := strings.Split(r.Header.Get("Authorization"), "Bearer ")
+			if len(splitAuthHeader) <= 1 || !auth.Verifier.IsIssuerOf(strings.TrimSpace(splitAuthHeader[1])) {
+				aasTokenAuth.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := auth.Verifier.ValidateToken(strings.TrimSpace(splitAuthHeader[1]))
+			if err != nil {
+				log.WithError(err).Error("OIDC token validation failure")
+				w.WriteHeader(http.StatusUnauthorized)
+				slog.Warningf("%s: Invalid OIDC token, requested from %s: ", commLogMsg.AuthenticationFailed, r.RemoteAddr)
+				return
+			}
+
+			subject := claims.ClientId
+			if subject == "" {
+				subject = claims.Subject
+			}
+			r = context.SetUserPermissions(r, scopePermissions(auth.Service, auth.ScopePermissions, claims.Scopes))
+			r = context.SetTokenSubject(r, subject)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// scopePermissions returns the permissions of the service granted to the scopes, the scopes without a mapping
+// grant nothing. The scopes are compared case insensitively since the configuration keys are lower cased.
+func scopePermissions(service string, permissionsByScope map[string][]string, scopes []string) []ct.PermissionInfo {
+	var rules []string
+	for _, scope := range scopes {
+		for mappedScope, mappedRules := range permissionsByScope {
+			if strings.EqualFold(scope, mappedScope) {
+				rules = append(rules, mappedRules...)
+			}
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return []ct.PermissionInfo{{Service: service, Rules: rules}}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/Waterdrips/jwt-go"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	jwtauth "github.com/intel-secl/intel-secl/v3/pkg/lib/common/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCTokenAuth(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	var issuer string
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/keys"})
+		case "/keys":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(signingKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(signingKey.E)).Bytes()),
+			}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer idp.Close()
+	issuer = idp.URL
+
+	newToken := func(key *rsa.PrivateKey, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key1"
+		tokenString, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return tokenString
+	}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   issuer,
+			"sub":   "service-account",
+			"aud":   []string{"kbs"},
+			"azp":   "key-transfer-client",
+			"scope": "openid kbs.transfer",
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	verifier, err := jwtauth.NewOIDCVerifier(issuer, "kbs", idp.Client(), time.Hour)
+	assert.NoError(t, err)
+	tokenAuth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	}
+	router := mux.NewRouter()
+	router.Use(NewOIDCTokenAuth(OIDCAuth{
+		Verifier:         verifier,
+		Service:          "KBS",
+		ScopePermissions: map[string][]string{"kbs.transfer": {"keys:transfer"}},
+	}, tokenAuth))
+	router.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		permissions, err := context.GetUserPermissions(r)
+		assert.NoError(t, err)
+		subject, err := context.GetTokenSubject(r)
+		assert.NoError(t, err)
+		if len(permissions) == 1 && permissions[0].Service == "KBS" && permissions[0].Rules[0] == "keys:transfer" &&
+			subject == "key-transfer-client" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	request := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/keys", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// a valid token is granted the permissions of its scopes
+	assert.Equal(t, http.StatusOK, request(newToken(signingKey, validClaims())))
+
+	// the tokens of other issuers are left to the AAS token middleware
+	claims := validClaims()
+	claims["iss"] = "AAS JWT Issuer"
+	assert.Equal(t, http.StatusTeapot, request(newToken(signingKey, claims)))
+
+	// the tokens of the provider with an invalid signature, audience or validity are rejected
+	assert.Equal(t, http.StatusUnauthorized, request(newToken(otherKey, validClaims())))
+	claims = validClaims()
+	claims["aud"] = "other-service"
+	assert.Equal(t, http.StatusUnauthorized, request(newToken(signingKey, claims)))
+	claims = validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	assert.Equal(t, http.StatusUnauthorized, request(newToken(signingKey, claims)))
+
+	// the symmetric algorithms are rejected
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	hmacToken.Header["kid"] = "key1"
+	tokenString, err := hmacToken.SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, request(tokenString))
+}
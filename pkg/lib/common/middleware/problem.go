@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/context"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+)
+
+// CorrelationIDHeader carries the correlation ID of a request, it is returned with every response
+const CorrelationIDHeader = "X-Correlation-ID"
+
+var correlationIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// NewCorrelationID returns a middleware setting the correlation ID of the requests in their context and in the
+// X-Correlation-ID header of the responses. The ID sent by the client is kept so that the calls made for the same
+// operation across the services can be related, a new ID is generated when there is none or it is not valid.
+func NewCorrelationID() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			correlationID := r.Header.Get(CorrelationIDHeader)
+			if !correlationIDPattern.MatchString(correlationID) {
+				correlationID = uuid.New().String()
+			}
+			w.Header().Set(CorrelationIDHeader, correlationID)
+			next.ServeHTTP(w, context.SetCorrelationID(r, correlationID))
+		})
+	}
+}
+
+// NewProblemDetails returns a middleware rendering the error responses of the routes as RFC 7807 problem details,
+// the free-form message of the error becomes the detail of the problem. The details of the server errors are only
+// logged with the correlation ID of the request, they are not returned to the clients.
+func NewProblemDetails() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			problemWriter := &problemResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(problemWriter, r)
+			if problemWriter.problem {
+				WriteProblem(w, r, commErr.NewProblemDetails("", problemWriter.status, problemWriter.body.String()))
+			}
+		})
+	}
+}
+
+// WriteProblem writes the problem details with the instance and the correlation ID of the request
+func WriteProblem(w http.ResponseWriter, r *http.Request, problem commErr.ProblemDetails) {
+	problem.Detail = strings.TrimSpace(problem.Detail)
+	if problem.Detail == problem.Title {
+		problem.Detail = ""
+	}
+	problem.Instance = r.URL.Path
+	problem.CorrelationID, _ = context.GetCorrelationID(r)
+	if problem.Status >= http.StatusInternalServerError && problem.Detail != "" {
+		log.WithField("correlation_id", problem.CorrelationID).Errorf("middleware/problem:WriteProblem() %s %s failed: %s",
+			r.Method, problem.Instance, problem.Detail)
+		problem.Detail = ""
+	}
+
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", constants.HTTPMediaTypeProblemJson)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.WithError(err).Error("middleware/problem:WriteProblem() Error writing problem details")
+	}
+}
+
+// problemResponseWriter holds back the error responses that are not problem details yet
+type problemResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	problem bool
+	body    bytes.Buffer
+}
+
+func (w *problemResponseWriter) WriteHeader(statusCode int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = statusCode
+	w.problem = statusCode >= http.StatusBadRequest &&
+		!strings.HasPrefix(w.Header().Get("Content-Type"), constants.HTTPMediaTypeProblemJson)
+	if !w.problem {
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (w *problemResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.problem {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v3/pkg/lib/common/err"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemDetails(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(NewCorrelationID(), NewProblemDetails())
+	router.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", constants.HTTPMediaTypeJson)
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}).Methods("GET")
+	router.HandleFunc("/keys/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Key with given ID does not exist", http.StatusNotFound)
+	}).Methods("GET")
+	router.HandleFunc("/keys/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "pq: connection refused", http.StatusInternalServerError)
+	}).Methods("DELETE")
+	router.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		// the handlers writing the status before the error are rendered once
+		w.WriteHeader(http.StatusUnauthorized)
+		http.Error(w, "Insufficient privileges to access /keys", http.StatusUnauthorized)
+	}).Methods("POST")
+
+	// the responses of the successful requests are kept
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/keys", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"keys":[]}`, w.Body.String())
+	assert.NotEmpty(t, w.Header().Get(CorrelationIDHeader))
+
+	// the message of the error is the detail of the problem
+	r := httptest.NewRequest("GET", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+	r.Header.Set(CorrelationIDHeader, "transfer-42")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, constants.HTTPMediaTypeProblemJson, w.Header().Get("Content-Type"))
+	assert.Equal(t, "transfer-42", w.Header().Get(CorrelationIDHeader))
+	var problem commErr.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, commErr.ProblemDetails{
+		Type:          "urn:intel-secl:problem:not-found",
+		Title:         "Not Found",
+		Status:        http.StatusNotFound,
+		Detail:        "Key with given ID does not exist",
+		Instance:      "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2",
+		CorrelationID: "transfer-42",
+	}, problem)
+
+	// the details of the server errors are not returned, an invalid correlation ID is replaced
+	r = httptest.NewRequest("DELETE", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2", nil)
+	r.Header.Set(CorrelationIDHeader, "invalid id\n")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	problem = commErr.ProblemDetails{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "urn:intel-secl:problem:internal-error", problem.Type)
+	assert.Empty(t, problem.Detail)
+	assert.NotEqual(t, "invalid id\n", problem.CorrelationID)
+	assert.Equal(t, w.Header().Get(CorrelationIDHeader), problem.CorrelationID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/keys", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	problem = commErr.ProblemDetails{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "Insufficient privileges to access /keys", problem.Detail)
+}
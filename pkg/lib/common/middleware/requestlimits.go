@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
+	commLogMsg "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log/message"
+	"github.com/pkg/errors"
+)
+
+// ErrResponseTooLarge is returned by the writes of a response exceeding the response size limit
+var ErrResponseTooLarge = errors.New("Response exceeds the size limit")
+
+// NewRequestLimits returns a middleware applying the request and response limits of the server configuration, in
+// addition to the header and read timeouts the server applies to the connections:
+//   - the request bodies are limited as by NewBodyLimit to MaxBodyBytes, or to the limit of the longest path of
+//     MaxBodyBytesByPath contained in the request path, e.g. "/flavors" for the flavor uploads
+//   - the request bodies must be received within BodyReadTimeout, they are then read before the handler is called
+//     so that a client trickling its body is rejected with 408 instead of holding the handler and its buffers. The
+//     servers wrapping the response writers set ConnContext as the ConnContext of their http.Server
+//   - the responses are limited to MaxResponseBytes, the connection is closed when a handler writes more so that
+//     the client cannot take the truncated response for a complete one
+func NewRequestLimits(cfg config.ServerConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if cfg.BodyReadTimeout > 0 {
+			next = newBodyReadTimeout(next, cfg.BodyReadTimeout)
+		}
+		bodyLimits := map[string]http.Handler{"": NewBodyLimit(cfg.MaxBodyBytes)(next)}
+		for path, maxBodyBytes := range cfg.MaxBodyBytesByPath {
+			// viper lowers the case of the map keys, the paths are matched regardless of their case
+			path = "/" + strings.Trim(strings.ToLower(path), "/")
+			bodyLimits[path] = NewBodyLimit(maxBodyBytes)(next)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.MaxResponseBytes > 0 {
+				w = &limitedResponseWriter{ResponseWriter: w, remaining: cfg.MaxResponseBytes, request: r}
+			}
+			bodyLimits[matchBodyLimitPath(r.URL.Path, bodyLimits)].ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchBodyLimitPath returns the longest path of the body limits contained in the request path as a sequence of
+// segments, the empty path of the default limit when none is
+func matchBodyLimitPath(requestPath string, bodyLimits map[string]http.Handler) string {
+	requestPath = strings.ToLower(requestPath)
+	match := ""
+	for path := range bodyLimits {
+		if path == "" || len(path) <= len(match) {
+			continue
+		}
+		for i := strings.Index(requestPath, path); i >= 0; {
+			end := i + len(path)
+			if end == len(requestPath) || requestPath[end] == '/' {
+				match = path
+				break
+			}
+			next := strings.Index(requestPath[i+1:], path)
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+	}
+	return match
+}
+
+// ConnContext keeps the connection of the requests in their context, it is meant for http.Server.ConnContext so
+// that newBodyReadTimeout can set the read deadline of the connection through the response writers that do not
+// unwrap to http.ResponseController, e.g. the writers of the gorilla logging handlers
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+type connContextKey struct{}
+
+// newBodyReadTimeout reads the request bodies, within the body limit set by NewBodyLimit, before calling the
+// handler. The read deadline of the connection is set to the timeout while the body is read, the requests the body
+// of which is not received within it are rejected with 408 and their connection is closed. The bodies of the
+// connections the read deadline of which cannot be set are left to the read timeout of the server.
+func newBodyReadTimeout(next http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := setReadDeadline(w, r, time.Now().Add(timeout)); err != nil {
+			slog.Debugf("middleware/requestlimits:newBodyReadTimeout() Could not set the read deadline of the request from %s: %s",
+				r.RemoteAddr, err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			slog.Warningf("middleware/requestlimits:newBodyReadTimeout() %s : Request body not received within %s from %s",
+				commLogMsg.InvalidInputBadParam, timeout, r.RemoteAddr)
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+		_ = setReadDeadline(w, r, time.Time{})
+
+		if err != nil {
+			// the body limit is reported by the handlers as for the bodies they read themselves
+			r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), &errorReader{err}))
+		} else {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setReadDeadline sets the read deadline of the connection of a request through the response writer, or through
+// the connection kept by ConnContext when the response writer does not support it
+func setReadDeadline(w http.ResponseWriter, r *http.Request, deadline time.Time) error {
+	err := http.NewResponseController(w).SetReadDeadline(deadline)
+	if errors.Is(err, http.ErrNotSupported) {
+		if conn, ok := r.Context().Value(connContextKey{}).(net.Conn); ok {
+			return conn.SetReadDeadline(deadline)
+		}
+	}
+	return err
+}
+
+// errorReader fails the reads of a request body that could not be read entirely
+type errorReader struct {
+	err error
+}
+
+func (er *errorReader) Read([]byte) (int, error) {
+	return 0, er.err
+}
+
+// limitedResponseWriter fails the writes exceeding the response size limit and closes the connection of the
+// response
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	remaining int64
+	exceeded  bool
+	request   *http.Request
+}
+
+func (lw *limitedResponseWriter) Write(p []byte) (int, error) {
+	if lw.exceeded {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) <= lw.remaining {
+		n, err := lw.ResponseWriter.Write(p)
+		lw.remaining -= int64(n)
+		return n, err
+	}
+
+	lw.exceeded = true
+	slog.Warningf("middleware/requestlimits:Write() Response to %s %s exceeds the size limit, closing the connection to %s",
+		lw.request.Method, lw.request.URL.Path, lw.request.RemoteAddr)
+	n, _ := lw.ResponseWriter.Write(p[:lw.remaining])
+	lw.remaining = 0
+	if hijacker, ok := lw.ResponseWriter.(http.Hijacker); ok {
+		if conn, _, err := hijacker.Hijack(); err == nil {
+			_ = conn.Close()
+		}
+	}
+	return n, ErrResponseTooLarge
+}
+
+func (lw *limitedResponseWriter) Flush() {
+	if flusher, ok := lw.ResponseWriter.(http.Flusher); ok && !lw.exceeded {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped response writer to http.ResponseController
+func (lw *limitedResponseWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
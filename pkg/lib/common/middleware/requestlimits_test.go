@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestLimitsRouter(cfg config.ServerConfig) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(NewRequestLimits(cfg))
+	router.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+	return router
+}
+
+func TestRequestLimitsBodyByPath(t *testing.T) {
+	router := newRequestLimitsRouter(config.ServerConfig{
+		MaxBodyBytes: 16,
+		MaxBodyBytesByPath: map[string]int64{
+			"/flavors":           64,
+			"/flavors/templates": 32,
+		},
+		BodyReadTimeout: time.Second,
+	})
+	body := strings.Repeat("a", 48)
+
+	for _, test := range []struct {
+		path string
+		code int
+	}{
+		{"/hvs/v2/flavors", http.StatusOK},
+		{"/hvs/v2/FLAVORS/b7a1c9d6-3d3f-4c4b-9e4f-8b6a2c1d0e5f", http.StatusOK},
+		{"/hvs/v2/flavors/templates", http.StatusRequestEntityTooLarge},
+		{"/hvs/v2/flavorgroups", http.StatusRequestEntityTooLarge},
+		{"/hvs/v2/hosts", http.StatusRequestEntityTooLarge},
+	} {
+		req := httptest.NewRequest("POST", test.path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, test.code, w.Code, test.path)
+	}
+
+	// body larger than the limit without a content length
+	req := httptest.NewRequest("POST", "/hvs/v2/flavors", bytes.NewBufferString(strings.Repeat("a", 128)))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestRequestLimitsBodyReadTimeout(t *testing.T) {
+	router := newRequestLimitsRouter(config.ServerConfig{BodyReadTimeout: 50 * time.Millisecond})
+
+	// body received within the timeout
+	req := httptest.NewRequest("POST", "/test", bytes.NewBufferString("{}"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{}", w.Body.String())
+
+	// body never completed by the client, the read deadline is set through the response writer of the server or,
+	// for the writers of the logging handler that do not unwrap, through the connection kept by ConnContext
+	for _, handler := range []http.Handler{router, handlers.CombinedLoggingHandler(ioutil.Discard, router)} {
+		server := httptest.NewUnstartedServer(handler)
+		server.Config.ConnContext = ConnContext
+		server.Start()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		assert.NoError(t, err)
+		_, err = io.WriteString(conn, "POST /test HTTP/1.1\r\nHost: test\r\nContent-Length: 16\r\n\r\n{}")
+		assert.NoError(t, err)
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusRequestTimeout, resp.StatusCode)
+			assert.True(t, resp.Close)
+			resp.Body.Close()
+		}
+		conn.Close()
+		server.Close()
+	}
+}
+
+func TestRequestLimitsResponse(t *testing.T) {
+	router := newRequestLimitsRouter(config.ServerConfig{MaxResponseBytes: 8})
+
+	// response within the limit
+	req := httptest.NewRequest("POST", "/test", bytes.NewBufferString("{}"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "{}", w.Body.String())
+
+	// response larger than the limit is truncated
+	req = httptest.NewRequest("POST", "/test", bytes.NewBufferString("0123456789abcdef"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "01234567", w.Body.String())
+
+	// the connection of a response larger than the limit is closed
+	server := httptest.NewServer(router)
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/test", "text/plain", bytes.NewBufferString(strings.Repeat("a", 64*1024)))
+	if err == nil {
+		_, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	assert.Error(t, err)
+}
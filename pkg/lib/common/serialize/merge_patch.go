@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package serialize
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// MergePatch applies the JSON merge patch (RFC 7386) to the JSON document: the members of a patch object replace
+// the members of the same name, recursively for objects, and a null member removes the member from the document.
+// Arrays and the other values are replaced as a whole.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	target, err := decodeNumbers(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal the JSON document")
+	}
+	patchValue, err := decodeNumbers(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal the JSON merge patch")
+	}
+
+	merged, err := json.Marshal(mergePatch(target, patchValue))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal the patched JSON document")
+	}
+	return merged, nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = mergePatch(targetObject[name], value)
+		}
+	}
+	return targetObject
+}
+
+// decodeNumbers decodes the JSON value keeping the numbers as they were written
+func decodeNumbers(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
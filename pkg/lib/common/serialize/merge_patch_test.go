@@ -0,0 +1,34 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// the example of RFC 7386 section 3
+	document := `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`
+	patch := `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`
+	patched, err := MergePatch([]byte(document), []byte(patch))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`, string(patched))
+
+	// the numbers are kept as they were written
+	patched, err = MergePatch([]byte(`{"counter":18446744073709551615,"a":{"b":1}}`), []byte(`{"a":{"c":{"d":2}}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"counter":18446744073709551615,"a":{"b":1,"c":{"d":2}}}`, string(patched))
+
+	// a patch that is not an object replaces the document
+	patched, err = MergePatch([]byte(`{"a":"b"}`), []byte(`["c"]`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["c"]`, string(patched))
+
+	_, err = MergePatch([]byte(`{"a":"b"}`), []byte(`{"a":`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package serialize
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaVersionField is the field holding the schema version of the versioned JSON documents, documents
+// written before the documents were versioned have no version and are of version 0
+const SchemaVersionField = "schema_version"
+
+// SchemaUpgrade upgrades the fields of a decoded JSON document of a schema version to the next version
+type SchemaUpgrade func(document map[string]interface{}) error
+
+// SchemaUpgrader upgrades the JSON documents of a kind from the schema version they were written with to the
+// current version before they are decoded, so that documents stored or sent by a previous minor version can
+// still be read during a rolling upgrade.
+type SchemaUpgrader struct {
+	Kind           string
+	CurrentVersion int
+	// Upgrades holds the upgrade of each version to the next one, versions that only added optional fields
+	// need no upgrade
+	Upgrades map[int]SchemaUpgrade
+}
+
+// NeedsUpgrade returns true when the fields of a document of the version must be upgraded before it is decoded
+func (su *SchemaUpgrader) NeedsUpgrade(version int) bool {
+	for ; version < su.CurrentVersion; version++ {
+		if _, ok := su.Upgrades[version]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Upgrade returns the document upgraded from the version it was written with to the current version. Documents
+// that need no upgrade are returned as is, including the documents of a newer version which are decoded as far
+// as the current version understands them.
+func (su *SchemaUpgrader) Upgrade(data []byte, version int) ([]byte, error) {
+	if !su.NeedsUpgrade(version) {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep the numbers as they were written, e.g. the 64 bit counters of the documents
+	decoder.UseNumber()
+	var document map[string]interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, errors.Wrapf(err, "Error decoding %s of schema version %d", su.Kind, version)
+	}
+	if document == nil {
+		return data, nil
+	}
+
+	for ; version < su.CurrentVersion; version++ {
+		if upgrade, ok := su.Upgrades[version]; ok {
+			if err := upgrade(document); err != nil {
+				return nil, errors.Wrapf(err, "Error upgrading %s from schema version %d", su.Kind, version)
+			}
+		}
+	}
+	document[SchemaVersionField] = su.CurrentVersion
+
+	upgraded, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error encoding %s upgraded to schema version %d", su.Kind, su.CurrentVersion)
+	}
+	return upgraded, nil
+}
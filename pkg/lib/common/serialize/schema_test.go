@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package serialize
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaUpgrader(t *testing.T) {
+	upgrader := SchemaUpgrader{
+		Kind:           "test document",
+		CurrentVersion: 3,
+		Upgrades: map[int]SchemaUpgrade{
+			// version 1 renamed hostName to host_name, version 2 only added optional fields
+			0: func(document map[string]interface{}) error {
+				document["host_name"] = document["hostName"]
+				delete(document, "hostName")
+				return nil
+			},
+		},
+	}
+
+	assert.True(t, upgrader.NeedsUpgrade(0))
+	assert.False(t, upgrader.NeedsUpgrade(1))
+	assert.False(t, upgrader.NeedsUpgrade(4))
+
+	upgraded, err := upgrader.Upgrade([]byte(`{"hostName":"host-1","counter":18446744073709551615}`), 0)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"schema_version":3,"host_name":"host-1","counter":18446744073709551615}`, string(upgraded))
+
+	// documents that need no upgrade are returned as is
+	document := []byte(`{"schema_version":4,"host_name":"host-1","added":true}`)
+	upgraded, err = upgrader.Upgrade(document, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, string(document), string(upgraded))
+
+	_, err = upgrader.Upgrade([]byte(`[]`), 0)
+	assert.Error(t, err)
+
+	var decoded map[string]interface{}
+	upgraded, err = upgrader.Upgrade([]byte(`null`), 0)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(upgraded, &decoded))
+	assert.Nil(t, decoded)
+}
@@ -5,8 +5,8 @@
 package setup
 
 import (
+	"bytes"
 	"crypto"
-	"crypto/tls"
 	"encoding/pem"
 	"fmt"
 	log "github.com/sirupsen/logrus"
@@ -89,33 +89,75 @@ func isDirEmpty(name string) (bool, error) {
 	return false, err
 }
 
+// DetectDrift checks that the CA certificates served by CMS are the ones downloaded, they change when the CMS root CA
+// is replaced
+func (cc *DownloadCMSCert) DetectDrift() error {
+	caCertsPem, err := fetchRootCaCertificates(cc.CmsBaseURL, cc.TlsCertDigest)
+	if err != nil {
+		return err
+	}
+	localCerts, err := crypt.GetCertsFromDir(cc.CaCertDirPath)
+	if err != nil {
+		return errors.Wrap(err, "Error reading CMS CA certificates")
+	}
+	for block, rest := pem.Decode(caCertsPem); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		found := false
+		for _, localCert := range localCerts {
+			if bytes.Equal(localCert.Raw, block.Bytes) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("CMS CA certificate has changed")
+		}
+	}
+	return nil
+}
+
 func downloadRootCaCertificate(cmsBaseUrl string, dirPath string, trustedTlsCertDigest string) (err error) {
+	tlsResp, err := fetchRootCaCertificates(cmsBaseUrl, trustedTlsCertDigest)
+	if err != nil {
+		return err
+	}
+	err = crypt.SavePemCertWithShortSha1FileName(tlsResp, dirPath)
+	if err != nil {
+		return errors.Wrap(err, "crypt.SavePemCertWithShortSha1FileName failed")
+	}
+	return nil
+}
+
+// fetchRootCaCertificates returns the PEM encoded CA certificates of CMS, the connection is trusted if the CMS TLS
+// certificate matches the digest
+func fetchRootCaCertificates(cmsBaseUrl string, trustedTlsCertDigest string) ([]byte, error) {
 	if !strings.HasSuffix(cmsBaseUrl, "/") {
 		cmsBaseUrl = cmsBaseUrl + "/"
 	}
 	parsedUrl, err := url.Parse(cmsBaseUrl)
 	if err != nil {
-		return errors.Wrap(err, "Failed to parse CMS URL")
+		return nil, errors.Wrap(err, "Failed to parse CMS URL")
 	}
 	certificates, _ := parsedUrl.Parse("ca-certificates")
 	endpoint := parsedUrl.ResolveReference(certificates)
 	req, err := http.NewRequest("GET", endpoint.String(), nil)
 	if err != nil {
-		return errors.Wrap(err, "Failed to instantiate http request to CMS")
+		return nil, errors.Wrap(err, "Failed to instantiate http request to CMS")
 	}
 	req.Header.Set("Accept", "application/x-pem-file")
 	//InsecureSkipVerify is set to true as connection is validated manually
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.InsecureSkipVerify = true
 	client := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: true,
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return errors.Wrap(err, "Failed to perform HTTP request to CMS")
+		return nil, errors.Wrap(err, "Failed to perform HTTP request to CMS")
 	}
 	defer func() {
 		derr := resp.Body.Close()
@@ -128,26 +170,22 @@ func downloadRootCaCertificate(cmsBaseUrl string, dirPath string, trustedTlsCert
 	certPEM := pem.EncodeToMemory(&pemBlock)
 	tlsCertDigest, err := crypt.GetCertHashFromPemInHex(certPEM, crypto.SHA384)
 	if err != nil {
-		return errors.Wrap(err, "crypt.GetCertHashFromPemInHex failed")
+		return nil, errors.Wrap(err, "crypt.GetCertHashFromPemInHex failed")
 	}
 	if resp.StatusCode != http.StatusOK {
 		text, _ := ioutil.ReadAll(resp.Body)
 		reqErr := fmt.Errorf("Status %d: %s", resp.StatusCode, string(text))
-		return errors.Wrap(reqErr, "CMS request failed to download CA Certificate")
+		return nil, errors.Wrap(reqErr, "CMS request failed to download CA Certificate")
 	}
 	if tlsCertDigest == "" || tlsCertDigest != trustedTlsCertDigest {
-		return errors.New("CMS TLS Certificate digest does not match")
+		return nil, errors.New("CMS TLS Certificate digest does not match")
 	}
 	tlsResp, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "Failed to read CMS response body")
+		return nil, errors.Wrap(err, "Failed to read CMS response body")
 	}
 	if tlsResp == nil {
-		return errors.Wrap(err, "Invalid response from Download CA Certificate")
-	}
-	err = crypt.SavePemCertWithShortSha1FileName(tlsResp, dirPath)
-	if err != nil {
-		return errors.Wrap(err, "crypt.SavePemCertWithShortSha1FileName failed")
+		return nil, errors.Wrap(err, "Invalid response from Download CA Certificate")
 	}
-	return nil
+	return tlsResp, nil
 }
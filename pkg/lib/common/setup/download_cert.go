@@ -6,7 +6,6 @@ package setup
 
 import (
 	"bytes"
-	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -126,10 +125,18 @@ func (dc *DownloadCert) Validate() error {
 	if os.IsNotExist(err) {
 		return errors.New("KeyFile is not configured")
 	}
+	if err = validateCertificateFile(dc.CertFile); err != nil {
+		return err
+	}
 	printToWriter(dc.ConsoleWriter, dc.commandName, "Certificate download setup validated")
 	return nil
 }
 
+// DetectDrift checks that the certificate is still issued by the CMS root CA
+func (dc *DownloadCert) DetectDrift() error {
+	return verifyIssuedByTrustedCA(dc.CertFile, dc.CaCertDirPath)
+}
+
 func (t *DownloadCert) PrintHelp(w io.Writer) {
 	PrintEnvHelp(w, downloadCAEnvHelpPrompt2+t.commandName, "", downloadCAEnvHelp2)
 	if t.commandName == "download-cert-tls" {
@@ -189,13 +196,11 @@ func getCertificateFromCMS(certType string, keyAlg string, keyLen int, cmsBaseUr
 		}
 	}
 
+	tlsConfig := crypt.TLSConfig()
+	tlsConfig.RootCAs = rootCAs
 	client := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: false,
-				RootCAs:            rootCAs,
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 	resp, err := client.Do(req)
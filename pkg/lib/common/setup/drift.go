@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package setup
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/pkg/errors"
+)
+
+// readCertificateChain reads the certificate and the certificates of its chain from a PEM file
+func readCertificateChain(certFile string) ([]*x509.Certificate, error) {
+	certPem, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can not read certificate file: "+certFile)
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPem); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "Can not parse certificate in file: "+certFile)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("No certificate found in file: " + certFile)
+	}
+	return certs, nil
+}
+
+// validateCertificateFile checks that the certificate in the file is in its validity period, the file is skipped if
+// it is a directory of certificates
+func validateCertificateFile(certFile string) error {
+	if fi, err := os.Stat(certFile); err != nil {
+		return errors.Wrap(err, "Can not access certificate file: "+certFile)
+	} else if fi.IsDir() {
+		return nil
+	}
+	certs, err := readCertificateChain(certFile)
+	if err != nil {
+		return err
+	}
+	return checkValidityPeriod(certs[0])
+}
+
+func checkValidityPeriod(cert *x509.Certificate) error {
+	now := time.Now()
+	if now.After(cert.NotAfter) {
+		return errors.Errorf("Certificate %s expired on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+	}
+	if now.Before(cert.NotBefore) {
+		return errors.Errorf("Certificate %s is not valid before %s", cert.Subject.CommonName,
+			cert.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// verifyIssuedByTrustedCA checks that the certificate in the file chains up to one of the CA certificates of the
+// directory, using the certificates following it in the file as intermediates
+func verifyIssuedByTrustedCA(certFile, caCertDirPath string) error {
+	if fi, err := os.Stat(certFile); err != nil {
+		return errors.Wrap(err, "Can not access certificate file: "+certFile)
+	} else if fi.IsDir() {
+		return nil
+	}
+	certs, err := readCertificateChain(certFile)
+	if err != nil {
+		return err
+	}
+	caCerts, err := crypt.GetCertsFromDir(caCertDirPath)
+	if err != nil {
+		return errors.Wrap(err, "Can not read CA certificates")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         crypt.GetCertPool(caCerts),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Certificate is not issued by a trusted CA")
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package setup
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// InteractiveFlag runs the setup tasks with the settings prompted for on the console
+const InteractiveFlag = "--interactive"
+
+// FixDriftFlag runs again the setup tasks that have drifted, see Runner.FixDrift
+const FixDriftFlag = "--fix-drift"
+
+// Prompt is a setting asked for by the interactive setup, the answer is stored in the environment variable of the
+// setting as if it was read from an answer file
+type Prompt struct {
+	EnvName     string
+	Description string
+	// Default is the answer used when none is given
+	Default string
+	// Optional settings are left unset when no answer is given
+	Optional bool
+	// Secret answers are not echoed when read from a terminal
+	Secret   bool
+	Validate func(string) error
+	// Tasks are the setup tasks that need the setting, it is asked for by all of them if none is given
+	Tasks []string
+}
+
+// Interactive reads the answers to the prompts from the console
+type Interactive struct {
+	ConsoleReader io.Reader
+	ConsoleWriter io.Writer
+	// ReadSecret reads a secret answer without echoing it, the secrets are read from the ConsoleReader if it is not set
+	ReadSecret func() (string, error)
+
+	scanner *bufio.Scanner
+}
+
+// NewConsoleInteractive returns an Interactive reading the answers from the standard input, the secrets are not
+// echoed if it is a terminal
+func NewConsoleInteractive(w io.Writer) *Interactive {
+	interactive := &Interactive{
+		ConsoleReader: os.Stdin,
+		ConsoleWriter: w,
+	}
+	fd := int(os.Stdin.Fd())
+	if terminal.IsTerminal(fd) {
+		interactive.ReadSecret = func() (string, error) {
+			secret, err := terminal.ReadPassword(fd)
+			fmt.Fprintln(w)
+			return string(secret), err
+		}
+	}
+	return interactive
+}
+
+// ReadAnswersToEnv prompts for the settings needed by the setup task, or by all the tasks for "all", and stores the
+// answers in the environment. The settings already set in the environment or in an answer file are not prompted for.
+// An invalid answer is prompted for again.
+func (i *Interactive) ReadAnswersToEnv(task string, prompts []Prompt) error {
+	if i.scanner == nil {
+		i.scanner = bufio.NewScanner(i.ConsoleReader)
+	}
+	for _, prompt := range prompts {
+		if !prompt.neededBy(task) || os.Getenv(prompt.EnvName) != "" {
+			continue
+		}
+		answer, err := i.readAnswer(prompt)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read %s", prompt.EnvName)
+		}
+		if answer == "" {
+			continue
+		}
+		if err = os.Setenv(prompt.EnvName, answer); err != nil {
+			return errors.Wrap(err, "Failed to set ENV")
+		}
+	}
+	return nil
+}
+
+func (i *Interactive) readAnswer(prompt Prompt) (string, error) {
+	for {
+		question := prompt.Description
+		if prompt.Default != "" && !prompt.Secret {
+			question += " [" + prompt.Default + "]"
+		} else if prompt.Optional {
+			question += " (optional)"
+		}
+		fmt.Fprint(i.ConsoleWriter, question+": ")
+
+		var answer string
+		var err error
+		if prompt.Secret && i.ReadSecret != nil {
+			answer, err = i.ReadSecret()
+		} else {
+			answer, err = i.readLine()
+		}
+		if err != nil {
+			return "", err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			answer = prompt.Default
+		}
+		if answer == "" {
+			if prompt.Optional {
+				return "", nil
+			}
+			fmt.Fprintln(i.ConsoleWriter, prompt.EnvName+" is required")
+			continue
+		}
+		if prompt.Validate != nil {
+			if err := prompt.Validate(answer); err != nil {
+				fmt.Fprintln(i.ConsoleWriter, prompt.EnvName+": "+err.Error())
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+func (i *Interactive) readLine() (string, error) {
+	if !i.scanner.Scan() {
+		if err := i.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.ErrUnexpectedEOF
+	}
+	return i.scanner.Text(), nil
+}
+
+func (prompt Prompt) neededBy(task string) bool {
+	if task == "all" || len(prompt.Tasks) == 0 {
+		return true
+	}
+	for _, t := range prompt.Tasks {
+		if t == task {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBaseURL checks that the answer is the https URL of a service
+func ValidateBaseURL(answer string) error {
+	u, err := url.ParseRequestURI(answer)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("Invalid https URL")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return errors.New("Unexpected query or fragment in URL")
+	}
+	return nil
+}
+
+// ValidateCertDigest checks that the answer is the hex encoded SHA384 digest of a certificate
+func ValidateCertDigest(answer string) error {
+	digest, err := hex.DecodeString(answer)
+	if err != nil || len(digest) != 48 {
+		return errors.New("Invalid SHA384 digest")
+	}
+	return nil
+}
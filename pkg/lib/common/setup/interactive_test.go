@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package setup_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+func TestInteractiveReadAnswersToEnv(t *testing.T) {
+	envNames := []string{"TEST_IA_URL", "TEST_IA_PORT", "TEST_IA_PASSWORD", "TEST_IA_OPTIONAL", "TEST_IA_SET", "TEST_IA_OTHER_TASK"}
+	for _, name := range envNames {
+		defer os.Unsetenv(name)
+	}
+	os.Setenv("TEST_IA_SET", "from-answer-file")
+
+	prompts := []setup.Prompt{
+		{EnvName: "TEST_IA_URL", Description: "Base URL", Validate: setup.ValidateBaseURL},
+		{EnvName: "TEST_IA_PORT", Description: "Port", Default: "8443"},
+		{EnvName: "TEST_IA_PASSWORD", Description: "Password", Secret: true},
+		{EnvName: "TEST_IA_OPTIONAL", Description: "Optional setting", Optional: true},
+		{EnvName: "TEST_IA_SET", Description: "Setting from the answer file"},
+		{EnvName: "TEST_IA_OTHER_TASK", Description: "Setting of another task", Tasks: []string{"other-task"}},
+	}
+	// the invalid URL and the missing password are prompted for again
+	input := strings.Join([]string{"http://cms.com", "https://cms.com:8445/cms/v1/", "", "", "secret", ""}, "\n") + "\n"
+	var output bytes.Buffer
+	interactive := &setup.Interactive{
+		ConsoleReader: strings.NewReader(input),
+		ConsoleWriter: &output,
+	}
+	if err := interactive.ReadAnswersToEnv("task", prompts); err != nil {
+		t.Fatal("Failed to read answers:", err)
+	}
+
+	expected := map[string]string{
+		"TEST_IA_URL":        "https://cms.com:8445/cms/v1/",
+		"TEST_IA_PORT":       "8443",
+		"TEST_IA_PASSWORD":   "secret",
+		"TEST_IA_OPTIONAL":   "",
+		"TEST_IA_SET":        "from-answer-file",
+		"TEST_IA_OTHER_TASK": "",
+	}
+	for name, value := range expected {
+		if os.Getenv(name) != value {
+			t.Errorf("%s is %q, expected %q", name, os.Getenv(name), value)
+		}
+	}
+	if !strings.Contains(output.String(), "TEST_IA_URL: Invalid https URL") ||
+		!strings.Contains(output.String(), "TEST_IA_PASSWORD is required") {
+		t.Error("Invalid answers are not reported:", output.String())
+	}
+	if strings.Contains(output.String(), "Setting from the answer file") {
+		t.Error("Setting from the answer file is prompted for")
+	}
+}
+
+func TestInteractiveReadSecret(t *testing.T) {
+	defer os.Unsetenv("TEST_IA_SECRET")
+	interactive := &setup.Interactive{
+		ConsoleReader: strings.NewReader(""),
+		ConsoleWriter: &bytes.Buffer{},
+		ReadSecret: func() (string, error) {
+			return "from-terminal", nil
+		},
+	}
+	err := interactive.ReadAnswersToEnv("all", []setup.Prompt{{EnvName: "TEST_IA_SECRET", Description: "Secret", Secret: true}})
+	if err != nil || os.Getenv("TEST_IA_SECRET") != "from-terminal" {
+		t.Error("Secret is not read with ReadSecret:", err)
+	}
+
+	interactive.ReadSecret = func() (string, error) {
+		return "", errors.New("terminal closed")
+	}
+	os.Unsetenv("TEST_IA_SECRET")
+	err = interactive.ReadAnswersToEnv("all", []setup.Prompt{{EnvName: "TEST_IA_SECRET", Description: "Secret", Secret: true}})
+	if err == nil {
+		t.Error("Error reading the secret is not returned")
+	}
+}
+
+func TestInteractiveEndOfInput(t *testing.T) {
+	interactive := &setup.Interactive{
+		ConsoleReader: strings.NewReader("not-a-url\n"),
+		ConsoleWriter: &bytes.Buffer{},
+	}
+	err := interactive.ReadAnswersToEnv("all", []setup.Prompt{{EnvName: "TEST_IA_EOF", Description: "Base URL", Validate: setup.ValidateBaseURL}})
+	if err == nil {
+		t.Error("End of input is not reported")
+	}
+}
+
+func TestValidateCertDigest(t *testing.T) {
+	if err := setup.ValidateCertDigest(strings.Repeat("ab", 48)); err != nil {
+		t.Error("Valid SHA384 digest is rejected:", err)
+	}
+	if err := setup.ValidateCertDigest(strings.Repeat("ab", 32)); err == nil {
+		t.Error("SHA256 digest is accepted")
+	}
+}
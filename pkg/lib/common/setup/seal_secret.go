@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package setup
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/pkg/errors"
+)
+
+// SecretSealingEnvHelp describes the environment variables of the TPM sealing of the service secrets
+var SecretSealingEnvHelp = map[string]string{
+	"TPM_SEAL_SECRETS": "Seal the service and database passwords to the local TPM instead of saving them in plaintext in config.yml",
+	"TPM_SEAL_PCRS":    "Comma separated PCRs the sealed secrets are bound to, e.g. 0,7, no PCR policy is set when not provided",
+}
+
+// SecretSealing is the option of the setup tasks to save the secrets of the service in the configuration sealed to
+// the local TPM. The services unseal the secrets when they use them, the configuration never holds them in plaintext.
+type SecretSealing struct {
+	Enabled bool
+	PCRs    string
+}
+
+// Seal returns the value of the secret to be saved in the configuration, the secret is returned as it is when
+// sealing is not enabled or the secret is already sealed
+func (s SecretSealing) Seal(secret string) (string, error) {
+	if !s.Enabled || secret == "" || crypt.IsSealedSecret(secret) {
+		return secret, nil
+	}
+	pcrs, err := crypt.ParsePCRSelection(s.PCRs)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid TPM_SEAL_PCRS")
+	}
+	sealed, err := crypt.SealSecret([]byte(secret), pcrs)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to seal the secret to the TPM")
+	}
+	return sealed, nil
+}
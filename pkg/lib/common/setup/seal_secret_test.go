@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package setup_test
+
+import (
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+)
+
+type reversingSealer struct{}
+
+func (reversingSealer) Seal(secret []byte, _ []int) ([]byte, error) {
+	blob := make([]byte, len(secret))
+	for i, b := range secret {
+		blob[len(secret)-1-i] = b
+	}
+	return blob, nil
+}
+
+func (s reversingSealer) Unseal(blob []byte) ([]byte, error) {
+	return s.Seal(blob, nil)
+}
+
+func TestSecretSealing(t *testing.T) {
+	crypt.RegisterSecretSealer(nil)
+	if _, err := (setup.SecretSealing{Enabled: true}).Seal("password"); err == nil {
+		t.Error("Expected an error when the build can not seal secrets")
+	}
+	if secret, err := (setup.SecretSealing{}).Seal("password"); err != nil || secret != "password" {
+		t.Errorf("Expected the secret to be kept when sealing is not enabled, got %s, %v", secret, err)
+	}
+
+	crypt.RegisterSecretSealer(reversingSealer{})
+	defer crypt.RegisterSecretSealer(nil)
+
+	sealing := setup.SecretSealing{Enabled: true, PCRs: "0,7"}
+	sealed, err := sealing.Seal("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crypt.IsSealedSecret(sealed) {
+		t.Errorf("Expected a sealed secret, got %s", sealed)
+	}
+	if secret, err := crypt.ResolveSecret(sealed); err != nil || secret != "password" {
+		t.Errorf("Expected the sealed secret to resolve to password, got %s, %v", secret, err)
+	}
+
+	// a secret sealed by a previous setup is not sealed again
+	if resealed, err := sealing.Seal(sealed); err != nil || resealed != sealed {
+		t.Errorf("Expected the sealed secret to be kept, got %s, %v", resealed, err)
+	}
+	if _, err = (setup.SecretSealing{Enabled: true, PCRs: "32"}).Seal("password"); err == nil {
+		t.Error("Expected an error for an invalid PCR selection")
+	}
+}
@@ -70,7 +70,7 @@ func (t *SelfSignedCert) Validate() error {
 	} else if err != nil {
 		return errors.Wrap(err, "Can not access private key file: "+t.CertFile)
 	}
-	return nil
+	return validateCertificateFile(t.CertFile)
 }
 
 func (t *SelfSignedCert) Run() error {
@@ -32,6 +32,10 @@ var svrEnvHelp = map[string]string{
 	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
 	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes ",
+	"SERVER_MAX_BODY_BYTES":      "Max Length Of Request Body in Bytes",
+	"SERVER_DRAIN_TIMEOUT":       "Maximum Duration to Drain the In-Flight Requests on Shutdown",
+	"SERVER_BODY_READ_TIMEOUT":   "Maximum Duration to Receive a Request Body",
+	"SERVER_MAX_RESPONSE_BYTES":  "Max Length Of Response Body in Bytes, 0 for no limit",
 }
 
 func (t *ServerSetup) Run() error {
@@ -48,6 +52,10 @@ func (t *ServerSetup) Run() error {
 	t.SvrConfigPtr.WriteTimeout = t.WriteTimeout
 	t.SvrConfigPtr.IdleTimeout = t.IdleTimeout
 	t.SvrConfigPtr.MaxHeaderBytes = t.MaxHeaderBytes
+	t.SvrConfigPtr.MaxBodyBytes = t.MaxBodyBytes
+	t.SvrConfigPtr.DrainTimeout = t.DrainTimeout
+	t.SvrConfigPtr.BodyReadTimeout = t.BodyReadTimeout
+	t.SvrConfigPtr.MaxResponseBytes = t.MaxResponseBytes
 	return nil
 }
 
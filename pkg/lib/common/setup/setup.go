@@ -18,6 +18,13 @@ type Task interface {
 	PrintHelp(io.Writer)
 }
 
+// DriftDetector is implemented by the tasks that can tell if what they have set up has drifted from what it should
+// be, like a certificate that is no longer issued by the CMS root CA. Validate only checks that a task has been run,
+// the drift checks may reach other services and are only done by Runner.FixDrift.
+type DriftDetector interface {
+	DetectDrift() error
+}
+
 type Runner struct {
 	ConsoleWriter io.Writer
 	ErrorWriter   io.Writer
@@ -140,3 +147,39 @@ func (r *Runner) PrintHelp(taskName string) error {
 func (r *Runner) FailedCommands() map[string]error {
 	return r.failedCommands
 }
+
+// FixDrift looks for drift in the task, or in all the tasks for "all", and runs again the tasks that no longer
+// validate or that report drift. The tasks are checked in the order they are added and each one after the previous
+// has been fixed, so a changed CMS root CA is fixed before the certificates it issued are checked.
+func (r *Runner) FixDrift(taskName string) error {
+	taskNames := r.order
+	if taskName != "all" {
+		if _, ok := r.tasks[taskName]; !ok {
+			return ErrTaskNotFound
+		}
+		taskNames = []string{taskName}
+	}
+	drifted := 0
+	for _, name := range taskNames {
+		printToWriter(r.ConsoleWriter, "", "Checking setup task for drift: "+name)
+		err := r.tasks[name].Validate()
+		if detector, ok := r.tasks[name].(DriftDetector); ok && err == nil {
+			err = detector.DetectDrift()
+		}
+		if err == nil {
+			continue
+		}
+		drifted++
+		printToWriter(r.ConsoleWriter, "", "Drift detected in setup task "+name+": "+err.Error())
+		if err = r.Run(name, true); err != nil {
+			log.WithError(err).Errorf("Failed to fix drift in task : %s", name)
+		}
+	}
+	if len(r.failedCommands) != 0 {
+		return errors.New("Failed to fix drift in all tasks")
+	}
+	if drifted == 0 {
+		printToWriter(r.ConsoleWriter, "", "No drift detected")
+	}
+	return nil
+}
@@ -76,3 +76,49 @@ func TestSetupRunner(t *testing.T) {
 		t.Error("Failed to run all tasks:", err.Error())
 	}
 }
+
+type testDriftTask struct {
+	testTaskOne
+
+	drifted bool
+	runs    int
+}
+
+func (t *testDriftTask) Run() error {
+	t.runs++
+	t.drifted = false
+	return t.testTaskOne.Run()
+}
+
+func (t *testDriftTask) DetectDrift() error {
+	if t.drifted {
+		return errors.New("drift detected")
+	}
+	return nil
+}
+
+func TestSetupRunnerFixDrift(t *testing.T) {
+	runner := setup.NewRunner()
+	clean := &testDriftTask{testTaskOne: testTaskOne{Arg: testConfigMap["TEST_ARG_KEY_ONE"]}}
+	drifted := &testDriftTask{testTaskOne: testTaskOne{Arg: testConfigMap["TEST_ARG_KEY_ONE"]}}
+	runner.AddTask("task-clean", "", clean)
+	runner.AddTask("task-drifted", "", drifted)
+	if err := runner.RunAll(true); err != nil {
+		t.Fatal("Failed to run all tasks:", err.Error())
+	}
+
+	drifted.drifted = true
+	if err := runner.FixDrift("all"); err != nil {
+		t.Fatal("Failed to fix drift:", err.Error())
+	}
+	if clean.runs != 1 {
+		t.Error("Task without drift should not be run again")
+	}
+	if drifted.runs != 2 || drifted.drifted {
+		t.Error("Task with drift should be run again")
+	}
+
+	if err := runner.FixDrift("task-unknown"); err != setup.ErrTaskNotFound {
+		t.Error("Expected ErrTaskNotFound for unknown task")
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CertReloader holds the serving TLS key pair of a service and allows it to be replaced at runtime.
+// It is meant to be plugged into tls.Config.GetCertificate so that a rotated certificate is picked up
+// by new handshakes without restarting the listener or dropping established connections.
+//
+// A replaced key pair stays in service for the overlap window, for the clients that cannot use the new one,
+// e.g. when the signing key moves from RSA to ECDSA, so that they can be updated before it is retired.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	overlap  time.Duration
+
+	mu       sync.RWMutex
+	keyPair  *tls.Certificate
+	leaf     *x509.Certificate
+	modTime  time.Time
+	loadedAt time.Time
+	retiring []RetiringKeyPair
+}
+
+// RetiringKeyPair is a replaced key pair still served during the overlap window
+type RetiringKeyPair struct {
+	KeyPair   *tls.Certificate
+	RetiresAt time.Time
+}
+
+// NewCertReloader loads the key pair from certFile and keyFile and returns a CertReloader serving it. The replaced
+// key pairs are served for overlap to the clients that do not support the new one, a zero overlap retires them at
+// once.
+func NewCertReloader(certFile, keyFile string, overlap time.Duration) (*CertReloader, error) {
+	cr := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		overlap:  overlap,
+	}
+	if err := cr.Reload(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Reload reads the key pair from disk and swaps it in. The previously loaded key pair is kept
+// if the files on disk cannot be parsed or do not form a valid pair, otherwise it is retired at the
+// end of the overlap window.
+func (cr *CertReloader) Reload() error {
+	// the files are stat'ed before they are loaded, a rotation written meanwhile is reported by Changed
+	modTime, err := cr.latestModTime()
+	if err != nil {
+		return err
+	}
+	keyPair, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "Failed to load TLS key pair")
+	}
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse TLS certificate")
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	now := time.Now()
+	cr.retiring = cr.retainedKeyPairs(now)
+	if cr.keyPair != nil && cr.overlap > 0 && !cr.leaf.Equal(leaf) {
+		cr.retiring = append(cr.retiring, RetiringKeyPair{KeyPair: cr.keyPair, RetiresAt: now.Add(cr.overlap)})
+	}
+	keyPair.Leaf = leaf
+	cr.keyPair = &keyPair
+	cr.leaf = leaf
+	cr.modTime = modTime
+	cr.loadedAt = now
+	return nil
+}
+
+// GetCertificate returns the currently loaded key pair, or the most recent retiring key pair that the client
+// supports when it does not support the current one. Its signature matches tls.Config.GetCertificate
+func (cr *CertReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	if hello == nil || hello.SupportsCertificate(cr.keyPair) == nil {
+		return cr.keyPair, nil
+	}
+	retiring := cr.retainedKeyPairs(time.Now())
+	for i := len(retiring) - 1; i >= 0; i-- {
+		if hello.SupportsCertificate(retiring[i].KeyPair) == nil {
+			return retiring[i].KeyPair, nil
+		}
+	}
+	// the handshake fails with the current key pair as it would without rotation
+	return cr.keyPair, nil
+}
+
+// Retiring returns the replaced key pairs still served until the end of their overlap window
+func (cr *CertReloader) Retiring() []RetiringKeyPair {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.retainedKeyPairs(time.Now())
+}
+
+// retainedKeyPairs returns the retiring key pairs the overlap window of which has not ended, cr.mu must be held
+func (cr *CertReloader) retainedKeyPairs(now time.Time) []RetiringKeyPair {
+	var retained []RetiringKeyPair
+	for _, retiring := range cr.retiring {
+		if now.Before(retiring.RetiresAt) {
+			retained = append(retained, retiring)
+		}
+	}
+	return retained
+}
+
+// Leaf returns the parsed leaf certificate currently being served along with the time it was loaded
+func (cr *CertReloader) Leaf() (*x509.Certificate, time.Time) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.leaf, cr.loadedAt
+}
+
+// Changed reports whether the certificate or key file has been modified since the last successful load
+func (cr *CertReloader) Changed() (bool, error) {
+	modTime, err := cr.latestModTime()
+	if err != nil {
+		return false, err
+	}
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return modTime.After(cr.modTime), nil
+}
+
+// Watch polls the certificate and key files every interval and reloads the key pair when either one
+// changes. Failures are passed to onError, if provided, and the current key pair stays in use. Watch
+// returns when stop is closed.
+func (cr *CertReloader) Watch(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			changed, err := cr.Changed()
+			if err == nil && changed {
+				err = cr.Reload()
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (cr *CertReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{cr.certFile, cr.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "Failed to stat %s", file)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tls
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+)
+
+func writeKeyPair(t *testing.T, dir, subject string) (string, string) {
+	return writeKeyPairOfType(t, dir, subject, "rsa", 2048)
+}
+
+func writeKeyPairOfType(t *testing.T, dir, subject, keyType string, keyLength int) (string, string) {
+	certDer, keyDer, err := crypt.CreateKeyPairAndCertificate(subject, "127.0.0.1", keyType, keyLength)
+	if err != nil {
+		t.Fatal("Failed to create key pair", err)
+	}
+	certFile := filepath.Join(dir, "tls-cert.pem")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err = crypt.SavePemCert(certDer, certFile); err != nil {
+		t.Fatal("Failed to save certificate", err)
+	}
+	if err = crypt.SavePrivateKeyAsPKCS8(keyDer, keyFile); err != nil {
+		t.Fatal("Failed to save private key", err)
+	}
+	return certFile, keyFile
+}
+
+func TestCertReloaderReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeKeyPair(t, dir, "first")
+	cr, err := NewCertReloader(certFile, keyFile, 0)
+	if err != nil {
+		t.Fatal("Failed to create reloader", err)
+	}
+	leaf, _ := cr.Leaf()
+	if leaf.Subject.Organization[0] != "first" {
+		t.Errorf("Unexpected subject %v", leaf.Subject)
+	}
+
+	// make sure the rewritten files get a newer modification time
+	time.Sleep(10 * time.Millisecond)
+	writeKeyPair(t, dir, "second")
+	future := time.Now().Add(time.Second)
+	_ = os.Chtimes(certFile, future, future)
+
+	changed, err := cr.Changed()
+	if err != nil || !changed {
+		t.Fatalf("Expected change to be detected, changed: %v, error: %v", changed, err)
+	}
+	if err = cr.Reload(); err != nil {
+		t.Fatal("Failed to reload", err)
+	}
+	keyPair, _ := cr.GetCertificate(nil)
+	if keyPair.Leaf.Subject.Organization[0] != "second" {
+		t.Errorf("Expected rotated certificate to be served, got %v", keyPair.Leaf.Subject)
+	}
+}
+
+func TestCertReloaderKeepsPairOnFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeKeyPair(t, dir, "first")
+	cr, err := NewCertReloader(certFile, keyFile, 0)
+	if err != nil {
+		t.Fatal("Failed to create reloader", err)
+	}
+	if err = ioutil.WriteFile(keyFile, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = cr.Reload(); err == nil {
+		t.Error("Expected reload of invalid key pair to fail")
+	}
+	keyPair, _ := cr.GetCertificate(nil)
+	if keyPair == nil || keyPair.Leaf.Subject.Organization[0] != "first" {
+		t.Error("Expected previous key pair to remain in use")
+	}
+}
+
+func TestCertReloaderOverlap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeKeyPairOfType(t, dir, "first", "rsa", 2048)
+	cr, err := NewCertReloader(certFile, keyFile, time.Hour)
+	if err != nil {
+		t.Fatal("Failed to create reloader", err)
+	}
+	writeKeyPairOfType(t, dir, "second", "ecdsa", 384)
+	if err = cr.Reload(); err != nil {
+		t.Fatal("Failed to reload", err)
+	}
+	if len(cr.Retiring()) != 1 {
+		t.Fatalf("Expected the replaced key pair to be retiring, got %d", len(cr.Retiring()))
+	}
+
+	rsaClient := &tls.ClientHelloInfo{
+		SupportedVersions: []uint16{tls.VersionTLS13},
+		SignatureSchemes:  []tls.SignatureScheme{tls.PSSWithSHA256},
+	}
+	ecdsaClient := &tls.ClientHelloInfo{
+		SupportedVersions: []uint16{tls.VersionTLS13},
+		SignatureSchemes:  []tls.SignatureScheme{tls.ECDSAWithP384AndSHA384, tls.PSSWithSHA256},
+	}
+	keyPair, _ := cr.GetCertificate(ecdsaClient)
+	if keyPair.Leaf.Subject.Organization[0] != "second" {
+		t.Errorf("Expected the new key pair for the clients supporting it, got %v", keyPair.Leaf.Subject)
+	}
+	keyPair, _ = cr.GetCertificate(rsaClient)
+	if keyPair.Leaf.Subject.Organization[0] != "first" {
+		t.Errorf("Expected the replaced key pair during the overlap window, got %v", keyPair.Leaf.Subject)
+	}
+
+	// the replaced key pair is no longer served once the overlap window has ended
+	cr.retiring[0].RetiresAt = time.Now().Add(-time.Second)
+	keyPair, _ = cr.GetCertificate(rsaClient)
+	if keyPair.Leaf.Subject.Organization[0] != "second" || len(cr.Retiring()) != 0 {
+		t.Errorf("Expected the replaced key pair to be retired, got %v", keyPair.Leaf.Subject)
+	}
+
+	// reloading the same key pair does not retire it
+	if err = cr.Reload(); err != nil {
+		t.Fatal("Failed to reload", err)
+	}
+	if len(cr.Retiring()) != 0 {
+		t.Error("Expected an unchanged key pair not to be retired")
+	}
+}
@@ -5,9 +5,11 @@
 package validation
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"regexp"
@@ -16,6 +18,9 @@ import (
 const (
 	UUIDReg = "[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-4[a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}"
 	MaxLen  = 256
+
+	// MaxXMLDepth is the maximum element nesting accepted by ValidateXMLDocument
+	MaxXMLDepth = 64
 )
 
 var (
@@ -40,7 +45,7 @@ var (
 	portReg             = regexp.MustCompile("(?:([0-9]{1,5}))")
 	textReg             = regexp.MustCompile("(?:[a-zA-Z0-9\\[\\]$@(){}_\\.\\, |:-]+)")
 	passwordReg         = regexp.MustCompile("(?:([a-zA-Z0-9_\\\\.\\\\, @!#$%^+=>?:{}()\\[\\]\\\"|;~`'*-/]+))")
-	connectionStringReg = regexp.MustCompile("^(((vmware)|(microsoft)|(intel))\\:)?https\\:\\/\\/.+[\\:\\d+]?(\\/sdk)?((;h=.+;u=.+;p=.+)|(;u=.+;p=.+))?$")
+	connectionStringReg = regexp.MustCompile("^(((vmware)|(microsoft)|(intel))\\:)?(https|ssh)\\:\\/\\/.+[\\:\\d+]?(\\/sdk)?((;h=.+;u=.+;p=.+)|(;u=.+;p=.+))?$")
 	jwtReg              = regexp.MustCompile("^[A-Za-z0-9-_=]+\\.[A-Za-z0-9-_=]+\\.?[A-Za-z0-9-_.+/=]*")
 )
 
@@ -209,6 +214,39 @@ func ValidateXMLString(value string) error {
 	return xml.Unmarshal([]byte(value), new(interface{}))
 }
 
+// ValidateXMLDocument method checks that an XML document is well formed, does not declare a DTD
+// or entities and does not nest elements deeper than MaxXMLDepth. It should be called before
+// parsing untrusted XML such as SAML reports and measurement logs to guard against entity
+// expansion and deeply nested payloads.
+func ValidateXMLDocument(document []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.Directive:
+			directive := bytes.ToUpper(bytes.TrimSpace(t))
+			if bytes.HasPrefix(directive, []byte("DOCTYPE")) || bytes.HasPrefix(directive, []byte("ENTITY")) {
+				return errors.New("XML document type and entity declarations are not allowed")
+			}
+		case xml.StartElement:
+			depth++
+			if depth > MaxXMLDepth {
+				return errors.New("XML document exceeds the maximum element depth")
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
+
 // ValidateHexString method checks if a string has a valid hex format
 func ValidateHexString(value string) error {
 	if !hexStringReg.MatchString(value) {
@@ -550,3 +550,25 @@ func TestValidateK8sJWT(t *testing.T) {
 	assert.Error(t, err)
 
 }
+
+func TestValidateXMLDocument(t *testing.T) {
+
+	goodXml := `<?xml version="1.0" encoding="UTF-8"?><Measurement DigestAlg="SHA384" Label="ISL_Applications"><File Path="/opt/trustagent/bin/module_analysis.sh">ab</File></Measurement>`
+	err := ValidateXMLDocument([]byte(goodXml))
+	assert.NoError(t, err)
+
+	entityXml := `<?xml version="1.0"?><!DOCTYPE lolz [<!ENTITY lol "lol"><!ENTITY lol1 "&lol;&lol;&lol;">]><lolz>&lol1;</lolz>`
+	err = ValidateXMLDocument([]byte(entityXml))
+	assert.Error(t, err)
+
+	deepXml := ""
+	for i := 0; i <= MaxXMLDepth; i++ {
+		deepXml = "<a>" + deepXml + "</a>"
+	}
+	err = ValidateXMLDocument([]byte(deepXml))
+	assert.Error(t, err)
+
+	malformedXml := `<Measurement><File></Measurement>`
+	err = ValidateXMLDocument([]byte(malformedXml))
+	assert.Error(t, err)
+}
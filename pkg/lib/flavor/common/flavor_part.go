@@ -26,6 +26,9 @@ const (
 	FlavorPartHostUnique FlavorPart = "HOST_UNIQUE"
 	FlavorPartSoftware   FlavorPart = "SOFTWARE"
 	FlavorPartAssetTag   FlavorPart = "ASSET_TAG"
+	// FlavorPartContainerImage flavors list the container images expected on the hosts, as measured by the
+	// workload agent
+	FlavorPartContainerImage FlavorPart = "CONTAINER_IMAGE"
 )
 
 // GetFlavorTypes returns a list of flavor types
@@ -33,7 +36,8 @@ func GetFlavorTypes() []FlavorPart {
 	log.Trace("flavor/common/flavor_part:GetFlavorTypes() Entering")
 	defer log.Trace("flavor/common/flavor_part:GetFlavorTypes() Leaving")
 
-	return []FlavorPart{FlavorPartPlatform, FlavorPartOs, FlavorPartHostUnique, FlavorPartSoftware, FlavorPartAssetTag}
+	return []FlavorPart{FlavorPartPlatform, FlavorPartOs, FlavorPartHostUnique, FlavorPartSoftware, FlavorPartAssetTag,
+		FlavorPartContainerImage}
 }
 
 // GetFlavorTypesString returns a list of flavor types as strings for given flavor types
@@ -70,6 +74,8 @@ func (flavorPart *FlavorPart) Parse(flavorPartString string) error {
 		result = FlavorPartSoftware
 	case string(FlavorPartAssetTag):
 		result = FlavorPartAssetTag
+	case string(FlavorPartContainerImage):
+		result = FlavorPartContainerImage
 	default:
 		err = errors.Errorf("Invalid flavor part string '%s'", flavorPartString)
 	}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+import (
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MetadataFieldType is the type of the value of a custom metadata field
+type MetadataFieldType string
+
+const (
+	MetadataFieldTypeString  MetadataFieldType = "string"
+	MetadataFieldTypeInteger MetadataFieldType = "integer"
+	MetadataFieldTypeNumber  MetadataFieldType = "number"
+	MetadataFieldTypeBoolean MetadataFieldType = "boolean"
+	MetadataFieldTypeDate    MetadataFieldType = "date"
+)
+
+// metadataFieldNameReg restricts the field names since they are used as json keys in flavor searches
+var metadataFieldNameReg = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// MetadataField describes an operator defined custom metadata field that can be set on a flavor
+type MetadataField struct {
+	Name     string            `json:"name" yaml:"name" mapstructure:"name"`
+	Type     MetadataFieldType `json:"type" yaml:"type" mapstructure:"type"`
+	Required bool              `json:"required,omitempty" yaml:"required" mapstructure:"required"`
+}
+
+// MetadataSchema is the list of custom metadata fields allowed on a flavor
+type MetadataSchema []MetadataField
+
+// ValidateFieldName checks that name can be used as a custom metadata field name
+func ValidateFieldName(name string) error {
+	if !metadataFieldNameReg.MatchString(name) {
+		return errors.Errorf("Invalid custom metadata field name '%s'", name)
+	}
+	return nil
+}
+
+// Check verifies that the schema fields have unique valid names and known types
+func (schema MetadataSchema) Check() error {
+	names := make(map[string]bool, len(schema))
+	for _, field := range schema {
+		if err := ValidateFieldName(field.Name); err != nil {
+			return err
+		}
+		if names[field.Name] {
+			return errors.Errorf("Custom metadata field '%s' is defined more than once", field.Name)
+		}
+		names[field.Name] = true
+		switch field.Type {
+		case MetadataFieldTypeString, MetadataFieldTypeInteger, MetadataFieldTypeNumber,
+			MetadataFieldTypeBoolean, MetadataFieldTypeDate:
+		default:
+			return errors.Errorf("Custom metadata field '%s' has unsupported type '%s'", field.Name, field.Type)
+		}
+	}
+	return nil
+}
+
+// Validate checks the custom metadata of a flavor against the schema. Fields that are not part
+// of the schema are rejected, required fields must be present and each value must match the type
+// of its field. Dates are given as RFC3339 timestamps or as YYYY-MM-DD.
+func (schema MetadataSchema) Validate(metadata map[string]interface{}) error {
+	fields := make(map[string]MetadataField, len(schema))
+	for _, field := range schema {
+		fields[field.Name] = field
+	}
+
+	for name, value := range metadata {
+		field, ok := fields[name]
+		if !ok {
+			return errors.Errorf("Custom metadata field '%s' is not defined in the flavor metadata schema", name)
+		}
+		if !isMetadataValueOfType(value, field.Type) {
+			return errors.Errorf("Custom metadata field '%s' must be of type %s", name, field.Type)
+		}
+	}
+
+	for _, field := range schema {
+		if _, ok := metadata[field.Name]; field.Required && !ok {
+			return errors.Errorf("Custom metadata field '%s' is required", field.Name)
+		}
+	}
+	return nil
+}
+
+// isMetadataValueOfType checks a value decoded from json against the field type
+func isMetadataValueOfType(value interface{}, fieldType MetadataFieldType) bool {
+	switch fieldType {
+	case MetadataFieldTypeString:
+		_, ok := value.(string)
+		return ok
+	case MetadataFieldTypeInteger:
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case MetadataFieldTypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case MetadataFieldTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case MetadataFieldTypeDate:
+		date, ok := value.(string)
+		if !ok {
+			return false
+		}
+		if _, err := time.Parse(time.RFC3339, date); err == nil {
+			return true
+		}
+		_, err := time.Parse("2006-01-02", date)
+		return err == nil
+	}
+	return false
+}
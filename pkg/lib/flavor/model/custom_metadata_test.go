@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMetadataSchema = MetadataSchema{
+	{Name: "owner", Type: MetadataFieldTypeString, Required: true},
+	{Name: "change_ticket", Type: MetadataFieldTypeString},
+	{Name: "baseline_version", Type: MetadataFieldTypeInteger},
+	{Name: "approved", Type: MetadataFieldTypeBoolean},
+	{Name: "approved_on", Type: MetadataFieldTypeDate},
+}
+
+func decodeMetadata(t *testing.T, content string) map[string]interface{} {
+	var metadata map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(content), &metadata))
+	return metadata
+}
+
+func TestMetadataSchemaCheck(t *testing.T) {
+	assert.NoError(t, testMetadataSchema.Check())
+	assert.NoError(t, MetadataSchema(nil).Check())
+
+	assert.Error(t, MetadataSchema{{Name: "owner", Type: "uuid"}}.Check())
+	assert.Error(t, MetadataSchema{{Name: "owner'", Type: MetadataFieldTypeString}}.Check())
+	assert.Error(t, MetadataSchema{
+		{Name: "owner", Type: MetadataFieldTypeString},
+		{Name: "owner", Type: MetadataFieldTypeInteger},
+	}.Check())
+}
+
+func TestMetadataSchemaValidate(t *testing.T) {
+	valid := decodeMetadata(t, `{"owner": "platform-team", "change_ticket": "CHG0012345", "baseline_version": 3,
+		"approved": true, "approved_on": "2020-10-01"}`)
+	assert.NoError(t, testMetadataSchema.Validate(valid))
+	assert.NoError(t, testMetadataSchema.Validate(decodeMetadata(t, `{"owner": "a", "approved_on": "2020-10-01T10:00:00Z"}`)))
+
+	// missing required field
+	assert.Error(t, testMetadataSchema.Validate(decodeMetadata(t, `{"change_ticket": "CHG0012345"}`)))
+	assert.Error(t, testMetadataSchema.Validate(nil))
+	// field not in schema
+	assert.Error(t, testMetadataSchema.Validate(decodeMetadata(t, `{"owner": "a", "site": "lab"}`)))
+	// type mismatches
+	assert.Error(t, testMetadataSchema.Validate(decodeMetadata(t, `{"owner": 1}`)))
+	assert.Error(t, testMetadataSchema.Validate(decodeMetadata(t, `{"owner": "a", "baseline_version": 3.5}`)))
+	assert.Error(t, testMetadataSchema.Validate(decodeMetadata(t, `{"owner": "a", "approved": "yes"}`)))
+	assert.Error(t, testMetadataSchema.Validate(decodeMetadata(t, `{"owner": "a", "approved_on": "01/10/2020"}`)))
+
+	// no schema configured only accepts flavors without custom metadata
+	assert.NoError(t, MetadataSchema(nil).Validate(nil))
+	assert.Error(t, MetadataSchema(nil).Validate(valid))
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// IsDelta returns true if the flavor is a delta flavor that only overrides the pcrs and event log
+// entries of a base flavor
+func (flavor *Flavor) IsDelta() bool {
+	return flavor.Meta.BaseFlavorID != nil && *flavor.Meta.BaseFlavorID != uuid.Nil
+}
+
+// MergeDelta returns the flavor obtained by applying the delta flavor to its base flavor. The pcr
+// values of the delta flavor replace those of the base flavor, event log entries of the delta flavor
+// replace the base flavor entries with the same label and are appended otherwise. The merged flavor
+// carries the ID of the delta flavor.
+func MergeDelta(base, delta *Flavor) (*Flavor, error) {
+
+	if base == nil || delta == nil {
+		return nil, errors.New("The base and delta flavors must be provided and cannot be nil")
+	}
+
+	if !delta.IsDelta() || *delta.Meta.BaseFlavorID != base.Meta.ID {
+		return nil, errors.Errorf("Flavor '%s' is not a delta of flavor '%s'", delta.Meta.ID, base.Meta.ID)
+	}
+
+	if base.IsDelta() {
+		return nil, errors.Errorf("The base flavor '%s' cannot be a delta flavor", base.Meta.ID)
+	}
+
+	if delta.Meta.Description.FlavorPart != base.Meta.Description.FlavorPart {
+		return nil, errors.Errorf("The delta flavor part '%s' does not match the base flavor part '%s'",
+			delta.Meta.Description.FlavorPart, base.Meta.Description.FlavorPart)
+	}
+
+	// deep copy the base flavor so that the pcr maps and event slices are not shared
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred attempting to convert the base flavor to json")
+	}
+	var merged Flavor
+	err = json.Unmarshal(baseJSON, &merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred attempting to copy the base flavor")
+	}
+
+	merged.Meta.ID = delta.Meta.ID
+	if delta.Meta.Description.Label != "" {
+		merged.Meta.Description.Label = delta.Meta.Description.Label
+	}
+
+	if merged.Pcrs == nil {
+		merged.Pcrs = make(map[string]map[string]PcrEx)
+	}
+	for bank, deltaPcrs := range delta.Pcrs {
+		if _, ok := merged.Pcrs[bank]; !ok {
+			merged.Pcrs[bank] = make(map[string]PcrEx)
+		}
+		for index, deltaPcr := range deltaPcrs {
+			mergedPcr := merged.Pcrs[bank][index]
+			if deltaPcr.Value != "" {
+				mergedPcr.Value = deltaPcr.Value
+			}
+			for _, deltaEvent := range deltaPcr.Event {
+				replaced := false
+				for i := range mergedPcr.Event {
+					if deltaEvent.Label != "" && mergedPcr.Event[i].Label == deltaEvent.Label {
+						mergedPcr.Event[i] = deltaEvent
+						replaced = true
+						break
+					}
+				}
+				if !replaced {
+					mergedPcr.Event = append(mergedPcr.Event, deltaEvent)
+				}
+			}
+			if len(deltaPcr.Exclude) > 0 {
+				// copy the exclusions so that the base flavor is not altered
+				mergedPcr.Exclude = append(mergedPcr.Exclude[:len(mergedPcr.Exclude):len(mergedPcr.Exclude)], deltaPcr.Exclude...)
+			}
+			if mergedPcr.Value == "" {
+				return nil, errors.Errorf("The delta flavor does not provide a value for bank '%s', pcr %s", bank, index)
+			}
+			merged.Pcrs[bank][index] = mergedPcr
+		}
+	}
+
+	return &merged, nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOsFlavor(id uuid.UUID, baseFlavorID *uuid.UUID, pcrs map[string]map[string]PcrEx) *Flavor {
+	return &Flavor{
+		Meta: Meta{
+			ID:           id,
+			BaseFlavorID: baseFlavorID,
+			Description:  Description{FlavorPart: "OS", Label: id.String()},
+		},
+		Pcrs: pcrs,
+	}
+}
+
+func TestMergeDelta(t *testing.T) {
+	baseID := uuid.New()
+	deltaID := uuid.New()
+
+	base := newOsFlavor(baseID, nil, map[string]map[string]PcrEx{
+		"SHA256": {
+			"pcr_17": {
+				Value: "base17",
+				Event: []hcTypes.EventLog{
+					{Label: "vmlinuz", Value: "oldkernel"},
+					{Label: "initrd", Value: "oldinitrd"},
+				},
+			},
+			"pcr_18": {Value: "base18"},
+		},
+	})
+	delta := newOsFlavor(deltaID, &baseID, map[string]map[string]PcrEx{
+		"SHA256": {
+			"pcr_17": {
+				Value: "delta17",
+				Event: []hcTypes.EventLog{
+					{Label: "vmlinuz", Value: "newkernel"},
+					{Label: "module", Value: "newmodule"},
+				},
+			},
+		},
+	})
+
+	assert.False(t, base.IsDelta())
+	assert.True(t, delta.IsDelta())
+
+	merged, err := MergeDelta(base, delta)
+	assert.NoError(t, err)
+	assert.Equal(t, deltaID, merged.Meta.ID)
+	assert.Equal(t, "delta17", merged.Pcrs["SHA256"]["pcr_17"].Value)
+	assert.Equal(t, "base18", merged.Pcrs["SHA256"]["pcr_18"].Value)
+	assert.Equal(t, []hcTypes.EventLog{
+		{Label: "vmlinuz", Value: "newkernel"},
+		{Label: "initrd", Value: "oldinitrd"},
+		{Label: "module", Value: "newmodule"},
+	}, merged.Pcrs["SHA256"]["pcr_17"].Event)
+
+	// the base flavor is left untouched
+	assert.Equal(t, "base17", base.Pcrs["SHA256"]["pcr_17"].Value)
+	assert.Equal(t, "oldkernel", base.Pcrs["SHA256"]["pcr_17"].Event[0].Value)
+}
+
+func TestMergeDeltaInvalid(t *testing.T) {
+	baseID := uuid.New()
+	otherID := uuid.New()
+	base := newOsFlavor(baseID, nil, nil)
+
+	// delta of another flavor
+	_, err := MergeDelta(base, newOsFlavor(uuid.New(), &otherID, nil))
+	assert.Error(t, err)
+
+	// not a delta flavor
+	_, err = MergeDelta(base, newOsFlavor(uuid.New(), nil, nil))
+	assert.Error(t, err)
+
+	// flavor part mismatch
+	delta := newOsFlavor(uuid.New(), &baseID, nil)
+	delta.Meta.Description.FlavorPart = "PLATFORM"
+	_, err = MergeDelta(base, delta)
+	assert.Error(t, err)
+
+	// new pcr without a value
+	delta = newOsFlavor(uuid.New(), &baseID, map[string]map[string]PcrEx{
+		"SHA256": {"pcr_19": {Event: []hcTypes.EventLog{{Label: "module", Value: "newmodule"}}}},
+	})
+	_, err = MergeDelta(base, delta)
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+import hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+
+// FirmwareManifest holds the golden firmware measurements published by a platform vendor, such as a
+// reference integrity manifest, from which PLATFORM flavors are built instead of a live good known host
+type FirmwareManifest struct {
+	// Source names the manifest the measurements were taken from, e.g. its file name or URI
+	Source      string                `json:"source,omitempty"`
+	BiosName    string                `json:"bios_name"`
+	BiosVersion string                `json:"bios_version"`
+	TpmVersion  string                `json:"tpm_version,omitempty"`
+	Feature     *Feature              `json:"feature,omitempty"`
+	Pcrs        []FirmwareManifestPcr `json:"pcrs"`
+}
+
+// FirmwareManifestPcr is the expected state of a PCR in a FirmwareManifest. The Value is computed by
+// replaying the Events when it is not given by the vendor, and must match the replay otherwise.
+type FirmwareManifestPcr struct {
+	Index  hcTypes.PcrIndex     `json:"index"`
+	Bank   hcTypes.SHAAlgorithm `json:"pcr_bank"`
+	Value  string               `json:"value,omitempty"`
+	Events []hcTypes.EventLog   `json:"event,omitempty"`
+}
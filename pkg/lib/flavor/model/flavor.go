@@ -6,10 +6,13 @@ package model
 
 import (
 	"crypto/sha512"
+	"encoding/hex"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/serialize"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 )
 
@@ -18,9 +21,22 @@ import (
  * @author mullas
  */
 
+// FlavorSchemaVersion is the schema version of the flavor JSON written by this version
+const FlavorSchemaVersion = 1
+
+// flavorSchema upgrades the flavors written by previous versions, the upgrades must keep the signed content of
+// the flavors unchanged for their signatures to be verified
+var flavorSchema = serialize.SchemaUpgrader{
+	Kind:           "flavor",
+	CurrentVersion: FlavorSchemaVersion,
+	Upgrades:       map[int]serialize.SchemaUpgrade{},
+}
+
 // Flavor is a standardized set of expectations that determines what platform
 // measurements will be considered “trusted.”
 type Flavor struct {
+	// SchemaVersion is the version of the flavor JSON, it is not part of the signed content of the flavor
+	SchemaVersion int `json:"schema_version,omitempty"`
 	// Meta section is mandatory for all Flavor types
 	Meta Meta  `json:"meta"`
 	Bios *Bios `json:"bios,omitempty"`
@@ -30,6 +46,8 @@ type Flavor struct {
 	// External section is unique to AssetTag Flavor type
 	External *External `json:"external,omitempty"`
 	Software *Software `json:"software,omitempty"`
+	// ContainerImages section is unique to ContainerImage Flavor type
+	ContainerImages []ta.ContainerImageMeasurement `json:"container_images,omitempty"`
 }
 
 // NewFlavor returns a new instance of Flavor
@@ -43,15 +61,47 @@ func NewFlavor(meta *Meta, bios *Bios, hardware *Hardware, pcrs map[crypt.Digest
 		}
 	}
 	return &Flavor{
-		Meta:     *meta,
-		Bios:     bios,
-		Hardware: hardware,
-		Pcrs:     pcrx,
-		External: external,
-		Software: software,
+		SchemaVersion: FlavorSchemaVersion,
+		Meta:          *meta,
+		Bios:          bios,
+		Hardware:      hardware,
+		Pcrs:          pcrx,
+		External:      external,
+		Software:      software,
 	}
 }
 
+// unversionedFlavor has the fields of Flavor without its JSON encoding
+type unversionedFlavor Flavor
+
+// MarshalJSON writes the flavor with the current schema version
+func (flavor Flavor) MarshalJSON() ([]byte, error) {
+	versioned := unversionedFlavor(flavor)
+	versioned.SchemaVersion = FlavorSchemaVersion
+	return json.Marshal(versioned)
+}
+
+// UnmarshalJSON reads a flavor written with any schema version, the flavors of previous versions are upgraded
+// to the current version
+func (flavor *Flavor) UnmarshalJSON(b []byte) error {
+	decoded := unversionedFlavor{}
+	// the fields decoded before an error are kept, as they are by json.Unmarshal
+	err := json.Unmarshal(b, &decoded)
+	if err == nil && flavorSchema.NeedsUpgrade(decoded.SchemaVersion) {
+		var upgraded []byte
+		if upgraded, err = flavorSchema.Upgrade(b, decoded.SchemaVersion); err != nil {
+			return err
+		}
+		decoded = unversionedFlavor{}
+		err = json.Unmarshal(upgraded, &decoded)
+	}
+	if decoded.SchemaVersion < FlavorSchemaVersion {
+		decoded.SchemaVersion = FlavorSchemaVersion
+	}
+	*flavor = Flavor(decoded)
+	return err
+}
+
 // Utility function for retrieving the PcrEx value at 'bank', 'index'.  Returns
 // an error if the pcr cannot be found.
 func (flavor *Flavor) GetPcrValue(bank types.SHAAlgorithm, index types.PcrIndex) (*PcrEx, error) {
@@ -67,14 +117,26 @@ func (flavor *Flavor) GetPcrValue(bank types.SHAAlgorithm, index types.PcrIndex)
 	}
 }
 
-// GetFlavorDigest Calculates the SHA384 hash of the Flavor's json data for use when
+// GetFlavorDigest Calculates the SHA384 hash of the Flavor's canonical json data (RFC 8785) for use when
 // signing/verifying signed flavors.
 func (flavor *Flavor) getFlavorDigest() ([]byte, error) {
-	// account for a differences in properties set at runtime
-	tempFlavor := *flavor
+	return flavor.digest(crypt.MarshalCanonicalJSON)
+}
+
+// getLegacyFlavorDigest Calculates the SHA384 hash of the Flavor's json data as encoded by Go, which the flavors
+// signed before the json was canonicalized are verified against.
+func (flavor *Flavor) getLegacyFlavorDigest() ([]byte, error) {
+	return flavor.digest(json.Marshal)
+}
+
+func (flavor *Flavor) digest(marshal func(interface{}) ([]byte, error)) ([]byte, error) {
+	// account for a differences in properties set at runtime, the schema version is left out so that the
+	// signatures of the flavors written before they were versioned remain valid
+	tempFlavor := unversionedFlavor(*flavor)
 	tempFlavor.Meta.ID = uuid.Nil
+	tempFlavor.SchemaVersion = 0
 
-	flavorJSON, err := json.Marshal(tempFlavor)
+	flavorJSON, err := marshal(tempFlavor)
 	if err != nil {
 		return nil, errors.Wrap(err, "An error occurred attempting to convert the flavor to json")
 	}
@@ -90,3 +152,13 @@ func (flavor *Flavor) getFlavorDigest() ([]byte, error) {
 	}
 	return hashEntity.Sum(nil), nil
 }
+
+// GetFlavorVersion returns the hex encoded SHA384 digest of the signed content of the flavor, the flavors with the
+// same expected values have the same version
+func (flavor *Flavor) GetFlavorVersion() (string, error) {
+	digest, err := flavor.getFlavorDigest()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest), nil
+}
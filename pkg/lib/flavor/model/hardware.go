@@ -4,6 +4,8 @@
  */
 package model
 
+import ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+
 /**
  *
  * @author mullas
@@ -15,4 +17,6 @@ type Hardware struct {
 	ProcessorInfo  string   `json:"processor_info,omitempty"`
 	ProcessorFlags string   `json:"processor_flags,omitempty"`
 	Feature        *Feature `json:"feature,omitempty"`
+	// Firmware are the firmware versions of the platform inventory read from the BMC of the host
+	Firmware []ta.FirmwareComponent `json:"firmware,omitempty"`
 }
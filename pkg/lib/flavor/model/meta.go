@@ -22,6 +22,13 @@ type Meta struct {
 	Realm       string             `json:"realm,omitempty"`
 	Description Description        `json:"description,omitempty"`
 	Vendor      hcConstants.Vendor `json:"vendor,omitempty"`
+	// BaseFlavorID is set for delta flavors and references the flavor whose pcrs are overridden
+	// swagger:strfmt uuid
+	BaseFlavorID *uuid.UUID `json:"base_flavor_id,omitempty"`
+	// CustomMetadata holds the operator defined fields validated against the flavor metadata schema
+	CustomMetadata map[string]interface{} `json:"custom_metadata,omitempty"`
+	// Labels select the flavorgroups of the flavor whose flavor selector matches them
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Schema defines the Uri of the schema
@@ -16,6 +16,8 @@ import hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types
 type PcrEx struct {
 	Value string             `json:"value"`
 	Event []hcTypes.EventLog `json:"event,omitempty"`
+	// Exclude lists the events of the host's event log that are not evaluated, such as volatile events
+	Exclude []hcTypes.EventLogExclusion `json:"exclude,omitempty"`
 }
 
 // NewPcrEx returns a initialized PcrEx instance
@@ -22,6 +22,9 @@ import (
 type SignedFlavor struct {
 	Flavor    Flavor `json:"flavor"`
 	Signature string `json:"signature"`
+	// AdditionalSignatures are the signatures of the flavor by other flavor signing keys, for the verifiers
+	// requiring a quorum of signers
+	AdditionalSignatures []string `json:"additional_signatures,omitempty"`
 }
 
 // NewSignedFlavor Provided an existing flavor and a privatekey, create a SignedFlavor
@@ -53,6 +56,28 @@ func NewSignedFlavor(flavor *Flavor, privateKey *rsa.PrivateKey) (*SignedFlavor,
 	}, nil
 }
 
+// AddSignature signs the flavor with another flavor signing key and adds the signature to the
+// additional signatures of the signed flavor
+func (signedFlavor *SignedFlavor) AddSignature(privateKey *rsa.PrivateKey) error {
+
+	if privateKey == nil || privateKey.Validate() != nil {
+		return errors.New("Valid private key must be provided and cannot be nil")
+	}
+
+	flavorDigest, err := signedFlavor.Flavor.getFlavorDigest()
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while collecting the flavor digest")
+	}
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA384, flavorDigest)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while signing the flavor")
+	}
+
+	signedFlavor.AdditionalSignatures = append(signedFlavor.AdditionalSignatures, base64.StdEncoding.EncodeToString(signature))
+	return nil
+}
+
 // Verify Provided the public key from the Flavor Signing Certificate,
 // verify that the signed flavor's signature is valid.
 func (signedFlavor *SignedFlavor) Verify(publicKey *rsa.PublicKey) error {
@@ -61,7 +86,14 @@ func (signedFlavor *SignedFlavor) Verify(publicKey *rsa.PublicKey) error {
 		return errors.New("Could not verify the signed flavor: The signed flavor that does not have a signature")
 	}
 
-	signatureBytes, err := base64.StdEncoding.DecodeString(signedFlavor.Signature)
+	return signedFlavor.VerifySignature(signedFlavor.Signature, publicKey)
+}
+
+// VerifySignature verifies that the signature, the signed flavor's or one made by another flavor signing key,
+// is a valid signature of the flavor by the public key
+func (signedFlavor *SignedFlavor) VerifySignature(signature string, publicKey *rsa.PublicKey) error {
+
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
 	if err != nil {
 		return errors.Wrap(err, "Could not verify the signed flavor: An error occurred attempting to decode the signed flavor's signature")
 	}
@@ -72,9 +104,43 @@ func (signedFlavor *SignedFlavor) Verify(publicKey *rsa.PublicKey) error {
 	}
 
 	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA384, flavorDigest, signatureBytes)
-	if err != nil {
-		return errors.Wrap(err, "Could not verify the signed flavor: PKCS1 verification failed")
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	// the flavors signed before the flavor json was canonicalized are signed over the json encoded by Go
+	legacyDigest, legacyErr := signedFlavor.Flavor.getLegacyFlavorDigest()
+	if legacyErr == nil && rsa.VerifyPKCS1v15(publicKey, crypto.SHA384, legacyDigest, signatureBytes) == nil {
+		return nil
+	}
+	return errors.Wrap(err, "Could not verify the signed flavor: PKCS1 verification failed")
+}
+
+// CountSigners returns how many of the public keys made a valid signature of the flavor, either the signed
+// flavor's signature or one of its additional signatures. A key is counted once, however many signatures it made.
+func (signedFlavor *SignedFlavor) CountSigners(publicKeys []*rsa.PublicKey) int {
+
+	signatures := append([]string{signedFlavor.Signature}, signedFlavor.AdditionalSignatures...)
+	var signers []*rsa.PublicKey
+	for _, publicKey := range publicKeys {
+		if publicKey == nil || containsPublicKey(signers, publicKey) {
+			continue
+		}
+		for _, signature := range signatures {
+			if signature != "" && signedFlavor.VerifySignature(signature, publicKey) == nil {
+				signers = append(signers, publicKey)
+				break
+			}
+		}
+	}
+	return len(signers)
+}
+
+func containsPublicKey(publicKeys []*rsa.PublicKey, publicKey *rsa.PublicKey) bool {
+	for _, key := range publicKeys {
+		if key.Equal(publicKey) {
+			return true
+		}
+	}
+	return false
 }
@@ -5,6 +5,10 @@
 package model
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -687,3 +691,98 @@ func TestNewSignedFlavorFromJSONPlatform(t *testing.T) {
 		})
 	}
 }
+
+func TestSignedFlavorCountSigners(t *testing.T) {
+	var keys []*rsa.PrivateKey
+	var publicKeys []*rsa.PublicKey
+	for i := 0; i < 3; i++ {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		keys = append(keys, key)
+		publicKeys = append(publicKeys, &key.PublicKey)
+	}
+
+	unsignedFlavor, err := newSignedFlavorFromJSON(goodSignedPlatformFlavor)
+	assert.NoError(t, err)
+	signedFlavor, err := NewSignedFlavor(&unsignedFlavor.Flavor, keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, signedFlavor.CountSigners(publicKeys))
+
+	// the same key signing again is not counted twice
+	assert.NoError(t, signedFlavor.AddSignature(keys[0]))
+	assert.Equal(t, 1, signedFlavor.CountSigners(publicKeys))
+
+	assert.NoError(t, signedFlavor.AddSignature(keys[1]))
+	assert.Equal(t, 2, signedFlavor.CountSigners(publicKeys))
+	assert.Equal(t, 1, signedFlavor.CountSigners(publicKeys[1:]))
+	assert.NoError(t, signedFlavor.Verify(publicKeys[0]))
+
+	// the additional signatures survive the JSON round trip, and do not verify a modified flavor
+	sfJSON, err := json.Marshal(signedFlavor)
+	assert.NoError(t, err)
+	roundTripped, err := newSignedFlavorFromJSON(string(sfJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, roundTripped.CountSigners(publicKeys))
+	roundTripped.Flavor.Meta.Description.Label = "modified"
+	assert.Equal(t, 0, roundTripped.CountSigners(publicKeys))
+}
+
+func TestSignedFlavorSchemaVersion(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	// a flavor signed before the flavors were versioned
+	unversionedFlavor, err := newSignedFlavorFromJSON(goodSignedPlatformFlavor)
+	assert.NoError(t, err)
+	signedFlavor, err := NewSignedFlavor(&unversionedFlavor.Flavor, key)
+	assert.NoError(t, err)
+	var flavor map[string]interface{}
+	flavorJSON, err := json.Marshal(signedFlavor.Flavor)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(flavorJSON, &flavor))
+	delete(flavor, "schema_version")
+	signedFlavorJSON := func() string {
+		sfJSON, err := json.Marshal(map[string]interface{}{"flavor": flavor, "signature": signedFlavor.Signature})
+		assert.NoError(t, err)
+		return string(sfJSON)
+	}
+
+	// is read with the current version and its signature is still verified
+	upgradedFlavor, err := newSignedFlavorFromJSON(signedFlavorJSON())
+	assert.NoError(t, err)
+	assert.Equal(t, FlavorSchemaVersion, upgradedFlavor.Flavor.SchemaVersion)
+	assert.NoError(t, upgradedFlavor.Verify(&key.PublicKey))
+	sfJSON, err := json.Marshal(upgradedFlavor)
+	assert.NoError(t, err)
+	assert.Contains(t, string(sfJSON), `"schema_version":1`)
+
+	// a flavor of a newer version is read as far as it is understood
+	flavor["schema_version"] = FlavorSchemaVersion + 1
+	flavor["unknown_section"] = map[string]string{"field": "value"}
+	newerFlavor, err := newSignedFlavorFromJSON(signedFlavorJSON())
+	assert.NoError(t, err)
+	assert.Equal(t, FlavorSchemaVersion+1, newerFlavor.Flavor.SchemaVersion)
+	assert.NoError(t, newerFlavor.Verify(&key.PublicKey))
+}
+
+func TestSignedFlavorLegacySignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	signedFlavor, err := newSignedFlavorFromJSON(goodSignedPlatformFlavor)
+	assert.NoError(t, err)
+	canonicalDigest, err := signedFlavor.Flavor.getFlavorDigest()
+	assert.NoError(t, err)
+	legacyDigest, err := signedFlavor.Flavor.getLegacyFlavorDigest()
+	assert.NoError(t, err)
+	assert.NotEqual(t, canonicalDigest, legacyDigest)
+
+	// a flavor signed over the json encoded by Go before it was canonicalized is still verified
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA384, legacyDigest)
+	assert.NoError(t, err)
+	signedFlavor.Signature = base64.StdEncoding.EncodeToString(signature)
+	assert.NoError(t, signedFlavor.Verify(&key.PublicKey))
+
+	signedFlavor.Flavor.Meta.Description.Label = "modified"
+	assert.Error(t, signedFlavor.Verify(&key.PublicKey))
+}
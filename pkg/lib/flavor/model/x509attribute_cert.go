@@ -7,7 +7,6 @@ package model
 import (
 	"crypto"
 	"crypto/x509"
-	"encoding/asn1"
 	"encoding/base64"
 	asset_tag "github.com/intel-secl/intel-secl/v3/pkg/lib/asset-tag"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
@@ -57,7 +56,6 @@ func NewX509AttributeCertificate(tagCert *x509.Certificate) (*X509AttributeCerti
 
 	// check for the custom ASN1 tags in Extra Extensions and pack into the Attributes
 	for _, attrExt := range tagCert.Extensions {
-		var tagkva1 asset_tag.TagKvAttribute
 		var attrObjects []AttrObjects
 		var attrkva Attribute
 
@@ -65,7 +63,7 @@ func NewX509AttributeCertificate(tagCert *x509.Certificate) (*X509AttributeCerti
 		attrkva.AttrType.ID = attrExt.Id.String()
 
 		// fill in the values
-		_, err := asn1.Unmarshal(attrExt.Value, &tagkva1)
+		tagkva1, err := asset_tag.UnmarshalTagKvAttribute(attrExt.Value)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failure unmarshalling ASN1 Attributes")
 		}
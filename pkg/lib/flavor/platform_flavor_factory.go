@@ -109,3 +109,16 @@ func (pff PlatformFlavorProvider) GetGenericPlatformFlavor(vendor hcConstants.Ve
 	return &gpf, nil
 
 }
+
+// GetFirmwarePlatformFlavor creates an instance of a FirmwarePlatformFlavor from the golden firmware measurements
+// published by the platform vendor, the PLATFORM flavor is built without a live good known host
+func GetFirmwarePlatformFlavor(firmwareManifest *model.FirmwareManifest) (*types.PlatformFlavor, error) {
+	log.Trace("flavor/platform_flavor_factory:GetFirmwarePlatformFlavor() Entering")
+	defer log.Trace("flavor/platform_flavor_factory:GetFirmwarePlatformFlavor() Leaving")
+
+	fwpf, err := types.NewFirmwarePlatformFlavor(firmwareManifest)
+	if err != nil {
+		return nil, errors.Wrap(err, common.INVALID_INPUT().Message)
+	}
+	return &fwpf, nil
+}
@@ -6,7 +6,9 @@ package flavor
 
 import (
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
@@ -16,10 +18,12 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/util"
 	hcConstants "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
 	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"math/big"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -72,8 +76,9 @@ func TestLinuxPlatformFlavorGetFlavorParts(t *testing.T) {
 	var pffactory FlavorProvider
 	var err error
 
-	// expected FlavorParts
-	expFlavorParts := cf.GetFlavorTypes()
+	// expected FlavorParts, the host manifest does not have container image measurements
+	expFlavorParts := []cf.FlavorPart{cf.FlavorPartPlatform, cf.FlavorPartOs, cf.FlavorPartHostUnique,
+		cf.FlavorPartSoftware, cf.FlavorPartAssetTag}
 
 	// load hostManifest and tagCertificate
 	hm, tagCert := loadManifestAndTagCert(RHELManifestPath, TagCertPath)
@@ -95,8 +100,9 @@ func TestLinuxPlatformFlavorGetSignedPlatformFlavorWithoutAssetTag(t *testing.T)
 	var pffactory FlavorProvider
 	var err error
 
-	// expected FlavorParts
-	expFlavorParts := cf.GetFlavorTypes()
+	// expected FlavorParts, the host manifest does not have container image measurements
+	expFlavorParts := []cf.FlavorPart{cf.FlavorPartPlatform, cf.FlavorPartOs, cf.FlavorPartHostUnique,
+		cf.FlavorPartSoftware, cf.FlavorPartAssetTag}
 
 	// load hostManifest and tagCertificate
 	hm, tagCert := loadManifestAndTagCert(RHELManifestPath, "")
@@ -129,8 +135,9 @@ func TestLinuxPlatformFlavorGetSignedPlatformFlavor(t *testing.T) {
 	var pffactory FlavorProvider
 	var err error
 
-	// expected FlavorParts
-	expFlavorParts := cf.GetFlavorTypes()
+	// expected FlavorParts, the host manifest does not have container image measurements
+	expFlavorParts := []cf.FlavorPart{cf.FlavorPartPlatform, cf.FlavorPartOs, cf.FlavorPartHostUnique,
+		cf.FlavorPartSoftware, cf.FlavorPartAssetTag}
 
 	// load hostManifest and tagCertificate
 	hm, tagCert := loadManifestAndTagCert(RHELManifestPath, TagCertPath)
@@ -231,6 +238,39 @@ func TestLinuxPlatformFlavorGetSignedSoftwareFlavor(t *testing.T) {
 	getSignedFlavor(t, pflavor, cf.FlavorPartSoftware)
 }
 
+// TestLinuxPlatformFlavorGetSignedContainerImageFlavor fetches the Container Image flavor of a host
+// reporting container image measurements and fetches the corresponding SignedFlavor
+func TestLinuxPlatformFlavorGetSignedContainerImageFlavor(t *testing.T) {
+	// load hostManifest and add the measurements of the workload agent
+	hm, _ := loadManifestAndTagCert(RHELManifestPath, "")
+	hm.ContainerImageMeasurements = []ta.ContainerImageMeasurement{
+		{
+			Name:             "registry.example.com/nginx:1.19",
+			Digest:           "sha256:7cbc2e5c9f3ab6b0f0a3a5d1c2ed1d5d1a1e5ff0f16fd2aab5b0b0f1b57a5b0e",
+			DmVerityRootHash: "4392b8b08f6e1c0f6b0bd0a3b7b6d9e1c4ee1fd80f0a0e8b7f6c3e1b8f0d7a6c",
+		},
+	}
+
+	pffactory, err := NewPlatformFlavorProvider(hm, nil)
+	assert.NoError(t, err, "Error initializing PlatformFlavorProvider")
+
+	// get the flavor
+	pflavor, err := pffactory.GetPlatformFlavor()
+	assert.NoError(t, err, "Error initializing PlatformFlavor")
+	pFlavorParts, err := (*pflavor).GetFlavorPartNames()
+	assert.NoError(t, err, "Error fetching flavor parts")
+
+	checkIfRequiredFlavorsArePresent(t, []cf.FlavorPart{cf.FlavorPartContainerImage}, pFlavorParts)
+
+	flavors, err := (*pflavor).GetFlavorPartRaw(cf.FlavorPartContainerImage)
+	assert.NoError(t, err, "Error fetching the Container Image flavor")
+	assert.Equal(t, 1, len(flavors))
+	assert.Equal(t, hm.ContainerImageMeasurements, flavors[0].ContainerImages)
+	assert.Empty(t, flavors[0].Pcrs)
+
+	getSignedFlavor(t, pflavor, cf.FlavorPartContainerImage)
+}
+
 // TestCreateAssetTagFlavorOnly fetches the ASSET_TAG flavor using
 // GetFlavorPartNames() method implementation of LinuxPlatformFlavor
 // And fetches the corresponding SignedFlavor
@@ -531,3 +571,65 @@ func loadManifestAndTagCert(hmFilePath string, tcFilePath string) (*hcTypes.Host
 
 	return &hm, tagCert
 }
+
+// TestFirmwarePlatformFlavor validates that the PLATFORM flavor built from a firmware manifest carries the
+// replayed PCR values and the expected events
+func TestFirmwarePlatformFlavor(t *testing.T) {
+	event := hcTypes.EventLog{
+		Value: "1cb1e7bd1a4bbf4ed2d37aa8e84cf6b48d3a6f2a2f8a5dcc6dbb8d1f9d80a1b2",
+		Label: "HASH_START",
+	}
+	eventDigest, _ := hex.DecodeString(event.Value)
+	replayedPcr := sha256.Sum256(append(make([]byte, sha256.Size), eventDigest...))
+
+	fwManifest := model.FirmwareManifest{
+		Source:      "RIM-S2600WFT",
+		BiosName:    "Intel Corporation",
+		BiosVersion: "SE5C620.86B.00.01.0014.070920180847",
+		TpmVersion:  "2.0",
+		Feature: &model.Feature{
+			TXT: &model.TXT{Enabled: true},
+		},
+		Pcrs: []model.FirmwareManifestPcr{
+			{
+				Index:  hcTypes.PCR0,
+				Bank:   hcTypes.SHA256,
+				Events: []hcTypes.EventLog{event},
+			},
+		},
+	}
+
+	pflavor, err := GetFirmwarePlatformFlavor(&fwManifest)
+	assert.NoError(t, err)
+
+	flavorParts, err := (*pflavor).GetFlavorPartNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []cf.FlavorPart{cf.FlavorPartPlatform}, flavorParts)
+
+	flavors, err := (*pflavor).GetFlavorPartRaw(cf.FlavorPartPlatform)
+	assert.NoError(t, err)
+	assert.Len(t, flavors, 1)
+	assert.Equal(t, fwManifest.BiosVersion, flavors[0].Bios.BiosVersion)
+	assert.Equal(t, fwManifest.Source, flavors[0].Meta.Description.Source)
+	assert.True(t, flavors[0].Hardware.Feature.TXT.Enabled)
+
+	pcr0, err := flavors[0].GetPcrValue(hcTypes.SHA256, hcTypes.PCR0)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(replayedPcr[:]), pcr0.Value)
+	assert.Len(t, pcr0.Event, 1)
+	assert.Equal(t, event.Label, pcr0.Event[0].Label)
+
+	_, err = (*pflavor).GetFlavorPartRaw(cf.FlavorPartOs)
+	assert.Error(t, err)
+
+	// the given value must match the replay of the events
+	fwManifest.Pcrs[0].Value = strings.Repeat("0", 64)
+	_, err = GetFirmwarePlatformFlavor(&fwManifest)
+	assert.Error(t, err)
+
+	// the PCR banks are limited to the ones supported by the flavors
+	fwManifest.Pcrs[0].Value = ""
+	fwManifest.Pcrs[0].Bank = hcTypes.SHA512
+	_, err = GetFirmwarePlatformFlavor(&fwManifest)
+	assert.Error(t, err)
+}
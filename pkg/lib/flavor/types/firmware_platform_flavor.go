@@ -0,0 +1,207 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package types
+
+import (
+	cf "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	cm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	hcConstants "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// FirmwarePlatformFlavor is used to generate the PLATFORM flavor from the golden firmware measurements
+// published by a platform vendor, without requiring a live good known host
+type FirmwarePlatformFlavor struct {
+	FirmwareManifest *cm.FirmwareManifest `json:"firmware_manifest"`
+}
+
+// NewFirmwarePlatformFlavor returns an instance of FirmwarePlatformFlavor after validating the firmware manifest
+// and replaying the PCR values of the entries that only list the expected events
+func NewFirmwarePlatformFlavor(firmwareManifest *cm.FirmwareManifest) (PlatformFlavor, error) {
+	log.Trace("flavor/types/firmware_platform_flavor:NewFirmwarePlatformFlavor() Entering")
+	defer log.Trace("flavor/types/firmware_platform_flavor:NewFirmwarePlatformFlavor() Leaving")
+
+	if firmwareManifest == nil {
+		return nil, errors.New("Firmware manifest must be specified")
+	}
+	if strings.TrimSpace(firmwareManifest.BiosName) == "" || strings.TrimSpace(firmwareManifest.BiosVersion) == "" {
+		return nil, errors.New("Firmware manifest must specify the bios name and version")
+	}
+	if len(firmwareManifest.Pcrs) == 0 {
+		return nil, errors.New("Firmware manifest must contain at least one PCR")
+	}
+
+	manifest := *firmwareManifest
+	manifest.Pcrs = make([]cm.FirmwareManifestPcr, 0, len(firmwareManifest.Pcrs))
+	pcrSet := make(map[hcTypes.SHAAlgorithm]map[hcTypes.PcrIndex]bool)
+	for _, pcr := range firmwareManifest.Pcrs {
+		if pcr.Bank != hcTypes.SHA1 && pcr.Bank != hcTypes.SHA256 {
+			return nil, errors.Errorf("Unsupported PCR bank '%s' in firmware manifest", pcr.Bank)
+		}
+		if pcr.Index < hcTypes.PCR0 || pcr.Index > hcTypes.PCR23 {
+			return nil, errors.Errorf("Invalid PCR index %d in firmware manifest", pcr.Index)
+		}
+		if pcrSet[pcr.Bank] == nil {
+			pcrSet[pcr.Bank] = make(map[hcTypes.PcrIndex]bool)
+		}
+		if pcrSet[pcr.Bank][pcr.Index] {
+			return nil, errors.Errorf("Duplicate entry for %s %s in firmware manifest", pcr.Bank, pcr.Index)
+		}
+		pcrSet[pcr.Bank][pcr.Index] = true
+
+		if len(pcr.Events) == 0 {
+			if pcr.Value == "" {
+				return nil, errors.Errorf("Firmware manifest entry for %s %s has neither a value nor events", pcr.Bank, pcr.Index)
+			}
+		} else {
+			eventLogEntry := hcTypes.EventLogEntry{
+				PcrIndex:  pcr.Index,
+				PcrBank:   pcr.Bank,
+				EventLogs: pcr.Events,
+			}
+			replayedValue, err := eventLogEntry.Replay()
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to replay the events of %s %s in firmware manifest", pcr.Bank, pcr.Index)
+			}
+			if pcr.Value == "" {
+				pcr.Value = replayedValue
+			} else if !strings.EqualFold(pcr.Value, replayedValue) {
+				return nil, errors.Errorf("The value of %s %s in firmware manifest does not match the replay of its events", pcr.Bank, pcr.Index)
+			}
+		}
+		manifest.Pcrs = append(manifest.Pcrs, pcr)
+	}
+
+	return FirmwarePlatformFlavor{
+		FirmwareManifest: &manifest,
+	}, nil
+}
+
+// GetFlavorPartRaw constructs the PLATFORM flavor from the firmware manifest
+func (fwpf FirmwarePlatformFlavor) GetFlavorPartRaw(name cf.FlavorPart) ([]cm.Flavor, error) {
+	log.Trace("flavor/types/firmware_platform_flavor:GetFlavorPartRaw() Entering")
+	defer log.Trace("flavor/types/firmware_platform_flavor:GetFlavorPartRaw() Leaving")
+
+	if name == cf.FlavorPartPlatform {
+		return fwpf.getPlatformFlavor()
+	}
+
+	return nil, cf.UNKNOWN_FLAVOR_PART()
+}
+
+// GetFlavorPartNames retrieves the list of flavor parts that can be obtained using the GetFlavorPartRaw function
+func (fwpf FirmwarePlatformFlavor) GetFlavorPartNames() ([]cf.FlavorPart, error) {
+	log.Trace("flavor/types/firmware_platform_flavor:GetFlavorPartNames() Entering")
+	defer log.Trace("flavor/types/firmware_platform_flavor:GetFlavorPartNames() Leaving")
+
+	return []cf.FlavorPart{cf.FlavorPartPlatform}, nil
+}
+
+// getPlatformFlavor returns the PLATFORM flavor having the PCR values and event logs of the firmware manifest
+func (fwpf FirmwarePlatformFlavor) getPlatformFlavor() ([]cm.Flavor, error) {
+	log.Trace("flavor/types/firmware_platform_flavor:getPlatformFlavor() Entering")
+	defer log.Trace("flavor/types/firmware_platform_flavor:getPlatformFlavor() Leaving")
+
+	var errorMessage = "Error during creation of PLATFORM flavor from firmware manifest"
+
+	hostManifest := fwpf.getHostManifest()
+
+	var platformPcrs []int
+	pcrSet := make(map[int]bool)
+	for _, pcr := range fwpf.FirmwareManifest.Pcrs {
+		if !pcrSet[int(pcr.Index)] {
+			pcrSet[int(pcr.Index)] = true
+			platformPcrs = append(platformPcrs, int(pcr.Index))
+		}
+	}
+	var pcrDetails = pfutil.GetPcrDetails(hostManifest.PcrManifest, platformPcrs, true)
+
+	newMeta, err := pfutil.GetMetaSectionDetails(&hostManifest.HostInfo, nil, "", cf.FlavorPartPlatform,
+		hcConstants.VendorIntel)
+	if err != nil {
+		return nil, errors.Wrap(err, errorMessage+" - failure in Meta section details")
+	}
+	log.Debugf("flavor/types/firmware_platform_flavor:getPlatformFlavor() New Meta Section: %v", *newMeta)
+
+	newBios := pfutil.GetBiosSectionDetails(&hostManifest.HostInfo)
+	if newBios == nil {
+		return nil, errors.Errorf(errorMessage + " - failure in Bios section details")
+	}
+
+	newHW := pfutil.GetHardwareSectionDetails(hostManifest)
+	if newHW == nil {
+		return nil, errors.Errorf(errorMessage + " - failure in Hardware section details")
+	}
+
+	platformFlavor := cm.NewFlavor(newMeta, newBios, newHW, pcrDetails, nil, nil)
+
+	log.Debugf("flavor/types/firmware_platform_flavor:getPlatformFlavor() New PlatformFlavor: %v", platformFlavor)
+
+	return []cm.Flavor{*platformFlavor}, nil
+}
+
+// getHostManifest maps the firmware manifest onto the HostManifest of a host that reproduces the golden
+// measurements, so that the PLATFORM flavor is assembled the same way as the flavors from a live host
+func (fwpf FirmwarePlatformFlavor) getHostManifest() *hcTypes.HostManifest {
+	log.Trace("flavor/types/firmware_platform_flavor:getHostManifest() Entering")
+	defer log.Trace("flavor/types/firmware_platform_flavor:getHostManifest() Leaving")
+
+	fwManifest := fwpf.FirmwareManifest
+
+	var hostManifest hcTypes.HostManifest
+	hostInfo := &hostManifest.HostInfo
+	hostInfo.HostName = fwManifest.Source
+	hostInfo.BiosName = strings.TrimSpace(fwManifest.BiosName)
+	hostInfo.BiosVersion = strings.TrimSpace(fwManifest.BiosVersion)
+	hostInfo.HardwareFeatures.TPM.Enabled = true
+	hostInfo.HardwareFeatures.TPM.Meta.TPMVersion = fwManifest.TpmVersion
+
+	if feature := fwManifest.Feature; feature != nil {
+		if feature.TXT != nil {
+			hostInfo.HardwareFeatures.TXT = &taModel.HardwareFeature{Enabled: feature.TXT.Enabled}
+		}
+		if feature.CBNT != nil {
+			hostInfo.HardwareFeatures.CBNT = &taModel.CBNT{Enabled: feature.CBNT.Enabled}
+			hostInfo.HardwareFeatures.CBNT.Meta.Profile = feature.CBNT.Profile
+		}
+		if feature.SUEFI != nil {
+			hostInfo.HardwareFeatures.SUEFI = &taModel.HardwareFeature{Enabled: feature.SUEFI.Enabled}
+		}
+		if feature.TPM != nil && feature.TPM.Version != "" && hostInfo.HardwareFeatures.TPM.Meta.TPMVersion == "" {
+			hostInfo.HardwareFeatures.TPM.Meta.TPMVersion = feature.TPM.Version
+		}
+	}
+
+	pcrManifest := &hostManifest.PcrManifest
+	for _, fwPcr := range fwManifest.Pcrs {
+		pcr := hcTypes.Pcr{
+			Index:   fwPcr.Index,
+			Value:   fwPcr.Value,
+			PcrBank: fwPcr.Bank,
+		}
+		eventLogEntry := hcTypes.EventLogEntry{
+			PcrIndex:  fwPcr.Index,
+			PcrBank:   fwPcr.Bank,
+			EventLogs: fwPcr.Events,
+		}
+		switch fwPcr.Bank {
+		case hcTypes.SHA1:
+			pcrManifest.Sha1Pcrs = append(pcrManifest.Sha1Pcrs, pcr)
+			if len(fwPcr.Events) > 0 {
+				pcrManifest.PcrEventLogMap.Sha1EventLogs = append(pcrManifest.PcrEventLogMap.Sha1EventLogs, eventLogEntry)
+			}
+		case hcTypes.SHA256:
+			pcrManifest.Sha256Pcrs = append(pcrManifest.Sha256Pcrs, pcr)
+			if len(fwPcr.Events) > 0 {
+				pcrManifest.PcrEventLogMap.Sha256EventLogs = append(pcrManifest.PcrEventLogMap.Sha256EventLogs, eventLogEntry)
+			}
+		}
+	}
+
+	return &hostManifest
+}
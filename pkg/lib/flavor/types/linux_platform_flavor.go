@@ -98,6 +98,8 @@ func (rhelpf LinuxPlatformFlavor) GetFlavorPartRaw(name cf.FlavorPart) ([]cm.Fla
 		return rhelpf.getHostUniqueFlavor()
 	case cf.FlavorPartSoftware:
 		return rhelpf.getDefaultSoftwareFlavor()
+	case cf.FlavorPartContainerImage:
+		return rhelpf.getContainerImageFlavor()
 	}
 	return nil, cf.UNKNOWN_FLAVOR_PART()
 }
@@ -124,6 +126,11 @@ func (rhelpf LinuxPlatformFlavor) GetFlavorPartNames() ([]cf.FlavorPart, error)
 	if rhelpf.TagCertificate != nil {
 		flavorPartList = append(flavorPartList, cf.FlavorPartAssetTag)
 	}
+
+	// The ContainerImage flavor part is present when the workload agent reported the images of its containers
+	if rhelpf.HostManifest != nil && len(rhelpf.HostManifest.ContainerImageMeasurements) > 0 {
+		flavorPartList = append(flavorPartList, cf.FlavorPartContainerImage)
+	}
 	return flavorPartList, nil
 }
 
@@ -411,6 +418,33 @@ func (rhelpf LinuxPlatformFlavor) getDefaultSoftwareFlavor() ([]cm.Flavor, error
 	return softwareFlavors, nil
 }
 
+// getContainerImageFlavor returns a flavor expecting the container images measured on the host by the workload agent
+func (rhelpf LinuxPlatformFlavor) getContainerImageFlavor() ([]cm.Flavor, error) {
+	log.Trace("flavor/types/linux_platform_flavor:getContainerImageFlavor() Entering")
+	defer log.Trace("flavor/types/linux_platform_flavor:getContainerImageFlavor() Leaving")
+
+	var errorMessage = "Error during creation of CONTAINER_IMAGE flavor"
+	if rhelpf.HostManifest == nil || len(rhelpf.HostManifest.ContainerImageMeasurements) == 0 {
+		return nil, errors.New(errorMessage + " No container image measurements in the host manifest")
+	}
+
+	newMeta, err := pfutil.GetMetaSectionDetails(rhelpf.HostInfo, rhelpf.TagCertificate, "", cf.FlavorPartContainerImage,
+		hcConstants.VendorIntel)
+	if err != nil {
+		return nil, errors.Wrap(err, errorMessage+" Failure in Meta section details")
+	}
+	log.Debugf("flavor/types/linux_platform_flavor:getContainerImageFlavor() New Meta Section: %v", *newMeta)
+
+	// Assemble the ContainerImage Flavor
+	containerImageFlavor := cm.NewFlavor(newMeta, nil, nil, nil, nil, nil)
+	containerImageFlavor.ContainerImages = append(containerImageFlavor.ContainerImages,
+		rhelpf.HostManifest.ContainerImageMeasurements...)
+
+	log.Debugf("flavor/types/linux_platform_flavor:getContainerImageFlavor() New PlatformFlavor: %v", containerImageFlavor)
+
+	return []cm.Flavor{*containerImageFlavor}, nil
+}
+
 // getDefaultMeasurement returns a default set of measurements for the Platform Flavor
 func (rhelpf LinuxPlatformFlavor) getDefaultMeasurement() ([]string, error) {
 	log.Trace("flavor/types/linux_platform_flavor:getDefaultMeasurement() Entering")
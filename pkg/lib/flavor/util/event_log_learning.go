@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	cm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+)
+
+// LearnedPcrDetails holds the pcr details learnt from the pcr manifests collected on a set of hosts
+type LearnedPcrDetails struct {
+	// Pcrs have the same value in all the manifests and hold the events measured in all of them
+	Pcrs map[crypt.DigestAlgorithm]map[hcTypes.PcrIndex]cm.PcrEx
+	// VolatilePcrs have different values across the manifests and cannot be added to a flavor
+	VolatilePcrs map[crypt.DigestAlgorithm][]hcTypes.PcrIndex
+	// VolatileEvents are the events that were not measured in all the manifests
+	VolatileEvents map[crypt.DigestAlgorithm]map[hcTypes.PcrIndex][]hcTypes.EventLog
+}
+
+type eventLogKey struct {
+	label string
+	value string
+}
+
+// LearnPcrDetails computes per pcr the intersection and the union of the event logs of the given pcr
+// manifests. Events measured in every manifest are kept in the learnt pcrs, the remaining events of
+// the union are reported as volatile so that they can be excluded from the flavor.
+func (pfutil PlatformFlavorUtil) LearnPcrDetails(pcrManifests []hcTypes.PcrManifest, pcrList []int) *LearnedPcrDetails {
+	log.Trace("flavor/util/event_log_learning:LearnPcrDetails() Entering")
+	defer log.Trace("flavor/util/event_log_learning:LearnPcrDetails() Leaving")
+
+	learned := LearnedPcrDetails{
+		Pcrs:           make(map[crypt.DigestAlgorithm]map[hcTypes.PcrIndex]cm.PcrEx),
+		VolatilePcrs:   make(map[crypt.DigestAlgorithm][]hcTypes.PcrIndex),
+		VolatileEvents: make(map[crypt.DigestAlgorithm]map[hcTypes.PcrIndex][]hcTypes.EventLog),
+	}
+	if len(pcrManifests) == 0 {
+		return &learned
+	}
+
+	var samples []map[crypt.DigestAlgorithm]map[hcTypes.PcrIndex]cm.PcrEx
+	for _, pcrManifest := range pcrManifests {
+		samples = append(samples, pfutil.GetPcrDetails(pcrManifest, pcrList, true))
+	}
+
+	for digestAlgorithm, pcrs := range samples[0] {
+		for _, pcrIndex := range pcrList {
+			pI := hcTypes.PcrIndex(pcrIndex)
+			firstPcr, ok := pcrs[pI]
+			if !ok {
+				continue
+			}
+
+			sameValue := true
+			var sampleEvents [][]hcTypes.EventLog
+			for _, sample := range samples {
+				pcr, ok := sample[digestAlgorithm][pI]
+				if !ok || pcr.Value != firstPcr.Value {
+					sameValue = false
+				}
+				sampleEvents = append(sampleEvents, pcr.Event)
+			}
+
+			stableEvents, volatileEvents := splitEventLogs(sampleEvents)
+			if sameValue {
+				if _, ok := learned.Pcrs[digestAlgorithm]; !ok {
+					learned.Pcrs[digestAlgorithm] = make(map[hcTypes.PcrIndex]cm.PcrEx)
+				}
+				learned.Pcrs[digestAlgorithm][pI] = *cm.NewPcrEx(firstPcr.Value, stableEvents)
+			} else {
+				learned.VolatilePcrs[digestAlgorithm] = append(learned.VolatilePcrs[digestAlgorithm], pI)
+			}
+			if len(volatileEvents) > 0 {
+				if _, ok := learned.VolatileEvents[digestAlgorithm]; !ok {
+					learned.VolatileEvents[digestAlgorithm] = make(map[hcTypes.PcrIndex][]hcTypes.EventLog)
+				}
+				learned.VolatileEvents[digestAlgorithm][pI] = volatileEvents
+			}
+		}
+	}
+	return &learned
+}
+
+// splitEventLogs returns the events present in all the event logs in the order of the first log, and the
+// events of the union that are missing from at least one of the logs
+func splitEventLogs(eventLogs [][]hcTypes.EventLog) ([]hcTypes.EventLog, []hcTypes.EventLog) {
+	minCount := make(map[eventLogKey]int)
+	maxCount := make(map[eventLogKey]int)
+	for i, eventLog := range eventLogs {
+		counts := make(map[eventLogKey]int)
+		for _, event := range eventLog {
+			counts[eventLogKey{event.Label, event.Value}]++
+		}
+		for key, count := range counts {
+			if count > maxCount[key] {
+				maxCount[key] = count
+			}
+			if i == 0 {
+				minCount[key] = count
+			} else if count < minCount[key] {
+				minCount[key] = count
+			}
+		}
+		// events missing from this log are not measured everywhere
+		for key := range minCount {
+			if _, ok := counts[key]; !ok {
+				minCount[key] = 0
+			}
+		}
+	}
+
+	var stableEvents []hcTypes.EventLog
+	used := make(map[eventLogKey]int)
+	for _, event := range eventLogs[0] {
+		key := eventLogKey{event.Label, event.Value}
+		if used[key] < minCount[key] {
+			stableEvents = append(stableEvents, event)
+			used[key]++
+		}
+	}
+
+	var volatileEvents []hcTypes.EventLog
+	reported := make(map[eventLogKey]bool)
+	for _, eventLog := range eventLogs {
+		for _, event := range eventLog {
+			key := eventLogKey{event.Label, event.Value}
+			if !reported[key] && minCount[key] < maxCount[key] {
+				volatileEvents = append(volatileEvents, event)
+				reported[key] = true
+			}
+		}
+	}
+	return stableEvents, volatileEvents
+}
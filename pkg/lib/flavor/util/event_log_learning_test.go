@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLearningPcrManifest(pcr17, pcr18 string, pcr17Events []hcTypes.EventLog) hcTypes.PcrManifest {
+	return hcTypes.PcrManifest{
+		Sha256Pcrs: []hcTypes.Pcr{
+			{Index: hcTypes.PCR17, Value: pcr17, PcrBank: hcTypes.SHA256},
+			{Index: hcTypes.PCR18, Value: pcr18, PcrBank: hcTypes.SHA256},
+		},
+		PcrEventLogMap: hcTypes.PcrEventLogMap{
+			Sha256EventLogs: []hcTypes.EventLogEntry{
+				{PcrIndex: hcTypes.PCR17, PcrBank: hcTypes.SHA256, EventLogs: pcr17Events},
+			},
+		},
+	}
+}
+
+func TestLearnPcrDetails(t *testing.T) {
+	var pfutil PlatformFlavorUtil
+	kernel := hcTypes.EventLog{Label: "vmlinuz", Value: "aaaa"}
+	initrd := hcTypes.EventLog{Label: "initrd", Value: "bbbb"}
+	nonce := hcTypes.EventLog{Label: "LCP_DETAILS_HASH", Value: "cccc"}
+	otherNonce := hcTypes.EventLog{Label: "LCP_DETAILS_HASH", Value: "dddd"}
+
+	learned := pfutil.LearnPcrDetails([]hcTypes.PcrManifest{
+		newLearningPcrManifest("17a", "18a", []hcTypes.EventLog{kernel, nonce, initrd}),
+		newLearningPcrManifest("17b", "18a", []hcTypes.EventLog{kernel, initrd, otherNonce}),
+		newLearningPcrManifest("17c", "18a", []hcTypes.EventLog{kernel, initrd}),
+	}, []int{17, 18})
+
+	// pcr 18 is stable, pcr 17 changes because of the nonce
+	assert.Equal(t, "18a", learned.Pcrs[crypt.SHA256()][hcTypes.PCR18].Value)
+	_, ok := learned.Pcrs[crypt.SHA256()][hcTypes.PCR17]
+	assert.False(t, ok)
+	assert.Equal(t, []hcTypes.PcrIndex{hcTypes.PCR17}, learned.VolatilePcrs[crypt.SHA256()])
+
+	volatileEvents := learned.VolatileEvents[crypt.SHA256()][hcTypes.PCR17]
+	assert.Len(t, volatileEvents, 2)
+	assert.Equal(t, "cccc", volatileEvents[0].Value)
+	assert.Equal(t, "dddd", volatileEvents[1].Value)
+	_, ok = learned.VolatileEvents[crypt.SHA256()][hcTypes.PCR18]
+	assert.False(t, ok)
+}
+
+func TestLearnPcrDetailsStableEvents(t *testing.T) {
+	var pfutil PlatformFlavorUtil
+	kernel := hcTypes.EventLog{Label: "vmlinuz", Value: "aaaa"}
+	initrd := hcTypes.EventLog{Label: "initrd", Value: "bbbb"}
+
+	learned := pfutil.LearnPcrDetails([]hcTypes.PcrManifest{
+		newLearningPcrManifest("17a", "18a", []hcTypes.EventLog{kernel, initrd}),
+		newLearningPcrManifest("17a", "18a", []hcTypes.EventLog{kernel, initrd}),
+	}, []int{17})
+
+	pcr17 := learned.Pcrs[crypt.SHA256()][hcTypes.PCR17]
+	assert.Equal(t, "17a", pcr17.Value)
+	assert.Len(t, pcr17.Event, 2)
+	assert.Equal(t, "vmlinuz", pcr17.Event[0].Label)
+	assert.Equal(t, "initrd", pcr17.Event[1].Label)
+	assert.Empty(t, learned.VolatilePcrs)
+	assert.Empty(t, learned.VolatileEvents)
+	_, ok := learned.Pcrs[crypt.SHA256()][hcTypes.PCR18]
+	assert.False(t, ok)
+
+	assert.Empty(t, pfutil.LearnPcrDetails(nil, []int{17}).Pcrs)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	cm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// FlavorPruner selects the superseded versions of the auto-updated flavor families that can be retired
+type FlavorPruner struct {
+	Policy hvs.FlavorPrunePolicy
+}
+
+// GetFlavorFamily returns the family of a flavor imported from a host, the flavors re-imported from the host as its
+// bios, os or vmm is updated. Delta flavors, the flavors that were not imported from a host and the flavor parts
+// other than PLATFORM and OS are not part of a family, an empty family is returned for them.
+func (pruner FlavorPruner) GetFlavorFamily(flavor *cm.Flavor) string {
+	description := flavor.Meta.Description
+	if flavor.IsDelta() || description.Source == "" {
+		return ""
+	}
+
+	var name string
+	switch description.FlavorPart {
+	case common.FlavorPartPlatform.String():
+		name = description.BiosName
+	case common.FlavorPartOs.String():
+		name = description.OsName + "_" + description.VmmName
+	default:
+		return ""
+	}
+	return strings.Join([]string{description.FlavorPart, flavor.Meta.Vendor.String(), description.Source, name,
+		description.TpmVersion}, "_")
+}
+
+// SelectSuperseded returns the versions of the flavor families that are retired as per the policy, and the
+// superseded versions that are kept because they are in use. The versions of a family are ordered by their creation
+// time, the versions that are not part of a family are never retired.
+func (pruner FlavorPruner) SelectSuperseded(versions []hvs.FlavorVersion, inUse map[uuid.UUID]bool, now time.Time) ([]hvs.FlavorVersion, []hvs.FlavorVersion) {
+	log.Trace("flavor/util/flavor_pruning:SelectSuperseded() Entering")
+	defer log.Trace("flavor/util/flavor_pruning:SelectSuperseded() Leaving")
+
+	flavorParts := make(map[string]bool)
+	for _, flavorPart := range pruner.Policy.FlavorParts {
+		flavorParts[flavorPart] = true
+	}
+
+	families := make(map[string][]hvs.FlavorVersion)
+	var familyNames []string
+	for _, version := range versions {
+		if version.Family == "" || (len(flavorParts) != 0 && !flavorParts[version.FlavorPart]) {
+			continue
+		}
+		if _, ok := families[version.Family]; !ok {
+			familyNames = append(familyNames, version.Family)
+		}
+		families[version.Family] = append(families[version.Family], version)
+	}
+	sort.Strings(familyNames)
+
+	keepVersions := pruner.Policy.KeepVersions
+	if keepVersions < 1 {
+		keepVersions = 1
+	}
+	minAge := time.Duration(pruner.Policy.MinAgeDays) * 24 * time.Hour
+
+	var retired, kept []hvs.FlavorVersion
+	for _, familyName := range familyNames {
+		family := families[familyName]
+		// newest first
+		sort.SliceStable(family, func(i, j int) bool {
+			return family[i].Created.After(family[j].Created)
+		})
+		if len(family) <= keepVersions {
+			continue
+		}
+		for _, version := range family[keepVersions:] {
+			if now.Sub(version.Created) < minAge {
+				continue
+			}
+			if inUse[version.FlavorID] {
+				log.Debugf("flavor/util/flavor_pruning:SelectSuperseded() Superseded flavor %s is in use", version.FlavorID)
+				kept = append(kept, version)
+				continue
+			}
+			retired = append(retired, version)
+		}
+	}
+	return retired, kept
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	cm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	hcConstants "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFlavorFamily(t *testing.T) {
+	var pruner FlavorPruner
+
+	newFlavor := func(description cm.Description) *cm.Flavor {
+		return &cm.Flavor{Meta: cm.Meta{Vendor: hcConstants.VendorIntel, Description: description}}
+	}
+
+	bios := newFlavor(cm.Description{FlavorPart: "PLATFORM", Source: "golden-1", BiosName: "Intel Corporation",
+		BiosVersion: "SE5C620.86B.00.01.0014", TpmVersion: "2.0"})
+	updatedBios := newFlavor(cm.Description{FlavorPart: "PLATFORM", Source: "golden-1", BiosName: "Intel Corporation",
+		BiosVersion: "SE5C620.86B.00.01.0015", TpmVersion: "2.0"})
+	otherHost := newFlavor(cm.Description{FlavorPart: "PLATFORM", Source: "golden-2", BiosName: "Intel Corporation",
+		BiosVersion: "SE5C620.86B.00.01.0015", TpmVersion: "2.0"})
+	assert.NotEmpty(t, pruner.GetFlavorFamily(bios))
+	assert.Equal(t, pruner.GetFlavorFamily(bios), pruner.GetFlavorFamily(updatedBios))
+	assert.NotEqual(t, pruner.GetFlavorFamily(bios), pruner.GetFlavorFamily(otherHost))
+
+	// the flavors that were not imported from a host and the host unique flavors are not part of a family
+	assert.Empty(t, pruner.GetFlavorFamily(newFlavor(cm.Description{FlavorPart: "OS", OsName: "RedHatEnterprise"})))
+	assert.Empty(t, pruner.GetFlavorFamily(newFlavor(cm.Description{FlavorPart: "HOST_UNIQUE", Source: "golden-1"})))
+}
+
+func TestSelectSuperseded(t *testing.T) {
+	now := time.Now()
+	newVersion := func(family, flavorPart string, age time.Duration) hvs.FlavorVersion {
+		return hvs.FlavorVersion{
+			FlavorID:   uuid.New(),
+			FlavorPart: flavorPart,
+			Family:     family,
+			Created:    now.Add(-age),
+		}
+	}
+	day := 24 * time.Hour
+	platform := []hvs.FlavorVersion{
+		newVersion("platform", "PLATFORM", 40*day),
+		newVersion("platform", "PLATFORM", 30*day),
+		newVersion("platform", "PLATFORM", 20*day),
+		newVersion("platform", "PLATFORM", 2*day),
+		newVersion("platform", "PLATFORM", day),
+	}
+	osVersions := []hvs.FlavorVersion{
+		newVersion("os", "OS", 40*day),
+		newVersion("os", "OS", day),
+	}
+	manual := newVersion("", "PLATFORM", 50*day)
+	versions := append(append([]hvs.FlavorVersion{manual}, osVersions...), platform...)
+
+	// the oldest platform version is still referenced by a host's report
+	inUse := map[uuid.UUID]bool{platform[0].FlavorID: true}
+
+	pruner := FlavorPruner{Policy: hvs.FlavorPrunePolicy{KeepVersions: 2, MinAgeDays: 25}}
+	retired, kept := pruner.SelectSuperseded(versions, inUse, now)
+	assert.Equal(t, []hvs.FlavorVersion{platform[1]}, retired)
+	assert.Equal(t, []hvs.FlavorVersion{platform[0]}, kept)
+
+	pruner = FlavorPruner{Policy: hvs.FlavorPrunePolicy{KeepVersions: 1, FlavorParts: []string{"OS"}}}
+	retired, kept = pruner.SelectSuperseded(versions, inUse, now)
+	assert.Equal(t, []hvs.FlavorVersion{osVersions[0]}, retired)
+	assert.Empty(t, kept)
+
+	// at least the newest version of every family is kept
+	pruner = FlavorPruner{Policy: hvs.FlavorPrunePolicy{}}
+	retired, _ = pruner.SelectSuperseded(versions, nil, now)
+	assert.Equal(t, 5, len(retired))
+	for _, version := range retired {
+		assert.NotEqual(t, platform[4].FlavorID, version.FlavorID)
+		assert.NotEqual(t, osVersions[1].FlavorID, version.FlavorID)
+		assert.NotEqual(t, manual.FlavorID, version.FlavorID)
+	}
+}
@@ -162,6 +162,14 @@ func (pfutil PlatformFlavorUtil) GetMetaSectionDetails(hostDetails *taModel.Host
 		description.OsVersion = osVersion
 		description.FlavorPart = flavorPartName.String()
 		description.Label = pfutil.getLabelFromDetails(meta.Vendor.String(), (*description.HardwareUUID).String(), pfutil.getCurrentTimeStamp())
+	case common.FlavorPartContainerImage:
+		description.FlavorPart = flavorPartName.String()
+		labelDetails := []string{meta.Vendor.String(), "ContainerImages"}
+		if hostDetails != nil && hostDetails.HostName != "" {
+			description.Source = strings.TrimSpace(hostDetails.HostName)
+			labelDetails = append(labelDetails, description.Source)
+		}
+		description.Label = pfutil.getLabelFromDetails(append(labelDetails, pfutil.getCurrentTimeStamp())...)
 	default:
 		return nil, errors.Errorf("Invalid FlavorPart %s", flavorPartName.String())
 	}
@@ -246,6 +254,11 @@ func (pfutil PlatformFlavorUtil) GetHardwareSectionDetails(hostManifest *hcTypes
 	}
 
 	hardware.Feature = &feature
+
+	// the firmware versions are only known for the hosts whose platform inventory is read from their BMC
+	if hostInfo.PlatformInventory != nil {
+		hardware.Firmware = hostInfo.PlatformInventory.Firmware
+	}
 	return &hardware
 }
 
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package host_connector
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
+	"github.com/pkg/errors"
+)
+
+// connectorRegistry maps the connection string schemes added by the connector plugins to their factories
+var connectorRegistry = struct {
+	sync.RWMutex
+	factories map[string]VendorHostConnectorFactory
+}{factories: map[string]VendorHostConnectorFactory{}}
+
+// RegisterConnector adds a connector implementation for the connection strings with the URL scheme, such as redfish
+// for the redfish://bmc.ip.com;u=admin;p=password connection strings, without changing the factory. The connection
+// strings of the scheme can have a vendor prefix, they are for intel hosts otherwise. The connectors of the scheme
+// are created by factory with the URL and the credentials of the connection string. Connector plugins usually
+// register from the init function of their package, so that importing the package is enough to enable them:
+//
+//	func init() {
+//		host_connector.MustRegisterConnector("redfish", &RedfishConnectorFactory{})
+//	}
+func RegisterConnector(scheme string, factory VendorHostConnectorFactory) error {
+	log.Trace("host_connector/connector_registry:RegisterConnector() Entering")
+	defer log.Trace("host_connector/connector_registry:RegisterConnector() Leaving")
+
+	if factory == nil {
+		return errors.New("host_connector/connector_registry:RegisterConnector() The connector factory is not provided")
+	}
+	connectorRegistry.Lock()
+	defer connectorRegistry.Unlock()
+	if err := util.RegisterScheme(scheme); err != nil {
+		return errors.Wrap(err, "host_connector/connector_registry:RegisterConnector() Could not register the connector")
+	}
+	connectorRegistry.factories[strings.ToLower(scheme)] = factory
+	log.Infof("host_connector/connector_registry:RegisterConnector() Registered the connector of the %s connection strings", scheme)
+	return nil
+}
+
+// MustRegisterConnector is RegisterConnector for the init functions of the connector plugins, it panics when the
+// connector cannot be registered
+func MustRegisterConnector(scheme string, factory VendorHostConnectorFactory) {
+	if err := RegisterConnector(scheme, factory); err != nil {
+		panic(err)
+	}
+}
+
+// getRegisteredConnectorFactory returns the factory registered for the URL scheme of the connector URL
+func getRegisteredConnectorFactory(vendorURL string) (VendorHostConnectorFactory, bool) {
+	connectorRegistry.RLock()
+	defer connectorRegistry.RUnlock()
+	factory, ok := connectorRegistry.factories[util.GetURLScheme(vendorURL)]
+	return factory, ok
+}
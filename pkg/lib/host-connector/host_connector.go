@@ -5,16 +5,88 @@
 package host_connector
 
 import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/redfish"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/vmware/govmomi/vim25/mo"
 )
 
+// HostConnector retrieves the data of the hosts, the calls to the hosts are canceled when their context is done or
+// when the call timeout of the connector expires
 type HostConnector interface {
-	GetHostDetails() (taModel.HostInfo, error)
-	GetHostManifest(pcrList []int) (types.HostManifest, error)
-	DeployAssetTag(string, string) error
-	DeploySoftwareManifest(taModel.Manifest) error
-	GetMeasurementFromManifest(taModel.Manifest) (taModel.Measurement, error)
-	GetClusterReference(string) ([]mo.HostSystem, error)
+	GetHostDetails(ctx context.Context) (taModel.HostInfo, error)
+	GetHostManifest(ctx context.Context, pcrList []int) (types.HostManifest, error)
+	DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error
+	DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error
+	DeploySoftwareManifests(ctx context.Context, manifests []taModel.Manifest) []error
+	GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error)
+	GetClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error)
+	// Capabilities returns the attestation features supported by the host
+	Capabilities(ctx context.Context) (taModel.HostCapabilities, error)
+}
+
+// QuoteNonceBinder is implemented by the connectors requesting the quotes with a nonce chosen by HVS
+type QuoteNonceBinder interface {
+	// BindQuoteNonce binds the nonces of the quote requests of the connector to the host record
+	BindQuoteNonce(hostId string)
+}
+
+// PCRBankSelector is implemented by the connectors that can restrict the PCR banks of the quotes they request
+type PCRBankSelector interface {
+	// SelectPCRBanks restricts the quotes of the connector to the PCR banks supported by the host
+	SelectPCRBanks(capabilities *taModel.HostCapabilities)
+}
+
+// AttestationBundleProvider is implemented by the connectors that can collect the host manifest in a single request
+// to the host, with a quote covering the host info and the logs of the manifest
+type AttestationBundleProvider interface {
+	// GetAttestationBundle collects the host manifest from an attestation bundle of the host
+	GetAttestationBundle(ctx context.Context, pcrList []int) (types.HostManifest, error)
+}
+
+// PlatformInventoryReader is implemented by the connectors that can add the platform inventory read out-of-band from
+// the BMC of the host to the host info they report
+type PlatformInventoryReader interface {
+	// SetPlatformInventoryClient makes the connector read the platform inventory of the host with redfishClient
+	SetPlatformInventoryClient(redfishClient redfish.RedfishClient)
+}
+
+// withCallTimeout derives the context of a connector call, bounded by the call timeout of the connector unless it is zero
+func withCallTimeout(ctx context.Context, callTimeout time.Duration) (context.Context, context.CancelFunc) {
+	if callTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, callTimeout)
+}
+
+// capabilitiesFromHostInfo derives the capabilities of the hosts that do not report them from their host info
+func capabilitiesFromHostInfo(hostInfo taModel.HostInfo) taModel.HostCapabilities {
+	tpm := hostInfo.HardwareFeatures.TPM
+	capabilities := taModel.HostCapabilities{
+		TPMVersion:        strings.TrimSpace(tpm.Meta.TPMVersion),
+		MeasurementAgents: hostInfo.InstalledComponents,
+	}
+
+	if tpm.Meta.PCRBanks != "" {
+		capabilities.PCRBanks = strings.Split(tpm.Meta.PCRBanks, "_")
+	} else if capabilities.TPMVersion == constants.TPMVersion2 {
+		capabilities.PCRBanks = []string{"SHA1", "SHA256"}
+	} else if capabilities.TPMVersion != "" {
+		capabilities.PCRBanks = []string{"SHA1"}
+	}
+
+	for _, flag := range strings.Fields(hostInfo.ProcessorFlags) {
+		switch strings.ToUpper(flag) {
+		case "SGX":
+			capabilities.SGX = true
+		case "TDX", "TDX_GUEST":
+			capabilities.TDX = true
+		}
+	}
+	return capabilities
 }
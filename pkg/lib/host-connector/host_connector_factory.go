@@ -6,10 +6,16 @@ package host_connector
 
 import (
 	"crypto/x509"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/redfish"
+	client "github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
 	"github.com/pkg/errors"
+	"net/url"
+	"time"
 )
 
 var log = commLog.GetDefaultLogger()
@@ -23,10 +29,60 @@ type HostConnectorProvider interface {
 type HostConnectorFactory struct {
 	aasApiUrl      string
 	trustedCaCerts []x509.Certificate
+	quoteCallbacks *QuoteCallbacks
+	requestAuth    *client.RequestAuth
+	quoteRequester string
+	sshConfig      client.SshConfig
+	callTimeout    time.Duration
+	bmcUsername    string
+	bmcPassword    string
+	proxies        map[constants.Vendor]clients.ProxyFunc
 }
 
 func NewHostConnectorFactory(aasApiUrl string, trustedCaCerts []x509.Certificate) *HostConnectorFactory {
-	return &HostConnectorFactory{aasApiUrl, trustedCaCerts}
+	return &HostConnectorFactory{aasApiUrl: aasApiUrl, trustedCaCerts: trustedCaCerts}
+}
+
+// SetQuoteCallbacks makes the intel connectors created by the factory collect the quotes asynchronously
+func (htcFactory *HostConnectorFactory) SetQuoteCallbacks(quoteCallbacks *QuoteCallbacks) {
+	htcFactory.quoteCallbacks = quoteCallbacks
+}
+
+// SetRequestAuth makes the intel connectors created by the factory send their requests to the trust agents with the
+// custom headers and auth plugin of requestAuth
+func (htcFactory *HostConnectorFactory) SetRequestAuth(requestAuth *client.RequestAuth) {
+	htcFactory.requestAuth = requestAuth
+}
+
+// SetQuoteRequester makes the intel connectors created by the factory bind the quote nonces to the identity of the
+// requesting verifier and to the host record set with BindQuoteNonce
+func (htcFactory *HostConnectorFactory) SetQuoteRequester(quoteRequester string) {
+	htcFactory.quoteRequester = quoteRequester
+}
+
+// SetSshConfig configures the connectors created by the factory for the ssh:// connection strings
+func (htcFactory *HostConnectorFactory) SetSshConfig(sshConfig client.SshConfig) {
+	htcFactory.sshConfig = sshConfig
+}
+
+// SetCallTimeout bounds every call of the connectors created by the factory to the hosts, zero leaves the calls
+// bounded by their context only
+func (htcFactory *HostConnectorFactory) SetCallTimeout(callTimeout time.Duration) {
+	htcFactory.callTimeout = callTimeout
+}
+
+// SetBmcCredentials sets the user the platform inventory is read with from the BMCs of the hosts that have a
+// bmc=https://bmc.ip.com option in their connection string
+func (htcFactory *HostConnectorFactory) SetBmcCredentials(username, password string) {
+	htcFactory.bmcUsername = username
+	htcFactory.bmcPassword = password
+}
+
+// SetProxies makes the connectors created by the factory reach the trust agents and vCenter through the proxy
+// selected for the vendor of the host, the hosts of the vendors without a proxy and the hosts reached over ssh are
+// reached directly
+func (htcFactory *HostConnectorFactory) SetProxies(proxies map[constants.Vendor]clients.ProxyFunc) {
+	htcFactory.proxies = proxies
 }
 
 func (htcFactory *HostConnectorFactory) NewHostConnector(connectionString string) (HostConnector, error) {
@@ -39,15 +95,64 @@ func (htcFactory *HostConnectorFactory) NewHostConnector(connectionString string
 		return nil, errors.Wrap(err, "host_connector/host_connector_factory:NewHostConnector() Error getting connector details")
 	}
 
+	if registeredFactory, ok := getRegisteredConnectorFactory(vendorConnector.Url); ok {
+		log.Debugf("host_connector/host_connector_factory:NewHostConnector() Connector type for provided connection string is %s",
+			util.GetURLScheme(vendorConnector.Url))
+		hostConnector, err := registeredFactory.GetHostConnector(vendorConnector, htcFactory.aasApiUrl, htcFactory.trustedCaCerts)
+		if err != nil {
+			return nil, err
+		}
+		return htcFactory.setPlatformInventoryClient(hostConnector, vendorConnector)
+	}
+
 	switch vendorConnector.Vendor {
 	case constants.VendorIntel, constants.VendorMicrosoft:
+		if util.IsSshURL(vendorConnector.Url) {
+			log.Debug("host_connector/host_connector_factory:NewHostConnector() Connector type for provided connection string is SSH")
+			connectorFactory = &SshConnectorFactory{sshConfig: htcFactory.sshConfig, quoteRequester: htcFactory.quoteRequester,
+				callTimeout: htcFactory.callTimeout}
+			break
+		}
 		log.Debug("host_connector/host_connector_factory:NewHostConnector() Connector type for provided connection string is INTEL")
-		connectorFactory = &IntelConnectorFactory{}
+		connectorFactory = &IntelConnectorFactory{quoteCallbacks: htcFactory.quoteCallbacks, requestAuth: htcFactory.requestAuth,
+			quoteRequester: htcFactory.quoteRequester, callTimeout: htcFactory.callTimeout,
+			proxy: htcFactory.proxies[vendorConnector.Vendor]}
 	case constants.VendorVMware:
 		log.Debug("host_connector/host_connector_factory:NewHostConnector() Connector type for provided connection string is VMWARE")
-		connectorFactory = &VmwareConnectorFactory{}
+		connectorFactory = &VmwareConnectorFactory{callTimeout: htcFactory.callTimeout,
+			proxy: htcFactory.proxies[vendorConnector.Vendor]}
 	default:
 		return nil, errors.New("host_connector_factory:NewHostConnector() Vendor not supported yet: " + vendorConnector.Vendor.String())
 	}
-	return connectorFactory.GetHostConnector(vendorConnector, htcFactory.aasApiUrl, htcFactory.trustedCaCerts)
+	hostConnector, err := connectorFactory.GetHostConnector(vendorConnector, htcFactory.aasApiUrl, htcFactory.trustedCaCerts)
+	if err != nil {
+		return nil, err
+	}
+	return htcFactory.setPlatformInventoryClient(hostConnector, vendorConnector)
+}
+
+// setPlatformInventoryClient makes the connector of a host with a BMC URL in its connection string add the platform
+// inventory read from the Redfish service of the BMC to the host info
+func (htcFactory *HostConnectorFactory) setPlatformInventoryClient(hostConnector HostConnector,
+	vendorConnector types.VendorConnector) (HostConnector, error) {
+	if vendorConnector.Configuration.BmcUrl == "" {
+		return hostConnector, nil
+	}
+
+	inventoryReader, ok := hostConnector.(PlatformInventoryReader)
+	if !ok {
+		return nil, errors.New("host_connector_factory:NewHostConnector() The connector of the " +
+			vendorConnector.Vendor.String() + " hosts does not support the BMC platform inventory")
+	}
+	bmcURL, err := url.Parse(vendorConnector.Configuration.BmcUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "host_connector_factory:NewHostConnector() Error parsing the BMC URL")
+	}
+	redfishClient, err := redfish.NewRedfishClient(bmcURL, htcFactory.bmcUsername, htcFactory.bmcPassword,
+		htcFactory.trustedCaCerts)
+	if err != nil {
+		return nil, errors.Wrap(err, "host_connector_factory:NewHostConnector() Could not create Redfish client")
+	}
+	inventoryReader.SetPlatformInventoryClient(redfishClient)
+	return hostConnector, nil
 }
@@ -7,8 +7,16 @@ package host_connector
 
 import (
 	"crypto/x509"
-	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	client "github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewHostConnector(t *testing.T) {
@@ -32,3 +40,83 @@ func TestNewHostConnector(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, hostConnector, nil)
 }
+
+func TestNewSshHostConnector(t *testing.T) {
+
+	tempDir, err := ioutil.TempDir("", "host-connector-ssh")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	knownHostsFile := filepath.Join(tempDir, "known_hosts")
+	assert.NoError(t, ioutil.WriteFile(knownHostsFile, []byte{}, 0600))
+
+	htcFactory := NewHostConnectorFactory("https://aas.url.com:8444/aas", nil)
+	htcFactory.SetSshConfig(client.SshConfig{KnownHostsFile: knownHostsFile})
+
+	hostConnector, err := htcFactory.NewHostConnector("ssh://ta.ip.com:22;u=admin;p=password")
+	assert.NoError(t, err)
+	intelConnector, ok := hostConnector.(*IntelConnector)
+	assert.True(t, ok)
+	assert.Equal(t, "ssh", intelConnector.client.GetBaseURL().Scheme)
+
+	// the connections to the hosts are refused when their keys cannot be checked
+	htcFactory.SetSshConfig(client.SshConfig{})
+	_, err = htcFactory.NewHostConnector("intel:ssh://ta.ip.com;u=admin;p=password")
+	assert.Error(t, err)
+}
+
+func TestNewHostConnectorWithBmc(t *testing.T) {
+
+	htcFactory := NewHostConnectorFactory("https://aas.url.com:8444/aas", nil)
+	htcFactory.SetBmcCredentials("root", "calvin")
+
+	hostConnector, err := htcFactory.NewHostConnector("intel:https://ta.ip.com:1443;bmc=https://bmc.ip.com;u=admin;p=password")
+	assert.NoError(t, err)
+	intelConnector, ok := hostConnector.(*IntelConnector)
+	assert.True(t, ok)
+	assert.NotNil(t, intelConnector.redfishClient)
+	assert.Equal(t, "https://ta.ip.com:1443/v2", intelConnector.client.GetBaseURL().String())
+
+	// the platform inventory is only read by the connectors supporting it
+	_, err = htcFactory.NewHostConnector("vmware:https://vsphere.com:443/sdk;bmc=https://bmc.ip.com;h=hostName;u=admin;p=password")
+	assert.Error(t, err)
+}
+
+type fakeConnectorFactory struct {
+	vendorConnector types.VendorConnector
+}
+
+func (fcf *fakeConnectorFactory) GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string,
+	trustedCaCerts []x509.Certificate) (HostConnector, error) {
+	fcf.vendorConnector = vendorConnector
+	return &IntelConnector{}, nil
+}
+
+func TestRegisterConnector(t *testing.T) {
+
+	htcFactory := NewHostConnectorFactory("https://aas.url.com:8444/aas", nil)
+	_, err := htcFactory.NewHostConnector("redfish://bmc.ip.com:443;u=admin;p=password")
+	assert.Error(t, err)
+
+	redfishFactory := &fakeConnectorFactory{}
+	assert.NoError(t, RegisterConnector("Redfish", redfishFactory))
+	hostConnector, err := htcFactory.NewHostConnector("redfish://bmc.ip.com:443;u=admin;p=password")
+	assert.NoError(t, err)
+	assert.NotNil(t, hostConnector)
+	assert.Equal(t, "redfish://bmc.ip.com:443", redfishFactory.vendorConnector.Url)
+	assert.Equal(t, "admin", redfishFactory.vendorConnector.Configuration.Username)
+	assert.Equal(t, constants.VendorIntel, redfishFactory.vendorConnector.Vendor)
+
+	// the connection strings of the registered schemes are valid for the hosts with a vendor prefix
+	vendorConnector, err := util.GetConnectorDetails("microsoft:redfish://bmc.ip.com;u=admin;p=password")
+	assert.NoError(t, err)
+	assert.Equal(t, constants.VendorMicrosoft, vendorConnector.Vendor)
+	_, err = util.GetConnectorDetails("redfish:// bmc.ip.com;u=admin;p=password")
+	assert.Error(t, err)
+
+	// a scheme is registered once and the built-in schemes cannot be replaced
+	assert.Error(t, RegisterConnector("redfish", &fakeConnectorFactory{}))
+	assert.Error(t, RegisterConnector("https", &fakeConnectorFactory{}))
+	assert.Error(t, RegisterConnector("ipmi://", &fakeConnectorFactory{}))
+	assert.Error(t, RegisterConnector("ipmi", nil))
+	assert.Panics(t, func() { MustRegisterConnector("redfish", &fakeConnectorFactory{}) })
+}
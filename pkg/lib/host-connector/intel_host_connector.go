@@ -5,42 +5,103 @@
 package host_connector
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/redfish"
 	client "github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/vim25/mo"
+	"time"
 )
 
+// defaultPCRBanks are the PCR banks requested from the trust agents
+var defaultPCRBanks = []string{"SHA1", "SHA256"}
+
 type IntelConnector struct {
 	client client.TAClient
+	// quoteCallbacks is set when the quotes are collected asynchronously
+	quoteCallbacks *QuoteCallbacks
+	// quoteRequester and hostId are set when the quote nonces are bound to the verifier and the host record
+	quoteRequester string
+	hostId         string
+	// pcrBanks are the PCR banks of the quotes, all the banks HVS verifies are requested when it is empty
+	pcrBanks []string
+	// callTimeout bounds every call of the connector, zero leaves the calls bounded by their context only
+	callTimeout time.Duration
+	// redfishClient is set when the platform inventory of the host is read from its BMC
+	redfishClient redfish.RedfishClient
+}
+
+// BindQuoteNonce binds the nonces of the quotes requested by the connector to the host record, when the factory
+// of the connector has a quote requester
+func (ic *IntelConnector) BindQuoteNonce(hostId string) {
+	ic.hostId = hostId
+}
+
+// SelectPCRBanks restricts the quotes requested by the connector to the PCR banks supported by the host
+func (ic *IntelConnector) SelectPCRBanks(capabilities *taModel.HostCapabilities) {
+	ic.pcrBanks = nil
+	for _, bank := range defaultPCRBanks {
+		if capabilities.SupportsPCRBank(bank) {
+			ic.pcrBanks = append(ic.pcrBanks, bank)
+		}
+	}
 }
 
-func (ic *IntelConnector) GetHostDetails() (taModel.HostInfo, error) {
+// SetPlatformInventoryClient makes the connector add the platform inventory read from the BMC of the host to the host
+// info it reports
+func (ic *IntelConnector) SetPlatformInventoryClient(redfishClient redfish.RedfishClient) {
+	ic.redfishClient = redfishClient
+}
+
+func (ic *IntelConnector) GetHostDetails(ctx context.Context) (taModel.HostInfo, error) {
 
 	log.Trace("intel_host_connector:GetHostDetails() Entering")
 	defer log.Trace("intel_host_connector:GetHostDetails() Leaving")
-	hostInfo, err := ic.client.GetHostInfo()
-	return hostInfo, err
+	ctx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+	hostInfo, err := ic.client.GetHostInfo(ctx)
+	if err != nil {
+		return hostInfo, err
+	}
+	ic.addPlatformInventory(ctx, &hostInfo)
+	return hostInfo, nil
+}
+
+// addPlatformInventory adds the platform inventory read from the BMC of the host to the host info. The host info is
+// reported without it when the BMC cannot be reached, the rules verifying the platform inventory then fail.
+func (ic *IntelConnector) addPlatformInventory(ctx context.Context, hostInfo *taModel.HostInfo) {
+	if ic.redfishClient == nil {
+		return
+	}
+	platformInventory, err := ic.redfishClient.GetPlatformInventory(ctx)
+	if err != nil {
+		log.WithError(err).Warn("intel_host_connector:addPlatformInventory() Error reading the platform inventory " +
+			"from the BMC of the host")
+		return
+	}
+	hostInfo.PlatformInventory = platformInventory
 }
 
-func (ic *IntelConnector) GetHostManifest(pcrList []int) (types.HostManifest, error) {
+func (ic *IntelConnector) GetHostManifest(ctx context.Context, pcrList []int) (types.HostManifest, error) {
 	log.Trace("intel_host_connector:GetHostManifest() Entering")
 	defer log.Trace("intel_host_connector:GetHostManifest() Leaving")
 
-	nonce, err := util.GenerateNonce(20)
+	nonce, err := ic.generateQuoteNonce()
 	if err != nil {
 		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifest() Error generating "+
 			"nonce for TPM quote request")
 	}
 
-	hostManifest, err := ic.GetHostManifestAcceptNonce(nonce, pcrList)
+	hostManifest, err := ic.GetHostManifestAcceptNonce(ctx, nonce, pcrList)
 	if err != nil {
 		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifest() Error creating "+
 			"host manifest")
@@ -48,100 +109,247 @@ func (ic *IntelConnector) GetHostManifest(pcrList []int) (types.HostManifest, er
 	return hostManifest, nil
 }
 
-//Separate function has been created that accepts nonce to support unit test.
-//Else it would be difficult to mock random nonce.
-func (ic *IntelConnector) GetHostManifestAcceptNonce(nonce string, pcrList []int) (types.HostManifest, error) {
-	log.Trace("intel_host_connector:GetHostManifestAcceptNonce() Entering")
-	defer log.Trace("intel_host_connector:GetHostManifestAcceptNonce() Leaving")
+// GetAttestationBundle collects the host manifest from an attestation bundle of the trust agent, so that the quote,
+// the event logs, the IMA log and the host info are read in a single request and cannot change between the calls.
+// The host manifest is collected with separate requests when the trust agent does not support the attestation bundles.
+func (ic *IntelConnector) GetAttestationBundle(ctx context.Context, pcrList []int) (types.HostManifest, error) {
+	log.Trace("intel_host_connector:GetAttestationBundle() Entering")
+	defer log.Trace("intel_host_connector:GetAttestationBundle() Leaving")
 
-	var verificationNonce string
-	var hostManifest types.HostManifest
-	var pcrBankList []string
+	nonce, err := ic.generateQuoteNonce()
+	if err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetAttestationBundle() Error generating "+
+			"nonce for attestation bundle request")
+	}
 
-	//Hardcoded pcr list here since there is no use case for customized pcr list
-	if pcrList == nil || len(pcrList) == 0 {
-		log.Infof("intel_host_connector:GetHostManifestAcceptNonce() pcrList is empty")
-		pcrList = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}
+	hostManifest, err := ic.GetAttestationBundleAcceptNonce(ctx, nonce, pcrList)
+	if err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetAttestationBundle() Error creating "+
+			"host manifest")
+	}
+	return hostManifest, nil
+}
+
+// GetAttestationBundleAcceptNonce accepts the nonce of GetAttestationBundle to support unit test
+func (ic *IntelConnector) GetAttestationBundleAcceptNonce(ctx context.Context, nonce string, pcrList []int) (types.HostManifest, error) {
+	log.Trace("intel_host_connector:GetAttestationBundleAcceptNonce() Entering")
+	defer log.Trace("intel_host_connector:GetAttestationBundleAcceptNonce() Leaving")
+
+	bundleCtx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+
+	pcrList, pcrBankList := ic.getQuotePcrs(pcrList)
+	bundleRequestedAt := time.Now()
+	bundle, err := ic.client.GetAttestationBundle(bundleCtx, nonce, pcrList, pcrBankList)
+	if err == client.ErrAttestationBundleNotSupported {
+		log.Debug("intel_host_connector:GetAttestationBundleAcceptNonce() TA does not support the attestation " +
+			"bundles, collecting the host manifest with separate requests")
+		return ic.GetHostManifestAcceptNonce(ctx, nonce, pcrList)
+	}
+	if err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetAttestationBundleAcceptNonce() Error "+
+			"getting attestation bundle")
+	}
+	bundleReceivedAt := time.Now()
+
+	var hostInfo taModel.HostInfo
+	hostInfoBytes, err := base64.StdEncoding.DecodeString(bundle.HostInfo)
+	if err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetAttestationBundleAcceptNonce() Error "+
+			"decoding host info of attestation bundle")
+	}
+	if err = json.Unmarshal(hostInfoBytes, &hostInfo); err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetAttestationBundleAcceptNonce() Error "+
+			"unmarshalling host info of attestation bundle")
+	}
+	ic.addPlatformInventory(bundleCtx, &hostInfo)
+	imaLog, err := base64.StdEncoding.DecodeString(bundle.ImaLog)
+	if err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetAttestationBundleAcceptNonce() Error "+
+			"decoding IMA log of attestation bundle")
+	}
+
+	verificationNonce, err := ic.getVerificationNonce(nonce, bundle.Quote)
+	if err != nil {
+		return types.HostManifest{}, err
 	}
+	// the quote of the bundle covers the host info and the logs it was collected with
+	verificationNonce, err = util.GetAttestationBundleNonce(verificationNonce, &bundle)
+	if err != nil {
+		return types.HostManifest{}, err
+	}
+
+	hostManifest, err := ic.createHostManifest(bundleCtx, nonce, verificationNonce, hostInfo, bundle.Quote,
+		bundleRequestedAt, bundleReceivedAt)
+	if err != nil {
+		return types.HostManifest{}, err
+	}
+	hostManifest.ImaLog = string(imaLog)
+	log.Info("intel_host_connector:GetAttestationBundleAcceptNonce() Host manifest created successfully from attestation bundle")
+	return hostManifest, nil
+}
 
-	//always request sha1/sha256 PCR banks from TA
-	pcrBankList = []string{"SHA1", "SHA256"}
+// Separate function has been created that accepts nonce to support unit test.
+// Else it would be difficult to mock random nonce.
+func (ic *IntelConnector) GetHostManifestAcceptNonce(ctx context.Context, nonce string, pcrList []int) (types.HostManifest, error) {
+	log.Trace("intel_host_connector:GetHostManifestAcceptNonce() Entering")
+	defer log.Trace("intel_host_connector:GetHostManifestAcceptNonce() Leaving")
+
+	ctx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+
+	pcrList, pcrBankList := ic.getQuotePcrs(pcrList)
 
 	//check if AIK Certificate is present on host before getting host manifest
-	aikInDER, err := ic.client.GetAIK()
+	aikInDER, err := ic.client.GetAIK(ctx)
 	if err != nil || len(aikInDER) == 0 {
 		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Invalid AIK"+
 			"certificate returned by TA")
 	}
 	secLog.Debug("intel_host_connector:GetHostManifestAcceptNonce() Successfully received AIK certificate in DER format")
 
-	hostManifest.HostInfo, err = ic.client.GetHostInfo()
+	hostInfo, err := ic.client.GetHostInfo(ctx)
 	if err != nil {
 		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error getting "+
 			"host details from TA")
 	}
+	ic.addPlatformInventory(ctx, &hostInfo)
 
-	tpmQuoteResponse, err := ic.client.GetTPMQuote(nonce, pcrList, pcrBankList)
+	quoteRequestedAt := time.Now()
+	tpmQuoteResponse, err := ic.getTPMQuote(ctx, nonce, pcrList, pcrBankList)
 	if err != nil {
 		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error getting TPM "+
 			"quote response")
 	}
+	quoteReceivedAt := time.Now()
+
+	verificationNonce, err := ic.getVerificationNonce(nonce, tpmQuoteResponse)
+	if err != nil {
+		return types.HostManifest{}, err
+	}
+
+	hostManifest, err := ic.createHostManifest(ctx, nonce, verificationNonce, hostInfo, tpmQuoteResponse,
+		quoteRequestedAt, quoteReceivedAt)
+	if err != nil {
+		return types.HostManifest{}, err
+	}
+	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Host manifest created successfully")
+	return hostManifest, nil
+}
+
+// generateQuoteNonce generates the nonce of a quote request, bound to the verifier and the host record when the
+// connector has them
+func (ic *IntelConnector) generateQuoteNonce() (string, error) {
+	if ic.quoteRequester != "" && ic.hostId != "" {
+		return util.GenerateBoundNonce(util.QuoteNonceRandomSize, util.GetQuoteNonceBinding(ic.quoteRequester, ic.hostId))
+	}
+	return util.GenerateNonce(util.QuoteNonceRandomSize)
+}
+
+// getQuotePcrs returns the PCRs and the PCR banks of a quote request
+func (ic *IntelConnector) getQuotePcrs(pcrList []int) ([]int, []string) {
+	//Hardcoded pcr list here since there is no use case for customized pcr list
+	if pcrList == nil || len(pcrList) == 0 {
+		log.Infof("intel_host_connector:getQuotePcrs() pcrList is empty")
+		pcrList = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}
+	}
+
+	//request the sha1/sha256 PCR banks from TA, unless the host is known to support only some of them
+	pcrBankList := defaultPCRBanks
+	if len(ic.pcrBanks) > 0 {
+		pcrBankList = ic.pcrBanks
+	}
+	return pcrList, pcrBankList
+}
 
+// getVerificationNonce returns the base64 encoded nonce the quote of the response is expected to be extended with
+func (ic *IntelConnector) getVerificationNonce(nonce string, tpmQuoteResponse taModel.TpmQuoteResponse) (string, error) {
 	nonceInBytes, err := base64.StdEncoding.DecodeString(nonce)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Base64 decode of TPM "+
+		return "", errors.Wrap(err, "intel_host_connector:getVerificationNonce() Base64 decode of TPM "+
 			"nonce failed")
 	}
 
-	verificationNonce, err = util.GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
+	verificationNonce, err := util.GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
 	if err != nil {
-		return types.HostManifest{}, err
+		return "", err
 	}
-	secLog.Debug("intel_host_connector:GetHostManifestAcceptNonce() Updated Verification nonce is : ", verificationNonce)
+	secLog.Debug("intel_host_connector:getVerificationNonce() Updated Verification nonce is : ", verificationNonce)
+	return verificationNonce, nil
+}
+
+// createHostManifest verifies the quote of the response against the verification nonce and creates the host manifest
+// of the host info and of the quote
+func (ic *IntelConnector) createHostManifest(ctx context.Context, nonce, verificationNonce string, hostInfo taModel.HostInfo,
+	tpmQuoteResponse taModel.TpmQuoteResponse, quoteRequestedAt, quoteReceivedAt time.Time) (types.HostManifest, error) {
+	log.Trace("intel_host_connector:createHostManifest() Entering")
+	defer log.Trace("intel_host_connector:createHostManifest() Leaving")
+
+	var hostManifest types.HostManifest
+	hostManifest.HostInfo = hostInfo
 
 	aikCertInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Aik)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error decoding"+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error decoding"+
 			"AIK certificate to bytes")
 	}
 
 	//Convert base64 encoded AIK to Pem format
 	aikPem, _ := pem.Decode(aikCertInBytes)
+	if aikPem == nil {
+		return types.HostManifest{}, errors.New("intel_host_connector:createHostManifest() Error decoding " +
+			"AIK certificate")
+	}
 	aikCertificate, err := x509.ParseCertificate(aikPem.Bytes)
 
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error parsing "+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error parsing "+
 			"AIK certicate")
 	}
 
 	eventLogBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.EventLog)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error converting "+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error converting "+
 			"event log to bytes")
 	}
 	decodedEventLog := string(eventLogBytes)
-	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Retrieved event log from TPM quote response")
+	log.Info("intel_host_connector:createHostManifest() Retrieved event log from TPM quote response")
+
+	// the binary TCG event log is only reported by the trust agents that can read it from the TPM
+	var binaryEventLog []byte
+	if tpmQuoteResponse.BinaryEventLog != "" {
+		binaryEventLog, err = base64.StdEncoding.DecodeString(tpmQuoteResponse.BinaryEventLog)
+		if err != nil {
+			return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error converting "+
+				"binary event log to bytes")
+		}
+	}
 
 	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error converting "+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error converting "+
 			"tpm quote to bytes")
 	}
 
 	verificationNonceInBytes, err := base64.StdEncoding.DecodeString(verificationNonce)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error converting "+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error converting "+
 			"nonce to bytes")
 	}
-	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Verifying quote and retrieving PCR manifest from TPM quote " +
+	log.Info("intel_host_connector:createHostManifest() Verifying quote and retrieving PCR manifest from TPM quote " +
 		"response ...")
-	pcrManifest, pcrsDigest, err := util.VerifyQuoteAndGetPCRManifest(decodedEventLog, verificationNonceInBytes,
+	pcrManifest, pcrsDigest, err := util.VerifyQuoteAndGetPCRManifest(decodedEventLog, binaryEventLog, verificationNonceInBytes,
 		tpmQuoteInBytes, aikCertificate)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error verifying "+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error verifying "+
 			"TPM Quote")
 	}
-	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Successfully retrieved PCR manifest from quote")
+	quotePcrDigest, err := util.GetQuotePcrDigest(tpmQuoteInBytes)
+	if err != nil {
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error "+
+			"retrieving PCR digest from TPM Quote")
+	}
+	log.Info("intel_host_connector:createHostManifest() Successfully retrieved PCR manifest from quote")
 
 	isWlaInstalled := false
 	for _, component := range hostManifest.HostInfo.InstalledComponents {
@@ -153,80 +361,159 @@ func (ic *IntelConnector) GetHostManifestAcceptNonce(nonce string, pcrList []int
 
 	bindingKeyCertificateBase64 := ""
 	if hostManifest.HostInfo.IsDockerEnvironment {
-		bindingKeyBytes, _ := ic.client.GetBindingKeyCertificate()
+		bindingKeyBytes, _ := ic.client.GetBindingKeyCertificate(ctx)
 		if bindingKeyBytes != nil && len(bindingKeyBytes) != 0 {
 			bindingKeyCertificate, _ := pem.Decode(bindingKeyBytes)
 			if bindingKeyCertificate == nil {
-				log.Warn("intel_host_connector:GetHostManifestAcceptNonce() - " +
+				log.Warn("intel_host_connector:createHostManifest() - " +
 					"Could not decode Binding key certificate. Unexpected response from client")
 			}
 			bindingKeyCertificateBase64 = base64.StdEncoding.EncodeToString(bindingKeyCertificate.Bytes)
 		} else {
-			log.Warn("intel_host_connector:GetHostManifestAcceptNonce() " +
+			log.Warn("intel_host_connector:createHostManifest() " +
 				"Empty Binding Key received")
 		}
 	} else if isWlaInstalled {
-		bindingKeyBytes, err := ic.client.GetBindingKeyCertificate()
+		bindingKeyBytes, err := ic.client.GetBindingKeyCertificate(ctx)
 		if err != nil {
-			return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() "+
+			return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() "+
 				"Error getting binding key certificate from TA")
 		}
 
 		if bindingKeyBytes == nil || len(bindingKeyBytes) == 0 {
-			return types.HostManifest{}, errors.New("intel_host_connector:GetHostManifestAcceptNonce() " +
+			return types.HostManifest{}, errors.New("intel_host_connector:createHostManifest() " +
 				"Empty Binding Key received")
 		}
 
 		bindingKeyCertificate, _ := pem.Decode(bindingKeyBytes)
 		if bindingKeyCertificate == nil {
-			return types.HostManifest{}, errors.New("intel_host_connector:GetHostManifestAcceptNonce() - " +
+			return types.HostManifest{}, errors.New("intel_host_connector:createHostManifest() - " +
 				"Could not decode Binding key certificate. Unexpected response from client")
 		}
 		bindingKeyCertificateBase64 = base64.StdEncoding.EncodeToString(bindingKeyCertificate.Bytes)
 	}
 	aikCertificateBase64 := base64.StdEncoding.EncodeToString(aikPem.Bytes)
 
+	for _, measurementXml := range tpmQuoteResponse.TcbMeasurements.TcbMeasurements {
+		if err := validation.ValidateXMLDocument([]byte(measurementXml)); err != nil {
+			return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() "+
+				"Invalid measurement xml received from TA")
+		}
+	}
+
 	hostManifest.PcrManifest = pcrManifest
 	hostManifest.AIKCertificate = aikCertificateBase64
 	hostManifest.AssetTagDigest = tpmQuoteResponse.AssetTag
 	hostManifest.BindingKeyCertificate = bindingKeyCertificateBase64
 	hostManifest.MeasurementXmls = tpmQuoteResponse.TcbMeasurements.TcbMeasurements
 	hostManifest.QuoteDigest = hex.EncodeToString(pcrsDigest) + hostManifest.AssetTagDigest
+	hostManifest.QuoteNonce = nonce
+	hostManifest.HostId = ic.hostId
+	hostManifest.QuotePcrDigest = quotePcrDigest
+	if isWlaInstalled {
+		hostManifest.ContainerImageMeasurements = tpmQuoteResponse.ContainerImageMeasurements.ContainerImageMeasurements
+	}
+	if tpmQuoteResponse.TimeStamp > 0 {
+		hostManifest.ClockSkew = types.NewClockSkew(tpmQuoteResponse.TimeStamp, quoteRequestedAt, quoteReceivedAt)
+		log.Debugf("intel_host_connector:createHostManifest() Host clock skew %dms (+/- %dms)",
+			hostManifest.ClockSkew.OffsetMillis, hostManifest.ClockSkew.UncertaintyMillis)
+	}
 
 	hostManifestJson, err := json.Marshal(hostManifest)
 	if err != nil {
-		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error "+
+		return types.HostManifest{}, errors.Wrap(err, "intel_host_connector:createHostManifest() Error "+
 			"marshalling host manifest to JSON")
 	}
-	log.Debugf("intel_host_connector:GetHostManifestAcceptNonce() Host Manifest : %s", string(hostManifestJson))
-	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Host manifest created successfully")
+	log.Debugf("intel_host_connector:createHostManifest() Host Manifest : %s", string(hostManifestJson))
 	return hostManifest, err
 }
 
-func (ic *IntelConnector) DeployAssetTag(hardwareUUID, tag string) error {
+// getTPMQuote retrieves the quote synchronously, or when quote callbacks are configured, requests the quote
+// and waits for the trust agent to post it back
+func (ic *IntelConnector) getTPMQuote(ctx context.Context, nonce string, pcrList []int, pcrBankList []string) (taModel.TpmQuoteResponse, error) {
+	if ic.quoteCallbacks == nil {
+		return ic.client.GetTPMQuote(ctx, nonce, pcrList, pcrBankList)
+	}
+
+	correlationID, callbackURL, quoteCh := ic.quoteCallbacks.register()
+	if err := ic.client.RequestTPMQuote(ctx, nonce, pcrList, pcrBankList, correlationID, callbackURL); err != nil {
+		ic.quoteCallbacks.remove(correlationID)
+		return taModel.TpmQuoteResponse{}, err
+	}
+	log.Debugf("intel_host_connector:getTPMQuote() Waiting for the quote of request %s", correlationID)
+	return ic.quoteCallbacks.wait(ctx, correlationID, quoteCh)
+}
+
+func (ic *IntelConnector) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
 
 	log.Trace("intel_host_connector:DeployAssetTag() Entering")
 	defer log.Trace("intel_host_connector:DeployAssetTag() Leaving")
-	err := ic.client.DeployAssetTag(hardwareUUID, tag)
+	ctx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+	err := ic.client.DeployAssetTag(ctx, hardwareUUID, tag)
 	return err
 }
 
-func (ic *IntelConnector) DeploySoftwareManifest(manifest taModel.Manifest) error {
+func (ic *IntelConnector) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
 
 	log.Trace("intel_host_connector:DeploySoftwareManifest() Entering")
 	defer log.Trace("intel_host_connector:DeploySoftwareManifest() Leaving")
-	err := ic.client.DeploySoftwareManifest(manifest)
+	ctx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+	err := ic.client.DeploySoftwareManifest(ctx, manifest)
 	return err
 }
 
-func (ic *IntelConnector) GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error) {
+// DeploySoftwareManifests deploys the manifests one after the other and returns the error of each
+// manifest at its index, a failed manifest does not prevent the deployment of the next ones. Every manifest is
+// deployed within the call timeout of the connector
+func (ic *IntelConnector) DeploySoftwareManifests(ctx context.Context, manifests []taModel.Manifest) []error {
+
+	log.Trace("intel_host_connector:DeploySoftwareManifests() Entering")
+	defer log.Trace("intel_host_connector:DeploySoftwareManifests() Leaving")
+	errs := make([]error, len(manifests))
+	for i, manifest := range manifests {
+		errs[i] = ic.DeploySoftwareManifest(ctx, manifest)
+	}
+	return errs
+}
+
+func (ic *IntelConnector) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
 
 	log.Trace("intel_host_connector:GetMeasurementFromManifest() Entering")
 	defer log.Trace("intel_host_connector:GetMeasurementFromManifest() Leaving")
-	measurement, err := ic.client.GetMeasurementFromManifest(manifest)
+	ctx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+	measurement, err := ic.client.GetMeasurementFromManifest(ctx, manifest)
 	return measurement, err
 }
 
-func (ic *IntelConnector) GetClusterReference(clusterName string) ([]mo.HostSystem, error) {
+// Capabilities returns the capabilities reported by the trust agent, the capabilities of the trust agents that
+// predate the capabilities API are derived from their host info
+func (ic *IntelConnector) Capabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	log.Trace("intel_host_connector:Capabilities() Entering")
+	defer log.Trace("intel_host_connector:Capabilities() Leaving")
+
+	ctx, cancel := withCallTimeout(ctx, ic.callTimeout)
+	defer cancel()
+	capabilities, err := ic.client.GetCapabilities(ctx)
+	if err == nil {
+		return capabilities, nil
+	}
+	if err != client.ErrCapabilitiesNotSupported {
+		return taModel.HostCapabilities{}, errors.Wrap(err, "intel_host_connector:Capabilities() Error getting "+
+			"host capabilities from TA")
+	}
+
+	log.Debug("intel_host_connector:Capabilities() TA does not report its capabilities, deriving them from the host info")
+	hostInfo, err := ic.client.GetHostInfo(ctx)
+	if err != nil {
+		return taModel.HostCapabilities{}, errors.Wrap(err, "intel_host_connector:Capabilities() Error getting "+
+			"host details from TA")
+	}
+	return capabilitiesFromHostInfo(hostInfo), nil
+}
+
+func (ic *IntelConnector) GetClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error) {
 	return nil, errors.New("intel_host_connector :GetClusterReference() Operation not supported")
 }
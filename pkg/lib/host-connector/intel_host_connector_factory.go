@@ -6,14 +6,21 @@ package host_connector
 
 import (
 	"crypto/x509"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients"
 	client "github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/pkg/errors"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type IntelConnectorFactory struct {
+	quoteCallbacks *QuoteCallbacks
+	requestAuth    *client.RequestAuth
+	quoteRequester string
+	callTimeout    time.Duration
+	proxy          clients.ProxyFunc
 }
 
 func (icf *IntelConnectorFactory) GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string,
@@ -30,16 +37,19 @@ func (icf *IntelConnectorFactory) GetHostConnector(vendorConnector types.VendorC
 		return nil, errors.New("intel_host_connector_factory:GetHostConnector() error retrieving TA API URL")
 	}
 
-	taClient, err := client.NewTAClient(aasApiUrl,
+	taClient, err := client.NewTAClientWithProxy(aasApiUrl,
 		taApiURL,
 		vendorConnector.Configuration.Username,
 		vendorConnector.Configuration.Password,
-		trustedCaCerts)
+		trustedCaCerts,
+		icf.requestAuth,
+		icf.proxy)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "intel_host_connector_factory:GetHostConnector() Could not create Trust Agent client")
 	}
 
 	log.Debug("intel_host_connector_factory:GetHostConnector() TA client created")
-	return &IntelConnector{taClient}, nil
+	return &IntelConnector{client: taClient, quoteCallbacks: icf.quoteCallbacks, quoteRequester: icf.quoteRequester,
+		callTimeout: icf.callTimeout}, nil
 }
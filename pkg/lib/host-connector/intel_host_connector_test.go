@@ -6,17 +6,22 @@
 package host_connector
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"encoding/xml"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients/redfish"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func TestGetHostDetails(t *testing.T) {
@@ -36,12 +41,44 @@ func TestGetHostDetails(t *testing.T) {
 		client: mockTAClient,
 	}
 
-	hostInfo, err = intelConnector.GetHostDetails()
+	hostInfo, err = intelConnector.GetHostDetails(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, "RedHatEnterprise", hostInfo.OSName)
 	assert.Equal(t, "Intel Corporation", hostInfo.BiosName)
 }
 
+func TestGetHostDetailsWithPlatformInventory(t *testing.T) {
+	mockTAClient, err := ta.NewMockTAClient()
+	assert.NoError(t, err)
+	var hostInfo taModel.HostInfo
+	hostInfoJson, err := ioutil.ReadFile("./test/sample_platform_info.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(hostInfoJson, &hostInfo))
+	mockTAClient.On("GetHostInfo").Return(hostInfo, nil)
+
+	platformInventory := &taModel.PlatformInventory{
+		Source:     redfish.InventorySource,
+		Firmware:   []taModel.FirmwareComponent{{Name: "BIOS", Version: "2.10.2"}},
+		TPMPresent: true,
+	}
+	mockRedfishClient := redfish.NewMockRedfishClient()
+	mockRedfishClient.On("GetPlatformInventory").Return(platformInventory, nil).Once()
+
+	intelConnector := IntelConnector{client: mockTAClient}
+	intelConnector.SetPlatformInventoryClient(mockRedfishClient)
+	hostInfo, err = intelConnector.GetHostDetails(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "RedHatEnterprise", hostInfo.OSName)
+	assert.Equal(t, platformInventory, hostInfo.PlatformInventory)
+
+	// the host info is reported without the platform inventory when the BMC cannot be reached
+	mockRedfishClient.On("GetPlatformInventory").Return((*taModel.PlatformInventory)(nil), errors.New("connection refused"))
+	hostInfo, err = intelConnector.GetHostDetails(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "RedHatEnterprise", hostInfo.OSName)
+	assert.Nil(t, hostInfo.PlatformInventory)
+}
+
 func TestCreateHostManifestFromSampleData(t *testing.T) {
 
 	// create a mock ta client that will return dummy data to host-connector
@@ -86,7 +123,7 @@ func TestCreateHostManifestFromSampleData(t *testing.T) {
 	// the sample data in ./test used this nonce which needs to be provided to GetHostManifest...
 	nonce := "tHgfRQED1+pYgEZpq3dZC9ONmBCZKdx10LErTZs1k/k="
 
-	hostManifest, err := intelConnector.GetHostManifestAcceptNonce(nonce, nil)
+	hostManifest, err := intelConnector.GetHostManifestAcceptNonce(context.Background(), nonce, nil)
 	assert.NoError(t, err)
 
 	json, err := json.Marshal(hostManifest)
@@ -94,6 +131,79 @@ func TestCreateHostManifestFromSampleData(t *testing.T) {
 	t.Log(string(json))
 }
 
+func TestCreateHostManifestAsync(t *testing.T) {
+
+	mockTAClient, err := ta.NewMockTAClient()
+	assert.NoError(t, err)
+
+	var tpmQuoteResponse taModel.TpmQuoteResponse
+	b, err := ioutil.ReadFile("./test/sample_tpm_quote.xml")
+	assert.NoError(t, err)
+	err = xml.Unmarshal(b, &tpmQuoteResponse)
+	assert.NoError(t, err)
+
+	var hostInfo taModel.HostInfo
+	b, err = ioutil.ReadFile("./test/sample_platform_info.json")
+	assert.NoError(t, err)
+	err = json.Unmarshal(b, &hostInfo)
+	assert.NoError(t, err)
+	mockTAClient.On("GetHostInfo").Return(hostInfo, nil)
+
+	aikBytes, err := ioutil.ReadFile("./test/aik.pem")
+	assert.NoError(t, err)
+	aikDer, _ := pem.Decode(aikBytes)
+	mockTAClient.On("GetAIK").Return(aikDer.Bytes, nil)
+	mockTAClient.On("GetBindingKeyCertificate").Return([]byte{}, nil)
+
+	quoteCallbacks := NewQuoteCallbacks("https://hvs.com:8443/hvs/v2/quote-callbacks/", time.Minute)
+
+	// the trust agent posts the quote back once the request is accepted
+	mockTAClient.On("RequestTPMQuote", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		correlationID := args.String(3)
+		assert.Equal(t, "https://hvs.com:8443/hvs/v2/quote-callbacks/"+correlationID, args.String(4))
+		go func() {
+			assert.NoError(t, quoteCallbacks.Deliver(correlationID, tpmQuoteResponse))
+		}()
+	})
+
+	intelConnector := IntelConnector{
+		client:         mockTAClient,
+		quoteCallbacks: quoteCallbacks,
+	}
+
+	nonce := "tHgfRQED1+pYgEZpq3dZC9ONmBCZKdx10LErTZs1k/k="
+	hostManifest, err := intelConnector.GetHostManifestAcceptNonce(context.Background(), nonce, nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hostManifest.QuoteDigest)
+	mockTAClient.AssertNotCalled(t, "GetTPMQuote", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQuoteCallbacksTimeout(t *testing.T) {
+	quoteCallbacks := NewQuoteCallbacks("https://hvs.com:8443/hvs/v2/quote-callbacks", 10*time.Millisecond)
+
+	correlationID, _, quoteCh := quoteCallbacks.register()
+	_, err := quoteCallbacks.wait(context.Background(), correlationID, quoteCh)
+	assert.Error(t, err)
+
+	// a quote posted back after the timeout is rejected
+	err = quoteCallbacks.Deliver(correlationID, taModel.TpmQuoteResponse{})
+	assert.Equal(t, ErrUnknownQuoteCorrelationID, err)
+}
+
+func TestQuoteCallbacksCanceled(t *testing.T) {
+	quoteCallbacks := NewQuoteCallbacks("https://hvs.com:8443/hvs/v2/quote-callbacks", time.Minute)
+
+	// the wait stops when the request the quote is collected for is done, well before the quote timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	correlationID, _, quoteCh := quoteCallbacks.register()
+	_, err := quoteCallbacks.wait(ctx, correlationID, quoteCh)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+
+	err = quoteCallbacks.Deliver(correlationID, taModel.TpmQuoteResponse{})
+	assert.Equal(t, ErrUnknownQuoteCorrelationID, err)
+}
+
 func TestEventReplay256(t *testing.T) {
 	// this data was extracted from an existing host manifest...
 	eventLogJson := `
@@ -207,7 +317,7 @@ func TestGetMeasurementFromManifest(t *testing.T) {
 		client: mockTAClient,
 	}
 
-	measurementResponse, err := intelConnector.GetMeasurementFromManifest(manifest)
+	measurementResponse, err := intelConnector.GetMeasurementFromManifest(context.Background(), manifest)
 	assert.NoError(t, err)
 	log.Info("Measurement is : ", measurementResponse)
 }
@@ -227,7 +337,7 @@ func TestDeployAssetTag(t *testing.T) {
 		client: mockTAClient,
 	}
 
-	err = intelConnector.DeployAssetTag(hardwareUUID, tag)
+	err = intelConnector.DeployAssetTag(context.Background(), hardwareUUID, tag)
 	assert.NoError(t, err)
 }
 
@@ -251,6 +361,136 @@ func TestDeploySoftwareManifest(t *testing.T) {
 		client: mockTAClient,
 	}
 
-	err = intelConnector.DeploySoftwareManifest(manifest)
+	err = intelConnector.DeploySoftwareManifest(context.Background(), manifest)
+	assert.NoError(t, err)
+}
+
+func TestDeploySoftwareManifests(t *testing.T) {
+	// create a mock ta client that will return dummy data to host-connector
+	mockTAClient, err := ta.NewMockTAClient()
+	assert.NoError(t, err)
+
+	var manifest taModel.Manifest
+
+	manifestXml, err := ioutil.ReadFile("./test/sample_manifest.xml")
+	assert.NoError(t, err)
+
+	err = xml.Unmarshal(manifestXml, &manifest)
+	assert.NoError(t, err)
+
+	failedManifest := manifest
+	failedManifest.Label = "ISL_Applications_Failed"
+
+	mockTAClient.On("DeploySoftwareManifest", manifest).Return(nil)
+	mockTAClient.On("DeploySoftwareManifest", failedManifest).Return(errors.New("Error deploying manifest"))
+
+	intelConnector := IntelConnector{
+		client: mockTAClient,
+	}
+
+	errs := intelConnector.DeploySoftwareManifests(context.Background(), []taModel.Manifest{failedManifest, manifest})
+	assert.Len(t, errs, 2)
+	assert.Error(t, errs[0])
+	assert.NoError(t, errs[1])
+}
+
+func TestCapabilities(t *testing.T) {
+	mockTAClient, err := ta.NewMockTAClient()
+	assert.NoError(t, err)
+	capabilities := taModel.HostCapabilities{TPMVersion: "2.0", PCRBanks: []string{"SHA256", "SHA384"}, SGX: true}
+	mockTAClient.On("GetCapabilities").Return(capabilities, nil)
+
+	intelConnector := IntelConnector{
+		client: mockTAClient,
+	}
+
+	reported, err := intelConnector.Capabilities(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, capabilities, reported)
+	mockTAClient.AssertNotCalled(t, "GetHostInfo")
+
+	// only the banks supported by the host are quoted
+	intelConnector.SelectPCRBanks(&reported)
+	assert.Equal(t, []string{"SHA256"}, intelConnector.pcrBanks)
+}
+
+func TestCapabilitiesOfAgentWithoutCapabilitiesAPI(t *testing.T) {
+	mockTAClient, err := ta.NewMockTAClient()
+	assert.NoError(t, err)
+	var hostInfo taModel.HostInfo
+	hostInfoJson, err := ioutil.ReadFile("./test/sample_platform_info.json")
+	assert.NoError(t, err)
+	err = json.Unmarshal(hostInfoJson, &hostInfo)
+	assert.NoError(t, err)
+	mockTAClient.On("GetCapabilities").Return(taModel.HostCapabilities{}, ta.ErrCapabilitiesNotSupported)
+	mockTAClient.On("GetHostInfo").Return(hostInfo, nil)
+
+	intelConnector := IntelConnector{
+		client: mockTAClient,
+	}
+
+	capabilities, err := intelConnector.Capabilities(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", capabilities.TPMVersion)
+	assert.Equal(t, []string{"SHA1", "SHA256"}, capabilities.PCRBanks)
+	assert.Equal(t, []string{"tagent"}, capabilities.MeasurementAgents)
+	assert.False(t, capabilities.SGX)
+
+	// the other errors are not hidden by the host info
+	mockTAClient, err = ta.NewMockTAClient()
+	assert.NoError(t, err)
+	mockTAClient.On("GetCapabilities").Return(taModel.HostCapabilities{}, errors.New("connection refused"))
+	intelConnector.client = mockTAClient
+	_, err = intelConnector.Capabilities(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGetAttestationBundle(t *testing.T) {
+	mockTAClient, err := ta.NewMockTAClient()
+	assert.NoError(t, err)
+
+	var tpmQuoteResponse taModel.TpmQuoteResponse
+	b, err := ioutil.ReadFile("./test/sample_tpm_quote.xml")
+	assert.NoError(t, err)
+	err = xml.Unmarshal(b, &tpmQuoteResponse)
+	assert.NoError(t, err)
+
+	hostInfoJson, err := ioutil.ReadFile("./test/sample_platform_info.json")
+	assert.NoError(t, err)
+	var hostInfo taModel.HostInfo
+	err = json.Unmarshal(hostInfoJson, &hostInfo)
+	assert.NoError(t, err)
+
+	aikBytes, err := ioutil.ReadFile("./test/aik.pem")
+	assert.NoError(t, err)
+	aikDer, _ := pem.Decode(aikBytes)
+	mockTAClient.On("GetAIK").Return(aikDer.Bytes, nil)
+	mockTAClient.On("GetHostInfo").Return(hostInfo, nil)
+	mockTAClient.On("GetTPMQuote", mock.Anything, mock.Anything, mock.Anything).Return(tpmQuoteResponse, nil)
+	mockTAClient.On("GetBindingKeyCertificate").Return([]byte{}, nil)
+
+	// the sample quote is extended with the nonce only, it does not cover the host info and the logs of a bundle
+	mockTAClient.On("GetAttestationBundle", mock.Anything, mock.Anything, mock.Anything).Return(taModel.AttestationBundle{
+		HostInfo: base64.StdEncoding.EncodeToString(hostInfoJson),
+		ImaLog:   base64.StdEncoding.EncodeToString([]byte("10 9797edf94e29f3bf37fd8401239ca7e3e0d6dc5c ima-ng sha1:0 boot_aggregate")),
+		Quote:    tpmQuoteResponse,
+	}, nil).Once()
+
+	intelConnector := IntelConnector{
+		client: mockTAClient,
+	}
+
+	nonce := "tHgfRQED1+pYgEZpq3dZC9ONmBCZKdx10LErTZs1k/k="
+	_, err = intelConnector.GetAttestationBundleAcceptNonce(context.Background(), nonce, nil)
+	assert.Error(t, err)
+	mockTAClient.AssertNotCalled(t, "GetTPMQuote", mock.Anything, mock.Anything, mock.Anything)
+
+	// the host manifest is collected with separate requests from the trust agents without attestation bundles
+	mockTAClient.On("GetAttestationBundle", mock.Anything, mock.Anything, mock.Anything).Return(taModel.AttestationBundle{},
+		ta.ErrAttestationBundleNotSupported)
+	hostManifest, err := intelConnector.GetAttestationBundleAcceptNonce(context.Background(), nonce, nil)
 	assert.NoError(t, err)
+	assert.NotEmpty(t, hostManifest.QuoteDigest)
+	assert.Empty(t, hostManifest.ImaLog)
+	mockTAClient.AssertCalled(t, "GetTPMQuote", mock.Anything, mock.Anything, mock.Anything)
 }
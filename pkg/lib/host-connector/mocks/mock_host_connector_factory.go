@@ -57,6 +57,11 @@ func (micf MockIntelConnectorFactory) GetHostConnector(vendorConnector types.Ven
 	hostInfoBytes, _ := ioutil.ReadAll(hostInfoJson)
 	_ = json.Unmarshal(hostInfoBytes, &hostInfo)
 	mhc.On("GetHostDetails").Return(hostInfo, nil)
+	mhc.On("Capabilities").Return(taModel.HostCapabilities{
+		TPMVersion:        hostInfo.HardwareFeatures.TPM.Meta.TPMVersion,
+		PCRBanks:          []string{"SHA1", "SHA256"},
+		MeasurementAgents: hostInfo.InstalledComponents,
+	}, nil)
 
 	// Mock GetHostManifest
 	var hm types.HostManifest
@@ -75,6 +80,7 @@ func (micf MockIntelConnectorFactory) GetHostConnector(vendorConnector types.Ven
 	mhc.On("GetMeasurementFromManifest", mock.Anything).Return(measurement, nil)
 
 	mhc.On("DeploySoftwareManifest", mock.Anything).Return(nil)
+	mhc.On("DeploySoftwareManifests", mock.Anything).Return(nil)
 
 	return &mhc, nil
 }
@@ -95,6 +101,10 @@ func (micf MockVmwareConnectorFactory) GetHostConnector(vendorConnector types.Ve
 	hostInfoJson, _ := ioutil.ReadFile("./test/sample_vmware_platform_info.json")
 	_ = json.Unmarshal(hostInfoJson, &hostInfo)
 	vmc.On("GetHostDetails").Return(hostInfo, nil)
+	vmc.On("Capabilities").Return(taModel.HostCapabilities{
+		TPMVersion: hostInfo.HardwareFeatures.TPM.Meta.TPMVersion,
+		PCRBanks:   []string{"SHA256"},
+	}, nil)
 
 	return &vmc, nil
 }
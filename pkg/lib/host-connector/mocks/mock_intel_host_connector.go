@@ -7,6 +7,7 @@ package mocks
 //go:generate mockgen -destination=mock_intel_host_connector.go -package=host_connector github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector MockIntelConnector
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
@@ -21,12 +22,12 @@ type MockIntelConnector struct {
 	mock.Mock
 }
 
-func (ihc *MockIntelConnector) GetHostDetails() (taModel.HostInfo, error) {
+func (ihc *MockIntelConnector) GetHostDetails(ctx context.Context) (taModel.HostInfo, error) {
 	args := ihc.Called()
 	return args.Get(0).(taModel.HostInfo), args.Error(1)
 }
 
-func (ihc *MockIntelConnector) GetHostManifest([]int) (types.HostManifest, error) {
+func (ihc *MockIntelConnector) GetHostManifest(ctx context.Context, pcrList []int) (types.HostManifest, error) {
 	args := ihc.Called()
 	var hostManifest types.HostManifest
 	// this is required for any test case that requires a good HostManifest
@@ -40,22 +41,35 @@ func (ihc *MockIntelConnector) GetHostManifest([]int) (types.HostManifest, error
 	}
 }
 
-func (ihc *MockIntelConnector) DeployAssetTag(hardwareUUID, tag string) error {
+func (ihc *MockIntelConnector) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
 	args := ihc.Called(hardwareUUID, tag)
 	return args.Error(0)
 }
 
-func (ihc *MockIntelConnector) DeploySoftwareManifest(manifest taModel.Manifest) error {
+func (ihc *MockIntelConnector) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
 	args := ihc.Called(manifest)
 	return args.Error(0)
 }
 
-func (ihc *MockIntelConnector) GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error) {
+func (ihc *MockIntelConnector) DeploySoftwareManifests(ctx context.Context, manifests []taModel.Manifest) []error {
+	args := ihc.Called(manifests)
+	if args.Get(0) == nil {
+		return make([]error, len(manifests))
+	}
+	return args.Get(0).([]error)
+}
+
+func (ihc *MockIntelConnector) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
 	args := ihc.Called(manifest)
 	return args.Get(0).(taModel.Measurement), args.Error(1)
 }
 
-func (ihc *MockIntelConnector) GetClusterReference(clusterName string) ([]mo.HostSystem, error) {
+func (ihc *MockIntelConnector) GetClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error) {
 	args := ihc.Called(clusterName)
 	return args.Get(0).([]mo.HostSystem), args.Error(1)
 }
+
+func (ihc *MockIntelConnector) Capabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	args := ihc.Called()
+	return args.Get(0).(taModel.HostCapabilities), args.Error(1)
+}
@@ -6,6 +6,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/vmware"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
@@ -18,32 +19,45 @@ type MockVmwareConnector struct {
 	mock.Mock
 }
 
-func (vhc *MockVmwareConnector) GetHostDetails() (taModel.HostInfo, error) {
+func (vhc *MockVmwareConnector) GetHostDetails(ctx context.Context) (taModel.HostInfo, error) {
 	args := vhc.Called()
 	return args.Get(0).(taModel.HostInfo), args.Error(1)
 }
 
-func (vhc *MockVmwareConnector) GetHostManifest([]int) (types.HostManifest, error) {
+func (vhc *MockVmwareConnector) GetHostManifest(ctx context.Context, pcrList []int) (types.HostManifest, error) {
 	args := vhc.Called()
 	return args.Get(0).(types.HostManifest), args.Error(1)
 }
 
-func (vhc *MockVmwareConnector) DeployAssetTag(hardwareUUID, tag string) error {
+func (vhc *MockVmwareConnector) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
 	args := vhc.Called(hardwareUUID, tag)
 	return args.Error(0)
 }
 
-func (vhc *MockVmwareConnector) DeploySoftwareManifest(manifest taModel.Manifest) error {
+func (vhc *MockVmwareConnector) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
 	args := vhc.Called(manifest)
 	return args.Error(0)
 }
 
-func (vhc *MockVmwareConnector) GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error) {
+func (vhc *MockVmwareConnector) DeploySoftwareManifests(ctx context.Context, manifests []taModel.Manifest) []error {
+	args := vhc.Called(manifests)
+	if args.Get(0) == nil {
+		return make([]error, len(manifests))
+	}
+	return args.Get(0).([]error)
+}
+
+func (vhc *MockVmwareConnector) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
 	args := vhc.Called(manifest)
 	return args.Get(0).(taModel.Measurement), args.Error(1)
 }
 
-func (vhc *MockVmwareConnector) GetClusterReference(clusterName string) ([]mo.HostSystem, error) {
+func (vhc *MockVmwareConnector) GetClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error) {
 	args := vhc.Called(clusterName)
 	return args.Get(0).([]mo.HostSystem), args.Error(1)
 }
+
+func (vhc *MockVmwareConnector) Capabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	args := vhc.Called()
+	return args.Get(0).(taModel.HostCapabilities), args.Error(1)
+}
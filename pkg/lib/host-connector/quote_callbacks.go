@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package host_connector
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownQuoteCorrelationID is returned when a quote is posted back for a request that is not pending,
+// either because it timed out or because the correlation id is not known
+var ErrUnknownQuoteCorrelationID = errors.New("Unknown or expired quote correlation id")
+
+// QuoteCallbacks correlates the TPM quotes posted back by the trust agents with the pending asynchronous
+// quote requests, so that no HTTP connection is held open while a slow host creates its quote
+type QuoteCallbacks struct {
+	callbackURL string
+	timeout     time.Duration
+	mtx         sync.Mutex
+	pending     map[string]chan taModel.TpmQuoteResponse
+}
+
+// NewQuoteCallbacks creates QuoteCallbacks, callbackURL is the url the trust agents post the quotes to
+// followed by the correlation id, and timeout is how long a quote is waited for
+func NewQuoteCallbacks(callbackURL string, timeout time.Duration) *QuoteCallbacks {
+	return &QuoteCallbacks{
+		callbackURL: strings.TrimSuffix(callbackURL, "/"),
+		timeout:     timeout,
+		pending:     make(map[string]chan taModel.TpmQuoteResponse),
+	}
+}
+
+// register creates a pending quote request and returns its correlation id and callback url
+func (qc *QuoteCallbacks) register() (string, string, chan taModel.TpmQuoteResponse) {
+	correlationID := uuid.New().String()
+	quoteCh := make(chan taModel.TpmQuoteResponse, 1)
+
+	qc.mtx.Lock()
+	defer qc.mtx.Unlock()
+	qc.pending[correlationID] = quoteCh
+	return correlationID, qc.callbackURL + "/" + correlationID, quoteCh
+}
+
+func (qc *QuoteCallbacks) remove(correlationID string) {
+	qc.mtx.Lock()
+	defer qc.mtx.Unlock()
+	delete(qc.pending, correlationID)
+}
+
+// wait blocks until the quote of the request is delivered, the timeout expires or the context is done
+func (qc *QuoteCallbacks) wait(ctx context.Context, correlationID string, quoteCh chan taModel.TpmQuoteResponse) (taModel.TpmQuoteResponse, error) {
+	defer qc.remove(correlationID)
+
+	timer := time.NewTimer(qc.timeout)
+	defer timer.Stop()
+	select {
+	case quote := <-quoteCh:
+		return quote, nil
+	case <-timer.C:
+		return taModel.TpmQuoteResponse{}, errors.Errorf("Timed out after %s waiting for the quote of request %s", qc.timeout, correlationID)
+	case <-ctx.Done():
+		return taModel.TpmQuoteResponse{}, errors.Wrapf(ctx.Err(), "Stopped waiting for the quote of request %s", correlationID)
+	}
+}
+
+// Deliver hands the quote posted back by a trust agent to the pending request with the correlation id
+func (qc *QuoteCallbacks) Deliver(correlationID string, quote taModel.TpmQuoteResponse) error {
+	qc.mtx.Lock()
+	quoteCh, ok := qc.pending[correlationID]
+	delete(qc.pending, correlationID)
+	qc.mtx.Unlock()
+
+	if !ok {
+		return ErrUnknownQuoteCorrelationID
+	}
+	quoteCh <- quote
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package host_connector
+
+import (
+	"crypto/x509"
+	"net/url"
+	"time"
+
+	client "github.com/intel-secl/intel-secl/v3/pkg/clients/ta"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/pkg/errors"
+)
+
+// SshConnectorFactory creates the connectors of the air-gapped hosts whose trust agent API cannot be exposed, the
+// connectors run the tagent CLI on the hosts over ssh and verify the quotes as the intel connectors do
+type SshConnectorFactory struct {
+	sshConfig      client.SshConfig
+	quoteRequester string
+	callTimeout    time.Duration
+}
+
+func (scf *SshConnectorFactory) GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string,
+	trustedCaCerts []x509.Certificate) (HostConnector, error) {
+
+	log.Trace("ssh_host_connector_factory:GetHostConnector() Entering")
+	defer log.Trace("ssh_host_connector_factory:GetHostConnector() Leaving")
+	sshURL, err := url.Parse(vendorConnector.Url)
+	if err != nil {
+		return nil, errors.New("ssh_host_connector_factory:GetHostConnector() error parsing the ssh URL")
+	}
+
+	taClient, err := client.NewSshTAClient(sshURL,
+		vendorConnector.Configuration.Username,
+		vendorConnector.Configuration.Password,
+		scf.sshConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "ssh_host_connector_factory:GetHostConnector() Could not create ssh Trust Agent client")
+	}
+
+	log.Debug("ssh_host_connector_factory:GetHostConnector() ssh TA client created")
+	return &IntelConnector{client: taClient, quoteRequester: scf.quoteRequester, callTimeout: scf.callTimeout}, nil
+}
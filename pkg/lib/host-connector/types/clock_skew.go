@@ -0,0 +1,60 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+package types
+
+import (
+	"time"
+)
+
+// hostTimestampResolution is the resolution of the quote timestamp of the trust agent, in seconds since the epoch
+const hostTimestampResolution = time.Second
+
+// ClockSkew is the offset of the host clock from the verifier clock, measured from the timestamp of the quote and the
+// time the verifier requested and received it. The offset is positive when the host clock is ahead of the verifier
+// clock, and is only known within the uncertainty.
+type ClockSkew struct {
+	QuoteTime         time.Time `json:"quote_time"`
+	OffsetMillis      int64     `json:"offset_millis"`
+	UncertaintyMillis int64     `json:"uncertainty_millis"`
+}
+
+// NewClockSkew measures the clock skew from the quote timestamp of the host, in seconds since the epoch, and the
+// verifier times the quote was requested and received at. The host is assumed to have taken the timestamp halfway
+// through the request.
+func NewClockSkew(quoteTimestamp int64, requestedAt, receivedAt time.Time) *ClockSkew {
+	halfRoundTrip := receivedAt.Sub(requestedAt) / 2
+	quoteTime := time.Unix(quoteTimestamp, 0)
+	offset := quoteTime.Add(hostTimestampResolution / 2).Sub(requestedAt.Add(halfRoundTrip))
+	return &ClockSkew{
+		QuoteTime:         quoteTime.UTC(),
+		OffsetMillis:      offset.Milliseconds(),
+		UncertaintyMillis: (halfRoundTrip + hostTimestampResolution/2).Milliseconds(),
+	}
+}
+
+func (skew *ClockSkew) Offset() time.Duration {
+	return time.Duration(skew.OffsetMillis) * time.Millisecond
+}
+
+func (skew *ClockSkew) Uncertainty() time.Duration {
+	return time.Duration(skew.UncertaintyMillis) * time.Millisecond
+}
+
+// MinimumSkew returns the smallest absolute skew of the host clock consistent with the measurement
+func (skew *ClockSkew) MinimumSkew() time.Duration {
+	offset := skew.Offset()
+	if offset < 0 {
+		offset = -offset
+	}
+	if offset <= skew.Uncertainty() {
+		return 0
+	}
+	return offset - skew.Uncertainty()
+}
+
+// ToVerifierTime compensates a time read from the host clock to the verifier clock
+func (skew *ClockSkew) ToVerifierTime(hostTime time.Time) time.Time {
+	return hostTime.Add(-skew.Offset())
+}
@@ -0,0 +1,34 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClockSkew(t *testing.T) {
+
+	requestedAt := time.Unix(1598255000, 0)
+	receivedAt := requestedAt.Add(2 * time.Second)
+
+	// the host clock is ten minutes ahead...
+	skew := NewClockSkew(requestedAt.Add(10*time.Minute+time.Second).Unix(), requestedAt, receivedAt)
+	assert.Equal(t, 10*time.Minute+500*time.Millisecond, skew.Offset())
+	assert.Equal(t, 1500*time.Millisecond, skew.Uncertainty())
+	assert.Equal(t, 10*time.Minute-time.Second, skew.MinimumSkew())
+	assert.True(t, requestedAt.Add(500*time.Millisecond).Equal(skew.ToVerifierTime(skew.QuoteTime)))
+
+	// ...and behind
+	skew = NewClockSkew(requestedAt.Add(-10*time.Minute).Unix(), requestedAt, receivedAt)
+	assert.Equal(t, -10*time.Minute-500*time.Millisecond, skew.Offset())
+	assert.Equal(t, 10*time.Minute-time.Second, skew.MinimumSkew())
+
+	// a skew within the uncertainty of the measurement is not significant
+	skew = NewClockSkew(requestedAt.Add(2*time.Second).Unix(), requestedAt, receivedAt)
+	assert.Equal(t, time.Duration(0), skew.MinimumSkew())
+}
@@ -0,0 +1,117 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package types
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EventLogExclusion defines events of a pcr event log that are not evaluated by the event log rules,
+// such as boot counters or policy hashes that change on every boot. An event is excluded when its label
+// and each of the info fields match the patterns of the exclusion, a field without a pattern matches any
+// value. Patterns are regular expressions that must match the whole value, or wildcards where '*' matches
+// any sequence of characters and '?' any single character when Wildcard is set.
+type EventLogExclusion struct {
+	Label    string            `json:"label,omitempty"`
+	Info     map[string]string `json:"info,omitempty"`
+	Wildcard bool              `json:"wildcard,omitempty"`
+}
+
+// EventLogExcluder holds the compiled patterns of a list of EventLogExclusion
+type EventLogExcluder struct {
+	matchers []eventLogMatcher
+}
+
+type eventLogMatcher struct {
+	label *regexp.Regexp
+	info  map[string]*regexp.Regexp
+}
+
+func compileEventLogPattern(pattern string, wildcard bool) (*regexp.Regexp, error) {
+	if wildcard {
+		pattern = regexp.QuoteMeta(pattern)
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// NewEventLogExcluder compiles the patterns of the exclusions. An error is returned when a pattern
+// is invalid or when an exclusion has no pattern, since it would exclude all the events.
+func NewEventLogExcluder(exclusions []EventLogExclusion) (*EventLogExcluder, error) {
+	excluder := EventLogExcluder{}
+	for _, exclusion := range exclusions {
+		if exclusion.Label == "" && len(exclusion.Info) == 0 {
+			return nil, errors.New("Event log exclusion must have a label or info pattern")
+		}
+
+		matcher := eventLogMatcher{}
+		if exclusion.Label != "" {
+			label, err := compileEventLogPattern(exclusion.Label, exclusion.Wildcard)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid event log exclusion label pattern '%s'", exclusion.Label)
+			}
+			matcher.label = label
+		}
+		if len(exclusion.Info) > 0 {
+			matcher.info = make(map[string]*regexp.Regexp, len(exclusion.Info))
+			for key, pattern := range exclusion.Info {
+				info, err := compileEventLogPattern(pattern, exclusion.Wildcard)
+				if err != nil {
+					return nil, errors.Wrapf(err, "Invalid event log exclusion info pattern '%s' for '%s'", pattern, key)
+				}
+				matcher.info[key] = info
+			}
+		}
+		excluder.matchers = append(excluder.matchers, matcher)
+	}
+	return &excluder, nil
+}
+
+// Excludes returns true when the event matches one of the exclusions
+func (excluder *EventLogExcluder) Excludes(eventLog EventLog) bool {
+	if excluder == nil {
+		return false
+	}
+	for _, matcher := range excluder.matchers {
+		if matcher.matches(eventLog) {
+			return true
+		}
+	}
+	return false
+}
+
+func (matcher *eventLogMatcher) matches(eventLog EventLog) bool {
+	if matcher.label != nil && !matcher.label.MatchString(eventLog.Label) {
+		return false
+	}
+	for key, pattern := range matcher.info {
+		value, ok := eventLog.Info[key]
+		if !ok || !pattern.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoveExcluded creates a new EventLogEntry without the events matching one of the exclusions.
+// Note: 'eventLogEntry' is not altered.
+func (excluder *EventLogExcluder) RemoveExcluded(eventLogEntry *EventLogEntry) *EventLogEntry {
+	result := EventLogEntry{
+		PcrIndex: eventLogEntry.PcrIndex,
+		PcrBank:  eventLogEntry.PcrBank,
+	}
+	for _, eventLog := range eventLogEntry.EventLogs {
+		if excluder.Excludes(eventLog) {
+			continue
+		}
+		result.EventLogs = append(result.EventLogs, eventLog)
+	}
+	return &result
+}
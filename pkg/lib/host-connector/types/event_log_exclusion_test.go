@@ -0,0 +1,44 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventLogExcluder(t *testing.T) {
+	excluder, err := NewEventLogExcluder([]EventLogExclusion{
+		{Label: "0x4f[0-9a-f]"},
+		{Label: "commandLine.*", Info: map[string]string{"PackageVendor": "?ntel"}, Wildcard: true},
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, excluder.Excludes(EventLog{Label: "0x4fe"}))
+	// the regular expression must match the whole label
+	assert.False(t, excluder.Excludes(EventLog{Label: "0x4fe1"}))
+	assert.True(t, excluder.Excludes(EventLog{Label: "commandLine.intel_iommu=on", Info: map[string]string{"PackageVendor": "Intel"}}))
+	// all the patterns of an exclusion must match
+	assert.False(t, excluder.Excludes(EventLog{Label: "commandLine.intel_iommu=on"}))
+	assert.False(t, excluder.Excludes(EventLog{Label: "commandLinexintel_iommu=on", Info: map[string]string{"PackageVendor": "Intel"}}))
+
+	var nilExcluder *EventLogExcluder
+	assert.False(t, nilExcluder.Excludes(EventLog{Label: "0x4fe"}))
+
+	entry := EventLogEntry{PcrIndex: PCR17, PcrBank: SHA256, EventLogs: []EventLog{{Label: "0x4fe"}, {Label: "vmlinuz"}}}
+	filtered := excluder.RemoveExcluded(&entry)
+	assert.Equal(t, []EventLog{{Label: "vmlinuz"}}, filtered.EventLogs)
+	assert.Equal(t, 2, len(entry.EventLogs))
+}
+
+func TestEventLogExcluderInvalid(t *testing.T) {
+	_, err := NewEventLogExcluder([]EventLogExclusion{{Label: "("}})
+	assert.Error(t, err)
+
+	_, err = NewEventLogExcluder([]EventLogExclusion{{Wildcard: true}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package types
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+// EventNameField is the info field the host connectors set to the type of the events they recognize
+const EventNameField = "EventName"
+
+// Validate returns an error when the event cannot be evaluated, because its type was not recognized by
+// the host connector or because its fields could not be parsed for the pcr bank of its event log
+func (eventLog *EventLog) Validate(pcrBank SHAAlgorithm) error {
+	if eventLog.Info[EventNameField] == "" {
+		return errors.New("Unrecognized event type")
+	}
+
+	if eventLog.Label == "" {
+		return errors.New("Event has no label")
+	}
+
+	digest, err := hex.DecodeString(eventLog.Value)
+	if err != nil {
+		return errors.Errorf("Event digest '%s' is not a hex string", eventLog.Value)
+	}
+
+	var digestSize int
+	switch pcrBank {
+	case SHA1:
+		digestSize = sha1.Size
+	case SHA256:
+		digestSize = sha256.Size
+	case SHA384:
+		digestSize = sha512.Size384
+	case SHA512:
+		digestSize = sha512.Size
+	default:
+		return errors.Errorf("Invalid sha algorithm '%s'", pcrBank)
+	}
+	if len(digest) != digestSize {
+		return errors.Errorf("Event digest '%s' is not a %s digest", eventLog.Value, pcrBank)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventLogValidate(t *testing.T) {
+	info := map[string]string{"EventName": "OpenSource.EventName", "ComponentName": "vmlinuz"}
+	sha256Value := "8a5b16d4c5e3c5ac2a2d6bd6b5d22e9e28fa3d8b2e3f1de9bc7a2e4b1c0f9a17"
+
+	eventLog := EventLog{Label: "vmlinuz", Value: sha256Value, Info: info}
+	assert.NoError(t, eventLog.Validate(SHA256))
+	// the digest does not match the bank
+	assert.Error(t, eventLog.Validate(SHA1))
+
+	unrecognized := EventLog{Label: "vmlinuz", Value: sha256Value, Info: map[string]string{}}
+	assert.Error(t, unrecognized.Validate(SHA256))
+
+	unlabeled := EventLog{Value: sha256Value, Info: info}
+	assert.Error(t, unlabeled.Validate(SHA256))
+
+	unparseable := EventLog{Label: "vmlinuz", Value: "not-a-digest", Info: info}
+	assert.Error(t, unparseable.Validate(SHA256))
+}
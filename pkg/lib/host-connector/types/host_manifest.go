@@ -7,11 +7,25 @@ package types
 import (
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/json"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/serialize"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 )
 
+// HostManifestSchemaVersion is the schema version of the host manifest JSON written by this version
+const HostManifestSchemaVersion = 1
+
+// hostManifestSchema upgrades the host manifests sent by the agents or stored by previous versions
+var hostManifestSchema = serialize.SchemaUpgrader{
+	Kind:           "host manifest",
+	CurrentVersion: HostManifestSchemaVersion,
+	Upgrades:       map[int]serialize.SchemaUpgrade{},
+}
+
 type HostManifest struct {
+	SchemaVersion         int              `json:"schema_version,omitempty"`
 	AIKCertificate        string           `json:"aik_certificate,omitempty"`
 	AssetTagDigest        string           `json:"asset_tag_digest,omitempty"`
 	HostInfo              taModel.HostInfo `json:"host_info"`
@@ -19,6 +33,56 @@ type HostManifest struct {
 	BindingKeyCertificate string           `json:"binding_key_certificate,omitempty"`
 	MeasurementXmls       []string         `json:"measurement_xmls,omitempty"`
 	QuoteDigest           string           `json:"quote_digest,omitempty"`
+	// QuoteNonce is the nonce the quote was requested and verified with, HostId the host record it was requested for
+	QuoteNonce string `json:"quote_nonce,omitempty"`
+	HostId     string `json:"host_id,omitempty"`
+	// QuotePcrDigest is the PCR composite digest covered by the quote signature
+	QuotePcrDigest *QuotePcrDigest `json:"quote_pcr_digest,omitempty"`
+	// ClockSkew is the skew of the host clock measured when the quote was collected
+	ClockSkew *ClockSkew `json:"clock_skew,omitempty"`
+	// ContainerImageMeasurements are the images of the containers launched by the workload agent
+	ContainerImageMeasurements []taModel.ContainerImageMeasurement `json:"container_image_measurements,omitempty"`
+	// ImaLog is the ascii IMA measurement list of the host, it is only collected with the attestation bundles
+	ImaLog string `json:"ima_log,omitempty"`
+}
+
+// QuotePcrDigest is the digest of the concatenated values of the quoted PCRs, in the order of the banks of the
+// quote's PCR selections and of the PCR indexes within a bank
+type QuotePcrDigest struct {
+	Algorithm SHAAlgorithm   `json:"algorithm"`
+	Banks     []SHAAlgorithm `json:"banks"`
+	Digest    string         `json:"digest"`
+}
+
+// unversionedHostManifest has the fields of HostManifest without its JSON encoding
+type unversionedHostManifest HostManifest
+
+// MarshalJSON writes the host manifest with the current schema version
+func (hostManifest HostManifest) MarshalJSON() ([]byte, error) {
+	versioned := unversionedHostManifest(hostManifest)
+	versioned.SchemaVersion = HostManifestSchemaVersion
+	return json.Marshal(versioned)
+}
+
+// UnmarshalJSON reads a host manifest written with any schema version, the host manifests of previous versions
+// are upgraded to the current version
+func (hostManifest *HostManifest) UnmarshalJSON(b []byte) error {
+	decoded := unversionedHostManifest{}
+	// the fields decoded before an error are kept, as they are by json.Unmarshal
+	err := json.Unmarshal(b, &decoded)
+	if err == nil && hostManifestSchema.NeedsUpgrade(decoded.SchemaVersion) {
+		var upgraded []byte
+		if upgraded, err = hostManifestSchema.Upgrade(b, decoded.SchemaVersion); err != nil {
+			return err
+		}
+		decoded = unversionedHostManifest{}
+		err = json.Unmarshal(upgraded, &decoded)
+	}
+	if decoded.SchemaVersion < HostManifestSchemaVersion {
+		decoded.SchemaVersion = HostManifestSchemaVersion
+	}
+	*hostManifest = HostManifest(decoded)
+	return err
 }
 
 func (hostManifest *HostManifest) GetAIKCertificate() (*x509.Certificate, error) {
@@ -6,15 +6,12 @@
 package types
 
 import (
-	"crypto/sha1"
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	"github.com/pkg/errors"
-	"hash"
 	"reflect"
 	"strconv"
 	"strings"
@@ -307,49 +304,40 @@ func (eventLogEntry *EventLogEntry) Subtract(eventsToSubtract *EventLogEntry) (*
 // an event log.
 func (eventLogEntry *EventLogEntry) Replay() (string, error) {
 
-	var cumulativeHash []byte
+	var digestAlgorithm crypt.DigestAlgorithm
 
 	if eventLogEntry.PcrBank == SHA1 {
-		cumulativeHash = make([]byte, sha1.Size)
+		digestAlgorithm = crypt.SHA1()
 	} else if eventLogEntry.PcrBank == SHA256 {
-		cumulativeHash = make([]byte, sha256.Size)
+		digestAlgorithm = crypt.SHA256()
 	} else if eventLogEntry.PcrBank == SHA384 {
-		cumulativeHash = make([]byte, sha512.Size384)
+		digestAlgorithm = crypt.SHA384()
 	} else if eventLogEntry.PcrBank == SHA512 {
-		cumulativeHash = make([]byte, sha512.Size)
+		digestAlgorithm = crypt.SHA512()
 	} else {
 		return "", errors.Errorf("Invalid sha algorithm '%s'", eventLogEntry.PcrBank)
 	}
 
+	// event log replay runs for every host verification, the extender reuses its buffers across the events
+	extender, err := crypt.NewExtender(digestAlgorithm)
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating PCR extender")
+	}
+
+	eventHash := make([]byte, 0, digestAlgorithm.Length)
 	for i, eventLog := range eventLogEntry.EventLogs {
-		var hash hash.Hash
-		if eventLogEntry.PcrBank == SHA1 {
-			hash = sha1.New()
-		} else if eventLogEntry.PcrBank == SHA256 {
-			hash = sha256.New()
-		} else if eventLogEntry.PcrBank == SHA384 {
-			hash = sha512.New384()
-		} else if eventLogEntry.PcrBank == SHA512 {
-			hash = sha512.New()
+		if hex.DecodedLen(len(eventLog.Value)) > cap(eventHash) {
+			eventHash = make([]byte, 0, hex.DecodedLen(len(eventLog.Value)))
 		}
-
-		eventHash, err := hex.DecodeString(eventLog.Value)
+		eventHash = eventHash[:hex.DecodedLen(len(eventLog.Value))]
+		_, err := hex.Decode(eventHash, []byte(eventLog.Value))
 		if err != nil {
 			return "", errors.Wrapf(err, "Failed to decode event log %d using hex string '%s'", i, eventLog.Value)
 		}
-
-		_, err = hash.Write(cumulativeHash)
-		if err != nil {
-			return "", errors.Wrap(err, "Error writing cumulative hash")
-		}
-		_, err = hash.Write(eventHash)
-		if err != nil {
-			return "", errors.Wrap(err, "Error writing event hash")
-		}
-		cumulativeHash = hash.Sum(nil)
+		extender.Extend(eventHash)
 	}
 
-	cumulativeHashString := hex.EncodeToString(cumulativeHash)
+	cumulativeHashString := hex.EncodeToString(extender.Value())
 	return cumulativeHashString, nil
 }
 
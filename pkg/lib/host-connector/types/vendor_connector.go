@@ -13,5 +13,7 @@ type VendorConnector struct {
 		Hostname string
 		Username string
 		Password string
+		// BmcUrl is the Redfish service of the BMC of the host, its platform inventory is added to the host info
+		BmcUrl string
 	}
 }
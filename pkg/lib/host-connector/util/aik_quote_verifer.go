@@ -8,21 +8,19 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/sha1"
-	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
-	"encoding/binary"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt/tpm2"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/pkg/errors"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -52,196 +50,117 @@ const (
 var PCR_NUMBER_PATTERN = regexp.MustCompile("[0-9]|[0-1][0-9]|2[0-3]")
 var PCR_VALUE_PATTERN = regexp.MustCompile("[0-9a-fA-F]+")
 
-type pcrSelection struct {
-	size        int
-	hashAlg     uint16
-	pcrSelected []byte
-}
-
-func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []byte, tpmQuoteInBytes []byte,
+// VerifyQuoteAndGetPCRManifest verifies the quote and returns the PCR manifest with the PCR values of the quote.
+// When the binary TCG event log of the host is given, it is replayed against the quoted PCR values of all banks
+// and its events are added to the PCRs the measure log does not have events for.
+func VerifyQuoteAndGetPCRManifest(decodedEventLog string, binaryEventLog []byte, verificationNonce []byte, tpmQuoteInBytes []byte,
 	aikCertificate *x509.Certificate) (types.PcrManifest, []byte, error) {
 
 	log.Trace("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Entering")
 	defer log.Trace("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Leaving")
-	hashAlgPcrSizeMap := make(map[int]int)
-	hashAlgPcrSizeMap[TPM_API_ALG_ID_SHA1] = SHA1_SIZE
-	hashAlgPcrSizeMap[TPM_API_ALG_ID_SHA256] = SHA256_SIZE
-	hashAlgPcrSizeMap[TPM_API_ALG_ID_SHA384] = SHA384_SIZE
-	hashAlgPcrSizeMap[TPM_API_ALG_ID_SHA512] = SHA512_SIZE
-	hashAlgPcrSizeMap[TPM_API_ALG_ID_SM3_SHA256] = SHA256_SIZE
-
-	//Get the length of quote
-	index := 0
-	quoteInfoLen := binary.BigEndian.Uint16(tpmQuoteInBytes[0:2])
-
-	index += 2
-	quoteInfo := tpmQuoteInBytes[index : index+int(quoteInfoLen)]
 
-	index += 6
-	tpm2bNameSize := binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
+	quote, err := tpm2.ParseQuote(tpmQuoteInBytes)
+	if err != nil {
+		return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
+			"AIK Quote verification failed, Error parsing quote")
+	}
+	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
+		"Received nonce is : %s", base64.StdEncoding.EncodeToString(quote.Attest.ExtraData))
+	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() PCR bank count is : %v",
+		len(quote.Attest.Quote.PCRSelections))
 
-	index += 2 + int(tpm2bNameSize)
-	tpm2bDataSize := binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
+	pcrs, err := quote.Verify(aikCertificate.PublicKey, verificationNonce)
+	if err != nil {
+		log.WithError(err).Error("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() AIK Quote verification failed")
+		return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
+			"AIK Quote verification failed")
+	}
+	log.Info("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest()  Successfully verified AIK Quote")
 
-	index += 2
-	tpm2bData := tpmQuoteInBytes[index : index+int(tpm2bDataSize)]
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
-		"Received nonce is : %s", base64.StdEncoding.EncodeToString(tpm2bData))
-	if !bytes.EqualFold(tpm2bData, verificationNonce) {
-		return types.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Challenge " +
-			"and received nonce does not match")
+	var tcgEventLog *TcgEventLog
+	if len(binaryEventLog) > 0 {
+		tcgEventLog, err = ParseTcgEventLog(binaryEventLog)
+		if err != nil {
+			return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
+				"Error parsing TCG event log")
+		}
+		err = tcgEventLog.VerifyPcrs(pcrs)
+		if err != nil {
+			log.WithError(err).Error("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TCG event log replay failed")
+			return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
+				"TCG event log replay failed")
+		}
+		log.Info("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Successfully replayed TCG event log")
 	}
 
-	index += int(tpm2bDataSize)
-	/* Parse quote file
-	 * The quote result is constructed as follows for now
-	 *
-	 * part1: pcr values (0-23), sha1 pcr bank. so the length is 20*24=480
-	 * part2: the quoted information: TPM2B_ATTEST
-	 * part3: the signature: TPMT_SIGNATURE
-	 */
-	index += 17 // skip over the TPMS_CLOCKINFO structure - Not interested
-	index += 8  // skip over the firmware info - Not interested
-
-	pcrBankCount := binary.BigEndian.Uint32(tpmQuoteInBytes[index : index+4])
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() PCR bank count is : %v", pcrBankCount)
-	if pcrBankCount > MAX_PCR_BANKS {
-		return types.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() AIK Quote " +
-			"verification failed, Number of PCR selection array in " + "the quote is greater than 5. PCRBankCount " +
-			": " + fmt.Sprint(pcrBankCount))
+	var buffer bytes.Buffer
+	for _, pcr := range pcrs {
+		//Ignore the pcr banks other than SHA1 and SHA256
+		if pcr.HashAlg == tpm2.AlgSHA1 {
+			buffer.WriteString(fmt.Sprintf("%2d %x\n", pcr.Index, pcr.Value))
+		} else if pcr.HashAlg == tpm2.AlgSHA256 {
+			buffer.WriteString(fmt.Sprintf("%2d_SHA256 %x\n", pcr.Index, pcr.Value))
+		}
 	}
+	pcrsDigest := tpm2.ComputePCRDigest(crypto.SHA256, pcrs)
 
-	index += 4
-	pcrSelection := make([]pcrSelection, pcrBankCount)
-	for i := 0; i < int(pcrBankCount); i++ {
-		pcrSelection[i].hashAlg = binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
-		index += 2
-		pcrSelection[i].size = int(tpmQuoteInBytes[index])
-		index += 1
-		pcrSelection[i].pcrSelected = tpmQuoteInBytes[index : index+pcrSelection[i].size]
-		index += pcrSelection[i].size
+	pcrManifest, err := createPCRManifest(strings.Split(buffer.String(), "\n"), decodedEventLog)
+	if err != nil {
+		return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Error "+
+			"retrieving PCR manifest from quote")
 	}
+	if tcgEventLog != nil {
+		addTcgEventLogEntries(tcgEventLog, &pcrManifest.PcrEventLogMap)
+	}
+	log.Info("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Successfully created PCR manifest")
+	return pcrManifest, pcrsDigest, nil
+}
 
-	tpm2bDigestSize := binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() tpm2bDigestSize is : %v", tpm2bDigestSize)
-	index += 2
-	tpm2bDigest := tpmQuoteInBytes[index : index+int(tpm2bDigestSize)]
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest()  PCR manifest digest: %v", tpm2bDigest)
-
-	/* PART 2: TPMT_SIGNATURE
-	   Skip the first 2 bytes having the quote info size and remaining bytes, which includes signer info, nonce, pcr selection
-	   and extra data. So jump to TPMT_SIGNATURE
-	*/
-
-	tpmtSigIndex := 2 + quoteInfoLen
-	tpmtSig := tpmQuoteInBytes[tpmtSigIndex:]
-	var pos uint16 = 0
-	/* sigAlg -indicates the signature algorithm TPMI_SIG_ALG_SCHEME
-	 * for now, it is TPM_ALG_RSASSA with value 0x0014
-	 */
-	tpmtSignatureAlg := binary.BigEndian.Uint16(tpmtSig[0:2])
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TPM signature Algorithm: %v", tpmtSignatureAlg)
-	/* hashAlg used by the signature algorithm indicated above
-	 * TPM_ALG_HASH
-	 * for TPM_ALG_RSASSA, the default hash algorithm is TPM_ALG_SHA256 with value 0x000b
-	 */
-	pos += 2
-	tpmtSignatureHashAlg := binary.BigEndian.Uint16(tpmtSig[pos : pos+2])
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TPM signature Hash Algorithm: %v", tpmtSignatureHashAlg)
-
-	pos += 2
-	tpmtSignatureSize := binary.BigEndian.Uint16(tpmtSig[pos : pos+2])
-
-	pos += 2
-	tpmtSignature := tpmtSig[pos : pos+tpmtSignatureSize]
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TPMT signature : %v", tpmtSignature)
-
-	hash := sha256.New()
-	_, err := hash.Write(quoteInfo)
+// GetQuotePcrDigest returns the PCR composite digest signed in the quote, so that the PCR values of the manifest
+// can be checked against it after the quote was verified
+func GetQuotePcrDigest(tpmQuoteInBytes []byte) (*types.QuotePcrDigest, error) {
+	log.Trace("util/aik_quote_verifier:GetQuotePcrDigest() Entering")
+	defer log.Trace("util/aik_quote_verifier:GetQuotePcrDigest() Leaving")
+
+	quote, err := tpm2.ParseQuote(tpmQuoteInBytes)
 	if err != nil {
-		return types.PcrManifest{}, nil, errors.Wrap(err, "Error writing quote information")
+		return nil, errors.Wrap(err, "util/aik_quote_verifier:GetQuotePcrDigest() Error parsing quote")
 	}
-	pcrsDigest := hash.Sum(nil)
-	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Quote signature : %v", pcrsDigest)
-	err = rsa.VerifyPKCS1v15(aikCertificate.PublicKey.(*rsa.PublicKey), crypto.SHA256, pcrsDigest, tpmtSignature)
+	algorithm, err := getSHAAlgorithm(quote.Signature.HashAlg)
 	if err != nil {
-		return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
-			"Error verifying pcrs digest")
+		return nil, errors.Wrap(err, "util/aik_quote_verifier:GetQuotePcrDigest() Unsupported quote hash algorithm")
 	}
 
-	pos += tpmtSignatureSize
-	pcrLen := uint16(len(tpmQuoteInBytes)) - (pos + tpmtSigIndex)
-	if pcrLen <= 0 {
-		return types.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() " +
-			"AIK Quote verification failed, No PCR values included in quote")
+	quotePcrDigest := types.QuotePcrDigest{
+		Algorithm: algorithm,
+		Banks:     []types.SHAAlgorithm{},
+		Digest:    hex.EncodeToString(quote.Attest.Quote.PCRDigest),
 	}
-	pcrs := tpmtSig[pos : pos+pcrLen]
-	pcrConcatLen := SHA256_SIZE * 24 * 3
-	pcrPos := 0
-	count := 0
-	var pcrConcat []byte
-	var pcrSize int
-	var buffer bytes.Buffer
-
-	for j := 0; j < int(pcrBankCount); j++ {
-		hashAlg := pcrSelection[j].hashAlg
-		if value, ok := hashAlgPcrSizeMap[int(hashAlg)]; ok {
-			pcrSize = value
-		} else {
-			secLog.Error("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
-				"AIK Quote verification failed, Unsupported PCR banks, hash algorithm id : ", hashAlg)
-			return types.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest()" +
-				"AIK Quote verification failed, Unsupported PCR banks, hash algorithm id : %s" + strconv.Itoa(int(hashAlg)))
+	for _, selection := range quote.Attest.Quote.PCRSelections {
+		if len(selection.PCRs) == 0 {
+			continue
 		}
-		/* For each pcr bank iterate through each pcr selection array.
-		   Here pcrSelection.pcrSelected byte array contains 3 elements, where each bit of this element corresponds to pcr entry.
-		   8 bits pcrSelection.pcrSelected value corresponds to 8 PCR entries.
-		*/
-		for pcr := 0; pcr < 8*pcrSelection[j].size; pcr++ {
-			pcrSelected := pcrSelection[j].pcrSelected
-			selected := pcrSelected[pcr/8] & (1 << (uint16(pcr) % 8))
-			if selected > 0 {
-				if (pcrPos + pcrSize) < pcrConcatLen {
-					pcrConcat = append(pcrConcat, pcrs[pcrPos:pcrPos+pcrSize]...)
-				}
-				if hashAlg == TPM_API_ALG_ID_SHA1 {
-					buffer.WriteString(fmt.Sprintf("%2d ", pcr))
-				} else if hashAlg == TPM_API_ALG_ID_SHA256 {
-					buffer.WriteString(fmt.Sprintf("%2d_SHA256 ", pcr))
-				}
-				//Ignore the pcr banks other than SHA1 and SHA256
-				if hashAlg == TPM_API_ALG_ID_SHA1 || hashAlg == TPM_API_ALG_ID_SHA256 {
-					for i := 0; i < pcrSize; i++ {
-						buffer.WriteString(fmt.Sprintf("%02x", pcrs[pcrPos+i]))
-					}
-				}
-				buffer.WriteString("\n")
-				count++
-				pcrPos += pcrSize
-			}
+		bank, err := getSHAAlgorithm(selection.HashAlg)
+		if err != nil {
+			return nil, errors.Wrap(err, "util/aik_quote_verifier:GetQuotePcrDigest() Unsupported PCR bank")
 		}
+		quotePcrDigest.Banks = append(quotePcrDigest.Banks, bank)
 	}
-	hash = sha256.New()
-	_, err = hash.Write(pcrConcat)
-	if err != nil {
-		return types.PcrManifest{}, nil, errors.Wrap(err, "Error writing pcr hash")
-	}
-	pcrsDigest = hash.Sum(nil)
+	return &quotePcrDigest, nil
+}
 
-	if !bytes.EqualFold(pcrsDigest, tpm2bDigest) {
-		log.Error("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() AIK Quote verification failed, Digest " +
-			"of Concatenated PCR values does not match with PCR digest in the quote")
-		return types.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() AIK Quote " +
-			"verification failed, Digest of Concatenated PCR values does not match with PCR digest in the quote")
+func getSHAAlgorithm(hashAlg uint16) (types.SHAAlgorithm, error) {
+	switch hashAlg {
+	case tpm2.AlgSHA1:
+		return types.SHA1, nil
+	case tpm2.AlgSHA256:
+		return types.SHA256, nil
+	case tpm2.AlgSHA384:
+		return types.SHA384, nil
+	case tpm2.AlgSHA512:
+		return types.SHA512, nil
 	}
-	log.Info("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest()  Successfully verified AIK Quote")
-	pcrManifest, err := createPCRManifest(strings.Split(buffer.String(), "\n"), decodedEventLog)
-	if err != nil {
-		return types.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Error "+
-			"retrieving PCR manifest from quote")
-	}
-	log.Info("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Successfully created PCR manifest")
-	return pcrManifest, pcrsDigest, nil
+	return types.UNKNOWN, errors.Errorf("TPM hash algorithm 0x%04x has no SHA algorithm", hashAlg)
 }
 
 func GetVerificationNonce(nonce []byte, quoteResponse taModel.TpmQuoteResponse) (string, error) {
@@ -365,6 +284,40 @@ func getPcrEventLog(eventLog string) (types.PcrEventLogMap, error) {
 	return pcrEventLogMap, nil
 }
 
+// addTcgEventLogEntries adds the events of the TCG event log to the PCRs that have no events in the measure log
+func addTcgEventLogEntries(tcgEventLog *TcgEventLog, eventLogMap *types.PcrEventLogMap) {
+	log.Trace("util/aik_quote_verifier:addTcgEventLogEntries() Entering")
+	defer log.Trace("util/aik_quote_verifier:addTcgEventLogEntries() Leaving")
+
+	for _, entry := range tcgEventLog.EventLogs {
+		var eventLogEntries *[]types.EventLogEntry
+		var digestType string
+		switch entry.PcrBank {
+		case types.SHA1:
+			eventLogEntries, digestType = &eventLogMap.Sha1EventLogs, EVENT_LOG_DIGEST_SHA1
+		case types.SHA256:
+			eventLogEntries, digestType = &eventLogMap.Sha256EventLogs, EVENT_LOG_DIGEST_SHA256
+		default:
+			continue
+		}
+
+		pcrFound := false
+		for _, existingEntry := range *eventLogEntries {
+			if existingEntry.PcrIndex == entry.PcrIndex {
+				pcrFound = true
+				break
+			}
+		}
+		if pcrFound {
+			continue
+		}
+		for i := range entry.EventLogs {
+			entry.EventLogs[i].DigestType = digestType
+		}
+		*eventLogEntries = append(*eventLogEntries, entry)
+	}
+}
+
 func addPcrEntry(module *types.Module, eventLogMap *types.PcrEventLogMap) {
 	log.Trace("util/aik_quote_verifier:addPcrEntry() Entering")
 	defer log.Trace("util/aik_quote_verifier:addPcrEntry() Leaving")
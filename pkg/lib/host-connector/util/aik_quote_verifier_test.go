@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/pem"
 	"encoding/xml"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
@@ -40,7 +41,7 @@ func TestVerifyQuoteAndGetPCRManifest(t *testing.T) {
 
 	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
 
-	_, _, err = VerifyQuoteAndGetPCRManifest(string(decodedEventLogBytes), verificationNonceInBytes, tpmQuoteInBytes, aikCertificate)
+	_, _, err = VerifyQuoteAndGetPCRManifest(string(decodedEventLogBytes), nil, verificationNonceInBytes, tpmQuoteInBytes, aikCertificate)
 	assert.NoError(t, err)
 }
 
@@ -68,7 +69,7 @@ func TestVerifyQuoteAndGetPCRManifestInvalidNonce(t *testing.T) {
 
 	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
 
-	_, _, err = VerifyQuoteAndGetPCRManifest(string(decodedEventLogBytes), verificationNonceInBytes, tpmQuoteInBytes, aikCertificate)
+	_, _, err = VerifyQuoteAndGetPCRManifest(string(decodedEventLogBytes), nil, verificationNonceInBytes, tpmQuoteInBytes, aikCertificate)
 	assert.Error(t, err)
 }
 
@@ -91,3 +92,23 @@ func TestGetVerificationNonceAssetTagProvisioned(t *testing.T) {
 	_, err = GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
 	assert.NoError(t, err)
 }
+
+func TestGetQuotePcrDigest(t *testing.T) {
+	var tpmQuoteResponse taModel.TpmQuoteResponse
+	b, err := ioutil.ReadFile("../test/sample_tpm_quote.xml")
+	assert.NoError(t, err)
+	err = xml.Unmarshal(b, &tpmQuoteResponse)
+	assert.NoError(t, err)
+
+	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
+	assert.NoError(t, err)
+
+	quotePcrDigest, err := GetQuotePcrDigest(tpmQuoteInBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, types.SHA256, quotePcrDigest.Algorithm)
+	assert.NotEmpty(t, quotePcrDigest.Banks)
+	assert.NotEmpty(t, quotePcrDigest.Digest)
+
+	_, err = GetQuotePcrDigest(tpmQuoteInBytes[:32])
+	assert.Error(t, err)
+}
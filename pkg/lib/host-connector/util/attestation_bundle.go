@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+)
+
+// GetAttestationBundleNonce returns the base64 encoded nonce the quote of an attestation bundle is expected to be
+// extended with. It is the SHA256 digest of the verification nonce followed by the SHA256 digests of the host info,
+// of the IMA log, of the event log and of the binary event log of the bundle, so that none of them can be replaced
+// after the quote is taken.
+func GetAttestationBundleNonce(verificationNonce string, bundle *taModel.AttestationBundle) (string, error) {
+	log.Trace("util/attestation_bundle:GetAttestationBundleNonce() Entering")
+	defer log.Trace("util/attestation_bundle:GetAttestationBundleNonce() Leaving")
+
+	verificationNonceBytes, err := base64.StdEncoding.DecodeString(verificationNonce)
+	if err != nil {
+		return "", errors.Wrap(err, "util/attestation_bundle:GetAttestationBundleNonce() Error decoding the verification nonce")
+	}
+
+	hash := sha256.New()
+	hash.Write(verificationNonceBytes)
+	for _, field := range []struct{ name, value string }{
+		{"host info", bundle.HostInfo},
+		{"IMA log", bundle.ImaLog},
+		{"event log", bundle.Quote.EventLog},
+		{"binary event log", bundle.Quote.BinaryEventLog},
+	} {
+		fieldBytes, err := base64.StdEncoding.DecodeString(field.value)
+		if err != nil {
+			return "", errors.Wrapf(err, "util/attestation_bundle:GetAttestationBundleNonce() Error decoding the %s "+
+				"of the attestation bundle", field.name)
+		}
+		digest := sha256.Sum256(fieldBytes)
+		hash.Write(digest[:])
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"encoding/base64"
+	"testing"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAttestationBundleNonce(t *testing.T) {
+	verificationNonce := base64.StdEncoding.EncodeToString([]byte("verification nonce"))
+	bundle := taModel.AttestationBundle{
+		HostInfo: base64.StdEncoding.EncodeToString([]byte(`{"hardware_uuid": "8032632b-8fa4-e811-906e-00163566263e"}`)),
+		ImaLog:   base64.StdEncoding.EncodeToString([]byte("10 9797edf94e29f3bf37fd8401239ca7e3e0d6dc5c ima-ng sha1:0 boot_aggregate")),
+	}
+	bundle.Quote.EventLog = base64.StdEncoding.EncodeToString([]byte("<measureLog></measureLog>"))
+
+	bundleNonce, err := GetAttestationBundleNonce(verificationNonce, &bundle)
+	assert.NoError(t, err)
+	bundleNonceBytes, err := base64.StdEncoding.DecodeString(bundleNonce)
+	assert.NoError(t, err)
+	assert.Len(t, bundleNonceBytes, 32)
+
+	// the nonce changes with any of the logs of the bundle
+	bundle.ImaLog = base64.StdEncoding.EncodeToString([]byte("10 0000000000000000000000000000000000000000 ima-ng sha1:0 boot_aggregate"))
+	otherNonce, err := GetAttestationBundleNonce(verificationNonce, &bundle)
+	assert.NoError(t, err)
+	assert.NotEqual(t, bundleNonce, otherNonce)
+
+	bundle.Quote.BinaryEventLog = "not base64"
+	_, err = GetAttestationBundleNonce(verificationNonce, &bundle)
+	assert.Error(t, err)
+}
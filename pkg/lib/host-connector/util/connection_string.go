@@ -6,7 +6,6 @@ package util
 
 import (
 	"fmt"
-	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/pkg/errors"
@@ -24,13 +23,13 @@ func GetConnectorDetails(connectionString string) (types.VendorConnector, error)
 	var vendorName string
 
 	// use a regex to eliminate all invalid connection strings
-	if err := validation.ValidateConnectionString(connectionString); err != nil {
+	if err := validateConnectionString(connectionString); err != nil {
 		return types.VendorConnector{}, err
 	}
 
 	vendor := GetVendorPrefix(connectionString)
 	if vendor == constants.VendorUnknown {
-		if connectionString != "" && (!strings.Contains(connectionString, ":") || strings.ToLower(connectionString[:strings.Index(connectionString, ":")]) != "https") {
+		if connectionString != "" && (!strings.Contains(connectionString, ":") || !isSupportedScheme(connectionString[:strings.Index(connectionString, ":")])) {
 			return types.VendorConnector{}, errors.New("Vendor provided at URL prefix is not supported")
 		}
 		vendor = GuessVendorFromURL(connectionString)
@@ -45,10 +44,29 @@ func GetConnectorDetails(connectionString string) (types.VendorConnector, error)
 	if _, err := url.Parse(vendorConnector.Url); err != nil {
 		return types.VendorConnector{}, err
 	}
+	vendorConnector.Configuration.BmcUrl = getConnectionOption(vendorURL, "bmc")
+	if vendorConnector.Configuration.BmcUrl != "" {
+		bmcURL, err := url.Parse(vendorConnector.Configuration.BmcUrl)
+		if err != nil || bmcURL.Scheme != "https" || bmcURL.Host == "" {
+			return types.VendorConnector{}, errors.New("The BMC URL of the connection string must be an https URL")
+		}
+	}
 	vendorConnector.Vendor = vendor
 	return vendorConnector, nil
 }
 
+// isSupportedScheme returns true for the schemes of the connection strings without vendor, the hosts reached over ssh
+// run the trust agent and the registered schemes are handled by the connector plugins
+func isSupportedScheme(scheme string) bool {
+	scheme = strings.ToLower(scheme)
+	return scheme == "https" || scheme == "ssh" || IsRegisteredScheme(scheme)
+}
+
+// IsSshURL returns true when the host of the connector URL is reached over ssh
+func IsSshURL(vendorURL string) bool {
+	return strings.HasPrefix(strings.ToLower(vendorURL), "ssh://")
+}
+
 func GuessVendorFromURL(connectionString string) constants.Vendor {
 
 	log.Trace("util/connection_string:GuessVendorFromURL() Entering")
@@ -101,17 +119,29 @@ func parseCredentials(credentials string) (string, string, string) {
 	var password string
 	var hostname string
 	for _, credentials := range splitCredentials {
-		if strings.Contains(credentials, "u=") {
+		if strings.HasPrefix(credentials, "u=") {
 			username = strings.Split(credentials, "=")[1]
-		} else if strings.Contains(credentials, "p=") {
+		} else if strings.HasPrefix(credentials, "p=") {
 			password = strings.Split(credentials, "=")[1]
-		} else if strings.Contains(credentials, "h=") {
+		} else if strings.HasPrefix(credentials, "h=") {
 			hostname = strings.Split(credentials, "=")[1]
 		}
 	}
 	return username, password, hostname
 }
 
+// getConnectionOption returns the value of an option of the connection string, e.g. the BMC URL of the
+// bmc=https://bmc.ip.com option, empty when the connection string does not have the option
+func getConnectionOption(vendorURL, name string) string {
+	options := strings.Split(vendorURL, ";")
+	for _, option := range options[1:] {
+		if strings.HasPrefix(option, name+"=") {
+			return option[len(name)+1:]
+		}
+	}
+	return ""
+}
+
 // getHostIP verifies that the hostname provided in the connection string can be resolved to an IPV4 address
 // since this will be required for the nonce verification
 func GetHostIP(hostRef string) (string, error) {
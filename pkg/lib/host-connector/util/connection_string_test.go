@@ -17,6 +17,7 @@ func TestGetConnectorDetails(t *testing.T) {
 	sampleUrl3 := "vmware:https://vsphere.com:443/sdk;h=hostName;u=admin.local;p=password"
 	sampleUrl4 := "https://vsphere.com:443/sdk;h=hostName;u=admin.local;p=password"
 	sampleUrl5 := "microsoft:https://microsoft.com:1443;u=admin.local;p=password"
+	sampleUrl6 := "ssh://ta.ip.com:22;u=admin;p=password"
 
 	invalidUrl := "https:// abcde"
 
@@ -40,10 +41,33 @@ func TestGetConnectorDetails(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, constants.VendorMicrosoft, connectorDetails.Vendor)
 
+	connectorDetails, err = GetConnectorDetails(sampleUrl6)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.VendorIntel, connectorDetails.Vendor)
+	assert.Equal(t, "ssh://ta.ip.com:22", connectorDetails.Url)
+	assert.True(t, IsSshURL(connectorDetails.Url))
+
 	connectorDetails, err = GetConnectorDetails(invalidUrl)
 	assert.Error(t, err)
 }
 
+func TestGetConnectorDetailsWithBmc(t *testing.T) {
+	connectorDetails, err := GetConnectorDetails("intel:https://ta.ip.com:1443;bmc=https://bmc.ip.com:8443;u=admin;p=password")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ta.ip.com:1443", connectorDetails.Url)
+	assert.Equal(t, "https://bmc.ip.com:8443", connectorDetails.Configuration.BmcUrl)
+	assert.Equal(t, "admin", connectorDetails.Configuration.Username)
+	assert.Equal(t, "password", connectorDetails.Configuration.Password)
+
+	// the connection strings are stored without the credentials
+	connectorDetails, err = GetConnectorDetails("https://ta.ip.com:1443;bmc=https://bmc.ip.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://bmc.ip.com", connectorDetails.Configuration.BmcUrl)
+
+	_, err = GetConnectorDetails("https://ta.ip.com:1443;bmc=http://bmc.ip.com;u=admin;p=password")
+	assert.Error(t, err)
+}
+
 func TestParseConnectionString(t *testing.T) {
 	sampleUrl1 := "vmware:https://vsphere.com:portNo/sdk;h=hostName;u=admin.local;p=password"
 
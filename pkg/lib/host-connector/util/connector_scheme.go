@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/constants"
+	"github.com/pkg/errors"
+)
+
+var schemeReg = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// registeredSchemes are the URL schemes of the connection strings added by the connector plugins
+var registeredSchemes = struct {
+	sync.RWMutex
+	schemes map[string]bool
+}{schemes: map[string]bool{}}
+
+// RegisterScheme makes the connection strings with the URL scheme valid, the scheme cannot be one of the schemes
+// supported by the built-in connectors
+func RegisterScheme(scheme string) error {
+	log.Trace("util/connector_scheme:RegisterScheme() Entering")
+	defer log.Trace("util/connector_scheme:RegisterScheme() Leaving")
+
+	scheme = strings.ToLower(scheme)
+	if !schemeReg.MatchString(scheme) {
+		return errors.Errorf("Invalid connection string scheme '%s'", scheme)
+	}
+	if scheme == "https" || scheme == "ssh" {
+		return errors.Errorf("The connection string scheme '%s' is supported by the built-in connectors", scheme)
+	}
+
+	registeredSchemes.Lock()
+	defer registeredSchemes.Unlock()
+	if registeredSchemes.schemes[scheme] {
+		return errors.Errorf("The connection string scheme '%s' is already registered", scheme)
+	}
+	registeredSchemes.schemes[scheme] = true
+	return nil
+}
+
+// IsRegisteredScheme returns true when the URL scheme was added with RegisterScheme
+func IsRegisteredScheme(scheme string) bool {
+	registeredSchemes.RLock()
+	defer registeredSchemes.RUnlock()
+	return registeredSchemes.schemes[strings.ToLower(scheme)]
+}
+
+// GetURLScheme returns the lower case URL scheme of the connector URL, empty when the URL has no scheme
+func GetURLScheme(vendorURL string) string {
+	schemeEndIndex := strings.Index(vendorURL, "://")
+	if schemeEndIndex == -1 {
+		return ""
+	}
+	return strings.ToLower(vendorURL[:schemeEndIndex])
+}
+
+// validateConnectionString validates the connection strings of the registered schemes with the same rules as the
+// https connection strings
+func validateConnectionString(connectionString string) error {
+	vendorURL := connectionString
+	if vendor := GetVendorPrefix(connectionString); vendor != constants.VendorUnknown {
+		vendorURL = connectionString[len(vendor.String())+1:]
+	}
+	if scheme := GetURLScheme(vendorURL); IsRegisteredScheme(scheme) {
+		connectionString = connectionString[:len(connectionString)-len(vendorURL)] + "https" + vendorURL[len(scheme):]
+	}
+	return validation.ValidateConnectionString(connectionString)
+}
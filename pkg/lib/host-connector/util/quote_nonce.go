@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+// QuoteNonceRandomSize is the size of the random part of the nonces the quotes are requested with
+const QuoteNonceRandomSize = 20
+
+// GetQuoteNonceBinding returns the digest of the identity of the verifier requesting the quote and of the host
+// record the quote is requested for. Quote nonces ending with the binding can't be relayed to satisfy the challenge
+// of another verifier or for another host.
+func GetQuoteNonceBinding(requesterIdentity, hostId string) []byte {
+	digest := sha256.Sum256([]byte(requesterIdentity + "\n" + hostId))
+	return digest[:]
+}
+
+// GenerateBoundNonce generates a base64 encoded nonce of nonceSize random bytes followed by the binding
+func GenerateBoundNonce(nonceSize int, binding []byte) (string, error) {
+	log.Trace("util/quote_nonce:GenerateBoundNonce() Entering")
+	defer log.Trace("util/quote_nonce:GenerateBoundNonce() Leaving")
+
+	nonce, err := GenerateNonce(nonceSize)
+	if err != nil {
+		return "", err
+	}
+	randomBytes, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return "", errors.Wrap(err, "util/quote_nonce:GenerateBoundNonce() Error decoding the generated nonce")
+	}
+	return base64.StdEncoding.EncodeToString(append(randomBytes, binding...)), nil
+}
+
+// IsQuoteNonceBound checks that the base64 encoded nonce has random bytes followed by the binding
+func IsQuoteNonceBound(nonce string, binding []byte) bool {
+	nonceBytes, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil || len(nonceBytes) <= len(binding) {
+		return false
+	}
+	return bytes.Equal(nonceBytes[len(nonceBytes)-len(binding):], binding)
+}
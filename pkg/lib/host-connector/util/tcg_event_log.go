@@ -0,0 +1,295 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt/tpm2"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	evNoAction uint32 = 0x00000003
+
+	specIdEventSignature     = "Spec ID Event03\x00"
+	startupLocalitySignature = "StartupLocality\x00"
+
+	// maxEventSize bounds the event data of a single event of the event log
+	maxEventSize = 1 << 20
+)
+
+// tcgEventTypes are the names of the event types defined by the TCG PC Client Platform Firmware Profile
+var tcgEventTypes = map[uint32]string{
+	0x00000000: "EV_PREBOOT_CERT",
+	0x00000001: "EV_POST_CODE",
+	0x00000003: "EV_NO_ACTION",
+	0x00000004: "EV_SEPARATOR",
+	0x00000005: "EV_ACTION",
+	0x00000006: "EV_EVENT_TAG",
+	0x00000007: "EV_S_CRTM_CONTENTS",
+	0x00000008: "EV_S_CRTM_VERSION",
+	0x00000009: "EV_CPU_MICROCODE",
+	0x0000000A: "EV_PLATFORM_CONFIG_FLAGS",
+	0x0000000B: "EV_TABLE_OF_DEVICES",
+	0x0000000C: "EV_COMPACT_HASH",
+	0x0000000D: "EV_IPL",
+	0x0000000E: "EV_IPL_PARTITION_DATA",
+	0x0000000F: "EV_NONHOST_CODE",
+	0x00000010: "EV_NONHOST_CONFIG",
+	0x00000011: "EV_NONHOST_INFO",
+	0x00000012: "EV_OMIT_BOOT_DEVICE_EVENTS",
+	0x80000001: "EV_EFI_VARIABLE_DRIVER_CONFIG",
+	0x80000002: "EV_EFI_VARIABLE_BOOT",
+	0x80000003: "EV_EFI_BOOT_SERVICES_APPLICATION",
+	0x80000004: "EV_EFI_BOOT_SERVICES_DRIVER",
+	0x80000005: "EV_EFI_RUNTIME_SERVICES_DRIVER",
+	0x80000006: "EV_EFI_GPT_EVENT",
+	0x80000007: "EV_EFI_ACTION",
+	0x80000008: "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+	0x80000009: "EV_EFI_HANDOFF_TABLES",
+	0x8000000A: "EV_EFI_PLATFORM_FIRMWARE_BLOB2",
+	0x8000000B: "EV_EFI_HANDOFF_TABLES2",
+	0x800000E0: "EV_EFI_VARIABLE_AUTHORITY",
+}
+
+// tcgHashAlgorithms are the hash functions extending the PCR banks
+var tcgHashAlgorithms = map[types.SHAAlgorithm]crypto.Hash{
+	types.SHA1:   crypto.SHA1,
+	types.SHA256: crypto.SHA256,
+	types.SHA384: crypto.SHA384,
+	types.SHA512: crypto.SHA512,
+}
+
+// TcgEventLog is a TCG crypto agile (TCG_PCR_EVENT2) event log of a host, such as the binary_bios_measurements
+// of the kernel, along with the PCR values replayed from it
+type TcgEventLog struct {
+	// EventLogs are the events extended into each PCR bank and index, in the order they are extended
+	EventLogs []types.EventLogEntry
+	// Pcrs are the hex encoded PCR values of each PCR bank and index after replaying the events
+	Pcrs map[types.SHAAlgorithm]map[types.PcrIndex]string
+	// StartupLocality is the locality the TPM was started from, which is the initial value of PCR0
+	StartupLocality uint8
+}
+
+// ParseTcgEventLog parses the crypto agile event log and replays the events of all PCR banks in a single pass.
+// EV_NO_ACTION events are not extended and skipped.
+func ParseTcgEventLog(eventLog []byte) (*TcgEventLog, error) {
+	log.Trace("util/tcg_event_log:ParseTcgEventLog() Entering")
+	defer log.Trace("util/tcg_event_log:ParseTcgEventLog() Leaving")
+
+	reader := bytes.NewReader(eventLog)
+
+	// the first event is a TCG_PCClientPCREvent with the SHA1 log format, carrying the Spec ID Event
+	var header struct {
+		PcrIndex  uint32
+		EventType uint32
+		Digest    [20]byte
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return nil, errors.Wrap(err, "Failed to read the header of the event log")
+	}
+	specIdEvent, err := readEventData(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the Spec ID Event of the event log")
+	}
+	if header.EventType != evNoAction || !bytes.HasPrefix(specIdEvent, []byte(specIdEventSignature)) {
+		return nil, errors.New("The event log does not start with a crypto agile Spec ID Event")
+	}
+	digestSizes, err := parseSpecIdEvent(specIdEvent[len(specIdEventSignature):])
+	if err != nil {
+		return nil, err
+	}
+
+	tcgEventLog := TcgEventLog{
+		Pcrs: make(map[types.SHAAlgorithm]map[types.PcrIndex]string),
+	}
+	// the PCR values are replayed as the events are read, starting from zeroes
+	pcrValues := make(map[types.SHAAlgorithm]map[types.PcrIndex][]byte)
+	entryIndex := make(map[types.SHAAlgorithm]map[types.PcrIndex]int)
+	for eventNumber := 1; reader.Len() > 0; eventNumber++ {
+		var eventHeader struct {
+			PcrIndex    uint32
+			EventType   uint32
+			DigestCount uint32
+		}
+		if err := binary.Read(reader, binary.LittleEndian, &eventHeader); err != nil {
+			return nil, errors.Wrapf(err, "Failed to read the header of event %d", eventNumber)
+		}
+		if eventHeader.PcrIndex > uint32(types.PCR23) {
+			return nil, errors.Errorf("Invalid PCR index %d in event %d", eventHeader.PcrIndex, eventNumber)
+		}
+		if eventHeader.DigestCount > uint32(len(digestSizes)) {
+			return nil, errors.Errorf("Invalid digest count %d in event %d", eventHeader.DigestCount, eventNumber)
+		}
+		pcrIndex := types.PcrIndex(eventHeader.PcrIndex)
+
+		digests := make(map[types.SHAAlgorithm][]byte, eventHeader.DigestCount)
+		for i := uint32(0); i < eventHeader.DigestCount; i++ {
+			var algorithmId uint16
+			if err := binary.Read(reader, binary.LittleEndian, &algorithmId); err != nil {
+				return nil, errors.Wrapf(err, "Failed to read the digest algorithm of event %d", eventNumber)
+			}
+			digestSize, ok := digestSizes[algorithmId]
+			if !ok {
+				return nil, errors.Errorf("Digest algorithm 0x%04x of event %d is not listed in the Spec ID Event", algorithmId, eventNumber)
+			}
+			digest := make([]byte, digestSize)
+			if _, err := io.ReadFull(reader, digest); err != nil {
+				return nil, errors.Wrapf(err, "Failed to read the digest of event %d", eventNumber)
+			}
+			// the digests of the banks the verifier does not support, e.g. SM3, are skipped
+			if bank, err := getSHAAlgorithm(algorithmId); err == nil {
+				if len(digest) != tcgHashAlgorithms[bank].Size() {
+					return nil, errors.Errorf("Invalid %s digest size %d in event %d", bank, len(digest), eventNumber)
+				}
+				digests[bank] = digest
+			}
+		}
+
+		eventData, err := readEventData(reader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read the data of event %d", eventNumber)
+		}
+
+		if eventHeader.EventType == evNoAction {
+			if bytes.HasPrefix(eventData, []byte(startupLocalitySignature)) && len(eventData) > len(startupLocalitySignature) {
+				tcgEventLog.StartupLocality = eventData[len(startupLocalitySignature)]
+			}
+			continue
+		}
+
+		eventName, ok := tcgEventTypes[eventHeader.EventType]
+		if !ok {
+			eventName = fmt.Sprintf("0x%08X", eventHeader.EventType)
+		}
+		for bank, digest := range digests {
+			if entryIndex[bank] == nil {
+				entryIndex[bank] = make(map[types.PcrIndex]int)
+				pcrValues[bank] = make(map[types.PcrIndex][]byte)
+			}
+			index, ok := entryIndex[bank][pcrIndex]
+			if !ok {
+				index = len(tcgEventLog.EventLogs)
+				entryIndex[bank][pcrIndex] = index
+				tcgEventLog.EventLogs = append(tcgEventLog.EventLogs, types.EventLogEntry{
+					PcrIndex: pcrIndex,
+					PcrBank:  bank,
+				})
+
+				pcrValue := make([]byte, len(digest))
+				if pcrIndex == types.PCR0 {
+					pcrValue[len(pcrValue)-1] = tcgEventLog.StartupLocality
+				}
+				pcrValues[bank][pcrIndex] = pcrValue
+			}
+			tcgEventLog.EventLogs[index].EventLogs = append(tcgEventLog.EventLogs[index].EventLogs, types.EventLog{
+				Value: hex.EncodeToString(digest),
+				Label: eventName,
+				Info: map[string]string{
+					types.EventNameField: eventName,
+					"EventType":          fmt.Sprintf("0x%08X", eventHeader.EventType),
+				},
+			})
+
+			hash := tcgHashAlgorithms[bank].New()
+			hash.Write(pcrValues[bank][pcrIndex])
+			hash.Write(digest)
+			pcrValues[bank][pcrIndex] = hash.Sum(nil)
+		}
+	}
+
+	for bank, values := range pcrValues {
+		tcgEventLog.Pcrs[bank] = make(map[types.PcrIndex]string, len(values))
+		for pcrIndex, value := range values {
+			tcgEventLog.Pcrs[bank][pcrIndex] = hex.EncodeToString(value)
+		}
+	}
+	return &tcgEventLog, nil
+}
+
+// VerifyPcrs checks the replayed value of every PCR extended by the event log against the PCR value in the quote.
+// The PCRs that are not in the event log, such as the PCRs extended by the OS, are not checked.
+func (tcgEventLog *TcgEventLog) VerifyPcrs(pcrs []tpm2.PCR) error {
+	log.Trace("util/tcg_event_log:VerifyPcrs() Entering")
+	defer log.Trace("util/tcg_event_log:VerifyPcrs() Leaving")
+
+	verified := 0
+	for _, pcr := range pcrs {
+		bank, err := getSHAAlgorithm(pcr.HashAlg)
+		if err != nil {
+			continue
+		}
+		replayedValue, ok := tcgEventLog.Pcrs[bank][types.PcrIndex(pcr.Index)]
+		if !ok {
+			continue
+		}
+		if replayedValue != hex.EncodeToString(pcr.Value) {
+			return errors.Errorf("The event log does not replay to the value of %s pcr_%d in the quote", bank, pcr.Index)
+		}
+		verified++
+	}
+	if verified == 0 {
+		return errors.New("The event log does not extend any of the PCRs in the quote")
+	}
+	return nil
+}
+
+// parseSpecIdEvent returns the digest size of every algorithm listed in the Spec ID Event
+func parseSpecIdEvent(specIdEvent []byte) (map[uint16]int, error) {
+	log.Trace("util/tcg_event_log:parseSpecIdEvent() Entering")
+	defer log.Trace("util/tcg_event_log:parseSpecIdEvent() Leaving")
+
+	reader := bytes.NewReader(specIdEvent)
+	var spec struct {
+		PlatformClass      uint32
+		SpecVersionMinor   uint8
+		SpecVersionMajor   uint8
+		SpecErrata         uint8
+		UintnSize          uint8
+		NumberOfAlgorithms uint32
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &spec); err != nil {
+		return nil, errors.Wrap(err, "Failed to read the Spec ID Event")
+	}
+	if spec.NumberOfAlgorithms == 0 || spec.NumberOfAlgorithms > uint32(reader.Len()/4) {
+		return nil, errors.Errorf("Invalid number of algorithms %d in the Spec ID Event", spec.NumberOfAlgorithms)
+	}
+
+	digestSizes := make(map[uint16]int, spec.NumberOfAlgorithms)
+	for i := uint32(0); i < spec.NumberOfAlgorithms; i++ {
+		var algorithm struct {
+			AlgorithmId uint16
+			DigestSize  uint16
+		}
+		if err := binary.Read(reader, binary.LittleEndian, &algorithm); err != nil {
+			return nil, errors.Wrap(err, "Failed to read the algorithms of the Spec ID Event")
+		}
+		digestSizes[algorithm.AlgorithmId] = int(algorithm.DigestSize)
+	}
+	return digestSizes, nil
+}
+
+// readEventData reads the size prefixed data of an event
+func readEventData(reader *bytes.Reader) ([]byte, error) {
+	var eventSize uint32
+	if err := binary.Read(reader, binary.LittleEndian, &eventSize); err != nil {
+		return nil, err
+	}
+	if eventSize > maxEventSize || int64(eventSize) > int64(reader.Len()) {
+		return nil, errors.Errorf("Invalid event size %d", eventSize)
+	}
+	eventData := make([]byte, eventSize)
+	if _, err := io.ReadFull(reader, eventData); err != nil {
+		return nil, err
+	}
+	return eventData, nil
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt/tpm2"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTcgEvent struct {
+	pcrIndex  uint32
+	eventType uint32
+	data      []byte
+}
+
+// newTestTcgEventLog returns a crypto agile event log with SHA1 and SHA256 digests of the event data
+func newTestTcgEventLog(events []testTcgEvent) []byte {
+	var eventLog bytes.Buffer
+	specIdEvent := bytes.NewBufferString(specIdEventSignature)
+	binary.Write(specIdEvent, binary.LittleEndian, []uint32{0, 0x00020000, 2})
+	binary.Write(specIdEvent, binary.LittleEndian, []uint16{tpm2.AlgSHA1, sha1.Size, tpm2.AlgSHA256, sha256.Size})
+	specIdEvent.WriteByte(0)
+
+	binary.Write(&eventLog, binary.LittleEndian, []uint32{0, evNoAction})
+	eventLog.Write(make([]byte, sha1.Size))
+	binary.Write(&eventLog, binary.LittleEndian, uint32(specIdEvent.Len()))
+	eventLog.Write(specIdEvent.Bytes())
+
+	for _, event := range events {
+		sha1Digest := sha1.Sum(event.data)
+		sha256Digest := sha256.Sum256(event.data)
+		binary.Write(&eventLog, binary.LittleEndian, []uint32{event.pcrIndex, event.eventType, 2})
+		binary.Write(&eventLog, binary.LittleEndian, tpm2.AlgSHA1)
+		eventLog.Write(sha1Digest[:])
+		binary.Write(&eventLog, binary.LittleEndian, tpm2.AlgSHA256)
+		eventLog.Write(sha256Digest[:])
+		binary.Write(&eventLog, binary.LittleEndian, uint32(len(event.data)))
+		eventLog.Write(event.data)
+	}
+	return eventLog.Bytes()
+}
+
+// extendSha256 returns the SHA256 PCR value after extending the digests of the data from the initial value
+func extendSha256(initial []byte, data ...[]byte) []byte {
+	pcrValue := initial
+	for _, d := range data {
+		digest := sha256.Sum256(d)
+		extended := sha256.Sum256(append(append([]byte{}, pcrValue...), digest[:]...))
+		pcrValue = extended[:]
+	}
+	return pcrValue
+}
+
+func TestParseTcgEventLog(t *testing.T) {
+	events := []testTcgEvent{
+		{pcrIndex: 0, eventType: 0x00000008, data: []byte("CRTM version")},
+		{pcrIndex: 0, eventType: 0x80000008, data: []byte("firmware blob")},
+		{pcrIndex: 0, eventType: evNoAction, data: []byte("not extended")},
+		{pcrIndex: 7, eventType: 0x00000004, data: []byte{0, 0, 0, 0}},
+	}
+	tcgEventLog, err := ParseTcgEventLog(newTestTcgEventLog(events))
+	assert.NoError(t, err)
+	assert.Len(t, tcgEventLog.EventLogs, 4)
+	assert.Equal(t, uint8(0), tcgEventLog.StartupLocality)
+
+	pcr0 := extendSha256(make([]byte, sha256.Size), events[0].data, events[1].data)
+	assert.Equal(t, hex.EncodeToString(pcr0), tcgEventLog.Pcrs[types.SHA256][types.PCR0])
+	for _, entry := range tcgEventLog.EventLogs {
+		// the replay of the events of each bank matches the replayed PCR value
+		replayedValue, err := entry.Replay()
+		assert.NoError(t, err)
+		assert.Equal(t, tcgEventLog.Pcrs[entry.PcrBank][entry.PcrIndex], replayedValue)
+		if entry.PcrBank == types.SHA256 && entry.PcrIndex == types.PCR0 {
+			assert.Len(t, entry.EventLogs, 2)
+			assert.Equal(t, "EV_EFI_PLATFORM_FIRMWARE_BLOB", entry.EventLogs[1].Label)
+			assert.NoError(t, entry.EventLogs[1].Validate(types.SHA256))
+		}
+	}
+
+	_, err = ParseTcgEventLog([]byte("not an event log"))
+	assert.Error(t, err)
+
+	// truncated event logs are rejected
+	eventLog := newTestTcgEventLog(events)
+	_, err = ParseTcgEventLog(eventLog[:len(eventLog)-2])
+	assert.Error(t, err)
+}
+
+func TestParseTcgEventLogStartupLocality(t *testing.T) {
+	events := []testTcgEvent{
+		{pcrIndex: 0, eventType: evNoAction, data: append([]byte(startupLocalitySignature), 3)},
+		{pcrIndex: 0, eventType: 0x00000008, data: []byte("CRTM version")},
+	}
+	tcgEventLog, err := ParseTcgEventLog(newTestTcgEventLog(events))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(3), tcgEventLog.StartupLocality)
+
+	// PCR0 starts from the startup locality instead of zeroes
+	initial := make([]byte, sha256.Size)
+	initial[sha256.Size-1] = 3
+	pcr0 := extendSha256(initial, events[1].data)
+	assert.Equal(t, hex.EncodeToString(pcr0), tcgEventLog.Pcrs[types.SHA256][types.PCR0])
+}
+
+func TestTcgEventLogVerifyPcrs(t *testing.T) {
+	events := []testTcgEvent{
+		{pcrIndex: 0, eventType: 0x80000008, data: []byte("firmware blob")},
+		{pcrIndex: 7, eventType: 0x00000004, data: []byte{0, 0, 0, 0}},
+	}
+	tcgEventLog, err := ParseTcgEventLog(newTestTcgEventLog(events))
+	assert.NoError(t, err)
+
+	pcrs := make([]tpm2.PCR, 0)
+	for bank, algorithm := range map[types.SHAAlgorithm]uint16{types.SHA1: tpm2.AlgSHA1, types.SHA256: tpm2.AlgSHA256} {
+		for _, index := range []types.PcrIndex{types.PCR0, types.PCR7} {
+			value, err := hex.DecodeString(tcgEventLog.Pcrs[bank][index])
+			assert.NoError(t, err)
+			pcrs = append(pcrs, tpm2.PCR{HashAlg: algorithm, Index: int(index), Value: value})
+		}
+	}
+	// PCRs extended outside of the event log are not checked
+	pcrs = append(pcrs, tpm2.PCR{HashAlg: tpm2.AlgSHA256, Index: 10, Value: make([]byte, sha256.Size)})
+	assert.NoError(t, tcgEventLog.VerifyPcrs(pcrs))
+
+	// a quoted PCR that does not match the replay of any bank fails the verification
+	pcrs[len(pcrs)-2].Value = make([]byte, len(pcrs[len(pcrs)-2].Value))
+	assert.Error(t, tcgEventLog.VerifyPcrs(pcrs))
+
+	// the event log must extend at least one of the quoted PCRs
+	assert.Error(t, tcgEventLog.VerifyPcrs([]tpm2.PCR{{HashAlg: tpm2.AlgSHA256, Index: 10, Value: make([]byte, sha256.Size)}}))
+}
+
+func TestAddTcgEventLogEntries(t *testing.T) {
+	tcgEventLog, err := ParseTcgEventLog(newTestTcgEventLog([]testTcgEvent{
+		{pcrIndex: 0, eventType: 0x80000008, data: []byte("firmware blob")},
+		{pcrIndex: 17, eventType: 0x00000004, data: []byte{0, 0, 0, 0}},
+	}))
+	assert.NoError(t, err)
+
+	// the events of the measure log take precedence
+	eventLogMap := types.PcrEventLogMap{
+		Sha256EventLogs: []types.EventLogEntry{{PcrIndex: types.PCR17, PcrBank: types.SHA256,
+			EventLogs: []types.EventLog{{Value: "00", Label: "tb_policy"}}}},
+	}
+	addTcgEventLogEntries(tcgEventLog, &eventLogMap)
+	assert.Len(t, eventLogMap.Sha1EventLogs, 2)
+	assert.Len(t, eventLogMap.Sha256EventLogs, 2)
+	for _, entry := range eventLogMap.Sha256EventLogs {
+		if entry.PcrIndex == types.PCR17 {
+			assert.Equal(t, "tb_policy", entry.EventLogs[0].Label)
+		} else {
+			assert.Equal(t, EVENT_LOG_DIGEST_SHA256, entry.EventLogs[0].DigestType)
+		}
+	}
+}
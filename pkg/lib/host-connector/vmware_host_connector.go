@@ -5,6 +5,7 @@
 package host_connector
 
 import (
+	"context"
 	"crypto"
 	"encoding/hex"
 	"encoding/json"
@@ -21,10 +22,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type VmwareConnector struct {
 	client vmware.VMWareClient
+	// callTimeout bounds every call of the connector, zero leaves the calls bounded by their context only
+	callTimeout time.Duration
 }
 
 const (
@@ -42,11 +46,13 @@ const (
 	BOOT_SECURITY_OPTIONS_PREFIX        = "bootSecurityOption."
 )
 
-func (vc *VmwareConnector) GetHostDetails() (taModel.HostInfo, error) {
+func (vc *VmwareConnector) GetHostDetails(ctx context.Context) (taModel.HostInfo, error) {
 
 	log.Trace("vmware_host_connector :GetHostDetails() Entering")
 	defer log.Trace("vmware_host_connector :GetHostDetails() Leaving")
-	hostInfo, err := vc.client.GetHostInfo()
+	ctx, cancel := withCallTimeout(ctx, vc.callTimeout)
+	defer cancel()
+	hostInfo, err := vc.client.GetHostInfo(ctx)
 	if err != nil {
 		return taModel.HostInfo{}, errors.Wrap(err, "vmware_host_connector: GetHostDetails() Error getting host"+
 			"info from vmware")
@@ -54,14 +60,16 @@ func (vc *VmwareConnector) GetHostDetails() (taModel.HostInfo, error) {
 	return hostInfo, nil
 }
 
-func (vc *VmwareConnector) GetHostManifest(pcrList []int) (types.HostManifest, error) {
+func (vc *VmwareConnector) GetHostManifest(ctx context.Context, pcrList []int) (types.HostManifest, error) {
 
 	log.Trace("vmware_host_connector :GetHostManifest() Entering")
 	defer log.Trace("vmware_host_connector :GetHostManifest() Leaving")
+	ctx, cancel := withCallTimeout(ctx, vc.callTimeout)
+	defer cancel()
 	var err error
 	var hostManifest types.HostManifest
 	var pcrManifest types.PcrManifest
-	tpmAttestationReport, err := vc.client.GetTPMAttestationReport()
+	tpmAttestationReport, err := vc.client.GetTPMAttestationReport(ctx)
 	if err != nil {
 		return types.HostManifest{}, errors.Wrap(err, "vmware_host_connector: GetHostManifest() Error getting TPM "+
 			"attestation report from vcenter API")
@@ -78,7 +86,7 @@ func (vc *VmwareConnector) GetHostManifest(pcrList []int) (types.HostManifest, e
 			"PCR manifest from Host Attestation Report")
 	}
 
-	hostManifest.HostInfo, err = vc.client.GetHostInfo()
+	hostManifest.HostInfo, err = vc.client.GetHostInfo(ctx)
 	log.Debugf("Host info received : %v", hostManifest.HostInfo)
 	if err != nil {
 		return types.HostManifest{}, errors.Wrap(err, "vmware_host_connector: GetHostManifest() Error getting host "+
@@ -89,22 +97,45 @@ func (vc *VmwareConnector) GetHostManifest(pcrList []int) (types.HostManifest, e
 	return hostManifest, nil
 }
 
-func (vc *VmwareConnector) DeployAssetTag(hardwareUUID, tag string) error {
+func (vc *VmwareConnector) DeployAssetTag(ctx context.Context, hardwareUUID, tag string) error {
 	return errors.New("vmware_host_connector:DeployAssetTag() Operation not supported")
 }
 
-func (vc *VmwareConnector) DeploySoftwareManifest(manifest taModel.Manifest) error {
+func (vc *VmwareConnector) DeploySoftwareManifest(ctx context.Context, manifest taModel.Manifest) error {
 	return errors.New("vmware_host_connector :DeploySoftwareManifest() Operation not supported")
 }
 
-func (vc *VmwareConnector) GetMeasurementFromManifest(manifest taModel.Manifest) (taModel.Measurement, error) {
+func (vc *VmwareConnector) DeploySoftwareManifests(ctx context.Context, manifests []taModel.Manifest) []error {
+	errs := make([]error, len(manifests))
+	for i := range manifests {
+		errs[i] = errors.New("vmware_host_connector :DeploySoftwareManifests() Operation not supported")
+	}
+	return errs
+}
+
+func (vc *VmwareConnector) GetMeasurementFromManifest(ctx context.Context, manifest taModel.Manifest) (taModel.Measurement, error) {
 	return taModel.Measurement{}, errors.New("vmware_host_connector :GetMeasurementFromManifest() Operation not supported")
 }
 
-func (vc *VmwareConnector) GetClusterReference(clusterName string) ([]mo.HostSystem, error) {
+// Capabilities returns the capabilities of the host derived from the host info reported by vCenter
+func (vc *VmwareConnector) Capabilities(ctx context.Context) (taModel.HostCapabilities, error) {
+	log.Trace("vmware_host_connector :Capabilities() Entering")
+	defer log.Trace("vmware_host_connector :Capabilities() Leaving")
+
+	hostInfo, err := vc.GetHostDetails(ctx)
+	if err != nil {
+		return taModel.HostCapabilities{}, errors.Wrap(err, "vmware_host_connector: Capabilities() Error getting "+
+			"host details")
+	}
+	return capabilitiesFromHostInfo(hostInfo), nil
+}
+
+func (vc *VmwareConnector) GetClusterReference(ctx context.Context, clusterName string) ([]mo.HostSystem, error) {
 	log.Trace("vmware_host_connector :GetClusterReference() Entering")
 	defer log.Trace("vmware_host_connector :GetClusterReference() Leaving")
-	hostInfoList, err := vc.client.GetVmwareClusterReference(clusterName)
+	ctx, cancel := withCallTimeout(ctx, vc.callTimeout)
+	defer cancel()
+	hostInfoList, err := vc.client.GetVmwareClusterReference(ctx, clusterName)
 	if err != nil {
 		return nil, errors.Wrap(err, "vmware_host_connector: GetClusterReference() Error getting host"+
 			"info from vmware")
@@ -272,7 +303,7 @@ func intArrayToHexString(pcrDigestArray []int) string {
 	return pcrDigestString
 }
 
-//It checks the type of TPM event and accordingly updates the event log entry values
+// It checks the type of TPM event and accordingly updates the event log entry values
 func getEventLogInfo(parsedEventLogEntry types.TpmEvent) types.EventLog {
 
 	log.Trace("vmware_host_connector:getEventLogInfo() Entering")
@@ -6,13 +6,17 @@ package host_connector
 
 import (
 	"crypto/x509"
+	"github.com/intel-secl/intel-secl/v3/pkg/clients"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/vmware"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/pkg/errors"
 	"net/url"
+	"time"
 )
 
 type VmwareConnectorFactory struct {
+	callTimeout time.Duration
+	proxy       clients.ProxyFunc
 }
 
 func (vcf *VmwareConnectorFactory) GetHostConnector(vc types.VendorConnector, aasApiUrl string,
@@ -25,10 +29,10 @@ func (vcf *VmwareConnectorFactory) GetHostConnector(vc types.VendorConnector, aa
 		return nil, errors.Wrap(err, "vmware_host_connector_factory:GetHostConnector() Invalid vcenter URL provided")
 	}
 
-	vmwareClient, err := vmware.NewVMwareClient(parsedURL, vc.Configuration.Username, vc.Configuration.Password,
-		vc.Configuration.Hostname, trustedCaCerts)
+	vmwareClient, err := vmware.NewVMwareClientWithProxy(parsedURL, vc.Configuration.Username, vc.Configuration.Password,
+		vc.Configuration.Hostname, trustedCaCerts, vcf.proxy)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error creating vmware client")
 	}
-	return &VmwareConnector{vmwareClient}, nil
+	return &VmwareConnector{client: vmwareClient, callTimeout: vcf.callTimeout}, nil
 }
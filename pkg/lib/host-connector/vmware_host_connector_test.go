@@ -6,6 +6,7 @@
 package host_connector
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/intel-secl/intel-secl/v3/pkg/clients/vmware"
@@ -30,7 +31,7 @@ func TestVmwareConnectorGetHostDetails(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	hostDetails, err := vmwareConnector.GetHostDetails()
+	hostDetails, err := vmwareConnector.GetHostDetails(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, "VMware ESXi", hostDetails.OSName)
 }
@@ -45,7 +46,7 @@ func TestVmwareConnectorGetHostDetailsError(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	_, err = vmwareConnector.GetHostDetails()
+	_, err = vmwareConnector.GetHostDetails(context.Background())
 	assert.Error(t, err)
 }
 
@@ -63,7 +64,7 @@ func TestVmwareConnectorGetHostManifest(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	hostManifest, err := vmwareConnector.GetHostManifest(nil)
+	hostManifest, err := vmwareConnector.GetHostManifest(context.Background(), nil)
 	log.Info(hostManifest)
 	assert.NoError(t, err)
 	assert.Equal(t, "VMware ESXi", hostManifest.HostInfo.OSName)
@@ -77,7 +78,7 @@ func TestVmwareConnectorGetHostManifest(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	_, err = vmwareConnector.GetHostManifest(nil)
+	_, err = vmwareConnector.GetHostManifest(context.Background(), nil)
 	assert.Error(t, err)
 
 	//Test error for invalid digest algorithm
@@ -90,7 +91,7 @@ func TestVmwareConnectorGetHostManifest(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	_, err = vmwareConnector.GetHostManifest(nil)
+	_, err = vmwareConnector.GetHostManifest(context.Background(), nil)
 	assert.Error(t, err)
 }
 
@@ -109,7 +110,7 @@ func TestVmwareConnectorGetHostManifestErrorHostInfo(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	_, err = vmwareConnector.GetHostManifest(nil)
+	_, err = vmwareConnector.GetHostManifest(context.Background(), nil)
 	assert.Error(t, err)
 }
 
@@ -129,7 +130,7 @@ func TestVmwareConnectorGetHostManifestErrorTpmReport(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	_, err = vmwareConnector.GetHostManifest(nil)
+	_, err = vmwareConnector.GetHostManifest(context.Background(), nil)
 	assert.Error(t, err)
 }
 
@@ -149,7 +150,7 @@ func TestVmwareConnectorGetHostManifestUnreliableTpmReport(t *testing.T) {
 		client: mockVMwareClient,
 	}
 
-	_, err = vmwareConnector.GetHostManifest(nil)
+	_, err = vmwareConnector.GetHostManifest(context.Background(), nil)
 	assert.Error(t, err)
 }
 
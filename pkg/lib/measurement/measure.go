@@ -0,0 +1,279 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package measurement takes the measurements of a manifest (the File, Dir and Symlink entries of the workload
+// measurement manifest format) in a live file system and creates the measurement XML and the SOFTWARE flavor of the
+// manifest, the same way the measurement library of the hosts does:
+//   - File: the digest of the content of the file. With the "regex" SearchType the last element of the path is a
+//     regular expression and every regular file of the directory it matches is measured.
+//   - Dir: the digest of the sorted list of the paths of the files and links under the directory, one per line,
+//     filtered by the Include and Exclude expressions ("regex" FilterType, the default, or "wildcard").
+//   - Symlink: the digest of the target of the link.
+//
+// The cumulative hash is the extension of the measurements from zeroes, in the order of the manifest.
+package measurement
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	cm "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/types"
+	"github.com/pkg/errors"
+)
+
+var log = commLog.GetDefaultLogger()
+
+const (
+	// Namespace is the XML namespace of the measurements
+	Namespace = "lib:wml:measurements:1.0"
+
+	// DigestAlgorithm is the only digest algorithm of the measurements
+	DigestAlgorithm = "SHA384"
+
+	entryFile    = "File"
+	entryDir     = "Dir"
+	entrySymlink = "Symlink"
+
+	searchTypeRegex    = "regex"
+	filterTypeRegex    = "regex"
+	filterTypeWildcard = "wildcard"
+)
+
+// entry is a File, Dir or Symlink element of the manifest and of the measurement, they are decoded and encoded as
+// generic elements to keep the order of the manifest the cumulative hash depends on
+type entry struct {
+	XMLName    xml.Name
+	Value      string `xml:",chardata"`
+	Exclude    string `xml:"Exclude,attr,omitempty"`
+	FilterType string `xml:"FilterType,attr,omitempty"`
+	Include    string `xml:"Include,attr,omitempty"`
+	Path       string `xml:"Path,attr"`
+	SearchType string `xml:"SearchType,attr,omitempty"`
+}
+
+type manifest struct {
+	XMLName   xml.Name `xml:"Manifest"`
+	Label     string   `xml:"Label,attr"`
+	Uuid      string   `xml:"Uuid,attr"`
+	DigestAlg string   `xml:"DigestAlg,attr"`
+	Entries   []entry  `xml:",any"`
+}
+
+type measurement struct {
+	XMLName        xml.Name
+	DigestAlg      string  `xml:"DigestAlg,attr"`
+	Label          string  `xml:"Label,attr"`
+	Uuid           string  `xml:"Uuid,attr"`
+	Entries        []entry `xml:",any"`
+	CumulativeHash string  `xml:"lib:wml:measurements:1.0 CumulativeHash"`
+}
+
+// Measure measures the entries of the manifest XML in the file system mounted at the root directory, the paths of
+// the manifest are relative to it. It returns the measurement XML, its entries are in the order of the manifest.
+func Measure(manifestXml []byte, rootDir string) ([]byte, error) {
+	log.Trace("lib/measurement/measure:Measure() Entering")
+	defer log.Trace("lib/measurement/measure:Measure() Leaving")
+
+	var mf manifest
+	if err := xml.Unmarshal(manifestXml, &mf); err != nil {
+		return nil, errors.Wrap(err, "Error parsing the manifest")
+	}
+	if mf.Label == "" {
+		return nil, errors.New("The manifest has no label")
+	}
+	if mf.DigestAlg != "" && !strings.EqualFold(mf.DigestAlg, DigestAlgorithm) {
+		return nil, errors.Errorf("Unsupported digest algorithm %s", mf.DigestAlg)
+	}
+
+	extender, err := crypt.NewExtender(crypt.SHA384())
+	if err != nil {
+		return nil, err
+	}
+	result := measurement{
+		XMLName:   xml.Name{Space: Namespace, Local: "Measurement"},
+		DigestAlg: DigestAlgorithm,
+		Label:     mf.Label,
+		Uuid:      mf.Uuid,
+	}
+	for _, manifestEntry := range mf.Entries {
+		measured, err := measureEntry(manifestEntry, rootDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range measured {
+			digest, _ := hex.DecodeString(e.Value)
+			extender.Extend(digest)
+		}
+		result.Entries = append(result.Entries, measured...)
+	}
+	result.CumulativeHash = hex.EncodeToString(extender.Value())
+
+	measurementXml, err := xml.MarshalIndent(result, "", "    ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error encoding the measurement")
+	}
+	return measurementXml, nil
+}
+
+// CreateSoftwareFlavor measures the manifest XML in the file system mounted at the root directory and returns the
+// SOFTWARE flavor of the measurements along with the measurement XML
+func CreateSoftwareFlavor(manifestXml []byte, rootDir string) (*cm.Flavor, []byte, error) {
+	log.Trace("lib/measurement/measure:CreateSoftwareFlavor() Entering")
+	defer log.Trace("lib/measurement/measure:CreateSoftwareFlavor() Leaving")
+
+	measurementXml, err := Measure(manifestXml, rootDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	softwareFlavor := types.NewSoftwareFlavor(string(measurementXml))
+	flavor, err := softwareFlavor.GetSoftwareFlavor()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error creating the SOFTWARE flavor")
+	}
+	return flavor, measurementXml, nil
+}
+
+// measureEntry returns the measurements of the manifest entry, a File entry with a regular expression has as many
+// measurements as matching files
+func measureEntry(manifestEntry entry, rootDir string) ([]entry, error) {
+	if !strings.HasPrefix(manifestEntry.Path, "/") {
+		return nil, errors.Errorf("The path %s of the manifest is not absolute", manifestEntry.Path)
+	}
+	measured := manifestEntry
+	measured.XMLName = xml.Name{Space: Namespace, Local: manifestEntry.XMLName.Local}
+	localPath := filepath.Join(rootDir, filepath.FromSlash(manifestEntry.Path))
+
+	var digest []byte
+	var err error
+	switch manifestEntry.XMLName.Local {
+	case entryFile:
+		if manifestEntry.SearchType == searchTypeRegex {
+			return measureFiles(measured, rootDir)
+		}
+		digest, err = crypt.HashFile(localPath, crypt.SHA384())
+	case entryDir:
+		digest, err = measureDir(manifestEntry, localPath)
+	case entrySymlink:
+		var target string
+		if target, err = os.Readlink(localPath); err != nil {
+			err = errors.Wrapf(err, "Error reading the link %s", manifestEntry.Path)
+			break
+		}
+		digest, err = crypt.HashReader(strings.NewReader(target), crypt.SHA384())
+	default:
+		return nil, errors.Errorf("Unknown manifest entry %s", manifestEntry.XMLName.Local)
+	}
+	if err != nil {
+		return nil, err
+	}
+	measured.Value = hex.EncodeToString(digest)
+	return []entry{measured}, nil
+}
+
+// measureFiles measures the regular files of the directory whose names match the last element of the path
+func measureFiles(file entry, rootDir string) ([]entry, error) {
+	dir, pattern := path.Split(file.Path)
+	nameRegex, err := regexp.Compile("^(" + pattern + ")$")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid regular expression of the file %s", file.Path)
+	}
+	localDir := filepath.Join(rootDir, filepath.FromSlash(dir))
+	infos, err := ioutil.ReadDir(localDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading the directory %s", dir)
+	}
+
+	var measured []entry
+	for _, info := range infos {
+		if !info.Mode().IsRegular() || !nameRegex.MatchString(info.Name()) {
+			continue
+		}
+		digest, err := crypt.HashFile(filepath.Join(localDir, info.Name()), crypt.SHA384())
+		if err != nil {
+			return nil, err
+		}
+		match := file
+		match.Path = path.Join(dir, info.Name())
+		match.SearchType = ""
+		match.Value = hex.EncodeToString(digest)
+		measured = append(measured, match)
+	}
+	if len(measured) == 0 {
+		return nil, errors.Errorf("No file matches %s", file.Path)
+	}
+	return measured, nil
+}
+
+// measureDir hashes the sorted list of the paths of the files and links under the directory that are included and
+// not excluded by the filters, each path is followed by a new line
+func measureDir(dir entry, localDir string) ([]byte, error) {
+	include, err := compileFilter(dir.Include, dir.FilterType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid include filter of the directory %s", dir.Path)
+	}
+	exclude, err := compileFilter(dir.Exclude, dir.FilterType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid exclude filter of the directory %s", dir.Path)
+	}
+
+	var paths []string
+	err = filepath.Walk(localDir, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		hostPath := path.Join(dir.Path, filepath.ToSlash(relativePath))
+		if (include == nil || include.MatchString(hostPath)) && (exclude == nil || !exclude.MatchString(hostPath)) {
+			paths = append(paths, hostPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing the directory %s", dir.Path)
+	}
+
+	sort.Strings(paths)
+	var list strings.Builder
+	for _, p := range paths {
+		list.WriteString(p)
+		list.WriteByte('\n')
+	}
+	return crypt.HashReader(strings.NewReader(list.String()), crypt.SHA384())
+}
+
+// compileFilter returns the regular expression of an Include or Exclude filter, nil when the filter is empty. A
+// wildcard filter matches the whole path, * and ? match any characters and any single character.
+func compileFilter(filter, filterType string) (*regexp.Regexp, error) {
+	if filter == "" {
+		return nil, nil
+	}
+	switch filterType {
+	case "", filterTypeRegex:
+		return regexp.Compile(filter)
+	case filterTypeWildcard:
+		expression := regexp.QuoteMeta(filter)
+		expression = strings.ReplaceAll(expression, `\*`, ".*")
+		expression = strings.ReplaceAll(expression, `\?`, ".")
+		return regexp.Compile("^(" + expression + ")$")
+	default:
+		return nil, errors.Errorf("Unknown filter type %s", filterType)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package measurement
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+const testManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<Manifest xmlns="lib:wml:manifests:1.0" DigestAlg="SHA384" Label="ISL_Applications123" Uuid="834076cd-f733-4cca-a417-113fac90adc7">
+    <Dir Exclude="*.log" FilterType="wildcard" Include="" Path="/opt/trustagent/hypertext"/>
+    <Symlink Path="/opt/trustagent/bin/tpm_nvinfo"/>
+    <File Path="/opt/trustagent/bin/module_.*\.sh" SearchType="regex"/>
+    <File Path="/opt/trustagent/bin/tagent"/>
+</Manifest>`
+
+func sha384Hex(content string) string {
+	digest := sha512.Sum384([]byte(content))
+	return hex.EncodeToString(digest[:])
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func TestMeasure(t *testing.T) {
+	root, err := ioutil.TempDir("", "measurement")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/hypertext/index.html"), "index")
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/hypertext/WEB-INF/web.xml"), "web")
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/hypertext/access.log"), "access")
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/bin/module_analysis.sh"), "analysis")
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/bin/module_analysis_da.sh"), "analysis da")
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/bin/module_analysis.txt"), "not measured")
+	writeTestFile(t, filepath.Join(root, "opt/trustagent/bin/tagent"), "tagent")
+	assert.NoError(t, os.Symlink("/usr/bin/tpm2_nvread", filepath.Join(root, "opt/trustagent/bin/tpm_nvinfo")))
+
+	measurementXml, err := Measure([]byte(testManifest), root)
+	assert.NoError(t, err)
+
+	var measurement taModel.Measurement
+	assert.NoError(t, xml.Unmarshal(measurementXml, &measurement))
+	assert.Equal(t, "ISL_Applications123", measurement.Label)
+	assert.Equal(t, "834076cd-f733-4cca-a417-113fac90adc7", measurement.Uuid)
+	assert.Equal(t, DigestAlgorithm, measurement.DigestAlg)
+
+	// the directory measurement is the digest of the list of the files that are not excluded
+	dirValue := sha384Hex("/opt/trustagent/hypertext/WEB-INF/web.xml\n/opt/trustagent/hypertext/index.html\n")
+	assert.Equal(t, []taModel.DirectoryMeasurementType{{Value: dirValue, Exclude: "*.log", FilterType: "wildcard",
+		Path: "/opt/trustagent/hypertext"}}, measurement.Dir)
+	assert.Equal(t, []taModel.SymlinkMeasurementType{{Value: sha384Hex("/usr/bin/tpm2_nvread"),
+		Path: "/opt/trustagent/bin/tpm_nvinfo"}}, measurement.Symlink)
+	assert.Equal(t, []taModel.FileMeasurementType{
+		{Value: sha384Hex("analysis"), Path: "/opt/trustagent/bin/module_analysis.sh"},
+		{Value: sha384Hex("analysis da"), Path: "/opt/trustagent/bin/module_analysis_da.sh"},
+		{Value: sha384Hex("tagent"), Path: "/opt/trustagent/bin/tagent"},
+	}, measurement.File)
+
+	// the cumulative hash extends the measurements in the order of the manifest
+	cumulativeHash := make([]byte, sha512.Size384)
+	for _, value := range []string{dirValue, sha384Hex("/usr/bin/tpm2_nvread"), sha384Hex("analysis"),
+		sha384Hex("analysis da"), sha384Hex("tagent")} {
+		measurement, _ := hex.DecodeString(value)
+		digest := sha512.Sum384(append(cumulativeHash, measurement...))
+		cumulativeHash = digest[:]
+	}
+	assert.Equal(t, hex.EncodeToString(cumulativeHash), measurement.CumulativeHash)
+
+	flavor, flavorMeasurementXml, err := CreateSoftwareFlavor([]byte(testManifest), root)
+	assert.NoError(t, err)
+	assert.Equal(t, measurementXml, flavorMeasurementXml)
+	assert.Equal(t, "ISL_Applications123", flavor.Meta.Description.Label)
+	assert.Equal(t, measurement.CumulativeHash, flavor.Software.CumulativeHash)
+	assert.Len(t, flavor.Software.Measurements, 5)
+	assert.Equal(t, dirValue, flavor.Software.Measurements["opt-trustagent-hypertext"].Value)
+
+	// the manifests that cannot be measured are rejected
+	_, err = Measure([]byte(`<Manifest Label="missing"><File Path="/opt/trustagent/bin/missing"/></Manifest>`), root)
+	assert.Error(t, err)
+	_, err = Measure([]byte(`<Manifest Label="none"><File Path="/opt/trustagent/bin/none_.*" SearchType="regex"/></Manifest>`), root)
+	assert.Error(t, err)
+	_, err = Measure([]byte(`<Manifest Label="relative"><File Path="opt/trustagent/bin/tagent"/></Manifest>`), root)
+	assert.Error(t, err)
+	_, err = Measure([]byte(`<Manifest Label="sha256" DigestAlg="SHA256"><File Path="/opt/trustagent/bin/tagent"/></Manifest>`), root)
+	assert.Error(t, err)
+}
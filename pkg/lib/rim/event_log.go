@@ -0,0 +1,28 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rim
+
+import (
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	hcUtil "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
+	"github.com/pkg/errors"
+)
+
+// ParseEventLog parses the crypto agile TCG event log of a support RIM and returns the expected events
+// per PCR bank and index, in the order they are extended. EV_NO_ACTION events are not extended and skipped.
+func ParseEventLog(eventLog []byte) ([]hcTypes.EventLogEntry, error) {
+	log.Trace("rim/event_log:ParseEventLog() Entering")
+	defer log.Trace("rim/event_log:ParseEventLog() Leaving")
+
+	tcgEventLog, err := hcUtil.ParseTcgEventLog(eventLog)
+	if err != nil {
+		return nil, err
+	}
+	// a startup locality other than 0 changes the initial value of PCR0 which the flavor replay does not support
+	if tcgEventLog.StartupLocality != 0 {
+		return nil, errors.Errorf("Startup locality %d is not supported", tcgEventLog.StartupLocality)
+	}
+	return tcgEventLog.EventLogs, nil
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rim
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"strings"
+
+	"github.com/beevik/etree"
+	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	rtvalidator "github.com/mattermost/xml-roundtrip-validator"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+var log = commLog.GetDefaultLogger()
+
+const (
+	// SHA256Namespace qualifies the hash attribute of the payload files of a RIM
+	SHA256Namespace = "http://www.w3.org/2001/04/xmlenc#sha256"
+
+	// the base RIM is signed over the whole SoftwareIdentity, which is referenced by its tagId
+	tagIdAttribute = "tagId"
+)
+
+// SoftwareIdentity is the SWID tag of a TCG PC Client base Reference Integrity Manifest (RIM)
+type SoftwareIdentity struct {
+	XMLName      xml.Name `xml:"SoftwareIdentity"`
+	Name         string   `xml:"name,attr"`
+	TagID        string   `xml:"tagId,attr"`
+	TagVersion   string   `xml:"tagVersion,attr"`
+	Version      string   `xml:"version,attr"`
+	Corpus       bool     `xml:"corpus,attr"`
+	Patch        bool     `xml:"patch,attr"`
+	Supplemental bool     `xml:"supplemental,attr"`
+	Entities     []Entity `xml:"Entity"`
+	Meta         Meta     `xml:"Meta"`
+	Payload      Payload  `xml:"Payload"`
+}
+
+// Entity is an organization responsible for the RIM, such as the tag creator or the software creator
+type Entity struct {
+	Name  string `xml:"name,attr"`
+	RegID string `xml:"regid,attr"`
+	Role  string `xml:"role,attr"`
+}
+
+// Meta holds the platform and firmware attributes the RIM applies to
+type Meta struct {
+	ColloquialVersion       string `xml:"colloquialVersion,attr"`
+	Product                 string `xml:"product,attr"`
+	Revision                string `xml:"revision,attr"`
+	Edition                 string `xml:"edition,attr"`
+	PayloadType             string `xml:"PayloadType,attr"`
+	PlatformManufacturerStr string `xml:"platformManufacturerStr,attr"`
+	PlatformManufacturerID  string `xml:"platformManufacturerId,attr"`
+	PlatformModel           string `xml:"platformModel,attr"`
+	PlatformVersion         string `xml:"platformVersion,attr"`
+	FirmwareManufacturerStr string `xml:"firmwareManufacturerStr,attr"`
+	FirmwareManufacturerID  string `xml:"firmwareManufacturerId,attr"`
+	FirmwareModel           string `xml:"firmwareModel,attr"`
+	FirmwareVersion         string `xml:"firmwareVersion,attr"`
+	BindingSpec             string `xml:"bindingSpec,attr"`
+	BindingSpecVersion      string `xml:"bindingSpecVersion,attr"`
+}
+
+// Payload lists the support RIM files of the base RIM
+type Payload struct {
+	Directories []Directory `xml:"Directory"`
+	Files       []File      `xml:"File"`
+}
+
+// Directory groups the support RIM files of the payload
+type Directory struct {
+	Name        string      `xml:"name,attr"`
+	Directories []Directory `xml:"Directory"`
+	Files       []File      `xml:"File"`
+}
+
+// File is a support RIM, such as the TCG event log with the reference measurements, referenced by its hash
+type File struct {
+	Name string `xml:"name,attr"`
+	Size int64  `xml:"size,attr"`
+	Hash string `xml:"http://www.w3.org/2001/04/xmlenc#sha256 hash,attr"`
+}
+
+// ParseBaseRim parses the SWID tag of a base RIM
+func ParseBaseRim(baseRim []byte) (*SoftwareIdentity, error) {
+	log.Trace("rim/rim:ParseBaseRim() Entering")
+	defer log.Trace("rim/rim:ParseBaseRim() Leaving")
+
+	if err := validation.ValidateXMLDocument(baseRim); err != nil {
+		return nil, errors.Wrap(err, "Invalid base RIM document")
+	}
+
+	var swid SoftwareIdentity
+	if err := xml.Unmarshal(baseRim, &swid); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse base RIM")
+	}
+	if strings.TrimSpace(swid.TagID) == "" {
+		return nil, errors.New("The base RIM does not have a tagId")
+	}
+	if swid.Corpus || swid.Patch || swid.Supplemental {
+		return nil, errors.New("Only base RIMs are supported, corpus, patch and supplemental tags cannot be imported")
+	}
+	return &swid, nil
+}
+
+// VerifyBaseRimSignature verifies the enveloped XML signature of the base RIM and the certificate chain of
+// the signer against the trusted RIM CA certificates. The signer certificate is returned on success.
+func VerifyBaseRimSignature(baseRim []byte, caCertificates []x509.Certificate) (*x509.Certificate, error) {
+	log.Trace("rim/rim:VerifyBaseRimSignature() Entering")
+	defer log.Trace("rim/rim:VerifyBaseRimSignature() Leaving")
+
+	if err := rtvalidator.Validate(strings.NewReader(string(baseRim))); err != nil {
+		return nil, errors.Wrap(err, "Invalid XML document: xml round-trip validation failed")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(baseRim); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse base RIM")
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("The base RIM document is empty")
+	}
+
+	// the signer certificate and the intermediate CA certificates are carried in the KeyInfo of the signature
+	var signerCerts []*x509.Certificate
+	err := etreeutils.NSFindIterate(doc.Root(), dsig.Namespace, dsig.X509CertificateTag,
+		func(ctx etreeutils.NSContext, el *etree.Element) error {
+			certBytes, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(el.Text()), ""))
+			if err != nil {
+				return errors.Wrap(err, "Failed to decode the signer certificate of the base RIM")
+			}
+			cert, err := x509.ParseCertificate(certBytes)
+			if err != nil {
+				return errors.Wrap(err, "Failed to parse the signer certificate of the base RIM")
+			}
+			signerCerts = append(signerCerts, cert)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(signerCerts) == 0 {
+		return nil, errors.New("The base RIM signature does not carry the signer certificate")
+	}
+
+	roots := x509.NewCertPool()
+	for i := range caCertificates {
+		roots.AddCert(&caCertificates[i])
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range signerCerts[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := signerCerts[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, errors.Wrap(err, "The base RIM signer certificate is not issued by a trusted RIM CA")
+	}
+
+	ctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: []*x509.Certificate{signerCerts[0]},
+	})
+	ctx.IdAttribute = tagIdAttribute
+	if _, err := ctx.Validate(doc.Root()); err != nil {
+		return nil, errors.Wrap(err, "Failed to verify the base RIM signature")
+	}
+	return signerCerts[0], nil
+}
+
+// VerifySupportRim checks that the support RIM is one of the files referenced by the payload of the base RIM
+func (swid *SoftwareIdentity) VerifySupportRim(supportRim []byte) error {
+	log.Trace("rim/rim:VerifySupportRim() Entering")
+	defer log.Trace("rim/rim:VerifySupportRim() Leaving")
+
+	digest := sha256.Sum256(supportRim)
+	supportRimHash := hex.EncodeToString(digest[:])
+	for _, file := range swid.Payload.getFiles() {
+		if strings.EqualFold(file.Hash, supportRimHash) {
+			return nil
+		}
+	}
+	return errors.New("The support RIM is not referenced by the payload of the base RIM")
+}
+
+// GetFirmwareManifest builds the FirmwareManifest of the reference measurements of the support RIM, the
+// platform features are not part of the RIM and are taken from the given feature
+func (swid *SoftwareIdentity) GetFirmwareManifest(supportRim []byte, feature *model.Feature) (*model.FirmwareManifest, error) {
+	log.Trace("rim/rim:GetFirmwareManifest() Entering")
+	defer log.Trace("rim/rim:GetFirmwareManifest() Leaving")
+
+	if err := swid.VerifySupportRim(supportRim); err != nil {
+		return nil, err
+	}
+	eventLogEntries, err := ParseEventLog(supportRim)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the event log of the support RIM")
+	}
+
+	firmwareManifest := model.FirmwareManifest{
+		Source:      swid.TagID,
+		BiosName:    swid.Meta.FirmwareManufacturerStr,
+		BiosVersion: swid.Meta.FirmwareVersion,
+		Feature:     feature,
+	}
+	if firmwareManifest.BiosName == "" {
+		firmwareManifest.BiosName = swid.Meta.PlatformManufacturerStr
+	}
+	if firmwareManifest.BiosVersion == "" {
+		firmwareManifest.BiosVersion = swid.Version
+	}
+
+	for _, entry := range eventLogEntries {
+		// the PLATFORM flavor holds the firmware PCRs of the banks supported by the flavors
+		if entry.PcrIndex > hcTypes.PCR7 || (entry.PcrBank != hcTypes.SHA1 && entry.PcrBank != hcTypes.SHA256) {
+			continue
+		}
+		firmwareManifest.Pcrs = append(firmwareManifest.Pcrs, model.FirmwareManifestPcr{
+			Index:  entry.PcrIndex,
+			Bank:   entry.PcrBank,
+			Events: entry.EventLogs,
+		})
+	}
+	if len(firmwareManifest.Pcrs) == 0 {
+		return nil, errors.New("The support RIM does not have any firmware measurements")
+	}
+	return &firmwareManifest, nil
+}
+
+// getFiles returns the files of the payload and all of its directories
+func (payload Payload) getFiles() []File {
+	files := append([]File{}, payload.Files...)
+	directories := append([]Directory{}, payload.Directories...)
+	for len(directories) > 0 {
+		directory := directories[0]
+		directories = append(directories[1:], directory.Directories...)
+		files = append(files, directory.Files...)
+	}
+	return files
+}
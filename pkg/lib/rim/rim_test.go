@@ -0,0 +1,201 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rim
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/types"
+	hcTypes "github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	evNoAction           uint32 = 0x00000003
+	specIdEventSignature        = "Spec ID Event03\x00"
+)
+
+type testKeyStore struct {
+	key  *rsa.PrivateKey
+	cert []byte
+}
+
+func (ks testKeyStore) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
+	return ks.key, ks.cert, nil
+}
+
+type testEvent struct {
+	pcrIndex  uint32
+	eventType uint32
+	data      []byte
+}
+
+// newTestEventLog returns a crypto agile event log with SHA1 and SHA256 digests of the event data
+func newTestEventLog(events []testEvent) []byte {
+	var eventLog bytes.Buffer
+	specIdEvent := bytes.NewBufferString(specIdEventSignature)
+	binary.Write(specIdEvent, binary.LittleEndian, []uint32{0, 0x00020000, 2})
+	binary.Write(specIdEvent, binary.LittleEndian, []uint16{0x0004, sha1.Size, 0x000B, sha256.Size})
+	specIdEvent.WriteByte(0)
+
+	binary.Write(&eventLog, binary.LittleEndian, []uint32{0, evNoAction})
+	eventLog.Write(make([]byte, sha1.Size))
+	binary.Write(&eventLog, binary.LittleEndian, uint32(specIdEvent.Len()))
+	eventLog.Write(specIdEvent.Bytes())
+
+	for _, event := range events {
+		sha1Digest := sha1.Sum(event.data)
+		sha256Digest := sha256.Sum256(event.data)
+		binary.Write(&eventLog, binary.LittleEndian, []uint32{event.pcrIndex, event.eventType, 2})
+		binary.Write(&eventLog, binary.LittleEndian, uint16(0x0004))
+		eventLog.Write(sha1Digest[:])
+		binary.Write(&eventLog, binary.LittleEndian, uint16(0x000B))
+		eventLog.Write(sha256Digest[:])
+		binary.Write(&eventLog, binary.LittleEndian, uint32(len(event.data)))
+		eventLog.Write(event.data)
+	}
+	return eventLog.Bytes()
+}
+
+// newTestCertificates returns a RIM CA certificate and the key store of a RIM signer issued by the CA
+func newTestCertificates(t *testing.T) (*x509.Certificate, testKeyStore) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "RIM CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caCertBytes, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caCertBytes)
+	assert.NoError(t, err)
+
+	signerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signerTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "RIM Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signerCertBytes, err := x509.CreateCertificate(rand.Reader, &signerTemplate, caCert, &signerKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	return caCert, testKeyStore{key: signerKey, cert: signerCertBytes}
+}
+
+// newTestBaseRim returns a base RIM referencing the support RIM, signed with the key store
+func newTestBaseRim(t *testing.T, supportRim []byte, ks testKeyStore) []byte {
+	supportRimHash := sha256.Sum256(supportRim)
+	swid := fmt.Sprintf(`<SoftwareIdentity xmlns="http://standards.iso.org/iso/19770/-2/2015/schema.xsd" `+
+		`xmlns:SHA256="%s" xmlns:rim="https://trustedcomputinggroup.org/resource/tcg-reference-integrity-manifest-rim-information-model/" `+
+		`corpus="false" name="Example.com BIOS" patch="false" supplemental="false" tagId="94f6b457-9ac9-4d35-9b3f-78804173b65a" tagVersion="0" version="01">`+
+		`<Entity name="Example Inc" regid="http://Example.com" role="softwareCreator tagCreator"/>`+
+		`<Meta rim:bindingSpec="PC Client RIM" rim:bindingSpecVersion="1.2" rim:firmwareManufacturerStr="Example.com" `+
+		`rim:firmwareVersion="1.0.13" rim:platformManufacturerStr="Example.com" rim:platformModel="ProLiant"/>`+
+		`<Payload><Directory name="rim"><File name="Example.com.BIOS.01.rimel" size="%d" SHA256:hash="%s"/></Directory></Payload>`+
+		`</SoftwareIdentity>`, SHA256Namespace, len(supportRim), hex.EncodeToString(supportRimHash[:]))
+
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromString(swid))
+	ctx := dsig.NewDefaultSigningContext(ks)
+	ctx.IdAttribute = tagIdAttribute
+	signed, err := ctx.SignEnveloped(doc.Root())
+	assert.NoError(t, err)
+	doc.SetRoot(signed)
+	baseRim, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return baseRim
+}
+
+func TestParseEventLog(t *testing.T) {
+	events := []testEvent{
+		{pcrIndex: 0, eventType: 0x00000008, data: []byte("CRTM version")},
+		{pcrIndex: 0, eventType: 0x80000008, data: []byte("firmware blob")},
+		{pcrIndex: 0, eventType: evNoAction, data: []byte("not extended")},
+		{pcrIndex: 7, eventType: 0x00000004, data: []byte{0, 0, 0, 0}},
+	}
+	entries, err := ParseEventLog(newTestEventLog(events))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	for _, entry := range entries {
+		if entry.PcrBank == hcTypes.SHA256 && entry.PcrIndex == hcTypes.PCR0 {
+			assert.Len(t, entry.EventLogs, 2)
+			digest := sha256.Sum256(events[1].data)
+			assert.Equal(t, hex.EncodeToString(digest[:]), entry.EventLogs[1].Value)
+			assert.Equal(t, "EV_EFI_PLATFORM_FIRMWARE_BLOB", entry.EventLogs[1].Label)
+		}
+	}
+
+	_, err = ParseEventLog([]byte("not an event log"))
+	assert.Error(t, err)
+
+	// truncated event logs are rejected
+	eventLog := newTestEventLog(events)
+	_, err = ParseEventLog(eventLog[:len(eventLog)-2])
+	assert.Error(t, err)
+}
+
+func TestBaseRimFirmwareManifest(t *testing.T) {
+	caCert, ks := newTestCertificates(t)
+	supportRim := newTestEventLog([]testEvent{
+		{pcrIndex: 0, eventType: 0x80000008, data: []byte("firmware blob")},
+		{pcrIndex: 7, eventType: 0x00000004, data: []byte{0, 0, 0, 0}},
+		{pcrIndex: 8, eventType: 0x0000000D, data: []byte("grub command line")},
+	})
+	baseRim := newTestBaseRim(t, supportRim, ks)
+
+	signerCert, err := VerifyBaseRimSignature(baseRim, []x509.Certificate{*caCert})
+	assert.NoError(t, err)
+	assert.Equal(t, "RIM Signer", signerCert.Subject.CommonName)
+
+	swid, err := ParseBaseRim(baseRim)
+	assert.NoError(t, err)
+	assert.Equal(t, "Example.com", swid.Meta.PlatformManufacturerStr)
+
+	firmwareManifest, err := swid.GetFirmwareManifest(supportRim, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, swid.TagID, firmwareManifest.Source)
+	assert.Equal(t, "1.0.13", firmwareManifest.BiosVersion)
+	// PCR0 and PCR7 of the SHA1 and SHA256 banks, the PCR8 events are not firmware measurements
+	assert.Len(t, firmwareManifest.Pcrs, 4)
+	_, err = types.NewFirmwarePlatformFlavor(firmwareManifest)
+	assert.NoError(t, err)
+
+	// the support RIM must be the one referenced by the base RIM
+	_, err = swid.GetFirmwareManifest(append(supportRim, 0), nil)
+	assert.Error(t, err)
+
+	// the signer must be issued by a trusted RIM CA
+	otherCaCert, _ := newTestCertificates(t)
+	_, err = VerifyBaseRimSignature(baseRim, []x509.Certificate{*otherCaCert})
+	assert.Error(t, err)
+
+	// the base RIM cannot be modified after signing
+	tampered := strings.Replace(string(baseRim), "1.0.13", "1.0.14", 1)
+	_, err = VerifyBaseRimSignature([]byte(tampered), []x509.Certificate{*caCert})
+	assert.Error(t, err)
+}
@@ -7,7 +7,6 @@ package saml
 
 import (
 	"crypto"
-	"crypto/rsa"
 	"crypto/x509"
 	"strings"
 	"time"
@@ -22,6 +21,7 @@ type legacySamlSigner struct {
 	issuerConfig     IssuerConfiguration
 	validityDuration time.Duration
 	certBytes        []byte
+	signer           crypto.Signer
 }
 
 type legacyMapFormatter struct {
@@ -41,8 +41,9 @@ func NewLegacySAML(ic IssuerConfiguration) (SamlSigner, error) {
 	if ic.ValiditySeconds == 0 {
 		return r, errors.New("Invalid ValiditySeconds for IssuerConfiguration")
 	}
-	if ic.PrivateKey == nil {
-		return r, errors.New("No private key assigned to issuer configuration")
+	signer, err := ic.signer()
+	if err != nil {
+		return r, err
 	}
 	if ic.Certificate == nil {
 		return r, errors.New("No certificate assigned to issuer configuration")
@@ -50,14 +51,10 @@ func NewLegacySAML(ic IssuerConfiguration) (SamlSigner, error) {
 	r.issuerConfig = ic
 	r.validityDuration = time.Second * time.Duration(ic.ValiditySeconds)
 	r.certBytes = ic.Certificate.Raw
+	r.signer = signer
 	return r, nil
 }
 
-// GetKeyPair returns the RSA key pair for signing the assertion
-func (ss legacySamlSigner) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
-	return ss.issuerConfig.PrivateKey, ss.certBytes, nil
-}
-
 // GenerateSamlAssertion generates SAML assertion with the input XML formatter
 func (ss legacySamlSigner) GenerateSamlAssertion(f assertionFormatter) (SamlAssertion, error) {
 	r := SamlAssertion{}
@@ -67,7 +64,7 @@ func (ss legacySamlSigner) GenerateSamlAssertion(f assertionFormatter) (SamlAsse
 		return r, errors.Wrap(err, "Failed to generate XML tree for signing")
 	}
 	// sign the xml tree
-	signedTree, err := signXMLTreeLegacy(ss, xml)
+	signedTree, err := signXMLTreeLegacy(ss.signer, ss.certBytes, xml)
 	if err != nil {
 		return r, err
 	}
@@ -214,14 +211,13 @@ func setNS(root *etree.Element, ns string) {
 	return
 }
 
-func signXMLTreeLegacy(ks dsig.X509KeyStore, e *etree.Element) (*etree.Element, error) {
+func signXMLTreeLegacy(signer crypto.Signer, cert []byte, e *etree.Element) (*etree.Element, error) {
 	ctx := &dsig.SigningContext{
 		Hash:          crypto.SHA256,
-		KeyStore:      ks,
 		IdAttribute:   dsig.DefaultIdAttr,
 		Canonicalizer: dsig.MakeC14N10CommentCanonicalizer(),
 	}
-	signedElement, err := ctx.SignEnveloped(e)
+	signedElement, err := signEnveloped(ctx, signer, cert, e)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to sign XML tree")
 	}
@@ -6,7 +6,7 @@
 package saml
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	rtvalidator "github.com/mattermost/xml-roundtrip-validator"
 	"strings"
@@ -26,6 +26,7 @@ type defaultSamlSigner struct {
 	issuerConfig     IssuerConfiguration
 	validityDuration time.Duration
 	certBytes        []byte
+	signer           crypto.Signer
 }
 
 // NewSAML returns an exported interface SamlSigner configured
@@ -41,8 +42,9 @@ func NewSAML(ic IssuerConfiguration) (SamlSigner, error) {
 	if ic.ValiditySeconds == 0 {
 		return nil, errors.New("Invalid ValiditySeconds for IssuerConfiguration")
 	}
-	if ic.PrivateKey == nil {
-		return nil, errors.New("No private key assigned to issuer configuration")
+	signer, err := ic.signer()
+	if err != nil {
+		return nil, err
 	}
 	if ic.Certificate == nil {
 		return nil, errors.New("No certificate assigned to issuer configuration")
@@ -50,14 +52,10 @@ func NewSAML(ic IssuerConfiguration) (SamlSigner, error) {
 	r.issuerConfig = ic
 	r.validityDuration = time.Second * time.Duration(ic.ValiditySeconds)
 	r.certBytes = ic.Certificate.Raw
+	r.signer = signer
 	return &r, nil
 }
 
-// GetKeyPair returns the RSA key pair for signing the assertion
-func (ss *defaultSamlSigner) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
-	return ss.issuerConfig.PrivateKey, ss.certBytes, nil
-}
-
 // GenerateSamlAssertion generates SAML assertion with the input XML formatter
 func (ss *defaultSamlSigner) GenerateSamlAssertion(f assertionFormatter) (SamlAssertion, error) {
 	r := SamlAssertion{}
@@ -67,7 +65,7 @@ func (ss *defaultSamlSigner) GenerateSamlAssertion(f assertionFormatter) (SamlAs
 		return r, errors.Wrap(err, "Failed to generate XML tree for signing")
 	}
 	// sign the xml tree
-	signedTree, err := signXMLTree(ss, xml)
+	signedTree, err := signXMLTree(ss.signer, ss.certBytes, xml)
 	if err != nil {
 		return r, err
 	}
@@ -120,9 +118,9 @@ func ValidateSamlAssertion(sa SamlAssertion, root *x509.Certificate) (*etree.Ele
 	return validated, nil
 }
 
-func signXMLTree(key dsig.X509KeyStore, e *etree.Element) (*etree.Element, error) {
-	ctx := dsig.NewDefaultSigningContext(key)
-	signedElement, err := ctx.SignEnveloped(e)
+func signXMLTree(signer crypto.Signer, cert []byte, e *etree.Element) (*etree.Element, error) {
+	ctx := dsig.NewDefaultSigningContext(nil)
+	signedElement, err := signEnveloped(ctx, signer, cert, e)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to sign XML tree")
 	}
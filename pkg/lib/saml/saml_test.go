@@ -6,10 +6,12 @@
 package saml
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"math/big"
 	"testing"
 	"time"
@@ -140,6 +142,58 @@ func TestFullGenAndSign(t *testing.T) {
 	t.Log(str)
 }
 
+// opaqueSigner hides the private key as a PKCS#11 or TPM signer would
+type opaqueSigner struct {
+	key *rsa.PrivateKey
+}
+
+func (s opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s opaqueSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(r, digest, opts)
+}
+
+func TestFullGenAndSignWithSigner(t *testing.T) {
+	testMap := map[string]string{
+		"test-field-1": "test-val-1",
+		"test-field-2": "test-val-2",
+	}
+	k, c, err := genKeyAndCert()
+	if err != nil {
+		t.Fatal("Failed to generate rsa key:", err.Error())
+	}
+	testIc := IssuerConfiguration{
+		IssuerName:        "http://idp.test.com/metadata.php",
+		IssuerServiceName: "test-idp",
+		ValiditySeconds:   100,
+		Certificate:       c,
+		Signer:            opaqueSigner{key: k},
+	}
+	testSAML, err := NewSAML(testIc)
+	if err != nil {
+		t.Fatal("Failed to create saml object:", err.Error())
+	}
+	assertion, err := testSAML.GenerateSamlAssertion(NewMapFormatter(testMap))
+	if err != nil {
+		t.Fatal("Failed to create saml assertion:", err.Error())
+	}
+	_, err = ValidateSamlAssertion(assertion, c)
+	assert.NoError(t, err)
+
+	legacySAML, err := NewLegacySAML(testIc)
+	if err != nil {
+		t.Fatal("Failed to create legacy saml object:", err.Error())
+	}
+	assertion, err = legacySAML.GenerateSamlAssertion(NewLegacyMapFormatter(testMap))
+	if err != nil {
+		t.Fatal("Failed to create legacy saml assertion:", err.Error())
+	}
+	_, err = ValidateLegacySamlAssertion(assertion, c)
+	assert.NoError(t, err)
+}
+
 func TestFullGenWithoutSign(t *testing.T) {
 	testMap := map[string]string{
 		"test-field-1": "test-val-1",
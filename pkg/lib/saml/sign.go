@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package saml
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+
+	"github.com/beevik/etree"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+// signer returns the signer of the assertions, the PrivateKey is used when no Signer is configured
+func (ic IssuerConfiguration) signer() (crypto.Signer, error) {
+	if ic.Signer != nil {
+		if _, ok := ic.Signer.Public().(*rsa.PublicKey); !ok {
+			return nil, errors.New("Signer assigned to issuer configuration is not an RSA key")
+		}
+		return ic.Signer, nil
+	}
+	if ic.PrivateKey == nil {
+		return nil, errors.New("No private key assigned to issuer configuration")
+	}
+	return ic.PrivateKey, nil
+}
+
+// signEnveloped follows dsig.SigningContext.SignEnveloped, the signature value is created by the crypto.Signer
+// instead of a *rsa.PrivateKey so that the key can be held by a PKCS#11 token or the TPM
+func signEnveloped(ctx *dsig.SigningContext, signer crypto.Signer, cert []byte, el *etree.Element) (*etree.Element, error) {
+	signedInfo, err := constructSignedInfo(ctx, el)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &etree.Element{
+		Tag:   dsig.SignatureTag,
+		Space: ctx.Prefix,
+	}
+	xmlns := "xmlns"
+	if ctx.Prefix != "" {
+		xmlns += ":" + ctx.Prefix
+	}
+	sig.CreateAttr(xmlns, dsig.Namespace)
+	sig.AddChild(signedInfo)
+
+	// the canonical SignedInfo declares the namespaces in scope at its final location within the signed element
+	rootNSCtx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, err
+	}
+	elNSCtx, err := rootNSCtx.SubContext(el)
+	if err != nil {
+		return nil, err
+	}
+	sigNSCtx, err := elNSCtx.SubContext(sig)
+	if err != nil {
+		return nil, err
+	}
+	detachedSignedInfo, err := etreeutils.NSDetatch(sigNSCtx, signedInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := digestElement(ctx, detachedSignedInfo)
+	if err != nil {
+		return nil, err
+	}
+	rawSignature, err := signer.Sign(rand.Reader, digest, ctx.Hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to sign the SignedInfo")
+	}
+
+	signatureValue := createNamespacedElement(ctx, sig, dsig.SignatureValueTag)
+	signatureValue.SetText(base64.StdEncoding.EncodeToString(rawSignature))
+	keyInfo := createNamespacedElement(ctx, sig, dsig.KeyInfoTag)
+	x509Data := createNamespacedElement(ctx, keyInfo, dsig.X509DataTag)
+	x509Certificate := createNamespacedElement(ctx, x509Data, dsig.X509CertificateTag)
+	x509Certificate.SetText(base64.StdEncoding.EncodeToString(cert))
+
+	signed := el.Copy()
+	signed.Child = append(signed.Child, sig)
+	return signed, nil
+}
+
+func constructSignedInfo(ctx *dsig.SigningContext, el *etree.Element) (*etree.Element, error) {
+	digestAlgorithmIdentifier := ctx.GetDigestAlgorithmIdentifier()
+	if digestAlgorithmIdentifier == "" {
+		return nil, errors.New("unsupported hash mechanism")
+	}
+	signatureMethodIdentifier := ctx.GetSignatureMethodIdentifier()
+	if signatureMethodIdentifier == "" {
+		return nil, errors.New("unsupported signature method")
+	}
+	dataId := el.SelectAttrValue(ctx.IdAttribute, "")
+	if dataId == "" {
+		return nil, errors.New("Missing data ID")
+	}
+
+	digest, err := digestElement(ctx, el)
+	if err != nil {
+		return nil, err
+	}
+
+	signedInfo := &etree.Element{
+		Tag:   dsig.SignedInfoTag,
+		Space: ctx.Prefix,
+	}
+	canonicalizationMethod := createNamespacedElement(ctx, signedInfo, dsig.CanonicalizationMethodTag)
+	canonicalizationMethod.CreateAttr(dsig.AlgorithmAttr, string(ctx.Canonicalizer.Algorithm()))
+	signatureMethod := createNamespacedElement(ctx, signedInfo, dsig.SignatureMethodTag)
+	signatureMethod.CreateAttr(dsig.AlgorithmAttr, signatureMethodIdentifier)
+
+	reference := createNamespacedElement(ctx, signedInfo, dsig.ReferenceTag)
+	reference.CreateAttr(dsig.URIAttr, "#"+dataId)
+	transforms := createNamespacedElement(ctx, reference, dsig.TransformsTag)
+	envelopedTransform := createNamespacedElement(ctx, transforms, dsig.TransformTag)
+	envelopedTransform.CreateAttr(dsig.AlgorithmAttr, dsig.EnvelopedSignatureAltorithmId.String())
+	canonicalizationTransform := createNamespacedElement(ctx, transforms, dsig.TransformTag)
+	canonicalizationTransform.CreateAttr(dsig.AlgorithmAttr, string(ctx.Canonicalizer.Algorithm()))
+	digestMethod := createNamespacedElement(ctx, reference, dsig.DigestMethodTag)
+	digestMethod.CreateAttr(dsig.AlgorithmAttr, digestAlgorithmIdentifier)
+	digestValue := createNamespacedElement(ctx, reference, dsig.DigestValueTag)
+	digestValue.SetText(base64.StdEncoding.EncodeToString(digest))
+
+	return signedInfo, nil
+}
+
+func digestElement(ctx *dsig.SigningContext, el *etree.Element) ([]byte, error) {
+	canonical, err := ctx.Canonicalizer.Canonicalize(el)
+	if err != nil {
+		return nil, err
+	}
+	hash := ctx.Hash.New()
+	if _, err = hash.Write(canonical); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+func createNamespacedElement(ctx *dsig.SigningContext, el *etree.Element, tag string) *etree.Element {
+	child := el.CreateElement(tag)
+	child.Space = ctx.Prefix
+	return child
+}
@@ -6,6 +6,7 @@
 package saml
 
 import (
+	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/xml"
@@ -18,6 +19,8 @@ type IssuerConfiguration struct {
 	IssuerName        string
 	IssuerServiceName string
 	ValiditySeconds   int
+	// Signer signs the assertions in place of the PrivateKey, e.g. with an RSA key held in a PKCS#11 token or the TPM
+	Signer crypto.Signer
 }
 
 type SamlAssertion struct {
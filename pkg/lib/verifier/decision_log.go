@@ -0,0 +1,279 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package verifier
+
+//
+// Implements the decision log of the verifications, a compact record of the inputs digests, the rules
+// applied and their outcome, that can be replayed against the archived host manifest and flavors.
+//
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// DecisionLogVersion is the version of the decision log format. Fields are only added to the format, the version
+// changes when the existing fields change meaning.
+const DecisionLogVersion = 1
+
+// DecisionLog is the record of a verification of a host manifest against a flavor, or against a delta flavor
+// merged with its base flavor. The host manifest and the flavors are recorded by the digest of their JSON encoding.
+type DecisionLog struct {
+	Version                         int            `json:"version"`
+	VerifiedAt                      time.Time      `json:"verified_at"`
+	HostHardwareUUID                string         `json:"host_hardware_uuid,omitempty"`
+	HostManifestDigest              string         `json:"host_manifest_digest"`
+	Flavors                         []FlavorDigest `json:"flavors"`
+	SkipFlavorSignatureVerification bool           `json:"skip_flavor_signature_verification,omitempty"`
+	PolicyName                      string         `json:"policy_name,omitempty"`
+	Rules                           []RuleDecision `json:"rules"`
+	Trusted                         bool           `json:"trusted"`
+}
+
+// FlavorDigest identifies a flavor of a decision, the base flavor is listed before the delta flavor
+type FlavorDigest struct {
+	FlavorId uuid.UUID `json:"flavor_id"`
+	Digest   string    `json:"digest"`
+}
+
+// RuleDecision is the outcome of a rule, the faults are listed by name
+type RuleDecision struct {
+	Name     string              `json:"rule_name"`
+	Markers  []common.FlavorPart `json:"markers,omitempty"`
+	PcrBank  string              `json:"pcr_bank,omitempty"`
+	PcrIndex *int                `json:"pcr_index,omitempty"`
+	FlavorId *uuid.UUID          `json:"flavor_id,omitempty"`
+	Trusted  bool                `json:"trusted"`
+	Faults   []string            `json:"faults,omitempty"`
+}
+
+// NewDecisionLog creates the decision log of the trust report of a host manifest verified against the flavors
+func NewDecisionLog(hostManifest *types.HostManifest, flavors []*hvs.SignedFlavor, skipFlavorSignatureVerification bool,
+	verifiedAt time.Time, trustReport *hvs.TrustReport) (*DecisionLog, error) {
+
+	manifestDigest, flavorDigests, err := inputDigests(hostManifest, flavors)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := DecisionLog{
+		Version:                         DecisionLogVersion,
+		VerifiedAt:                      verifiedAt.UTC(),
+		HostHardwareUUID:                hostManifest.HostInfo.HardwareUUID,
+		HostManifestDigest:              manifestDigest,
+		Flavors:                         flavorDigests,
+		SkipFlavorSignatureVerification: skipFlavorSignatureVerification,
+		PolicyName:                      trustReport.PolicyName,
+		Rules:                           []RuleDecision{},
+		Trusted:                         trustReport.Trusted,
+	}
+	for _, result := range trustReport.Results {
+		rule := RuleDecision{
+			Name:     result.Rule.Name,
+			Markers:  result.Rule.Markers,
+			FlavorId: result.FlavorId,
+			Trusted:  result.Trusted,
+		}
+		if result.Rule.ExpectedPcr != nil {
+			pcrIndex := int(result.Rule.ExpectedPcr.Index)
+			rule.PcrBank = string(result.Rule.ExpectedPcr.PcrBank)
+			rule.PcrIndex = &pcrIndex
+		}
+		for _, fault := range result.Faults {
+			rule.Faults = append(rule.Faults, fault.Name)
+		}
+		decision.Rules = append(decision.Rules, rule)
+	}
+	return &decision, nil
+}
+
+// Matches returns true if the decision was made on the host manifest and the flavors
+func (decision *DecisionLog) Matches(hostManifest *types.HostManifest, flavors []*hvs.SignedFlavor) (bool, error) {
+	manifestDigest, flavorDigests, err := inputDigests(hostManifest, flavors)
+	if err != nil {
+		return false, err
+	}
+	return manifestDigest == decision.HostManifestDigest && reflect.DeepEqual(flavorDigests, decision.Flavors), nil
+}
+
+// ReadDecisionLogs reads the decision logs written one after the other by a DecisionLogger
+func ReadDecisionLogs(r io.Reader) ([]DecisionLog, error) {
+	var decisions []DecisionLog
+	decoder := json.NewDecoder(r)
+	for {
+		var decision DecisionLog
+		if err := decoder.Decode(&decision); err == io.EOF {
+			return decisions, nil
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "Error decoding decision log %d", len(decisions)+1)
+		}
+		decisions = append(decisions, decision)
+	}
+}
+
+// ReplayDecision verifies the archived host manifest and flavors of a decision again, at the time of the decision,
+// and returns the differences between the recorded and the replayed decision. The flavors are the flavor of the
+// decision, or its base and delta flavors.
+func ReplayDecision(verifierCertificates VerifierCertificates, decision *DecisionLog, hostManifest *types.HostManifest,
+	flavors []*hvs.SignedFlavor) ([]string, error) {
+
+	if decision.Version > DecisionLogVersion {
+		return nil, errors.Errorf("Decision log version %d is not supported", decision.Version)
+	}
+	if len(flavors) != 1 && len(flavors) != 2 {
+		return nil, errors.New("The flavor, or the base and delta flavors, of the decision must be provided")
+	}
+	matches, err := decision.Matches(hostManifest, flavors)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		return nil, errors.New("The host manifest and flavors do not match the digests of the decision")
+	}
+
+	verifierCertificates.VerificationTime = decision.VerifiedAt
+	v, err := NewVerifier(verifierCertificates)
+	if err != nil {
+		return nil, err
+	}
+	var trustReport *hvs.TrustReport
+	if len(flavors) == 1 {
+		trustReport, err = v.Verify(hostManifest, flavors[0], decision.SkipFlavorSignatureVerification)
+	} else {
+		trustReport, err = v.VerifyDelta(hostManifest, flavors[0], flavors[1], decision.SkipFlavorSignatureVerification)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error replaying the verification")
+	}
+	replayed, err := NewDecisionLog(hostManifest, flavors, decision.SkipFlavorSignatureVerification, decision.VerifiedAt, trustReport)
+	if err != nil {
+		return nil, err
+	}
+	return compareDecisions(decision, replayed), nil
+}
+
+func compareDecisions(decision, replayed *DecisionLog) []string {
+	var differences []string
+	if decision.Trusted != replayed.Trusted {
+		differences = append(differences, fmt.Sprintf("trusted: recorded %t, replayed %t", decision.Trusted, replayed.Trusted))
+	}
+	if decision.PolicyName != replayed.PolicyName {
+		differences = append(differences, fmt.Sprintf("policy: recorded %s, replayed %s", decision.PolicyName, replayed.PolicyName))
+	}
+	if len(decision.Rules) != len(replayed.Rules) {
+		differences = append(differences, fmt.Sprintf("rules: recorded %d, replayed %d", len(decision.Rules), len(replayed.Rules)))
+		return differences
+	}
+	// the rules are compared by their encoding, a decision read from the log has nil in place of empty lists
+	for i := range decision.Rules {
+		recorded, _ := json.Marshal(decision.Rules[i])
+		replayedRule, _ := json.Marshal(replayed.Rules[i])
+		if !bytes.Equal(recorded, replayedRule) {
+			differences = append(differences, fmt.Sprintf("rule %d %s: recorded %+v, replayed %+v", i+1,
+				decision.Rules[i].Name, decision.Rules[i], replayed.Rules[i]))
+		}
+	}
+	return differences
+}
+
+func inputDigests(hostManifest *types.HostManifest, flavors []*hvs.SignedFlavor) (string, []FlavorDigest, error) {
+	manifestDigest, err := jsonDigest(hostManifest)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "Error computing the host manifest digest")
+	}
+	var flavorDigests []FlavorDigest
+	for _, flavor := range flavors {
+		flavorDigest, err := jsonDigest(flavor)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "Error computing the digest of flavor %s", flavor.Flavor.Meta.ID)
+		}
+		flavorDigests = append(flavorDigests, FlavorDigest{FlavorId: flavor.Flavor.Meta.ID, Digest: flavorDigest})
+	}
+	return manifestDigest, flavorDigests, nil
+}
+
+func jsonDigest(v interface{}) (string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	digest := sha512.Sum384(encoded)
+	return "sha384:" + hex.EncodeToString(digest[:]), nil
+}
+
+// NewDecisionLogger returns a Verifier that writes the decision log of every verification to the writer, one JSON
+// object per line. A decision log that can not be written does not fail the verification.
+func NewDecisionLogger(v Verifier, w io.Writer) Verifier {
+	return &decisionLogger{verifier: v, writer: w}
+}
+
+type decisionLogger struct {
+	verifier Verifier
+	writer   io.Writer
+	lock     sync.Mutex
+}
+
+func (d *decisionLogger) Verify(hostManifest *types.HostManifest, signedFlavor *hvs.SignedFlavor, skipFlavorSignatureVerification bool) (*hvs.TrustReport, error) {
+	verifiedAt := d.verificationTime()
+	trustReport, err := d.verifier.Verify(hostManifest, signedFlavor, skipFlavorSignatureVerification)
+	if err == nil {
+		d.write(hostManifest, []*hvs.SignedFlavor{signedFlavor}, skipFlavorSignatureVerification, verifiedAt, trustReport)
+	}
+	return trustReport, err
+}
+
+func (d *decisionLogger) VerifyDelta(hostManifest *types.HostManifest, baseFlavor *hvs.SignedFlavor, deltaFlavor *hvs.SignedFlavor, skipFlavorSignatureVerification bool) (*hvs.TrustReport, error) {
+	verifiedAt := d.verificationTime()
+	trustReport, err := d.verifier.VerifyDelta(hostManifest, baseFlavor, deltaFlavor, skipFlavorSignatureVerification)
+	if err == nil {
+		d.write(hostManifest, []*hvs.SignedFlavor{baseFlavor, deltaFlavor}, skipFlavorSignatureVerification, verifiedAt, trustReport)
+	}
+	return trustReport, err
+}
+
+func (d *decisionLogger) GetVerifierCerts() VerifierCertificates {
+	return d.verifier.GetVerifierCerts()
+}
+
+func (d *decisionLogger) verificationTime() time.Time {
+	if verificationTime := d.verifier.GetVerifierCerts().VerificationTime; !verificationTime.IsZero() {
+		return verificationTime
+	}
+	return time.Now()
+}
+
+func (d *decisionLogger) write(hostManifest *types.HostManifest, flavors []*hvs.SignedFlavor, skipFlavorSignatureVerification bool,
+	verifiedAt time.Time, trustReport *hvs.TrustReport) {
+
+	decision, err := NewDecisionLog(hostManifest, flavors, skipFlavorSignatureVerification, verifiedAt, trustReport)
+	if err != nil {
+		log.WithError(err).Error("Error creating the decision log of the verification")
+		return
+	}
+	encoded, err := json.Marshal(decision)
+	if err != nil {
+		log.WithError(err).Error("Error encoding the decision log of the verification")
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, err = d.writer.Write(append(encoded, '\n')); err != nil {
+		log.WithError(err).Error("Error writing the decision log of the verification")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package verifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadDecisionLogTestData(t *testing.T) (VerifierCertificates, types.HostManifest, []hvs.SignedFlavor) {
+	verifierCertificates, err := createVerifierCertificates(t,
+		"test_data/vmware20/PrivacyCA.pem",
+		"test_data/vmware20/flavor-signer.crt.pem",
+		"test_data/vmware20/cms-ca-cert.pem",
+		"test_data/vmware20/tag-cacerts.pem")
+	assert.NoError(t, err)
+
+	var hostManifest types.HostManifest
+	var signedFlavors []hvs.SignedFlavor
+
+	manifestJSON, err := ioutil.ReadFile("test_data/vmware20/host_manifest.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(manifestJSON, &hostManifest))
+
+	flavorsJSON, err := ioutil.ReadFile("test_data/vmware20/signed_flavors.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(flavorsJSON, &signedFlavors))
+	return verifierCertificates, hostManifest, signedFlavors
+}
+
+func TestDecisionLogReplay(t *testing.T) {
+	verifierCertificates, hostManifest, signedFlavors := loadDecisionLogTestData(t)
+
+	v, err := NewVerifier(verifierCertificates)
+	assert.NoError(t, err)
+	var decisionLog bytes.Buffer
+	logger := NewDecisionLogger(v, &decisionLog)
+	for i := range signedFlavors {
+		_, err = logger.Verify(&hostManifest, &signedFlavors[i], false)
+		assert.NoError(t, err)
+	}
+
+	decisions, err := ReadDecisionLogs(&decisionLog)
+	assert.NoError(t, err)
+	assert.Len(t, decisions, len(signedFlavors))
+
+	for i := range decisions {
+		flavors := []*hvs.SignedFlavor{&signedFlavors[i]}
+		matches, err := decisions[i].Matches(&hostManifest, flavors)
+		assert.NoError(t, err)
+		assert.True(t, matches)
+		assert.NotEmpty(t, decisions[i].Rules)
+
+		differences, err := ReplayDecision(verifierCertificates, &decisions[i], &hostManifest, flavors)
+		assert.NoError(t, err)
+		assert.Empty(t, differences)
+
+		// a tampered decision is not reproduced
+		tampered := decisions[i]
+		tampered.Trusted = !tampered.Trusted
+		differences, err = ReplayDecision(verifierCertificates, &tampered, &hostManifest, flavors)
+		assert.NoError(t, err)
+		assert.Len(t, differences, 1)
+	}
+
+	// the decision can not be replayed on other evidence
+	otherManifest := hostManifest
+	otherManifest.HostInfo.HostName = "other-host"
+	_, err = ReplayDecision(verifierCertificates, &decisions[0], &otherManifest, []*hvs.SignedFlavor{&signedFlavors[0]})
+	assert.Error(t, err)
+}
+
+func TestDecisionLogReplayAtVerificationTime(t *testing.T) {
+	verifierCertificates, hostManifest, signedFlavors := loadDecisionLogTestData(t)
+
+	// once the flavor signing certificate has expired the flavors are no longer trusted, the decisions made then
+	// are reproduced at their time
+	expiredCertificates := verifierCertificates
+	expiredCertificates.VerificationTime = verifierCertificates.FlavorSigningCertificate.NotAfter.Add(time.Hour)
+	v, err := NewVerifier(expiredCertificates)
+	assert.NoError(t, err)
+	var decisionLog bytes.Buffer
+	_, err = NewDecisionLogger(v, &decisionLog).Verify(&hostManifest, &signedFlavors[0], false)
+	assert.NoError(t, err)
+
+	decisions, err := ReadDecisionLogs(&decisionLog)
+	assert.NoError(t, err)
+	assert.Len(t, decisions, 1)
+	assert.False(t, decisions[0].Trusted)
+	assert.True(t, decisions[0].VerifiedAt.Equal(expiredCertificates.VerificationTime))
+
+	differences, err := ReplayDecision(verifierCertificates, &decisions[0], &hostManifest, []*hvs.SignedFlavor{&signedFlavors[0]})
+	assert.NoError(t, err)
+	assert.Empty(t, differences)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package verifier
+
+//
+// Verifies archived evidence offline, without HVS.
+//
+
+import (
+	"crypto/x509"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// CertificatePaths are the PEM files, or directories of PEM files, holding the certificates HVS verifies the
+// evidence with
+type CertificatePaths struct {
+	PrivacyCA         string
+	AssetTagCA        string
+	FlavorSigningCert string
+	FlavorCA          string
+	// FlavorCoSigningCerts is optional
+	FlavorCoSigningCerts string
+}
+
+// LoadVerifierCertificates loads the certificates of the paths, the certificates following the flavor signing
+// certificate in its file are its intermediate CAs and are trusted as HVS does
+func LoadVerifierCertificates(paths CertificatePaths) (VerifierCertificates, error) {
+	privacyCAs, err := loadCertificates(paths.PrivacyCA)
+	if err != nil {
+		return VerifierCertificates{}, err
+	}
+	tagCAs, err := loadCertificates(paths.AssetTagCA)
+	if err != nil {
+		return VerifierCertificates{}, err
+	}
+	signingCerts, err := loadCertificates(paths.FlavorSigningCert)
+	if err != nil {
+		return VerifierCertificates{}, err
+	}
+	flavorCAs, err := loadCertificates(paths.FlavorCA)
+	if err != nil {
+		return VerifierCertificates{}, err
+	}
+	if len(signingCerts) == 0 {
+		return VerifierCertificates{}, errors.New("No flavor signing certificate in " + paths.FlavorSigningCert)
+	}
+	var coSigningCerts []*x509.Certificate
+	if paths.FlavorCoSigningCerts != "" {
+		certs, err := loadCertificates(paths.FlavorCoSigningCerts)
+		if err != nil {
+			return VerifierCertificates{}, err
+		}
+		for i := range certs {
+			coSigningCerts = append(coSigningCerts, &certs[i])
+		}
+	}
+
+	flavorCAPool := crypt.GetCertPool(flavorCAs)
+	for i := range signingCerts[1:] {
+		flavorCAPool.AddCert(&signingCerts[i+1])
+	}
+	return VerifierCertificates{
+		PrivacyCACertificates:       crypt.GetCertPool(privacyCAs),
+		AssetTagCACertificates:      crypt.GetCertPool(tagCAs),
+		FlavorSigningCertificate:    &signingCerts[0],
+		FlavorCACertificates:        flavorCAPool,
+		FlavorCoSigningCertificates: coSigningCerts,
+	}, nil
+}
+
+func loadCertificates(path string) ([]x509.Certificate, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the certificates")
+	}
+	if info.IsDir() {
+		return crypt.GetCertsFromDir(path)
+	}
+	certs, err := crypt.GetSubjectCertsMapFromPemFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the certificates from "+path)
+	}
+	return certs, nil
+}
+
+// VerifyEvidence verifies a host manifest against each of the signed flavors and returns the trust report
+// collecting their results, the host is trusted when all the flavors are. A delta flavor is verified merged with
+// its base flavor, which must be one of the flavors, and the base flavor is not verified on its own.
+func VerifyEvidence(verifierCertificates VerifierCertificates, hostManifest *types.HostManifest,
+	signedFlavors []hvs.SignedFlavor, skipFlavorSignatureVerification bool) (*hvs.TrustReport, error) {
+
+	if hostManifest == nil {
+		return nil, errors.New("The host manifest cannot be nil")
+	}
+	if len(signedFlavors) == 0 {
+		return nil, errors.New("At least one flavor must be provided")
+	}
+
+	v, err := NewVerifier(verifierCertificates)
+	if err != nil {
+		return nil, err
+	}
+
+	flavors := map[uuid.UUID]*hvs.SignedFlavor{}
+	baseFlavors := map[uuid.UUID]bool{}
+	for i := range signedFlavors {
+		flavors[signedFlavors[i].Flavor.Meta.ID] = &signedFlavors[i]
+		if baseFlavorID := signedFlavors[i].Flavor.Meta.BaseFlavorID; baseFlavorID != nil {
+			baseFlavors[*baseFlavorID] = true
+		}
+	}
+
+	collectiveTrustReport := hvs.TrustReport{
+		HostManifest: *hostManifest,
+	}
+	for i := range signedFlavors {
+		signedFlavor := &signedFlavors[i]
+		meta := signedFlavor.Flavor.Meta
+		if baseFlavors[meta.ID] {
+			continue
+		}
+
+		var trustReport *hvs.TrustReport
+		if meta.BaseFlavorID != nil {
+			baseFlavor, ok := flavors[*meta.BaseFlavorID]
+			if !ok {
+				return nil, errors.Errorf("The base flavor %s of delta flavor %s was not provided", meta.BaseFlavorID, meta.ID)
+			}
+			trustReport, err = v.VerifyDelta(hostManifest, baseFlavor, signedFlavor, skipFlavorSignatureVerification)
+		} else {
+			trustReport, err = v.Verify(hostManifest, signedFlavor, skipFlavorSignatureVerification)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error verifying flavor %s", meta.ID)
+		}
+
+		if collectiveTrustReport.PolicyName == "" {
+			collectiveTrustReport.PolicyName = trustReport.PolicyName
+		}
+		collectiveTrustReport.AddResults(trustReport.Results)
+	}
+	collectiveTrustReport.Trusted = collectiveTrustReport.IsTrusted()
+	return &collectiveTrustReport, nil
+}
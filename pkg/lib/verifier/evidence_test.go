@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package verifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	flavormodel "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEvidenceVMWare20(t *testing.T) {
+	verifierCertificates, err := LoadVerifierCertificates(CertificatePaths{
+		PrivacyCA:         "test_data/vmware20/PrivacyCA.pem",
+		AssetTagCA:        "test_data/vmware20/tag-cacerts.pem",
+		FlavorSigningCert: "test_data/vmware20/flavor-signer.crt.pem",
+		FlavorCA:          "test_data/vmware20/cms-ca-cert.pem",
+	})
+	assert.NoError(t, err)
+
+	var hostManifest types.HostManifest
+	manifestJSON, err := ioutil.ReadFile("test_data/vmware20/host_manifest.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(manifestJSON, &hostManifest))
+
+	var signedFlavors []hvs.SignedFlavor
+	flavorsJSON, err := ioutil.ReadFile("test_data/vmware20/signed_flavors.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(flavorsJSON, &signedFlavors))
+
+	// the results of all the flavors are collected in the trust report
+	trustReport, err := VerifyEvidence(verifierCertificates, &hostManifest, signedFlavors, true)
+	assert.NoError(t, err)
+	assert.True(t, trustReport.Trusted)
+	for _, signedFlavor := range signedFlavors {
+		assert.NotEmpty(t, trustReport.GetResultsForMarker(signedFlavor.Flavor.Meta.Description.FlavorPart))
+	}
+
+	// a delta flavor replaces its base flavor
+	baseFlavor := signedFlavors[0]
+	pcr17, err := baseFlavor.Flavor.GetPcrValue(types.SHA256, types.PCR17)
+	assert.NoError(t, err)
+	baseFlavorID := baseFlavor.Flavor.Meta.ID
+	deltaFlavor := hvs.SignedFlavor{
+		Flavor: flavormodel.Flavor{
+			Meta: flavormodel.Meta{
+				ID:           uuid.New(),
+				BaseFlavorID: &baseFlavorID,
+				Description:  baseFlavor.Flavor.Meta.Description,
+				Vendor:       baseFlavor.Flavor.Meta.Vendor,
+			},
+			Pcrs: map[string]map[string]flavormodel.PcrEx{
+				string(types.SHA256): {types.PCR17.String(): {Value: strings.Repeat("0", len(pcr17.Value))}},
+			},
+		},
+	}
+	trustReport, err = VerifyEvidence(verifierCertificates, &hostManifest, []hvs.SignedFlavor{baseFlavor, deltaFlavor}, true)
+	assert.NoError(t, err)
+	assert.False(t, trustReport.Trusted)
+	for _, result := range trustReport.Results {
+		assert.Equal(t, deltaFlavor.Flavor.Meta.ID, *result.FlavorId)
+	}
+
+	// the base flavor of a delta flavor must be provided
+	_, err = VerifyEvidence(verifierCertificates, &hostManifest, []hvs.SignedFlavor{deltaFlavor}, true)
+	assert.Error(t, err)
+}
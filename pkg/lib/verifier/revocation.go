@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package verifier
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier/rules"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+type cachedCrl struct {
+	revokedSerials map[string]bool
+	expiry         time.Time
+}
+
+type revocationChecker struct {
+	httpClient   *http.Client
+	crlCacheTime time.Duration
+	ocspCheck    bool
+
+	mutex sync.Mutex
+	crls  map[string]*cachedCrl
+}
+
+// NewRevocationChecker returns a RevocationChecker fetching the CRLs from the distribution points of the
+// certificates. The CRLs are cached until their next update, for crlCacheTime at most. When ocspCheck is set,
+// the OCSP responders of the certificates are queried first and the CRLs are only used when they fail.
+func NewRevocationChecker(httpClient *http.Client, crlCacheTime time.Duration, ocspCheck bool) rules.RevocationChecker {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &revocationChecker{
+		httpClient:   httpClient,
+		crlCacheTime: crlCacheTime,
+		ocspCheck:    ocspCheck,
+		crls:         make(map[string]*cachedCrl),
+	}
+}
+
+func (rc *revocationChecker) IsRevoked(cert *x509.Certificate, issuer *x509.Certificate) (bool, error) {
+	if rc.ocspCheck && len(cert.OCSPServer) > 0 {
+		revoked, err := rc.checkOcsp(cert, issuer)
+		if err == nil {
+			return revoked, nil
+		}
+		log.WithError(err).Debugf("OCSP check of the certificate '%s' failed, checking the CRLs", cert.Subject.CommonName)
+	}
+
+	if len(cert.CRLDistributionPoints) == 0 {
+		if rc.ocspCheck && len(cert.OCSPServer) > 0 {
+			return false, errors.Errorf("The revocation status of the certificate '%s' could not be determined", cert.Subject.CommonName)
+		}
+		// the certificates without any revocation information can not be revoked
+		return false, nil
+	}
+
+	var lastErr error
+	for _, url := range cert.CRLDistributionPoints {
+		crl, err := rc.getCrl(url, issuer)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return crl.revokedSerials[cert.SerialNumber.String()], nil
+	}
+	return false, lastErr
+}
+
+func (rc *revocationChecker) checkOcsp(cert *x509.Certificate, issuer *x509.Certificate) (bool, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "Error creating the OCSP request")
+	}
+
+	var lastErr error
+	for _, server := range cert.OCSPServer {
+		body, err := rc.post(server, "application/ocsp-request", request)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		response, err := ocsp.ParseResponseForCert(body, cert, issuer)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "Error parsing the OCSP response of %s", server)
+			continue
+		}
+		switch response.Status {
+		case ocsp.Good:
+			return false, nil
+		case ocsp.Revoked:
+			return true, nil
+		default:
+			lastErr = errors.Errorf("The OCSP responder %s does not know the certificate", server)
+		}
+	}
+	return false, lastErr
+}
+
+// getCrl returns the cached CRL of the distribution point, the CRL is fetched and its signature is verified
+// against the issuer when it is not cached or has expired
+func (rc *revocationChecker) getCrl(url string, issuer *x509.Certificate) (*cachedCrl, error) {
+	now := time.Now()
+	rc.mutex.Lock()
+	crl, ok := rc.crls[url]
+	rc.mutex.Unlock()
+	if ok && now.Before(crl.expiry) {
+		return crl, nil
+	}
+
+	body, err := rc.get(url)
+	if err != nil {
+		return nil, err
+	}
+	certList, err := x509.ParseCRL(body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the CRL of %s", url)
+	}
+	if err = issuer.CheckCRLSignature(certList); err != nil {
+		return nil, errors.Wrapf(err, "The CRL of %s is not signed by the issuer '%s'", url, issuer.Subject.CommonName)
+	}
+
+	crl = newCachedCrl(certList, now.Add(rc.crlCacheTime))
+	rc.mutex.Lock()
+	rc.crls[url] = crl
+	rc.mutex.Unlock()
+	return crl, nil
+}
+
+func newCachedCrl(certList *pkix.CertificateList, expiry time.Time) *cachedCrl {
+	crl := &cachedCrl{
+		revokedSerials: make(map[string]bool),
+		expiry:         expiry,
+	}
+	if nextUpdate := certList.TBSCertList.NextUpdate; !nextUpdate.IsZero() && nextUpdate.Before(expiry) {
+		crl.expiry = nextUpdate
+	}
+	for _, revoked := range certList.TBSCertList.RevokedCertificates {
+		crl.revokedSerials[revoked.SerialNumber.String()] = true
+	}
+	return crl
+}
+
+func (rc *revocationChecker) get(url string) ([]byte, error) {
+	response, err := rc.httpClient.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error fetching %s", url)
+	}
+	return readResponse(url, response)
+}
+
+func (rc *revocationChecker) post(url string, contentType string, body []byte) ([]byte, error) {
+	response, err := rc.httpClient.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error querying %s", url)
+	}
+	return readResponse(url, response)
+}
+
+func readResponse(url string, response *http.Response) ([]byte, error) {
+	defer func() {
+		derr := response.Body.Close()
+		if derr != nil {
+			log.WithError(derr).Error("Error closing response body")
+		}
+	}()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s returned status %d", url, response.StatusCode)
+	}
+	return ioutil.ReadAll(response.Body)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package verifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationChecker(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Privacy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDer)
+	assert.NoError(t, err)
+
+	crlRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		crlRequests++
+		crl, err := caCert.CreateCRL(rand.Reader, caKey, []pkix.RevokedCertificate{
+			{SerialNumber: big.NewInt(2), RevocationTime: time.Now()},
+		}, time.Now(), time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		_, _ = w.Write(crl)
+	}))
+	defer server.Close()
+
+	newCert := func(serial int64, crlUrl string) *x509.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "AIK"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		if crlUrl != "" {
+			template.CRLDistributionPoints = []string{crlUrl}
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		assert.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		assert.NoError(t, err)
+		return cert
+	}
+
+	checker := NewRevocationChecker(server.Client(), time.Minute, false)
+
+	revoked, err := checker.IsRevoked(newCert(2, server.URL), caCert)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = checker.IsRevoked(newCert(3, server.URL), caCert)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	// the CRL is cached
+	assert.Equal(t, 1, crlRequests)
+
+	revoked, err = checker.IsRevoked(newCert(2, ""), caCert)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	// a CRL not signed by the issuer is rejected
+	_, err = checker.IsRevoked(newCert(2, server.URL+"/other"), newCert(4, ""))
+	assert.Error(t, err)
+}
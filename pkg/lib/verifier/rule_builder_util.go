@@ -7,7 +7,6 @@ package verifier
 import (
 	"crypto/sha512"
 	"crypto/x509"
-	"encoding/asn1"
 
 	asset_tag "github.com/intel-secl/intel-secl/v3/pkg/lib/asset-tag"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
@@ -57,7 +56,7 @@ func getPcrEventLogEqualsExcludingRules(pcrs []types.PcrIndex, flavor *hvs.Flavo
 					EventLogs: expectedPcrEx.Event,
 				}
 				expectedPcr, _ := rules.FlavorPcr2ManifestPcr(&expectedPcrEx, types.SHAAlgorithm(bank), index)
-				rule, err := rules.NewPcrEventLogEqualsExcluding(&expectedEventLogEntry, expectedPcr, flavor.Meta.ID, marker)
+				rule, err := rules.NewPcrEventLogEqualsExcluding(&expectedEventLogEntry, expectedPcr, expectedPcrEx.Exclude, flavor.Meta.ID, marker)
 				if err != nil {
 					return nil, errors.Wrapf(err, "An error occurred creating a PcrEventLogEqualsExcluding rule for bank '%s', index '%s'", bank, index)
 				}
@@ -86,7 +85,7 @@ func getPcrEventLogEqualsRules(pcrs []types.PcrIndex, flavor *hvs.Flavor, marker
 					EventLogs: expectedPcrEx.Event,
 				}
 
-				rule, err := rules.NewPcrEventLogEquals(&expectedEventLogEntry, flavor.Meta.ID, marker)
+				rule, err := rules.NewPcrEventLogEquals(&expectedEventLogEntry, expectedPcrEx.Exclude, flavor.Meta.ID, marker)
 				if err != nil {
 					return nil, errors.Wrapf(err, "An error occurred creating a PcrEventLogEqualsExcluding rule for bank '%s', index '%s'", bank, index)
 				}
@@ -141,8 +140,7 @@ func getAssetTagMatchesRule(flavor *hvs.Flavor) (rules.Rule, error) {
 
 	tags := make([]asset_tag.TagKvAttribute, 0)
 	for _, extensions := range assetTagCertficate.Extensions {
-		var tagAttribute asset_tag.TagKvAttribute
-		_, err = asn1.Unmarshal(extensions.Value, &tagAttribute)
+		tagAttribute, err := asset_tag.UnmarshalTagKvAttribute(extensions.Value)
 		if err != nil {
 			return nil, errors.Wrap(err, "Error parsing asset tag attribute")
 		}
@@ -199,7 +197,7 @@ func getPcrEventLogIncludesRules(pcrs []types.PcrIndex, flavor *hvs.Flavor, mark
 				}
 
 				expectedPcr, _ := rules.FlavorPcr2ManifestPcr(&expectedPcrEx, types.SHAAlgorithm(bank), index)
-				rule, err := rules.NewPcrEventLogIncludes(&expectedEventLogEntry, expectedPcr, marker)
+				rule, err := rules.NewPcrEventLogIncludes(&expectedEventLogEntry, expectedPcr, expectedPcrEx.Exclude, marker)
 				if err != nil {
 					return nil, errors.Wrapf(err, "An error occurred creating a PcrEventLogEqualsExcluding rule for bank '%s', index '%s'", bank, index)
 				}
@@ -23,6 +23,7 @@ type ruleBuilder interface {
 	GetOsRules() ([]rules.Rule, error)
 	GetHostUniqueRules() ([]rules.Rule, error)
 	GetSoftwareRules() ([]rules.Rule, error)
+	GetContainerImageRules() ([]rules.Rule, error)
 	GetName() string
 }
 
@@ -79,6 +80,8 @@ func (factory *ruleFactory) GetVerificationRules() ([]rules.Rule, string, error)
 		requiredRules, err = ruleBuilder.GetHostUniqueRules()
 	case common.FlavorPartSoftware:
 		requiredRules, err = ruleBuilder.GetSoftwareRules()
+	case common.FlavorPartContainerImage:
+		requiredRules, err = ruleBuilder.GetContainerImageRules()
 	default:
 		return nil, "", errors.Errorf("Cannot build requiredRules for unknown flavor part %s", flavorPart)
 	}
@@ -96,9 +99,10 @@ func (factory *ruleFactory) GetVerificationRules() ([]rules.Rule, string, error)
 			return nil, "", errors.Wrap(err, "Could not retrieve flavor part name")
 		}
 
-		flavorTrusted, err := rules.NewFlavorTrusted(factory.signedFlavor,
-			factory.verifierCertificates.FlavorSigningCertificate,
+		flavorTrusted, err := rules.NewFlavorTrustedQuorum(factory.signedFlavor,
+			factory.verifierCertificates.flavorSigningCertificates(),
 			factory.verifierCertificates.FlavorCACertificates,
+			factory.verifierCertificates.FlavorSignatureQuorum,
 			flavorPart)
 
 		if err != nil {
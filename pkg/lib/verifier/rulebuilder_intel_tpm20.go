@@ -43,9 +43,13 @@ func (builder *ruleBuilderIntelTpm20) GetName() string {
 
 // From 'design' repo at isecl/libraries/verifier/verifier.md...
 // AikCertificateTrusted
+// QuoteNonceBound (if the verifier has a quote requester identity)
+// QuoteFresh (if the verifier has a maximum quote age or clock skew threshold)
+// QuoteDigestMatches
 // PcrMatchesConstant depend on HW features present in flavor
 // PcrEventLogEqualsExcluding rule for PCR 17, 18
 // PcrEventLogIntegrity rule for PCR 17,18 (if tboot is installed)
+// FirmwareVersionsMatch (if the flavor has firmware versions)
 // FlavorTrusted (added in verifierimpl)
 func (builder *ruleBuilderIntelTpm20) GetPlatformRules() ([]rules.Rule, error) {
 
@@ -61,6 +65,41 @@ func (builder *ruleBuilderIntelTpm20) GetPlatformRules() ([]rules.Rule, error) {
 
 	results = append(results, aikCertificateTrusted)
 
+	//
+	// Add 'QuoteNonceBound' rule...
+	//
+	if builder.verifierCertificates.QuoteRequesterIdentity != "" {
+		quoteNonceBound, err := rules.NewQuoteNonceBound(builder.verifierCertificates.QuoteRequesterIdentity, common.FlavorPartPlatform)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, quoteNonceBound)
+	}
+
+	//
+	// Add 'QuoteFresh' rule...
+	//
+	if builder.verifierCertificates.MaxQuoteAge != 0 || builder.verifierCertificates.ClockSkewThreshold != 0 {
+		quoteFresh, err := rules.NewQuoteFresh(builder.verifierCertificates.MaxQuoteAge,
+			builder.verifierCertificates.ClockSkewThreshold, common.FlavorPartPlatform)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, quoteFresh)
+	}
+
+	//
+	// Add 'QuoteDigestMatches' rule...
+	//
+	quoteDigestMatches, err := rules.NewQuoteDigestMatches(common.FlavorPartPlatform)
+	if err != nil {
+		return nil, err
+	}
+
+	results = append(results, quoteDigestMatches)
+
 	//
 	// Add 'PcrMatchesConstant' rules...
 	//
@@ -100,6 +139,19 @@ func (builder *ruleBuilderIntelTpm20) GetPlatformRules() ([]rules.Rule, error) {
 		results = append(results, pcrEventLogIntegrityRules...)
 	}
 
+	//
+	// Add 'FirmwareVersionsMatch' rule when the flavor has the firmware versions of the BMC platform inventory...
+	//
+	hardware := builder.signedFlavor.Flavor.Hardware
+	if hardware != nil && len(hardware.Firmware) > 0 {
+		firmwareVersionsMatch, err := rules.NewFirmwareVersionsMatch(hardware.Firmware, common.FlavorPartPlatform)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, firmwareVersionsMatch)
+	}
+
 	return results, nil
 }
 
@@ -294,6 +346,40 @@ func (builder *ruleBuilderIntelTpm20) GetSoftwareRules() ([]rules.Rule, error) {
 	return results, nil
 }
 
+// AikCertificateTrusted
+// ContainerImagesMatch
+// FlavorTrusted (added in verifierimpl)
+func (builder *ruleBuilderIntelTpm20) GetContainerImageRules() ([]rules.Rule, error) {
+
+	var results []rules.Rule
+
+	//
+	// Add 'AikCertificateTrusted' rule...
+	//
+	aikCertificateTrusted, err := rules.NewAikCertificateTrusted(builder.verifierCertificates.PrivacyCACertificates, common.FlavorPartContainerImage)
+	if err != nil {
+		return nil, err
+	}
+
+	results = append(results, aikCertificateTrusted)
+
+	//
+	// Add 'ContainerImagesMatch' rule...
+	//
+	if len(builder.signedFlavor.Flavor.ContainerImages) == 0 {
+		return nil, errors.New("'ContainerImages' was not present in the flavor")
+	}
+
+	containerImagesMatch, err := rules.NewContainerImagesMatch(builder.signedFlavor.Flavor.Meta.ID, builder.signedFlavor.Flavor.ContainerImages)
+	if err != nil {
+		return nil, err
+	}
+
+	results = append(results, containerImagesMatch)
+
+	return results, nil
+}
+
 // Based on the manifest's hardware metadata, return the correct PCRs...
 //   - Always match on PCR0
 //   - If CBNT is enabled and profile 5: Add PCR7
@@ -13,6 +13,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier/rules"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
 )
 
 type ruleBuilderVMWare12 struct {
@@ -169,3 +170,8 @@ func (builder *ruleBuilderVMWare12) GetHostUniqueRules() ([]rules.Rule, error) {
 func (builder *ruleBuilderVMWare12) GetSoftwareRules() ([]rules.Rule, error) {
 	return nil, nil
 }
+
+// The workload agent does not run on VMware hosts, their container images are not measured
+func (builder *ruleBuilderVMWare12) GetContainerImageRules() ([]rules.Rule, error) {
+	return nil, errors.New("CONTAINER_IMAGE flavors are not supported on VMware hosts")
+}
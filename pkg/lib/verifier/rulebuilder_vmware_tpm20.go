@@ -13,6 +13,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier/rules"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
 )
 
 type ruleBuilderVMWare20 struct {
@@ -202,3 +203,8 @@ func (builder *ruleBuilderVMWare20) GetHostUniqueRules() ([]rules.Rule, error) {
 func (builder *ruleBuilderVMWare20) GetSoftwareRules() ([]rules.Rule, error) {
 	return nil, nil
 }
+
+// The workload agent does not run on VMware hosts, their container images are not measured
+func (builder *ruleBuilderVMWare20) GetContainerImageRules() ([]rules.Rule, error) {
+	return nil, errors.New("CONTAINER_IMAGE flavors are not supported on VMware hosts")
+}
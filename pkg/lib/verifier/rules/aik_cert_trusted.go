@@ -19,6 +19,19 @@ import (
 	"time"
 )
 
+var aikCertificateTrustedDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleAikCertificateTrusted,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs, common.FlavorPartHostUnique},
+	Faults: []string{
+		constants.FaultAikCertificateMissing,
+		constants.FaultAikCertificateExpired,
+		constants.FaultAikCertificateNotYetValid,
+		constants.FaultAikCertificateNotTrusted,
+		constants.FaultAikCertificateRevoked,
+	},
+	Description: "Verifies that the host's AIK certificate is present, within its validity period, issued by a trusted privacy CA and not revoked.",
+})
+
 func NewAikCertificateTrusted(privacyCACertificates *x509.CertPool, marker common.FlavorPart) (Rule, error) {
 
 	if privacyCACertificates == nil {
@@ -35,12 +48,23 @@ func NewAikCertificateTrusted(privacyCACertificates *x509.CertPool, marker commo
 type aikCertTrusted struct {
 	privacyCACertificates *x509.CertPool
 	marker                common.FlavorPart
+	verificationTime      time.Time
+	revocationChecker     RevocationChecker
+}
+
+func (rule *aikCertTrusted) SetVerificationTime(verificationTime time.Time) {
+	rule.verificationTime = verificationTime
+}
+
+func (rule *aikCertTrusted) SetRevocationChecker(checker RevocationChecker) {
+	rule.revocationChecker = checker
 }
 
 // - if the aik is not present in the manifest, raise 'aik missing' fault
 // - if the host cert is not valid, raise 'aik expired' or 'aik not yet valid' faults
 // - check the host's aik against the trustedAuthority certs and raise 'not trusted' fault
 //   if none are valid
+// - if a certificate of the aik chain is revoked, raise 'aik revoked' fault
 func (rule *aikCertTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
 
 	var fault *hvs.Fault
@@ -48,6 +72,7 @@ func (rule *aikCertTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleRe
 	result.Trusted = true // default to true, set to false when fault encountered
 	result.Rule.Name = constants.RuleAikCertificateTrusted
 	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathAIKCertificate)}
 
 	if len(hostManifest.AIKCertificate) == 0 {
 		fault = &hvs.Fault{
@@ -60,28 +85,38 @@ func (rule *aikCertTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleRe
 		if err != nil {
 			return nil, errors.Wrap(err, "Could not retrive the HostManifest's AIK to validate rule AikCertificateTrusted")
 		}
+		// the quotes signed with the AIK are only trusted until the AIK certificate expires
+		notAfter := aik.NotAfter
+		result.ValidUntil = &notAfter
 
-		if time.Now().After(aik.NotAfter) {
+		now := currentTime(rule.verificationTime)
+		if now.After(aik.NotAfter) {
 			fault = &hvs.Fault{
 				Name:        constants.FaultAikCertificateExpired,
 				Description: fmt.Sprintf("AIK certificate not valid after '%s'", aik.NotAfter),
 			}
-		} else if time.Now().Before(aik.NotBefore) {
+		} else if now.Before(aik.NotBefore) {
 			fault = &hvs.Fault{
 				Name:        constants.FaultAikCertificateNotYetValid,
 				Description: fmt.Sprintf("AIK certificate not valid before '%s'", aik.NotBefore),
 			}
 		} else {
 			opts := x509.VerifyOptions{
-				Roots: rule.privacyCACertificates,
+				Roots:       rule.privacyCACertificates,
+				CurrentTime: now,
 			}
 
-			_, err := aik.Verify(opts)
+			chains, err := aik.Verify(opts)
 			if err != nil {
 				fault = &hvs.Fault{
 					Name:        constants.FaultAikCertificateNotTrusted,
 					Description: "AIK certificate is not signed by any trusted CA",
 				}
+			} else if revoked := revokedCertificate(rule.revocationChecker, chains); revoked != nil {
+				fault = &hvs.Fault{
+					Name:        constants.FaultAikCertificateRevoked,
+					Description: fmt.Sprintf("Certificate '%s' of the AIK certificate chain is revoked", revoked.Subject.CommonName),
+				}
 			}
 		}
 	}
@@ -12,6 +12,7 @@ package rules
 import (
 	"bytes"
 	"encoding/base64"
+	"time"
 
 	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
 	asset_tag "github.com/intel-secl/intel-secl/v3/pkg/lib/asset-tag"
@@ -21,6 +22,17 @@ import (
 	"github.com/pkg/errors"
 )
 
+var assetTagMatchesDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleAssetTagMatches,
+	FlavorParts: []common.FlavorPart{common.FlavorPartAssetTag},
+	Faults: []string{
+		constants.FaultAssetTagMissing,
+		constants.FaultAssetTagNotProvisioned,
+		constants.FaultAssetTagMismatch,
+	},
+	Description: "Verifies that the asset tag provisioned on the host matches the digest of the tag certificate in the flavor.",
+})
+
 func NewAssetTagMatches(expectedAssetTagDigest []byte, tags []asset_tag.TagKvAttribute) (Rule, error) {
 
 	assetTagMatches := assetTagMatches{
@@ -34,6 +46,11 @@ func NewAssetTagMatches(expectedAssetTagDigest []byte, tags []asset_tag.TagKvAtt
 type assetTagMatches struct {
 	expectedAssetTagDigest []byte
 	tags                   []asset_tag.TagKvAttribute
+	verificationTime       time.Time
+}
+
+func (rule *assetTagMatches) SetVerificationTime(verificationTime time.Time) {
+	rule.verificationTime = verificationTime
 }
 
 func (rule *assetTagMatches) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
@@ -43,9 +60,14 @@ func (rule *assetTagMatches) Apply(hostManifest *types.HostManifest) (*hvs.RuleR
 	result.Rule.Name = constants.RuleAssetTagMatches
 	result.Rule.ExpectedTag = rule.expectedAssetTagDigest
 	result.Rule.Markers = append(result.Rule.Markers, common.FlavorPartAssetTag)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathAssetTagDigest)}
+	// the tags outside of their activation window are not reported, as if they were not in the tag certificate
+	now := currentTime(rule.verificationTime)
 	tags := map[string]string{}
 	for _, kvAttr := range rule.tags {
-		tags[kvAttr.Key] = kvAttr.Value
+		if kvAttr.ActiveAt(now) {
+			tags[kvAttr.Key] = kvAttr.Value
+		}
 	}
 	result.Rule.Tags = tags
 
@@ -6,8 +6,10 @@ package rules
 
 import (
 	"testing"
+	"time"
 
 	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	asset_tag "github.com/intel-secl/intel-secl/v3/pkg/lib/asset-tag"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -85,3 +87,34 @@ func TestAssetTagMatchesNoFault(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, 0, len(result.Faults))
 }
+
+func TestAssetTagMatchesActivationWindow(t *testing.T) {
+
+	hostManifest := types.HostManifest{
+		AssetTagDigest: validAssetTagString,
+	}
+
+	verificationTime := time.Date(2020, time.October, 1, 0, 0, 0, 0, time.UTC)
+	windowStart := verificationTime.Add(-time.Hour)
+	windowEnd := verificationTime.Add(time.Hour)
+	tags := []asset_tag.TagKvAttribute{
+		{Key: "Country", Value: "US"},
+		{Key: "Maintenance", Value: "true", NotBefore: &windowStart, NotAfter: &windowEnd},
+		{Key: "ForensicHold", Value: "true", NotAfter: &windowStart},
+	}
+	rule, err := NewAssetTagMatches(validAssetTagBytes, tags)
+	assert.NoError(t, err)
+	rule.(TimedRule).SetVerificationTime(verificationTime)
+
+	// the tags are reported within their window only, the trust is not affected
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.True(t, result.Trusted)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.Equal(t, map[string]string{"Country": "US", "Maintenance": "true"}, result.Rule.Tags)
+
+	rule.(TimedRule).SetVerificationTime(windowEnd.Add(time.Second))
+	result, err = rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Country": "US"}, result.Rule.Tags)
+}
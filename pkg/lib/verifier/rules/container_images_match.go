@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that validates that the container images measured by the workload agent
+// match the images expected by a CONTAINER_IMAGE flavor.
+//
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+)
+
+var containerImagesMatchDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleContainerImagesMatch,
+	FlavorParts: []common.FlavorPart{common.FlavorPartContainerImage},
+	Faults: []string{
+		constants.FaultContainerImageMeasurementsMissing,
+		constants.FaultContainerImageMissing,
+		constants.FaultContainerImageDigestMismatch,
+		constants.FaultContainerImageRootHashMismatch,
+	},
+	Description: "Verifies that each container image of the flavor was measured by the workload agent with the digest, and the dm-verity root hash when the flavor has one, in the flavor.",
+})
+
+func NewContainerImagesMatch(flavorID uuid.UUID, expectedImages []ta.ContainerImageMeasurement) (Rule, error) {
+	if len(expectedImages) == 0 {
+		return nil, errors.New("The container images cannot be empty")
+	}
+
+	containerImagesMatch := containerImagesMatch{
+		flavorID:       flavorID,
+		expectedImages: expectedImages,
+	}
+
+	return &containerImagesMatch, nil
+}
+
+type containerImagesMatch struct {
+	flavorID       uuid.UUID
+	expectedImages []ta.ContainerImageMeasurement
+}
+
+// Apply verifies the container images of the flavor against the measurements of the host manifest:
+//   - If the host manifest does not have container image measurements, create a ContainerImageMeasurementsMissing fault.
+//   - If an image of the flavor was not measured, create a ContainerImageMissing fault.
+//   - If the image was measured with another digest, create a ContainerImageDigestMismatch fault.
+//   - If the flavor has the dm-verity root hash of the image and the image was measured with another root hash, create
+//     a ContainerImageRootHashMismatch fault.
+//
+// The images measured on the host that are not in the flavor are not verified, they are verified by their own flavors.
+func (rule *containerImagesMatch) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
+	result := hvs.RuleResult{}
+	result.Trusted = true
+	result.Rule.Name = constants.RuleContainerImagesMatch
+	result.Rule.Markers = append(result.Rule.Markers, common.FlavorPartContainerImage)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathContainerImageMeasurements)}
+	result.Rule.FlavorID = &rule.flavorID
+	result.Rule.ExpectedContainerImages = rule.expectedImages
+
+	if len(hostManifest.ContainerImageMeasurements) == 0 {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultContainerImageMeasurementsMissing,
+			Description: "Host report does not include container image measurements",
+		})
+		return &result, nil
+	}
+
+	for _, expectedImage := range rule.expectedImages {
+		if fault := rule.verifyImage(expectedImage, hostManifest.ContainerImageMeasurements); fault != nil {
+			result.Faults = append(result.Faults, *fault)
+		}
+	}
+
+	return &result, nil
+}
+
+// verifyImage returns the fault of an expected image, nil when one of the measurements of the image matches it
+func (rule *containerImagesMatch) verifyImage(expectedImage ta.ContainerImageMeasurement,
+	measurements []ta.ContainerImageMeasurement) *hvs.Fault {
+
+	var actualDigest, actualRootHash *string
+	for i := range measurements {
+		measurement := measurements[i]
+		if measurement.Name != expectedImage.Name {
+			continue
+		}
+		if !strings.EqualFold(measurement.Digest, expectedImage.Digest) {
+			actualDigest = &measurement.Digest
+			continue
+		}
+		if expectedImage.DmVerityRootHash != "" &&
+			!strings.EqualFold(measurement.DmVerityRootHash, expectedImage.DmVerityRootHash) {
+			actualRootHash = &measurement.DmVerityRootHash
+			continue
+		}
+		return nil
+	}
+
+	imageName := expectedImage.Name
+	switch {
+	case actualRootHash != nil:
+		expectedRootHash := expectedImage.DmVerityRootHash
+		return &hvs.Fault{
+			Name:          constants.FaultContainerImageRootHashMismatch,
+			Description:   "Container image " + imageName + " was measured with a dm-verity root hash that does not match the flavor",
+			MeasurementId: &imageName,
+			ExpectedValue: &expectedRootHash,
+			ActualValue:   actualRootHash,
+		}
+	case actualDigest != nil:
+		expectedDigest := expectedImage.Digest
+		return &hvs.Fault{
+			Name:          constants.FaultContainerImageDigestMismatch,
+			Description:   "Container image " + imageName + " was measured with a digest that does not match the flavor",
+			MeasurementId: &imageName,
+			ExpectedValue: &expectedDigest,
+			ActualValue:   actualDigest,
+		}
+	}
+	return &hvs.Fault{
+		Name:          constants.FaultContainerImageMissing,
+		Description:   "Container image " + imageName + " was not measured on the host",
+		MeasurementId: &imageName,
+	}
+}
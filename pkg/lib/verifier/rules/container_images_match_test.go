@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	nginxImage = ta.ContainerImageMeasurement{
+		Name:             "registry.example.com/nginx:1.19",
+		Digest:           "sha256:7cbc2e5c9f3ab6b0f0a3a5d1c2ed1d5d1a1e5ff0f16fd2aab5b0b0f1b57a5b0e",
+		DmVerityRootHash: "4392b8b08f6e1c0f6b0bd0a3b7b6d9e1c4ee1fd80f0a0e8b7f6c3e1b8f0d7a6c",
+	}
+	redisImage = ta.ContainerImageMeasurement{
+		Name:   "registry.example.com/redis:6.0",
+		Digest: "sha256:1b0a7c5e3f1d2c4b6a8e9f0d1c2b3a4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c",
+	}
+)
+
+func TestContainerImagesMatchNoFault(t *testing.T) {
+
+	// the host runs the images of the flavor and another one, the digests are reported in upper case
+	reportedNginx := nginxImage
+	reportedNginx.Digest = "sha256:7CBC2E5C9F3AB6B0F0A3A5D1C2ED1D5D1A1E5FF0F16FD2AAB5B0B0F1B57A5B0E"
+	hostManifest := types.HostManifest{
+		ContainerImageMeasurements: []ta.ContainerImageMeasurement{
+			reportedNginx,
+			redisImage,
+			{Name: "registry.example.com/busybox:1.32", Digest: "sha256:00"},
+		},
+	}
+
+	rule, err := NewContainerImagesMatch(uuid.New(), []ta.ContainerImageMeasurement{nginxImage, redisImage})
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.True(t, result.Trusted)
+	assert.Equal(t, 2, len(result.Rule.ExpectedContainerImages))
+}
+
+func TestContainerImagesMatchMeasurementsMissing(t *testing.T) {
+
+	hostManifest := types.HostManifest{}
+
+	rule, err := NewContainerImagesMatch(uuid.New(), []ta.ContainerImageMeasurement{nginxImage})
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultContainerImageMeasurementsMissing, result.Faults[0].Name)
+}
+
+func TestContainerImagesMatchImageMissing(t *testing.T) {
+
+	hostManifest := types.HostManifest{
+		ContainerImageMeasurements: []ta.ContainerImageMeasurement{nginxImage},
+	}
+
+	rule, err := NewContainerImagesMatch(uuid.New(), []ta.ContainerImageMeasurement{nginxImage, redisImage})
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultContainerImageMissing, result.Faults[0].Name)
+	assert.Equal(t, redisImage.Name, *result.Faults[0].MeasurementId)
+}
+
+func TestContainerImagesMatchDigestMismatch(t *testing.T) {
+
+	tampered := nginxImage
+	tampered.Digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	hostManifest := types.HostManifest{
+		ContainerImageMeasurements: []ta.ContainerImageMeasurement{tampered},
+	}
+
+	rule, err := NewContainerImagesMatch(uuid.New(), []ta.ContainerImageMeasurement{nginxImage})
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultContainerImageDigestMismatch, result.Faults[0].Name)
+	assert.Equal(t, nginxImage.Digest, *result.Faults[0].ExpectedValue)
+	assert.Equal(t, tampered.Digest, *result.Faults[0].ActualValue)
+}
+
+func TestContainerImagesMatchRootHashMismatch(t *testing.T) {
+
+	tampered := nginxImage
+	tampered.DmVerityRootHash = "0000000000000000000000000000000000000000000000000000000000000000"
+	hostManifest := types.HostManifest{
+		ContainerImageMeasurements: []ta.ContainerImageMeasurement{tampered},
+	}
+
+	rule, err := NewContainerImagesMatch(uuid.New(), []ta.ContainerImageMeasurement{nginxImage})
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultContainerImageRootHashMismatch, result.Faults[0].Name)
+
+	// the root hash is not verified when the flavor does not have it
+	withoutRootHash := nginxImage
+	withoutRootHash.DmVerityRootHash = ""
+	rule, err = NewContainerImagesMatch(uuid.New(), []ta.ContainerImageMeasurement{withoutRootHash})
+	assert.NoError(t, err)
+
+	result, err = rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Faults))
+}
+
+func TestContainerImagesMatchWithoutImages(t *testing.T) {
+
+	_, err := NewContainerImagesMatch(uuid.New(), nil)
+	assert.Error(t, err)
+}
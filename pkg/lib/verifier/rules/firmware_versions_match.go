@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that validates that the firmware inventory read from the BMC of the host
+// has the firmware versions expected by a PLATFORM flavor.
+//
+
+import (
+	"strings"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/pkg/errors"
+)
+
+var firmwareVersionsMatchDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleFirmwareVersionsMatch,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform},
+	Faults: []string{
+		constants.FaultPlatformInventoryMissing,
+		constants.FaultFirmwareMissing,
+		constants.FaultFirmwareVersionMismatch,
+	},
+	Description: "Verifies that the firmware inventory read from the BMC of the host has each firmware of the flavor with the version in the flavor.",
+})
+
+func NewFirmwareVersionsMatch(expectedFirmware []ta.FirmwareComponent, marker common.FlavorPart) (Rule, error) {
+	if len(expectedFirmware) == 0 {
+		return nil, errors.New("The firmware versions cannot be empty")
+	}
+
+	firmwareVersionsMatch := firmwareVersionsMatch{
+		expectedFirmware: expectedFirmware,
+		marker:           marker,
+	}
+
+	return &firmwareVersionsMatch, nil
+}
+
+type firmwareVersionsMatch struct {
+	expectedFirmware []ta.FirmwareComponent
+	marker           common.FlavorPart
+}
+
+// Apply verifies the firmware versions of the flavor against the platform inventory of the host manifest:
+//   - If the host info does not have a platform inventory, create a PlatformInventoryMissing fault.
+//   - If a firmware of the flavor is not in the firmware inventory, create a FirmwareMissing fault.
+//   - If the firmware has another version, create a FirmwareVersionMismatch fault.
+//
+// The firmware of the inventory that is not in the flavor is not verified.
+func (rule *firmwareVersionsMatch) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
+	result := hvs.RuleResult{}
+	result.Trusted = true
+	result.Rule.Name = constants.RuleFirmwareVersionsMatch
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathFirmware)}
+	result.Rule.ExpectedFirmware = rule.expectedFirmware
+
+	platformInventory := hostManifest.HostInfo.PlatformInventory
+	if platformInventory == nil {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultPlatformInventoryMissing,
+			Description: "Host report does not include the platform inventory of the BMC",
+		})
+		return &result, nil
+	}
+
+	for _, expectedFirmware := range rule.expectedFirmware {
+		if fault := verifyFirmware(expectedFirmware, platformInventory.Firmware); fault != nil {
+			result.Faults = append(result.Faults, *fault)
+		}
+	}
+
+	return &result, nil
+}
+
+// verifyFirmware returns the fault of an expected firmware, nil when a firmware of the inventory with the same name
+// has the expected version. Platforms can have several firmware components with the same name, e.g. one per NIC.
+func verifyFirmware(expectedFirmware ta.FirmwareComponent, inventory []ta.FirmwareComponent) *hvs.Fault {
+	var actualVersion *string
+	for i := range inventory {
+		firmware := inventory[i]
+		if firmware.Name != expectedFirmware.Name {
+			continue
+		}
+		if strings.TrimSpace(firmware.Version) == strings.TrimSpace(expectedFirmware.Version) {
+			return nil
+		}
+		actualVersion = &firmware.Version
+	}
+
+	firmwareName := expectedFirmware.Name
+	if actualVersion != nil {
+		expectedVersion := expectedFirmware.Version
+		return &hvs.Fault{
+			Name:          constants.FaultFirmwareVersionMismatch,
+			Description:   "Firmware " + firmwareName + " has a version that does not match the flavor",
+			MeasurementId: &firmwareName,
+			ExpectedValue: &expectedVersion,
+			ActualValue:   actualVersion,
+		}
+	}
+	return &hvs.Fault{
+		Name:          constants.FaultFirmwareMissing,
+		Description:   "Firmware " + firmwareName + " is not in the firmware inventory of the host",
+		MeasurementId: &firmwareName,
+	}
+}
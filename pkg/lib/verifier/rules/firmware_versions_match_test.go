@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"testing"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+var expectedFirmware = []ta.FirmwareComponent{
+	{Name: "BIOS", Version: "2.10.2"},
+	{Name: "Broadcom Gigabit Ethernet BCM5720", Version: "21.60.16"},
+}
+
+func TestFirmwareVersionsMatchNoFault(t *testing.T) {
+
+	// the platform has two NICs with the same name and firmware that is not in the flavor
+	hostManifest := types.HostManifest{}
+	hostManifest.HostInfo.PlatformInventory = &ta.PlatformInventory{
+		Firmware: []ta.FirmwareComponent{
+			{Name: "BIOS", Version: "2.10.2"},
+			{Name: "Broadcom Gigabit Ethernet BCM5720", Version: "21.40.9"},
+			{Name: "Broadcom Gigabit Ethernet BCM5720", Version: "21.60.16"},
+			{Name: "Integrated Remote Access Controller", Version: "5.00.00.00"},
+		},
+	}
+
+	rule, err := NewFirmwareVersionsMatch(expectedFirmware, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.True(t, result.Trusted)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.Equal(t, expectedFirmware, result.Rule.ExpectedFirmware)
+}
+
+func TestFirmwareVersionsMatchPlatformInventoryMissing(t *testing.T) {
+
+	rule, err := NewFirmwareVersionsMatch(expectedFirmware, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultPlatformInventoryMissing, result.Faults[0].Name)
+}
+
+func TestFirmwareVersionsMatchFaults(t *testing.T) {
+
+	hostManifest := types.HostManifest{}
+	hostManifest.HostInfo.PlatformInventory = &ta.PlatformInventory{
+		Firmware: []ta.FirmwareComponent{
+			{Name: "BIOS", Version: "2.9.4"},
+		},
+	}
+
+	rule, err := NewFirmwareVersionsMatch(expectedFirmware, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Faults))
+	assert.Equal(t, constants.FaultFirmwareVersionMismatch, result.Faults[0].Name)
+	assert.Equal(t, "2.10.2", *result.Faults[0].ExpectedValue)
+	assert.Equal(t, "2.9.4", *result.Faults[0].ActualValue)
+	assert.Equal(t, constants.FaultFirmwareMissing, result.Faults[1].Name)
+	assert.Equal(t, "Broadcom Gigabit Ethernet BCM5720", *result.Faults[1].MeasurementId)
+
+	_, err = NewFirmwareVersionsMatch(nil, common.FlavorPartPlatform)
+	assert.Error(t, err)
+}
@@ -13,30 +13,71 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
 )
 
+var flavorTrustedDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleFlavorTrusted,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs, common.FlavorPartHostUnique, common.FlavorPartSoftware, common.FlavorPartAssetTag},
+	Faults: []string{
+		constants.FaultFlavorSignatureMissing,
+		constants.FaultFlavorSignatureNotTrusted,
+		constants.FaultFlavorSignatureVerificationFailed,
+		constants.FaultFlavorSigningCertificateRevoked,
+	},
+	Description: "Verifies that the flavor is signed by the trusted flavor signing certificate, which is not revoked.",
+})
+
 func NewFlavorTrusted(signedFlavor *hvs.SignedFlavor, flavorSigningCertificate *x509.Certificate, flavorCaCertificates *x509.CertPool, marker common.FlavorPart) (Rule, error) {
 
+	return NewFlavorTrustedQuorum(signedFlavor, []*x509.Certificate{flavorSigningCertificate}, flavorCaCertificates, 1, marker)
+}
+
+// NewFlavorTrustedQuorum creates a FlavorTrusted rule requiring the flavor to be signed by at least quorum of the
+// flavor signing certificates, so that no single flavor signing key can make a flavor trusted.
+func NewFlavorTrustedQuorum(signedFlavor *hvs.SignedFlavor, flavorSigningCertificates []*x509.Certificate, flavorCaCertificates *x509.CertPool, quorum int, marker common.FlavorPart) (Rule, error) {
+
+	if quorum > len(flavorSigningCertificates) {
+		return nil, errors.Errorf("The flavor signature quorum %d exceeds the %d flavor signing certificates", quorum, len(flavorSigningCertificates))
+	}
+
 	return &flavorTrusted{
-		signedFlavor:             signedFlavor,
-		flavorId:                 signedFlavor.Flavor.Meta.ID,
-		flavorSigningCertificate: flavorSigningCertificate,
-		flavorCaCertificates:     flavorCaCertificates,
-		marker:                   marker,
+		signedFlavor:              signedFlavor,
+		flavorId:                  signedFlavor.Flavor.Meta.ID,
+		flavorSigningCertificates: flavorSigningCertificates,
+		flavorCaCertificates:      flavorCaCertificates,
+		quorum:                    quorum,
+		marker:                    marker,
 	}, nil
 }
 
 type flavorTrusted struct {
-	signedFlavor             *hvs.SignedFlavor
-	flavorId                 uuid.UUID
-	flavorSigningCertificate *x509.Certificate
-	flavorCaCertificates     *x509.CertPool
-	marker                   common.FlavorPart
+	signedFlavor              *hvs.SignedFlavor
+	flavorId                  uuid.UUID
+	flavorSigningCertificates []*x509.Certificate
+	flavorCaCertificates      *x509.CertPool
+	quorum                    int
+	marker                    common.FlavorPart
+	verificationTime          time.Time
+	revocationChecker         RevocationChecker
+}
+
+func (rule *flavorTrusted) SetVerificationTime(verificationTime time.Time) {
+	rule.verificationTime = verificationTime
+}
+
+func (rule *flavorTrusted) SetRevocationChecker(checker RevocationChecker) {
+	rule.revocationChecker = checker
 }
 
 // - If the flavor does not have a signature create a FaultFlavorSignatureMissing
-// - If the flavor's signature does not verify with the signing certificate and CAs, create a
+// - If none of the signing certificates verify with the CAs, create FaultFlavorSignatureVerificationFailed
+// - If fewer than the quorum of the trusted signing certificates made a valid signature of the flavor, create a
 //   FaultFlavorSignatureNotTrusted
+// - If the signatures fall short of the quorum because of revoked signing certificates, create
+//   FaultFlavorSigningCertificateRevoked
 // - If any errors occur during verification, create FaultFlavorSignatureVerificationFailed
 func (rule *flavorTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
 
@@ -52,7 +93,7 @@ func (rule *flavorTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleRes
 		}
 
 		result.Faults = append(result.Faults, fault)
-	} else if rule.flavorSigningCertificate == nil {
+	} else if len(rule.flavorSigningCertificates) == 0 || rule.flavorSigningCertificates[0] == nil {
 		log.Error("FlavorSignatureVerificationFailed fault: The flavor signing certificate was not provided")
 		result.Faults = append(result.Faults, newFlavorSignatureVerificationFailed(rule.flavorId))
 	} else if rule.flavorCaCertificates == nil {
@@ -60,36 +101,64 @@ func (rule *flavorTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleRes
 		result.Faults = append(result.Faults, newFlavorSignatureVerificationFailed(rule.flavorId))
 	} else {
 
-		// verify the cert and ca...
+		// verify the certs and ca...
 		opts := x509.VerifyOptions{
-			Roots: rule.flavorCaCertificates,
+			Roots:       rule.flavorCaCertificates,
+			CurrentTime: currentTime(rule.verificationTime),
 		}
 
-		_, err := rule.flavorSigningCertificate.Verify(opts)
-		if err != nil {
-			log.Error("FlavorSignatureVerificationFailed fault: The flavor signing certificate did not validate against the CAs")
-			result.Faults = append(result.Faults, newFlavorSignatureVerificationFailed(rule.flavorId))
-			return &result, nil
+		// get the public keys of the trusted certificates for verifying the signed flavor
+		var publicKeys []*rsa.PublicKey
+		var revokedCertificates []string
+		for _, flavorSigningCertificate := range rule.flavorSigningCertificates {
+			if flavorSigningCertificate == nil {
+				continue
+			}
+			chains, err := flavorSigningCertificate.Verify(opts)
+			if err != nil {
+				log.Errorf("The flavor signing certificate '%s' did not validate against the CAs", flavorSigningCertificate.Subject.CommonName)
+				continue
+			}
+			if revoked := revokedCertificate(rule.revocationChecker, chains); revoked != nil {
+				revokedCertificates = append(revokedCertificates, revoked.Subject.CommonName)
+				continue
+			}
+			publicKey, ok := flavorSigningCertificate.PublicKey.(*rsa.PublicKey)
+			if !ok {
+				log.Errorf("Could not get the public key of the flavor signing certificate '%s'", flavorSigningCertificate.Subject.CommonName)
+				continue
+			}
+			publicKeys = append(publicKeys, publicKey)
 		}
 
-		// get the public key for verifying the signed flavor
-		var ok bool
-		var publicKey *rsa.PublicKey
-		if publicKey, ok = rule.flavorSigningCertificate.PublicKey.(*rsa.PublicKey); !ok {
-			log.Error("FlavorSignatureVerificationFailed fault: Could not get the flavor signing certificate's public key")
-			result.Faults = append(result.Faults, newFlavorSignatureVerificationFailed(rule.flavorId))
-			return &result, nil
+		quorum := rule.quorum
+		if quorum < 1 {
+			quorum = 1
 		}
-
-		err = rule.signedFlavor.Verify(publicKey)
-		if err != nil {
-			log.WithError(err).Error("FlavorSignatureVerificationFailed fault: Flavor Signature verification failed")
-			fault := hvs.Fault{
-				Name:        constants.FaultFlavorSignatureNotTrusted,
-				Description: fmt.Sprintf("Signature is not trusted for flavor with id %s", rule.flavorId),
+		signers := 0
+		if len(publicKeys) > 0 {
+			signers = rule.signedFlavor.CountSigners(publicKeys)
+		}
+		if signers < quorum && len(revokedCertificates) > 0 {
+			log.Errorf("FlavorSigningCertificateRevoked fault: Flavor signed by %d of the required %d trusted signers", signers, quorum)
+			result.Faults = append(result.Faults, hvs.Fault{
+				Name: constants.FaultFlavorSigningCertificateRevoked,
+				Description: fmt.Sprintf("Flavor with id %s is not signed by the required trusted signers, the certificates %s are revoked",
+					rule.flavorId, strings.Join(revokedCertificates, ", ")),
+			})
+		} else if len(publicKeys) == 0 {
+			log.Error("FlavorSignatureVerificationFailed fault: No flavor signing certificate validated against the CAs")
+			result.Faults = append(result.Faults, newFlavorSignatureVerificationFailed(rule.flavorId))
+		} else if signers < quorum {
+			log.Errorf("FlavorSignatureVerificationFailed fault: Flavor signed by %d of the required %d trusted signers", signers, quorum)
+			description := fmt.Sprintf("Signature is not trusted for flavor with id %s", rule.flavorId)
+			if quorum > 1 {
+				description += fmt.Sprintf(", signed by %d of the required %d trusted signers", signers, quorum)
 			}
-
-			result.Faults = append(result.Faults, fault)
+			result.Faults = append(result.Faults, hvs.Fault{
+				Name:        constants.FaultFlavorSignatureNotTrusted,
+				Description: description,
+			})
 		}
 	}
 
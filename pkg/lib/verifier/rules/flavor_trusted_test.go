@@ -239,6 +239,63 @@ func TestFlavorTrustedForceSignatureVerificationToFail(t *testing.T) {
 	t.Logf("Fault description: %s", result.Faults[0].Description)
 }
 
+func TestFlavorTrustedQuorum(t *testing.T) {
+
+	// three signers, trusted by adding their certificates to the CAs
+	flavorCaCertificates := x509.NewCertPool()
+	var flavorSigningCertificates []*x509.Certificate
+	var privateKeys []*rsa.PrivateKey
+	for i := 0; i < 3; i++ {
+		flavorSigningCertificate, _, privateKey, err := createCryptoResources()
+		assert.NoError(t, err)
+		flavorCaCertificates.AddCert(flavorSigningCertificate)
+		flavorSigningCertificates = append(flavorSigningCertificates, flavorSigningCertificate)
+		privateKeys = append(privateKeys, privateKey)
+	}
+
+	flavor := hvs.Flavor{
+		Meta: model.Meta{
+			ID: testUuid,
+		},
+	}
+	signedFlavor, err := model.NewSignedFlavor(&flavor, privateKeys[0])
+	assert.NoError(t, err)
+
+	_, err = NewFlavorTrustedQuorum(signedFlavor, flavorSigningCertificates, flavorCaCertificates, 4, common.FlavorPartPlatform)
+	assert.Error(t, err)
+
+	rule, err := NewFlavorTrustedQuorum(signedFlavor, flavorSigningCertificates, flavorCaCertificates, 2, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// a single signer does not meet the quorum...
+	result, err := rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultFlavorSignatureNotTrusted, result.Faults[0].Name)
+
+	// ...even when it signs twice
+	assert.NoError(t, signedFlavor.AddSignature(privateKeys[0]))
+	result, err = rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+
+	// a second signer meets it
+	assert.NoError(t, signedFlavor.AddSignature(privateKeys[2]))
+	result, err = rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Faults))
+
+	// the signatures of untrusted signers are not counted
+	untrustedCertificate, _, _, err := createCryptoResources()
+	assert.NoError(t, err)
+	rule, err = NewFlavorTrustedQuorum(signedFlavor, []*x509.Certificate{flavorSigningCertificates[0], untrustedCertificate}, flavorCaCertificates, 2, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+	result, err = rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultFlavorSignatureNotTrusted, result.Faults[0].Name)
+}
+
 func createCryptoResources() (*x509.Certificate, *x509.CertPool, *rsa.PrivateKey, error) {
 
 	// create a CA certpool...
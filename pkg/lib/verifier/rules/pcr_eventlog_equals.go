@@ -33,21 +33,57 @@ var (
 // This rule implements both PcrEventLogEquals and PcrEventLogEqualsExcluding.  Only
 // the 'new' functions are different, populating the rule name and 'excludes'.
 
-func NewPcrEventLogEquals(expectedEventLogEntry *types.EventLogEntry, flavorID uuid.UUID, marker common.FlavorPart) (Rule, error) {
+var pcrEventLogEqualsDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RulePcrEventLogEquals,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs},
+	Faults: []string{
+		constants.FaultPcrManifestMissing,
+		constants.FaultPcrEventLogMissing,
+		constants.FaultPcrEventLogMissingExpectedEntries,
+		constants.FaultPcrEventLogContainsUnexpectedEntries,
+	},
+	Description: "Verifies that the host's event log for a PCR contains exactly the measurements in the flavor.",
+})
+
+func NewPcrEventLogEquals(expectedEventLogEntry *types.EventLogEntry, exclusions []types.EventLogExclusion, flavorID uuid.UUID, marker common.FlavorPart) (Rule, error) {
+
+	excluder, err := types.NewEventLogExcluder(exclusions)
+	if err != nil {
+		return nil, err
+	}
 
 	// create the rule without the defaultExcludeComponents/labels so that all
-	// events are evaluated (i.e. no 'excludes').
+	// events are evaluated, except for the ones excluded by the flavor.
 	rule := pcrEventLogEquals{
 		expectedEventLogEntry: expectedEventLogEntry,
 		flavorID:              &flavorID,
 		marker:                marker,
 		ruleName:              constants.RulePcrEventLogEquals,
+		exclusions:            exclusions,
+		excluder:              excluder,
 	}
 
 	return &rule, nil
 }
 
-func NewPcrEventLogEqualsExcluding(expectedEventLogEntry *types.EventLogEntry, expectedPcr *types.Pcr, flavorID uuid.UUID, marker common.FlavorPart) (Rule, error) {
+var pcrEventLogEqualsExcludingDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RulePcrEventLogEqualsExcluding,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs},
+	Faults: []string{
+		constants.FaultPcrManifestMissing,
+		constants.FaultPcrEventLogMissing,
+		constants.FaultPcrEventLogMissingExpectedEntries,
+		constants.FaultPcrEventLogContainsUnexpectedEntries,
+	},
+	Description: "Verifies that the host's event log for a PCR contains exactly the measurements in the flavor, ignoring host specific measurements.",
+})
+
+func NewPcrEventLogEqualsExcluding(expectedEventLogEntry *types.EventLogEntry, expectedPcr *types.Pcr, exclusions []types.EventLogExclusion, flavorID uuid.UUID, marker common.FlavorPart) (Rule, error) {
+
+	excluder, err := types.NewEventLogExcluder(exclusions)
+	if err != nil {
+		return nil, err
+	}
 
 	// create the rule providing the defaultExcludeComponents and labels so
 	// they are not included for evaluation during 'Apply'.
@@ -59,6 +95,8 @@ func NewPcrEventLogEqualsExcluding(expectedEventLogEntry *types.EventLogEntry, e
 		excludeComponents:     defaultExcludeComponents,
 		excludeLabels:         defaultExcludeLabels,
 		ruleName:              constants.RulePcrEventLogEqualsExcluding,
+		exclusions:            exclusions,
+		excluder:              excluder,
 	}
 
 	return &rule, nil
@@ -72,13 +110,15 @@ type pcrEventLogEquals struct {
 	ruleName              string
 	excludeComponents     map[string]int
 	excludeLabels         map[string]int
+	exclusions            []types.EventLogExclusion
+	excluder              *types.EventLogExcluder
 }
 
 // - If the PcrManifest is not present in the host manifest, raise PcrEventLogMissing fault.
 // - If the PcrManifest's event log is not present in the host manifest, raise PcrEventLogMissing fault.
 // - Otherwise, strip out pre-defined events from the host manifest's event log (when 'excludes' are
-//   present) and then subtract 'expected' from 'actual'. If the results are not empty, raise a
-//   PcrEventLogContainsUnexpectedEntries fault.
+//   present) and the events matching the flavor's exclusions from both event logs, then subtract
+//   'expected' from 'actual'. If the results are not empty, raise a PcrEventLogContainsUnexpectedEntries fault.
 // - Also report the missing events by subtracting 'actual' from 'expected' and raising a
 //   PcrEventLogMissingExpectedEntries fault.
 func (rule *pcrEventLogEquals) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
@@ -88,7 +128,10 @@ func (rule *pcrEventLogEquals) Apply(hostManifest *types.HostManifest) (*hvs.Rul
 	result.Rule.Name = rule.ruleName
 	result.Rule.ExpectedPcr = rule.expectedPcr
 	result.Rule.ExpectedEventLogEntry = rule.expectedEventLogEntry
+	result.Rule.EventLogExclusions = rule.exclusions
 	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{
+		newPcrEventLogProvenance(rule.expectedEventLogEntry.PcrBank, rule.expectedEventLogEntry.PcrIndex)}
 
 	if hostManifest.PcrManifest.IsEmpty() {
 		result.Faults = append(result.Faults, newPcrManifestMissingFault())
@@ -119,8 +162,15 @@ func (rule *pcrEventLogEquals) Apply(hostManifest *types.HostManifest) (*hvs.Rul
 				}
 			}
 
+			// strip out the events matching the exclusions of the flavor from 'actual' and 'expected'
+			expectedEventLog := rule.expectedEventLogEntry
+			if rule.excluder != nil {
+				actualEventLog = rule.excluder.RemoveExcluded(actualEventLog)
+				expectedEventLog = rule.excluder.RemoveExcluded(expectedEventLog)
+			}
+
 			// now subtract out 'expected'
-			unexpectedEventLogs, err := actualEventLog.Subtract(rule.expectedEventLogEntry)
+			unexpectedEventLogs, err := actualEventLog.Subtract(expectedEventLog)
 			if err != nil {
 				return nil, err
 			}
@@ -131,7 +181,7 @@ func (rule *pcrEventLogEquals) Apply(hostManifest *types.HostManifest) (*hvs.Rul
 			}
 
 			// now, look the other way -- find events that are in actual but not expected (i.e. missing)
-			missingEventLogs, err := rule.expectedEventLogEntry.Subtract(actualEventLog)
+			missingEventLogs, err := expectedEventLog.Subtract(actualEventLog)
 			if err != nil {
 				return nil, err
 			}
@@ -34,7 +34,7 @@ func TestPcrEventLogEqualsNoFault(t *testing.T) {
 
 	newUuid, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	rule, err := NewPcrEventLogEquals(&testHostManifestEventLogEntry, newUuid, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogEquals(&testHostManifestEventLogEntry, nil, newUuid, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -63,7 +63,7 @@ func TestPcrEventLogEqualsExcludingNoFault(t *testing.T) {
 
 	newUuid, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	rule, err := NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, newUuid, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, nil, newUuid, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -114,7 +114,7 @@ func TestPcrEventLogEqualsExcludingPcrEventLogMissingFault(t *testing.T) {
 
 	newUuid, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	rule, err := NewPcrEventLogEqualsExcluding(&flavorEvents, nil, newUuid, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogEqualsExcluding(&flavorEvents, nil, nil, newUuid, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -154,7 +154,7 @@ func TestPcrEventLogEqualsExcludingPcrEventLogContainsUnexpectedEntriesFault(t *
 
 	newUuid, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	rule, err := NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, newUuid, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, nil, newUuid, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -191,7 +191,7 @@ func TestPcrEventLogEqualsExcludingPcrEventLogMissingExpectedEntriesFault(t *tes
 
 	newUuid, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	rule, err := NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, newUuid, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, nil, newUuid, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -201,3 +201,74 @@ func TestPcrEventLogEqualsExcludingPcrEventLogMissingExpectedEntriesFault(t *tes
 	assert.NotNil(t, result.Faults[0].MissingEntries)
 	t.Logf("Fault description: %s", result.Faults[0].Description)
 }
+
+// Add a volatile event to the host manifest with a value that is not in the flavor and
+// provide an exclusion matching its label, expecting no faults.
+func TestPcrEventLogEqualsExclusionNoFault(t *testing.T) {
+
+	flavorEvents := types.EventLogEntry{
+		PcrIndex: types.PCR0,
+		PcrBank:  types.SHA256,
+		EventLogs: []types.EventLog{
+			{
+				DigestType: util.EVENT_LOG_DIGEST_SHA256,
+				Value:      zeros,
+				Label:      "BOOT_COUNTER_1",
+			},
+		},
+	}
+
+	hostEvents := types.EventLogEntry{
+		PcrIndex: types.PCR0,
+		PcrBank:  types.SHA256,
+		EventLogs: []types.EventLog{
+			{
+				DigestType: util.EVENT_LOG_DIGEST_SHA256,
+				Value:      ones,
+				Label:      "BOOT_COUNTER_2",
+			},
+		},
+	}
+
+	hostManifest := types.HostManifest{
+		PcrManifest: types.PcrManifest{
+			Sha256Pcrs: []types.Pcr{
+				{
+					Index:   0,
+					Value:   PCR_VALID_256,
+					PcrBank: types.SHA256,
+				},
+			},
+		},
+	}
+
+	hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs = append(hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs, hostEvents)
+
+	newUuid, err := uuid.NewRandom()
+	assert.NoError(t, err)
+
+	// without the exclusion, both the unexpected and missing entries are reported
+	rule, err := NewPcrEventLogEquals(&flavorEvents, nil, newUuid, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Faults))
+
+	exclusions := []types.EventLogExclusion{{Label: "BOOT_COUNTER_*", Wildcard: true}}
+	rule, err = NewPcrEventLogEquals(&flavorEvents, exclusions, newUuid, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+	result, err = rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.Equal(t, exclusions, result.Rule.EventLogExclusions)
+}
+
+// Provide an exclusion with an invalid regular expression, expecting the rule creation to fail.
+func TestPcrEventLogEqualsInvalidExclusion(t *testing.T) {
+	newUuid, err := uuid.NewRandom()
+	assert.NoError(t, err)
+
+	_, err = NewPcrEventLogEqualsExcluding(&testExpectedEventLogEntry, nil, []types.EventLogExclusion{{Label: "LCP_[POLICY"}}, newUuid, common.FlavorPartPlatform)
+	assert.Error(t, err)
+}
@@ -12,15 +12,33 @@ import (
 	"github.com/pkg/errors"
 )
 
-func NewPcrEventLogIncludes(expectedEventLogEntry *types.EventLogEntry, expectedPcr *types.Pcr, marker common.FlavorPart) (Rule, error) {
+var pcrEventLogIncludesDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RulePcrEventLogIncludes,
+	FlavorParts: []common.FlavorPart{common.FlavorPartOs, common.FlavorPartHostUnique},
+	Faults: []string{
+		constants.FaultPcrManifestMissing,
+		constants.FaultPcrEventLogMissing,
+		constants.FaultPcrEventLogMissingExpectedEntries,
+	},
+	Description: "Verifies that the host's event log for a PCR includes all measurements in the flavor.",
+})
+
+func NewPcrEventLogIncludes(expectedEventLogEntry *types.EventLogEntry, expectedPcr *types.Pcr, exclusions []types.EventLogExclusion, marker common.FlavorPart) (Rule, error) {
 	if expectedEventLogEntry == nil {
 		return nil, errors.New("The expected event log cannot be nil")
 	}
 
+	excluder, err := types.NewEventLogExcluder(exclusions)
+	if err != nil {
+		return nil, err
+	}
+
 	rule := pcrEventLogIncludes{
 		expectedEventLogEntry: expectedEventLogEntry,
 		expectedPcr:           expectedPcr,
 		marker:                marker,
+		exclusions:            exclusions,
+		excluder:              excluder,
 	}
 	return &rule, nil
 }
@@ -29,20 +47,26 @@ type pcrEventLogIncludes struct {
 	expectedEventLogEntry *types.EventLogEntry
 	expectedPcr           *types.Pcr
 	marker                common.FlavorPart
+	exclusions            []types.EventLogExclusion
+	excluder              *types.EventLogExcluder
 }
 
 // - if the host manifest does not have any log entries, or it doesn't have any value
 //   at the bank/index 'expected', raise "PcrEventLogMissing".
 // - if the log at bank/index does not have the same events as 'expected', raise
-//   "PcrEventLogMissingExpectedEntries".
+//   "PcrEventLogMissingExpectedEntries". The events of 'expected' matching the exclusions of
+//   the flavor are not evaluated.
 func (rule *pcrEventLogIncludes) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
 
 	result := hvs.RuleResult{}
 	result.Trusted = true
 	result.Rule.Name = constants.RulePcrEventLogIncludes
 	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{
+		newPcrEventLogProvenance(rule.expectedEventLogEntry.PcrBank, rule.expectedEventLogEntry.PcrIndex)}
 	result.Rule.ExpectedEventLogs = rule.expectedEventLogEntry.EventLogs
 	result.Rule.ExpectedPcr = rule.expectedPcr
+	result.Rule.EventLogExclusions = rule.exclusions
 
 	if hostManifest.PcrManifest.IsEmpty() {
 		result.Faults = append(result.Faults, newPcrManifestMissingFault())
@@ -58,7 +82,12 @@ func (rule *pcrEventLogIncludes) Apply(hostManifest *types.HostManifest) (*hvs.R
 			// subtract the 'actual' event log measurements from 'expected'.
 			// if there are any left in 'expected', then 'actual' did not include all entries
 
-			missingEvents, err := rule.expectedEventLogEntry.Subtract(actualEventLog)
+			expectedEventLog := rule.expectedEventLogEntry
+			if rule.excluder != nil {
+				expectedEventLog = rule.excluder.RemoveExcluded(expectedEventLog)
+			}
+
+			missingEvents, err := expectedEventLog.Subtract(actualEventLog)
 			if err != nil {
 				return nil, errors.Wrap(err, "Error subtracting event logs in pcr eventlog includes rule.")
 			}
@@ -31,7 +31,7 @@ func TestPcrEventLogIncludesNoFault(t *testing.T) {
 
 	hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs = append(hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs, testExpectedEventLogEntry)
 
-	rule, err := NewPcrEventLogIncludes(&testExpectedEventLogEntry, nil, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogIncludes(&testExpectedEventLogEntry, nil, nil, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -84,7 +84,7 @@ func TestPcrEventLogIncludesMissingMeasurement(t *testing.T) {
 
 	hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs = append(hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs, hostEvents)
 
-	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, nil, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -139,7 +139,7 @@ func TestPcrEventLogIncludesDifferentMeasurement(t *testing.T) {
 
 	hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs = append(hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs, hostEvents)
 
-	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, nil, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -193,7 +193,7 @@ func TestPcrEventLogIncludesPcrEventLogMissingFault(t *testing.T) {
 
 	hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs = append(hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs, hostEvents)
 
-	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, nil, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -231,7 +231,7 @@ func TestPcrEventLogIncludesNoEventLogInHostManifest(t *testing.T) {
 		},
 	}
 
-	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, common.FlavorPartPlatform)
+	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, nil, common.FlavorPartPlatform)
 
 	result, err := rule.Apply(&hostManifest)
 	assert.NoError(t, err)
@@ -240,3 +240,59 @@ func TestPcrEventLogIncludesNoEventLogInHostManifest(t *testing.T) {
 	assert.Equal(t, constants.FaultPcrEventLogMissing, result.Faults[0].Name)
 	t.Logf("Fault description: %s", result.Faults[0].Description)
 }
+
+// Provide a flavor event that is missing from the host's event log and an exclusion matching
+// its info field with a regular expression, expecting no faults.
+func TestPcrEventLogIncludesExclusionNoFault(t *testing.T) {
+
+	flavorEvents := types.EventLogEntry{
+		PcrIndex: types.PCR0,
+		PcrBank:  types.SHA256,
+		EventLogs: []types.EventLog{
+			{
+				DigestType: util.EVENT_LOG_DIGEST_SHA256,
+				Value:      zeros,
+			},
+			{
+				DigestType: util.EVENT_LOG_DIGEST_SHA256,
+				Value:      ones,
+				Label:      "LCP_CONTROL_HASH",
+				Info:       map[string]string{"ComponentName": "LCP_CONTROL_HASH"},
+			},
+		},
+	}
+
+	hostEvents := types.EventLogEntry{
+		PcrIndex: types.PCR0,
+		PcrBank:  types.SHA256,
+		EventLogs: []types.EventLog{
+			{
+				DigestType: util.EVENT_LOG_DIGEST_SHA256,
+				Value:      zeros,
+			},
+		},
+	}
+
+	hostManifest := types.HostManifest{
+		PcrManifest: types.PcrManifest{
+			Sha256Pcrs: []types.Pcr{
+				{
+					Index:   0,
+					Value:   PCR_VALID_256,
+					PcrBank: types.SHA256,
+				},
+			},
+		},
+	}
+
+	hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs = append(hostManifest.PcrManifest.PcrEventLogMap.Sha256EventLogs, hostEvents)
+
+	exclusions := []types.EventLogExclusion{{Info: map[string]string{"ComponentName": "LCP_(CONTROL|POLICY)_HASH"}}}
+	rule, err := NewPcrEventLogIncludes(&flavorEvents, nil, exclusions, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result.Faults))
+}
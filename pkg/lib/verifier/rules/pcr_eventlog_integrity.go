@@ -13,6 +13,18 @@ import (
 	"github.com/pkg/errors"
 )
 
+var pcrEventLogIntegrityDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RulePcrEventLogIntegrity,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs, common.FlavorPartHostUnique, common.FlavorPartSoftware},
+	Faults: []string{
+		constants.FaultPcrManifestMissing,
+		constants.FaultPcrValueMissing,
+		constants.FaultPcrEventLogMissing,
+		constants.FaultPcrEventLogInvalid,
+	},
+	Description: "Verifies that replaying the host's event log for a PCR results in the PCR value reported by the host.",
+})
+
 // NewPcrEventLogIntegrity creates a rule that will check if a PCR (in the host-manifest only)
 // has a "calculated hash" (i.e. from event log replay) that matches its actual hash.
 func NewPcrEventLogIntegrity(expectedPcr *types.Pcr, marker common.FlavorPart) (Rule, error) {
@@ -45,6 +57,10 @@ func (rule *pcrEventLogIntegrity) Apply(hostManifest *types.HostManifest) (*hvs.
 	result.Rule.Name = constants.RulePcrEventLogIntegrity
 	result.Rule.ExpectedPcr = rule.expectedPcr
 	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{
+		newPcrProvenance(rule.expectedPcr.PcrBank, rule.expectedPcr.Index),
+		newPcrEventLogProvenance(rule.expectedPcr.PcrBank, rule.expectedPcr.Index),
+	}
 
 	if hostManifest.PcrManifest.IsEmpty() {
 		result.Faults = append(result.Faults, newPcrManifestMissingFault())
@@ -16,6 +16,18 @@ import (
 	"github.com/pkg/errors"
 )
 
+var pcrMatchesConstantDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RulePcrMatchesConstant,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs, common.FlavorPartAssetTag},
+	Faults: []string{
+		constants.FaultPcrManifestMissing,
+		constants.FaultPcrValueMissing,
+		constants.FaultPcrValueMismatchSHA1,
+		constants.FaultPcrValueMismatchSHA256,
+	},
+	Description: "Verifies that a PCR value reported by the host matches the value in the flavor.",
+})
+
 func NewPcrMatchesConstant(expectedPcr *types.Pcr, marker common.FlavorPart) (Rule, error) {
 	if expectedPcr == nil {
 		return nil, errors.New("The expected PCR cannot be nil")
@@ -44,6 +56,7 @@ func (rule *pcrMatchesConstant) Apply(hostManifest *types.HostManifest) (*hvs.Ru
 	result.Rule.Name = constants.RulePcrMatchesConstant
 	result.Rule.ExpectedPcr = &rule.expectedPcr
 	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newPcrProvenance(rule.expectedPcr.PcrBank, rule.expectedPcr.Index)}
 
 	if hostManifest.PcrManifest.IsEmpty() {
 		result.Faults = append(result.Faults, newPcrManifestMissingFault())
@@ -8,6 +8,7 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -40,6 +41,14 @@ func TestPcrMatchesConstantNoFault(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, len(result.Faults), 0)
 	assert.True(t, result.Trusted)
+
+	// the actual value is reported with the PCR of the host manifest it was read from
+	pcrIndex := types.PCR0
+	assert.Equal(t, []hvs.ActualValueProvenance{{
+		ManifestPath: "pcr_manifest.sha2pcrs",
+		PcrBank:      string(types.SHA256),
+		PcrIndex:     &pcrIndex,
+	}}, result.ActualProvenance)
 }
 
 func TestPcrMatchesConstantPcrManifestMissingFault(t *testing.T) {
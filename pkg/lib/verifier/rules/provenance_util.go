@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// This file contains utility functions recording where in the host manifest the actual values compared
+// by a rule are, the paths are those of the host manifest JSON.
+//
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+const (
+	manifestPathAIKCertificate             = "aik_certificate"
+	manifestPathAssetTagDigest             = "asset_tag_digest"
+	manifestPathClockSkew                  = "clock_skew"
+	manifestPathContainerImageMeasurements = "container_image_measurements"
+	manifestPathFirmware                   = "host_info.platform_inventory.firmware"
+	manifestPathMeasurementXmls            = "measurement_xmls"
+	manifestPathQuoteNonce                 = "quote_nonce"
+	manifestPathQuotePcrDigest             = "quote_pcr_digest"
+)
+
+// newManifestProvenance returns the provenance of the values at the path of the host manifest
+func newManifestProvenance(manifestPath string) hvs.ActualValueProvenance {
+	return hvs.ActualValueProvenance{ManifestPath: manifestPath}
+}
+
+// newPcrProvenance returns the provenance of the value of a PCR of the host manifest
+func newPcrProvenance(bank types.SHAAlgorithm, pcrIndex types.PcrIndex) hvs.ActualValueProvenance {
+	manifestPath := "pcr_manifest.sha2pcrs"
+	if bank == types.SHA1 {
+		manifestPath = "pcr_manifest.sha1pcrs"
+	}
+	return hvs.ActualValueProvenance{
+		ManifestPath: manifestPath,
+		PcrBank:      string(bank),
+		PcrIndex:     &pcrIndex,
+	}
+}
+
+// newPcrEventLogProvenance returns the provenance of the event log of a PCR of the host manifest
+func newPcrEventLogProvenance(bank types.SHAAlgorithm, pcrIndex types.PcrIndex) hvs.ActualValueProvenance {
+	return hvs.ActualValueProvenance{
+		ManifestPath: "pcr_manifest.pcr_event_log_map." + string(bank),
+		PcrBank:      string(bank),
+		PcrIndex:     &pcrIndex,
+	}
+}
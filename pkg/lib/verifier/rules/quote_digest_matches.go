@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that validates the PCR values of the host manifest are the ones covered by the quote signature.
+//
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"sort"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+var quoteDigestMatchesDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleQuoteDigestMatches,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform},
+	Faults: []string{
+		constants.FaultQuoteDigestMissing,
+		constants.FaultQuoteDigestMismatch,
+	},
+	Description: "Recomputes the PCR composite digest from the host's PCR values and verifies it is the digest covered by the TPM quote signature, so that PCR values altered after the quote was verified are detected.",
+})
+
+func NewQuoteDigestMatches(marker common.FlavorPart) (Rule, error) {
+	rule := quoteDigestMatches{
+		marker: marker,
+	}
+	return &rule, nil
+}
+
+type quoteDigestMatches struct {
+	marker common.FlavorPart
+}
+
+//   - if the manifest has no quote PCR digest, raise 'quote digest missing' fault
+//   - if the digest of the manifest's PCR values of the quoted banks doesn't match the
+//     quote PCR digest, raise 'quote digest mismatch' fault
+func (rule *quoteDigestMatches) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
+
+	result := hvs.RuleResult{}
+	result.Trusted = true // default to true, set to false when fault encountered
+	result.Rule.Name = constants.RuleQuoteDigestMatches
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathQuotePcrDigest)}
+
+	if hostManifest.QuotePcrDigest == nil || hostManifest.QuotePcrDigest.Digest == "" {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultQuoteDigestMissing,
+			Description: "Host report does not include the PCR digest of the quote",
+		})
+		return &result, nil
+	}
+
+	digest, err := computeQuotePcrDigest(&hostManifest.PcrManifest, hostManifest.QuotePcrDigest)
+	if err != nil {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultQuoteDigestMismatch,
+			Description: "The PCR digest of the host's PCR values could not be computed: " + err.Error(),
+		})
+	} else if quoteDigest, err := hex.DecodeString(hostManifest.QuotePcrDigest.Digest); err != nil || !bytes.Equal(digest, quoteDigest) {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultQuoteDigestMismatch,
+			Description: "The digest of the host's PCR values does not match the PCR digest of the quote",
+		})
+	}
+
+	return &result, nil
+}
+
+// computeQuotePcrDigest concatenates the PCR values of the quoted banks in PCR index order, as the TPM does for the
+// PCR digest of a quote, and returns their digest
+func computeQuotePcrDigest(pcrManifest *types.PcrManifest, quotePcrDigest *types.QuotePcrDigest) ([]byte, error) {
+
+	var h hash.Hash
+	switch quotePcrDigest.Algorithm {
+	case types.SHA1:
+		h = sha1.New()
+	case types.SHA256:
+		h = sha256.New()
+	case types.SHA384:
+		h = sha512.New384()
+	case types.SHA512:
+		h = sha512.New()
+	default:
+		return nil, errors.Errorf("unsupported digest algorithm %s", quotePcrDigest.Algorithm)
+	}
+
+	for _, bank := range quotePcrDigest.Banks {
+		var pcrs []types.Pcr
+		switch bank {
+		case types.SHA1:
+			pcrs = append(pcrs, pcrManifest.Sha1Pcrs...)
+		case types.SHA256:
+			pcrs = append(pcrs, pcrManifest.Sha256Pcrs...)
+		default:
+			return nil, errors.Errorf("the PCR values of bank %s are not in the host report", bank)
+		}
+		sort.Slice(pcrs, func(i, j int) bool {
+			return pcrs[i].Index < pcrs[j].Index
+		})
+		for _, pcr := range pcrs {
+			value, err := hex.DecodeString(pcr.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid value of PCR %d in bank %s", pcr.Index, bank)
+			}
+			_, _ = h.Write(value)
+		}
+	}
+	return h.Sum(nil), nil
+}
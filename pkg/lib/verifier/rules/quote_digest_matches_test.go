@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newQuotedManifest returns a manifest with PCRs 0 and 7 in both banks and the PCR digest of a quote of them, the
+// PCRs are not in index order as the quote digest must not depend on the order of the manifest
+func newQuotedManifest() *types.HostManifest {
+	sha1Pcr0 := strings.Repeat("01", 20)
+	sha1Pcr7 := strings.Repeat("07", 20)
+	sha256Pcr0 := strings.Repeat("a0", 32)
+	sha256Pcr7 := strings.Repeat("a7", 32)
+
+	quoted, _ := hex.DecodeString(sha1Pcr0 + sha1Pcr7 + sha256Pcr0 + sha256Pcr7)
+	digest := sha256.Sum256(quoted)
+
+	return &types.HostManifest{
+		PcrManifest: types.PcrManifest{
+			Sha1Pcrs: []types.Pcr{
+				{Index: types.PCR7, Value: sha1Pcr7, PcrBank: types.SHA1},
+				{Index: types.PCR0, Value: sha1Pcr0, PcrBank: types.SHA1},
+			},
+			Sha256Pcrs: []types.Pcr{
+				{Index: types.PCR0, Value: sha256Pcr0, PcrBank: types.SHA256},
+				{Index: types.PCR7, Value: sha256Pcr7, PcrBank: types.SHA256},
+			},
+		},
+		QuotePcrDigest: &types.QuotePcrDigest{
+			Algorithm: types.SHA256,
+			Banks:     []types.SHAAlgorithm{types.SHA1, types.SHA256},
+			Digest:    hex.EncodeToString(digest[:]),
+		},
+	}
+}
+
+func TestQuoteDigestMatchesNoFault(t *testing.T) {
+
+	rule, err := NewQuoteDigestMatches(common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(newQuotedManifest())
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.True(t, result.Trusted)
+}
+
+func TestQuoteDigestMismatchAlteredPcr(t *testing.T) {
+
+	rule, err := NewQuoteDigestMatches(common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// the PCR value was altered after the quote was verified...
+	hostManifest := newQuotedManifest()
+	hostManifest.PcrManifest.Sha256Pcrs[1].Value = strings.Repeat("ff", 32)
+
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteDigestMismatch, result.Faults[0].Name)
+	t.Logf("Fault description: %s", result.Faults[0].Description)
+}
+
+func TestQuoteDigestMismatchRemovedPcr(t *testing.T) {
+
+	rule, err := NewQuoteDigestMatches(common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// a quoted PCR was removed from the manifest...
+	hostManifest := newQuotedManifest()
+	hostManifest.PcrManifest.Sha1Pcrs = hostManifest.PcrManifest.Sha1Pcrs[:1]
+
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteDigestMismatch, result.Faults[0].Name)
+}
+
+func TestQuoteDigestMismatchUnreportedBank(t *testing.T) {
+
+	rule, err := NewQuoteDigestMatches(common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// the quote covers a bank whose values are not reported...
+	hostManifest := newQuotedManifest()
+	hostManifest.QuotePcrDigest.Banks = append(hostManifest.QuotePcrDigest.Banks, types.SHA384)
+
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteDigestMismatch, result.Faults[0].Name)
+	t.Logf("Fault description: %s", result.Faults[0].Description)
+}
+
+func TestQuoteDigestMissing(t *testing.T) {
+
+	rule, err := NewQuoteDigestMatches(common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	hostManifest := newQuotedManifest()
+	hostManifest.QuotePcrDigest = nil
+
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteDigestMissing, result.Faults[0].Name)
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that validates the age of the host's quote, compensating the skew of the host clock, and
+// warns when the host clock is skewed.
+//
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+var quoteFreshDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleQuoteFresh,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform},
+	Faults: []string{
+		constants.FaultQuoteTimestampMissing,
+		constants.FaultQuoteExpired,
+		constants.FaultHostClockSkewed,
+	},
+	Description: "Verifies that the host's quote is not older than the maximum quote age, compensating the skew of the host clock measured when the quote was collected. A host clock skewed beyond the threshold is reported as a warning that does not make the host untrusted.",
+})
+
+// NewQuoteFresh creates the rule validating the age of the quote when maxQuoteAge is not zero, and warning of the
+// host clock skew when skewThreshold is not zero
+func NewQuoteFresh(maxQuoteAge, skewThreshold time.Duration, marker common.FlavorPart) (Rule, error) {
+
+	if maxQuoteAge < 0 || skewThreshold < 0 {
+		return nil, errors.New("The maximum quote age and the clock skew threshold cannot be negative")
+	}
+
+	if maxQuoteAge == 0 && skewThreshold == 0 {
+		return nil, errors.New("The maximum quote age or the clock skew threshold must be provided")
+	}
+
+	rule := quoteFresh{
+		maxQuoteAge:   maxQuoteAge,
+		skewThreshold: skewThreshold,
+		marker:        marker,
+	}
+	return &rule, nil
+}
+
+type quoteFresh struct {
+	maxQuoteAge      time.Duration
+	skewThreshold    time.Duration
+	marker           common.FlavorPart
+	verificationTime time.Time
+}
+
+func (rule *quoteFresh) SetVerificationTime(verificationTime time.Time) {
+	rule.verificationTime = verificationTime
+}
+
+//   - if the manifest has no clock skew and the quote age is validated, raise 'quote timestamp missing' fault
+//   - if the quote time compensated to the verifier clock is older than the maximum quote age,
+//     raise 'quote expired' fault
+//   - if the host clock is skewed more than the threshold, beyond the uncertainty of the measurement,
+//     raise 'host clock skewed' warning
+func (rule *quoteFresh) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
+
+	result := hvs.RuleResult{}
+	result.Trusted = true // default to true, set to false when fault encountered
+	result.Rule.Name = constants.RuleQuoteFresh
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathClockSkew)}
+
+	skew := hostManifest.ClockSkew
+	if skew == nil {
+		if rule.maxQuoteAge != 0 {
+			result.Faults = append(result.Faults, hvs.Fault{
+				Name:        constants.FaultQuoteTimestampMissing,
+				Description: "Host report does not include the time the quote was taken at",
+			})
+		}
+		return &result, nil
+	}
+
+	if rule.maxQuoteAge != 0 {
+		quoteTime := skew.ToVerifierTime(skew.QuoteTime)
+		if age := currentTime(rule.verificationTime).Sub(quoteTime); age > rule.maxQuoteAge+skew.Uncertainty() {
+			result.Faults = append(result.Faults, hvs.Fault{
+				Name: constants.FaultQuoteExpired,
+				Description: fmt.Sprintf("Host quote taken at %s is older than %s",
+					quoteTime.UTC().Format(time.RFC3339), rule.maxQuoteAge),
+			})
+		}
+	}
+
+	if rule.skewThreshold != 0 && skew.MinimumSkew() > rule.skewThreshold {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name: constants.FaultHostClockSkewed,
+			Description: fmt.Sprintf("Host clock is skewed by %s (+/- %s), more than %s",
+				skew.Offset(), skew.Uncertainty(), rule.skewThreshold),
+			Warning: true,
+		})
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"testing"
+	"time"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSkewedManifest returns a manifest with a quote collected at the time, from a host whose clock is skewed
+func newSkewedManifest(collectedAt time.Time, skew time.Duration) *types.HostManifest {
+	return &types.HostManifest{
+		ClockSkew: types.NewClockSkew(collectedAt.Add(skew).Unix(), collectedAt, collectedAt.Add(100*time.Millisecond)),
+	}
+}
+
+func TestQuoteFreshNoFault(t *testing.T) {
+
+	rule, err := NewQuoteFresh(5*time.Minute, 5*time.Minute, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(newSkewedManifest(time.Now(), 0))
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.True(t, result.IsTrusted())
+}
+
+func TestQuoteFreshSkewedHostClock(t *testing.T) {
+
+	rule, err := NewQuoteFresh(5*time.Minute, 5*time.Minute, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// the host clock is an hour behind, the quote is fresh once compensated but the skew is warned of
+	result, err := rule.Apply(newSkewedManifest(time.Now(), -time.Hour))
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultHostClockSkewed, result.Faults[0].Name)
+	assert.True(t, result.Faults[0].Warning)
+	assert.True(t, result.IsTrusted())
+	t.Logf("Fault description: %s", result.Faults[0].Description)
+}
+
+func TestQuoteFreshExpiredQuote(t *testing.T) {
+
+	rule, err := NewQuoteFresh(5*time.Minute, 0, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// the quote was collected from a host whose clock is ahead, it would look fresh if not compensated...
+	collectedAt := time.Now().Add(-10 * time.Minute)
+	result, err := rule.Apply(newSkewedManifest(collectedAt, 10*time.Minute))
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteExpired, result.Faults[0].Name)
+	assert.False(t, result.IsTrusted())
+
+	// ...the quote is fresh at the time it was verified at
+	rule.(TimedRule).SetVerificationTime(collectedAt.Add(time.Minute))
+	result, err = rule.Apply(newSkewedManifest(collectedAt, 10*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Faults))
+}
+
+func TestQuoteFreshTimestampMissing(t *testing.T) {
+
+	rule, err := NewQuoteFresh(5*time.Minute, 5*time.Minute, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteTimestampMissing, result.Faults[0].Name)
+
+	// the skew cannot be warned of without the timestamp, which is not a fault
+	rule, err = NewQuoteFresh(0, 5*time.Minute, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+	result, err = rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Faults))
+
+	_, err = NewQuoteFresh(0, 0, common.FlavorPartPlatform)
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that validates the quote of the host manifest was requested by this verifier for the host.
+//
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+var quoteNonceBoundDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleQuoteNonceBound,
+	FlavorParts: []common.FlavorPart{common.FlavorPartPlatform},
+	Faults: []string{
+		constants.FaultQuoteNonceMissing,
+		constants.FaultQuoteNonceNotBound,
+	},
+	Description: "Verifies that the host's quote was requested with a nonce bound to this verifier and to the host record, so that quotes solicited by another verifier are not accepted.",
+})
+
+func NewQuoteNonceBound(requesterIdentity string, marker common.FlavorPart) (Rule, error) {
+
+	if requesterIdentity == "" {
+		return nil, errors.New("The quote requester identity cannot be empty")
+	}
+
+	rule := quoteNonceBound{
+		requesterIdentity: requesterIdentity,
+		marker:            marker,
+	}
+	return &rule, nil
+}
+
+type quoteNonceBound struct {
+	requesterIdentity string
+	marker            common.FlavorPart
+}
+
+//   - if the manifest has no quote nonce or host record, raise 'quote nonce missing' fault
+//   - if the nonce the quote was verified with doesn't end with the digest of the requester identity
+//     and the host record, raise 'quote nonce not bound' fault
+func (rule *quoteNonceBound) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
+
+	result := hvs.RuleResult{}
+	result.Trusted = true // default to true, set to false when fault encountered
+	result.Rule.Name = constants.RuleQuoteNonceBound
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathQuoteNonce)}
+
+	if hostManifest.QuoteNonce == "" || hostManifest.HostId == "" {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultQuoteNonceMissing,
+			Description: "Host report does not include the nonce the quote was requested with",
+		})
+	} else if !util.IsQuoteNonceBound(hostManifest.QuoteNonce, util.GetQuoteNonceBinding(rule.requesterIdentity, hostManifest.HostId)) {
+		result.Faults = append(result.Faults, hvs.Fault{
+			Name:        constants.FaultQuoteNonceNotBound,
+			Description: "Host quote was not requested by this verifier for host " + hostManifest.HostId,
+		})
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"testing"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testQuoteRequester      = "hvs-1"
+	testOtherQuoteRequester = "hvs-2"
+	testQuoteHostId         = "204e7a6a-a4c5-4d2b-8bd8-6b7a3a0a7d21"
+)
+
+func newBoundManifest(t *testing.T, requester string) *types.HostManifest {
+	nonce, err := util.GenerateBoundNonce(util.QuoteNonceRandomSize, util.GetQuoteNonceBinding(requester, testQuoteHostId))
+	assert.NoError(t, err)
+	return &types.HostManifest{
+		QuoteNonce: nonce,
+		HostId:     testQuoteHostId,
+	}
+}
+
+func TestQuoteNonceBoundNoFault(t *testing.T) {
+
+	rule, err := NewQuoteNonceBound(testQuoteRequester, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(newBoundManifest(t, testQuoteRequester))
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.True(t, result.Trusted)
+}
+
+func TestQuoteNonceBoundRelayedQuote(t *testing.T) {
+
+	rule, err := NewQuoteNonceBound(testQuoteRequester, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// the quote was solicited by another verifier...
+	result, err := rule.Apply(newBoundManifest(t, testOtherQuoteRequester))
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteNonceNotBound, result.Faults[0].Name)
+	t.Logf("Fault description: %s", result.Faults[0].Description)
+}
+
+func TestQuoteNonceBoundOtherHost(t *testing.T) {
+
+	rule, err := NewQuoteNonceBound(testQuoteRequester, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// the quote was requested for another host record...
+	hostManifest := newBoundManifest(t, testQuoteRequester)
+	hostManifest.HostId = "7d3e2f6c-0d4f-4d0a-9a43-2b1f3c5e6a70"
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteNonceNotBound, result.Faults[0].Name)
+}
+
+func TestQuoteNonceMissing(t *testing.T) {
+
+	rule, err := NewQuoteNonceBound(testQuoteRequester, common.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	// a nonce that is not bound to a host record...
+	nonce, err := util.GenerateNonce(util.QuoteNonceRandomSize)
+	assert.NoError(t, err)
+	result, err := rule.Apply(&types.HostManifest{QuoteNonce: nonce})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultQuoteNonceMissing, result.Faults[0].Name)
+}
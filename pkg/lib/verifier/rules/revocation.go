@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"crypto/x509"
+)
+
+// RevocationChecker checks whether a certificate has been revoked by its issuer, it returns an error when the
+// revocation status could not be determined
+type RevocationChecker interface {
+	IsRevoked(cert *x509.Certificate, issuer *x509.Certificate) (bool, error)
+}
+
+// TagCertificateRevocationChecker checks whether a tag certificate has been revoked in the tag certificate store
+// of the verification service, it returns an error when the revocation status could not be determined
+type TagCertificateRevocationChecker interface {
+	IsTagCertificateRevoked(tagCertificate *x509.Certificate) (bool, error)
+}
+
+// revokedCertificate returns the first revoked certificate of a verified certificate chain, from the leaf to the
+// root, or nil when none is revoked. The certificates whose revocation status can not be determined are logged and
+// not considered revoked, so that an unavailable CRL or OCSP responder does not fail the verification of the hosts.
+func revokedCertificate(checker RevocationChecker, chains [][]*x509.Certificate) *x509.Certificate {
+	if checker == nil || len(chains) == 0 {
+		return nil
+	}
+
+	chain := chains[0]
+	for i := 0; i < len(chain)-1; i++ {
+		revoked, err := checker.IsRevoked(chain[i], chain[i+1])
+		if err != nil {
+			log.WithError(err).Warnf("Could not check the revocation status of the certificate '%s'", chain[i].Subject.CommonName)
+			continue
+		}
+		if revoked {
+			secLog.Warnf("The certificate '%s' with serial number %s is revoked", chain[i].Subject.CommonName, chain[i].SerialNumber)
+			return chain[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRevocationChecker struct {
+	revokedSerials map[int64]bool
+	unavailable    bool
+}
+
+func (c *testRevocationChecker) IsRevoked(cert *x509.Certificate, issuer *x509.Certificate) (bool, error) {
+	if c.unavailable {
+		return false, errors.New("CRL unavailable")
+	}
+	return c.revokedSerials[cert.SerialNumber.Int64()], nil
+}
+
+func TestRevokedCertificate(t *testing.T) {
+	newCert := func(serial int64, commonName string) *x509.Certificate {
+		return &x509.Certificate{SerialNumber: big.NewInt(serial), Subject: pkix.Name{CommonName: commonName}}
+	}
+	chains := [][]*x509.Certificate{{newCert(1, "AIK"), newCert(2, "Privacy CA"), newCert(3, "Root CA")}}
+
+	assert.Nil(t, revokedCertificate(nil, chains))
+	assert.Nil(t, revokedCertificate(&testRevocationChecker{}, chains))
+	// the root is trusted as configured and never checked
+	assert.Nil(t, revokedCertificate(&testRevocationChecker{revokedSerials: map[int64]bool{3: true}}, chains))
+	// the revocation status that can not be determined does not fail the verification
+	assert.Nil(t, revokedCertificate(&testRevocationChecker{revokedSerials: map[int64]bool{1: true}, unavailable: true}, chains))
+
+	revoked := revokedCertificate(&testRevocationChecker{revokedSerials: map[int64]bool{2: true}}, chains)
+	assert.NotNil(t, revoked)
+	assert.Equal(t, "Privacy CA", revoked.Subject.CommonName)
+}
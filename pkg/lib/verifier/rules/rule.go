@@ -5,6 +5,8 @@
 package rules
 
 import (
+	"time"
+
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
@@ -16,5 +18,32 @@ type Rule interface {
 	Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error)
 }
 
+// TimedRule is implemented by the rules whose outcome depends on the time they are applied at, like the
+// certificate validity checks. The verifier sets the time when the verification is replayed at the time of a
+// past decision.
+type TimedRule interface {
+	SetVerificationTime(verificationTime time.Time)
+}
+
+// RevocationCheckedRule is implemented by the rules validating certificates, the verifier sets the revocation
+// checker when the certificates must be checked against the revocation lists of their issuers.
+type RevocationCheckedRule interface {
+	SetRevocationChecker(checker RevocationChecker)
+}
+
+// TagRevocationCheckedRule is implemented by the rules validating tag certificates, the verifier sets the tag
+// certificate revocation checker when the tag certificates must be checked against the tag certificate store.
+type TagRevocationCheckedRule interface {
+	SetTagCertificateRevocationChecker(checker TagCertificateRevocationChecker)
+}
+
+// currentTime returns the verification time set on a TimedRule, or the current time when none was set
+func currentTime(verificationTime time.Time) time.Time {
+	if verificationTime.IsZero() {
+		return time.Now()
+	}
+	return verificationTime
+}
+
 var log = commLog.GetDefaultLogger()
 var secLog = commLog.GetSecurityLogger()
@@ -0,0 +1,32 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"sort"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+)
+
+// ruleDefinitions collects the definitions declared alongside each rule implementation, the definition of a rule is
+// published in the rule catalog by declaring it with registerRuleDefinition next to the constructor of the rule.
+var ruleDefinitions []hvs.RuleDefinition
+
+// registerRuleDefinition adds the definition of a rule to the rule catalog and returns it
+func registerRuleDefinition(definition hvs.RuleDefinition) hvs.RuleDefinition {
+	ruleDefinitions = append(ruleDefinitions, definition)
+	return definition
+}
+
+// GetRuleDefinitions returns the names, flavor parts, faults and descriptions of
+// all rules supported by the verifier, ordered by name.
+func GetRuleDefinitions() []hvs.RuleDefinition {
+	definitions := make([]hvs.RuleDefinition, len(ruleDefinitions))
+	copy(definitions, ruleDefinitions)
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"testing"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRuleDefinitions(t *testing.T) {
+
+	ruleNames := []string{
+		constants.RuleAikCertificateTrusted,
+		constants.RuleAssetTagMatches,
+		constants.RuleContainerImagesMatch,
+		constants.RuleFirmwareVersionsMatch,
+		constants.RuleFlavorTrusted,
+		constants.RulePcrEventLogEquals,
+		constants.RulePcrEventLogEqualsExcluding,
+		constants.RulePcrEventLogIncludes,
+		constants.RulePcrEventLogIntegrity,
+		constants.RulePcrMatchesConstant,
+		constants.RuleQuoteDigestMatches,
+		constants.RuleQuoteFresh,
+		constants.RuleQuoteNonceBound,
+		constants.RuleTagCertificateTrusted,
+		constants.RuleXmlMeasurementsDigestEquals,
+		constants.RuleXmlMeasurementLogEquals,
+		constants.RuleXmlMeasurementLogIntegrity,
+	}
+
+	definitions := GetRuleDefinitions()
+	assert.Equal(t, len(ruleNames), len(definitions))
+
+	definedRules := make(map[string]bool)
+	for _, definition := range definitions {
+		assert.False(t, definedRules[definition.Name], "duplicate rule definition %s", definition.Name)
+		definedRules[definition.Name] = true
+		assert.NotEmpty(t, definition.FlavorParts, definition.Name)
+		assert.NotEmpty(t, definition.Faults, definition.Name)
+		assert.NotEmpty(t, definition.Description, definition.Name)
+	}
+
+	for _, ruleName := range ruleNames {
+		assert.True(t, definedRules[ruleName], "missing rule definition %s", ruleName)
+	}
+}
@@ -17,6 +17,19 @@ import (
 	"github.com/pkg/errors"
 )
 
+var tagCertificateTrustedDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        faultsConst.RuleTagCertificateTrusted,
+	FlavorParts: []common.FlavorPart{common.FlavorPartAssetTag},
+	Faults: []string{
+		faultsConst.FaultTagCertificateMissing,
+		faultsConst.FaultTagCertificateNotTrusted,
+		faultsConst.FaultTagCertificateNotYetValid,
+		faultsConst.FaultTagCertificateExpired,
+		faultsConst.FaultTagCertificateRevoked,
+	},
+	Description: "Verifies that the tag certificate in the flavor is within its validity period, issued by a trusted asset tag CA and not revoked.",
+})
+
 func NewTagCertificateTrusted(assetTagCACertificates *x509.CertPool, attributeCertificate *model.X509AttributeCertificate) (Rule, error) {
 	if assetTagCACertificates == nil {
 		return nil, errors.New("The tag certificates cannot be nil")
@@ -33,6 +46,16 @@ func NewTagCertificateTrusted(assetTagCACertificates *x509.CertPool, attributeCe
 type tagCertificateTrusted struct {
 	assetTagCACertificates *x509.CertPool
 	attributeCertificate   *model.X509AttributeCertificate
+	verificationTime       time.Time
+	revocationChecker      TagCertificateRevocationChecker
+}
+
+func (rule *tagCertificateTrusted) SetVerificationTime(verificationTime time.Time) {
+	rule.verificationTime = verificationTime
+}
+
+func (rule *tagCertificateTrusted) SetTagCertificateRevocationChecker(checker TagCertificateRevocationChecker) {
+	rule.revocationChecker = checker
 }
 
 // - If the X509AttributeCertificate is null, raise TagCertificateMissing fault.
@@ -41,6 +64,8 @@ type tagCertificateTrusted struct {
 //   raise a TagCertificateNotYetValid fault.
 // - If the attributeCertificate is valid but has a 'NotAfter' value after 'today,
 //   raise a TagCertificateNotYetExpired fault.
+// - If the attributeCertificate has been revoked in the tag certificate store, raise a
+//   TagCertificateRevoked fault.
 func (rule *tagCertificateTrusted) Apply(hostManifest *types.HostManifest) (*hvs.RuleResult, error) {
 
 	var fault *hvs.Fault
@@ -60,9 +85,14 @@ func (rule *tagCertificateTrusted) Apply(hostManifest *types.HostManifest) (*hvs
 		if err != nil {
 			return nil, errors.Wrap(err, "Could not parse attribute certificate")
 		}
+		// the asset tag is only trusted until the tag certificate expires
+		notAfter := rule.attributeCertificate.NotAfter
+		result.ValidUntil = &notAfter
 
+		now := currentTime(rule.verificationTime)
 		opts := x509.VerifyOptions{
-			Roots: rule.assetTagCACertificates,
+			Roots:       rule.assetTagCACertificates,
+			CurrentTime: now,
 		}
 
 		_, err = tagCertificate.Verify(opts)
@@ -73,7 +103,7 @@ func (rule *tagCertificateTrusted) Apply(hostManifest *types.HostManifest) (*hvs
 			}
 		} else {
 			// check to see if the attribute certificate's 'not before' is before today...
-			if time.Now().Before(rule.attributeCertificate.NotBefore) {
+			if now.Before(rule.attributeCertificate.NotBefore) {
 				fault = &hvs.Fault{
 					Name:        faultsConst.FaultTagCertificateNotYetValid,
 					Description: fmt.Sprintf("Tag certificate not valid before %s", rule.attributeCertificate.NotBefore),
@@ -81,13 +111,27 @@ func (rule *tagCertificateTrusted) Apply(hostManifest *types.HostManifest) (*hvs
 			}
 
 			// check to see if the attributes certificate's 'not after' is after today...
-			if time.Now().After(rule.attributeCertificate.NotAfter) {
+			if now.After(rule.attributeCertificate.NotAfter) {
 				fault = &hvs.Fault{
 					Name:        faultsConst.FaultTagCertificateExpired,
 					Description: fmt.Sprintf("Tag certificate not valid after %s", rule.attributeCertificate.NotAfter),
 				}
 			}
 
+			// check to see if the attribute certificate has been revoked, the certificates whose revocation
+			// status can not be determined are not considered revoked
+			if fault == nil && rule.revocationChecker != nil {
+				revoked, err := rule.revocationChecker.IsTagCertificateRevoked(tagCertificate)
+				if err != nil {
+					log.WithError(err).Warnf("Could not check the revocation status of the tag certificate of '%s'", tagCertificate.Subject.CommonName)
+				} else if revoked {
+					secLog.Warnf("The tag certificate of '%s' with serial number %s is revoked", tagCertificate.Subject.CommonName, tagCertificate.SerialNumber)
+					fault = &hvs.Fault{
+						Name:        faultsConst.FaultTagCertificateRevoked,
+						Description: fmt.Sprintf("Tag certificate with serial number %s is revoked", tagCertificate.SerialNumber),
+					}
+				}
+			}
 		}
 	}
 
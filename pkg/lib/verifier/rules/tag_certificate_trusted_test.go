@@ -5,11 +5,17 @@
 package rules
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	faultsConst "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -179,3 +185,75 @@ func TestTagCertificateTrustedNotYetValidFault(t *testing.T) {
 	assert.Equal(t, result.Faults[0].Name, faultsConst.FaultTagCertificateNotYetValid)
 	t.Logf("Fault description: %s", result.Faults[0].Description)
 }
+
+type testTagRevocationChecker struct {
+	revoked     bool
+	unavailable bool
+}
+
+func (c *testTagRevocationChecker) IsTagCertificateRevoked(tagCertificate *x509.Certificate) (bool, error) {
+	if c.unavailable {
+		return false, errors.New("Tag certificate store unavailable")
+	}
+	return c.revoked, nil
+}
+
+func TestTagCertificateTrustedRevokedFault(t *testing.T) {
+
+	// create a CA certpool...
+	caPrivateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Asset Tag CA"},
+		NotBefore:             time.Now().AddDate(-1, 0, 0),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caPrivateKey.PublicKey, caPrivateKey)
+	assert.NoError(t, err)
+	caCertificate, err := x509.ParseCertificate(caBytes)
+	assert.NoError(t, err)
+
+	trustedAuthorityCerts := x509.NewCertPool()
+	trustedAuthorityCerts.AddCert(caCertificate)
+
+	// create the attribute certificate issued by the CA...
+	tagTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2020),
+		Subject:      pkix.Name{CommonName: "80ecce40-04b8-e811-906e-00163566263e"},
+		NotBefore:    time.Now().AddDate(-1, 0, 0),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	tagCertificateBytes, err := x509.CreateCertificate(rand.Reader, tagTemplate, caCertificate, &caPrivateKey.PublicKey, caPrivateKey)
+	assert.NoError(t, err)
+
+	attributeCertificate := model.X509AttributeCertificate{
+		Encoded:   tagCertificateBytes,
+		NotBefore: tagTemplate.NotBefore,
+		NotAfter:  tagTemplate.NotAfter,
+	}
+
+	// the tag certificate is trusted when it is not revoked or its revocation status is unknown
+	for _, checker := range []*testTagRevocationChecker{{}, {revoked: true, unavailable: true}} {
+		rule, err := NewTagCertificateTrusted(trustedAuthorityCerts, &attributeCertificate)
+		assert.NoError(t, err)
+		rule.(TagRevocationCheckedRule).SetTagCertificateRevocationChecker(checker)
+
+		result, err := rule.Apply(&types.HostManifest{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(result.Faults))
+	}
+
+	rule, err := NewTagCertificateTrusted(trustedAuthorityCerts, &attributeCertificate)
+	assert.NoError(t, err)
+	rule.(TagRevocationCheckedRule).SetTagCertificateRevocationChecker(&testTagRevocationChecker{revoked: true})
+
+	result, err := rule.Apply(&types.HostManifest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, faultsConst.FaultTagCertificateRevoked, result.Faults[0].Name)
+	assert.False(t, result.IsTrusted())
+}
@@ -15,6 +15,17 @@ import (
 	"github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 )
 
+var xmlMeasurementLogDigestEqualsDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleXmlMeasurementsDigestEquals,
+	FlavorParts: []common.FlavorPart{common.FlavorPartSoftware},
+	Faults: []string{
+		constants.FaultXmlMeasurementLogMissing,
+		constants.FaultXmlMeasurementLogInvalid,
+		constants.FaultXmlMeasurementsDigestValueMismatch,
+	},
+	Description: "Verifies that the host's XML measurement log uses the digest algorithm of the flavor.",
+})
+
 func NewXmlMeasurementLogDigestEquals(expectedDigestAlgorithm string, flavorID uuid.UUID) (Rule, error) {
 
 	rule := xmlMeasurementLogDigestEquals{
@@ -39,6 +50,7 @@ func (rule *xmlMeasurementLogDigestEquals) Apply(hostManifest *types.HostManifes
 	result.Trusted = true
 	result.Rule.Name = constants.RuleXmlMeasurementsDigestEquals
 	result.Rule.Markers = append(result.Rule.Markers, common.FlavorPartSoftware)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathMeasurementXmls)}
 
 	if hostManifest.MeasurementXmls == nil || len(hostManifest.MeasurementXmls) == 0 {
 		result.Faults = append(result.Faults, newXmlEventLogMissingFault(rule.flavorID))
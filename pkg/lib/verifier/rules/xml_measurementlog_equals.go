@@ -17,6 +17,19 @@ import (
 	"reflect"
 )
 
+var xmlMeasurementLogEqualsDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        constants.RuleXmlMeasurementLogEquals,
+	FlavorParts: []common.FlavorPart{common.FlavorPartSoftware},
+	Faults: []string{
+		constants.FaultXmlMeasurementLogMissing,
+		constants.FaultXmlMeasurementLogInvalid,
+		constants.FaultXmlMeasurementLogMissingExpectedEntries,
+		constants.FaultXmlMeasurementLogContainsUnexpectedEntries,
+		constants.FaultXmlMeasurementLogValueMismatchEntries384,
+	},
+	Description: "Verifies that the host's XML measurement log contains exactly the measurements in the software flavor.",
+})
+
 func NewXmlMeasurementLogEquals(softwareFlavor *hvs.Flavor) (Rule, error) {
 
 	meta := softwareFlavor.Meta
@@ -77,6 +90,7 @@ func (rule *xmlMeasurementLogEquals) Apply(hostManifest *types.HostManifest) (*h
 	result.Rule.Name = constants.RuleXmlMeasurementLogEquals
 	result.Rule.FlavorName = &rule.flavorLabel
 	result.Rule.Markers = append(result.Rule.Markers, common.FlavorPartSoftware)
+	result.ActualProvenance = []hvs.ActualValueProvenance{newManifestProvenance(manifestPathMeasurementXmls)}
 	result.Rule.FlavorID = &rule.flavorID
 
 	result.Rule.ExpectedMeasurements = append(result.Rule.ExpectedMeasurements, rule.expectedFileMeasurements...)
@@ -22,6 +22,18 @@ import (
 	"strings"
 )
 
+var xmlMeasurementLogIntegrityDefinition = registerRuleDefinition(hvs.RuleDefinition{
+	Name:        faultsConst.RuleXmlMeasurementLogIntegrity,
+	FlavorParts: []common.FlavorPart{common.FlavorPartSoftware},
+	Faults: []string{
+		faultsConst.FaultXmlMeasurementLogMissing,
+		faultsConst.FaultXmlMeasurementLogInvalid,
+		faultsConst.FaultPcrEventLogMissing,
+		faultsConst.FaultXmlMeasurementValueMismatch,
+	},
+	Description: "Verifies that the cumulative hash of the host's XML measurement log matches the flavor and the PCR 15 event log.",
+})
+
 func NewXmlMeasurementLogIntegrity(flavorID uuid.UUID, flavorLabel string, expectedCumulativeHash string) (Rule, error) {
 
 	rule := xmlMeasurementLogIntegrity{
@@ -56,6 +68,10 @@ func (rule *xmlMeasurementLogIntegrity) Apply(hostManifest *types.HostManifest)
 	result.Rule.FlavorName = &rule.flavorLabel
 	result.Rule.ExpectedValue = &rule.expectedCumulativeHash
 	result.Rule.Markers = append(result.Rule.Markers, common.FlavorPartSoftware)
+	result.ActualProvenance = []hvs.ActualValueProvenance{
+		newManifestProvenance(manifestPathMeasurementXmls),
+		newPcrEventLogProvenance(types.SHA256, types.PCR15),
+	}
 	result.Rule.FlavorID = &rule.flavorId
 
 	if hostManifest.MeasurementXmls == nil || len(hostManifest.MeasurementXmls) == 0 {
@@ -13,8 +13,10 @@ import (
 	"crypto/x509"
 	commLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier/rules"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/pkg/errors"
+	"time"
 )
 
 // VerifierCertificates A collection of certificates/certificate pools that
@@ -24,13 +26,43 @@ type VerifierCertificates struct {
 	AssetTagCACertificates   *x509.CertPool
 	FlavorSigningCertificate *x509.Certificate
 	FlavorCACertificates     *x509.CertPool
+	// FlavorCoSigningCertificates are the certificates of the other trusted flavor signers, which must also be
+	// issued by one of FlavorCACertificates
+	FlavorCoSigningCertificates []*x509.Certificate
+	// FlavorSignatureQuorum is the number of the trusted flavor signers that must have signed a flavor, one when
+	// not set
+	FlavorSignatureQuorum int
+	// QuoteRequesterIdentity is the identity the quote nonces are bound to, when set the platform flavors require
+	// the quotes to be requested by this verifier
+	QuoteRequesterIdentity string
+	// MaxQuoteAge is the maximum age of the quotes, compensated for the skew of the host clocks, and
+	// ClockSkewThreshold the skew of a host clock that is warned of. The platform flavors validate the
+	// quote timestamps when any of them is set.
+	MaxQuoteAge        time.Duration
+	ClockSkewThreshold time.Duration
+	// VerificationTime is the time the certificate validity periods are checked at, the current time when not set.
+	// It is set when a past decision is replayed.
+	VerificationTime time.Time
+	// RevocationChecker checks the AIK and flavor signing certificate chains against the CRLs and OCSP responders
+	// of their issuers, the revocation is not checked when it is nil
+	RevocationChecker rules.RevocationChecker
+	// TagCertificateRevocationChecker checks the tag certificates of the asset tag flavors against the tag
+	// certificates revoked by the verification service, the revocation is not checked when it is nil
+	TagCertificateRevocationChecker rules.TagCertificateRevocationChecker
+}
+
+// flavorSigningCertificates returns the certificates of all the trusted flavor signers
+func (verifierCertificates VerifierCertificates) flavorSigningCertificates() []*x509.Certificate {
+	return append([]*x509.Certificate{verifierCertificates.FlavorSigningCertificate}, verifierCertificates.FlavorCoSigningCertificates...)
 }
 
 // Verifier The interface that exposes the verification of a host manifest
 // and signed flavor.  The 'skipFlavorsignatureVerfication' parameter can
-// be used to disable the verification of the flavor signature.
+// be used to disable the verification of the flavor signature.  VerifyDelta
+// verifies a delta flavor merged with its base flavor.
 type Verifier interface {
 	Verify(hostManifest *types.HostManifest, signedFlavor *hvs.SignedFlavor, skipFlavorSignatureVerification bool) (*hvs.TrustReport, error)
+	VerifyDelta(hostManifest *types.HostManifest, baseFlavor *hvs.SignedFlavor, deltaFlavor *hvs.SignedFlavor, skipFlavorSignatureVerification bool) (*hvs.TrustReport, error)
 	GetVerifierCerts() VerifierCertificates
 }
 
@@ -54,8 +86,22 @@ func NewVerifier(verifierCertificates VerifierCertificates) (Verifier, error) {
 		return nil, errors.New("The flavor CA certificates cannot be nil")
 	}
 
+	if verifierCertificates.FlavorSignatureQuorum > len(verifierCertificates.flavorSigningCertificates()) {
+		return nil, errors.Errorf("The flavor signature quorum %d exceeds the number of flavor signing certificates", verifierCertificates.FlavorSignatureQuorum)
+	}
+
+	if verifierCertificates.MaxQuoteAge < 0 || verifierCertificates.ClockSkewThreshold < 0 {
+		return nil, errors.New("The maximum quote age and the clock skew threshold cannot be negative")
+	}
+
 	return &verifierImpl{verifierCertificates: verifierCertificates}, nil
 }
 
+// GetRuleDefinitions Returns the catalog of rules applied by the Verifier, including
+// the flavor parts each rule is applied for and the faults it may raise.
+func GetRuleDefinitions() []hvs.RuleDefinition {
+	return rules.GetRuleDefinitions()
+}
+
 var log = commLog.GetDefaultLogger()
 var secLog = commLog.GetSecurityLogger()
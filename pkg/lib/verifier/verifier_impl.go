@@ -9,6 +9,8 @@ package verifier
 //
 
 import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	flavormodel "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/verifier/rules"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
@@ -37,7 +39,76 @@ func (v *verifierImpl) Verify(hostManifest *types.HostManifest, signedFlavor *hv
 		return nil, err
 	}
 
-	results, overallTrust, err := v.applyRules(verificationRules, hostManifest, signedFlavor)
+	expectedProvenance, err := newExpectedValueProvenance(&signedFlavor.Flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	results, overallTrust, err := v.applyRules(verificationRules, hostManifest, signedFlavor, expectedProvenance)
+	if err != nil {
+		return nil, err
+	}
+
+	trustReport := hvs.TrustReport{
+		PolicyName:   policyName,
+		Results:      results,
+		Trusted:      overallTrust,
+		HostManifest: *hostManifest,
+	}
+
+	return &trustReport, nil
+}
+
+func (v *verifierImpl) VerifyDelta(hostManifest *types.HostManifest, baseFlavor *hvs.SignedFlavor, deltaFlavor *hvs.SignedFlavor, skipSignedFlavorVerification bool) (*hvs.TrustReport, error) {
+
+	if hostManifest == nil {
+		return nil, errors.New("The host manifest cannot be nil")
+	}
+
+	if baseFlavor == nil || deltaFlavor == nil {
+		return nil, errors.New("The base and delta signed flavors cannot be nil")
+	}
+
+	mergedFlavor, err := flavormodel.MergeDelta(&baseFlavor.Flavor, &deltaFlavor.Flavor)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error merging the delta flavor with its base flavor")
+	}
+
+	// the merged flavor is not signed, the signatures of the base and delta flavors are verified instead
+	signedMergedFlavor := hvs.SignedFlavor{Flavor: *mergedFlavor}
+	ruleFactory := NewRuleFactory(v.verifierCertificates, hostManifest, &signedMergedFlavor, true)
+	verificationRules, policyName, err := ruleFactory.GetVerificationRules()
+	if err != nil {
+		return nil, err
+	}
+
+	if !skipSignedFlavorVerification {
+		var flavorPart common.FlavorPart
+		err = (&flavorPart).Parse(mergedFlavor.Meta.Description.FlavorPart)
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not retrieve flavor part name")
+		}
+
+		for _, signedFlavor := range []*hvs.SignedFlavor{baseFlavor, deltaFlavor} {
+			flavorTrusted, err := rules.NewFlavorTrustedQuorum(signedFlavor,
+				v.verifierCertificates.flavorSigningCertificates(),
+				v.verifierCertificates.FlavorCACertificates,
+				v.verifierCertificates.FlavorSignatureQuorum,
+				flavorPart)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error creating the flavor trusted rule")
+			}
+			verificationRules = append(verificationRules, flavorTrusted)
+		}
+	}
+
+	// the expected values are reported as those of the delta flavor, which references its base flavor
+	expectedProvenance, err := newExpectedValueProvenance(&deltaFlavor.Flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	results, overallTrust, err := v.applyRules(verificationRules, hostManifest, &signedMergedFlavor, expectedProvenance)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +123,7 @@ func (v *verifierImpl) Verify(hostManifest *types.HostManifest, signedFlavor *hv
 	return &trustReport, nil
 }
 
-func (v *verifierImpl) applyRules(rulesToApply []rules.Rule, hostManifest *types.HostManifest, signedFlavor *hvs.SignedFlavor) ([]hvs.RuleResult, bool, error) {
+func (v *verifierImpl) applyRules(rulesToApply []rules.Rule, hostManifest *types.HostManifest, signedFlavor *hvs.SignedFlavor, expectedProvenance *hvs.ExpectedValueProvenance) ([]hvs.RuleResult, bool, error) {
 
 	var results []hvs.RuleResult
 
@@ -62,14 +133,23 @@ func (v *verifierImpl) applyRules(rulesToApply []rules.Rule, hostManifest *types
 	for _, rule := range rulesToApply {
 
 		log.Debugf("Applying verifier rule %T", rule)
+		if timedRule, ok := rule.(rules.TimedRule); ok && !v.verifierCertificates.VerificationTime.IsZero() {
+			timedRule.SetVerificationTime(v.verifierCertificates.VerificationTime)
+		}
+		if checkedRule, ok := rule.(rules.RevocationCheckedRule); ok && v.verifierCertificates.RevocationChecker != nil {
+			checkedRule.SetRevocationChecker(v.verifierCertificates.RevocationChecker)
+		}
+		if checkedRule, ok := rule.(rules.TagRevocationCheckedRule); ok && v.verifierCertificates.TagCertificateRevocationChecker != nil {
+			checkedRule.SetTagCertificateRevocationChecker(v.verifierCertificates.TagCertificateRevocationChecker)
+		}
 		result, err := rule.Apply(hostManifest)
 		if err != nil {
 			return nil, overallTrust, errors.Wrapf(err, "Error ocrurred applying rule type '%T'", rule)
 		}
 
-		// if 'Apply' returned a result with any faults, then the
-		// rule is not trusted
-		if len(result.Faults) > 0 {
+		// if 'Apply' returned a result with any faults other than
+		// warnings, then the rule is not trusted
+		if !result.IsTrusted() {
 			result.Trusted = false
 			overallTrust = false
 		}
@@ -77,6 +157,10 @@ func (v *verifierImpl) applyRules(rulesToApply []rules.Rule, hostManifest *types
 		// assign the flavor id to all rules
 		fId := signedFlavor.Flavor.Meta.ID
 		result.FlavorId = &fId
+		result.ExpectedProvenance = expectedProvenance
+		for i := range result.Faults {
+			result.Faults[i].Key = result.FaultKey(result.Faults[i])
+		}
 
 		results = append(results, *result)
 	}
@@ -84,6 +168,27 @@ func (v *verifierImpl) applyRules(rulesToApply []rules.Rule, hostManifest *types
 	return results, overallTrust, nil
 }
 
+// newExpectedValueProvenance returns the provenance of the expected values of the rules created from the flavor
+func newExpectedValueProvenance(flavor *flavormodel.Flavor) (*hvs.ExpectedValueProvenance, error) {
+	flavorVersion, err := flavor.GetFlavorVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error computing the version of the flavor")
+	}
+
+	provenance := hvs.ExpectedValueProvenance{
+		FlavorID:      flavor.Meta.ID,
+		FlavorLabel:   flavor.Meta.Description.Label,
+		FlavorPart:    flavor.Meta.Description.FlavorPart,
+		FlavorVersion: flavorVersion,
+		BaseFlavorID:  flavor.Meta.BaseFlavorID,
+		ImportSource:  flavor.Meta.Description.Source,
+	}
+	if flavor.Meta.Schema != nil {
+		provenance.Template = flavor.Meta.Schema.Uri
+	}
+	return &provenance, nil
+}
+
 func (v *verifierImpl) GetVerifierCerts() VerifierCertificates {
 	return v.verifierCertificates
 }
@@ -14,13 +14,16 @@ package verifier
 import (
 	"crypto/x509"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	flavormodel "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
 	//"sort"
+	"strings"
 	"testing"
 )
 
@@ -95,6 +98,73 @@ func TestVerifierIntegrationVMWare20(t *testing.T) {
 		verifierCertificates)
 }
 
+func TestVerifierDeltaFlavorVMWare20(t *testing.T) {
+
+	verifierCertificates, err := createVerifierCertificates(t,
+		"test_data/vmware20/PrivacyCA.pem",
+		"test_data/vmware20/flavor-signer.crt.pem",
+		"test_data/vmware20/cms-ca-cert.pem",
+		"test_data/vmware20/tag-cacerts.pem")
+	if err != nil {
+		assert.FailNowf(t, "Could not create verifier certificates for vmware 2.0", "%s", err)
+	}
+
+	var hostManifest types.HostManifest
+	var signedFlavors []hvs.SignedFlavor
+
+	manifestJSON, err := ioutil.ReadFile("test_data/vmware20/host_manifest.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(manifestJSON, &hostManifest))
+
+	flavorsJSON, err := ioutil.ReadFile("test_data/vmware20/signed_flavors.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(flavorsJSON, &signedFlavors))
+
+	// the first flavor is the PLATFORM flavor
+	baseFlavor := signedFlavors[0]
+	pcr17, err := baseFlavor.Flavor.GetPcrValue(types.SHA256, types.PCR17)
+	assert.NoError(t, err)
+
+	v, err := NewVerifier(verifierCertificates)
+	assert.NoError(t, err)
+
+	baseFlavorID := baseFlavor.Flavor.Meta.ID
+	deltaFlavor := hvs.SignedFlavor{
+		Flavor: flavormodel.Flavor{
+			Meta: flavormodel.Meta{
+				ID:           uuid.New(),
+				BaseFlavorID: &baseFlavorID,
+				Description:  baseFlavor.Flavor.Meta.Description,
+				Vendor:       baseFlavor.Flavor.Meta.Vendor,
+			},
+			Pcrs: map[string]map[string]flavormodel.PcrEx{
+				string(types.SHA256): {types.PCR17.String(): {Value: pcr17.Value}},
+			},
+		},
+	}
+
+	// a delta overriding pcr 17 with the measured value is trusted and reported against the delta flavor
+	trustReport, err := v.VerifyDelta(&hostManifest, &baseFlavor, &deltaFlavor, true)
+	assert.NoError(t, err)
+	assert.True(t, trustReport.Trusted)
+	for _, result := range trustReport.Results {
+		assert.Equal(t, deltaFlavor.Flavor.Meta.ID, *result.FlavorId)
+		// the expected values are reported with the flavors they were merged from
+		if assert.NotNil(t, result.ExpectedProvenance) {
+			assert.Equal(t, deltaFlavor.Flavor.Meta.ID, result.ExpectedProvenance.FlavorID)
+			assert.Equal(t, baseFlavorID, *result.ExpectedProvenance.BaseFlavorID)
+			assert.Equal(t, baseFlavor.Flavor.Meta.Description.Source, result.ExpectedProvenance.ImportSource)
+			assert.Len(t, result.ExpectedProvenance.FlavorVersion, 96)
+		}
+	}
+
+	// a delta overriding pcr 17 with another value is not trusted
+	deltaFlavor.Flavor.Pcrs[string(types.SHA256)][types.PCR17.String()] = flavormodel.PcrEx{Value: strings.Repeat("0", len(pcr17.Value))}
+	trustReport, err = v.VerifyDelta(&hostManifest, &baseFlavor, &deltaFlavor, true)
+	assert.NoError(t, err)
+	assert.False(t, trustReport.Trusted)
+}
+
 func runVerifierIntegrationTest(t *testing.T,
 	hostManifestFile string,
 	signedFlavorsFile string,
@@ -281,6 +351,11 @@ func (v *MockVerifier) Verify(hostManifest *types.HostManifest, signedFlavor *hv
 	return args.Get(0).(*hvs.TrustReport), args.Error(1)
 }
 
+func (v *MockVerifier) VerifyDelta(hostManifest *types.HostManifest, baseFlavor *hvs.SignedFlavor, deltaFlavor *hvs.SignedFlavor, skipFlavorSignatureVerification bool) (*hvs.TrustReport, error) {
+	args := v.Called(hostManifest, baseFlavor, deltaFlavor, skipFlavorSignatureVerification)
+	return args.Get(0).(*hvs.TrustReport), args.Error(1)
+}
+
 //-------------------------------------------------------------------------------------------------
 // R E S U L T S   S O R T
 //-------------------------------------------------------------------------------------------------
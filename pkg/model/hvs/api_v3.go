@@ -0,0 +1,17 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "encoding/json"
+
+// CollectionPage is the envelope of the collections returned by the v3 API. Items holds the page of the collection
+// starting at Offset, Next is the link to the following page when there is one.
+type CollectionPage struct {
+	Items  []json.RawMessage `json:"items"`
+	Total  int               `json:"total"`
+	Offset int               `json:"offset"`
+	Limit  int               `json:"limit"`
+	Next   string            `json:"next,omitempty"`
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent records a mutating (POST, PUT or DELETE) call to the HVS API. Before and After are the state of the
+// flavor or flavorgroup changed by the call, they are not recorded for the other resources.
+type AuditEvent struct {
+	// swagger:strfmt uuid
+	ID         uuid.UUID `json:"id"`
+	Created    time.Time `json:"created"`
+	Actor      string    `json:"actor"`
+	TenantId   string    `json:"tenant_id,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+	StatusCode int       `json:"status_code"`
+	EntityType string    `json:"entity_type,omitempty"`
+	// swagger:strfmt uuid
+	EntityID *uuid.UUID      `json:"entity_id,omitempty"`
+	Summary  string          `json:"summary"`
+	Before   json.RawMessage `json:"before,omitempty"`
+	After    json.RawMessage `json:"after,omitempty"`
+}
+
+type AuditEventCollection struct {
+	AuditEvents []*AuditEvent `json:"audit_events"`
+}
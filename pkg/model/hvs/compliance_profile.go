@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+)
+
+// ComplianceProfile is a named set of flavor parts and rules the hosts of a flavorgroup are required to pass to
+// comply with a control, e.g. "NIST-boot-integrity"
+type ComplianceProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// FlavorParts are the flavor parts the host must be trusted for
+	FlavorParts []common.FlavorPart `json:"flavor_parts,omitempty"`
+	// Rules are the names of the rules that must have been applied and passed
+	Rules []string `json:"rules,omitempty"`
+}
+
+// ComplianceResult is the outcome of a compliance profile of a flavorgroup for a host
+type ComplianceResult struct {
+	Profile string `json:"profile"`
+	// swagger:strfmt uuid
+	FlavorgroupId   uuid.UUID `json:"flavorgroup_id"`
+	FlavorgroupName string    `json:"flavorgroup_name"`
+	Compliant       bool      `json:"compliant"`
+	// FailedFlavorParts are the required flavor parts that are not trusted, or that have no results
+	FailedFlavorParts []common.FlavorPart `json:"failed_flavor_parts,omitempty"`
+	// FailedRules are the required rules that raised faults, or that were not applied
+	FailedRules []string `json:"failed_rules,omitempty"`
+}
+
+// Evaluate returns the compliance of a host with the profile, from the trust report of a flavorgroup
+func (profile ComplianceProfile) Evaluate(flavorgroup FlavorGroup, trustReport *TrustReport) ComplianceResult {
+	result := ComplianceResult{
+		Profile:         profile.Name,
+		FlavorgroupId:   flavorgroup.ID,
+		FlavorgroupName: flavorgroup.Name,
+	}
+	for _, flavorPart := range profile.FlavorParts {
+		if !trustReport.IsTrustedForMarker(flavorPart.String()) {
+			result.FailedFlavorParts = append(result.FailedFlavorParts, flavorPart)
+		}
+	}
+	for _, rule := range profile.Rules {
+		applied := false
+		passed := true
+		for _, ruleResult := range trustReport.Results {
+			if ruleResult.Rule.Name == rule {
+				applied = true
+				passed = passed && ruleResult.IsTrusted()
+			}
+		}
+		if !applied || !passed {
+			result.FailedRules = append(result.FailedRules, rule)
+		}
+	}
+	result.Compliant = len(result.FailedFlavorParts) == 0 && len(result.FailedRules) == 0
+	return result
+}
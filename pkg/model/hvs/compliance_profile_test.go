@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs_test
+
+import (
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ComplianceProfile", func() {
+
+	flavorgroup := hvs.FlavorGroup{ID: uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"), Name: "automatic"}
+	trustReport := hvs.TrustReport{Results: []hvs.RuleResult{
+		{
+			Rule:    hvs.RuleInfo{Name: constants.RulePcrMatchesConstant, Markers: []common.FlavorPart{common.FlavorPartPlatform}},
+			Trusted: true,
+		},
+		{
+			Rule:    hvs.RuleInfo{Name: constants.RulePcrEventLogIntegrity, Markers: []common.FlavorPart{common.FlavorPartOs}},
+			Faults:  []hvs.Fault{{Name: constants.FaultPcrEventLogInvalid}},
+			Trusted: false,
+		},
+	}}
+
+	Context("Provided a profile whose flavor parts and rules passed", func() {
+		It("Should be compliant", func() {
+			profile := hvs.ComplianceProfile{
+				Name:        "NIST-boot-integrity",
+				FlavorParts: []common.FlavorPart{common.FlavorPartPlatform},
+				Rules:       []string{constants.RulePcrMatchesConstant},
+			}
+			result := profile.Evaluate(flavorgroup, &trustReport)
+			Expect(result.Compliant).To(BeTrue())
+			Expect(result.Profile).To(Equal("NIST-boot-integrity"))
+			Expect(result.FlavorgroupName).To(Equal("automatic"))
+		})
+	})
+
+	Context("Provided a profile with an untrusted flavor part, a failed rule and a rule that was not applied", func() {
+		It("Should not be compliant and list them", func() {
+			profile := hvs.ComplianceProfile{
+				Name:        "NIST-boot-integrity",
+				FlavorParts: []common.FlavorPart{common.FlavorPartPlatform, common.FlavorPartOs, common.FlavorPartSoftware},
+				Rules:       []string{constants.RulePcrEventLogIntegrity, constants.RuleAssetTagMatches},
+			}
+			result := profile.Evaluate(flavorgroup, &trustReport)
+			Expect(result.Compliant).To(BeFalse())
+			Expect(result.FailedFlavorParts).To(Equal([]common.FlavorPart{common.FlavorPartOs, common.FlavorPartSoftware}))
+			Expect(result.FailedRules).To(Equal([]string{constants.RulePcrEventLogIntegrity, constants.RuleAssetTagMatches}))
+		})
+	})
+})
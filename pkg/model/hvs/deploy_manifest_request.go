@@ -13,3 +13,27 @@ type DeployManifestRequest struct {
 	// swagger:strfmt uuid
 	FlavorId uuid.UUID `json:"flavor_id"`
 }
+
+// DeployManifestsRequest deploys the manifests of several SOFTWARE flavors to a host in one call
+type DeployManifestsRequest struct {
+	// swagger:strfmt uuid
+	HostId    uuid.UUID   `json:"host_id"`
+	FlavorIds []uuid.UUID `json:"flavor_ids"`
+	// VerifyMeasurements checks that the measurements of the deployed manifests appear in the PCR 15 event log
+	VerifyMeasurements bool `json:"verify_measurements,omitempty"`
+}
+
+type DeployManifestsResponse struct {
+	// swagger:strfmt uuid
+	HostId  uuid.UUID              `json:"host_id"`
+	Results []DeployManifestResult `json:"results"`
+}
+
+type DeployManifestResult struct {
+	// swagger:strfmt uuid
+	FlavorId uuid.UUID `json:"flavor_id"`
+	Deployed bool      `json:"deployed"`
+	// Measured is only set when the measurements were verified
+	Measured *bool  `json:"measured,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
@@ -5,7 +5,13 @@
 
 package hvs
 
-import "github.com/google/uuid"
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+)
 
 type ESXiClusterCollection struct {
 	ESXiCluster []ESXiCluster `json:"esxi_clusters"`
@@ -23,3 +29,77 @@ type ESXiClusterCreateRequest struct {
 	ConnectionString string `json:"connection_string"`
 	ClusterName      string `json:"cluster_name"`
 }
+
+// ESXiClusterTrust is the trust of an ESXi cluster rolled up from the latest reports of its hosts
+type ESXiClusterTrust struct {
+	// swagger:strfmt uuid
+	Id          uuid.UUID `json:"id"`
+	ClusterName string    `json:"cluster_name"`
+	// Trusted is set when all the hosts of the cluster are trusted
+	Trusted        bool `json:"trusted"`
+	HostCount      int  `json:"host_count"`
+	TrustedCount   int  `json:"trusted_count"`
+	UntrustedCount int  `json:"untrusted_count"`
+	// UnknownCount is the number of hosts that are not registered or have no report yet
+	UnknownCount int `json:"unknown_count"`
+	// UntrustedFlavorParts is the number of hosts untrusted for each flavor part
+	UntrustedFlavorParts map[common.FlavorPart]int `json:"untrusted_flavor_parts,omitempty"`
+	// TrustScore is the average trust score of the hosts whose reports have one
+	TrustScore *float64               `json:"trust_score,omitempty"`
+	Hosts      []ESXiClusterHostTrust `json:"hosts"`
+}
+
+// ESXiClusterHostTrust is the trust of a host of an ESXi cluster from its latest report, the trust of the hosts
+// that are not registered or have no report yet is not set
+type ESXiClusterHostTrust struct {
+	HostName string `json:"host_name"`
+	// swagger:strfmt uuid
+	HostId     *uuid.UUID `json:"host_id,omitempty"`
+	Trusted    *bool      `json:"trusted,omitempty"`
+	TrustScore *float64   `json:"trust_score,omitempty"`
+	// UntrustedFlavorParts are the flavor parts the host is not trusted for
+	UntrustedFlavorParts []common.FlavorPart `json:"untrusted_flavor_parts,omitempty"`
+	ReportCreated        *time.Time          `json:"report_created,omitempty"`
+	ReportExpiration     *time.Time          `json:"report_expiration,omitempty"`
+}
+
+// NewESXiClusterTrust rolls up the trust of the hosts of the cluster
+func NewESXiClusterTrust(cluster ESXiCluster, hosts []ESXiClusterHostTrust) *ESXiClusterTrust {
+	clusterTrust := ESXiClusterTrust{
+		Id:          cluster.Id,
+		ClusterName: cluster.ClusterName,
+		HostCount:   len(hosts),
+		Hosts:       hosts,
+	}
+	var scoreSum float64
+	scoreCount := 0
+	for _, host := range hosts {
+		switch {
+		case host.Trusted == nil:
+			clusterTrust.UnknownCount++
+		case *host.Trusted:
+			clusterTrust.TrustedCount++
+		default:
+			clusterTrust.UntrustedCount++
+		}
+		for _, flavorPart := range host.UntrustedFlavorParts {
+			if clusterTrust.UntrustedFlavorParts == nil {
+				clusterTrust.UntrustedFlavorParts = make(map[common.FlavorPart]int)
+			}
+			clusterTrust.UntrustedFlavorParts[flavorPart]++
+		}
+		if host.TrustScore != nil {
+			scoreSum += *host.TrustScore
+			scoreCount++
+		}
+	}
+	if scoreCount > 0 {
+		trustScore := math.Round(scoreSum/float64(scoreCount)*100) / 100
+		clusterTrust.TrustScore = &trustScore
+	}
+	clusterTrust.Trusted = len(hosts) > 0 && clusterTrust.TrustedCount == len(hosts)
+	if clusterTrust.Hosts == nil {
+		clusterTrust.Hosts = []ESXiClusterHostTrust{}
+	}
+	return &clusterTrust
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+)
+
+// GroupedFault is a fault of the host raised by one or more rules, the faults raised by the rules for the same
+// underlying issue, e.g. a PCR that does not have its expected value, are reported once
+type GroupedFault struct {
+	Key         string `json:"fault_key"`
+	Name        string `json:"fault_name"`
+	Description string `json:"description"`
+	// FaultNames are the names of the faults of the group, when the rules raised different faults for the issue
+	FaultNames []string `json:"fault_names,omitempty"`
+	// Rules are the names of the rules that raised the faults of the group
+	Rules []string `json:"rules"`
+	// swagger:strfmt uuid
+	FlavorIds []uuid.UUID `json:"flavor_ids,omitempty"`
+	// Warning is set when all the faults of the group are warnings
+	Warning bool `json:"warning,omitempty"`
+}
+
+// FaultKey returns the key of the underlying issue of a fault of the result, the faults of the PCRs are keyed by
+// their bank and index, the faults of the measurement logs and flavor signatures by their flavor, the faults of the
+// container images by their image and the other faults by their name
+func (r *RuleResult) FaultKey(fault Fault) string {
+	if fault.Key != "" {
+		return fault.Key
+	}
+
+	name := strings.TrimPrefix(fault.Name, constants.FaultPrefix)
+	switch {
+	case fault.PcrIndex != nil:
+		return fmt.Sprintf("pcr/%s/%d", r.pcrBank(fault), *fault.PcrIndex)
+	case strings.HasPrefix(fault.Name, constants.FaultPrefix+"XmlMeasurement"):
+		return "xml-measurement-log/" + r.faultFlavorId(fault)
+	case strings.HasPrefix(fault.Name, constants.FaultPrefix+"FlavorSignature"):
+		return "flavor-signature/" + r.faultFlavorId(fault)
+	case strings.HasPrefix(fault.Name, constants.FaultPrefix+"ContainerImage") && fault.MeasurementId != nil:
+		return "container-image/" + *fault.MeasurementId
+	}
+	return name
+}
+
+// pcrBank returns the PCR bank of a PCR fault of the result
+func (r *RuleResult) pcrBank(fault Fault) string {
+	if r.Rule.ExpectedPcr != nil && r.Rule.ExpectedPcr.PcrBank != "" {
+		return string(r.Rule.ExpectedPcr.PcrBank)
+	}
+	if r.Rule.ExpectedEventLogEntry != nil && r.Rule.ExpectedEventLogEntry.PcrBank != "" {
+		return string(r.Rule.ExpectedEventLogEntry.PcrBank)
+	}
+	// the PCR value mismatch faults are named after their bank
+	if bank := strings.TrimPrefix(fault.Name, constants.FaultPcrValueMismatch); bank != fault.Name && bank != "" {
+		return bank
+	}
+	return "unknown"
+}
+
+// faultFlavorId returns the flavor of a fault of the result
+func (r *RuleResult) faultFlavorId(fault Fault) string {
+	switch {
+	case fault.FlavorId != nil:
+		return fault.FlavorId.String()
+	case r.FlavorId != nil:
+		return r.FlavorId.String()
+	case r.Rule.FlavorID != nil:
+		return r.Rule.FlavorID.String()
+	}
+	return "unknown"
+}
+
+// GroupFaults returns the faults of the report grouped by their key, in the order they were first raised. The
+// name and description of a group are those of its first fault that is not a warning.
+func (t *TrustReport) GroupFaults() []GroupedFault {
+	var groups []*GroupedFault
+	groupsByKey := map[string]*GroupedFault{}
+	for i := range t.Results {
+		result := &t.Results[i]
+		for _, fault := range result.Faults {
+			key := result.FaultKey(fault)
+			group, ok := groupsByKey[key]
+			if !ok {
+				group = &GroupedFault{Key: key, Name: fault.Name, Description: fault.Description, Warning: true}
+				groupsByKey[key] = group
+				groups = append(groups, group)
+			}
+			if group.Warning && !fault.Warning {
+				group.Name, group.Description, group.Warning = fault.Name, fault.Description, false
+			}
+			group.FaultNames = appendUnique(group.FaultNames, fault.Name)
+			group.Rules = appendUnique(group.Rules, result.Rule.Name)
+			if result.FlavorId != nil {
+				group.FlavorIds = appendUniqueId(group.FlavorIds, *result.FlavorId)
+			}
+		}
+	}
+
+	groupedFaults := make([]GroupedFault, 0, len(groups))
+	for _, group := range groups {
+		if len(group.FaultNames) == 1 {
+			group.FaultNames = nil
+		}
+		sort.Strings(group.Rules)
+		groupedFaults = append(groupedFaults, *group)
+	}
+	return groupedFaults
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func appendUniqueId(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	for _, i := range ids {
+		if i == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs_test
+
+import (
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GroupFaults", func() {
+
+	flavorId := uuid.MustParse("e6612219-b5b0-4bcd-b0b5-8b6ab2d5e2b1")
+	pcrIndex := types.PCR18
+
+	Context("Provided faults raised by different rules for the same PCR", func() {
+		It("Should return one fault listing the rules", func() {
+			trustReport := hvs.TrustReport{Results: []hvs.RuleResult{
+				{
+					Rule: hvs.RuleInfo{
+						Name:        constants.RulePcrMatchesConstant,
+						ExpectedPcr: &types.Pcr{Index: pcrIndex, PcrBank: types.SHA256},
+					},
+					Faults:   []hvs.Fault{{Name: constants.FaultPcrValueMismatchSHA256, PcrIndex: &pcrIndex}},
+					FlavorId: &flavorId,
+				},
+				{
+					Rule: hvs.RuleInfo{
+						Name:                  constants.RulePcrEventLogIntegrity,
+						ExpectedEventLogEntry: &types.EventLogEntry{PcrIndex: pcrIndex, PcrBank: types.SHA256},
+					},
+					Faults:   []hvs.Fault{{Name: constants.FaultPcrEventLogInvalid, PcrIndex: &pcrIndex}},
+					FlavorId: &flavorId,
+				},
+			}}
+
+			groupedFaults := trustReport.GroupFaults()
+			Expect(groupedFaults).To(HaveLen(1))
+			Expect(groupedFaults[0].Key).To(Equal("pcr/SHA256/18"))
+			Expect(groupedFaults[0].Name).To(Equal(constants.FaultPcrValueMismatchSHA256))
+			Expect(groupedFaults[0].Rules).To(ConsistOf(constants.RulePcrMatchesConstant, constants.RulePcrEventLogIntegrity))
+			Expect(groupedFaults[0].FaultNames).To(HaveLen(2))
+			Expect(groupedFaults[0].FlavorIds).To(Equal([]uuid.UUID{flavorId}))
+		})
+	})
+
+	Context("Provided measurement log faults of a flavor and a warning", func() {
+		It("Should group the measurement log faults and keep the warning apart", func() {
+			trustReport := hvs.TrustReport{Results: []hvs.RuleResult{
+				{
+					Rule:     hvs.RuleInfo{Name: constants.RuleXmlMeasurementLogIntegrity},
+					Faults:   []hvs.Fault{{Name: constants.FaultXmlMeasurementValueMismatch}},
+					FlavorId: &flavorId,
+				},
+				{
+					Rule: hvs.RuleInfo{Name: constants.RuleXmlMeasurementLogEquals},
+					Faults: []hvs.Fault{
+						{Name: constants.FaultXmlMeasurementLogMissingExpectedEntries},
+						{Name: constants.FaultHostClockSkewed, Warning: true},
+					},
+					FlavorId: &flavorId,
+				},
+			}}
+
+			groupedFaults := trustReport.GroupFaults()
+			Expect(groupedFaults).To(HaveLen(2))
+			Expect(groupedFaults[0].Key).To(Equal("xml-measurement-log/" + flavorId.String()))
+			Expect(groupedFaults[0].Rules).To(HaveLen(2))
+			Expect(groupedFaults[0].Warning).To(BeFalse())
+			Expect(groupedFaults[1].Key).To(Equal("HostClockSkewed"))
+			Expect(groupedFaults[1].FaultNames).To(BeNil())
+			Expect(groupedFaults[1].Warning).To(BeTrue())
+		})
+	})
+
+	Context("Provided faults of different container images", func() {
+		It("Should return one fault per container image", func() {
+			nginx, redis := "registry.example.com/nginx:1.19", "registry.example.com/redis:6.0"
+			trustReport := hvs.TrustReport{Results: []hvs.RuleResult{
+				{
+					Rule: hvs.RuleInfo{Name: constants.RuleContainerImagesMatch},
+					Faults: []hvs.Fault{
+						{Name: constants.FaultContainerImageDigestMismatch, MeasurementId: &nginx},
+						{Name: constants.FaultContainerImageMissing, MeasurementId: &redis},
+					},
+					FlavorId: &flavorId,
+				},
+			}}
+
+			groupedFaults := trustReport.GroupFaults()
+			Expect(groupedFaults).To(HaveLen(2))
+			Expect(groupedFaults[0].Key).To(Equal("container-image/" + nginx))
+			Expect(groupedFaults[1].Key).To(Equal("container-image/" + redis))
+		})
+	})
+})
@@ -29,6 +29,21 @@ type FlavorGroup struct {
 	FlavorIds     []uuid.UUID         `json:"flavorIds,omitempty"`
 	Flavors       []Flavor            `json:"flavors,omitempty"`
 	MatchPolicies FlavorMatchPolicies `json:"flavor_match_policies,omitempty"`
+	// StrictEventLogVerification faults the events with an unrecognized type or unparseable fields in the
+	// event logs evaluated for the flavorgroup instead of skipping them
+	StrictEventLogVerification bool `json:"strict_event_log_verification,omitempty"`
+	// TenantId is the tenant the flavorgroup belongs to, flavorgroups of the default namespace do not have a tenant
+	TenantId string `json:"tenant_id,omitempty"`
+	// ParentId is the flavorgroup this flavorgroup inherits from. The flavors of a flavor part and the match policy
+	// of a flavor part are inherited unless the flavorgroup has its own
+	// swagger:strfmt uuid
+	ParentId *uuid.UUID `json:"parent_id,omitempty"`
+	// ComplianceProfiles are evaluated for the hosts of the flavorgroup, their results are part of the reports
+	ComplianceProfiles []ComplianceProfile `json:"compliance_profiles,omitempty"`
+	// FlavorSelector selects the flavors of the flavorgroup by their labels, in addition to the linked flavors
+	FlavorSelector *LabelSelector `json:"flavor_selector,omitempty"`
+	// HostSelector selects the hosts of the flavorgroup by their labels, in addition to the linked hosts
+	HostSelector *LabelSelector `json:"host_selector,omitempty"`
 }
 
 type FlavorMatchPolicy struct {
@@ -85,12 +100,22 @@ func (r FlavorGroup) MarshalJSON() ([]byte, error) {
 		FlavorIds                   []uuid.UUID                 `json:"flavorIds,omitempty"`
 		Flavors                     []Flavor                    `json:"flavors,omitempty"`
 		FlavorMatchPolicyCollection FlavorMatchPolicyCollection `json:"flavor_match_policy_collection,omitempty"`
+		StrictEventLogVerification  bool                        `json:"strict_event_log_verification,omitempty"`
+		ParentId                    *uuid.UUID                  `json:"parent_id,omitempty"`
+		ComplianceProfiles          []ComplianceProfile         `json:"compliance_profiles,omitempty"`
+		FlavorSelector              *LabelSelector              `json:"flavor_selector,omitempty"`
+		HostSelector                *LabelSelector              `json:"host_selector,omitempty"`
 	}{
 		ID:                          r.ID,
 		Name:                        r.Name,
 		FlavorIds:                   r.FlavorIds,
 		Flavors:                     r.Flavors,
 		FlavorMatchPolicyCollection: FlavorMatchPolicyCollection{r.MatchPolicies},
+		StrictEventLogVerification:  r.StrictEventLogVerification,
+		ParentId:                    r.ParentId,
+		ComplianceProfiles:          r.ComplianceProfiles,
+		FlavorSelector:              r.FlavorSelector,
+		HostSelector:                r.HostSelector,
 	})
 }
 
@@ -101,6 +126,11 @@ func (r *FlavorGroup) UnmarshalJSON(b []byte) error {
 		FlavorIds                   []uuid.UUID                 `json:"flavorIds,omitempty"`
 		Flavors                     []Flavor                    `json:"flavors,omitempty"`
 		FlavorMatchPolicyCollection FlavorMatchPolicyCollection `json:"flavor_match_policy_collection,omitempty"`
+		StrictEventLogVerification  bool                        `json:"strict_event_log_verification,omitempty"`
+		ParentId                    *uuid.UUID                  `json:"parent_id,omitempty"`
+		ComplianceProfiles          []ComplianceProfile         `json:"compliance_profiles,omitempty"`
+		FlavorSelector              *LabelSelector              `json:"flavor_selector,omitempty"`
+		HostSelector                *LabelSelector              `json:"host_selector,omitempty"`
 	})
 	err := json.Unmarshal(b, decoded)
 	if err == nil {
@@ -109,6 +139,11 @@ func (r *FlavorGroup) UnmarshalJSON(b []byte) error {
 		r.FlavorIds = decoded.FlavorIds
 		r.Flavors = decoded.Flavors
 		r.MatchPolicies = decoded.FlavorMatchPolicyCollection.FlavorMatchPolicies
+		r.StrictEventLogVerification = decoded.StrictEventLogVerification
+		r.ParentId = decoded.ParentId
+		r.ComplianceProfiles = decoded.ComplianceProfiles
+		r.FlavorSelector = decoded.FlavorSelector
+		r.HostSelector = decoded.HostSelector
 	}
 	return err
 }
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+)
+
+// FlavorLearning defines a set of golden hosts and a time window over which their event logs are
+// collected to propose a flavor
+type FlavorLearning struct {
+	// swagger:strfmt uuid
+	ID         uuid.UUID   `json:"id,omitempty"`
+	Label      string      `json:"label"`
+	FlavorPart string      `json:"flavor_part"`
+	Pcrs       []int       `json:"pcrs"`
+	HostIDs    []uuid.UUID `json:"host_ids"`
+	StartTime  time.Time   `json:"start_time,omitempty"`
+	EndTime    time.Time   `json:"end_time"`
+}
+
+type FlavorLearningCollection struct {
+	FlavorLearnings []*FlavorLearning `json:"flavor_learnings"`
+}
+
+// FlavorLearningProposal is the flavor learnt from the event logs of the golden hosts. The flavor only
+// holds the pcrs that have the same value on all the hosts and the events measured on all of them,
+// volatile pcrs and events are listed per pcr bank and pcr index so that they can be reviewed.
+type FlavorLearningProposal struct {
+	// swagger:strfmt uuid
+	FlavorLearningID  uuid.UUID                              `json:"flavor_learning_id"`
+	HostManifestCount int                                    `json:"host_manifest_count"`
+	Flavor            Flavor                                 `json:"flavor"`
+	VolatilePcrs      map[string][]string                    `json:"volatile_pcrs,omitempty"`
+	VolatileEvents    map[string]map[string][]types.EventLog `json:"volatile_events,omitempty"`
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FlavorPrunePolicy selects the superseded versions of the auto-updated flavor families that are retired. The
+// newest KeepVersions versions of every family are kept, as well as the versions created less than MinAgeDays ago.
+type FlavorPrunePolicy struct {
+	KeepVersions int      `json:"keep_versions"`
+	MinAgeDays   int      `json:"min_age_days,omitempty"`
+	FlavorParts  []string `json:"flavor_parts,omitempty"`
+}
+
+// FlavorVersion is a flavor of an auto-updated flavor family, the flavors imported from the same host for the same
+// flavor part, vendor, bios or os and tpm version
+type FlavorVersion struct {
+	// swagger:strfmt uuid
+	FlavorID   uuid.UUID `json:"flavor_id"`
+	Label      string    `json:"label"`
+	FlavorPart string    `json:"flavor_part"`
+	Family     string    `json:"family"`
+	Created    time.Time `json:"created"`
+	// swagger:strfmt uuid
+	FlavorgroupIDs []uuid.UUID `json:"flavorgroup_ids,omitempty"`
+}
+
+// FlavorPrune records the flavor versions retired by a run of the flavor pruner, and the superseded versions that
+// were kept because a host's latest report references them. A preview is not recorded and does not retire flavors.
+// The retired flavors are restored, with their flavorgroups, when the prune is undone.
+type FlavorPrune struct {
+	// swagger:strfmt uuid
+	ID             uuid.UUID         `json:"id,omitempty"`
+	Policy         FlavorPrunePolicy `json:"policy"`
+	Preview        bool              `json:"preview,omitempty"`
+	Created        time.Time         `json:"created"`
+	Undone         *time.Time        `json:"undone,omitempty"`
+	RetiredFlavors []FlavorVersion   `json:"retired_flavors"`
+	InUseFlavors   []FlavorVersion   `json:"in_use_flavors,omitempty"`
+}
+
+type FlavorPruneCollection struct {
+	FlavorPrunes []*FlavorPrune `json:"flavor_prunes"`
+}
@@ -4,7 +4,12 @@
  */
 package hvs
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
+)
 
 type HostCollection struct {
 	Hosts []*Host `json:"hosts" xml:"host"`
@@ -17,18 +22,103 @@ type Host struct {
 	Description      string    `json:"description,omitempty"`
 	ConnectionString string    `json:"connection_string"`
 	// swagger:strfmt uuid
-	HardwareUuid     *uuid.UUID             `json:"hardware_uuid,omitempty"`
-	FlavorgroupNames []string               `json:"flavorgroup_names,omitempty"`
-	Report           *TrustReport           `json:"report,omitempty"`
-	Trusted          *bool                  `json:"trusted,omitempty"`
-	ConnectionStatus *HostStatusInformation `json:"status,omitempty"`
+	HardwareUuid       *uuid.UUID             `json:"hardware_uuid,omitempty"`
+	FlavorgroupNames   []string               `json:"flavorgroup_names,omitempty"`
+	Report             *TrustReport           `json:"report,omitempty"`
+	Trusted            *bool                  `json:"trusted,omitempty"`
+	ConnectionStatus   *HostStatusInformation `json:"status,omitempty"`
+	PlatformAttributes *PlatformAttributes    `json:"platform_attributes,omitempty"`
+	// TenantId is the tenant the host belongs to, hosts of the default namespace do not have a tenant
+	TenantId string `json:"tenant_id,omitempty"`
+	// Lifecycle is the registration state of the host
+	Lifecycle HostLifecycle `json:"lifecycle,omitempty"`
+	// Capabilities are the attestation features of the host discovered when it was last reached
+	Capabilities *taModel.HostCapabilities `json:"capabilities,omitempty"`
+	// Labels select the flavorgroups of the host whose host selector matches them
+	Labels map[string]string `json:"labels,omitempty"`
+	// Maintenance is set while the host is in maintenance mode
+	Maintenance *HostMaintenance `json:"maintenance,omitempty"`
+}
+
+// HostMaintenance puts a host in maintenance mode while its firmware or OS is updated. The host is not attested on
+// schedule, the reports created in the meantime are suppressed and do not trigger notifications.
+type HostMaintenance struct {
+	Reason    string    `json:"reason,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// ResumeAt is the time the maintenance ends and the host is attested again, the maintenance of hosts without
+	// one lasts until it is ended
+	ResumeAt *time.Time `json:"resume_at,omitempty"`
+}
+
+// HostMaintenanceRequest puts a host in maintenance mode until the resume time, or until the maintenance is ended
+// when there is none
+type HostMaintenanceRequest struct {
+	Reason   string     `json:"reason,omitempty"`
+	ResumeAt *time.Time `json:"resume_at,omitempty"`
 }
 
+// Active tells whether the maintenance is still in progress at the time
+func (m *HostMaintenance) Active(now time.Time) bool {
+	return m != nil && (m.ResumeAt == nil || now.Before(*m.ResumeAt))
+}
+
+// HostLifecycle is the registration state of a host
+type HostLifecycle string
+
+const (
+	// HostLifecyclePreRegistered hosts have been registered before they were reachable, HVS completes their
+	// registration when the trust agent comes online
+	HostLifecyclePreRegistered HostLifecycle = "PRE_REGISTERED"
+	// HostLifecycleRegistered hosts are attested by HVS
+	HostLifecycleRegistered HostLifecycle = "REGISTERED"
+	// HostLifecycleRegistrationFailed hosts were pre-registered with a hardware UUID that does not match the one
+	// reported by the host
+	HostLifecycleRegistrationFailed HostLifecycle = "REGISTRATION_FAILED"
+)
+
 type HostCreateRequest struct {
 	HostName         string   `json:"host_name"`
 	Description      string   `json:"description,omitempty"`
 	ConnectionString string   `json:"connection_string"`
 	FlavorgroupNames []string `json:"flavorgroup_names,omitempty"`
+	// PreRegister registers the host without connecting to it, the hardware UUID must be provided
+	PreRegister bool `json:"pre_register,omitempty"`
+	// swagger:strfmt uuid
+	HardwareUuid *uuid.UUID `json:"hardware_uuid,omitempty"`
+	// Labels select the flavorgroups of the host whose host selector matches them
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// The policies applied when a host is registered with the hardware UUID of a registered host, e.g. a cloned VM
+const (
+	// HardwareUuidCollisionReject fails the registration of the host
+	HardwareUuidCollisionReject = "reject"
+	// HardwareUuidCollisionAutoSuffix registers the host with a hardware UUID derived from the hardware UUID and the
+	// name of the host
+	HardwareUuidCollisionAutoSuffix = "auto-suffix"
+	// HardwareUuidCollisionMerge updates the registered host with the name and connection string of the host
+	HardwareUuidCollisionMerge = "merge"
+)
+
+type HardwareUuidConflictCollection struct {
+	Conflicts []HardwareUuidConflict `json:"conflicts"`
+}
+
+// HardwareUuidConflict lists the hosts registered with the same hardware UUID
+type HardwareUuidConflict struct {
+	// swagger:strfmt uuid
+	HardwareUuid uuid.UUID `json:"hardware_uuid"`
+	Hosts        []*Host   `json:"hosts"`
+}
+
+// HardwareUuidConflictResolution resolves the conflict of the hosts registered with the same hardware UUID, the host
+// identified by HostId keeps the hardware UUID and the other hosts are resolved with the auto-suffix or merge policy
+type HardwareUuidConflictResolution struct {
+	// swagger:strfmt uuid
+	HardwareUuid uuid.UUID `json:"hardware_uuid"`
+	// swagger:strfmt uuid
+	HostId     uuid.UUID `json:"host_id"`
+	Resolution string    `json:"resolution"`
 }
 
 type HostFlavorgroupCollection struct {
@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HostLifecycleState is the attestation lifecycle state of a host, it is persisted with the host status along with
+// the history of its transitions
+type HostLifecycleState string
+
+const (
+	// HostLifecycleStateRegistered hosts have been registered but not yet queued for attestation
+	HostLifecycleStateRegistered HostLifecycleState = "REGISTERED"
+	// HostLifecycleStateQueued hosts are waiting for their data to be retrieved or verified
+	HostLifecycleStateQueued HostLifecycleState = "QUEUED"
+	// HostLifecycleStateConnected hosts have had their data retrieved and are waiting for the flavor verification
+	HostLifecycleStateConnected HostLifecycleState = "CONNECTED"
+	// HostLifecycleStateAttested hosts have been verified as trusted
+	HostLifecycleStateAttested HostLifecycleState = "ATTESTED"
+	// HostLifecycleStateConnectionFailed hosts could not be reached, the host state of the status holds the reason
+	HostLifecycleStateConnectionFailed HostLifecycleState = "CONNECTION_FAILED"
+	// HostLifecycleStateUntrusted hosts have been verified as untrusted
+	HostLifecycleStateUntrusted HostLifecycleState = "UNTRUSTED"
+)
+
+// hostLifecycleTransitions lists the states each state can transition to. Hosts registered before the lifecycle was
+// introduced have no state and enter it with their next attestation.
+var hostLifecycleTransitions = map[HostLifecycleState][]HostLifecycleState{
+	"": {HostLifecycleStateRegistered, HostLifecycleStateQueued, HostLifecycleStateConnected,
+		HostLifecycleStateConnectionFailed},
+	HostLifecycleStateRegistered: {HostLifecycleStateQueued, HostLifecycleStateConnected,
+		HostLifecycleStateConnectionFailed},
+	HostLifecycleStateQueued: {HostLifecycleStateConnected, HostLifecycleStateConnectionFailed,
+		HostLifecycleStateAttested, HostLifecycleStateUntrusted},
+	HostLifecycleStateConnected: {HostLifecycleStateQueued, HostLifecycleStateConnectionFailed,
+		HostLifecycleStateAttested, HostLifecycleStateUntrusted},
+	HostLifecycleStateAttested: {HostLifecycleStateQueued, HostLifecycleStateConnected,
+		HostLifecycleStateConnectionFailed, HostLifecycleStateUntrusted},
+	HostLifecycleStateUntrusted: {HostLifecycleStateQueued, HostLifecycleStateConnected,
+		HostLifecycleStateConnectionFailed, HostLifecycleStateAttested},
+	HostLifecycleStateConnectionFailed: {HostLifecycleStateQueued, HostLifecycleStateConnected},
+}
+
+// GetHostLifecycleState converts a plain string to a HostLifecycleState, it returns false if the state is unknown
+func GetHostLifecycleState(str string) (HostLifecycleState, bool) {
+	state := HostLifecycleState(strings.ToUpper(str))
+	if _, ok := hostLifecycleTransitions[state]; !ok || state == "" {
+		return "", false
+	}
+	return state, true
+}
+
+// CanTransitionTo returns true if the host can move from the state to the next one
+func (s HostLifecycleState) CanTransitionTo(next HostLifecycleState) bool {
+	for _, state := range hostLifecycleTransitions[s] {
+		if state == next {
+			return true
+		}
+	}
+	return false
+}
+
+// HostLifecycleTransition records a change of the lifecycle state of a host
+type HostLifecycleTransition struct {
+	// swagger:strfmt uuid
+	ID uuid.UUID `json:"id"`
+	// swagger:strfmt uuid
+	HostID uuid.UUID `json:"host_id"`
+	// From is empty for the first transition of a host
+	From    HostLifecycleState `json:"from,omitempty"`
+	To      HostLifecycleState `json:"to"`
+	Reason  string             `json:"reason,omitempty"`
+	Created time.Time          `json:"created"`
+}
+
+// HostLifecycleTransitionCollection holds the lifecycle history of a host in response to an API query
+type HostLifecycleTransitionCollection struct {
+	Transitions []HostLifecycleTransition `json:"transitions" xml:"transition"`
+}
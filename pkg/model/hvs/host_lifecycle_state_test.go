@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs_test
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HostLifecycleState", func() {
+
+	Describe("Transition between lifecycle states", func() {
+		Context("Provided the states of an attestation cycle", func() {
+			It("Should allow each transition", func() {
+				Expect(hvs.HostLifecycleState("").CanTransitionTo(hvs.HostLifecycleStateRegistered)).To(BeTrue())
+				Expect(hvs.HostLifecycleStateRegistered.CanTransitionTo(hvs.HostLifecycleStateQueued)).To(BeTrue())
+				Expect(hvs.HostLifecycleStateQueued.CanTransitionTo(hvs.HostLifecycleStateConnected)).To(BeTrue())
+				Expect(hvs.HostLifecycleStateConnected.CanTransitionTo(hvs.HostLifecycleStateAttested)).To(BeTrue())
+				Expect(hvs.HostLifecycleStateAttested.CanTransitionTo(hvs.HostLifecycleStateQueued)).To(BeTrue())
+				Expect(hvs.HostLifecycleStateQueued.CanTransitionTo(hvs.HostLifecycleStateConnectionFailed)).To(BeTrue())
+				Expect(hvs.HostLifecycleStateConnectionFailed.CanTransitionTo(hvs.HostLifecycleStateQueued)).To(BeTrue())
+			})
+		})
+		Context("Provided states that skip a step of the attestation", func() {
+			It("Should not allow the transition", func() {
+				Expect(hvs.HostLifecycleStateRegistered.CanTransitionTo(hvs.HostLifecycleStateAttested)).To(BeFalse())
+				Expect(hvs.HostLifecycleStateConnectionFailed.CanTransitionTo(hvs.HostLifecycleStateUntrusted)).To(BeFalse())
+				Expect(hvs.HostLifecycleStateAttested.CanTransitionTo(hvs.HostLifecycleStateRegistered)).To(BeFalse())
+				Expect(hvs.HostLifecycleState("").CanTransitionTo(hvs.HostLifecycleStateAttested)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Parse a lifecycle state", func() {
+		Context("Provided a known state", func() {
+			It("Should return the state", func() {
+				state, ok := hvs.GetHostLifecycleState("connection_failed")
+				Expect(ok).To(BeTrue())
+				Expect(state).To(Equal(hvs.HostLifecycleStateConnectionFailed))
+			})
+		})
+		Context("Provided an unknown state", func() {
+			It("Should fail", func() {
+				_, ok := hvs.GetHostLifecycleState("QUEUE")
+				Expect(ok).To(BeFalse())
+				_, ok = hvs.GetHostLifecycleState("")
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+})
@@ -16,6 +16,12 @@ type HostStatusInformation struct {
 	// swagger:strfmt string
 	HostState         HostState `json:"host_state"`
 	LastTimeConnected time.Time `json:"last_time_connected"`
+	// LifecycleState is the attestation lifecycle state of the host and LifecycleStateChanged the time of its last
+	// transition
+	LifecycleState        HostLifecycleState `json:"lifecycle_state,omitempty"`
+	LifecycleStateChanged *time.Time         `json:"lifecycle_state_changed,omitempty"`
+	// ClockSkew is the skew of the host clock measured at the last successful connection
+	ClockSkew *types.ClockSkew `json:"clock_skew,omitempty"`
 }
 
 // HostStatus contains the response for the Host Status API for an individual host
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LabelSelectorOperator is the relation of the labels of a flavor or host to the values of a requirement
+type LabelSelectorOperator string
+
+const (
+	// LabelSelectorOpIn matches the labels whose value is one of the values of the requirement
+	LabelSelectorOpIn LabelSelectorOperator = "In"
+	// LabelSelectorOpNotIn matches the labels whose value is not one of the values, or that do not have the label
+	LabelSelectorOpNotIn LabelSelectorOperator = "NotIn"
+	// LabelSelectorOpExists matches the labels that have the label, whatever its value
+	LabelSelectorOpExists LabelSelectorOperator = "Exists"
+	// LabelSelectorOpDoesNotExist matches the labels that do not have the label
+	LabelSelectorOpDoesNotExist LabelSelectorOperator = "DoesNotExist"
+)
+
+// LabelSelectorRequirement is a requirement on the value of a label
+type LabelSelectorRequirement struct {
+	Key      string                `json:"key"`
+	Operator LabelSelectorOperator `json:"operator"`
+	Values   []string              `json:"values,omitempty"`
+}
+
+// LabelSelector selects the flavors or hosts of a flavorgroup by their labels, in addition to the ones linked to
+// the flavorgroup. The labels must satisfy all the match labels and all the match expressions.
+type LabelSelector struct {
+	MatchLabels      map[string]string          `json:"match_labels,omitempty"`
+	MatchExpressions []LabelSelectorRequirement `json:"match_expressions,omitempty"`
+}
+
+const (
+	labelMaxLength       = 63
+	labelPrefixMaxLength = 253
+)
+
+var labelKeyRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+var labelValueRegex = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+
+// ValidateLabelKey checks that the key is an optional DNS prefix followed by a name of alphanumerics, '-', '_' and
+// '.', e.g. "intel.com/datacenter"
+func ValidateLabelKey(key string) error {
+	prefixLength := strings.LastIndex(key, "/")
+	if prefixLength > labelPrefixMaxLength || len(key)-prefixLength-1 > labelMaxLength || !labelKeyRegex.MatchString(key) {
+		return errors.Errorf("Invalid label key %s", key)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks that the value is empty or made of alphanumerics, '-', '_' and '.'
+func ValidateLabelValue(value string) error {
+	if len(value) > labelMaxLength || !labelValueRegex.MatchString(value) {
+		return errors.Errorf("Invalid label value %s", value)
+	}
+	return nil
+}
+
+// ValidateLabels checks the keys and values of the labels of a flavor or host
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if err := ValidateLabelKey(key); err != nil {
+			return err
+		}
+		if err := ValidateLabelValue(value); err != nil {
+			return errors.Wrapf(err, "Invalid value of label %s", key)
+		}
+	}
+	return nil
+}
+
+// Validate checks the requirements of the selector, a selector without requirements would select every flavor or
+// host and is rejected
+func (selector LabelSelector) Validate() error {
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return errors.New("Label selector must have match labels or match expressions")
+	}
+	if err := ValidateLabels(selector.MatchLabels); err != nil {
+		return err
+	}
+	for _, requirement := range selector.MatchExpressions {
+		if err := ValidateLabelKey(requirement.Key); err != nil {
+			return err
+		}
+		switch requirement.Operator {
+		case LabelSelectorOpIn, LabelSelectorOpNotIn:
+			if len(requirement.Values) == 0 {
+				return errors.Errorf("Operator %s of label %s requires values", requirement.Operator, requirement.Key)
+			}
+			for _, value := range requirement.Values {
+				if err := ValidateLabelValue(value); err != nil {
+					return errors.Wrapf(err, "Invalid value of label %s", requirement.Key)
+				}
+			}
+		case LabelSelectorOpExists, LabelSelectorOpDoesNotExist:
+			if len(requirement.Values) != 0 {
+				return errors.Errorf("Operator %s of label %s does not take values", requirement.Operator, requirement.Key)
+			}
+		default:
+			return errors.Errorf("Invalid operator %s of label %s", requirement.Operator, requirement.Key)
+		}
+	}
+	return nil
+}
+
+// IsEmpty returns true if the selector has no requirements, an empty selector does not select anything
+func (selector *LabelSelector) IsEmpty() bool {
+	return selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0)
+}
+
+// Matches returns true if the labels satisfy all the requirements of the selector
+func (selector *LabelSelector) Matches(labels map[string]string) bool {
+	if selector.IsEmpty() {
+		return false
+	}
+	for key, value := range selector.MatchLabels {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	for _, requirement := range selector.MatchExpressions {
+		labelValue, ok := labels[requirement.Key]
+		switch requirement.Operator {
+		case LabelSelectorOpIn:
+			if !ok || !containsLabelValue(requirement.Values, labelValue) {
+				return false
+			}
+		case LabelSelectorOpNotIn:
+			if ok && containsLabelValue(requirement.Values, labelValue) {
+				return false
+			}
+		case LabelSelectorOpExists:
+			if !ok {
+				return false
+			}
+		case LabelSelectorOpDoesNotExist:
+			if ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func containsLabelValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs_test
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LabelSelector", func() {
+
+	labels := map[string]string{"intel.com/datacenter": "dc1", "os": "rhel"}
+
+	Context("Provided a selector whose requirements are satisfied by the labels", func() {
+		It("Should match", func() {
+			selector := hvs.LabelSelector{
+				MatchLabels: map[string]string{"intel.com/datacenter": "dc1"},
+				MatchExpressions: []hvs.LabelSelectorRequirement{
+					{Key: "os", Operator: hvs.LabelSelectorOpIn, Values: []string{"rhel", "ubuntu"}},
+					{Key: "tier", Operator: hvs.LabelSelectorOpNotIn, Values: []string{"test"}},
+					{Key: "os", Operator: hvs.LabelSelectorOpExists},
+					{Key: "tier", Operator: hvs.LabelSelectorOpDoesNotExist},
+				},
+			}
+			Expect(selector.Validate()).To(Succeed())
+			Expect(selector.Matches(labels)).To(BeTrue())
+		})
+	})
+
+	Context("Provided a selector with a requirement not satisfied by the labels", func() {
+		It("Should not match", func() {
+			Expect((&hvs.LabelSelector{MatchLabels: map[string]string{"intel.com/datacenter": "dc2"}}).Matches(labels)).To(BeFalse())
+			Expect((&hvs.LabelSelector{MatchExpressions: []hvs.LabelSelectorRequirement{
+				{Key: "os", Operator: hvs.LabelSelectorOpNotIn, Values: []string{"rhel"}},
+			}}).Matches(labels)).To(BeFalse())
+			Expect((&hvs.LabelSelector{MatchExpressions: []hvs.LabelSelectorRequirement{
+				{Key: "os", Operator: hvs.LabelSelectorOpDoesNotExist},
+			}}).Matches(labels)).To(BeFalse())
+		})
+	})
+
+	Context("Provided an empty selector", func() {
+		It("Should not match and should be invalid", func() {
+			var selector *hvs.LabelSelector
+			Expect(selector.Matches(labels)).To(BeFalse())
+			Expect(hvs.LabelSelector{}.Validate()).To(HaveOccurred())
+		})
+	})
+
+	Context("Provided invalid selectors and labels", func() {
+		It("Should fail validation", func() {
+			Expect(hvs.LabelSelector{MatchExpressions: []hvs.LabelSelectorRequirement{
+				{Key: "os", Operator: hvs.LabelSelectorOpIn},
+			}}.Validate()).To(HaveOccurred())
+			Expect(hvs.LabelSelector{MatchExpressions: []hvs.LabelSelectorRequirement{
+				{Key: "os", Operator: hvs.LabelSelectorOpExists, Values: []string{"rhel"}},
+			}}.Validate()).To(HaveOccurred())
+			Expect(hvs.LabelSelector{MatchExpressions: []hvs.LabelSelectorRequirement{
+				{Key: "os", Operator: "Equals", Values: []string{"rhel"}},
+			}}.Validate()).To(HaveOccurred())
+			Expect(hvs.ValidateLabels(map[string]string{"-os": "rhel"})).To(HaveOccurred())
+			Expect(hvs.ValidateLabels(map[string]string{"os": "rhel 8"})).To(HaveOccurred())
+			Expect(hvs.ValidateLabels(map[string]string{"os": ""})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlatformCertificate struct
+type PlatformCertificate struct {
+	// swagger:strfmt uuid
+	ID uuid.UUID `json:"id,omitempty"`
+	// swagger:strfmt uuid
+	HardwareUUID        uuid.UUID `json:"hardware_uuid"`
+	Certificate         string    `json:"certificate"`
+	Issuer              string    `json:"issuer,omitempty"`
+	Manufacturer        string    `json:"manufacturer,omitempty"`
+	Model               string    `json:"model,omitempty"`
+	Serial              string    `json:"serial,omitempty"`
+	Version             string    `json:"version,omitempty"`
+	CertificateDigest   string    `json:"certificate_digest,omitempty"`
+	EkCertificateDigest string    `json:"ek_certificate_digest,omitempty"`
+	NotBefore           time.Time `json:"not_before,omitempty"`
+	NotAfter            time.Time `json:"not_after,omitempty"`
+	Revoked             bool      `json:"revoked,omitempty"`
+}
+
+type PlatformCertificateCollection struct {
+	PlatformCertificates []*PlatformCertificate `json:"platform_certificates"`
+}
+
+// PlatformAttributes are the platform identity attributes verified from a platform certificate
+type PlatformAttributes struct {
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Model        string `json:"model,omitempty"`
+	Serial       string `json:"serial,omitempty"`
+	Version      string `json:"version,omitempty"`
+	// swagger:strfmt uuid
+	CertificateID uuid.UUID `json:"certificate_id,omitempty"`
+}
@@ -6,39 +6,155 @@
 package hvs
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/serialize"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	taModel "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
-	"time"
 )
 
+// ReportSchemaVersion is the schema version of the report JSON written by this version
+const ReportSchemaVersion = 1
+
+// reportSchema upgrades the reports returned by previous versions
+var reportSchema = serialize.SchemaUpgrader{
+	Kind:           "report",
+	CurrentVersion: ReportSchemaVersion,
+	Upgrades:       map[int]serialize.SchemaUpgrade{},
+}
+
 type ReportCollection struct {
 	Reports []*Report `json:"reports" xml:"reports"`
 }
 
 type Report struct {
+	SchemaVersion int `json:"schema_version,omitempty"`
 	// swagger:strfmt uuid
 	ID               uuid.UUID        `json:"id"`
 	TrustInformation TrustInformation `json:"trust_information"`
 	// swagger:strfmt uuid
-	HostID      uuid.UUID        `json:"host_id"`
-	TrustReport TrustReport      `json:"-"`
-	Saml        string           `json:"-"`
-	HostInfo    taModel.HostInfo `json:"host_info"`
-	CreatedAt   time.Time        `json:"created"`
-	Expiration  time.Time        `json:"expiration"`
+	HostID             uuid.UUID           `json:"host_id"`
+	TrustReport        TrustReport         `json:"-"`
+	Saml               string              `json:"-"`
+	HostInfo           taModel.HostInfo    `json:"host_info"`
+	CreatedAt          time.Time           `json:"created"`
+	Expiration         time.Time           `json:"expiration"`
+	PlatformAttributes *PlatformAttributes `json:"platform_attributes,omitempty"`
+	StageTimings       *ReportStageTimings `json:"stage_timings,omitempty"`
+	Status             ReportStatus        `json:"status,omitempty"`
+}
+
+// unversionedReport has the fields of Report without its JSON encoding
+type unversionedReport Report
+
+// MarshalJSON writes the report with the current schema version
+func (report Report) MarshalJSON() ([]byte, error) {
+	versioned := unversionedReport(report)
+	versioned.SchemaVersion = ReportSchemaVersion
+	return json.Marshal(versioned)
+}
+
+// UnmarshalJSON reads a report written with any schema version, the reports of previous versions are
+// upgraded to the current version
+func (report *Report) UnmarshalJSON(b []byte) error {
+	decoded := unversionedReport{}
+	// the fields decoded before an error are kept, as they are by json.Unmarshal
+	err := json.Unmarshal(b, &decoded)
+	if err == nil && reportSchema.NeedsUpgrade(decoded.SchemaVersion) {
+		var upgraded []byte
+		if upgraded, err = reportSchema.Upgrade(b, decoded.SchemaVersion); err != nil {
+			return err
+		}
+		decoded = unversionedReport{}
+		err = json.Unmarshal(upgraded, &decoded)
+	}
+	if decoded.SchemaVersion < ReportSchemaVersion {
+		decoded.SchemaVersion = ReportSchemaVersion
+	}
+	*report = Report(decoded)
+	return err
+}
+
+// ReportStageTimings is the time in milliseconds spent in each stage of the attestation of a host,
+// from the time the request was queued until the report was persisted
+type ReportStageTimings struct {
+	QueueWait      int64 `json:"queue_wait_ms"`
+	QuoteRetrieval int64 `json:"quote_retrieval_ms"`
+	FlavorMatch    int64 `json:"flavor_match_ms"`
+	RuleEvaluation int64 `json:"rule_evaluation_ms"`
+	Signing        int64 `json:"signing_ms"`
+	Persistence    int64 `json:"persistence_ms"`
+	Total          int64 `json:"total_ms"`
+}
+
+// AttestationLatencyStage aggregates the timings of a single attestation stage
+type AttestationLatencyStage struct {
+	TotalMs   int64 `json:"total_ms"`
+	AverageMs int64 `json:"average_ms"`
+	MaxMs     int64 `json:"max_ms"`
+	LastMs    int64 `json:"last_ms"`
+}
+
+// AttestationLatencyMetrics aggregates the stage timings of the reports created since HVS was started.
+// When a latency budget is configured, the stages of the reports that exceeded it are aggregated separately
+// in over_budget_stages so that the stage responsible for the budget being missed can be identified.
+type AttestationLatencyMetrics struct {
+	ReportCount      int64                              `json:"report_count"`
+	BudgetMs         int64                              `json:"budget_ms,omitempty"`
+	OverBudgetCount  int64                              `json:"over_budget_count"`
+	Stages           map[string]AttestationLatencyStage `json:"stages"`
+	OverBudgetStages map[string]AttestationLatencyStage `json:"over_budget_stages,omitempty"`
 }
 
 type TrustInformation struct {
 	Overall     bool                                    `json:"OVERALL"`
 	FlavorTrust map[common.FlavorPart]FlavorTrustStatus `json:"flavors_trust"`
+	// GroupedFaults are the faults of the host, one for each underlying issue
+	GroupedFaults []GroupedFault `json:"grouped_faults,omitempty"`
+	// Compliance is the pass/fail of each compliance profile of the flavorgroups of the host
+	Compliance []ComplianceResult `json:"compliance,omitempty"`
+	// TrustScore is the weighted share of the rule results that passed, from 0 to MaxTrustScore
+	TrustScore *float64 `json:"trust_score,omitempty"`
 }
 
+// FlavorTrustStatus is the trust of a flavor part of the host. The trust of the part holds until ValidUntil, the
+// expiration of the report or of a certificate the trust of the part depends on, whichever comes first.
 type FlavorTrustStatus struct {
-	Trust                bool         `json:"trust"`
+	Trust      bool       `json:"trust"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	// swagger:strfmt uuid
+	FlavorIds            []uuid.UUID  `json:"flavor_ids,omitempty"`
 	RuleResultCollection []RuleResult `json:"rules"`
 }
 
+// NewTrustInformation returns the overall trust of the host and the trust of each of its flavor parts that has
+// results in the trust report
+func NewTrustInformation(trustReport TrustReport, expiration time.Time) *TrustInformation {
+	tr := NewTrustReport(trustReport)
+	flavorsTrustStatus := make(map[common.FlavorPart]FlavorTrustStatus)
+	for _, flavorPart := range common.GetFlavorTypes() {
+		results := tr.GetResultsForMarker(flavorPart.String())
+		if len(results) == 0 {
+			continue
+		}
+		status := FlavorTrustStatus{
+			Trust:                tr.IsTrustedForMarker(flavorPart.String()),
+			ValidUntil:           tr.GetValidUntilForMarker(flavorPart.String()),
+			FlavorIds:            tr.GetFlavorIdsForMarker(flavorPart.String()),
+			RuleResultCollection: results,
+		}
+		if !expiration.IsZero() && (status.ValidUntil == nil || expiration.Before(*status.ValidUntil)) {
+			validUntil := expiration
+			status.ValidUntil = &validUntil
+		}
+		flavorsTrustStatus[flavorPart] = status
+	}
+	return &TrustInformation{Overall: tr.IsTrusted(), FlavorTrust: flavorsTrustStatus, GroupedFaults: tr.GroupFaults(),
+		Compliance: trustReport.ComplianceResults, TrustScore: trustReport.TrustScore}
+}
+
 type ReportCreateRequest struct {
 	// swagger:strfmt uuid
 	HostID uuid.UUID `json:"host_id"`
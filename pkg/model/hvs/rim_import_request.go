@@ -0,0 +1,21 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/model"
+
+// RimImportRequest carries a TCG PC Client reference integrity manifest (RIM) published by a platform vendor,
+// from which a PLATFORM flavor is created
+type RimImportRequest struct {
+	// BaseRim is the signed SWID tag of the base RIM
+	// swagger:strfmt base64
+	BaseRim []byte `json:"base_rim"`
+	// SupportRim is the TCG event log with the reference measurements, referenced by the payload of the base RIM
+	// swagger:strfmt base64
+	SupportRim []byte `json:"support_rim"`
+	// Feature holds the platform features expected on the hosts, which are not described by the RIM
+	Feature          *model.Feature `json:"feature,omitempty"`
+	FlavorgroupNames []string       `json:"flavorgroup_names,omitempty"`
+}
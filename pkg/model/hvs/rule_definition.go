@@ -0,0 +1,20 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+
+// RuleDefinition describes a verifier rule, the flavor parts it is applied for and the faults it may raise
+type RuleDefinition struct {
+	Name        string              `json:"rule_name"`
+	FlavorParts []common.FlavorPart `json:"flavor_parts"`
+	Faults      []string            `json:"faults,omitempty"`
+	Description string              `json:"description"`
+}
+
+type RuleDefinitionCollection struct {
+	RuleDefinitions []RuleDefinition `json:"rule_definitions"`
+}
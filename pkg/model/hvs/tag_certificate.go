@@ -25,6 +25,8 @@ type TagCertificate struct {
 	// swagger:strfmt uuid
 	HardwareUUID  uuid.UUID `json:"hardware_uuid"`
 	TagCertDigest string    `json:"asset_tag_digest"`
+	// Revoked tag certificates can not be deployed, their asset tag flavors are deleted when they are revoked
+	Revoked bool `json:"revoked,omitempty"`
 }
 
 // TagCertificateCollection is the response sent by the tag-certificate API
@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "time"
+
+// TenantUsage is the usage of HVS by an identity of a tenant on a day (UTC). The reports generated for the hosts
+// of a tenant are not made by an identity, they are counted without one.
+type TenantUsage struct {
+	TenantId          string    `json:"tenant_id"`
+	Identity          string    `json:"identity,omitempty"`
+	Day               time.Time `json:"day"`
+	ApiCalls          int64     `json:"api_calls"`
+	ReportGenerations int64     `json:"report_generations"`
+}
+
+// TenantUsageReport sums up the usage of each tenant over a period, for the chargeback of a shared attestation
+// infrastructure. Hosts of the default namespace are reported under the empty tenant.
+type TenantUsageReport struct {
+	FromDate *time.Time           `json:"from_date,omitempty"`
+	ToDate   *time.Time           `json:"to_date,omitempty"`
+	Tenants  []TenantUsageSummary `json:"tenants"`
+}
+
+// TenantUsageSummary is the usage of a tenant over the period of the report, the host count is the number of hosts
+// the tenant has registered when the report is made
+type TenantUsageSummary struct {
+	TenantId          string          `json:"tenant_id"`
+	ApiCalls          int64           `json:"api_calls"`
+	ReportGenerations int64           `json:"report_generations"`
+	HostCount         int             `json:"host_count"`
+	Identities        []IdentityUsage `json:"identities,omitempty"`
+}
+
+// IdentityUsage is the number of API calls made by an identity of a tenant
+type IdentityUsage struct {
+	Identity string `json:"identity"`
+	ApiCalls int64  `json:"api_calls"`
+}
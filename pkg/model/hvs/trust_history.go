@@ -0,0 +1,39 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The intervals the trust history of a host is bucketed by, the days and weeks (starting on Monday) are in UTC
+const (
+	TrustHistoryIntervalHour = "hour"
+	TrustHistoryIntervalDay  = "day"
+	TrustHistoryIntervalWeek = "week"
+)
+
+// TrustHistory is the trust of a host over a period, summed up per time bucket. The buckets in which no report was
+// created for the host are left out.
+type TrustHistory struct {
+	// swagger:strfmt uuid
+	HostId   uuid.UUID            `json:"host_id"`
+	FromDate time.Time            `json:"from_date"`
+	ToDate   time.Time            `json:"to_date"`
+	Interval string               `json:"interval"`
+	Buckets  []TrustHistoryBucket `json:"buckets"`
+}
+
+// TrustHistoryBucket counts the trusted and untrusted reports of a host created in the bucket starting at Start,
+// the bucket is trusted when all of them are. Faults counts the faults of the untrusted reports by fault name.
+type TrustHistoryBucket struct {
+	Start            time.Time        `json:"start"`
+	Trusted          bool             `json:"trusted"`
+	TrustedReports   int64            `json:"trusted_reports"`
+	UntrustedReports int64            `json:"untrusted_reports"`
+	Faults           map[string]int64 `json:"faults,omitempty"`
+}
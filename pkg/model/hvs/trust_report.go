@@ -9,26 +9,91 @@ package hvs
 //
 
 import (
+	"encoding/json"
+	"sort"
+	"time"
+
 	"github.com/google/uuid"
 	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/serialize"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
 	ta "github.com/intel-secl/intel-secl/v3/pkg/model/ta"
 )
 
+// TrustReportSchemaVersion is the schema version of the trust report JSON written by this version
+const TrustReportSchemaVersion = 1
+
+// trustReportSchema upgrades the trust reports stored by previous versions
+var trustReportSchema = serialize.SchemaUpgrader{
+	Kind:           "trust report",
+	CurrentVersion: TrustReportSchemaVersion,
+	Upgrades:       map[int]serialize.SchemaUpgrade{},
+}
+
 type TrustReport struct {
-	PolicyName   string             `json:"policy_name"`
-	Results      []RuleResult       `json:"results"`
-	Trusted      bool               `json:"trusted"`
-	HostManifest types.HostManifest `json:"host_manifest"`
+	SchemaVersion int                `json:"schema_version,omitempty"`
+	PolicyName    string             `json:"policy_name"`
+	Results       []RuleResult       `json:"results"`
+	Trusted       bool               `json:"trusted"`
+	HostManifest  types.HostManifest `json:"host_manifest"`
+	// StageTimings is the time spent in each stage of the attestation that created the report
+	StageTimings *ReportStageTimings `json:"stage_timings,omitempty"`
+	// ComplianceResults are the results of the compliance profiles of the flavorgroups of the host
+	ComplianceResults []ComplianceResult `json:"compliance_results,omitempty"`
+	// Status is set on the reports that are not to be acted upon
+	Status ReportStatus `json:"status,omitempty"`
+	// TrustScore is the weighted share of the rule results that passed, from 0 to MaxTrustScore, it is only set when
+	// the trust score is configured
+	TrustScore *float64 `json:"trust_score,omitempty"`
 }
 
+// ReportStatus qualifies the reports that are not to be acted upon
+type ReportStatus string
+
+// ReportStatusSuppressedMaintenance reports were created while their host was in maintenance mode, they are not
+// part of the trust history of the host and do not trigger notifications
+const ReportStatusSuppressedMaintenance ReportStatus = "suppressed-maintenance"
+
 type RuleResult struct {
 	Rule RuleInfo `json:"rule"`
 	// swagger:strfmt uuid
 	FlavorId *uuid.UUID `json:"flavor_id,omitempty"`
 	Faults   []Fault    `json:"faults,omitempty"`
 	Trusted  bool       `json:"trusted"`
+	// ValidUntil is the time the certificate the result depends on expires, after which the result no longer holds
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	// ExpectedProvenance records the flavor the expected values of the rule come from
+	ExpectedProvenance *ExpectedValueProvenance `json:"expected_provenance,omitempty"`
+	// ActualProvenance records where in the host manifest the actual values compared by the rule are
+	ActualProvenance []ActualValueProvenance `json:"actual_provenance,omitempty"`
+}
+
+// ExpectedValueProvenance identifies the flavor the expected values of a rule come from, so that a report can be
+// audited without the flavor being looked up
+type ExpectedValueProvenance struct {
+	// swagger:strfmt uuid
+	FlavorID    uuid.UUID `json:"flavor_id"`
+	FlavorLabel string    `json:"flavor_label,omitempty"`
+	FlavorPart  string    `json:"flavor_part,omitempty"`
+	// FlavorVersion is the hex encoded SHA384 digest of the signed content of the flavor, it changes whenever the
+	// expected values of the flavor change
+	FlavorVersion string `json:"flavor_version,omitempty"`
+	// BaseFlavorID is set when the expected values are those of a delta flavor merged with its base flavor
+	// swagger:strfmt uuid
+	BaseFlavorID *uuid.UUID `json:"base_flavor_id,omitempty"`
+	// Template is the schema the flavor was created with
+	Template string `json:"template,omitempty"`
+	// ImportSource is the host the flavor was imported from, it is empty for the flavors created from their content
+	ImportSource string `json:"import_source,omitempty"`
+}
+
+// ActualValueProvenance identifies the values of the host manifest compared by a rule
+type ActualValueProvenance struct {
+	// ManifestPath is the path of the values in the JSON of the host manifest, e.g. pcr_manifest.sha2pcrs
+	ManifestPath string          `json:"manifest_path"`
+	PcrBank      string          `json:"pcr_bank,omitempty"`
+	PcrIndex     *types.PcrIndex `json:"pcr_index,omitempty"`
 }
 
 type RuleInfo struct {
@@ -36,14 +101,19 @@ type RuleInfo struct {
 	Markers     []common.FlavorPart `json:"markers,omitempty"`
 	ExpectedPcr *types.Pcr          `json:"expected_pcr,omitempty"`
 	// swagger:strfmt uuid
-	FlavorID              *uuid.UUID             `json:"flavor_id,omitempty"`
-	FlavorName            *string                `json:"flavor_name,omitempty"`
-	ExpectedValue         *string                `json:"expected_value,omitempty"`
-	ExpectedMeasurements  []ta.FlavorMeasurement `json:"expected_measurements,omitempty"`
-	ExpectedEventLogs     []types.EventLog       `json:"expected,omitempty"`
-	ExpectedEventLogEntry *types.EventLogEntry   `json:"expected,omitempty"`
-	ExpectedTag           []byte                 `json:"expected_tag,omitempty"`
-	Tags                  map[string]string      `json:"tags,omitempty"`
+	FlavorID              *uuid.UUID                `json:"flavor_id,omitempty"`
+	FlavorName            *string                   `json:"flavor_name,omitempty"`
+	ExpectedValue         *string                   `json:"expected_value,omitempty"`
+	ExpectedMeasurements  []ta.FlavorMeasurement    `json:"expected_measurements,omitempty"`
+	ExpectedEventLogs     []types.EventLog          `json:"expected,omitempty"`
+	ExpectedEventLogEntry *types.EventLogEntry      `json:"expected,omitempty"`
+	EventLogExclusions    []types.EventLogExclusion `json:"event_log_exclusions,omitempty"`
+	ExpectedTag           []byte                    `json:"expected_tag,omitempty"`
+	Tags                  map[string]string         `json:"tags,omitempty"`
+	// ExpectedContainerImages are the container images of a CONTAINER_IMAGE flavor
+	ExpectedContainerImages []ta.ContainerImageMeasurement `json:"expected_container_images,omitempty"`
+	// ExpectedFirmware are the firmware versions of a PLATFORM flavor
+	ExpectedFirmware []ta.FirmwareComponent `json:"expected_firmware,omitempty"`
 }
 
 type Fault struct {
@@ -64,6 +134,42 @@ type Fault struct {
 	MeasurementId          *string                `json:"measurement_id,omitempty"`
 	FlavorDigestAlg        *string                `json:"flavor_digest_alg,omitempty"`
 	MeasurementDigestAlg   *string                `json:"measurement_digest_alg,omitempty"`
+	// Warning is set on the faults that are reported without making the rule untrusted
+	Warning bool `json:"warning,omitempty"`
+	// Key identifies the underlying issue of the fault, the faults raised by different rules for the same issue
+	// have the same key
+	Key string `json:"fault_key,omitempty"`
+}
+
+// unversionedTrustReport has the fields of TrustReport without its JSON encoding
+type unversionedTrustReport TrustReport
+
+// MarshalJSON writes the trust report with the current schema version
+func (trustReport TrustReport) MarshalJSON() ([]byte, error) {
+	versioned := unversionedTrustReport(trustReport)
+	versioned.SchemaVersion = TrustReportSchemaVersion
+	return json.Marshal(versioned)
+}
+
+// UnmarshalJSON reads a trust report written with any schema version, the trust reports of previous versions are
+// upgraded to the current version
+func (trustReport *TrustReport) UnmarshalJSON(b []byte) error {
+	decoded := unversionedTrustReport{}
+	// the fields decoded before an error are kept, as they are by json.Unmarshal
+	err := json.Unmarshal(b, &decoded)
+	if err == nil && trustReportSchema.NeedsUpgrade(decoded.SchemaVersion) {
+		var upgraded []byte
+		if upgraded, err = trustReportSchema.Upgrade(b, decoded.SchemaVersion); err != nil {
+			return err
+		}
+		decoded = unversionedTrustReport{}
+		err = json.Unmarshal(upgraded, &decoded)
+	}
+	if decoded.SchemaVersion < TrustReportSchemaVersion {
+		decoded.SchemaVersion = TrustReportSchemaVersion
+	}
+	*trustReport = TrustReport(decoded)
+	return err
 }
 
 func NewTrustReport(report TrustReport) *TrustReport {
@@ -104,6 +210,39 @@ func (t *TrustReport) GetResultsForMarker(marker string) []RuleResult {
 	return ruleResults
 }
 
+// GetFlavorIdsForMarker returns the IDs of the flavors the results of the marker were evaluated against
+func (t *TrustReport) GetFlavorIdsForMarker(marker string) []uuid.UUID {
+	var flavorIds []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, result := range t.GetResultsForMarker(marker) {
+		flavorId := result.FlavorId
+		if flavorId == nil {
+			flavorId = result.Rule.FlavorID
+		}
+		if flavorId != nil && !seen[*flavorId] {
+			seen[*flavorId] = true
+			flavorIds = append(flavorIds, *flavorId)
+		}
+	}
+	sort.Slice(flavorIds, func(i, j int) bool {
+		return flavorIds[i].String() < flavorIds[j].String()
+	})
+	return flavorIds
+}
+
+// GetValidUntilForMarker returns the earliest time the results of the marker are valid until, nil when none of
+// them depends on a certificate that expires
+func (t *TrustReport) GetValidUntilForMarker(marker string) *time.Time {
+	var validUntil *time.Time
+	for _, result := range t.GetResultsForMarker(marker) {
+		if result.ValidUntil != nil && (validUntil == nil || result.ValidUntil.Before(*validUntil)) {
+			resultValidUntil := *result.ValidUntil
+			validUntil = &resultValidUntil
+		}
+	}
+	return validUntil
+}
+
 // TrustReport.java 106
 func (t *TrustReport) CheckResultExists(targetRuleResult RuleResult) bool {
 	//In TrustReport.java 107 marker := targetRuleResult.Rule.Markers[0] why dont we iterate all over the markers?
@@ -128,10 +267,7 @@ func (t *TrustReport) CheckResultExists(targetRuleResult RuleResult) bool {
 					continue
 				}
 			default:
-				if len(targetRuleResult.Faults) > 0 {
-					return false
-				}
-				return true
+				return targetRuleResult.IsTrusted()
 			}
 		}
 	}
@@ -166,9 +302,18 @@ func (r *RuleResult) equals(target RuleResult) bool {
 	return false
 }
 
+// IsTrusted returns true if the result has no fault, other than warnings
 func (r *RuleResult) IsTrusted() bool {
-	if r.Faults == nil || len(r.Faults) == 0 {
-		return true
+	return r.FaultCount() == 0
+}
+
+// FaultCount returns the number of faults of the result that make it untrusted, warnings are not counted
+func (r *RuleResult) FaultCount() int {
+	count := 0
+	for _, fault := range r.Faults {
+		if !fault.Warning {
+			count++
+		}
 	}
-	return false
+	return count
 }
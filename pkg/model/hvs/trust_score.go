@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"math"
+	"strings"
+
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/pkg/errors"
+)
+
+// RuleSeverity is how much a rule weighs in the trust score of a host
+type RuleSeverity string
+
+const (
+	RuleSeverityCritical RuleSeverity = "critical"
+	RuleSeverityHigh     RuleSeverity = "high"
+	RuleSeverityMedium   RuleSeverity = "medium"
+	RuleSeverityLow      RuleSeverity = "low"
+)
+
+// MaxTrustScore is the score of a host all the rules of which passed
+const MaxTrustScore = 100
+
+// defaultSeverityWeights are the weights of the severities that are not configured
+var defaultSeverityWeights = map[RuleSeverity]float64{
+	RuleSeverityCritical: 10,
+	RuleSeverityHigh:     5,
+	RuleSeverityMedium:   3,
+	RuleSeverityLow:      1,
+}
+
+// defaultRuleSeverities are the severities of the rules that are not configured, the other rules are of medium
+// severity
+var defaultRuleSeverities = map[string]RuleSeverity{
+	constants.RuleAikCertificateTrusted:       RuleSeverityCritical,
+	constants.RuleFlavorTrusted:               RuleSeverityCritical,
+	constants.RulePcrEventLogIntegrity:        RuleSeverityCritical,
+	constants.RuleXmlMeasurementLogIntegrity:  RuleSeverityCritical,
+	constants.RuleQuoteNonceBound:             RuleSeverityCritical,
+	constants.RuleQuoteDigestMatches:          RuleSeverityCritical,
+	constants.RulePcrMatchesConstant:          RuleSeverityHigh,
+	constants.RulePcrEventLogEquals:           RuleSeverityHigh,
+	constants.RulePcrEventLogEqualsExcluding:  RuleSeverityHigh,
+	constants.RulePcrEventLogIncludes:         RuleSeverityHigh,
+	constants.RuleStrictEventLog:              RuleSeverityHigh,
+	constants.RuleQuoteFresh:                  RuleSeverityHigh,
+	constants.RuleFirmwareVersionsMatch:       RuleSeverityHigh,
+	constants.RuleXmlMeasurementsDigestEquals: RuleSeverityMedium,
+	constants.RuleXmlMeasurementLogEquals:     RuleSeverityMedium,
+	constants.RuleContainerImagesMatch:        RuleSeverityMedium,
+	constants.RuleTagCertificateTrusted:       RuleSeverityMedium,
+	constants.RuleAssetTagMatches:             RuleSeverityLow,
+}
+
+// TrustScoreWeights configure the trust score of the hosts: the share of the weight of the rule results of the
+// trust report that passed, from 0 to MaxTrustScore. The weight of a result is the weight of the severity of its rule
+// times the weight of its flavor part, so that the schedulers can prefer the most trusted hosts among the untrusted
+// ones. The weights that are not configured have their default value, the flavor parts weigh 1 by default.
+type TrustScoreWeights struct {
+	// Severities are the weights of the critical, high, medium and low severities
+	Severities map[RuleSeverity]float64 `json:"severities,omitempty" yaml:"severities" mapstructure:"severities"`
+	// Rules are the severities of the rules, by rule name with or without the policy prefix, e.g. PcrMatchesConstant
+	Rules map[string]RuleSeverity `json:"rules,omitempty" yaml:"rules" mapstructure:"rules"`
+	// FlavorParts are the weights of the flavor parts, e.g. PLATFORM
+	FlavorParts map[string]float64 `json:"flavor_parts,omitempty" yaml:"flavor-parts" mapstructure:"flavor-parts"`
+}
+
+// Check verifies that the weights are not negative and that the severities and flavor parts are known
+func (weights TrustScoreWeights) Check() error {
+	for severity, weight := range weights.Severities {
+		if _, ok := defaultSeverityWeights[RuleSeverity(strings.ToLower(string(severity)))]; !ok {
+			return errors.Errorf("Unknown rule severity '%s'", severity)
+		}
+		if weight < 0 || math.IsNaN(weight) {
+			return errors.Errorf("Invalid weight %v of the rule severity '%s'", weight, severity)
+		}
+	}
+	for rule, severity := range weights.Rules {
+		if _, ok := defaultSeverityWeights[RuleSeverity(strings.ToLower(string(severity)))]; !ok {
+			return errors.Errorf("Unknown severity '%s' of the rule '%s'", severity, rule)
+		}
+	}
+	for flavorPart, weight := range weights.FlavorParts {
+		var fp common.FlavorPart
+		if err := (&fp).Parse(flavorPart); err != nil {
+			return errors.Errorf("Unknown flavor part '%s'", flavorPart)
+		}
+		if weight < 0 || math.IsNaN(weight) {
+			return errors.Errorf("Invalid weight %v of the flavor part '%s'", weight, flavorPart)
+		}
+	}
+	return nil
+}
+
+// Score returns the trust score of the trust report, rounded to two decimals, zero when it has no results
+func (weights TrustScoreWeights) Score(trustReport *TrustReport) float64 {
+	var total, trusted float64
+	for _, result := range trustReport.Results {
+		weight := weights.ResultWeight(result)
+		total += weight
+		if result.IsTrusted() {
+			trusted += weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(trusted/total*MaxTrustScore*100) / 100
+}
+
+// ResultWeight returns the weight of a rule result, the weight of the severity of its rule times the largest weight
+// of its flavor parts
+func (weights TrustScoreWeights) ResultWeight(result RuleResult) float64 {
+	flavorPartWeight := 1.0
+	for i, marker := range result.Rule.Markers {
+		weight := weights.flavorPartWeight(marker)
+		if i == 0 || weight > flavorPartWeight {
+			flavorPartWeight = weight
+		}
+	}
+	return weights.severityWeight(weights.RuleSeverity(result.Rule.Name)) * flavorPartWeight
+}
+
+// RuleSeverity returns the severity of the rule
+func (weights TrustScoreWeights) RuleSeverity(rule string) RuleSeverity {
+	shortName := strings.TrimPrefix(rule, constants.RulePrefix)
+	// the configured keys are lower case when they are read by viper
+	for name, severity := range weights.Rules {
+		if strings.EqualFold(name, rule) || strings.EqualFold(name, shortName) {
+			return RuleSeverity(strings.ToLower(string(severity)))
+		}
+	}
+	if severity, ok := defaultRuleSeverities[rule]; ok {
+		return severity
+	}
+	return RuleSeverityMedium
+}
+
+func (weights TrustScoreWeights) severityWeight(severity RuleSeverity) float64 {
+	for configured, weight := range weights.Severities {
+		if strings.EqualFold(string(configured), string(severity)) {
+			return weight
+		}
+	}
+	return defaultSeverityWeights[severity]
+}
+
+func (weights TrustScoreWeights) flavorPartWeight(flavorPart common.FlavorPart) float64 {
+	for configured, weight := range weights.FlavorParts {
+		if strings.EqualFold(configured, flavorPart.String()) {
+			return weight
+		}
+	}
+	return 1
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs_test
+
+import (
+	constants "github.com/intel-secl/intel-secl/v3/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor/common"
+	"github.com/intel-secl/intel-secl/v3/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TrustScoreWeights", func() {
+
+	trustReport := hvs.TrustReport{Results: []hvs.RuleResult{
+		{
+			Rule:    hvs.RuleInfo{Name: constants.RuleAikCertificateTrusted, Markers: []common.FlavorPart{common.FlavorPartPlatform}},
+			Trusted: true,
+		},
+		{
+			Rule:    hvs.RuleInfo{Name: constants.RulePcrMatchesConstant, Markers: []common.FlavorPart{common.FlavorPartOs}},
+			Faults:  []hvs.Fault{{Name: constants.FaultPcrValueMismatchSHA256}},
+			Trusted: false,
+		},
+		{
+			Rule:    hvs.RuleInfo{Name: constants.RuleAssetTagMatches, Markers: []common.FlavorPart{common.FlavorPartAssetTag}},
+			Faults:  []hvs.Fault{{Name: constants.FaultAssetTagMismatch}},
+			Trusted: false,
+		},
+		{
+			Rule:    hvs.RuleInfo{Name: constants.RuleQuoteFresh, Markers: []common.FlavorPart{common.FlavorPartPlatform}},
+			Faults:  []hvs.Fault{{Name: constants.FaultHostClockSkewed, Warning: true}},
+			Trusted: true,
+		},
+	}}
+
+	Context("Provided the default weights", func() {
+		It("Should weigh the results by the severity of their rules", func() {
+			// 10 + 5 trusted out of 10 + 5 + 1 + 5, the warning does not make the result untrusted
+			Expect(hvs.TrustScoreWeights{}.Score(&trustReport)).To(Equal(71.43))
+		})
+	})
+
+	Context("Provided configured weights", func() {
+		It("Should weigh the results by the configured severities and flavor parts", func() {
+			// the keys are lower case when the configuration is read by viper
+			weights := hvs.TrustScoreWeights{
+				Severities:  map[hvs.RuleSeverity]float64{"high": 4},
+				Rules:       map[string]hvs.RuleSeverity{"assettagmatches": "CRITICAL"},
+				FlavorParts: map[string]float64{"platform": 2, "asset_tag": 0.5},
+			}
+			Expect(weights.Check()).To(Succeed())
+			Expect(weights.RuleSeverity(constants.RuleAssetTagMatches)).To(Equal(hvs.RuleSeverityCritical))
+			// 10 * 2 + 4 * 2 trusted out of 10 * 2 + 4 + 10 * 0.5 + 4 * 2
+			Expect(weights.Score(&trustReport)).To(Equal(75.68))
+		})
+	})
+
+	Context("Provided a report without results", func() {
+		It("Should have a zero score", func() {
+			Expect(hvs.TrustScoreWeights{}.Score(&hvs.TrustReport{})).To(BeZero())
+		})
+	})
+
+	Context("Provided invalid weights", func() {
+		It("Should reject them", func() {
+			Expect(hvs.TrustScoreWeights{Severities: map[hvs.RuleSeverity]float64{"urgent": 1}}.Check()).ToNot(Succeed())
+			Expect(hvs.TrustScoreWeights{Severities: map[hvs.RuleSeverity]float64{"low": -1}}.Check()).ToNot(Succeed())
+			Expect(hvs.TrustScoreWeights{Rules: map[string]hvs.RuleSeverity{"PcrMatchesConstant": "urgent"}}.Check()).ToNot(Succeed())
+			Expect(hvs.TrustScoreWeights{FlavorParts: map[string]float64{"bios": 1}}.Check()).ToNot(Succeed())
+		})
+	})
+})
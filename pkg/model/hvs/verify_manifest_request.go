@@ -0,0 +1,22 @@
+/*
+ *  Copyright (C) 2020 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/host-connector/types"
+)
+
+// VerifyManifestRequest verifies a captured host manifest against stored and/or proposed flavors, nothing is
+// persisted
+type VerifyManifestRequest struct {
+	HostManifest types.HostManifest `json:"host_manifest"`
+	// FlavorIds are the stored flavors the manifest is verified against
+	FlavorIds []uuid.UUID `json:"flavor_ids,omitempty"`
+	// Flavors are the proposed flavors the manifest is verified against, they are signed by HVS for the verification
+	// only
+	Flavors []Flavors `json:"flavors,omitempty"`
+}
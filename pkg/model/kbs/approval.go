@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalOperation is a destructive operation of KBS that requires the approval of a second administrator when
+// dual control is enabled
+type ApprovalOperation string
+
+const (
+	ApprovalOperationDeleteKey               ApprovalOperation = "delete-key"
+	ApprovalOperationDeleteKeyTransferPolicy ApprovalOperation = "delete-key-transfer-policy"
+	ApprovalOperationUpdateKeyTransferPolicy ApprovalOperation = "update-key-transfer-policy"
+)
+
+// ApprovalStatus is the state of an approval request, only the pending requests can be approved or rejected
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "pending"
+	ApprovalStatusExecuted ApprovalStatus = "executed"
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+	ApprovalStatusFailed   ApprovalStatus = "failed"
+)
+
+// ApprovalAction is an action recorded in the audit records of an approval request
+type ApprovalAction string
+
+const (
+	ApprovalActionRequested ApprovalAction = "requested"
+	ApprovalActionApproved  ApprovalAction = "approved"
+	ApprovalActionRejected  ApprovalAction = "rejected"
+	ApprovalActionExecuted  ApprovalAction = "executed"
+	ApprovalActionFailed    ApprovalAction = "failed"
+)
+
+// ApprovalRequest - A destructive operation pending the approval of an administrator other than the one who
+// requested it. The operation is executed when it is approved.
+type ApprovalRequest struct {
+	// swagger:strfmt uuid
+	ID        uuid.UUID         `json:"id"`
+	Operation ApprovalOperation `json:"operation"`
+	// swagger:strfmt uuid
+	ResourceID uuid.UUID `json:"resource_id"`
+	// ResourceVersion is the version of the key transfer policy the update was requested against, the update
+	// fails when the policy changed in the meantime
+	ResourceVersion int `json:"resource_version,omitempty"`
+	// Payload is the key transfer policy of an update
+	Payload      json.RawMessage       `json:"payload,omitempty"`
+	TenantID     string                `json:"tenant_id,omitempty"`
+	Status       ApprovalStatus        `json:"status"`
+	RequestedBy  string                `json:"requested_by"`
+	RequestedAt  time.Time             `json:"requested_at"`
+	ReviewedBy   string                `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time            `json:"reviewed_at,omitempty"`
+	Comment      string                `json:"comment,omitempty"`
+	Error        string                `json:"error,omitempty"`
+	AuditRecords []ApprovalAuditRecord `json:"audit_records"`
+}
+
+// ApprovalAuditRecord - An action taken on an approval request
+type ApprovalAuditRecord struct {
+	Action  ApprovalAction `json:"action"`
+	Actor   string         `json:"actor"`
+	Time    time.Time      `json:"time"`
+	Comment string         `json:"comment,omitempty"`
+}
+
+// ApprovalDecision - The comment of the administrator approving or rejecting a request, the body is optional
+type ApprovalDecision struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// AddAuditRecord records an action taken on the approval request
+func (approval *ApprovalRequest) AddAuditRecord(action ApprovalAction, actor, comment string) {
+	approval.AuditRecords = append(approval.AuditRecords, ApprovalAuditRecord{
+		Action:  action,
+		Actor:   actor,
+		Time:    time.Now().UTC(),
+		Comment: comment,
+	})
+}
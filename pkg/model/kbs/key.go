@@ -28,6 +28,14 @@ type KeyRequest struct {
 	TransferPolicyID uuid.UUID `json:"transfer_policy_id,omitempty"`
 	Label            string    `json:"label,omitempty"`
 	Usage            string    `json:"usage,omitempty"`
+	// StorageClass is persistent by default, ephemeral keys are never written to the backing store and are
+	// removed from memory once their ttl (in seconds) expires
+	StorageClass string `json:"storage_class,omitempty"`
+	TTL          int    `json:"ttl,omitempty"`
+	// InjectionTargets are where the secret is delivered on the attested node once it is transferred
+	InjectionTargets []InjectionTarget `json:"injection_targets,omitempty"`
+	// Attributes are arbitrary name/value pairs the keys can be searched with
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 // KeyResponse - key attributes from key create or register response.
@@ -39,6 +47,31 @@ type KeyResponse struct {
 	CreatedAt        time.Time `json:"created_at"`
 	Label            string    `json:"label,omitempty"`
 	Usage            string    `json:"usage,omitempty"`
+	// swagger:strfmt uuid
+	ImageFlavorID *uuid.UUID `json:"image_flavor_id,omitempty"`
+	TenantID      string     `json:"tenant_id,omitempty"`
+	StorageClass  string     `json:"storage_class,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	// Version is incremented by each update, it is the ETag of the key
+	Version          int               `json:"version"`
+	InjectionTargets []InjectionTarget `json:"injection_targets,omitempty"`
+	Attributes       map[string]string `json:"attributes,omitempty"`
+}
+
+// KeyAttributesUpdate - Replaces the attributes of a key, the attributes are removed when it is empty.
+type KeyAttributesUpdate struct {
+	Attributes map[string]string `json:"attributes"`
+}
+
+// ImageFlavorIDHeader is the header in which the workload service reports the image flavor of the workload
+// requesting the transfer of a key
+const ImageFlavorIDHeader = "Image-Flavor-Id"
+
+// KeyImageFlavorBinding - Binds a key to a workload service image flavor, the key is then only transferred
+// for workloads that report the image flavor.
+type KeyImageFlavorBinding struct {
+	// swagger:strfmt uuid
+	ImageFlavorID uuid.UUID `json:"image_flavor_id"`
 }
 
 // KeyTransferAttributes - Contains all possible key transfer attributes.
@@ -50,7 +83,16 @@ type KeyTransferAttributes struct {
 	KeyAlgorithm string     `json:"algorithm,omitempty"`
 	KeyLength    int        `json:"key_length,omitempty"`
 	CreatedAt    *time.Time `json:"created_at,omitempty"`
-	Policy       struct {
+	// SWK is the session key wrapped with the client certificate public key, returned
+	// for sessions established over RA-TLS
+	SWK []byte `json:"swk,omitempty"`
+	// Compression is set when the payload was compressed before it was encrypted with the session key
+	Compression string `json:"compression,omitempty"`
+	// InjectionTargets are where the agent delivers the unwrapped secret
+	InjectionTargets []InjectionTarget `json:"injection_targets,omitempty"`
+	// CachePolicy is set on the keys transferred to a client enclave
+	CachePolicy *KeyCachePolicy `json:"cache_policy,omitempty"`
+	Policy      struct {
 		Link struct {
 			KeyTransfer struct {
 				Href   string `json:"href,omitempty"`
@@ -59,3 +101,14 @@ type KeyTransferAttributes struct {
 		} `json:"link,omitempty"`
 	} `json:"policy,omitempty"`
 }
+
+// KeyCachePolicy tells the client enclave how the key transfer policy lets it keep the transferred key
+type KeyCachePolicy struct {
+	// Cacheable is set when the key may be cached in the client enclave
+	Cacheable bool `json:"cacheable"`
+	// CacheUntil is when the cached key must be discarded, the expiry of the session at the latest
+	CacheUntil *time.Time `json:"cache_until,omitempty"`
+	// ReattestOnReuse is set when the client enclave must be attested again before it reuses the cached key, the
+	// session the key was transferred in is ended after the transfer
+	ReattestOnReuse bool `json:"reattest_on_reuse"`
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyEscrowRequest - Splits the escrow KEK among custodians, threshold of which are needed to recover the keys.
+type KeyEscrowRequest struct {
+	Custodians int `json:"custodians"`
+	Threshold  int `json:"threshold"`
+}
+
+// KeyEscrowShares - The shares of the escrow KEK, one for each custodian. They are only returned when the escrow is
+// initialized.
+type KeyEscrowShares struct {
+	KekID      uuid.UUID `json:"kek_id"`
+	Custodians int       `json:"custodians"`
+	Threshold  int       `json:"threshold"`
+	Shares     []string  `json:"shares"`
+}
+
+// KeyEscrowStatus - The escrow KEK, the number of keys in escrow and the shares submitted for a recovery.
+type KeyEscrowStatus struct {
+	KekID           uuid.UUID `json:"kek_id"`
+	Custodians      int       `json:"custodians"`
+	Threshold       int       `json:"threshold"`
+	CreatedAt       time.Time `json:"created_at"`
+	EscrowedKeys    int       `json:"escrowed_keys"`
+	SubmittedShares int       `json:"submitted_shares"`
+}
+
+// KeyEscrowShare - A custodian share of the escrow KEK, encoded as returned when the escrow was initialized.
+type KeyEscrowShare struct {
+	Share string `json:"share"`
+}
+
+// KeyEscrowRecovery - The keys restored from escrow, the keys still in the key store are not restored.
+type KeyEscrowRecovery struct {
+	RestoredKeys []uuid.UUID `json:"restored_keys"`
+	SkippedKeys  int         `json:"skipped_keys"`
+}
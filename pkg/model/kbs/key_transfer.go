@@ -5,8 +5,47 @@
 
 package kbs
 
+import "github.com/google/uuid"
+
 type KeyTransferResponse struct {
 	KeyInfo   KeyTransferAttributes `json:"data"`
 	Operation string                `json:"operation"`
 	Status    string                `json:"status"`
 }
+
+// Tpm2KeyTransferRequest is the request to transfer a key as a duplication blob to be imported under a storage key
+// of the TPM of an attested host
+type Tpm2KeyTransferRequest struct {
+	// SamlReport is the trust report of the host
+	SamlReport string `json:"saml_report"`
+	// ParentPublic is the TPM2B_PUBLIC of the storage key the key is imported under
+	// swagger:strfmt base64
+	ParentPublic []byte `json:"parent_public"`
+	// ParentCertifyInfo is the TPMS_ATTEST of the certification of the storage key by the AIK of the host
+	// swagger:strfmt base64
+	ParentCertifyInfo []byte `json:"parent_certify_info"`
+	// ParentCertifySignature is the TPMT_SIGNATURE of the certification with the AIK
+	// swagger:strfmt base64
+	ParentCertifySignature []byte `json:"parent_certify_signature"`
+	// AuthPolicy is the policy digest the imported key can be used with, the key can be used with an empty
+	// authorization value when it is not set
+	// swagger:strfmt base64
+	AuthPolicy []byte `json:"auth_policy,omitempty"`
+}
+
+// Tpm2KeyTransferResponse holds the parameters of the TPM2_Import of the transferred key
+type Tpm2KeyTransferResponse struct {
+	// swagger:strfmt uuid
+	KeyId        uuid.UUID `json:"id"`
+	KeyAlgorithm string    `json:"algorithm"`
+	KeyLength    int       `json:"key_length"`
+	// ObjectPublic is the TPM2B_PUBLIC of the key
+	// swagger:strfmt base64
+	ObjectPublic []byte `json:"object_public"`
+	// Duplicate is the TPM2B_PRIVATE of the key wrapped to the storage key
+	// swagger:strfmt base64
+	Duplicate []byte `json:"duplicate"`
+	// InSymSeed is the TPM2B_ENCRYPTED_SECRET of the duplication
+	// swagger:strfmt base64
+	InSymSeed []byte `json:"in_sym_seed"`
+}
@@ -27,4 +27,17 @@ type KeyTransferPolicyAttributes struct {
 	TLSClientCertificateSANAllof           []string  `json:"client_permissions_allof,omitempty"`
 	AttestationTypeAnyof                   []string  `json:"attestation_type_anyof,omitempty"`
 	SGXEnforceTCBUptoDate                  bool      `json:"sgx_enforce_tcb_up_to_date,omitempty"`
+	// KeyCachingAllowed allows the client enclave to cache the released keys, for at most KeyCacheTimeout seconds
+	// when it is set
+	KeyCachingAllowed bool `json:"key_caching_allowed,omitempty"`
+	KeyCacheTimeout   int  `json:"key_cache_timeout,omitempty"`
+	// KeyReattestOnReuse requires the client enclave to be attested again before it reuses a cached key
+	KeyReattestOnReuse bool `json:"key_reattest_on_reuse,omitempty"`
+	// TransferCacheTimeout caches a successful key transfer for this many seconds, a client of the session
+	// requesting the same key again is answered from the cache without being attested again
+	TransferCacheTimeout int `json:"transfer_cache_timeout,omitempty"`
+	// TenantID is set from the tenant of the user creating the policy
+	TenantID string `json:"tenant_id,omitempty"`
+	// Version is incremented by each update, it is the ETag of the policy
+	Version int `json:"version"`
 }
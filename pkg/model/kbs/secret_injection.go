@@ -0,0 +1,29 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+// Types of the targets a released secret is injected into on the attested node
+const (
+	InjectionTargetKubernetesSecret = "kubernetes-secret"
+	InjectionTargetFile             = "file"
+	InjectionTargetEnvTemplate      = "env-template"
+)
+
+// InjectionTarget - Where the agent on the attested node delivers the secret once it is released, for the
+// workloads that cannot embed the key transfer client.
+type InjectionTarget struct {
+	// Type is one of kubernetes-secret, file or env-template
+	Type string `json:"type"`
+	// Name and Namespace of the kubernetes Secret the secret is written to
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Key is the entry of the kubernetes Secret holding the secret, defaults to the key id
+	Key string `json:"key,omitempty"`
+	// Path of the file written, relative to the directory the agent delivers secrets into
+	Path string `json:"path,omitempty"`
+	// Template is the text/template rendered into the file of an env-template target, the secret is
+	// available as {{.Secret}} and {{.SecretBase64}}
+	Template string `json:"template,omitempty"`
+}
@@ -13,6 +13,8 @@ type KeyTransferSession struct {
 	SessionId         string `json:"sessionid"`
 	ClientCertHash    string `json:"clientcerthash"`
 	Stmlabel          string `json:"stmlabel"`
+	Compression       string `json:"compression,omitempty"`
+	TenantID          string `json:"tenant_id,omitempty"`
 	SessionExpiryTime time.Time
 }
 
@@ -23,6 +25,8 @@ type ChallengeRequest struct {
 	Link          ChallengeLink `json:"link,omitempty"`
 	Operation     string        `json:"operation"`
 	Status        string        `json:"status"`
+	// ChallengeExpiry is the time until which the session can be created with the challenge, only once
+	ChallengeExpiry *time.Time `json:"challenge_expiry,omitempty"`
 }
 
 type NotFoundResponse struct {
@@ -72,10 +76,14 @@ type SessionResponseAttributes struct {
 type Data struct {
 	SWK           []byte `json:"swk,omitempty"`
 	AlgorithmType string `json:"type,omitempty"`
+	// Compression is the payload compression negotiated for the session
+	Compression string `json:"compression,omitempty"`
 }
 
 type SessionManagementAttributes struct {
 	ChallengeType string `json:"challenge_type"`
 	Challenge     string `json:"challenge"`
 	Quote         string `json:"quote"`
+	// Compression lists the payload compression algorithms accepted by the client, in order of preference
+	Compression []string `json:"compression,omitempty"`
 }
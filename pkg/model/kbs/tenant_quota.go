@@ -0,0 +1,12 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+// TenantQuota - Limits the number of keys and key transfer policies a tenant can create, a limit of zero is unlimited.
+type TenantQuota struct {
+	TenantID               string `json:"tenant_id,omitempty"`
+	MaxKeys                int    `json:"max_keys,omitempty"`
+	MaxKeyTransferPolicies int    `json:"max_key_transfer_policies,omitempty"`
+}
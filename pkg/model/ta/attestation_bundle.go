@@ -0,0 +1,31 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+import "encoding/xml"
+
+// AttestationBundle is the data the trust agent collects for an attestation in a single request. The extra data of
+// the quote is derived from the nonce of the request and the digests of the host info and of the logs of the bundle,
+// so that the quote covers all of them
+//
+//	<attestation_bundle>
+//	    <hostInfo>eyJvc19uYW1lIjoiUmVkSGF0RW50ZXJwcmlzZSIsIm9zX3ZlcnNpb24iOiI4LjEi...=</hostInfo>
+//	    <imaLog>MTAgOTc5N2VkZjk0ZTI5ZjNiZjM3ZmQ4NDAxMjM5Y2E3ZTNlMGQ2ZGM1YyBpbWEt...=</imaLog>
+//	    <tpm_quote_response>
+//	        <timestamp>1569264156635</timestamp>
+//	        <quote>AIv/VENHgBgAIgALUiWzd9...=</quote>
+//	        <eventLog>PG1lYXN1cmVMb2c+PHR4dD48dHh0U3RhdH...=</eventLog>
+//	        <binaryEventLog>AAAAAAMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC0AAABTcGVj...=</binaryEventLog>
+//	        <...>
+//	    </tpm_quote_response>
+//	</attestation_bundle>
+type AttestationBundle struct {
+	XMLName xml.Name `xml:"attestation_bundle"`
+	// HostInfo is the base64 encoded JSON host info of the host
+	HostInfo string `xml:"hostInfo"`
+	// ImaLog is the base64 encoded ascii IMA measurement list of the host, it is only reported when IMA is enabled
+	ImaLog string           `xml:"imaLog,omitempty"`
+	Quote  TpmQuoteResponse `xml:"tpm_quote_response"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+// ContainerImageMeasurement is the measurement of a container image, as reported by the workload agent when it
+// launches the containers of the image and as expected by the CONTAINER_IMAGE flavors
+type ContainerImageMeasurement struct {
+	// Name is the reference of the image, e.g. registry.example.com/nginx:1.19
+	Name string `xml:"name" json:"name"`
+	// Digest is the content digest of the image manifest, e.g. sha256:7cbc...
+	Digest string `xml:"digest" json:"digest"`
+	// DmVerityRootHash is the root hash of the dm-verity device the image is mounted from, when it is
+	DmVerityRootHash string `xml:"dmVerityRootHash,omitempty" json:"dm_verity_root_hash,omitempty"`
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package model
+
+// HostCapabilities are the attestation features supported by a host, HVS caches them in the host record to
+// select the PCR banks to quote and the flavors to match for the host
+type HostCapabilities struct {
+	TPMVersion string   `json:"tpm_version,omitempty"`
+	PCRBanks   []string `json:"pcr_banks,omitempty"`
+	SGX        bool     `json:"sgx"`
+	TDX        bool     `json:"tdx"`
+	// MeasurementAgents are the measurement agents installed on the host, e.g. tagent and wlagent
+	MeasurementAgents []string `json:"measurement_agents,omitempty"`
+	// AttestationBundle is true when the trust agent collects the quote, the logs and the host info in a single request
+	AttestationBundle bool `json:"attestation_bundle,omitempty"`
+}
+
+// SupportsPCRBank returns true when the host supports the PCR bank, hosts whose banks are unknown are expected to
+// support all of them
+func (hc *HostCapabilities) SupportsPCRBank(bank string) bool {
+	if hc == nil || len(hc.PCRBanks) == 0 {
+		return true
+	}
+	for _, b := range hc.PCRBanks {
+		if b == bank {
+			return true
+		}
+	}
+	return false
+}
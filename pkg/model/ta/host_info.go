@@ -33,6 +33,8 @@ type HostInfo struct {
 	IsDockerEnvironment bool             `json:"is_docker_env,string,omitempty"`
 	HardwareFeatures    HardwareFeatures `json:"hardware_features"`
 	InstalledComponents []string         `json:"installed_components"`
+	// PlatformInventory is read out-of-band from the BMC of the host, it is not covered by the quotes of the host
+	PlatformInventory *PlatformInventory `json:"platform_inventory,omitempty"`
 }
 
 type HardwareFeatures struct {
@@ -41,8 +43,33 @@ type HardwareFeatures struct {
 		Enabled bool `json:"enabled,string"`
 		Meta    struct {
 			TPMVersion string `json:"tpm_version,omitempty"`
+			// PCRBanks are the PCR banks of the TPM separated by underscores, e.g. SHA1_SHA256
+			PCRBanks string `json:"pcr_banks,omitempty"`
 		} `json:"meta"`
 	} `json:"TPM,omitempty"`
 	CBNT  *CBNT            `json:"CBNT,omitempty"`
 	SUEFI *HardwareFeature `json:"SUEFI,omitempty"`
 }
+
+// PlatformInventory is the firmware inventory, secure boot state and TPM presence reported by the BMC of a host
+type PlatformInventory struct {
+	// Source is the protocol the inventory was read with, e.g. redfish
+	Source     string              `json:"source"`
+	Firmware   []FirmwareComponent `json:"firmware,omitempty"`
+	SecureBoot *SecureBootState    `json:"secure_boot,omitempty"`
+	TPMPresent bool                `json:"tpm_present"`
+	// TPMInterfaceType is the TPM interface reported by the BMC, e.g. TPM2_0
+	TPMInterfaceType string `json:"tpm_interface_type,omitempty"`
+}
+
+// FirmwareComponent is a firmware of the platform, e.g. the BIOS or the BMC firmware
+type FirmwareComponent struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type SecureBootState struct {
+	Enabled bool `json:"enabled"`
+	// Mode is the secure boot mode reported by the BMC, e.g. DeployedMode or SetupMode
+	Mode string `json:"mode,omitempty"`
+}
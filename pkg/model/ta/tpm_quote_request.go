@@ -8,4 +8,8 @@ type TpmQuoteRequest struct {
 	Nonce    []byte   `json:"nonce"`
 	Pcrs     []int    `json:"pcrs"`
 	PcrBanks []string `json:"pcrbanks"`
+	// CorrelationID and CallbackURL are set for asynchronous quote requests, the trust agent
+	// accepts the request and posts the quote response to the callback url once it is ready
+	CorrelationID string `json:"correlation_id,omitempty"`
+	CallbackURL   string `json:"callback_url,omitempty"`
 }
@@ -13,6 +13,7 @@ import "encoding/xml"
 //     <aik>MIIDSjCCAbKgAwIBAgIGAWz...</aik>
 //     <quote>AIv/VENHgBgAIgALUiWzd9...=</quote>
 //     <eventLog>PG1lYXN1cmVMb2c+PHR4dD48dHh0U3RhdH...=</eventLog>
+//     <binaryEventLog>AAAAAAMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC0AAABTcGVj...=</binaryEventLog>
 //     <tcbMeasurements>
 //         <tcbMeasurements>&lt;?xml version="1.0" encoding="UTF-8" standalone="yes"?>&lt;Measurement xmlns="lib:wml:measurements:1.0" Label="ISecL_Default_Workload_Flavor_v2.0" Uuid="b13b405b-97a7-4480-a2e7-eea01f9799ce" DigestAlg="SHA384">&lt;CumulativeHash>000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000&lt;/CumulativeHash>&lt;/Measurement></tcbMeasurements>
 // 			<...>
@@ -22,6 +23,12 @@ import "encoding/xml"
 //         <selectedPcrBanks>SHA256</selectedPcrBanks>
 //     </selectedPcrBanks>
 //     <isTagProvisioned>false</isTagProvisioned>
+//     <containerImageMeasurements>
+//         <containerImageMeasurement>
+//             <name>registry.example.com/nginx:1.19</name>
+//             <digest>sha256:7cbc2e5c9f3ab6b0f0a3a5d1c2ed1d5d1a1e5ff0f16fd2aab5b0b0f1b57a5b0e</digest>
+//         </containerImageMeasurement>
+//     </containerImageMeasurements>
 // </tpm_quote_response>
 type TpmQuoteResponse struct {
 	XMLName         xml.Name `xml:"tpm_quote_response"`
@@ -31,6 +38,9 @@ type TpmQuoteResponse struct {
 	Aik             string   `xml:"aik"`
 	Quote           string   `xml:"quote"`
 	EventLog        string   `xml:"eventLog"`
+	// BinaryEventLog is the base64 encoded TCG crypto agile event log of the host, it is only reported by the
+	// trust agents that can read the event log of the TPM
+	BinaryEventLog  string   `xml:"binaryEventLog,omitempty"`
 	TcbMeasurements struct {
 		XMLName         xml.Name `xml:"tcbMeasurements"`
 		TcbMeasurements []string `xml:"tcbMeasurements"`
@@ -41,4 +51,10 @@ type TpmQuoteResponse struct {
 	}
 	IsTagProvisioned bool   `xml:"isTagProvisioned"`
 	AssetTag         string `xml:"assetTag,omitempty"`
+	// ContainerImageMeasurements are the images of the containers launched by the workload agent, they are only
+	// reported when the workload agent is installed
+	ContainerImageMeasurements struct {
+		XMLName                    xml.Name                    `xml:"containerImageMeasurements"`
+		ContainerImageMeasurements []ContainerImageMeasurement `xml:"containerImageMeasurement"`
+	}
 }
@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package wls
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
+	"github.com/pkg/errors"
+)
+
+// InstanceInfo identifies a workload instance and the host it runs on
+type InstanceInfo struct {
+	InstanceID   string `json:"instance_id"`
+	ImageID      string `json:"image_id"`
+	HardwareUUID string `json:"host_hardware_uuid"`
+}
+
+// VolumeBinding is the binding of an encrypted volume of an instance to the key it was unlocked with
+type VolumeBinding struct {
+	VolumeID string `json:"volume_id"`
+	KeyURL   string `json:"key_url"`
+	Bound    bool   `json:"bound"`
+}
+
+// InstanceMeasurement contains the measurements of a workload instance reported by the workload agent
+type InstanceMeasurement struct {
+	ImageDigest    string          `json:"image_digest"`
+	VolumeBindings []VolumeBinding `json:"volume_bindings,omitempty"`
+}
+
+// HostTrust is the trust status of the host of an instance, as reported by the Host Verification Service
+type HostTrust struct {
+	Trusted     bool      `json:"trusted"`
+	HvsReportID string    `json:"hvs_report_id,omitempty"`
+	ValidTo     time.Time `json:"valid_to"`
+}
+
+// InstanceTrustReport combines the trust of the host of a workload instance with the measurements of the
+// instance. The instance is trusted when its host is trusted, its image digest matches the image flavor and all
+// of its encrypted volumes are bound to their keys.
+type InstanceTrustReport struct {
+	Instance           InstanceInfo        `json:"instance_info"`
+	HostTrust          HostTrust           `json:"host_trust"`
+	Measurement        InstanceMeasurement `json:"instance_measurement"`
+	ExpectedDigest     string              `json:"expected_image_digest"`
+	ImageDigestMatches bool                `json:"image_digest_matches"`
+	Trusted            bool                `json:"trusted"`
+	CreatedAt          time.Time           `json:"created_at"`
+}
+
+// NewInstanceTrustReport creates the trust report of an instance from the trust of its host and its measurements,
+// the image digest of the measurements is compared with the expected digest of the image flavor. An expired host
+// trust is not trusted.
+func NewInstanceTrustReport(instance InstanceInfo, hostTrust HostTrust, measurement InstanceMeasurement,
+	expectedDigest string) *InstanceTrustReport {
+
+	report := InstanceTrustReport{
+		Instance:           instance,
+		HostTrust:          hostTrust,
+		Measurement:        measurement,
+		ExpectedDigest:     expectedDigest,
+		ImageDigestMatches: expectedDigest != "" && strings.EqualFold(measurement.ImageDigest, expectedDigest),
+		CreatedAt:          time.Now().UTC(),
+	}
+
+	// the host report is not trusted anymore once it expired
+	hostTrusted := hostTrust.Trusted && (hostTrust.ValidTo.IsZero() || report.CreatedAt.Before(hostTrust.ValidTo))
+	report.Trusted = hostTrusted && report.ImageDigestMatches
+	for _, volumeBinding := range measurement.VolumeBindings {
+		report.Trusted = report.Trusted && volumeBinding.Bound
+	}
+	return &report
+}
+
+// SignedInstanceTrustReport combines the instance trust report with the signature of the workload service
+type SignedInstanceTrustReport struct {
+	Report    InstanceTrustReport `json:"instance_trust_report"`
+	Signature string              `json:"signature"`
+}
+
+// NewSignedInstanceTrustReport signs the instance trust report with the private key of the workload service
+func NewSignedInstanceTrustReport(report *InstanceTrustReport, privateKey *rsa.PrivateKey) (*SignedInstanceTrustReport, error) {
+
+	if report == nil {
+		return nil, errors.New("The instance trust report must be provided and cannot be nil")
+	}
+
+	if privateKey == nil || privateKey.Validate() != nil {
+		return nil, errors.New("Valid private key must be provided and cannot be nil")
+	}
+
+	reportDigest, err := report.getReportDigest()
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred while creating the signed instance trust report")
+	}
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA384, reportDigest)
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred while signing the instance trust report")
+	}
+
+	return &SignedInstanceTrustReport{
+		Report:    *report,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// Verify Provided the public key from the certificate of the workload service,
+// verify that the signature of the instance trust report is valid.
+func (signedReport *SignedInstanceTrustReport) Verify(publicKey *rsa.PublicKey) error {
+
+	if len(signedReport.Signature) == 0 {
+		return errors.New("Could not verify the instance trust report: The report does not have a signature")
+	}
+
+	signatureBytes, err := base64.StdEncoding.DecodeString(signedReport.Signature)
+	if err != nil {
+		return errors.Wrap(err, "Could not verify the instance trust report: An error occurred attempting to decode the signature")
+	}
+
+	reportDigest, err := signedReport.Report.getReportDigest()
+	if err != nil {
+		return errors.Wrap(err, "Could not verify the instance trust report: An error occurred collecting the report digest")
+	}
+
+	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA384, reportDigest, signatureBytes)
+	if err != nil {
+		return errors.Wrap(err, "Could not verify the instance trust report: PKCS1 verification failed")
+	}
+
+	return nil
+}
+
+// getReportDigest returns the SHA384 hash of the canonical json (RFC 8785) of the report, so that the signature
+// can be verified by the clients that do not encode the report as Go does
+func (report *InstanceTrustReport) getReportDigest() ([]byte, error) {
+	reportJSON, err := crypt.MarshalCanonicalJSON(report)
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred attempting to convert the instance trust report to json")
+	}
+
+	hashEntity := sha512.New384()
+	_, err = hashEntity.Write(reportJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error writing instance trust report hash")
+	}
+	return hashEntity.Sum(nil), nil
+}
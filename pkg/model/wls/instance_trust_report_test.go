@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package wls
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const imageDigest = "sha256:7cbc2e5c9f3ab6b0f0a3a5d1c2ed1d5d1a1e5ff0f16fd2aab5b0b0f1b57a5b0e"
+
+var instance = InstanceInfo{
+	InstanceID:   "a9f6b8a3-5a0b-4f5e-9d3e-8f1f3d1f0c2a",
+	ImageID:      "2d2f3c0e-1a6e-4d5b-9b7a-3c8e6f4b2a1d",
+	HardwareUUID: "00ecd3ab-9af4-e711-906e-001560a04062",
+}
+
+func TestNewInstanceTrustReport(t *testing.T) {
+	trustedHost := HostTrust{Trusted: true, ValidTo: time.Now().Add(time.Hour)}
+	measurement := InstanceMeasurement{
+		ImageDigest:    imageDigest,
+		VolumeBindings: []VolumeBinding{{VolumeID: "data", KeyURL: "https://kbs.example.com/v1/keys/1/transfer", Bound: true}},
+	}
+
+	report := NewInstanceTrustReport(instance, trustedHost, measurement, imageDigest)
+	assert.True(t, report.ImageDigestMatches)
+	assert.True(t, report.Trusted)
+
+	// the image digest does not match the flavor
+	report = NewInstanceTrustReport(instance, trustedHost, InstanceMeasurement{ImageDigest: "sha256:00"}, imageDigest)
+	assert.False(t, report.ImageDigestMatches)
+	assert.False(t, report.Trusted)
+
+	// an encrypted volume is not bound to its key
+	measurement.VolumeBindings = append(measurement.VolumeBindings, VolumeBinding{VolumeID: "logs"})
+	report = NewInstanceTrustReport(instance, trustedHost, measurement, imageDigest)
+	assert.True(t, report.ImageDigestMatches)
+	assert.False(t, report.Trusted)
+
+	// the host trust expired
+	expiredHost := HostTrust{Trusted: true, ValidTo: time.Now().Add(-time.Hour)}
+	report = NewInstanceTrustReport(instance, expiredHost, InstanceMeasurement{ImageDigest: imageDigest}, imageDigest)
+	assert.False(t, report.Trusted)
+}
+
+func TestSignedInstanceTrustReportVerify(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 3072)
+	assert.NoError(t, err)
+
+	report := NewInstanceTrustReport(instance, HostTrust{Trusted: true}, InstanceMeasurement{ImageDigest: imageDigest}, imageDigest)
+	signedReport, err := NewSignedInstanceTrustReport(report, privateKey)
+	assert.NoError(t, err)
+	assert.NoError(t, signedReport.Verify(&privateKey.PublicKey))
+
+	// the signature does not match a tampered report
+	signedReport.Report.Trusted = false
+	assert.Error(t, signedReport.Verify(&privateKey.PublicKey))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 3072)
+	assert.NoError(t, err)
+	signedReport.Report.Trusted = true
+	assert.Error(t, signedReport.Verify(&otherKey.PublicKey))
+
+	_, err = NewSignedInstanceTrustReport(nil, privateKey)
+	assert.Error(t, err)
+}
@@ -25,7 +25,11 @@ Available Commands:
     uninstall [--purge]              Uninstall wpm. --purge option needs to be applied to remove configuration and data files
     setup                            Run workload-policy-manager setup tasks
 
-Setup command usage:     wpm setup [task] [--force]
+Setup command usage:     wpm setup [task] [--force] [--interactive] [--fix-drift]
+                         wpm setup --interactive
+                         wpm setup --fix-drift
+    --interactive          prompt for the required env variables not set, all setup tasks are run if no task is given
+    --fix-drift            run again the tasks whose configuration has drifted, all tasks are checked if no task is given
 
 Available tasks for setup:
    all                                         Runs all setup tasks
@@ -10,9 +10,9 @@ package imageflavor
  *
  */
 import (
-	"crypto/sha512"
 	"encoding/base64"
 	"encoding/json"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/crypt"
 	cLog "github.com/intel-secl/intel-secl/v3/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v3/pkg/lib/flavor"
 	consts "github.com/intel-secl/intel-secl/v3/pkg/wpm/constants"
@@ -91,17 +91,14 @@ func CreateImageFlavor(flavorLabel, outputFlavorFilename, inputImageFilename,
 		imageFilePath = outputEncImageFilePath
 	}
 
-	//Check the encrypted image output file
-	imageFile, err := ioutil.ReadFile(imageFilePath)
+	//Take the digest of the encrypted image, the image is hashed as it is read
+	digest, err := crypt.HashFile(imageFilePath, crypt.SHA384())
 	if err != nil {
-		return "", errors.Wrap(err, "I/O Error creating encrypted image file: "+err.Error())
+		return "", errors.Wrap(err, "I/O Error reading encrypted image file: "+err.Error())
 	}
 
-	//Take the digest of the encrypted image
-	digest := sha512.Sum384(imageFile)
-
 	//Create image flavor
-	imageFlavor, err := flavor.GetImageFlavor(flavorLabel, encRequired, keyUrlString, base64.StdEncoding.EncodeToString(digest[:]))
+	imageFlavor, err := flavor.GetImageFlavor(flavorLabel, encRequired, keyUrlString, base64.StdEncoding.EncodeToString(digest))
 	if err != nil {
 		return "", errors.Wrap(err, "Error creating image flavor: "+err.Error())
 	}
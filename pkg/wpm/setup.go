@@ -22,8 +22,14 @@ func (a *App) setup(args []string) error {
 	}
 	// look for cli flags
 	var ansFile string
-	var force bool
+	var force, interactive, fixDrift bool
 	for i, s := range args {
+		if s == setup.InteractiveFlag {
+			interactive = true
+		}
+		if s == setup.FixDriftFlag {
+			fixDrift = true
+		}
 		if s == "-f" || s == "--file" {
 			if i+1 < len(args) {
 				ansFile = args[i+1]
@@ -36,6 +42,11 @@ func (a *App) setup(args []string) error {
 			force = true
 		}
 	}
+	cmd := args[1]
+	// "wpm setup --interactive" and "wpm setup --fix-drift" run all the setup tasks
+	if cmd == setup.InteractiveFlag || cmd == setup.FixDriftFlag {
+		cmd = "all"
+	}
 	// dump answer file to env
 	if ansFile != "" {
 		err := setup.ReadAnswerFileToEnv(ansFile)
@@ -43,12 +54,18 @@ func (a *App) setup(args []string) error {
 			return errors.Wrap(err, "Failed to read answer file")
 		}
 	}
+	// prompt for the settings not in the answer file
+	if interactive && !(len(args) > 2 && args[2] == "--help") {
+		err := setup.NewConsoleInteractive(a.consoleWriter()).ReadAnswersToEnv(cmd, setupPrompts())
+		if err != nil {
+			return errors.Wrap(err, "Failed to read setup settings")
+		}
+	}
 	runner, err := a.setupTaskRunner()
 	if err != nil {
 		return err
 	}
 	defer a.Config.Save(constants.DefaultConfigFilePath)
-	cmd := args[1]
 	// print help and return if applicable
 	if len(args) > 2 && args[2] == "--help" {
 		if cmd == "all" {
@@ -64,7 +81,15 @@ func (a *App) setup(args []string) error {
 		}
 		return nil
 	}
-	if cmd == "all" {
+	if fixDrift {
+		if err = runner.FixDrift(cmd); err != nil {
+			fmt.Fprintln(a.errorWriter(), "Error(s) encountered when fixing setup drift:")
+			for errCmd, failErr := range runner.FailedCommands() {
+				fmt.Fprintln(a.errorWriter(), errCmd+": "+failErr.Error())
+			}
+			return err
+		}
+	} else if cmd == "all" {
 		if err = runner.RunAll(force); err != nil {
 			errCmds := runner.FailedCommands()
 			fmt.Fprintln(a.errorWriter(), "Error(s) encountered when running all setup commands:")
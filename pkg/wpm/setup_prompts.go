@@ -0,0 +1,29 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package wpm
+
+import (
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/setup"
+	"github.com/intel-secl/intel-secl/v3/pkg/lib/common/validation"
+)
+
+// setupPrompts lists the settings prompted for by the interactive setup, in the order of the setup tasks. The
+// service settings are only written by a complete setup and are asked for by it.
+func setupPrompts() []setup.Prompt {
+	serviceTasks := []string{"all"}
+	return []setup.Prompt{
+		{EnvName: "CMS_BASE_URL", Description: "CMS base URL", Validate: setup.ValidateBaseURL,
+			Tasks: []string{"download-ca-cert", "download-cert-flavor-signing"}},
+		{EnvName: "CMS_TLS_CERT_SHA384", Description: "SHA384 digest of the CMS TLS certificate",
+			Validate: setup.ValidateCertDigest, Tasks: []string{"download-ca-cert"}},
+		{EnvName: "BEARER_TOKEN", Description: "Installation bearer token", Secret: true,
+			Validate: validation.ValidateJWT, Tasks: []string{"download-cert-flavor-signing"}},
+		{EnvName: "AAS_API_URL", Description: "AAS base URL", Validate: setup.ValidateBaseURL, Tasks: serviceTasks},
+		{EnvName: "KMS_API_URL", Description: "KBS base URL", Validate: setup.ValidateBaseURL, Tasks: serviceTasks},
+		{EnvName: "WPM_SERVICE_USERNAME", Description: "WPM service username",
+			Validate: validation.ValidateUserNameString, Tasks: serviceTasks},
+		{EnvName: "WPM_SERVICE_PASSWORD", Description: "WPM service password", Secret: true, Tasks: serviceTasks},
+	}
+}